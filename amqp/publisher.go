@@ -15,6 +15,7 @@ type Publisher interface {
 	ChannelClient
 
 	PublishUplink(dataUp types.UplinkMessage) error
+	PublishUplinkFormat(dataUp types.UplinkMessage, format types.PayloadFormat) error
 	PublishDownlink(dataDown types.DownlinkMessage) error
 }
 
@@ -36,8 +37,12 @@ func (c *DefaultClient) NewPublisher(exchange string) Publisher {
 }
 
 func (p *DefaultPublisher) publish(key string, msg []byte, timestamp time.Time) error {
+	return p.publishFormat(key, msg, timestamp, types.PayloadFormatJSON)
+}
+
+func (p *DefaultPublisher) publishFormat(key string, msg []byte, timestamp time.Time, format types.PayloadFormat) error {
 	return p.channel.Publish(p.exchange, key, false, false, AMQP.Publishing{
-		ContentType:  "application/json",
+		ContentType:  format.ContentType(),
 		DeliveryMode: AMQP.Persistent,
 		Timestamp:    timestamp,
 		Body:         msg,
@@ -4,7 +4,6 @@
 package amqp
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -18,18 +17,23 @@ type UplinkHandler func(subscriber Subscriber, appID string, devID string, req t
 
 // PublishUplink publishes an uplink message to the AMQP broker
 func (c *DefaultPublisher) PublishUplink(dataUp types.UplinkMessage) error {
+	return c.PublishUplinkFormat(dataUp, types.PayloadFormatJSON)
+}
+
+// PublishUplinkFormat publishes an uplink message to the AMQP broker in the given format
+func (c *DefaultPublisher) PublishUplinkFormat(dataUp types.UplinkMessage, format types.PayloadFormat) error {
 	key := DeviceKey{dataUp.AppID, dataUp.DevID, DeviceUplink, ""}
-	msg, err := json.Marshal(dataUp)
+	msg, err := types.MarshalUplinkMessage(format, dataUp)
 	if err != nil {
 		return fmt.Errorf("Unable to marshal the message payload")
 	}
-	return c.publish(key.String(), msg, time.Time(dataUp.Metadata.Time))
+	return c.publishFormat(key.String(), msg, time.Time(dataUp.Metadata.Time), format)
 }
 
 func (s *DefaultSubscriber) handleUplink(messages <-chan AMQP.Delivery, handler UplinkHandler) {
 	for delivery := range messages {
 		dataUp := &types.UplinkMessage{}
-		if err := json.Unmarshal(delivery.Body, dataUp); err != nil {
+		if err := types.UnmarshalUplinkMessage(delivery.Body, dataUp); err != nil {
 			s.ctx.Warnf("Could not unmarshal uplink (%s)", err)
 			continue
 		}
@@ -67,3 +67,40 @@ func TestSubscribeUplink(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestSubscribeUplinkFormat(t *testing.T) {
+	a := New(t)
+	c := NewClient(getLogger(t, "TestSubscribeUplinkFormat"), "guest", "guest", host)
+	err := c.Connect()
+	a.So(err, ShouldBeNil)
+	defer c.Disconnect()
+
+	p := c.NewPublisher("amq.topic")
+	err = p.Open()
+	a.So(err, ShouldBeNil)
+	defer p.Close()
+
+	s := c.NewSubscriber("amq.topic", "", false, true)
+	err = s.Open()
+	a.So(err, ShouldBeNil)
+	defer s.Close()
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	err = s.SubscribeDeviceUplink("app", "format", func(_ Subscriber, appID, devID string, req types.UplinkMessage) {
+		a.So(appID, ShouldEqual, "app")
+		a.So(devID, ShouldEqual, "format")
+		a.So(req.PayloadRaw, ShouldResemble, []byte{0x01, 0x08})
+		wg.Done()
+	})
+	a.So(err, ShouldBeNil)
+
+	err = p.PublishUplinkFormat(types.UplinkMessage{
+		AppID:      "app",
+		DevID:      "format",
+		PayloadRaw: []byte{0x01, 0x08},
+	}, types.PayloadFormatCBOR)
+	a.So(err, ShouldBeNil)
+
+	wg.Wait()
+}
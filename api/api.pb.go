@@ -13,6 +13,8 @@
 		Rates
 		SystemStats
 		ComponentStats
+		LogsRequest
+		LogEntry
 */
 package api
 
@@ -377,6 +379,108 @@ func (m *ComponentStats_MemoryStats) GetSwap() uint64 {
 	return 0
 }
 
+// LogsRequest selects the log entries that a component streams to a network
+// operator. Empty fields match all entries.
+type LogsRequest struct {
+	// Minimum level of the entries (debug, info, warn, error or fatal; default debug)
+	Level     string `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	DevEui    string `protobuf:"bytes,2,opt,name=dev_eui,json=devEui,proto3" json:"dev_eui,omitempty"`
+	AppEui    string `protobuf:"bytes,3,opt,name=app_eui,json=appEui,proto3" json:"app_eui,omitempty"`
+	GatewayId string `protobuf:"bytes,4,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`
+	AppId     string `protobuf:"bytes,5,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	DevId     string `protobuf:"bytes,6,opt,name=dev_id,json=devId,proto3" json:"dev_id,omitempty"`
+}
+
+func (m *LogsRequest) Reset()                    { *m = LogsRequest{} }
+func (m *LogsRequest) String() string            { return proto.CompactTextString(m) }
+func (*LogsRequest) ProtoMessage()               {}
+func (*LogsRequest) Descriptor() ([]byte, []int) { return fileDescriptorApi, []int{4} }
+
+func (m *LogsRequest) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *LogsRequest) GetDevEui() string {
+	if m != nil {
+		return m.DevEui
+	}
+	return ""
+}
+
+func (m *LogsRequest) GetAppEui() string {
+	if m != nil {
+		return m.AppEui
+	}
+	return ""
+}
+
+func (m *LogsRequest) GetGatewayId() string {
+	if m != nil {
+		return m.GatewayId
+	}
+	return ""
+}
+
+func (m *LogsRequest) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *LogsRequest) GetDevId() string {
+	if m != nil {
+		return m.DevId
+	}
+	return ""
+}
+
+// LogEntry is a log entry of a component
+type LogEntry struct {
+	// Time of the entry (Unix nanoseconds)
+	Time    int64  `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Level   string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// JSON-encoded fields of the entry
+	Fields string `protobuf:"bytes,4,opt,name=fields,proto3" json:"fields,omitempty"`
+}
+
+func (m *LogEntry) Reset()                    { *m = LogEntry{} }
+func (m *LogEntry) String() string            { return proto.CompactTextString(m) }
+func (*LogEntry) ProtoMessage()               {}
+func (*LogEntry) Descriptor() ([]byte, []int) { return fileDescriptorApi, []int{5} }
+
+func (m *LogEntry) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *LogEntry) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *LogEntry) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *LogEntry) GetFields() string {
+	if m != nil {
+		return m.Fields
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Percentiles)(nil), "api.Percentiles")
 	proto.RegisterType((*Rates)(nil), "api.Rates")
@@ -387,6 +491,8 @@ func init() {
 	proto.RegisterType((*ComponentStats)(nil), "api.ComponentStats")
 	proto.RegisterType((*ComponentStats_CPUStats)(nil), "api.ComponentStats.CPUStats")
 	proto.RegisterType((*ComponentStats_MemoryStats)(nil), "api.ComponentStats.MemoryStats")
+	proto.RegisterType((*LogsRequest)(nil), "api.LogsRequest")
+	proto.RegisterType((*LogEntry)(nil), "api.LogEntry")
 }
 func (m *Percentiles) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -745,6 +851,101 @@ func (m *ComponentStats_MemoryStats) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *LogsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LogsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Level) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Level)))
+		i += copy(dAtA[i:], m.Level)
+	}
+	if len(m.DevEui) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.DevEui)))
+		i += copy(dAtA[i:], m.DevEui)
+	}
+	if len(m.AppEui) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.AppEui)))
+		i += copy(dAtA[i:], m.AppEui)
+	}
+	if len(m.GatewayId) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.GatewayId)))
+		i += copy(dAtA[i:], m.GatewayId)
+	}
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
+	}
+	if len(m.DevId) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.DevId)))
+		i += copy(dAtA[i:], m.DevId)
+	}
+	return i, nil
+}
+
+func (m *LogEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LogEntry) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Time != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(m.Time))
+	}
+	if len(m.Level) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Level)))
+		i += copy(dAtA[i:], m.Level)
+	}
+	if len(m.Message) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Message)))
+		i += copy(dAtA[i:], m.Message)
+	}
+	if len(m.Fields) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Fields)))
+		i += copy(dAtA[i:], m.Fields)
+	}
+	return i, nil
+}
+
 func encodeFixed64Api(dAtA []byte, offset int, v uint64) int {
 	dAtA[offset] = uint8(v)
 	dAtA[offset+1] = uint8(v >> 8)
@@ -933,6 +1134,57 @@ func (m *ComponentStats_MemoryStats) Size() (n int) {
 	return n
 }
 
+func (m *LogsRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Level)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.DevEui)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.AppEui)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.GatewayId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+
+func (m *LogEntry) Size() (n int) {
+	var l int
+	_ = l
+	if m.Time != 0 {
+		n += 1 + sovApi(uint64(m.Time))
+	}
+	l = len(m.Level)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Fields)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+
 func sovApi(x uint64) (n int) {
 	for {
 		n++
@@ -2002,6 +2254,386 @@ func (m *ComponentStats_MemoryStats) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *LogsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LogsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LogsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Level = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevEui", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DevEui = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppEui", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppEui = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GatewayId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GatewayId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DevId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LogEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LogEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LogEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			m.Time = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Time |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Level = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Fields = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipApi(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -2110,39 +2742,48 @@ var (
 func init() { proto.RegisterFile("github.com/TheThingsNetwork/ttn/api/api.proto", fileDescriptorApi) }
 
 var fileDescriptorApi = []byte{
-	// 539 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x54, 0xcf, 0x6a, 0xdb, 0x4e,
-	0x10, 0xc6, 0xb6, 0xec, 0x5f, 0x3c, 0xfa, 0xb5, 0x85, 0xa5, 0x18, 0xd5, 0x04, 0x37, 0xb8, 0x50,
-	0x0a, 0xa5, 0xb2, 0xe3, 0x56, 0x18, 0x5d, 0x6b, 0xe8, 0xa1, 0x7f, 0x83, 0x92, 0x5c, 0x7a, 0x09,
-	0x6b, 0x79, 0x6b, 0x8b, 0x4a, 0xda, 0x45, 0xbb, 0x6a, 0xc8, 0x7b, 0xf4, 0xa1, 0x7a, 0x29, 0xf4,
-	0x11, 0x82, 0x9f, 0xa4, 0xec, 0x68, 0xad, 0xca, 0xeb, 0x1c, 0x7a, 0xe8, 0x41, 0x30, 0xdf, 0xec,
-	0x37, 0xdf, 0xce, 0x7c, 0x83, 0x16, 0x5e, 0xac, 0x13, 0xb5, 0x29, 0x97, 0x7e, 0xcc, 0xb3, 0xc9,
-	0xc5, 0x86, 0x5d, 0x6c, 0x92, 0x7c, 0x2d, 0x3f, 0x32, 0x75, 0xcd, 0x8b, 0xaf, 0x13, 0xa5, 0xf2,
-	0x09, 0x15, 0x89, 0xfe, 0x7c, 0x51, 0x70, 0xc5, 0x49, 0x87, 0x8a, 0x64, 0xfc, 0xb3, 0x0d, 0xee,
-	0x19, 0x2b, 0x62, 0x96, 0xab, 0x24, 0x65, 0x92, 0x9c, 0x80, 0x2b, 0x6a, 0x78, 0xea, 0xb5, 0x4e,
-	0x5a, 0xcf, 0xda, 0x51, 0x33, 0xb5, 0xcf, 0x08, 0xbc, 0xb6, 0xcd, 0x08, 0xc8, 0x18, 0xfe, 0x6f,
-	0x14, 0x4c, 0xbd, 0x0e, 0x52, 0xf6, 0x72, 0xfb, 0x9c, 0x59, 0xe0, 0x39, 0x36, 0x67, 0x66, 0xe9,
-	0x04, 0x53, 0xaf, 0x6b, 0x73, 0x02, 0x4b, 0x67, 0x1e, 0x78, 0x3d, 0x9b, 0x33, 0xb7, 0x74, 0xc2,
-	0xa9, 0xf7, 0x9f, 0xcd, 0x09, 0x2d, 0x9d, 0x30, 0xf0, 0x8e, 0x0e, 0x38, 0xb6, 0x4e, 0xe8, 0xf5,
-	0x0f, 0x38, 0xe1, 0xf8, 0x1d, 0x74, 0x23, 0xaa, 0x98, 0x24, 0x0f, 0xa1, 0x5b, 0x50, 0x55, 0x5b,
-	0x58, 0x81, 0x5d, 0x76, 0x67, 0x5b, 0x05, 0xc8, 0x00, 0x7a, 0x78, 0x1c, 0x18, 0xab, 0x0c, 0x1a,
-	0x7f, 0xef, 0x80, 0x7b, 0x7e, 0x23, 0x15, 0xcb, 0xce, 0x15, 0x55, 0x92, 0xf8, 0xe0, 0xa4, 0x9c,
-	0xae, 0x50, 0xd2, 0x9d, 0x0d, 0x7d, 0xbd, 0xcb, 0xc6, 0xb9, 0xff, 0x9e, 0xd3, 0x95, 0xd4, 0x51,
-	0x84, 0x3c, 0xf2, 0x1c, 0x3a, 0xb1, 0x28, 0xf1, 0x2e, 0x77, 0xf6, 0xe8, 0x80, 0xbe, 0x38, 0xbb,
-	0xc4, 0x20, 0xd2, 0x2c, 0xf2, 0x0a, 0x7a, 0x19, 0xcb, 0x78, 0x71, 0x83, 0x4d, 0xb8, 0xb3, 0xe3,
-	0x03, 0xfe, 0x07, 0x3c, 0xae, 0x4a, 0x0c, 0x77, 0xf8, 0x09, 0xfa, 0xf5, 0xad, 0x7a, 0x3a, 0x7d,
-	0x6f, 0x3d, 0x33, 0x82, 0x5d, 0xb6, 0x9e, 0x19, 0x81, 0x9e, 0x19, 0x8f, 0xeb, 0x99, 0x2b, 0x34,
-	0x7c, 0x0b, 0x47, 0xbb, 0xbe, 0x08, 0x01, 0xa7, 0x94, 0xac, 0x30, 0x72, 0x18, 0xeb, 0x3a, 0x89,
-	0x3d, 0x19, 0x39, 0x83, 0x34, 0x37, 0x59, 0xa5, 0xcc, 0xa8, 0x61, 0x3c, 0xbc, 0x04, 0xb7, 0xd1,
-	0xb3, 0x6e, 0x44, 0x71, 0x45, 0x53, 0xd4, 0x73, 0xa2, 0x0a, 0x90, 0x63, 0xe8, 0xd3, 0x6f, 0x34,
-	0x49, 0xe9, 0x32, 0x65, 0xa8, 0xe9, 0x44, 0x7f, 0x12, 0xa6, 0x85, 0x15, 0xca, 0x3a, 0xd8, 0xc2,
-	0x6a, 0x7c, 0xdb, 0x86, 0xfb, 0x0b, 0x9e, 0x09, 0x9e, 0xb3, 0x5c, 0x55, 0xd2, 0x03, 0xe8, 0x95,
-	0x42, 0x25, 0x19, 0x33, 0xda, 0x06, 0x11, 0xbf, 0xb9, 0x81, 0xca, 0xd1, 0xfd, 0x4a, 0x6b, 0x09,
-	0x73, 0x6b, 0x09, 0x8f, 0xef, 0x2a, 0xb9, 0x63, 0x0f, 0x64, 0x04, 0xb0, 0xe6, 0x05, 0x2f, 0x55,
-	0x92, 0x33, 0x89, 0x7f, 0x93, 0x13, 0x35, 0x32, 0xe4, 0x29, 0x3c, 0x58, 0xc7, 0x57, 0xb1, 0x28,
-	0xaf, 0xbe, 0x14, 0x34, 0x56, 0x09, 0xcf, 0xcd, 0xef, 0x74, 0x6f, 0x1d, 0x2f, 0x44, 0xf9, 0xc6,
-	0x24, 0xff, 0xa9, 0xfd, 0xe1, 0xbe, 0xfd, 0x83, 0x7a, 0x36, 0xe3, 0x91, 0x69, 0x9d, 0x80, 0x23,
-	0xaf, 0xa9, 0x30, 0xde, 0x63, 0xfc, 0xfa, 0xf4, 0xc7, 0x76, 0xd4, 0xfa, 0xb5, 0x1d, 0xb5, 0x6e,
-	0xb7, 0xa3, 0xd6, 0xe7, 0x27, 0x7f, 0xf1, 0xb0, 0x2d, 0x7b, 0xf8, 0xaa, 0xbd, 0xfc, 0x1d, 0x00,
-	0x00, 0xff, 0xff, 0xa7, 0x5b, 0x2f, 0xb0, 0x06, 0x05, 0x00, 0x00,
+	// 673 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x54, 0xdd, 0x6e, 0xd3, 0x4c,
+	0x10, 0x55, 0x12, 0x27, 0xad, 0xc7, 0xdf, 0x8f, 0xb4, 0xfa, 0xbe, 0x62, 0xa2, 0x12, 0xaa, 0x20,
+	0x21, 0x24, 0x44, 0xda, 0x06, 0xac, 0x2a, 0xb7, 0x54, 0x45, 0x2a, 0x14, 0xa8, 0xdc, 0xf6, 0x86,
+	0x9b, 0x68, 0x1b, 0x6f, 0xdd, 0x15, 0xb6, 0x77, 0xf1, 0xae, 0x53, 0xf5, 0x3d, 0x78, 0x02, 0x9e,
+	0x86, 0x1b, 0x24, 0x1e, 0xa1, 0xea, 0x93, 0xa0, 0x1d, 0x6f, 0x5c, 0xc7, 0xe9, 0x05, 0x17, 0x5c,
+	0x44, 0xda, 0x33, 0x73, 0xe6, 0xec, 0x99, 0x99, 0x78, 0xe1, 0x45, 0xcc, 0xf5, 0x65, 0x71, 0x3e,
+	0x9a, 0x89, 0x74, 0xfb, 0xf4, 0x92, 0x9d, 0x5e, 0xf2, 0x2c, 0x56, 0x1f, 0x98, 0xbe, 0x12, 0xf9,
+	0xe7, 0x6d, 0xad, 0xb3, 0x6d, 0x2a, 0xb9, 0xf9, 0x8d, 0x64, 0x2e, 0xb4, 0x20, 0x1d, 0x2a, 0xf9,
+	0xf0, 0x47, 0x1b, 0xbc, 0x63, 0x96, 0xcf, 0x58, 0xa6, 0x79, 0xc2, 0x14, 0xd9, 0x02, 0x4f, 0x56,
+	0x70, 0xd7, 0x6f, 0x6d, 0xb5, 0x9e, 0xb5, 0xc3, 0x7a, 0x68, 0x99, 0x11, 0xf8, 0xed, 0x26, 0x23,
+	0x20, 0x43, 0xf8, 0xab, 0x56, 0xb0, 0xe3, 0x77, 0x90, 0xb2, 0x14, 0x5b, 0xe6, 0x8c, 0x03, 0xdf,
+	0x69, 0x72, 0xc6, 0x0d, 0x9d, 0x60, 0xc7, 0xef, 0x36, 0x39, 0x41, 0x43, 0x67, 0x2f, 0xf0, 0x7b,
+	0x4d, 0xce, 0x5e, 0x43, 0x67, 0xb2, 0xe3, 0xaf, 0x35, 0x39, 0x93, 0x86, 0xce, 0x24, 0xf0, 0xd7,
+	0x57, 0x38, 0x4d, 0x9d, 0x89, 0xef, 0xae, 0x70, 0x26, 0xc3, 0x77, 0xd0, 0x0d, 0xa9, 0x66, 0x8a,
+	0xfc, 0x07, 0xdd, 0x9c, 0xea, 0x6a, 0x84, 0x25, 0x58, 0x44, 0x17, 0x63, 0x2b, 0x01, 0xd9, 0x80,
+	0x1e, 0xa6, 0x03, 0x3b, 0x2a, 0x8b, 0x86, 0x5f, 0x3b, 0xe0, 0x9d, 0x5c, 0x2b, 0xcd, 0xd2, 0x13,
+	0x4d, 0xb5, 0x22, 0x23, 0x70, 0x12, 0x41, 0x23, 0x94, 0xf4, 0xc6, 0xfd, 0x91, 0xd9, 0x65, 0x2d,
+	0x3f, 0x3a, 0x12, 0x34, 0x52, 0xe6, 0x14, 0x22, 0x8f, 0x3c, 0x87, 0xce, 0x4c, 0x16, 0x78, 0x97,
+	0x37, 0x7e, 0xb8, 0x42, 0xdf, 0x3f, 0x3e, 0xc3, 0x43, 0x68, 0x58, 0xe4, 0x15, 0xf4, 0x52, 0x96,
+	0x8a, 0xfc, 0x1a, 0x4d, 0x78, 0xe3, 0xcd, 0x15, 0xfe, 0x7b, 0x4c, 0x97, 0x25, 0x96, 0xdb, 0xff,
+	0x08, 0x6e, 0x75, 0xab, 0xe9, 0xce, 0xdc, 0x5b, 0xf5, 0x8c, 0x60, 0x11, 0xad, 0x7a, 0x46, 0x60,
+	0x7a, 0xc6, 0x74, 0xd5, 0x73, 0x89, 0xfa, 0x6f, 0x61, 0x7d, 0xe1, 0x8b, 0x10, 0x70, 0x0a, 0xc5,
+	0x72, 0x2b, 0x87, 0x67, 0x53, 0xa7, 0xd0, 0x93, 0x95, 0xb3, 0xc8, 0x70, 0x79, 0x94, 0x30, 0xab,
+	0x86, 0xe7, 0xfe, 0x19, 0x78, 0x35, 0xcf, 0xc6, 0x88, 0x16, 0x9a, 0x26, 0xa8, 0xe7, 0x84, 0x25,
+	0x20, 0x9b, 0xe0, 0xd2, 0x39, 0xe5, 0x09, 0x3d, 0x4f, 0x18, 0x6a, 0x3a, 0xe1, 0x5d, 0xc0, 0x5a,
+	0x88, 0x50, 0xd6, 0x41, 0x0b, 0xd1, 0xf0, 0xa6, 0x0d, 0xff, 0xec, 0x8b, 0x54, 0x8a, 0x8c, 0x65,
+	0xba, 0x94, 0xde, 0x80, 0x5e, 0x21, 0x35, 0x4f, 0x99, 0xd5, 0xb6, 0x88, 0x8c, 0xea, 0x1b, 0x28,
+	0x27, 0xba, 0x5c, 0xd9, 0x58, 0xc2, 0x5e, 0x63, 0x09, 0x8f, 0xef, 0x2b, 0xb9, 0x67, 0x0f, 0x64,
+	0x00, 0x10, 0x8b, 0x5c, 0x14, 0x9a, 0x67, 0x4c, 0xe1, 0xd7, 0xe4, 0x84, 0xb5, 0x08, 0x79, 0x0a,
+	0xff, 0xc6, 0xb3, 0xe9, 0x4c, 0x16, 0xd3, 0x8b, 0x9c, 0xce, 0x34, 0x17, 0x99, 0xfd, 0x9c, 0xfe,
+	0x8e, 0x67, 0xfb, 0xb2, 0x78, 0x63, 0x83, 0x7f, 0x74, 0xfc, 0x93, 0xe5, 0xf1, 0x6f, 0x54, 0xbd,
+	0xd9, 0x19, 0x59, 0xeb, 0x04, 0x1c, 0x75, 0x45, 0xa5, 0x9d, 0x3d, 0x9e, 0x87, 0xdf, 0x5a, 0xe0,
+	0x1d, 0x89, 0x58, 0x85, 0xec, 0x4b, 0xc1, 0x94, 0xc6, 0xff, 0x10, 0x9b, 0xb3, 0x72, 0x75, 0x6e,
+	0x58, 0x02, 0xf2, 0x00, 0xd6, 0x22, 0x36, 0x9f, 0xb2, 0x82, 0x63, 0xb1, 0x1b, 0xf6, 0x22, 0x36,
+	0x3f, 0x28, 0xb8, 0x49, 0x50, 0x29, 0x31, 0xd1, 0x29, 0x13, 0x54, 0x4a, 0x93, 0x78, 0x04, 0x10,
+	0x53, 0xcd, 0xae, 0xe8, 0xf5, 0x94, 0x47, 0x38, 0x26, 0x37, 0x74, 0x6d, 0xe4, 0x30, 0x22, 0xff,
+	0x83, 0x21, 0x9a, 0x54, 0xb7, 0xbc, 0x87, 0x4a, 0x59, 0x86, 0xcd, 0x3d, 0x3c, 0xc2, 0xe7, 0xc5,
+	0x0d, 0xbb, 0x11, 0x9b, 0x1f, 0x46, 0xc3, 0x0b, 0x58, 0x3f, 0x12, 0xf1, 0x41, 0xa6, 0xcb, 0x26,
+	0xaa, 0xf5, 0x77, 0x42, 0x3c, 0xdf, 0x99, 0x6e, 0xd7, 0x4d, 0xfb, 0xb0, 0x96, 0x32, 0xa5, 0x68,
+	0xcc, 0xac, 0xb7, 0x05, 0x34, 0x03, 0xba, 0xe0, 0x2c, 0x89, 0x94, 0x35, 0x66, 0xd1, 0xeb, 0xdd,
+	0xef, 0xb7, 0x83, 0xd6, 0xcf, 0xdb, 0x41, 0xeb, 0xe6, 0x76, 0xd0, 0xfa, 0xf4, 0xe4, 0x37, 0x5e,
+	0xf9, 0xf3, 0x1e, 0x3e, 0xf1, 0x2f, 0x7f, 0x0d, 0x00, 0xc3, 0x17, 0x1f, 0xf6, 0x13, 0x06, 0x00,
+	0x00,
 }
@@ -0,0 +1,80 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"sync"
+	"time"
+)
+
+// AckInterval is the interval at which a Handler acknowledges the uplink
+// messages that it handled
+var AckInterval = time.Second
+
+// uplinkAcks keeps track of the sequence numbers of the uplink messages that
+// were received on a Subscribe stream, but are not handled yet. The Broker
+// replays the messages that were not acknowledged when the Handler subscribes
+// again.
+type uplinkAcks struct {
+	sync.Mutex
+	pending  map[uint64]struct{}
+	received uint64 // highest sequence number that was received
+	acked    uint64 // sequence number that was acknowledged to the Broker
+}
+
+func newUplinkAcks() *uplinkAcks {
+	return &uplinkAcks{pending: make(map[uint64]struct{})}
+}
+
+// reset forgets the received messages when the stream restarts, as the Broker
+// replays the messages that were not acknowledged. It returns the sequence
+// number that was acknowledged.
+func (a *uplinkAcks) reset() uint64 {
+	a.Lock()
+	defer a.Unlock()
+	acked := a.acked
+	a.pending = make(map[uint64]struct{})
+	a.received, a.acked = 0, 0
+	return acked
+}
+
+// receive records that the message with the sequence number was received
+func (a *uplinkAcks) receive(seq uint64) {
+	a.Lock()
+	defer a.Unlock()
+	a.pending[seq] = struct{}{}
+	if seq > a.received {
+		a.received = seq
+	}
+}
+
+// done records that the message with the sequence number was handled
+func (a *uplinkAcks) done(seq uint64) {
+	a.Lock()
+	defer a.Unlock()
+	delete(a.pending, seq)
+}
+
+// next returns the sequence number up to which all received messages were
+// handled, and false if it was already acknowledged
+func (a *uplinkAcks) next() (uint64, bool) {
+	a.Lock()
+	defer a.Unlock()
+	seq := a.received
+	for pending := range a.pending {
+		if pending <= seq {
+			seq = pending - 1
+		}
+	}
+	return seq, seq > a.acked
+}
+
+// setAcked records that the messages up to the sequence number were acknowledged
+func (a *uplinkAcks) setAcked(seq uint64) {
+	a.Lock()
+	defer a.Unlock()
+	if seq > a.acked {
+		a.acked = seq
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestUplinkAcks(t *testing.T) {
+	a := New(t)
+	acks := newUplinkAcks()
+
+	_, ok := acks.next()
+	a.So(ok, ShouldBeFalse)
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		acks.receive(seq)
+	}
+
+	// The second message is still being handled
+	acks.done(1)
+	acks.done(3)
+	seq, ok := acks.next()
+	a.So(ok, ShouldBeTrue)
+	a.So(seq, ShouldEqual, 1)
+	acks.setAcked(seq)
+
+	_, ok = acks.next()
+	a.So(ok, ShouldBeFalse)
+
+	acks.done(2)
+	seq, ok = acks.next()
+	a.So(ok, ShouldBeTrue)
+	a.So(seq, ShouldEqual, 3)
+	acks.setAcked(seq)
+
+	a.So(acks.reset(), ShouldEqual, 3)
+	_, ok = acks.next()
+	a.So(ok, ShouldBeFalse)
+}
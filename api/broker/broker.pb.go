@@ -10,15 +10,25 @@
 
 	It has these top-level messages:
 		DownlinkOption
+		RXSettings
 		UplinkMessage
 		DownlinkMessage
 		DeviceActivationResponse
 		DeduplicatedUplinkMessage
+		PolicyViolation
+		ReplayAttack
+		Quarantine
+		LinkQualityChange
+		DeviceReset
+		RelayMetadata
+		FCntRegression
 		DeviceActivationRequest
 		DeduplicatedDeviceActivationRequest
+		JoinLoop
 		ActivationChallengeRequest
 		ActivationChallengeResponse
 		SubscribeRequest
+		SubscribeAck
 		StatusRequest
 		Status
 		ApplicationHandlerRegistration
@@ -66,6 +76,9 @@ type DownlinkOption struct {
 	Deadline       int64                     `protobuf:"varint,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
 	ProtocolConfig *protocol.TxConfiguration `protobuf:"bytes,5,opt,name=protocol_config,json=protocolConfig" json:"protocol_config,omitempty"`
 	GatewayConfig  *gateway.TxConfiguration  `protobuf:"bytes,6,opt,name=gateway_config,json=gatewayConfig" json:"gateway_config,omitempty"`
+	// Receive window parameters of the device, set by the Handler if they differ from the defaults of the band.
+	// The Router builds the option again with these parameters before it reserves the transmission slot.
+	RxSettings *RXSettings `protobuf:"bytes,7,opt,name=rx_settings,json=rxSettings" json:"rx_settings,omitempty"`
 }
 
 func (m *DownlinkOption) Reset()                    { *m = DownlinkOption{} }
@@ -115,6 +128,58 @@ func (m *DownlinkOption) GetGatewayConfig() *gateway.TxConfiguration {
 	return nil
 }
 
+func (m *DownlinkOption) GetRxSettings() *RXSettings {
+	if m != nil {
+		return m.RxSettings
+	}
+	return nil
+}
+
+// Receive window parameters of a device
+type RXSettings struct {
+	// Delay of RX1 in seconds (0 for the default of the band)
+	Rx1Delay uint32 `protobuf:"varint,1,opt,name=rx1_delay,json=rx1Delay,proto3" json:"rx1_delay,omitempty"`
+	// Data rate offset of RX1
+	Rx1DrOffset uint32 `protobuf:"varint,2,opt,name=rx1_dr_offset,json=rx1DrOffset,proto3" json:"rx1_dr_offset,omitempty"`
+	// Data rate of RX2 (empty for the default of the band)
+	Rx2DataRate string `protobuf:"bytes,3,opt,name=rx2_data_rate,json=rx2DataRate,proto3" json:"rx2_data_rate,omitempty"`
+	// The downlink is sent in RX2 only
+	Rx2Only bool `protobuf:"varint,4,opt,name=rx2_only,json=rx2Only,proto3" json:"rx2_only,omitempty"`
+}
+
+func (m *RXSettings) Reset()                    { *m = RXSettings{} }
+func (m *RXSettings) String() string            { return proto.CompactTextString(m) }
+func (*RXSettings) ProtoMessage()               {}
+func (*RXSettings) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{1} }
+
+func (m *RXSettings) GetRx1Delay() uint32 {
+	if m != nil {
+		return m.Rx1Delay
+	}
+	return 0
+}
+
+func (m *RXSettings) GetRx1DrOffset() uint32 {
+	if m != nil {
+		return m.Rx1DrOffset
+	}
+	return 0
+}
+
+func (m *RXSettings) GetRx2DataRate() string {
+	if m != nil {
+		return m.Rx2DataRate
+	}
+	return ""
+}
+
+func (m *RXSettings) GetRx2Only() bool {
+	if m != nil {
+		return m.Rx2Only
+	}
+	return false
+}
+
 // received from the Router
 type UplinkMessage struct {
 	Payload          []byte                                             `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
@@ -126,13 +191,16 @@ type UplinkMessage struct {
 	ProtocolMetadata *protocol.RxMetadata                               `protobuf:"bytes,21,opt,name=protocol_metadata,json=protocolMetadata" json:"protocol_metadata,omitempty"`
 	GatewayMetadata  *gateway.RxMetadata                                `protobuf:"bytes,22,opt,name=gateway_metadata,json=gatewayMetadata" json:"gateway_metadata,omitempty"`
 	DownlinkOptions  []*DownlinkOption                                  `protobuf:"bytes,31,rep,name=downlink_options,json=downlinkOptions" json:"downlink_options,omitempty"`
-	Trace            *trace.Trace                                       `protobuf:"bytes,41,opt,name=trace" json:"trace,omitempty"`
+	// Set by the Router if the uplink was spooled while the Broker was unreachable. The receive windows of the
+	// device have passed, so the Broker does not send a downlink in response.
+	Delayed bool         `protobuf:"varint,32,opt,name=delayed,proto3" json:"delayed,omitempty"`
+	Trace   *trace.Trace `protobuf:"bytes,41,opt,name=trace" json:"trace,omitempty"`
 }
 
 func (m *UplinkMessage) Reset()                    { *m = UplinkMessage{} }
 func (m *UplinkMessage) String() string            { return proto.CompactTextString(m) }
 func (*UplinkMessage) ProtoMessage()               {}
-func (*UplinkMessage) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{1} }
+func (*UplinkMessage) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{2} }
 
 func (m *UplinkMessage) GetPayload() []byte {
 	if m != nil {
@@ -183,6 +251,13 @@ func (m *UplinkMessage) GetDownlinkOptions() []*DownlinkOption {
 	return nil
 }
 
+func (m *UplinkMessage) GetDelayed() bool {
+	if m != nil {
+		return m.Delayed
+	}
+	return false
+}
+
 func (m *UplinkMessage) GetTrace() *trace.Trace {
 	if m != nil {
 		return m.Trace
@@ -199,13 +274,17 @@ type DownlinkMessage struct {
 	AppId          string                                             `protobuf:"bytes,13,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
 	DevId          string                                             `protobuf:"bytes,14,opt,name=dev_id,json=devId,proto3" json:"dev_id,omitempty"`
 	DownlinkOption *DownlinkOption                                    `protobuf:"bytes,21,opt,name=downlink_option,json=downlinkOption" json:"downlink_option,omitempty"`
-	Trace          *trace.Trace                                       `protobuf:"bytes,31,opt,name=trace" json:"trace,omitempty"`
+	// Priority of the downlink when it conflicts with other downlinks on the gateway (low, normal, high or mac)
+	Priority string `protobuf:"bytes,22,opt,name=priority,proto3" json:"priority,omitempty"`
+	// Set by the NetworkServer if the pending MAC commands of the device were added when it handled the uplink
+	MacCommandsAdded bool         `protobuf:"varint,23,opt,name=mac_commands_added,json=macCommandsAdded,proto3" json:"mac_commands_added,omitempty"`
+	Trace            *trace.Trace `protobuf:"bytes,31,opt,name=trace" json:"trace,omitempty"`
 }
 
 func (m *DownlinkMessage) Reset()                    { *m = DownlinkMessage{} }
 func (m *DownlinkMessage) String() string            { return proto.CompactTextString(m) }
 func (*DownlinkMessage) ProtoMessage()               {}
-func (*DownlinkMessage) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{2} }
+func (*DownlinkMessage) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{3} }
 
 func (m *DownlinkMessage) GetPayload() []byte {
 	if m != nil {
@@ -242,6 +321,20 @@ func (m *DownlinkMessage) GetDownlinkOption() *DownlinkOption {
 	return nil
 }
 
+func (m *DownlinkMessage) GetPriority() string {
+	if m != nil {
+		return m.Priority
+	}
+	return ""
+}
+
+func (m *DownlinkMessage) GetMacCommandsAdded() bool {
+	if m != nil {
+		return m.MacCommandsAdded
+	}
+	return false
+}
+
 func (m *DownlinkMessage) GetTrace() *trace.Trace {
 	if m != nil {
 		return m.Trace
@@ -260,7 +353,7 @@ type DeviceActivationResponse struct {
 func (m *DeviceActivationResponse) Reset()                    { *m = DeviceActivationResponse{} }
 func (m *DeviceActivationResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeviceActivationResponse) ProtoMessage()               {}
-func (*DeviceActivationResponse) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{3} }
+func (*DeviceActivationResponse) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{4} }
 
 func (m *DeviceActivationResponse) GetPayload() []byte {
 	if m != nil {
@@ -303,12 +396,36 @@ type DeduplicatedUplinkMessage struct {
 	ServerTime       int64                                              `protobuf:"varint,23,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
 	ResponseTemplate *DownlinkMessage                                   `protobuf:"bytes,31,opt,name=response_template,json=responseTemplate" json:"response_template,omitempty"`
 	Trace            *trace.Trace                                       `protobuf:"bytes,41,opt,name=trace" json:"trace,omitempty"`
+	// Policy violations of the device, added by the NetworkServer
+	PolicyViolations []*PolicyViolation `protobuf:"bytes,51,rep,name=policy_violations,json=policyViolations" json:"policy_violations,omitempty"`
+	// Added by the Broker if the uplink is a replay attack
+	ReplayAttack *ReplayAttack `protobuf:"bytes,52,opt,name=replay_attack,json=replayAttack" json:"replay_attack,omitempty"`
+	// Added by the NetworkServer if the device is quarantined
+	Quarantine *Quarantine `protobuf:"bytes,53,opt,name=quarantine" json:"quarantine,omitempty"`
+	// Added by the NetworkServer if the quality level of the link changed
+	LinkQuality *LinkQualityChange `protobuf:"bytes,54,opt,name=link_quality,json=linkQuality" json:"link_quality,omitempty"`
+	// Added by the NetworkServer if the device indicated that it was reset
+	DeviceReset *DeviceReset `protobuf:"bytes,55,opt,name=device_reset,json=deviceReset" json:"device_reset,omitempty"`
+	// Added by the NetworkServer if the device is a relay that forwards the uplink of an end device
+	RelayForward bool `protobuf:"varint,56,opt,name=relay_forward,json=relayForward,proto3" json:"relay_forward,omitempty"`
+	// Added by the Broker if the uplink was forwarded by a relay
+	Relay *RelayMetadata `protobuf:"bytes,57,opt,name=relay" json:"relay,omitempty"`
+	// Added by the Broker if the frame counter is lower than the last frame counter of the device
+	FcntRegression *FCntRegression `protobuf:"bytes,58,opt,name=fcnt_regression,json=fcntRegression" json:"fcnt_regression,omitempty"`
+	// Added by the Broker if no gateway can send a downlink in response to the uplink
+	NoDownlink bool `protobuf:"varint,59,opt,name=no_downlink,json=noDownlink,proto3" json:"no_downlink,omitempty"`
+	// All downlink options of the uplink, best first, added by the Broker so that the Handler can select another
+	// option than the response template if the downlink has scheduling hints
+	DownlinkOptions []*DownlinkOption `protobuf:"bytes,60,rep,name=downlink_options,json=downlinkOptions" json:"downlink_options,omitempty"`
+	// Sequence number of the uplink on the Subscribe stream, added by the Broker if it keeps uplinks for replay.
+	// The Handler acknowledges the uplinks that it handled with this sequence number.
+	Seq uint64 `protobuf:"varint,61,opt,name=seq,proto3" json:"seq,omitempty"`
 }
 
 func (m *DeduplicatedUplinkMessage) Reset()                    { *m = DeduplicatedUplinkMessage{} }
 func (m *DeduplicatedUplinkMessage) String() string            { return proto.CompactTextString(m) }
 func (*DeduplicatedUplinkMessage) ProtoMessage()               {}
-func (*DeduplicatedUplinkMessage) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{4} }
+func (*DeduplicatedUplinkMessage) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{5} }
 
 func (m *DeduplicatedUplinkMessage) GetPayload() []byte {
 	if m != nil {
@@ -373,6 +490,283 @@ func (m *DeduplicatedUplinkMessage) GetTrace() *trace.Trace {
 	return nil
 }
 
+func (m *DeduplicatedUplinkMessage) GetPolicyViolations() []*PolicyViolation {
+	if m != nil {
+		return m.PolicyViolations
+	}
+	return nil
+}
+
+func (m *DeduplicatedUplinkMessage) GetReplayAttack() *ReplayAttack {
+	if m != nil {
+		return m.ReplayAttack
+	}
+	return nil
+}
+
+func (m *DeduplicatedUplinkMessage) GetQuarantine() *Quarantine {
+	if m != nil {
+		return m.Quarantine
+	}
+	return nil
+}
+
+func (m *DeduplicatedUplinkMessage) GetLinkQuality() *LinkQualityChange {
+	if m != nil {
+		return m.LinkQuality
+	}
+	return nil
+}
+
+func (m *DeduplicatedUplinkMessage) GetDeviceReset() *DeviceReset {
+	if m != nil {
+		return m.DeviceReset
+	}
+	return nil
+}
+
+func (m *DeduplicatedUplinkMessage) GetRelayForward() bool {
+	if m != nil {
+		return m.RelayForward
+	}
+	return false
+}
+
+func (m *DeduplicatedUplinkMessage) GetRelay() *RelayMetadata {
+	if m != nil {
+		return m.Relay
+	}
+	return nil
+}
+
+func (m *DeduplicatedUplinkMessage) GetFcntRegression() *FCntRegression {
+	if m != nil {
+		return m.FcntRegression
+	}
+	return nil
+}
+
+func (m *DeduplicatedUplinkMessage) GetNoDownlink() bool {
+	if m != nil {
+		return m.NoDownlink
+	}
+	return false
+}
+
+func (m *DeduplicatedUplinkMessage) GetDownlinkOptions() []*DownlinkOption {
+	if m != nil {
+		return m.DownlinkOptions
+	}
+	return nil
+}
+
+func (m *DeduplicatedUplinkMessage) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+// A device violates a policy of the network
+type PolicyViolation struct {
+	Policy    string `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	Violation string `protobuf:"bytes,2,opt,name=violation,proto3" json:"violation,omitempty"`
+	// Time (Unix nanoseconds) since the device violates the policy
+	Since int64 `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+}
+
+func (m *PolicyViolation) Reset()                    { *m = PolicyViolation{} }
+func (m *PolicyViolation) String() string            { return proto.CompactTextString(m) }
+func (*PolicyViolation) ProtoMessage()               {}
+func (*PolicyViolation) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{6} }
+
+func (m *PolicyViolation) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *PolicyViolation) GetViolation() string {
+	if m != nil {
+		return m.Violation
+	}
+	return ""
+}
+
+func (m *PolicyViolation) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+// The same frame was received by gateways that are too far apart
+type ReplayAttack struct {
+	// Distance (meters) between the gateways
+	Distance uint32   `protobuf:"varint,1,opt,name=distance,proto3" json:"distance,omitempty"`
+	Gateways []string `protobuf:"bytes,2,rep,name=gateways" json:"gateways,omitempty"`
+}
+
+func (m *ReplayAttack) Reset()                    { *m = ReplayAttack{} }
+func (m *ReplayAttack) String() string            { return proto.CompactTextString(m) }
+func (*ReplayAttack) ProtoMessage()               {}
+func (*ReplayAttack) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{7} }
+
+func (m *ReplayAttack) GetDistance() uint32 {
+	if m != nil {
+		return m.Distance
+	}
+	return 0
+}
+
+func (m *ReplayAttack) GetGateways() []string {
+	if m != nil {
+		return m.Gateways
+	}
+	return nil
+}
+
+// A device is quarantined after a replay attack
+type Quarantine struct {
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Time (Unix nanoseconds) since the device is quarantined
+	Since int64 `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"`
+}
+
+func (m *Quarantine) Reset()                    { *m = Quarantine{} }
+func (m *Quarantine) String() string            { return proto.CompactTextString(m) }
+func (*Quarantine) ProtoMessage()               {}
+func (*Quarantine) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{8} }
+
+func (m *Quarantine) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Quarantine) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+// The quality level of the link of a device changed
+type LinkQualityChange struct {
+	Quality  string `protobuf:"bytes,1,opt,name=quality,proto3" json:"quality,omitempty"`
+	Previous string `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
+	// Link score from 0 (no link) to 100 (perfect link)
+	Score uint32 `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	// Frame loss (0-1)
+	Loss float64 `protobuf:"fixed64,4,opt,name=loss,proto3" json:"loss,omitempty"`
+}
+
+func (m *LinkQualityChange) Reset()                    { *m = LinkQualityChange{} }
+func (m *LinkQualityChange) String() string            { return proto.CompactTextString(m) }
+func (*LinkQualityChange) ProtoMessage()               {}
+func (*LinkQualityChange) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{9} }
+
+func (m *LinkQualityChange) GetQuality() string {
+	if m != nil {
+		return m.Quality
+	}
+	return ""
+}
+
+func (m *LinkQualityChange) GetPrevious() string {
+	if m != nil {
+		return m.Previous
+	}
+	return ""
+}
+
+func (m *LinkQualityChange) GetScore() uint32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *LinkQualityChange) GetLoss() float64 {
+	if m != nil {
+		return m.Loss
+	}
+	return 0
+}
+
+// An ABP device indicated that it was reset
+type DeviceReset struct {
+	// LoRaWAN version (1.x) that the network answered to the device
+	LorawanVersion string `protobuf:"bytes,1,opt,name=lorawan_version,json=lorawanVersion,proto3" json:"lorawan_version,omitempty"`
+}
+
+func (m *DeviceReset) Reset()                    { *m = DeviceReset{} }
+func (m *DeviceReset) String() string            { return proto.CompactTextString(m) }
+func (*DeviceReset) ProtoMessage()               {}
+func (*DeviceReset) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{10} }
+
+func (m *DeviceReset) GetLorawanVersion() string {
+	if m != nil {
+		return m.LorawanVersion
+	}
+	return ""
+}
+
+// A relay forwarded the uplink of an end device
+type RelayMetadata struct {
+	// Device ID of the relay (in the application of the relay)
+	DevId string `protobuf:"bytes,1,opt,name=dev_id,json=devId,proto3" json:"dev_id,omitempty"`
+	// Wake On Radio channel on which the relay received the uplink
+	WorChannel uint32 `protobuf:"varint,2,opt,name=wor_channel,json=worChannel,proto3" json:"wor_channel,omitempty"`
+}
+
+func (m *RelayMetadata) Reset()                    { *m = RelayMetadata{} }
+func (m *RelayMetadata) String() string            { return proto.CompactTextString(m) }
+func (*RelayMetadata) ProtoMessage()               {}
+func (*RelayMetadata) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{11} }
+
+func (m *RelayMetadata) GetDevId() string {
+	if m != nil {
+		return m.DevId
+	}
+	return ""
+}
+
+func (m *RelayMetadata) GetWorChannel() uint32 {
+	if m != nil {
+		return m.WorChannel
+	}
+	return 0
+}
+
+// The frame counter of a device went back, which happens when multiple devices use the same ABP credentials
+type FCntRegression struct {
+	FCnt uint32 `protobuf:"varint,1,opt,name=f_cnt,json=fCnt,proto3" json:"f_cnt,omitempty"`
+	// Last frame counter of the device
+	LastFCnt uint32 `protobuf:"varint,2,opt,name=last_f_cnt,json=lastFCnt,proto3" json:"last_f_cnt,omitempty"`
+}
+
+func (m *FCntRegression) Reset()                    { *m = FCntRegression{} }
+func (m *FCntRegression) String() string            { return proto.CompactTextString(m) }
+func (*FCntRegression) ProtoMessage()               {}
+func (*FCntRegression) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{12} }
+
+func (m *FCntRegression) GetFCnt() uint32 {
+	if m != nil {
+		return m.FCnt
+	}
+	return 0
+}
+
+func (m *FCntRegression) GetLastFCnt() uint32 {
+	if m != nil {
+		return m.LastFCnt
+	}
+	return 0
+}
+
 // received from the Router
 type DeviceActivationRequest struct {
 	Payload            []byte                                             `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
@@ -389,7 +783,7 @@ type DeviceActivationRequest struct {
 func (m *DeviceActivationRequest) Reset()                    { *m = DeviceActivationRequest{} }
 func (m *DeviceActivationRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeviceActivationRequest) ProtoMessage()               {}
-func (*DeviceActivationRequest) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{5} }
+func (*DeviceActivationRequest) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{13} }
 
 func (m *DeviceActivationRequest) GetPayload() []byte {
 	if m != nil {
@@ -454,13 +848,17 @@ type DeduplicatedDeviceActivationRequest struct {
 	ServerTime         int64                                              `protobuf:"varint,24,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
 	ResponseTemplate   *DeviceActivationResponse                          `protobuf:"bytes,31,opt,name=response_template,json=responseTemplate" json:"response_template,omitempty"`
 	Trace              *trace.Trace                                       `protobuf:"bytes,41,opt,name=trace" json:"trace,omitempty"`
+	// Added by the Broker if the device exceeded its join rate
+	JoinLoop *JoinLoop `protobuf:"bytes,51,opt,name=join_loop,json=joinLoop" json:"join_loop,omitempty"`
+	// Added by the NetworkServer: the gateway groups that the device prefers for downlink, most preferred first
+	GatewayGroups []string `protobuf:"bytes,52,rep,name=gateway_groups,json=gatewayGroups" json:"gateway_groups,omitempty"`
 }
 
 func (m *DeduplicatedDeviceActivationRequest) Reset()         { *m = DeduplicatedDeviceActivationRequest{} }
 func (m *DeduplicatedDeviceActivationRequest) String() string { return proto.CompactTextString(m) }
 func (*DeduplicatedDeviceActivationRequest) ProtoMessage()    {}
 func (*DeduplicatedDeviceActivationRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptorBroker, []int{6}
+	return fileDescriptorBroker, []int{14}
 }
 
 func (m *DeduplicatedDeviceActivationRequest) GetPayload() []byte {
@@ -533,6 +931,38 @@ func (m *DeduplicatedDeviceActivationRequest) GetTrace() *trace.Trace {
 	return nil
 }
 
+func (m *DeduplicatedDeviceActivationRequest) GetJoinLoop() *JoinLoop {
+	if m != nil {
+		return m.JoinLoop
+	}
+	return nil
+}
+
+func (m *DeduplicatedDeviceActivationRequest) GetGatewayGroups() []string {
+	if m != nil {
+		return m.GatewayGroups
+	}
+	return nil
+}
+
+// A device sends join requests faster than the network allows
+type JoinLoop struct {
+	// Duration (nanoseconds) that the join requests of the device are not accepted
+	Penalty int64 `protobuf:"varint,1,opt,name=penalty,proto3" json:"penalty,omitempty"`
+}
+
+func (m *JoinLoop) Reset()                    { *m = JoinLoop{} }
+func (m *JoinLoop) String() string            { return proto.CompactTextString(m) }
+func (*JoinLoop) ProtoMessage()               {}
+func (*JoinLoop) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{15} }
+
+func (m *JoinLoop) GetPenalty() int64 {
+	if m != nil {
+		return m.Penalty
+	}
+	return 0
+}
+
 type ActivationChallengeRequest struct {
 	Payload []byte                                             `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
 	Message *protocol.Message                                  `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
@@ -545,7 +975,7 @@ type ActivationChallengeRequest struct {
 func (m *ActivationChallengeRequest) Reset()                    { *m = ActivationChallengeRequest{} }
 func (m *ActivationChallengeRequest) String() string            { return proto.CompactTextString(m) }
 func (*ActivationChallengeRequest) ProtoMessage()               {}
-func (*ActivationChallengeRequest) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{7} }
+func (*ActivationChallengeRequest) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{16} }
 
 func (m *ActivationChallengeRequest) GetPayload() []byte {
 	if m != nil {
@@ -584,7 +1014,7 @@ func (m *ActivationChallengeResponse) Reset()         { *m = ActivationChallenge
 func (m *ActivationChallengeResponse) String() string { return proto.CompactTextString(m) }
 func (*ActivationChallengeResponse) ProtoMessage()    {}
 func (*ActivationChallengeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptorBroker, []int{8}
+	return fileDescriptorBroker, []int{17}
 }
 
 func (m *ActivationChallengeResponse) GetPayload() []byte {
@@ -608,7 +1038,24 @@ type SubscribeRequest struct {
 func (m *SubscribeRequest) Reset()                    { *m = SubscribeRequest{} }
 func (m *SubscribeRequest) String() string            { return proto.CompactTextString(m) }
 func (*SubscribeRequest) ProtoMessage()               {}
-func (*SubscribeRequest) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{9} }
+func (*SubscribeRequest) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{18} }
+
+// message SubscribeAck is used by a Handler to acknowledge that it handled the uplink messages up to the sequence number
+type SubscribeAck struct {
+	Seq uint64 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+}
+
+func (m *SubscribeAck) Reset()                    { *m = SubscribeAck{} }
+func (m *SubscribeAck) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeAck) ProtoMessage()               {}
+func (*SubscribeAck) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{19} }
+
+func (m *SubscribeAck) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
 
 // message StatusRequest is used to request the status of this Broker
 type StatusRequest struct {
@@ -617,7 +1064,7 @@ type StatusRequest struct {
 func (m *StatusRequest) Reset()                    { *m = StatusRequest{} }
 func (m *StatusRequest) String() string            { return proto.CompactTextString(m) }
 func (*StatusRequest) ProtoMessage()               {}
-func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{10} }
+func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{20} }
 
 type Status struct {
 	System            *api.SystemStats    `protobuf:"bytes,1,opt,name=system" json:"system,omitempty"`
@@ -636,7 +1083,7 @@ type Status struct {
 func (m *Status) Reset()                    { *m = Status{} }
 func (m *Status) String() string            { return proto.CompactTextString(m) }
 func (*Status) ProtoMessage()               {}
-func (*Status) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{11} }
+func (*Status) Descriptor() ([]byte, []int) { return fileDescriptorBroker, []int{21} }
 
 func (m *Status) GetSystem() *api.SystemStats {
 	if m != nil {
@@ -717,7 +1164,7 @@ func (m *ApplicationHandlerRegistration) Reset()         { *m = ApplicationHandl
 func (m *ApplicationHandlerRegistration) String() string { return proto.CompactTextString(m) }
 func (*ApplicationHandlerRegistration) ProtoMessage()    {}
 func (*ApplicationHandlerRegistration) Descriptor() ([]byte, []int) {
-	return fileDescriptorBroker, []int{12}
+	return fileDescriptorBroker, []int{22}
 }
 
 func (m *ApplicationHandlerRegistration) GetAppId() string {
@@ -736,15 +1183,25 @@ func (m *ApplicationHandlerRegistration) GetHandlerId() string {
 
 func init() {
 	proto.RegisterType((*DownlinkOption)(nil), "broker.DownlinkOption")
+	proto.RegisterType((*RXSettings)(nil), "broker.RXSettings")
 	proto.RegisterType((*UplinkMessage)(nil), "broker.UplinkMessage")
 	proto.RegisterType((*DownlinkMessage)(nil), "broker.DownlinkMessage")
 	proto.RegisterType((*DeviceActivationResponse)(nil), "broker.DeviceActivationResponse")
 	proto.RegisterType((*DeduplicatedUplinkMessage)(nil), "broker.DeduplicatedUplinkMessage")
+	proto.RegisterType((*PolicyViolation)(nil), "broker.PolicyViolation")
+	proto.RegisterType((*ReplayAttack)(nil), "broker.ReplayAttack")
+	proto.RegisterType((*Quarantine)(nil), "broker.Quarantine")
+	proto.RegisterType((*LinkQualityChange)(nil), "broker.LinkQualityChange")
+	proto.RegisterType((*DeviceReset)(nil), "broker.DeviceReset")
+	proto.RegisterType((*RelayMetadata)(nil), "broker.RelayMetadata")
+	proto.RegisterType((*FCntRegression)(nil), "broker.FCntRegression")
 	proto.RegisterType((*DeviceActivationRequest)(nil), "broker.DeviceActivationRequest")
 	proto.RegisterType((*DeduplicatedDeviceActivationRequest)(nil), "broker.DeduplicatedDeviceActivationRequest")
+	proto.RegisterType((*JoinLoop)(nil), "broker.JoinLoop")
 	proto.RegisterType((*ActivationChallengeRequest)(nil), "broker.ActivationChallengeRequest")
 	proto.RegisterType((*ActivationChallengeResponse)(nil), "broker.ActivationChallengeResponse")
 	proto.RegisterType((*SubscribeRequest)(nil), "broker.SubscribeRequest")
+	proto.RegisterType((*SubscribeAck)(nil), "broker.SubscribeAck")
 	proto.RegisterType((*StatusRequest)(nil), "broker.StatusRequest")
 	proto.RegisterType((*Status)(nil), "broker.Status")
 	proto.RegisterType((*ApplicationHandlerRegistration)(nil), "broker.ApplicationHandlerRegistration")
@@ -767,6 +1224,8 @@ type BrokerClient interface {
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Broker_SubscribeClient, error)
 	// Handler initiates downlink stream.
 	Publish(ctx context.Context, opts ...grpc.CallOption) (Broker_PublishClient, error)
+	// Handler acknowledges the uplink messages that it handled, so that the Broker does not replay them.
+	Acknowledge(ctx context.Context, in *SubscribeAck, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
 	// Router requests device activation
 	Activate(ctx context.Context, in *DeviceActivationRequest, opts ...grpc.CallOption) (*DeviceActivationResponse, error)
 }
@@ -876,6 +1335,15 @@ func (x *brokerPublishClient) CloseAndRecv() (*google_protobuf.Empty, error) {
 	return m, nil
 }
 
+func (c *brokerClient) Acknowledge(ctx context.Context, in *SubscribeAck, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/broker.Broker/Acknowledge", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *brokerClient) Activate(ctx context.Context, in *DeviceActivationRequest, opts ...grpc.CallOption) (*DeviceActivationResponse, error) {
 	out := new(DeviceActivationResponse)
 	err := grpc.Invoke(ctx, "/broker.Broker/Activate", in, out, c.cc, opts...)
@@ -894,6 +1362,8 @@ type BrokerServer interface {
 	Subscribe(*SubscribeRequest, Broker_SubscribeServer) error
 	// Handler initiates downlink stream.
 	Publish(Broker_PublishServer) error
+	// Handler acknowledges the uplink messages that it handled, so that the Broker does not replay them.
+	Acknowledge(context.Context, *SubscribeAck) (*google_protobuf.Empty, error)
 	// Router requests device activation
 	Activate(context.Context, *DeviceActivationRequest) (*DeviceActivationResponse, error)
 }
@@ -975,6 +1445,24 @@ func (x *brokerPublishServer) Recv() (*DownlinkMessage, error) {
 	return m, nil
 }
 
+func _Broker_Acknowledge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeAck)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Acknowledge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/broker.Broker/Acknowledge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Acknowledge(ctx, req.(*SubscribeAck))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Broker_Activate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeviceActivationRequest)
 	if err := dec(in); err != nil {
@@ -997,6 +1485,10 @@ var _Broker_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "broker.Broker",
 	HandlerType: (*BrokerServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Acknowledge",
+			Handler:    _Broker_Acknowledge_Handler,
+		},
 		{
 			MethodName: "Activate",
 			Handler:    _Broker_Activate_Handler,
@@ -1031,6 +1523,8 @@ type BrokerManagerClient interface {
 	RegisterApplicationHandler(ctx context.Context, in *ApplicationHandlerRegistration, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
 	// Network operator requests Broker status
 	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// Network operator streams the log entries of the Broker
+	TailLogs(ctx context.Context, in *api.LogsRequest, opts ...grpc.CallOption) (BrokerManager_TailLogsClient, error)
 }
 
 type brokerManagerClient struct {
@@ -1059,15 +1553,49 @@ func (c *brokerManagerClient) GetStatus(ctx context.Context, in *StatusRequest,
 	return out, nil
 }
 
-// Server API for BrokerManager service
-
-type BrokerManagerServer interface {
-	// Handler announces a new application to Broker. This is a temporary method that will be removed
-	// when we can push updates from the Discovery service to the routing services.
-	RegisterApplicationHandler(context.Context, *ApplicationHandlerRegistration) (*google_protobuf.Empty, error)
-	// Network operator requests Broker status
-	GetStatus(context.Context, *StatusRequest) (*Status, error)
-}
+func (c *brokerManagerClient) TailLogs(ctx context.Context, in *api.LogsRequest, opts ...grpc.CallOption) (BrokerManager_TailLogsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_BrokerManager_serviceDesc.Streams[0], c.cc, "/broker.BrokerManager/TailLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &brokerManagerTailLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BrokerManager_TailLogsClient interface {
+	Recv() (*api.LogEntry, error)
+	grpc.ClientStream
+}
+
+type brokerManagerTailLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *brokerManagerTailLogsClient) Recv() (*api.LogEntry, error) {
+	m := new(api.LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for BrokerManager service
+
+type BrokerManagerServer interface {
+	// Handler announces a new application to Broker. This is a temporary method that will be removed
+	// when we can push updates from the Discovery service to the routing services.
+	RegisterApplicationHandler(context.Context, *ApplicationHandlerRegistration) (*google_protobuf.Empty, error)
+	// Network operator requests Broker status
+	GetStatus(context.Context, *StatusRequest) (*Status, error)
+	// Network operator streams the log entries of the Broker
+	TailLogs(*api.LogsRequest, BrokerManager_TailLogsServer) error
+}
 
 func RegisterBrokerManagerServer(s *grpc.Server, srv BrokerManagerServer) {
 	s.RegisterService(&_BrokerManager_serviceDesc, srv)
@@ -1109,6 +1637,27 @@ func _BrokerManager_GetStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BrokerManager_TailLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(api.LogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BrokerManagerServer).TailLogs(m, &brokerManagerTailLogsServer{stream})
+}
+
+type BrokerManager_TailLogsServer interface {
+	Send(*api.LogEntry) error
+	grpc.ServerStream
+}
+
+type brokerManagerTailLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *brokerManagerTailLogsServer) Send(m *api.LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _BrokerManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "broker.BrokerManager",
 	HandlerType: (*BrokerManagerServer)(nil),
@@ -1122,7 +1671,13 @@ var _BrokerManager_serviceDesc = grpc.ServiceDesc{
 			Handler:    _BrokerManager_GetStatus_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailLogs",
+			Handler:       _BrokerManager_TailLogs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "github.com/TheThingsNetwork/ttn/api/broker/broker.proto",
 }
 
@@ -1183,6 +1738,60 @@ func (m *DownlinkOption) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n2
 	}
+	if m.RxSettings != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.RxSettings.Size()))
+		n3, err := m.RxSettings.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+
+func (m *RXSettings) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RXSettings) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Rx1Delay != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Rx1Delay))
+	}
+	if m.Rx1DrOffset != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Rx1DrOffset))
+	}
+	if len(m.Rx2DataRate) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Rx2DataRate)))
+		i += copy(dAtA[i:], m.Rx2DataRate)
+	}
+	if m.Rx2Only {
+		dAtA[i] = 0x20
+		i++
+		if m.Rx2Only {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -1211,31 +1820,31 @@ func (m *UplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
-		n3, err := m.Message.MarshalTo(dAtA[i:])
+		n4, err := m.Message.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n3
+		i += n4
 	}
 	if m.DevEui != nil {
 		dAtA[i] = 0x5a
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
-		n4, err := m.DevEui.MarshalTo(dAtA[i:])
+		n5, err := m.DevEui.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n4
+		i += n5
 	}
 	if m.AppEui != nil {
 		dAtA[i] = 0x62
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
-		n5, err := m.AppEui.MarshalTo(dAtA[i:])
+		n6, err := m.AppEui.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n5
+		i += n6
 	}
 	if len(m.AppId) > 0 {
 		dAtA[i] = 0x6a
@@ -1255,11 +1864,11 @@ func (m *UplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.ProtocolMetadata.Size()))
-		n6, err := m.ProtocolMetadata.MarshalTo(dAtA[i:])
+		n7, err := m.ProtocolMetadata.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n6
+		i += n7
 	}
 	if m.GatewayMetadata != nil {
 		dAtA[i] = 0xb2
@@ -1267,11 +1876,11 @@ func (m *UplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.GatewayMetadata.Size()))
-		n7, err := m.GatewayMetadata.MarshalTo(dAtA[i:])
+		n8, err := m.GatewayMetadata.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n7
+		i += n8
 	}
 	if len(m.DownlinkOptions) > 0 {
 		for _, msg := range m.DownlinkOptions {
@@ -1287,17 +1896,29 @@ func (m *UplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.Delayed {
+		dAtA[i] = 0x80
+		i++
+		dAtA[i] = 0x2
+		i++
+		if m.Delayed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if m.Trace != nil {
 		dAtA[i] = 0xca
 		i++
 		dAtA[i] = 0x2
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.Trace.Size()))
-		n8, err := m.Trace.MarshalTo(dAtA[i:])
+		n9, err := m.Trace.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n8
+		i += n9
 	}
 	return i, nil
 }
@@ -1327,31 +1948,31 @@ func (m *DownlinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
-		n9, err := m.Message.MarshalTo(dAtA[i:])
+		n10, err := m.Message.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n9
+		i += n10
 	}
 	if m.DevEui != nil {
 		dAtA[i] = 0x5a
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
-		n10, err := m.DevEui.MarshalTo(dAtA[i:])
+		n11, err := m.DevEui.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n10
+		i += n11
 	}
 	if m.AppEui != nil {
 		dAtA[i] = 0x62
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
-		n11, err := m.AppEui.MarshalTo(dAtA[i:])
+		n12, err := m.AppEui.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n11
+		i += n12
 	}
 	if len(m.AppId) > 0 {
 		dAtA[i] = 0x6a
@@ -1371,11 +1992,31 @@ func (m *DownlinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.DownlinkOption.Size()))
-		n12, err := m.DownlinkOption.MarshalTo(dAtA[i:])
+		n13, err := m.DownlinkOption.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n12
+		i += n13
+	}
+	if len(m.Priority) > 0 {
+		dAtA[i] = 0xb2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Priority)))
+		i += copy(dAtA[i:], m.Priority)
+	}
+	if m.MacCommandsAdded {
+		dAtA[i] = 0xb8
+		i++
+		dAtA[i] = 0x1
+		i++
+		if m.MacCommandsAdded {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
 	}
 	if m.Trace != nil {
 		dAtA[i] = 0xfa
@@ -1383,11 +2024,11 @@ func (m *DownlinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.Trace.Size()))
-		n13, err := m.Trace.MarshalTo(dAtA[i:])
+		n14, err := m.Trace.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n13
+		i += n14
 	}
 	return i, nil
 }
@@ -1417,21 +2058,21 @@ func (m *DeviceActivationResponse) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
-		n14, err := m.Message.MarshalTo(dAtA[i:])
+		n15, err := m.Message.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n14
+		i += n15
 	}
 	if m.DownlinkOption != nil {
 		dAtA[i] = 0x5a
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.DownlinkOption.Size()))
-		n15, err := m.DownlinkOption.MarshalTo(dAtA[i:])
+		n16, err := m.DownlinkOption.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n15
+		i += n16
 	}
 	if m.Trace != nil {
 		dAtA[i] = 0xaa
@@ -1439,11 +2080,11 @@ func (m *DeviceActivationResponse) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.Trace.Size()))
-		n16, err := m.Trace.MarshalTo(dAtA[i:])
+		n17, err := m.Trace.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n16
+		i += n17
 	}
 	return i, nil
 }
@@ -1473,31 +2114,31 @@ func (m *DeduplicatedUplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
-		n17, err := m.Message.MarshalTo(dAtA[i:])
+		n18, err := m.Message.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n17
+		i += n18
 	}
 	if m.DevEui != nil {
 		dAtA[i] = 0x5a
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
-		n18, err := m.DevEui.MarshalTo(dAtA[i:])
+		n19, err := m.DevEui.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n18
+		i += n19
 	}
 	if m.AppEui != nil {
 		dAtA[i] = 0x62
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
-		n19, err := m.AppEui.MarshalTo(dAtA[i:])
+		n20, err := m.AppEui.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n19
+		i += n20
 	}
 	if len(m.AppId) > 0 {
 		dAtA[i] = 0x6a
@@ -1517,11 +2158,11 @@ func (m *DeduplicatedUplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.ProtocolMetadata.Size()))
-		n20, err := m.ProtocolMetadata.MarshalTo(dAtA[i:])
+		n21, err := m.ProtocolMetadata.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n20
+		i += n21
 	}
 	if len(m.GatewayMetadata) > 0 {
 		for _, msg := range m.GatewayMetadata {
@@ -1550,11 +2191,11 @@ func (m *DeduplicatedUplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.ResponseTemplate.Size()))
-		n21, err := m.ResponseTemplate.MarshalTo(dAtA[i:])
+		n22, err := m.ResponseTemplate.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n21
+		i += n22
 	}
 	if m.Trace != nil {
 		dAtA[i] = 0xca
@@ -1562,107 +2203,127 @@ func (m *DeduplicatedUplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x2
 		i++
 		i = encodeVarintBroker(dAtA, i, uint64(m.Trace.Size()))
-		n22, err := m.Trace.MarshalTo(dAtA[i:])
+		n23, err := m.Trace.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n22
-	}
-	return i, nil
-}
-
-func (m *DeviceActivationRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *DeviceActivationRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Payload) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.Payload)))
-		i += copy(dAtA[i:], m.Payload)
+		i += n23
 	}
-	if m.Message != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
-		n23, err := m.Message.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if len(m.PolicyViolations) > 0 {
+		for _, msg := range m.PolicyViolations {
+			dAtA[i] = 0x9a
+			i++
+			dAtA[i] = 0x3
+			i++
+			i = encodeVarintBroker(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
 		}
-		i += n23
 	}
-	if m.DevEui != nil {
-		dAtA[i] = 0x5a
+	if m.ReplayAttack != nil {
+		dAtA[i] = 0xa2
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
-		n24, err := m.DevEui.MarshalTo(dAtA[i:])
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.ReplayAttack.Size()))
+		n24, err := m.ReplayAttack.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
 		i += n24
 	}
-	if m.AppEui != nil {
-		dAtA[i] = 0x62
+	if m.Quarantine != nil {
+		dAtA[i] = 0xaa
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
-		n25, err := m.AppEui.MarshalTo(dAtA[i:])
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Quarantine.Size()))
+		n25, err := m.Quarantine.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
 		i += n25
 	}
-	if m.ProtocolMetadata != nil {
-		dAtA[i] = 0xaa
+	if m.LinkQuality != nil {
+		dAtA[i] = 0xb2
 		i++
-		dAtA[i] = 0x1
+		dAtA[i] = 0x3
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ProtocolMetadata.Size()))
-		n26, err := m.ProtocolMetadata.MarshalTo(dAtA[i:])
+		i = encodeVarintBroker(dAtA, i, uint64(m.LinkQuality.Size()))
+		n26, err := m.LinkQuality.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
 		i += n26
 	}
-	if m.GatewayMetadata != nil {
-		dAtA[i] = 0xb2
+	if m.DeviceReset != nil {
+		dAtA[i] = 0xba
 		i++
-		dAtA[i] = 0x1
+		dAtA[i] = 0x3
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.GatewayMetadata.Size()))
-		n27, err := m.GatewayMetadata.MarshalTo(dAtA[i:])
+		i = encodeVarintBroker(dAtA, i, uint64(m.DeviceReset.Size()))
+		n27, err := m.DeviceReset.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
 		i += n27
 	}
-	if m.ActivationMetadata != nil {
-		dAtA[i] = 0xba
+	if m.RelayForward {
+		dAtA[i] = 0xc0
 		i++
-		dAtA[i] = 0x1
+		dAtA[i] = 0x3
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ActivationMetadata.Size()))
-		n28, err := m.ActivationMetadata.MarshalTo(dAtA[i:])
+		if m.RelayForward {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Relay != nil {
+		dAtA[i] = 0xca
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Relay.Size()))
+		n28, err := m.Relay.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
 		i += n28
 	}
+	if m.FcntRegression != nil {
+		dAtA[i] = 0xd2
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.FcntRegression.Size()))
+		n29, err := m.FcntRegression.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n29
+	}
+	if m.NoDownlink {
+		dAtA[i] = 0xd8
+		i++
+		dAtA[i] = 0x3
+		i++
+		if m.NoDownlink {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if len(m.DownlinkOptions) > 0 {
 		for _, msg := range m.DownlinkOptions {
-			dAtA[i] = 0xfa
+			dAtA[i] = 0xe2
 			i++
-			dAtA[i] = 0x1
+			dAtA[i] = 0x3
 			i++
 			i = encodeVarintBroker(dAtA, i, uint64(msg.Size()))
 			n, err := msg.MarshalTo(dAtA[i:])
@@ -1672,22 +2333,17 @@ func (m *DeviceActivationRequest) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
-	if m.Trace != nil {
-		dAtA[i] = 0xca
+	if m.Seq != 0 {
+		dAtA[i] = 0xe8
 		i++
-		dAtA[i] = 0x2
+		dAtA[i] = 0x3
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Trace.Size()))
-		n29, err := m.Trace.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n29
+		i = encodeVarintBroker(dAtA, i, uint64(m.Seq))
 	}
 	return i, nil
 }
 
-func (m *DeduplicatedDeviceActivationRequest) Marshal() (dAtA []byte, err error) {
+func (m *PolicyViolation) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1697,132 +2353,99 @@ func (m *DeduplicatedDeviceActivationRequest) Marshal() (dAtA []byte, err error)
 	return dAtA[:n], nil
 }
 
-func (m *DeduplicatedDeviceActivationRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *PolicyViolation) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Payload) > 0 {
+	if len(m.Policy) > 0 {
 		dAtA[i] = 0xa
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.Payload)))
-		i += copy(dAtA[i:], m.Payload)
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Policy)))
+		i += copy(dAtA[i:], m.Policy)
 	}
-	if m.Message != nil {
+	if len(m.Violation) > 0 {
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
-		n30, err := m.Message.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n30
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Violation)))
+		i += copy(dAtA[i:], m.Violation)
 	}
-	if m.DevEui != nil {
-		dAtA[i] = 0x5a
+	if m.Since != 0 {
+		dAtA[i] = 0x18
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
-		n31, err := m.DevEui.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n31
+		i = encodeVarintBroker(dAtA, i, uint64(m.Since))
 	}
-	if m.AppEui != nil {
-		dAtA[i] = 0x62
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
-		n32, err := m.AppEui.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n32
-	}
-	if len(m.AppId) > 0 {
-		dAtA[i] = 0x6a
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.AppId)))
-		i += copy(dAtA[i:], m.AppId)
-	}
-	if len(m.DevId) > 0 {
-		dAtA[i] = 0x72
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.DevId)))
-		i += copy(dAtA[i:], m.DevId)
+	return i, nil
+}
+
+func (m *ReplayAttack) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	if m.ProtocolMetadata != nil {
-		dAtA[i] = 0xaa
-		i++
-		dAtA[i] = 0x1
+	return dAtA[:n], nil
+}
+
+func (m *ReplayAttack) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Distance != 0 {
+		dAtA[i] = 0x8
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ProtocolMetadata.Size()))
-		n33, err := m.ProtocolMetadata.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n33
+		i = encodeVarintBroker(dAtA, i, uint64(m.Distance))
 	}
-	if len(m.GatewayMetadata) > 0 {
-		for _, msg := range m.GatewayMetadata {
-			dAtA[i] = 0xb2
+	if len(m.Gateways) > 0 {
+		for _, s := range m.Gateways {
+			dAtA[i] = 0x12
 			i++
-			dAtA[i] = 0x1
-			i++
-			i = encodeVarintBroker(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
 			}
-			i += n
-		}
-	}
-	if m.ActivationMetadata != nil {
-		dAtA[i] = 0xba
-		i++
-		dAtA[i] = 0x1
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ActivationMetadata.Size()))
-		n34, err := m.ActivationMetadata.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
 		}
-		i += n34
 	}
-	if m.ServerTime != 0 {
-		dAtA[i] = 0xc0
-		i++
-		dAtA[i] = 0x1
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ServerTime))
+	return i, nil
+}
+
+func (m *Quarantine) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	if m.ResponseTemplate != nil {
-		dAtA[i] = 0xfa
-		i++
-		dAtA[i] = 0x1
+	return dAtA[:n], nil
+}
+
+func (m *Quarantine) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Reason) > 0 {
+		dAtA[i] = 0xa
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ResponseTemplate.Size()))
-		n35, err := m.ResponseTemplate.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n35
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Reason)))
+		i += copy(dAtA[i:], m.Reason)
 	}
-	if m.Trace != nil {
-		dAtA[i] = 0xca
+	if m.Since != 0 {
+		dAtA[i] = 0x10
 		i++
-		dAtA[i] = 0x2
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Trace.Size()))
-		n36, err := m.Trace.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n36
+		i = encodeVarintBroker(dAtA, i, uint64(m.Since))
 	}
 	return i, nil
 }
 
-func (m *ActivationChallengeRequest) Marshal() (dAtA []byte, err error) {
+func (m *LinkQualityChange) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1832,63 +2455,37 @@ func (m *ActivationChallengeRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ActivationChallengeRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *LinkQualityChange) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Payload) > 0 {
+	if len(m.Quality) > 0 {
 		dAtA[i] = 0xa
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.Payload)))
-		i += copy(dAtA[i:], m.Payload)
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Quality)))
+		i += copy(dAtA[i:], m.Quality)
 	}
-	if m.Message != nil {
+	if len(m.Previous) > 0 {
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
-		n37, err := m.Message.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n37
-	}
-	if m.DevEui != nil {
-		dAtA[i] = 0x5a
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
-		n38, err := m.DevEui.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n38
-	}
-	if m.AppEui != nil {
-		dAtA[i] = 0x62
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
-		n39, err := m.AppEui.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n39
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Previous)))
+		i += copy(dAtA[i:], m.Previous)
 	}
-	if len(m.AppId) > 0 {
-		dAtA[i] = 0x6a
+	if m.Score != 0 {
+		dAtA[i] = 0x18
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.AppId)))
-		i += copy(dAtA[i:], m.AppId)
+		i = encodeVarintBroker(dAtA, i, uint64(m.Score))
 	}
-	if len(m.DevId) > 0 {
-		dAtA[i] = 0x72
+	if m.Loss != 0 {
+		dAtA[i] = 0x21
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.DevId)))
-		i += copy(dAtA[i:], m.DevId)
+		i = encodeFixed64Broker(dAtA, i, uint64(math.Float64bits(float64(m.Loss))))
 	}
 	return i, nil
 }
 
-func (m *ActivationChallengeResponse) Marshal() (dAtA []byte, err error) {
+func (m *DeviceReset) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1898,31 +2495,21 @@ func (m *ActivationChallengeResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ActivationChallengeResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *DeviceReset) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Payload) > 0 {
+	if len(m.LorawanVersion) > 0 {
 		dAtA[i] = 0xa
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.Payload)))
-		i += copy(dAtA[i:], m.Payload)
-	}
-	if m.Message != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
-		n40, err := m.Message.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n40
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.LorawanVersion)))
+		i += copy(dAtA[i:], m.LorawanVersion)
 	}
 	return i, nil
 }
 
-func (m *SubscribeRequest) Marshal() (dAtA []byte, err error) {
+func (m *RelayMetadata) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1932,15 +2519,26 @@ func (m *SubscribeRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SubscribeRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *RelayMetadata) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
+	if len(m.DevId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.DevId)))
+		i += copy(dAtA[i:], m.DevId)
+	}
+	if m.WorChannel != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.WorChannel))
+	}
 	return i, nil
 }
 
-func (m *StatusRequest) Marshal() (dAtA []byte, err error) {
+func (m *FCntRegression) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1950,15 +2548,25 @@ func (m *StatusRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *StatusRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *FCntRegression) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
+	if m.FCnt != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.FCnt))
+	}
+	if m.LastFCnt != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.LastFCnt))
+	}
 	return i, nil
 }
 
-func (m *Status) Marshal() (dAtA []byte, err error) {
+func (m *DeviceActivationRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1968,111 +2576,113 @@ func (m *Status) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Status) MarshalTo(dAtA []byte) (int, error) {
+func (m *DeviceActivationRequest) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if m.System != nil {
+	if len(m.Payload) > 0 {
 		dAtA[i] = 0xa
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.System.Size()))
-		n41, err := m.System.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n41
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Payload)))
+		i += copy(dAtA[i:], m.Payload)
 	}
-	if m.Component != nil {
+	if m.Message != nil {
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Component.Size()))
-		n42, err := m.Component.MarshalTo(dAtA[i:])
+		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
+		n30, err := m.Message.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n42
+		i += n30
 	}
-	if m.Uplink != nil {
+	if m.DevEui != nil {
 		dAtA[i] = 0x5a
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Uplink.Size()))
-		n43, err := m.Uplink.MarshalTo(dAtA[i:])
+		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
+		n31, err := m.DevEui.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n43
+		i += n31
 	}
-	if m.UplinkUnique != nil {
+	if m.AppEui != nil {
 		dAtA[i] = 0x62
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.UplinkUnique.Size()))
-		n44, err := m.UplinkUnique.MarshalTo(dAtA[i:])
+		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
+		n32, err := m.AppEui.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n44
+		i += n32
 	}
-	if m.Downlink != nil {
-		dAtA[i] = 0x6a
+	if m.ProtocolMetadata != nil {
+		dAtA[i] = 0xaa
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Downlink.Size()))
-		n45, err := m.Downlink.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n45
-	}
-	if m.Activations != nil {
-		dAtA[i] = 0x72
+		dAtA[i] = 0x1
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Activations.Size()))
-		n46, err := m.Activations.MarshalTo(dAtA[i:])
+		i = encodeVarintBroker(dAtA, i, uint64(m.ProtocolMetadata.Size()))
+		n33, err := m.ProtocolMetadata.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n46
+		i += n33
 	}
-	if m.ActivationsUnique != nil {
-		dAtA[i] = 0x7a
+	if m.GatewayMetadata != nil {
+		dAtA[i] = 0xb2
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ActivationsUnique.Size()))
-		n47, err := m.ActivationsUnique.MarshalTo(dAtA[i:])
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.GatewayMetadata.Size()))
+		n34, err := m.GatewayMetadata.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n47
+		i += n34
 	}
-	if m.Deduplication != nil {
-		dAtA[i] = 0x82
+	if m.ActivationMetadata != nil {
+		dAtA[i] = 0xba
 		i++
 		dAtA[i] = 0x1
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.Deduplication.Size()))
-		n48, err := m.Deduplication.MarshalTo(dAtA[i:])
+		i = encodeVarintBroker(dAtA, i, uint64(m.ActivationMetadata.Size()))
+		n35, err := m.ActivationMetadata.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n48
+		i += n35
 	}
-	if m.ConnectedRouters != 0 {
-		dAtA[i] = 0xa8
-		i++
-		dAtA[i] = 0x1
-		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ConnectedRouters))
+	if len(m.DownlinkOptions) > 0 {
+		for _, msg := range m.DownlinkOptions {
+			dAtA[i] = 0xfa
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintBroker(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
 	}
-	if m.ConnectedHandlers != 0 {
-		dAtA[i] = 0xb0
+	if m.Trace != nil {
+		dAtA[i] = 0xca
 		i++
-		dAtA[i] = 0x1
+		dAtA[i] = 0x2
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(m.ConnectedHandlers))
+		i = encodeVarintBroker(dAtA, i, uint64(m.Trace.Size()))
+		n36, err := m.Trace.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n36
 	}
 	return i, nil
 }
 
-func (m *ApplicationHandlerRegistration) Marshal() (dAtA []byte, err error) {
+func (m *DeduplicatedDeviceActivationRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -2082,241 +2692,565 @@ func (m *ApplicationHandlerRegistration) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ApplicationHandlerRegistration) MarshalTo(dAtA []byte) (int, error) {
+func (m *DeduplicatedDeviceActivationRequest) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.AppId) > 0 {
+	if len(m.Payload) > 0 {
 		dAtA[i] = 0xa
 		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Payload)))
+		i += copy(dAtA[i:], m.Payload)
+	}
+	if m.Message != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
+		n37, err := m.Message.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n37
+	}
+	if m.DevEui != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
+		n38, err := m.DevEui.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n38
+	}
+	if m.AppEui != nil {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
+		n39, err := m.AppEui.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n39
+	}
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0x6a
+		i++
 		i = encodeVarintBroker(dAtA, i, uint64(len(m.AppId)))
 		i += copy(dAtA[i:], m.AppId)
 	}
-	if len(m.HandlerId) > 0 {
-		dAtA[i] = 0x12
+	if len(m.DevId) > 0 {
+		dAtA[i] = 0x72
 		i++
-		i = encodeVarintBroker(dAtA, i, uint64(len(m.HandlerId)))
-		i += copy(dAtA[i:], m.HandlerId)
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.DevId)))
+		i += copy(dAtA[i:], m.DevId)
+	}
+	if m.ProtocolMetadata != nil {
+		dAtA[i] = 0xaa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.ProtocolMetadata.Size()))
+		n40, err := m.ProtocolMetadata.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n40
+	}
+	if len(m.GatewayMetadata) > 0 {
+		for _, msg := range m.GatewayMetadata {
+			dAtA[i] = 0xb2
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintBroker(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.ActivationMetadata != nil {
+		dAtA[i] = 0xba
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.ActivationMetadata.Size()))
+		n41, err := m.ActivationMetadata.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n41
+	}
+	if m.ServerTime != 0 {
+		dAtA[i] = 0xc0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.ServerTime))
+	}
+	if m.ResponseTemplate != nil {
+		dAtA[i] = 0xfa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.ResponseTemplate.Size()))
+		n42, err := m.ResponseTemplate.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n42
+	}
+	if m.Trace != nil {
+		dAtA[i] = 0xca
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Trace.Size()))
+		n43, err := m.Trace.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n43
+	}
+	if m.JoinLoop != nil {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.JoinLoop.Size()))
+		n44, err := m.JoinLoop.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n44
+	}
+	if len(m.GatewayGroups) > 0 {
+		for _, s := range m.GatewayGroups {
+			dAtA[i] = 0xa2
+			i++
+			dAtA[i] = 0x3
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
 	return i, nil
 }
 
-func encodeFixed64Broker(dAtA []byte, offset int, v uint64) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	dAtA[offset+4] = uint8(v >> 32)
-	dAtA[offset+5] = uint8(v >> 40)
-	dAtA[offset+6] = uint8(v >> 48)
-	dAtA[offset+7] = uint8(v >> 56)
-	return offset + 8
-}
-func encodeFixed32Broker(dAtA []byte, offset int, v uint32) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	return offset + 4
-}
-func encodeVarintBroker(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *JoinLoop) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *DownlinkOption) Size() (n int) {
+
+func (m *JoinLoop) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Identifier)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	l = len(m.GatewayId)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	if m.Score != 0 {
-		n += 1 + sovBroker(uint64(m.Score))
-	}
-	if m.Deadline != 0 {
-		n += 1 + sovBroker(uint64(m.Deadline))
-	}
-	if m.ProtocolConfig != nil {
-		l = m.ProtocolConfig.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	if m.Penalty != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Penalty))
 	}
-	if m.GatewayConfig != nil {
-		l = m.GatewayConfig.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	return i, nil
+}
+
+func (m *ActivationChallengeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *UplinkMessage) Size() (n int) {
+func (m *ActivationChallengeRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
+	if len(m.Payload) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Payload)))
+		i += copy(dAtA[i:], m.Payload)
 	}
 	if m.Message != nil {
-		l = m.Message.Size()
-		n += 1 + l + sovBroker(uint64(l))
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
+		n45, err := m.Message.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n45
 	}
 	if m.DevEui != nil {
-		l = m.DevEui.Size()
-		n += 1 + l + sovBroker(uint64(l))
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.DevEui.Size()))
+		n46, err := m.DevEui.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n46
 	}
 	if m.AppEui != nil {
-		l = m.AppEui.Size()
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	l = len(m.AppId)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	l = len(m.DevId)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	if m.ProtocolMetadata != nil {
-		l = m.ProtocolMetadata.Size()
-		n += 2 + l + sovBroker(uint64(l))
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.AppEui.Size()))
+		n47, err := m.AppEui.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n47
 	}
-	if m.GatewayMetadata != nil {
-		l = m.GatewayMetadata.Size()
-		n += 2 + l + sovBroker(uint64(l))
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
 	}
-	if len(m.DownlinkOptions) > 0 {
-		for _, e := range m.DownlinkOptions {
-			l = e.Size()
-			n += 2 + l + sovBroker(uint64(l))
-		}
+	if len(m.DevId) > 0 {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.DevId)))
+		i += copy(dAtA[i:], m.DevId)
 	}
-	if m.Trace != nil {
-		l = m.Trace.Size()
-		n += 2 + l + sovBroker(uint64(l))
+	return i, nil
+}
+
+func (m *ActivationChallengeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *DownlinkMessage) Size() (n int) {
+func (m *ActivationChallengeResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
+	if len(m.Payload) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.Payload)))
+		i += copy(dAtA[i:], m.Payload)
 	}
 	if m.Message != nil {
-		l = m.Message.Size()
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	if m.DevEui != nil {
-		l = m.DevEui.Size()
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	if m.AppEui != nil {
-		l = m.AppEui.Size()
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	l = len(m.AppId)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	l = len(m.DevId)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	if m.DownlinkOption != nil {
-		l = m.DownlinkOption.Size()
-		n += 2 + l + sovBroker(uint64(l))
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Message.Size()))
+		n48, err := m.Message.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n48
 	}
-	if m.Trace != nil {
-		l = m.Trace.Size()
-		n += 2 + l + sovBroker(uint64(l))
+	return i, nil
+}
+
+func (m *SubscribeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *DeviceActivationResponse) Size() (n int) {
+func (m *SubscribeRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
-	}
-	if m.Message != nil {
-		l = m.Message.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	return i, nil
+}
+
+func (m *SubscribeAck) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	if m.DownlinkOption != nil {
-		l = m.DownlinkOption.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	return dAtA[:n], nil
+}
+
+func (m *SubscribeAck) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Seq))
 	}
-	if m.Trace != nil {
-		l = m.Trace.Size()
-		n += 2 + l + sovBroker(uint64(l))
+	return i, nil
+}
+
+func (m *StatusRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *DeduplicatedUplinkMessage) Size() (n int) {
+func (m *StatusRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
+	return i, nil
+}
+
+func (m *Status) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	if m.Message != nil {
-		l = m.Message.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	return dAtA[:n], nil
+}
+
+func (m *Status) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.System != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.System.Size()))
+		n49, err := m.System.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n49
 	}
-	if m.DevEui != nil {
-		l = m.DevEui.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	if m.Component != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Component.Size()))
+		n50, err := m.Component.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n50
 	}
-	if m.AppEui != nil {
-		l = m.AppEui.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	if m.Uplink != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Uplink.Size()))
+		n51, err := m.Uplink.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n51
 	}
-	l = len(m.AppId)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
+	if m.UplinkUnique != nil {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.UplinkUnique.Size()))
+		n52, err := m.UplinkUnique.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n52
 	}
-	l = len(m.DevId)
-	if l > 0 {
-		n += 1 + l + sovBroker(uint64(l))
+	if m.Downlink != nil {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Downlink.Size()))
+		n53, err := m.Downlink.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n53
 	}
-	if m.ProtocolMetadata != nil {
-		l = m.ProtocolMetadata.Size()
-		n += 2 + l + sovBroker(uint64(l))
+	if m.Activations != nil {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Activations.Size()))
+		n54, err := m.Activations.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n54
 	}
-	if len(m.GatewayMetadata) > 0 {
-		for _, e := range m.GatewayMetadata {
-			l = e.Size()
-			n += 2 + l + sovBroker(uint64(l))
+	if m.ActivationsUnique != nil {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.ActivationsUnique.Size()))
+		n55, err := m.ActivationsUnique.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
 		}
+		i += n55
 	}
-	if m.ServerTime != 0 {
-		n += 2 + sovBroker(uint64(m.ServerTime))
+	if m.Deduplication != nil {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.Deduplication.Size()))
+		n56, err := m.Deduplication.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n56
 	}
-	if m.ResponseTemplate != nil {
-		l = m.ResponseTemplate.Size()
-		n += 2 + l + sovBroker(uint64(l))
+	if m.ConnectedRouters != 0 {
+		dAtA[i] = 0xa8
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.ConnectedRouters))
 	}
-	if m.Trace != nil {
-		l = m.Trace.Size()
-		n += 2 + l + sovBroker(uint64(l))
+	if m.ConnectedHandlers != 0 {
+		dAtA[i] = 0xb0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(m.ConnectedHandlers))
+	}
+	return i, nil
+}
+
+func (m *ApplicationHandlerRegistration) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ApplicationHandlerRegistration) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
+	}
+	if len(m.HandlerId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintBroker(dAtA, i, uint64(len(m.HandlerId)))
+		i += copy(dAtA[i:], m.HandlerId)
+	}
+	return i, nil
+}
+
+func encodeFixed64Broker(dAtA []byte, offset int, v uint64) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	dAtA[offset+4] = uint8(v >> 32)
+	dAtA[offset+5] = uint8(v >> 40)
+	dAtA[offset+6] = uint8(v >> 48)
+	dAtA[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Broker(dAtA []byte, offset int, v uint32) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintBroker(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *DownlinkOption) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Identifier)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.GatewayId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Score != 0 {
+		n += 1 + sovBroker(uint64(m.Score))
+	}
+	if m.Deadline != 0 {
+		n += 1 + sovBroker(uint64(m.Deadline))
+	}
+	if m.ProtocolConfig != nil {
+		l = m.ProtocolConfig.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.GatewayConfig != nil {
+		l = m.GatewayConfig.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.RxSettings != nil {
+		l = m.RxSettings.Size()
+		n += 1 + l + sovBroker(uint64(l))
 	}
 	return n
 }
 
-func (m *DeviceActivationRequest) Size() (n int) {
+func (m *RXSettings) Size() (n int) {
+	var l int
+	_ = l
+	if m.Rx1Delay != 0 {
+		n += 1 + sovBroker(uint64(m.Rx1Delay))
+	}
+	if m.Rx1DrOffset != 0 {
+		n += 1 + sovBroker(uint64(m.Rx1DrOffset))
+	}
+	l = len(m.Rx2DataRate)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Rx2Only {
+		n += 2
+	}
+	return n
+}
+
+func (m *UplinkMessage) Size() (n int) {
 	var l int
 	_ = l
 	l = len(m.Payload)
@@ -2335,6 +3269,14 @@ func (m *DeviceActivationRequest) Size() (n int) {
 		l = m.AppEui.Size()
 		n += 1 + l + sovBroker(uint64(l))
 	}
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
 	if m.ProtocolMetadata != nil {
 		l = m.ProtocolMetadata.Size()
 		n += 2 + l + sovBroker(uint64(l))
@@ -2343,16 +3285,15 @@ func (m *DeviceActivationRequest) Size() (n int) {
 		l = m.GatewayMetadata.Size()
 		n += 2 + l + sovBroker(uint64(l))
 	}
-	if m.ActivationMetadata != nil {
-		l = m.ActivationMetadata.Size()
-		n += 2 + l + sovBroker(uint64(l))
-	}
 	if len(m.DownlinkOptions) > 0 {
 		for _, e := range m.DownlinkOptions {
 			l = e.Size()
 			n += 2 + l + sovBroker(uint64(l))
 		}
 	}
+	if m.Delayed {
+		n += 3
+	}
 	if m.Trace != nil {
 		l = m.Trace.Size()
 		n += 2 + l + sovBroker(uint64(l))
@@ -2360,7 +3301,7 @@ func (m *DeviceActivationRequest) Size() (n int) {
 	return n
 }
 
-func (m *DeduplicatedDeviceActivationRequest) Size() (n int) {
+func (m *DownlinkMessage) Size() (n int) {
 	var l int
 	_ = l
 	l = len(m.Payload)
@@ -2387,27 +3328,39 @@ func (m *DeduplicatedDeviceActivationRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovBroker(uint64(l))
 	}
-	if m.ProtocolMetadata != nil {
-		l = m.ProtocolMetadata.Size()
+	if m.DownlinkOption != nil {
+		l = m.DownlinkOption.Size()
 		n += 2 + l + sovBroker(uint64(l))
 	}
-	if len(m.GatewayMetadata) > 0 {
-		for _, e := range m.GatewayMetadata {
-			l = e.Size()
-			n += 2 + l + sovBroker(uint64(l))
-		}
-	}
-	if m.ActivationMetadata != nil {
-		l = m.ActivationMetadata.Size()
+	l = len(m.Priority)
+	if l > 0 {
 		n += 2 + l + sovBroker(uint64(l))
 	}
-	if m.ServerTime != 0 {
-		n += 2 + sovBroker(uint64(m.ServerTime))
+	if m.MacCommandsAdded {
+		n += 3
 	}
-	if m.ResponseTemplate != nil {
-		l = m.ResponseTemplate.Size()
+	if m.Trace != nil {
+		l = m.Trace.Size()
 		n += 2 + l + sovBroker(uint64(l))
 	}
+	return n
+}
+
+func (m *DeviceActivationResponse) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Message != nil {
+		l = m.Message.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.DownlinkOption != nil {
+		l = m.DownlinkOption.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
 	if m.Trace != nil {
 		l = m.Trace.Size()
 		n += 2 + l + sovBroker(uint64(l))
@@ -2415,7 +3368,7 @@ func (m *DeduplicatedDeviceActivationRequest) Size() (n int) {
 	return n
 }
 
-func (m *ActivationChallengeRequest) Size() (n int) {
+func (m *DeduplicatedUplinkMessage) Size() (n int) {
 	var l int
 	_ = l
 	l = len(m.Payload)
@@ -2442,61 +3395,384 @@ func (m *ActivationChallengeRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovBroker(uint64(l))
 	}
+	if m.ProtocolMetadata != nil {
+		l = m.ProtocolMetadata.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if len(m.GatewayMetadata) > 0 {
+		for _, e := range m.GatewayMetadata {
+			l = e.Size()
+			n += 2 + l + sovBroker(uint64(l))
+		}
+	}
+	if m.ServerTime != 0 {
+		n += 2 + sovBroker(uint64(m.ServerTime))
+	}
+	if m.ResponseTemplate != nil {
+		l = m.ResponseTemplate.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.Trace != nil {
+		l = m.Trace.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if len(m.PolicyViolations) > 0 {
+		for _, e := range m.PolicyViolations {
+			l = e.Size()
+			n += 2 + l + sovBroker(uint64(l))
+		}
+	}
+	if m.ReplayAttack != nil {
+		l = m.ReplayAttack.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.Quarantine != nil {
+		l = m.Quarantine.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.LinkQuality != nil {
+		l = m.LinkQuality.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.DeviceReset != nil {
+		l = m.DeviceReset.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.RelayForward {
+		n += 3
+	}
+	if m.Relay != nil {
+		l = m.Relay.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.FcntRegression != nil {
+		l = m.FcntRegression.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.NoDownlink {
+		n += 3
+	}
+	if len(m.DownlinkOptions) > 0 {
+		for _, e := range m.DownlinkOptions {
+			l = e.Size()
+			n += 2 + l + sovBroker(uint64(l))
+		}
+	}
+	if m.Seq != 0 {
+		n += 2 + sovBroker(uint64(m.Seq))
+	}
 	return n
 }
 
-func (m *ActivationChallengeResponse) Size() (n int) {
+func (m *PolicyViolation) Size() (n int) {
 	var l int
 	_ = l
-	l = len(m.Payload)
+	l = len(m.Policy)
 	if l > 0 {
 		n += 1 + l + sovBroker(uint64(l))
 	}
-	if m.Message != nil {
-		l = m.Message.Size()
+	l = len(m.Violation)
+	if l > 0 {
 		n += 1 + l + sovBroker(uint64(l))
 	}
+	if m.Since != 0 {
+		n += 1 + sovBroker(uint64(m.Since))
+	}
 	return n
 }
 
-func (m *SubscribeRequest) Size() (n int) {
+func (m *ReplayAttack) Size() (n int) {
 	var l int
 	_ = l
+	if m.Distance != 0 {
+		n += 1 + sovBroker(uint64(m.Distance))
+	}
+	if len(m.Gateways) > 0 {
+		for _, s := range m.Gateways {
+			l = len(s)
+			n += 1 + l + sovBroker(uint64(l))
+		}
+	}
 	return n
 }
 
-func (m *StatusRequest) Size() (n int) {
+func (m *Quarantine) Size() (n int) {
 	var l int
 	_ = l
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Since != 0 {
+		n += 1 + sovBroker(uint64(m.Since))
+	}
 	return n
 }
 
-func (m *Status) Size() (n int) {
+func (m *LinkQualityChange) Size() (n int) {
 	var l int
 	_ = l
-	if m.System != nil {
-		l = m.System.Size()
+	l = len(m.Quality)
+	if l > 0 {
 		n += 1 + l + sovBroker(uint64(l))
 	}
-	if m.Component != nil {
-		l = m.Component.Size()
+	l = len(m.Previous)
+	if l > 0 {
 		n += 1 + l + sovBroker(uint64(l))
 	}
-	if m.Uplink != nil {
-		l = m.Uplink.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	if m.Score != 0 {
+		n += 1 + sovBroker(uint64(m.Score))
 	}
-	if m.UplinkUnique != nil {
-		l = m.UplinkUnique.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	if m.Loss != 0 {
+		n += 9
 	}
-	if m.Downlink != nil {
-		l = m.Downlink.Size()
+	return n
+}
+
+func (m *DeviceReset) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.LorawanVersion)
+	if l > 0 {
 		n += 1 + l + sovBroker(uint64(l))
 	}
-	if m.Activations != nil {
-		l = m.Activations.Size()
-		n += 1 + l + sovBroker(uint64(l))
+	return n
+}
+
+func (m *RelayMetadata) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.WorChannel != 0 {
+		n += 1 + sovBroker(uint64(m.WorChannel))
+	}
+	return n
+}
+
+func (m *FCntRegression) Size() (n int) {
+	var l int
+	_ = l
+	if m.FCnt != 0 {
+		n += 1 + sovBroker(uint64(m.FCnt))
+	}
+	if m.LastFCnt != 0 {
+		n += 1 + sovBroker(uint64(m.LastFCnt))
+	}
+	return n
+}
+
+func (m *DeviceActivationRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Message != nil {
+		l = m.Message.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.DevEui != nil {
+		l = m.DevEui.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.AppEui != nil {
+		l = m.AppEui.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.ProtocolMetadata != nil {
+		l = m.ProtocolMetadata.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.GatewayMetadata != nil {
+		l = m.GatewayMetadata.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.ActivationMetadata != nil {
+		l = m.ActivationMetadata.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if len(m.DownlinkOptions) > 0 {
+		for _, e := range m.DownlinkOptions {
+			l = e.Size()
+			n += 2 + l + sovBroker(uint64(l))
+		}
+	}
+	if m.Trace != nil {
+		l = m.Trace.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	return n
+}
+
+func (m *DeduplicatedDeviceActivationRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Message != nil {
+		l = m.Message.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.DevEui != nil {
+		l = m.DevEui.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.AppEui != nil {
+		l = m.AppEui.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.ProtocolMetadata != nil {
+		l = m.ProtocolMetadata.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if len(m.GatewayMetadata) > 0 {
+		for _, e := range m.GatewayMetadata {
+			l = e.Size()
+			n += 2 + l + sovBroker(uint64(l))
+		}
+	}
+	if m.ActivationMetadata != nil {
+		l = m.ActivationMetadata.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.ServerTime != 0 {
+		n += 2 + sovBroker(uint64(m.ServerTime))
+	}
+	if m.ResponseTemplate != nil {
+		l = m.ResponseTemplate.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.Trace != nil {
+		l = m.Trace.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if m.JoinLoop != nil {
+		l = m.JoinLoop.Size()
+		n += 2 + l + sovBroker(uint64(l))
+	}
+	if len(m.GatewayGroups) > 0 {
+		for _, s := range m.GatewayGroups {
+			l = len(s)
+			n += 2 + l + sovBroker(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *JoinLoop) Size() (n int) {
+	var l int
+	_ = l
+	if m.Penalty != 0 {
+		n += 1 + sovBroker(uint64(m.Penalty))
+	}
+	return n
+}
+
+func (m *ActivationChallengeRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Message != nil {
+		l = m.Message.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.DevEui != nil {
+		l = m.DevEui.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.AppEui != nil {
+		l = m.AppEui.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	return n
+}
+
+func (m *ActivationChallengeResponse) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Message != nil {
+		l = m.Message.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	return n
+}
+
+func (m *SubscribeRequest) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SubscribeAck) Size() (n int) {
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		n += 1 + sovBroker(uint64(m.Seq))
+	}
+	return n
+}
+
+func (m *StatusRequest) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *Status) Size() (n int) {
+	var l int
+	_ = l
+	if m.System != nil {
+		l = m.System.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Component != nil {
+		l = m.Component.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Uplink != nil {
+		l = m.Uplink.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.UplinkUnique != nil {
+		l = m.UplinkUnique.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Downlink != nil {
+		l = m.Downlink.Size()
+		n += 1 + l + sovBroker(uint64(l))
+	}
+	if m.Activations != nil {
+		l = m.Activations.Size()
+		n += 1 + l + sovBroker(uint64(l))
 	}
 	if m.ActivationsUnique != nil {
 		l = m.ActivationsUnique.Size()
@@ -2552,28 +3828,924 @@ func (m *DownlinkOption) Unmarshal(dAtA []byte) error {
 			if shift >= 64 {
 				return ErrIntOverflowBroker
 			}
-			if iNdEx >= l {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DownlinkOption: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DownlinkOption: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Identifier", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Identifier = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GatewayId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GatewayId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Score", wireType)
+			}
+			m.Score = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Score |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deadline", wireType)
+			}
+			m.Deadline = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Deadline |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolConfig", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ProtocolConfig == nil {
+				m.ProtocolConfig = &protocol.TxConfiguration{}
+			}
+			if err := m.ProtocolConfig.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GatewayConfig", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.GatewayConfig == nil {
+				m.GatewayConfig = &gateway.TxConfiguration{}
+			}
+			if err := m.GatewayConfig.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RxSettings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RxSettings == nil {
+				m.RxSettings = &RXSettings{}
+			}
+			if err := m.RxSettings.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RXSettings) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RXSettings: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RXSettings: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rx1Delay", wireType)
+			}
+			m.Rx1Delay = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rx1Delay |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rx1DrOffset", wireType)
+			}
+			m.Rx1DrOffset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rx1DrOffset |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rx2DataRate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Rx2DataRate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rx2Only", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Rx2Only = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UplinkMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UplinkMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UplinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Message == nil {
+				m.Message = &protocol.Message{}
+			}
+			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevEui", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_TheThingsNetwork_ttn_core_types.DevEUI
+			m.DevEui = &v
+			if err := m.DevEui.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppEui", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_TheThingsNetwork_ttn_core_types.AppEUI
+			m.AppEui = &v
+			if err := m.AppEui.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DevId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolMetadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ProtocolMetadata == nil {
+				m.ProtocolMetadata = &protocol.RxMetadata{}
+			}
+			if err := m.ProtocolMetadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GatewayMetadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.GatewayMetadata == nil {
+				m.GatewayMetadata = &gateway.RxMetadata{}
+			}
+			if err := m.GatewayMetadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DownlinkOptions = append(m.DownlinkOptions, &DownlinkOption{})
+			if err := m.DownlinkOptions[len(m.DownlinkOptions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 32:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delayed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Delayed = bool(v != 0)
+		case 41:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Trace == nil {
+				m.Trace = &trace.Trace{}
+			}
+			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DownlinkMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DownlinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Message == nil {
+				m.Message = &protocol.Message{}
+			}
+			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevEui", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_TheThingsNetwork_ttn_core_types.DevEUI
+			m.DevEui = &v
+			if err := m.DevEui.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppEui", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			var v github_com_TheThingsNetwork_ttn_core_types.AppEUI
+			m.AppEui = &v
+			if err := m.AppEui.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: DownlinkOption: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DownlinkOption: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 13:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Identifier", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2598,11 +4770,11 @@ func (m *DownlinkOption) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Identifier = string(dAtA[iNdEx:postIndex])
+			m.AppId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 14:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GatewayId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2627,13 +4799,13 @@ func (m *DownlinkOption) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GatewayId = string(dAtA[iNdEx:postIndex])
+			m.DevId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Score", wireType)
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOption", wireType)
 			}
-			m.Score = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -2643,16 +4815,59 @@ func (m *DownlinkOption) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Score |= (uint32(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DownlinkOption == nil {
+				m.DownlinkOption = &DownlinkOption{}
+			}
+			if err := m.DownlinkOption.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Priority = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 23:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Deadline", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MacCommandsAdded", wireType)
 			}
-			m.Deadline = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -2662,14 +4877,162 @@ func (m *DownlinkOption) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Deadline |= (int64(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MacCommandsAdded = bool(v != 0)
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Trace == nil {
+				m.Trace = &trace.Trace{}
+			}
+			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeviceActivationResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeviceActivationResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+			if byteLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Message == nil {
+				m.Message = &protocol.Message{}
+			}
+			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolConfig", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOption", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2693,16 +5056,16 @@ func (m *DownlinkOption) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.ProtocolConfig == nil {
-				m.ProtocolConfig = &protocol.TxConfiguration{}
+			if m.DownlinkOption == nil {
+				m.DownlinkOption = &DownlinkOption{}
 			}
-			if err := m.ProtocolConfig.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.DownlinkOption.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 6:
+		case 21:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GatewayConfig", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2726,10 +5089,10 @@ func (m *DownlinkOption) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.GatewayConfig == nil {
-				m.GatewayConfig = &gateway.TxConfiguration{}
+			if m.Trace == nil {
+				m.Trace = &trace.Trace{}
 			}
-			if err := m.GatewayConfig.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2754,7 +5117,7 @@ func (m *DownlinkOption) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *UplinkMessage) Unmarshal(dAtA []byte) error {
+func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2777,10 +5140,10 @@ func (m *UplinkMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: UplinkMessage: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeduplicatedUplinkMessage: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UplinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeduplicatedUplinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3028,16 +5391,33 @@ func (m *UplinkMessage) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.GatewayMetadata == nil {
-				m.GatewayMetadata = &gateway.RxMetadata{}
-			}
-			if err := m.GatewayMetadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.GatewayMetadata = append(m.GatewayMetadata, &gateway.RxMetadata{})
+			if err := m.GatewayMetadata[len(m.GatewayMetadata)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 23:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServerTime", wireType)
+			}
+			m.ServerTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ServerTime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		case 31:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOptions", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ResponseTemplate", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3061,8 +5441,10 @@ func (m *UplinkMessage) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DownlinkOptions = append(m.DownlinkOptions, &DownlinkOption{})
-			if err := m.DownlinkOptions[len(m.DownlinkOptions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.ResponseTemplate == nil {
+				m.ResponseTemplate = &DownlinkMessage{}
+			}
+			if err := m.ResponseTemplate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -3099,61 +5481,194 @@ func (m *UplinkMessage) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipBroker(dAtA[iNdEx:])
-			if err != nil {
+		case 51:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PolicyViolations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PolicyViolations = append(m.PolicyViolations, &PolicyViolation{})
+			if err := m.PolicyViolations[len(m.PolicyViolations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			if skippy < 0 {
+			iNdEx = postIndex
+		case 52:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplayAttack", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthBroker
 			}
-			if (iNdEx + skippy) > l {
+			postIndex := iNdEx + msglen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowBroker
+			if m.ReplayAttack == nil {
+				m.ReplayAttack = &ReplayAttack{}
 			}
-			if iNdEx >= l {
+			if err := m.ReplayAttack.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 53:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Quarantine", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			if m.Quarantine == nil {
+				m.Quarantine = &Quarantine{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: DownlinkMessage: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DownlinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			if err := m.Quarantine.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 54:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LinkQuality", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LinkQuality == nil {
+				m.LinkQuality = &LinkQualityChange{}
+			}
+			if err := m.LinkQuality.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 55:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeviceReset", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DeviceReset == nil {
+				m.DeviceReset = &DeviceReset{}
+			}
+			if err := m.DeviceReset.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 56:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RelayForward", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.RelayForward = bool(v != 0)
+		case 57:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Relay", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3163,26 +5678,28 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthBroker
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
+			if m.Relay == nil {
+				m.Relay = &RelayMetadata{}
+			}
+			if err := m.Relay.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 58:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FcntRegression", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3206,18 +5723,18 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Message == nil {
-				m.Message = &protocol.Message{}
+			if m.FcntRegression == nil {
+				m.FcntRegression = &FCntRegression{}
 			}
-			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.FcntRegression.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 11:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DevEui", wireType)
+		case 59:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NoDownlink", wireType)
 			}
-			var byteLen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3227,29 +5744,17 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthBroker
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			var v github_com_TheThingsNetwork_ttn_core_types.DevEUI
-			m.DevEui = &v
-			if err := m.DevEui.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 12:
+			m.NoDownlink = bool(v != 0)
+		case 60:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppEui", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOptions", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3259,29 +5764,28 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthBroker
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			var v github_com_TheThingsNetwork_ttn_core_types.AppEUI
-			m.AppEui = &v
-			if err := m.AppEui.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.DownlinkOptions = append(m.DownlinkOptions, &DownlinkOption{})
+			if err := m.DownlinkOptions[len(m.DownlinkOptions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 13:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+		case 61:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
 			}
-			var stringLen uint64
+			m.Seq = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3291,24 +5795,64 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.Seq |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthBroker
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AppId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 14:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PolicyViolation) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PolicyViolation: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PolicyViolation: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Policy", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3333,13 +5877,13 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DevId = string(dAtA[iNdEx:postIndex])
+			m.Policy = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 21:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOption", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Violation", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3349,30 +5893,26 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthBroker
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.DownlinkOption == nil {
-				m.DownlinkOption = &DownlinkOption{}
-			}
-			if err := m.DownlinkOption.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Violation = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 31:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Since", wireType)
 			}
-			var msglen int
+			m.Since = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3382,25 +5922,11 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Since |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthBroker
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Trace == nil {
-				m.Trace = &trace.Trace{}
-			}
-			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBroker(dAtA[iNdEx:])
@@ -3422,7 +5948,7 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
+func (m *ReplayAttack) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3445,17 +5971,17 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DeviceActivationResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReplayAttack: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DeviceActivationResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReplayAttack: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Distance", wireType)
 			}
-			var byteLen int
+			m.Distance = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3465,28 +5991,16 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.Distance |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthBroker
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
-			}
-			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Gateways", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3496,30 +6010,76 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthBroker
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Message == nil {
-				m.Message = &protocol.Message{}
-			}
-			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Gateways = append(m.Gateways, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 11:
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Quarantine) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Quarantine: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Quarantine: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOption", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3529,30 +6089,26 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthBroker
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.DownlinkOption == nil {
-				m.DownlinkOption = &DownlinkOption{}
-			}
-			if err := m.DownlinkOption.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 21:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Since", wireType)
 			}
-			var msglen int
+			m.Since = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3562,25 +6118,11 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Since |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthBroker
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Trace == nil {
-				m.Trace = &trace.Trace{}
-			}
-			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBroker(dAtA[iNdEx:])
@@ -3602,7 +6144,7 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
+func (m *LinkQualityChange) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3625,17 +6167,17 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DeduplicatedUplinkMessage: wiretype end group for non-group")
+			return fmt.Errorf("proto: LinkQualityChange: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DeduplicatedUplinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LinkQualityChange: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Quality", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3645,28 +6187,26 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthBroker
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
-			}
+			m.Quality = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Previous", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3676,30 +6216,26 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthBroker
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Message == nil {
-				m.Message = &protocol.Message{}
-			}
-			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Previous = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 11:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DevEui", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Score", wireType)
 			}
-			var byteLen int
+			m.Score = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3709,59 +6245,82 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.Score |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthBroker
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Loss", wireType)
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			var v github_com_TheThingsNetwork_ttn_core_types.DevEUI
-			m.DevEui = &v
-			if err := m.DevEui.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			iNdEx += 8
+			v = uint64(dAtA[iNdEx-8])
+			v |= uint64(dAtA[iNdEx-7]) << 8
+			v |= uint64(dAtA[iNdEx-6]) << 16
+			v |= uint64(dAtA[iNdEx-5]) << 24
+			v |= uint64(dAtA[iNdEx-4]) << 32
+			v |= uint64(dAtA[iNdEx-3]) << 40
+			v |= uint64(dAtA[iNdEx-2]) << 48
+			v |= uint64(dAtA[iNdEx-1]) << 56
+			m.Loss = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 12:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppEui", wireType)
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
 			}
-			var byteLen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowBroker
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthBroker
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeviceReset) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			var v github_com_TheThingsNetwork_ttn_core_types.AppEUI
-			m.AppEui = &v
-			if err := m.AppEui.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			iNdEx = postIndex
-		case 13:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeviceReset: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeviceReset: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LorawanVersion", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3786,9 +6345,59 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AppId = string(dAtA[iNdEx:postIndex])
+			m.LorawanVersion = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 14:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RelayMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RelayMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RelayMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
 			}
@@ -3817,11 +6426,11 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 			}
 			m.DevId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 21:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolMetadata", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WorChannel", wireType)
 			}
-			var msglen int
+			m.WorChannel = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3831,80 +6440,66 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.WorChannel |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthBroker
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.ProtocolMetadata == nil {
-				m.ProtocolMetadata = &protocol.RxMetadata{}
-			}
-			if err := m.ProtocolMetadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 22:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GatewayMetadata", wireType)
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
 			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowBroker
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthBroker
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FCntRegression) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
 			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GatewayMetadata = append(m.GatewayMetadata, &gateway.RxMetadata{})
-			if err := m.GatewayMetadata[len(m.GatewayMetadata)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			iNdEx = postIndex
-		case 23:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FCntRegression: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FCntRegression: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ServerTime", wireType)
-			}
-			m.ServerTime = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowBroker
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.ServerTime |= (int64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 31:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResponseTemplate", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FCnt", wireType)
 			}
-			var msglen int
+			m.FCnt = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3914,30 +6509,16 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.FCnt |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthBroker
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.ResponseTemplate == nil {
-				m.ResponseTemplate = &DownlinkMessage{}
-			}
-			if err := m.ResponseTemplate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 41:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastFCnt", wireType)
 			}
-			var msglen int
+			m.LastFCnt = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowBroker
@@ -3947,25 +6528,11 @@ func (m *DeduplicatedUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.LastFCnt |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthBroker
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Trace == nil {
-				m.Trace = &trace.Trace{}
-			}
-			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBroker(dAtA[iNdEx:])
@@ -4725,6 +7292,137 @@ func (m *DeduplicatedDeviceActivationRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 51:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JoinLoop", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.JoinLoop == nil {
+				m.JoinLoop = &JoinLoop{}
+			}
+			if err := m.JoinLoop.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 52:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GatewayGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBroker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GatewayGroups = append(m.GatewayGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *JoinLoop) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: JoinLoop: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: JoinLoop: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Penalty", wireType)
+			}
+			m.Penalty = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Penalty |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBroker(dAtA[iNdEx:])
@@ -5146,6 +7844,75 @@ func (m *SubscribeRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *SubscribeAck) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBroker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SubscribeAck: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SubscribeAck: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBroker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBroker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBroker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *StatusRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -5766,81 +8533,127 @@ func init() {
 }
 
 var fileDescriptorBroker = []byte{
-	// 1208 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xec, 0x58, 0x4d, 0x8f, 0xdb, 0x44,
-	0x18, 0x96, 0xf7, 0x23, 0xdb, 0x7d, 0xb3, 0xd9, 0x24, 0xd3, 0xee, 0xae, 0x9b, 0xd2, 0x4d, 0x08,
-	0x52, 0x15, 0x28, 0x4d, 0xda, 0x20, 0xbe, 0x24, 0x44, 0x95, 0xed, 0x56, 0xb0, 0x48, 0x29, 0x95,
-	0x9b, 0x72, 0x40, 0x48, 0xd1, 0xc4, 0x7e, 0xeb, 0x8c, 0xea, 0xd8, 0xae, 0x67, 0x9c, 0x36, 0x7f,
-	0x80, 0x0b, 0x12, 0xbf, 0x01, 0xf8, 0x07, 0x1c, 0xb9, 0x70, 0x44, 0x1c, 0x39, 0x73, 0x00, 0xd4,
-	0x5f, 0x82, 0x3c, 0x9e, 0xb1, 0x93, 0xdd, 0xa6, 0xad, 0xaa, 0x8a, 0x0f, 0x75, 0x2f, 0x89, 0xe7,
-	0x79, 0x1f, 0x3f, 0x33, 0xf3, 0x7e, 0x79, 0x6c, 0x78, 0xdf, 0x65, 0x62, 0x1c, 0x8f, 0xda, 0x76,
-	0x30, 0xe9, 0x0c, 0xc6, 0x38, 0x18, 0x33, 0xdf, 0xe5, 0xb7, 0x50, 0x3c, 0x0c, 0xa2, 0xfb, 0x1d,
-	0x21, 0xfc, 0x0e, 0x0d, 0x59, 0x67, 0x14, 0x05, 0xf7, 0x31, 0x52, 0x7f, 0xed, 0x30, 0x0a, 0x44,
-	0x40, 0x0a, 0xe9, 0xa8, 0x76, 0xc1, 0x0d, 0x02, 0xd7, 0xc3, 0x8e, 0x44, 0x47, 0xf1, 0xbd, 0x0e,
-	0x4e, 0x42, 0x31, 0x4b, 0x49, 0xb5, 0x2b, 0x73, 0xea, 0x6e, 0xe0, 0x06, 0x39, 0x2b, 0x19, 0xc9,
-	0x81, 0xbc, 0x52, 0xf4, 0xaa, 0x9e, 0x90, 0x86, 0x4c, 0x41, 0x75, 0x0d, 0xc9, 0xa1, 0x1d, 0x78,
-	0xd9, 0x85, 0x22, 0x5c, 0xd4, 0x04, 0x97, 0x0a, 0x7c, 0x48, 0x67, 0xfa, 0x5f, 0x99, 0xcf, 0x6b,
-	0xb3, 0x88, 0xa8, 0x8d, 0xe9, 0x6f, 0x6a, 0x6a, 0x7e, 0xbd, 0x02, 0xdb, 0x87, 0xc1, 0x43, 0xdf,
-	0x63, 0xfe, 0xfd, 0xcf, 0x43, 0xc1, 0x02, 0x9f, 0xec, 0x03, 0x30, 0x07, 0x7d, 0xc1, 0xee, 0x31,
-	0x8c, 0x4c, 0xa3, 0x61, 0xb4, 0x36, 0xad, 0x39, 0x84, 0x5c, 0x04, 0x50, 0xf2, 0x43, 0xe6, 0x98,
-	0x2b, 0xd2, 0xbe, 0xa9, 0x90, 0x23, 0x87, 0x9c, 0x83, 0x75, 0x6e, 0x07, 0x11, 0x9a, 0xab, 0x0d,
-	0xa3, 0x55, 0xb2, 0xd2, 0x01, 0xa9, 0xc1, 0x19, 0x07, 0xa9, 0xe3, 0x31, 0x1f, 0xcd, 0xb5, 0x86,
-	0xd1, 0x5a, 0xb5, 0xb2, 0x31, 0x39, 0x80, 0xb2, 0xde, 0xcf, 0xd0, 0x0e, 0xfc, 0x7b, 0xcc, 0x35,
-	0xd7, 0x1b, 0x46, 0xab, 0xd8, 0x3d, 0xdf, 0xce, 0xf6, 0x39, 0x78, 0x74, 0x43, 0x5a, 0xe2, 0x88,
-	0x26, 0x8b, 0xb4, 0xb6, 0xb5, 0x25, 0x85, 0xc9, 0x75, 0xd8, 0xd6, 0x8b, 0x52, 0x12, 0x05, 0x29,
-	0x61, 0xb6, 0xb5, 0x2b, 0x8e, 0x2b, 0x94, 0x94, 0x21, 0x45, 0x9b, 0xdf, 0xae, 0x41, 0xe9, 0x6e,
-	0x98, 0xb8, 0xa1, 0x8f, 0x9c, 0x53, 0x17, 0x89, 0x09, 0x1b, 0x21, 0x9d, 0x79, 0x01, 0x75, 0xa4,
-	0x13, 0xb6, 0x2c, 0x3d, 0x24, 0x97, 0x61, 0x63, 0x92, 0x92, 0xe4, 0xf6, 0x8b, 0xdd, 0x6a, 0xbe,
-	0x50, 0x75, 0xb7, 0xa5, 0x19, 0xe4, 0x16, 0x6c, 0x38, 0x38, 0x1d, 0x62, 0xcc, 0xcc, 0x62, 0x22,
-	0x73, 0xf0, 0xee, 0xef, 0x7f, 0xd4, 0xaf, 0x3d, 0x2b, 0xe3, 0x12, 0xa7, 0x75, 0xc4, 0x2c, 0x44,
-	0xde, 0x3e, 0xc4, 0xe9, 0xcd, 0xbb, 0x47, 0x56, 0xc1, 0xc1, 0xe9, 0xcd, 0x98, 0x25, 0x7a, 0x34,
-	0x0c, 0xa5, 0xde, 0xd6, 0x0b, 0xe9, 0xf5, 0xc2, 0x50, 0xea, 0xd1, 0x30, 0x4c, 0xf4, 0x76, 0x20,
-	0xb9, 0x4a, 0x42, 0x59, 0x92, 0xa1, 0x5c, 0xa7, 0x61, 0x78, 0xe4, 0x24, 0x70, 0xb2, 0x6c, 0xe6,
-	0x98, 0xdb, 0x29, 0xec, 0xe0, 0xf4, 0xc8, 0x21, 0x3d, 0xa8, 0x66, 0xb1, 0x9a, 0xa0, 0xa0, 0x0e,
-	0x15, 0xd4, 0xdc, 0x91, 0x4e, 0x38, 0x97, 0x3b, 0xc1, 0x7a, 0xd4, 0x57, 0x36, 0xab, 0xa2, 0x41,
-	0x8d, 0x90, 0x8f, 0xa1, 0xa2, 0x43, 0x95, 0x29, 0xec, 0x4a, 0x85, 0xb3, 0x59, 0xb0, 0xe6, 0x04,
-	0xca, 0x0a, 0xcb, 0xee, 0xef, 0x41, 0xc5, 0x51, 0x19, 0x3b, 0x0c, 0x64, 0xca, 0x72, 0xb3, 0xde,
-	0x58, 0x6d, 0x15, 0xbb, 0xbb, 0x6d, 0x55, 0x9d, 0x8b, 0x19, 0x6d, 0x95, 0x9d, 0x85, 0x31, 0x27,
-	0x4d, 0x58, 0x97, 0x45, 0x60, 0xbe, 0x29, 0xe7, 0xdd, 0x6a, 0xa7, 0x25, 0x31, 0x48, 0x7e, 0xad,
-	0xd4, 0xd4, 0xfc, 0x66, 0x15, 0xca, 0x5a, 0xe7, 0x34, 0x25, 0x9e, 0x92, 0x12, 0xd7, 0xa1, 0x7c,
-	0x2c, 0x1e, 0x2a, 0x21, 0x96, 0x85, 0x63, 0x7b, 0x31, 0x1c, 0x79, 0x34, 0xea, 0xcb, 0xa3, 0xf1,
-	0x8b, 0x01, 0xe6, 0x21, 0x4e, 0x99, 0x8d, 0x3d, 0x5b, 0xb0, 0x69, 0x5a, 0xc2, 0xc8, 0xc3, 0xc0,
-	0xe7, 0x2f, 0x2d, 0x2c, 0x4f, 0xd8, 0x48, 0xf1, 0xc5, 0x36, 0xb2, 0xb3, 0x7c, 0x23, 0x3f, 0xaf,
-	0xc1, 0xf9, 0x43, 0x74, 0xe2, 0xd0, 0x63, 0x36, 0x15, 0xe8, 0x9c, 0xf6, 0x9c, 0x7f, 0xaf, 0xe7,
-	0xac, 0x3e, 0x77, 0xcf, 0xa9, 0x43, 0x91, 0x63, 0x34, 0xc5, 0x68, 0x28, 0xd8, 0x04, 0xcd, 0x3d,
-	0xf9, 0x04, 0x83, 0x14, 0x1a, 0xb0, 0x09, 0x92, 0x43, 0xa8, 0x46, 0x2a, 0x1d, 0x87, 0x02, 0x27,
-	0xa1, 0x47, 0x85, 0xce, 0xe7, 0xbd, 0xe3, 0xd9, 0xa3, 0xc3, 0x55, 0xd1, 0x77, 0x0c, 0xd4, 0x0d,
-	0xcf, 0xd5, 0x97, 0x7e, 0x5a, 0x83, 0xbd, 0x93, 0x95, 0xf0, 0x20, 0x46, 0x2e, 0x5e, 0x95, 0xf4,
-	0xf9, 0x0f, 0x3c, 0x84, 0xfa, 0x70, 0x96, 0x66, 0xee, 0xcf, 0x25, 0xf6, 0xa4, 0xc4, 0x6b, 0xf9,
-	0x22, 0xf2, 0x18, 0x65, 0x5a, 0x84, 0x9e, 0xc0, 0xfe, 0xa9, 0x67, 0xda, 0x77, 0xeb, 0xf0, 0xc6,
-	0x7c, 0xf3, 0x79, 0xc5, 0xf3, 0xe8, 0x7f, 0xd7, 0x86, 0x5e, 0x72, 0xd6, 0x1d, 0xeb, 0x6a, 0xe6,
-	0x89, 0xae, 0xd6, 0x5f, 0xde, 0xd5, 0x1a, 0x59, 0x5e, 0x2e, 0x79, 0x2a, 0xbf, 0x60, 0x7b, 0xfb,
-	0x71, 0x05, 0x6a, 0xb9, 0xd8, 0x8d, 0x31, 0xf5, 0x3c, 0xf4, 0x5d, 0x3c, 0xcd, 0xcc, 0xe5, 0x99,
-	0xd9, 0x74, 0xe0, 0xc2, 0x13, 0x5d, 0xf6, 0x52, 0x8f, 0x47, 0x4d, 0x02, 0x95, 0x3b, 0xf1, 0x88,
-	0xdb, 0x11, 0x1b, 0xe9, 0x70, 0x34, 0xcb, 0x50, 0xba, 0x23, 0xa8, 0x88, 0xb9, 0x06, 0xfe, 0x5c,
-	0x85, 0x42, 0x8a, 0x90, 0x16, 0x14, 0xf8, 0x8c, 0x0b, 0x9c, 0xc8, 0x59, 0x8b, 0xdd, 0x4a, 0x3b,
-	0x79, 0xa3, 0xbd, 0x23, 0xa1, 0x84, 0xc2, 0x2d, 0x65, 0x27, 0xd7, 0x60, 0xd3, 0x0e, 0x26, 0x61,
-	0xe0, 0xa3, 0x2f, 0xd4, 0x42, 0xce, 0x4a, 0xf2, 0x0d, 0x8d, 0xa6, 0xfc, 0x9c, 0x45, 0x9a, 0x50,
-	0x88, 0xe5, 0xc9, 0x49, 0x1d, 0xd1, 0x40, 0xf2, 0x2d, 0x2a, 0x90, 0x5b, 0xca, 0x42, 0x3a, 0x50,
-	0x4a, 0xaf, 0x86, 0xb1, 0xcf, 0x1e, 0xc4, 0x28, 0x43, 0xb3, 0x48, 0xdd, 0x4a, 0x09, 0x77, 0xa5,
-	0x9d, 0x5c, 0x82, 0x33, 0xba, 0xab, 0x4a, 0xbf, 0x2f, 0x72, 0x33, 0x1b, 0x79, 0x1b, 0x8a, 0x79,
-	0x35, 0x71, 0x19, 0x8b, 0x45, 0xea, 0xbc, 0x99, 0x7c, 0x08, 0x73, 0xb5, 0xc7, 0xf5, 0x5a, 0xca,
-	0x27, 0x6e, 0xaa, 0xce, 0xb1, 0xd4, 0x82, 0xde, 0x83, 0x92, 0x93, 0xb5, 0xeb, 0xe4, 0x3c, 0x5a,
-	0x99, 0xf3, 0xe4, 0x6d, 0x8c, 0xec, 0xe4, 0xa5, 0xdc, 0x43, 0x6e, 0x2d, 0xd2, 0xc8, 0x65, 0xa8,
-	0xda, 0x81, 0xef, 0xa3, 0x2d, 0xd0, 0x19, 0x46, 0x41, 0x2c, 0x30, 0xe2, 0xb2, 0x55, 0x95, 0xac,
-	0x4a, 0x66, 0xb0, 0x52, 0x9c, 0x5c, 0x01, 0x92, 0x93, 0xc7, 0xd4, 0x77, 0xbc, 0x84, 0xbd, 0x2b,
-	0xd9, 0xb9, 0xcc, 0xa7, 0xca, 0xd0, 0xfc, 0x02, 0xf6, 0x7b, 0x61, 0x36, 0x95, 0x82, 0x2d, 0x74,
-	0x19, 0x17, 0xe9, 0x9b, 0xf5, 0x5c, 0xf2, 0x1a, 0xf3, 0xc9, 0x7b, 0x11, 0x40, 0xa9, 0xcf, 0x7d,
-	0x37, 0x50, 0xc8, 0x91, 0xd3, 0xfd, 0x61, 0x05, 0x0a, 0x07, 0xb2, 0xa5, 0x90, 0xeb, 0xb0, 0xd9,
-	0xe3, 0x3c, 0xb0, 0x59, 0xd2, 0x34, 0x76, 0x74, 0xa3, 0x59, 0x38, 0x29, 0xd7, 0x96, 0x9d, 0xaa,
-	0x5a, 0xc6, 0x55, 0x83, 0x7c, 0x06, 0x9b, 0x59, 0xaa, 0x12, 0x53, 0x33, 0x8f, 0x67, 0x6f, 0xed,
-	0xf5, 0xbc, 0x87, 0x2d, 0x39, 0x90, 0x5f, 0x35, 0xc8, 0x47, 0xb0, 0x71, 0x3b, 0x1e, 0x79, 0x8c,
-	0x8f, 0xc9, 0xb2, 0x39, 0x6b, 0xbb, 0xed, 0xf4, 0x03, 0x50, 0x5b, 0x7f, 0xda, 0x69, 0xdf, 0x9c,
-	0x84, 0x62, 0xd6, 0x32, 0x48, 0x1f, 0xce, 0xa8, 0xd2, 0x44, 0x52, 0x5f, 0xde, 0x32, 0xd3, 0xf5,
-	0x3c, 0xb3, 0xa7, 0x76, 0xbf, 0x37, 0xa0, 0x94, 0x3a, 0xa9, 0x4f, 0x7d, 0xea, 0x62, 0x44, 0xbe,
-	0x82, 0x5a, 0xea, 0x7c, 0x8c, 0x4e, 0x86, 0x85, 0x5c, 0xd2, 0x8a, 0x4f, 0x0f, 0xd9, 0xb2, 0x0d,
-	0x90, 0x2e, 0x6c, 0x7e, 0x82, 0x42, 0x15, 0x74, 0x16, 0x89, 0x85, 0x92, 0xaf, 0x6d, 0x2f, 0xc2,
-	0x07, 0x1f, 0xfc, 0xfa, 0x78, 0xdf, 0xf8, 0xed, 0xf1, 0xbe, 0xf1, 0xd7, 0xe3, 0x7d, 0xe3, 0xcb,
-	0xb7, 0x9e, 0xff, 0xdb, 0xda, 0xa8, 0x20, 0x67, 0x7f, 0xe7, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0x4b, 0x13, 0x77, 0x15, 0x90, 0x13, 0x00, 0x00,
+	// 1939 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xec, 0x59, 0x4b, 0x73, 0x1b, 0xc7,
+	0x11, 0xae, 0x25, 0xf8, 0x00, 0x1a, 0x00, 0x09, 0x8e, 0xf5, 0x58, 0x51, 0x0f, 0x22, 0x70, 0xe2,
+	0x30, 0x91, 0x05, 0x4a, 0x94, 0x2d, 0x5b, 0xb6, 0x12, 0x15, 0x44, 0x4a, 0x8a, 0x5c, 0xa2, 0x25,
+	0x8f, 0x28, 0x55, 0x2a, 0x95, 0xd4, 0xd6, 0x70, 0x77, 0x08, 0xae, 0xb9, 0xd8, 0x59, 0xce, 0x0c,
+	0x48, 0xe2, 0x3f, 0xe4, 0x87, 0xe4, 0x9a, 0x63, 0x2a, 0xf7, 0x54, 0x8e, 0xbe, 0xe4, 0x92, 0x43,
+	0x9c, 0xd2, 0x4f, 0xc8, 0x21, 0xe7, 0xd4, 0xbc, 0x76, 0x17, 0x84, 0x60, 0xcb, 0x2e, 0x55, 0x25,
+	0x29, 0xeb, 0x42, 0x4e, 0x7f, 0xdd, 0xd3, 0x3b, 0xd3, 0xfd, 0x4d, 0x4f, 0xef, 0x02, 0x3e, 0xea,
+	0xc7, 0x72, 0x7f, 0xb8, 0xdb, 0x0d, 0xd9, 0x60, 0x7d, 0x67, 0x9f, 0xee, 0xec, 0xc7, 0x69, 0x5f,
+	0x7c, 0x4e, 0xe5, 0x31, 0xe3, 0x07, 0xeb, 0x52, 0xa6, 0xeb, 0x24, 0x8b, 0xd7, 0x77, 0x39, 0x3b,
+	0xa0, 0xdc, 0xfe, 0xeb, 0x66, 0x9c, 0x49, 0x86, 0xe6, 0x8d, 0xb4, 0x72, 0xb1, 0xcf, 0x58, 0x3f,
+	0xa1, 0xeb, 0x1a, 0xdd, 0x1d, 0xee, 0xad, 0xd3, 0x41, 0x26, 0x47, 0xc6, 0x68, 0xe5, 0x5a, 0xc9,
+	0x7b, 0x9f, 0xf5, 0x59, 0x61, 0xa5, 0x24, 0x2d, 0xe8, 0x91, 0x35, 0x5f, 0x76, 0x0f, 0x24, 0x59,
+	0x6c, 0xa1, 0x55, 0x07, 0x69, 0x31, 0x64, 0x49, 0x3e, 0xb0, 0x06, 0x97, 0x9d, 0x41, 0x9f, 0x48,
+	0x7a, 0x4c, 0x46, 0xee, 0xbf, 0x55, 0x5f, 0x70, 0x6a, 0xc9, 0x49, 0x48, 0xcd, 0x5f, 0xa3, 0xea,
+	0xfc, 0x79, 0x06, 0x16, 0xb7, 0xd8, 0x71, 0x9a, 0xc4, 0xe9, 0xc1, 0x93, 0x4c, 0xc6, 0x2c, 0x45,
+	0x57, 0x00, 0xe2, 0x88, 0xa6, 0x32, 0xde, 0x8b, 0x29, 0xf7, 0xbd, 0xb6, 0xb7, 0x56, 0xc3, 0x25,
+	0x04, 0x5d, 0x06, 0xb0, 0xee, 0x83, 0x38, 0xf2, 0x67, 0xb4, 0xbe, 0x66, 0x91, 0x47, 0x11, 0x3a,
+	0x03, 0x73, 0x22, 0x64, 0x9c, 0xfa, 0x95, 0xb6, 0xb7, 0xd6, 0xc4, 0x46, 0x40, 0x2b, 0x50, 0x8d,
+	0x28, 0x89, 0x92, 0x38, 0xa5, 0xfe, 0x6c, 0xdb, 0x5b, 0xab, 0xe0, 0x5c, 0x46, 0xf7, 0x60, 0xc9,
+	0xed, 0x27, 0x08, 0x59, 0xba, 0x17, 0xf7, 0xfd, 0xb9, 0xb6, 0xb7, 0x56, 0xdf, 0xb8, 0xd0, 0xcd,
+	0xf7, 0xb9, 0x73, 0xb2, 0xa9, 0x35, 0x43, 0x4e, 0xd4, 0x22, 0xf1, 0xa2, 0xd3, 0x18, 0x18, 0xdd,
+	0x85, 0x45, 0xb7, 0x28, 0xeb, 0x62, 0x5e, 0xbb, 0xf0, 0xbb, 0x2e, 0x14, 0xa7, 0x3d, 0x34, 0xad,
+	0xc2, 0x3a, 0xb8, 0x09, 0x75, 0x7e, 0x12, 0x08, 0x2a, 0xa5, 0x4a, 0xbe, 0xbf, 0xa0, 0x67, 0xa3,
+	0xae, 0x4d, 0x37, 0xfe, 0xf5, 0x33, 0xab, 0xc1, 0xc0, 0x4f, 0xdc, 0xb8, 0xf3, 0x7b, 0x0f, 0xa0,
+	0x50, 0xa1, 0x8b, 0x50, 0xe3, 0x27, 0x37, 0x82, 0x88, 0x26, 0x64, 0xa4, 0x03, 0xd7, 0xc4, 0x55,
+	0x7e, 0x72, 0x63, 0x4b, 0xc9, 0xa8, 0x03, 0x4d, 0xad, 0xe4, 0x01, 0xdb, 0xdb, 0x13, 0x54, 0xea,
+	0xc8, 0x35, 0x71, 0x5d, 0x19, 0xf0, 0x27, 0x1a, 0x32, 0x36, 0x1b, 0x41, 0x44, 0x24, 0x09, 0x38,
+	0x91, 0x26, 0x86, 0x35, 0x65, 0xb3, 0xb1, 0x45, 0x24, 0xc1, 0x44, 0x52, 0x74, 0x01, 0xaa, 0xca,
+	0x86, 0xa5, 0xc9, 0x48, 0x47, 0xb2, 0x8a, 0x17, 0xf8, 0xc9, 0xc6, 0x93, 0x34, 0x19, 0x75, 0xfe,
+	0x30, 0x0b, 0xcd, 0xe7, 0x99, 0x4a, 0xe5, 0x36, 0x15, 0x82, 0xf4, 0x29, 0xf2, 0x61, 0x21, 0x23,
+	0xa3, 0x84, 0x91, 0x48, 0xaf, 0xa7, 0x81, 0x9d, 0x88, 0xae, 0xc2, 0xc2, 0xc0, 0x18, 0xe9, 0x85,
+	0xd4, 0x37, 0x96, 0x8b, 0x60, 0xdb, 0xd9, 0xd8, 0x59, 0xa0, 0xcf, 0x61, 0x21, 0xa2, 0x47, 0x01,
+	0x1d, 0xc6, 0x7e, 0x5d, 0xb9, 0xb9, 0xf7, 0xe1, 0xdf, 0xff, 0xb1, 0x7a, 0xe3, 0xdb, 0x4e, 0x8d,
+	0x4a, 0xfc, 0xba, 0x1c, 0x65, 0x54, 0x74, 0xb7, 0xe8, 0xd1, 0xfd, 0xe7, 0x8f, 0xf0, 0x7c, 0x44,
+	0x8f, 0xee, 0x0f, 0x63, 0xe5, 0x8f, 0x64, 0x99, 0xf6, 0xd7, 0xf8, 0x5e, 0xfe, 0x7a, 0x59, 0xa6,
+	0xfd, 0x91, 0x2c, 0x53, 0xfe, 0xce, 0x82, 0x1a, 0x29, 0x3a, 0x36, 0x75, 0xc0, 0xe6, 0x48, 0x96,
+	0x3d, 0x8a, 0x14, 0xac, 0x96, 0x1d, 0x47, 0xfe, 0xa2, 0x81, 0x23, 0x7a, 0xf4, 0x28, 0x42, 0x3d,
+	0x58, 0xce, 0xf9, 0x36, 0xa0, 0x92, 0xa8, 0x70, 0xfb, 0x67, 0x75, 0x10, 0xce, 0x14, 0x41, 0xc0,
+	0x27, 0xdb, 0x56, 0x87, 0x5b, 0x0e, 0x74, 0x08, 0xfa, 0x25, 0xb4, 0x1c, 0xdd, 0x72, 0x0f, 0xe7,
+	0xb4, 0x87, 0x77, 0x72, 0xc2, 0x95, 0x1c, 0x2c, 0x59, 0x2c, 0x9f, 0xdf, 0x83, 0x56, 0x64, 0x4f,
+	0x5d, 0xc0, 0xf4, 0xb1, 0x13, 0xfe, 0x6a, 0xbb, 0xb2, 0x56, 0xdf, 0x38, 0xe7, 0x28, 0x37, 0x7e,
+	0x2a, 0xf1, 0x52, 0x34, 0x26, 0x0b, 0x95, 0x5a, 0x4d, 0x34, 0x1a, 0xf9, 0x6d, 0x43, 0x03, 0x2b,
+	0xa2, 0x0e, 0xcc, 0xe9, 0x23, 0xee, 0xff, 0x4c, 0xaf, 0xa8, 0xd1, 0xd5, 0x52, 0x77, 0x47, 0xfd,
+	0xc5, 0x46, 0xd5, 0xf9, 0x5b, 0x05, 0x96, 0xdc, 0x13, 0xde, 0x92, 0xe5, 0x1b, 0xc8, 0x72, 0x17,
+	0x96, 0x4e, 0x65, 0xca, 0x52, 0x65, 0x5a, 0xa2, 0x16, 0xc7, 0x13, 0xa5, 0x2a, 0x5f, 0xc6, 0x63,
+	0xc6, 0x63, 0x39, 0xd2, 0x14, 0xa9, 0xe1, 0x5c, 0x46, 0xef, 0x03, 0x1a, 0x90, 0x30, 0x08, 0xd9,
+	0x60, 0x40, 0xd2, 0x48, 0x04, 0x24, 0x8a, 0x68, 0xe4, 0x9f, 0xd7, 0xe9, 0x6c, 0x0d, 0x48, 0xb8,
+	0x69, 0x15, 0xbd, 0x28, 0x2a, 0xe7, 0x75, 0x75, 0x7a, 0x5e, 0xff, 0xe2, 0x81, 0xbf, 0x45, 0x8f,
+	0xe2, 0x90, 0xf6, 0x42, 0x19, 0x1f, 0x99, 0x52, 0x47, 0x45, 0xc6, 0x52, 0xf1, 0xc6, 0x12, 0xfc,
+	0x8a, 0x90, 0xd4, 0xbf, 0x53, 0x48, 0xf2, 0x8d, 0x9c, 0x9d, 0xbe, 0x91, 0x7f, 0x55, 0xe1, 0xc2,
+	0x16, 0x8d, 0x86, 0x59, 0x12, 0x87, 0x44, 0xd2, 0xe8, 0x6d, 0x5d, 0xfb, 0xef, 0xd5, 0xb5, 0xca,
+	0x6b, 0xd7, 0xb5, 0x55, 0xa8, 0x0b, 0xca, 0x8f, 0x28, 0x0f, 0x64, 0x3c, 0xa0, 0x9a, 0xc9, 0x15,
+	0x0c, 0x06, 0xda, 0x89, 0x07, 0x14, 0x6d, 0xc1, 0x32, 0xb7, 0x74, 0x0c, 0x24, 0x1d, 0x64, 0x09,
+	0x91, 0x8e, 0xcf, 0xe7, 0x4f, 0xb3, 0xc7, 0xa5, 0xab, 0xe5, 0x66, 0xec, 0xd8, 0x09, 0xaf, 0x53,
+	0xe1, 0xd4, 0x93, 0x32, 0x96, 0xc4, 0xe1, 0x28, 0x38, 0x8a, 0x59, 0x42, 0x4c, 0x8d, 0xbd, 0xd9,
+	0xae, 0x94, 0x9f, 0xf4, 0x54, 0x1b, 0xbc, 0x70, 0x7a, 0xdc, 0xca, 0xc6, 0x01, 0x81, 0x6e, 0x43,
+	0x93, 0xd3, 0x2c, 0x21, 0xa3, 0x80, 0x48, 0x49, 0xc2, 0x03, 0xff, 0x03, 0x1b, 0x4f, 0xeb, 0x01,
+	0x6b, 0x65, 0x4f, 0xeb, 0x70, 0x83, 0x97, 0x24, 0xb4, 0x01, 0x70, 0x38, 0x24, 0x9c, 0xa4, 0x52,
+	0x35, 0x3d, 0x1f, 0x8e, 0x37, 0x14, 0x5f, 0xe4, 0x1a, 0x5c, 0xb2, 0x42, 0x77, 0xa0, 0xa1, 0x8f,
+	0xd5, 0xe1, 0x90, 0x24, 0xaa, 0x60, 0xdc, 0xb2, 0x7d, 0x90, 0x9d, 0xf5, 0x38, 0x4e, 0x0f, 0xbe,
+	0x30, 0xaa, 0xcd, 0x7d, 0x92, 0xf6, 0x29, 0xae, 0x27, 0x05, 0x84, 0x6e, 0x41, 0x23, 0xd2, 0x67,
+	0x3f, 0xe0, 0x54, 0x75, 0x18, 0x1f, 0xd9, 0x1b, 0xc9, 0xc5, 0x55, 0xeb, 0xb0, 0x52, 0xe1, 0x7a,
+	0x54, 0x08, 0xe8, 0x5d, 0xb5, 0x49, 0xb5, 0xc7, 0x3d, 0xc6, 0x8f, 0x09, 0x8f, 0xfc, 0x8f, 0x75,
+	0x05, 0x6a, 0x68, 0xf0, 0x81, 0xc1, 0xd0, 0x55, 0x98, 0xd3, 0xb2, 0x7f, 0x5b, 0x7b, 0x3d, 0x5b,
+	0x44, 0x20, 0x29, 0x08, 0x80, 0x8d, 0x8d, 0x2a, 0x11, 0x7b, 0x61, 0x2a, 0x03, 0x4e, 0xfb, 0x9c,
+	0x0a, 0xa1, 0x4a, 0xc4, 0x27, 0xe3, 0x25, 0xe2, 0xc1, 0x66, 0x2a, 0x71, 0xae, 0xc5, 0x8b, 0x7b,
+	0x61, 0x59, 0x56, 0x44, 0x4a, 0x59, 0xe0, 0xea, 0x86, 0xff, 0xa9, 0x5e, 0x10, 0xa4, 0xcc, 0x71,
+	0xe3, 0x95, 0x37, 0xe8, 0x9d, 0xef, 0x76, 0x83, 0xb6, 0xa0, 0x22, 0xe8, 0xa1, 0xff, 0x8b, 0xb6,
+	0xb7, 0x36, 0x8b, 0xd5, 0xb0, 0xf3, 0x3b, 0x58, 0x3a, 0x45, 0x09, 0x74, 0x0e, 0xe6, 0x0d, 0x29,
+	0x6c, 0x27, 0x6c, 0x25, 0x74, 0x09, 0x6a, 0x39, 0xaf, 0x5c, 0x13, 0x9c, 0x03, 0xba, 0x09, 0x8e,
+	0xd3, 0xd0, 0x34, 0x70, 0x15, 0x6c, 0x84, 0xce, 0x03, 0x68, 0x94, 0xf9, 0xa2, 0x9b, 0xe2, 0x58,
+	0x48, 0xa2, 0x0c, 0x6d, 0xbb, 0xe8, 0x64, 0xa5, 0xb3, 0x87, 0x4b, 0xf8, 0x33, 0xed, 0x8a, 0xba,
+	0x36, 0x9c, 0xdc, 0xf9, 0x04, 0xa0, 0xe0, 0x8f, 0x5a, 0x21, 0xa7, 0x44, 0xb0, 0xd4, 0xad, 0xd0,
+	0x48, 0xc5, 0x1a, 0x66, 0xca, 0x6b, 0x10, 0xb0, 0x3c, 0xc1, 0x22, 0x55, 0x4e, 0x1d, 0xe3, 0x8c,
+	0x0f, 0x27, 0x9a, 0xdb, 0x8b, 0x1e, 0xc5, 0x6c, 0x28, 0xec, 0x2e, 0x73, 0x79, 0x4a, 0xa7, 0x8f,
+	0x60, 0x36, 0x61, 0x42, 0xe8, 0xde, 0xd4, 0xc3, 0x7a, 0xdc, 0xb9, 0x05, 0xf5, 0x12, 0xf9, 0xd0,
+	0x4f, 0x61, 0x29, 0x61, 0x9c, 0x1c, 0x93, 0x34, 0x38, 0xa2, 0x5c, 0xb3, 0xc3, 0x3c, 0x76, 0xd1,
+	0xc2, 0x2f, 0x0c, 0xda, 0x79, 0x08, 0xcd, 0x31, 0x7a, 0x95, 0x2a, 0x9f, 0x57, 0xae, 0x7c, 0xab,
+	0x50, 0x3f, 0x66, 0x3c, 0x08, 0xf7, 0x49, 0x9a, 0xd2, 0xc4, 0x76, 0xd6, 0x70, 0xcc, 0xf8, 0xa6,
+	0x41, 0x3a, 0x9b, 0xb0, 0x38, 0x4e, 0x38, 0xf4, 0x0e, 0xcc, 0xed, 0x05, 0x61, 0x2a, 0x6d, 0xe0,
+	0x67, 0xf7, 0x36, 0x53, 0x89, 0x2e, 0x01, 0x24, 0x44, 0xc8, 0xc0, 0x68, 0x8c, 0x9b, 0xaa, 0x42,
+	0xd4, 0xe4, 0xce, 0x9f, 0x66, 0xe1, 0xfc, 0xe4, 0xdd, 0x7a, 0x38, 0xa4, 0x42, 0xfe, 0x50, 0x2e,
+	0xa4, 0xff, 0x81, 0xd6, 0x79, 0x1b, 0xde, 0x21, 0x79, 0xf8, 0x0b, 0x17, 0xe7, 0xb5, 0x8b, 0x4b,
+	0xc5, 0x22, 0x8a, 0x1c, 0xe5, 0xbe, 0x10, 0x99, 0xc0, 0xde, 0x44, 0x27, 0xfe, 0x3a, 0xfd, 0xf6,
+	0xbf, 0xe7, 0xe0, 0xdd, 0x72, 0x3b, 0xf3, 0x03, 0xe7, 0xd1, 0xff, 0x5d, 0x63, 0xf3, 0x86, 0x59,
+	0x77, 0xaa, 0x4f, 0xf2, 0x27, 0xfa, 0xa4, 0xed, 0xe9, 0x7d, 0x52, 0x7b, 0xfc, 0x3e, 0x9f, 0xec,
+	0xf3, 0xbf, 0x67, 0xc3, 0x74, 0x0d, 0x6a, 0x5f, 0xb2, 0x38, 0x0d, 0x12, 0xc6, 0x32, 0xff, 0xa6,
+	0xb6, 0x6b, 0xb9, 0x47, 0x7d, 0xc6, 0xe2, 0xf4, 0x31, 0x63, 0x19, 0xae, 0x7e, 0x69, 0x47, 0xe8,
+	0x27, 0xc5, 0x17, 0x97, 0x3e, 0x67, 0xc3, 0x4c, 0xf8, 0x1f, 0xe8, 0x6b, 0xca, 0x7d, 0x57, 0x79,
+	0xa8, 0xc1, 0xce, 0x8f, 0xa1, 0xea, 0x26, 0x6b, 0x72, 0xd3, 0x94, 0x24, 0xf6, 0x9a, 0xa9, 0x60,
+	0x27, 0x76, 0xfe, 0x38, 0x03, 0x2b, 0xc5, 0x46, 0x36, 0xf7, 0x49, 0x92, 0x50, 0xd5, 0xde, 0xbc,
+	0x3d, 0x15, 0x53, 0x4f, 0x45, 0x27, 0x82, 0x8b, 0xaf, 0x0c, 0xd9, 0x1b, 0x7d, 0xd9, 0xeb, 0x20,
+	0x68, 0x3d, 0x1b, 0xee, 0x8a, 0x90, 0xc7, 0xbb, 0x2e, 0x1d, 0x9d, 0x36, 0x34, 0x72, 0xac, 0x17,
+	0x1e, 0xb8, 0x46, 0xca, 0x2b, 0x1a, 0xa9, 0x25, 0x68, 0x3e, 0x93, 0x44, 0x0e, 0x85, 0x9b, 0xf2,
+	0x75, 0x05, 0xe6, 0x0d, 0x82, 0xd6, 0x60, 0x5e, 0x8c, 0x84, 0xa4, 0x03, 0xdf, 0xb3, 0x24, 0x53,
+	0x5f, 0x3a, 0x9f, 0x69, 0x48, 0x99, 0x08, 0x6c, 0xf5, 0xe8, 0x06, 0xd4, 0x42, 0x36, 0xc8, 0x58,
+	0x4a, 0xed, 0x6d, 0xac, 0x4e, 0xab, 0x32, 0xde, 0x74, 0xa8, 0xb1, 0x2f, 0xac, 0x50, 0x07, 0xe6,
+	0x87, 0xfa, 0x4d, 0xd1, 0xbe, 0x92, 0x82, 0xb6, 0xc7, 0x44, 0x52, 0x81, 0xad, 0x06, 0xad, 0x43,
+	0xd3, 0x8c, 0x82, 0x61, 0x1a, 0x1f, 0x0e, 0xa9, 0xdf, 0x98, 0x30, 0x6d, 0x18, 0x83, 0xe7, 0x5a,
+	0x8f, 0xde, 0x83, 0x6a, 0xde, 0x89, 0x36, 0x27, 0x6c, 0x73, 0x1d, 0x7a, 0x1f, 0xea, 0xc5, 0x59,
+	0x17, 0xfe, 0xe2, 0x84, 0x69, 0x59, 0x8d, 0x6e, 0x43, 0xa9, 0x32, 0x08, 0xb7, 0x96, 0xa5, 0x89,
+	0x49, 0xcb, 0x25, 0x2b, 0xbb, 0xa0, 0x5b, 0xd0, 0x8c, 0xf2, 0xcb, 0x44, 0xb5, 0x4f, 0xad, 0x52,
+	0x24, 0x9f, 0x52, 0x1e, 0xd2, 0x54, 0xc6, 0x09, 0x15, 0x78, 0xdc, 0x0c, 0x5d, 0x85, 0xe5, 0x90,
+	0xa5, 0x29, 0x0d, 0x25, 0x8d, 0x02, 0xce, 0x86, 0x92, 0x72, 0xa1, 0x0b, 0x69, 0x13, 0xb7, 0x72,
+	0x05, 0x36, 0x38, 0xba, 0x06, 0xa8, 0x30, 0xde, 0x27, 0x69, 0x94, 0x28, 0xeb, 0x73, 0xda, 0xba,
+	0x70, 0xf3, 0x2b, 0xab, 0xe8, 0xbc, 0x80, 0x2b, 0xbd, 0x2c, 0x7f, 0x94, 0x85, 0x31, 0xed, 0xc7,
+	0x42, 0x9a, 0x2f, 0xae, 0x25, 0x7a, 0x7b, 0x65, 0x7a, 0x5f, 0x06, 0xb0, 0xde, 0x4b, 0xdf, 0x93,
+	0x2d, 0xf2, 0x28, 0xda, 0xf8, 0x7a, 0x06, 0xe6, 0xef, 0xe9, 0x2a, 0x84, 0xee, 0x42, 0xad, 0x27,
+	0x04, 0x0b, 0x63, 0x55, 0xd2, 0xf2, 0x17, 0x90, 0xb1, 0x2f, 0x03, 0x2b, 0xd3, 0xde, 0x22, 0xd7,
+	0xbc, 0xeb, 0x1e, 0xfa, 0x0c, 0x6a, 0x39, 0x71, 0x91, 0xef, 0x2c, 0x4f, 0xf3, 0x7b, 0xe5, 0x47,
+	0xb9, 0x8f, 0x69, 0x1f, 0x20, 0xae, 0x7b, 0xe8, 0x0e, 0x2c, 0x3c, 0x1d, 0xee, 0x26, 0xb1, 0xd8,
+	0x47, 0xd3, 0x9e, 0xb9, 0x72, 0xae, 0x6b, 0x7e, 0x18, 0xe8, 0xba, 0x4f, 0xfe, 0xdd, 0xfb, 0xea,
+	0x87, 0x81, 0x35, 0x0f, 0x7d, 0x0a, 0xf5, 0x5e, 0x78, 0x90, 0xb2, 0xe3, 0x84, 0x46, 0x7d, 0x8a,
+	0xce, 0x4c, 0xac, 0xa5, 0x17, 0x1e, 0x4c, 0x9b, 0x8e, 0xb6, 0xa1, 0x6a, 0x4f, 0x3e, 0x45, 0xab,
+	0xd3, 0x6f, 0x03, 0xb3, 0x99, 0x6f, 0xbd, 0x2e, 0x36, 0xbe, 0xf2, 0xa0, 0x69, 0x22, 0xbc, 0x4d,
+	0x52, 0xd2, 0xa7, 0x1c, 0xfd, 0x16, 0x56, 0x4c, 0xe6, 0x28, 0x9f, 0xcc, 0x29, 0x7a, 0xcf, 0x79,
+	0xfc, 0xe6, 0x7c, 0x4f, 0x5d, 0xfe, 0x06, 0xd4, 0x1e, 0x52, 0x69, 0xab, 0x41, 0x9e, 0xc6, 0xb1,
+	0x7a, 0xb1, 0xb2, 0x38, 0x0e, 0xa3, 0x6b, 0x50, 0xdd, 0x21, 0x71, 0xf2, 0x98, 0xf5, 0x05, 0x32,
+	0x34, 0x57, 0x43, 0x67, 0xdd, 0x74, 0xc8, 0xfd, 0x54, 0xf2, 0xd1, 0x75, 0xef, 0xde, 0xc7, 0x7f,
+	0x7d, 0x79, 0xc5, 0xfb, 0xea, 0xe5, 0x15, 0xef, 0x9f, 0x2f, 0xaf, 0x78, 0xbf, 0xf9, 0xf9, 0xeb,
+	0xff, 0xbe, 0xb3, 0x3b, 0xaf, 0x17, 0x7b, 0xf3, 0x3f, 0x03, 0x00, 0x43, 0x6c, 0xdf, 0x92, 0x14,
+	0x1a, 0x00, 0x00,
 }
@@ -6,11 +6,14 @@ package broker
 import (
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
 	"github.com/TheThingsNetwork/ttn/api/fields"
 	"github.com/TheThingsNetwork/ttn/utils/backoff"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/golang/protobuf/ptypes/empty"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -42,6 +45,8 @@ type RouterStream interface {
 	Stream
 	Send(*UplinkMessage) error
 	Channel() <-chan *DownlinkMessage
+	// Connected returns true if the Associate stream is established
+	Connected() bool
 }
 
 // NewMonitoredRouterStream starts and monitors a RouterStream
@@ -76,6 +81,7 @@ func NewMonitoredRouterStream(client BrokerClient, getContextFunc func() context
 				continue
 			}
 			retries = 0
+			atomic.StoreInt32(&s.connected, 1)
 
 			s.ctx.Debug("Started Associate stream")
 
@@ -133,6 +139,7 @@ func NewMonitoredRouterStream(client BrokerClient, getContextFunc func() context
 				}
 			}
 
+			atomic.StoreInt32(&s.connected, 0)
 			close(up)
 			client.CloseSend()
 
@@ -146,6 +153,7 @@ func NewMonitoredRouterStream(client BrokerClient, getContextFunc func() context
 				break
 			}
 
+			api.StreamRestarted("broker.Associate")
 			s.setup.Add(1)
 			time.Sleep(backoff.Backoff(retries))
 			retries++
@@ -157,17 +165,22 @@ func NewMonitoredRouterStream(client BrokerClient, getContextFunc func() context
 
 type routerStream struct {
 	stream
-	cancel context.CancelFunc
-	up     chan *UplinkMessage
-	down   chan *DownlinkMessage
-	err    chan error
+	cancel    context.CancelFunc
+	up        chan *UplinkMessage
+	down      chan *DownlinkMessage
+	err       chan error
+	connected int32
 }
 
+// ErrBufferFull is returned when a message is dropped because the buffer of the stream is full
+var ErrBufferFull = errors.New("Buffer full")
+
 func (s *routerStream) Send(uplink *UplinkMessage) error {
 	select {
 	case s.up <- uplink:
 	default:
 		s.ctx.Warn("Dropping Uplink message, buffer full")
+		return ErrBufferFull
 	}
 	return nil
 }
@@ -176,6 +189,10 @@ func (s *routerStream) Channel() <-chan *DownlinkMessage {
 	return s.down
 }
 
+func (s *routerStream) Connected() bool {
+	return atomic.LoadInt32(&s.connected) == 1
+}
+
 func (s *routerStream) Close() {
 	s.closing = true
 	close(s.up)
@@ -275,6 +292,7 @@ func NewMonitoredHandlerPublishStream(client BrokerClient, getContextFunc func()
 				break
 			}
 
+			api.StreamRestarted("broker.Publish")
 			s.setup.Add(1)
 			time.Sleep(backoff.Backoff(retries))
 			retries++
@@ -310,18 +328,24 @@ func (s *handlerPublishStream) Close() {
 type HandlerSubscribeStream interface {
 	Stream
 	Channel() <-chan *DeduplicatedUplinkMessage
+	// Ack acknowledges that the uplink message was handled, so that the Broker does not replay it
+	Ack(*DeduplicatedUplinkMessage)
 }
 
 // NewMonitoredHandlerSubscribeStream starts and monitors a HandlerSubscribeStream
 func NewMonitoredHandlerSubscribeStream(client BrokerClient, getContextFunc func() context.Context) HandlerSubscribeStream {
 	s := &handlerSubscribeStream{
-		ch:  make(chan *DeduplicatedUplinkMessage, DefaultBufferSize),
-		err: make(chan error),
+		ch:   make(chan *DeduplicatedUplinkMessage, DefaultBufferSize),
+		err:  make(chan error),
+		acks: newUplinkAcks(),
 	}
 	s.setup.Add(1)
 	s.client = client
 	s.ctx = log.Get()
 
+	stopAcks := make(chan struct{})
+	go s.acknowledge(getContextFunc, stopAcks)
+
 	go func() {
 		var client Broker_SubscribeClient
 		var err error
@@ -349,17 +373,37 @@ func NewMonitoredHandlerSubscribeStream(client BrokerClient, getContextFunc func
 
 			s.ctx.Info("Started Uplink stream")
 
+			// The Broker replays the messages that were not acknowledged
+			acked := s.acks.reset()
+			first := true
+
 			for {
 				message, err = client.Recv()
 				if message != nil {
 					s.ctx.WithFields(fields.Get(message)).Debug("Receiving Uplink message")
+					if message.Seq != 0 {
+						if first && acked != 0 && message.Seq > acked+1 {
+							s.ctx.WithField("Lost", message.Seq-acked-1).Warn("Broker could not replay all Uplink messages")
+						}
+						first = false
+						s.acks.receive(message.Seq)
+					}
 					if err := message.Validate(); err != nil {
 						s.ctx.WithError(err).Warn("Invalid Uplink")
+						s.Ack(message)
 						continue
 					}
 					if err := message.UnmarshalPayload(); err != nil {
 						s.ctx.Warn("Could not unmarshal Uplink payload")
 					}
+					if message.Seq != 0 {
+						// The Broker replays the message if it is not acknowledged, so it is not dropped
+						select {
+						case s.ch <- message:
+						case <-ctx.Done():
+						}
+						continue
+					}
 					select {
 					case s.ch <- message:
 					default:
@@ -381,11 +425,13 @@ func NewMonitoredHandlerSubscribeStream(client BrokerClient, getContextFunc func
 				break
 			}
 
+			api.StreamRestarted("broker.Subscribe")
 			s.setup.Add(1)
 			time.Sleep(backoff.Backoff(retries))
 			retries++
 		}
 
+		close(stopAcks)
 		close(s.ch)
 	}()
 	return s
@@ -396,6 +442,41 @@ type handlerSubscribeStream struct {
 	cancel context.CancelFunc
 	ch     chan *DeduplicatedUplinkMessage
 	err    chan error
+	acks   *uplinkAcks
+}
+
+func (s *handlerSubscribeStream) Ack(message *DeduplicatedUplinkMessage) {
+	if message.Seq != 0 {
+		s.acks.done(message.Seq)
+	}
+}
+
+// acknowledge periodically acknowledges the handled uplink messages to the
+// Broker, and once more when the stream is closed
+func (s *handlerSubscribeStream) acknowledge(getContextFunc func() context.Context, stop <-chan struct{}) {
+	ticker := time.NewTicker(AckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+		}
+		if seq, ok := s.acks.next(); ok {
+			ctx, cancel := context.WithTimeout(getContextFunc(), AckInterval)
+			_, err := s.client.Acknowledge(ctx, &SubscribeAck{Seq: seq})
+			cancel()
+			if err != nil {
+				s.ctx.WithError(err).Warn("Could not acknowledge Uplink messages")
+			} else {
+				s.acks.setAcked(seq)
+			}
+		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+	}
 }
 
 func (s *handlerSubscribeStream) Close() {
@@ -13,6 +13,7 @@ import (
 	"github.com/TheThingsNetwork/go-utils/log"
 	"github.com/TheThingsNetwork/ttn/api"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/golang/protobuf/ptypes/empty"
 	. "github.com/smartystreets/assertions"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -36,6 +37,10 @@ func (s *testBroker) Activate(context.Context, *DeviceActivationRequest) (*Devic
 	return nil, grpc.Errorf(codes.Unimplemented, "Not implemented")
 }
 
+func (s *testBroker) Acknowledge(context.Context, *SubscribeAck) (*empty.Empty, error) {
+	return nil, grpc.Errorf(codes.Unimplemented, "Not implemented")
+}
+
 func (s *testBroker) Serve(port int) {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
@@ -33,6 +33,8 @@ type Client interface {
 	RemoveAppID(appID string, token string) error
 	GetAllBrokersForDevAddr(devAddr types.DevAddr) ([]*Announcement, error)
 	GetAllHandlersForAppID(appID string) ([]*Announcement, error)
+	Invalidate(serviceName string)
+	Watch(serviceName string) (<-chan *AnnouncementChange, error)
 	Close() error
 }
 
@@ -241,6 +243,41 @@ next:
 	return
 }
 
+// Invalidate removes the cached announcements of the given service type, so
+// that they are requested from the Discovery server again
+func (c *DefaultClient) Invalidate(serviceName string) {
+	c.Lock()
+	defer c.Unlock()
+	for _, announcement := range c.lists[serviceName] {
+		c.cache.Remove(cacheKey{serviceName: announcement.ServiceName, id: announcement.Id})
+	}
+	delete(c.lists, serviceName)
+	delete(c.listsUpdated, serviceName)
+}
+
+// Watch the changes of the announcements of the given service type. The cached
+// announcements of the service type are invalidated before a change is sent.
+// The channel is closed when the stream with the Discovery server ends.
+func (c *DefaultClient) Watch(serviceName string) (<-chan *AnnouncementChange, error) {
+	stream, err := c.client.Watch(c.getContext(""), &GetServiceRequest{ServiceName: serviceName})
+	if err != nil {
+		return nil, err
+	}
+	changes := make(chan *AnnouncementChange)
+	go func() {
+		defer close(changes)
+		for {
+			change, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			c.Invalidate(serviceName)
+			changes <- change
+		}
+	}()
+	return changes, nil
+}
+
 // Close purges the cache and closes the connection with the Discovery server
 func (c *DefaultClient) Close() error {
 	c.cache.Purge()
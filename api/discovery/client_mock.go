@@ -123,6 +123,25 @@ func (_mr *_MockClientRecorder) GetAllHandlersForAppID(arg0 interface{}) *gomock
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetAllHandlersForAppID", arg0)
 }
 
+func (_m *MockClient) Invalidate(serviceName string) {
+	_m.ctrl.Call(_m, "Invalidate", serviceName)
+}
+
+func (_mr *_MockClientRecorder) Invalidate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Invalidate", arg0)
+}
+
+func (_m *MockClient) Watch(serviceName string) (<-chan *AnnouncementChange, error) {
+	ret := _m.ctrl.Call(_m, "Watch", serviceName)
+	ret0, _ := ret[0].(<-chan *AnnouncementChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockClientRecorder) Watch(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Watch", arg0)
+}
+
 func (_m *MockClient) Close() error {
 	ret := _m.ctrl.Call(_m, "Close")
 	ret0, _ := ret[0].(error)
@@ -15,6 +15,7 @@
 		GetRequest
 		MetadataRequest
 		AnnouncementsResponse
+		AnnouncementChange
 */
 package discovery
 
@@ -407,6 +408,33 @@ func (m *AnnouncementsResponse) GetServices() []*Announcement {
 	return nil
 }
 
+// The identifier of a service of which the announcement or metadata changed
+type AnnouncementChange struct {
+	// The ID of the service
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The name of the service (router/broker/handler)
+	ServiceName string `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+}
+
+func (m *AnnouncementChange) Reset()                    { *m = AnnouncementChange{} }
+func (m *AnnouncementChange) String() string            { return proto.CompactTextString(m) }
+func (*AnnouncementChange) ProtoMessage()               {}
+func (*AnnouncementChange) Descriptor() ([]byte, []int) { return fileDescriptorDiscovery, []int{6} }
+
+func (m *AnnouncementChange) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AnnouncementChange) GetServiceName() string {
+	if m != nil {
+		return m.ServiceName
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Metadata)(nil), "discovery.Metadata")
 	proto.RegisterType((*Announcement)(nil), "discovery.Announcement")
@@ -414,6 +442,7 @@ func init() {
 	proto.RegisterType((*GetRequest)(nil), "discovery.GetRequest")
 	proto.RegisterType((*MetadataRequest)(nil), "discovery.MetadataRequest")
 	proto.RegisterType((*AnnouncementsResponse)(nil), "discovery.AnnouncementsResponse")
+	proto.RegisterType((*AnnouncementChange)(nil), "discovery.AnnouncementChange")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -439,6 +468,9 @@ type DiscoveryClient interface {
 	AddMetadata(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
 	// Delete metadata from an announcement
 	DeleteMetadata(ctx context.Context, in *MetadataRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// Watch the changes of the announcements and metadata of a service type.
+	// Changes are only sent when something other than the timestamps changed.
+	Watch(ctx context.Context, in *GetServiceRequest, opts ...grpc.CallOption) (Discovery_WatchClient, error)
 }
 
 type discoveryClient struct {
@@ -494,6 +526,38 @@ func (c *discoveryClient) DeleteMetadata(ctx context.Context, in *MetadataReques
 	return out, nil
 }
 
+func (c *discoveryClient) Watch(ctx context.Context, in *GetServiceRequest, opts ...grpc.CallOption) (Discovery_WatchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Discovery_serviceDesc.Streams[0], c.cc, "/discovery.Discovery/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &discoveryWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Discovery_WatchClient interface {
+	Recv() (*AnnouncementChange, error)
+	grpc.ClientStream
+}
+
+type discoveryWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *discoveryWatchClient) Recv() (*AnnouncementChange, error) {
+	m := new(AnnouncementChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Discovery service
 
 type DiscoveryServer interface {
@@ -509,6 +573,9 @@ type DiscoveryServer interface {
 	AddMetadata(context.Context, *MetadataRequest) (*google_protobuf.Empty, error)
 	// Delete metadata from an announcement
 	DeleteMetadata(context.Context, *MetadataRequest) (*google_protobuf.Empty, error)
+	// Watch the changes of the announcements and metadata of a service type.
+	// Changes are only sent when something other than the timestamps changed.
+	Watch(*GetServiceRequest, Discovery_WatchServer) error
 }
 
 func RegisterDiscoveryServer(s *grpc.Server, srv DiscoveryServer) {
@@ -605,6 +672,27 @@ func _Discovery_DeleteMetadata_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Discovery_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetServiceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DiscoveryServer).Watch(m, &discoveryWatchServer{stream})
+}
+
+type Discovery_WatchServer interface {
+	Send(*AnnouncementChange) error
+	grpc.ServerStream
+}
+
+type discoveryWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *discoveryWatchServer) Send(m *AnnouncementChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Discovery_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "discovery.Discovery",
 	HandlerType: (*DiscoveryServer)(nil),
@@ -630,7 +718,13 @@ var _Discovery_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Discovery_DeleteMetadata_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Discovery_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "github.com/TheThingsNetwork/ttn/api/discovery/discovery.proto",
 }
 
@@ -957,6 +1051,36 @@ func (m *AnnouncementsResponse) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *AnnouncementChange) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AnnouncementChange) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDiscovery(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.ServiceName) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDiscovery(dAtA, i, uint64(len(m.ServiceName)))
+		i += copy(dAtA[i:], m.ServiceName)
+	}
+	return i, nil
+}
+
 func encodeFixed64Discovery(dAtA []byte, offset int, v uint64) int {
 	dAtA[offset] = uint8(v)
 	dAtA[offset+1] = uint8(v >> 8)
@@ -1131,6 +1255,20 @@ func (m *AnnouncementsResponse) Size() (n int) {
 	return n
 }
 
+func (m *AnnouncementChange) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovDiscovery(uint64(l))
+	}
+	l = len(m.ServiceName)
+	if l > 0 {
+		n += 1 + l + sovDiscovery(uint64(l))
+	}
+	return n
+}
+
 func sovDiscovery(x uint64) (n int) {
 	for {
 		n++
@@ -2112,6 +2250,114 @@ func (m *AnnouncementsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *AnnouncementChange) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDiscovery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AnnouncementChange: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AnnouncementChange: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDiscovery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDiscovery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDiscovery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDiscovery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDiscovery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDiscovery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipDiscovery(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -2222,48 +2468,50 @@ func init() {
 }
 
 var fileDescriptorDiscovery = []byte{
-	// 681 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x54, 0x5d, 0x4f, 0x13, 0x4b,
-	0x18, 0x66, 0xdb, 0x43, 0x4f, 0xfb, 0xb6, 0xb4, 0x9c, 0x39, 0x07, 0xd8, 0x53, 0xa1, 0x94, 0x8d,
-	0xc6, 0xc6, 0xc4, 0x6e, 0x02, 0x89, 0x37, 0xc4, 0x98, 0x12, 0x48, 0x31, 0x0a, 0x31, 0x2b, 0xf1,
-	0xc2, 0x9b, 0x66, 0xba, 0xf3, 0x52, 0x26, 0x74, 0x67, 0x87, 0xdd, 0xd9, 0x2a, 0x21, 0xdc, 0xf8,
-	0x17, 0xfc, 0x0d, 0xde, 0xfb, 0x33, 0xbc, 0x34, 0xf1, 0x0f, 0x18, 0xe2, 0x0f, 0x31, 0xfb, 0xd9,
-	0x25, 0x58, 0x0d, 0x7a, 0xb7, 0xfb, 0xbc, 0xcf, 0xfb, 0x3c, 0x33, 0xef, 0x33, 0x33, 0xf0, 0x78,
-	0xc4, 0xd5, 0x49, 0x30, 0xec, 0xda, 0xae, 0x63, 0x1e, 0x9d, 0xe0, 0xd1, 0x09, 0x17, 0x23, 0xff,
-	0x10, 0xd5, 0x1b, 0xd7, 0x3b, 0x35, 0x95, 0x12, 0x26, 0x95, 0xdc, 0x64, 0xdc, 0xb7, 0xdd, 0x09,
-	0x7a, 0xe7, 0xd3, 0xaf, 0xae, 0xf4, 0x5c, 0xe5, 0x92, 0x4a, 0x06, 0x34, 0xef, 0x8c, 0x5c, 0x77,
-	0x34, 0x46, 0x33, 0x2a, 0x0c, 0x83, 0x63, 0x13, 0x1d, 0xa9, 0x12, 0x5e, 0x73, 0x35, 0x29, 0x86,
-	0x6a, 0x54, 0x08, 0x57, 0x51, 0xc5, 0x5d, 0xe1, 0xc7, 0x55, 0x43, 0x41, 0xf9, 0x00, 0x15, 0x65,
-	0x54, 0x51, 0xd2, 0x81, 0x06, 0xc3, 0xc9, 0x80, 0x32, 0xe6, 0x0d, 0xa4, 0x87, 0xc7, 0xfc, 0xad,
-	0xfe, 0x5f, 0x5b, 0xeb, 0xd4, 0xf6, 0xe7, 0xac, 0x05, 0x86, 0x93, 0x1e, 0x63, 0xde, 0x8b, 0x08,
-	0x26, 0x2b, 0x50, 0xa2, 0x52, 0x0e, 0x38, 0xd3, 0x5b, 0x6d, 0xad, 0x53, 0xd9, 0x9f, 0xb3, 0xe6,
-	0xa9, 0x94, 0x4f, 0x19, 0xf9, 0x1f, 0xfe, 0x0e, 0x0b, 0x18, 0x70, 0x7d, 0x3d, 0x69, 0x0d, 0x99,
-	0x7b, 0x01, 0xdf, 0x01, 0x28, 0x3b, 0x89, 0x93, 0xf1, 0xb1, 0x08, 0xb5, 0x9e, 0x10, 0x6e, 0x20,
-	0x6c, 0x74, 0x50, 0x28, 0x52, 0x87, 0x02, 0x67, 0xba, 0x16, 0x8a, 0x59, 0x05, 0xce, 0xc8, 0x06,
-	0xd4, 0x7c, 0xf4, 0x26, 0xdc, 0xc6, 0x81, 0xa0, 0x0e, 0xea, 0x85, 0xa8, 0x52, 0x4d, 0xb0, 0x43,
-	0xea, 0x20, 0xb9, 0x0f, 0x8d, 0x94, 0x32, 0x41, 0xcf, 0xe7, 0xae, 0xd0, 0x8b, 0x11, 0xab, 0x9e,
-	0xc0, 0xaf, 0x62, 0x94, 0xb4, 0xa1, 0xca, 0xd0, 0xb7, 0x3d, 0x2e, 0xc3, 0x8d, 0xeb, 0x7f, 0xc5,
-	0x52, 0x39, 0x88, 0x2c, 0x42, 0x31, 0xf0, 0xc6, 0xfa, 0x7c, 0x54, 0x09, 0x3f, 0xc9, 0x32, 0x94,
-	0x64, 0x30, 0x1c, 0x73, 0x5b, 0x2f, 0xb5, 0xb5, 0x4e, 0xd9, 0x4a, 0xfe, 0xc8, 0x3a, 0x54, 0x05,
-	0xaa, 0x68, 0x44, 0xe8, 0xfb, 0x7a, 0x35, 0xea, 0x00, 0x81, 0xaa, 0x17, 0x23, 0x64, 0x0d, 0x20,
-	0xa6, 0x0e, 0x4e, 0xf1, 0x5c, 0xaf, 0x45, 0xf5, 0x4a, 0x8c, 0x3c, 0xc3, 0xf3, 0x70, 0x2d, 0x36,
-	0x7a, 0x8a, 0x1f, 0x73, 0x9b, 0x2a, 0xd4, 0x17, 0xe2, 0xb5, 0xe4, 0xa0, 0xd0, 0x81, 0x4a, 0x9e,
-	0x39, 0xd4, 0x63, 0x07, 0x2a, 0x79, 0xea, 0xb0, 0x01, 0x35, 0xe7, 0x4c, 0x4d, 0xd7, 0xd0, 0x88,
-	0x35, 0x42, 0x2c, 0x47, 0xa1, 0xce, 0x99, 0xcc, 0x28, 0x8b, 0x31, 0x25, 0xc4, 0x52, 0x8a, 0x39,
-	0x4d, 0x43, 0x5f, 0x6e, 0x17, 0x3b, 0xd5, 0xcd, 0x7f, 0xbb, 0xd3, 0x13, 0x96, 0x1e, 0x09, 0x6b,
-	0x1a, 0xd9, 0x23, 0xf8, 0xa7, 0x8f, 0xea, 0x65, 0x3c, 0x5a, 0x0b, 0xcf, 0x02, 0xf4, 0xd5, 0x8d,
-	0x98, 0xb4, 0x1b, 0x31, 0x19, 0x4f, 0x00, 0xfa, 0xa8, 0xd2, 0x86, 0xdb, 0xe7, 0x6c, 0x04, 0xd0,
-	0xc8, 0x96, 0xf3, 0xdb, 0x2a, 0xd7, 0xf6, 0x1b, 0xa6, 0xf2, 0xcb, 0xfd, 0x3e, 0x87, 0xa5, 0xfc,
-	0x09, 0xf5, 0x2d, 0xf4, 0xa5, 0x2b, 0x7c, 0x24, 0x5b, 0x50, 0x4e, 0x84, 0x7d, 0x5d, 0x8b, 0x26,
-	0xb7, 0x92, 0x53, 0xca, 0xf7, 0x58, 0x19, 0x71, 0xf3, 0x43, 0x11, 0x2a, 0xbb, 0x29, 0x89, 0x6c,
-	0x43, 0x39, 0xe5, 0x91, 0x59, 0xcd, 0xcd, 0xe5, 0x6e, 0x7c, 0x71, 0xbb, 0xe9, 0xad, 0xee, 0xee,
-	0x85, 0xb7, 0x9a, 0xb8, 0x50, 0xea, 0xa3, 0xea, 0x8d, 0xc7, 0x64, 0x35, 0xd7, 0x7a, 0x23, 0x9b,
-	0x66, 0x7b, 0x86, 0x70, 0xb6, 0x13, 0xe3, 0xde, 0xbb, 0x2f, 0xdf, 0xde, 0x17, 0xd6, 0xc9, 0x5a,
-	0xf4, 0x2e, 0x64, 0x75, 0xf3, 0x22, 0x3f, 0xcc, 0x4b, 0x42, 0xa1, 0xd8, 0x47, 0x45, 0x96, 0xae,
-	0xbb, 0xa5, 0x36, 0xb3, 0xd6, 0x6f, 0x3c, 0x88, 0xd4, 0xef, 0x12, 0xe3, 0xa7, 0xea, 0xe6, 0x05,
-	0x67, 0x97, 0xa4, 0x07, 0xd5, 0x1e, 0x63, 0xd9, 0x43, 0xd4, 0xfc, 0x51, 0x34, 0x89, 0xdf, 0xac,
-	0xb1, 0xec, 0x42, 0x7d, 0x17, 0xc7, 0xa8, 0xf0, 0x4f, 0x54, 0x36, 0x09, 0x2c, 0x66, 0x31, 0x1d,
-	0x50, 0x41, 0x47, 0xe8, 0xed, 0x6c, 0x7f, 0xba, 0x6a, 0x69, 0x9f, 0xaf, 0x5a, 0xda, 0xd7, 0xab,
-	0x96, 0xf6, 0xfa, 0xe1, 0xad, 0x5e, 0xed, 0x61, 0x29, 0x32, 0xd8, 0xfa, 0x1e, 0x00, 0x00, 0xff,
-	0xff, 0xe7, 0xe5, 0xe6, 0xd9, 0xed, 0x05, 0x00, 0x00,
+	// 708 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x55, 0xdd, 0x4e, 0x13, 0x5b,
+	0x14, 0x66, 0xda, 0x43, 0x4f, 0xbb, 0x5a, 0x5a, 0xce, 0x3e, 0x07, 0x98, 0x53, 0xa1, 0x94, 0x89,
+	0xc6, 0xc6, 0xc4, 0x8e, 0x81, 0xc4, 0x1b, 0x62, 0x4c, 0x11, 0x2c, 0x46, 0x21, 0x66, 0x24, 0x9a,
+	0x78, 0xd3, 0xec, 0xce, 0x5e, 0xb4, 0x3b, 0x74, 0xf6, 0x0c, 0x33, 0x7b, 0xaa, 0x84, 0x70, 0xe3,
+	0x2b, 0xf8, 0x22, 0x3e, 0x86, 0x97, 0x26, 0xbe, 0x80, 0x21, 0xde, 0xfa, 0x0e, 0x66, 0x7e, 0x3b,
+	0x04, 0x2b, 0x01, 0xef, 0xa6, 0xdf, 0xfa, 0xf6, 0xf7, 0xad, 0xb5, 0xbe, 0x9d, 0x5d, 0x78, 0x34,
+	0xe0, 0x72, 0xe8, 0xf7, 0xdb, 0xa6, 0x6d, 0xe9, 0x07, 0x43, 0x3c, 0x18, 0x72, 0x31, 0xf0, 0xf6,
+	0x51, 0xbe, 0xb3, 0xdd, 0x23, 0x5d, 0x4a, 0xa1, 0x53, 0x87, 0xeb, 0x8c, 0x7b, 0xa6, 0x3d, 0x46,
+	0xf7, 0x64, 0xf2, 0xd5, 0x76, 0x5c, 0x5b, 0xda, 0xa4, 0x94, 0x02, 0xf5, 0x5b, 0x03, 0xdb, 0x1e,
+	0x8c, 0x50, 0x0f, 0x0b, 0x7d, 0xff, 0x50, 0x47, 0xcb, 0x91, 0x31, 0xaf, 0xbe, 0x1c, 0x17, 0x03,
+	0x35, 0x2a, 0x84, 0x2d, 0xa9, 0xe4, 0xb6, 0xf0, 0xa2, 0xaa, 0x26, 0xa1, 0xb8, 0x87, 0x92, 0x32,
+	0x2a, 0x29, 0x69, 0x41, 0x8d, 0xe1, 0xb8, 0x47, 0x19, 0x73, 0x7b, 0x8e, 0x8b, 0x87, 0xfc, 0xbd,
+	0xfa, 0x5f, 0x53, 0x69, 0x55, 0x76, 0x67, 0x8c, 0x39, 0x86, 0xe3, 0x0e, 0x63, 0xee, 0xcb, 0x10,
+	0x26, 0x4b, 0x50, 0xa0, 0x8e, 0xd3, 0xe3, 0x4c, 0x6d, 0x34, 0x95, 0x56, 0x69, 0x77, 0xc6, 0x98,
+	0xa5, 0x8e, 0xf3, 0x8c, 0x91, 0xff, 0xe1, 0xef, 0xa0, 0x80, 0x3e, 0x57, 0x57, 0xe3, 0xa3, 0x01,
+	0x73, 0xc7, 0xe7, 0x5b, 0x00, 0x45, 0x2b, 0x76, 0xd2, 0x3e, 0xe5, 0xa1, 0xd2, 0x11, 0xc2, 0xf6,
+	0x85, 0x89, 0x16, 0x0a, 0x49, 0xaa, 0x90, 0xe3, 0x4c, 0x55, 0x02, 0x31, 0x23, 0xc7, 0x19, 0x59,
+	0x83, 0x8a, 0x87, 0xee, 0x98, 0x9b, 0xd8, 0x13, 0xd4, 0x42, 0x35, 0x17, 0x56, 0xca, 0x31, 0xb6,
+	0x4f, 0x2d, 0x24, 0x77, 0xa1, 0x96, 0x50, 0xc6, 0xe8, 0x7a, 0xdc, 0x16, 0x6a, 0x3e, 0x64, 0x55,
+	0x63, 0xf8, 0x75, 0x84, 0x92, 0x26, 0x94, 0x19, 0x7a, 0xa6, 0xcb, 0x9d, 0x60, 0x70, 0xf5, 0xaf,
+	0x48, 0x2a, 0x03, 0x91, 0x79, 0xc8, 0xfb, 0xee, 0x48, 0x9d, 0x0d, 0x2b, 0xc1, 0x27, 0x59, 0x84,
+	0x82, 0xe3, 0xf7, 0x47, 0xdc, 0x54, 0x0b, 0x4d, 0xa5, 0x55, 0x34, 0xe2, 0x5f, 0x64, 0x15, 0xca,
+	0x02, 0x65, 0xb8, 0x22, 0xf4, 0x3c, 0xb5, 0x1c, 0x9e, 0x00, 0x81, 0xb2, 0x13, 0x21, 0x64, 0x05,
+	0x20, 0xa2, 0xf6, 0x8e, 0xf0, 0x44, 0xad, 0x84, 0xf5, 0x52, 0x84, 0x3c, 0xc7, 0x93, 0xa0, 0x17,
+	0x13, 0x5d, 0xc9, 0x0f, 0xb9, 0x49, 0x25, 0xaa, 0x73, 0x51, 0x2f, 0x19, 0x28, 0x70, 0xa0, 0x0e,
+	0x4f, 0x1d, 0xaa, 0x91, 0x03, 0x75, 0x78, 0xe2, 0xb0, 0x06, 0x15, 0xeb, 0x58, 0x4e, 0x7a, 0xa8,
+	0x45, 0x1a, 0x01, 0x96, 0xa1, 0x50, 0xeb, 0xd8, 0x49, 0x29, 0xf3, 0x11, 0x25, 0xc0, 0x12, 0x8a,
+	0x3e, 0x49, 0x43, 0x5d, 0x6c, 0xe6, 0x5b, 0xe5, 0xf5, 0x7f, 0xdb, 0x93, 0x1b, 0x96, 0x5c, 0x09,
+	0x63, 0x12, 0xd9, 0x43, 0xf8, 0xa7, 0x8b, 0xf2, 0x55, 0xb4, 0x5a, 0x03, 0x8f, 0x7d, 0xf4, 0xe4,
+	0xa5, 0x98, 0x94, 0x4b, 0x31, 0x69, 0x8f, 0x01, 0xba, 0x28, 0x93, 0x03, 0xd7, 0xcf, 0x59, 0xf3,
+	0xa1, 0x96, 0xb6, 0x73, 0x63, 0x95, 0x0b, 0xf3, 0x06, 0xa9, 0x5c, 0x39, 0xef, 0x0b, 0x58, 0xc8,
+	0xde, 0x50, 0xcf, 0x40, 0xcf, 0xb1, 0x85, 0x87, 0x64, 0x03, 0x8a, 0xb1, 0xb0, 0xa7, 0x2a, 0xe1,
+	0xe6, 0x96, 0x32, 0x4a, 0xd9, 0x33, 0x46, 0x4a, 0xd4, 0xba, 0x40, 0xb2, 0x95, 0x27, 0x43, 0x2a,
+	0x06, 0x78, 0x83, 0x39, 0xd6, 0x7f, 0xe4, 0xa1, 0xb4, 0x9d, 0xb8, 0x91, 0x4d, 0x28, 0x26, 0xb2,
+	0x64, 0x5a, 0x17, 0xf5, 0xc5, 0x76, 0xf4, 0x02, 0xb4, 0x93, 0xe7, 0xa1, 0xbd, 0x13, 0x3c, 0x0f,
+	0xc4, 0x86, 0x42, 0x17, 0x65, 0x67, 0x34, 0x22, 0xcb, 0x99, 0xa3, 0x97, 0x42, 0xae, 0x37, 0xa7,
+	0x08, 0xa7, 0x2b, 0xd1, 0xee, 0x7c, 0xf8, 0xfa, 0xfd, 0x63, 0x6e, 0x95, 0xac, 0xe8, 0x34, 0x5b,
+	0xd7, 0x4f, 0xb3, 0xd3, 0x9c, 0x11, 0x0a, 0xf9, 0x2e, 0x4a, 0xb2, 0x70, 0xd1, 0x2d, 0xb1, 0x99,
+	0xd6, 0xbf, 0x76, 0x2f, 0x54, 0xbf, 0x4d, 0xb4, 0xdf, 0xaa, 0xeb, 0xa7, 0x9c, 0x9d, 0x91, 0x0e,
+	0x94, 0x3b, 0x8c, 0xa5, 0x2f, 0x5a, 0xfd, 0x57, 0x19, 0xc7, 0x7e, 0xd3, 0xd6, 0xb2, 0x0d, 0xd5,
+	0x6d, 0x1c, 0xa1, 0xc4, 0x3f, 0x52, 0x79, 0x0a, 0xb3, 0x6f, 0xa8, 0x34, 0x87, 0x57, 0xec, 0x76,
+	0x65, 0xca, 0xd0, 0xd1, 0x05, 0x79, 0xa0, 0xac, 0x13, 0x98, 0x4f, 0xe3, 0xde, 0xa3, 0x82, 0x0e,
+	0xd0, 0xdd, 0xda, 0xfc, 0x7c, 0xde, 0x50, 0xbe, 0x9c, 0x37, 0x94, 0x6f, 0xe7, 0x0d, 0xe5, 0xed,
+	0xfd, 0x6b, 0xfd, 0x8d, 0xf4, 0x0b, 0x61, 0xa3, 0x1b, 0x3f, 0x07, 0x00, 0xd5, 0x73, 0x4a, 0xac,
+	0x7e, 0x06, 0x00, 0x00,
 }
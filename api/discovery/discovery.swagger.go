@@ -0,0 +1,178 @@
+// Code generated from discovery.swagger.json by make swagger
+// DO NOT EDIT!
+
+package discovery
+
+// SwaggerJSON is the OpenAPI specification of the REST API of this package
+const SwaggerJSON = `
+{
+  "swagger": "2.0",
+  "info": {
+    "title": "github.com/TheThingsNetwork/ttn/api/discovery/discovery.proto",
+    "version": "version not set"
+  },
+  "schemes": [
+    "http",
+    "https"
+  ],
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "paths": {
+    "/announcements/{service_name}": {
+      "get": {
+        "summary": "Get all announcements for a specific service type",
+        "operationId": "GetAll",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/discoveryAnnouncementsResponse"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "service_name",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "Discovery"
+        ]
+      }
+    },
+    "/announcements/{service_name}/{id}": {
+      "get": {
+        "summary": "Get a specific announcement",
+        "operationId": "Get",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/discoveryAnnouncement"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "service_name",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "Discovery"
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "discoveryAnnouncement": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "The ID of the component"
+        },
+        "service_name": {
+          "type": "string",
+          "description": "The name of the component (router/broker/handler)"
+        },
+        "service_version": {
+          "type": "string",
+          "description": "Service version in the form \"[version]-[commit] ([build date])\""
+        },
+        "description": {
+          "type": "string",
+          "description": "Description of the component"
+        },
+        "url": {
+          "type": "string",
+          "description": "URL with documentation or more information about this component"
+        },
+        "public": {
+          "type": "boolean",
+          "format": "boolean",
+          "description": "Indicates whether this service is part of The Things Network (the public community network)"
+        },
+        "net_address": {
+          "type": "string",
+          "description": "Comma-separated network addresses in the form \"[hostname]:[port]\" (currently we only use the first)"
+        },
+        "public_key": {
+          "type": "string",
+          "description": "ECDSA public key of this component"
+        },
+        "certificate": {
+          "type": "string",
+          "description": "TLS Certificate (if TLS is enabled)"
+        },
+        "api_address": {
+          "type": "string",
+          "description": "Contains the address where the HTTP API is exposed (if there is one)"
+        },
+        "mqtt_address": {
+          "type": "string",
+          "description": "Contains the address where the MQTT API is exposed (if there is one)"
+        },
+        "amqp_address": {
+          "type": "string",
+          "description": "Contains the address where the AMQP API is exposed (if there is one)"
+        },
+        "metadata": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/discoveryMetadata"
+          },
+          "description": "Metadata for this component"
+        }
+      },
+      "description": "The Announcement of a service (also called component)"
+    },
+    "discoveryAnnouncementsResponse": {
+      "type": "object",
+      "properties": {
+        "services": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/discoveryAnnouncement"
+          }
+        }
+      },
+      "description": "A list of announcements"
+    },
+    "discoveryMetadata": {
+      "type": "object",
+      "properties": {
+        "dev_addr_prefix": {
+          "type": "string",
+          "format": "byte",
+          "description": "DevAddr prefix that is routed by this Broker\n5 bytes; the first byte is the prefix length, the following 4 bytes are the address.\nOnly authorized Brokers can announce PREFIX metadata."
+        },
+        "app_id": {
+          "type": "string",
+          "description": "AppID that is registered to this Handler\nThis metadata can only be added if the requesting client is authorized to manage this AppID."
+        },
+        "app_eui": {
+          "type": "string",
+          "format": "byte",
+          "description": "AppEUI that is registered to this Join Handler\nOnly authorized Join Handlers can announce APP_EUI metadata (and we don't have any of those yet)."
+        }
+      }
+    }
+  }
+}
+`
@@ -85,6 +85,8 @@ type RxMetadata struct {
 	Time    int64  `protobuf:"varint,12,opt,name=time,proto3" json:"time,omitempty"`
 	RfChain uint32 `protobuf:"varint,21,opt,name=rf_chain,json=rfChain,proto3" json:"rf_chain,omitempty"`
 	Channel uint32 `protobuf:"varint,22,opt,name=channel,proto3" json:"channel,omitempty"`
+	// Index of the antenna that received the message
+	Antenna uint32 `protobuf:"varint,23,opt,name=antenna,proto3" json:"antenna,omitempty"`
 	// Frequency in Hz
 	Frequency uint64 `protobuf:"varint,31,opt,name=frequency,proto3" json:"frequency,omitempty"`
 	// Received signal strength in dBm
@@ -141,6 +143,13 @@ func (m *RxMetadata) GetChannel() uint32 {
 	return 0
 }
 
+func (m *RxMetadata) GetAntenna() uint32 {
+	if m != nil {
+		return m.Antenna
+	}
+	return 0
+}
+
 func (m *RxMetadata) GetFrequency() uint64 {
 	if m != nil {
 		return m.Frequency
@@ -177,6 +186,8 @@ type TxConfiguration struct {
 	Frequency uint64 `protobuf:"varint,22,opt,name=frequency,proto3" json:"frequency,omitempty"`
 	// Transmit power in dBm
 	Power int32 `protobuf:"varint,23,opt,name=power,proto3" json:"power,omitempty"`
+	// Index of the antenna to transmit on
+	Antenna uint32 `protobuf:"varint,24,opt,name=antenna,proto3" json:"antenna,omitempty"`
 	// LoRa polarization inversion (basically always true for messages from gateway to node)
 	PolarizationInversion bool `protobuf:"varint,31,opt,name=polarization_inversion,json=polarizationInversion,proto3" json:"polarization_inversion,omitempty"`
 	// FSK frequency deviation in Hz
@@ -216,6 +227,13 @@ func (m *TxConfiguration) GetPower() int32 {
 	return 0
 }
 
+func (m *TxConfiguration) GetAntenna() uint32 {
+	if m != nil {
+		return m.Antenna
+	}
+	return 0
+}
+
 func (m *TxConfiguration) GetPolarizationInversion() bool {
 	if m != nil {
 		return m.PolarizationInversion
@@ -543,6 +561,13 @@ func (m *RxMetadata) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintGateway(dAtA, i, uint64(m.Channel))
 	}
+	if m.Antenna != 0 {
+		dAtA[i] = 0xb8
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintGateway(dAtA, i, uint64(m.Antenna))
+	}
 	if m.Frequency != 0 {
 		dAtA[i] = 0xf8
 		i++
@@ -620,6 +645,13 @@ func (m *TxConfiguration) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintGateway(dAtA, i, uint64(m.Power))
 	}
+	if m.Antenna != 0 {
+		dAtA[i] = 0xc0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintGateway(dAtA, i, uint64(m.Antenna))
+	}
 	if m.PolarizationInversion {
 		dAtA[i] = 0xf8
 		i++
@@ -913,6 +945,9 @@ func (m *RxMetadata) Size() (n int) {
 	if m.Channel != 0 {
 		n += 2 + sovGateway(uint64(m.Channel))
 	}
+	if m.Antenna != 0 {
+		n += 2 + sovGateway(uint64(m.Antenna))
+	}
 	if m.Frequency != 0 {
 		n += 2 + sovGateway(uint64(m.Frequency))
 	}
@@ -944,6 +979,9 @@ func (m *TxConfiguration) Size() (n int) {
 	if m.Power != 0 {
 		n += 2 + sovGateway(uint64(m.Power))
 	}
+	if m.Antenna != 0 {
+		n += 2 + sovGateway(uint64(m.Antenna))
+	}
 	if m.PolarizationInversion {
 		n += 3
 	}
@@ -1328,6 +1366,25 @@ func (m *RxMetadata) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 23:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Antenna", wireType)
+			}
+			m.Antenna = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Antenna |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		case 31:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Frequency", wireType)
@@ -1534,6 +1591,25 @@ func (m *TxConfiguration) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 24:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Antenna", wireType)
+			}
+			m.Antenna = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGateway
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Antenna |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		case 31:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field PolarizationInversion", wireType)
@@ -2310,52 +2386,53 @@ func init() {
 }
 
 var fileDescriptorGateway = []byte{
-	// 748 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0x4f, 0x93, 0xdb, 0x34,
-	0x14, 0x1f, 0x3b, 0xff, 0x95, 0x26, 0xbb, 0x55, 0x9b, 0x54, 0xdd, 0x81, 0xc5, 0x84, 0x01, 0x52,
-	0x16, 0x92, 0x59, 0x3a, 0x39, 0xf4, 0x4a, 0x61, 0x98, 0x3d, 0xc0, 0x76, 0xd4, 0x9c, 0xb8, 0x78,
-	0x14, 0x5b, 0x71, 0x34, 0xb1, 0x25, 0x23, 0xcb, 0x4d, 0x96, 0x8f, 0xc3, 0x85, 0xaf, 0xd2, 0x23,
-	0x1f, 0x81, 0xd9, 0x03, 0x9f, 0x83, 0xd1, 0xf3, 0x9f, 0x75, 0x99, 0xc2, 0x4e, 0x4f, 0x79, 0xbf,
-	0x3f, 0xf2, 0x7b, 0x4f, 0xef, 0x45, 0xe8, 0x45, 0x24, 0xcc, 0x2e, 0xdf, 0x2c, 0x02, 0x95, 0x2c,
-	0xd7, 0x3b, 0xbe, 0xde, 0x09, 0x19, 0x65, 0x3f, 0x73, 0x73, 0x50, 0x7a, 0xbf, 0x34, 0x46, 0x2e,
-	0x59, 0x2a, 0x96, 0x11, 0x33, 0xfc, 0xc0, 0x6e, 0xaa, 0xdf, 0x45, 0xaa, 0x95, 0x51, 0xb8, 0x57,
-	0xc2, 0xb3, 0x6f, 0x1a, 0xdf, 0x88, 0x54, 0xa4, 0x96, 0xa0, 0x6f, 0xf2, 0x2d, 0x20, 0x00, 0x10,
-	0x15, 0xe7, 0x66, 0x07, 0x34, 0xfc, 0xf1, 0xd5, 0xeb, 0x9f, 0xb8, 0x61, 0x21, 0x33, 0x0c, 0x63,
-	0xd4, 0x36, 0x22, 0xe1, 0xc4, 0xf1, 0x9c, 0x79, 0x8b, 0x42, 0x8c, 0xcf, 0x50, 0x3f, 0x66, 0x46,
-	0x98, 0x3c, 0xe4, 0xc4, 0xf5, 0x9c, 0xb9, 0x4b, 0x6b, 0x8c, 0x3f, 0x42, 0x83, 0x58, 0xc9, 0xa8,
-	0x10, 0x5b, 0x20, 0xde, 0x11, 0xf6, 0x24, 0x8b, 0xcb, 0x93, 0x6d, 0xcf, 0x99, 0x77, 0x68, 0x8d,
-	0x67, 0x7f, 0xb8, 0x08, 0xd1, 0x63, 0x9d, 0xf8, 0x63, 0x84, 0xca, 0x0e, 0x7c, 0x11, 0x42, 0xfa,
-	0x01, 0x1d, 0x94, 0xcc, 0x55, 0x88, 0xbf, 0x44, 0x27, 0x95, 0x6c, 0x74, 0x9e, 0x19, 0x1e, 0x42,
-	0x29, 0x7d, 0x3a, 0x2e, 0xe9, 0x75, 0xc1, 0xda, 0x82, 0x6c, 0xd1, 0x99, 0x61, 0x49, 0x4a, 0x86,
-	0x9e, 0x33, 0x1f, 0xd1, 0x3b, 0xa2, 0x6e, 0xef, 0x41, 0xa3, 0xbd, 0xa7, 0xa8, 0xaf, 0xb7, 0x7e,
-	0xb0, 0x63, 0x42, 0x92, 0x09, 0x1c, 0xe8, 0xe9, 0xed, 0x4b, 0x0b, 0x31, 0x41, 0xbd, 0x60, 0xc7,
-	0xa4, 0xe4, 0x31, 0x99, 0x16, 0x4a, 0x09, 0x6d, 0x9a, 0xad, 0xe6, 0xbf, 0xe6, 0x5c, 0x06, 0x37,
-	0xe4, 0x13, 0xcf, 0x99, 0xb7, 0xe9, 0x1d, 0x61, 0xd3, 0xe8, 0x2c, 0x13, 0xc4, 0x83, 0x0b, 0x81,
-	0x18, 0x9f, 0xa2, 0x56, 0x26, 0x35, 0xf9, 0x14, 0x28, 0x1b, 0xe2, 0x2f, 0x50, 0x2b, 0x4a, 0x33,
-	0xf2, 0xcc, 0x73, 0xe6, 0xc3, 0x6f, 0x1f, 0x2f, 0xaa, 0x79, 0x36, 0xc6, 0x41, 0xad, 0x61, 0xf6,
-	0xb7, 0x83, 0x4e, 0xd6, 0xc7, 0x97, 0x4a, 0x6e, 0x45, 0x94, 0x6b, 0x66, 0x84, 0x92, 0xf7, 0xb4,
-	0xf9, 0x3f, 0x2d, 0xbd, 0x53, 0xf8, 0xf4, 0xdf, 0x85, 0x3f, 0x46, 0x9d, 0x54, 0x1d, 0xb8, 0x26,
-	0x4f, 0x60, 0x5a, 0x05, 0xc0, 0x2b, 0x34, 0x4d, 0x55, 0xcc, 0xb4, 0xf8, 0x0d, 0x92, 0xfb, 0x42,
-	0xbe, 0xe1, 0x3a, 0x13, 0x4a, 0x42, 0xe7, 0x7d, 0x3a, 0x69, 0xaa, 0x57, 0x95, 0x88, 0x97, 0xe8,
-	0x51, 0xfd, 0x65, 0x3f, 0xe4, 0x6f, 0x04, 0xe8, 0x70, 0x29, 0x23, 0x8a, 0x6b, 0xe9, 0xfb, 0x4a,
-	0x99, 0xfd, 0xde, 0x41, 0xdd, 0xd7, 0x86, 0x99, 0x3c, 0x7b, 0xb7, 0x3f, 0xe7, 0xbf, 0xc6, 0xe8,
-	0x36, 0xc6, 0xf8, 0x9e, 0x0d, 0x69, 0xbd, 0x77, 0x43, 0xc6, 0xc8, 0x15, 0xf6, 0xce, 0x5a, 0xf3,
-	0x01, 0x75, 0x45, 0x6a, 0x97, 0x34, 0x8d, 0x99, 0xd9, 0x2a, 0x9d, 0xc0, 0x5e, 0x0c, 0x68, 0x8d,
-	0xf1, 0x67, 0x68, 0x14, 0x28, 0x69, 0x58, 0x60, 0x7c, 0x9e, 0x30, 0x11, 0x93, 0x11, 0x18, 0x1e,
-	0x94, 0xe4, 0x0f, 0x96, 0xc3, 0x1e, 0x1a, 0x86, 0x3c, 0x0b, 0xb4, 0x48, 0xa1, 0xbf, 0x31, 0x58,
-	0x9a, 0x14, 0x9e, 0xa2, 0xae, 0xe6, 0x91, 0x15, 0x4f, 0x40, 0x2c, 0x91, 0xe5, 0x37, 0x5a, 0x84,
-	0x11, 0x27, 0xa7, 0x05, 0x5f, 0x20, 0xf0, 0xab, 0xdc, 0x70, 0x4d, 0x1e, 0x96, 0x7e, 0x40, 0xd5,
-	0xc6, 0x4c, 0xee, 0xd9, 0x18, 0xbb, 0x6b, 0xda, 0x18, 0x98, 0xce, 0x88, 0xda, 0x10, 0x3f, 0x42,
-	0x1d, 0x7d, 0xf4, 0x85, 0x84, 0x6d, 0x1b, 0xd1, 0xb6, 0x3e, 0x5e, 0xc9, 0x92, 0x54, 0x7b, 0xf2,
-	0x55, 0x45, 0x5e, 0xef, 0x2d, 0x69, 0xc0, 0x79, 0x51, 0x90, 0xa6, 0x74, 0x1a, 0x70, 0x7e, 0x5d,
-	0x91, 0xd7, 0x7b, 0xfc, 0x0c, 0xb9, 0x2a, 0x23, 0xcf, 0xa1, 0x98, 0xa7, 0x75, 0x31, 0xc5, 0x00,
-	0x17, 0xd7, 0xb6, 0x24, 0x2d, 0x82, 0x8c, 0xba, 0x2a, 0x3b, 0x7b, 0xeb, 0xa0, 0x41, 0xcd, 0xe0,
-	0x09, 0xea, 0xc6, 0x8a, 0x85, 0xfe, 0x25, 0x4c, 0xd6, 0xa5, 0x1d, 0x8b, 0x2e, 0x6b, 0x7a, 0x55,
-	0xbe, 0x32, 0x40, 0xaf, 0xf0, 0x13, 0xd4, 0x2b, 0xdc, 0xab, 0xf2, 0x81, 0x01, 0xd7, 0xe5, 0x0a,
-	0x7f, 0x8e, 0xc6, 0x41, 0x9a, 0xfb, 0x29, 0xd7, 0x01, 0x97, 0x86, 0x45, 0x1c, 0xfe, 0x08, 0x2e,
-	0x1d, 0x05, 0x69, 0xfe, 0xaa, 0x26, 0xf1, 0x05, 0x7a, 0x98, 0xf0, 0x44, 0xe9, 0x9b, 0xa6, 0x73,
-	0x02, 0xce, 0xd3, 0x42, 0x68, 0x98, 0x3d, 0x34, 0x34, 0x3c, 0x49, 0xb9, 0x66, 0x26, 0xd7, 0x1c,
-	0x6e, 0xd0, 0xa5, 0x4d, 0xea, 0xbb, 0x17, 0x6f, 0x6f, 0xcf, 0x9d, 0x3f, 0x6f, 0xcf, 0x9d, 0xbf,
-	0x6e, 0xcf, 0x9d, 0x5f, 0x2e, 0x3e, 0xe0, 0xc5, 0xde, 0x74, 0xe1, 0xc9, 0x7d, 0xfe, 0x4f, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0xc3, 0x51, 0x8b, 0xc4, 0xe7, 0x05, 0x00, 0x00,
+	// 759 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x92, 0xdb, 0x34,
+	0x1c, 0x1f, 0x3b, 0x9b, 0x2f, 0xa5, 0xc9, 0x6e, 0xd5, 0x26, 0x55, 0x77, 0x60, 0x31, 0x61, 0x80,
+	0x94, 0x85, 0x64, 0x96, 0x4e, 0x0e, 0xbd, 0x52, 0x18, 0x66, 0x0f, 0xb0, 0x1d, 0x35, 0x27, 0x2e,
+	0x1e, 0xc5, 0x56, 0x1c, 0x4d, 0x6c, 0xc9, 0xc8, 0x72, 0x93, 0xe5, 0xc4, 0xb3, 0xf0, 0x26, 0xdc,
+	0x7a, 0xe4, 0x11, 0x98, 0x7d, 0x12, 0x46, 0x7f, 0x7f, 0xc4, 0xcb, 0x14, 0x3a, 0x3d, 0xad, 0x7e,
+	0x1f, 0x5a, 0xff, 0xfe, 0x1f, 0x11, 0x7a, 0x11, 0x09, 0xb3, 0xcd, 0xd7, 0xf3, 0x40, 0x25, 0x8b,
+	0xd5, 0x96, 0xaf, 0xb6, 0x42, 0x46, 0xd9, 0xcf, 0xdc, 0xec, 0x95, 0xde, 0x2d, 0x8c, 0x91, 0x0b,
+	0x96, 0x8a, 0x45, 0xc4, 0x0c, 0xdf, 0xb3, 0xdb, 0xea, 0xef, 0x3c, 0xd5, 0xca, 0x28, 0xdc, 0x2d,
+	0xe1, 0xf9, 0x37, 0x8d, 0xff, 0x11, 0xa9, 0x48, 0x2d, 0x40, 0x5f, 0xe7, 0x1b, 0x40, 0x00, 0xe0,
+	0x54, 0xdc, 0x9b, 0xee, 0xd1, 0xe0, 0xc7, 0x57, 0xaf, 0x7f, 0xe2, 0x86, 0x85, 0xcc, 0x30, 0x8c,
+	0xd1, 0x89, 0x11, 0x09, 0x27, 0x8e, 0xe7, 0xcc, 0x5a, 0x14, 0xce, 0xf8, 0x1c, 0xf5, 0x62, 0x66,
+	0x84, 0xc9, 0x43, 0x4e, 0x5c, 0xcf, 0x99, 0xb9, 0xb4, 0xc6, 0xf8, 0x23, 0xd4, 0x8f, 0x95, 0x8c,
+	0x0a, 0xb1, 0x05, 0xe2, 0x91, 0xb0, 0x37, 0x59, 0x5c, 0xde, 0x3c, 0xf1, 0x9c, 0x59, 0x9b, 0xd6,
+	0x78, 0xfa, 0xa7, 0x8b, 0x10, 0x3d, 0xd4, 0x1f, 0xfe, 0x18, 0xa1, 0xb2, 0x02, 0x5f, 0x84, 0xf0,
+	0xf9, 0x3e, 0xed, 0x97, 0xcc, 0x75, 0x88, 0xbf, 0x44, 0xa7, 0x95, 0x6c, 0x74, 0x9e, 0x19, 0x1e,
+	0x42, 0x94, 0x1e, 0x1d, 0x95, 0xf4, 0xaa, 0x60, 0x6d, 0x20, 0x1b, 0x3a, 0x33, 0x2c, 0x49, 0xc9,
+	0xc0, 0x73, 0x66, 0x43, 0x7a, 0x24, 0xea, 0xf2, 0x1e, 0x34, 0xca, 0x7b, 0x8a, 0x7a, 0x7a, 0xe3,
+	0x07, 0x5b, 0x26, 0x24, 0x19, 0xc3, 0x85, 0xae, 0xde, 0xbc, 0xb4, 0x10, 0x13, 0xd4, 0x0d, 0xb6,
+	0x4c, 0x4a, 0x1e, 0x93, 0x49, 0xa1, 0x94, 0xd0, 0x2a, 0x4c, 0x1a, 0x2e, 0x25, 0x23, 0x4f, 0x0a,
+	0xa5, 0x84, 0x36, 0xc0, 0x46, 0xf3, 0x5f, 0x73, 0x2e, 0x83, 0x5b, 0xf2, 0x89, 0xe7, 0xcc, 0x4e,
+	0xe8, 0x91, 0xb0, 0x01, 0x74, 0x96, 0x09, 0xe2, 0x41, 0xab, 0xe0, 0x8c, 0xcf, 0x50, 0x2b, 0x93,
+	0x9a, 0x7c, 0x0a, 0x94, 0x3d, 0xe2, 0x2f, 0x50, 0x2b, 0x4a, 0x33, 0xf2, 0xcc, 0x73, 0x66, 0x83,
+	0x6f, 0x1f, 0xcf, 0xab, 0x49, 0x37, 0x06, 0x45, 0xad, 0x61, 0xfa, 0xbb, 0x8b, 0x4e, 0x57, 0x87,
+	0x97, 0x4a, 0x6e, 0x44, 0x94, 0x6b, 0x66, 0x84, 0x92, 0xef, 0x69, 0xc0, 0xff, 0x14, 0x7b, 0x2f,
+	0xf8, 0xe4, 0xdf, 0xc1, 0x1f, 0xa3, 0x76, 0xaa, 0xf6, 0x5c, 0x43, 0xb9, 0x6d, 0x5a, 0x80, 0x66,
+	0x1b, 0xc8, 0xfd, 0x36, 0x2c, 0xd1, 0x24, 0x55, 0x31, 0xd3, 0xe2, 0x37, 0x88, 0xe5, 0x0b, 0xf9,
+	0x86, 0xeb, 0x4c, 0x28, 0x09, 0x3d, 0xe9, 0xd1, 0x71, 0x53, 0xbd, 0xae, 0x44, 0xbc, 0x40, 0x8f,
+	0xea, 0x6f, 0xfa, 0x21, 0x7f, 0x23, 0x40, 0x87, 0x76, 0x0d, 0x29, 0xae, 0xa5, 0xef, 0x2b, 0x65,
+	0xfa, 0x47, 0x1b, 0x75, 0x5e, 0x1b, 0x66, 0xf2, 0xec, 0x7e, 0xe5, 0xce, 0x7f, 0x8d, 0xde, 0x6d,
+	0x8c, 0xfe, 0x1d, 0x5b, 0xd5, 0x7a, 0xe7, 0x56, 0x8d, 0x90, 0x2b, 0x6c, 0x37, 0x5b, 0xb3, 0x3e,
+	0x75, 0x45, 0x6a, 0x17, 0x3b, 0x8d, 0x99, 0xd9, 0x28, 0x9d, 0xc0, 0x2e, 0xf5, 0x69, 0x8d, 0xf1,
+	0x67, 0x68, 0x18, 0x28, 0x69, 0x58, 0x60, 0x7c, 0x9e, 0x30, 0x11, 0x93, 0x21, 0x18, 0x1e, 0x94,
+	0xe4, 0x0f, 0x96, 0xc3, 0x1e, 0x1a, 0x84, 0x3c, 0x0b, 0xb4, 0x48, 0xa1, 0xbe, 0x11, 0x58, 0x9a,
+	0x14, 0x9e, 0xa0, 0x8e, 0xe6, 0x91, 0x15, 0x4f, 0x41, 0x2c, 0x91, 0xe5, 0xd7, 0x5a, 0x84, 0x11,
+	0x27, 0x67, 0x05, 0x5f, 0x20, 0xf0, 0xab, 0xdc, 0x70, 0x4d, 0x1e, 0x96, 0x7e, 0x40, 0xd5, 0x2e,
+	0x8d, 0xdf, 0xb3, 0x4b, 0x76, 0x0b, 0xb5, 0x31, 0x30, 0x9d, 0x21, 0xb5, 0x47, 0xfc, 0x08, 0xb5,
+	0xf5, 0xc1, 0x17, 0x12, 0xf6, 0x70, 0x48, 0x4f, 0xf4, 0xe1, 0x5a, 0x96, 0xa4, 0xda, 0x91, 0xaf,
+	0x2a, 0xf2, 0x66, 0x67, 0x49, 0x03, 0xce, 0xcb, 0x82, 0x34, 0xa5, 0xd3, 0x80, 0xf3, 0xeb, 0x8a,
+	0xbc, 0xd9, 0xe1, 0x67, 0xc8, 0x55, 0x19, 0x79, 0x0e, 0x61, 0x9e, 0xd6, 0x61, 0x8a, 0x01, 0xce,
+	0x6f, 0x6c, 0x24, 0x2d, 0x82, 0x8c, 0xba, 0x2a, 0x3b, 0x7f, 0xeb, 0xa0, 0x7e, 0xcd, 0xe0, 0x31,
+	0xea, 0xc4, 0x8a, 0x85, 0xfe, 0x15, 0x4c, 0xd6, 0xa5, 0x6d, 0x8b, 0xae, 0x6a, 0x7a, 0x49, 0xdc,
+	0x23, 0xbd, 0xc4, 0x4f, 0x50, 0xb7, 0x70, 0x2f, 0xcb, 0x47, 0x09, 0x5c, 0x57, 0x4b, 0xfc, 0x39,
+	0x1a, 0x05, 0x69, 0xee, 0xa7, 0x5c, 0x07, 0x5c, 0x1a, 0x16, 0x71, 0xf8, 0x89, 0xb8, 0x74, 0x18,
+	0xa4, 0xf9, 0xab, 0x9a, 0xc4, 0x97, 0xe8, 0x61, 0xc2, 0x13, 0xa5, 0x6f, 0x9b, 0xce, 0x31, 0x38,
+	0xcf, 0x0a, 0xa1, 0x61, 0xf6, 0xd0, 0xc0, 0xf0, 0x24, 0xe5, 0x9a, 0x99, 0x5c, 0x73, 0xe8, 0xa0,
+	0x4b, 0x9b, 0xd4, 0x77, 0x2f, 0xde, 0xde, 0x5d, 0x38, 0x7f, 0xdd, 0x5d, 0x38, 0x7f, 0xdf, 0x5d,
+	0x38, 0xbf, 0x5c, 0x7e, 0xc0, 0x2b, 0xbf, 0xee, 0xc0, 0x33, 0xfd, 0xfc, 0x9f, 0x01, 0x00, 0xe2,
+	0x66, 0x77, 0xaa, 0x1b, 0x06, 0x00, 0x00,
 }
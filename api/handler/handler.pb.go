@@ -23,6 +23,14 @@
 		LogEntry
 		DryUplinkResult
 		DryDownlinkResult
+		EventsRequest
+		StreamEvent
+		EventsResponse
+		AckEventsRequest
+		BulkDevicesRequest
+		BulkDevicesResponse
+		JoinCryptoRequest
+		JoinCryptoResponse
 */
 package handler
 
@@ -639,6 +647,391 @@ func (m *DryDownlinkResult) GetLogs() []*LogEntry {
 	return nil
 }
 
+// EventsRequest reads messages from the event stream of an application
+type EventsRequest struct {
+	AppId string `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	// The cursor of the last message that was received. Without a group, the
+	// messages after this cursor are returned.
+	After string `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	// The maximum number of messages that are returned (default 100)
+	Limit int64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The consumer group that reads the messages. The group returns the
+	// messages that it did not return before, and keeps them pending until
+	// they are acknowledged with AckEvents.
+	Group string `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`
+	// The consumer in the group (default "default")
+	Consumer string `protobuf:"bytes,5,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	// Return the pending messages of the consumer instead of new messages
+	Pending bool `protobuf:"varint,6,opt,name=pending,proto3" json:"pending,omitempty"`
+}
+
+func (m *EventsRequest) Reset()                    { *m = EventsRequest{} }
+func (m *EventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*EventsRequest) ProtoMessage()               {}
+func (*EventsRequest) Descriptor() ([]byte, []int) { return fileDescriptorHandler, []int{14} }
+
+func (m *EventsRequest) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *EventsRequest) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *EventsRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *EventsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *EventsRequest) GetConsumer() string {
+	if m != nil {
+		return m.Consumer
+	}
+	return ""
+}
+
+func (m *EventsRequest) GetPending() bool {
+	if m != nil {
+		return m.Pending
+	}
+	return false
+}
+
+// StreamEvent is a message in the event stream of an application
+type StreamEvent struct {
+	// The cursor of the message, to resume reading after it
+	Cursor string `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	DevId  string `protobuf:"bytes,2,opt,name=dev_id,json=devId,proto3" json:"dev_id,omitempty"`
+	// The type of the message (up, activations, down/sent, ...)
+	Event string `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	// JSON-encoded data of the message
+	Data string `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *StreamEvent) Reset()                    { *m = StreamEvent{} }
+func (m *StreamEvent) String() string            { return proto.CompactTextString(m) }
+func (*StreamEvent) ProtoMessage()               {}
+func (*StreamEvent) Descriptor() ([]byte, []int) { return fileDescriptorHandler, []int{15} }
+
+func (m *StreamEvent) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *StreamEvent) GetDevId() string {
+	if m != nil {
+		return m.DevId
+	}
+	return ""
+}
+
+func (m *StreamEvent) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
+}
+
+func (m *StreamEvent) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+type EventsResponse struct {
+	Events []*StreamEvent `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *EventsResponse) Reset()                    { *m = EventsResponse{} }
+func (m *EventsResponse) String() string            { return proto.CompactTextString(m) }
+func (*EventsResponse) ProtoMessage()               {}
+func (*EventsResponse) Descriptor() ([]byte, []int) { return fileDescriptorHandler, []int{16} }
+
+func (m *EventsResponse) GetEvents() []*StreamEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+// AckEventsRequest acknowledges messages that a consumer group read
+type AckEventsRequest struct {
+	AppId   string   `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Group   string   `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	Cursors []string `protobuf:"bytes,3,rep,name=cursors" json:"cursors,omitempty"`
+}
+
+func (m *AckEventsRequest) Reset()                    { *m = AckEventsRequest{} }
+func (m *AckEventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*AckEventsRequest) ProtoMessage()               {}
+func (*AckEventsRequest) Descriptor() ([]byte, []int) { return fileDescriptorHandler, []int{17} }
+
+func (m *AckEventsRequest) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *AckEventsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *AckEventsRequest) GetCursors() []string {
+	if m != nil {
+		return m.Cursors
+	}
+	return nil
+}
+
+// BulkDevicesRequest applies an action to the devices of an application that
+// match all criteria of the filter that are set
+type BulkDevicesRequest struct {
+	AppId string `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	// The IDs of the devices. An ID that ends with a * matches all IDs that
+	// start with the part before the *, so "*" selects all devices.
+	DevIds []string `protobuf:"bytes,2,rep,name=dev_ids,json=devIds" json:"dev_ids,omitempty"`
+	// Text in the description of the devices
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// The activation constraints of the devices
+	ActivationConstraints string `protobuf:"bytes,4,opt,name=activation_constraints,json=activationConstraints,proto3" json:"activation_constraints,omitempty"`
+	// Words in the ID, DevEUI, name, description or tags of the devices
+	Query string `protobuf:"bytes,5,opt,name=query,proto3" json:"query,omitempty"`
+	// The tags that the devices must all have
+	Tags []string `protobuf:"bytes,6,rep,name=tags" json:"tags,omitempty"`
+	// The action that is applied to the devices (set, delete or requeue)
+	Action string `protobuf:"bytes,7,opt,name=action,proto3" json:"action,omitempty"`
+	// JSON-encoded object with the fields that the set action changes
+	Set string `protobuf:"bytes,8,opt,name=set,proto3" json:"set,omitempty"`
+	// Only return the selected devices, without applying the action
+	DryRun bool `protobuf:"varint,9,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+}
+
+func (m *BulkDevicesRequest) Reset()                    { *m = BulkDevicesRequest{} }
+func (m *BulkDevicesRequest) String() string            { return proto.CompactTextString(m) }
+func (*BulkDevicesRequest) ProtoMessage()               {}
+func (*BulkDevicesRequest) Descriptor() ([]byte, []int) { return fileDescriptorHandler, []int{18} }
+
+func (m *BulkDevicesRequest) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *BulkDevicesRequest) GetDevIds() []string {
+	if m != nil {
+		return m.DevIds
+	}
+	return nil
+}
+
+func (m *BulkDevicesRequest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *BulkDevicesRequest) GetActivationConstraints() string {
+	if m != nil {
+		return m.ActivationConstraints
+	}
+	return ""
+}
+
+func (m *BulkDevicesRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *BulkDevicesRequest) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *BulkDevicesRequest) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *BulkDevicesRequest) GetSet() string {
+	if m != nil {
+		return m.Set
+	}
+	return ""
+}
+
+func (m *BulkDevicesRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type BulkDevicesResponse struct {
+	// The IDs of the selected devices
+	DevIds []string `protobuf:"bytes,1,rep,name=dev_ids,json=devIds" json:"dev_ids,omitempty"`
+}
+
+func (m *BulkDevicesResponse) Reset()                    { *m = BulkDevicesResponse{} }
+func (m *BulkDevicesResponse) String() string            { return proto.CompactTextString(m) }
+func (*BulkDevicesResponse) ProtoMessage()               {}
+func (*BulkDevicesResponse) Descriptor() ([]byte, []int) { return fileDescriptorHandler, []int{19} }
+
+func (m *BulkDevicesResponse) GetDevIds() []string {
+	if m != nil {
+		return m.DevIds
+	}
+	return nil
+}
+
+type JoinCryptoRequest struct {
+	AppId  string `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	DevId  string `protobuf:"bytes,2,opt,name=dev_id,json=devId,proto3" json:"dev_id,omitempty"`
+	AppEui []byte `protobuf:"bytes,3,opt,name=app_eui,json=appEui,proto3" json:"app_eui,omitempty"`
+	DevEui []byte `protobuf:"bytes,4,opt,name=dev_eui,json=devEui,proto3" json:"dev_eui,omitempty"`
+	// The PHYPayload of the join request or of the join-accept
+	Payload []byte `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	// The DevNonce of the join request, to derive the session keys
+	DevNonce []byte `protobuf:"bytes,6,opt,name=dev_nonce,json=devNonce,proto3" json:"dev_nonce,omitempty"`
+	// The AppKey of the device, only set in SetAppKey
+	AppKey []byte `protobuf:"bytes,7,opt,name=app_key,json=appKey,proto3" json:"app_key,omitempty"`
+	// The key service does not return the AppSKey of devices with end-to-end
+	// encryption
+	EndToEnd bool `protobuf:"varint,8,opt,name=end_to_end,json=endToEnd,proto3" json:"end_to_end,omitempty"`
+}
+
+func (m *JoinCryptoRequest) Reset()                    { *m = JoinCryptoRequest{} }
+func (m *JoinCryptoRequest) String() string            { return proto.CompactTextString(m) }
+func (*JoinCryptoRequest) ProtoMessage()               {}
+func (*JoinCryptoRequest) Descriptor() ([]byte, []int) { return fileDescriptorHandler, []int{20} }
+
+func (m *JoinCryptoRequest) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *JoinCryptoRequest) GetDevId() string {
+	if m != nil {
+		return m.DevId
+	}
+	return ""
+}
+
+func (m *JoinCryptoRequest) GetAppEui() []byte {
+	if m != nil {
+		return m.AppEui
+	}
+	return nil
+}
+
+func (m *JoinCryptoRequest) GetDevEui() []byte {
+	if m != nil {
+		return m.DevEui
+	}
+	return nil
+}
+
+func (m *JoinCryptoRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *JoinCryptoRequest) GetDevNonce() []byte {
+	if m != nil {
+		return m.DevNonce
+	}
+	return nil
+}
+
+func (m *JoinCryptoRequest) GetAppKey() []byte {
+	if m != nil {
+		return m.AppKey
+	}
+	return nil
+}
+
+func (m *JoinCryptoRequest) GetEndToEnd() bool {
+	if m != nil {
+		return m.EndToEnd
+	}
+	return false
+}
+
+type JoinCryptoResponse struct {
+	// The MIC of the join request
+	Mic []byte `protobuf:"bytes,1,opt,name=mic,proto3" json:"mic,omitempty"`
+	// The join-accept with the MIC set and encrypted
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	AppSKey []byte `protobuf:"bytes,3,opt,name=app_s_key,json=appSKey,proto3" json:"app_s_key,omitempty"`
+	NwkSKey []byte `protobuf:"bytes,4,opt,name=nwk_s_key,json=nwkSKey,proto3" json:"nwk_s_key,omitempty"`
+}
+
+func (m *JoinCryptoResponse) Reset()                    { *m = JoinCryptoResponse{} }
+func (m *JoinCryptoResponse) String() string            { return proto.CompactTextString(m) }
+func (*JoinCryptoResponse) ProtoMessage()               {}
+func (*JoinCryptoResponse) Descriptor() ([]byte, []int) { return fileDescriptorHandler, []int{21} }
+
+func (m *JoinCryptoResponse) GetMic() []byte {
+	if m != nil {
+		return m.Mic
+	}
+	return nil
+}
+
+func (m *JoinCryptoResponse) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *JoinCryptoResponse) GetAppSKey() []byte {
+	if m != nil {
+		return m.AppSKey
+	}
+	return nil
+}
+
+func (m *JoinCryptoResponse) GetNwkSKey() []byte {
+	if m != nil {
+		return m.NwkSKey
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DeviceActivationResponse)(nil), "handler.DeviceActivationResponse")
 	proto.RegisterType((*StatusRequest)(nil), "handler.StatusRequest")
@@ -654,6 +1047,14 @@ func init() {
 	proto.RegisterType((*LogEntry)(nil), "handler.LogEntry")
 	proto.RegisterType((*DryUplinkResult)(nil), "handler.DryUplinkResult")
 	proto.RegisterType((*DryDownlinkResult)(nil), "handler.DryDownlinkResult")
+	proto.RegisterType((*EventsRequest)(nil), "handler.EventsRequest")
+	proto.RegisterType((*StreamEvent)(nil), "handler.StreamEvent")
+	proto.RegisterType((*EventsResponse)(nil), "handler.EventsResponse")
+	proto.RegisterType((*AckEventsRequest)(nil), "handler.AckEventsRequest")
+	proto.RegisterType((*BulkDevicesRequest)(nil), "handler.BulkDevicesRequest")
+	proto.RegisterType((*BulkDevicesResponse)(nil), "handler.BulkDevicesResponse")
+	proto.RegisterType((*JoinCryptoRequest)(nil), "handler.JoinCryptoRequest")
+	proto.RegisterType((*JoinCryptoResponse)(nil), "handler.JoinCryptoResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -786,6 +1187,14 @@ type ApplicationManagerClient interface {
 	DryUplink(ctx context.Context, in *DryUplinkMessage, opts ...grpc.CallOption) (*DryUplinkResult, error)
 	// SimulateUplink simulates an uplink message
 	SimulateUplink(ctx context.Context, in *SimulatedUplinkMessage, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// GetEvents reads messages from the event stream of an application, so that
+	// clients can resume after the cursor of the last message they received
+	GetEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (*EventsResponse, error)
+	// AckEvents acknowledges messages that a consumer group read with GetEvents
+	AckEvents(ctx context.Context, in *AckEventsRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// BulkDevices updates, deletes or requeues the downlinks of the devices
+	// that match a filter. Updates are applied to all devices or to none.
+	BulkDevices(ctx context.Context, in *BulkDevicesRequest, opts ...grpc.CallOption) (*BulkDevicesResponse, error)
 }
 
 type applicationManagerClient struct {
@@ -895,6 +1304,33 @@ func (c *applicationManagerClient) SimulateUplink(ctx context.Context, in *Simul
 	return out, nil
 }
 
+func (c *applicationManagerClient) GetEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (*EventsResponse, error) {
+	out := new(EventsResponse)
+	err := grpc.Invoke(ctx, "/handler.ApplicationManager/GetEvents", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *applicationManagerClient) AckEvents(ctx context.Context, in *AckEventsRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/handler.ApplicationManager/AckEvents", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *applicationManagerClient) BulkDevices(ctx context.Context, in *BulkDevicesRequest, opts ...grpc.CallOption) (*BulkDevicesResponse, error) {
+	out := new(BulkDevicesResponse)
+	err := grpc.Invoke(ctx, "/handler.ApplicationManager/BulkDevices", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for ApplicationManager service
 
 type ApplicationManagerServer interface {
@@ -920,6 +1356,14 @@ type ApplicationManagerServer interface {
 	DryUplink(context.Context, *DryUplinkMessage) (*DryUplinkResult, error)
 	// SimulateUplink simulates an uplink message
 	SimulateUplink(context.Context, *SimulatedUplinkMessage) (*google_protobuf.Empty, error)
+	// GetEvents reads messages from the event stream of an application, so that
+	// clients can resume after the cursor of the last message they received
+	GetEvents(context.Context, *EventsRequest) (*EventsResponse, error)
+	// AckEvents acknowledges messages that a consumer group read with GetEvents
+	AckEvents(context.Context, *AckEventsRequest) (*google_protobuf.Empty, error)
+	// BulkDevices updates, deletes or requeues the downlinks of the devices
+	// that match a filter. Updates are applied to all devices or to none.
+	BulkDevices(context.Context, *BulkDevicesRequest) (*BulkDevicesResponse, error)
 }
 
 func RegisterApplicationManagerServer(s *grpc.Server, srv ApplicationManagerServer) {
@@ -1124,37 +1568,91 @@ func _ApplicationManager_SimulateUplink_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
-var _ApplicationManager_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "handler.ApplicationManager",
-	HandlerType: (*ApplicationManagerServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "RegisterApplication",
-			Handler:    _ApplicationManager_RegisterApplication_Handler,
-		},
-		{
-			MethodName: "GetApplication",
-			Handler:    _ApplicationManager_GetApplication_Handler,
-		},
-		{
-			MethodName: "SetApplication",
-			Handler:    _ApplicationManager_SetApplication_Handler,
-		},
-		{
-			MethodName: "DeleteApplication",
-			Handler:    _ApplicationManager_DeleteApplication_Handler,
-		},
-		{
-			MethodName: "GetDevice",
-			Handler:    _ApplicationManager_GetDevice_Handler,
-		},
-		{
-			MethodName: "SetDevice",
-			Handler:    _ApplicationManager_SetDevice_Handler,
-		},
-		{
-			MethodName: "DeleteDevice",
-			Handler:    _ApplicationManager_DeleteDevice_Handler,
+func _ApplicationManager_GetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationManagerServer).GetEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/handler.ApplicationManager/GetEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationManagerServer).GetEvents(ctx, req.(*EventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApplicationManager_AckEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationManagerServer).AckEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/handler.ApplicationManager/AckEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationManagerServer).AckEvents(ctx, req.(*AckEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApplicationManager_BulkDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationManagerServer).BulkDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/handler.ApplicationManager/BulkDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationManagerServer).BulkDevices(ctx, req.(*BulkDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ApplicationManager_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "handler.ApplicationManager",
+	HandlerType: (*ApplicationManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterApplication",
+			Handler:    _ApplicationManager_RegisterApplication_Handler,
+		},
+		{
+			MethodName: "GetApplication",
+			Handler:    _ApplicationManager_GetApplication_Handler,
+		},
+		{
+			MethodName: "SetApplication",
+			Handler:    _ApplicationManager_SetApplication_Handler,
+		},
+		{
+			MethodName: "DeleteApplication",
+			Handler:    _ApplicationManager_DeleteApplication_Handler,
+		},
+		{
+			MethodName: "GetDevice",
+			Handler:    _ApplicationManager_GetDevice_Handler,
+		},
+		{
+			MethodName: "SetDevice",
+			Handler:    _ApplicationManager_SetDevice_Handler,
+		},
+		{
+			MethodName: "DeleteDevice",
+			Handler:    _ApplicationManager_DeleteDevice_Handler,
 		},
 		{
 			MethodName: "GetDevicesForApplication",
@@ -1172,6 +1670,18 @@ var _ApplicationManager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SimulateUplink",
 			Handler:    _ApplicationManager_SimulateUplink_Handler,
 		},
+		{
+			MethodName: "GetEvents",
+			Handler:    _ApplicationManager_GetEvents_Handler,
+		},
+		{
+			MethodName: "AckEvents",
+			Handler:    _ApplicationManager_AckEvents_Handler,
+		},
+		{
+			MethodName: "BulkDevices",
+			Handler:    _ApplicationManager_BulkDevices_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "github.com/TheThingsNetwork/ttn/api/handler/handler.proto",
@@ -1181,6 +1691,8 @@ var _ApplicationManager_serviceDesc = grpc.ServiceDesc{
 
 type HandlerManagerClient interface {
 	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// Network operator streams the log entries of the Handler
+	TailLogs(ctx context.Context, in *api.LogsRequest, opts ...grpc.CallOption) (HandlerManager_TailLogsClient, error)
 }
 
 type handlerManagerClient struct {
@@ -1200,10 +1712,44 @@ func (c *handlerManagerClient) GetStatus(ctx context.Context, in *StatusRequest,
 	return out, nil
 }
 
+func (c *handlerManagerClient) TailLogs(ctx context.Context, in *api.LogsRequest, opts ...grpc.CallOption) (HandlerManager_TailLogsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_HandlerManager_serviceDesc.Streams[0], c.cc, "/handler.HandlerManager/TailLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &handlerManagerTailLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HandlerManager_TailLogsClient interface {
+	Recv() (*api.LogEntry, error)
+	grpc.ClientStream
+}
+
+type handlerManagerTailLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *handlerManagerTailLogsClient) Recv() (*api.LogEntry, error) {
+	m := new(api.LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for HandlerManager service
 
 type HandlerManagerServer interface {
 	GetStatus(context.Context, *StatusRequest) (*Status, error)
+	// Network operator streams the log entries of the Handler
+	TailLogs(*api.LogsRequest, HandlerManager_TailLogsServer) error
 }
 
 func RegisterHandlerManagerServer(s *grpc.Server, srv HandlerManagerServer) {
@@ -1228,6 +1774,27 @@ func _HandlerManager_GetStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HandlerManager_TailLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(api.LogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HandlerManagerServer).TailLogs(m, &handlerManagerTailLogsServer{stream})
+}
+
+type HandlerManager_TailLogsServer interface {
+	Send(*api.LogEntry) error
+	grpc.ServerStream
+}
+
+type handlerManagerTailLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *handlerManagerTailLogsServer) Send(m *api.LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _HandlerManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "handler.HandlerManager",
 	HandlerType: (*HandlerManagerServer)(nil),
@@ -1237,6 +1804,152 @@ var _HandlerManager_serviceDesc = grpc.ServiceDesc{
 			Handler:    _HandlerManager_GetStatus_Handler,
 		},
 	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailLogs",
+			Handler:       _HandlerManager_TailLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "github.com/TheThingsNetwork/ttn/api/handler/handler.proto",
+}
+
+// Client API for JoinCrypto service
+
+type JoinCryptoClient interface {
+	// JoinRequestMIC returns the MIC of the join request
+	JoinRequestMIC(ctx context.Context, in *JoinCryptoRequest, opts ...grpc.CallOption) (*JoinCryptoResponse, error)
+	// JoinAccept sets the MIC of the join-accept, encrypts it and derives the
+	// session keys from the AppNonce and NetID of the join-accept and the DevNonce
+	JoinAccept(ctx context.Context, in *JoinCryptoRequest, opts ...grpc.CallOption) (*JoinCryptoResponse, error)
+	// SetAppKey stores the AppKey of a device that is registered, imported or
+	// provisioned on the Handler
+	SetAppKey(ctx context.Context, in *JoinCryptoRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+}
+
+type joinCryptoClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewJoinCryptoClient(cc *grpc.ClientConn) JoinCryptoClient {
+	return &joinCryptoClient{cc}
+}
+
+func (c *joinCryptoClient) JoinRequestMIC(ctx context.Context, in *JoinCryptoRequest, opts ...grpc.CallOption) (*JoinCryptoResponse, error) {
+	out := new(JoinCryptoResponse)
+	err := grpc.Invoke(ctx, "/handler.JoinCrypto/JoinRequestMIC", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *joinCryptoClient) JoinAccept(ctx context.Context, in *JoinCryptoRequest, opts ...grpc.CallOption) (*JoinCryptoResponse, error) {
+	out := new(JoinCryptoResponse)
+	err := grpc.Invoke(ctx, "/handler.JoinCrypto/JoinAccept", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *joinCryptoClient) SetAppKey(ctx context.Context, in *JoinCryptoRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/handler.JoinCrypto/SetAppKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for JoinCrypto service
+
+type JoinCryptoServer interface {
+	// JoinRequestMIC returns the MIC of the join request
+	JoinRequestMIC(context.Context, *JoinCryptoRequest) (*JoinCryptoResponse, error)
+	// JoinAccept sets the MIC of the join-accept, encrypts it and derives the
+	// session keys from the AppNonce and NetID of the join-accept and the DevNonce
+	JoinAccept(context.Context, *JoinCryptoRequest) (*JoinCryptoResponse, error)
+	// SetAppKey stores the AppKey of a device that is registered, imported or
+	// provisioned on the Handler
+	SetAppKey(context.Context, *JoinCryptoRequest) (*google_protobuf.Empty, error)
+}
+
+func RegisterJoinCryptoServer(s *grpc.Server, srv JoinCryptoServer) {
+	s.RegisterService(&_JoinCrypto_serviceDesc, srv)
+}
+
+func _JoinCrypto_JoinRequestMIC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinCryptoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JoinCryptoServer).JoinRequestMIC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/handler.JoinCrypto/JoinRequestMIC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JoinCryptoServer).JoinRequestMIC(ctx, req.(*JoinCryptoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JoinCrypto_JoinAccept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinCryptoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JoinCryptoServer).JoinAccept(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/handler.JoinCrypto/JoinAccept",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JoinCryptoServer).JoinAccept(ctx, req.(*JoinCryptoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JoinCrypto_SetAppKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinCryptoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JoinCryptoServer).SetAppKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/handler.JoinCrypto/SetAppKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JoinCryptoServer).SetAppKey(ctx, req.(*JoinCryptoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _JoinCrypto_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "handler.JoinCrypto",
+	HandlerType: (*JoinCryptoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "JoinRequestMIC",
+			Handler:    _JoinCrypto_JoinRequestMIC_Handler,
+		},
+		{
+			MethodName: "JoinAccept",
+			Handler:    _JoinCrypto_JoinAccept_Handler,
+		},
+		{
+			MethodName: "SetAppKey",
+			Handler:    _JoinCrypto_SetAppKey_Handler,
+		},
+	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "github.com/TheThingsNetwork/ttn/api/handler/handler.proto",
 }
@@ -1563,11 +2276,11 @@ func (m *Device_LorawanDevice) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintHandler(dAtA, i, uint64(m.LorawanDevice.Size()))
-		n11, err := m.LorawanDevice.MarshalTo(dAtA[i:])
+		n10, err := m.LorawanDevice.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n11
+		i += n10
 	}
 	return i, nil
 }
@@ -1632,11 +2345,11 @@ func (m *DryDownlinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintHandler(dAtA, i, uint64(m.App.Size()))
-		n12, err := m.App.MarshalTo(dAtA[i:])
+		n11, err := m.App.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n12
+		i += n11
 	}
 	if m.Port != 0 {
 		dAtA[i] = 0x20
@@ -1671,11 +2384,11 @@ func (m *DryUplinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintHandler(dAtA, i, uint64(m.App.Size()))
-		n13, err := m.App.MarshalTo(dAtA[i:])
+		n12, err := m.App.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n13
+		i += n12
 	}
 	if m.Port != 0 {
 		dAtA[i] = 0x18
@@ -1853,231 +2566,541 @@ func (m *DryDownlinkResult) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func encodeFixed64Handler(dAtA []byte, offset int, v uint64) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	dAtA[offset+4] = uint8(v >> 32)
-	dAtA[offset+5] = uint8(v >> 40)
-	dAtA[offset+6] = uint8(v >> 48)
-	dAtA[offset+7] = uint8(v >> 56)
-	return offset + 8
-}
-func encodeFixed32Handler(dAtA []byte, offset int, v uint32) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	return offset + 4
-}
-func encodeVarintHandler(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *EventsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *DeviceActivationResponse) Size() (n int) {
+
+func (m *EventsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
 	}
-	if m.Message != nil {
-		l = m.Message.Size()
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.After) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.After)))
+		i += copy(dAtA[i:], m.After)
 	}
-	if m.DownlinkOption != nil {
-		l = m.DownlinkOption.Size()
-		n += 1 + l + sovHandler(uint64(l))
+	if m.Limit != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(m.Limit))
 	}
-	if m.ActivationMetadata != nil {
-		l = m.ActivationMetadata.Size()
-		n += 2 + l + sovHandler(uint64(l))
+	if len(m.Group) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Group)))
+		i += copy(dAtA[i:], m.Group)
 	}
-	if m.Trace != nil {
-		l = m.Trace.Size()
-		n += 2 + l + sovHandler(uint64(l))
+	if len(m.Consumer) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Consumer)))
+		i += copy(dAtA[i:], m.Consumer)
 	}
-	return n
+	if m.Pending {
+		dAtA[i] = 0x30
+		i++
+		if m.Pending {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
 }
 
-func (m *StatusRequest) Size() (n int) {
-	var l int
-	_ = l
-	return n
+func (m *StreamEvent) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
 
-func (m *Status) Size() (n int) {
+func (m *StreamEvent) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.System != nil {
-		l = m.System.Size()
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.Cursor) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Cursor)))
+		i += copy(dAtA[i:], m.Cursor)
 	}
-	if m.Component != nil {
-		l = m.Component.Size()
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.DevId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.DevId)))
+		i += copy(dAtA[i:], m.DevId)
 	}
-	if m.Uplink != nil {
-		l = m.Uplink.Size()
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.Event) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Event)))
+		i += copy(dAtA[i:], m.Event)
 	}
-	if m.Downlink != nil {
-		l = m.Downlink.Size()
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
 	}
-	if m.Activations != nil {
-		l = m.Activations.Size()
-		n += 1 + l + sovHandler(uint64(l))
+	return i, nil
+}
+
+func (m *EventsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *ApplicationIdentifier) Size() (n int) {
+func (m *EventsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.AppId)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.Events) > 0 {
+		for _, msg := range m.Events {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintHandler(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
 	}
-	return n
+	return i, nil
 }
 
-func (m *Application) Size() (n int) {
+func (m *AckEventsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AckEventsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.AppId)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
-	}
-	l = len(m.Decoder)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
-	}
-	l = len(m.Converter)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
 	}
-	l = len(m.Validator)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.Group) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Group)))
+		i += copy(dAtA[i:], m.Group)
 	}
-	l = len(m.Encoder)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.Cursors) > 0 {
+		for _, s := range m.Cursors {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	return n
+	return i, nil
 }
 
-func (m *DeviceIdentifier) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.AppId)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
-	}
-	l = len(m.DevId)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+func (m *BulkDevicesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *Device) Size() (n int) {
+func (m *BulkDevicesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.AppId)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
 	}
-	l = len(m.DevId)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+	if len(m.DevIds) > 0 {
+		for _, s := range m.DevIds {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	if m.Device != nil {
-		n += m.Device.Size()
+	if len(m.Description) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Description)))
+		i += copy(dAtA[i:], m.Description)
 	}
-	if m.Latitude != 0 {
-		n += 5
+	if len(m.ActivationConstraints) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.ActivationConstraints)))
+		i += copy(dAtA[i:], m.ActivationConstraints)
 	}
-	if m.Longitude != 0 {
-		n += 5
+	if len(m.Query) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Query)))
+		i += copy(dAtA[i:], m.Query)
 	}
-	if m.Altitude != 0 {
-		n += 1 + sovHandler(uint64(m.Altitude))
+	if len(m.Tags) > 0 {
+		for _, s := range m.Tags {
+			dAtA[i] = 0x32
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
 	}
-	l = len(m.Description)
-	if l > 0 {
-		n += 2 + l + sovHandler(uint64(l))
+	if len(m.Action) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Action)))
+		i += copy(dAtA[i:], m.Action)
 	}
-	return n
+	if len(m.Set) > 0 {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Set)))
+		i += copy(dAtA[i:], m.Set)
+	}
+	if m.DryRun {
+		dAtA[i] = 0x48
+		i++
+		if m.DryRun {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
 }
 
-func (m *Device_LorawanDevice) Size() (n int) {
-	var l int
-	_ = l
-	if m.LorawanDevice != nil {
-		l = m.LorawanDevice.Size()
-		n += 1 + l + sovHandler(uint64(l))
+func (m *BulkDevicesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
-func (m *DeviceList) Size() (n int) {
+
+func (m *BulkDevicesResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if len(m.Devices) > 0 {
-		for _, e := range m.Devices {
-			l = e.Size()
-			n += 1 + l + sovHandler(uint64(l))
+	if len(m.DevIds) > 0 {
+		for _, s := range m.DevIds {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
 		}
 	}
-	return n
+	return i, nil
 }
 
-func (m *DryDownlinkMessage) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovHandler(uint64(l))
+func (m *JoinCryptoRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	l = len(m.Fields)
+	return dAtA[:n], nil
+}
+
+func (m *JoinCryptoRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
+	}
+	if len(m.DevId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.DevId)))
+		i += copy(dAtA[i:], m.DevId)
+	}
+	if len(m.AppEui) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.AppEui)))
+		i += copy(dAtA[i:], m.AppEui)
+	}
+	if len(m.DevEui) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.DevEui)))
+		i += copy(dAtA[i:], m.DevEui)
+	}
+	if len(m.Payload) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Payload)))
+		i += copy(dAtA[i:], m.Payload)
+	}
+	if len(m.DevNonce) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.DevNonce)))
+		i += copy(dAtA[i:], m.DevNonce)
+	}
+	if len(m.AppKey) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.AppKey)))
+		i += copy(dAtA[i:], m.AppKey)
+	}
+	if m.EndToEnd {
+		dAtA[i] = 0x40
+		i++
+		if m.EndToEnd {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *JoinCryptoResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *JoinCryptoResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Mic) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Mic)))
+		i += copy(dAtA[i:], m.Mic)
+	}
+	if len(m.Payload) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.Payload)))
+		i += copy(dAtA[i:], m.Payload)
+	}
+	if len(m.AppSKey) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.AppSKey)))
+		i += copy(dAtA[i:], m.AppSKey)
+	}
+	if len(m.NwkSKey) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintHandler(dAtA, i, uint64(len(m.NwkSKey)))
+		i += copy(dAtA[i:], m.NwkSKey)
+	}
+	return i, nil
+}
+
+func encodeFixed64Handler(dAtA []byte, offset int, v uint64) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	dAtA[offset+4] = uint8(v >> 32)
+	dAtA[offset+5] = uint8(v >> 40)
+	dAtA[offset+6] = uint8(v >> 48)
+	dAtA[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Handler(dAtA []byte, offset int, v uint32) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintHandler(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *DeviceActivationResponse) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
 	if l > 0 {
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	if m.App != nil {
-		l = m.App.Size()
+	if m.Message != nil {
+		l = m.Message.Size()
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	if m.Port != 0 {
-		n += 1 + sovHandler(uint64(m.Port))
+	if m.DownlinkOption != nil {
+		l = m.DownlinkOption.Size()
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.ActivationMetadata != nil {
+		l = m.ActivationMetadata.Size()
+		n += 2 + l + sovHandler(uint64(l))
+	}
+	if m.Trace != nil {
+		l = m.Trace.Size()
+		n += 2 + l + sovHandler(uint64(l))
 	}
 	return n
 }
 
-func (m *DryUplinkMessage) Size() (n int) {
+func (m *StatusRequest) Size() (n int) {
 	var l int
 	_ = l
-	l = len(m.Payload)
+	return n
+}
+
+func (m *Status) Size() (n int) {
+	var l int
+	_ = l
+	if m.System != nil {
+		l = m.System.Size()
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Component != nil {
+		l = m.Component.Size()
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Uplink != nil {
+		l = m.Uplink.Size()
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Downlink != nil {
+		l = m.Downlink.Size()
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Activations != nil {
+		l = m.Activations.Size()
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	return n
+}
+
+func (m *ApplicationIdentifier) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AppId)
 	if l > 0 {
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	if m.App != nil {
-		l = m.App.Size()
+	return n
+}
+
+func (m *Application) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AppId)
+	if l > 0 {
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	if m.Port != 0 {
-		n += 1 + sovHandler(uint64(m.Port))
+	l = len(m.Decoder)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Converter)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Validator)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Encoder)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
 	}
 	return n
 }
 
-func (m *SimulatedUplinkMessage) Size() (n int) {
+func (m *DeviceIdentifier) Size() (n int) {
 	var l int
 	_ = l
 	l = len(m.AppId)
@@ -2088,33 +3111,61 @@ func (m *SimulatedUplinkMessage) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	l = len(m.Payload)
+	return n
+}
+
+func (m *Device) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AppId)
 	if l > 0 {
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	if m.Port != 0 {
-		n += 1 + sovHandler(uint64(m.Port))
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Device != nil {
+		n += m.Device.Size()
+	}
+	if m.Latitude != 0 {
+		n += 5
+	}
+	if m.Longitude != 0 {
+		n += 5
+	}
+	if m.Altitude != 0 {
+		n += 1 + sovHandler(uint64(m.Altitude))
+	}
+	l = len(m.Description)
+	if l > 0 {
+		n += 2 + l + sovHandler(uint64(l))
 	}
 	return n
 }
 
-func (m *LogEntry) Size() (n int) {
+func (m *Device_LorawanDevice) Size() (n int) {
 	var l int
 	_ = l
-	l = len(m.Function)
-	if l > 0 {
+	if m.LorawanDevice != nil {
+		l = m.LorawanDevice.Size()
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	if len(m.Fields) > 0 {
-		for _, s := range m.Fields {
-			l = len(s)
+	return n
+}
+func (m *DeviceList) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Devices) > 0 {
+		for _, e := range m.Devices {
+			l = e.Size()
 			n += 1 + l + sovHandler(uint64(l))
 		}
 	}
 	return n
 }
 
-func (m *DryUplinkResult) Size() (n int) {
+func (m *DryDownlinkMessage) Size() (n int) {
 	var l int
 	_ = l
 	l = len(m.Payload)
@@ -2125,48 +3176,1931 @@ func (m *DryUplinkResult) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	if m.Valid {
-		n += 2
+	if m.App != nil {
+		l = m.App.Size()
+		n += 1 + l + sovHandler(uint64(l))
 	}
-	if len(m.Logs) > 0 {
-		for _, e := range m.Logs {
-			l = e.Size()
-			n += 1 + l + sovHandler(uint64(l))
-		}
+	if m.Port != 0 {
+		n += 1 + sovHandler(uint64(m.Port))
 	}
 	return n
 }
 
-func (m *DryDownlinkResult) Size() (n int) {
+func (m *DryUplinkMessage) Size() (n int) {
 	var l int
 	_ = l
 	l = len(m.Payload)
 	if l > 0 {
 		n += 1 + l + sovHandler(uint64(l))
 	}
-	if len(m.Logs) > 0 {
-		for _, e := range m.Logs {
-			l = e.Size()
-			n += 1 + l + sovHandler(uint64(l))
+	if m.App != nil {
+		l = m.App.Size()
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Port != 0 {
+		n += 1 + sovHandler(uint64(m.Port))
+	}
+	return n
+}
+
+func (m *SimulatedUplinkMessage) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Port != 0 {
+		n += 1 + sovHandler(uint64(m.Port))
+	}
+	return n
+}
+
+func (m *LogEntry) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Function)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if len(m.Fields) > 0 {
+		for _, s := range m.Fields {
+			l = len(s)
+			n += 1 + l + sovHandler(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DryUplinkResult) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Fields)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Valid {
+		n += 2
+	}
+	if len(m.Logs) > 0 {
+		for _, e := range m.Logs {
+			l = e.Size()
+			n += 1 + l + sovHandler(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DryDownlinkResult) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if len(m.Logs) > 0 {
+		for _, e := range m.Logs {
+			l = e.Size()
+			n += 1 + l + sovHandler(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *EventsRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.After)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovHandler(uint64(m.Limit))
+	}
+	l = len(m.Group)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Consumer)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.Pending {
+		n += 2
+	}
+	return n
+}
+
+func (m *StreamEvent) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Cursor)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Event)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	return n
+}
+
+func (m *EventsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Events) > 0 {
+		for _, e := range m.Events {
+			l = e.Size()
+			n += 1 + l + sovHandler(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AckEventsRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Group)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if len(m.Cursors) > 0 {
+		for _, s := range m.Cursors {
+			l = len(s)
+			n += 1 + l + sovHandler(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *BulkDevicesRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if len(m.DevIds) > 0 {
+		for _, s := range m.DevIds {
+			l = len(s)
+			n += 1 + l + sovHandler(uint64(l))
+		}
+	}
+	l = len(m.Description)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.ActivationConstraints)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if len(m.Tags) > 0 {
+		for _, s := range m.Tags {
+			l = len(s)
+			n += 1 + l + sovHandler(uint64(l))
+		}
+	}
+	l = len(m.Action)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Set)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.DryRun {
+		n += 2
+	}
+	return n
+}
+
+func (m *BulkDevicesResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.DevIds) > 0 {
+		for _, s := range m.DevIds {
+			l = len(s)
+			n += 1 + l + sovHandler(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *JoinCryptoRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.DevId)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.AppEui)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.DevEui)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.DevNonce)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.AppKey)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	if m.EndToEnd {
+		n += 2
+	}
+	return n
+}
+
+func (m *JoinCryptoResponse) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Mic)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.AppSKey)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	l = len(m.NwkSKey)
+	if l > 0 {
+		n += 1 + l + sovHandler(uint64(l))
+	}
+	return n
+}
+
+func sovHandler(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozHandler(x uint64) (n int) {
+	return sovHandler(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeviceActivationResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeviceActivationResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Message == nil {
+				m.Message = &protocol.Message{}
+			}
+			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOption", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DownlinkOption == nil {
+				m.DownlinkOption = &broker.DownlinkOption{}
+			}
+			if err := m.DownlinkOption.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ActivationMetadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ActivationMetadata == nil {
+				m.ActivationMetadata = &protocol.ActivationMetadata{}
+			}
+			if err := m.ActivationMetadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Trace == nil {
+				m.Trace = &trace.Trace{}
+			}
+			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StatusRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StatusRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StatusRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Status) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Status: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Status: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field System", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.System == nil {
+				m.System = &api.SystemStats{}
+			}
+			if err := m.System.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Component", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Component == nil {
+				m.Component = &api.ComponentStats{}
+			}
+			if err := m.Component.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uplink", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Uplink == nil {
+				m.Uplink = &api.Rates{}
+			}
+			if err := m.Uplink.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Downlink", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Downlink == nil {
+				m.Downlink = &api.Rates{}
+			}
+			if err := m.Downlink.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Activations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Activations == nil {
+				m.Activations = &api.Rates{}
+			}
+			if err := m.Activations.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ApplicationIdentifier) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ApplicationIdentifier: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ApplicationIdentifier: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Application) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Application: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Application: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Decoder", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Decoder = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Converter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Converter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Validator", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Validator = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encoder", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Encoder = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeviceIdentifier) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeviceIdentifier: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeviceIdentifier: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DevId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Device) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Device: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Device: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DevId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LorawanDevice", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &lorawan1.Device{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Device = &Device_LorawanDevice{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Latitude", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 4
+			v = uint32(dAtA[iNdEx-4])
+			v |= uint32(dAtA[iNdEx-3]) << 8
+			v |= uint32(dAtA[iNdEx-2]) << 16
+			v |= uint32(dAtA[iNdEx-1]) << 24
+			m.Latitude = float32(math.Float32frombits(v))
+		case 11:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Longitude", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 4
+			v = uint32(dAtA[iNdEx-4])
+			v |= uint32(dAtA[iNdEx-3]) << 8
+			v |= uint32(dAtA[iNdEx-2]) << 16
+			v |= uint32(dAtA[iNdEx-1]) << 24
+			m.Longitude = float32(math.Float32frombits(v))
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Altitude", wireType)
+			}
+			m.Altitude = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Altitude |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Description", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Description = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeviceList) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeviceList: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeviceList: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Devices", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Devices = append(m.Devices, &Device{})
+			if err := m.Devices[len(m.Devices)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DryDownlinkMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DryDownlinkMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DryDownlinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Fields = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field App", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.App == nil {
+				m.App = &Application{}
+			}
+			if err := m.App.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Port", wireType)
+			}
+			m.Port = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Port |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DryUplinkMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DryUplinkMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DryUplinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field App", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.App == nil {
+				m.App = &Application{}
+			}
+			if err := m.App.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Port", wireType)
+			}
+			m.Port = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Port |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SimulatedUplinkMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SimulatedUplinkMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SimulatedUplinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DevId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Port", wireType)
+			}
+			m.Port = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Port |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
 
-func sovHandler(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozHandler(x uint64) (n int) {
-	return sovHandler(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
+func (m *LogEntry) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2189,17 +5123,17 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DeviceActivationResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: LogEntry: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DeviceActivationResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LogEntry: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Function", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2209,28 +5143,26 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
-			}
+			m.Function = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2240,30 +5172,76 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Message == nil {
-				m.Message = &protocol.Message{}
-			}
-			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Fields = append(m.Fields, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHandler(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 11:
+			if skippy < 0 {
+				return ErrInvalidLengthHandler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DryUplinkResult) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHandler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DryUplinkResult: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DryUplinkResult: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DownlinkOption", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2273,30 +5251,28 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.DownlinkOption == nil {
-				m.DownlinkOption = &broker.DownlinkOption{}
-			}
-			if err := m.DownlinkOption.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
 			}
 			iNdEx = postIndex
-		case 23:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ActivationMetadata", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2306,28 +5282,44 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Fields = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Valid", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.ActivationMetadata == nil {
-				m.ActivationMetadata = &protocol.ActivationMetadata{}
-			}
-			if err := m.ActivationMetadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 31:
+			m.Valid = bool(v != 0)
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Logs", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2351,10 +5343,8 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Trace == nil {
-				m.Trace = &trace.Trace{}
-			}
-			if err := m.Trace.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Logs = append(m.Logs, &LogEntry{})
+			if err := m.Logs[len(m.Logs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2379,7 +5369,7 @@ func (m *DeviceActivationResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StatusRequest) Unmarshal(dAtA []byte) error {
+func (m *DryDownlinkResult) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2402,12 +5392,74 @@ func (m *StatusRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StatusRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: DryDownlinkResult: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StatusRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DryDownlinkResult: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Logs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Logs = append(m.Logs, &LogEntry{})
+			if err := m.Logs[len(m.Logs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHandler(dAtA[iNdEx:])
@@ -2429,7 +5481,7 @@ func (m *StatusRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Status) Unmarshal(dAtA []byte) error {
+func (m *EventsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2452,17 +5504,17 @@ func (m *Status) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Status: wiretype end group for non-group")
+			return fmt.Errorf("proto: EventsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Status: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: EventsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field System", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2472,30 +5524,26 @@ func (m *Status) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.System == nil {
-				m.System = &api.SystemStats{}
-			}
-			if err := m.System.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Component", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2505,30 +5553,26 @@ func (m *Status) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Component == nil {
-				m.Component = &api.ComponentStats{}
-			}
-			if err := m.Component.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.After = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 11:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Uplink", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
 			}
-			var msglen int
+			m.Limit = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2538,30 +5582,16 @@ func (m *Status) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Limit |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Uplink == nil {
-				m.Uplink = &api.Rates{}
-			}
-			if err := m.Uplink.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 12:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Downlink", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Group", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2571,30 +5601,26 @@ func (m *Status) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Downlink == nil {
-				m.Downlink = &api.Rates{}
-			}
-			if err := m.Downlink.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Group = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 13:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Activations", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Consumer", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2604,80 +5630,26 @@ func (m *Status) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Activations == nil {
-				m.Activations = &api.Rates{}
-			}
-			if err := m.Activations.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Consumer = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipHandler(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthHandler
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *ApplicationIdentifier) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowHandler
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: ApplicationIdentifier: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ApplicationIdentifier: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pending", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2686,22 +5658,13 @@ func (m *ApplicationIdentifier) Unmarshal(dAtA []byte) error {
 					return io.ErrUnexpectedEOF
 				}
 				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.AppId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
+			m.Pending = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHandler(dAtA[iNdEx:])
@@ -2723,7 +5686,7 @@ func (m *ApplicationIdentifier) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Application) Unmarshal(dAtA []byte) error {
+func (m *StreamEvent) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2746,15 +5709,15 @@ func (m *Application) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Application: wiretype end group for non-group")
+			return fmt.Errorf("proto: StreamEvent: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Application: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StreamEvent: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Cursor", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2779,11 +5742,11 @@ func (m *Application) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AppId = string(dAtA[iNdEx:postIndex])
+			m.Cursor = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Decoder", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2808,11 +5771,11 @@ func (m *Application) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Decoder = string(dAtA[iNdEx:postIndex])
+			m.DevId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Converter", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Event", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2837,40 +5800,11 @@ func (m *Application) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Converter = string(dAtA[iNdEx:postIndex])
+			m.Event = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Validator", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowHandler
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Validator = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Encoder", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2895,7 +5829,7 @@ func (m *Application) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Encoder = string(dAtA[iNdEx:postIndex])
+			m.Data = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2918,7 +5852,7 @@ func (m *Application) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DeviceIdentifier) Unmarshal(dAtA []byte) error {
+func (m *EventsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2941,17 +5875,17 @@ func (m *DeviceIdentifier) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DeviceIdentifier: wiretype end group for non-group")
+			return fmt.Errorf("proto: EventsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DeviceIdentifier: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: EventsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -2961,49 +5895,22 @@ func (m *DeviceIdentifier) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AppId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowHandler
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.Events = append(m.Events, &StreamEvent{})
+			if err := m.Events[len(m.Events)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			m.DevId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3026,7 +5933,7 @@ func (m *DeviceIdentifier) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Device) Unmarshal(dAtA []byte) error {
+func (m *AckEventsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3049,10 +5956,10 @@ func (m *Device) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Device: wiretype end group for non-group")
+			return fmt.Errorf("proto: AckEventsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Device: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AckEventsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3086,7 +5993,7 @@ func (m *Device) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Group", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3111,171 +6018,13 @@ func (m *Device) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DevId = string(dAtA[iNdEx:postIndex])
+			m.Group = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LorawanDevice", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowHandler
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			v := &lorawan1.Device{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.Device = &Device_LorawanDevice{v}
-			iNdEx = postIndex
-		case 10:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Latitude", wireType)
-			}
-			var v uint32
-			if (iNdEx + 4) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += 4
-			v = uint32(dAtA[iNdEx-4])
-			v |= uint32(dAtA[iNdEx-3]) << 8
-			v |= uint32(dAtA[iNdEx-2]) << 16
-			v |= uint32(dAtA[iNdEx-1]) << 24
-			m.Latitude = float32(math.Float32frombits(v))
-		case 11:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Longitude", wireType)
-			}
-			var v uint32
-			if (iNdEx + 4) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += 4
-			v = uint32(dAtA[iNdEx-4])
-			v |= uint32(dAtA[iNdEx-3]) << 8
-			v |= uint32(dAtA[iNdEx-2]) << 16
-			v |= uint32(dAtA[iNdEx-1]) << 24
-			m.Longitude = float32(math.Float32frombits(v))
-		case 12:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Altitude", wireType)
-			}
-			m.Altitude = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowHandler
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Altitude |= (int32(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 20:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Description", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowHandler
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Description = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipHandler(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthHandler
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *DeviceList) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowHandler
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: DeviceList: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DeviceList: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Devices", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Cursors", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3285,22 +6034,20 @@ func (m *DeviceList) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Devices = append(m.Devices, &Device{})
-			if err := m.Devices[len(m.Devices)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Cursors = append(m.Cursors, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3323,7 +6070,7 @@ func (m *DeviceList) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DryDownlinkMessage) Unmarshal(dAtA []byte) error {
+func (m *BulkDevicesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3346,17 +6093,17 @@ func (m *DryDownlinkMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DryDownlinkMessage: wiretype end group for non-group")
+			return fmt.Errorf("proto: BulkDevicesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DryDownlinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BulkDevicesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3366,26 +6113,24 @@ func (m *DryDownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
-			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DevIds", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3410,13 +6155,13 @@ func (m *DryDownlinkMessage) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Fields = string(dAtA[iNdEx:postIndex])
+			m.DevIds = append(m.DevIds, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field App", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Description", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3426,30 +6171,26 @@ func (m *DryDownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.App == nil {
-				m.App = &Application{}
-			}
-			if err := m.App.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Description = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Port", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ActivationConstraints", wireType)
 			}
-			m.Port = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3459,66 +6200,55 @@ func (m *DryDownlinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Port |= (uint32(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipHandler(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			if (iNdEx + skippy) > l {
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *DryUplinkMessage) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowHandler
+			m.ActivationConstraints = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: DryUplinkMessage: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DryUplinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3528,28 +6258,26 @@ func (m *DryUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
-			}
+			m.Tags = append(m.Tags, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 2:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field App", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Action", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3559,30 +6287,55 @@ func (m *DryUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.App == nil {
-				m.App = &Application{}
+			m.Action = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Set", wireType)
 			}
-			if err := m.App.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.Set = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 9:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Port", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DryRun", wireType)
 			}
-			m.Port = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3592,11 +6345,12 @@ func (m *DryUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Port |= (uint32(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.DryRun = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHandler(dAtA[iNdEx:])
@@ -3618,7 +6372,7 @@ func (m *DryUplinkMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SimulatedUplinkMessage) Unmarshal(dAtA []byte) error {
+func (m *BulkDevicesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3641,75 +6395,17 @@ func (m *SimulatedUplinkMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SimulatedUplinkMessage: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SimulatedUplinkMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BulkDevicesResponse: wiretype end group for non-group")
 		}
-		switch fieldNum {
-		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowHandler
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.AppId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowHandler
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthHandler
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.DevId = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BulkDevicesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DevIds", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3719,42 +6415,21 @@ func (m *SimulatedUplinkMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
-			}
+			m.DevIds = append(m.DevIds, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Port", wireType)
-			}
-			m.Port = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowHandler
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Port |= (uint32(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHandler(dAtA[iNdEx:])
@@ -3776,7 +6451,7 @@ func (m *SimulatedUplinkMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LogEntry) Unmarshal(dAtA []byte) error {
+func (m *JoinCryptoRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3799,15 +6474,15 @@ func (m *LogEntry) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LogEntry: wiretype end group for non-group")
+			return fmt.Errorf("proto: JoinCryptoRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LogEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: JoinCryptoRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Function", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3832,11 +6507,11 @@ func (m *LogEntry) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Function = string(dAtA[iNdEx:postIndex])
+			m.AppId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DevId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3861,61 +6536,42 @@ func (m *LogEntry) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Fields = append(m.Fields, string(dAtA[iNdEx:postIndex]))
+			m.DevId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipHandler(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthHandler
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppEui", wireType)
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *DryUplinkResult) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowHandler
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
 			}
-			if iNdEx >= l {
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			m.AppEui = append(m.AppEui[:0], dAtA[iNdEx:postIndex]...)
+			if m.AppEui == nil {
+				m.AppEui = []byte{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: DryUplinkResult: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DryUplinkResult: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DevEui", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -3939,16 +6595,16 @@ func (m *DryUplinkResult) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
+			m.DevEui = append(m.DevEui[:0], dAtA[iNdEx:postIndex]...)
+			if m.DevEui == nil {
+				m.DevEui = []byte{}
 			}
 			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3958,26 +6614,28 @@ func (m *DryUplinkResult) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Fields = string(dAtA[iNdEx:postIndex])
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Valid", wireType)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DevNonce", wireType)
 			}
-			var v int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -3987,17 +6645,28 @@ func (m *DryUplinkResult) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Valid = bool(v != 0)
-		case 4:
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DevNonce = append(m.DevNonce[:0], dAtA[iNdEx:postIndex]...)
+			if m.DevNonce == nil {
+				m.DevNonce = []byte{}
+			}
+			iNdEx = postIndex
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Logs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AppKey", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -4007,23 +6676,43 @@ func (m *DryUplinkResult) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Logs = append(m.Logs, &LogEntry{})
-			if err := m.Logs[len(m.Logs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.AppKey = append(m.AppKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.AppKey == nil {
+				m.AppKey = []byte{}
 			}
 			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndToEnd", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.EndToEnd = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHandler(dAtA[iNdEx:])
@@ -4045,7 +6734,7 @@ func (m *DryUplinkResult) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DryDownlinkResult) Unmarshal(dAtA []byte) error {
+func (m *JoinCryptoResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4068,13 +6757,44 @@ func (m *DryDownlinkResult) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DryDownlinkResult: wiretype end group for non-group")
+			return fmt.Errorf("proto: JoinCryptoResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DryDownlinkResult: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: JoinCryptoResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mic", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mic = append(m.Mic[:0], dAtA[iNdEx:postIndex]...)
+			if m.Mic == nil {
+				m.Mic = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
 			}
@@ -4105,11 +6825,11 @@ func (m *DryDownlinkResult) Unmarshal(dAtA []byte) error {
 				m.Payload = []byte{}
 			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Logs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AppSKey", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowHandler
@@ -4119,21 +6839,52 @@ func (m *DryDownlinkResult) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthHandler
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Logs = append(m.Logs, &LogEntry{})
-			if err := m.Logs[len(m.Logs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.AppSKey = append(m.AppSKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.AppSKey == nil {
+				m.AppSKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NwkSKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHandler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthHandler
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NwkSKey = append(m.NwkSKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.NwkSKey == nil {
+				m.NwkSKey = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -4267,83 +7018,117 @@ func init() {
 }
 
 var fileDescriptorHandler = []byte{
-	// 1248 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x57, 0xdb, 0x6e, 0xdb, 0x46,
-	0x13, 0xfe, 0xe9, 0x83, 0x2c, 0x8d, 0x6c, 0x39, 0x5e, 0x27, 0xfa, 0x19, 0x39, 0x50, 0x54, 0x06,
-	0x49, 0x1d, 0x27, 0xa0, 0x50, 0xb5, 0x40, 0x93, 0x5c, 0xa4, 0x39, 0x38, 0x07, 0x03, 0x71, 0x0b,
-	0xd0, 0xee, 0x8d, 0x2f, 0x6a, 0xac, 0xc5, 0x31, 0x45, 0x98, 0xe2, 0xb2, 0xe4, 0x4a, 0x86, 0x10,
-	0xa4, 0x28, 0xf2, 0x0a, 0x6d, 0xdf, 0xa0, 0x77, 0x7d, 0x8e, 0x02, 0xbd, 0x2c, 0xd0, 0x07, 0x68,
-	0xe1, 0xf6, 0x09, 0xfa, 0x04, 0x05, 0x77, 0x97, 0x07, 0x9d, 0x6c, 0xab, 0xe8, 0x8d, 0xa4, 0x99,
-	0xef, 0xe3, 0x9c, 0x76, 0x66, 0x96, 0x82, 0x87, 0x8e, 0xcb, 0x3b, 0xbd, 0x23, 0xb3, 0xcd, 0xba,
-	0xcd, 0xfd, 0x0e, 0xee, 0x77, 0x5c, 0xdf, 0x89, 0x3e, 0x47, 0x7e, 0xca, 0xc2, 0x93, 0x26, 0xe7,
-	0x7e, 0x93, 0x06, 0x6e, 0xb3, 0x43, 0x7d, 0xdb, 0xc3, 0x30, 0xf9, 0x36, 0x83, 0x90, 0x71, 0x46,
-	0x96, 0x94, 0x58, 0xdb, 0x70, 0x18, 0x73, 0x3c, 0x6c, 0x0a, 0xf5, 0x51, 0xef, 0xb8, 0x89, 0xdd,
-	0x80, 0x0f, 0x24, 0xab, 0x76, 0x43, 0x81, 0xb1, 0x1d, 0xea, 0xfb, 0x8c, 0x53, 0xee, 0x32, 0x3f,
-	0x52, 0xe8, 0x5a, 0xe2, 0x82, 0x06, 0xae, 0x52, 0x6d, 0x24, 0xaa, 0xa3, 0x90, 0x9d, 0x60, 0xa8,
-	0xbe, 0x14, 0x78, 0x33, 0x01, 0x85, 0xd8, 0x66, 0x5e, 0xfa, 0x43, 0x11, 0x6e, 0x8f, 0x11, 0x3c,
-	0x16, 0xd2, 0x53, 0xea, 0x37, 0x6d, 0xec, 0xbb, 0x6d, 0x54, 0xb4, 0xeb, 0x09, 0x8d, 0x87, 0xb4,
-	0x8d, 0xf2, 0x53, 0x42, 0xc6, 0x0f, 0x73, 0xa0, 0x6f, 0x0b, 0xee, 0xd3, 0x36, 0x77, 0xfb, 0x22,
-	0x5c, 0x0b, 0xa3, 0x80, 0xf9, 0x11, 0x12, 0x1d, 0x96, 0x02, 0x3a, 0xf0, 0x18, 0xb5, 0x75, 0xad,
-	0xa1, 0x6d, 0x2e, 0x5b, 0x89, 0x48, 0xee, 0xc1, 0x52, 0x17, 0xa3, 0x88, 0x3a, 0xa8, 0xcf, 0x35,
-	0xb4, 0xcd, 0x72, 0x6b, 0xcd, 0x4c, 0x43, 0xdb, 0x95, 0x80, 0x95, 0x30, 0xc8, 0x67, 0xb0, 0x6a,
-	0xb3, 0x53, 0xdf, 0x73, 0xfd, 0x93, 0x43, 0x16, 0xc4, 0x1e, 0xf4, 0xb2, 0x78, 0xa8, 0x6a, 0xaa,
-	0x74, 0xb7, 0x15, 0xfc, 0x85, 0x40, 0xad, 0x8a, 0x3d, 0x24, 0x93, 0x5d, 0x58, 0xa7, 0x69, 0x74,
-	0x87, 0x5d, 0xe4, 0xd4, 0xa6, 0x9c, 0xea, 0xff, 0x17, 0x46, 0x6e, 0x64, 0x9e, 0xb3, 0x14, 0x76,
-	0x15, 0xc7, 0x22, 0x74, 0x4c, 0x47, 0x0c, 0x58, 0x14, 0x25, 0xd0, 0x6f, 0x0a, 0x03, 0xcb, 0xa6,
-	0x2c, 0xc8, 0x7e, 0xfc, 0x69, 0x49, 0xc8, 0x58, 0x85, 0x95, 0x3d, 0x4e, 0x79, 0x2f, 0xb2, 0xf0,
-	0xeb, 0x1e, 0x46, 0xdc, 0xf8, 0x5d, 0x83, 0x82, 0xd4, 0x90, 0x4d, 0x28, 0x44, 0x83, 0x88, 0x63,
-	0x57, 0x54, 0xa5, 0xdc, 0xba, 0x62, 0xc6, 0xe7, 0xb9, 0x27, 0x54, 0x31, 0x25, 0xb2, 0x14, 0x4e,
-	0x3e, 0x82, 0x52, 0x9b, 0x75, 0x03, 0xe6, 0xa3, 0xcf, 0x55, 0xa1, 0xd6, 0x05, 0xf9, 0x79, 0xa2,
-	0x95, 0xfc, 0x8c, 0x45, 0x0c, 0x28, 0xf4, 0x82, 0x38, 0x77, 0x55, 0x23, 0x10, 0x7c, 0x8b, 0x72,
-	0x8c, 0x2c, 0x85, 0x90, 0x3b, 0x50, 0x4c, 0x2a, 0xa4, 0x2f, 0x8f, 0xb1, 0x52, 0x8c, 0xdc, 0x87,
-	0x72, 0x96, 0x7e, 0xa4, 0xaf, 0x8c, 0x51, 0xf3, 0xb0, 0x61, 0xc2, 0xb5, 0xa7, 0x41, 0xe0, 0xb9,
-	0x6d, 0x21, 0xef, 0xd8, 0xe8, 0x73, 0xf7, 0xd8, 0xc5, 0x90, 0x5c, 0x83, 0x02, 0x0d, 0x82, 0x43,
-	0x57, 0x76, 0x41, 0xc9, 0x5a, 0xa4, 0x41, 0xb0, 0x63, 0x1b, 0xdf, 0x6b, 0x50, 0xce, 0x3d, 0x30,
-	0x85, 0x16, 0x37, 0x91, 0x8d, 0x6d, 0x66, 0x63, 0x28, 0x2a, 0x50, 0xb2, 0x12, 0x91, 0xdc, 0x88,
-	0xab, 0xe3, 0xf7, 0x31, 0xe4, 0x18, 0xea, 0xf3, 0x02, 0xcb, 0x14, 0x31, 0xda, 0xa7, 0x9e, 0x6b,
-	0x53, 0xce, 0x42, 0x7d, 0x41, 0xa2, 0xa9, 0x22, 0xb6, 0x8a, 0xbe, 0xb4, 0xba, 0x28, 0xad, 0x2a,
-	0xd1, 0x78, 0x02, 0x57, 0x64, 0x43, 0x5f, 0x98, 0x41, 0xac, 0xb6, 0xb1, 0x1f, 0xab, 0x65, 0x64,
-	0x8b, 0x36, 0xf6, 0x77, 0x6c, 0xe3, 0x6f, 0x0d, 0x0a, 0xd2, 0xc4, 0x6c, 0x0f, 0x92, 0x07, 0x50,
-	0x51, 0xf3, 0x77, 0x28, 0xe7, 0x4f, 0x64, 0x55, 0x6e, 0xad, 0x9a, 0x4a, 0x6d, 0x4a, 0xb3, 0xaf,
-	0xff, 0x67, 0xad, 0x28, 0x8d, 0xf2, 0x53, 0x83, 0xa2, 0x47, 0xb9, 0xcb, 0x7b, 0x36, 0xea, 0xd0,
-	0xd0, 0x36, 0xe7, 0xac, 0x54, 0x8e, 0x0b, 0xe1, 0x31, 0xdf, 0x91, 0x60, 0x59, 0x80, 0x99, 0x22,
-	0x7e, 0x92, 0x7a, 0xea, 0xc9, 0xb8, 0x17, 0x16, 0xad, 0x54, 0x26, 0x0d, 0x28, 0xdb, 0x18, 0xb5,
-	0x43, 0x57, 0x0e, 0xdd, 0x55, 0x11, 0x6b, 0x5e, 0xf5, 0xac, 0x28, 0x12, 0x71, 0xdb, 0x68, 0x7c,
-	0x0a, 0x20, 0x63, 0x79, 0xe3, 0x46, 0x9c, 0xdc, 0x8d, 0x0f, 0x2d, 0x96, 0x22, 0x5d, 0x6b, 0xcc,
-	0x8b, 0x14, 0x92, 0x75, 0x28, 0x59, 0x56, 0x82, 0x1b, 0xef, 0x35, 0x20, 0xdb, 0xe1, 0x20, 0x19,
-	0x61, 0x35, 0xfd, 0xe7, 0xec, 0x8e, 0x2a, 0x14, 0x8e, 0x5d, 0xf4, 0xec, 0x48, 0x15, 0x4f, 0x49,
-	0xe4, 0x0e, 0xcc, 0xd3, 0x20, 0x50, 0x25, 0xbb, 0x9a, 0xfa, 0xcb, 0xb5, 0x98, 0x15, 0x13, 0x08,
-	0x81, 0x85, 0x80, 0x85, 0x5c, 0xf4, 0xc4, 0x8a, 0x25, 0x7e, 0x1b, 0x1d, 0xb8, 0xb2, 0x1d, 0x0e,
-	0xbe, 0x0c, 0x2e, 0x17, 0x81, 0xf2, 0x34, 0x77, 0x59, 0x4f, 0xf3, 0x39, 0x4f, 0x1c, 0xaa, 0x7b,
-	0x6e, 0xb7, 0xe7, 0x51, 0x8e, 0xf6, 0xb0, 0xbf, 0xd9, 0x7a, 0x25, 0x17, 0xdd, 0xfc, 0x70, 0x74,
-	0x93, 0xf2, 0x7b, 0x0c, 0xc5, 0x37, 0xcc, 0x79, 0xe1, 0xf3, 0x70, 0x10, 0x9f, 0xf8, 0x71, 0xcf,
-	0x6f, 0x8b, 0x23, 0x95, 0x9e, 0x52, 0x79, 0xa8, 0xb6, 0xf3, 0x59, 0x6d, 0x8d, 0x6f, 0x35, 0x58,
-	0x4d, 0x0b, 0x64, 0x61, 0xd4, 0xf3, 0xf8, 0xbf, 0x38, 0xa1, 0xab, 0xb0, 0x28, 0x26, 0x50, 0x44,
-	0x5c, 0xb4, 0xa4, 0x40, 0x6e, 0xc3, 0x82, 0xc7, 0x9c, 0x48, 0x5f, 0x10, 0x8d, 0xb2, 0x96, 0x96,
-	0x33, 0x09, 0xd8, 0x12, 0xb0, 0xb1, 0x0f, 0x6b, 0xb9, 0x36, 0xb9, 0x30, 0x86, 0xc4, 0xea, 0xdc,
-	0xb9, 0x56, 0x5b, 0x3f, 0x6b, 0xb0, 0xf4, 0x5a, 0x42, 0xe4, 0x2b, 0x58, 0xcf, 0x6e, 0x80, 0xe7,
-	0x1d, 0xea, 0x79, 0xe8, 0x3b, 0x48, 0x8c, 0xe4, 0x96, 0x99, 0x00, 0xaa, 0xed, 0x5e, 0xbb, 0x75,
-	0x2e, 0x47, 0x5d, 0x87, 0x07, 0x50, 0x54, 0x30, 0x92, 0x7b, 0xe9, 0xd5, 0x85, 0x76, 0x4f, 0xb6,
-	0x0d, 0xda, 0xe3, 0x17, 0xa9, 0xb4, 0xfe, 0xc1, 0xc8, 0xf0, 0x8c, 0x5f, 0xb5, 0xad, 0x3f, 0x4b,
-	0x40, 0x72, 0xfd, 0xb7, 0x4b, 0x7d, 0xea, 0x60, 0x48, 0x1c, 0x58, 0xb7, 0xd0, 0x71, 0x23, 0x8e,
-	0x61, 0x7e, 0xd5, 0xd6, 0x27, 0xf5, 0x6c, 0xb6, 0xef, 0x6a, 0x55, 0x53, 0xbe, 0x87, 0x98, 0xc9,
-	0x4b, 0x8a, 0xf9, 0x22, 0x7e, 0x49, 0x31, 0xf4, 0xf7, 0xbf, 0xfd, 0xf5, 0xdd, 0x1c, 0x31, 0x56,
-	0x9a, 0x34, 0x7b, 0x2e, 0x7a, 0xa4, 0x6d, 0x91, 0x63, 0xa8, 0xbc, 0x42, 0x3e, 0x8b, 0x8f, 0x89,
-	0x73, 0x63, 0xd4, 0x85, 0x07, 0x9d, 0x54, 0x87, 0x3c, 0x34, 0xdf, 0xca, 0xc9, 0x78, 0x47, 0xbe,
-	0x81, 0xca, 0xde, 0xb0, 0x9f, 0x89, 0x76, 0xa6, 0x66, 0xf0, 0x58, 0xd8, 0x7f, 0x60, 0x4c, 0xb1,
-	0xff, 0x48, 0xdb, 0x3a, 0xd8, 0xa8, 0x4d, 0x07, 0xc9, 0x09, 0xac, 0x6d, 0xa3, 0x87, 0x1c, 0xff,
-	0x8b, 0x72, 0xaa, 0x64, 0xb7, 0xa6, 0x25, 0xdb, 0x81, 0xd2, 0x2b, 0xe4, 0x6a, 0xc5, 0x5f, 0x1f,
-	0x69, 0x82, 0x9c, 0xfd, 0xd1, 0xe5, 0x6a, 0x34, 0x85, 0xe1, 0xbb, 0xe4, 0xc3, 0xc9, 0x86, 0xd5,
-	0xdb, 0x5d, 0xd4, 0x7c, 0x2b, 0x37, 0xcb, 0x3b, 0x72, 0xa6, 0x41, 0x69, 0x2f, 0x75, 0x35, 0x6a,
-	0x6f, 0x6a, 0x02, 0x3f, 0x69, 0xc2, 0xd1, 0x8f, 0x9a, 0x71, 0x59, 0x4f, 0x71, 0x81, 0xef, 0xd7,
-	0x66, 0x61, 0xdf, 0x32, 0xea, 0xe7, 0xb3, 0x05, 0xa9, 0x76, 0x31, 0x89, 0x84, 0xb0, 0x2c, 0xcf,
-	0xee, 0xe2, 0x8a, 0x4e, 0x4b, 0x58, 0x15, 0x76, 0xeb, 0xd2, 0x85, 0x3d, 0x05, 0x3d, 0x3d, 0xc2,
-	0xe8, 0x25, 0x9b, 0x69, 0x0a, 0xd7, 0x47, 0xe2, 0x8b, 0x6f, 0x56, 0xe3, 0x8e, 0x88, 0xa0, 0x41,
-	0x2e, 0xc8, 0x97, 0xbc, 0x84, 0x72, 0x6e, 0x5d, 0x92, 0x8d, 0xcc, 0xd6, 0xd8, 0x5d, 0x5b, 0xab,
-	0x4d, 0x02, 0xd5, 0x86, 0x7d, 0x02, 0xa5, 0x74, 0xf1, 0xe7, 0x2b, 0x36, 0x72, 0x5b, 0xd6, 0xf4,
-	0x71, 0x48, 0x59, 0xd8, 0x81, 0x4a, 0x72, 0xe3, 0x29, 0x33, 0x37, 0x53, 0xee, 0xe4, 0xab, 0x70,
-	0x5a, 0xf9, 0x5b, 0x2f, 0xa1, 0xa2, 0x96, 0x75, 0xb2, 0xe0, 0x3e, 0x11, 0x23, 0xa2, 0x5e, 0xac,
-	0xab, 0x99, 0xdd, 0xfc, 0xbb, 0x77, 0x6e, 0x3e, 0xa4, 0xfe, 0xd9, 0xc3, 0x5f, 0xce, 0xea, 0xda,
-	0xaf, 0x67, 0x75, 0xed, 0x8f, 0xb3, 0xba, 0x76, 0x70, 0x6f, 0x86, 0x7f, 0x75, 0x47, 0x05, 0x11,
-	0xd2, 0xc7, 0xff, 0x04, 0x00, 0x00, 0xff, 0xff, 0xdc, 0xb0, 0x50, 0xc3, 0x0b, 0x0e, 0x00, 0x00,
+	// 1780 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x58, 0x4b, 0x6f, 0x24, 0x57,
+	0x15, 0xa6, 0xba, 0xdd, 0xaf, 0xd3, 0x7e, 0x5e, 0x7b, 0x3c, 0x95, 0xb2, 0xe5, 0x31, 0x15, 0x65,
+	0x98, 0x78, 0x86, 0x6e, 0x30, 0x20, 0x92, 0x11, 0x9a, 0xc4, 0x63, 0x3b, 0x19, 0xc3, 0x38, 0x48,
+	0x65, 0xb3, 0x60, 0x16, 0xb4, 0xee, 0x54, 0x5d, 0x97, 0x4b, 0x5d, 0x7d, 0x6f, 0xa5, 0xea, 0x56,
+	0x9b, 0x56, 0x14, 0x84, 0xb2, 0x64, 0x0b, 0x6c, 0x59, 0xb1, 0xcb, 0xef, 0x40, 0x62, 0x89, 0xc4,
+	0x0f, 0x08, 0x58, 0xfc, 0x02, 0x7e, 0x01, 0xba, 0x8f, 0x7a, 0xf4, 0xcb, 0x8f, 0x88, 0x8d, 0x5d,
+	0xe7, 0x51, 0xe7, 0xf1, 0x9d, 0x73, 0xcf, 0x3d, 0x5d, 0xf0, 0xa1, 0x1f, 0xf0, 0xcb, 0xf4, 0x6d,
+	0xc7, 0x65, 0x83, 0xee, 0xf9, 0x25, 0x39, 0xbf, 0x0c, 0xa8, 0x9f, 0x7c, 0x46, 0xf8, 0x15, 0x8b,
+	0xfb, 0x5d, 0xce, 0x69, 0x17, 0x47, 0x41, 0xf7, 0x12, 0x53, 0x2f, 0x24, 0x71, 0xf6, 0xbf, 0x13,
+	0xc5, 0x8c, 0x33, 0xd4, 0xd0, 0xa4, 0xb5, 0xe5, 0x33, 0xe6, 0x87, 0xa4, 0x2b, 0xd9, 0x6f, 0xd3,
+	0x8b, 0x2e, 0x19, 0x44, 0x7c, 0xa4, 0xb4, 0xac, 0x6d, 0x2d, 0x14, 0x76, 0x30, 0xa5, 0x8c, 0x63,
+	0x1e, 0x30, 0x9a, 0x68, 0xe9, 0x5a, 0xe6, 0x02, 0x47, 0x81, 0x66, 0x6d, 0x65, 0xac, 0xb7, 0x31,
+	0xeb, 0x93, 0x58, 0xff, 0xd3, 0xc2, 0x47, 0x99, 0x50, 0x92, 0x2e, 0x0b, 0xf3, 0x07, 0xad, 0xf0,
+	0xde, 0x94, 0x42, 0xc8, 0x62, 0x7c, 0x85, 0x69, 0xd7, 0x23, 0xc3, 0xc0, 0x25, 0x5a, 0xed, 0x9d,
+	0x4c, 0x8d, 0xc7, 0xd8, 0x25, 0xea, 0xaf, 0x12, 0xd9, 0x7f, 0xae, 0x80, 0x79, 0x24, 0x75, 0x0f,
+	0x5c, 0x1e, 0x0c, 0x65, 0xb8, 0x0e, 0x49, 0x22, 0x46, 0x13, 0x82, 0x4c, 0x68, 0x44, 0x78, 0x14,
+	0x32, 0xec, 0x99, 0xc6, 0xae, 0xf1, 0x64, 0xd1, 0xc9, 0x48, 0xf4, 0x14, 0x1a, 0x03, 0x92, 0x24,
+	0xd8, 0x27, 0x66, 0x65, 0xd7, 0x78, 0xd2, 0xde, 0x5f, 0xeb, 0xe4, 0xa1, 0x9d, 0x2a, 0x81, 0x93,
+	0x69, 0xa0, 0x8f, 0x60, 0xc5, 0x63, 0x57, 0x34, 0x0c, 0x68, 0xbf, 0xc7, 0x22, 0xe1, 0xc1, 0x6c,
+	0xcb, 0x97, 0x36, 0x3b, 0x3a, 0xdd, 0x23, 0x2d, 0xfe, 0xa5, 0x94, 0x3a, 0xcb, 0xde, 0x18, 0x8d,
+	0x4e, 0x61, 0x1d, 0xe7, 0xd1, 0xf5, 0x06, 0x84, 0x63, 0x0f, 0x73, 0x6c, 0x3e, 0x94, 0x46, 0xb6,
+	0x0b, 0xcf, 0x45, 0x0a, 0xa7, 0x5a, 0xc7, 0x41, 0x78, 0x8a, 0x87, 0x6c, 0xa8, 0x49, 0x08, 0xcc,
+	0x47, 0xd2, 0xc0, 0x62, 0x47, 0x52, 0x9d, 0x73, 0xf1, 0xd7, 0x51, 0x22, 0x7b, 0x05, 0x96, 0xce,
+	0x38, 0xe6, 0x69, 0xe2, 0x90, 0xcf, 0x53, 0x92, 0x70, 0xfb, 0x1b, 0x03, 0xea, 0x8a, 0x83, 0x9e,
+	0x40, 0x3d, 0x19, 0x25, 0x9c, 0x0c, 0x24, 0x2a, 0xed, 0xfd, 0xd5, 0x8e, 0xa8, 0xe7, 0x99, 0x64,
+	0x09, 0x95, 0xc4, 0xd1, 0x72, 0xf4, 0x43, 0x68, 0xb9, 0x6c, 0x10, 0x31, 0x4a, 0x28, 0xd7, 0x40,
+	0xad, 0x4b, 0xe5, 0xc3, 0x8c, 0xab, 0xf4, 0x0b, 0x2d, 0x64, 0x43, 0x3d, 0x8d, 0x44, 0xee, 0x1a,
+	0x23, 0x90, 0xfa, 0x0e, 0xe6, 0x24, 0x71, 0xb4, 0x04, 0x3d, 0x86, 0x66, 0x86, 0x90, 0xb9, 0x38,
+	0xa5, 0x95, 0xcb, 0xd0, 0x33, 0x68, 0x17, 0xe9, 0x27, 0xe6, 0xd2, 0x94, 0x6a, 0x59, 0x6c, 0x77,
+	0xe0, 0xc1, 0x41, 0x14, 0x85, 0x81, 0x2b, 0xe9, 0x13, 0x8f, 0x50, 0x1e, 0x5c, 0x04, 0x24, 0x46,
+	0x0f, 0xa0, 0x8e, 0xa3, 0xa8, 0x17, 0xa8, 0x2e, 0x68, 0x39, 0x35, 0x1c, 0x45, 0x27, 0x9e, 0xfd,
+	0x27, 0x03, 0xda, 0xa5, 0x17, 0xe6, 0xa8, 0x89, 0x26, 0xf2, 0x88, 0xcb, 0x3c, 0x12, 0x4b, 0x04,
+	0x5a, 0x4e, 0x46, 0xa2, 0x6d, 0x81, 0x0e, 0x1d, 0x92, 0x98, 0x93, 0xd8, 0xac, 0x4a, 0x59, 0xc1,
+	0x10, 0xd2, 0x21, 0x0e, 0x03, 0x0f, 0x73, 0x16, 0x9b, 0x0b, 0x4a, 0x9a, 0x33, 0x84, 0x55, 0x42,
+	0x95, 0xd5, 0x9a, 0xb2, 0xaa, 0x49, 0xfb, 0x63, 0x58, 0x55, 0x0d, 0x7d, 0x6b, 0x06, 0x82, 0xed,
+	0x91, 0xa1, 0x60, 0xab, 0xc8, 0x6a, 0x1e, 0x19, 0x9e, 0x78, 0xf6, 0x7f, 0x0d, 0xa8, 0x2b, 0x13,
+	0xf7, 0x7b, 0x11, 0x7d, 0x00, 0xcb, 0xfa, 0xfc, 0xf5, 0xd4, 0xf9, 0x93, 0x59, 0xb5, 0xf7, 0x57,
+	0x3a, 0x9a, 0xdd, 0x51, 0x66, 0x5f, 0x7d, 0xc7, 0x59, 0xd2, 0x1c, 0xed, 0xc7, 0x82, 0x66, 0x88,
+	0x79, 0xc0, 0x53, 0x8f, 0x98, 0xb0, 0x6b, 0x3c, 0xa9, 0x38, 0x39, 0x2d, 0x80, 0x08, 0x19, 0xf5,
+	0x95, 0xb0, 0x2d, 0x85, 0x05, 0x43, 0xbc, 0x89, 0x43, 0xfd, 0xa6, 0xe8, 0x85, 0x9a, 0x93, 0xd3,
+	0x68, 0x17, 0xda, 0x1e, 0x49, 0xdc, 0x38, 0x50, 0x87, 0x6e, 0x43, 0xc6, 0x5a, 0x66, 0xbd, 0x6c,
+	0xca, 0x44, 0x02, 0x97, 0xd8, 0x3f, 0x05, 0x50, 0xb1, 0xbc, 0x0e, 0x12, 0x8e, 0xde, 0x17, 0x45,
+	0x13, 0x54, 0x62, 0x1a, 0xbb, 0x55, 0x99, 0x42, 0x36, 0x0e, 0x95, 0x96, 0x93, 0xc9, 0xed, 0xaf,
+	0x0c, 0x40, 0x47, 0xf1, 0x28, 0x3b, 0xc2, 0xfa, 0xf4, 0xdf, 0x30, 0x3b, 0x36, 0xa1, 0x7e, 0x11,
+	0x90, 0xd0, 0x4b, 0x34, 0x78, 0x9a, 0x42, 0x8f, 0xa1, 0x8a, 0xa3, 0x48, 0x43, 0xb6, 0x91, 0xfb,
+	0x2b, 0xb5, 0x98, 0x23, 0x14, 0x10, 0x82, 0x85, 0x88, 0xc5, 0x5c, 0xf6, 0xc4, 0x92, 0x23, 0x9f,
+	0xed, 0x4b, 0x58, 0x3d, 0x8a, 0x47, 0xbf, 0x8a, 0xee, 0x16, 0x81, 0xf6, 0x54, 0xb9, 0xab, 0xa7,
+	0x6a, 0xc9, 0x13, 0x87, 0xcd, 0xb3, 0x60, 0x90, 0x86, 0x98, 0x13, 0x6f, 0xdc, 0xdf, 0xfd, 0x7a,
+	0xa5, 0x14, 0x5d, 0x75, 0x3c, 0xba, 0x59, 0xf9, 0xbd, 0x80, 0xe6, 0x6b, 0xe6, 0x1f, 0x53, 0x1e,
+	0x8f, 0x44, 0xc5, 0x2f, 0x52, 0xea, 0xca, 0x92, 0x2a, 0x4f, 0x39, 0x3d, 0x86, 0x6d, 0xb5, 0xc0,
+	0xd6, 0xfe, 0xbd, 0x01, 0x2b, 0x39, 0x40, 0x0e, 0x49, 0xd2, 0x90, 0x7f, 0x8b, 0x0a, 0x6d, 0x40,
+	0x4d, 0x9e, 0x40, 0x19, 0x71, 0xd3, 0x51, 0x04, 0x7a, 0x0f, 0x16, 0x42, 0xe6, 0x27, 0xe6, 0x82,
+	0x6c, 0x94, 0xb5, 0x1c, 0xce, 0x2c, 0x60, 0x47, 0x8a, 0xed, 0x73, 0x58, 0x2b, 0xb5, 0xc9, 0xad,
+	0x31, 0x64, 0x56, 0x2b, 0x37, 0x5b, 0xfd, 0x8b, 0x01, 0x4b, 0xc7, 0x43, 0x42, 0x79, 0x36, 0xa8,
+	0xe7, 0x95, 0x61, 0x03, 0x6a, 0xf8, 0x82, 0xe7, 0x43, 0x48, 0x11, 0x82, 0x1b, 0x06, 0x83, 0x40,
+	0x95, 0xb8, 0xea, 0x28, 0x42, 0x70, 0xfd, 0x98, 0xa5, 0x91, 0x1e, 0x3b, 0x8a, 0x10, 0xb8, 0xbb,
+	0x8c, 0x26, 0xe9, 0x20, 0x9f, 0x39, 0x39, 0x2d, 0xf3, 0x20, 0xd4, 0x0b, 0xa8, 0x6f, 0xd6, 0x25,
+	0x36, 0x19, 0x69, 0x5f, 0x40, 0xfb, 0x8c, 0xc7, 0x04, 0x0f, 0x64, 0x94, 0x02, 0x5a, 0x37, 0x8d,
+	0x13, 0x16, 0xeb, 0xe8, 0x34, 0x35, 0xaf, 0x4b, 0x36, 0xa0, 0x46, 0xc4, 0x7b, 0x7a, 0x3c, 0x2a,
+	0x42, 0x74, 0x88, 0xbc, 0x00, 0x55, 0x78, 0xf2, 0xd9, 0x7e, 0x01, 0xcb, 0x19, 0x0e, 0xfa, 0xf6,
+	0x7e, 0x06, 0x75, 0xa9, 0x9e, 0x1d, 0xe1, 0xa2, 0xd1, 0x4b, 0x01, 0x39, 0x5a, 0xc7, 0xfe, 0x35,
+	0xac, 0x1e, 0xb8, 0xfd, 0xbb, 0x42, 0xa9, 0xe0, 0xa9, 0x94, 0xe1, 0x31, 0xa1, 0xa1, 0x72, 0x49,
+	0xcc, 0xaa, 0xec, 0xbd, 0x8c, 0xb4, 0xff, 0x50, 0x01, 0xf4, 0x32, 0x0d, 0xfb, 0x6a, 0x72, 0xdc,
+	0x66, 0xfd, 0xa1, 0x1c, 0x3d, 0xbd, 0xa0, 0xe8, 0x61, 0x09, 0x45, 0x32, 0x39, 0xcd, 0xaa, 0x53,
+	0xd3, 0x0c, 0xfd, 0x04, 0x36, 0x4b, 0x7b, 0x82, 0x28, 0x0e, 0x8f, 0x71, 0x20, 0x10, 0x50, 0x48,
+	0x3d, 0x28, 0xa4, 0x87, 0x85, 0x50, 0xe4, 0xf3, 0x79, 0x4a, 0xe2, 0x91, 0xae, 0xaa, 0x22, 0x04,
+	0xc8, 0x1c, 0xfb, 0x89, 0x59, 0x97, 0x41, 0xc8, 0x67, 0x51, 0x3d, 0xac, 0x0e, 0x5e, 0x43, 0x55,
+	0x4f, 0x51, 0x68, 0x15, 0xaa, 0x09, 0xe1, 0x66, 0x53, 0x32, 0xc5, 0xa3, 0xcc, 0x22, 0x1e, 0xf5,
+	0xe2, 0x94, 0x9a, 0x2d, 0xd9, 0x10, 0x75, 0x2f, 0x1e, 0x39, 0x29, 0xb5, 0x3b, 0xb0, 0x3e, 0x86,
+	0x85, 0x2e, 0x56, 0x29, 0x6b, 0xa3, 0x9c, 0xb5, 0xfd, 0x6f, 0x03, 0xd6, 0x7e, 0xce, 0x02, 0x7a,
+	0x18, 0x8f, 0x22, 0xce, 0x6e, 0xc1, 0x6e, 0x4e, 0x17, 0x3d, 0x84, 0x86, 0xd0, 0x26, 0x69, 0xa0,
+	0x67, 0x8d, 0x78, 0xf9, 0x38, 0x0d, 0x32, 0xaf, 0x42, 0xb0, 0xa0, 0x04, 0x1e, 0x19, 0x0a, 0x41,
+	0xe9, 0x5c, 0xd6, 0xc6, 0xcf, 0xe5, 0x16, 0xb4, 0xc4, 0x2b, 0x94, 0x51, 0x97, 0xc8, 0x5e, 0x5f,
+	0x74, 0x9a, 0x1e, 0x19, 0x7e, 0x26, 0xe8, 0xcc, 0x51, 0x9f, 0x8c, 0xcc, 0x46, 0xee, 0xe8, 0x17,
+	0x64, 0x84, 0xb6, 0x01, 0x08, 0xf5, 0x7a, 0x9c, 0xf5, 0x08, 0xf5, 0x24, 0x4e, 0x4d, 0xa7, 0x49,
+	0xa8, 0x77, 0xce, 0x8e, 0xa9, 0x67, 0xff, 0x16, 0x50, 0x39, 0x45, 0x0d, 0xc9, 0x2a, 0x54, 0x07,
+	0x81, 0xab, 0xe7, 0x82, 0x78, 0x2c, 0x47, 0x55, 0x19, 0x8f, 0xca, 0x82, 0x96, 0x70, 0x9c, 0x48,
+	0xd7, 0x7a, 0x9e, 0xe2, 0x28, 0x3a, 0x13, 0xbe, 0x2d, 0x68, 0xd1, 0xab, 0xbe, 0x96, 0xa9, 0x34,
+	0x1b, 0xf4, 0xaa, 0x2f, 0x64, 0xfb, 0x7f, 0x33, 0xa0, 0xf1, 0x4a, 0x9d, 0x0a, 0xf4, 0x1b, 0x58,
+	0x2f, 0x16, 0xc8, 0xc3, 0x4b, 0x1c, 0x86, 0x84, 0xfa, 0x04, 0xd9, 0xd9, 0x92, 0x3a, 0x43, 0xa8,
+	0xcb, 0x61, 0xbd, 0x7b, 0xa3, 0x8e, 0xce, 0xe7, 0x0d, 0x34, 0xb5, 0x98, 0xa0, 0xa7, 0xf9, 0xe6,
+	0x4b, 0xbc, 0x54, 0xdd, 0x3a, 0xc4, 0x9b, 0xde, 0xc3, 0x95, 0xf5, 0xef, 0x4e, 0xdc, 0xbd, 0xd3,
+	0x9b, 0xfa, 0xfe, 0xd7, 0x6d, 0x40, 0xa5, 0xeb, 0xeb, 0x14, 0x53, 0xec, 0x93, 0x18, 0xf9, 0xb0,
+	0xee, 0x10, 0x3f, 0x48, 0x38, 0x89, 0x4b, 0x52, 0xb4, 0x33, 0xeb, 0xca, 0x2b, 0xd6, 0x25, 0x6b,
+	0xb3, 0xa3, 0x7e, 0xc6, 0x74, 0xb2, 0xdf, 0x38, 0x9d, 0x63, 0xf1, 0x1b, 0xc7, 0x36, 0xbf, 0xfa,
+	0xe7, 0x7f, 0xfe, 0x58, 0x41, 0xcf, 0x8d, 0x3d, 0x7b, 0xa9, 0x8b, 0x8b, 0x57, 0x13, 0x74, 0x01,
+	0xcb, 0x9f, 0x12, 0x7e, 0x1f, 0x1f, 0x33, 0xaf, 0x5d, 0x7b, 0x47, 0x7a, 0x30, 0xd1, 0xe6, 0x98,
+	0xf9, 0xee, 0x17, 0xaa, 0xd9, 0xbf, 0x44, 0xbf, 0x83, 0xe5, 0xb3, 0x71, 0x3f, 0x33, 0xed, 0xcc,
+	0xcd, 0xe0, 0x85, 0xb4, 0xff, 0xc1, 0x73, 0x63, 0xef, 0xcd, 0xd6, 0x73, 0x63, 0xcf, 0x9a, 0xe3,
+	0xc7, 0x9e, 0xe7, 0xbf, 0x0f, 0x6b, 0x47, 0x24, 0x24, 0x9c, 0xfc, 0x3f, 0xe0, 0xd4, 0xc9, 0xee,
+	0xcd, 0x73, 0x76, 0x09, 0xad, 0x4f, 0x09, 0xd7, 0x1b, 0xe2, 0x3b, 0x13, 0x4d, 0x50, 0xb2, 0x3f,
+	0xb9, 0x9b, 0xd9, 0x5d, 0x69, 0xf8, 0x7d, 0xf4, 0xbd, 0xd9, 0x86, 0xf5, 0x8f, 0xc3, 0xa4, 0xfb,
+	0x85, 0x1a, 0x16, 0x5f, 0xa2, 0x6b, 0x03, 0x5a, 0x67, 0xb9, 0xab, 0x49, 0x7b, 0x73, 0x13, 0xf8,
+	0xda, 0x90, 0x8e, 0xfe, 0x6a, 0x08, 0x3c, 0x9f, 0x09, 0x3c, 0xef, 0xea, 0xf1, 0xcd, 0xbb, 0xa2,
+	0x89, 0x76, 0x6e, 0xd6, 0x96, 0x4a, 0xd6, 0x2d, 0x4a, 0xf6, 0x9d, 0x93, 0x8c, 0x61, 0x51, 0xd5,
+	0xee, 0x76, 0x44, 0xe7, 0x25, 0xac, 0x81, 0xdd, 0xbb, 0xb3, 0xcf, 0x2b, 0x30, 0xf3, 0x12, 0x26,
+	0x9f, 0xb0, 0x7b, 0x9d, 0xc2, 0xf5, 0x89, 0xf8, 0xc4, 0x62, 0x6e, 0x3f, 0x96, 0x11, 0xec, 0xa2,
+	0x5b, 0x50, 0x41, 0x9f, 0x40, 0xbb, 0xb4, 0x6d, 0xa1, 0xad, 0xc2, 0xd6, 0xd4, 0xaa, 0x6e, 0x59,
+	0xb3, 0x84, 0x7a, 0x41, 0xfb, 0x18, 0x5a, 0xf9, 0xde, 0x58, 0x46, 0x6c, 0x62, 0xd9, 0xb6, 0xcc,
+	0x69, 0x91, 0xb6, 0x70, 0x02, 0xcb, 0xd9, 0xc2, 0xac, 0xcd, 0x3c, 0x2a, 0x16, 0x91, 0x99, 0x9b,
+	0xf4, 0x3c, 0xf8, 0xd1, 0xcf, 0xe4, 0x81, 0x50, 0x3b, 0x0a, 0xda, 0xcc, 0xad, 0x8c, 0x2d, 0x2d,
+	0xd6, 0xc3, 0x29, 0xbe, 0x9e, 0xbf, 0x2f, 0xa0, 0x95, 0x6f, 0x38, 0xa5, 0x54, 0x26, 0xb7, 0x9e,
+	0xb9, 0xde, 0x5f, 0x41, 0xbb, 0x74, 0x73, 0x97, 0x20, 0x9d, 0xde, 0x6d, 0xac, 0xed, 0xd9, 0x42,
+	0x3d, 0xad, 0x53, 0x58, 0xd6, 0x97, 0x4e, 0x36, 0xa8, 0x7f, 0x2c, 0x33, 0xd3, 0xdf, 0x17, 0x8a,
+	0xcc, 0xc6, 0x3e, 0x41, 0x58, 0x2b, 0x13, 0x7c, 0xf4, 0x7d, 0x68, 0x9e, 0xe3, 0x20, 0x7c, 0xcd,
+	0xfc, 0x04, 0xa9, 0x8f, 0x10, 0xe2, 0x31, 0x53, 0x5f, 0xca, 0x38, 0x72, 0x5f, 0xfe, 0x81, 0xb1,
+	0xff, 0x8d, 0x01, 0x50, 0xdc, 0xb3, 0xa2, 0x30, 0x82, 0xd2, 0xea, 0xa7, 0x27, 0x87, 0xa8, 0x68,
+	0x84, 0xa9, 0x8d, 0xc3, 0xda, 0x9a, 0x29, 0xd3, 0xd0, 0x1e, 0x2b, 0xc3, 0x07, 0xae, 0x4b, 0x22,
+	0xfe, 0xed, 0xcd, 0x7c, 0x24, 0xa7, 0xd0, 0x81, 0x5a, 0x19, 0x6e, 0xb2, 0x32, 0xa7, 0x44, 0x2f,
+	0x3f, 0xfc, 0xfb, 0xf5, 0x8e, 0xf1, 0x8f, 0xeb, 0x1d, 0xe3, 0x5f, 0xd7, 0x3b, 0xc6, 0x9b, 0xa7,
+	0xf7, 0xf8, 0xda, 0xf7, 0xb6, 0x2e, 0x4d, 0xfd, 0xe8, 0x7f, 0x03, 0x00, 0xeb, 0xa5, 0xb2, 0x1e,
+	0x23, 0x14, 0x00, 0x00,
 }
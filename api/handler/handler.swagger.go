@@ -0,0 +1,544 @@
+// Code generated from handler.swagger.json by make swagger
+// DO NOT EDIT!
+
+package handler
+
+// SwaggerJSON is the OpenAPI specification of the REST API of this package
+const SwaggerJSON = `
+{
+  "swagger": "2.0",
+  "info": {
+    "title": "github.com/TheThingsNetwork/ttn/api/handler/handler.proto",
+    "version": "version not set"
+  },
+  "schemes": [
+    "http",
+    "https"
+  ],
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "paths": {
+    "/applications": {
+      "post": {
+        "summary": "Applications should first be registered to the Handler with the \u0060RegisterApplication\u0060 method",
+        "operationId": "RegisterApplication",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/handlerApplicationIdentifier"
+            }
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      }
+    },
+    "/applications/{app_id}": {
+      "get": {
+        "summary": "GetApplication returns the application with the given identifier (app_id)",
+        "operationId": "GetApplication",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/handlerApplication"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      },
+      "delete": {
+        "summary": "DeleteApplication deletes the application with the given identifier (app_id)",
+        "operationId": "DeleteApplication",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      },
+      "post": {
+        "summary": "SetApplication updates the settings for the application. All fields must be supplied.",
+        "operationId": "SetApplication",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/handlerApplication"
+            }
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      },
+      "put": {
+        "summary": "SetApplication updates the settings for the application. All fields must be supplied.",
+        "operationId": "SetApplication2",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/handlerApplication"
+            }
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      }
+    },
+    "/applications/{app_id}/devices": {
+      "get": {
+        "summary": "GetDevicesForApplication returns all devices that belong to the application with the given identifier (app_id)",
+        "operationId": "GetDevicesForApplication",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/handlerDeviceList"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      },
+      "post": {
+        "summary": "SetDevice creates or updates a device. All fields must be supplied.",
+        "operationId": "SetDevice3",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/handlerDevice"
+            }
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      },
+      "put": {
+        "summary": "SetDevice creates or updates a device. All fields must be supplied.",
+        "operationId": "SetDevice4",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/handlerDevice"
+            }
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      }
+    },
+    "/applications/{app_id}/devices/{dev_id}": {
+      "get": {
+        "summary": "GetDevice returns the device with the given identifier (app_id and dev_id)",
+        "operationId": "GetDevice",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/handlerDevice"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      },
+      "delete": {
+        "summary": "DeleteDevice deletes the device with the given identifier (app_id and dev_id)",
+        "operationId": "DeleteDevice",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      },
+      "post": {
+        "summary": "SetDevice creates or updates a device. All fields must be supplied.",
+        "operationId": "SetDevice",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/handlerDevice"
+            }
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      },
+      "put": {
+        "summary": "SetDevice creates or updates a device. All fields must be supplied.",
+        "operationId": "SetDevice2",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/protobufEmpty"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/handlerDevice"
+            }
+          }
+        ],
+        "tags": [
+          "ApplicationManager"
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "handlerApplication": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "decoder": {
+          "type": "string",
+          "description": "The decoder is a JavaScript function that decodes a byte array to an object."
+        },
+        "converter": {
+          "type": "string",
+          "description": "The converter is a JavaScript function that can be used to convert values\nin the object returned from the decoder. This can for example be useful to\nconvert a voltage to a temperature."
+        },
+        "validator": {
+          "type": "string",
+          "description": "The validator is a JavaScript function that checks the validity of the\nobject returned by the decoder or converter. If validation fails, the\nmessage is dropped."
+        },
+        "encoder": {
+          "type": "string",
+          "description": "The encoder is a JavaScript function that encodes an object to a byte array."
+        }
+      },
+      "description": "The Application settings"
+    },
+    "handlerApplicationIdentifier": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        }
+      }
+    },
+    "handlerDevice": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "lorawan_device": {
+          "$ref": "#/definitions/lorawanDevice"
+        },
+        "latitude": {
+          "type": "number",
+          "format": "float"
+        },
+        "longitude": {
+          "type": "number",
+          "format": "float"
+        },
+        "altitude": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "description": {
+          "type": "string"
+        }
+      },
+      "description": "The Device settings"
+    },
+    "handlerDeviceList": {
+      "type": "object",
+      "properties": {
+        "devices": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/handlerDevice"
+          }
+        }
+      }
+    },
+    "lorawanDevice": {
+      "type": "object",
+      "properties": {
+        "app_eui": {
+          "type": "string",
+          "format": "byte",
+          "description": "The AppEUI is a unique, 8 byte identifier for the application a device belongs to."
+        },
+        "dev_eui": {
+          "type": "string",
+          "format": "byte",
+          "description": "The DevEUI is a unique, 8 byte identifier for the device."
+        },
+        "app_id": {
+          "type": "string",
+          "description": "The AppID is a unique identifier for the application a device belongs to. It can contain lowercase letters, numbers, - and _."
+        },
+        "dev_id": {
+          "type": "string",
+          "description": "The DevID is a unique identifier for the device. It can contain lowercase letters, numbers, - and _."
+        },
+        "dev_addr": {
+          "type": "string",
+          "format": "byte",
+          "description": "The DevAddr is a dynamic, 4 byte session address for the device."
+        },
+        "nwk_s_key": {
+          "type": "string",
+          "format": "byte",
+          "description": "The NwkSKey is a 16 byte session key that is known by the device and the network. It is used for routing and MAC related functionality.\nThis key is negotiated during the OTAA join procedure, or statically configured using ABP."
+        },
+        "app_s_key": {
+          "type": "string",
+          "format": "byte",
+          "description": "The AppSKey is a 16 byte session key that is known by the device and the application. It is used for payload encryption.\nThis key is negotiated during the OTAA join procedure, or statically configured using ABP."
+        },
+        "app_key": {
+          "type": "string",
+          "format": "byte",
+          "description": "The AppKey is a 16 byte static key that is known by the device and the application. It is used for negotiating session keys (OTAA)."
+        },
+        "f_cnt_up": {
+          "type": "integer",
+          "format": "int64",
+          "description": "FCntUp is the uplink frame counter for a device session."
+        },
+        "f_cnt_down": {
+          "type": "integer",
+          "format": "int64",
+          "description": "FCntDown is the downlink frame counter for a device session."
+        },
+        "disable_f_cnt_check": {
+          "type": "boolean",
+          "format": "boolean",
+          "description": "The DisableFCntCheck option disables the frame counter check. Disabling this makes the device vulnerable to replay attacks, but makes ABP slightly easier."
+        },
+        "uses32_bit_f_cnt": {
+          "type": "boolean",
+          "format": "boolean",
+          "description": "The Uses32BitFCnt option indicates that the device keeps track of full 32 bit frame counters. As only the 16 lsb are actually transmitted, the 16 msb will have to be inferred."
+        },
+        "activation_constraints": {
+          "type": "string",
+          "description": "The ActivationContstraints are used to allocate a device address for a device (comma-separated).\nThere are different prefixes for \u0060otaa\u0060, \u0060abp\u0060, \u0060world\u0060, \u0060local\u0060, \u0060private\u0060, \u0060testing\u0060."
+        },
+        "disable_adr": {
+          "type": "boolean",
+          "format": "boolean",
+          "description": "The DisableADR option prevents the NetworkServer from sending ADR commands to the device, even if the device requests ADR."
+        },
+        "last_seen": {
+          "type": "string",
+          "format": "int64",
+          "title": "When the device was last seen (Unix nanoseconds)"
+        }
+      }
+    },
+    "protobufEmpty": {
+      "type": "object",
+      "properties": {},
+      "description": "service Foo {\n      rpc Bar(google.protobuf.Empty) returns (google.protobuf.Empty);\n    }\n\nThe JSON representation for \u0060Empty\u0060 is empty JSON object \u0060{}\u0060.",
+      "title": "A generic empty message that you can re-use to avoid defining duplicated\nempty messages in your APIs. A typical example is to use it as the request\nor the response type of an API method. For instance:"
+    }
+  }
+}
+`
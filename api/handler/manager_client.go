@@ -61,6 +61,11 @@ func (h *ManagerClient) UpdateAccessToken(accessToken string) {
 	h.accessToken = accessToken
 }
 
+// OverrideDevEUICheckMetadata is the key of the metadata that asks the Handler
+// to register a device even if its DevEUI is not in the DevEUI blocks of the
+// Handler or already used in the applications of the tenant
+const OverrideDevEUICheckMetadata = "override-dev-eui-check"
+
 // GetContext returns a new context with authentication
 func (h *ManagerClient) GetContext() context.Context {
 	h.RLock()
@@ -127,6 +132,21 @@ func (h *ManagerClient) SetDevice(in *Device) error {
 	return errors.Wrap(errors.FromGRPCError(err), "Could not set device on Handler")
 }
 
+// SetDeviceOverridingDevEUICheck sets a device on the Handler, overriding the
+// validation of its DevEUI. This requires the rights to manage the
+// collaborators of the application.
+func (h *ManagerClient) SetDeviceOverridingDevEUICheck(in *Device) error {
+	h.RLock()
+	md := metadata.Pairs(
+		"id", h.id,
+		"token", h.accessToken,
+		OverrideDevEUICheckMetadata, "true",
+	)
+	h.RUnlock()
+	_, err := h.applicationManagerClient.SetDevice(metadata.NewContext(context.Background(), md), in)
+	return errors.Wrap(errors.FromGRPCError(err), "Could not set device on Handler")
+}
+
 // DeleteDevice deletes a device from the Handler
 func (h *ManagerClient) DeleteDevice(appID string, devID string) error {
 	_, err := h.applicationManagerClient.DeleteDevice(h.GetContext(), &DeviceIdentifier{AppId: appID, DevId: devID})
@@ -148,8 +168,16 @@ func (h *ManagerClient) GetDevicesForApplication(appID string, limit, offset int
 
 // GetDevAddr requests a random device address with the given constraints
 func (h *ManagerClient) GetDevAddr(constraints ...string) (types.DevAddr, error) {
+	return h.GetDevAddrForApp("", constraints...)
+}
+
+// GetDevAddrForApp requests a random device address with the given
+// constraints for a device of the application, which is in the DevAddr blocks
+// of the tenant of the application if it has any
+func (h *ManagerClient) GetDevAddrForApp(appID string, constraints ...string) (types.DevAddr, error) {
 	devAddrManager := lorawan.NewDevAddrManagerClient(h.conn)
 	resp, err := devAddrManager.GetDevAddr(h.GetContext(), &lorawan.DevAddrRequest{
+		AppId: appID,
 		Usage: constraints,
 	})
 	if err != nil {
@@ -219,6 +247,40 @@ func (h *ManagerClient) SimulateUplink(appID string, devID string, port uint32,
 	return nil
 }
 
+// GetEvents reads messages from the event stream of an application. Without a
+// group, the messages after the cursor are returned.
+func (h *ManagerClient) GetEvents(in *EventsRequest) ([]*StreamEvent, error) {
+	res, err := h.applicationManagerClient.GetEvents(h.GetContext(), in)
+	if err != nil {
+		return nil, errors.Wrap(errors.FromGRPCError(err), "Could not get events from Handler")
+	}
+	return res.Events, nil
+}
+
+// AckEvents acknowledges messages that a consumer group read from the event
+// stream of an application
+func (h *ManagerClient) AckEvents(appID string, group string, cursors ...string) error {
+	_, err := h.applicationManagerClient.AckEvents(h.GetContext(), &AckEventsRequest{
+		AppId:   appID,
+		Group:   group,
+		Cursors: cursors,
+	})
+	if err != nil {
+		return errors.Wrap(errors.FromGRPCError(err), "Could not acknowledge events on Handler")
+	}
+	return nil
+}
+
+// BulkDevices updates, deletes or requeues the downlinks of the devices that
+// match the filter of the request, and returns the IDs of the selected devices
+func (h *ManagerClient) BulkDevices(in *BulkDevicesRequest) ([]string, error) {
+	res, err := h.applicationManagerClient.BulkDevices(h.GetContext(), in)
+	if err != nil {
+		return nil, errors.Wrap(errors.FromGRPCError(err), "Could not apply bulk action on Handler")
+	}
+	return res.DevIds, nil
+}
+
 // Close closes the client
 func (h *ManagerClient) Close() error {
 	return h.conn.Close()
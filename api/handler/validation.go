@@ -83,3 +83,39 @@ func (m *SimulatedUplinkMessage) Validate() error {
 	}
 	return nil
 }
+
+// Validate implements the api.Validator interface
+func (m *EventsRequest) Validate() error {
+	if err := api.NotEmptyAndValidID(m.AppId, "AppId"); err != nil {
+		return err
+	}
+	if m.Limit < 0 {
+		return errors.NewErrInvalidArgument("Limit", "can not be negative")
+	}
+	if m.Group == "" && (m.Consumer != "" || m.Pending) {
+		return errors.NewErrInvalidArgument("Group", "can not be empty for a consumer")
+	}
+	return nil
+}
+
+// Validate implements the api.Validator interface
+func (m *AckEventsRequest) Validate() error {
+	if err := api.NotEmptyAndValidID(m.AppId, "AppId"); err != nil {
+		return err
+	}
+	if m.Group == "" {
+		return errors.NewErrInvalidArgument("Group", "can not be empty")
+	}
+	return nil
+}
+
+// Validate implements the api.Validator interface
+func (m *BulkDevicesRequest) Validate() error {
+	if err := api.NotEmptyAndValidID(m.AppId, "AppId"); err != nil {
+		return err
+	}
+	if m.Action == "" {
+		return errors.NewErrInvalidArgument("Action", "can not be empty")
+	}
+	return nil
+}
@@ -11,7 +11,13 @@ import (
 
 // Check the health of a connection
 func Check(conn *grpc.ClientConn) (bool, error) {
-	res, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	return CheckService(conn, "")
+}
+
+// CheckService checks the health of a service on a connection. Components
+// report the health of their dependencies, such as "redis", as services.
+func CheckService(conn *grpc.ClientConn, service string) (bool, error) {
+	res, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
 	if err != nil {
 		return false, err
 	}
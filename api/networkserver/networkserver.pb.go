@@ -371,6 +371,8 @@ var _NetworkServer_serviceDesc = grpc.ServiceDesc{
 
 type NetworkServerManagerClient interface {
 	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// Network operator streams the log entries of the NetworkServer
+	TailLogs(ctx context.Context, in *api.LogsRequest, opts ...grpc.CallOption) (NetworkServerManager_TailLogsClient, error)
 }
 
 type networkServerManagerClient struct {
@@ -390,10 +392,44 @@ func (c *networkServerManagerClient) GetStatus(ctx context.Context, in *StatusRe
 	return out, nil
 }
 
+func (c *networkServerManagerClient) TailLogs(ctx context.Context, in *api.LogsRequest, opts ...grpc.CallOption) (NetworkServerManager_TailLogsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_NetworkServerManager_serviceDesc.Streams[0], c.cc, "/networkserver.NetworkServerManager/TailLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &networkServerManagerTailLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NetworkServerManager_TailLogsClient interface {
+	Recv() (*api.LogEntry, error)
+	grpc.ClientStream
+}
+
+type networkServerManagerTailLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *networkServerManagerTailLogsClient) Recv() (*api.LogEntry, error) {
+	m := new(api.LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for NetworkServerManager service
 
 type NetworkServerManagerServer interface {
 	GetStatus(context.Context, *StatusRequest) (*Status, error)
+	// Network operator streams the log entries of the NetworkServer
+	TailLogs(*api.LogsRequest, NetworkServerManager_TailLogsServer) error
 }
 
 func RegisterNetworkServerManagerServer(s *grpc.Server, srv NetworkServerManagerServer) {
@@ -418,6 +454,27 @@ func _NetworkServerManager_GetStatus_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NetworkServerManager_TailLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(api.LogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NetworkServerManagerServer).TailLogs(m, &networkServerManagerTailLogsServer{stream})
+}
+
+type NetworkServerManager_TailLogsServer interface {
+	Send(*api.LogEntry) error
+	grpc.ServerStream
+}
+
+type networkServerManagerTailLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *networkServerManagerTailLogsServer) Send(m *api.LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _NetworkServerManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "networkserver.NetworkServerManager",
 	HandlerType: (*NetworkServerManagerServer)(nil),
@@ -427,7 +484,13 @@ var _NetworkServerManager_serviceDesc = grpc.ServiceDesc{
 			Handler:    _NetworkServerManager_GetStatus_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailLogs",
+			Handler:       _NetworkServerManager_TailLogs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "github.com/TheThingsNetwork/ttn/api/networkserver/networkserver.proto",
 }
 
@@ -1283,43 +1346,45 @@ func init() {
 }
 
 var fileDescriptorNetworkserver = []byte{
-	// 603 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x94, 0x5d, 0x4f, 0x13, 0x4f,
-	0x14, 0xc6, 0xb3, 0xf0, 0xff, 0x97, 0x72, 0x4a, 0x45, 0x06, 0x89, 0x4d, 0x95, 0x0a, 0x4d, 0x34,
-	0x35, 0xea, 0x6e, 0xa8, 0x89, 0x57, 0x24, 0xf2, 0x52, 0xc3, 0x85, 0x81, 0xd4, 0x05, 0x13, 0xe3,
-	0x0d, 0x99, 0xee, 0x1e, 0xda, 0x0d, 0xdb, 0x99, 0x75, 0x66, 0xb6, 0xc8, 0xc7, 0xf1, 0xda, 0x2f,
-	0xe2, 0xa5, 0xd7, 0x5e, 0x18, 0xc3, 0x27, 0x31, 0x9d, 0x97, 0xc2, 0x0a, 0xa4, 0xe1, 0x6a, 0xf7,
-	0x3c, 0xcf, 0x6f, 0x67, 0xce, 0xce, 0x73, 0x76, 0xe1, 0x5d, 0x3f, 0x51, 0x83, 0xbc, 0xe7, 0x47,
-	0x7c, 0x18, 0x1c, 0x0d, 0xf0, 0x68, 0x90, 0xb0, 0xbe, 0x3c, 0x40, 0x75, 0xc6, 0xc5, 0x69, 0xa0,
-	0x14, 0x0b, 0x68, 0x96, 0x04, 0xcc, 0xd4, 0x12, 0xc5, 0x08, 0x45, 0xb1, 0xf2, 0x33, 0xc1, 0x15,
-	0x27, 0xd5, 0x82, 0x58, 0x7f, 0x75, 0x65, 0xd5, 0x3e, 0xef, 0xf3, 0x40, 0x53, 0xbd, 0xfc, 0x44,
-	0x57, 0xba, 0xd0, 0x77, 0xe6, 0xe9, 0xfa, 0x92, 0xdb, 0x88, 0x66, 0x89, 0x95, 0x9e, 0x3a, 0x49,
-	0x97, 0x11, 0x4f, 0x83, 0x94, 0x0b, 0x7a, 0x46, 0x59, 0x10, 0xe3, 0x28, 0x89, 0xd0, 0x62, 0x8f,
-	0x1c, 0xd6, 0x13, 0xfc, 0x14, 0x85, 0xbd, 0x58, 0x73, 0xd5, 0x99, 0x03, 0xca, 0xe2, 0x14, 0x85,
-	0xbb, 0x1a, 0xbb, 0xf9, 0x15, 0xee, 0x75, 0xf4, 0x5a, 0x32, 0xc4, 0x2f, 0x39, 0x4a, 0x45, 0x3e,
-	0x40, 0x39, 0xc6, 0xd1, 0x31, 0x8d, 0x63, 0x51, 0xf3, 0xd6, 0xbc, 0xd6, 0xc2, 0xce, 0x9b, 0x5f,
-	0xbf, 0x9f, 0xb4, 0xa7, 0x1d, 0x51, 0xc4, 0x05, 0x06, 0xea, 0x3c, 0x43, 0xe9, 0x77, 0x70, 0xb4,
-	0x1d, 0xc7, 0x22, 0x9c, 0x8b, 0xcd, 0x0d, 0x59, 0x86, 0xff, 0x4f, 0x8e, 0x23, 0xa6, 0x6a, 0x33,
-	0x6b, 0x5e, 0xab, 0x1a, 0xfe, 0x77, 0xb2, 0xcb, 0x54, 0x73, 0x13, 0x16, 0x27, 0x3b, 0xcb, 0x8c,
-	0x33, 0x89, 0xe4, 0x39, 0xcc, 0x09, 0x94, 0x79, 0xaa, 0x64, 0xcd, 0x5b, 0x9b, 0x6d, 0x55, 0xda,
-	0x8b, 0xbe, 0x7d, 0x61, 0xdf, 0xa0, 0xa1, 0xf3, 0x9b, 0x8b, 0x50, 0x3d, 0x54, 0x54, 0xe5, 0xae,
-	0xed, 0xe6, 0xb7, 0x19, 0x28, 0x19, 0x85, 0xb4, 0xa0, 0x24, 0xcf, 0xa5, 0xc2, 0xa1, 0xee, 0xbf,
-	0xd2, 0xbe, 0xef, 0x8f, 0x8f, 0xf4, 0x50, 0x4b, 0x63, 0x44, 0x86, 0xd6, 0x27, 0x1b, 0x30, 0x1f,
-	0xf1, 0x61, 0xc6, 0x19, 0xda, 0xe6, 0x2a, 0xed, 0x65, 0x0d, 0xef, 0x3a, 0xd5, 0xf0, 0x97, 0x14,
-	0x69, 0x42, 0x29, 0xcf, 0xd2, 0x84, 0x9d, 0xd6, 0x2a, 0x9a, 0x07, 0xcd, 0x87, 0x54, 0xa1, 0x0c,
-	0xad, 0x43, 0x9e, 0x41, 0x39, 0xe6, 0x67, 0x4c, 0x53, 0x0b, 0xd7, 0xa8, 0x89, 0x47, 0x5e, 0x42,
-	0x85, 0x46, 0x2a, 0x19, 0x51, 0x95, 0x70, 0x26, 0x6b, 0xd5, 0x6b, 0xe8, 0x55, 0x9b, 0x6c, 0xc1,
-	0xb2, 0x89, 0x5d, 0x1e, 0x67, 0x28, 0x74, 0x40, 0x28, 0x65, 0x6d, 0xe5, 0xca, 0x3b, 0x76, 0x51,
-	0x44, 0xc8, 0x54, 0x92, 0xa2, 0x0c, 0x97, 0x2c, 0xdc, 0x45, 0xb1, 0x6d, 0xd0, 0xf6, 0xf7, 0x59,
-	0xa8, 0xda, 0xcc, 0x0e, 0xf5, 0x8c, 0x92, 0xf7, 0x00, 0x7b, 0xa8, 0x6c, 0x0e, 0x64, 0xd5, 0x2f,
-	0x8e, 0x75, 0x71, 0x32, 0xea, 0x8d, 0xdb, 0x6c, 0x1b, 0xdf, 0x10, 0x96, 0xba, 0x02, 0x33, 0x2a,
-	0x70, 0x7b, 0xd2, 0x36, 0x79, 0xe1, 0xdb, 0x71, 0xec, 0x60, 0x3c, 0x3e, 0x9e, 0x88, 0x2a, 0x8c,
-	0xcd, 0x93, 0x97, 0x94, 0xdb, 0xe1, 0x2e, 0x30, 0xe9, 0x42, 0xd9, 0x8a, 0x48, 0xd6, 0x7d, 0x37,
-	0xd6, 0xd7, 0x69, 0xd3, 0x5d, 0x7d, 0x3a, 0x42, 0x0e, 0xa0, 0xf4, 0xd1, 0x24, 0xb8, 0x7e, 0x53,
-	0x23, 0xc6, 0xdb, 0x47, 0x29, 0x69, 0x7f, 0xbc, 0xde, 0x34, 0x84, 0x6c, 0x42, 0xb9, 0xe3, 0xb2,
-	0x7e, 0x38, 0xc1, 0xad, 0xe2, 0xd6, 0xb9, 0xcd, 0x68, 0x7f, 0x82, 0x07, 0x85, 0xb0, 0xf6, 0x29,
-	0xa3, 0x7d, 0x14, 0x64, 0x0b, 0xe6, 0xf7, 0x50, 0xd9, 0x59, 0x7f, 0xfc, 0x4f, 0x26, 0x85, 0x8f,
-	0xa2, 0xbe, 0x72, 0xa3, 0xbb, 0xf3, 0xf6, 0xc7, 0x45, 0xc3, 0xfb, 0x79, 0xd1, 0xf0, 0xfe, 0x5c,
-	0x34, 0xbc, 0xcf, 0x1b, 0x77, 0xfe, 0xfb, 0xf5, 0x4a, 0xfa, 0xe7, 0xf1, 0xfa, 0x6f, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x4f, 0x28, 0xd8, 0x5e, 0x39, 0x05, 0x00, 0x00,
+	// 625 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x94, 0x4f, 0x4f, 0x13, 0x4f,
+	0x18, 0xc7, 0xb3, 0xf0, 0xfb, 0x95, 0xf2, 0x94, 0x8a, 0x0c, 0x12, 0x9b, 0x2a, 0x15, 0x9a, 0x68,
+	0x6a, 0x94, 0x5d, 0xa9, 0x89, 0x27, 0x12, 0xf9, 0x53, 0xc2, 0x41, 0x21, 0x75, 0xc1, 0x8b, 0x17,
+	0x32, 0xdd, 0x7d, 0xd8, 0x6e, 0xd8, 0xce, 0xac, 0x33, 0xd3, 0x22, 0xaf, 0xc0, 0xd7, 0xe1, 0xd9,
+	0x37, 0xe2, 0xd1, 0xb3, 0x07, 0x63, 0x78, 0x25, 0xa6, 0xf3, 0xa7, 0x50, 0x81, 0x34, 0x9c, 0x76,
+	0xe6, 0xfb, 0xfd, 0xcc, 0xcc, 0xb3, 0xf3, 0x7d, 0x76, 0x61, 0x37, 0x49, 0x55, 0xb7, 0xdf, 0xf1,
+	0x23, 0xde, 0x0b, 0x8e, 0xba, 0x78, 0xd4, 0x4d, 0x59, 0x22, 0x0f, 0x50, 0x9d, 0x71, 0x71, 0x1a,
+	0x28, 0xc5, 0x02, 0x9a, 0xa7, 0x01, 0x33, 0x73, 0x89, 0x62, 0x80, 0x62, 0x7c, 0xe6, 0xe7, 0x82,
+	0x2b, 0x4e, 0xca, 0x63, 0x62, 0x75, 0xed, 0xca, 0xae, 0x09, 0x4f, 0x78, 0xa0, 0xa9, 0x4e, 0xff,
+	0x44, 0xcf, 0xf4, 0x44, 0x8f, 0xcc, 0xea, 0xea, 0x82, 0x3b, 0x88, 0xe6, 0xa9, 0x95, 0x9e, 0x3a,
+	0x49, 0x4f, 0x23, 0x9e, 0x05, 0x19, 0x17, 0xf4, 0x8c, 0xb2, 0x20, 0xc6, 0x41, 0x1a, 0xa1, 0xc5,
+	0x1e, 0x39, 0xac, 0x23, 0xf8, 0x29, 0x0a, 0xfb, 0xb0, 0xe6, 0xb2, 0x33, 0xbb, 0x94, 0xc5, 0x19,
+	0x0a, 0xf7, 0x34, 0x76, 0xfd, 0x0b, 0xdc, 0x6b, 0xe9, 0xbd, 0x64, 0x88, 0x9f, 0xfb, 0x28, 0x15,
+	0xf9, 0x00, 0xc5, 0x18, 0x07, 0xc7, 0x34, 0x8e, 0x45, 0xc5, 0x5b, 0xf1, 0x1a, 0x73, 0xdb, 0x6f,
+	0x7e, 0xfd, 0x7e, 0xd2, 0x9c, 0x74, 0x45, 0x11, 0x17, 0x18, 0xa8, 0xf3, 0x1c, 0xa5, 0xdf, 0xc2,
+	0xc1, 0x56, 0x1c, 0x8b, 0x70, 0x26, 0x36, 0x03, 0xb2, 0x08, 0xff, 0x9f, 0x1c, 0x47, 0x4c, 0x55,
+	0xa6, 0x56, 0xbc, 0x46, 0x39, 0xfc, 0xef, 0x64, 0x87, 0xa9, 0xfa, 0x06, 0xcc, 0x8f, 0x4e, 0x96,
+	0x39, 0x67, 0x12, 0xc9, 0x73, 0x98, 0x11, 0x28, 0xfb, 0x99, 0x92, 0x15, 0x6f, 0x65, 0xba, 0x51,
+	0x6a, 0xce, 0xfb, 0xf6, 0x85, 0x7d, 0x83, 0x86, 0xce, 0xaf, 0xcf, 0x43, 0xf9, 0x50, 0x51, 0xd5,
+	0x77, 0x65, 0xd7, 0xbf, 0x4d, 0x41, 0xc1, 0x28, 0xa4, 0x01, 0x05, 0x79, 0x2e, 0x15, 0xf6, 0x74,
+	0xfd, 0xa5, 0xe6, 0x7d, 0x7f, 0x78, 0xa5, 0x87, 0x5a, 0x1a, 0x22, 0x32, 0xb4, 0x3e, 0x59, 0x87,
+	0xd9, 0x88, 0xf7, 0x72, 0xce, 0xd0, 0x16, 0x57, 0x6a, 0x2e, 0x6a, 0x78, 0xc7, 0xa9, 0x86, 0xbf,
+	0xa4, 0x48, 0x1d, 0x0a, 0xfd, 0x3c, 0x4b, 0xd9, 0x69, 0xa5, 0xa4, 0x79, 0xd0, 0x7c, 0x48, 0x15,
+	0xca, 0xd0, 0x3a, 0xe4, 0x19, 0x14, 0x63, 0x7e, 0xc6, 0x34, 0x35, 0x77, 0x8d, 0x1a, 0x79, 0xe4,
+	0x25, 0x94, 0x68, 0xa4, 0xd2, 0x01, 0x55, 0x29, 0x67, 0xb2, 0x52, 0xbe, 0x86, 0x5e, 0xb5, 0xc9,
+	0x26, 0x2c, 0x9a, 0xd8, 0xe5, 0x71, 0x8e, 0x42, 0x07, 0x84, 0x52, 0x56, 0x96, 0xae, 0xbc, 0x63,
+	0x1b, 0x45, 0x84, 0x4c, 0xa5, 0x19, 0xca, 0x70, 0xc1, 0xc2, 0x6d, 0x14, 0x5b, 0x06, 0x6d, 0x7e,
+	0x9f, 0x86, 0xb2, 0xcd, 0xec, 0x50, 0xf7, 0x28, 0x79, 0x07, 0xb0, 0x87, 0xca, 0xe6, 0x40, 0x96,
+	0xfd, 0xf1, 0xb6, 0x1e, 0xef, 0x8c, 0x6a, 0xed, 0x36, 0xdb, 0xc6, 0xd7, 0x83, 0x85, 0xb6, 0xc0,
+	0x9c, 0x0a, 0xdc, 0x1a, 0x95, 0x4d, 0x5e, 0xf8, 0xb6, 0x1d, 0x5b, 0x18, 0x0f, 0xaf, 0x27, 0xa2,
+	0x0a, 0x63, 0xb3, 0xf2, 0x92, 0x72, 0x27, 0xdc, 0x05, 0x26, 0x6d, 0x28, 0x5a, 0x11, 0xc9, 0xaa,
+	0xef, 0xda, 0xfa, 0x3a, 0x6d, 0xaa, 0xab, 0x4e, 0x46, 0xc8, 0x01, 0x14, 0x3e, 0x9a, 0x04, 0x57,
+	0x6f, 0x2a, 0xc4, 0x78, 0xfb, 0x28, 0x25, 0x4d, 0xb0, 0x3a, 0x19, 0x21, 0x1b, 0x50, 0x6c, 0xb9,
+	0xac, 0x1f, 0x8e, 0x70, 0xab, 0xb8, 0x7d, 0x6e, 0x33, 0x9a, 0x5f, 0x3d, 0x78, 0x30, 0x96, 0xd6,
+	0x3e, 0x65, 0x34, 0x41, 0x41, 0x36, 0x61, 0x76, 0x0f, 0x95, 0x6d, 0xf6, 0xc7, 0xff, 0x84, 0x32,
+	0xf6, 0x55, 0x54, 0x97, 0x6e, 0x74, 0xc9, 0x1a, 0x14, 0x8f, 0x68, 0x9a, 0xbd, 0xe7, 0x89, 0x24,
+	0xa6, 0x73, 0x86, 0x43, 0xb7, 0xa8, 0xec, 0x94, 0x5d, 0xa6, 0xc4, 0xf9, 0x2b, 0x6f, 0xfb, 0xed,
+	0x8f, 0x8b, 0x9a, 0xf7, 0xf3, 0xa2, 0xe6, 0xfd, 0xb9, 0xa8, 0x79, 0x9f, 0xd6, 0xef, 0xfc, 0xb7,
+	0xec, 0x14, 0xf4, 0xcf, 0xe6, 0xf5, 0xdf, 0x01, 0x00, 0xc6, 0xb1, 0xc5, 0xae, 0x69, 0x05, 0x00,
+	0x00,
 }
@@ -0,0 +1,277 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package lorawan
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/otaa"
+	"github.com/brocaar/lorawan"
+)
+
+// DecodeKeys are the keys that are used to verify the MIC and decrypt the
+// payload of a PHYPayload. Keys that are empty are not used.
+type DecodeKeys struct {
+	NwkSKey types.NwkSKey
+	AppSKey types.AppSKey
+	AppKey  types.AppKey
+	// DevNonce is the DevNonce of the join-request, used to derive the
+	// session keys from a join-accept
+	DevNonce *types.DevNonce
+}
+
+// DecodedPHYPayload is a breakdown of a PHYPayload
+type DecodedPHYPayload struct {
+	MType string `json:"m_type"`
+	Major string `json:"major"`
+	MIC   string `json:"mic"`
+	// MICValid is nil if the MIC was not verified because the key was not given
+	MICValid    *bool               `json:"mic_valid,omitempty"`
+	Data        *DecodedMACPayload  `json:"data,omitempty"`
+	JoinRequest *JoinRequestPayload `json:"join_request,omitempty"`
+	JoinAccept  *DecodedJoinAccept  `json:"join_accept,omitempty"`
+}
+
+// DecodedMACPayload is a breakdown of the MACPayload of a data message
+type DecodedMACPayload struct {
+	DevAddr   types.DevAddr       `json:"dev_addr"`
+	ADR       bool                `json:"adr"`
+	ADRAckReq bool                `json:"adr_ack_req"`
+	ACK       bool                `json:"ack"`
+	FPending  bool                `json:"f_pending"`
+	FCnt      uint32              `json:"f_cnt"`
+	FOpts     []DecodedMACCommand `json:"f_opts,omitempty"`
+	// FPort is nil if the message has no FPort and FRMPayload
+	FPort      *uint8 `json:"f_port,omitempty"`
+	FRMPayload []byte `json:"frm_payload,omitempty"`
+	// Decrypted is the decrypted FRMPayload, if the key was given. The
+	// FRMPayload on FPort 0 is decrypted with the NwkSKey and contains MAC
+	// commands, other ports are decrypted with the AppSKey.
+	Decrypted   []byte              `json:"decrypted,omitempty"`
+	MACCommands []DecodedMACCommand `json:"mac_commands,omitempty"`
+}
+
+// DecodedMACCommand is a MAC command in the FOpts or in the FRMPayload on FPort 0
+type DecodedMACCommand struct {
+	CID     uint8  `json:"cid"`
+	Name    string `json:"name,omitempty"`
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// DecodedJoinAccept is a breakdown of a join-accept
+type DecodedJoinAccept struct {
+	// Encrypted is true if the join-accept was not decrypted because the AppKey was not given
+	Encrypted   bool           `json:"encrypted,omitempty"`
+	AppNonce    types.AppNonce `json:"app_nonce"`
+	NetID       types.NetID    `json:"net_id"`
+	DevAddr     types.DevAddr  `json:"dev_addr"`
+	RX1DROffset uint32         `json:"rx1_dr_offset"`
+	RX2DataRate uint32         `json:"rx2_data_rate"`
+	RXDelay     uint32         `json:"rx_delay"`
+	CFList      []uint32       `json:"cf_list,omitempty"`
+	// AppSKey and NwkSKey are derived if the AppKey and DevNonce were given
+	AppSKey *types.AppSKey `json:"app_s_key,omitempty"`
+	NwkSKey *types.NwkSKey `json:"nwk_s_key,omitempty"`
+}
+
+type macCommandDefinition struct {
+	name   string
+	length int
+}
+
+var uplinkMACCommands = map[uint8]macCommandDefinition{
+	0x01: {"ResetInd", 1},
+	0x02: {"LinkCheckReq", 0},
+	0x03: {"LinkADRAns", 1},
+	0x04: {"DutyCycleAns", 0},
+	0x05: {"RXParamSetupAns", 1},
+	0x06: {"DevStatusAns", 2},
+	0x07: {"NewChannelAns", 1},
+	0x08: {"RXTimingSetupAns", 0},
+	0x0D: {"DeviceTimeReq", 0},
+}
+
+var downlinkMACCommands = map[uint8]macCommandDefinition{
+	0x01: {"ResetConf", 1},
+	0x02: {"LinkCheckAns", 2},
+	0x03: {"LinkADRReq", 4},
+	0x04: {"DutyCycleReq", 1},
+	0x05: {"RXParamSetupReq", 4},
+	0x06: {"DevStatusReq", 0},
+	0x07: {"NewChannelReq", 5},
+	0x08: {"RXTimingSetupReq", 1},
+	0x0D: {"DeviceTimeAns", 5},
+}
+
+func macCommandName(uplink bool, cid uint8) string {
+	if uplink {
+		return uplinkMACCommands[cid].name
+	}
+	return downlinkMACCommands[cid].name
+}
+
+// decodeMACCommands splits the MAC commands in a decrypted FRMPayload. It
+// stops at the first unknown command, which is returned with the rest of the bytes.
+func decodeMACCommands(uplink bool, data []byte) (commands []DecodedMACCommand) {
+	definitions := downlinkMACCommands
+	if uplink {
+		definitions = uplinkMACCommands
+	}
+	for len(data) > 0 {
+		cid := data[0]
+		definition, ok := definitions[cid]
+		if !ok || len(data) < 1+definition.length {
+			return append(commands, DecodedMACCommand{CID: cid, Payload: data[1:]})
+		}
+		commands = append(commands, DecodedMACCommand{CID: cid, Name: definition.name, Payload: data[1 : 1+definition.length]})
+		data = data[1+definition.length:]
+	}
+	return
+}
+
+// CryptFRMPayload encrypts or decrypts the FRMPayload of a data message as
+// specified in section 4.3.3 of the LoRaWAN 1.0 specification
+func CryptFRMPayload(key types.AES128Key, uplink bool, devAddr types.DevAddr, fCnt uint32, payload []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(payload))
+	var a, s [16]byte
+	a[0] = 0x01
+	if !uplink {
+		a[5] = 0x01
+	}
+	for i := 0; i < 4; i++ {
+		a[6+i] = devAddr[3-i]
+	}
+	binary.LittleEndian.PutUint32(a[10:14], fCnt)
+	for i := 0; i < len(payload); i += 16 {
+		a[15] = byte(i/16 + 1)
+		block.Encrypt(s[:], a[:])
+		for j := 0; j < 16 && i+j < len(payload); j++ {
+			out[i+j] = payload[i+j] ^ s[j]
+		}
+	}
+	return out, nil
+}
+
+func isUplink(mType MType) bool {
+	return mType == MType_JOIN_REQUEST || mType == MType_UNCONFIRMED_UP || mType == MType_CONFIRMED_UP
+}
+
+// DecodePHYPayload decodes a PHYPayload. If the keys are given, it verifies
+// the MIC and decrypts the payload.
+func DecodePHYPayload(payload []byte, keys DecodeKeys) (*DecodedPHYPayload, error) {
+	var phy lorawan.PHYPayload
+	if err := phy.UnmarshalBinary(payload); err != nil {
+		return nil, err
+	}
+
+	decoded := &DecodedPHYPayload{
+		MType: MType(phy.MHDR.MType).String(),
+		Major: Major(phy.MHDR.Major).String(),
+	}
+	verifyMIC := func(key types.AES128Key) error {
+		if key == (types.AES128Key{}) {
+			return nil
+		}
+		ok, err := phy.ValidateMIC(lorawan.AES128Key(key))
+		if err != nil {
+			return err
+		}
+		decoded.MICValid = &ok
+		return nil
+	}
+
+	mType := MType(phy.MHDR.MType)
+	uplink := isUplink(mType)
+	switch mType {
+	case MType_JOIN_REQUEST:
+		if err := verifyMIC(types.AES128Key(keys.AppKey)); err != nil {
+			return nil, err
+		}
+		request := JoinRequestPayloadFromPayload(phy.MACPayload)
+		decoded.JoinRequest = &request
+	case MType_JOIN_ACCEPT:
+		encrypted := keys.AppKey.IsEmpty()
+		if !encrypted {
+			if err := phy.DecryptJoinAcceptPayload(lorawan.AES128Key(keys.AppKey)); err != nil {
+				return nil, err
+			}
+			if err := verifyMIC(types.AES128Key(keys.AppKey)); err != nil {
+				return nil, err
+			}
+		}
+		if encrypted {
+			decoded.JoinAccept = &DecodedJoinAccept{Encrypted: true}
+			break
+		}
+		accept := JoinAcceptPayloadFromPayload(phy.MACPayload)
+		decoded.JoinAccept = &DecodedJoinAccept{
+			AppNonce:    accept.AppNonce,
+			NetID:       accept.NetId,
+			DevAddr:     accept.DevAddr,
+			RX1DROffset: accept.DLSettings.Rx1DrOffset,
+			RX2DataRate: accept.DLSettings.Rx2Dr,
+			RXDelay:     accept.RxDelay,
+		}
+		if accept.CfList != nil {
+			decoded.JoinAccept.CFList = accept.CfList.Freq
+		}
+		if keys.DevNonce != nil {
+			appSKey, nwkSKey, err := otaa.CalculateSessionKeys(keys.AppKey, accept.AppNonce, accept.NetId, *keys.DevNonce)
+			if err != nil {
+				return nil, err
+			}
+			decoded.JoinAccept.AppSKey, decoded.JoinAccept.NwkSKey = &appSKey, &nwkSKey
+		}
+	case MType_UNCONFIRMED_UP, MType_UNCONFIRMED_DOWN, MType_CONFIRMED_UP, MType_CONFIRMED_DOWN:
+		if err := verifyMIC(types.AES128Key(keys.NwkSKey)); err != nil {
+			return nil, err
+		}
+		mac := MACPayloadFromPayload(phy.MACPayload)
+		data := &DecodedMACPayload{
+			DevAddr:    mac.DevAddr,
+			ADR:        mac.Adr,
+			ADRAckReq:  mac.AdrAckReq,
+			ACK:        mac.Ack,
+			FPending:   mac.FPending,
+			FCnt:       mac.FCnt,
+			FRMPayload: mac.FrmPayload,
+		}
+		for _, cmd := range mac.FOpts {
+			data.FOpts = append(data.FOpts, DecodedMACCommand{CID: uint8(cmd.Cid), Name: macCommandName(uplink, uint8(cmd.Cid)), Payload: cmd.Payload})
+		}
+		if macPayload, ok := phy.MACPayload.(*lorawan.MACPayload); ok && macPayload.FPort != nil {
+			fPort := *macPayload.FPort
+			data.FPort = &fPort
+		}
+		if data.FPort != nil && len(data.FRMPayload) > 0 {
+			key := types.AES128Key(keys.AppSKey)
+			if *data.FPort == 0 {
+				key = types.AES128Key(keys.NwkSKey)
+			}
+			if key != (types.AES128Key{}) {
+				decrypted, err := CryptFRMPayload(key, uplink, data.DevAddr, data.FCnt, data.FRMPayload)
+				if err != nil {
+					return nil, err
+				}
+				data.Decrypted = decrypted
+				if *data.FPort == 0 {
+					data.MACCommands = decodeMACCommands(uplink, decrypted)
+				}
+			}
+		}
+		decoded.Data = data
+	default:
+		return nil, fmt.Errorf("Unknown MType %d", phy.MHDR.MType)
+	}
+
+	decoded.MIC = fmt.Sprintf("%X", phy.MIC[:])
+	return decoded, nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package lorawan
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestDecodeDataPHYPayload(t *testing.T) {
+	a := New(t)
+
+	payload := []byte{0x40, 0xF1, 0x7D, 0xBE, 0x49, 0x00, 0x02, 0x00, 0x01, 0x95, 0x43, 0x78, 0x76, 0x2B, 0x11, 0xFF, 0x0D}
+	nwkSKey := types.NwkSKey{0x44, 0x02, 0x42, 0x41, 0xED, 0x4C, 0xE9, 0xA6, 0x8C, 0x6A, 0x8B, 0xC0, 0x55, 0x23, 0x3F, 0xD3}
+	appSKey := types.AppSKey{0xEC, 0x92, 0x58, 0x02, 0xAE, 0x43, 0x0C, 0xA7, 0x7F, 0xD3, 0xDD, 0x73, 0xCB, 0x2C, 0xC5, 0x88}
+
+	{
+		decoded, err := DecodePHYPayload(payload, DecodeKeys{})
+		a.So(err, ShouldBeNil)
+		a.So(decoded.MType, ShouldEqual, "UNCONFIRMED_UP")
+		a.So(decoded.MIC, ShouldEqual, "2B11FF0D")
+		a.So(decoded.MICValid, ShouldBeNil)
+		a.So(decoded.Data.DevAddr, ShouldEqual, types.DevAddr{0x49, 0xBE, 0x7D, 0xF1})
+		a.So(decoded.Data.FCnt, ShouldEqual, 2)
+		a.So(*decoded.Data.FPort, ShouldEqual, 1)
+		a.So(decoded.Data.Decrypted, ShouldBeNil)
+	}
+
+	{
+		decoded, err := DecodePHYPayload(payload, DecodeKeys{NwkSKey: nwkSKey, AppSKey: appSKey})
+		a.So(err, ShouldBeNil)
+		a.So(*decoded.MICValid, ShouldBeTrue)
+		a.So(decoded.Data.Decrypted, ShouldResemble, []byte("test"))
+	}
+
+	{
+		decoded, err := DecodePHYPayload(payload, DecodeKeys{NwkSKey: types.NwkSKey{1}})
+		a.So(err, ShouldBeNil)
+		a.So(*decoded.MICValid, ShouldBeFalse)
+	}
+
+	{
+		_, err := DecodePHYPayload([]byte{0x40, 0x01}, DecodeKeys{})
+		a.So(err, ShouldNotBeNil)
+	}
+}
+
+func TestDecodeJoinPHYPayload(t *testing.T) {
+	a := New(t)
+
+	appKey := types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	devNonce := types.DevNonce{0x01, 0x02}
+
+	request := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{MType: lorawan.JoinRequest, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.JoinRequestPayload{
+			AppEUI:   lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+			DevEUI:   lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+			DevNonce: devNonce,
+		},
+	}
+	request.SetMIC(lorawan.AES128Key(appKey))
+	requestBytes, _ := request.MarshalBinary()
+
+	{
+		decoded, err := DecodePHYPayload(requestBytes, DecodeKeys{AppKey: appKey})
+		a.So(err, ShouldBeNil)
+		a.So(decoded.MType, ShouldEqual, "JOIN_REQUEST")
+		a.So(*decoded.MICValid, ShouldBeTrue)
+		a.So(decoded.JoinRequest.DevEui, ShouldEqual, types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1})
+		a.So(decoded.JoinRequest.DevNonce, ShouldEqual, devNonce)
+	}
+
+	accept := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{MType: lorawan.JoinAccept, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.JoinAcceptPayload{
+			AppNonce: [3]byte{1, 2, 3},
+			NetID:    [3]byte{0, 0, 0x13},
+			DevAddr:  lorawan.DevAddr{0x26, 0x01, 0x02, 0x03},
+			RXDelay:  1,
+		},
+	}
+	accept.SetMIC(lorawan.AES128Key(appKey))
+	accept.EncryptJoinAcceptPayload(lorawan.AES128Key(appKey))
+	acceptBytes, _ := accept.MarshalBinary()
+
+	{
+		decoded, err := DecodePHYPayload(acceptBytes, DecodeKeys{})
+		a.So(err, ShouldBeNil)
+		a.So(decoded.MType, ShouldEqual, "JOIN_ACCEPT")
+		a.So(decoded.JoinAccept.Encrypted, ShouldBeTrue)
+	}
+
+	{
+		decoded, err := DecodePHYPayload(acceptBytes, DecodeKeys{AppKey: appKey, DevNonce: &devNonce})
+		a.So(err, ShouldBeNil)
+		a.So(*decoded.MICValid, ShouldBeTrue)
+		a.So(decoded.JoinAccept.DevAddr, ShouldEqual, types.DevAddr{0x26, 0x01, 0x02, 0x03})
+		a.So(decoded.JoinAccept.RXDelay, ShouldEqual, 1)
+		a.So(decoded.JoinAccept.AppSKey, ShouldNotBeNil)
+		a.So(decoded.JoinAccept.NwkSKey, ShouldNotBeNil)
+	}
+}
+
+func TestDecodeMACCommands(t *testing.T) {
+	a := New(t)
+
+	commands := decodeMACCommands(true, []byte{0x02, 0x03, 0x07, 0x06, 0xFF, 0x01, 0x01})
+	a.So(commands, ShouldHaveLength, 4)
+	a.So(commands[0].Name, ShouldEqual, "LinkCheckReq")
+	a.So(commands[1].Name, ShouldEqual, "LinkADRAns")
+	a.So(commands[1].Payload, ShouldResemble, []byte{0x07})
+	a.So(commands[2].Name, ShouldEqual, "DevStatusAns")
+	a.So(commands[2].Payload, ShouldResemble, []byte{0xFF, 0x01})
+	a.So(commands[3].CID, ShouldEqual, 0x01)
+	a.So(commands[3].Name, ShouldBeEmpty)
+
+	commands = decodeMACCommands(false, []byte{0x02, 0x10, 0x02})
+	a.So(commands, ShouldHaveLength, 1)
+	a.So(commands[0].Name, ShouldEqual, "LinkCheckAns")
+}
@@ -82,8 +82,13 @@ type Device struct {
 	// The ActivationContstraints are used to allocate a device address for a device (comma-separated).
 	// There are different prefixes for `otaa`, `abp`, `world`, `local`, `private`, `testing`.
 	ActivationConstraints string `protobuf:"bytes,13,opt,name=activation_constraints,json=activationConstraints,proto3" json:"activation_constraints,omitempty"`
+	// The DisableADR option prevents the NetworkServer from sending ADR commands to the device, even if the device requests ADR.
+	DisableAdr bool `protobuf:"varint,14,opt,name=disable_adr,json=disableAdr,proto3" json:"disable_adr,omitempty"`
 	// When the device was last seen (Unix nanoseconds)
 	LastSeen int64 `protobuf:"varint,21,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	// The names of the gateway groups that the device prefers for downlink, most preferred first.
+	// Devices without preferences use those of their application.
+	GatewayGroups []string `protobuf:"bytes,22,rep,name=gateway_groups,json=gatewayGroups" json:"gateway_groups,omitempty"`
 }
 
 func (m *Device) Reset()                    { *m = Device{} }
@@ -140,6 +145,13 @@ func (m *Device) GetActivationConstraints() string {
 	return ""
 }
 
+func (m *Device) GetDisableAdr() bool {
+	if m != nil {
+		return m.DisableAdr
+	}
+	return false
+}
+
 func (m *Device) GetLastSeen() int64 {
 	if m != nil {
 		return m.LastSeen
@@ -147,6 +159,13 @@ func (m *Device) GetLastSeen() int64 {
 	return 0
 }
 
+func (m *Device) GetGatewayGroups() []string {
+	if m != nil {
+		return m.GatewayGroups
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DeviceIdentifier)(nil), "lorawan.DeviceIdentifier")
 	proto.RegisterType((*Device)(nil), "lorawan.Device")
@@ -451,6 +470,16 @@ func (m *Device) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintDevice(dAtA, i, uint64(len(m.ActivationConstraints)))
 		i += copy(dAtA[i:], m.ActivationConstraints)
 	}
+	if m.DisableAdr {
+		dAtA[i] = 0x70
+		i++
+		if m.DisableAdr {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	if m.LastSeen != 0 {
 		dAtA[i] = 0xa8
 		i++
@@ -458,6 +487,23 @@ func (m *Device) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintDevice(dAtA, i, uint64(m.LastSeen))
 	}
+	if len(m.GatewayGroups) > 0 {
+		for _, s := range m.GatewayGroups {
+			dAtA[i] = 0xb2
+			i++
+			dAtA[i] = 0x1
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
 	return i, nil
 }
 
@@ -553,9 +599,18 @@ func (m *Device) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovDevice(uint64(l))
 	}
+	if m.DisableAdr {
+		n += 2
+	}
 	if m.LastSeen != 0 {
 		n += 2 + sovDevice(uint64(m.LastSeen))
 	}
+	if len(m.GatewayGroups) > 0 {
+		for _, s := range m.GatewayGroups {
+			l = len(s)
+			n += 2 + l + sovDevice(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1072,6 +1127,26 @@ func (m *Device) Unmarshal(dAtA []byte) error {
 			}
 			m.ActivationConstraints = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DisableAdr", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DisableAdr = bool(v != 0)
 		case 21:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field LastSeen", wireType)
@@ -1091,6 +1166,35 @@ func (m *Device) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GatewayGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDevice
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GatewayGroups = append(m.GatewayGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDevice(dAtA[iNdEx:])
@@ -1222,42 +1326,44 @@ func init() {
 }
 
 var fileDescriptorDevice = []byte{
-	// 579 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xcc, 0x94, 0x4f, 0x6f, 0xd3, 0x3e,
-	0x18, 0xc7, 0x95, 0xdf, 0x7e, 0x6b, 0x13, 0xb3, 0x8a, 0xc9, 0x68, 0x93, 0xe9, 0xd0, 0x56, 0xed,
-	0x42, 0x2f, 0x4b, 0xc4, 0xfe, 0xc0, 0xb9, 0xff, 0x40, 0x15, 0x62, 0x12, 0xd9, 0x76, 0xe1, 0x12,
-	0xb9, 0xf1, 0xd3, 0xd4, 0x6a, 0x66, 0x5b, 0x89, 0x93, 0xa8, 0x2f, 0x80, 0x37, 0xc4, 0x3b, 0xe0,
-	0xc6, 0x91, 0xf3, 0x0e, 0x13, 0xda, 0x2b, 0x41, 0x8e, 0x3b, 0x86, 0x2a, 0xa1, 0x89, 0x9e, 0xb8,
-	0x3d, 0xf9, 0x7e, 0xbf, 0xfe, 0x3c, 0x76, 0x5d, 0x3f, 0xa8, 0x97, 0x70, 0x3d, 0x2b, 0x26, 0x7e,
-	0x2c, 0xaf, 0x83, 0xcb, 0x19, 0x5c, 0xce, 0xb8, 0x48, 0xf2, 0x73, 0xd0, 0x95, 0xcc, 0xe6, 0x81,
-	0xd6, 0x22, 0xa0, 0x8a, 0x07, 0x2a, 0x93, 0x5a, 0xc6, 0x32, 0x0d, 0x52, 0x99, 0xd1, 0x8a, 0x8a,
-	0x80, 0x41, 0xc9, 0x63, 0xf0, 0x6b, 0x1d, 0x37, 0x97, 0x6a, 0x7b, 0x2f, 0x91, 0x32, 0x49, 0xc1,
-	0xc6, 0x27, 0xc5, 0x34, 0x80, 0x6b, 0xa5, 0x17, 0x36, 0xd5, 0x3e, 0xfa, 0xad, 0x51, 0x22, 0x13,
-	0xf9, 0x90, 0x32, 0x5f, 0xf5, 0x47, 0x5d, 0xd9, 0xf8, 0xe1, 0x17, 0x07, 0x6d, 0x0f, 0xeb, 0x2e,
-	0x63, 0x06, 0x42, 0xf3, 0x29, 0x87, 0x0c, 0x9f, 0xa3, 0x26, 0x55, 0x2a, 0x82, 0x82, 0x13, 0xa7,
-	0xe3, 0x74, 0xb7, 0xfa, 0x67, 0x37, 0xb7, 0x07, 0xaf, 0x1e, 0x3b, 0x41, 0x2c, 0x33, 0x08, 0xf4,
-	0x42, 0x41, 0xee, 0xf7, 0x94, 0x1a, 0x5d, 0x8d, 0xc3, 0x06, 0x55, 0x6a, 0x54, 0x70, 0xc3, 0x63,
-	0x50, 0xd6, 0xbc, 0xff, 0xd6, 0xe2, 0x0d, 0xa1, 0xac, 0x79, 0x0c, 0xca, 0x51, 0xc1, 0x0f, 0x3f,
-	0x37, 0x50, 0xc3, 0x6e, 0xfa, 0x5f, 0xdf, 0x2a, 0xde, 0x41, 0x86, 0x1c, 0x71, 0x46, 0x36, 0x3a,
-	0x4e, 0xd7, 0x0b, 0x37, 0xa9, 0x52, 0x63, 0x66, 0x64, 0xd3, 0x86, 0x33, 0xf2, 0xbf, 0x95, 0x19,
-	0x94, 0x63, 0x86, 0x3f, 0x22, 0xd7, 0xc8, 0x94, 0xb1, 0x8c, 0x6c, 0xd6, 0xed, 0x5f, 0xdf, 0xdc,
-	0x1e, 0x1c, 0xff, 0x5d, 0xfb, 0x1e, 0x63, 0x59, 0x68, 0x4e, 0x61, 0x0a, 0x1c, 0x22, 0x4f, 0x54,
-	0xf3, 0x28, 0x8f, 0xe6, 0xb0, 0x20, 0x8d, 0xb5, 0x98, 0xe7, 0xd5, 0xfc, 0xe2, 0x3d, 0x2c, 0xc2,
-	0xa6, 0xb0, 0x85, 0x61, 0x9a, 0x43, 0x59, 0x66, 0x73, 0x2d, 0x66, 0x4f, 0x29, 0xcb, 0xa4, 0xb6,
-	0xb8, 0xbf, 0x48, 0x43, 0x74, 0xd7, 0xbd, 0x48, 0x03, 0x34, 0x3f, 0xb7, 0xe1, 0x11, 0xe4, 0x4e,
-	0xa3, 0x58, 0xe8, 0xa8, 0x50, 0xc4, 0xeb, 0x38, 0xdd, 0x56, 0xd8, 0x98, 0x0e, 0x84, 0xbe, 0x52,
-	0xf8, 0x05, 0x42, 0xd6, 0x61, 0xb2, 0x12, 0x04, 0xd5, 0x9e, 0x6b, 0xbc, 0xa1, 0xac, 0x04, 0x3e,
-	0x42, 0xcf, 0x18, 0xcf, 0xe9, 0x24, 0x85, 0xc8, 0xa6, 0xe2, 0x19, 0xc4, 0x73, 0xf2, 0xa4, 0xe3,
-	0x74, 0xdd, 0x70, 0x7b, 0x69, 0xbd, 0x1d, 0x08, 0x3d, 0x30, 0x3a, 0x7e, 0x89, 0xb6, 0x8b, 0x1c,
-	0xf2, 0x93, 0xe3, 0x68, 0xc2, 0xb5, 0x5d, 0x41, 0xb6, 0xea, 0x6c, 0xcb, 0xea, 0x7d, 0xae, 0x4d,
-	0x1a, 0x9f, 0xa1, 0x5d, 0x1a, 0x6b, 0x5e, 0x52, 0xcd, 0xa5, 0x88, 0x62, 0x29, 0x72, 0x9d, 0x51,
-	0x2e, 0x74, 0x4e, 0x5a, 0xf5, 0x3f, 0x60, 0xe7, 0xc1, 0x1d, 0x3c, 0x98, 0x78, 0x0f, 0x79, 0x29,
-	0xcd, 0x75, 0x94, 0x03, 0x08, 0xb2, 0xd3, 0x71, 0xba, 0x1b, 0xa1, 0x6b, 0x84, 0x0b, 0x00, 0x71,
-	0xfc, 0xd5, 0x41, 0x2d, 0xfb, 0x0e, 0x3e, 0x50, 0x41, 0x13, 0xc8, 0xf0, 0x1b, 0xe4, 0xbd, 0x03,
-	0xbd, 0x7c, 0x1b, 0xcf, 0xfd, 0xe5, 0xc4, 0xf0, 0x57, 0x5f, 0x78, 0xfb, 0xe9, 0x8a, 0x85, 0x4f,
-	0x91, 0x77, 0xf1, 0x6b, 0xe1, 0xaa, 0xdb, 0xde, 0xf5, 0xed, 0xc8, 0xf1, 0xef, 0x87, 0x89, 0x3f,
-	0x32, 0x23, 0x07, 0xf7, 0xd0, 0xd6, 0x10, 0x52, 0xd0, 0xf0, 0x78, 0xc7, 0x3f, 0x20, 0xfa, 0xfd,
-	0x6f, 0x77, 0xfb, 0xce, 0xf7, 0xbb, 0x7d, 0xe7, 0xc7, 0xdd, 0xbe, 0xf3, 0xe9, 0x74, 0x9d, 0x31,
-	0x39, 0x69, 0xd4, 0xca, 0xc9, 0xcf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x75, 0x3e, 0x90, 0x84, 0x65,
-	0x05, 0x00, 0x00,
+	// 619 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xcc, 0x94, 0xcb, 0x6a, 0x1b, 0x31,
+	0x14, 0x86, 0x99, 0xb8, 0xf1, 0x45, 0x89, 0xd3, 0xa0, 0x92, 0xa0, 0x3a, 0x25, 0x31, 0x81, 0x52,
+	0x6f, 0x32, 0x43, 0x73, 0x69, 0xd7, 0xbe, 0x35, 0x98, 0xd2, 0x40, 0x27, 0xc9, 0xa6, 0x9b, 0x41,
+	0x1e, 0x1d, 0x8f, 0x85, 0x1d, 0x49, 0xcc, 0x68, 0x3c, 0xf8, 0x0d, 0xfa, 0x3c, 0x7d, 0x88, 0xd2,
+	0x4d, 0xa1, 0xeb, 0x2c, 0x42, 0xc9, 0x93, 0x14, 0x49, 0x4e, 0x53, 0x02, 0x25, 0xd4, 0xab, 0xee,
+	0xce, 0xfc, 0xff, 0xaf, 0xef, 0x48, 0x96, 0x75, 0x50, 0x3b, 0xe1, 0x7a, 0x9c, 0x0f, 0xfd, 0x58,
+	0x5e, 0x05, 0x17, 0x63, 0xb8, 0x18, 0x73, 0x91, 0x64, 0x67, 0xa0, 0x0b, 0x99, 0x4e, 0x02, 0xad,
+	0x45, 0x40, 0x15, 0x0f, 0x54, 0x2a, 0xb5, 0x8c, 0xe5, 0x34, 0x98, 0xca, 0x94, 0x16, 0x54, 0x04,
+	0x0c, 0x66, 0x3c, 0x06, 0xdf, 0xea, 0xb8, 0xb2, 0x50, 0x1b, 0x3b, 0x89, 0x94, 0xc9, 0x14, 0x5c,
+	0x7c, 0x98, 0x8f, 0x02, 0xb8, 0x52, 0x7a, 0xee, 0x52, 0x8d, 0x83, 0x3f, 0x1a, 0x25, 0x32, 0x91,
+	0xf7, 0x29, 0xf3, 0x65, 0x3f, 0x6c, 0xe5, 0xe2, 0xfb, 0x5f, 0x3c, 0xb4, 0xd9, 0xb3, 0x5d, 0x06,
+	0x0c, 0x84, 0xe6, 0x23, 0x0e, 0x29, 0x3e, 0x43, 0x15, 0xaa, 0x54, 0x04, 0x39, 0x27, 0x5e, 0xd3,
+	0x6b, 0xad, 0x77, 0x4e, 0xae, 0x6f, 0xf6, 0x5e, 0x3f, 0x76, 0x82, 0x58, 0xa6, 0x10, 0xe8, 0xb9,
+	0x82, 0xcc, 0x6f, 0x2b, 0xd5, 0xbf, 0x1c, 0x84, 0x65, 0xaa, 0x54, 0x3f, 0xe7, 0x86, 0xc7, 0x60,
+	0x66, 0x79, 0x2b, 0x4b, 0xf1, 0x7a, 0x30, 0xb3, 0x3c, 0x06, 0xb3, 0x7e, 0xce, 0xf7, 0xbf, 0x97,
+	0x51, 0xd9, 0x6d, 0xfa, 0x7f, 0xdf, 0x2a, 0xde, 0x42, 0x86, 0x1c, 0x71, 0x46, 0x4a, 0x4d, 0xaf,
+	0x55, 0x0b, 0x57, 0xa9, 0x52, 0x03, 0x66, 0x64, 0xd3, 0x86, 0x33, 0xf2, 0xc4, 0xc9, 0x0c, 0x66,
+	0x03, 0x86, 0x3f, 0xa2, 0xaa, 0x91, 0x29, 0x63, 0x29, 0x59, 0xb5, 0xed, 0xdf, 0x5c, 0xdf, 0xec,
+	0x1d, 0xfe, 0x5b, 0xfb, 0x36, 0x63, 0x69, 0x58, 0x61, 0xae, 0xc0, 0x21, 0xaa, 0x89, 0x62, 0x12,
+	0x65, 0xd1, 0x04, 0xe6, 0xa4, 0xbc, 0x14, 0xf3, 0xac, 0x98, 0x9c, 0xbf, 0x87, 0x79, 0x58, 0x11,
+	0xae, 0x30, 0x4c, 0x73, 0x28, 0xc7, 0xac, 0x2c, 0xc5, 0x6c, 0x2b, 0xe5, 0x98, 0xd4, 0x15, 0x77,
+	0x17, 0x69, 0x88, 0xd5, 0x65, 0x2f, 0xd2, 0x00, 0xcd, 0xcf, 0x6d, 0x78, 0x04, 0x55, 0x47, 0x51,
+	0x2c, 0x74, 0x94, 0x2b, 0x52, 0x6b, 0x7a, 0xad, 0x7a, 0x58, 0x1e, 0x75, 0x85, 0xbe, 0x54, 0xf8,
+	0x05, 0x42, 0xce, 0x61, 0xb2, 0x10, 0x04, 0x59, 0xaf, 0x6a, 0xbc, 0x9e, 0x2c, 0x04, 0x3e, 0x40,
+	0xcf, 0x18, 0xcf, 0xe8, 0x70, 0x0a, 0x91, 0x4b, 0xc5, 0x63, 0x88, 0x27, 0x64, 0xad, 0xe9, 0xb5,
+	0xaa, 0xe1, 0xe6, 0xc2, 0x7a, 0xd7, 0x15, 0xba, 0x6b, 0x74, 0xfc, 0x0a, 0x6d, 0xe6, 0x19, 0x64,
+	0x47, 0x87, 0xd1, 0x90, 0x6b, 0xb7, 0x82, 0xac, 0xdb, 0x6c, 0xdd, 0xe9, 0x1d, 0xae, 0x4d, 0x1a,
+	0x9f, 0xa0, 0x6d, 0x1a, 0x6b, 0x3e, 0xa3, 0x9a, 0x4b, 0x11, 0xc5, 0x52, 0x64, 0x3a, 0xa5, 0x5c,
+	0xe8, 0x8c, 0xd4, 0xed, 0x3f, 0x60, 0xeb, 0xde, 0xed, 0xde, 0x9b, 0x78, 0x0f, 0xad, 0xdd, 0x6d,
+	0x87, 0xb2, 0x94, 0x6c, 0x58, 0x34, 0x5a, 0x48, 0x6d, 0x96, 0xe2, 0x1d, 0x54, 0x9b, 0xd2, 0x4c,
+	0x47, 0x19, 0x80, 0x20, 0x5b, 0x4d, 0xaf, 0x55, 0x0a, 0xab, 0x46, 0x38, 0x07, 0x10, 0xf8, 0x25,
+	0xda, 0x48, 0xa8, 0x86, 0x82, 0xce, 0xa3, 0x24, 0x95, 0xb9, 0xca, 0xc8, 0x76, 0xb3, 0xd4, 0xaa,
+	0x85, 0xf5, 0x85, 0x7a, 0x6a, 0xc5, 0xc3, 0xaf, 0x1e, 0xaa, 0xbb, 0xf7, 0xf4, 0x81, 0x0a, 0x9a,
+	0x40, 0x8a, 0xdf, 0xa2, 0xda, 0x29, 0xe8, 0xc5, 0x1b, 0x7b, 0xee, 0x2f, 0x26, 0x8f, 0xff, 0x70,
+	0x52, 0x34, 0x9e, 0x3e, 0xb0, 0xf0, 0x31, 0xaa, 0x9d, 0xff, 0x5e, 0xf8, 0xd0, 0x6d, 0x6c, 0xfb,
+	0x6e, 0x74, 0xf9, 0x77, 0x43, 0xc9, 0xef, 0x9b, 0xd1, 0x85, 0xdb, 0x68, 0xbd, 0x07, 0x53, 0xd0,
+	0xf0, 0x78, 0xc7, 0xbf, 0x20, 0x1a, 0xa5, 0xcf, 0x2b, 0x5e, 0xa7, 0xf3, 0xed, 0x76, 0xd7, 0xfb,
+	0x71, 0xbb, 0xeb, 0xfd, 0xbc, 0xdd, 0xf5, 0x3e, 0x1d, 0x2f, 0x33, 0x73, 0x87, 0x65, 0xab, 0x1c,
+	0xfd, 0x1a, 0x00, 0xc2, 0x74, 0xc3, 0xf9, 0xb2, 0x05, 0x00, 0x00,
 }
@@ -97,6 +97,8 @@ func (m *PrefixesResponse_PrefixMapping) GetUsage() []string {
 type DevAddrRequest struct {
 	// The usage constraints (see activation_constraints in device.proto)
 	Usage []string `protobuf:"bytes,1,rep,name=usage" json:"usage,omitempty"`
+	// The application of the device, whose tenant may reserve DevAddr blocks
+	AppId string `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
 }
 
 func (m *DevAddrRequest) Reset()                    { *m = DevAddrRequest{} }
@@ -111,6 +113,13 @@ func (m *DevAddrRequest) GetUsage() []string {
 	return nil
 }
 
+func (m *DevAddrRequest) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
 type DevAddrResponse struct {
 	DevAddr *github_com_TheThingsNetwork_ttn_core_types.DevAddr `protobuf:"bytes,1,opt,name=dev_addr,json=devAddr,proto3,customtype=github.com/TheThingsNetwork/ttn/core/types.DevAddr" json:"dev_addr,omitempty"`
 }
@@ -354,6 +363,12 @@ func (m *DevAddrRequest) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], s)
 		}
 	}
+	if len(m.AppId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDeviceAddress(dAtA, i, uint64(len(m.AppId)))
+		i += copy(dAtA[i:], m.AppId)
+	}
 	return i, nil
 }
 
@@ -455,6 +470,10 @@ func (m *DevAddrRequest) Size() (n int) {
 			n += 1 + l + sovDeviceAddress(uint64(l))
 		}
 	}
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovDeviceAddress(uint64(l))
+	}
 	return n
 }
 
@@ -778,6 +797,35 @@ func (m *DevAddrRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Usage = append(m.Usage, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDeviceAddress
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDeviceAddress
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDeviceAddress(dAtA[iNdEx:])
@@ -991,28 +1039,29 @@ func init() {
 }
 
 var fileDescriptorDeviceAddress = []byte{
-	// 362 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x52, 0x4d, 0x4f, 0xe3, 0x30,
-	0x14, 0x94, 0xb7, 0xda, 0x7e, 0xb8, 0xbb, 0xdb, 0x5d, 0x6b, 0x05, 0x21, 0x87, 0x52, 0xf5, 0x00,
-	0xbd, 0x90, 0x48, 0x05, 0x71, 0x43, 0x88, 0x80, 0x54, 0x71, 0x28, 0x82, 0xa8, 0x27, 0x2e, 0xc8,
-	0x8d, 0x5f, 0xd3, 0x88, 0x12, 0x07, 0xdb, 0x69, 0xe1, 0x87, 0xc0, 0x6f, 0xe2, 0xc8, 0x99, 0x03,
-	0x42, 0xfd, 0x25, 0x48, 0x8e, 0x9b, 0xb6, 0x7c, 0x08, 0x89, 0x9b, 0xdf, 0xcc, 0xbc, 0x79, 0x93,
-	0x51, 0xf0, 0x71, 0x18, 0xa9, 0x61, 0xda, 0x77, 0x02, 0x7e, 0xe5, 0xf6, 0x86, 0xd0, 0x1b, 0x46,
-	0x71, 0x28, 0x4f, 0x40, 0x4d, 0xb8, 0xb8, 0x74, 0x95, 0x8a, 0x5d, 0x9a, 0x44, 0x6e, 0x22, 0xb8,
-	0xe2, 0x01, 0x1f, 0xb9, 0x23, 0x2e, 0xe8, 0x84, 0xc6, 0x2e, 0x83, 0x71, 0x14, 0xc0, 0x05, 0x65,
-	0x4c, 0x80, 0x94, 0x8e, 0xe6, 0x49, 0xc9, 0xb0, 0xf6, 0xd6, 0x82, 0x67, 0xc8, 0x43, 0x9e, 0xed,
-	0xf7, 0xd3, 0x81, 0x9e, 0xf4, 0xa0, 0x5f, 0xd9, 0x5e, 0xf3, 0x1f, 0xae, 0x9d, 0x0a, 0x18, 0x44,
-	0x37, 0x20, 0x7d, 0xb8, 0x4e, 0x41, 0xaa, 0xe6, 0x3d, 0xc2, 0x7f, 0xe7, 0x98, 0x4c, 0x78, 0x2c,
-	0x81, 0x1c, 0xe2, 0x72, 0x62, 0x30, 0x0b, 0x35, 0x0a, 0xad, 0x6a, 0x7b, 0xd3, 0x31, 0x27, 0x9d,
-	0xb7, 0x62, 0x03, 0x74, 0x69, 0x92, 0x44, 0x71, 0xe8, 0xe7, 0x8b, 0xf6, 0x1e, 0xfe, 0xbd, 0x44,
-	0x91, 0x15, 0x5c, 0xcc, 0x48, 0x0b, 0x35, 0x50, 0xab, 0xe2, 0x9b, 0x89, 0xfc, 0xc7, 0x3f, 0x53,
-	0x49, 0x43, 0xb0, 0x7e, 0x34, 0x0a, 0xad, 0x8a, 0x9f, 0x0d, 0xcd, 0x0d, 0xfc, 0xe7, 0x08, 0xc6,
-	0x07, 0x8c, 0x09, 0x13, 0x75, 0xae, 0x43, 0x8b, 0x3a, 0x86, 0x6b, 0xb9, 0xce, 0xc4, 0x3f, 0xc3,
-	0x65, 0x06, 0x63, 0xdd, 0x99, 0x3e, 0xf5, 0xcb, 0xdb, 0x7d, 0x7a, 0x5e, 0x6f, 0x7f, 0xd5, 0x7f,
-	0xc0, 0x05, 0xb8, 0xea, 0x36, 0x01, 0xe9, 0xcc, 0x1c, 0x4b, 0x2c, 0x7b, 0xb4, 0xef, 0x50, 0x1e,
-	0xa7, 0x4b, 0x63, 0x1a, 0x82, 0x20, 0x1e, 0xae, 0x76, 0x40, 0xcd, 0xea, 0x20, 0xd6, 0x07, 0x0d,
-	0xe9, 0xdc, 0xf6, 0xda, 0xa7, 0xdd, 0x91, 0x7d, 0x8c, 0x3b, 0xa0, 0x8c, 0x31, 0x59, 0xcd, 0x85,
-	0xcb, 0x5f, 0x6e, 0x5b, 0xef, 0x89, 0xcc, 0xc0, 0xf3, 0x1e, 0xa6, 0x75, 0xf4, 0x38, 0xad, 0xa3,
-	0x97, 0x69, 0x1d, 0x9d, 0xef, 0x7c, 0xe7, 0x17, 0xeb, 0x17, 0x35, 0xb2, 0xfd, 0x1a, 0x00, 0x00,
-	0xff, 0xff, 0x63, 0xd3, 0xbe, 0xdc, 0xa1, 0x02, 0x00, 0x00,
+	// 375 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x52, 0xcb, 0x8e, 0xd3, 0x30,
+	0x14, 0x95, 0x5b, 0xf5, 0xe5, 0x02, 0x05, 0x8b, 0x47, 0xc8, 0xa2, 0x54, 0xd9, 0xd0, 0x0d, 0x89,
+	0x54, 0x10, 0xbb, 0x0a, 0x11, 0x90, 0xaa, 0x2e, 0x8a, 0x20, 0xea, 0x8a, 0x4d, 0xe5, 0xc6, 0xb7,
+	0x69, 0x44, 0x89, 0x8d, 0xed, 0xb4, 0xf0, 0x21, 0xcc, 0x37, 0xcd, 0x72, 0xd6, 0xb3, 0x18, 0x8d,
+	0xfa, 0x25, 0x23, 0x39, 0x6e, 0xda, 0xce, 0x43, 0x23, 0xcd, 0xce, 0xf7, 0xbc, 0x72, 0x7d, 0x62,
+	0x3c, 0x4e, 0x52, 0xbd, 0xcc, 0xe7, 0x7e, 0xcc, 0x7f, 0x07, 0xd3, 0x25, 0x4c, 0x97, 0x69, 0x96,
+	0xa8, 0x6f, 0xa0, 0x37, 0x5c, 0xfe, 0x0a, 0xb4, 0xce, 0x02, 0x2a, 0xd2, 0x40, 0x48, 0xae, 0x79,
+	0xcc, 0x57, 0xc1, 0x8a, 0x4b, 0xba, 0xa1, 0x59, 0xc0, 0x60, 0x9d, 0xc6, 0x30, 0xa3, 0x8c, 0x49,
+	0x50, 0xca, 0x37, 0x3c, 0x69, 0x58, 0xd6, 0x7d, 0x77, 0x90, 0x99, 0xf0, 0x84, 0x17, 0xfe, 0x79,
+	0xbe, 0x30, 0x93, 0x19, 0xcc, 0xa9, 0xf0, 0x79, 0xcf, 0x70, 0xe7, 0xbb, 0x84, 0x45, 0xfa, 0x17,
+	0x54, 0x04, 0x7f, 0x72, 0x50, 0xda, 0x3b, 0x41, 0xf8, 0xe9, 0x1e, 0x53, 0x82, 0x67, 0x0a, 0xc8,
+	0x17, 0xdc, 0x14, 0x16, 0x73, 0x50, 0xaf, 0xda, 0x6f, 0x0f, 0xde, 0xfa, 0xf6, 0x93, 0xfe, 0x75,
+	0xb1, 0x05, 0x26, 0x54, 0x88, 0x34, 0x4b, 0xa2, 0xd2, 0xe8, 0x0e, 0xf1, 0xe3, 0x23, 0x8a, 0xbc,
+	0xc4, 0xf5, 0x82, 0x74, 0x50, 0x0f, 0xf5, 0x5b, 0x91, 0x9d, 0xc8, 0x73, 0x5c, 0xcb, 0x15, 0x4d,
+	0xc0, 0xa9, 0xf4, 0xaa, 0xfd, 0x56, 0x54, 0x0c, 0xde, 0x10, 0x3f, 0xf9, 0x0a, 0xeb, 0xcf, 0x8c,
+	0x49, 0xbb, 0xea, 0x5e, 0x87, 0x0e, 0x74, 0xe4, 0x05, 0xae, 0x53, 0x21, 0x66, 0x29, 0x73, 0x2a,
+	0x26, 0xb5, 0x46, 0x85, 0x18, 0x33, 0x8f, 0xe1, 0x4e, 0x69, 0xb7, 0xb7, 0xfa, 0x81, 0x9b, 0x0c,
+	0xd6, 0xa6, 0x4a, 0xb3, 0xc1, 0xa3, 0xf0, 0xe3, 0xf9, 0xc5, 0x9b, 0xc1, 0x7d, 0xbf, 0x25, 0xe6,
+	0x12, 0x02, 0xfd, 0x4f, 0x80, 0xf2, 0x77, 0x89, 0x0d, 0x56, 0x1c, 0x06, 0xff, 0x51, 0xb9, 0xe5,
+	0x84, 0x66, 0x34, 0x01, 0x49, 0x42, 0xdc, 0x1e, 0x81, 0xde, 0xb5, 0x44, 0x9c, 0x5b, 0x8a, 0x33,
+	0xd7, 0x71, 0x5f, 0xdf, 0x59, 0x29, 0xf9, 0x84, 0xf1, 0x08, 0xb4, 0x0d, 0x26, 0xaf, 0x4a, 0xe1,
+	0x71, 0x21, 0xae, 0x73, 0x93, 0x28, 0x02, 0xc2, 0xf0, 0x74, 0xdb, 0x45, 0x67, 0xdb, 0x2e, 0xba,
+	0xdc, 0x76, 0xd1, 0xcf, 0x0f, 0x0f, 0x79, 0x79, 0xf3, 0xba, 0x41, 0xde, 0x5f, 0x0d, 0x00, 0x75,
+	0x77, 0xb2, 0x3e, 0xb8, 0x02, 0x00, 0x00,
 }
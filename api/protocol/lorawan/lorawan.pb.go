@@ -167,6 +167,8 @@ type Metadata struct {
 	// Store the full 32 bit FCnt (deprecated; do not use)
 	FCnt   uint32 `protobuf:"varint,15,opt,name=f_cnt,json=fCnt,proto3" json:"f_cnt,omitempty"`
 	Region Region `protobuf:"varint,16,opt,name=region,proto3,enum=lorawan.Region" json:"region,omitempty"`
+	// Time-on-air of the message in nanoseconds, computed by the Router
+	Airtime int64 `protobuf:"varint,17,opt,name=airtime,proto3" json:"airtime,omitempty"`
 }
 
 func (m *Metadata) Reset()                    { *m = Metadata{} }
@@ -216,6 +218,13 @@ func (m *Metadata) GetRegion() Region {
 	return Region_EU_863_870
 }
 
+func (m *Metadata) GetAirtime() int64 {
+	if m != nil {
+		return m.Airtime
+	}
+	return 0
+}
+
 type TxConfiguration struct {
 	Modulation Modulation `protobuf:"varint,11,opt,name=modulation,proto3,enum=lorawan.Modulation" json:"modulation,omitempty"`
 	// LoRa data rate - SF{spreadingfactor}BW{bandwidth}
@@ -777,6 +786,13 @@ func (m *Metadata) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintLorawan(dAtA, i, uint64(m.Region))
 	}
+	if m.Airtime != 0 {
+		dAtA[i] = 0x88
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintLorawan(dAtA, i, uint64(m.Airtime))
+	}
 	return i, nil
 }
 
@@ -1423,6 +1439,9 @@ func (m *Metadata) Size() (n int) {
 	if m.Region != 0 {
 		n += 2 + sovLorawan(uint64(m.Region))
 	}
+	if m.Airtime != 0 {
+		n += 2 + sovLorawan(uint64(m.Airtime))
+	}
 	return n
 }
 
@@ -1844,6 +1863,25 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Airtime", wireType)
+			}
+			m.Airtime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLorawan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Airtime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLorawan(dAtA[iNdEx:])
@@ -3835,87 +3873,88 @@ func init() {
 }
 
 var fileDescriptorLorawan = []byte{
-	// 1300 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xcc, 0x57, 0xcb, 0x6e, 0xdb, 0xc6,
-	0x1a, 0x36, 0x2d, 0x52, 0x97, 0x5f, 0x96, 0xcd, 0x4c, 0x12, 0x1c, 0x9d, 0x24, 0xb0, 0x0d, 0xe1,
-	0x1c, 0x1c, 0xc3, 0x38, 0xb5, 0x64, 0x29, 0x89, 0xad, 0x16, 0x28, 0xa0, 0x9b, 0x1b, 0x27, 0xb6,
-	0xe4, 0x8c, 0x2c, 0xa4, 0xe8, 0x66, 0x40, 0x93, 0x43, 0x99, 0x96, 0x78, 0xc9, 0x68, 0x64, 0x4b,
-	0x5d, 0x75, 0xd1, 0x67, 0xe8, 0x4b, 0x74, 0xdb, 0x45, 0x1f, 0x21, 0xcb, 0x6c, 0xda, 0x45, 0x16,
-	0x46, 0x91, 0x27, 0x29, 0x66, 0x48, 0x59, 0xb2, 0xdc, 0xa6, 0x88, 0xd3, 0x45, 0x57, 0xfc, 0xaf,
-	0xdf, 0xfc, 0x33, 0xff, 0x4d, 0x82, 0x6a, 0xd7, 0xe1, 0xa7, 0xc3, 0x93, 0x2d, 0xd3, 0x77, 0xf3,
-	0xc7, 0xa7, 0xf4, 0xf8, 0xd4, 0xf1, 0xba, 0x83, 0x26, 0xe5, 0x17, 0x3e, 0xeb, 0xe5, 0x39, 0xf7,
-	0xf2, 0x46, 0xe0, 0xe4, 0x03, 0xe6, 0x73, 0xdf, 0xf4, 0xfb, 0xf9, 0xbe, 0xcf, 0x8c, 0x0b, 0xc3,
-	0x9b, 0x7c, 0xb7, 0xa4, 0x02, 0x25, 0x22, 0xf6, 0xc1, 0x67, 0x33, 0x60, 0x5d, 0xbf, 0xeb, 0x87,
-	0x8e, 0x27, 0x43, 0x5b, 0x72, 0x92, 0x91, 0x54, 0xe8, 0x97, 0xfb, 0x55, 0x81, 0xe4, 0x21, 0xe5,
-	0x86, 0x65, 0x70, 0x03, 0x95, 0x00, 0x5c, 0xdf, 0x1a, 0xf6, 0x0d, 0xee, 0xf8, 0x5e, 0x36, 0xbd,
-	0xae, 0x6c, 0x2c, 0x17, 0xef, 0x6e, 0x4d, 0x0e, 0x3a, 0xbc, 0x52, 0xe1, 0x19, 0x33, 0xf4, 0x10,
-	0x52, 0xc2, 0x99, 0x30, 0x83, 0xd3, 0xec, 0xd2, 0xba, 0xb2, 0x91, 0xc2, 0x49, 0x21, 0xc0, 0x06,
-	0xa7, 0xe8, 0xdf, 0x90, 0x3c, 0x71, 0x78, 0xa8, 0xcb, 0xac, 0x2b, 0x1b, 0x19, 0x9c, 0x38, 0x71,
-	0xb8, 0x54, 0xad, 0x41, 0xda, 0xf4, 0x2d, 0xc7, 0xeb, 0x86, 0xda, 0x65, 0xe9, 0x09, 0xa1, 0x48,
-	0x1a, 0xdc, 0x05, 0xcd, 0x26, 0xa6, 0xc7, 0xb3, 0x2b, 0xd2, 0x51, 0xb5, 0x6b, 0x1e, 0x47, 0xff,
-	0x83, 0x38, 0xa3, 0x5d, 0x11, 0x9e, 0x2e, 0xc3, 0x5b, 0xb9, 0x0a, 0x0f, 0x4b, 0x31, 0x8e, 0xd4,
-	0xb9, 0x9f, 0x14, 0x58, 0x39, 0x1e, 0xd5, 0x7c, 0xcf, 0x76, 0xba, 0x43, 0x16, 0x86, 0xfa, 0xcf,
-	0xbf, 0x5f, 0xee, 0x7b, 0x15, 0x50, 0xc5, 0xe4, 0xce, 0xb9, 0x3c, 0xfc, 0x2a, 0x33, 0x4d, 0x48,
-	0x18, 0x41, 0x40, 0xe8, 0xd0, 0xc9, 0x2a, 0xeb, 0xca, 0xc6, 0x52, 0xf5, 0xc9, 0xbb, 0xcb, 0xb5,
-	0xed, 0xbf, 0xaa, 0x1b, 0xd3, 0x67, 0x34, 0xcf, 0xc7, 0x01, 0x1d, 0x6c, 0x55, 0x82, 0xa0, 0xd1,
-	0xd9, 0xc7, 0x71, 0x23, 0x08, 0x1a, 0x43, 0x47, 0xe0, 0x59, 0xf4, 0x5c, 0xe2, 0x2d, 0xde, 0x0a,
-	0xaf, 0x4e, 0xcf, 0x25, 0x9e, 0x45, 0xcf, 0x05, 0xde, 0x4b, 0x48, 0x0a, 0x3c, 0xc3, 0xb2, 0x58,
-	0x36, 0x26, 0x01, 0x9f, 0xbe, 0xbb, 0x5c, 0x2b, 0x7e, 0x1c, 0x60, 0xc5, 0xb2, 0x18, 0x16, 0x71,
-	0x09, 0x02, 0x61, 0x48, 0x79, 0x17, 0x3d, 0x32, 0x20, 0x3d, 0x3a, 0xce, 0xaa, 0xb7, 0xc2, 0x6c,
-	0x5e, 0xf4, 0xda, 0x2f, 0xe8, 0x18, 0x27, 0xbc, 0x90, 0x40, 0x39, 0xc8, 0xb0, 0xd1, 0x36, 0xb1,
-	0x18, 0xf1, 0x6d, 0x7b, 0x40, 0xb9, 0xac, 0x81, 0x0c, 0x4e, 0xb3, 0xd1, 0x76, 0x9d, 0xb5, 0xa4,
-	0x08, 0xdd, 0x87, 0x38, 0x1b, 0x15, 0x89, 0xc5, 0x64, 0xb2, 0x33, 0x58, 0x63, 0xa3, 0x62, 0x9d,
-	0x89, 0x4c, 0xb3, 0x11, 0xb1, 0x68, 0xdf, 0x18, 0x4f, 0x32, 0xcd, 0x46, 0x75, 0xc1, 0xa2, 0x0d,
-	0x48, 0x98, 0x36, 0xe9, 0x3b, 0x03, 0x2e, 0xb3, 0x9c, 0x9e, 0x29, 0xca, 0xda, 0xde, 0x81, 0x33,
-	0xe0, 0x38, 0x6e, 0xda, 0xe2, 0x3b, 0x53, 0xbd, 0x2b, 0x1f, 0xae, 0xde, 0x1f, 0x17, 0x21, 0x71,
-	0x48, 0x07, 0x03, 0xa3, 0x4b, 0xd1, 0xff, 0x41, 0x73, 0xc9, 0xa9, 0xc5, 0x64, 0xe6, 0xd3, 0xc5,
-	0xcc, 0xb4, 0x60, 0x9f, 0xd5, 0x71, 0x35, 0xf9, 0xe6, 0x72, 0x6d, 0xe1, 0xed, 0xe5, 0x9a, 0x82,
-	0x55, 0xf7, 0x99, 0xc5, 0x90, 0x0e, 0x31, 0xd7, 0x31, 0xc3, 0xac, 0x62, 0x41, 0xa2, 0xa7, 0x90,
-	0x76, 0x0d, 0x93, 0x04, 0xc6, 0xb8, 0xef, 0x1b, 0x96, 0x4c, 0x4f, 0x7a, 0xb6, 0xec, 0x2b, 0xb5,
-	0xa3, 0x50, 0xf5, 0x6c, 0x01, 0x83, 0x6b, 0x98, 0x11, 0x87, 0x5a, 0x70, 0xef, 0xcc, 0x77, 0x3c,
-	0xc2, 0xe8, 0xeb, 0x21, 0x1d, 0xf0, 0x2b, 0x00, 0x55, 0x02, 0x3c, 0xbc, 0x02, 0x78, 0xee, 0x3b,
-	0x1e, 0x0e, 0x6d, 0xa6, 0x40, 0xe8, 0xec, 0x86, 0x14, 0x1d, 0xc0, 0x5d, 0x09, 0x68, 0x98, 0x26,
-	0x0d, 0xa6, 0x78, 0x9a, 0xc4, 0x7b, 0x70, 0x0d, 0xaf, 0x22, 0x4d, 0xa6, 0x70, 0x77, 0xce, 0xe6,
-	0x85, 0xd5, 0x14, 0x24, 0x22, 0x32, 0xd7, 0x06, 0x55, 0xbc, 0x05, 0xfa, 0x2f, 0xc4, 0x5d, 0x22,
-	0x52, 0x2f, 0x9f, 0x6a, 0xb9, 0xb8, 0x3c, 0xbd, 0xe4, 0xf1, 0x38, 0xa0, 0x58, 0x73, 0xc5, 0x07,
-	0xfd, 0x07, 0x34, 0xd7, 0x38, 0xf3, 0x99, 0x7c, 0xa4, 0x6b, 0x56, 0x42, 0x8a, 0x43, 0x65, 0x8e,
-	0x01, 0x4c, 0x9f, 0x46, 0x24, 0xc1, 0xfe, 0xc3, 0x24, 0xec, 0xcd, 0x25, 0xc1, 0x16, 0x49, 0xb8,
-	0x0f, 0x71, 0x9b, 0x04, 0x3e, 0xe3, 0xf2, 0x08, 0x0d, 0x6b, 0xf6, 0x91, 0xcf, 0xb8, 0x18, 0x09,
-	0x36, 0x73, 0xaf, 0x65, 0x62, 0x09, 0x83, 0xcd, 0xdc, 0xc9, 0x45, 0x7e, 0x51, 0x40, 0x15, 0x80,
-	0xa8, 0x33, 0xd3, 0x4f, 0x61, 0xc3, 0x7f, 0x2e, 0x8e, 0xf8, 0xd4, 0x9e, 0xca, 0x8b, 0xb8, 0x4c,
-	0xce, 0xfa, 0x32, 0xae, 0xf4, 0xcc, 0xd5, 0xf7, 0x6a, 0x9c, 0xf5, 0x67, 0xee, 0xa1, 0xd9, 0x42,
-	0x30, 0x9d, 0x51, 0xb1, 0x99, 0x19, 0x5c, 0x10, 0x28, 0x7e, 0xc0, 0x07, 0x59, 0x75, 0x3d, 0x36,
-	0x5f, 0x4b, 0x35, 0xdf, 0x75, 0x0d, 0xcf, 0xaa, 0xaa, 0x02, 0x0a, 0x6b, 0x76, 0x2b, 0xe0, 0x83,
-	0xdc, 0x29, 0x68, 0xf2, 0x00, 0x51, 0x9d, 0x46, 0x74, 0xa5, 0x24, 0x16, 0x24, 0x5a, 0x85, 0xb4,
-	0x61, 0x31, 0x62, 0x98, 0x3d, 0x51, 0x68, 0x32, 0xae, 0x24, 0x4e, 0x19, 0x16, 0xab, 0x98, 0x3d,
-	0x4c, 0x5f, 0x4b, 0x0f, 0xb3, 0x27, 0xcf, 0x17, 0x1e, 0x66, 0x4f, 0x0c, 0x64, 0x9b, 0x04, 0xd4,
-	0x13, 0x83, 0x54, 0x16, 0x63, 0x12, 0x27, 0xed, 0xa3, 0x90, 0xcf, 0xed, 0xca, 0xac, 0x45, 0x41,
-	0x08, 0x67, 0xd3, 0xb1, 0xe4, 0x71, 0x19, 0x2c, 0x48, 0x94, 0x85, 0xc4, 0xe4, 0xf9, 0xc3, 0x16,
-	0x99, 0xb0, 0xb9, 0x1f, 0x16, 0x01, 0xdd, 0x2c, 0x65, 0x84, 0xe7, 0x27, 0x6f, 0x39, 0x4a, 0xc4,
-	0x27, 0x4c, 0x5f, 0x3c, 0x3f, 0x7d, 0x6f, 0x83, 0x39, 0x37, 0x81, 0xbf, 0x86, 0x94, 0xc0, 0xf4,
-	0x7c, 0xcf, 0xa4, 0xd1, 0x08, 0xfe, 0x22, 0x42, 0x2d, 0x7d, 0x1c, 0x6a, 0x53, 0x40, 0x60, 0x51,
-	0x7f, 0x92, 0xca, 0xfd, 0x1c, 0x83, 0x3b, 0x37, 0x7a, 0x12, 0x3d, 0x82, 0x14, 0xf5, 0x4c, 0x36,
-	0x0e, 0x38, 0x0d, 0x1f, 0x78, 0x09, 0x4f, 0x05, 0x22, 0x1a, 0xf1, 0x6a, 0x61, 0x34, 0x8b, 0xb7,
-	0x8e, 0xa6, 0x12, 0x04, 0x51, 0x34, 0x46, 0x44, 0xa1, 0x16, 0xc4, 0x3d, 0xca, 0x89, 0x13, 0xb5,
+	// 1313 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xcc, 0x57, 0xcb, 0x72, 0x1a, 0xc7,
+	0x1a, 0xd6, 0xc0, 0x0c, 0xa0, 0x1f, 0x5d, 0xc6, 0x6d, 0xbb, 0x0e, 0xc7, 0x76, 0x49, 0x14, 0x75,
+	0x4e, 0x1d, 0x95, 0xea, 0x44, 0x20, 0xb0, 0x2d, 0x91, 0x54, 0xa5, 0x8a, 0x9b, 0x62, 0xd9, 0x12,
+	0xc8, 0x8d, 0x28, 0xa7, 0xb2, 0xe9, 0x1a, 0xcd, 0xf4, 0xa0, 0x11, 0xcc, 0xc5, 0x4d, 0x23, 0x41,
+	0x56, 0x59, 0xe4, 0x19, 0xf2, 0x12, 0xd9, 0x66, 0x91, 0x47, 0xf0, 0xd2, 0x9b, 0x6c, 0xbc, 0x50,
+	0xa5, 0xfc, 0x04, 0x79, 0x84, 0x54, 0xf7, 0x0c, 0x02, 0xa1, 0xc4, 0x29, 0xcb, 0x59, 0x64, 0x35,
+	0xff, 0xf5, 0xeb, 0xbf, 0xfb, 0xbf, 0x01, 0x54, 0xbb, 0x0e, 0x3f, 0x1d, 0x9e, 0x6c, 0x99, 0xbe,
+	0x9b, 0x3f, 0x3e, 0xa5, 0xc7, 0xa7, 0x8e, 0xd7, 0x1d, 0x34, 0x29, 0xbf, 0xf0, 0x59, 0x2f, 0xcf,
+	0xb9, 0x97, 0x37, 0x02, 0x27, 0x1f, 0x30, 0x9f, 0xfb, 0xa6, 0xdf, 0xcf, 0xf7, 0x7d, 0x66, 0x5c,
+	0x18, 0xde, 0xe4, 0xbb, 0x25, 0x15, 0x28, 0x19, 0xb1, 0x0f, 0x3e, 0x9b, 0x01, 0xeb, 0xfa, 0x5d,
+	0x3f, 0x74, 0x3c, 0x19, 0xda, 0x92, 0x93, 0x8c, 0xa4, 0x42, 0xbf, 0xdc, 0x6f, 0x0a, 0xa4, 0x0e,
+	0x29, 0x37, 0x2c, 0x83, 0x1b, 0xa8, 0x04, 0xe0, 0xfa, 0xd6, 0xb0, 0x6f, 0x70, 0xc7, 0xf7, 0x32,
+	0xe9, 0xac, 0xb2, 0xb1, 0x52, 0xbc, 0xbb, 0x35, 0x39, 0xe8, 0xf0, 0x4a, 0x85, 0x67, 0xcc, 0xd0,
+	0x43, 0x58, 0x14, 0xce, 0x84, 0x19, 0x9c, 0x66, 0x96, 0xb2, 0xca, 0xc6, 0x22, 0x4e, 0x09, 0x01,
+	0x36, 0x38, 0x45, 0xff, 0x86, 0xd4, 0x89, 0xc3, 0x43, 0xdd, 0x72, 0x56, 0xd9, 0x58, 0xc6, 0xc9,
+	0x13, 0x87, 0x4b, 0xd5, 0x3a, 0xa4, 0x4d, 0xdf, 0x72, 0xbc, 0x6e, 0xa8, 0x5d, 0x91, 0x9e, 0x10,
+	0x8a, 0xa4, 0xc1, 0x5d, 0xd0, 0x6c, 0x62, 0x7a, 0x3c, 0xb3, 0x2a, 0x1d, 0x55, 0xbb, 0xe6, 0x71,
+	0xf4, 0x3f, 0x48, 0x30, 0xda, 0x15, 0xe1, 0xe9, 0x32, 0xbc, 0xd5, 0xab, 0xf0, 0xb0, 0x14, 0xe3,
+	0x48, 0x8d, 0x32, 0x90, 0x34, 0x1c, 0xc6, 0x1d, 0x97, 0x66, 0xee, 0x64, 0x95, 0x8d, 0x38, 0x9e,
+	0xb0, 0xb9, 0x9f, 0x14, 0x58, 0x3d, 0x1e, 0xd5, 0x7c, 0xcf, 0x76, 0xba, 0x43, 0x16, 0x5e, 0xe2,
+	0x9f, 0x7f, 0xf3, 0xdc, 0xf7, 0x2a, 0xa0, 0x8a, 0xc9, 0x9d, 0x73, 0x79, 0xf8, 0x55, 0xce, 0x9a,
+	0x90, 0x34, 0x82, 0x80, 0xd0, 0xa1, 0x93, 0x51, 0xb2, 0xca, 0xc6, 0x52, 0xf5, 0xc9, 0xbb, 0xcb,
+	0xf5, 0xed, 0xbf, 0xaa, 0x28, 0xd3, 0x67, 0x34, 0xcf, 0xc7, 0x01, 0x1d, 0x6c, 0x55, 0x82, 0xa0,
+	0xd1, 0xd9, 0xc7, 0x09, 0x23, 0x08, 0x1a, 0x43, 0x47, 0xe0, 0x59, 0xf4, 0x5c, 0xe2, 0xc5, 0x6e,
+	0x85, 0x57, 0xa7, 0xe7, 0x12, 0xcf, 0xa2, 0xe7, 0x02, 0xef, 0x25, 0xa4, 0x04, 0x9e, 0x61, 0x59,
+	0x2c, 0x13, 0x97, 0x80, 0x4f, 0xdf, 0x5d, 0xae, 0x17, 0x3f, 0x0e, 0xb0, 0x62, 0x59, 0x0c, 0x27,
+	0xad, 0x90, 0x40, 0x18, 0x16, 0xbd, 0x8b, 0x1e, 0x19, 0x90, 0x1e, 0x1d, 0x67, 0xd4, 0x5b, 0x61,
+	0x36, 0x2f, 0x7a, 0xed, 0x17, 0x74, 0x8c, 0x93, 0x5e, 0x48, 0xa0, 0x1c, 0x2c, 0xb3, 0xd1, 0x36,
+	0xb1, 0x18, 0xf1, 0x6d, 0x7b, 0x40, 0xb9, 0xac, 0x81, 0x65, 0x9c, 0x66, 0xa3, 0xed, 0x3a, 0x6b,
+	0x49, 0x11, 0xba, 0x0f, 0x09, 0x36, 0x2a, 0x12, 0x8b, 0xc9, 0x64, 0x2f, 0x63, 0x8d, 0x8d, 0x8a,
+	0x75, 0x26, 0x32, 0xcd, 0x46, 0xc4, 0xa2, 0x7d, 0x63, 0x3c, 0xc9, 0x34, 0x1b, 0xd5, 0x05, 0x8b,
+	0x36, 0x20, 0x69, 0xda, 0xa4, 0xef, 0x0c, 0xb8, 0xcc, 0x72, 0x7a, 0xa6, 0x5c, 0x6b, 0x7b, 0x07,
+	0xce, 0x80, 0xe3, 0x84, 0x69, 0x8b, 0xef, 0x4c, 0x5d, 0xaf, 0x7e, 0xb0, 0xae, 0x73, 0x3f, 0xc6,
+	0x20, 0x79, 0x48, 0x07, 0x03, 0xa3, 0x4b, 0xd1, 0xff, 0x41, 0x73, 0xc9, 0xa9, 0xc5, 0x64, 0xe6,
+	0xd3, 0xc5, 0xe5, 0x69, 0xc1, 0x3e, 0xab, 0xe3, 0x6a, 0xea, 0xcd, 0xe5, 0xfa, 0xc2, 0xdb, 0xcb,
+	0x75, 0x05, 0xab, 0xee, 0x33, 0x8b, 0x21, 0x1d, 0xe2, 0xae, 0x63, 0x86, 0x59, 0xc5, 0x82, 0x44,
+	0x4f, 0x21, 0xed, 0x1a, 0x26, 0x09, 0x8c, 0x71, 0xdf, 0x37, 0x2c, 0x99, 0x9e, 0xf4, 0x6c, 0xd9,
+	0x57, 0x6a, 0x47, 0xa1, 0xea, 0xd9, 0x02, 0x06, 0xd7, 0x30, 0x23, 0x0e, 0xb5, 0xe0, 0xde, 0x99,
+	0xef, 0x78, 0x84, 0xd1, 0xd7, 0x43, 0x3a, 0xe0, 0x57, 0x00, 0xaa, 0x04, 0x78, 0x78, 0x05, 0xf0,
+	0xdc, 0x77, 0x3c, 0x1c, 0xda, 0x4c, 0x81, 0xd0, 0xd9, 0x0d, 0x29, 0x3a, 0x80, 0xbb, 0x12, 0xd0,
+	0x30, 0x4d, 0x1a, 0x4c, 0xf1, 0x34, 0x89, 0xf7, 0xe0, 0x1a, 0x5e, 0x45, 0x9a, 0x4c, 0xe1, 0xee,
+	0x9c, 0xcd, 0x0b, 0xab, 0x8b, 0x90, 0x8c, 0xc8, 0x5c, 0x1b, 0x54, 0xf1, 0x16, 0xe8, 0xbf, 0x90,
+	0x70, 0x89, 0x48, 0xbd, 0x7c, 0xaa, 0x95, 0xe2, 0xca, 0xf4, 0x92, 0xc7, 0xe3, 0x80, 0x62, 0xcd,
+	0x15, 0x1f, 0xf4, 0x1f, 0xd0, 0x5c, 0xe3, 0xcc, 0x67, 0x99, 0xd8, 0xbc, 0x95, 0x90, 0xe2, 0x50,
+	0x99, 0x63, 0x00, 0xd3, 0xa7, 0x11, 0x49, 0xb0, 0xff, 0x30, 0x09, 0x7b, 0x73, 0x49, 0xb0, 0x45,
+	0x12, 0xee, 0x43, 0xc2, 0x26, 0x81, 0xcf, 0xb8, 0x3c, 0x42, 0xc3, 0x9a, 0x7d, 0xe4, 0x33, 0x2e,
+	0x46, 0x82, 0xcd, 0xdc, 0x6b, 0x99, 0x58, 0xc2, 0x60, 0x33, 0x77, 0x72, 0x91, 0x5f, 0x14, 0x50,
+	0x05, 0x20, 0xea, 0xcc, 0xf4, 0x53, 0xd8, 0xf0, 0x9f, 0x8b, 0x23, 0x3e, 0xb5, 0xa7, 0xf2, 0x22,
+	0x2e, 0x93, 0xb3, 0xbe, 0x8c, 0x2b, 0x3d, 0x73, 0xf5, 0xbd, 0x1a, 0x67, 0xfd, 0x99, 0x7b, 0x68,
+	0xb6, 0x10, 0x4c, 0x67, 0x54, 0x7c, 0x66, 0x3a, 0x17, 0x04, 0x8a, 0x1f, 0xf0, 0x41, 0x46, 0xcd,
+	0xc6, 0xe7, 0x6b, 0xa9, 0xe6, 0xbb, 0xae, 0xe1, 0x59, 0x55, 0x55, 0x40, 0x61, 0xcd, 0x6e, 0x05,
+	0x7c, 0x90, 0x3b, 0x05, 0x4d, 0x1e, 0x20, 0xaa, 0xd3, 0x88, 0xae, 0x94, 0xc2, 0x82, 0x44, 0x6b,
+	0x90, 0x36, 0x2c, 0x46, 0x0c, 0xb3, 0x27, 0x0a, 0x4d, 0xc6, 0x95, 0xc2, 0x8b, 0x86, 0xc5, 0x2a,
+	0x66, 0x0f, 0xd3, 0xd7, 0xd2, 0xc3, 0xec, 0x65, 0xe2, 0x91, 0x87, 0xd9, 0x13, 0x03, 0xd9, 0x26,
+	0x01, 0xf5, 0xc4, 0x20, 0x95, 0xc5, 0x98, 0xc2, 0x29, 0xfb, 0x28, 0xe4, 0x73, 0xbb, 0x00, 0xd3,
+	0x20, 0x84, 0xb3, 0xe9, 0x58, 0xf2, 0xb8, 0x65, 0x2c, 0x48, 0xb1, 0x30, 0x26, 0xcf, 0x1f, 0xb6,
+	0xc8, 0x84, 0xcd, 0xfd, 0x10, 0x03, 0x74, 0xb3, 0x94, 0x11, 0x9e, 0x9f, 0xbc, 0xe5, 0x28, 0x11,
+	0x9f, 0x30, 0x7d, 0xf1, 0xfc, 0xf4, 0xbd, 0x0d, 0xe6, 0xdc, 0x04, 0xfe, 0x1a, 0x16, 0x05, 0xa6,
+	0xe7, 0x7b, 0x26, 0x8d, 0x46, 0xf0, 0x17, 0x11, 0x6a, 0xe9, 0xe3, 0x50, 0x9b, 0x02, 0x02, 0xa7,
+	0xac, 0x88, 0xca, 0xfd, 0x1c, 0x87, 0x3b, 0x37, 0x7a, 0x12, 0x3d, 0x82, 0x45, 0xea, 0x99, 0x6c,
+	0x1c, 0x70, 0x1a, 0x3e, 0xf0, 0x12, 0x9e, 0x0a, 0x44, 0x34, 0xe2, 0xd5, 0xc2, 0x68, 0x62, 0xb7,
+	0x8e, 0xa6, 0x12, 0x04, 0x51, 0x34, 0x46, 0x44, 0xa1, 0x16, 0x24, 0x3c, 0xca, 0x89, 0x13, 0xb5,
 	0x4f, 0x75, 0x37, 0x82, 0x2d, 0x7c, 0xcc, 0x5e, 0xa0, 0x7c, 0xbf, 0x8e, 0x35, 0x8f, 0xf2, 0x7d,
 	0xeb, 0x5a, 0xab, 0xa9, 0x7f, 0x5f, 0xab, 0x7d, 0x09, 0x69, 0xab, 0x4f, 0x06, 0x94, 0x73, 0xe1,
 	0x15, 0x0d, 0xb9, 0x69, 0xa7, 0xd4, 0x0f, 0xda, 0x91, 0x6a, 0xa6, 0xe9, 0xc0, 0xea, 0x4f, 0xa4,
-	0xd7, 0xf6, 0x4d, 0xfc, 0x4f, 0xf7, 0x4d, 0xe2, 0x83, 0xfb, 0x26, 0xf7, 0x15, 0xc0, 0xf4, 0xa0,
-	0x9b, 0xdb, 0x4f, 0xf9, 0xd0, 0xf6, 0x5b, 0x9c, 0xd9, 0x7e, 0xb9, 0x47, 0x10, 0x0f, 0xa1, 0x11,
-	0x02, 0xd5, 0x16, 0x8d, 0xaa, 0xac, 0xc7, 0xe4, 0x40, 0x60, 0xf4, 0xf5, 0xe6, 0x1a, 0xc0, 0xf4,
-	0xc7, 0x13, 0x4a, 0x82, 0x7a, 0xd0, 0xc2, 0x15, 0x7d, 0x01, 0x25, 0x20, 0xb6, 0xd7, 0x7e, 0xa1,
-	0x2b, 0x9b, 0xdf, 0x29, 0x10, 0x0f, 0x37, 0x1c, 0x5a, 0x06, 0x68, 0x74, 0xc8, 0xee, 0xd3, 0x12,
-	0xd9, 0xdd, 0x29, 0xe8, 0x0b, 0x82, 0xef, 0xb4, 0x49, 0xb9, 0x50, 0x24, 0xe5, 0xe2, 0xae, 0xae,
-	0x08, 0xbe, 0xd6, 0x24, 0x3b, 0x3b, 0x65, 0xb2, 0xb3, 0xbb, 0xa3, 0x2f, 0x22, 0x80, 0x78, 0xa3,
-	0x43, 0x1e, 0x97, 0x4a, 0x7a, 0x4c, 0xe8, 0x2a, 0x1d, 0x52, 0xde, 0x7e, 0x22, 0x6d, 0xd5, 0xc8,
-	0xf6, 0xf1, 0x4e, 0x81, 0x3c, 0xd9, 0x2e, 0xe8, 0x9a, 0xb0, 0xad, 0xb4, 0x49, 0xb9, 0x58, 0xd2,
-	0xe3, 0x42, 0xf7, 0x02, 0x93, 0x72, 0xb1, 0x20, 0xf9, 0xc4, 0xe6, 0xbf, 0x40, 0x93, 0xe3, 0x5d,
-	0x28, 0x44, 0x78, 0xaf, 0x2a, 0x4d, 0x82, 0xb7, 0xf5, 0x85, 0xcd, 0x6f, 0x41, 0x93, 0xdb, 0x01,
-	0xe9, 0xb0, 0xf4, 0xbc, 0xb5, 0xdf, 0x24, 0xb8, 0xf1, 0xb2, 0xd3, 0x68, 0x1f, 0xeb, 0x0b, 0x68,
-	0x05, 0xd2, 0x52, 0x52, 0xa9, 0xd5, 0x1a, 0x47, 0xc7, 0xba, 0x82, 0x10, 0x2c, 0x77, 0x9a, 0xb5,
-	0x56, 0x73, 0x6f, 0x1f, 0x1f, 0x36, 0xea, 0xa4, 0x73, 0xa4, 0x2f, 0xa2, 0x7b, 0xa0, 0xcf, 0xca,
-	0xea, 0xad, 0x57, 0x4d, 0x3d, 0x26, 0xc0, 0xae, 0xd9, 0xa9, 0xc2, 0x77, 0xce, 0x4a, 0xab, 0x56,
-	0xdf, 0xbc, 0x5f, 0x55, 0xde, 0xbe, 0x5f, 0x55, 0x7e, 0x7b, 0xbf, 0xaa, 0x7c, 0xf3, 0xf8, 0x36,
-	0xff, 0x03, 0x4e, 0xe2, 0x52, 0x52, 0xfa, 0x3d, 0x00, 0x00, 0xff, 0xff, 0x15, 0x1b, 0xb4, 0x6a,
-	0x46, 0x0c, 0x00, 0x00,
+	0xd7, 0xf6, 0x4d, 0xe2, 0x4f, 0xf7, 0x4d, 0xf2, 0x83, 0xfb, 0x26, 0xf7, 0x15, 0xc0, 0xf4, 0xa0,
+	0x9b, 0xdb, 0x4f, 0xf9, 0xd0, 0xf6, 0x8b, 0xcd, 0x6c, 0xbf, 0xdc, 0x23, 0x48, 0x84, 0xd0, 0x08,
+	0x81, 0x6a, 0x8b, 0x46, 0x55, 0xb2, 0x71, 0x39, 0x10, 0x18, 0x7d, 0xbd, 0xb9, 0x0e, 0x30, 0xfd,
+	0xf1, 0x84, 0x52, 0xa0, 0x1e, 0xb4, 0x70, 0x45, 0x5f, 0x40, 0x49, 0x88, 0xef, 0xb5, 0x5f, 0xe8,
+	0xca, 0xe6, 0x77, 0x0a, 0x24, 0xc2, 0x0d, 0x87, 0x56, 0x00, 0x1a, 0x1d, 0xb2, 0xfb, 0xb4, 0x44,
+	0x76, 0x77, 0x0a, 0xfa, 0x82, 0xe0, 0x3b, 0x6d, 0x52, 0x2e, 0x14, 0x49, 0xb9, 0xb8, 0xab, 0x2b,
+	0x82, 0xaf, 0x35, 0xc9, 0xce, 0x4e, 0x99, 0xec, 0xec, 0xee, 0xe8, 0x31, 0x04, 0x90, 0x68, 0x74,
+	0xc8, 0xe3, 0x52, 0x49, 0x8f, 0x0b, 0x5d, 0xa5, 0x43, 0xca, 0xdb, 0x4f, 0xa4, 0xad, 0x1a, 0xd9,
+	0x3e, 0xde, 0x29, 0x90, 0x27, 0xdb, 0x05, 0x5d, 0x13, 0xb6, 0x95, 0x36, 0x29, 0x17, 0x4b, 0x7a,
+	0x42, 0xe8, 0x5e, 0x60, 0x52, 0x2e, 0x16, 0x24, 0x9f, 0xdc, 0xfc, 0x17, 0x68, 0x72, 0xbc, 0x0b,
+	0x85, 0x08, 0xef, 0x55, 0xa5, 0x49, 0xf0, 0xb6, 0xbe, 0xb0, 0xf9, 0x2d, 0x68, 0x72, 0x3b, 0x20,
+	0x1d, 0x96, 0x9e, 0xb7, 0xf6, 0x9b, 0x04, 0x37, 0x5e, 0x76, 0x1a, 0xed, 0x63, 0x7d, 0x01, 0xad,
+	0x42, 0x5a, 0x4a, 0x2a, 0xb5, 0x5a, 0xe3, 0xe8, 0x58, 0x57, 0x10, 0x82, 0x95, 0x4e, 0xb3, 0xd6,
+	0x6a, 0xee, 0xed, 0xe3, 0xc3, 0x46, 0x9d, 0x74, 0x8e, 0xf4, 0x18, 0xba, 0x07, 0xfa, 0xac, 0xac,
+	0xde, 0x7a, 0xd5, 0xd4, 0xe3, 0x02, 0xec, 0x9a, 0x9d, 0x2a, 0x7c, 0xe7, 0xac, 0xb4, 0x6a, 0xf5,
+	0xcd, 0xfb, 0x35, 0xe5, 0xed, 0xfb, 0x35, 0xe5, 0xd7, 0xf7, 0x6b, 0xca, 0x37, 0x8f, 0x6f, 0xf3,
+	0x0f, 0xe1, 0x24, 0x21, 0x25, 0xa5, 0xdf, 0x07, 0x00, 0xb3, 0x52, 0xff, 0x6c, 0x60, 0x0c, 0x00,
+	0x00,
 }
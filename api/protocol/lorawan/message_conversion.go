@@ -238,8 +238,10 @@ func (c *TxConfiguration) SetDataRate(dataRate band.DataRate) error {
 			return err
 		}
 		c.DataRate = datr.String()
+		c.BitRate = 0
 	case band.FSKModulation:
 		c.Modulation = Modulation_FSK
+		c.DataRate = ""
 		c.BitRate = uint32(dataRate.BitRate)
 	}
 	return nil
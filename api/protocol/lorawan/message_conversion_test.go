@@ -124,4 +124,13 @@ func TestConvertDataRate(t *testing.T) {
 		a.So(tx.BitRate, ShouldEqual, 50000)
 	}
 
+	{
+		tx := &TxConfiguration{Modulation: Modulation_LORA, DataRate: "SF9BW125"}
+		err := tx.SetDataRate(band.DataRate{Modulation: band.FSKModulation, BitRate: 50000})
+		a.So(err, ShouldBeNil)
+		a.So(tx.Modulation, ShouldEqual, Modulation_FSK)
+		a.So(tx.DataRate, ShouldBeEmpty)
+		a.So(tx.BitRate, ShouldEqual, 50000)
+	}
+
 }
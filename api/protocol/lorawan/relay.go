@@ -0,0 +1,62 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package lorawan
+
+import (
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// RelayFPort is the FPort on which relays forward the uplinks of end devices
+// and receive the downlinks for them (LoRaWAN Relay Specification TS011).
+// The FRMPayload on this port is encrypted with the NwkSKey of the relay.
+const RelayFPort = 226
+
+// ForwardUplinkReq is the uplink of an end device that a relay forwards in the
+// FRMPayload of an uplink on the RelayFPort
+type ForwardUplinkReq struct {
+	WORChannel uint8  // the wake-on-radio channel on which the relay received the uplink
+	DataRate   uint8  // the index of the data rate of the uplink
+	RSSI       int32  // the RSSI of the uplink at the relay, in dBm
+	SNR        int32  // the SNR of the uplink at the relay, in dB
+	Frequency  uint64 // the frequency of the uplink, in Hz
+	Payload    []byte // the PHYPayload of the end device
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The uplink metadata is
+// encoded in 3 bytes (little endian): DataRate in bits 0-3, SNR+20 in bits
+// 4-8, -RSSI in bits 9-15 and WORChannel in bits 16-17. It is followed by the
+// frequency in steps of 100 Hz (3 bytes, little endian) and the PHYPayload.
+func (r ForwardUplinkReq) MarshalBinary() ([]byte, error) {
+	if r.DataRate > 15 || r.WORChannel > 3 {
+		return nil, errors.NewErrInvalidArgument("ForwardUplinkReq", "invalid data rate or WOR channel")
+	}
+	if r.SNR < -20 || r.SNR > 11 || r.RSSI > 0 || r.RSSI < -127 {
+		return nil, errors.NewErrInvalidArgument("ForwardUplinkReq", "SNR or RSSI out of range")
+	}
+	if r.Frequency/100 > 0xffffff {
+		return nil, errors.NewErrInvalidArgument("ForwardUplinkReq", "frequency out of range")
+	}
+	metadata := uint32(r.DataRate) | uint32(r.SNR+20)<<4 | uint32(-r.RSSI)<<9 | uint32(r.WORChannel)<<16
+	frequency := uint32(r.Frequency / 100)
+	out := []byte{
+		byte(metadata), byte(metadata >> 8), byte(metadata >> 16),
+		byte(frequency), byte(frequency >> 8), byte(frequency >> 16),
+	}
+	return append(out, r.Payload...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (r *ForwardUplinkReq) UnmarshalBinary(data []byte) error {
+	if len(data) < 6 {
+		return errors.NewErrInvalidArgument("ForwardUplinkReq", "must be at least 6 bytes")
+	}
+	metadata := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16
+	r.DataRate = uint8(metadata & 0x0f)
+	r.SNR = int32((metadata>>4)&0x1f) - 20
+	r.RSSI = -int32((metadata >> 9) & 0x7f)
+	r.WORChannel = uint8((metadata >> 16) & 0x03)
+	r.Frequency = uint64(uint32(data[3])|uint32(data[4])<<8|uint32(data[5])<<16) * 100
+	r.Payload = append([]byte{}, data[6:]...)
+	return nil
+}
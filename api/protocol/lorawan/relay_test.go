@@ -0,0 +1,36 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package lorawan
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestForwardUplinkReq(t *testing.T) {
+	a := New(t)
+
+	req := ForwardUplinkReq{
+		WORChannel: 1,
+		DataRate:   5,
+		RSSI:       -110,
+		SNR:        -7,
+		Frequency:  868100000,
+		Payload:    []byte{0x40, 0x01, 0x02, 0x03, 0x04},
+	}
+	data, err := req.MarshalBinary()
+	a.So(err, ShouldBeNil)
+	a.So(data, ShouldHaveLength, 11)
+
+	var decoded ForwardUplinkReq
+	a.So(decoded.UnmarshalBinary(data), ShouldBeNil)
+	a.So(decoded, ShouldResemble, req)
+
+	a.So(decoded.UnmarshalBinary(data[:5]), ShouldNotBeNil)
+
+	req.SNR = 20
+	_, err = req.MarshalBinary()
+	a.So(err, ShouldNotBeNil)
+}
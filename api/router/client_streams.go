@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
 	"github.com/TheThingsNetwork/ttn/api/fields"
 	"github.com/TheThingsNetwork/ttn/api/gateway"
 	"github.com/TheThingsNetwork/ttn/utils/backoff"
@@ -128,6 +129,7 @@ func NewMonitoredGatewayStatusStream(client RouterClientForGateway) GatewayStatu
 				break
 			}
 
+			api.StreamRestarted("router.GatewayStatus")
 			s.setup.Add(1)
 			time.Sleep(backoff.Backoff(retries))
 			retries++
@@ -254,6 +256,7 @@ func NewMonitoredUplinkStream(client RouterClientForGateway) UplinkStream {
 				break
 			}
 
+			api.StreamRestarted("router.Uplink")
 			s.setup.Add(1)
 			time.Sleep(backoff.Backoff(retries))
 			retries++
@@ -363,6 +366,7 @@ func NewMonitoredDownlinkStream(client RouterClientForGateway) DownlinkStream {
 				break
 			}
 
+			api.StreamRestarted("router.Subscribe")
 			s.setup.Add(1)
 			time.Sleep(backoff.Backoff(retries))
 			retries++
@@ -112,7 +112,9 @@ type DownlinkMessage struct {
 	Message               *protocol.Message         `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
 	ProtocolConfiguration *protocol.TxConfiguration `protobuf:"bytes,11,opt,name=protocol_configuration,json=protocolConfiguration" json:"protocol_configuration,omitempty"`
 	GatewayConfiguration  *gateway.TxConfiguration  `protobuf:"bytes,12,opt,name=gateway_configuration,json=gatewayConfiguration" json:"gateway_configuration,omitempty"`
-	Trace                 *trace.Trace              `protobuf:"bytes,21,opt,name=trace" json:"trace,omitempty"`
+	// Priority of the downlink when it conflicts with other downlinks on the gateway (low, normal, high or mac)
+	Priority string       `protobuf:"bytes,13,opt,name=priority,proto3" json:"priority,omitempty"`
+	Trace    *trace.Trace `protobuf:"bytes,21,opt,name=trace" json:"trace,omitempty"`
 }
 
 func (m *DownlinkMessage) Reset()                    { *m = DownlinkMessage{} }
@@ -148,6 +150,13 @@ func (m *DownlinkMessage) GetGatewayConfiguration() *gateway.TxConfiguration {
 	return nil
 }
 
+func (m *DownlinkMessage) GetPriority() string {
+	if m != nil {
+		return m.Priority
+	}
+	return ""
+}
+
 func (m *DownlinkMessage) GetTrace() *trace.Trace {
 	if m != nil {
 		return m.Trace
@@ -645,6 +654,8 @@ type RouterManagerClient interface {
 	GatewayStatus(ctx context.Context, in *GatewayStatusRequest, opts ...grpc.CallOption) (*GatewayStatusResponse, error)
 	// Network operator requests Router status
 	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*Status, error)
+	// Network operator streams the log entries of the Router
+	TailLogs(ctx context.Context, in *api.LogsRequest, opts ...grpc.CallOption) (RouterManager_TailLogsClient, error)
 }
 
 type routerManagerClient struct {
@@ -673,6 +684,38 @@ func (c *routerManagerClient) GetStatus(ctx context.Context, in *StatusRequest,
 	return out, nil
 }
 
+func (c *routerManagerClient) TailLogs(ctx context.Context, in *api.LogsRequest, opts ...grpc.CallOption) (RouterManager_TailLogsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_RouterManager_serviceDesc.Streams[0], c.cc, "/router.RouterManager/TailLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerManagerTailLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RouterManager_TailLogsClient interface {
+	Recv() (*api.LogEntry, error)
+	grpc.ClientStream
+}
+
+type routerManagerTailLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerManagerTailLogsClient) Recv() (*api.LogEntry, error) {
+	m := new(api.LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for RouterManager service
 
 type RouterManagerServer interface {
@@ -681,6 +724,8 @@ type RouterManagerServer interface {
 	GatewayStatus(context.Context, *GatewayStatusRequest) (*GatewayStatusResponse, error)
 	// Network operator requests Router status
 	GetStatus(context.Context, *StatusRequest) (*Status, error)
+	// Network operator streams the log entries of the Router
+	TailLogs(*api.LogsRequest, RouterManager_TailLogsServer) error
 }
 
 func RegisterRouterManagerServer(s *grpc.Server, srv RouterManagerServer) {
@@ -723,6 +768,27 @@ func _RouterManager_GetStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RouterManager_TailLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(api.LogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterManagerServer).TailLogs(m, &routerManagerTailLogsServer{stream})
+}
+
+type RouterManager_TailLogsServer interface {
+	Send(*api.LogEntry) error
+	grpc.ServerStream
+}
+
+type routerManagerTailLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerManagerTailLogsServer) Send(m *api.LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _RouterManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "router.RouterManager",
 	HandlerType: (*RouterManagerServer)(nil),
@@ -736,7 +802,13 @@ var _RouterManager_serviceDesc = grpc.ServiceDesc{
 			Handler:    _RouterManager_GetStatus_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailLogs",
+			Handler:       _RouterManager_TailLogs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "github.com/TheThingsNetwork/ttn/api/router/router.proto",
 }
 
@@ -875,6 +947,12 @@ func (m *DownlinkMessage) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n7
 	}
+	if len(m.Priority) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintRouter(dAtA, i, uint64(len(m.Priority)))
+		i += copy(dAtA[i:], m.Priority)
+	}
 	if m.Trace != nil {
 		dAtA[i] = 0xaa
 		i++
@@ -1255,6 +1333,10 @@ func (m *DownlinkMessage) Size() (n int) {
 		l = m.GatewayConfiguration.Size()
 		n += 1 + l + sovRouter(uint64(l))
 	}
+	l = len(m.Priority)
+	if l > 0 {
+		n += 1 + l + sovRouter(uint64(l))
+	}
 	if m.Trace != nil {
 		l = m.Trace.Size()
 		n += 2 + l + sovRouter(uint64(l))
@@ -1806,6 +1888,35 @@ func (m *DownlinkMessage) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRouter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRouter
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Priority = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 21:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Trace", wireType)
@@ -2847,61 +2958,63 @@ func init() {
 }
 
 var fileDescriptorRouter = []byte{
-	// 884 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x56, 0xcb, 0x6e, 0xdb, 0x46,
-	0x14, 0x05, 0x1d, 0x94, 0xb6, 0xae, 0x25, 0x5b, 0x1e, 0x5b, 0x36, 0xa3, 0xc4, 0x0f, 0x70, 0xd1,
-	0x1a, 0x4d, 0x43, 0xd5, 0x2a, 0x82, 0x3e, 0x16, 0x45, 0xed, 0xd8, 0x08, 0x02, 0x54, 0x41, 0x41,
-	0x3b, 0x9b, 0x02, 0x85, 0x30, 0xa2, 0x6e, 0x68, 0xc2, 0x12, 0x87, 0xe5, 0x0c, 0xe5, 0xe8, 0x2f,
-	0xda, 0x0f, 0xe8, 0xff, 0x74, 0xd9, 0x75, 0x17, 0x45, 0xe1, 0x0f, 0xe8, 0xb2, 0xbb, 0x02, 0x05,
-	0xe7, 0x41, 0x8a, 0x92, 0xdd, 0xa6, 0x8f, 0x6c, 0x44, 0xce, 0x3d, 0xe7, 0x1e, 0xce, 0x9c, 0xb9,
-	0x33, 0x57, 0xf0, 0x71, 0x18, 0x89, 0xcb, 0x6c, 0xe0, 0x05, 0x6c, 0xdc, 0xb9, 0xb8, 0xc4, 0x8b,
-	0xcb, 0x28, 0x0e, 0xf9, 0x0b, 0x14, 0xd7, 0x2c, 0xbd, 0xea, 0x08, 0x11, 0x77, 0x68, 0x12, 0x75,
-	0x52, 0x96, 0x09, 0x4c, 0xf5, 0xc3, 0x4b, 0x52, 0x26, 0x18, 0xb1, 0xd5, 0xa8, 0xfd, 0x20, 0x64,
-	0x2c, 0x1c, 0x61, 0x47, 0x46, 0x07, 0xd9, 0xab, 0x0e, 0x8e, 0x13, 0x31, 0x55, 0xa4, 0xf6, 0xe3,
-	0x19, 0xf5, 0x90, 0x85, 0xac, 0x64, 0xe5, 0x23, 0x39, 0x90, 0x6f, 0x9a, 0xbe, 0x61, 0x3e, 0x48,
-	0x93, 0x48, 0x87, 0xf6, 0x4d, 0x48, 0x0e, 0x03, 0x36, 0x2a, 0x5e, 0x34, 0x61, 0xd7, 0x10, 0x42,
-	0x2a, 0xf0, 0x9a, 0x4e, 0xcd, 0x53, 0xc3, 0xf7, 0x0d, 0x2c, 0x52, 0x1a, 0xa0, 0xfa, 0x55, 0x90,
-	0x4b, 0xa0, 0x79, 0x9e, 0x0d, 0x78, 0x90, 0x46, 0x03, 0xf4, 0xf1, 0xdb, 0x0c, 0xb9, 0x70, 0xff,
-	0xb0, 0xa0, 0xf1, 0x32, 0x19, 0x45, 0xf1, 0x55, 0x0f, 0x39, 0xa7, 0x21, 0x12, 0x07, 0x96, 0x13,
-	0x3a, 0x1d, 0x31, 0x3a, 0x74, 0xac, 0x03, 0xeb, 0xb0, 0xee, 0x9b, 0x21, 0x79, 0x04, 0xcb, 0x63,
-	0x45, 0x72, 0x96, 0x0e, 0xac, 0xc3, 0xd5, 0xee, 0x86, 0x57, 0xcc, 0x4d, 0x67, 0xfb, 0x86, 0x41,
-	0x8e, 0x61, 0xc3, 0x80, 0xfd, 0x31, 0x0a, 0x3a, 0xa4, 0x82, 0x3a, 0xab, 0x32, 0x6d, 0xab, 0x4c,
-	0xf3, 0x5f, 0xf7, 0x34, 0xe6, 0x37, 0x4d, 0xd0, 0x44, 0xc8, 0xe7, 0xd0, 0xd4, 0x6b, 0x2b, 0x15,
-	0xea, 0x52, 0x61, 0xd3, 0x33, 0x8b, 0x9e, 0x11, 0x58, 0xd7, 0xb1, 0x22, 0xdf, 0x85, 0x77, 0xe4,
-	0xf2, 0x9d, 0x96, 0x4c, 0xaa, 0x7b, 0xca, 0x8c, 0x8b, 0xfc, 0xd7, 0x57, 0x90, 0xfb, 0xc3, 0x12,
-	0xac, 0x9f, 0xb2, 0xeb, 0xf8, 0x2d, 0x38, 0xf0, 0x15, 0x6c, 0x17, 0x0e, 0x04, 0x2c, 0x7e, 0x15,
-	0x85, 0x59, 0x4a, 0x45, 0xc4, 0x62, 0x6d, 0xc3, 0xfd, 0x32, 0xf7, 0xe2, 0xf5, 0xd3, 0x59, 0x82,
-	0xdf, 0x32, 0x48, 0x25, 0x4c, 0x7a, 0xd0, 0x32, 0x86, 0x54, 0x05, 0x95, 0x2b, 0x4e, 0xe1, 0xca,
-	0xbc, 0xde, 0x96, 0x06, 0xaa, 0x72, 0x6f, 0xe2, 0xcf, 0xef, 0xf7, 0x60, 0xe7, 0x14, 0x27, 0x51,
-	0x80, 0xc7, 0x81, 0x88, 0x26, 0x4a, 0x4e, 0xd5, 0xce, 0xff, 0xe5, 0xd3, 0x0b, 0x58, 0x1e, 0xe2,
-	0xa4, 0x8f, 0x59, 0x24, 0x8d, 0xa9, 0x9f, 0x3c, 0xf9, 0xf9, 0x97, 0xfd, 0xa3, 0xbf, 0x3b, 0xa6,
-	0x01, 0x4b, 0xb1, 0x23, 0xa6, 0x09, 0x72, 0xef, 0x14, 0x27, 0x67, 0x2f, 0x9f, 0xfb, 0xf6, 0x10,
-	0x27, 0x67, 0x59, 0x94, 0xeb, 0xd1, 0x24, 0x91, 0x7a, 0xf5, 0x7f, 0xa5, 0x77, 0x9c, 0x24, 0x52,
-	0x8f, 0x26, 0x49, 0xae, 0x77, 0x6b, 0x25, 0xb7, 0xfe, 0x73, 0x25, 0x6f, 0xff, 0x83, 0x4a, 0xee,
-	0xc1, 0x26, 0x2d, 0xec, 0x2f, 0x25, 0x76, 0xa4, 0xc4, 0xc3, 0x72, 0x12, 0xe5, 0x1e, 0x15, 0x5a,
-	0x84, 0x2e, 0xc4, 0xca, 0x8d, 0xdf, 0xbf, 0x7b, 0xe3, 0xdb, 0xe0, 0x2c, 0xee, 0x3b, 0x4f, 0x58,
-	0xcc, 0xd1, 0x7d, 0x02, 0x5b, 0xcf, 0xd4, 0x0c, 0xcf, 0x05, 0x15, 0x19, 0x37, 0x05, 0xb1, 0x0b,
-	0x60, 0x96, 0x19, 0xa9, 0x9a, 0xa8, 0xf9, 0x35, 0x1d, 0x79, 0x3e, 0x74, 0xbf, 0x81, 0xd6, 0x5c,
-	0x9a, 0xd2, 0x23, 0x0f, 0xa0, 0x36, 0xa2, 0x5c, 0xf4, 0x39, 0x62, 0x2c, 0xd3, 0xee, 0xf9, 0x2b,
-	0x79, 0xe0, 0x1c, 0x31, 0x26, 0xef, 0x81, 0xcd, 0x25, 0x5d, 0x97, 0xd2, 0x7a, 0xe1, 0x98, 0x56,
-	0xd1, 0xb0, 0xbb, 0x0e, 0x8d, 0xca, 0x74, 0xdc, 0xdf, 0x96, 0xc0, 0x56, 0x11, 0x72, 0x08, 0x36,
-	0x9f, 0x72, 0x81, 0x63, 0x29, 0xbf, 0xda, 0x6d, 0x7a, 0xf9, 0x8d, 0x7b, 0x2e, 0x43, 0x39, 0x25,
-	0x57, 0x91, 0x03, 0x72, 0x04, 0xb5, 0x80, 0x8d, 0x13, 0x16, 0x63, 0x2c, 0xf4, 0x17, 0x37, 0x25,
-	0xf9, 0xa9, 0x89, 0x2a, 0x7e, 0xc9, 0x22, 0x47, 0xb0, 0x66, 0x96, 0xad, 0x67, 0xaa, 0x0e, 0x38,
-	0xc8, 0x3c, 0x9f, 0x0a, 0xe4, 0x7e, 0x23, 0x9c, 0x5d, 0x39, 0x71, 0xc1, 0xce, 0xe4, 0xad, 0xab,
-	0x8f, 0xee, 0x2c, 0x55, 0x23, 0xe4, 0x5d, 0x58, 0x19, 0xea, 0x9b, 0xc9, 0x69, 0x2c, 0xb0, 0x0a,
-	0x8c, 0x7c, 0x00, 0xab, 0xe5, 0x1e, 0x73, 0x67, 0x6d, 0x81, 0x3a, 0x0b, 0x93, 0xc7, 0x40, 0x02,
-	0x16, 0xc7, 0x18, 0x08, 0x1c, 0xf6, 0xf5, 0xa4, 0xb8, 0x2c, 0xe7, 0x86, 0xbf, 0x51, 0x20, 0x7a,
-	0x9f, 0x38, 0x79, 0x04, 0x65, 0xb0, 0x3f, 0x48, 0xd9, 0x15, 0xa6, 0x5c, 0x96, 0x6e, 0xc3, 0x6f,
-	0x16, 0xc0, 0x89, 0x8a, 0x77, 0xbf, 0x5b, 0x02, 0xdb, 0x97, 0x5d, 0x92, 0x7c, 0x06, 0x8d, 0xca,
-	0x5e, 0x93, 0xf9, 0x6d, 0x6b, 0x6f, 0x7b, 0xaa, 0x91, 0x7a, 0xa6, 0x45, 0x7a, 0x67, 0x79, 0x23,
-	0x3d, 0xb4, 0xc8, 0xa7, 0x60, 0xab, 0x96, 0x44, 0x5a, 0x9e, 0x6e, 0xc1, 0x95, 0x16, 0xf5, 0x17,
-	0xa9, 0x5f, 0x40, 0xad, 0x68, 0x71, 0xc4, 0x31, 0xd9, 0xf3, 0x5d, 0xaf, 0xbd, 0x63, 0x90, 0xb9,
-	0xab, 0xff, 0x43, 0x8b, 0xf4, 0x60, 0x45, 0x57, 0x3c, 0x92, 0xfd, 0x82, 0x76, 0xfb, 0x0d, 0xd8,
-	0x3e, 0xb8, 0x9b, 0xa0, 0x4a, 0xbb, 0xfb, 0xbd, 0x05, 0x0d, 0x65, 0x49, 0x8f, 0xc6, 0x34, 0xc4,
-	0x94, 0x7c, 0x39, 0xef, 0xcc, 0x43, 0x23, 0x72, 0xdb, 0x99, 0x6a, 0xef, 0xde, 0x81, 0xea, 0xa3,
-	0xd3, 0x85, 0xda, 0x33, 0x14, 0x5a, 0xa9, 0xb0, 0xab, 0x2a, 0xb1, 0x56, 0x0d, 0x9f, 0x7c, 0xf2,
-	0xe3, 0xcd, 0x9e, 0xf5, 0xd3, 0xcd, 0x9e, 0xf5, 0xeb, 0xcd, 0x9e, 0xf5, 0xf5, 0xfb, 0x6f, 0xfe,
-	0x87, 0x68, 0x60, 0x4b, 0xc3, 0x3f, 0xfa, 0x33, 0x00, 0x00, 0xff, 0xff, 0x83, 0xd2, 0x76, 0xdc,
-	0x45, 0x09, 0x00, 0x00,
+	// 920 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x56, 0x4b, 0x6f, 0xdb, 0x46,
+	0x10, 0x06, 0x1d, 0x54, 0x96, 0xc6, 0xa2, 0x2d, 0xaf, 0x2d, 0x9b, 0x51, 0xe2, 0x07, 0x74, 0x68,
+	0x85, 0xa6, 0xa6, 0x62, 0x15, 0x41, 0x1f, 0x87, 0xa2, 0x76, 0x6c, 0x04, 0x01, 0xa2, 0xa0, 0xa0,
+	0x95, 0x4b, 0x81, 0x42, 0x58, 0x51, 0x13, 0x9a, 0xb0, 0xc4, 0x65, 0xb9, 0x4b, 0x39, 0xfa, 0x17,
+	0xfd, 0x3b, 0xbd, 0xf5, 0xd8, 0x63, 0xcf, 0x3d, 0x14, 0x85, 0x7f, 0x40, 0x8f, 0xbd, 0x15, 0x28,
+	0xb8, 0x0f, 0x52, 0x0f, 0xbb, 0x4d, 0x1f, 0xb9, 0x88, 0x9c, 0x6f, 0xbe, 0xf9, 0xb8, 0x3b, 0x33,
+	0xbb, 0x23, 0xf8, 0x24, 0x08, 0xc5, 0x65, 0x3a, 0x70, 0x7d, 0x36, 0x6e, 0xf7, 0x2e, 0xb1, 0x77,
+	0x19, 0x46, 0x01, 0x7f, 0x89, 0xe2, 0x9a, 0x25, 0x57, 0x6d, 0x21, 0xa2, 0x36, 0x8d, 0xc3, 0x76,
+	0xc2, 0x52, 0x81, 0x89, 0x7e, 0xb8, 0x71, 0xc2, 0x04, 0x23, 0x25, 0x65, 0x35, 0x1e, 0x04, 0x8c,
+	0x05, 0x23, 0x6c, 0x4b, 0x74, 0x90, 0xbe, 0x6e, 0xe3, 0x38, 0x16, 0x53, 0x45, 0x6a, 0x1c, 0xcd,
+	0xa8, 0x07, 0x2c, 0x60, 0x05, 0x2b, 0xb3, 0xa4, 0x21, 0xdf, 0x34, 0x7d, 0xd3, 0x7c, 0x90, 0xc6,
+	0xa1, 0x86, 0x0e, 0x0c, 0x24, 0x4d, 0x9f, 0x8d, 0xf2, 0x17, 0x4d, 0xd8, 0x33, 0x84, 0x80, 0x0a,
+	0xbc, 0xa6, 0x53, 0xf3, 0xd4, 0xee, 0xfb, 0xc6, 0x2d, 0x12, 0xea, 0xa3, 0xfa, 0x55, 0xae, 0x26,
+	0x81, 0xda, 0x45, 0x3a, 0xe0, 0x7e, 0x12, 0x0e, 0xd0, 0xc3, 0x6f, 0x53, 0xe4, 0xa2, 0xf9, 0x87,
+	0x05, 0xf6, 0xab, 0x78, 0x14, 0x46, 0x57, 0x5d, 0xe4, 0x9c, 0x06, 0x48, 0x1c, 0x58, 0x8d, 0xe9,
+	0x74, 0xc4, 0xe8, 0xd0, 0xb1, 0x0e, 0xad, 0x56, 0xd5, 0x33, 0x26, 0x79, 0x04, 0xab, 0x63, 0x45,
+	0x72, 0x56, 0x0e, 0xad, 0xd6, 0x5a, 0x67, 0xd3, 0xcd, 0xd7, 0xa6, 0xa3, 0x3d, 0xc3, 0x20, 0x27,
+	0xb0, 0x69, 0x9c, 0xfd, 0x31, 0x0a, 0x3a, 0xa4, 0x82, 0x3a, 0x6b, 0x32, 0x6c, 0xbb, 0x08, 0xf3,
+	0xde, 0x74, 0xb5, 0xcf, 0xab, 0x19, 0xd0, 0x20, 0xe4, 0x0b, 0xa8, 0xe9, 0xbd, 0x15, 0x0a, 0x55,
+	0xa9, 0xb0, 0xe5, 0x9a, 0x4d, 0xcf, 0x08, 0x6c, 0x68, 0x2c, 0x8f, 0x6f, 0xc2, 0x7b, 0x72, 0xfb,
+	0x4e, 0x5d, 0x06, 0x55, 0x5d, 0x69, 0xb9, 0xbd, 0xec, 0xd7, 0x53, 0xae, 0xe6, 0xf7, 0x2b, 0xb0,
+	0x71, 0xc6, 0xae, 0xa3, 0x77, 0x90, 0x81, 0xaf, 0x60, 0x27, 0xcf, 0x80, 0xcf, 0xa2, 0xd7, 0x61,
+	0x90, 0x26, 0x54, 0x84, 0x2c, 0xd2, 0x69, 0xb8, 0x5f, 0xc4, 0xf6, 0xde, 0x3c, 0x9d, 0x25, 0x78,
+	0x75, 0xe3, 0x99, 0x83, 0x49, 0x17, 0xea, 0x26, 0x21, 0xf3, 0x82, 0x2a, 0x2b, 0x4e, 0x9e, 0x95,
+	0x45, 0xbd, 0x6d, 0xed, 0x98, 0x97, 0x6b, 0x40, 0x39, 0x4e, 0x42, 0x96, 0x84, 0x62, 0xea, 0xd8,
+	0x87, 0x56, 0xab, 0xe2, 0xe5, 0xf6, 0x5b, 0xe5, 0xee, 0xf7, 0x7b, 0xb0, 0x7b, 0x86, 0x93, 0xd0,
+	0xc7, 0x13, 0x5f, 0x84, 0x13, 0xf5, 0x29, 0xd5, 0x57, 0xff, 0x57, 0x0e, 0x5f, 0xc2, 0xea, 0x10,
+	0x27, 0x7d, 0x4c, 0x43, 0x99, 0xb4, 0xea, 0xe9, 0x93, 0x9f, 0x7f, 0x39, 0x38, 0xfe, 0xbb, 0x23,
+	0xec, 0xb3, 0x04, 0xdb, 0x62, 0x1a, 0x23, 0x77, 0xcf, 0x70, 0x72, 0xfe, 0xea, 0xb9, 0x57, 0x1a,
+	0xe2, 0xe4, 0x3c, 0x0d, 0x33, 0x3d, 0x1a, 0xc7, 0x52, 0xaf, 0xfa, 0xaf, 0xf4, 0x4e, 0xe2, 0x58,
+	0xea, 0xd1, 0x38, 0xce, 0xf4, 0x6e, 0xed, 0xf2, 0xfa, 0x7f, 0xee, 0xf2, 0x9d, 0x7f, 0xd0, 0xe5,
+	0x5d, 0xd8, 0xa2, 0x79, 0xfa, 0x0b, 0x89, 0x5d, 0x29, 0xf1, 0xb0, 0x58, 0x44, 0x51, 0xa3, 0x5c,
+	0x8b, 0xd0, 0x25, 0xac, 0x28, 0xfc, 0xc1, 0xdd, 0x85, 0x6f, 0x80, 0xb3, 0x5c, 0x77, 0x1e, 0xb3,
+	0x88, 0x63, 0xf3, 0x09, 0x6c, 0x3f, 0x53, 0x2b, 0xbc, 0x10, 0x54, 0xa4, 0xdc, 0x34, 0xc4, 0x1e,
+	0x80, 0xd9, 0x66, 0xa8, 0x7a, 0xa2, 0xe2, 0x55, 0x34, 0xf2, 0x7c, 0xd8, 0xfc, 0x06, 0xea, 0x0b,
+	0x61, 0x4a, 0x8f, 0x3c, 0x80, 0xca, 0x88, 0x72, 0xd1, 0xe7, 0x88, 0x91, 0x0c, 0xbb, 0xe7, 0x95,
+	0x33, 0xe0, 0x02, 0x31, 0x22, 0x1f, 0x40, 0x89, 0x4b, 0xba, 0x6e, 0xa5, 0x8d, 0x3c, 0x63, 0x5a,
+	0x45, 0xbb, 0x9b, 0x1b, 0x60, 0xcf, 0x2d, 0xa7, 0xf9, 0xdb, 0x0a, 0x94, 0x14, 0x42, 0x5a, 0x50,
+	0xe2, 0x53, 0x2e, 0x70, 0x2c, 0xe5, 0xd7, 0x3a, 0x35, 0x37, 0xbb, 0x8d, 0x2f, 0x24, 0x94, 0x51,
+	0x32, 0x15, 0x69, 0x90, 0x63, 0xa8, 0xf8, 0x6c, 0x1c, 0xb3, 0x08, 0x23, 0xa1, 0xbf, 0xb8, 0x25,
+	0xc9, 0x4f, 0x0d, 0xaa, 0xf8, 0x05, 0x8b, 0x1c, 0xc3, 0xba, 0xd9, 0xb6, 0x5e, 0xa9, 0x3a, 0xfc,
+	0x20, 0xe3, 0x3c, 0x2a, 0x90, 0x7b, 0x76, 0x30, 0xbb, 0x73, 0xd2, 0x84, 0x52, 0x2a, 0x6f, 0x64,
+	0xa7, 0xba, 0x44, 0xd5, 0x1e, 0xf2, 0x3e, 0x94, 0x87, 0xfa, 0xd6, 0x72, 0xec, 0x25, 0x56, 0xee,
+	0x23, 0x1f, 0xc1, 0x5a, 0x51, 0x63, 0xee, 0xac, 0x2f, 0x51, 0x67, 0xdd, 0xe4, 0x08, 0x88, 0xcf,
+	0xa2, 0x08, 0x7d, 0x81, 0xc3, 0xbe, 0x5e, 0x14, 0x97, 0xed, 0x6c, 0x7b, 0x9b, 0xb9, 0x47, 0xd7,
+	0x89, 0x93, 0x47, 0x50, 0x80, 0xfd, 0x41, 0xc2, 0xae, 0x30, 0xe1, 0xb2, 0x75, 0x6d, 0xaf, 0x96,
+	0x3b, 0x4e, 0x15, 0xde, 0xf9, 0x6e, 0x05, 0x4a, 0x9e, 0x9c, 0xa0, 0xe4, 0x73, 0xb0, 0xe7, 0x6a,
+	0x4d, 0x16, 0xcb, 0xd6, 0xd8, 0x71, 0xd5, 0x90, 0x75, 0xcd, 0xf8, 0x74, 0xcf, 0xb3, 0x21, 0xdb,
+	0xb2, 0xc8, 0x67, 0x50, 0x52, 0xe3, 0x8a, 0xd4, 0x5d, 0x3d, 0x9e, 0xe7, 0xc6, 0xd7, 0x5f, 0x84,
+	0x7e, 0x09, 0x95, 0x7c, 0xfc, 0x11, 0xc7, 0x44, 0x2f, 0x4e, 0xc4, 0xc6, 0xae, 0xf1, 0x2c, 0x8c,
+	0x85, 0xc7, 0x16, 0xe9, 0x42, 0x59, 0x77, 0x3c, 0x92, 0x83, 0x9c, 0x76, 0xfb, 0x0d, 0xd8, 0x38,
+	0xbc, 0x9b, 0xa0, 0x5a, 0xbb, 0xf3, 0x83, 0x05, 0xb6, 0x4a, 0x49, 0x97, 0x46, 0x34, 0xc0, 0x84,
+	0xbc, 0x58, 0xcc, 0xcc, 0x43, 0x23, 0x72, 0xdb, 0x99, 0x6a, 0xec, 0xdd, 0xe1, 0xd5, 0x47, 0xa7,
+	0x03, 0x95, 0x67, 0x28, 0xb4, 0x52, 0x9e, 0xae, 0x79, 0x89, 0xf5, 0x79, 0x98, 0x1c, 0x41, 0xb9,
+	0x47, 0xc3, 0xd1, 0x0b, 0x16, 0x70, 0xa2, 0x0e, 0x42, 0xf6, 0x6a, 0xd8, 0xb6, 0x41, 0xce, 0x23,
+	0x91, 0x4c, 0x1f, 0x5b, 0xa7, 0x9f, 0xfe, 0x78, 0xb3, 0x6f, 0xfd, 0x74, 0xb3, 0x6f, 0xfd, 0x7a,
+	0xb3, 0x6f, 0x7d, 0xfd, 0xe1, 0xdb, 0xff, 0xb7, 0x1a, 0x94, 0x64, 0x7d, 0x3e, 0xfe, 0x73, 0x00,
+	0x54, 0xe2, 0xbb, 0x0c, 0x90, 0x09, 0x00, 0x00,
 }
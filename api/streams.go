@@ -0,0 +1,30 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package api
+
+import "sync"
+
+var streamRestarts = struct {
+	sync.Mutex
+	count map[string]uint64
+}{count: make(map[string]uint64)}
+
+// StreamRestarted records that the client stream with the name is
+// re-established after it stopped
+func StreamRestarted(name string) {
+	streamRestarts.Lock()
+	defer streamRestarts.Unlock()
+	streamRestarts.count[name]++
+}
+
+// StreamRestarts returns the number of restarts of each client stream
+func StreamRestarts() map[string]uint64 {
+	streamRestarts.Lock()
+	defer streamRestarts.Unlock()
+	res := make(map[string]uint64, len(streamRestarts.count))
+	for name, count := range streamRestarts.count {
+		res[name] = count
+	}
+	return res
+}
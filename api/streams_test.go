@@ -0,0 +1,21 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestStreamRestarts(t *testing.T) {
+	a := New(t)
+	a.So(StreamRestarts()["test.Stream"], ShouldEqual, 0)
+	StreamRestarted("test.Stream")
+	StreamRestarted("test.Stream")
+	restarts := StreamRestarts()
+	a.So(restarts["test.Stream"], ShouldEqual, 2)
+	restarts["test.Stream"] = 0
+	a.So(StreamRestarts()["test.Stream"], ShouldEqual, 2)
+}
@@ -6,13 +6,19 @@ package trace
 // Event types
 const (
 	AcceptEvent        = "accept"
+	BeaconEvent        = "beacon"
 	BuildDownlinkEvent = "build downlink"
 	CheckMICEvent      = "check mic"
 	DeduplicateEvent   = "deduplicate"
+	DelayEvent         = "delay"
 	DropEvent          = "drop"
 	ForwardEvent       = "forward"
 	HandleMACEvent     = "handle mac command"
+	MACDownlinkEvent   = "mac downlink"
+	NoDownlinkEvent    = "no downlink option"
 	ReceiveEvent       = "receive"
+	RelayEvent         = "relay"
 	SendEvent          = "send"
+	SkipRXWindowEvent  = "skip rx window"
 	UpdateStateEvent   = "update state"
 )
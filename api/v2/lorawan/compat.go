@@ -0,0 +1,110 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package lorawan
+
+import (
+	"github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/golang/protobuf/ptypes/empty"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// Classes of devices
+const (
+	ClassA = "A"
+	ClassB = "B"
+	ClassC = "C"
+)
+
+// FromV1 returns the v2 Device of a v1 Device. Devices of the v1 API are
+// Class A unicast devices with LoRaWAN 1.0 keys.
+func FromV1(dev *lorawan.Device) *Device {
+	if dev == nil {
+		return nil
+	}
+	return &Device{Device: dev, Class: ClassA}
+}
+
+// V1 returns the v1 Device of the device, without the fields that were added in v2
+func (m *Device) V1() *lorawan.Device {
+	return m.GetDevice()
+}
+
+// IsV1 returns true if the device does not use the settings that were added
+// in v2, so that it can be stored by servers of the v1 API
+func (m *Device) IsV1() bool {
+	return (m.Class == "" || m.Class == ClassA) && !m.Multicast &&
+		len(m.NwkKey) == 0 && len(m.FNwkSIntKey) == 0 && len(m.SNwkSIntKey) == 0 && len(m.NwkSEncKey) == 0
+}
+
+// NewDeviceManagerServer serves the v2 DeviceManager API with a server of the
+// v1 API. Devices that use the settings that were added in v2 are rejected,
+// because the v1 server can not store them.
+func NewDeviceManagerServer(server lorawan.DeviceManagerServer) DeviceManagerServer {
+	return &v1DeviceManager{server: server}
+}
+
+type v1DeviceManager struct {
+	server lorawan.DeviceManagerServer
+}
+
+func (m *v1DeviceManager) GetDevice(ctx context.Context, in *lorawan.DeviceIdentifier) (*Device, error) {
+	dev, err := m.server.GetDevice(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return FromV1(dev), nil
+}
+
+func (m *v1DeviceManager) SetDevice(ctx context.Context, in *Device) (*empty.Empty, error) {
+	if err := in.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Invalid Device")
+	}
+	if !in.IsV1() {
+		return nil, errors.NewErrInvalidArgument("Device", "Class B and Class C, multicast and LoRaWAN 1.1 devices are not supported by this server")
+	}
+	return m.server.SetDevice(ctx, in.V1())
+}
+
+func (m *v1DeviceManager) DeleteDevice(ctx context.Context, in *lorawan.DeviceIdentifier) (*empty.Empty, error) {
+	return m.server.DeleteDevice(ctx, in)
+}
+
+// NewV1DeviceManagerServer serves the v1 DeviceManager API with a server of the
+// v2 API, so that clients that were built against the v1 protos keep working.
+// Updates of v1 clients keep the settings that were added in v2.
+func NewV1DeviceManagerServer(server DeviceManagerServer) lorawan.DeviceManagerServer {
+	return &v2DeviceManager{server: server}
+}
+
+type v2DeviceManager struct {
+	server DeviceManagerServer
+}
+
+func (m *v2DeviceManager) GetDevice(ctx context.Context, in *lorawan.DeviceIdentifier) (*lorawan.Device, error) {
+	dev, err := m.server.GetDevice(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return dev.V1(), nil
+}
+
+func (m *v2DeviceManager) SetDevice(ctx context.Context, in *lorawan.Device) (*empty.Empty, error) {
+	dev, err := m.server.GetDevice(ctx, &lorawan.DeviceIdentifier{AppEui: in.AppEui, DevEui: in.DevEui})
+	switch {
+	case err == nil:
+		dev.Device = in
+	case errors.GetErrType(err) == errors.NotFound || grpc.Code(err) == codes.NotFound:
+		dev = FromV1(in)
+	default:
+		return nil, err
+	}
+	return m.server.SetDevice(ctx, dev)
+}
+
+func (m *v2DeviceManager) DeleteDevice(ctx context.Context, in *lorawan.DeviceIdentifier) (*empty.Empty, error) {
+	return m.server.DeleteDevice(ctx, in)
+}
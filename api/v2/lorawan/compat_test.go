@@ -0,0 +1,126 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package lorawan
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/golang/protobuf/ptypes/empty"
+	. "github.com/smartystreets/assertions"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+type testV1DeviceManager struct {
+	devices map[types.DevEUI]*lorawan.Device
+}
+
+func (m *testV1DeviceManager) GetDevice(ctx context.Context, in *lorawan.DeviceIdentifier) (*lorawan.Device, error) {
+	if dev, ok := m.devices[*in.DevEui]; ok {
+		return dev, nil
+	}
+	return nil, errors.NewErrNotFound(in.DevEui.String())
+}
+
+func (m *testV1DeviceManager) SetDevice(ctx context.Context, in *lorawan.Device) (*empty.Empty, error) {
+	m.devices[*in.DevEui] = in
+	return &empty.Empty{}, nil
+}
+
+func (m *testV1DeviceManager) DeleteDevice(ctx context.Context, in *lorawan.DeviceIdentifier) (*empty.Empty, error) {
+	delete(m.devices, *in.DevEui)
+	return &empty.Empty{}, nil
+}
+
+type testV2DeviceManager struct {
+	devices map[types.DevEUI]*Device
+}
+
+func (m *testV2DeviceManager) GetDevice(ctx context.Context, in *lorawan.DeviceIdentifier) (*Device, error) {
+	if dev, ok := m.devices[*in.DevEui]; ok {
+		return dev, nil
+	}
+	return nil, errors.NewErrNotFound(in.DevEui.String())
+}
+
+func (m *testV2DeviceManager) SetDevice(ctx context.Context, in *Device) (*empty.Empty, error) {
+	m.devices[*in.Device.DevEui] = in
+	return &empty.Empty{}, nil
+}
+
+func (m *testV2DeviceManager) DeleteDevice(ctx context.Context, in *lorawan.DeviceIdentifier) (*empty.Empty, error) {
+	delete(m.devices, *in.DevEui)
+	return &empty.Empty{}, nil
+}
+
+func testDevice() *lorawan.Device {
+	return &lorawan.Device{
+		AppEui: &types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8},
+		DevEui: &types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1},
+		AppId:  "test-app",
+		DevId:  "test-dev",
+	}
+}
+
+func TestDeviceValidate(t *testing.T) {
+	a := New(t)
+	a.So((&Device{}).Validate(), ShouldNotBeNil)
+	a.So((&Device{Device: testDevice()}).Validate(), ShouldBeNil)
+	a.So((&Device{Device: testDevice(), Class: "D"}).Validate(), ShouldNotBeNil)
+	a.So((&Device{Device: testDevice(), NwkKey: []byte{1, 2, 3}}).Validate(), ShouldNotBeNil)
+	a.So((&Device{Device: testDevice(), NwkKey: make([]byte, 16)}).Validate(), ShouldBeNil)
+}
+
+func TestDeviceManagerServer(t *testing.T) {
+	a := New(t)
+	v1 := &testV1DeviceManager{devices: make(map[types.DevEUI]*lorawan.Device)}
+	server := NewDeviceManagerServer(v1)
+	id := &lorawan.DeviceIdentifier{AppEui: testDevice().AppEui, DevEui: testDevice().DevEui}
+
+	_, err := server.SetDevice(context.Background(), &Device{Device: testDevice(), Class: ClassA})
+	a.So(err, ShouldBeNil)
+	a.So(v1.devices, ShouldHaveLength, 1)
+
+	dev, err := server.GetDevice(context.Background(), id)
+	a.So(err, ShouldBeNil)
+	a.So(dev.Class, ShouldEqual, ClassA)
+	a.So(dev.Device.DevId, ShouldEqual, "test-dev")
+
+	// The v1 server can not store the settings that were added in v2
+	_, err = server.SetDevice(context.Background(), &Device{Device: testDevice(), Class: ClassC})
+	a.So(errors.GetErrType(err), ShouldEqual, errors.InvalidArgument)
+	_, err = server.SetDevice(context.Background(), &Device{Device: testDevice(), Multicast: true})
+	a.So(errors.GetErrType(err), ShouldEqual, errors.InvalidArgument)
+
+	_, err = server.DeleteDevice(context.Background(), id)
+	a.So(err, ShouldBeNil)
+	a.So(v1.devices, ShouldBeEmpty)
+}
+
+func TestV1DeviceManagerServer(t *testing.T) {
+	a := New(t)
+	v2 := &testV2DeviceManager{devices: make(map[types.DevEUI]*Device)}
+	server := NewV1DeviceManagerServer(v2)
+	id := &lorawan.DeviceIdentifier{AppEui: testDevice().AppEui, DevEui: testDevice().DevEui}
+
+	// New devices of v1 clients are Class A devices
+	_, err := server.SetDevice(context.Background(), testDevice())
+	a.So(err, ShouldBeNil)
+	a.So(v2.devices[*id.DevEui].Class, ShouldEqual, ClassA)
+
+	// Updates of v1 clients keep the settings that were added in v2
+	v2.devices[*id.DevEui].Class = ClassC
+	update := testDevice()
+	update.FCntUp = 42
+	_, err = server.SetDevice(context.Background(), update)
+	a.So(err, ShouldBeNil)
+	a.So(v2.devices[*id.DevEui].Class, ShouldEqual, ClassC)
+	a.So(v2.devices[*id.DevEui].Device.FCntUp, ShouldEqual, 42)
+
+	dev, err := server.GetDevice(context.Background(), id)
+	a.So(err, ShouldBeNil)
+	a.So(dev.FCntUp, ShouldEqual, 42)
+}
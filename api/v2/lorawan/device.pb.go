@@ -0,0 +1,787 @@
+// Code generated by protoc-gen-gogo.
+// source: github.com/TheThingsNetwork/ttn/api/v2/lorawan/device.proto
+// DO NOT EDIT!
+
+/*
+	Package lorawan is a generated protocol buffer package.
+
+	It is generated from these files:
+		github.com/TheThingsNetwork/ttn/api/v2/lorawan/device.proto
+
+	It has these top-level messages:
+		Device
+*/
+package lorawan
+
+import proto "github.com/gogo/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/empty"
+import lorawan1 "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+import io "io"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+// Device of the v2 API. It has the fields of the v1 Device, and adds the
+// settings of Class B and Class C, multicast and LoRaWAN 1.1 devices.
+type Device struct {
+	// The fields of the v1 Device
+	Device *lorawan1.Device `protobuf:"bytes,1,opt,name=device" json:"device,omitempty"`
+	// The Class of the device: A, B or C. Devices without a class are Class A devices.
+	Class string `protobuf:"bytes,2,opt,name=class,proto3" json:"class,omitempty"`
+	// Multicast devices share their session with other devices, and only receive downlink.
+	Multicast bool `protobuf:"varint,3,opt,name=multicast,proto3" json:"multicast,omitempty"`
+	// The NwkKey is a 16 byte static key that is known by LoRaWAN 1.1 devices and the network. It is used for negotiating network session keys (OTAA).
+	NwkKey []byte `protobuf:"bytes,4,opt,name=nwk_key,json=nwkKey,proto3" json:"nwk_key,omitempty"`
+	// The FNwkSIntKey is a 16 byte session key of LoRaWAN 1.1 devices that is used for the integrity of uplink messages. It replaces the NwkSKey of the v1 Device.
+	FNwkSIntKey []byte `protobuf:"bytes,5,opt,name=f_nwk_s_int_key,json=fNwkSIntKey,proto3" json:"f_nwk_s_int_key,omitempty"`
+	// The SNwkSIntKey is a 16 byte session key of LoRaWAN 1.1 devices that is used for the integrity of downlink messages and MAC commands.
+	SNwkSIntKey []byte `protobuf:"bytes,6,opt,name=s_nwk_s_int_key,json=sNwkSIntKey,proto3" json:"s_nwk_s_int_key,omitempty"`
+	// The NwkSEncKey is a 16 byte session key of LoRaWAN 1.1 devices that is used for the encryption of MAC commands.
+	NwkSEncKey []byte `protobuf:"bytes,7,opt,name=nwk_s_enc_key,json=nwkSEncKey,proto3" json:"nwk_s_enc_key,omitempty"`
+}
+
+func (m *Device) Reset()                    { *m = Device{} }
+func (m *Device) String() string            { return proto.CompactTextString(m) }
+func (*Device) ProtoMessage()               {}
+func (*Device) Descriptor() ([]byte, []int) { return fileDescriptorDevice, []int{0} }
+
+func (m *Device) GetDevice() *lorawan1.Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+func (m *Device) GetClass() string {
+	if m != nil {
+		return m.Class
+	}
+	return ""
+}
+
+func (m *Device) GetMulticast() bool {
+	if m != nil {
+		return m.Multicast
+	}
+	return false
+}
+
+func (m *Device) GetNwkKey() []byte {
+	if m != nil {
+		return m.NwkKey
+	}
+	return nil
+}
+
+func (m *Device) GetFNwkSIntKey() []byte {
+	if m != nil {
+		return m.FNwkSIntKey
+	}
+	return nil
+}
+
+func (m *Device) GetSNwkSIntKey() []byte {
+	if m != nil {
+		return m.SNwkSIntKey
+	}
+	return nil
+}
+
+func (m *Device) GetNwkSEncKey() []byte {
+	if m != nil {
+		return m.NwkSEncKey
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Device)(nil), "lorawan.v2.Device")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for DeviceManager service
+
+type DeviceManagerClient interface {
+	GetDevice(ctx context.Context, in *lorawan1.DeviceIdentifier, opts ...grpc.CallOption) (*Device, error)
+	SetDevice(ctx context.Context, in *Device, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	DeleteDevice(ctx context.Context, in *lorawan1.DeviceIdentifier, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+}
+
+type deviceManagerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDeviceManagerClient(cc *grpc.ClientConn) DeviceManagerClient {
+	return &deviceManagerClient{cc}
+}
+
+func (c *deviceManagerClient) GetDevice(ctx context.Context, in *lorawan1.DeviceIdentifier, opts ...grpc.CallOption) (*Device, error) {
+	out := new(Device)
+	err := grpc.Invoke(ctx, "/lorawan.v2.DeviceManager/GetDevice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceManagerClient) SetDevice(ctx context.Context, in *Device, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/lorawan.v2.DeviceManager/SetDevice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceManagerClient) DeleteDevice(ctx context.Context, in *lorawan1.DeviceIdentifier, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/lorawan.v2.DeviceManager/DeleteDevice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for DeviceManager service
+
+type DeviceManagerServer interface {
+	GetDevice(context.Context, *lorawan1.DeviceIdentifier) (*Device, error)
+	SetDevice(context.Context, *Device) (*google_protobuf.Empty, error)
+	DeleteDevice(context.Context, *lorawan1.DeviceIdentifier) (*google_protobuf.Empty, error)
+}
+
+func RegisterDeviceManagerServer(s *grpc.Server, srv DeviceManagerServer) {
+	s.RegisterService(&_DeviceManager_serviceDesc, srv)
+}
+
+func _DeviceManager_GetDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(lorawan1.DeviceIdentifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceManagerServer).GetDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lorawan.v2.DeviceManager/GetDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceManagerServer).GetDevice(ctx, req.(*lorawan1.DeviceIdentifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceManager_SetDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Device)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceManagerServer).SetDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lorawan.v2.DeviceManager/SetDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceManagerServer).SetDevice(ctx, req.(*Device))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceManager_DeleteDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(lorawan1.DeviceIdentifier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceManagerServer).DeleteDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lorawan.v2.DeviceManager/DeleteDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceManagerServer).DeleteDevice(ctx, req.(*lorawan1.DeviceIdentifier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DeviceManager_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "lorawan.v2.DeviceManager",
+	HandlerType: (*DeviceManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDevice",
+			Handler:    _DeviceManager_GetDevice_Handler,
+		},
+		{
+			MethodName: "SetDevice",
+			Handler:    _DeviceManager_SetDevice_Handler,
+		},
+		{
+			MethodName: "DeleteDevice",
+			Handler:    _DeviceManager_DeleteDevice_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "github.com/TheThingsNetwork/ttn/api/v2/lorawan/device.proto",
+}
+
+func (m *Device) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Device) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Device != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDevice(dAtA, i, uint64(m.Device.Size()))
+		n1, err := m.Device.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.Class) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDevice(dAtA, i, uint64(len(m.Class)))
+		i += copy(dAtA[i:], m.Class)
+	}
+	if m.Multicast {
+		dAtA[i] = 0x18
+		i++
+		if m.Multicast {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.NwkKey) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintDevice(dAtA, i, uint64(len(m.NwkKey)))
+		i += copy(dAtA[i:], m.NwkKey)
+	}
+	if len(m.FNwkSIntKey) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintDevice(dAtA, i, uint64(len(m.FNwkSIntKey)))
+		i += copy(dAtA[i:], m.FNwkSIntKey)
+	}
+	if len(m.SNwkSIntKey) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintDevice(dAtA, i, uint64(len(m.SNwkSIntKey)))
+		i += copy(dAtA[i:], m.SNwkSIntKey)
+	}
+	if len(m.NwkSEncKey) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintDevice(dAtA, i, uint64(len(m.NwkSEncKey)))
+		i += copy(dAtA[i:], m.NwkSEncKey)
+	}
+	return i, nil
+}
+
+func encodeFixed64Device(dAtA []byte, offset int, v uint64) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	dAtA[offset+4] = uint8(v >> 32)
+	dAtA[offset+5] = uint8(v >> 40)
+	dAtA[offset+6] = uint8(v >> 48)
+	dAtA[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Device(dAtA []byte, offset int, v uint32) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintDevice(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Device) Size() (n int) {
+	var l int
+	_ = l
+	if m.Device != nil {
+		l = m.Device.Size()
+		n += 1 + l + sovDevice(uint64(l))
+	}
+	l = len(m.Class)
+	if l > 0 {
+		n += 1 + l + sovDevice(uint64(l))
+	}
+	if m.Multicast {
+		n += 2
+	}
+	l = len(m.NwkKey)
+	if l > 0 {
+		n += 1 + l + sovDevice(uint64(l))
+	}
+	l = len(m.FNwkSIntKey)
+	if l > 0 {
+		n += 1 + l + sovDevice(uint64(l))
+	}
+	l = len(m.SNwkSIntKey)
+	if l > 0 {
+		n += 1 + l + sovDevice(uint64(l))
+	}
+	l = len(m.NwkSEncKey)
+	if l > 0 {
+		n += 1 + l + sovDevice(uint64(l))
+	}
+	return n
+}
+
+func sovDevice(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozDevice(x uint64) (n int) {
+	return sovDevice(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Device) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDevice
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Device: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Device: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Device", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDevice
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Device == nil {
+				m.Device = &lorawan1.Device{}
+			}
+			if err := m.Device.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Class", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDevice
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Class = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Multicast", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Multicast = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NwkKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDevice
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NwkKey = append(m.NwkKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.NwkKey == nil {
+				m.NwkKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FNwkSIntKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDevice
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FNwkSIntKey = append(m.FNwkSIntKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.FNwkSIntKey == nil {
+				m.FNwkSIntKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SNwkSIntKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDevice
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SNwkSIntKey = append(m.SNwkSIntKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.SNwkSIntKey == nil {
+				m.SNwkSIntKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NwkSEncKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDevice
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NwkSEncKey = append(m.NwkSEncKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.NwkSEncKey == nil {
+				m.NwkSEncKey = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDevice(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDevice
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipDevice(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowDevice
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDevice
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthDevice
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowDevice
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipDevice(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthDevice = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowDevice   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() {
+	proto.RegisterFile("github.com/TheThingsNetwork/ttn/api/v2/lorawan/device.proto", fileDescriptorDevice)
+}
+
+var fileDescriptorDevice = []byte{
+	// 371 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x92, 0x41, 0xef, 0xd2, 0x30,
+	0x18, 0xc6, 0x53, 0x95, 0xe1, 0x0a, 0x84, 0xa4, 0x31, 0x3a, 0xa7, 0x21, 0xd3, 0x98, 0xb8, 0x53,
+	0x9b, 0xcc, 0x83, 0x89, 0x7a, 0xc1, 0x40, 0x0c, 0x21, 0x72, 0x18, 0x9c, 0xbc, 0x90, 0x31, 0xde,
+	0x8d, 0x66, 0xa3, 0x25, 0x5b, 0x61, 0xd9, 0x27, 0xd4, 0xa3, 0x1f, 0xc1, 0x70, 0xf7, 0x3b, 0x18,
+	0xda, 0x21, 0x86, 0x60, 0xf4, 0x7f, 0xdc, 0xf3, 0xfc, 0xf6, 0x3e, 0x7d, 0xfb, 0x14, 0xbf, 0x4f,
+	0xb9, 0xda, 0xec, 0x57, 0x34, 0x96, 0x5b, 0xb6, 0xd8, 0xc0, 0x62, 0xc3, 0x45, 0x5a, 0xce, 0x40,
+	0x55, 0xb2, 0xc8, 0x98, 0x52, 0x82, 0x45, 0x3b, 0xce, 0x0e, 0x01, 0xcb, 0x65, 0x11, 0x55, 0x91,
+	0x60, 0x6b, 0x38, 0xf0, 0x18, 0xe8, 0xae, 0x90, 0x4a, 0x12, 0xdc, 0xa8, 0xf4, 0x10, 0xb8, 0xcf,
+	0x52, 0x29, 0xd3, 0x1c, 0x98, 0x76, 0x56, 0xfb, 0x84, 0xc1, 0x76, 0xa7, 0x6a, 0x03, 0xba, 0xc3,
+	0xff, 0x49, 0xd1, 0x68, 0x2c, 0xf3, 0x9b, 0x59, 0x2f, 0x7f, 0x22, 0x6c, 0x8d, 0xb4, 0x40, 0x5e,
+	0x63, 0xcb, 0x58, 0x0e, 0xf2, 0x90, 0xdf, 0x09, 0xfa, 0xf4, 0x7c, 0x0e, 0x03, 0x84, 0x8d, 0x4d,
+	0x1e, 0xe1, 0x56, 0x9c, 0x47, 0x65, 0xe9, 0xdc, 0xf3, 0x90, 0x6f, 0x87, 0xe6, 0x83, 0x3c, 0xc7,
+	0xf6, 0x76, 0x9f, 0x2b, 0x1e, 0x47, 0xa5, 0x72, 0xee, 0x7b, 0xc8, 0x7f, 0x18, 0x5e, 0x04, 0xf2,
+	0x04, 0xb7, 0x45, 0x95, 0x2d, 0x33, 0xa8, 0x9d, 0x07, 0x1e, 0xf2, 0xbb, 0xa1, 0x25, 0xaa, 0x6c,
+	0x0a, 0x35, 0x79, 0x85, 0xfb, 0xc9, 0xf2, 0x64, 0x95, 0x4b, 0x2e, 0x94, 0x06, 0x5a, 0x1a, 0xe8,
+	0x24, 0xb3, 0x2a, 0x9b, 0x4f, 0x84, 0x6a, 0xa8, 0xf2, 0x8a, 0xb2, 0x0c, 0x55, 0xfe, 0x41, 0xbd,
+	0xc0, 0x3d, 0xc3, 0x80, 0x88, 0x35, 0xd3, 0xd6, 0x0c, 0x16, 0x55, 0x36, 0x1f, 0x8b, 0x78, 0x0a,
+	0x75, 0xf0, 0x15, 0xe1, 0x9e, 0x59, 0xe7, 0x73, 0x24, 0xa2, 0x14, 0x0a, 0xf2, 0x0e, 0xdb, 0x9f,
+	0x40, 0x35, 0x77, 0xf0, 0xf4, 0x6a, 0xe7, 0xc9, 0x1a, 0x84, 0xe2, 0x09, 0x87, 0xc2, 0x25, 0xf4,
+	0x52, 0x4b, 0xe3, 0x92, 0xb7, 0xd8, 0x9e, 0xff, 0xfe, 0xf7, 0x06, 0xe0, 0x3e, 0xa6, 0xa6, 0x3f,
+	0x7a, 0xee, 0x8f, 0x8e, 0x4f, 0xfd, 0x91, 0x21, 0xee, 0x8e, 0x20, 0x07, 0x05, 0xff, 0xce, 0xfd,
+	0xcb, 0x88, 0x8f, 0x1f, 0xbe, 0x1d, 0x07, 0xe8, 0xfb, 0x71, 0x80, 0x7e, 0x1c, 0x07, 0xe8, 0x0b,
+	0xbd, 0xdb, 0x83, 0x5b, 0x59, 0x7a, 0xda, 0x9b, 0x5f, 0x03, 0x00, 0xad, 0x5f, 0x22, 0x05, 0xa9,
+	0x02, 0x00, 0x00,
+}
@@ -0,0 +1,32 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package lorawan
+
+import (
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// Validate implements the api.Validator interface
+func (m *Device) Validate() error {
+	if err := api.NotNilAndValid(m.Device, "Device"); err != nil {
+		return err
+	}
+	switch m.Class {
+	case "", ClassA, ClassB, ClassC:
+	default:
+		return errors.NewErrInvalidArgument("Class", "must be A, B or C")
+	}
+	for name, key := range map[string][]byte{
+		"NwkKey":      m.NwkKey,
+		"FNwkSIntKey": m.FNwkSIntKey,
+		"SNwkSIntKey": m.SNwkSIntKey,
+		"NwkSEncKey":  m.NwkSEncKey,
+	} {
+		if len(key) != 0 && len(key) != 16 {
+			return errors.NewErrInvalidArgument(name, "must be 16 bytes")
+		}
+	}
+	return nil
+}
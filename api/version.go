@@ -0,0 +1,64 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc/metadata"
+)
+
+// API versions. Clients that do not send an api-version in the metadata are
+// assumed to be built against the V1 protos.
+const (
+	V1 = 1
+	V2 = 2
+
+	LatestVersion = V2
+)
+
+// ParseVersion parses an API version such as "v2"
+func ParseVersion(version string) (int, error) {
+	var v int
+	if _, err := fmt.Sscanf(strings.ToLower(version), "v%d", &v); err != nil || v < V1 || v > LatestVersion {
+		return 0, errors.NewErrInvalidArgument("Metadata", fmt.Sprintf("api-version %s is not supported", version))
+	}
+	return v, nil
+}
+
+// FormatVersion formats an API version as "v2"
+func FormatVersion(version int) string {
+	return fmt.Sprintf("v%d", version)
+}
+
+// VersionFromMetadata returns the API version of the client, or V1 if the
+// client did not send a version
+func VersionFromMetadata(md metadata.MD) (int, error) {
+	version, ok := md["api-version"]
+	if !ok || len(version) == 0 {
+		return V1, nil
+	}
+	return ParseVersion(version[0])
+}
+
+// VersionFromContext returns the API version of the client, or V1 if the
+// client did not send a version
+func VersionFromContext(ctx context.Context) (int, error) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return V1, nil
+	}
+	return VersionFromMetadata(md)
+}
+
+// ContextWithVersion returns a context that sends the API version to servers
+func ContextWithVersion(ctx context.Context, version int) context.Context {
+	md, _ := metadata.FromContext(ctx)
+	md = metadata.Join(md)
+	md["api-version"] = []string{FormatVersion(version)}
+	return metadata.NewContext(ctx, md)
+}
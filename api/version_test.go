@@ -0,0 +1,49 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestVersion(t *testing.T) {
+	a := New(t)
+
+	version, err := ParseVersion("v2")
+	a.So(err, ShouldBeNil)
+	a.So(version, ShouldEqual, V2)
+	a.So(FormatVersion(version), ShouldEqual, "v2")
+
+	for _, invalid := range []string{"", "2", "v0", "v3", "latest"} {
+		_, err = ParseVersion(invalid)
+		a.So(err, ShouldNotBeNil)
+	}
+
+	version, err = VersionFromContext(context.Background())
+	a.So(err, ShouldBeNil)
+	a.So(version, ShouldEqual, V1)
+
+	ctx := metadata.NewContext(context.Background(), metadata.Pairs("token", "token"))
+	version, err = VersionFromContext(ctx)
+	a.So(err, ShouldBeNil)
+	a.So(version, ShouldEqual, V1)
+
+	versionCtx := ContextWithVersion(ctx, V2)
+	version, err = VersionFromContext(versionCtx)
+	a.So(err, ShouldBeNil)
+	a.So(version, ShouldEqual, V2)
+	token, _ := TokenFromContext(versionCtx)
+	a.So(token, ShouldEqual, "token")
+
+	// The metadata of the original context is not changed
+	version, _ = VersionFromContext(ctx)
+	a.So(version, ShouldEqual, V1)
+
+	_, err = VersionFromContext(metadata.NewContext(context.Background(), metadata.Pairs("api-version", "v9")))
+	a.So(err, ShouldNotBeNil)
+}
@@ -0,0 +1,141 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package client is a client for applications on The Things Network. It
+// receives the messages and events of the devices of an application over MQTT,
+// sends downlink messages and manages devices on the Handler.
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb_handler "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"google.golang.org/grpc"
+)
+
+// Config is the configuration of a Client
+type Config struct {
+	// The ID and an access key of the application
+	AppID        string
+	AppAccessKey string
+
+	// The address of the MQTT broker of the Handler, for example "tcp://eu.thethings.network:1883"
+	MQTTAddress string
+
+	// The address of the gRPC API of the Handler, for example
+	// "eu.thethings.network:1904". It is only required to manage devices.
+	HandlerAddress string
+
+	// The number of messages that are buffered for each subscription (default 10)
+	BufferSize int
+
+	// The time to wait for the MQTT broker to acknowledge requests (default 10s)
+	Timeout time.Duration
+
+	// The logger of the client (default log.Get())
+	Logger log.Interface
+}
+
+// Client of an application. The client reconnects automatically if the
+// connection to the MQTT broker is lost, and subscribes to its topics again.
+type Client struct {
+	config Config
+	ctx    log.Interface
+	mqtt   mqtt.Client
+
+	mu            sync.Mutex
+	subscriptions map[string]bool
+	done          chan struct{}
+
+	conn    *grpc.ClientConn
+	manager pb_handler.ApplicationManagerClient
+}
+
+// New returns a new Client that is connected to the MQTT broker of the Handler
+func New(config Config) (*Client, error) {
+	if config.AppID == "" || config.AppAccessKey == "" {
+		return nil, errors.NewErrInvalidArgument("Config", "AppID and AppAccessKey are required")
+	}
+	if config.MQTTAddress == "" {
+		return nil, errors.NewErrInvalidArgument("Config", "MQTTAddress is required")
+	}
+	if config.Logger == nil {
+		config.Logger = log.Get()
+	}
+	c := newClient(config, mqtt.NewClient(config.Logger, "ttn-client", config.AppID, config.AppAccessKey, config.MQTTAddress))
+	if err := c.mqtt.Connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func newClient(config Config, mqttClient mqtt.Client) *Client {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 10
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.Logger == nil {
+		config.Logger = log.Get()
+	}
+	return &Client{
+		config:        config,
+		ctx:           config.Logger.WithField("AppID", config.AppID),
+		mqtt:          mqttClient,
+		subscriptions: make(map[string]bool),
+		done:          make(chan struct{}),
+	}
+}
+
+// Close ends all subscriptions and disconnects the client
+func (c *Client) Close() error {
+	c.mu.Lock()
+	select {
+	case <-c.done:
+		c.mu.Unlock()
+		return nil
+	default:
+		close(c.done)
+	}
+	conn := c.conn
+	c.mu.Unlock()
+	c.mqtt.Disconnect()
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// wait waits for the MQTT broker to acknowledge the request of the token
+func (c *Client) wait(token mqtt.Token) error {
+	if !token.WaitTimeout(c.config.Timeout) {
+		return fmt.Errorf("MQTT broker did not respond in %s", c.config.Timeout)
+	}
+	return token.Error()
+}
+
+// handlerManager connects to the Handler on first use
+func (c *Client) handlerManager() (pb_handler.ApplicationManagerClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.manager != nil {
+		return c.manager, nil
+	}
+	if c.config.HandlerAddress == "" {
+		return nil, errors.NewErrInvalidArgument("Config", "HandlerAddress is required to manage devices")
+	}
+	conn, err := api.Dial(c.config.HandlerAddress)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.manager = pb_handler.NewApplicationManagerClient(conn)
+	return c.manager, nil
+}
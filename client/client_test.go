@@ -0,0 +1,169 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/smartystreets/assertions"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+var host string
+
+func init() {
+	host = os.Getenv("MQTT_ADDRESS")
+	if host == "" {
+		host = "localhost:1883"
+	}
+}
+
+func getTestClients(t *testing.T, tag string) (*Client, mqtt.Client) {
+	ctx := GetLogger(t, tag)
+	appClient := mqtt.NewClient(ctx, "test", "", "", "tcp://"+host)
+	if err := appClient.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	handlerClient := mqtt.NewClient(ctx, "test", "", "", "tcp://"+host)
+	if err := handlerClient.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	return newClient(Config{AppID: "client-test", Timeout: time.Second, Logger: ctx}, appClient), handlerClient
+}
+
+func TestNew(t *testing.T) {
+	a := assertions.New(t)
+	_, err := New(Config{MQTTAddress: "tcp://" + host})
+	a.So(errors.GetErrType(err), assertions.ShouldEqual, errors.InvalidArgument)
+	_, err = New(Config{AppID: "client-test", AppAccessKey: "key"})
+	a.So(errors.GetErrType(err), assertions.ShouldEqual, errors.InvalidArgument)
+}
+
+func TestSubscribeUplinks(t *testing.T) {
+	a := assertions.New(t)
+	c, handler := getTestClients(t, "TestSubscribeUplinks")
+	defer c.Close()
+	defer handler.Disconnect()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	uplinks, err := c.SubscribeUplinks(ctx, "dev")
+	a.So(err, assertions.ShouldBeNil)
+
+	_, err = c.SubscribeUplinks(ctx, "dev")
+	a.So(errors.GetErrType(err), assertions.ShouldEqual, errors.AlreadyExists)
+
+	handler.PublishUplink(types.UplinkMessage{AppID: "client-test", DevID: "dev", FPort: 1, PayloadRaw: []byte{0x01}}).Wait()
+	handler.PublishUplink(types.UplinkMessage{AppID: "client-test", DevID: "other", FPort: 1, PayloadRaw: []byte{0x02}}).Wait()
+
+	select {
+	case uplink := <-uplinks:
+		a.So(uplink.DevID, assertions.ShouldEqual, "dev")
+		a.So(uplink.PayloadRaw, assertions.ShouldResemble, []byte{0x01})
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive uplink")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-uplinks:
+		a.So(ok, assertions.ShouldBeFalse)
+	case <-time.After(time.Second):
+		t.Fatal("Subscription was not closed")
+	}
+
+	// The subscription can be started again
+	time.Sleep(10 * time.Millisecond)
+	_, err = c.SubscribeUplinks(context.Background(), "dev")
+	a.So(err, assertions.ShouldBeNil)
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	a := assertions.New(t)
+	c, handler := getTestClients(t, "TestSubscribeEvents")
+	defer handler.Disconnect()
+
+	activations, err := c.SubscribeActivations(context.Background(), "")
+	a.So(err, assertions.ShouldBeNil)
+	deviceEvents, err := c.SubscribeDeviceEvents(context.Background(), "", types.ActivationEvent)
+	a.So(err, assertions.ShouldBeNil)
+	appEvents, err := c.SubscribeAppEvents(context.Background(), "")
+	a.So(err, assertions.ShouldBeNil)
+
+	handler.PublishActivation(types.Activation{AppID: "client-test", DevID: "dev", DevAddr: types.DevAddr{1, 2, 3, 4}}).Wait()
+	handler.PublishDeviceEvent("client-test", "dev", types.ActivationEvent, map[string]string{"dev_addr": "01020304"}).Wait()
+	handler.PublishAppEvent("client-test", types.QuotaWarningEvent, types.QuotaEventData{Metric: "uplinks"}).Wait()
+
+	select {
+	case activation := <-activations:
+		a.So(activation.DevAddr, assertions.ShouldEqual, types.DevAddr{1, 2, 3, 4})
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive activation")
+	}
+	select {
+	case event := <-deviceEvents:
+		a.So(event.DevID, assertions.ShouldEqual, "dev")
+		a.So(event.Event, assertions.ShouldEqual, types.ActivationEvent)
+		a.So(string(event.Data), assertions.ShouldContainSubstring, "01020304")
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive device event")
+	}
+	select {
+	case event := <-appEvents:
+		a.So(event.DevID, assertions.ShouldBeEmpty)
+		a.So(event.Event, assertions.ShouldEqual, types.QuotaWarningEvent)
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive application event")
+	}
+
+	// Closing the client closes all subscriptions
+	c.Close()
+	for _, closed := range []func() bool{
+		func() bool { _, ok := <-activations; return !ok },
+		func() bool { _, ok := <-deviceEvents; return !ok },
+		func() bool { _, ok := <-appEvents; return !ok },
+	} {
+		a.So(closed(), assertions.ShouldBeTrue)
+	}
+}
+
+func TestSendDownlink(t *testing.T) {
+	a := assertions.New(t)
+	c, handler := getTestClients(t, "TestSendDownlink")
+	defer c.Close()
+	defer handler.Disconnect()
+
+	downlinks := make(chan types.DownlinkMessage, 1)
+	handler.SubscribeAppDownlink("client-test", func(_ mqtt.Client, _, _ string, msg types.DownlinkMessage) {
+		downlinks <- msg
+	}).Wait()
+
+	err := c.SendDownlink("", &Downlink{FPort: 1, PayloadRaw: []byte{0x01}})
+	a.So(errors.GetErrType(err), assertions.ShouldEqual, errors.InvalidArgument)
+	err = c.SendDownlink("dev", &Downlink{FPort: 1})
+	a.So(errors.GetErrType(err), assertions.ShouldEqual, errors.InvalidArgument)
+
+	err = c.SendDownlink("dev", &Downlink{FPort: 1, PayloadFields: map[string]interface{}{"led": true}})
+	a.So(err, assertions.ShouldBeNil)
+	select {
+	case downlink := <-downlinks:
+		a.So(downlink.AppID, assertions.ShouldEqual, "client-test")
+		a.So(downlink.DevID, assertions.ShouldEqual, "dev")
+		a.So(downlink.PayloadFields, assertions.ShouldResemble, map[string]interface{}{"led": true})
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive downlink")
+	}
+}
+
+func TestDevicesWithoutHandler(t *testing.T) {
+	a := assertions.New(t)
+	c := newClient(Config{AppID: "client-test", AppAccessKey: "key"}, nil)
+	_, err := c.GetDevice(context.Background(), "dev")
+	a.So(errors.GetErrType(err), assertions.ShouldEqual, errors.InvalidArgument)
+}
@@ -0,0 +1,75 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"strconv"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	pb_handler "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcContext returns a context with the access key of the application
+func (c *Client) grpcContext(ctx context.Context, pairs ...string) context.Context {
+	md, _ := metadata.FromContext(ctx)
+	md = metadata.Join(md, metadata.Pairs(append([]string{"key", c.config.AppAccessKey}, pairs...)...))
+	return api.ContextWithVersion(metadata.NewContext(ctx, md), api.LatestVersion)
+}
+
+// GetDevice returns a device of the application
+func (c *Client) GetDevice(ctx context.Context, devID string) (*pb_handler.Device, error) {
+	manager, err := c.handlerManager()
+	if err != nil {
+		return nil, err
+	}
+	dev, err := manager.GetDevice(c.grpcContext(ctx), &pb_handler.DeviceIdentifier{AppId: c.config.AppID, DevId: devID})
+	if err != nil {
+		return nil, errors.FromGRPCError(err)
+	}
+	return dev, nil
+}
+
+// ListDevices returns the devices of the application, a limit of 0 returns all devices
+func (c *Client) ListDevices(ctx context.Context, limit, offset int) ([]*pb_handler.Device, error) {
+	manager, err := c.handlerManager()
+	if err != nil {
+		return nil, err
+	}
+	res, err := manager.GetDevicesForApplication(
+		c.grpcContext(ctx, "limit", strconv.Itoa(limit), "offset", strconv.Itoa(offset)),
+		&pb_handler.ApplicationIdentifier{AppId: c.config.AppID},
+	)
+	if err != nil {
+		return nil, errors.FromGRPCError(err)
+	}
+	return res.Devices, nil
+}
+
+// SetDevice creates or updates a device of the application
+func (c *Client) SetDevice(ctx context.Context, dev *pb_handler.Device) error {
+	manager, err := c.handlerManager()
+	if err != nil {
+		return err
+	}
+	dev.AppId = c.config.AppID
+	if _, err := manager.SetDevice(c.grpcContext(ctx), dev); err != nil {
+		return errors.FromGRPCError(err)
+	}
+	return nil
+}
+
+// DeleteDevice deletes a device of the application
+func (c *Client) DeleteDevice(ctx context.Context, devID string) error {
+	manager, err := c.handlerManager()
+	if err != nil {
+		return err
+	}
+	if _, err := manager.DeleteDevice(c.grpcContext(ctx), &pb_handler.DeviceIdentifier{AppId: c.config.AppID, DevId: devID}); err != nil {
+		return errors.FromGRPCError(err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// Downlink is a downlink message for a device. It should have either a
+// PayloadRaw or PayloadFields that are encoded by the payload functions of the
+// application.
+type Downlink types.DownlinkMessage
+
+// SendDownlink enqueues a downlink message for a device of the application
+func (c *Client) SendDownlink(devID string, downlink *Downlink) error {
+	if devID == "" {
+		return errors.NewErrInvalidArgument("DevID", "can not be empty")
+	}
+	if downlink.PayloadRaw == nil && downlink.PayloadFields == nil {
+		return errors.NewErrInvalidArgument("Downlink", "needs a payload")
+	}
+	msg := types.DownlinkMessage(*downlink)
+	msg.AppID = c.config.AppID
+	msg.DevID = devID
+	return c.wait(c.mqtt.PublishDownlink(msg))
+}
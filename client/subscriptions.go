@@ -0,0 +1,205 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+// Uplink is an uplink message of a device
+type Uplink types.UplinkMessage
+
+// Activation is an activation of a device
+type Activation types.Activation
+
+// Event is an event of the application or of one of its devices
+type Event struct {
+	DevID string          // empty for events of the application
+	Event types.EventType // for example "activations/errors" or "down/acks"
+	Data  json.RawMessage // the data of the event
+}
+
+// subscription delivers messages to a channel until it is closed
+type subscription struct {
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// deliver calls send unless the subscription is closed. Send should return
+// when done is closed.
+func (s *subscription) deliver(send func(done <-chan struct{})) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	send(s.done)
+}
+
+// subscribe subscribes to a topic of the application with the subscribe
+// function until the context is done or the client is closed. It then
+// unsubscribes and calls closeChannel.
+func (c *Client) subscribe(ctx context.Context, key string, subscribe func(s *subscription) mqtt.Token, unsubscribe func() mqtt.Token, closeChannel func()) error {
+	c.mu.Lock()
+	if c.subscriptions[key] {
+		c.mu.Unlock()
+		return errors.NewErrAlreadyExists("Subscription to " + key)
+	}
+	c.subscriptions[key] = true
+	c.mu.Unlock()
+
+	s := &subscription{done: make(chan struct{})}
+	if err := c.wait(subscribe(s)); err != nil {
+		c.mu.Lock()
+		delete(c.subscriptions, key)
+		c.mu.Unlock()
+		return err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.done:
+		}
+		close(s.done)
+		if err := c.wait(unsubscribe()); err != nil {
+			c.ctx.WithError(err).Warnf("Could not unsubscribe from %s", key)
+		}
+		s.mu.Lock()
+		s.closed = true
+		closeChannel()
+		s.mu.Unlock()
+		c.mu.Lock()
+		delete(c.subscriptions, key)
+		c.mu.Unlock()
+	}()
+	return nil
+}
+
+// SubscribeUplinks returns a channel with the uplink messages of the device,
+// or of all devices of the application if devID is empty. The channel is
+// closed when the context is done or the client is closed.
+func (c *Client) SubscribeUplinks(ctx context.Context, devID string) (<-chan *Uplink, error) {
+	uplinks := make(chan *Uplink, c.config.BufferSize)
+	err := c.subscribe(ctx, "uplinks of "+deviceOrAll(devID), func(s *subscription) mqtt.Token {
+		return c.mqtt.SubscribeDeviceUplink(c.config.AppID, devID, func(_ mqtt.Client, _, _ string, msg types.UplinkMessage) {
+			uplink := Uplink(msg)
+			s.deliver(func(done <-chan struct{}) {
+				select {
+				case uplinks <- &uplink:
+				case <-done:
+				}
+			})
+		})
+	}, func() mqtt.Token {
+		return c.mqtt.UnsubscribeDeviceUplink(c.config.AppID, devID)
+	}, func() {
+		close(uplinks)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uplinks, nil
+}
+
+// SubscribeActivations returns a channel with the activations of the device,
+// or of all devices of the application if devID is empty. The channel is
+// closed when the context is done or the client is closed.
+func (c *Client) SubscribeActivations(ctx context.Context, devID string) (<-chan *Activation, error) {
+	activations := make(chan *Activation, c.config.BufferSize)
+	err := c.subscribe(ctx, "activations of "+deviceOrAll(devID), func(s *subscription) mqtt.Token {
+		return c.mqtt.SubscribeDeviceActivations(c.config.AppID, devID, func(_ mqtt.Client, _, _ string, msg types.Activation) {
+			activation := Activation(msg)
+			s.deliver(func(done <-chan struct{}) {
+				select {
+				case activations <- &activation:
+				case <-done:
+				}
+			})
+		})
+	}, func() mqtt.Token {
+		return c.mqtt.UnsubscribeDeviceActivations(c.config.AppID, devID)
+	}, func() {
+		close(activations)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return activations, nil
+}
+
+// SubscribeDeviceEvents returns a channel with the events of the given type of
+// the device, or of all devices of the application if devID is empty. An empty
+// event type subscribes to all events that have a single-level type, such as
+// "activations". The channel is closed when the context is done or the client
+// is closed.
+func (c *Client) SubscribeDeviceEvents(ctx context.Context, devID string, eventType types.EventType) (<-chan *Event, error) {
+	events := make(chan *Event, c.config.BufferSize)
+	err := c.subscribe(ctx, "events "+eventOrAll(eventType)+" of "+deviceOrAll(devID), func(s *subscription) mqtt.Token {
+		return c.mqtt.SubscribeDeviceEvents(c.config.AppID, devID, eventType, func(_ mqtt.Client, _, devID string, eventType types.EventType, payload []byte) {
+			event := &Event{DevID: devID, Event: eventType, Data: json.RawMessage(payload)}
+			s.deliver(func(done <-chan struct{}) {
+				select {
+				case events <- event:
+				case <-done:
+				}
+			})
+		})
+	}, func() mqtt.Token {
+		return c.mqtt.UnsubscribeDeviceEvents(c.config.AppID, devID, eventType)
+	}, func() {
+		close(events)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// SubscribeAppEvents returns a channel with the events of the given type of the
+// application. An empty event type subscribes to all events. The channel is
+// closed when the context is done or the client is closed.
+func (c *Client) SubscribeAppEvents(ctx context.Context, eventType types.EventType) (<-chan *Event, error) {
+	events := make(chan *Event, c.config.BufferSize)
+	err := c.subscribe(ctx, "events "+eventOrAll(eventType)+" of application", func(s *subscription) mqtt.Token {
+		return c.mqtt.SubscribeAppEvents(c.config.AppID, eventType, func(_ mqtt.Client, _ string, eventType types.EventType, payload []byte) {
+			event := &Event{Event: eventType, Data: json.RawMessage(payload)}
+			s.deliver(func(done <-chan struct{}) {
+				select {
+				case events <- event:
+				case <-done:
+				}
+			})
+		})
+	}, func() mqtt.Token {
+		return c.mqtt.UnsubscribeAppEvents(c.config.AppID, eventType)
+	}, func() {
+		close(events)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func deviceOrAll(devID string) string {
+	if devID == "" {
+		return "all devices"
+	}
+	return "device " + devID
+}
+
+func eventOrAll(eventType types.EventType) string {
+	if eventType == "" {
+		return "*"
+	}
+	return string(eventType)
+}
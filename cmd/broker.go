@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -17,6 +18,7 @@ import (
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	"github.com/TheThingsNetwork/ttn/core/broker"
 	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/proxy"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -43,6 +45,11 @@ var brokerCmd = &cobra.Command{
 			ctx.WithError(err).Fatal("Could not initialize component")
 		}
 
+		httpActive := viper.GetString("broker.http-address") != "" && viper.GetInt("broker.http-port") != 0
+		if httpActive && component.Identity.ApiAddress == "" {
+			component.Identity.ApiAddress = fmt.Sprintf("http://%s:%d", viper.GetString("broker.server-address-announce"), viper.GetInt("broker.http-port"))
+		}
+
 		var nsCert string
 		if nsCertFile := viper.GetString("broker.networkserver-cert"); nsCertFile != "" {
 			contents, err := ioutil.ReadFile(nsCertFile)
@@ -52,11 +59,70 @@ var brokerCmd = &cobra.Command{
 			nsCert = string(contents)
 		}
 
+		// Peering
+		var peeringExporter broker.PeeringExporter
+		peeringConfig := broker.PeeringConfig{
+			NetworkID:  viper.GetString("broker.peering-network-id"),
+			HashSecret: viper.GetString("broker.peering-hash-secret"),
+		}
+		if peeringConfig.NetworkID == "" {
+			peeringConfig.NetworkID = component.Identity.Id
+		}
+		if peeringURL := viper.GetString("broker.peering-nats-url"); peeringURL != "" {
+			peeringExporter, err = broker.NewNATSPeeringExporter(ctx, peeringURL, viper.GetString("broker.peering-subject"), viper.GetString("broker.peering-nats-cert"))
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not connect to peering network")
+			}
+		}
+
+		// MIC checks
+		micCheckOptions := broker.DefaultMICCheckOptions
+		micCheckOptions.FrameCacheSize = viper.GetInt("broker.mic-cache-size")
+		micCheckOptions.NwkSKeyCheck = viper.GetBool("broker.mic-nwkskey-check")
+		micCheckOptions.ReplayDistance = viper.GetFloat64("broker.mic-replay-distance")
+		micCheckOptions.RetransmissionWindow = viper.GetDuration("broker.mic-retransmission-window")
+
+		// Join throttling
+		joinRateLimits := broker.JoinRateLimits{
+			PerDevice:  viper.GetInt("broker.join-rate-device"),
+			PerGateway: viper.GetInt("broker.join-rate-gateway"),
+			MaxPenalty: viper.GetDuration("broker.join-penalty-max"),
+		}
+
+		// Shadow Handlers
+		shadowHandlers, err := broker.ParseShadowHandlers(viper.GetStringSlice("broker.shadow-handlers"))
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not parse shadow Handlers")
+		}
+
+		// Gateway groups
+		var gatewayGroups broker.GatewayGroups
+		if filename := viper.GetString("broker.gateway-groups"); filename != "" {
+			gatewayGroups, err = broker.LoadGatewayGroupsFile(filename)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not load gateway groups")
+			}
+		}
+
 		// Broker
 		broker := broker.NewBroker(
 			time.Duration(viper.GetInt("broker.deduplication-delay")) * time.Millisecond,
 		)
 		broker.SetNetworkServer(viper.GetString("broker.networkserver-address"), nsCert, viper.GetString("broker.networkserver-token"))
+		if peeringExporter != nil {
+			broker.SetPeering(peeringExporter, peeringConfig)
+		}
+		broker.SetMICCheck(micCheckOptions)
+		broker.SetTenants(getTenants())
+		broker.SetShadowHandlers(shadowHandlers)
+		broker.SetDeduplicationAlert(uint64(viper.GetInt("broker.alert-deduplication-late")))
+		broker.SetHandlerReplay(viper.GetInt("broker.handler-replay-size"), viper.GetDuration("broker.handler-replay-age"))
+		broker.SetJoinRateLimits(joinRateLimits)
+		broker.SetInvalidMICRate(viper.GetInt("broker.mic-invalid-rate"))
+		if err := broker.SetGatewayGroups(gatewayGroups); err != nil {
+			ctx.WithError(err).Fatal("Invalid gateway groups")
+		}
+		broker.SetRoutingTable(viper.GetBool("broker.routing-table"))
 		err = broker.Init(component)
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize broker")
@@ -71,10 +137,23 @@ var brokerCmd = &cobra.Command{
 
 		// Register and Listen
 		component.RegisterHealthServer(grpc)
+		component.RegisterDebugServer(grpc)
 		broker.RegisterRPC(grpc)
 		broker.RegisterManager(grpc)
 		go grpc.Serve(lis)
 
+		if httpActive {
+			go func() {
+				err := http.ListenAndServe(
+					fmt.Sprintf("%s:%d", viper.GetString("broker.http-address"), viper.GetInt("broker.http-port")),
+					proxy.WithLogger(broker.HTTPHandler(), ctx),
+				)
+				if err != nil {
+					ctx.WithError(err).Fatal("Error in HTTP server")
+				}
+			}()
+		}
+
 		sigChan := make(chan os.Signal)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		ctx.WithField("signal", <-sigChan).Info("signal received")
@@ -97,10 +176,61 @@ func init() {
 	brokerCmd.Flags().Int("deduplication-delay", 200, "Deduplication delay (in ms)")
 	viper.BindPFlag("broker.deduplication-delay", brokerCmd.Flags().Lookup("deduplication-delay"))
 
+	brokerCmd.Flags().Int("mic-cache-size", 10000, "Number of recent frames that is remembered to reject replays (0 to disable)")
+	viper.BindPFlag("broker.mic-cache-size", brokerCmd.Flags().Lookup("mic-cache-size"))
+	brokerCmd.Flags().Bool("mic-nwkskey-check", false, "Reject uplink that does not validate with the devices that the Networkserver recently returned for its DevAddr")
+	viper.BindPFlag("broker.mic-nwkskey-check", brokerCmd.Flags().Lookup("mic-nwkskey-check"))
+	brokerCmd.Flags().Float64("mic-replay-distance", 0, "Distance in meters from the original gateways above which a replayed frame quarantines the device (0 to disable)")
+	viper.BindPFlag("broker.mic-replay-distance", brokerCmd.Flags().Lookup("mic-replay-distance"))
+	brokerCmd.Flags().Duration("mic-retransmission-window", time.Minute, "Time after a frame in which identical frames are dropped as repetitions of the device instead of rejected as replays")
+	viper.BindPFlag("broker.mic-retransmission-window", brokerCmd.Flags().Lookup("mic-retransmission-window"))
+	brokerCmd.Flags().Int("mic-invalid-rate", 0, "Number of frames with an invalid MIC per minute that an untrusted gateway can forward before its uplinks are dropped (0 to disable)")
+	viper.BindPFlag("broker.mic-invalid-rate", brokerCmd.Flags().Lookup("mic-invalid-rate"))
+
+	brokerCmd.Flags().Int("alert-deduplication-late", 0, "Number of uplink messages per minute that arrive after their deduplication window above which an alert is sent (0 to disable)")
+	viper.BindPFlag("broker.alert-deduplication-late", brokerCmd.Flags().Lookup("alert-deduplication-late"))
+
+	brokerCmd.Flags().Int("handler-replay-size", 1000, "Number of uplink messages per Handler that are kept until the Handler acknowledges them, and replayed when it subscribes again (0 to disable)")
+	brokerCmd.Flags().Duration("handler-replay-age", 5*time.Minute, "Maximum age of uplink messages that are replayed to a Handler")
+	viper.BindPFlag("broker.handler-replay-size", brokerCmd.Flags().Lookup("handler-replay-size"))
+	viper.BindPFlag("broker.handler-replay-age", brokerCmd.Flags().Lookup("handler-replay-age"))
+
+	brokerCmd.Flags().Int("join-rate-device", 0, "Number of join requests per minute that is accepted from a device (0 to disable)")
+	brokerCmd.Flags().Int("join-rate-gateway", 0, "Number of join requests per minute that is accepted through a gateway (0 to disable)")
+	brokerCmd.Flags().Duration("join-penalty-max", time.Hour, "Maximum duration for which the join requests of a device that exceeds its join rate are dropped")
+	viper.BindPFlag("broker.join-rate-device", brokerCmd.Flags().Lookup("join-rate-device"))
+	viper.BindPFlag("broker.join-rate-gateway", brokerCmd.Flags().Lookup("join-rate-gateway"))
+	viper.BindPFlag("broker.join-penalty-max", brokerCmd.Flags().Lookup("join-penalty-max"))
+
+	brokerCmd.Flags().StringSlice("shadow-handlers", []string{}, "Read-only Handlers that receive copies of the uplink messages of an AppEUI (AppEUI=HandlerID)")
+	viper.BindPFlag("broker.shadow-handlers", brokerCmd.Flags().Lookup("shadow-handlers"))
+
+	brokerCmd.Flags().String("gateway-groups", "", "YAML file with groups of gateways and the default downlink preferences of applications and devices for these groups")
+	viper.BindPFlag("broker.gateway-groups", brokerCmd.Flags().Lookup("gateway-groups"))
+
+	brokerCmd.Flags().Bool("routing-table", false, "Cache the Handlers of each application in a routing table that is invalidated by the Discovery server")
+	viper.BindPFlag("broker.routing-table", brokerCmd.Flags().Lookup("routing-table"))
+
+	brokerCmd.Flags().String("peering-nats-url", "", "URL of the NATS server to export deduplicated uplink messages to (nats://host:port or tls://host:port)")
+	viper.BindPFlag("broker.peering-nats-url", brokerCmd.Flags().Lookup("peering-nats-url"))
+	brokerCmd.Flags().String("peering-nats-cert", "", "Location of the CA certificate of the NATS server (if it uses TLS with a custom CA)")
+	viper.BindPFlag("broker.peering-nats-cert", brokerCmd.Flags().Lookup("peering-nats-cert"))
+	brokerCmd.Flags().String("peering-subject", "ttn.peering.uplink", "NATS subject for exported uplink messages")
+	viper.BindPFlag("broker.peering-subject", brokerCmd.Flags().Lookup("peering-subject"))
+	brokerCmd.Flags().String("peering-network-id", "", "ID of this network in exported uplink messages (default is the broker ID)")
+	viper.BindPFlag("broker.peering-network-id", brokerCmd.Flags().Lookup("peering-network-id"))
+	brokerCmd.Flags().String("peering-hash-secret", "", "Secret for hashing device identifiers in exported uplink messages (empty to export them as-is)")
+	viper.BindPFlag("broker.peering-hash-secret", brokerCmd.Flags().Lookup("peering-hash-secret"))
+
 	brokerCmd.Flags().String("server-address", "0.0.0.0", "The IP address to listen for communication")
 	brokerCmd.Flags().String("server-address-announce", "localhost", "The public IP address to announce")
 	brokerCmd.Flags().Int("server-port", 1902, "The port for communication")
 	viper.BindPFlag("broker.server-address", brokerCmd.Flags().Lookup("server-address"))
 	viper.BindPFlag("broker.server-address-announce", brokerCmd.Flags().Lookup("server-address-announce"))
 	viper.BindPFlag("broker.server-port", brokerCmd.Flags().Lookup("server-port"))
+
+	brokerCmd.Flags().String("http-address", "0.0.0.0", "The IP address where the HTTP API should listen")
+	brokerCmd.Flags().Int("http-port", 0, "The port where the HTTP API should listen (0 to disable)")
+	viper.BindPFlag("broker.http-address", brokerCmd.Flags().Lookup("http-address"))
+	viper.BindPFlag("broker.http-port", brokerCmd.Flags().Lookup("http-port"))
 }
@@ -55,6 +55,7 @@ var discoveryCmd = &cobra.Command{
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize component")
 		}
+		watchRedis(component, client)
 
 		// Discovery Server
 		discovery := discovery.NewRedisDiscovery(client)
@@ -76,6 +77,7 @@ var discoveryCmd = &cobra.Command{
 
 		// Register and Listen
 		component.RegisterHealthServer(grpc)
+		component.RegisterDebugServer(grpc)
 		discovery.RegisterRPC(grpc)
 		go grpc.Serve(lis)
 
@@ -91,7 +93,7 @@ var discoveryCmd = &cobra.Command{
 			defer cancel()
 			pb.RegisterDiscoveryHandler(netCtx, mux, proxyConn)
 
-			prxy := proxy.WithLogger(mux, ctx)
+			prxy := proxy.WithLogger(proxy.WithSwagger(mux, pb.SwaggerJSON), ctx)
 			prxy = proxy.WithPagination(prxy)
 
 			go func() {
@@ -4,21 +4,29 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
 	pb "github.com/TheThingsNetwork/ttn/api/handler"
 	"github.com/TheThingsNetwork/ttn/core/component"
 	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/core/kek"
 	"github.com/TheThingsNetwork/ttn/core/proxy"
 	"github.com/TheThingsNetwork/ttn/core/proxy/jsonpb"
+	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/parse"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	_ "github.com/lib/pq" // The postgres driver for the TimescaleDB integration
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
@@ -38,6 +46,7 @@ var handlerCmd = &cobra.Command{
 			"Announce":      fmt.Sprintf("%s:%d", viper.GetString("handler.server-address-announce"), viper.GetInt("handler.server-port")),
 			"Database":      fmt.Sprintf("%s/%d", viper.GetString("handler.redis-address"), viper.GetInt("handler.redis-db")),
 			"TTN Broker ID": viper.GetString("handler.broker-id"),
+			"TTN NS ID":     viper.GetString("handler.networkserver-id"),
 			"MQTT":          viper.GetString("handler.mqtt-address"),
 			"AMQP":          viper.GetString("handler.amqp-address"),
 		}).Info("Initializing Handler")
@@ -59,12 +68,83 @@ var handlerCmd = &cobra.Command{
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize component")
 		}
+		watchRedis(component, client)
 
 		httpActive := viper.GetString("handler.http-address") != "" && viper.GetInt("handler.http-port") != 0
 		if httpActive && component.Identity.ApiAddress == "" {
 			component.Identity.ApiAddress = fmt.Sprintf("http://%s:%d", viper.GetString("handler.server-address-announce"), viper.GetInt("handler.http-port"))
 		}
 
+		// Metering
+		quota, err := handler.ParseQuota(viper.GetString("handler.quota"))
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not parse quota")
+		}
+		meteringConfig := handler.MeteringConfig{
+			Interval:  viper.GetDuration("handler.metering-interval"),
+			Directory: viper.GetString("handler.metering-directory"),
+			Format:    viper.GetString("handler.metering-format"),
+			Period:    viper.GetDuration("handler.quota-period"),
+			Quota:     quota,
+		}
+
+		// Coverage
+		coverageConfig := handler.CoverageConfig{
+			CellSize: viper.GetFloat64("handler.coverage-cell-size"),
+			MaxCells: viper.GetInt("handler.coverage-max-cells"),
+		}
+
+		// Metadata privacy
+		metadataPrivacy, err := handler.ParseMetadataPrivacy(viper.GetString("handler.metadata-privacy"))
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not parse metadata privacy")
+		}
+
+		// DevEUI validation
+		devEUIBlocks, err := handler.ParseDevEUIBlocks(viper.GetStringSlice("handler.dev-eui-blocks"))
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not parse DevEUI blocks")
+		}
+		devEUIValidation := handler.DevEUIValidation{
+			Blocks:          devEUIBlocks,
+			UniquePerTenant: viper.GetBool("handler.unique-dev-euis"),
+		}
+
+		// Key service
+		var joinCrypto handler.JoinCrypto
+		if joinCryptoAddress := viper.GetString("handler.join-crypto-address"); joinCryptoAddress != "" {
+			var cert []byte
+			if certFile := viper.GetString("handler.join-crypto-cert"); certFile != "" {
+				cert, err = ioutil.ReadFile(certFile)
+				if err != nil {
+					ctx.WithError(err).Fatal("Could not get key service certificate")
+				}
+			}
+			var conn *grpc.ClientConn
+			if len(cert) == 0 {
+				conn, err = api.Dial(joinCryptoAddress)
+			} else {
+				conn, err = api.DialWithCert(joinCryptoAddress, string(cert))
+			}
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not connect to key service")
+			}
+			joinCrypto = handler.NewRemoteJoinCrypto(conn, viper.GetString("handler.join-crypto-token"))
+		}
+
+		// Kafka
+		kafkaConfig := handler.KafkaConfig{
+			Brokers:       viper.GetStringSlice("handler.kafka-brokers"),
+			UplinkTopic:   viper.GetString("handler.kafka-uplink-topic"),
+			EventTopic:    viper.GetString("handler.kafka-event-topic"),
+			DownlinkTopic: viper.GetString("handler.kafka-downlink-topic"),
+			ConsumerGroup: viper.GetString("handler.kafka-consumer-group"),
+			TLS:           viper.GetBool("handler.kafka-tls"),
+			TLSCA:         viper.GetString("handler.kafka-tls-ca"),
+			SASLUsername:  viper.GetString("handler.kafka-sasl-username"),
+			SASLPassword:  viper.GetString("handler.kafka-sasl-password"),
+		}
+
 		// Handler
 		handler := handler.NewRedisHandler(
 			client,
@@ -109,6 +189,49 @@ var handlerCmd = &cobra.Command{
 		} else {
 			ctx.Warn("AMQP is not enabled in your configuration")
 		}
+		if len(kafkaConfig.Brokers) > 0 {
+			handler = handler.WithKafka(kafkaConfig)
+		}
+		if networkServerID := viper.GetString("handler.networkserver-id"); networkServerID != "" {
+			handler = handler.WithNetworkServer(networkServerID)
+		}
+		if blockString := viper.GetString("handler.dev-eui-block"); blockString != "" {
+			block, err := types.ParseDevEUIBlock(blockString)
+			if err != nil {
+				ctx.WithError(err).WithField("DevEUIBlock", blockString).Fatal("Could not parse DevEUI block")
+			}
+			handler = handler.WithDevEUIBlock(block)
+		}
+		handler = handler.WithDevEUIValidation(devEUIValidation)
+		handler = handler.WithTenants(getTenants())
+		handler = handler.WithMetering(meteringConfig)
+		if coverageConfig.CellSize > 0 {
+			handler = handler.WithCoverage(coverageConfig)
+		}
+		if codecRepository := viper.GetString("handler.codec-repository"); codecRepository != "" {
+			handler = handler.WithCodecRepository(codecRepository)
+		}
+		handler = handler.WithMetadataPrivacy(metadataPrivacy)
+		handler = handler.WithIntegrationHosts(viper.GetStringSlice("handler.integration-hosts"))
+		if secretsKey := viper.GetString("handler.secrets-key"); secretsKey != "" {
+			key, err := hex.DecodeString(secretsKey)
+			if err != nil || (len(key) != 16 && len(key) != 24 && len(key) != 32) {
+				ctx.Fatal("The secrets key must be a hex AES key of 16, 24 or 32 bytes")
+			}
+			handler = handler.WithSecretsKey(key)
+		}
+		kekPartners := make(map[string]string)
+		for _, partner := range viper.GetStringSlice("handler.kek-partners") {
+			parts := strings.SplitN(partner, "=", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				ctx.WithField("Partner", partner).Fatal("Invalid KEK partner")
+			}
+			kekPartners[parts[0]] = parts[1]
+		}
+		handler = handler.WithKEKs(kek.NewRedisStore(client, "handler"), kekPartners)
+		if joinCrypto != nil {
+			handler = handler.WithJoinCrypto(joinCrypto)
+		}
 		err = handler.Init(component)
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize handler")
@@ -124,6 +247,7 @@ var handlerCmd = &cobra.Command{
 
 		// Register and Listen
 		component.RegisterHealthServer(grpc)
+		component.RegisterDebugServer(grpc)
 		handler.RegisterRPC(grpc)
 		handler.RegisterManager(grpc)
 		go grpc.Serve(lis)
@@ -141,7 +265,7 @@ var handlerCmd = &cobra.Command{
 			defer cancel()
 			pb.RegisterApplicationManagerHandler(netCtx, mux, proxyConn)
 
-			prxy := proxy.WithToken(mux)
+			prxy := proxy.WithSwagger(proxy.WithToken(handler.HTTPHandler(mux)), pb.SwaggerJSON)
 			prxy = proxy.WithPagination(prxy)
 			prxy = proxy.WithLogger(prxy, ctx)
 
@@ -174,6 +298,53 @@ func init() {
 	handlerCmd.Flags().String("broker-id", "dev", "The ID of the TTN Broker as announced in the Discovery server")
 	viper.BindPFlag("handler.broker-id", handlerCmd.Flags().Lookup("broker-id"))
 
+	handlerCmd.Flags().String("networkserver-id", "", "The ID of the TTN NetworkServer as announced in the Discovery server, used to erase device data")
+	viper.BindPFlag("handler.networkserver-id", handlerCmd.Flags().Lookup("networkserver-id"))
+
+	handlerCmd.Flags().String("dev-eui-block", "", "The block of DevEUIs for devices that are provisioned by the Handler (70B3D57ED0000000/36)")
+	viper.BindPFlag("handler.dev-eui-block", handlerCmd.Flags().Lookup("dev-eui-block"))
+	handlerCmd.Flags().StringSlice("dev-eui-blocks", []string{}, "The OUIs (70B3D5) and DevEUI blocks (70B3D57ED0000000/36) that the DevEUIs of registered devices must be in (leave empty to allow all DevEUIs)")
+	viper.BindPFlag("handler.dev-eui-blocks", handlerCmd.Flags().Lookup("dev-eui-blocks"))
+	handlerCmd.Flags().Bool("unique-dev-euis", false, "Do not register devices with a DevEUI that is already used in the applications of the same tenant")
+	viper.BindPFlag("handler.unique-dev-euis", handlerCmd.Flags().Lookup("unique-dev-euis"))
+
+	handlerCmd.Flags().Duration("metering-interval", time.Hour, "The interval of the usage records of applications")
+	handlerCmd.Flags().String("metering-directory", "", "The directory where usage records are exported (leave empty to disable)")
+	handlerCmd.Flags().String("metering-format", "csv", "The format of the exported usage records (csv or json)")
+	handlerCmd.Flags().Duration("quota-period", 30*24*time.Hour, "The period of the quotas of applications")
+	handlerCmd.Flags().String("quota", "", "The soft and hard limits per application per period (uplinks=10000/12000,downlinks=100/200,airtime=1h/2h,devices=100/120)")
+	viper.BindPFlag("handler.metering-interval", handlerCmd.Flags().Lookup("metering-interval"))
+	viper.BindPFlag("handler.metering-directory", handlerCmd.Flags().Lookup("metering-directory"))
+	viper.BindPFlag("handler.metering-format", handlerCmd.Flags().Lookup("metering-format"))
+	viper.BindPFlag("handler.quota-period", handlerCmd.Flags().Lookup("quota-period"))
+	viper.BindPFlag("handler.quota", handlerCmd.Flags().Lookup("quota"))
+
+	handlerCmd.Flags().Float64("coverage-cell-size", 0, "The size of the cells of the coverage statistics of applications in degrees (0 to disable, 0.001 is about 100 m)")
+	handlerCmd.Flags().Int("coverage-max-cells", 10000, "The maximum number of coverage cells per application (0 for unlimited)")
+	viper.BindPFlag("handler.coverage-cell-size", handlerCmd.Flags().Lookup("coverage-cell-size"))
+	viper.BindPFlag("handler.coverage-max-cells", handlerCmd.Flags().Lookup("coverage-max-cells"))
+
+	handlerCmd.Flags().String("metadata-privacy", "", "The gateway metadata that is hidden from all applications, which can hide more (hide-gateway-ids,hide-locations,location-decimals=N,hide-timestamps,untrusted-only)")
+	viper.BindPFlag("handler.metadata-privacy", handlerCmd.Flags().Lookup("metadata-privacy"))
+
+	handlerCmd.Flags().StringSlice("integration-hosts", []string{}, "Hosts, domains (.example.com) and networks (10.0.0.0/8) that the integrations of applications can connect to, in addition to public addresses")
+	viper.BindPFlag("handler.integration-hosts", handlerCmd.Flags().Lookup("integration-hosts"))
+	handlerCmd.Flags().String("secrets-key", "", "Hex AES key that encrypts the credentials of MQTT bridges before they are stored (required to store credentials)")
+	viper.BindPFlag("handler.secrets-key", handlerCmd.Flags().Lookup("secrets-key"))
+
+	handlerCmd.Flags().String("codec-repository", "", "The directory with LoRaWAN payload codecs that devices select with their codec attribute (leave empty to disable)")
+	viper.BindPFlag("handler.codec-repository", handlerCmd.Flags().Lookup("codec-repository"))
+
+	handlerCmd.Flags().StringSlice("kek-partners", []string{}, "Broker IDs and KEK labels (broker-id=label) to wrap the session keys that are sent to the NetworkServer behind the Broker")
+	viper.BindPFlag("handler.kek-partners", handlerCmd.Flags().Lookup("kek-partners"))
+
+	handlerCmd.Flags().String("join-crypto-address", "", "The host and port of the key service that holds the AppKeys and performs the join cryptography (leave empty to use the AppKeys of the devices)")
+	viper.BindPFlag("handler.join-crypto-address", handlerCmd.Flags().Lookup("join-crypto-address"))
+	handlerCmd.Flags().String("join-crypto-cert", "", "The certificate of the key service")
+	viper.BindPFlag("handler.join-crypto-cert", handlerCmd.Flags().Lookup("join-crypto-cert"))
+	handlerCmd.Flags().String("join-crypto-token", "", "The token for the key service")
+	viper.BindPFlag("handler.join-crypto-token", handlerCmd.Flags().Lookup("join-crypto-token"))
+
 	handlerCmd.Flags().String("mqtt-address", "", "MQTT host and port. Leave empty to disable MQTT")
 	handlerCmd.Flags().String("mqtt-address-announce", "", "MQTT address to announce (takes value of server-address-announce if empty while enabled)")
 	handlerCmd.Flags().String("mqtt-username", "", "MQTT username")
@@ -194,6 +365,25 @@ func init() {
 	viper.BindPFlag("handler.amqp-password", handlerCmd.Flags().Lookup("amqp-password"))
 	viper.BindPFlag("handler.amqp-exchange", handlerCmd.Flags().Lookup("amqp-exchange"))
 
+	handlerCmd.Flags().StringSlice("kafka-brokers", []string{}, "Kafka brokers (host:port). Leave empty to disable Kafka")
+	handlerCmd.Flags().String("kafka-uplink-topic", "ttn.uplink", "Kafka topic for uplink messages (leave empty to disable)")
+	handlerCmd.Flags().String("kafka-event-topic", "ttn.events", "Kafka topic for events (leave empty to disable)")
+	handlerCmd.Flags().String("kafka-downlink-topic", "ttn.downlink", "Kafka topic that downlink messages are consumed from (leave empty to disable)")
+	handlerCmd.Flags().String("kafka-consumer-group", "", "Kafka consumer group that the downlink offsets are committed to (default ttn-handler-<id>)")
+	handlerCmd.Flags().Bool("kafka-tls", false, "Connect to Kafka with TLS")
+	handlerCmd.Flags().String("kafka-tls-ca", "", "File with the CA certificates of Kafka (leave empty to use the system roots)")
+	handlerCmd.Flags().String("kafka-sasl-username", "", "Kafka SASL/PLAIN username (leave empty to disable SASL)")
+	handlerCmd.Flags().String("kafka-sasl-password", "", "Kafka SASL/PLAIN password")
+	viper.BindPFlag("handler.kafka-brokers", handlerCmd.Flags().Lookup("kafka-brokers"))
+	viper.BindPFlag("handler.kafka-uplink-topic", handlerCmd.Flags().Lookup("kafka-uplink-topic"))
+	viper.BindPFlag("handler.kafka-event-topic", handlerCmd.Flags().Lookup("kafka-event-topic"))
+	viper.BindPFlag("handler.kafka-downlink-topic", handlerCmd.Flags().Lookup("kafka-downlink-topic"))
+	viper.BindPFlag("handler.kafka-consumer-group", handlerCmd.Flags().Lookup("kafka-consumer-group"))
+	viper.BindPFlag("handler.kafka-tls", handlerCmd.Flags().Lookup("kafka-tls"))
+	viper.BindPFlag("handler.kafka-tls-ca", handlerCmd.Flags().Lookup("kafka-tls-ca"))
+	viper.BindPFlag("handler.kafka-sasl-username", handlerCmd.Flags().Lookup("kafka-sasl-username"))
+	viper.BindPFlag("handler.kafka-sasl-password", handlerCmd.Flags().Lookup("kafka-sasl-password"))
+
 	handlerCmd.Flags().String("server-address", "0.0.0.0", "The IP address to listen for communication")
 	handlerCmd.Flags().String("server-address-announce", "localhost", "The public IP address to announce")
 	handlerCmd.Flags().Int("server-port", 1904, "The port for communication")
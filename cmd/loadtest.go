@@ -0,0 +1,145 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/utils/loadtest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// loadtestCmd represents the loadtest command
+var loadtestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Generate uplink traffic against a Router",
+	Long: `ttn loadtest sends uplink messages of devices through simulated gateways to a Router.
+
+If an MQTT address and application are given, the uplink messages of that
+application are used to measure the end-to-end latency and loss. This requires
+that the devices in the devices file are registered in that application.
+
+After the load test, the last FCnt of each device is written to the devices
+file, so that the Network Server does not reject the uplink messages of the
+next load test as replays.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var devices []*loadtest.Device
+		if devicesFile := viper.GetString("loadtest.devices-file"); devicesFile != "" {
+			f, err := os.Open(devicesFile)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not open devices file")
+			}
+			devices, err = loadtest.ReadDevices(f)
+			f.Close()
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not read devices file")
+			}
+		} else {
+			devices = loadtest.RandomDevices(viper.GetInt("loadtest.devices"))
+		}
+		if len(devices) == 0 {
+			ctx.Fatal("No devices to simulate")
+		}
+
+		config := loadtest.Config{
+			Gateways:      viper.GetInt("loadtest.gateways"),
+			GatewayPrefix: viper.GetString("loadtest.gateway-prefix"),
+			GatewayToken:  viper.GetString("loadtest.gateway-token"),
+			Rate:          viper.GetInt("loadtest.rate"),
+			Duration:      viper.GetDuration("loadtest.duration"),
+			Wait:          viper.GetDuration("loadtest.wait"),
+			Frequency:     uint64(viper.GetInt64("loadtest.frequency")),
+			DataRate:      viper.GetString("loadtest.data-rate"),
+			FPort:         1,
+		}
+		if config.Gateways <= 0 || config.Rate <= 0 {
+			ctx.Fatal("The number of gateways and the rate must be positive")
+		}
+		test := loadtest.New(ctx, config, devices)
+
+		if mqttAddress := viper.GetString("loadtest.mqtt-address"); mqttAddress != "" {
+			appID := viper.GetString("loadtest.app-id")
+			client := mqtt.NewClient(ctx, "ttn-loadtest", appID, viper.GetString("loadtest.app-access-key"), fmt.Sprintf("tcp://%s", mqttAddress))
+			if err := client.Connect(); err != nil {
+				ctx.WithError(err).Fatal("Could not connect to MQTT")
+			}
+			defer client.Disconnect()
+			token := client.SubscribeAppUplink(appID, func(_ mqtt.Client, _ string, _ string, msg types.UplinkMessage) {
+				test.HandleUplink(msg.PayloadRaw)
+			})
+			if token.Wait(); token.Error() != nil {
+				ctx.WithError(token.Error()).Fatal("Could not subscribe to uplink messages")
+			}
+		}
+
+		conn, err := api.Dial(viper.GetString("loadtest.router-address"))
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not connect to Router")
+		}
+		defer conn.Close()
+
+		report := test.Run(pb_router.NewRouterClient(conn))
+		ctx.WithFields(ttnlog.Fields{
+			"Sent":     report.Sent,
+			"Received": report.Received,
+		}).Info("Finished load test")
+		fmt.Print(report)
+
+		if devicesFile := viper.GetString("loadtest.devices-file"); devicesFile != "" {
+			f, err := os.Create(devicesFile)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not open devices file")
+			}
+			defer f.Close()
+			if err := loadtest.WriteDevices(f, devices); err != nil {
+				ctx.WithError(err).Fatal("Could not write devices file")
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(loadtestCmd)
+	loadtestCmd.Flags().String("router-address", "localhost:1901", "The address of the Router")
+	viper.BindPFlag("loadtest.router-address", loadtestCmd.Flags().Lookup("router-address"))
+
+	loadtestCmd.Flags().Int("gateways", 10, "The number of simulated gateways")
+	loadtestCmd.Flags().String("gateway-prefix", "loadtest-", "The prefix of the IDs of simulated gateways")
+	loadtestCmd.Flags().String("gateway-token", "", "The access token of simulated gateways")
+	viper.BindPFlag("loadtest.gateways", loadtestCmd.Flags().Lookup("gateways"))
+	viper.BindPFlag("loadtest.gateway-prefix", loadtestCmd.Flags().Lookup("gateway-prefix"))
+	viper.BindPFlag("loadtest.gateway-token", loadtestCmd.Flags().Lookup("gateway-token"))
+
+	loadtestCmd.Flags().Int("devices", 100, "The number of simulated devices with random addresses and keys")
+	loadtestCmd.Flags().String("devices-file", "", "Location of a file with the DevAddr, NwkSKey, AppSKey and FCnt of registered devices")
+	viper.BindPFlag("loadtest.devices", loadtestCmd.Flags().Lookup("devices"))
+	viper.BindPFlag("loadtest.devices-file", loadtestCmd.Flags().Lookup("devices-file"))
+
+	loadtestCmd.Flags().Int("rate", 100, "The number of uplink messages per second")
+	loadtestCmd.Flags().Duration("duration", time.Minute, "The duration of the load test")
+	loadtestCmd.Flags().Duration("wait", 5*time.Second, "The time to wait for uplink messages after the last one was sent")
+	viper.BindPFlag("loadtest.rate", loadtestCmd.Flags().Lookup("rate"))
+	viper.BindPFlag("loadtest.duration", loadtestCmd.Flags().Lookup("duration"))
+	viper.BindPFlag("loadtest.wait", loadtestCmd.Flags().Lookup("wait"))
+
+	loadtestCmd.Flags().Int64("frequency", 868100000, "The frequency (in Hz) of uplink messages")
+	loadtestCmd.Flags().String("data-rate", "SF7BW125", "The data rate of uplink messages")
+	viper.BindPFlag("loadtest.frequency", loadtestCmd.Flags().Lookup("frequency"))
+	viper.BindPFlag("loadtest.data-rate", loadtestCmd.Flags().Lookup("data-rate"))
+
+	loadtestCmd.Flags().String("mqtt-address", "", "The address of the MQTT broker of the Handler (host:port)")
+	loadtestCmd.Flags().String("app-id", "", "The ID of the application of the devices")
+	loadtestCmd.Flags().String("app-access-key", "", "The access key of the application of the devices")
+	viper.BindPFlag("loadtest.mqtt-address", loadtestCmd.Flags().Lookup("mqtt-address"))
+	viper.BindPFlag("loadtest.app-id", loadtestCmd.Flags().Lookup("app-id"))
+	viper.BindPFlag("loadtest.app-access-key", loadtestCmd.Flags().Lookup("app-access-key"))
+}
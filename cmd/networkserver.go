@@ -6,6 +6,7 @@ package cmd
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -13,7 +14,9 @@ import (
 
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/kek"
 	"github.com/TheThingsNetwork/ttn/core/networkserver"
+	"github.com/TheThingsNetwork/ttn/core/proxy"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -50,8 +53,25 @@ var networkserverCmd = &cobra.Command{
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize component")
 		}
+		watchRedis(component, client)
+
+		httpActive := viper.GetString("networkserver.http-address") != "" && viper.GetInt("networkserver.http-port") != 0
+		if httpActive && component.Identity.ApiAddress == "" {
+			component.Identity.ApiAddress = fmt.Sprintf("http://%s:%d", viper.GetString("networkserver.server-address-announce"), viper.GetInt("networkserver.http-port"))
+		}
+
+		fairAccessPolicy := networkserver.FairAccessPolicy{
+			UplinkAirtime: viper.GetDuration("networkserver.fair-access-uplink-airtime"),
+			Downlinks:     viper.GetInt("networkserver.fair-access-downlinks"),
+		}
+
+		frameHistoryLimits := networkserver.FrameHistoryLimits{
+			MaxDepth: viper.GetInt("networkserver.frame-history-max-depth"),
+			MaxTTL:   viper.GetDuration("networkserver.frame-history-max-ttl"),
+		}
 
 		// networkserver Server
+		nsSwagger := networkserver.SwaggerJSON
 		networkserver := networkserver.NewRedisNetworkServer(client, viper.GetInt("networkserver.net-id"))
 
 		// Register Prefixes
@@ -69,6 +89,29 @@ var networkserverCmd = &cobra.Command{
 			ctx.Infof("Using DevAddr prefix %s (%v)", prefix, usage)
 		}
 
+		// Tenants
+		if err := networkserver.UseTenants(getTenants()); err != nil {
+			ctx.WithError(err).Fatal("Could not use DevAddr blocks of tenants")
+		}
+
+		// Key encryption keys
+		networkserver.UseKEKs(kek.NewRedisStore(client, "ns"))
+
+		// Fair Access Policy
+		if fairAccessPolicy.UplinkAirtime > 0 || fairAccessPolicy.Downlinks > 0 {
+			networkserver.UseFairAccessPolicy(fairAccessPolicy)
+			ctx.WithField("UplinkAirtime", fairAccessPolicy.UplinkAirtime).WithField("Downlinks", fairAccessPolicy.Downlinks).Info("Using fair access policy")
+		}
+
+		// Frame History
+		networkserver.UseFrameHistoryLimits(frameHistoryLimits)
+
+		// MAC Downlinks
+		if interval := viper.GetDuration("networkserver.mac-downlink-interval"); interval > 0 {
+			networkserver.UseMACDownlinkInterval(interval)
+			ctx.WithField("Interval", interval).Info("Scheduling downlinks for pending MAC commands")
+		}
+
 		err = networkserver.Init(component)
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize networkserver")
@@ -83,10 +126,23 @@ var networkserverCmd = &cobra.Command{
 
 		// Register and Listen
 		component.RegisterHealthServer(grpc)
+		component.RegisterDebugServer(grpc)
 		networkserver.RegisterRPC(grpc)
 		networkserver.RegisterManager(grpc)
 		go grpc.Serve(lis)
 
+		if httpActive {
+			go func() {
+				err := http.ListenAndServe(
+					fmt.Sprintf("%s:%d", viper.GetString("networkserver.http-address"), viper.GetInt("networkserver.http-port")),
+					proxy.WithLogger(proxy.WithSwagger(networkserver.HTTPHandler(), nsSwagger), ctx),
+				)
+				if err != nil {
+					ctx.WithError(err).Fatal("Error in HTTP server")
+				}
+			}()
+		}
+
 		sigChan := make(chan os.Signal)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		ctx.WithField("signal", <-sigChan).Info("signal received")
@@ -117,4 +173,22 @@ func init() {
 	viper.BindPFlag("networkserver.server-address", networkserverCmd.Flags().Lookup("server-address"))
 	viper.BindPFlag("networkserver.server-address-announce", networkserverCmd.Flags().Lookup("server-address-announce"))
 	viper.BindPFlag("networkserver.server-port", networkserverCmd.Flags().Lookup("server-port"))
+
+	networkserverCmd.Flags().Duration("fair-access-uplink-airtime", 0, "The maximum uplink airtime per device in 24 hours before downlinks are deferred (0 to disable)")
+	networkserverCmd.Flags().Int("fair-access-downlinks", 0, "The maximum number of downlinks per device in 24 hours (0 to disable)")
+	viper.BindPFlag("networkserver.fair-access-uplink-airtime", networkserverCmd.Flags().Lookup("fair-access-uplink-airtime"))
+	viper.BindPFlag("networkserver.fair-access-downlinks", networkserverCmd.Flags().Lookup("fair-access-downlinks"))
+
+	networkserverCmd.Flags().Int("frame-history-max-depth", networkserver.DefaultFrameHistoryLimits.MaxDepth, "The maximum number of frames that devices can keep in their frame history")
+	networkserverCmd.Flags().Duration("frame-history-max-ttl", networkserver.DefaultFrameHistoryLimits.MaxTTL, "The maximum time that frames are kept in the frame history of devices (0 for no limit)")
+	viper.BindPFlag("networkserver.frame-history-max-depth", networkserverCmd.Flags().Lookup("frame-history-max-depth"))
+	viper.BindPFlag("networkserver.frame-history-max-ttl", networkserverCmd.Flags().Lookup("frame-history-max-ttl"))
+
+	networkserverCmd.Flags().Duration("mac-downlink-interval", 0, "The minimum time between downlinks that only contain pending MAC commands (0 to wait for application downlinks)")
+	viper.BindPFlag("networkserver.mac-downlink-interval", networkserverCmd.Flags().Lookup("mac-downlink-interval"))
+
+	networkserverCmd.Flags().String("http-address", "0.0.0.0", "The IP address where the HTTP API should listen")
+	networkserverCmd.Flags().Int("http-port", 0, "The port where the HTTP API should listen (0 to disable)")
+	viper.BindPFlag("networkserver.http-address", networkserverCmd.Flags().Lookup("http-address"))
+	viper.BindPFlag("networkserver.http-port", networkserverCmd.Flags().Lookup("http-port"))
 }
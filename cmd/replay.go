@@ -0,0 +1,85 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/utils/replay"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay [file]",
+	Short: "Replay captured packet forwarder traffic against a Router",
+	Long: `ttn replay sends the uplink messages that were captured from packet forwarders to a Router.
+
+The file is a pcap capture of the Semtech UDP protocol, or a file with JSON
+lines that contain the time, the gateway_eui or gateway_id and the rxpk array
+of a PUSH_DATA packet:
+
+  {"time":"2017-06-01T12:00:00Z","gateway_eui":"0102030405060708","rxpk":[...]}
+
+The packets are replayed with the delays between them divided by the speed.
+With speed 0 they are replayed as fast as possible.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.UsageFunc()(cmd)
+			return
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not open file")
+		}
+		packets, err := replay.Read(f, uint16(viper.GetInt("replay.port")))
+		f.Close()
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not read packets")
+		}
+		if len(packets) == 0 {
+			ctx.Fatal("No packets to replay")
+		}
+
+		conn, err := api.Dial(viper.GetString("replay.router-address"))
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not connect to Router")
+		}
+		defer conn.Close()
+
+		streams := replay.NewRouterStreams(pb_router.NewRouterClient(conn), viper.GetString("replay.gateway-token"))
+		defer streams.Close()
+
+		speed := viper.GetFloat64("replay.speed")
+		ctx.WithFields(ttnlog.Fields{
+			"Packets": len(packets),
+			"Speed":   speed,
+		}).Info("Starting replay")
+		report := replay.Replay(ctx, packets, speed, streams.Send)
+		ctx.WithFields(ttnlog.Fields{
+			"Sent":   report.Sent,
+			"Errors": report.SendErrors,
+		}).Info("Finished replay")
+		fmt.Print(report)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(replayCmd)
+	replayCmd.Flags().String("router-address", "localhost:1901", "The address of the Router")
+	replayCmd.Flags().String("gateway-token", "", "The access token of the gateways")
+	viper.BindPFlag("replay.router-address", replayCmd.Flags().Lookup("router-address"))
+	viper.BindPFlag("replay.gateway-token", replayCmd.Flags().Lookup("gateway-token"))
+
+	replayCmd.Flags().Float64("speed", 1, "The speed of the replay relative to the capture (0 for as fast as possible)")
+	replayCmd.Flags().Int("port", replay.DefaultPort, "The UDP port of the packet forwarder traffic in pcap files")
+	viper.BindPFlag("replay.speed", replayCmd.Flags().Lookup("speed"))
+	viper.BindPFlag("replay.port", replayCmd.Flags().Lookup("port"))
+}
@@ -17,10 +17,17 @@ import (
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	"github.com/TheThingsNetwork/go-utils/log/apex"
 	"github.com/TheThingsNetwork/go-utils/log/grpc"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/alerting"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/backoff"
 	esHandler "github.com/TheThingsNetwork/ttn/utils/elasticsearch/handler"
+	"github.com/TheThingsNetwork/ttn/utils/fault"
+	"github.com/TheThingsNetwork/ttn/utils/logging"
 	"github.com/apex/log"
 	jsonHandler "github.com/apex/log/handlers/json"
-	levelHandler "github.com/apex/log/handlers/level"
 	multiHandler "github.com/apex/log/handlers/multi"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
@@ -42,15 +49,29 @@ var RootCmd = &cobra.Command{
 	Short: "The Things Network's backend servers",
 	Long:  `ttn launches The Things Network's backend servers`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		var logLevel = log.InfoLevel
+		logConfig := logging.Config{Level: "info", Sampling: viper.GetInt("log-sampling")}
 		if viper.GetBool("debug") {
-			logLevel = log.DebugLevel
+			logConfig.Level = "debug"
 		}
+		logLevels, err := logging.ParseLevels(viper.GetStringSlice("log-levels"))
+		if err != nil {
+			panic(err)
+		}
+		logConfig.Levels = logLevels
 
 		var logHandlers []log.Handler
 
 		if !viper.GetBool("no-cli-logs") {
-			logHandlers = append(logHandlers, levelHandler.New(cliHandler.New(os.Stdout), logLevel))
+			switch format := viper.GetString("log-format"); format {
+			case "cli":
+				logHandlers = append(logHandlers, cliHandler.New(os.Stdout))
+			case "json":
+				logHandlers = append(logHandlers, jsonHandler.New(os.Stdout))
+			case "logfmt":
+				logHandlers = append(logHandlers, logging.NewLogfmtHandler(os.Stdout))
+			default:
+				panic(fmt.Errorf("Invalid log format %s, must be cli, json or logfmt", format))
+			}
 		}
 
 		if logFileLocation := viper.GetString("log-file"); logFileLocation != "" {
@@ -63,7 +84,7 @@ var RootCmd = &cobra.Command{
 				panic(err)
 			}
 			if err == nil {
-				logHandlers = append(logHandlers, levelHandler.New(jsonHandler.New(logFile), logLevel))
+				logHandlers = append(logHandlers, jsonHandler.New(logFile))
 			}
 		}
 
@@ -72,20 +93,41 @@ var RootCmd = &cobra.Command{
 			esClient.HTTPClient = &http.Client{
 				Timeout: 5 * time.Second,
 			}
-			logHandlers = append(logHandlers, levelHandler.New(esHandler.New(&esHandler.Config{
+			logHandlers = append(logHandlers, esHandler.New(&esHandler.Config{
 				Client:     esClient,
 				Prefix:     cmd.Name(),
 				BufferSize: 10,
-			}), logLevel))
+			}))
+		}
+
+		logHandler, err := logging.NewHandler(multiHandler.New(logHandlers...), logConfig)
+		if err != nil {
+			panic(err)
 		}
+		// The log configuration can be changed at runtime on the admin API of the debug server
+		logging.DefaultHandler = logHandler
 
-		// Set the API/gRPC logger
+		// Set the API/gRPC logger. The tail receives all entries, so that the
+		// debug logs of a single device can be streamed with the TailLogs RPC.
 		ctx = apex.Wrap(&log.Logger{
-			Handler: multiHandler.New(logHandlers...),
+			Handler: multiHandler.New(logHandler, logging.DefaultTail),
 		})
 		ttnlog.Set(ctx)
 		grpclog.SetLogger(grpc.Wrap(ttnlog.Get()))
 
+		api.KeepAlive = viper.GetDuration("grpc-keepalive")
+		backoff.DefaultConfig.BaseDelay = viper.GetDuration("stream-backoff-base")
+		backoff.DefaultConfig.MaxDelay = viper.GetDuration("stream-backoff-max")
+		backoff.DefaultConfig.Jitter = viper.GetFloat64("stream-backoff-jitter")
+
+		if bandDefinitions := viper.GetString("band-definitions"); bandDefinitions != "" {
+			regions, err := band.LoadDefinitions(bandDefinitions)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not load band definitions")
+			}
+			ctx.WithField("Regions", regions).Info("Loaded band definitions")
+		}
+
 		ctx.WithFields(ttnlog.Fields{
 			"ComponentID":              viper.GetString("id"),
 			"Description":              viper.GetString("description"),
@@ -126,6 +168,9 @@ func init() {
 	RootCmd.PersistentFlags().Bool("no-cli-logs", false, "Disable CLI logs")
 	RootCmd.PersistentFlags().String("log-file", "", "Location of the log file")
 	RootCmd.PersistentFlags().String("elasticsearch", "", "Location of Elasticsearch server for logging")
+	RootCmd.PersistentFlags().String("log-format", "cli", "Format of the CLI logs (cli, json or logfmt)")
+	RootCmd.PersistentFlags().StringSlice("log-levels", []string{}, "Log levels of components (component=level)")
+	RootCmd.PersistentFlags().Int("log-sampling", 0, "Log the first N debug messages of each kind per second, and every Nth after that (0 to disable)")
 
 	RootCmd.PersistentFlags().String("id", "", "The id of this component")
 	RootCmd.PersistentFlags().String("description", "", "The description of this component")
@@ -135,6 +180,14 @@ func init() {
 	RootCmd.PersistentFlags().String("auth-token", "", "The JWT token to be used for the discovery server")
 
 	RootCmd.PersistentFlags().Int("health-port", 0, "The port number where the health server should be started")
+	RootCmd.PersistentFlags().Int("debug-port", 0, "The port number where the debug server (pprof, config, streams, log configuration, KEK management) should be started")
+	RootCmd.PersistentFlags().String("debug-token", "", "The admin token for the debug server and gRPC reflection")
+
+	RootCmd.PersistentFlags().StringSlice("alert-webhooks", []string{}, "URLs that alerts are posted to")
+	RootCmd.PersistentFlags().StringSlice("alert-emails", []string{}, "Email addresses that alerts are sent to")
+	RootCmd.PersistentFlags().String("alert-smtp-server", "localhost:25", "SMTP server (host:port) that sends alert emails")
+	RootCmd.PersistentFlags().String("alert-smtp-from", "", "Sender address of alert emails")
+	RootCmd.PersistentFlags().Duration("alert-redis-latency", 0, "Redis latency above which an alert is sent (0 to disable)")
 
 	viper.SetDefault("auth-servers", map[string]string{
 		"ttn-account-v2": "https://account.thethingsnetwork.org",
@@ -151,6 +204,14 @@ func init() {
 		}
 	}
 
+	RootCmd.PersistentFlags().String("band-definitions", "", "Location of a file with additional band definitions")
+
+	RootCmd.PersistentFlags().Duration("grpc-keepalive", api.KeepAlive, "The keep-alive period of connections to other components")
+	RootCmd.PersistentFlags().Int("grpc-max-concurrent-streams", 0, "The maximum number of concurrent streams per connection to the gRPC server (0 for no limit)")
+	RootCmd.PersistentFlags().Duration("stream-backoff-base", backoff.DefaultConfig.BaseDelay, "The delay before re-establishing a stream to another component")
+	RootCmd.PersistentFlags().Duration("stream-backoff-max", backoff.DefaultConfig.MaxDelay, "The maximum delay before re-establishing a stream to another component")
+	RootCmd.PersistentFlags().Float64("stream-backoff-jitter", backoff.DefaultConfig.Jitter, "The fraction by which the stream re-establishment delay is randomized")
+
 	RootCmd.PersistentFlags().Bool("tls", false, "Use TLS")
 	RootCmd.PersistentFlags().String("key-dir", path.Clean(dir+"/.ttn/"), "The directory where public/private keys are stored")
 
@@ -189,6 +250,7 @@ var RedisConnectRetries = 10
 var RedisConnectRetryDelay = 1 * time.Second
 
 func connectRedis(client *redis.Client) error {
+	fault.WrapRedis(client)
 	var err error
 	for retries := 0; retries < RedisConnectRetries; retries++ {
 		_, err = client.Ping().Result()
@@ -204,3 +266,25 @@ func connectRedis(client *redis.Client) error {
 	}
 	return nil
 }
+
+// watchRedis reports the health of Redis on the health server, and alerts the
+// operators when the latency of Redis exceeds the configured threshold
+func watchRedis(c *component.Component, client *redis.Client) {
+	go c.WatchDependency("redis", func() error { return client.Ping().Err() })
+	if threshold := viper.GetDuration("alert-redis-latency"); threshold > 0 {
+		go c.Alerts.WatchLatency(alerting.RedisLatency, "Redis", func() error { return client.Ping().Err() }, threshold)
+	}
+}
+
+// getTenants returns the tenants in the config file, which are shared by the
+// components of a deployment that serves multiple customers
+func getTenants() types.Tenants {
+	tenants, err := types.ParseTenants(viper.GetStringMapString("tenants"))
+	if err != nil {
+		ctx.WithError(err).Fatal("Could not parse tenants")
+	}
+	for _, tenant := range tenants {
+		ctx.WithField("Tenant", tenant.ID).Info("Serving tenant")
+	}
+	return tenants
+}
@@ -6,16 +6,25 @@ package cmd
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/proxy"
 	"github.com/TheThingsNetwork/ttn/core/router"
+	"github.com/TheThingsNetwork/ttn/core/router/frequencyplan"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/router/registry"
+	"github.com/TheThingsNetwork/ttn/core/router/spool"
+	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
+	"gopkg.in/redis.v5"
 )
 
 // routerCmd represents the router command
@@ -38,8 +47,87 @@ var routerCmd = &cobra.Command{
 			ctx.WithError(err).Fatal("Could not initialize component")
 		}
 
+		// Traffic filter
+		var devAddrPrefixes []types.DevAddrPrefix
+		for _, prefixString := range viper.GetStringSlice("router.dev-addr-prefixes") {
+			prefix, err := types.ParseDevAddrPrefix(prefixString)
+			if err != nil {
+				ctx.WithError(err).WithField("Prefix", prefixString).Fatal("Invalid DevAddr prefix")
+			}
+			devAddrPrefixes = append(devAddrPrefixes, prefix)
+		}
+		var joinEUIRanges []router.JoinEUIRange
+		for _, rangeString := range viper.GetStringSlice("router.join-eui-ranges") {
+			joinEUIRange, err := router.ParseJoinEUIRange(rangeString)
+			if err != nil {
+				ctx.WithError(err).WithField("Range", rangeString).Fatal("Invalid JoinEUI range")
+			}
+			joinEUIRanges = append(joinEUIRanges, joinEUIRange)
+		}
+		var joinRequestRules []router.JoinRequestRule
+		for _, ruleString := range viper.GetStringSlice("router.join-request-rules") {
+			rule, err := router.ParseJoinRequestRule(ruleString)
+			if err != nil {
+				ctx.WithError(err).WithField("Rule", ruleString).Fatal("Invalid join request rule")
+			}
+			joinRequestRules = append(joinRequestRules, rule)
+		}
+
+		// Downlink airtime budgets
+		downlinkBudgets := router.DownlinkAirtimeBudgets{
+			Default:      viper.GetDuration("router.downlink-airtime-budget"),
+			Applications: make(map[string]time.Duration),
+		}
+		for _, budgetString := range viper.GetStringSlice("router.downlink-airtime-budgets") {
+			appID, budget, err := router.ParseDownlinkAirtimeBudget(budgetString)
+			if err != nil {
+				ctx.WithError(err).WithField("Budget", budgetString).Fatal("Invalid downlink airtime budget")
+			}
+			downlinkBudgets.Applications[appID] = budget
+		}
+
 		// Router
 		router := router.NewRouter()
+		if frequencyPlans := viper.GetString("router.frequency-plans"); frequencyPlans != "" {
+			if err := frequencyplan.LoadFile(router.FrequencyPlans(), frequencyPlans); err != nil {
+				ctx.WithError(err).Fatal("Could not load frequency plans")
+			}
+		}
+		for _, gatewayID := range viper.GetStringSlice("router.trusted-gateways") {
+			router.SetGatewayTrust(gatewayID, gateway.TrustTrusted)
+		}
+		for _, gatewayID := range viper.GetStringSlice("router.untrusted-gateways") {
+			router.SetGatewayTrust(gatewayID, gateway.TrustUntrusted)
+		}
+		gateway.MovementRadius = viper.GetFloat64("router.gateway-movement-radius")
+		router.SetTrafficFilter(devAddrPrefixes, joinEUIRanges)
+		router.SetJoinRequestRules(joinRequestRules)
+		router.SetGatewayOfflineAlert(viper.GetDuration("router.alert-gateway-offline"))
+		router.SetRXWindowMargin(viper.GetDuration("router.rx-window-margin"))
+		if downlinkBudgets.Default > 0 || len(downlinkBudgets.Applications) > 0 {
+			router.SetDownlinkAirtimeBudgets(downlinkBudgets)
+		}
+		if spoolDir := viper.GetString("router.spool-dir"); spoolDir != "" {
+			s, err := spool.New(spoolDir, viper.GetInt64("router.spool-max-size"), viper.GetDuration("router.spool-max-age"))
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not open uplink spool")
+			}
+			router.SetSpool(s)
+		}
+		if address := viper.GetString("router.gateway-registry-redis-address"); address != "" {
+			client := redis.NewClient(&redis.Options{
+				Addr: address,
+				DB:   viper.GetInt("router.gateway-registry-redis-db"),
+			})
+			if err := connectRedis(client); err != nil {
+				ctx.WithError(err).Fatal("Could not connect to Redis of the gateway registry")
+			}
+			router.SetGatewayRegistry(registry.NewRedisStore(client, "router"))
+			ctx.Info("Accepting access keys of the local gateway registry")
+		}
+		if viper.GetBool("router.beacons") {
+			router.EnableBeacons()
+		}
 		err = router.Init(component)
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not initialize router")
@@ -54,10 +142,23 @@ var routerCmd = &cobra.Command{
 
 		// Register and Listen
 		component.RegisterHealthServer(grpc)
+		component.RegisterDebugServer(grpc)
 		router.RegisterRPC(grpc)
 		router.RegisterManager(grpc)
 		go grpc.Serve(lis)
 
+		if viper.GetString("router.http-address") != "" && viper.GetInt("router.http-port") != 0 {
+			go func() {
+				err := http.ListenAndServe(
+					fmt.Sprintf("%s:%d", viper.GetString("router.http-address"), viper.GetInt("router.http-port")),
+					proxy.WithLogger(router.HTTPHandler(), ctx),
+				)
+				if err != nil {
+					ctx.WithError(err).Fatal("Error in HTTP server")
+				}
+			}()
+		}
+
 		sigChan := make(chan os.Signal)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		ctx.WithField("signal", <-sigChan).Info("signal received")
@@ -77,4 +178,53 @@ func init() {
 	viper.BindPFlag("router.server-address-announce", routerCmd.Flags().Lookup("server-address-announce"))
 	viper.BindPFlag("router.server-port", routerCmd.Flags().Lookup("server-port"))
 	viper.BindPFlag("router.skip-verify-gateway-token", routerCmd.Flags().Lookup("skip-verify-gateway-token"))
+
+	routerCmd.Flags().Float64("gateway-movement-radius", 1000, "The distance in meters that a gateway can move before an alert is emitted (0 to disable)")
+	viper.BindPFlag("router.gateway-movement-radius", routerCmd.Flags().Lookup("gateway-movement-radius"))
+
+	routerCmd.Flags().StringSlice("trusted-gateways", []string{}, "IDs of gateways that are trusted, even if they do not authenticate")
+	viper.BindPFlag("router.trusted-gateways", routerCmd.Flags().Lookup("trusted-gateways"))
+	routerCmd.Flags().StringSlice("untrusted-gateways", []string{}, "IDs of gateways that are not trusted, even if they authenticate")
+	viper.BindPFlag("router.untrusted-gateways", routerCmd.Flags().Lookup("untrusted-gateways"))
+
+	routerCmd.Flags().StringSlice("dev-addr-prefixes", []string{}, "DevAddr prefixes that are served by this router; uplink of other DevAddrs is dropped")
+	viper.BindPFlag("router.dev-addr-prefixes", routerCmd.Flags().Lookup("dev-addr-prefixes"))
+	routerCmd.Flags().StringSlice("join-eui-ranges", []string{}, "JoinEUI ranges (start-end or prefix) that are served by this router; other join requests are dropped")
+	viper.BindPFlag("router.join-eui-ranges", routerCmd.Flags().Lookup("join-eui-ranges"))
+	routerCmd.Flags().StringSlice("join-request-rules", []string{}, "Rules (allow|deny join-eui|dev-eui EUI, OUI, prefix or range) that drop join requests of unknown vendors")
+	viper.BindPFlag("router.join-request-rules", routerCmd.Flags().Lookup("join-request-rules"))
+
+	routerCmd.Flags().Duration("alert-gateway-offline", 0, "Duration after which an alert is sent for a gateway that was not seen (0 to disable)")
+	viper.BindPFlag("router.alert-gateway-offline", routerCmd.Flags().Lookup("alert-gateway-offline"))
+
+	routerCmd.Flags().Bool("beacons", false, "Schedule Class B beacons on GPS-synchronized gateways")
+	viper.BindPFlag("router.beacons", routerCmd.Flags().Lookup("beacons"))
+
+	routerCmd.Flags().Duration("rx-window-margin", 500*time.Millisecond, "Time needed to schedule a downlink in addition to the round-trip time of the gateway backhaul; earlier receive windows are skipped")
+	viper.BindPFlag("router.rx-window-margin", routerCmd.Flags().Lookup("rx-window-margin"))
+
+	routerCmd.Flags().Duration("downlink-airtime-budget", 0, "The downlink airtime that each application can use on a gateway per day (0 for no limit)")
+	routerCmd.Flags().StringSlice("downlink-airtime-budgets", []string{}, "Downlink airtime budgets (app-id=duration) of applications that have a different budget")
+	viper.BindPFlag("router.downlink-airtime-budget", routerCmd.Flags().Lookup("downlink-airtime-budget"))
+	viper.BindPFlag("router.downlink-airtime-budgets", routerCmd.Flags().Lookup("downlink-airtime-budgets"))
+
+	routerCmd.Flags().String("spool-dir", "", "Directory where uplink messages are stored while a broker is unreachable (empty to disable)")
+	routerCmd.Flags().Int64("spool-max-size", 64*1024*1024, "Maximum size in bytes of the spooled uplink messages per broker")
+	routerCmd.Flags().Duration("spool-max-age", time.Hour, "Maximum age of spooled uplink messages")
+	viper.BindPFlag("router.spool-dir", routerCmd.Flags().Lookup("spool-dir"))
+	viper.BindPFlag("router.spool-max-size", routerCmd.Flags().Lookup("spool-max-size"))
+	viper.BindPFlag("router.spool-max-age", routerCmd.Flags().Lookup("spool-max-age"))
+
+	routerCmd.Flags().String("gateway-registry-redis-address", "", "Redis server and port of the local gateway registry, to authenticate gateways with access keys (empty to disable)")
+	routerCmd.Flags().Int("gateway-registry-redis-db", 0, "Redis database of the local gateway registry")
+	viper.BindPFlag("router.gateway-registry-redis-address", routerCmd.Flags().Lookup("gateway-registry-redis-address"))
+	viper.BindPFlag("router.gateway-registry-redis-db", routerCmd.Flags().Lookup("gateway-registry-redis-db"))
+
+	routerCmd.Flags().String("frequency-plans", "", "Location of a file with frequency plans and gateway assignments")
+	viper.BindPFlag("router.frequency-plans", routerCmd.Flags().Lookup("frequency-plans"))
+
+	routerCmd.Flags().String("http-address", "0.0.0.0", "The IP address where the HTTP API should listen")
+	routerCmd.Flags().Int("http-port", 0, "The port where the HTTP API should listen (0 to disable)")
+	viper.BindPFlag("router.http-address", routerCmd.Flags().Lookup("http-address"))
+	viper.BindPFlag("router.http-port", routerCmd.Flags().Lookup("http-port"))
 }
@@ -0,0 +1,245 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/claims"
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/broker"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/discovery"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/core/networkserver"
+	"github.com/TheThingsNetwork/ttn/core/router"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/security"
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+)
+
+// standaloneIssuer is the auth server ID that is used for the tokens that are
+// generated for the components of a standalone network
+const standaloneIssuer = "local"
+
+// standaloneCmd represents the standalone command
+var standaloneCmd = &cobra.Command{
+	Use:   "standalone",
+	Short: "Run all components of The Things Network in a single process",
+	Long: `ttn standalone runs the discovery, router, broker, networkserver and handler in a single process.
+
+All state is kept in memory and is lost when the process exits. This is intended
+for local development and integration tests.
+
+The standalone network does not include an MQTT broker. Set --mqtt-address to
+the address of an external broker, such as the rabbitmq service in docker-compose.yml,
+to enable MQTT for the Handler.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		ctx.WithFields(ttnlog.Fields{
+			"Server":        viper.GetString("standalone.server-address"),
+			"Announce":      viper.GetString("standalone.server-address-announce"),
+			"Discovery":     viper.GetInt("standalone.discovery-port"),
+			"Router":        viper.GetInt("standalone.router-port"),
+			"Broker":        viper.GetInt("standalone.broker-port"),
+			"NetworkServer": viper.GetInt("standalone.networkserver-port"),
+			"Handler":       viper.GetInt("standalone.handler-port"),
+			"MQTT":          viper.GetString("standalone.mqtt-address"),
+		}).Info("Initializing Standalone Network")
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx.Info("Starting")
+
+		keyDir := viper.GetString("key-dir")
+		privKey, err := security.LoadKeypair(keyDir)
+		if err != nil {
+			ctx.WithField("KeyDir", keyDir).Info("Generating keypair")
+			if err := os.MkdirAll(keyDir, 0755); err != nil {
+				ctx.WithError(err).Fatal("Could not create key directory")
+			}
+			if err := security.GenerateKeypair(keyDir); err != nil {
+				ctx.WithError(err).Fatal("Could not generate keypair")
+			}
+			if privKey, err = security.LoadKeypair(keyDir); err != nil {
+				ctx.WithError(err).Fatal("Could not load security keys")
+			}
+		}
+
+		id := viper.GetString("id")
+		if id == "" {
+			id = "dev"
+			viper.Set("id", id)
+		}
+
+		serverAddress := viper.GetString("standalone.server-address")
+		announceAddress := viper.GetString("standalone.server-address-announce")
+		address := func(port int) string { return fmt.Sprintf("%s:%d", announceAddress, port) }
+
+		// All components share the same keypair, which is also used as auth server
+		authServers := viper.GetStringMapString("auth-servers")
+		authServers[standaloneIssuer] = "file://" + filepath.Join(keyDir, "server.pub")
+		viper.Set("auth-servers", authServers)
+		viper.Set("discovery-address", address(viper.GetInt("standalone.discovery-port")))
+		viper.Set("tls", false)
+		viper.Set("health-port", 0) // The health server can not be shared between components
+
+		var servers []*grpc.Server
+		var components []component.Interface
+
+		start := func(serviceName string, port int, impl component.Interface) *component.Component {
+			c, err := component.New(ctx.WithField("Component", serviceName), serviceName, address(port))
+			if err != nil {
+				ctx.WithError(err).Fatalf("Could not initialize %s component", serviceName)
+			}
+			c.AccessToken, err = buildStandaloneToken(privKey, serviceName, id)
+			if err != nil {
+				ctx.WithError(err).Fatalf("Could not build token for %s", serviceName)
+			}
+			if err := impl.Init(c); err != nil {
+				ctx.WithError(err).Fatalf("Could not initialize %s", serviceName)
+			}
+			lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", serverAddress, port))
+			if err != nil {
+				ctx.WithError(err).Fatalf("Could not start gRPC server for %s", serviceName)
+			}
+			srv := grpc.NewServer(c.ServerOptions()...)
+			c.RegisterHealthServer(srv)
+			c.RegisterDebugServer(srv)
+			impl.RegisterRPC(srv)
+			if manager, ok := impl.(component.ManagementInterface); ok {
+				manager.RegisterManager(srv)
+			}
+			go srv.Serve(lis)
+			servers = append(servers, srv)
+			components = append(components, impl)
+			return c
+		}
+
+		// Discovery
+		dsc := discovery.NewMemoryDiscovery()
+		dsc.WithMasterAuthServers(standaloneIssuer)
+		start("discovery", viper.GetInt("standalone.discovery-port"), dsc)
+
+		// Network Server
+		ns := networkserver.NewMemoryNetworkServer(viper.GetInt("networkserver.net-id"))
+		for prefix, usage := range viper.GetStringMapString("networkserver.prefixes") {
+			prefix, err := types.ParseDevAddrPrefix(prefix)
+			if err != nil {
+				ctx.WithError(err).Warn("Could not use DevAddr Prefix. Skipping.")
+				continue
+			}
+			if err := ns.UsePrefix(prefix, strings.Split(usage, ",")); err != nil {
+				ctx.WithError(err).Fatal("Could not initialize networkserver")
+			}
+		}
+		tenants := getTenants()
+		if err := ns.UseTenants(tenants); err != nil {
+			ctx.WithError(err).Fatal("Could not use DevAddr blocks of tenants")
+		}
+		start("networkserver", viper.GetInt("standalone.networkserver-port"), ns)
+
+		// Broker
+		nsPrivPEM, err := security.PrivatePEM(privKey)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not convert private key")
+		}
+		nsToken, err := security.BuildJWT(id, 0, nsPrivPEM)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not build networkserver token")
+		}
+		brk := broker.NewBroker(time.Duration(viper.GetInt("broker.deduplication-delay")) * time.Millisecond)
+		brk.SetNetworkServer(address(viper.GetInt("standalone.networkserver-port")), "", nsToken)
+		brk.SetTenants(tenants)
+		start("broker", viper.GetInt("standalone.broker-port"), brk)
+		for _, prefix := range ns.GetPrefixesFor() {
+			err := dsc.AddMetadata("broker", id, &pb_discovery.Metadata{Metadata: &pb_discovery.Metadata_DevAddrPrefix{
+				DevAddrPrefix: prefix.Bytes(),
+			}})
+			if err != nil {
+				ctx.WithError(err).WithField("Prefix", prefix).Fatal("Could not register prefix")
+			}
+		}
+
+		// Router
+		start("router", viper.GetInt("standalone.router-port"), router.NewRouter())
+
+		// Handler
+		hdl := handler.NewMemoryHandler(id)
+		if mqttAddress := viper.GetString("standalone.mqtt-address"); mqttAddress != "" {
+			hdl = hdl.WithMQTT(
+				viper.GetString("standalone.mqtt-username"),
+				viper.GetString("standalone.mqtt-password"),
+				mqttAddress,
+			)
+		} else {
+			ctx.Warn("MQTT is not enabled in your configuration")
+		}
+		hdl = hdl.WithTenants(tenants)
+		hdlComponent := start("handler", viper.GetInt("standalone.handler-port"), hdl)
+		if mqttAddress := viper.GetString("standalone.mqtt-address"); mqttAddress != "" {
+			hdlComponent.Identity.MqttAddress = mqttAddress
+		}
+
+		ctx.Info("Started all components")
+
+		sigChan := make(chan os.Signal)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		ctx.WithField("signal", <-sigChan).Info("signal received")
+
+		for i := len(servers) - 1; i >= 0; i-- {
+			servers[i].Stop()
+			components[i].Shutdown()
+		}
+	},
+}
+
+// buildStandaloneToken builds a token that the given component can use to
+// announce itself to the standalone discovery server
+func buildStandaloneToken(privKey *ecdsa.PrivateKey, serviceName, id string) (string, error) {
+	var claims claims.ComponentClaims
+	claims.Subject = id
+	claims.Type = serviceName
+	claims.Issuer = standaloneIssuer
+	claims.IssuedAt = time.Now().Unix()
+	claims.NotBefore = time.Now().Unix()
+	return jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(privKey)
+}
+
+func init() {
+	RootCmd.AddCommand(standaloneCmd)
+
+	standaloneCmd.Flags().String("server-address", "0.0.0.0", "The IP address to listen for communication")
+	standaloneCmd.Flags().String("server-address-announce", "localhost", "The public IP address to announce")
+	viper.BindPFlag("standalone.server-address", standaloneCmd.Flags().Lookup("server-address"))
+	viper.BindPFlag("standalone.server-address-announce", standaloneCmd.Flags().Lookup("server-address-announce"))
+
+	standaloneCmd.Flags().Int("discovery-port", 1900, "The port for the Discovery server")
+	standaloneCmd.Flags().Int("router-port", 1901, "The port for the Router")
+	standaloneCmd.Flags().Int("broker-port", 1902, "The port for the Broker")
+	standaloneCmd.Flags().Int("networkserver-port", 1903, "The port for the Network Server")
+	standaloneCmd.Flags().Int("handler-port", 1904, "The port for the Handler")
+	viper.BindPFlag("standalone.discovery-port", standaloneCmd.Flags().Lookup("discovery-port"))
+	viper.BindPFlag("standalone.router-port", standaloneCmd.Flags().Lookup("router-port"))
+	viper.BindPFlag("standalone.broker-port", standaloneCmd.Flags().Lookup("broker-port"))
+	viper.BindPFlag("standalone.networkserver-port", standaloneCmd.Flags().Lookup("networkserver-port"))
+	viper.BindPFlag("standalone.handler-port", standaloneCmd.Flags().Lookup("handler-port"))
+
+	standaloneCmd.Flags().String("mqtt-address", "", "MQTT host and port for the Handler. Leave empty to disable MQTT")
+	standaloneCmd.Flags().String("mqtt-username", "", "MQTT username")
+	standaloneCmd.Flags().String("mqtt-password", "", "MQTT password")
+	viper.BindPFlag("standalone.mqtt-address", standaloneCmd.Flags().Lookup("mqtt-address"))
+	viper.BindPFlag("standalone.mqtt-username", standaloneCmd.Flags().Lookup("mqtt-username"))
+	viper.BindPFlag("standalone.mqtt-password", standaloneCmd.Flags().Lookup("mqtt-password"))
+}
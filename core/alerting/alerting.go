@@ -0,0 +1,202 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package alerting notifies operators when a gateway goes offline or moves, or
+// a component is degraded, and when the problem is resolved.
+package alerting
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+)
+
+// Kinds of alerts
+const (
+	GatewayOffline     = "gateway_offline"
+	GatewayMoved       = "gateway_moved"
+	ComponentUnhealthy = "component_unhealthy"
+	RedisLatency       = "redis_latency"
+	DeduplicationLate  = "deduplication_late"
+)
+
+// Alert statuses
+const (
+	Firing   = "firing"
+	Resolved = "resolved"
+)
+
+// CheckInterval is the interval in which the components check the conditions of alerts
+var CheckInterval = time.Minute
+
+// NotifyTimeout is the timeout of the notifiers that send an alert
+var NotifyTimeout = 10 * time.Second
+
+// QueueSize is the number of alerts that can wait to be sent to the notifiers
+var QueueSize = 100
+
+// Alert is sent to the notifiers when it starts firing and when it is resolved
+type Alert struct {
+	Kind       string    `json:"kind"`
+	Subject    string    `json:"subject"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message"`
+	Component  string    `json:"component"`
+	StartedAt  time.Time `json:"started_at"`
+	ResolvedAt time.Time `json:"resolved_at,omitempty"`
+}
+
+// Notifier sends alerts to operators
+type Notifier interface {
+	Notify(alert Alert) error
+}
+
+type alertKey struct {
+	kind    string
+	subject string
+}
+
+// Manager keeps track of the alerts of a component. An alert is only sent
+// once while it is firing, and once when it is resolved. Alerts are sent to
+// the notifiers in the background, in the order in which they were updated.
+type Manager struct {
+	ctx       ttnlog.Interface
+	component string
+	notifiers []Notifier
+
+	mu     sync.Mutex
+	firing map[alertKey]*Alert
+
+	queue   chan Alert
+	pending sync.WaitGroup
+}
+
+// NewManager returns a new Manager for the component with the given ID
+func NewManager(ctx ttnlog.Interface, component string, notifiers ...Notifier) *Manager {
+	m := &Manager{
+		ctx:       ctx,
+		component: component,
+		notifiers: notifiers,
+		firing:    make(map[alertKey]*Alert),
+		queue:     make(chan Alert, QueueSize),
+	}
+	go func() {
+		for alert := range m.queue {
+			m.send(alert)
+			m.pending.Done()
+		}
+	}()
+	return m
+}
+
+// Update fires the alert of the kind for the subject if the condition is
+// true, or resolves it if the condition is false
+func (m *Manager) Update(kind, subject string, condition bool, message string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	key := alertKey{kind, subject}
+	alert, isFiring := m.firing[key]
+	switch {
+	case condition && !isFiring:
+		alert = &Alert{
+			Kind:      kind,
+			Subject:   subject,
+			Status:    Firing,
+			Message:   message,
+			Component: m.component,
+			StartedAt: time.Now().UTC(),
+		}
+		m.firing[key] = alert
+	case !condition && isFiring:
+		delete(m.firing, key)
+		alert.Status = Resolved
+		alert.Message = message
+		alert.ResolvedAt = time.Now().UTC()
+	default:
+		m.mu.Unlock()
+		return
+	}
+	notification := *alert
+	m.mu.Unlock()
+	m.notify(notification)
+}
+
+// Firing returns the alerts that are firing
+func (m *Manager) Firing() []Alert {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	alerts := make([]Alert, 0, len(m.firing))
+	for _, alert := range m.firing {
+		alerts = append(alerts, *alert)
+	}
+	return alerts
+}
+
+// notify logs the alert and queues it for the notifiers, so that a slow
+// notifier does not block the component
+func (m *Manager) notify(alert Alert) {
+	ctx := m.ctx.WithFields(ttnlog.Fields{
+		"Alert":   alert.Kind,
+		"Subject": alert.Subject,
+	})
+	if alert.Status == Firing {
+		ctx.Warn(alert.Message)
+	} else {
+		ctx.Info(alert.Message)
+	}
+	if len(m.notifiers) == 0 {
+		return
+	}
+	m.pending.Add(1)
+	select {
+	case m.queue <- alert:
+	default:
+		m.pending.Done()
+		ctx.Warn("Could not send alert, queue full")
+	}
+}
+
+func (m *Manager) send(alert Alert) {
+	for _, notifier := range m.notifiers {
+		if err := notifier.Notify(alert); err != nil {
+			m.ctx.WithFields(ttnlog.Fields{
+				"Alert":   alert.Kind,
+				"Subject": alert.Subject,
+			}).WithError(err).Warn("Could not send alert")
+		}
+	}
+}
+
+// wait waits until the queued alerts are sent
+func (m *Manager) wait() {
+	m.pending.Wait()
+}
+
+// WatchLatency periodically measures the latency of ping and fires the alert
+// of the kind for the subject while it exceeds the threshold or fails
+func (m *Manager) WatchLatency(kind, subject string, ping func() error, threshold time.Duration) {
+	for range time.Tick(CheckInterval) {
+		m.checkLatency(kind, subject, ping, threshold)
+	}
+}
+
+func (m *Manager) checkLatency(kind, subject string, ping func() error, threshold time.Duration) {
+	start := time.Now()
+	err := ping()
+	latency := time.Since(start)
+	switch {
+	case err != nil:
+		m.Update(kind, subject, true, fmt.Sprintf("%s is not reachable: %s", subject, err))
+	case latency > threshold:
+		m.Update(kind, subject, true, fmt.Sprintf("%s latency of %s exceeds %s", subject, latency, threshold))
+	default:
+		m.Update(kind, subject, false, fmt.Sprintf("%s latency of %s is below %s", subject, latency, threshold))
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package alerting
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+type testNotifier struct {
+	alerts []Alert
+}
+
+func (n *testNotifier) Notify(alert Alert) error {
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestManagerUpdate(t *testing.T) {
+	a := New(t)
+
+	notifier := &testNotifier{}
+	m := NewManager(GetLogger(t, "TestManagerUpdate"), "router-1", notifier)
+
+	// Resolving an alert that is not firing does nothing
+	m.Update(GatewayOffline, "gtw", false, "Gateway gtw is online")
+	m.wait()
+	a.So(notifier.alerts, ShouldBeEmpty)
+
+	// An alert is only sent once while it is firing
+	m.Update(GatewayOffline, "gtw", true, "Gateway gtw was not seen for 10m0s")
+	m.Update(GatewayOffline, "gtw", true, "Gateway gtw was not seen for 11m0s")
+	m.wait()
+	a.So(notifier.alerts, ShouldHaveLength, 1)
+	a.So(notifier.alerts[0].Status, ShouldEqual, Firing)
+	a.So(notifier.alerts[0].Component, ShouldEqual, "router-1")
+	a.So(notifier.alerts[0].Message, ShouldEqual, "Gateway gtw was not seen for 10m0s")
+	a.So(m.Firing(), ShouldHaveLength, 1)
+
+	// Alerts of other subjects are separate
+	m.Update(GatewayOffline, "other", true, "Gateway other was not seen for 10m0s")
+	m.wait()
+	a.So(notifier.alerts, ShouldHaveLength, 2)
+
+	m.Update(GatewayOffline, "gtw", false, "Gateway gtw is online again")
+	m.wait()
+	a.So(notifier.alerts, ShouldHaveLength, 3)
+	a.So(notifier.alerts[2].Status, ShouldEqual, Resolved)
+	a.So(notifier.alerts[2].StartedAt, ShouldResemble, notifier.alerts[0].StartedAt)
+	a.So(notifier.alerts[2].ResolvedAt.IsZero(), ShouldBeFalse)
+	a.So(m.Firing(), ShouldHaveLength, 1)
+
+	// A nil Manager does nothing
+	var nilManager *Manager
+	nilManager.Update(GatewayOffline, "gtw", true, "")
+	a.So(nilManager.Firing(), ShouldBeEmpty)
+}
+
+type blockingNotifier struct {
+	release chan struct{}
+}
+
+func (n *blockingNotifier) Notify(alert Alert) error {
+	<-n.release
+	return nil
+}
+
+func TestManagerSlowNotifier(t *testing.T) {
+	a := New(t)
+
+	notifier := &blockingNotifier{release: make(chan struct{})}
+	m := NewManager(GetLogger(t, "TestManagerSlowNotifier"), "router-1", notifier)
+
+	done := make(chan struct{})
+	go func() {
+		m.Update(GatewayOffline, "gtw", true, "Gateway gtw was not seen for 10m0s")
+		m.Update(GatewayOffline, "gtw", false, "Gateway gtw is online again")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Update waited for the notifier")
+	}
+	a.So(m.Firing(), ShouldBeEmpty)
+
+	close(notifier.release)
+	m.wait()
+}
+
+func TestCheckLatency(t *testing.T) {
+	a := New(t)
+
+	notifier := &testNotifier{}
+	m := NewManager(GetLogger(t, "TestCheckLatency"), "handler-1", notifier)
+
+	m.checkLatency(RedisLatency, "Redis", func() error { return nil }, time.Second)
+	m.wait()
+	a.So(notifier.alerts, ShouldBeEmpty)
+
+	m.checkLatency(RedisLatency, "Redis", func() error { time.Sleep(10 * time.Millisecond); return nil }, time.Millisecond)
+	m.wait()
+	a.So(notifier.alerts, ShouldHaveLength, 1)
+	a.So(notifier.alerts[0].Kind, ShouldEqual, RedisLatency)
+
+	m.checkLatency(RedisLatency, "Redis", func() error { return nil }, time.Second)
+	m.wait()
+	a.So(notifier.alerts, ShouldHaveLength, 2)
+	a.So(notifier.alerts[1].Status, ShouldEqual, Resolved)
+
+	m.checkLatency(RedisLatency, "Redis", func() error { return errors.New("connection refused") }, time.Second)
+	m.wait()
+	a.So(notifier.alerts, ShouldHaveLength, 3)
+	a.So(notifier.alerts[2].Message, ShouldContainSubstring, "connection refused")
+}
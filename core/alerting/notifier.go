@@ -0,0 +1,126 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package alerting
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// WebhookNotifier posts alerts as JSON to a URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier returns a notifier that posts alerts to the URL
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: NotifyTimeout},
+	}
+}
+
+// Notify implements Notifier
+func (n *WebhookNotifier) Notify(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	res, err := n.Client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("Webhook %s returned %s", n.URL, res.Status)
+	}
+	return nil
+}
+
+// EmailNotifier sends alerts as emails through an SMTP server
+type EmailNotifier struct {
+	Server string
+	From   string
+	To     []string
+
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier returns a notifier that sends alerts from the address to
+// the addresses through the SMTP server (host:port)
+func NewEmailNotifier(server, from string, to []string) *EmailNotifier {
+	return &EmailNotifier{Server: server, From: from, To: to, sendMail: sendMail}
+}
+
+// sendMail is smtp.SendMail with the NotifyTimeout as the deadline of the
+// connection to the SMTP server
+func sendMail(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	conn, err := net.DialTimeout("tcp", addr, NotifyTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(NotifyTimeout)); err != nil {
+		return err
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Notify implements Notifier
+func (n *EmailNotifier) Notify(alert Alert) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [%s] %s %s on %s\r\n", strings.ToUpper(alert.Status), alert.Kind, alert.Subject, alert.Component)
+	fmt.Fprintf(&msg, "\r\n%s\r\n\r\nStarted at %s\r\n", alert.Message, alert.StartedAt.Format(time.RFC3339))
+	if alert.Status == Resolved {
+		fmt.Fprintf(&msg, "Resolved at %s\r\n", alert.ResolvedAt.Format(time.RFC3339))
+	}
+	return n.sendMail(n.Server, nil, n.From, n.To, msg.Bytes())
+}
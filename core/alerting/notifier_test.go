@@ -0,0 +1,71 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package alerting
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	a := New(t)
+
+	var received []Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, alert)
+		if alert.Subject == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	err := notifier.Notify(Alert{Kind: GatewayOffline, Subject: "gtw", Status: Firing})
+	a.So(err, ShouldBeNil)
+	a.So(received, ShouldHaveLength, 1)
+	a.So(received[0].Subject, ShouldEqual, "gtw")
+
+	err = notifier.Notify(Alert{Kind: GatewayOffline, Subject: "fail", Status: Firing})
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestEmailNotifier(t *testing.T) {
+	a := New(t)
+
+	var server, from string
+	var to []string
+	var msg []byte
+	notifier := NewEmailNotifier("smtp.example.com:25", "ttn@example.com", []string{"ops@example.com"})
+	notifier.sendMail = func(addr string, _ smtp.Auth, f string, t []string, m []byte) error {
+		server, from, to, msg = addr, f, t, m
+		return nil
+	}
+
+	err := notifier.Notify(Alert{
+		Kind:       GatewayOffline,
+		Subject:    "gtw",
+		Status:     Resolved,
+		Message:    "Gateway gtw is online again",
+		Component:  "router-1",
+		StartedAt:  time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC),
+		ResolvedAt: time.Date(2017, 5, 1, 12, 30, 0, 0, time.UTC),
+	})
+	a.So(err, ShouldBeNil)
+	a.So(server, ShouldEqual, "smtp.example.com:25")
+	a.So(from, ShouldEqual, "ttn@example.com")
+	a.So(to, ShouldResemble, []string{"ops@example.com"})
+	a.So(string(msg), ShouldContainSubstring, "Subject: [RESOLVED] gateway_offline gtw on router-1\r\n")
+	a.So(string(msg), ShouldContainSubstring, "Resolved at 2017-05-01T12:30:00Z")
+}
@@ -13,6 +13,14 @@ var demodulationFloor = map[string]float32{
 	"SF11BW125": -17.5,
 	"SF12BW125": -20,
 	"SF7BW250":  -4.5,
+	"SF5BW812":  -2.5,
+	"SF6BW812":  -5,
+	"SF7BW812":  -7.5,
+	"SF8BW812":  -10,
+	"SF9BW812":  -12.5,
+	"SF10BW812": -15,
+	"SF11BW812": -17.5,
+	"SF12BW812": -20,
 }
 
 func linkMargin(dataRate string, snr float32) float32 {
@@ -24,10 +32,10 @@ func linkMargin(dataRate string, snr float32) float32 {
 
 // ADRConfig contains configuration for Adaptive Data Rate
 type ADRConfig struct {
-	MinDataRate int
-	MaxDataRate int
-	MinTXPower  int
-	MaxTXPower  int
+	MinDataRate int `yaml:"min-data-rate"`
+	MaxDataRate int `yaml:"max-data-rate"`
+	MinTXPower  int `yaml:"min-tx-power"`
+	MaxTXPower  int `yaml:"max-tx-power"`
 }
 
 // ErrADRUnavailable is returned when ADR is not available
@@ -43,6 +51,11 @@ func (f *FrequencyPlan) ADRSettings(dataRate string, txPower int, snr float32, d
 	if err != nil {
 		return dataRate, txPower, err
 	}
+	if IsFSK(dataRate) {
+		// FSK is faster than any LoRa data rate and has no SNR-based link
+		// margin, so the current settings are kept
+		return dataRate, txPower, nil
+	}
 	nStep := int(margin / 3)
 
 	// Increase the data rate with each step
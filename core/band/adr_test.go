@@ -46,6 +46,14 @@ func TestADRSettings(t *testing.T) {
 		a.So(tx, ShouldEqual, 14)
 	}
 
+	{
+		// FSK keeps the current settings
+		dr, tx, err := eu.ADRSettings("50000", 14, 0, defaultMargin)
+		a.So(err, ShouldBeNil)
+		a.So(dr, ShouldEqual, "50000")
+		a.So(tx, ShouldEqual, 14)
+	}
+
 	us, _ := Get("US_902_928")
 	{
 		_, _, err := us.ADRSettings("SF10BW125", 14, -3, defaultMargin)
@@ -63,6 +71,10 @@ func TestADRSettings(t *testing.T) {
 		us.ADR = new(ADRConfig)
 		_, _, err = us.ADRSettings("SF12BW125", 14, -3, defaultMargin)
 		a.So(err, ShouldNotBeNil)
+
+		// Invalid datarate (there is no FSK in US)
+		_, _, err = us.ADRSettings("50000", 14, -3, defaultMargin)
+		a.So(err, ShouldNotBeNil)
 	}
 
 }
@@ -4,6 +4,8 @@
 package band
 
 import (
+	"strconv"
+
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
@@ -16,9 +18,50 @@ type FrequencyPlan struct {
 	lora.Band
 	ADR    *ADRConfig
 	CFList *lorawan.CFList
+
+	// DwellTime indicates whether transmissions are limited to MaxDwellTime
+	DwellTime bool
+
+	// RepeaterMaxPayloadSize contains the maximum payload sizes by data rate
+	// index that are compatible with repeaters
+	RepeaterMaxPayloadSize []lora.MaxPayloadSize
+
+	rx1Frequency func(txFrequency int) (int, error)
+}
+
+// setBand sets the band with the given name and the corresponding repeater-compatible payload sizes
+func (f *FrequencyPlan) setBand(name lora.Name, dwellTime bool) (err error) {
+	lorawanDwellTime := lorawan.DwellTimeNoLimit
+	if dwellTime {
+		lorawanDwellTime = lorawan.DwellTime400ms
+	}
+	repeater, err := lora.GetConfig(name, true, lorawanDwellTime)
+	if err != nil {
+		return err
+	}
+	f.RepeaterMaxPayloadSize = repeater.MaxPayloadSize
+	f.DwellTime = dwellTime
+	f.Band, err = lora.GetConfig(name, false, lorawanDwellTime)
+	return err
 }
 
+// GetRX1Frequency returns the frequency to use for RX1 given the uplink frequency
+func (f *FrequencyPlan) GetRX1Frequency(txFrequency int) (int, error) {
+	if f.rx1Frequency != nil {
+		return f.rx1Frequency(txFrequency)
+	}
+	return f.Band.GetRX1Frequency(txFrequency)
+}
+
+// GetDataRateStringForIndex returns the data rate with the given index, such
+// as "SF7BW125". FSK data rates are returned as bit rate, such as "50000".
 func (f *FrequencyPlan) GetDataRateStringForIndex(drIdx int) (string, error) {
+	if drIdx < 0 || drIdx >= len(f.DataRates) {
+		return "", errors.New("core/band: the given data rate index does not exist")
+	}
+	if f.DataRates[drIdx].Modulation == lora.FSKModulation {
+		return strconv.Itoa(f.DataRates[drIdx].BitRate), nil
+	}
 	dr, err := types.ConvertDataRate(f.DataRates[drIdx])
 	if err != nil {
 		return "", err
@@ -26,12 +69,23 @@ func (f *FrequencyPlan) GetDataRateStringForIndex(drIdx int) (string, error) {
 	return dr.String(), nil
 }
 
+// GetDataRateIndexFor returns the index of a LoRa data rate such as "SF7BW125"
+// or an FSK data rate given as bit rate, such as "50000"
 func (f *FrequencyPlan) GetDataRateIndexFor(dataRate string) (int, error) {
-	dr, err := types.ParseDataRate(dataRate)
+	if dataRate == "" {
+		return 0, errors.New("core/band: the data rate is empty")
+	}
+	dr, err := parseDefinitionDataRate(dataRate)
 	if err != nil {
 		return 0, err
 	}
-	return f.Band.GetDataRate(lora.DataRate{Modulation: lora.LoRaModulation, SpreadFactor: int(dr.SpreadingFactor), Bandwidth: int(dr.Bandwidth)})
+	return f.Band.GetDataRate(dr)
+}
+
+// IsFSK returns true if the data rate is an FSK data rate given as bit rate, such as "50000"
+func IsFSK(dataRate string) bool {
+	dr, err := parseDefinitionDataRate(dataRate)
+	return err == nil && dr.Modulation == lora.FSKModulation
 }
 
 func (f *FrequencyPlan) GetTxPowerIndexFor(txPower int) (int, error) {
@@ -46,6 +100,8 @@ func (f *FrequencyPlan) GetTxPowerIndexFor(txPower int) (int, error) {
 // Guess the region based on frequency
 func Guess(frequency uint64) string {
 	switch {
+	case frequency >= 2400000000 && frequency <= 2500000000:
+		return ISM_2400
 	case frequency >= 863000000 && frequency <= 870000000:
 		return pb_lorawan.Region_EU_863_870.String()
 	case frequency >= 902300000 && frequency <= 914900000:
@@ -68,54 +124,11 @@ func Guess(frequency uint64) string {
 
 // Get the frequency plan for the given region
 func Get(region string) (frequencyPlan FrequencyPlan, err error) {
-	switch region {
-	case pb_lorawan.Region_EU_863_870.String():
-		frequencyPlan.Band, err = lora.GetConfig(lora.EU_863_870, false, lorawan.DwellTimeNoLimit)
-		// TTN uses SF9BW125 in RX2
-		frequencyPlan.RX2DataRate = 3
-		// TTN frequency plan includes extra channels next to the default channels:
-		frequencyPlan.UplinkChannels = []lora.Channel{
-			lora.Channel{Frequency: 868100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 868300000, DataRates: []int{0, 1, 2, 3, 4, 5, 6}}, // Also SF7BW250
-			lora.Channel{Frequency: 868500000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 867100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 867300000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 867500000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 867700000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 867900000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 868800000, DataRates: []int{7}}, // FSK 50kbps
-		}
-		frequencyPlan.DownlinkChannels = frequencyPlan.UplinkChannels
-		frequencyPlan.CFList = &lorawan.CFList{867100000, 867300000, 867500000, 867700000, 867900000}
-		frequencyPlan.ADR = &ADRConfig{MinDataRate: 0, MaxDataRate: 5, MinTXPower: 2, MaxTXPower: 14}
-	case pb_lorawan.Region_US_902_928.String():
-		frequencyPlan.Band, err = lora.GetConfig(lora.US_902_928, false, lorawan.DwellTime400ms)
-	case pb_lorawan.Region_CN_779_787.String():
-		frequencyPlan.Band, err = lora.GetConfig(lora.CN_779_787, false, lorawan.DwellTimeNoLimit)
-	case pb_lorawan.Region_EU_433.String():
-		frequencyPlan.Band, err = lora.GetConfig(lora.EU_433, false, lorawan.DwellTimeNoLimit)
-	case pb_lorawan.Region_AU_915_928.String():
-		frequencyPlan.Band, err = lora.GetConfig(lora.AU_915_928, false, lorawan.DwellTime400ms)
-	case pb_lorawan.Region_CN_470_510.String():
-		frequencyPlan.Band, err = lora.GetConfig(lora.CN_470_510, false, lorawan.DwellTimeNoLimit)
-	case pb_lorawan.Region_AS_923.String():
-		frequencyPlan.Band, err = lora.GetConfig(lora.AS_923, false, lorawan.DwellTime400ms)
-	case pb_lorawan.Region_KR_920_923.String():
-		frequencyPlan.Band, err = lora.GetConfig(lora.KR_920_923, false, lorawan.DwellTimeNoLimit)
-		// TTN frequency plan includes extra channels next to the default channels:
-		frequencyPlan.UplinkChannels = []lora.Channel{
-			lora.Channel{Frequency: 922100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 922300000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 922500000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 922700000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 922900000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 923100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-			lora.Channel{Frequency: 923300000, DataRates: []int{0, 1, 2, 3, 4, 5}},
-		}
-		frequencyPlan.DownlinkChannels = frequencyPlan.UplinkChannels
-		frequencyPlan.CFList = &lorawan.CFList{922700000, 922900000, 923100000, 923300000, 0}
-	default:
-		err = errors.NewErrInvalidArgument("Frequency Band", "unknown")
+	registry.RLock()
+	builder, ok := registry.builders[region]
+	registry.RUnlock()
+	if !ok {
+		return frequencyPlan, errors.NewErrInvalidArgument("Frequency Band", "unknown")
 	}
-	return
+	return builder()
 }
@@ -20,6 +20,7 @@ func TestGuess(t *testing.T) {
 	a.So(Guess(470300000), ShouldEqual, "CN_470_510")
 	a.So(Guess(923200000), ShouldEqual, "AS_923")
 	a.So(Guess(922100000), ShouldEqual, "KR_920_923")
+	a.So(Guess(2425000000), ShouldEqual, "ISM_2400")
 }
 
 func TestGet(t *testing.T) {
@@ -110,3 +111,30 @@ func TestGetTxPower(t *testing.T) {
 		a.So(idx, ShouldEqual, expIdx)
 	}
 }
+
+func TestGetFSKDataRate(t *testing.T) {
+	a := New(t)
+
+	eu, _ := Get("EU_863_870")
+
+	idx, err := eu.GetDataRateIndexFor("50000")
+	a.So(err, ShouldBeNil)
+	a.So(idx, ShouldEqual, 7)
+
+	rate, err := eu.GetDataRateStringForIndex(7)
+	a.So(err, ShouldBeNil)
+	a.So(rate, ShouldEqual, "50000")
+
+	_, err = eu.GetDataRateIndexFor("100000")
+	a.So(err, ShouldNotBeNil)
+
+	_, err = eu.GetDataRateIndexFor("")
+	a.So(err, ShouldNotBeNil)
+
+	_, err = eu.GetDataRateStringForIndex(16)
+	a.So(err, ShouldNotBeNil)
+
+	a.So(IsFSK("50000"), ShouldBeTrue)
+	a.So(IsFSK("SF7BW125"), ShouldBeFalse)
+	a.So(IsFSK(""), ShouldBeFalse)
+}
@@ -0,0 +1,80 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+import (
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/brocaar/lorawan"
+	lora "github.com/brocaar/lorawan/band"
+)
+
+func init() {
+	Register(pb_lorawan.Region_EU_863_870.String(), func() (frequencyPlan FrequencyPlan, err error) {
+		err = frequencyPlan.setBand(lora.EU_863_870, false)
+		// TTN uses SF9BW125 in RX2
+		frequencyPlan.RX2DataRate = 3
+		// TTN frequency plan includes extra channels next to the default channels:
+		frequencyPlan.UplinkChannels = []lora.Channel{
+			lora.Channel{Frequency: 868100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 868300000, DataRates: []int{0, 1, 2, 3, 4, 5, 6}}, // Also SF7BW250
+			lora.Channel{Frequency: 868500000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 867100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 867300000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 867500000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 867700000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 867900000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 868800000, DataRates: []int{7}}, // FSK 50kbps
+		}
+		frequencyPlan.DownlinkChannels = frequencyPlan.UplinkChannels
+		frequencyPlan.CFList = &lorawan.CFList{867100000, 867300000, 867500000, 867700000, 867900000}
+		frequencyPlan.ADR = &ADRConfig{MinDataRate: 0, MaxDataRate: 5, MinTXPower: 2, MaxTXPower: 14}
+		return
+	})
+	Register(pb_lorawan.Region_US_902_928.String(), func() (frequencyPlan FrequencyPlan, err error) {
+		err = frequencyPlan.setBand(lora.US_902_928, true)
+		return
+	})
+	Register(pb_lorawan.Region_CN_779_787.String(), func() (frequencyPlan FrequencyPlan, err error) {
+		err = frequencyPlan.setBand(lora.CN_779_787, false)
+		return
+	})
+	Register(pb_lorawan.Region_EU_433.String(), func() (frequencyPlan FrequencyPlan, err error) {
+		err = frequencyPlan.setBand(lora.EU_433, false)
+		return
+	})
+	Register(pb_lorawan.Region_AU_915_928.String(), func() (frequencyPlan FrequencyPlan, err error) {
+		err = frequencyPlan.setBand(lora.AU_915_928, true)
+		return
+	})
+	Register(pb_lorawan.Region_CN_470_510.String(), func() (frequencyPlan FrequencyPlan, err error) {
+		err = frequencyPlan.setBand(lora.CN_470_510, false)
+		return
+	})
+	Register(pb_lorawan.Region_AS_923.String(), func() (frequencyPlan FrequencyPlan, err error) {
+		err = frequencyPlan.setBand(lora.AS_923, true)
+		return
+	})
+	Register(pb_lorawan.Region_KR_920_923.String(), func() (frequencyPlan FrequencyPlan, err error) {
+		err = frequencyPlan.setBand(lora.KR_920_923, false)
+		// TTN frequency plan includes extra channels next to the default channels:
+		frequencyPlan.UplinkChannels = []lora.Channel{
+			lora.Channel{Frequency: 922100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 922300000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 922500000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 922700000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 922900000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 923100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			lora.Channel{Frequency: 923300000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+		}
+		frequencyPlan.DownlinkChannels = frequencyPlan.UplinkChannels
+		frequencyPlan.CFList = &lorawan.CFList{922700000, 922900000, 923100000, 923300000, 0}
+		return
+	})
+
+	for _, definition := range append(rp002Definitions, ism2400Definition) {
+		if err := RegisterDefinition(definition); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/brocaar/lorawan"
+	lora "github.com/brocaar/lorawan/band"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// RX1 modes of a Definition
+const (
+	// RX1SameFrequency uses the uplink frequency for RX1
+	RX1SameFrequency = "uplink"
+	// RX1SameChannel uses the downlink channel with the same index as the uplink channel for RX1
+	RX1SameChannel = "channel"
+)
+
+// Definition of a band plan, following the structure of the LoRaWAN Regional
+// Parameters (RP002). Definitions can be loaded from a config file to add
+// private band plans.
+type Definition struct {
+	// Name of the region
+	Name string `yaml:"name"`
+	// Base band that is used for the values that are not in this definition
+	// (RX1 data rate offsets, maximum payload sizes)
+	Base string `yaml:"base"`
+	// DwellTime indicates whether the 400ms dwell time limit applies
+	DwellTime bool `yaml:"dwell-time"`
+	// DataRates by index, such as "SF12BW125". FSK data rates are given as bit rate, such as "50000".
+	// Empty strings are used for RFU data rates
+	DataRates []string `yaml:"data-rates"`
+	// UplinkChannels that are used in this region
+	UplinkChannels []ChannelDefinition `yaml:"uplink-channels"`
+	// DownlinkChannels that are used in this region. Defaults to the UplinkChannels
+	DownlinkChannels []ChannelDefinition `yaml:"downlink-channels"`
+	// RX1 is the mode that is used to determine the RX1 frequency (uplink or channel)
+	RX1 string `yaml:"rx1"`
+	// RX2Frequency in Hz
+	RX2Frequency int `yaml:"rx2-frequency"`
+	// RX2DataRate index. Defaults to the RX2 data rate of the base band
+	RX2DataRate *int `yaml:"rx2-data-rate"`
+	// TXPower in dBm by index
+	TXPower []int `yaml:"tx-power"`
+	// DefaultTXPower in dBm
+	DefaultTXPower int `yaml:"default-tx-power"`
+	// CFList with the extra channels that are sent in the Join Accept
+	CFList []uint32 `yaml:"cf-list"`
+	// ADR configuration. ADR is not available if this is empty
+	ADR *ADRConfig `yaml:"adr"`
+	// MaxPayloadSize contains the maximum MACPayload size (M) by data rate index. Defaults to
+	// the sizes of the base band
+	MaxPayloadSize []int `yaml:"max-payload-size"`
+}
+
+// ChannelDefinition defines a channel of a Definition
+type ChannelDefinition struct {
+	Frequency int   `yaml:"frequency"`
+	DataRates []int `yaml:"data-rates"`
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func parseDefinitionDataRate(dataRate string) (lora.DataRate, error) {
+	if dataRate == "" {
+		return lora.DataRate{}, nil
+	}
+	if dr, err := types.ParseDataRate(dataRate); err == nil {
+		return lora.DataRate{Modulation: lora.LoRaModulation, SpreadFactor: int(dr.SpreadingFactor), Bandwidth: int(dr.Bandwidth)}, nil
+	}
+	bitRate, err := strconv.Atoi(dataRate)
+	if err != nil {
+		return lora.DataRate{}, errors.NewErrInvalidArgument("Data Rate", fmt.Sprintf("%s is not a LoRa or FSK data rate", dataRate))
+	}
+	return lora.DataRate{Modulation: lora.FSKModulation, BitRate: bitRate}, nil
+}
+
+func buildChannels(definitions []ChannelDefinition) []lora.Channel {
+	channels := make([]lora.Channel, 0, len(definitions))
+	for _, channel := range definitions {
+		channels = append(channels, lora.Channel{Frequency: channel.Frequency, DataRates: channel.DataRates})
+	}
+	return channels
+}
+
+// Validate the definition
+func (d Definition) Validate() error {
+	if d.Name == "" {
+		return errors.NewErrInvalidArgument("Band Definition", "name is empty")
+	}
+	if d.Base == "" {
+		return errors.NewErrInvalidArgument("Band Definition", "base is empty")
+	}
+	if len(d.UplinkChannels) == 0 {
+		return errors.NewErrInvalidArgument("Band Definition", "no uplink channels")
+	}
+	switch d.RX1 {
+	case "", RX1SameFrequency, RX1SameChannel:
+	default:
+		return errors.NewErrInvalidArgument("Band Definition", fmt.Sprintf("unknown rx1 mode %s", d.RX1))
+	}
+	if len(d.CFList) > len(lorawan.CFList{}) {
+		return errors.NewErrInvalidArgument("Band Definition", "too many channels in cf-list")
+	}
+	return nil
+}
+
+// Build the frequency plan of the definition
+func (d Definition) Build() (frequencyPlan FrequencyPlan, err error) {
+	if err = frequencyPlan.setBand(lora.Name(d.Base), d.DwellTime); err != nil {
+		return frequencyPlan, err
+	}
+
+	if len(d.DataRates) > 0 {
+		frequencyPlan.DataRates = make([]lora.DataRate, len(d.DataRates))
+		for i, dataRate := range d.DataRates {
+			if frequencyPlan.DataRates[i], err = parseDefinitionDataRate(dataRate); err != nil {
+				return frequencyPlan, err
+			}
+		}
+	}
+
+	frequencyPlan.UplinkChannels = buildChannels(d.UplinkChannels)
+	frequencyPlan.DownlinkChannels = frequencyPlan.UplinkChannels
+	if len(d.DownlinkChannels) > 0 {
+		frequencyPlan.DownlinkChannels = buildChannels(d.DownlinkChannels)
+	}
+
+	if d.RX2Frequency != 0 {
+		frequencyPlan.RX2Frequency = d.RX2Frequency
+	}
+	if d.RX2DataRate != nil {
+		frequencyPlan.RX2DataRate = *d.RX2DataRate
+	}
+	if len(d.TXPower) > 0 {
+		frequencyPlan.TXPower = d.TXPower
+	}
+	if d.DefaultTXPower != 0 {
+		frequencyPlan.DefaultTXPower = d.DefaultTXPower
+	}
+
+	if len(d.CFList) > 0 {
+		var cfList lorawan.CFList
+		copy(cfList[:], d.CFList)
+		frequencyPlan.CFList = &cfList
+	}
+
+	if d.ADR != nil {
+		adr := *d.ADR
+		frequencyPlan.ADR = &adr
+	}
+
+	if len(d.MaxPayloadSize) > 0 {
+		frequencyPlan.MaxPayloadSize = make([]lora.MaxPayloadSize, len(d.MaxPayloadSize))
+		for i, m := range d.MaxPayloadSize {
+			// The FHDR without FOpts and the FPort take 8 bytes of the MACPayload
+			frequencyPlan.MaxPayloadSize[i] = lora.MaxPayloadSize{M: m, N: m - 8}
+		}
+		frequencyPlan.RepeaterMaxPayloadSize = frequencyPlan.MaxPayloadSize
+	}
+
+	uplinkChannels, downlinkChannels := frequencyPlan.UplinkChannels, frequencyPlan.DownlinkChannels
+	switch d.RX1 {
+	case RX1SameChannel:
+		frequencyPlan.rx1Frequency = func(txFrequency int) (int, error) {
+			for i, channel := range uplinkChannels {
+				if channel.Frequency == txFrequency {
+					return downlinkChannels[i%len(downlinkChannels)].Frequency, nil
+				}
+			}
+			return 0, errors.NewErrInvalidArgument("Frequency", "not an uplink channel")
+		}
+	default:
+		frequencyPlan.rx1Frequency = func(txFrequency int) (int, error) {
+			return txFrequency, nil
+		}
+	}
+
+	return frequencyPlan, nil
+}
+
+// RegisterDefinition validates the definition and registers it
+func RegisterDefinition(definition Definition) error {
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+	if _, err := definition.Build(); err != nil {
+		return err
+	}
+	Register(definition.Name, definition.Build)
+	return nil
+}
+
+// LoadDefinitions loads band definitions from a YAML (or JSON) file and
+// registers them. Definitions in the file replace built-in regions with the
+// same name.
+func LoadDefinitions(filename string) ([]string, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var definitions []Definition
+	if err := yaml.Unmarshal(contents, &definitions); err != nil {
+		return nil, err
+	}
+	var regions []string
+	for _, definition := range definitions {
+		if err := RegisterDefinition(definition); err != nil {
+			return regions, errors.Wrapf(err, "could not register %s", definition.Name)
+		}
+		regions = append(regions, definition.Name)
+	}
+	return regions, nil
+}
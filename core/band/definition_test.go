@@ -0,0 +1,155 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestRP002Regions(t *testing.T) {
+	a := New(t)
+
+	for _, region := range []string{IN_865_867, RU_864_870, KZ_865_868, CN_470_510_20_A, CN_470_510_20_B} {
+		a.So(Regions(), ShouldContain, region)
+		fp, err := Get(region)
+		a.So(err, ShouldBeNil)
+		a.So(fp.UplinkChannels, ShouldNotBeEmpty)
+	}
+
+	in, _ := Get(IN_865_867)
+	a.So(in.RX2Frequency, ShouldEqual, 866550000)
+	a.So(in.RX2DataRate, ShouldEqual, 2)
+	freq, err := in.GetRX1Frequency(865062500)
+	a.So(err, ShouldBeNil)
+	a.So(freq, ShouldEqual, 865062500)
+
+	cn, _ := Get(CN_470_510_20_A)
+	a.So(cn.UplinkChannels, ShouldHaveLength, 64)
+	a.So(cn.UplinkChannels[32].Frequency, ShouldEqual, 503500000)
+	dr, err := cn.GetDataRateIndexFor("SF7BW500")
+	a.So(err, ShouldBeNil)
+	a.So(dr, ShouldEqual, 6)
+	a.So(cn.RX2DataRate, ShouldEqual, 1)
+
+	// Plan A has separate downlink channels
+	freq, err = cn.GetRX1Frequency(470300000)
+	a.So(err, ShouldBeNil)
+	a.So(freq, ShouldEqual, 483900000)
+	freq, err = cn.GetRX1Frequency(503700000)
+	a.So(err, ShouldBeNil)
+	a.So(freq, ShouldEqual, 490500000)
+
+	// Plan B uses the uplink channels for downlink
+	cn, _ = Get(CN_470_510_20_B)
+	a.So(cn.UplinkChannels[32].Frequency, ShouldEqual, 496900000)
+	freq, err = cn.GetRX1Frequency(496900000)
+	a.So(err, ShouldBeNil)
+	a.So(freq, ShouldEqual, 496900000)
+}
+
+func TestISM2400(t *testing.T) {
+	a := New(t)
+
+	fp, err := Get(ISM_2400)
+	a.So(err, ShouldBeNil)
+	a.So(fp.UplinkChannels, ShouldHaveLength, 3)
+	a.So(fp.RX2Frequency, ShouldEqual, 2423000000)
+
+	dr, err := fp.GetDataRateStringForIndex(fp.RX2DataRate)
+	a.So(err, ShouldBeNil)
+	a.So(dr, ShouldEqual, "SF12BW812")
+	idx, err := fp.GetDataRateIndexFor("SF5BW812")
+	a.So(err, ShouldBeNil)
+	a.So(idx, ShouldEqual, 7)
+	_, err = fp.GetDataRateIndexFor("SF7BW125")
+	a.So(err, ShouldNotBeNil)
+
+	freq, err := fp.GetRX1Frequency(2479000000)
+	a.So(err, ShouldBeNil)
+	a.So(freq, ShouldEqual, 2479000000)
+
+	size, err := fp.GetMaxPayloadSizeFor("SF7BW812", true)
+	a.So(err, ShouldBeNil)
+	a.So(size.M, ShouldEqual, 248)
+	a.So(size.N, ShouldEqual, 240)
+
+	dataRate, txPower, err := fp.ADRSettings("SF12BW812", 10, 10, 15)
+	a.So(err, ShouldBeNil)
+	a.So(dataRate, ShouldEqual, "SF7BW812")
+	a.So(txPower, ShouldEqual, 10)
+}
+
+func TestDefinition(t *testing.T) {
+	a := New(t)
+
+	a.So(Definition{}.Validate(), ShouldNotBeNil)
+	a.So(Definition{Name: "test", Base: "EU_863_870"}.Validate(), ShouldNotBeNil)
+	a.So(Definition{Name: "test", Base: "EU_863_870", RX1: "other", UplinkChannels: []ChannelDefinition{{Frequency: 868100000}}}.Validate(), ShouldNotBeNil)
+
+	fp, err := Definition{
+		Name:             "test",
+		Base:             "EU_863_870",
+		UplinkChannels:   []ChannelDefinition{{Frequency: 868100000}, {Frequency: 868300000}},
+		DownlinkChannels: []ChannelDefinition{{Frequency: 869525000}},
+		RX1:              RX1SameChannel,
+	}.Build()
+	a.So(err, ShouldBeNil)
+	freq, err := fp.GetRX1Frequency(868300000)
+	a.So(err, ShouldBeNil)
+	a.So(freq, ShouldEqual, 869525000)
+	_, err = fp.GetRX1Frequency(868500000)
+	a.So(err, ShouldNotBeNil)
+
+	// The RX2 data rate of the base band is used if it is not set
+	definition := Definition{Name: "test", Base: "US_902_928", UplinkChannels: []ChannelDefinition{{Frequency: 902300000}}}
+	fp, err = definition.Build()
+	a.So(err, ShouldBeNil)
+	a.So(fp.RX2DataRate, ShouldEqual, 8)
+	definition.RX2DataRate = intPtr(0)
+	fp, err = definition.Build()
+	a.So(err, ShouldBeNil)
+	a.So(fp.RX2DataRate, ShouldEqual, 0)
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	a := New(t)
+
+	file, err := ioutil.TempFile("", "ttn-bands")
+	a.So(err, ShouldBeNil)
+	defer os.Remove(file.Name())
+	file.WriteString(`
+- name: PRIVATE_868
+  base: EU_863_870
+  data-rates: [SF12BW125, SF11BW125, SF10BW125, SF9BW125, SF8BW125, SF7BW125, SF7BW250, "50000"]
+  uplink-channels:
+  - frequency: 869100000
+    data-rates: [0, 1, 2, 3, 4, 5]
+  - frequency: 869300000
+    data-rates: [0, 1, 2, 3, 4, 5]
+  rx2-frequency: 869525000
+  rx2-data-rate: 3
+  cf-list: [869300000]
+  adr:
+    min-data-rate: 0
+    max-data-rate: 5
+    min-tx-power: 2
+    max-tx-power: 14
+`)
+	file.Close()
+
+	regions, err := LoadDefinitions(file.Name())
+	a.So(err, ShouldBeNil)
+	a.So(regions, ShouldResemble, []string{"PRIVATE_868"})
+
+	fp, err := Get("PRIVATE_868")
+	a.So(err, ShouldBeNil)
+	a.So(fp.UplinkChannels, ShouldHaveLength, 2)
+	a.So(fp.RX2Frequency, ShouldEqual, 869525000)
+	a.So(fp.CFList[0], ShouldEqual, 869300000)
+	a.So(fp.ADR.MaxTXPower, ShouldEqual, 14)
+}
@@ -0,0 +1,36 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
+)
+
+// MaxDwellTime is the maximum time on air of a single transmission in regions
+// with a dwell time limit
+const MaxDwellTime = 400 * time.Millisecond
+
+// CheckDwellTime returns an error if a LoRa transmission of payloadSize bytes
+// with the given data rate and coding rate exceeds the dwell time limit of the
+// frequency plan. Frequency plans without dwell time limit accept everything.
+func (f *FrequencyPlan) CheckDwellTime(dataRate string, codingRate string, payloadSize int) error {
+	if !f.DwellTime {
+		return nil
+	}
+	timeOnAir, err := toa.ComputeLoRa(uint(payloadSize), dataRate, codingRate)
+	if err != nil {
+		return err
+	}
+	if timeOnAir > MaxDwellTime {
+		return errors.NewErrInvalidArgument("Payload", fmt.Sprintf(
+			"%d bytes at %s takes %s on air, which exceeds the %s dwell time limit",
+			payloadSize, dataRate, timeOnAir, MaxDwellTime,
+		))
+	}
+	return nil
+}
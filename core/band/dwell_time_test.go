@@ -0,0 +1,27 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestCheckDwellTime(t *testing.T) {
+	a := New(t)
+
+	eu, _ := Get("EU_863_870")
+	a.So(eu.DwellTime, ShouldBeFalse)
+	a.So(eu.CheckDwellTime("SF12BW125", "4/5", 64), ShouldBeNil)
+
+	as, _ := Get("AS_923")
+	a.So(as.DwellTime, ShouldBeTrue)
+	a.So(as.CheckDwellTime("SF7BW125", "4/5", 64), ShouldBeNil)
+	a.So(as.CheckDwellTime("SF10BW125", "4/5", 12), ShouldBeNil)
+	a.So(as.CheckDwellTime("SF12BW125", "4/5", 64), ShouldNotBeNil)
+
+	us, _ := Get("US_902_928")
+	a.So(us.DwellTime, ShouldBeTrue)
+}
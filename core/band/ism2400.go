@@ -0,0 +1,29 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+// ISM_2400 is the worldwide 2.4 GHz ISM band of the LoRaWAN 2.4 GHz Regional
+// Parameters. LoRa uses 812.5 kHz bandwidth in this band (written as BW812),
+// and there is no duty cycle limit.
+const ISM_2400 = "ISM_2400"
+
+var ism2400Definition = Definition{
+	Name: ISM_2400,
+	Base: "EU_863_870", // The RX1 data rate offsets are the same
+	DataRates: []string{
+		"SF12BW812", "SF11BW812", "SF10BW812", "SF9BW812",
+		"SF8BW812", "SF7BW812", "SF6BW812", "SF5BW812",
+	},
+	UplinkChannels: []ChannelDefinition{
+		{Frequency: 2403000000, DataRates: []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		{Frequency: 2425000000, DataRates: []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		{Frequency: 2479000000, DataRates: []int{0, 1, 2, 3, 4, 5, 6, 7}},
+	},
+	RX2Frequency:   2423000000,
+	RX2DataRate:    intPtr(0),
+	TXPower:        []int{10, 8, 6, 4, 2, 0, -2, -4},
+	DefaultTXPower: 10,
+	MaxPayloadSize: []int{59, 123, 248, 248, 248, 248, 248, 248},
+	ADR:            &ADRConfig{MinDataRate: 0, MaxDataRate: 7, MinTXPower: -4, MaxTXPower: 10},
+}
@@ -0,0 +1,44 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+import (
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	lora "github.com/brocaar/lorawan/band"
+)
+
+// GetMaxPayloadSizeFor returns the maximum MACPayload size (M) and the maximum
+// application payload size (N) for the given LoRa data rate
+func (f *FrequencyPlan) GetMaxPayloadSizeFor(dataRate string, repeaterCompatible bool) (size lora.MaxPayloadSize, err error) {
+	drIdx, err := f.GetDataRateIndexFor(dataRate)
+	if err != nil {
+		return size, err
+	}
+	sizes := f.MaxPayloadSize
+	if repeaterCompatible && len(f.RepeaterMaxPayloadSize) > 0 {
+		sizes = f.RepeaterMaxPayloadSize
+	}
+	if drIdx >= len(sizes) {
+		return size, errors.NewErrNotFound(fmt.Sprintf("max payload size for %s", dataRate))
+	}
+	return sizes[drIdx], nil
+}
+
+// CheckMaxPayloadSize returns an error if a MACPayload of macPayloadSize bytes
+// exceeds the repeater-compatible maximum of the given LoRa data rate
+func (f *FrequencyPlan) CheckMaxPayloadSize(dataRate string, macPayloadSize int) error {
+	size, err := f.GetMaxPayloadSizeFor(dataRate, true)
+	if err != nil {
+		return err
+	}
+	if macPayloadSize > size.M {
+		return errors.NewErrInvalidArgument("Payload", fmt.Sprintf(
+			"%d bytes exceeds the maximum of %d bytes at %s",
+			macPayloadSize, size.M, dataRate,
+		))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestMaxPayloadSize(t *testing.T) {
+	a := New(t)
+
+	eu, _ := Get("EU_863_870")
+
+	size, err := eu.GetMaxPayloadSizeFor("SF7BW125", false)
+	a.So(err, ShouldBeNil)
+	a.So(size.N, ShouldEqual, 242)
+
+	size, err = eu.GetMaxPayloadSizeFor("SF7BW125", true)
+	a.So(err, ShouldBeNil)
+	a.So(size.N, ShouldEqual, 222)
+
+	size, err = eu.GetMaxPayloadSizeFor("SF12BW125", true)
+	a.So(err, ShouldBeNil)
+	a.So(size.M, ShouldEqual, 59)
+
+	_, err = eu.GetMaxPayloadSizeFor("SF13BW125", true)
+	a.So(err, ShouldNotBeNil)
+
+	a.So(eu.CheckMaxPayloadSize("SF12BW125", 59), ShouldBeNil)
+	a.So(eu.CheckMaxPayloadSize("SF12BW125", 60), ShouldNotBeNil)
+}
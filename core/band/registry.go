@@ -0,0 +1,39 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+import (
+	"sort"
+	"sync"
+)
+
+// Builder builds the frequency plan of a region
+type Builder func() (FrequencyPlan, error)
+
+var registry = struct {
+	sync.RWMutex
+	builders map[string]Builder
+}{
+	builders: make(map[string]Builder),
+}
+
+// Register the builder for the given region. Registering a region that was
+// already registered replaces the existing builder.
+func Register(region string, builder Builder) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.builders[region] = builder
+}
+
+// Regions returns the names of all registered regions
+func Regions() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	regions := make([]string, 0, len(registry.builders))
+	for region := range registry.builders {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
@@ -0,0 +1,100 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package band
+
+// Regions that are defined by the LoRaWAN Regional Parameters (RP002), but
+// that are not available in the lorawan/band package
+const (
+	IN_865_867      = "IN_865_867"
+	RU_864_870      = "RU_864_870"
+	KZ_865_868      = "KZ_865_868"
+	CN_470_510_20_A = "CN_470_510_20_A"
+	CN_470_510_20_B = "CN_470_510_20_B"
+)
+
+var loraDataRates = []string{"SF12BW125", "SF11BW125", "SF10BW125", "SF9BW125", "SF8BW125", "SF7BW125"}
+
+// cn470Channels returns 64 channels of 200kHz: channels 0-31 start at the first
+// frequency and channels 32-63 at the second frequency
+func cn470Channels(first, second int) []ChannelDefinition {
+	channels := make([]ChannelDefinition, 0, 64)
+	for _, start := range []int{first, second} {
+		for i := 0; i < 32; i++ {
+			channels = append(channels, ChannelDefinition{Frequency: start + i*200000, DataRates: []int{0, 1, 2, 3, 4, 5}})
+		}
+	}
+	return channels
+}
+
+var rp002Definitions = []Definition{
+	{
+		Name:      IN_865_867,
+		Base:      "EU_863_870",
+		DataRates: append(loraDataRates, "", "50000"),
+		UplinkChannels: []ChannelDefinition{
+			{Frequency: 865062500, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			{Frequency: 865402500, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			{Frequency: 865985000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+		},
+		RX2Frequency:   866550000,
+		RX2DataRate:    intPtr(2),
+		TXPower:        []int{30, 28, 26, 24, 22, 20, 18, 16, 14, 12, 10},
+		DefaultTXPower: 30,
+		ADR:            &ADRConfig{MinDataRate: 0, MaxDataRate: 5, MinTXPower: 10, MaxTXPower: 30},
+	},
+	{
+		Name:      RU_864_870,
+		Base:      "EU_863_870",
+		DataRates: append(loraDataRates, "SF7BW250", "50000"),
+		UplinkChannels: []ChannelDefinition{
+			{Frequency: 868900000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			{Frequency: 869100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+		},
+		RX2Frequency:   869100000,
+		RX2DataRate:    intPtr(0),
+		TXPower:        []int{16, 14, 12, 10, 8, 6, 4, 2},
+		DefaultTXPower: 16,
+		ADR:            &ADRConfig{MinDataRate: 0, MaxDataRate: 5, MinTXPower: 2, MaxTXPower: 16},
+	},
+	{
+		Name:      KZ_865_868,
+		Base:      "EU_863_870",
+		DataRates: append(loraDataRates, "SF7BW250", "50000"),
+		UplinkChannels: []ChannelDefinition{
+			{Frequency: 865100000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			{Frequency: 865300000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+			{Frequency: 865500000, DataRates: []int{0, 1, 2, 3, 4, 5}},
+		},
+		RX2Frequency:   866700000,
+		RX2DataRate:    intPtr(0),
+		TXPower:        []int{16, 14, 12, 10, 8, 6, 4, 2},
+		DefaultTXPower: 16,
+		ADR:            &ADRConfig{MinDataRate: 0, MaxDataRate: 5, MinTXPower: 2, MaxTXPower: 16},
+	},
+	{
+		Name:             CN_470_510_20_A,
+		Base:             "CN_470_510",
+		DataRates:        append(loraDataRates, "SF7BW500", "50000"),
+		UplinkChannels:   cn470Channels(470300000, 503500000),
+		DownlinkChannels: cn470Channels(483900000, 490300000),
+		RX1:              RX1SameChannel,
+		RX2Frequency:     485300000,
+		RX2DataRate:      intPtr(1),
+		TXPower:          []int{19, 17, 15, 13, 11, 9, 7, 5},
+		DefaultTXPower:   19,
+	},
+	{
+		// The downlink channels of plan B are the same as the uplink channels
+		Name:             CN_470_510_20_B,
+		Base:             "CN_470_510",
+		DataRates:        append(loraDataRates, "SF7BW500", "50000"),
+		UplinkChannels:   cn470Channels(476900000, 496900000),
+		DownlinkChannels: cn470Channels(476900000, 496900000),
+		RX1:              RX1SameChannel,
+		RX2Frequency:     486900000,
+		RX2DataRate:      intPtr(1),
+		TXPower:          []int{19, 17, 15, 13, 11, 9, 7, 5},
+		DefaultTXPower:   19,
+	},
+}
@@ -16,8 +16,12 @@ import (
 	"github.com/TheThingsNetwork/ttn/api/fields"
 	pb_handler "github.com/TheThingsNetwork/ttn/api/handler"
 	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/kek"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/fault"
 	"github.com/brocaar/lorawan"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 type challengeResponseWithHandler struct {
@@ -28,6 +32,8 @@ type challengeResponseWithHandler struct {
 
 var errDuplicateActivation = errors.New("Not handling duplicate activation on this gateway")
 
+var errGatewayJoinRate = errors.New("Join rate of gateways exceeded")
+
 func (b *broker) HandleActivation(activation *pb.DeviceActivationRequest) (res *pb.DeviceActivationResponse, err error) {
 	ctx := b.Ctx.WithFields(fields.Get(activation))
 	start := time.Now()
@@ -56,6 +62,17 @@ func (b *broker) HandleActivation(activation *pb.DeviceActivationRequest) (res *
 	}
 	ctx = ctx.WithField("Duplicates", len(duplicates))
 
+	// Throttle join requests per gateway and per device
+	duplicates = b.joinThrottle.filterGateways(duplicates)
+	if len(duplicates) == 0 {
+		return nil, errGatewayJoinRate
+	}
+	if duplicates[0].DevEui != nil {
+		if err = b.joinThrottle.checkDevice(*duplicates[0].DevEui, start); err != nil {
+			return nil, err
+		}
+	}
+
 	b.status.activationsUnique.Mark(1)
 
 	deduplicatedActivationRequest.Payload = duplicates[0].Payload
@@ -87,6 +104,9 @@ func (b *broker) HandleActivation(activation *pb.DeviceActivationRequest) (res *
 	}
 
 	// Send Activate to NS
+	if err = fault.Inject(fault.BrokerToNetworkServer); err != nil {
+		return nil, errors.Wrap(err, "NetworkServer refused to prepare activation")
+	}
 	deduplicatedActivationRequest, err = b.ns.PrepareActivation(b.Component.GetContext(b.nsToken), deduplicatedActivationRequest)
 	if err != nil {
 		return nil, errors.Wrap(errors.FromGRPCError(err), "NetworkServer refused to prepare activation")
@@ -97,12 +117,19 @@ func (b *broker) HandleActivation(activation *pb.DeviceActivationRequest) (res *
 		"DevID": deduplicatedActivationRequest.DevId,
 	})
 
+	// Now that the device is known, apply its gateway group preferences
+	if template := deduplicatedActivationRequest.ResponseTemplate; template != nil && len(downlinkOptions) > 0 {
+		template.DownlinkOption = b.selectDownlink(deduplicatedActivationRequest.AppId, deduplicatedActivationRequest.DevId, deduplicatedActivationRequest.GatewayGroups, downlinkOptions)
+	}
+
 	// Find Handler (based on AppEUI)
 	var announcements []*pb_discovery.Announcement
-	announcements, err = b.Discovery.GetAllHandlersForAppID(deduplicatedActivationRequest.AppId)
+	announcements, err = b.getHandlersForAppID(deduplicatedActivationRequest.AppId)
 	if err != nil {
 		return nil, err
 	}
+	announcements = b.primaryHandlers(deduplicatedActivationRequest.AppEui, announcements)
+	announcements = b.migrationHandlers(deduplicatedActivationRequest.AppId, deduplicatedActivationRequest.DevId, announcements)
 	if len(announcements) == 0 {
 		return nil, errors.NewErrNotFound(fmt.Sprintf("Handler for AppID %s", deduplicatedActivationRequest.AppId))
 	}
@@ -191,25 +218,50 @@ func (b *broker) HandleActivation(activation *pb.DeviceActivationRequest) (res *
 		return nil, errors.New("Activation not accepted by any Handler")
 	}
 
+	// The join request that exceeds the rate of the device is sent to the
+	// Handler to report the join loop to the application
+	if devEUI := deduplicatedActivationRequest.DevEui; devEUI != nil {
+		if penalty := b.joinThrottle.countDevice(*devEUI, start); penalty > 0 {
+			ctx.WithField("Penalty", penalty).Warn("Device exceeded its join rate")
+			deduplicatedActivationRequest.JoinLoop = &pb.JoinLoop{Penalty: penalty.Nanoseconds()}
+		}
+	}
+
 	ctx.WithField("HandlerID", joinHandler.Id).Debug("Forward Activation")
 	deduplicatedActivationRequest.Trace = deduplicatedActivationRequest.Trace.WithEvent(trace.ForwardEvent,
 		"handler", joinHandler.Id,
 	)
 
-	handlerResponse, err := joinHandlerClient.Activate(b.Component.GetContext(""), deduplicatedActivationRequest)
+	var handlerHeader metadata.MD
+	handlerResponse, err := joinHandlerClient.Activate(b.Component.GetContext(""), deduplicatedActivationRequest, grpc.Header(&handlerHeader))
 	if err != nil {
 		return nil, errors.Wrap(errors.FromGRPCError(err), "Handler refused activation")
 	}
 
 	handlerResponse.Trace = handlerResponse.Trace.WithEvent(trace.ReceiveEvent)
 
-	handlerResponse, err = b.ns.Activate(b.Component.GetContext(b.nsToken), handlerResponse)
+	// Forward the NwkSKey that the Handler wrapped with the KEK of the NetworkServer
+	nsCtx := b.Component.GetContext(b.nsToken)
+	nwkSKey, err := kek.FromMetadata(handlerHeader, kek.NwkSKey)
+	if err != nil {
+		return nil, err
+	}
+	if nwkSKey != nil {
+		nsCtx = nwkSKey.AppendToContext(nsCtx, kek.NwkSKey)
+	}
+
+	handlerResponse, err = b.ns.Activate(nsCtx, handlerResponse)
 	if err != nil {
 		return nil, errors.Wrap(errors.FromGRPCError(err), "NetworkServer refused activation")
 	}
 
 	handlerResponse.Trace = handlerResponse.Trace.WithEvent(trace.ForwardEvent)
 
+	// The devices that were cached for the new DevAddr are outdated
+	if activationMetadata := handlerResponse.GetActivationMetadata().GetLorawan(); activationMetadata != nil && activationMetadata.DevAddr != nil {
+		b.micCheck.forgetDevices(*activationMetadata.DevAddr)
+	}
+
 	res = &pb.DeviceActivationResponse{
 		Payload:        handlerResponse.Payload,
 		Message:        handlerResponse.Message,
@@ -0,0 +1,43 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/alerting"
+)
+
+// SetDeduplicationAlert makes the Broker alert the operators when more than
+// the threshold of uplink messages arrive after their deduplication window
+// within the check interval. The alert is disabled if the threshold is 0.
+func (b *broker) SetDeduplicationAlert(threshold uint64) {
+	b.deduplicationAlert = threshold
+}
+
+// checkDeduplicationLate fires the deduplication alert if the number of late
+// uplink messages since the previous check exceeds the threshold, and returns
+// the current number of late uplink messages
+func (b *broker) checkDeduplicationLate(previous uint64) uint64 {
+	late := b.uplinkDeduplicator.Late()
+	if spike := late - previous; spike > b.deduplicationAlert {
+		b.Alerts.Update(alerting.DeduplicationLate, b.Identity.Id, true, fmt.Sprintf("%d uplink messages arrived after their deduplication window", spike))
+	} else {
+		b.Alerts.Update(alerting.DeduplicationLate, b.Identity.Id, false, fmt.Sprintf("%d uplink messages arrived after their deduplication window", spike))
+	}
+	return late
+}
+
+func (b *broker) handleDeduplicationAlerts() {
+	if b.deduplicationAlert == 0 {
+		return
+	}
+	go func() {
+		late := b.uplinkDeduplicator.Late()
+		for range time.Tick(alerting.CheckInterval) {
+			late = b.checkDeduplicationLate(late)
+		}
+	}()
+}
@@ -0,0 +1,50 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/alerting"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+type lateDeduplicator struct {
+	Deduplicator
+	late uint64
+}
+
+func (d *lateDeduplicator) Late() uint64 {
+	return d.late
+}
+
+func TestCheckDeduplicationLate(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	defer b.ctrl.Finish()
+	b.Identity = &pb_discovery.Announcement{Id: "broker"}
+	b.Alerts = alerting.NewManager(GetLogger(t, "TestCheckDeduplicationLate"), "broker")
+	b.SetDeduplicationAlert(10)
+	deduplicator := &lateDeduplicator{Deduplicator: b.uplinkDeduplicator}
+	b.uplinkDeduplicator = deduplicator
+
+	deduplicator.late = 5
+	late := b.checkDeduplicationLate(0)
+	a.So(late, ShouldEqual, 5)
+	a.So(b.Alerts.Firing(), ShouldBeEmpty)
+
+	deduplicator.late = 50
+	late = b.checkDeduplicationLate(late)
+	firing := b.Alerts.Firing()
+	a.So(firing, ShouldHaveLength, 1)
+	a.So(firing[0].Kind, ShouldEqual, alerting.DeduplicationLate)
+	a.So(firing[0].Message, ShouldEqual, "45 uplink messages arrived after their deduplication window")
+
+	deduplicator.late = 52
+	b.checkDeduplicationLate(late)
+	a.So(b.Alerts.Firing(), ShouldBeEmpty)
+}
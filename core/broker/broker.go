@@ -5,6 +5,7 @@ package broker
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/TheThingsNetwork/ttn/core/component"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/bluele/gcache"
 	"google.golang.org/grpc"
 )
 
@@ -24,11 +26,27 @@ type Broker interface {
 	component.ManagementInterface
 
 	SetNetworkServer(addr, cert, token string)
+	SetPeering(exporter PeeringExporter, config PeeringConfig)
+	SetMICCheck(options MICCheckOptions)
+	SetTenants(tenants types.Tenants)
+	SetShadowHandlers(shadowHandlers ShadowHandlers)
+	SetDeduplicationAlert(threshold uint64)
+	SetHandlerReplay(size int, age time.Duration)
+	SetJoinRateLimits(limits JoinRateLimits)
+	SetInvalidMICRate(rate int)
+	SetRoutingTable(enabled bool)
+	SetGatewayGroups(groups GatewayGroups) error
+	GetRejectedUplinks() RejectedUplinks
 
 	HandleUplink(uplink *pb.UplinkMessage) error
 	HandleDownlink(downlink *pb.DownlinkMessage) error
 	HandleActivation(activation *pb.DeviceActivationRequest) (*pb.DeviceActivationResponse, error)
 
+	SetMigration(handlerID string, migration *types.Migration) error
+	DeleteMigration(handlerID string, appID string) error
+	GetMigration(appID string) (*types.Migration, error)
+	HTTPHandler() http.Handler
+
 	ActivateRouter(id string) (<-chan *pb.DownlinkMessage, error)
 	DeactivateRouter(id string) error
 	ActivateHandlerUplink(id string) (<-chan *pb.DeduplicatedUplinkMessage, error)
@@ -39,8 +57,9 @@ func NewBroker(timeout time.Duration) Broker {
 	return &broker{
 		routers:                make(map[string]chan *pb.DownlinkMessage),
 		handlers:               make(map[string]*handler),
-		uplinkDeduplicator:     NewDeduplicator(timeout),
-		activationDeduplicator: NewDeduplicator(timeout),
+		uplinkDeduplicator:     NewShardedDeduplicator(timeout, DeduplicatorShards),
+		activationDeduplicator: NewShardedDeduplicator(timeout, DeduplicatorShards),
+		relayDownlinks:         newRelayDownlinks(),
 	}
 }
 
@@ -64,6 +83,19 @@ type broker struct {
 	uplinkDeduplicator     Deduplicator
 	activationDeduplicator Deduplicator
 	status                 *status
+	peering                *peering
+	micCheck               *micCheck
+	tenants                types.Tenants
+	shadowHandlers         ShadowHandlers
+	migrations             migrations
+	deduplicationAlert     uint64
+	replaySize             int
+	replayAge              time.Duration
+	joinThrottle           *joinThrottle
+	uplinkGuard            *uplinkGuard
+	routing                *routingTable
+	gatewayGroups          *gatewayGroups
+	relayDownlinks         gcache.Cache // DownlinkOption Identifier -> *pb_lorawan.Device (relay)
 }
 
 func (b *broker) checkPrefixAnnouncements() error {
@@ -117,7 +149,10 @@ func (b *broker) Init(c *component.Component) error {
 	if err != nil {
 		return err
 	}
-	b.Discovery.GetAll("handler") // Update cache
+	handlers, err := b.Discovery.GetAll("handler") // Update cache
+	if err == nil {
+		b.loadMigrations(handlers)
+	}
 	var conn *grpc.ClientConn
 	if b.nsCert == "" {
 		conn, err = api.Dial(b.nsAddr)
@@ -130,12 +165,18 @@ func (b *broker) Init(c *component.Component) error {
 	b.nsConn = conn
 	b.ns = networkserver.NewNetworkServerClient(conn)
 	b.checkPrefixAnnouncements()
+	b.handleDeduplicationAlerts()
+	b.watchRoutingChanges()
 	b.Component.SetStatus(component.StatusHealthy)
 
 	return nil
 }
 
-func (b *broker) Shutdown() {}
+func (b *broker) Shutdown() {
+	if b.peering != nil {
+		b.peering.stop()
+	}
+}
 
 func (b *broker) ActivateRouter(id string) (<-chan *pb.DownlinkMessage, error) {
 	b.routersLock.Lock()
@@ -170,6 +211,7 @@ func (b *broker) getRouter(id string) (chan<- *pb.DownlinkMessage, error) {
 type handler struct {
 	conn   *grpc.ClientConn
 	uplink chan *pb.DeduplicatedUplinkMessage
+	replay *replayBuffer
 	sync.Mutex
 }
 
@@ -216,6 +258,24 @@ func (b *broker) getHandlerUplink(id string) (chan<- *pb.DeduplicatedUplinkMessa
 	return hdl.uplink, nil
 }
 
+// sendHandlerUplink sends the uplink message to the Handler, or keeps it for
+// replay if the Handler is not subscribed
+func (b *broker) sendHandlerUplink(id string, uplink *pb.DeduplicatedUplinkMessage) error {
+	hdl := b.getHandler(id)
+	hdl.Lock()
+	if hdl.uplink == nil {
+		defer hdl.Unlock()
+		if b.bufferHandlerUplink(hdl, uplink) {
+			return nil
+		}
+		return errors.NewErrInternal(fmt.Sprintf("Handler %s not active", id))
+	}
+	ch := hdl.uplink
+	hdl.Unlock()
+	ch <- uplink
+	return nil
+}
+
 func (b *broker) getHandlerConn(id string) (*grpc.ClientConn, error) {
 	hdl := b.getHandler(id)
 	hdl.Lock()
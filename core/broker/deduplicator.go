@@ -4,13 +4,16 @@
 package broker
 
 import (
+	"hash/fnv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type collection struct {
 	sync.Mutex
 	ready  chan bool
+	closed bool
 	values []interface{}
 }
 
@@ -36,21 +39,33 @@ func (c *collection) GetAndClear() []interface{} {
 }
 
 func (c *collection) done() {
+	c.Lock()
+	c.closed = true
+	c.Unlock()
 	c.ready <- true
 }
 
+func (c *collection) isClosed() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.closed
+}
+
 func (c *collection) wait() {
 	<-c.ready
 }
 
 type Deduplicator interface {
 	Deduplicate(key string, value interface{}) []interface{}
+	// Late returns the number of values that arrived after their deduplication window
+	Late() uint64
 }
 
 type deduplicator struct {
 	sync.Mutex
 	timeout     time.Duration
 	collections map[string]*collection
+	late        uint64
 }
 
 func (d *deduplicator) add(key string, value interface{}) (c *collection, isFirst bool) {
@@ -58,6 +73,9 @@ func (d *deduplicator) add(key string, value interface{}) (c *collection, isFirs
 	defer d.Unlock()
 	var ok bool
 	if c, ok = d.collections[key]; ok {
+		if c.isClosed() {
+			atomic.AddUint64(&d.late, 1)
+		}
 		c.Add(value)
 	} else {
 		isFirst = true
@@ -68,26 +86,74 @@ func (d *deduplicator) add(key string, value interface{}) (c *collection, isFirs
 	return
 }
 
+func (d *deduplicator) remove(key string) {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.collections, key)
+}
+
 func (d *deduplicator) Deduplicate(key string, value interface{}) (values []interface{}) {
 	collection, isFirst := d.add(key, value)
 	if isFirst {
-		go func() {
-			<-time.After(d.timeout)
+		// Timers are used instead of a goroutine per key, because at high
+		// message rates the goroutines dominate the memory and scheduler load
+		time.AfterFunc(d.timeout, func() {
 			collection.done()
-			<-time.After(d.timeout)
-			d.Lock()
-			defer d.Unlock()
-			delete(d.collections, key)
-		}()
+			time.AfterFunc(d.timeout, func() { d.remove(key) })
+		})
 		collection.wait()
 		values = collection.GetAndClear()
 	}
 	return
 }
 
+func (d *deduplicator) Late() uint64 {
+	return atomic.LoadUint64(&d.late)
+}
+
 func NewDeduplicator(timeout time.Duration) Deduplicator {
 	return &deduplicator{
 		timeout:     timeout,
 		collections: map[string]*collection{},
 	}
 }
+
+// DeduplicatorShards is the number of shards of the deduplicators of the Broker
+var DeduplicatorShards = 64
+
+// shardedDeduplicator spreads the keys over multiple deduplicators, so that
+// concurrent messages with different keys do not contend for the same lock
+type shardedDeduplicator struct {
+	shards []*deduplicator
+}
+
+// NewShardedDeduplicator returns a Deduplicator that spreads its keys over the number of shards
+func NewShardedDeduplicator(timeout time.Duration, shards int) Deduplicator {
+	if shards < 1 {
+		shards = 1
+	}
+	d := &shardedDeduplicator{
+		shards: make([]*deduplicator, shards),
+	}
+	for i := range d.shards {
+		d.shards[i] = NewDeduplicator(timeout).(*deduplicator)
+	}
+	return d
+}
+
+func (d *shardedDeduplicator) shard(key string) *deduplicator {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return d.shards[h.Sum32()%uint32(len(d.shards))]
+}
+
+func (d *shardedDeduplicator) Deduplicate(key string, value interface{}) []interface{} {
+	return d.shard(key).Deduplicate(key, value)
+}
+
+func (d *shardedDeduplicator) Late() (late uint64) {
+	for _, shard := range d.shards {
+		late += shard.Late()
+	}
+	return
+}
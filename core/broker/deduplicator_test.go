@@ -4,7 +4,9 @@
 package broker
 
 import (
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -68,3 +70,67 @@ func TestDeduplicatorDeduplicate(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestDeduplicatorLate(t *testing.T) {
+	a := New(t)
+	d := NewDeduplicator(10 * time.Millisecond).(*deduplicator)
+
+	a.So(d.Deduplicate("key", "value1"), ShouldResemble, []interface{}{"value1"})
+	a.So(d.Late(), ShouldEqual, 0)
+
+	// The collection is kept for another timeout to detect late values
+	a.So(d.Deduplicate("key", "value2"), ShouldBeNil)
+	a.So(d.Late(), ShouldEqual, 1)
+}
+
+func TestShardedDeduplicator(t *testing.T) {
+	a := New(t)
+	d := NewShardedDeduplicator(10*time.Millisecond, 4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			res := d.Deduplicate(key, key+"-1")
+			a.So(res, ShouldResemble, []interface{}{key + "-1", key + "-2"})
+		}(strconv.Itoa(i))
+	}
+
+	<-time.After(5 * time.Millisecond)
+	for i := 0; i < 8; i++ {
+		key := strconv.Itoa(i)
+		a.So(d.Deduplicate(key, key+"-2"), ShouldBeNil)
+	}
+	wg.Wait()
+
+	a.So(d.Deduplicate("0", "0-3"), ShouldBeNil)
+	a.So(d.Deduplicate("1", "1-3"), ShouldBeNil)
+	a.So(d.Late(), ShouldEqual, 2)
+}
+
+// benchmarkDeduplicator deduplicates messages that are each received by 8 gateways
+func benchmarkDeduplicator(b *testing.B, d Deduplicator) {
+	const copies = 8
+	var counter uint64
+	b.SetParallelism(copies * 16)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := atomic.AddUint64(&counter, 1)
+			d.Deduplicate(strconv.FormatUint(n/copies, 10), n)
+		}
+	})
+}
+
+func BenchmarkDeduplicator(b *testing.B) {
+	benchmarkDeduplicator(b, NewDeduplicator(time.Millisecond))
+}
+
+func BenchmarkShardedDeduplicator(b *testing.B) {
+	for _, shards := range []int{1, 16, DeduplicatorShards} {
+		b.Run(strconv.Itoa(shards), func(b *testing.B) {
+			benchmarkDeduplicator(b, NewShardedDeduplicator(time.Millisecond, shards))
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	pb "github.com/TheThingsNetwork/ttn/api/broker"
 	"github.com/TheThingsNetwork/ttn/api/fields"
 	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 )
 
@@ -42,9 +43,30 @@ func (b *broker) HandleDownlink(downlink *pb.DownlinkMessage) error {
 
 	downlink.Trace = downlink.Trace.WithEvent(trace.ReceiveEvent)
 
+	appID, devID := downlink.AppId, downlink.DevId
+	gatewayID := downlink.GetDownlinkOption().GetGatewayId()
+
 	downlink, err = b.ns.Downlink(b.Component.GetContext(b.nsToken), downlink)
 	if err != nil {
-		return errors.Wrap(errors.FromGRPCError(err), "NetworkServer did not handle downlink")
+		err = errors.Wrap(errors.FromGRPCError(err), "NetworkServer did not handle downlink")
+		b.reportDownlinkFailure(appID, devID, gatewayID, types.DownlinkFailureNetworkServer, err)
+		return err
+	}
+
+	// Downlinks for end devices behind a relay are sent in a downlink to the relay
+	relayDownlink, err := b.relayDownlink(downlink)
+	if err != nil {
+		b.reportDownlinkFailure(appID, devID, gatewayID, types.DownlinkFailureTooLate, err)
+		return err
+	}
+	if relayDownlink != nil {
+		ctx = ctx.WithField("Relay", relayDownlink.DevId)
+		downlink, err = b.ns.Downlink(b.Component.GetContext(b.nsToken), relayDownlink)
+		if err != nil {
+			err = errors.Wrap(errors.FromGRPCError(err), "NetworkServer did not handle downlink for relay")
+			b.reportDownlinkFailure(appID, devID, gatewayID, types.DownlinkFailureNetworkServer, err)
+			return err
+		}
 	}
 
 	var routerID string
@@ -57,6 +79,7 @@ func (b *broker) HandleDownlink(downlink *pb.DownlinkMessage) error {
 
 	router, err := b.getRouter(routerID)
 	if err != nil {
+		b.reportDownlinkFailure(appID, devID, gatewayID, types.DownlinkFailureNoGateway, err)
 		return err
 	}
 
@@ -66,3 +89,19 @@ func (b *broker) HandleDownlink(downlink *pb.DownlinkMessage) error {
 
 	return nil
 }
+
+// reportDownlinkFailure reports the downlink failure to the Handler of the application
+func (b *broker) reportDownlinkFailure(appID, devID, gatewayID string, reason types.DownlinkFailureReason, err error) {
+	failure := types.DownlinkFailure{
+		AppID:     appID,
+		DevID:     devID,
+		Reason:    reason,
+		Error:     err.Error(),
+		GatewayID: gatewayID,
+	}
+	go func() {
+		if err := b.ReportDownlinkFailure(failure); err != nil {
+			b.Ctx.WithError(err).WithField("AppID", appID).WithField("DevID", devID).Warn("Could not report downlink failure")
+		}
+	}()
+}
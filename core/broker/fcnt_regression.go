@@ -0,0 +1,25 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+)
+
+// handleFCntRegression forwards an uplink with a frame counter that is lower
+// than the last frame counter of the device to the Handler, which counts it as
+// evidence that multiple devices use the same ABP credentials. The uplink does
+// not go through the NetworkServer and the Handler does not process its payload.
+func (b *broker) handleFCntRegression(ctx ttnlog.Interface, device *pb_lorawan.Device, uplink *pb.DeduplicatedUplinkMessage, duplicates []*pb.UplinkMessage, fCnt uint32) {
+	uplink.GatewayMetadata = gatewayMetadata(duplicates)
+	uplink.FcntRegression = &pb.FCntRegression{
+		FCnt:     fCnt,
+		LastFCnt: device.FCntUp,
+	}
+	if err := b.forwardUplink(device.AppId, uplink); err != nil {
+		ctx.WithError(err).Debug("Could not forward frame counter regression to Handler")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// GatewayGroups groups gateways (for example per site) and contains the
+// default preferences of applications and devices for the groups of the
+// gateways that send their downlink. Applications and devices set their own
+// preferences in the NetworkServer, and these take precedence.
+type GatewayGroups struct {
+	// Groups maps the name of a group to the IDs of its gateways
+	Groups map[string][]string `yaml:"groups"`
+	// Preferences maps an AppID or AppID/DevID to group names, most preferred first
+	Preferences map[string][]string `yaml:"preferences"`
+}
+
+// LoadGatewayGroupsFile loads the gateway groups from a YAML file
+func LoadGatewayGroupsFile(filename string) (GatewayGroups, error) {
+	var groups GatewayGroups
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return groups, err
+	}
+	if err := yaml.Unmarshal(contents, &groups); err != nil {
+		return groups, err
+	}
+	return groups, nil
+}
+
+type gatewayGroups struct {
+	members     map[string]map[string]bool // group name -> gateway IDs
+	preferences map[string][]string
+}
+
+// SetGatewayGroups sets the gateway groups and the downlink preferences of
+// applications and devices. If the gateways of a preferred group received an
+// uplink message, the downlink is sent through the best of them, otherwise the
+// best gateway overall is selected.
+func (b *broker) SetGatewayGroups(groups GatewayGroups) error {
+	members := make(map[string]map[string]bool, len(groups.Groups))
+	for name, gatewayIDs := range groups.Groups {
+		members[name] = make(map[string]bool, len(gatewayIDs))
+		for _, gatewayID := range gatewayIDs {
+			members[name][gatewayID] = true
+		}
+	}
+	for target, preference := range groups.Preferences {
+		for _, name := range preference {
+			if _, ok := members[name]; !ok {
+				return errors.NewErrInvalidArgument("Gateway Groups", fmt.Sprintf("preference of %s contains unknown group %s", target, name))
+			}
+		}
+	}
+	b.gatewayGroups = &gatewayGroups{
+		members:     members,
+		preferences: groups.Preferences,
+	}
+	return nil
+}
+
+// preferredGroups returns the preferred groups of the device, or those of its application
+func (g *gatewayGroups) preferredGroups(appID, devID string) []string {
+	if preference, ok := g.preferences[appID+"/"+devID]; ok {
+		return preference
+	}
+	return g.preferences[appID]
+}
+
+// selectDownlink selects the best DownlinkOption of the most preferred gateway
+// group of the device, falling back to the best DownlinkOption overall. The
+// preferences that the NetworkServer returned for the device take precedence
+// over those of the configuration of the Broker. Unknown groups are skipped.
+func (b *broker) selectDownlink(appID, devID string, preferences []string, options []*pb.DownlinkOption) *pb.DownlinkOption {
+	if b.gatewayGroups != nil {
+		if len(preferences) == 0 {
+			preferences = b.gatewayGroups.preferredGroups(appID, devID)
+		}
+		for _, name := range preferences {
+			var inGroup []*pb.DownlinkOption
+			for _, option := range options {
+				if b.gatewayGroups.members[name][option.GatewayId] {
+					inGroup = append(inGroup, option)
+				}
+			}
+			if len(inGroup) > 0 {
+				return selectBestDownlink(inGroup)
+			}
+		}
+	}
+	return selectBestDownlink(options)
+}
@@ -0,0 +1,85 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestLoadGatewayGroupsFile(t *testing.T) {
+	a := New(t)
+
+	file, err := ioutil.TempFile("", "gateway-groups")
+	a.So(err, ShouldBeNil)
+	defer os.Remove(file.Name())
+	file.WriteString("groups:\n  site-a: [gtw-1, gtw-2]\npreferences:\n  app/dev: [site-a]\n")
+	file.Close()
+
+	groups, err := LoadGatewayGroupsFile(file.Name())
+	a.So(err, ShouldBeNil)
+	a.So(groups.Groups["site-a"], ShouldResemble, []string{"gtw-1", "gtw-2"})
+	a.So(groups.Preferences["app/dev"], ShouldResemble, []string{"site-a"})
+
+	_, err = LoadGatewayGroupsFile(file.Name() + "-missing")
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestSelectDownlink(t *testing.T) {
+	a := New(t)
+
+	b := &broker{}
+
+	options := []*pb.DownlinkOption{
+		{Identifier: "1", GatewayId: "gtw-1", Score: 10},
+		{Identifier: "2", GatewayId: "gtw-2", Score: 30},
+		{Identifier: "3", GatewayId: "gtw-3", Score: 20},
+		{Identifier: "4", GatewayId: "gtw-4", Score: 40},
+	}
+
+	// Without groups
+	a.So(b.selectDownlink("app", "dev", nil, options).Identifier, ShouldEqual, "1")
+
+	err := b.SetGatewayGroups(GatewayGroups{
+		Groups:      map[string][]string{"site-a": {"gtw-1"}},
+		Preferences: map[string][]string{"app": {"unknown"}},
+	})
+	a.So(err, ShouldNotBeNil)
+
+	err = b.SetGatewayGroups(GatewayGroups{
+		Groups: map[string][]string{
+			"site-a": {"gtw-1"},
+			"site-b": {"gtw-2", "gtw-4"},
+			"site-c": {"gtw-5"},
+		},
+		Preferences: map[string][]string{
+			"app":         {"site-b"},
+			"app/dev":     {"site-c", "site-a"},
+			"app/dev-all": {},
+		},
+	})
+	a.So(err, ShouldBeNil)
+
+	// Application preference
+	a.So(b.selectDownlink("app", "other-dev", nil, options).Identifier, ShouldEqual, "2")
+
+	// Device preference, falling back to the next group
+	a.So(b.selectDownlink("app", "dev", nil, options).Identifier, ShouldEqual, "1")
+
+	// Device without preferences
+	a.So(b.selectDownlink("app", "dev-all", nil, options).Identifier, ShouldEqual, "1")
+
+	// Without preferences
+	a.So(b.selectDownlink("other-app", "dev", nil, options).Identifier, ShouldEqual, "1")
+
+	// No gateways of the preferred group
+	a.So(b.selectDownlink("app", "other-dev", nil, options[:1]).Identifier, ShouldEqual, "1")
+
+	// Preferences from the NetworkServer take precedence, unknown groups are skipped
+	a.So(b.selectDownlink("app", "dev", []string{"unknown", "site-b"}, options).Identifier, ShouldEqual, "2")
+}
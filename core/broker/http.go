@@ -0,0 +1,119 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+type httpHandler struct {
+	broker *broker
+}
+
+// HTTPHandler returns an HTTP API for the Broker:
+//
+//	GET /applications/{app_id}/migration    returns the migration of an application to another Handler
+//	PUT /applications/{app_id}/migration    sets the migration of an application, called by the Handler of the application
+//	DELETE /applications/{app_id}/migration ends the migration of an application, called by the Handler of the application
+//
+// The Handlers authenticate with their component token.
+func (b *broker) HTTPHandler() http.Handler {
+	return &httpHandler{broker: b}
+}
+
+func (h *httpHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	path := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(path) != 3 || path[0] != "applications" || path[2] != "migration" {
+		http.NotFound(res, req)
+		return
+	}
+	appID := path[1]
+	switch req.Method {
+	case http.MethodGet:
+		migration, err := h.broker.GetMigration(appID)
+		h.write(res, migration, err)
+	case http.MethodPut:
+		migration, err := h.setMigration(req, appID)
+		h.write(res, migration, err)
+	case http.MethodDelete:
+		handlerID, err := h.handlerID(req)
+		if err == nil {
+			err = h.broker.DeleteMigration(handlerID, appID)
+		}
+		if err != nil {
+			h.write(res, nil, err)
+			return
+		}
+		res.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlerID validates the component token of the Handler that sent the request
+func (h *httpHandler) handlerID(req *http.Request) (string, error) {
+	var token string
+	if authorization := req.Header.Get("authorization"); len(authorization) >= 7 && strings.ToLower(authorization[0:7]) == "bearer " {
+		token = authorization[7:]
+	}
+	ctx := metadata.NewContext(context.Background(), metadata.Pairs(
+		"token", token,
+		"id", req.Header.Get("Grpc-Metadata-Id"),
+		"service-name", req.Header.Get("Grpc-Metadata-Service-Name"),
+	))
+	component, err := h.broker.ValidateNetworkContext(ctx)
+	if err != nil {
+		return "", errors.NewErrPermissionDenied(err.Error())
+	}
+	if component.ServiceName != "handler" {
+		return "", errors.NewErrPermissionDenied("Only Handlers can migrate applications")
+	}
+	return component.Id, nil
+}
+
+func (h *httpHandler) setMigration(req *http.Request, appID string) (*types.Migration, error) {
+	handlerID, err := h.handlerID(req)
+	if err != nil {
+		return nil, err
+	}
+	var migration types.Migration
+	if err := json.NewDecoder(req.Body).Decode(&migration); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	migration.AppID = appID
+	if err := h.broker.SetMigration(handlerID, &migration); err != nil {
+		return nil, err
+	}
+	return &migration, nil
+}
+
+func (h *httpHandler) write(res http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		if grpc.Code(err) != codes.Unknown {
+			err = errors.FromGRPCError(err)
+		}
+		code := http.StatusInternalServerError
+		switch errors.GetErrType(err) {
+		case errors.NotFound:
+			code = http.StatusNotFound
+		case errors.InvalidArgument:
+			code = http.StatusBadRequest
+		case errors.PermissionDenied:
+			code = http.StatusForbidden
+		}
+		http.Error(res, err.Error(), code)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(res).Encode(v)
+}
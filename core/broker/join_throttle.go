@@ -0,0 +1,136 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/api/ratelimit"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// JoinRateLimits configure the throttling of join requests before they are sent to the NetworkServer
+type JoinRateLimits struct {
+	// PerDevice is the number of join requests per minute that is accepted from a DevEUI (0 to disable)
+	PerDevice int
+	// PerGateway is the number of join requests per minute that is accepted through a gateway (0 to disable)
+	PerGateway int
+	// MaxPenalty is the maximum duration for which the join requests of a device that exceeds
+	// its rate are dropped. The penalty starts at JoinPenalty and doubles every time the device
+	// exceeds its rate again within MaxPenalty after the previous penalty.
+	MaxPenalty time.Duration
+}
+
+// JoinPenalty is the first penalty of a device that exceeds its join rate
+var JoinPenalty = time.Minute
+
+type joinPenalty struct {
+	until    time.Time
+	duration time.Duration
+}
+
+type joinThrottle struct {
+	devices    *ratelimit.Registry
+	gateways   *ratelimit.Registry
+	maxPenalty time.Duration
+
+	mu        sync.Mutex
+	penalties map[types.DevEUI]*joinPenalty
+}
+
+// SetJoinRateLimits configures the throttling of join requests per device and per gateway
+func (b *broker) SetJoinRateLimits(limits JoinRateLimits) {
+	if limits.PerDevice <= 0 && limits.PerGateway <= 0 {
+		b.joinThrottle = nil
+		return
+	}
+	throttle := &joinThrottle{
+		maxPenalty: limits.MaxPenalty,
+		penalties:  make(map[types.DevEUI]*joinPenalty),
+	}
+	if throttle.maxPenalty < JoinPenalty {
+		throttle.maxPenalty = JoinPenalty
+	}
+	if limits.PerDevice > 0 {
+		throttle.devices = ratelimit.NewRegistry(limits.PerDevice, time.Minute)
+	}
+	if limits.PerGateway > 0 {
+		throttle.gateways = ratelimit.NewRegistry(limits.PerGateway, time.Minute)
+	}
+	b.joinThrottle = throttle
+}
+
+// filterGateways returns the duplicates of a join request that were received
+// through gateways that did not exceed their rate
+func (t *joinThrottle) filterGateways(duplicates []*pb.DeviceActivationRequest) []*pb.DeviceActivationRequest {
+	if t == nil || t.gateways == nil {
+		return duplicates
+	}
+	filtered := duplicates[:0]
+	for _, duplicate := range duplicates {
+		if t.gateways.Limit(duplicate.GatewayMetadata.GetGatewayId()) {
+			continue
+		}
+		filtered = append(filtered, duplicate)
+	}
+	return filtered
+}
+
+// checkDevice returns an error if the join requests of the device are dropped
+// because of a penalty
+func (t *joinThrottle) checkDevice(devEUI types.DevEUI, now time.Time) error {
+	if t == nil || t.devices == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if penalty, ok := t.penalties[devEUI]; ok && now.Before(penalty.until) {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Join requests of device dropped until %s", penalty.until.UTC().Format(time.RFC3339)))
+	}
+	return nil
+}
+
+// countDevice counts a join request of the device of which a Handler validated
+// the MIC, so that join requests with a spoofed DevEUI do not penalize the
+// device. If the device exceeds its rate, it gets a penalty, which is returned
+// so that the join request that started it can be reported.
+func (t *joinThrottle) countDevice(devEUI types.DevEUI, now time.Time) time.Duration {
+	if t == nil || t.devices == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	penalty, ok := t.penalties[devEUI]
+	if ok && now.Sub(penalty.until) > t.maxPenalty {
+		delete(t.penalties, devEUI)
+		ok = false
+	}
+	if !t.devices.Limit(devEUI.String()) {
+		return 0
+	}
+	duration := JoinPenalty
+	if ok {
+		duration = penalty.duration * 2
+		if duration > t.maxPenalty {
+			duration = t.maxPenalty
+		}
+	}
+	t.prunePenalties(now)
+	t.penalties[devEUI] = &joinPenalty{until: now.Add(duration), duration: duration}
+	return duration
+}
+
+// prunePenalties forgets the penalties of devices that behaved for the
+// maximum penalty, so that the penalties do not grow without bound
+func (t *joinThrottle) prunePenalties(now time.Time) {
+	for devEUI, penalty := range t.penalties {
+		if now.Sub(penalty.until) > t.maxPenalty {
+			delete(t.penalties, devEUI)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestJoinThrottleDevice(t *testing.T) {
+	a := New(t)
+
+	b := &broker{}
+	b.SetJoinRateLimits(JoinRateLimits{})
+	a.So(b.joinThrottle, ShouldBeNil)
+	a.So(b.joinThrottle.checkDevice(types.DevEUI{1}, time.Now()), ShouldBeNil)
+	a.So(b.joinThrottle.countDevice(types.DevEUI{1}, time.Now()), ShouldEqual, 0)
+
+	b.SetJoinRateLimits(JoinRateLimits{PerDevice: 2, MaxPenalty: 3 * time.Minute})
+	now := time.Now()
+	dev := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+
+	// Join requests that were not validated are not counted
+	for i := 0; i < 5; i++ {
+		a.So(b.joinThrottle.checkDevice(dev, now), ShouldBeNil)
+	}
+
+	for i := 0; i < 2; i++ {
+		a.So(b.joinThrottle.countDevice(dev, now), ShouldEqual, 0)
+	}
+
+	// The device exceeds its rate
+	a.So(b.joinThrottle.countDevice(dev, now), ShouldEqual, time.Minute)
+
+	// Join requests are dropped during the penalty
+	a.So(b.joinThrottle.checkDevice(dev, now.Add(30*time.Second)), ShouldNotBeNil)
+	a.So(b.joinThrottle.checkDevice(dev, now.Add(2*time.Minute)), ShouldBeNil)
+
+	// Other devices are not affected
+	other := types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1}
+	a.So(b.joinThrottle.checkDevice(other, now), ShouldBeNil)
+	a.So(b.joinThrottle.countDevice(other, now), ShouldEqual, 0)
+
+	// The penalty doubles up to the maximum, the rate of the device is still exceeded
+	b.joinThrottle.penalties[dev].until = now
+	a.So(b.joinThrottle.countDevice(dev, now.Add(time.Second)), ShouldEqual, 2*time.Minute)
+	b.joinThrottle.penalties[dev].until = now
+	a.So(b.joinThrottle.countDevice(dev, now.Add(time.Second)), ShouldEqual, 3*time.Minute)
+
+	// The penalty is reset after the device behaved for the maximum penalty
+	b.joinThrottle.penalties[dev].until = now
+	a.So(b.joinThrottle.countDevice(dev, now.Add(4*time.Minute)), ShouldEqual, time.Minute)
+
+	// Penalties of devices that behaved for the maximum penalty are forgotten
+	b.joinThrottle.penalties[other] = &joinPenalty{until: now, duration: time.Minute}
+	b.joinThrottle.penalties[dev].until = now
+	b.joinThrottle.countDevice(dev, now.Add(4*time.Minute))
+	a.So(b.joinThrottle.penalties, ShouldHaveLength, 1)
+}
+
+func TestJoinThrottleGateways(t *testing.T) {
+	a := New(t)
+
+	b := &broker{}
+	b.SetJoinRateLimits(JoinRateLimits{PerGateway: 1})
+
+	request := func(gatewayID string) *pb.DeviceActivationRequest {
+		return &pb.DeviceActivationRequest{GatewayMetadata: &pb_gateway.RxMetadata{GatewayId: gatewayID}}
+	}
+
+	duplicates := b.joinThrottle.filterGateways([]*pb.DeviceActivationRequest{request("a"), request("b")})
+	a.So(duplicates, ShouldHaveLength, 2)
+
+	duplicates = b.joinThrottle.filterGateways([]*pb.DeviceActivationRequest{request("a"), request("c")})
+	a.So(duplicates, ShouldHaveLength, 1)
+	a.So(duplicates[0].GatewayMetadata.GatewayId, ShouldEqual, "c")
+}
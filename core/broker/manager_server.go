@@ -9,11 +9,13 @@ import (
 
 	"github.com/TheThingsNetwork/go-account-lib/claims"
 	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
 	pb "github.com/TheThingsNetwork/ttn/api/broker"
 	"github.com/TheThingsNetwork/ttn/api/discovery"
 	"github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/api/ratelimit"
+	pb_v2_lorawan "github.com/TheThingsNetwork/ttn/api/v2/lorawan"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/golang/protobuf/ptypes/empty"
 	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
@@ -127,6 +129,10 @@ func (b *brokerManager) GetStatus(ctx context.Context, in *pb.StatusRequest) (*p
 	return status, nil
 }
 
+func (b *brokerManager) TailLogs(in *api.LogsRequest, stream pb.BrokerManager_TailLogsServer) error {
+	return b.broker.Component.TailLogs(in, stream)
+}
+
 func (b *broker) RegisterManager(s *grpc.Server) {
 	server := &brokerManager{
 		broker:         b,
@@ -138,5 +144,6 @@ func (b *broker) RegisterManager(s *grpc.Server) {
 
 	pb.RegisterBrokerManagerServer(s, server)
 	lorawan.RegisterDeviceManagerServer(s, server)
+	pb_v2_lorawan.RegisterDeviceManagerServer(s, pb_v2_lorawan.NewDeviceManagerServer(server))
 	lorawan.RegisterDevAddrManagerServer(s, server)
 }
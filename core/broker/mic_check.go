@@ -0,0 +1,223 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/fcnt"
+	"github.com/bluele/gcache"
+	"github.com/brocaar/lorawan"
+)
+
+// MICCheckOptions configure the checks that reject uplink messages before they are sent to the NetworkServer
+type MICCheckOptions struct {
+	// FrameCacheSize is the number of recently accepted frames that is remembered
+	// to reject replays (0 to disable)
+	FrameCacheSize       int
+	FrameCacheExpiration time.Duration
+
+	// ReplayDistance is the distance in meters between the gateways that received
+	// a frame and a gateway that receives a replay of it, above which the replay
+	// is considered a replay attack and the device is quarantined (0 to disable).
+	// Only gateways that report their location are taken into account.
+	ReplayDistance float64
+
+	// RetransmissionWindow is the time after an accepted frame in which identical
+	// frames are considered repetitions of the device (NbTrans) instead of replays.
+	// They are dropped as duplicates.
+	RetransmissionWindow time.Duration
+
+	// NwkSKeyCheck enables checking the MIC of uplink messages against the devices
+	// that the NetworkServer recently returned for the same DevAddr. Uplink messages
+	// that don't validate are rejected without asking the NetworkServer. Devices that
+	// got a DevAddr from a different Broker are only found after the expiration.
+	NwkSKeyCheck           bool
+	NwkSKeyCacheSize       int
+	NwkSKeyCacheExpiration time.Duration
+}
+
+// DefaultMICCheckOptions are the default MICCheckOptions
+var DefaultMICCheckOptions = MICCheckOptions{
+	FrameCacheSize:         10000,
+	FrameCacheExpiration:   time.Hour,
+	ReplayDistance:         0,
+	RetransmissionWindow:   time.Minute,
+	NwkSKeyCheck:           false,
+	NwkSKeyCacheSize:       10000,
+	NwkSKeyCacheExpiration: time.Minute,
+}
+
+type frameKey struct {
+	devAddr types.DevAddr
+	fCnt    uint32
+	mic     lorawan.MIC
+}
+
+// acceptedFrame is the device and the gateway locations of an accepted frame
+type acceptedFrame struct {
+	device     *pb_lorawan.Device
+	locations  []gateway.Location
+	acceptedAt time.Time
+}
+
+type micCheck struct {
+	frames               gcache.Cache // frameKey -> *acceptedFrame
+	devices              gcache.Cache // types.DevAddr -> []*pb_lorawan.Device
+	replayDistance       float64
+	retransmissionWindow time.Duration
+}
+
+// SetMICCheck configures the checks that reject replayed or corrupted uplink messages
+// before they are sent to the NetworkServer
+func (b *broker) SetMICCheck(options MICCheckOptions) {
+	check := new(micCheck)
+	if options.FrameCacheSize > 0 {
+		check.frames = gcache.New(options.FrameCacheSize).Expiration(options.FrameCacheExpiration).LRU().Build()
+		check.replayDistance = options.ReplayDistance
+		check.retransmissionWindow = options.RetransmissionWindow
+	}
+	if options.NwkSKeyCheck && options.NwkSKeyCacheSize > 0 {
+		check.devices = gcache.New(options.NwkSKeyCacheSize).Expiration(options.NwkSKeyCacheExpiration).LRU().Build()
+	}
+	if check.frames == nil && check.devices == nil {
+		check = nil
+	}
+	b.micCheck = check
+}
+
+// isReplay returns the accepted frame if the frame was already accepted before
+func (c *micCheck) isReplay(key frameKey) (*acceptedFrame, bool) {
+	if c == nil || c.frames == nil {
+		return nil, false
+	}
+	frame, err := c.frames.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	return frame.(*acceptedFrame), true
+}
+
+// accept remembers the frame and the locations of the gateways that received it,
+// so that replays of it are rejected
+func (c *micCheck) accept(key frameKey, device *pb_lorawan.Device, gateways []*pb_gateway.RxMetadata) {
+	if c == nil || c.frames == nil {
+		return
+	}
+	frame := &acceptedFrame{device: device, acceptedAt: time.Now()}
+	for _, gtw := range gateways {
+		if location := gateway.LocationFromGPS(gtw.GetGps(), time.Time{}); location != nil {
+			frame.locations = append(frame.locations, *location)
+		}
+	}
+	c.frames.Set(key, frame)
+}
+
+// isRetransmission returns true if the frame is received within the retransmission window
+// after it was accepted. Repetitions of unconfirmed uplink (NbTrans) are identical to the
+// original frame and may be received by other gateways than the original.
+func (c *micCheck) isRetransmission(frame *acceptedFrame, receivedAt time.Time) bool {
+	if c == nil || frame == nil {
+		return false
+	}
+	return receivedAt.Sub(frame.acceptedAt) <= c.retransmissionWindow
+}
+
+// isReplayAttack returns true if one of the gateways that received the replay of the frame
+// is further than the replay distance from all gateways that received the original frame.
+// It also returns the distance in meters from that gateway to the nearest original gateway.
+func (c *micCheck) isReplayAttack(frame *acceptedFrame, gateways []*pb_gateway.RxMetadata) (distance float64, attack bool) {
+	if c == nil || c.replayDistance <= 0 || frame == nil || len(frame.locations) == 0 {
+		return 0, false
+	}
+	for _, gtw := range gateways {
+		location := gateway.LocationFromGPS(gtw.GetGps(), time.Time{})
+		if location == nil {
+			continue
+		}
+		nearest := -1.0
+		for _, original := range frame.locations {
+			if d := original.Distance(*location); nearest < 0 || d < nearest {
+				nearest = d
+			}
+		}
+		if nearest > distance {
+			distance = nearest
+		}
+	}
+	return distance, distance > c.replayDistance
+}
+
+// getDevices returns the devices that the NetworkServer recently returned for the DevAddr
+func (c *micCheck) getDevices(devAddr types.DevAddr) ([]*pb_lorawan.Device, bool) {
+	if c == nil || c.devices == nil {
+		return nil, false
+	}
+	devices, err := c.devices.Get(devAddr)
+	if err != nil {
+		return nil, false
+	}
+	return devices.([]*pb_lorawan.Device), true
+}
+
+// setDevices remembers the devices that the NetworkServer returned for the DevAddr
+func (c *micCheck) setDevices(devAddr types.DevAddr, devices []*pb_lorawan.Device) {
+	if c == nil || c.devices == nil {
+		return
+	}
+	c.devices.Set(devAddr, append([]*pb_lorawan.Device(nil), devices...))
+}
+
+// forgetDevices forgets the devices for the DevAddr, for example because a device was activated with it
+func (c *micCheck) forgetDevices(devAddr types.DevAddr) {
+	if c == nil || c.devices == nil {
+		return
+	}
+	c.devices.Remove(devAddr)
+}
+
+// findDeviceByMIC returns the first candidate whose NwkSKey validates the MIC of the payload.
+// If the candidate uses a 32 bit frame counter, the FCnt of the MAC payload is set to the full counter.
+func findDeviceByMIC(phyPayload *lorawan.PHYPayload, macPayload *lorawan.MACPayload, candidates []*pb_lorawan.Device) (device *pb_lorawan.Device, micChecks int, err error) {
+	originalFCnt := macPayload.FHDR.FCnt
+	for _, candidate := range candidates {
+		if candidate.NwkSKey == nil {
+			continue
+		}
+		nwkSKey := lorawan.AES128Key(*candidate.NwkSKey)
+
+		// First check with the 16 bit counter
+		macPayload.FHDR.FCnt = originalFCnt
+		micChecks++
+		ok, err := phyPayload.ValidateMIC(nwkSKey)
+		if err != nil {
+			return nil, micChecks, err
+		}
+		if ok {
+			return candidate, micChecks, nil
+		}
+
+		if candidate.Uses32BitFCnt {
+			macPayload.FHDR.FCnt = fcnt.GetFull(candidate.FCntUp, uint16(originalFCnt))
+
+			// If 32 bit counter has different value, perform another MIC check
+			if macPayload.FHDR.FCnt != originalFCnt {
+				micChecks++
+				ok, err = phyPayload.ValidateMIC(nwkSKey)
+				if err != nil {
+					return nil, micChecks, err
+				}
+				if ok {
+					return candidate, micChecks, nil
+				}
+			}
+		}
+	}
+	macPayload.FHDR.FCnt = originalFCnt
+	return nil, micChecks, nil
+}
@@ -0,0 +1,151 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_networkserver "github.com/TheThingsNetwork/ttn/api/networkserver"
+	"github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/assertions"
+)
+
+func buildMICTestPayload(fCnt uint32, nwkSKey types.NwkSKey) (*lorawan.PHYPayload, *lorawan.MACPayload) {
+	macPayload := &lorawan.MACPayload{
+		FHDR: lorawan.FHDR{
+			DevAddr: lorawan.DevAddr([4]byte{1, 2, 3, 4}),
+			FCnt:    fCnt,
+		},
+	}
+	phy := &lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: macPayload,
+	}
+	phy.SetMIC(lorawan.AES128Key(nwkSKey))
+	return phy, macPayload
+}
+
+func TestFindDeviceByMIC(t *testing.T) {
+	a := New(t)
+
+	nwkSKey := types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	wrongNwkSKey := types.NwkSKey{8, 7, 6, 5, 4, 3, 2, 1, 8, 7, 6, 5, 4, 3, 2, 1}
+	right := &pb_lorawan.Device{DevId: "right", NwkSKey: &nwkSKey}
+	wrong := &pb_lorawan.Device{DevId: "wrong", NwkSKey: &wrongNwkSKey}
+
+	phy, mac := buildMICTestPayload(1, nwkSKey)
+	device, micChecks, err := findDeviceByMIC(phy, mac, []*pb_lorawan.Device{wrong, {}, right})
+	a.So(err, ShouldBeNil)
+	a.So(device, ShouldEqual, right)
+	a.So(micChecks, ShouldEqual, 2)
+
+	device, _, err = findDeviceByMIC(phy, mac, []*pb_lorawan.Device{wrong})
+	a.So(err, ShouldBeNil)
+	a.So(device, ShouldBeNil)
+
+	// The full 32 bit counter is used for devices that use it
+	phy, mac = buildMICTestPayload(0x10001, nwkSKey)
+	mac.FHDR.FCnt = 0x0001
+	right.Uses32BitFCnt = true
+	right.FCntUp = 0x10000
+	device, micChecks, err = findDeviceByMIC(phy, mac, []*pb_lorawan.Device{wrong, right})
+	a.So(err, ShouldBeNil)
+	a.So(device, ShouldEqual, right)
+	a.So(micChecks, ShouldEqual, 3)
+	a.So(mac.FHDR.FCnt, ShouldEqual, 0x10001)
+}
+
+func TestMICCheck(t *testing.T) {
+	a := New(t)
+
+	// Everything is allowed if the checks are disabled
+	var check *micCheck
+	frame := frameKey{devAddr: types.DevAddr{1, 2, 3, 4}, fCnt: 1, mic: lorawan.MIC{1, 2, 3, 4}}
+	check.accept(frame, nil, nil)
+	_, replay := check.isReplay(frame)
+	a.So(replay, ShouldBeFalse)
+	check.setDevices(frame.devAddr, []*pb_lorawan.Device{{}})
+	_, ok := check.getDevices(frame.devAddr)
+	a.So(ok, ShouldBeFalse)
+
+	b := getTestBroker(t)
+	b.SetMICCheck(MICCheckOptions{})
+	a.So(b.micCheck, ShouldBeNil)
+
+	options := DefaultMICCheckOptions
+	options.NwkSKeyCheck = true
+	b.SetMICCheck(options)
+	check = b.micCheck
+
+	_, replay = check.isReplay(frame)
+	a.So(replay, ShouldBeFalse)
+	check.accept(frame, &pb_lorawan.Device{DevId: "dev"}, nil)
+	accepted, replay := check.isReplay(frame)
+	a.So(replay, ShouldBeTrue)
+	a.So(accepted.device.DevId, ShouldEqual, "dev")
+	_, replay = check.isReplay(frameKey{devAddr: frame.devAddr, fCnt: 2, mic: frame.mic})
+	a.So(replay, ShouldBeFalse)
+
+	_, ok = check.getDevices(frame.devAddr)
+	a.So(ok, ShouldBeFalse)
+	check.setDevices(frame.devAddr, []*pb_lorawan.Device{{DevId: "dev"}})
+	devices, ok := check.getDevices(frame.devAddr)
+	a.So(ok, ShouldBeTrue)
+	a.So(devices, ShouldHaveLength, 1)
+	check.forgetDevices(frame.devAddr)
+	_, ok = check.getDevices(frame.devAddr)
+	a.So(ok, ShouldBeFalse)
+}
+
+func TestHandleUplinkNwkSKeyCheck(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	options := DefaultMICCheckOptions
+	options.NwkSKeyCheck = true
+	b.SetMICCheck(options)
+
+	nwkSKey := types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	phy, _ := buildMICTestPayload(1, nwkSKey)
+	bytes, _ := phy.MarshalBinary()
+	uplink := func() *pb.UplinkMessage {
+		return &pb.UplinkMessage{
+			Payload:          bytes,
+			GatewayMetadata:  &gateway.RxMetadata{GatewayId: "eui-0102030405060708"},
+			ProtocolMetadata: &protocol.RxMetadata{Protocol: &protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{}}},
+		}
+	}
+
+	// The NetworkServer is asked once
+	b.ns.EXPECT().GetDevices(gomock.Any(), gomock.Any()).Return(&pb_networkserver.DevicesResponse{}, nil)
+	a.So(b.HandleUplink(uplink()), ShouldNotBeNil)
+	a.So(b.GetRejectedUplinks().InvalidMIC, ShouldEqual, 0)
+
+	// The next uplink is rejected by the broker
+	a.So(b.HandleUplink(uplink()), ShouldNotBeNil)
+	a.So(b.GetRejectedUplinks().InvalidMIC, ShouldEqual, 1)
+}
+
+func TestIsRetransmission(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	b.SetMICCheck(DefaultMICCheckOptions)
+	frame := frameKey{devAddr: types.DevAddr{1, 2, 3, 4}, fCnt: 1, mic: lorawan.MIC{1, 2, 3, 4}}
+	b.micCheck.accept(frame, &pb_lorawan.Device{}, nil)
+	accepted, _ := b.micCheck.isReplay(frame)
+
+	a.So(b.micCheck.isRetransmission(accepted, time.Now()), ShouldBeTrue)
+	a.So(b.micCheck.isRetransmission(accepted, time.Now().Add(DefaultMICCheckOptions.RetransmissionWindow+time.Second)), ShouldBeFalse)
+}
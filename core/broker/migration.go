@@ -0,0 +1,188 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// MigrationLoadTimeout is the timeout of the requests in which the Broker
+// loads the migrations of applications from the Handlers when it starts
+var MigrationLoadTimeout = 10 * time.Second
+
+type migrations struct {
+	sync.RWMutex
+	items map[string]*types.Migration
+}
+
+// SetMigration sets the migration of an application to another Handler. It
+// must be set by the Handler that the application is registered to.
+func (b *broker) SetMigration(handlerID string, migration *types.Migration) error {
+	if migration == nil || migration.AppID == "" {
+		return errors.NewErrInvalidArgument("Migration", "must have an AppID")
+	}
+	if migration.ToHandler == "" || migration.ToHandler == handlerID {
+		return errors.NewErrInvalidArgument("Migration", "must have another target Handler")
+	}
+	if migration.Percentage < 0 || migration.Percentage > 100 {
+		return errors.NewErrInvalidArgument("Migration", "percentage must be between 0 and 100")
+	}
+	if err := b.checkApplicationHandler(migration.AppID, handlerID); err != nil {
+		return err
+	}
+	if _, err := b.Discovery.Get("handler", migration.ToHandler); err != nil {
+		return errors.Wrap(errors.FromGRPCError(err), fmt.Sprintf("Handler %s", migration.ToHandler))
+	}
+	migration.FromHandler = handlerID
+	b.migrations.Lock()
+	defer b.migrations.Unlock()
+	if b.migrations.items == nil {
+		b.migrations.items = make(map[string]*types.Migration)
+	}
+	b.migrations.items[migration.AppID] = migration
+	b.Ctx.WithField("AppID", migration.AppID).WithField("FromHandler", handlerID).WithField("ToHandler", migration.ToHandler).Info("Set migration")
+	return nil
+}
+
+// DeleteMigration ends the migration of an application. It must be deleted
+// by the Handler that set it, or by the Handler that the application was
+// migrated to.
+func (b *broker) DeleteMigration(handlerID string, appID string) error {
+	b.migrations.Lock()
+	defer b.migrations.Unlock()
+	migration, ok := b.migrations.items[appID]
+	if !ok {
+		return nil
+	}
+	if migration.FromHandler != handlerID && migration.ToHandler != handlerID {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Handler %s does not serve application %s", handlerID, appID))
+	}
+	delete(b.migrations.items, appID)
+	b.Ctx.WithField("AppID", appID).Info("Deleted migration")
+	return nil
+}
+
+// GetMigration returns the migration of an application
+func (b *broker) GetMigration(appID string) (*types.Migration, error) {
+	migration := b.migration(appID)
+	if migration == nil {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("Migration of application %s", appID))
+	}
+	return migration, nil
+}
+
+func (b *broker) migration(appID string) *types.Migration {
+	b.migrations.RLock()
+	defer b.migrations.RUnlock()
+	return b.migrations.items[appID]
+}
+
+// checkApplicationHandler checks that the application is registered to the Handler
+func (b *broker) checkApplicationHandler(appID string, handlerID string) error {
+	announcements, err := b.getHandlersForAppID(appID)
+	if err != nil {
+		return errors.FromGRPCError(err)
+	}
+	for _, announcement := range announcements {
+		if announcement.Id == handlerID {
+			return nil
+		}
+	}
+	return errors.NewErrPermissionDenied(fmt.Sprintf("Application %s is not registered to Handler %s", appID, handlerID))
+}
+
+// migratedHandler returns the ID of the Handler that serves the device, if
+// it is migrated away from the Handler that the application is registered to
+func (b *broker) migratedHandler(appID, devID, handlerID string) (string, bool) {
+	migration := b.migration(appID)
+	if migration == nil || migration.FromHandler != handlerID || !migration.Migrated(devID) {
+		return "", false
+	}
+	return migration.ToHandler, true
+}
+
+// migrationHandlers returns the announcements of the Handlers that serve the
+// device during the migration of its application
+func (b *broker) migrationHandlers(appID, devID string, announcements []*pb_discovery.Announcement) []*pb_discovery.Announcement {
+	if len(announcements) != 1 {
+		return announcements
+	}
+	toHandler, ok := b.migratedHandler(appID, devID, announcements[0].Id)
+	if !ok {
+		return announcements
+	}
+	announcement, err := b.Discovery.Get("handler", toHandler)
+	if err != nil {
+		b.Ctx.WithField("HandlerID", toHandler).WithError(err).Warn("Could not get migration Handler")
+		return announcements
+	}
+	return []*pb_discovery.Announcement{announcement}
+}
+
+// isMigrationHandler returns true if the Handler serves migrated devices of the application
+func (b *broker) isMigrationHandler(appID, handlerID string) bool {
+	migration := b.migration(appID)
+	return migration != nil && migration.ToHandler == handlerID
+}
+
+// loadMigrations loads the migrations of applications from the Handlers that
+// announce an HTTP API, so that a Broker that restarts does not route migrated
+// devices to their old Handler until the Handlers send their migrations again
+func (b *broker) loadMigrations(handlers []*pb_discovery.Announcement) {
+	token, err := b.BuildJWT()
+	if err != nil {
+		b.Ctx.WithError(err).Warn("Could not load migrations")
+		return
+	}
+	client := &http.Client{Timeout: MigrationLoadTimeout}
+	for _, handler := range handlers {
+		if handler.ApiAddress == "" {
+			continue
+		}
+		migrations, err := b.getHandlerMigrations(client, token, handler)
+		if err != nil {
+			b.Ctx.WithError(err).WithField("HandlerID", handler.Id).Warn("Could not load migrations")
+			continue
+		}
+		for _, migration := range migrations {
+			if err := b.SetMigration(handler.Id, migration); err != nil {
+				b.Ctx.WithError(err).WithField("HandlerID", handler.Id).WithField("AppID", migration.AppID).Warn("Could not load migration")
+			}
+		}
+	}
+}
+
+func (b *broker) getHandlerMigrations(client *http.Client, token string, handler *pb_discovery.Announcement) ([]*types.Migration, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(handler.ApiAddress, "/")+"/migrations", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Grpc-Metadata-Id", b.Identity.Id)
+	req.Header.Set("Grpc-Metadata-Service-Name", b.Identity.ServiceName)
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return nil, errors.NewErrInternal(fmt.Sprintf("Handler %s did not return migrations: %s %s", handler.Id, res.Status, strings.TrimSpace(string(body))))
+	}
+	var migrations []*types.Migration
+	if err := json.NewDecoder(res.Body).Decode(&migrations); err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
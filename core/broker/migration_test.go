@@ -0,0 +1,144 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestSetMigration(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	defer b.ctrl.Finish()
+
+	a.So(b.SetMigration("old", nil), ShouldNotBeNil)
+	a.So(b.SetMigration("old", &types.Migration{AppID: "app"}), ShouldNotBeNil)
+	a.So(b.SetMigration("old", &types.Migration{AppID: "app", ToHandler: "old"}), ShouldNotBeNil)
+	a.So(b.SetMigration("old", &types.Migration{AppID: "app", ToHandler: "new", Percentage: 101}), ShouldNotBeNil)
+
+	// Only the Handler of the application can migrate it
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{{Id: "old"}}, nil)
+	err := b.SetMigration("other", &types.Migration{AppID: "app", ToHandler: "new"})
+	a.So(errors.GetErrType(err), ShouldEqual, errors.PermissionDenied)
+
+	// The target Handler must exist
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{{Id: "old"}}, nil)
+	b.discovery.EXPECT().Get("handler", "unknown").Return(nil, errors.NewErrNotFound("unknown"))
+	err = b.SetMigration("old", &types.Migration{AppID: "app", ToHandler: "unknown"})
+	a.So(err, ShouldNotBeNil)
+
+	_, err = b.GetMigration("app")
+	a.So(errors.GetErrType(err), ShouldEqual, errors.NotFound)
+
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{{Id: "old"}}, nil)
+	b.discovery.EXPECT().Get("handler", "new").Return(&pb_discovery.Announcement{Id: "new"}, nil)
+	err = b.SetMigration("old", &types.Migration{AppID: "app", ToHandler: "new", Devices: []string{"dev"}})
+	a.So(err, ShouldBeNil)
+
+	migration, err := b.GetMigration("app")
+	a.So(err, ShouldBeNil)
+	a.So(migration.FromHandler, ShouldEqual, "old")
+	a.So(b.isMigrationHandler("app", "new"), ShouldBeTrue)
+	a.So(b.isMigrationHandler("app", "old"), ShouldBeFalse)
+	a.So(b.isMigrationHandler("other", "new"), ShouldBeFalse)
+
+	a.So(b.DeleteMigration("other", "app"), ShouldNotBeNil)
+	a.So(b.DeleteMigration("new", "app"), ShouldBeNil)
+	a.So(b.isMigrationHandler("app", "new"), ShouldBeFalse)
+	a.So(b.DeleteMigration("old", "app"), ShouldBeNil)
+}
+
+func TestForwardMigratedUplink(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	defer b.ctrl.Finish()
+
+	b.migrations.items = map[string]*types.Migration{
+		"app": {AppID: "app", FromHandler: "old", ToHandler: "new", Devices: []string{"migrated"}},
+	}
+	b.handlers["old"] = &handler{uplink: make(chan *pb.DeduplicatedUplinkMessage, 10)}
+	b.handlers["new"] = &handler{uplink: make(chan *pb.DeduplicatedUplinkMessage, 10)}
+
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{{Id: "old"}}, nil)
+	a.So(b.forwardUplink("app", &pb.DeduplicatedUplinkMessage{AppId: "app", DevId: "migrated"}), ShouldBeNil)
+	a.So(b.handlers["new"].uplink, ShouldHaveLength, 1)
+	a.So(b.handlers["old"].uplink, ShouldBeEmpty)
+
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{{Id: "old"}}, nil)
+	a.So(b.forwardUplink("app", &pb.DeduplicatedUplinkMessage{AppId: "app", DevId: "other"}), ShouldBeNil)
+	a.So(b.handlers["old"].uplink, ShouldHaveLength, 1)
+
+	// Activations of migrated devices go to the new Handler
+	b.discovery.EXPECT().Get("handler", "new").Return(&pb_discovery.Announcement{Id: "new"}, nil)
+	announcements := b.migrationHandlers("app", "migrated", []*pb_discovery.Announcement{{Id: "old"}})
+	a.So(announcements, ShouldHaveLength, 1)
+	a.So(announcements[0].Id, ShouldEqual, "new")
+	announcements = b.migrationHandlers("app", "other", []*pb_discovery.Announcement{{Id: "old"}})
+	a.So(announcements[0].Id, ShouldEqual, "old")
+}
+
+func TestMigrationHTTP(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	defer b.ctrl.Finish()
+	handler := b.HTTPHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/applications/app/migration", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusNotFound)
+
+	b.migrations.items = map[string]*types.Migration{
+		"app": {AppID: "app", FromHandler: "old", ToHandler: "new", Percentage: 10},
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/applications/app/migration", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	a.So(rec.Body.String(), ShouldContainSubstring, `"to_handler":"new"`)
+
+	// Only Handlers can set migrations
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("PUT", "/applications/app/migration", strings.NewReader(`{"to_handler":"other"}`)))
+	a.So(rec.Code, ShouldEqual, http.StatusForbidden)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/applications/app", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusNotFound)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	defer b.ctrl.Finish()
+	b.Identity = &pb_discovery.Announcement{Id: "broker", ServiceName: "broker"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.So(r.URL.Path, ShouldEqual, "/migrations")
+		a.So(r.Header.Get("Grpc-Metadata-Service-Name"), ShouldEqual, "broker")
+		fmt.Fprint(w, `[{"app_id":"app","to_handler":"new","percentage":10}]`)
+	}))
+	defer server.Close()
+
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{{Id: "old"}}, nil)
+	b.discovery.EXPECT().Get("handler", "new").Return(&pb_discovery.Announcement{Id: "new"}, nil)
+	b.loadMigrations([]*pb_discovery.Announcement{{Id: "old", ApiAddress: server.URL + "/"}, {Id: "without-api"}})
+
+	migration, err := b.GetMigration("app")
+	a.So(err, ShouldBeNil)
+	a.So(migration.FromHandler, ShouldEqual, "old")
+	a.So(migration.Percentage, ShouldEqual, 10)
+}
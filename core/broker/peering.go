@@ -0,0 +1,239 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+	"github.com/nats-io/go-nats"
+)
+
+// PeeringBufferSize is the number of uplink messages that can be waiting for
+// export. If the buffer is full, uplink messages are not exported.
+var PeeringBufferSize = 1000
+
+// PeeringUplink is a deduplicated uplink message that is exported to a peering network
+type PeeringUplink struct {
+	NetworkID  string           `json:"network_id"`
+	ServerTime time.Time        `json:"server_time"`
+	DevAddr    types.DevAddr    `json:"dev_addr"`
+	Payload    []byte           `json:"payload"`
+	Frequency  uint64           `json:"frequency"`
+	DataRate   string           `json:"data_rate,omitempty"`
+	CodingRate string           `json:"coding_rate,omitempty"`
+	Gateways   []PeeringGateway `json:"gateways"`
+
+	// Device identifiers are only set if the device is known. They are hashed
+	// if the peering is configured with a hash secret.
+	AppEUI string `json:"app_eui,omitempty"`
+	DevEUI string `json:"dev_eui,omitempty"`
+	AppID  string `json:"app_id,omitempty"`
+	DevID  string `json:"dev_id,omitempty"`
+}
+
+// PeeringGateway is the metadata of a gateway that received an exported uplink message
+type PeeringGateway struct {
+	GatewayID string  `json:"gtw_id"`
+	Trusted   bool    `json:"gtw_trusted,omitempty"`
+	Timestamp uint32  `json:"timestamp"`
+	Time      int64   `json:"time,omitempty"`
+	RSSI      float32 `json:"rssi"`
+	SNR       float32 `json:"snr"`
+	Latitude  float32 `json:"latitude,omitempty"`
+	Longitude float32 `json:"longitude,omitempty"`
+	Altitude  int32   `json:"altitude,omitempty"`
+}
+
+// PeeringExporter exports uplink messages to a peering network
+type PeeringExporter interface {
+	Export(uplink *PeeringUplink) error
+	Close() error
+}
+
+// PeeringConfig is the configuration of the peering export
+type PeeringConfig struct {
+	// NetworkID identifies this network to the peers
+	NetworkID string
+	// HashSecret is used to hash the device identifiers. If it is empty, device
+	// identifiers are exported as-is.
+	HashSecret string
+}
+
+type peering struct {
+	config   PeeringConfig
+	exporter PeeringExporter
+	uplink   chan *PeeringUplink
+	done     chan struct{}
+}
+
+// SetPeering sets the exporter for deduplicated uplink messages. It must be called before the
+// broker is started; the exporter can not be changed afterwards and is closed when the broker
+// shuts down.
+func (b *broker) SetPeering(exporter PeeringExporter, config PeeringConfig) {
+	if exporter == nil || b.peering != nil {
+		return
+	}
+	p := &peering{
+		config:   config,
+		exporter: exporter,
+		uplink:   make(chan *PeeringUplink, PeeringBufferSize),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer p.exporter.Close()
+		for {
+			select {
+			case uplink := <-p.uplink:
+				if err := p.exporter.Export(uplink); err != nil {
+					b.Ctx.WithError(err).Warn("Could not export uplink to peering network")
+				}
+			case <-p.done:
+				return
+			}
+		}
+	}()
+	b.peering = p
+}
+
+// stop stops the export. The uplink channel is not closed, as uplink messages may still be
+// handled; they are dropped when the buffer is full.
+func (p *peering) stop() {
+	close(p.done)
+}
+
+func (p *peering) hash(identifier string) string {
+	if identifier == "" || p.config.HashSecret == "" {
+		return identifier
+	}
+	mac := hmac.New(sha256.New, []byte(p.config.HashSecret))
+	mac.Write([]byte(identifier))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// buildPeeringUplink builds the uplink message that is exported from the duplicates of an
+// uplink message and the deduplicated uplink message, which contains the device identifiers
+// if the device was found.
+func (p *peering) buildPeeringUplink(duplicates []*pb.UplinkMessage, deduplicated *pb.DeduplicatedUplinkMessage) *PeeringUplink {
+	if len(duplicates) == 0 {
+		return nil
+	}
+	var phyPayload lorawan.PHYPayload
+	if err := phyPayload.UnmarshalBinary(duplicates[0].Payload); err != nil {
+		return nil
+	}
+	macPayload, ok := phyPayload.MACPayload.(*lorawan.MACPayload)
+	if !ok {
+		return nil
+	}
+	uplink := &PeeringUplink{
+		NetworkID:  p.config.NetworkID,
+		ServerTime: time.Unix(0, deduplicated.ServerTime).UTC(),
+		DevAddr:    types.DevAddr(macPayload.FHDR.DevAddr),
+		Payload:    duplicates[0].Payload,
+	}
+	if lorawanMetadata := duplicates[0].GetProtocolMetadata().GetLorawan(); lorawanMetadata != nil {
+		uplink.DataRate = lorawanMetadata.DataRate
+		uplink.CodingRate = lorawanMetadata.CodingRate
+	}
+	for _, duplicate := range duplicates {
+		md := duplicate.GatewayMetadata
+		if md == nil {
+			continue
+		}
+		uplink.Frequency = md.Frequency
+		gateway := PeeringGateway{
+			GatewayID: md.GatewayId,
+			Trusted:   md.GatewayTrusted,
+			Timestamp: md.Timestamp,
+			Time:      md.Time,
+			RSSI:      md.Rssi,
+			SNR:       md.Snr,
+		}
+		if gps := md.GetGps(); gps != nil {
+			gateway.Latitude = gps.Latitude
+			gateway.Longitude = gps.Longitude
+			gateway.Altitude = gps.Altitude
+		}
+		uplink.Gateways = append(uplink.Gateways, gateway)
+	}
+	if deduplicated.AppEui != nil {
+		uplink.AppEUI = p.hash(deduplicated.AppEui.String())
+	}
+	if deduplicated.DevEui != nil {
+		uplink.DevEUI = p.hash(deduplicated.DevEui.String())
+	}
+	uplink.AppID = p.hash(deduplicated.AppId)
+	uplink.DevID = p.hash(deduplicated.DevId)
+	return uplink
+}
+
+// exportUplink queues the uplink message for export, if peering is enabled
+func (b *broker) exportUplink(duplicates []*pb.UplinkMessage, deduplicated *pb.DeduplicatedUplinkMessage) {
+	if b.peering == nil {
+		return
+	}
+	uplink := b.peering.buildPeeringUplink(duplicates, deduplicated)
+	if uplink == nil {
+		return
+	}
+	select {
+	case b.peering.uplink <- uplink:
+	default:
+		b.Ctx.Warn("Peering export buffer full, dropping uplink")
+	}
+}
+
+// NewNATSPeeringExporter returns a PeeringExporter that publishes uplink messages as JSON on
+// the given subject of a NATS server. The connection uses TLS if the URL has the tls:// scheme
+// or if the server requires it; caFile optionally sets the CA certificate of the server.
+func NewNATSPeeringExporter(ctx ttnlog.Interface, serverURL, subject, caFile string) (PeeringExporter, error) {
+	options := []nats.Option{
+		nats.Name("ttn-broker"),
+		nats.MaxReconnects(-1),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			ctx.WithError(err).Warn("Error from peering network")
+		}),
+		nats.DisconnectHandler(func(_ *nats.Conn) {
+			ctx.Warn("Disconnected from peering network")
+		}),
+		nats.ReconnectHandler(func(_ *nats.Conn) {
+			ctx.Info("Reconnected to peering network")
+		}),
+	}
+	if caFile != "" {
+		options = append(options, nats.RootCAs(caFile))
+	}
+	conn, err := nats.Connect(serverURL, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &natsPeeringExporter{conn: conn, subject: subject}, nil
+}
+
+type natsPeeringExporter struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func (e *natsPeeringExporter) Export(uplink *PeeringUplink) error {
+	data, err := json.Marshal(uplink)
+	if err != nil {
+		return err
+	}
+	return e.conn.Publish(e.subject, data)
+}
+
+func (e *natsPeeringExporter) Close() error {
+	err := e.conn.Flush()
+	e.conn.Close()
+	return err
+}
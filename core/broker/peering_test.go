@@ -0,0 +1,188 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_networkserver "github.com/TheThingsNetwork/ttn/api/networkserver"
+	"github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/brocaar/lorawan"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/assertions"
+)
+
+type testPeeringExporter struct {
+	uplink chan *PeeringUplink
+	closed chan struct{}
+}
+
+func (e *testPeeringExporter) Export(uplink *PeeringUplink) error {
+	e.uplink <- uplink
+	return nil
+}
+
+func (e *testPeeringExporter) Close() error {
+	if e.closed != nil {
+		close(e.closed)
+	}
+	return nil
+}
+
+func buildPeeringTestUplink(gtwID string, rssi float32) *pb.UplinkMessage {
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{
+				DevAddr: lorawan.DevAddr([4]byte{1, 2, 3, 4}),
+				FCnt:    1,
+			},
+		},
+	}
+	bytes, _ := phy.MarshalBinary()
+	return &pb.UplinkMessage{
+		Payload: bytes,
+		GatewayMetadata: &gateway.RxMetadata{
+			GatewayId: gtwID,
+			Frequency: 868100000,
+			Rssi:      rssi,
+			Gps:       &gateway.GPSMetadata{Latitude: 52.37, Longitude: 4.89},
+		},
+		ProtocolMetadata: &protocol.RxMetadata{Protocol: &protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{
+			DataRate:   "SF7BW125",
+			CodingRate: "4/5",
+		}}},
+	}
+}
+
+func TestBuildPeeringUplink(t *testing.T) {
+	a := New(t)
+
+	p := &peering{config: PeeringConfig{NetworkID: "ttn"}}
+	a.So(p.buildPeeringUplink(nil, &pb.DeduplicatedUplinkMessage{}), ShouldBeNil)
+
+	devEUI := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	duplicates := []*pb.UplinkMessage{
+		buildPeeringTestUplink("gtw-1", -80),
+		buildPeeringTestUplink("gtw-2", -100),
+	}
+	deduplicated := &pb.DeduplicatedUplinkMessage{
+		ServerTime: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano(),
+		DevEui:     &devEUI,
+		AppId:      "app",
+		DevId:      "dev",
+	}
+
+	uplink := p.buildPeeringUplink(duplicates, deduplicated)
+	a.So(uplink, ShouldNotBeNil)
+	a.So(uplink.NetworkID, ShouldEqual, "ttn")
+	a.So(uplink.DevAddr, ShouldEqual, types.DevAddr{1, 2, 3, 4})
+	a.So(uplink.Frequency, ShouldEqual, 868100000)
+	a.So(uplink.DataRate, ShouldEqual, "SF7BW125")
+	a.So(uplink.Gateways, ShouldHaveLength, 2)
+	a.So(uplink.Gateways[1].RSSI, ShouldEqual, -100)
+	a.So(uplink.Gateways[0].Latitude, ShouldEqual, float32(52.37))
+	a.So(uplink.DevEUI, ShouldEqual, devEUI.String())
+	a.So(uplink.AppEUI, ShouldBeEmpty)
+	a.So(uplink.AppID, ShouldEqual, "app")
+	a.So(uplink.DevID, ShouldEqual, "dev")
+
+	// Device identifiers are hashed
+	p.config.HashSecret = "secret"
+	hashed := p.buildPeeringUplink(duplicates, deduplicated)
+	a.So(hashed.DevEUI, ShouldHaveLength, 64)
+	a.So(hashed.DevEUI, ShouldNotEqual, devEUI.String())
+	a.So(hashed.AppID, ShouldNotEqual, "app")
+	a.So(hashed.DevID, ShouldEqual, p.hash("dev"))
+	a.So(hashed.DevAddr, ShouldEqual, uplink.DevAddr)
+
+	// The hash depends on the secret
+	other := &peering{config: PeeringConfig{HashSecret: "other"}}
+	a.So(other.hash("dev"), ShouldNotEqual, p.hash("dev"))
+}
+
+func TestPeeringExport(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	exporter := &testPeeringExporter{uplink: make(chan *PeeringUplink, 10)}
+	b.SetPeering(exporter, PeeringConfig{NetworkID: "ttn"})
+
+	// Uplink messages of unknown devices are exported as well
+	b.ns.EXPECT().GetDevices(gomock.Any(), gomock.Any()).Return(&pb_networkserver.DevicesResponse{}, nil)
+	err := b.HandleUplink(buildPeeringTestUplink("gtw-1", -80))
+	a.So(err, ShouldNotBeNil)
+
+	select {
+	case uplink := <-exporter.uplink:
+		a.So(uplink.NetworkID, ShouldEqual, "ttn")
+		a.So(uplink.Gateways, ShouldHaveLength, 1)
+		a.So(uplink.DevEUI, ShouldBeEmpty)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Uplink was not exported")
+	}
+
+	// The exporter can not be replaced
+	b.SetPeering(&testPeeringExporter{}, PeeringConfig{NetworkID: "other"})
+	a.So(b.peering.exporter, ShouldEqual, exporter)
+}
+
+func TestPeeringShutdown(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	exporter := &testPeeringExporter{uplink: make(chan *PeeringUplink, 10), closed: make(chan struct{})}
+	b.SetPeering(exporter, PeeringConfig{NetworkID: "ttn"})
+	b.Shutdown()
+
+	select {
+	case <-exporter.closed:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Exporter was not closed")
+	}
+
+	// Uplink messages that are handled during shutdown do not panic
+	b.ns.EXPECT().GetDevices(gomock.Any(), gomock.Any()).Return(&pb_networkserver.DevicesResponse{}, nil)
+	a.So(func() { b.HandleUplink(buildPeeringTestUplink("gtw-1", -80)) }, ShouldNotPanic)
+}
+
+func TestPeeringExportNetworkServerError(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	exporter := &testPeeringExporter{uplink: make(chan *PeeringUplink, 10)}
+	b.SetPeering(exporter, PeeringConfig{NetworkID: "ttn"})
+
+	devEUI := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	nwkSKey := types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	phy, _ := buildMICTestPayload(1, nwkSKey)
+	uplink := buildPeeringTestUplink("gtw-1", -80)
+	uplink.Payload, _ = phy.MarshalBinary()
+
+	// The uplink message is exported as it was before it was sent to the NetworkServer
+	b.ns.EXPECT().GetDevices(gomock.Any(), gomock.Any()).Return(&pb_networkserver.DevicesResponse{
+		Results: []*pb_lorawan.Device{{DevEui: &devEUI, AppEui: &appEUI, AppId: "app", DevId: "dev", NwkSKey: &nwkSKey}},
+	}, nil)
+	b.ns.EXPECT().Uplink(gomock.Any(), gomock.Any()).Return(nil, errors.NewErrInternal("NetworkServer unavailable"))
+	a.So(b.HandleUplink(uplink), ShouldNotBeNil)
+
+	select {
+	case uplink := <-exporter.uplink:
+		a.So(uplink.AppID, ShouldEqual, "app")
+		a.So(uplink.DevID, ShouldEqual, "dev")
+		a.So(uplink.AppEUI, ShouldEqual, appEUI.String())
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Uplink was not exported")
+	}
+}
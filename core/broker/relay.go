@@ -0,0 +1,209 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/bluele/gcache"
+	"github.com/brocaar/lorawan"
+)
+
+// Size and expiration of the cache of downlink options that are given to the
+// uplinks that relays forward. Downlinks with these options are sent through
+// the relay.
+var (
+	RelayDownlinkCacheSize       = 10000
+	RelayDownlinkCacheExpiration = 10 * time.Second
+)
+
+// RelayResponseTimeout is how long the Broker waits for the downlink of the
+// end device before it sends the response to the uplink of the relay without it
+var RelayResponseTimeout = 500 * time.Millisecond
+
+func newRelayDownlinks() gcache.Cache {
+	return gcache.New(RelayDownlinkCacheSize).Expiration(RelayDownlinkCacheExpiration).LRU().Build()
+}
+
+// relayResponse is the response to an uplink in which a relay forwarded the
+// uplink of an end device. It is sent once: with the downlink of the end
+// device, or without it if the relay needs a response of its own.
+type relayResponse struct {
+	relay    *pb_lorawan.Device
+	template *pb.DownlinkMessage // ResponseTemplate of the uplink of the relay, with its MAC commands and ack
+	trace    *trace.Trace        // Trace of the uplink of the relay
+	needed   bool                // The relay needs the response, even without a downlink for the end device
+
+	mu   sync.Mutex
+	sent bool
+}
+
+func newRelayResponse(relay *pb_lorawan.Device, uplink *pb.DeduplicatedUplinkMessage) *relayResponse {
+	mac := uplink.GetResponseTemplate().GetMessage().GetLorawan().GetMacPayload()
+	if mac == nil {
+		return nil
+	}
+	return &relayResponse{
+		relay:    relay,
+		template: uplink.ResponseTemplate,
+		trace:    uplink.Trace,
+		needed:   mac.Ack || len(mac.FOpts) > 0,
+	}
+}
+
+// take returns the response if it was not sent yet
+func (r *relayResponse) take() (*pb.DownlinkMessage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sent {
+		return nil, false
+	}
+	r.sent = true
+	return r.template, true
+}
+
+// relayGatewayID returns the gateway ID in the metadata of the uplinks that a relay forwards
+func relayGatewayID(relay *pb_lorawan.Device) string {
+	return fmt.Sprintf("relay-%s", relay.DevId)
+}
+
+// relayedUplink returns the uplink of the end device that the relay forwards
+// in the (decrypted) FRMPayload of its uplink on the relay port, and the
+// metadata of the relay. The uplink of the end device gets the downlink option
+// of the uplink of the relay.
+func relayedUplink(relay *pb_lorawan.Device, payload []byte, uplink *pb.DeduplicatedUplinkMessage) (*pb.UplinkMessage, *pb.RelayMetadata, error) {
+	var req pb_lorawan.ForwardUplinkReq
+	if err := req.UnmarshalBinary(payload); err != nil {
+		return nil, nil, err
+	}
+	lorawanMetadata := uplink.GetProtocolMetadata().GetLorawan()
+	fp, err := band.Get(lorawanMetadata.GetRegion().String())
+	if err != nil {
+		return nil, nil, errors.NewErrInvalidArgument("Relay Uplink", err.Error())
+	}
+	dataRate, err := fp.GetDataRateStringForIndex(int(req.DataRate))
+	if err != nil {
+		return nil, nil, errors.NewErrInvalidArgument("Relay Uplink", err.Error())
+	}
+
+	relayed := &pb.UplinkMessage{
+		Payload: req.Payload,
+		ProtocolMetadata: &pb_protocol.RxMetadata{Protocol: &pb_protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{
+			Modulation: lorawanMetadata.Modulation,
+			DataRate:   dataRate,
+			CodingRate: lorawanMetadata.CodingRate,
+			Region:     lorawanMetadata.Region,
+		}}},
+		GatewayMetadata: &pb_gateway.RxMetadata{
+			GatewayId: relayGatewayID(relay),
+			Frequency: req.Frequency,
+			Rssi:      float32(req.RSSI),
+			Snr:       float32(req.SNR),
+		},
+		Trace: uplink.Trace.WithEvent(trace.RelayEvent, "relay", relay.DevId, "wor_channel", req.WORChannel),
+	}
+	if len(uplink.GatewayMetadata) > 0 {
+		relayed.GatewayMetadata.Timestamp = uplink.GatewayMetadata[0].Timestamp
+		relayed.GatewayMetadata.Time = uplink.GatewayMetadata[0].Time
+	}
+	if option := uplink.GetResponseTemplate().GetDownlinkOption(); option != nil {
+		relayed.DownlinkOptions = []*pb.DownlinkOption{option}
+	}
+	return relayed, &pb.RelayMetadata{DevId: relay.DevId, WorChannel: uint32(req.WORChannel)}, nil
+}
+
+// handleRelayUplink handles the uplink of the end device that the relay
+// forwards, instead of forwarding the uplink of the relay to the Handler. The
+// response to the uplink of the relay is sent with the downlink for the end
+// device, or on its own when the RelayResponseTimeout expires.
+func (b *broker) handleRelayUplink(ctx ttnlog.Interface, relay *pb_lorawan.Device, phyPayload *lorawan.PHYPayload, uplink *pb.DeduplicatedUplinkMessage) error {
+	if relay.NwkSKey == nil {
+		return errors.NewErrInvalidArgument("Relay", "does not have a NwkSKey")
+	}
+	macPayload, ok := phyPayload.MACPayload.(*lorawan.MACPayload)
+	if !ok || len(macPayload.FRMPayload) != 1 {
+		return errors.NewErrInvalidArgument("Relay Uplink", "does not contain a FRMPayload")
+	}
+	if err := phyPayload.DecryptFRMPayload(lorawan.AES128Key(*relay.NwkSKey)); err != nil {
+		return errors.NewErrInvalidArgument("Relay Uplink", err.Error())
+	}
+	payload, ok := macPayload.FRMPayload[0].(*lorawan.DataPayload)
+	if !ok {
+		return errors.NewErrInvalidArgument("Relay Uplink FRMPayload", "must be of type *lorawan.DataPayload")
+	}
+
+	relayed, relayMetadata, err := relayedUplink(relay, payload.Bytes, uplink)
+	if err != nil {
+		return err
+	}
+	if response := newRelayResponse(relay, uplink); b.relayDownlinks != nil && response != nil {
+		for _, option := range relayed.DownlinkOptions {
+			b.relayDownlinks.Set(option.Identifier, response)
+		}
+		time.AfterFunc(RelayResponseTimeout, func() {
+			b.sendRelayResponse(response)
+		})
+	}
+
+	ctx.WithField("Relay", relay.DevId).Debug("Handle uplink forwarded by relay")
+	return b.handleUplink(relayed, relayMetadata)
+}
+
+// sendRelayResponse sends the response to the uplink of the relay without a
+// downlink for the end device, if it was not sent yet and the relay needs it
+func (b *broker) sendRelayResponse(response *relayResponse) {
+	if !response.needed {
+		return
+	}
+	template, ok := response.take()
+	if !ok {
+		return
+	}
+	template.Trace = response.trace.WithEvent(trace.RelayEvent, "relay", response.relay.DevId)
+	if err := b.HandleDownlink(template); err != nil {
+		b.Ctx.WithError(err).WithField("Relay", response.relay.DevId).Warn("Could not send response to relay")
+	}
+}
+
+// relayDownlink returns the downlink for the relay that contains the downlink
+// for the end device, if the downlink option was given to an uplink that a
+// relay forwarded. The downlink for the relay is the response to its uplink,
+// so that it also contains the MAC commands and ack for the relay.
+func (b *broker) relayDownlink(downlink *pb.DownlinkMessage) (*pb.DownlinkMessage, error) {
+	if b.relayDownlinks == nil || downlink.GetDownlinkOption() == nil {
+		return nil, nil
+	}
+	value, err := b.relayDownlinks.Get(downlink.DownlinkOption.Identifier)
+	if err != nil {
+		return nil, nil
+	}
+	response := value.(*relayResponse)
+	if downlink.AppId == response.relay.AppId && downlink.DevId == response.relay.DevId {
+		return nil, nil // the response to the relay itself
+	}
+	template, ok := response.take()
+	if !ok {
+		return nil, errors.NewErrInvalidArgument("Downlink", fmt.Sprintf("response to relay %s was already sent", response.relay.DevId))
+	}
+	b.relayDownlinks.Remove(downlink.DownlinkOption.Identifier)
+
+	mac := template.Message.GetLorawan().GetMacPayload()
+	mac.FPort = pb_lorawan.RelayFPort
+	mac.FrmPayload = downlink.Payload
+
+	template.DownlinkOption = downlink.DownlinkOption
+	template.Trace = response.trace.WithEvent(trace.RelayEvent, "relay", response.relay.DevId)
+	template.Trace.Parents = append(template.Trace.Parents, downlink.Trace)
+	return template, nil
+}
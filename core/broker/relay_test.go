@@ -0,0 +1,108 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestRelayUplink(t *testing.T) {
+	a := New(t)
+
+	devAddr := types.DevAddr([4]byte{1, 2, 3, 4})
+	relay := &pb_lorawan.Device{AppId: "app", DevId: "relay", DevAddr: &devAddr}
+	uplink := &pb.DeduplicatedUplinkMessage{
+		ProtocolMetadata: &pb_protocol.RxMetadata{Protocol: &pb_protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{
+			Modulation: pb_lorawan.Modulation_LORA,
+			DataRate:   "SF9BW125",
+			CodingRate: "4/5",
+			Region:     pb_lorawan.Region_EU_863_870,
+		}}},
+		GatewayMetadata:  []*pb_gateway.RxMetadata{{GatewayId: "gateway", Timestamp: 1000}},
+		ResponseTemplate: &pb.DownlinkMessage{DownlinkOption: &pb.DownlinkOption{Identifier: "router:1"}},
+	}
+
+	req := pb_lorawan.ForwardUplinkReq{DataRate: 5, RSSI: -100, SNR: 3, Frequency: 868300000, Payload: []byte{0x40, 1, 2, 3, 4}}
+	payload, _ := req.MarshalBinary()
+	relayed, relayMetadata, err := relayedUplink(relay, payload, uplink)
+	a.So(err, ShouldBeNil)
+	a.So(relayMetadata.DevId, ShouldEqual, "relay")
+	a.So(relayed.Payload, ShouldResemble, req.Payload)
+	a.So(relayed.ProtocolMetadata.GetLorawan().DataRate, ShouldEqual, "SF7BW125")
+	a.So(relayed.GatewayMetadata.GatewayId, ShouldEqual, "relay-relay")
+	a.So(relayed.GatewayMetadata.Frequency, ShouldEqual, 868300000)
+	a.So(relayed.GatewayMetadata.Rssi, ShouldEqual, -100)
+	a.So(relayed.GatewayMetadata.Timestamp, ShouldEqual, 1000)
+	a.So(relayed.DownlinkOptions, ShouldHaveLength, 1)
+
+	req.DataRate = 15
+	payload, _ = req.MarshalBinary()
+	_, _, err = relayedUplink(relay, payload, uplink)
+	a.So(err, ShouldNotBeNil)
+
+	_, _, err = relayedUplink(relay, []byte{1, 2}, uplink)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestRelayDownlink(t *testing.T) {
+	a := New(t)
+	b := &broker{relayDownlinks: newRelayDownlinks()}
+
+	devAddr := types.DevAddr([4]byte{1, 2, 3, 4})
+	relay := &pb_lorawan.Device{AppId: "app", DevId: "relay", DevAddr: &devAddr}
+	uplink := &pb.DeduplicatedUplinkMessage{
+		AppId:            "app",
+		DevId:            "relay",
+		ResponseTemplate: &pb.DownlinkMessage{DownlinkOption: &pb.DownlinkOption{Identifier: "router:1"}},
+	}
+	template := uplink.InitResponseTemplate()
+	mac := template.Message.InitLoRaWAN().InitDownlink()
+	mac.DevAddr = devAddr
+	mac.FPort = pb_lorawan.RelayFPort
+	mac.Ack = true
+	template.MacCommandsAdded = true
+	response := newRelayResponse(relay, uplink)
+	a.So(response.needed, ShouldBeTrue)
+	b.relayDownlinks.Set("router:1", response)
+
+	downlink := &pb.DownlinkMessage{
+		AppId:          "app",
+		DevId:          "device",
+		Payload:        []byte{0x60, 5, 6, 7, 8},
+		DownlinkOption: &pb.DownlinkOption{Identifier: "router:2"},
+	}
+	relayDownlink, err := b.relayDownlink(downlink)
+	a.So(err, ShouldBeNil)
+	a.So(relayDownlink, ShouldBeNil)
+
+	// The response to the relay itself is not relayed
+	relayDownlink, err = b.relayDownlink(template)
+	a.So(err, ShouldBeNil)
+	a.So(relayDownlink, ShouldBeNil)
+
+	downlink.DownlinkOption.Identifier = "router:1"
+	relayDownlink, err = b.relayDownlink(downlink)
+	a.So(err, ShouldBeNil)
+	a.So(relayDownlink.DevId, ShouldEqual, "relay")
+	a.So(relayDownlink.DownlinkOption, ShouldEqual, downlink.DownlinkOption)
+	mac = relayDownlink.Message.GetLorawan().GetMacPayload()
+	a.So(mac.DevAddr, ShouldEqual, devAddr)
+	a.So(mac.Ack, ShouldBeTrue)
+	a.So(mac.FPort, ShouldEqual, pb_lorawan.RelayFPort)
+	a.So(mac.FrmPayload, ShouldResemble, downlink.Payload)
+
+	// The response is sent once
+	_, ok := response.take()
+	a.So(ok, ShouldBeFalse)
+	b.relayDownlinks.Set("router:1", response)
+	_, err = b.relayDownlink(downlink)
+	a.So(err, ShouldNotBeNil)
+}
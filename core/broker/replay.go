@@ -0,0 +1,165 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+)
+
+// SetHandlerReplay makes the Broker keep the uplink messages of each Handler,
+// up to the size and age, until the Handler acknowledges them, and replay them
+// when it subscribes again. Replay is disabled if the size is 0.
+func (b *broker) SetHandlerReplay(size int, age time.Duration) {
+	b.replaySize = size
+	b.replayAge = age
+}
+
+type sequencedUplink struct {
+	seq    uint64
+	time   time.Time
+	uplink *pb.DeduplicatedUplinkMessage
+}
+
+// replayBuffer keeps the recent uplink messages of a Handler with their
+// sequence numbers, until the Handler acknowledges them
+type replayBuffer struct {
+	sync.Mutex
+	size     int
+	age      time.Duration
+	next     uint64 // sequence number of the next message
+	acked    uint64 // sequence number up to which the Handler acknowledged the messages
+	messages []*sequencedUplink
+}
+
+func newReplayBuffer(size int, age time.Duration) *replayBuffer {
+	return &replayBuffer{size: size, age: age, next: 1}
+}
+
+// trim removes the messages that were acknowledged or that exceed the size or
+// age of the buffer
+func (r *replayBuffer) trim(now time.Time) {
+	drop := 0
+	if len(r.messages) > r.size {
+		drop = len(r.messages) - r.size
+	}
+	for drop < len(r.messages) && (r.messages[drop].seq <= r.acked || (r.age > 0 && now.Sub(r.messages[drop].time) > r.age)) {
+		drop++
+	}
+	if drop > 0 {
+		r.messages = append(r.messages[:0], r.messages[drop:]...)
+	}
+}
+
+// add assigns the next sequence number to the uplink message and keeps it
+func (r *replayBuffer) add(uplink *pb.DeduplicatedUplinkMessage) *sequencedUplink {
+	r.Lock()
+	defer r.Unlock()
+	msg := &sequencedUplink{seq: r.next, time: time.Now(), uplink: uplink}
+	r.next++
+	r.messages = append(r.messages, msg)
+	r.trim(msg.time)
+	return msg
+}
+
+// ack records that the Handler handled the messages up to the sequence number
+func (r *replayBuffer) ack(seq uint64) {
+	r.Lock()
+	defer r.Unlock()
+	if seq >= r.next {
+		seq = r.next - 1
+	}
+	if seq > r.acked {
+		r.acked = seq
+	}
+	r.trim(time.Now())
+}
+
+// pending returns the messages that the Handler did not acknowledge
+func (r *replayBuffer) pending() []*sequencedUplink {
+	r.Lock()
+	defer r.Unlock()
+	r.trim(time.Now())
+	return append([]*sequencedUplink(nil), r.messages...)
+}
+
+// getHandlerReplay gets or creates the replay buffer of the Handler
+func (b *broker) getHandlerReplay(id string) *replayBuffer {
+	hdl := b.getHandler(id)
+	hdl.Lock()
+	defer hdl.Unlock()
+	if hdl.replay == nil {
+		hdl.replay = newReplayBuffer(b.replaySize, b.replayAge)
+	}
+	return hdl.replay
+}
+
+// sequenceHandlerUplink numbers the uplink messages of the Handler and keeps
+// them in its replay buffer until the Handler acknowledges them. It first
+// sends the messages that were not acknowledged. After stop is called,
+// messages are only kept in the replay buffer.
+func (b *broker) sequenceHandlerUplink(id string, in <-chan *pb.DeduplicatedUplinkMessage) (out <-chan *pb.DeduplicatedUplinkMessage, stop func()) {
+	replay := b.getHandlerReplay(id)
+	pending := replay.pending()
+	if len(pending) > 0 {
+		b.Ctx.WithField("HandlerID", id).WithField("Messages", len(pending)).Info("Replaying uplink messages to Handler")
+	}
+
+	ch := make(chan *pb.DeduplicatedUplinkMessage)
+	done := make(chan struct{})
+	go func() {
+		defer close(ch)
+		send := func(msg *sequencedUplink) bool {
+			uplink := *msg.uplink
+			uplink.Seq = msg.seq
+			select {
+			case ch <- &uplink:
+				return true
+			case <-done:
+				return false
+			}
+		}
+		for _, msg := range pending {
+			if !send(msg) {
+				break
+			}
+		}
+		for uplink := range in {
+			msg := replay.add(uplink)
+			select {
+			case <-done:
+				continue // keep the message for the next subscription
+			default:
+			}
+			send(msg)
+		}
+	}()
+
+	return ch, func() { close(done) }
+}
+
+// ackHandlerUplink removes the uplink messages up to the sequence number from
+// the replay buffer of the Handler
+func (b *broker) ackHandlerUplink(id string, seq uint64) {
+	hdl := b.getHandler(id)
+	hdl.Lock()
+	replay := hdl.replay
+	hdl.Unlock()
+	if replay != nil {
+		replay.ack(seq)
+	}
+}
+
+// bufferHandlerUplink keeps the uplink message for a Handler that is not
+// subscribed, so that it is sent when the Handler subscribes again. It
+// returns false if the Broker does not keep messages for the Handler.
+func (b *broker) bufferHandlerUplink(hdl *handler, uplink *pb.DeduplicatedUplinkMessage) bool {
+	if hdl.replay == nil {
+		return false
+	}
+	hdl.replay.add(uplink)
+	return true
+}
@@ -0,0 +1,54 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"time"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// handleReplayAttack sends the replayed frame through the NetworkServer, which
+// quarantines the device, and forwards it to the Handler, which publishes a
+// security event. The Handler does not process the payload of the replay.
+func (b *broker) handleReplayAttack(ctx ttnlog.Interface, device *pb_lorawan.Device, duplicates []*pb.UplinkMessage, distance float64) {
+	b.status.replayAttacks.Inc(1)
+	ctx = ctx.WithFields(ttnlog.Fields{
+		"AppID":    device.AppId,
+		"DevID":    device.DevId,
+		"Distance": int(distance),
+	})
+	ctx.Warn("Possible replay attack")
+
+	gateways := make([]string, 0, len(duplicates))
+	replay := &pb.DeduplicatedUplinkMessage{
+		Payload:          duplicates[0].Payload,
+		ProtocolMetadata: duplicates[0].ProtocolMetadata,
+		DevEui:           device.DevEui,
+		AppEui:           device.AppEui,
+		AppId:            device.AppId,
+		DevId:            device.DevId,
+		ServerTime:       time.Now().UnixNano(),
+	}
+	for _, duplicate := range duplicates {
+		replay.GatewayMetadata = append(replay.GatewayMetadata, duplicate.GatewayMetadata)
+		gateways = append(gateways, duplicate.GatewayMetadata.GetGatewayId())
+	}
+	replay.ReplayAttack = &pb.ReplayAttack{
+		Distance: uint32(distance),
+		Gateways: gateways,
+	}
+
+	replay, err := b.ns.Uplink(b.Component.GetContext(b.nsToken), replay)
+	if err != nil {
+		ctx.WithError(errors.FromGRPCError(err)).Warn("NetworkServer did not quarantine device")
+		return
+	}
+	if err := b.forwardUplink(device.AppId, replay); err != nil {
+		ctx.WithError(err).Warn("Could not forward replay attack to Handler")
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_networkserver "github.com/TheThingsNetwork/ttn/api/networkserver"
+	"github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/assertions"
+)
+
+var (
+	amsterdam = &gateway.GPSMetadata{Latitude: 52.37, Longitude: 4.89}
+	utrecht   = &gateway.GPSMetadata{Latitude: 52.09, Longitude: 5.12}
+	berlin    = &gateway.GPSMetadata{Latitude: 52.52, Longitude: 13.40}
+)
+
+func TestIsReplayAttack(t *testing.T) {
+	a := New(t)
+
+	frame := frameKey{devAddr: types.DevAddr{1, 2, 3, 4}, fCnt: 1, mic: lorawan.MIC{1, 2, 3, 4}}
+	gateways := func(locations ...*gateway.GPSMetadata) (metadata []*gateway.RxMetadata) {
+		for _, location := range locations {
+			metadata = append(metadata, &gateway.RxMetadata{Gps: location})
+		}
+		return
+	}
+
+	// Disabled
+	b := getTestBroker(t)
+	b.SetMICCheck(DefaultMICCheckOptions)
+	b.micCheck.accept(frame, &pb_lorawan.Device{}, gateways(amsterdam))
+	accepted, _ := b.micCheck.isReplay(frame)
+	_, attack := b.micCheck.isReplayAttack(accepted, gateways(berlin))
+	a.So(attack, ShouldBeFalse)
+
+	options := DefaultMICCheckOptions
+	options.ReplayDistance = 100000
+	b.SetMICCheck(options)
+	b.micCheck.accept(frame, &pb_lorawan.Device{}, gateways(amsterdam, nil))
+	accepted, _ = b.micCheck.isReplay(frame)
+	a.So(accepted.locations, ShouldHaveLength, 1)
+
+	// Nearby gateways and gateways without location are no attack
+	_, attack = b.micCheck.isReplayAttack(accepted, gateways(utrecht, nil))
+	a.So(attack, ShouldBeFalse)
+
+	distance, attack := b.micCheck.isReplayAttack(accepted, gateways(utrecht, berlin))
+	a.So(attack, ShouldBeTrue)
+	a.So(distance, ShouldBeBetween, 550000, 600000)
+
+	// Frames without gateway locations can not be checked
+	b.micCheck.accept(frame, &pb_lorawan.Device{}, gateways(nil))
+	accepted, _ = b.micCheck.isReplay(frame)
+	_, attack = b.micCheck.isReplayAttack(accepted, gateways(berlin))
+	a.So(attack, ShouldBeFalse)
+}
+
+func TestHandleUplinkReplayAttack(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	options := DefaultMICCheckOptions
+	options.ReplayDistance = 100000
+	b.SetMICCheck(options)
+	b.handlers["handlerID"] = &handler{uplink: make(chan *pb.DeduplicatedUplinkMessage, 10)}
+
+	devEUI := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	nwkSKey := types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	phy, _ := buildMICTestPayload(1, nwkSKey)
+	bytes, _ := phy.MarshalBinary()
+	uplink := func(gps *gateway.GPSMetadata) *pb.UplinkMessage {
+		return &pb.UplinkMessage{
+			Payload:          bytes,
+			GatewayMetadata:  &gateway.RxMetadata{GatewayId: "eui-0102030405060708", Gps: gps},
+			ProtocolMetadata: &protocol.RxMetadata{Protocol: &protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{}}},
+		}
+	}
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{{Id: "handlerID"}}, nil).Times(2)
+
+	// The original frame is accepted
+	b.ns.EXPECT().GetDevices(gomock.Any(), gomock.Any()).Return(&pb_networkserver.DevicesResponse{
+		Results: []*pb_lorawan.Device{{DevEui: &devEUI, AppEui: &appEUI, AppId: "app", DevId: "dev", NwkSKey: &nwkSKey}},
+	}, nil)
+	b.ns.EXPECT().Uplink(gomock.Any(), gomock.Any()).Return(&pb.DeduplicatedUplinkMessage{}, nil)
+	a.So(b.HandleUplink(uplink(amsterdam)), ShouldBeNil)
+	<-b.handlers["handlerID"].uplink
+
+	// A repetition of the device is dropped as duplicate, even if it is received far away
+	b.uplinkDeduplicator = NewDeduplicator(10 * time.Millisecond)
+	a.So(b.HandleUplink(uplink(berlin)), ShouldBeNil)
+	a.So(b.GetRejectedUplinks().Replayed, ShouldEqual, 0)
+	a.So(b.GetRejectedUplinks().ReplayAttacks, ShouldEqual, 0)
+
+	// After the retransmission window, identical frames are replays
+	accepted, _ := b.micCheck.isReplay(frameKey{devAddr: types.DevAddr{1, 2, 3, 4}, fCnt: 1, mic: phy.MIC})
+	accepted.acceptedAt = time.Now().Add(-2 * options.RetransmissionWindow)
+
+	// A replay nearby is only rejected
+	b.uplinkDeduplicator = NewDeduplicator(10 * time.Millisecond)
+	a.So(b.HandleUplink(uplink(utrecht)), ShouldNotBeNil)
+	a.So(b.GetRejectedUplinks().Replayed, ShouldEqual, 1)
+	a.So(b.GetRejectedUplinks().ReplayAttacks, ShouldEqual, 0)
+
+	// A replay far away is sent to the NetworkServer and the Handler
+	b.uplinkDeduplicator = NewDeduplicator(10 * time.Millisecond)
+	b.ns.EXPECT().Uplink(gomock.Any(), gomock.Any()).Return(&pb.DeduplicatedUplinkMessage{DevId: "dev"}, nil)
+	a.So(b.HandleUplink(uplink(berlin)), ShouldNotBeNil)
+	a.So(b.GetRejectedUplinks().Replayed, ShouldEqual, 2)
+	a.So(b.GetRejectedUplinks().ReplayAttacks, ShouldEqual, 1)
+	replay := <-b.handlers["handlerID"].uplink
+	a.So(replay.DevId, ShouldEqual, "dev")
+	a.So(replay.Trace.Event, ShouldEqual, trace.ForwardEvent)
+}
@@ -0,0 +1,85 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestReplayBuffer(t *testing.T) {
+	a := New(t)
+	r := newReplayBuffer(3, time.Minute)
+
+	for i := 0; i < 5; i++ {
+		r.add(&pb.DeduplicatedUplinkMessage{AppId: "app"})
+	}
+	a.So(r.messages, ShouldHaveLength, 3)
+
+	pending := r.pending()
+	a.So(pending, ShouldHaveLength, 3)
+	a.So(pending[0].seq, ShouldEqual, 3)
+
+	r.ack(4)
+	pending = r.pending()
+	a.So(pending, ShouldHaveLength, 1)
+	a.So(pending[0].seq, ShouldEqual, 5)
+
+	// Messages that were not sent yet can not be acknowledged
+	r.ack(10)
+	a.So(r.acked, ShouldEqual, 5)
+	a.So(r.pending(), ShouldBeEmpty)
+
+	r.add(&pb.DeduplicatedUplinkMessage{AppId: "app"})
+	r.add(&pb.DeduplicatedUplinkMessage{AppId: "app"})
+	r.messages[0].time = time.Now().Add(-2 * time.Minute)
+	r.trim(time.Now())
+	a.So(r.messages, ShouldHaveLength, 1)
+}
+
+func TestSequenceHandlerUplink(t *testing.T) {
+	a := New(t)
+	b := getTestBroker(t)
+	b.SetHandlerReplay(10, time.Minute)
+
+	subscribe := func() (<-chan *pb.DeduplicatedUplinkMessage, func()) {
+		in, err := b.ActivateHandlerUplink("handler")
+		a.So(err, ShouldBeNil)
+		out, stop := b.sequenceHandlerUplink("handler", in)
+		return out, func() {
+			stop()
+			b.DeactivateHandlerUplink("handler")
+		}
+	}
+
+	out, cancel := subscribe()
+	go b.sendHandlerUplink("handler", &pb.DeduplicatedUplinkMessage{DevId: "1"})
+	msg := <-out
+	a.So(msg.DevId, ShouldEqual, "1")
+	a.So(msg.Seq, ShouldEqual, 1)
+	go b.sendHandlerUplink("handler", &pb.DeduplicatedUplinkMessage{DevId: "2"})
+	a.So((<-out).Seq, ShouldEqual, 2)
+	cancel()
+
+	// The Handler is not subscribed, the uplink is kept
+	a.So(b.sendHandlerUplink("handler", &pb.DeduplicatedUplinkMessage{DevId: "3"}), ShouldBeNil)
+
+	// The Handler only handled the first message
+	b.ackHandlerUplink("handler", 1)
+	out, cancel = subscribe()
+	defer cancel()
+	msg = <-out
+	a.So(msg.DevId, ShouldEqual, "2")
+	a.So(msg.Seq, ShouldEqual, 2)
+	a.So((<-out).DevId, ShouldEqual, "3")
+}
+
+func TestSendHandlerUplinkWithoutReplay(t *testing.T) {
+	a := New(t)
+	b := getTestBroker(t)
+	a.So(b.sendHandlerUplink("handler", &pb.DeduplicatedUplinkMessage{}), ShouldNotBeNil)
+}
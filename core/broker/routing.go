@@ -0,0 +1,112 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"sync"
+	"time"
+
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+)
+
+// RoutingTableExpiration is the time after which the routing table is built
+// again, also if the Discovery server did not send a change of the announcements
+var RoutingTableExpiration = 5 * time.Minute
+
+// routingRetryDelay is the delay before watching the changes again after the stream ended
+var routingRetryDelay = 5 * time.Second
+
+// routingTable contains the Handlers of each AppID, so that the Broker does
+// not need to go through all Handler announcements for every message
+type routingTable struct {
+	mu       sync.RWMutex
+	handlers map[string][]*pb_discovery.Announcement
+	built    time.Time
+}
+
+// SetRoutingTable enables or disables the routing table of the Broker. The
+// routing table is invalidated when the Discovery server sends a change of a
+// Handler announcement, and is built again after RoutingTableExpiration.
+func (b *broker) SetRoutingTable(enabled bool) {
+	if !enabled {
+		b.routing = nil
+		return
+	}
+	b.routing = new(routingTable)
+}
+
+// getHandlersForAppID returns the announcements of the Handlers of the AppID
+func (b *broker) getHandlersForAppID(appID string) ([]*pb_discovery.Announcement, error) {
+	if b.routing == nil {
+		return b.Discovery.GetAllHandlersForAppID(appID)
+	}
+	return b.routing.getHandlers(b.Discovery, appID)
+}
+
+func (t *routingTable) getHandlers(discovery pb_discovery.Client, appID string) ([]*pb_discovery.Announcement, error) {
+	t.mu.RLock()
+	if t.handlers != nil && time.Since(t.built) < RoutingTableExpiration {
+		handlers := t.handlers[appID]
+		t.mu.RUnlock()
+		return handlers, nil
+	}
+	t.mu.RUnlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.handlers == nil || time.Since(t.built) >= RoutingTableExpiration {
+		announcements, err := discovery.GetAll("handler")
+		if err != nil {
+			return nil, err
+		}
+		handlers := make(map[string][]*pb_discovery.Announcement)
+		for _, announcement := range announcements {
+			for _, appID := range announcement.AppIDs() {
+				handlers[appID] = append(handlers[appID], announcement)
+			}
+		}
+		t.handlers, t.built = handlers, time.Now()
+	}
+	return t.handlers[appID], nil
+}
+
+func (t *routingTable) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers = nil
+}
+
+// handleRoutingChange invalidates the routing table if the announcement of a Handler changed
+func (b *broker) handleRoutingChange(change *pb_discovery.AnnouncementChange) {
+	if change.ServiceName != "handler" {
+		return
+	}
+	b.Ctx.WithField("HandlerID", change.Id).Debug("Invalidating routing table")
+	b.routing.invalidate()
+}
+
+// watchRoutingChanges watches the changes of the Handler announcements on the Discovery server
+func (b *broker) watchRoutingChanges() {
+	if b.routing == nil {
+		return
+	}
+	go func() {
+		for {
+			changes, err := b.Discovery.Watch("handler")
+			if err != nil {
+				b.Ctx.WithError(err).Warn("Could not watch announcement changes")
+				time.Sleep(routingRetryDelay)
+				continue
+			}
+			for change := range changes {
+				b.handleRoutingChange(change)
+			}
+			b.Ctx.Warn("Stopped watching announcement changes")
+			// Changes may have been missed while not watching
+			b.Discovery.Invalidate("handler")
+			b.routing.invalidate()
+			time.Sleep(routingRetryDelay)
+		}
+	}()
+}
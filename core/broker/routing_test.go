@@ -0,0 +1,62 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestRoutingTable(t *testing.T) {
+	a := New(t)
+	b := getTestBroker(t)
+	defer b.ctrl.Finish()
+
+	handler := func(id string, appIDs ...string) *pb_discovery.Announcement {
+		announcement := &pb_discovery.Announcement{ServiceName: "handler", Id: id}
+		for _, appID := range appIDs {
+			announcement.Metadata = append(announcement.Metadata, &pb_discovery.Metadata{
+				Metadata: &pb_discovery.Metadata_AppId{AppId: appID},
+			})
+		}
+		return announcement
+	}
+
+	// Without routing table
+	b.discovery.EXPECT().GetAllHandlersForAppID("app-1").Return([]*pb_discovery.Announcement{handler("handler-1", "app-1")}, nil)
+	handlers, err := b.getHandlersForAppID("app-1")
+	a.So(err, ShouldBeNil)
+	a.So(handlers, ShouldHaveLength, 1)
+
+	// The routing table is built once
+	b.SetRoutingTable(true)
+	b.discovery.EXPECT().GetAll("handler").Return([]*pb_discovery.Announcement{
+		handler("handler-1", "app-1", "app-2"),
+		handler("handler-2", "app-3"),
+	}, nil)
+	handlers, err = b.getHandlersForAppID("app-2")
+	a.So(err, ShouldBeNil)
+	a.So(handlers, ShouldHaveLength, 1)
+	a.So(handlers[0].Id, ShouldEqual, "handler-1")
+	handlers, _ = b.getHandlersForAppID("app-3")
+	a.So(handlers[0].Id, ShouldEqual, "handler-2")
+	handlers, _ = b.getHandlersForAppID("app-4")
+	a.So(handlers, ShouldBeEmpty)
+
+	// Changes of other services are ignored
+	b.handleRoutingChange(&pb_discovery.AnnouncementChange{ServiceName: "router", Id: "router-1"})
+	handlers, _ = b.getHandlersForAppID("app-3")
+	a.So(handlers[0].Id, ShouldEqual, "handler-2")
+
+	// A change of a Handler invalidates the routing table
+	b.handleRoutingChange(&pb_discovery.AnnouncementChange{ServiceName: "handler", Id: "handler-2"})
+	b.discovery.EXPECT().GetAll("handler").Return([]*pb_discovery.Announcement{
+		handler("handler-1", "app-1", "app-2", "app-3"),
+	}, nil)
+	handlers, _ = b.getHandlersForAppID("app-3")
+	a.So(handlers, ShouldHaveLength, 1)
+	a.So(handlers[0].Id, ShouldEqual, "handler-1")
+}
@@ -9,6 +9,7 @@ import (
 	pb "github.com/TheThingsNetwork/ttn/api/broker"
 	"github.com/TheThingsNetwork/ttn/api/ratelimit"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/golang/protobuf/ptypes/empty"
 	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -65,11 +66,21 @@ func (b *brokerRPC) getHandlerSubscribe(md metadata.MD) (<-chan *pb.Deduplicated
 		return nil, nil, err
 	}
 
+	if b.broker.replaySize == 0 {
+		cancel := func() {
+			b.broker.DeactivateHandlerUplink(handler.Id)
+		}
+		return ch, cancel, nil
+	}
+
+	sequenced, stop := b.broker.sequenceHandlerUplink(handler.Id, ch)
+
 	cancel := func() {
+		stop()
 		b.broker.DeactivateHandlerUplink(handler.Id)
 	}
 
-	return ch, cancel, nil
+	return sequenced, cancel, nil
 }
 
 func (b *brokerRPC) getHandlerPublish(md metadata.MD) (chan *pb.DownlinkMessage, error) {
@@ -88,15 +99,22 @@ func (b *brokerRPC) getHandlerPublish(md metadata.MD) (chan *pb.DownlinkMessage,
 				if err != nil {
 					return
 				}
+				if b.broker.isShadowHandler(downlink.AppEui, handler.Id) {
+					b.broker.Ctx.WithField("HandlerID", handler.Id).WithField("AppEUI", downlink.AppEui).Warn("Shadow Handler can not schedule downlink")
+					return
+				}
+				serves := b.broker.isMigrationHandler(downlink.AppId, handler.Id)
 				for _, announcedID := range handler.AppIDs() {
 					if announcedID == downlink.AppId {
-						if waitTime := b.handlerDownRate.Wait(handler.Id); waitTime != 0 {
-							b.broker.Ctx.WithField("HandlerID", handler.Id).WithField("Wait", waitTime).Warn("Handler reached downlink rate limit")
-							time.Sleep(waitTime)
-						}
-						b.broker.HandleDownlink(downlink)
-						return
+						serves = true
+					}
+				}
+				if serves {
+					if waitTime := b.handlerDownRate.Wait(handler.Id); waitTime != 0 {
+						b.broker.Ctx.WithField("HandlerID", handler.Id).WithField("Wait", waitTime).Warn("Handler reached downlink rate limit")
+						time.Sleep(waitTime)
 					}
+					b.broker.HandleDownlink(downlink)
 				}
 			}(message)
 		}
@@ -104,6 +122,15 @@ func (b *brokerRPC) getHandlerPublish(md metadata.MD) (chan *pb.DownlinkMessage,
 	return ch, nil
 }
 
+func (b *brokerRPC) Acknowledge(ctx context.Context, ack *pb.SubscribeAck) (*empty.Empty, error) {
+	handler, err := b.broker.ValidateNetworkContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.broker.ackHandlerUplink(handler.Id, ack.Seq)
+	return &empty.Empty{}, nil
+}
+
 func (b *brokerRPC) Activate(ctx context.Context, req *pb.DeviceActivationRequest) (res *pb.DeviceActivationResponse, err error) {
 	_, err = b.broker.ValidateNetworkContext(ctx)
 	if err != nil {
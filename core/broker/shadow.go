@@ -0,0 +1,95 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ShadowHandlers maps AppEUIs to the IDs of their read-only shadow Handlers
+type ShadowHandlers map[types.AppEUI][]string
+
+// ParseShadowHandlers parses shadow Handlers in the format AppEUI=HandlerID
+func ParseShadowHandlers(values []string) (ShadowHandlers, error) {
+	shadowHandlers := make(ShadowHandlers)
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, errors.NewErrInvalidArgument("Shadow Handler", fmt.Sprintf("%s is not in the format AppEUI=HandlerID", value))
+		}
+		appEUI, err := types.ParseAppEUI(parts[0])
+		if err != nil {
+			return nil, errors.NewErrInvalidArgument("Shadow Handler", err.Error())
+		}
+		shadowHandlers[appEUI] = append(shadowHandlers[appEUI], parts[1])
+	}
+	return shadowHandlers, nil
+}
+
+// SetShadowHandlers sets the read-only shadow Handlers of AppEUIs. Shadow
+// Handlers receive copies of the uplink messages of the devices with these
+// AppEUIs, but they can not schedule downlinks or handle activations.
+func (b *broker) SetShadowHandlers(shadowHandlers ShadowHandlers) {
+	b.shadowHandlers = shadowHandlers
+}
+
+// isShadowHandler returns true if the Handler is a shadow Handler of the AppEUI
+func (b *broker) isShadowHandler(appEUI *types.AppEUI, handlerID string) bool {
+	if appEUI == nil {
+		return false
+	}
+	for _, id := range b.shadowHandlers[*appEUI] {
+		if id == handlerID {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryHandlers returns the announced Handlers that are not shadow Handlers of the AppEUI
+func (b *broker) primaryHandlers(appEUI *types.AppEUI, announcements []*pb_discovery.Announcement) []*pb_discovery.Announcement {
+	if len(b.shadowHandlers) == 0 {
+		return announcements
+	}
+	primary := make([]*pb_discovery.Announcement, 0, len(announcements))
+	for _, announcement := range announcements {
+		if !b.isShadowHandler(appEUI, announcement.Id) {
+			primary = append(primary, announcement)
+		}
+	}
+	return primary
+}
+
+// forwardShadowUplink forwards a copy of the uplink message without response
+// template to the shadow Handlers of the AppEUI that are connected
+func (b *broker) forwardShadowUplink(uplink *pb.DeduplicatedUplinkMessage) {
+	if uplink.AppEui == nil {
+		return
+	}
+	for _, id := range b.shadowHandlers[*uplink.AppEui] {
+		handler, err := b.getHandlerUplink(id)
+		if err != nil {
+			b.Ctx.WithField("HandlerID", id).WithError(err).Debug("Could not forward uplink to shadow Handler")
+			continue
+		}
+		shadow := *uplink
+		shadow.ResponseTemplate = nil
+		shadow.Trace = uplink.Trace.WithEvent(trace.ForwardEvent,
+			"handler", id,
+			"shadow", true,
+		)
+		select {
+		case handler <- &shadow:
+		default:
+			b.Ctx.WithField("HandlerID", id).Warn("Shadow Handler not consuming uplink messages fast enough")
+		}
+	}
+}
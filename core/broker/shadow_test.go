@@ -0,0 +1,78 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParseShadowHandlers(t *testing.T) {
+	a := New(t)
+
+	shadowHandlers, err := ParseShadowHandlers([]string{
+		"0102030405060708=shadow-1",
+		"0102030405060708=shadow-2",
+		"0807060504030201=shadow-1",
+	})
+	a.So(err, ShouldBeNil)
+	a.So(shadowHandlers[types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}], ShouldResemble, []string{"shadow-1", "shadow-2"})
+	a.So(shadowHandlers[types.AppEUI{8, 7, 6, 5, 4, 3, 2, 1}], ShouldResemble, []string{"shadow-1"})
+
+	_, err = ParseShadowHandlers([]string{"0102030405060708"})
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseShadowHandlers([]string{"0102030405060708="})
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseShadowHandlers([]string{"appeui=shadow-1"})
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestForwardShadowUplink(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	defer b.ctrl.Finish()
+
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	otherAppEUI := types.AppEUI{8, 7, 6, 5, 4, 3, 2, 1}
+	b.SetShadowHandlers(ShadowHandlers{appEUI: {"shadow", "offline"}})
+	b.handlers["primary"] = &handler{uplink: make(chan *pb.DeduplicatedUplinkMessage, 10)}
+	b.handlers["shadow"] = &handler{uplink: make(chan *pb.DeduplicatedUplinkMessage, 10)}
+
+	a.So(b.isShadowHandler(&appEUI, "shadow"), ShouldBeTrue)
+	a.So(b.isShadowHandler(&appEUI, "primary"), ShouldBeFalse)
+	a.So(b.isShadowHandler(&otherAppEUI, "shadow"), ShouldBeFalse)
+	a.So(b.isShadowHandler(nil, "shadow"), ShouldBeFalse)
+
+	// The shadow Handler also announces the application, but is not its primary Handler
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{
+		{Id: "primary"},
+		{Id: "shadow"},
+	}, nil)
+	err := b.forwardUplink("app", &pb.DeduplicatedUplinkMessage{
+		AppEui:           &appEUI,
+		AppId:            "app",
+		ResponseTemplate: &pb.DownlinkMessage{AppId: "app"},
+	})
+	a.So(err, ShouldBeNil)
+
+	primary := <-b.handlers["primary"].uplink
+	a.So(primary.ResponseTemplate, ShouldNotBeNil)
+	shadow := <-b.handlers["shadow"].uplink
+	a.So(shadow.ResponseTemplate, ShouldBeNil)
+	a.So(shadow.Trace.Metadata["shadow"], ShouldEqual, "true")
+
+	// Other AppEUIs are not forwarded to the shadow Handler
+	b.discovery.EXPECT().GetAllHandlersForAppID("app").Return([]*pb_discovery.Announcement{
+		{Id: "primary"},
+	}, nil)
+	err = b.forwardUplink("app", &pb.DeduplicatedUplinkMessage{AppEui: &otherAppEUI, AppId: "app"})
+	a.So(err, ShouldBeNil)
+	<-b.handlers["primary"].uplink
+	a.So(b.handlers["shadow"].uplink, ShouldBeEmpty)
+}
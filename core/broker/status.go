@@ -17,6 +17,11 @@ type status struct {
 	activations       metrics.Meter
 	activationsUnique metrics.Meter
 	deduplication     metrics.Histogram
+	rejectedReplay    metrics.Counter
+	rejectedMIC       metrics.Counter
+	replayAttacks     metrics.Counter
+	rejectedTenant    metrics.Counter
+	rejectedThrottled metrics.Counter
 	connectedRouters  metrics.Gauge
 	connectedHandlers metrics.Gauge
 }
@@ -29,6 +34,11 @@ func (b *broker) InitStatus() {
 		activations:       metrics.NewMeter(),
 		activationsUnique: metrics.NewMeter(),
 		deduplication:     metrics.NewHistogram(metrics.NewUniformSample(512)),
+		rejectedReplay:    metrics.NewCounter(),
+		rejectedMIC:       metrics.NewCounter(),
+		replayAttacks:     metrics.NewCounter(),
+		rejectedTenant:    metrics.NewCounter(),
+		rejectedThrottled: metrics.NewCounter(),
 		connectedRouters: metrics.NewFunctionalGauge(func() int64 {
 			b.routersLock.RLock()
 			defer b.routersLock.RUnlock()
@@ -95,3 +105,25 @@ func (b *broker) GetStatus() *pb.Status {
 	status.ConnectedHandlers = uint32(b.status.connectedHandlers.Snapshot().Value())
 	return status
 }
+
+// RejectedUplinks are the numbers of uplink messages that were rejected by the MIC and tenant checks
+type RejectedUplinks struct {
+	Replayed      int64 `json:"replayed"`
+	InvalidMIC    int64 `json:"invalid_mic"`
+	ReplayAttacks int64 `json:"replay_attacks"` // replays that were received far away from the original frame
+	OtherTenant   int64 `json:"other_tenant"`   // uplinks that were only received by gateways of other tenants
+	Throttled     int64 `json:"throttled"`      // uplinks of gateways that forwarded too many frames with an invalid MIC
+}
+
+func (b *broker) GetRejectedUplinks() RejectedUplinks {
+	if b.status == nil {
+		return RejectedUplinks{}
+	}
+	return RejectedUplinks{
+		Replayed:      b.status.rejectedReplay.Count(),
+		InvalidMIC:    b.status.rejectedMIC.Count(),
+		ReplayAttacks: b.status.replayAttacks.Count(),
+		OtherTenant:   b.status.rejectedTenant.Count(),
+		Throttled:     b.status.rejectedThrottled.Count(),
+	}
+}
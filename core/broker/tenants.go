@@ -0,0 +1,34 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// SetTenants isolates the gateways of the tenants of a deployment: uplink
+// messages that are received by the gateways of a tenant are only forwarded
+// to the applications of that tenant
+func (b *broker) SetTenants(tenants types.Tenants) {
+	b.tenants = tenants
+}
+
+// tenantDuplicates returns the duplicates of an uplink message that were
+// received by gateways that are shared or that belong to the tenant of the
+// application. Downlinks are only scheduled on these gateways.
+func (b *broker) tenantDuplicates(appID string, duplicates []*pb.UplinkMessage) []*pb.UplinkMessage {
+	if len(b.tenants) == 0 {
+		return duplicates
+	}
+	tenant := b.tenants.ForApplication(appID)
+	allowed := make([]*pb.UplinkMessage, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		owner := b.tenants.ForGateway(duplicate.GatewayMetadata.GetGatewayId())
+		if owner == nil || owner == tenant {
+			allowed = append(allowed, duplicate)
+		}
+	}
+	return allowed
+}
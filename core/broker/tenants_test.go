@@ -0,0 +1,44 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestTenantDuplicates(t *testing.T) {
+	a := New(t)
+	b := &broker{}
+
+	duplicates := []*pb.UplinkMessage{
+		{GatewayMetadata: &gateway.RxMetadata{GatewayId: "shared-gw"}},
+		{GatewayMetadata: &gateway.RxMetadata{GatewayId: "acme-gw-1"}},
+		{GatewayMetadata: &gateway.RxMetadata{GatewayId: "globex-gw-1"}},
+	}
+
+	// Without tenants all gateways are shared
+	a.So(b.tenantDuplicates("acme-sensors", duplicates), ShouldHaveLength, 3)
+
+	tenants, _ := types.ParseTenants(map[string]string{
+		"acme":   "applications=acme-*;gateways=acme-gw-*",
+		"globex": "applications=globex-*;gateways=globex-gw-*",
+	})
+	b.SetTenants(tenants)
+
+	allowed := b.tenantDuplicates("acme-sensors", duplicates)
+	a.So(allowed, ShouldHaveLength, 2)
+	a.So(allowed[0].GatewayMetadata.GatewayId, ShouldEqual, "shared-gw")
+	a.So(allowed[1].GatewayMetadata.GatewayId, ShouldEqual, "acme-gw-1")
+
+	allowed = b.tenantDuplicates("other", duplicates)
+	a.So(allowed, ShouldHaveLength, 1)
+	a.So(allowed[0].GatewayMetadata.GatewayId, ShouldEqual, "shared-gw")
+
+	a.So(b.tenantDuplicates("globex-sensors", duplicates[1:2]), ShouldBeEmpty)
+}
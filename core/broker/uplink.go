@@ -4,32 +4,37 @@
 package broker
 
 import (
-	"crypto/md5"
-	"encoding/hex"
 	"fmt"
 	"sort"
 	"time"
 
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	pb "github.com/TheThingsNetwork/ttn/api/broker"
-	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
 	"github.com/TheThingsNetwork/ttn/api/fields"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
 	"github.com/TheThingsNetwork/ttn/api/networkserver"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/api/trace"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
-	"github.com/TheThingsNetwork/ttn/utils/fcnt"
+	"github.com/TheThingsNetwork/ttn/utils/fault"
 	"github.com/brocaar/lorawan"
 )
 
 const maxFCntGap = 16384
 
-func (b *broker) HandleUplink(uplink *pb.UplinkMessage) (err error) {
+func (b *broker) HandleUplink(uplink *pb.UplinkMessage) error {
+	return b.handleUplink(uplink, nil)
+}
+
+// handleUplink handles an uplink message. Uplinks that a relay forwarded are
+// not deduplicated, as that already happened for the uplink of the relay.
+func (b *broker) handleUplink(uplink *pb.UplinkMessage, relay *pb.RelayMetadata) (err error) {
 	ctx := b.Ctx.WithFields(fields.Get(uplink))
 	start := time.Now()
 	deduplicatedUplink := new(pb.DeduplicatedUplinkMessage)
 	deduplicatedUplink.ServerTime = start.UnixNano()
+	var duplicates []*pb.UplinkMessage
 	defer func() {
 		if err != nil {
 			if deduplicatedUplink != nil {
@@ -45,18 +50,32 @@ func (b *broker) HandleUplink(uplink *pb.UplinkMessage) (err error) {
 				go monitor.SendUplink(deduplicatedUplink)
 			}
 		}
+		b.exportUplink(duplicates, deduplicatedUplink)
 	}()
 
 	b.status.uplink.Mark(1)
 
 	uplink.Trace = uplink.Trace.WithEvent(trace.ReceiveEvent)
 
-	// De-duplicate uplink messages
-	duplicates := b.deduplicateUplink(uplink)
-	if len(duplicates) == 0 {
-		return nil
+	if relay != nil {
+		ctx = ctx.WithField("Relay", relay.DevId)
+		deduplicatedUplink.Relay = relay
+		duplicates = []*pb.UplinkMessage{uplink}
+	} else {
+		// Drop uplinks of gateways that forwarded too many frames with an invalid MIC
+		if !b.uplinkGuard.allow(uplink, start) {
+			b.status.rejectedThrottled.Inc(1)
+			return errors.NewErrPermissionDenied(fmt.Sprintf("Gateway %s forwarded too many frames with an invalid MIC", uplink.GetGatewayMetadata().GetGatewayId()))
+		}
+
+		// De-duplicate uplink messages
+		duplicates = b.deduplicateUplink(uplink)
+		if len(duplicates) == 0 {
+			return nil
+		}
 	}
 	ctx = ctx.WithField("Duplicates", len(duplicates))
+	b.rankDuplicates(duplicates)
 
 	b.status.uplinkUnique.Mark(1)
 
@@ -86,12 +105,47 @@ func (b *broker) HandleUplink(uplink *pb.UplinkMessage) (err error) {
 		return errors.NewErrInvalidArgument("Uplink", "does not contain a MAC payload")
 	}
 
-	// Request devices from NS
 	devAddr := types.DevAddr(macPayload.FHDR.DevAddr)
+	originalFCnt := macPayload.FHDR.FCnt
 	ctx = ctx.WithFields(ttnlog.Fields{
 		"DevAddr": devAddr,
-		"FCnt":    macPayload.FHDR.FCnt,
+		"FCnt":    originalFCnt,
 	})
+
+	// Reject replayed frames. Retries of confirmed uplink are identical, so they are accepted.
+	// Repetitions of unconfirmed uplink are identical as well, so they are dropped as duplicates.
+	frame := frameKey{devAddr: devAddr, fCnt: originalFCnt, mic: phyPayload.MIC}
+	if accepted, replay := b.micCheck.isReplay(frame); replay && phyPayload.MHDR.MType != lorawan.ConfirmedDataUp {
+		if b.micCheck.isRetransmission(accepted, start) {
+			ctx.Debug("Dropping repetition of uplink")
+			duplicates = nil
+			return nil
+		}
+		b.status.rejectedReplay.Inc(1)
+		if distance, attack := b.micCheck.isReplayAttack(accepted, gatewayMetadata(duplicates)); attack {
+			b.handleReplayAttack(ctx, accepted.device, duplicates, distance)
+		}
+		return errors.NewErrInvalidArgument("Uplink", "replayed frame")
+	}
+
+	// Reject frames that don't validate with the devices that were recently returned by the NS
+	if candidates, ok := b.micCheck.getDevices(devAddr); ok {
+		var candidate *pb_lorawan.Device
+		candidate, _, err = findDeviceByMIC(&phyPayload, macPayload, candidates)
+		if err != nil {
+			return err
+		}
+		if candidate == nil {
+			b.status.rejectedMIC.Inc(1)
+			if relay == nil {
+				b.uplinkGuard.chargeInvalidMIC(duplicates, start)
+			}
+			return errors.NewErrNotFound("device that validates MIC")
+		}
+		macPayload.FHDR.FCnt = originalFCnt
+	}
+
+	// Request devices from NS
 	var getDevicesResp *networkserver.DevicesResponse
 	getDevicesResp, err = b.ns.GetDevices(b.Component.GetContext(b.nsToken), &networkserver.DevicesRequest{
 		DevAddr: &devAddr,
@@ -101,6 +155,7 @@ func (b *broker) HandleUplink(uplink *pb.UplinkMessage) (err error) {
 		return errors.Wrap(errors.FromGRPCError(err), "NetworkServer did not return devices")
 	}
 	b.status.deduplication.Update(int64(len(getDevicesResp.Results)))
+	b.micCheck.setDevices(devAddr, getDevicesResp.Results)
 	if len(getDevicesResp.Results) == 0 {
 		return errors.NewErrNotFound(fmt.Sprintf("Device with DevAddr %s and FCnt <= %d", devAddr, macPayload.FHDR.FCnt))
 	}
@@ -115,39 +170,15 @@ func (b *broker) HandleUplink(uplink *pb.UplinkMessage) (err error) {
 	// Find AppEUI/DevEUI through MIC check
 	var device *pb_lorawan.Device
 	var micChecks int
-	originalFCnt := macPayload.FHDR.FCnt
-	for _, candidate := range getDevicesResp.Results {
-		nwkSKey := lorawan.AES128Key(*candidate.NwkSKey)
-
-		// First check with the 16 bit counter
-		micChecks++
-		ok, err = phyPayload.ValidateMIC(nwkSKey)
-		if err != nil {
-			return err
-		}
-		if ok {
-			device = candidate
-			break
-		}
-
-		if candidate.Uses32BitFCnt {
-			macPayload.FHDR.FCnt = fcnt.GetFull(candidate.FCntUp, uint16(originalFCnt))
-
-			// If 32 bit counter has different value, perform another MIC check
-			if macPayload.FHDR.FCnt != originalFCnt {
-				micChecks++
-				ok, err = phyPayload.ValidateMIC(nwkSKey)
-				if err != nil {
-					return err
-				}
-				if ok {
-					device = candidate
-					break
-				}
-			}
-		}
+	device, micChecks, err = findDeviceByMIC(&phyPayload, macPayload, getDevicesResp.Results)
+	if err != nil {
+		return err
 	}
 	if device == nil {
+		b.status.rejectedMIC.Inc(1)
+		if relay == nil {
+			b.uplinkGuard.chargeInvalidMIC(duplicates, start)
+		}
 		return errors.NewErrNotFound("device that validates MIC")
 	}
 
@@ -182,6 +213,9 @@ func (b *broker) HandleUplink(uplink *pb.UplinkMessage) (err error) {
 		}
 		fallthrough
 	case macPayload.FHDR.FCnt <= device.FCntUp:
+		if macPayload.FHDR.FCnt < device.FCntUp && relay == nil {
+			b.handleFCntRegression(ctx, device, deduplicatedUplink, duplicates, macPayload.FHDR.FCnt)
+		}
 		return errors.NewErrInvalidArgument("FCnt", "not high enough")
 	case macPayload.FHDR.FCnt-device.FCntUp > maxFCntGap:
 		return errors.NewErrInvalidArgument("FCnt", "too high")
@@ -189,64 +223,106 @@ func (b *broker) HandleUplink(uplink *pb.UplinkMessage) (err error) {
 		return errors.NewErrInternal("FCnt check failed")
 	}
 
+	// Only use the gateways that are shared or that belong to the tenant of the application
+	duplicates = b.tenantDuplicates(device.AppId, duplicates)
+	if len(duplicates) == 0 {
+		b.status.rejectedTenant.Inc(1)
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Application %s does not have access to the gateways", device.AppId))
+	}
+
 	// Add FCnt to Metadata (because it's not marshaled in lorawan payload)
 	deduplicatedUplink.ProtocolMetadata.GetLorawan().FCnt = macPayload.FHDR.FCnt
 
 	// Collect GatewayMetadata and DownlinkOptions
 	var downlinkOptions []*pb.DownlinkOption
-	for _, duplicate := range duplicates {
+	var delayed bool
+	for _, duplicate := range b.mergedDuplicates(duplicates) {
 		deduplicatedUplink.GatewayMetadata = append(deduplicatedUplink.GatewayMetadata, duplicate.GatewayMetadata)
+		if duplicate.Delayed {
+			// The receive windows of the device have passed
+			delayed = true
+			continue
+		}
 		downlinkOptions = append(downlinkOptions, duplicate.DownlinkOptions...)
 	}
 
-	// Select best DownlinkOption
+	b.micCheck.accept(frame, device, deduplicatedUplink.GatewayMetadata)
+
+	// Select best DownlinkOption, the Handler selects another option if it does not satisfy the scheduling hints of the downlink
 	if len(downlinkOptions) > 0 {
+		sort.Sort(ByScore(downlinkOptions))
+		deduplicatedUplink.DownlinkOptions = downlinkOptions
 		deduplicatedUplink.ResponseTemplate = &pb.DownlinkMessage{
 			DevEui:         device.DevEui,
 			AppEui:         device.AppEui,
 			AppId:          device.AppId,
 			DevId:          device.DevId,
-			DownlinkOption: selectBestDownlink(downlinkOptions),
+			DownlinkOption: b.selectDownlink(device.AppId, device.DevId, device.GatewayGroups, downlinkOptions),
 		}
+	} else if !delayed {
+		// The Handler reports this when it has a downlink for the device
+		deduplicatedUplink.NoDownlink = true
+		deduplicatedUplink.Trace = deduplicatedUplink.Trace.WithEvent(trace.NoDownlinkEvent)
 	}
 
 	// Pass Uplink through NS
-	deduplicatedUplink, err = b.ns.Uplink(b.Component.GetContext(b.nsToken), deduplicatedUplink)
+	if err = fault.Inject(fault.BrokerToNetworkServer); err != nil {
+		return errors.Wrap(err, "NetworkServer did not handle uplink")
+	}
+	var nsUplink *pb.DeduplicatedUplinkMessage
+	nsUplink, err = b.ns.Uplink(b.Component.GetContext(b.nsToken), deduplicatedUplink)
 	if err != nil {
 		return errors.Wrap(errors.FromGRPCError(err), "NetworkServer did not handle uplink")
 	}
+	deduplicatedUplink = nsUplink
+
+	// Relays forward the uplinks of end devices, which are handled as uplinks of their own
+	if deduplicatedUplink.RelayForward {
+		return b.handleRelayUplink(ctx, device, &phyPayload, deduplicatedUplink)
+	}
+
+	return b.forwardUplink(device.AppId, deduplicatedUplink)
+}
 
-	var announcements []*pb_discovery.Announcement
-	announcements, err = b.Discovery.GetAllHandlersForAppID(device.AppId)
+// forwardUplink forwards the uplink message to the Handler of the application
+func (b *broker) forwardUplink(appID string, uplink *pb.DeduplicatedUplinkMessage) error {
+	announcements, err := b.getHandlersForAppID(appID)
 	if err != nil {
 		return err
 	}
+	announcements = b.primaryHandlers(uplink.AppEui, announcements)
 	if len(announcements) == 0 {
-		return errors.NewErrNotFound(fmt.Sprintf("Handler for AppID %s", device.AppId))
+		return errors.NewErrNotFound(fmt.Sprintf("Handler for AppID %s", appID))
 	}
 	if len(announcements) > 1 {
-		return errors.NewErrInternal(fmt.Sprintf("Multiple Handlers for AppID %s", device.AppId))
+		return errors.NewErrInternal(fmt.Sprintf("Multiple Handlers for AppID %s", appID))
 	}
 
-	var handler chan<- *pb.DeduplicatedUplinkMessage
-	handler, err = b.getHandlerUplink(announcements[0].Id)
-	if err != nil {
-		return err
+	handlerID := announcements[0].Id
+	if toHandler, ok := b.migratedHandler(appID, uplink.DevId, handlerID); ok {
+		handlerID = toHandler
 	}
 
-	deduplicatedUplink.Trace = deduplicatedUplink.Trace.WithEvent(trace.ForwardEvent,
-		"handler", announcements[0].Id,
+	b.forwardShadowUplink(uplink)
+
+	uplink.Trace = uplink.Trace.WithEvent(trace.ForwardEvent,
+		"handler", handlerID,
 	)
 
-	handler <- deduplicatedUplink
+	return b.sendHandlerUplink(handlerID, uplink)
+}
 
-	return nil
+// gatewayMetadata returns the gateway metadata of the duplicates of an uplink message
+func gatewayMetadata(duplicates []*pb.UplinkMessage) []*pb_gateway.RxMetadata {
+	metadata := make([]*pb_gateway.RxMetadata, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		metadata = append(metadata, duplicate.GatewayMetadata)
+	}
+	return metadata
 }
 
 func (b *broker) deduplicateUplink(duplicate *pb.UplinkMessage) (uplinks []*pb.UplinkMessage) {
-	sum := md5.Sum(duplicate.Payload)
-	key := hex.EncodeToString(sum[:])
-	list := b.uplinkDeduplicator.Deduplicate(key, duplicate)
+	list := b.uplinkDeduplicator.Deduplicate(deduplicationKey(duplicate), duplicate)
 	if len(list) == 0 {
 		return
 	}
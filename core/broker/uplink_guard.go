@@ -0,0 +1,173 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+)
+
+// SetInvalidMICRate configures the number of frames with an invalid MIC per
+// minute that an untrusted gateway can forward as the only gateway that
+// received them. Gateways that exceed this rate are throttled: their uplinks
+// are dropped before deduplication until their token bucket refills. Trusted
+// gateways are never throttled.
+func (b *broker) SetInvalidMICRate(rate int) {
+	if rate <= 0 {
+		b.uplinkGuard = nil
+		return
+	}
+	b.uplinkGuard = &uplinkGuard{
+		rate:    float64(rate),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// deduplicationKey returns the key under which the duplicates of an uplink
+// are collected. Next to the payload, it contains the modulation and data
+// rate, so that a gateway that forwards a valid payload with other metadata
+// does not end up in the same collection.
+func deduplicationKey(uplink *pb.UplinkMessage) string {
+	hash := md5.New()
+	hash.Write(uplink.Payload)
+	if lorawan := uplink.GetProtocolMetadata().GetLorawan(); lorawan != nil {
+		fmt.Fprintf(hash, "%s:%s:%d", lorawan.Modulation, lorawan.DataRate, lorawan.BitRate)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// uplinkGuard keeps a token bucket for each untrusted gateway that forwarded
+// frames with an invalid MIC. Every such frame takes a token; the buckets
+// refill at the rate per minute.
+type uplinkGuard struct {
+	rate float64
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+func (g *uplinkGuard) refill(bucket *tokenBucket, now time.Time) {
+	bucket.tokens += now.Sub(bucket.updated).Minutes() * g.rate
+	if bucket.tokens > g.rate {
+		bucket.tokens = g.rate
+	}
+	bucket.updated = now
+}
+
+// allow returns false if the uplinks of the gateway are throttled
+func (g *uplinkGuard) allow(uplink *pb.UplinkMessage, now time.Time) bool {
+	if g == nil || uplink.GetGatewayMetadata().GetGatewayTrusted() {
+		return true
+	}
+	gatewayID := uplink.GetGatewayMetadata().GetGatewayId()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	bucket, ok := g.buckets[gatewayID]
+	if !ok {
+		return true
+	}
+	g.refill(bucket, now)
+	if bucket.tokens >= g.rate {
+		delete(g.buckets, gatewayID)
+	}
+	return bucket.tokens >= 1
+}
+
+// chargeInvalidMIC takes a token from the untrusted gateway that forwarded a
+// frame with an invalid MIC. A frame that multiple gateways received was
+// transmitted by a device, for example of another network, and not injected
+// by a gateway, so then no gateway is charged.
+func (g *uplinkGuard) chargeInvalidMIC(duplicates []*pb.UplinkMessage, now time.Time) {
+	if g == nil || len(duplicates) == 0 {
+		return
+	}
+	gatewayID := duplicates[0].GetGatewayMetadata().GetGatewayId()
+	for _, duplicate := range duplicates {
+		if duplicate.GetGatewayMetadata().GetGatewayId() != gatewayID {
+			return
+		}
+	}
+	if duplicates[0].GetGatewayMetadata().GetGatewayTrusted() {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	bucket, ok := g.buckets[gatewayID]
+	if !ok {
+		bucket = &tokenBucket{tokens: g.rate, updated: now}
+		g.buckets[gatewayID] = bucket
+	}
+	g.refill(bucket, now)
+	bucket.tokens--
+	if bucket.tokens < 0 {
+		bucket.tokens = 0
+	}
+}
+
+// suspect returns true if the gateway recently forwarded frames with an invalid MIC
+func (g *uplinkGuard) suspect(gatewayID string) bool {
+	if g == nil {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.buckets[gatewayID]
+	return ok
+}
+
+// rankDuplicates orders the duplicates of a frame by the trust in their
+// gateways: trusted gateways first, suspect gateways last. The protocol
+// metadata of the deduplicated uplink is taken from the first duplicate.
+func (b *broker) rankDuplicates(duplicates []*pb.UplinkMessage) {
+	rank := func(duplicate *pb.UplinkMessage) int {
+		switch {
+		case duplicate.GetGatewayMetadata().GetGatewayTrusted():
+			return 0
+		case b.uplinkGuard.suspect(duplicate.GetGatewayMetadata().GetGatewayId()):
+			return 2
+		default:
+			return 1
+		}
+	}
+	sort.Stable(byRank{duplicates, rank})
+}
+
+// mergedDuplicates returns the duplicates of which the gateway metadata and
+// downlink options are merged into the deduplicated uplink. The duplicates of
+// suspect gateways are left out, unless only suspect gateways forwarded the
+// frame, so that they can not inject crafted metadata.
+func (b *broker) mergedDuplicates(duplicates []*pb.UplinkMessage) []*pb.UplinkMessage {
+	merged := make([]*pb.UplinkMessage, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		if !b.uplinkGuard.suspect(duplicate.GetGatewayMetadata().GetGatewayId()) {
+			merged = append(merged, duplicate)
+		}
+	}
+	if len(merged) == 0 {
+		return duplicates
+	}
+	return merged
+}
+
+type byRank struct {
+	duplicates []*pb.UplinkMessage
+	rank       func(*pb.UplinkMessage) int
+}
+
+func (a byRank) Len() int      { return len(a.duplicates) }
+func (a byRank) Swap(i, j int) { a.duplicates[i], a.duplicates[j] = a.duplicates[j], a.duplicates[i] }
+func (a byRank) Less(i, j int) bool {
+	return a.rank(a.duplicates[i]) < a.rank(a.duplicates[j])
+}
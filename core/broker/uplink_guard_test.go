@@ -0,0 +1,136 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package broker
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_networkserver "github.com/TheThingsNetwork/ttn/api/networkserver"
+	"github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/assertions"
+)
+
+func guardTestUplink(gatewayID string, trusted bool, dataRate string) *pb.UplinkMessage {
+	return &pb.UplinkMessage{
+		Payload:          []byte{1, 2, 3, 4},
+		GatewayMetadata:  &gateway.RxMetadata{GatewayId: gatewayID, GatewayTrusted: trusted},
+		ProtocolMetadata: &protocol.RxMetadata{Protocol: &protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{DataRate: dataRate}}},
+	}
+}
+
+func TestDeduplicationKey(t *testing.T) {
+	a := New(t)
+	a.So(deduplicationKey(guardTestUplink("gtw-1", false, "SF7BW125")), ShouldEqual, deduplicationKey(guardTestUplink("gtw-2", true, "SF7BW125")))
+	a.So(deduplicationKey(guardTestUplink("gtw-1", false, "SF7BW125")), ShouldNotEqual, deduplicationKey(guardTestUplink("gtw-1", false, "SF12BW125")))
+}
+
+func TestUplinkGuard(t *testing.T) {
+	a := New(t)
+	b := &broker{}
+
+	// Disabled
+	b.SetInvalidMICRate(0)
+	a.So(b.uplinkGuard.allow(guardTestUplink("gtw", false, ""), time.Now()), ShouldBeTrue)
+	b.uplinkGuard.chargeInvalidMIC([]*pb.UplinkMessage{guardTestUplink("gtw", false, "")}, time.Now())
+
+	b.SetInvalidMICRate(2)
+	now := time.Now()
+	untrusted, trusted := guardTestUplink("untrusted", false, ""), guardTestUplink("trusted", true, "")
+
+	// Frames that multiple gateways received are not charged
+	b.uplinkGuard.chargeInvalidMIC([]*pb.UplinkMessage{untrusted, trusted}, now)
+	a.So(b.uplinkGuard.suspect("untrusted"), ShouldBeFalse)
+
+	b.uplinkGuard.chargeInvalidMIC([]*pb.UplinkMessage{trusted}, now)
+	a.So(b.uplinkGuard.suspect("trusted"), ShouldBeFalse)
+
+	b.uplinkGuard.chargeInvalidMIC([]*pb.UplinkMessage{untrusted}, now)
+	a.So(b.uplinkGuard.suspect("untrusted"), ShouldBeTrue)
+	a.So(b.uplinkGuard.allow(untrusted, now), ShouldBeTrue)
+
+	// A gateway that forwards the frame twice is charged once
+	b.uplinkGuard.chargeInvalidMIC([]*pb.UplinkMessage{untrusted, untrusted}, now)
+	a.So(b.uplinkGuard.allow(untrusted, now), ShouldBeFalse)
+	a.So(b.uplinkGuard.allow(trusted, now), ShouldBeTrue)
+
+	// The bucket refills at the rate per minute
+	a.So(b.uplinkGuard.allow(untrusted, now.Add(20*time.Second)), ShouldBeFalse)
+	a.So(b.uplinkGuard.allow(untrusted, now.Add(30*time.Second)), ShouldBeTrue)
+	a.So(b.uplinkGuard.suspect("untrusted"), ShouldBeTrue)
+	a.So(b.uplinkGuard.allow(untrusted, now.Add(time.Minute)), ShouldBeTrue)
+	a.So(b.uplinkGuard.suspect("untrusted"), ShouldBeFalse)
+}
+
+func TestRankDuplicates(t *testing.T) {
+	a := New(t)
+	b := &broker{}
+	b.SetInvalidMICRate(10)
+	b.uplinkGuard.chargeInvalidMIC([]*pb.UplinkMessage{guardTestUplink("suspect", false, "")}, time.Now())
+
+	duplicates := []*pb.UplinkMessage{
+		guardTestUplink("suspect", false, ""),
+		guardTestUplink("untrusted-1", false, ""),
+		guardTestUplink("trusted", true, ""),
+		guardTestUplink("untrusted-2", false, ""),
+	}
+	b.rankDuplicates(duplicates)
+	var ids []string
+	for _, duplicate := range duplicates {
+		ids = append(ids, duplicate.GatewayMetadata.GatewayId)
+	}
+	a.So(ids, ShouldResemble, []string{"trusted", "untrusted-1", "untrusted-2", "suspect"})
+
+	// The metadata of suspect gateways is not merged
+	merged := b.mergedDuplicates(duplicates)
+	a.So(merged, ShouldHaveLength, 3)
+	a.So(merged, ShouldNotContain, duplicates[3])
+	suspect := []*pb.UplinkMessage{guardTestUplink("suspect", false, "")}
+	a.So(b.mergedDuplicates(suspect), ShouldResemble, suspect)
+}
+
+func TestHandleUplinkInvalidMICRate(t *testing.T) {
+	a := New(t)
+
+	b := getTestBroker(t)
+	options := DefaultMICCheckOptions
+	options.NwkSKeyCheck = true
+	b.SetMICCheck(options)
+	b.SetInvalidMICRate(1)
+
+	nwkSKey := types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	phy, _ := buildMICTestPayload(1, nwkSKey)
+	bytes, _ := phy.MarshalBinary()
+	uplink := func(trusted bool) *pb.UplinkMessage {
+		b.uplinkDeduplicator = NewDeduplicator(10 * time.Millisecond)
+		return &pb.UplinkMessage{
+			Payload:          bytes,
+			GatewayMetadata:  &gateway.RxMetadata{GatewayId: "eui-0102030405060708", GatewayTrusted: trusted},
+			ProtocolMetadata: &protocol.RxMetadata{Protocol: &protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{}}},
+		}
+	}
+
+	b.ns.EXPECT().GetDevices(gomock.Any(), gomock.Any()).Return(&pb_networkserver.DevicesResponse{}, nil)
+	a.So(b.HandleUplink(uplink(false)), ShouldNotBeNil)
+
+	// The frame does not validate with the cached devices
+	a.So(b.HandleUplink(uplink(false)), ShouldNotBeNil)
+	a.So(b.GetRejectedUplinks().InvalidMIC, ShouldEqual, 1)
+	a.So(b.GetRejectedUplinks().Throttled, ShouldEqual, 0)
+
+	// The gateway is throttled
+	a.So(b.HandleUplink(uplink(false)), ShouldNotBeNil)
+	a.So(b.GetRejectedUplinks().InvalidMIC, ShouldEqual, 1)
+	a.So(b.GetRejectedUplinks().Throttled, ShouldEqual, 1)
+
+	// Trusted gateways are not throttled
+	a.So(b.HandleUplink(uplink(true)), ShouldNotBeNil)
+	a.So(b.GetRejectedUplinks().InvalidMIC, ShouldEqual, 2)
+	a.So(b.GetRejectedUplinks().Throttled, ShouldEqual, 1)
+}
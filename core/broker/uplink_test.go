@@ -104,15 +104,22 @@ func TestHandleUplink(t *testing.T) {
 	phy.SetMIC(lorawan.AES128Key{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8})
 	bytes, _ = phy.MarshalBinary()
 
-	// Wrong FCnt
+	// Wrong FCnt is forwarded to the Handler as regression
 	b.uplinkDeduplicator = NewDeduplicator(10 * time.Millisecond)
 	b.ns.EXPECT().GetDevices(gomock.Any(), gomock.Any()).Return(nsResponse, nil)
+	b.discovery.EXPECT().GetAllHandlersForAppID("appid-1").Return([]*pb_discovery.Announcement{
+		&pb_discovery.Announcement{
+			Id: "handlerID",
+		},
+	}, nil)
 	err = b.HandleUplink(&pb.UplinkMessage{
 		Payload:          bytes,
 		GatewayMetadata:  &gateway.RxMetadata{Snr: 1.2, GatewayId: gtwID},
 		ProtocolMetadata: &protocol.RxMetadata{Protocol: &protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{}}},
 	})
 	a.So(err, ShouldHaveSameTypeAs, &errors.ErrInvalidArgument{})
+	regression := <-b.handlers["handlerID"].uplink
+	a.So(regression.FcntRegression, ShouldResemble, &pb.FCntRegression{FCnt: 1, LastFCnt: 3})
 
 	// Disable FCnt Check
 	b.uplinkDeduplicator = NewDeduplicator(10 * time.Millisecond)
@@ -10,14 +10,18 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/TheThingsNetwork/go-account-lib/claims"
 	"github.com/TheThingsNetwork/go-account-lib/tokenkey"
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
 	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
 	pb_monitor "github.com/TheThingsNetwork/ttn/api/monitor"
 	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/alerting"
+	"github.com/TheThingsNetwork/ttn/utils/logging"
 	"github.com/spf13/viper"
 	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
 	"google.golang.org/grpc"
@@ -30,6 +34,7 @@ type Component struct {
 	Identity         *pb_discovery.Announcement
 	Discovery        pb_discovery.Client
 	Monitors         pb_monitor.Registry
+	Alerts           *alerting.Manager
 	Ctx              ttnlog.Interface
 	AccessToken      string
 	privateKey       *ecdsa.PrivateKey
@@ -37,6 +42,8 @@ type Component struct {
 	TokenKeyProvider tokenkey.Provider
 	status           int64
 	healthServer     *health.Server
+	healthOnce       sync.Once
+	debug            debug
 }
 
 type Interface interface {
@@ -53,6 +60,8 @@ type ManagementInterface interface {
 
 // New creates a new Component
 func New(ctx ttnlog.Interface, serviceName string, announcedAddress string) (*Component, error) {
+	ctx = ctx.WithField(logging.ComponentField, serviceName)
+
 	go func() {
 		memstats := new(runtime.MemStats)
 		for range time.Tick(time.Minute) {
@@ -119,6 +128,28 @@ func New(ctx ttnlog.Interface, serviceName string, announcedAddress string) (*Co
 		go http.ListenAndServe(fmt.Sprintf(":%d", healthPort), nil)
 	}
 
+	if debugPort := viper.GetInt("debug-port"); debugPort > 0 {
+		component.debug.token = viper.GetString("debug-token")
+		if component.debug.token == "" {
+			return nil, fmt.Errorf("A debug token is required to start the debug server")
+		}
+		if logging.DefaultHandler != nil {
+			component.RegisterAdminHandler("log-config", logging.DefaultHandler)
+		}
+		go http.ListenAndServe(fmt.Sprintf(":%d", debugPort), component.DebugHandler())
+	}
+
+	var notifiers []alerting.Notifier
+	for _, url := range viper.GetStringSlice("alert-webhooks") {
+		notifiers = append(notifiers, alerting.NewWebhookNotifier(url))
+	}
+	if emails := viper.GetStringSlice("alert-emails"); len(emails) > 0 {
+		notifiers = append(notifiers, alerting.NewEmailNotifier(viper.GetString("alert-smtp-server"), viper.GetString("alert-smtp-from"), emails))
+	}
+	component.Alerts = alerting.NewManager(ctx, component.Identity.Id, notifiers...)
+
+	component.RegisterDebugInfo("stream-restarts", func() interface{} { return api.StreamRestarts() })
+
 	component.Monitors = pb_monitor.NewRegistry(ctx)
 	for name, addr := range viper.GetStringMapString("monitor-servers") {
 		go component.Monitors.InitClient(name, addr)
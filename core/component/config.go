@@ -7,9 +7,10 @@ import "github.com/spf13/viper"
 
 // Config is the configuration for this component
 type Config struct {
-	AuthServers map[string]string
-	KeyDir      string
-	UseTLS      bool
+	AuthServers          map[string]string
+	KeyDir               string
+	UseTLS               bool
+	MaxConcurrentStreams uint32
 }
 
 // ConfigFromViper imports configuration from Viper
@@ -18,5 +19,7 @@ func ConfigFromViper() Config {
 		AuthServers: viper.GetStringMapString("auth-servers"),
 		KeyDir:      viper.GetString("key-dir"),
 		UseTLS:      viper.GetBool("tls"),
+
+		MaxConcurrentStreams: uint32(viper.GetInt("grpc-max-concurrent-streams")),
 	}
 }
@@ -0,0 +1,252 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package component
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/fault"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+)
+
+// DebugInfo returns a JSON-serializable snapshot of the state of a component
+type DebugInfo func() interface{}
+
+type debug struct {
+	token string
+	mu    sync.Mutex
+	info  map[string]DebugInfo
+	// admin contains the HTTP handlers of the admin APIs by name
+	admin map[string]http.Handler
+	// streams contains the number of open gRPC streams per method
+	streams map[string]int
+}
+
+// secretConfigKeys are the substrings of config keys whose values are hidden in the config dump
+var secretConfigKeys = []string{"token", "key", "secret", "password", "pass"}
+
+// RegisterDebugInfo makes the info available on the debug endpoint under /debug/<name>
+func (c *Component) RegisterDebugInfo(name string, info DebugInfo) {
+	c.debug.mu.Lock()
+	defer c.debug.mu.Unlock()
+	if c.debug.info == nil {
+		c.debug.info = make(map[string]DebugInfo)
+	}
+	c.debug.info[name] = info
+}
+
+// RegisterAdminHandler makes the admin API available on the debug endpoint under /admin/<name>/
+func (c *Component) RegisterAdminHandler(name string, handler http.Handler) {
+	c.debug.mu.Lock()
+	defer c.debug.mu.Unlock()
+	if c.debug.admin == nil {
+		c.debug.admin = make(map[string]http.Handler)
+	}
+	c.debug.admin[name] = http.StripPrefix("/admin/"+name, handler)
+}
+
+// RegisterDebugServer registers gRPC server reflection if the debug endpoint is enabled.
+// Reflection requests must contain the admin token in the "debug-token" metadata.
+func (c *Component) RegisterDebugServer(srv *grpc.Server) {
+	if c.debug.token == "" {
+		return
+	}
+	reflection.Register(srv)
+}
+
+func (c *Component) streamCounts() map[string]int {
+	c.debug.mu.Lock()
+	defer c.debug.mu.Unlock()
+	counts := make(map[string]int, len(c.debug.streams))
+	for method, count := range c.debug.streams {
+		if count > 0 {
+			counts[method] = count
+		}
+	}
+	return counts
+}
+
+func (c *Component) countStream(method string, delta int) {
+	c.debug.mu.Lock()
+	defer c.debug.mu.Unlock()
+	if c.debug.streams == nil {
+		c.debug.streams = make(map[string]int)
+	}
+	c.debug.streams[method] += delta
+}
+
+func (c *Component) streamDebug(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if strings.HasPrefix(info.FullMethod, "/grpc.reflection.") && !c.validDebugContext(stream.Context()) {
+		return errors.NewErrPermissionDenied("Invalid debug token")
+	}
+	c.countStream(info.FullMethod, 1)
+	defer c.countStream(info.FullMethod, -1)
+	return handler(srv, stream)
+}
+
+func (c *Component) validDebugToken(token string) bool {
+	return c.debug.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(c.debug.token)) == 1
+}
+
+// ValidDebugContext returns true if the context contains the debug token of
+// the component, which only the operator of the component has
+func (c *Component) ValidDebugContext(ctx context.Context) bool {
+	return c.validDebugContext(ctx)
+}
+
+func (c *Component) validDebugContext(ctx context.Context) bool {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	token, ok := md["debug-token"]
+	return ok && len(token) > 0 && c.validDebugToken(token[0])
+}
+
+func writeDebugJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// configDump returns the settings of the component with the values of secrets hidden
+func configDump() map[string]interface{} {
+	dump := make(map[string]interface{})
+	for _, key := range viper.AllKeys() {
+		value := viper.Get(key)
+		for _, secret := range secretConfigKeys {
+			if strings.Contains(strings.ToLower(key), secret) {
+				value = "<hidden>"
+				break
+			}
+		}
+		dump[key] = value
+	}
+	return dump
+}
+
+// The profiles are served with runtime/pprof, as net/http/pprof registers its
+// handlers on the http.DefaultServeMux, which is served without authentication
+// on the health port.
+
+// pprofIndex lists the profiles, or writes the profile with the name in the path
+func pprofIndex(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/debug/pprof/")
+	if name == "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, profile := range pprof.Profiles() {
+			fmt.Fprintf(w, "%s %d\n", profile.Name(), profile.Count())
+		}
+		return
+	}
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		http.NotFound(w, r)
+		return
+	}
+	debug, _ := strconv.Atoi(r.URL.Query().Get("debug"))
+	if debug == 0 {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	if name == "heap" && r.URL.Query().Get("gc") != "" {
+		runtime.GC()
+	}
+	profile.WriteTo(w, debug)
+}
+
+func pprofSeconds(r *http.Request, fallback int) time.Duration {
+	seconds, err := strconv.Atoi(r.URL.Query().Get("seconds"))
+	if err != nil || seconds <= 0 {
+		seconds = fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// pprofCPU writes a CPU profile of the number of seconds in the query (default 30)
+func pprofCPU(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if err := pprof.StartCPUProfile(w); err != nil {
+		http.Error(w, fmt.Sprintf("Could not enable CPU profiling: %s", err), http.StatusInternalServerError)
+		return
+	}
+	time.Sleep(pprofSeconds(r, 30))
+	pprof.StopCPUProfile()
+}
+
+// pprofTrace writes an execution trace of the number of seconds in the query (default 1)
+func pprofTrace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if err := trace.Start(w); err != nil {
+		http.Error(w, fmt.Sprintf("Could not enable tracing: %s", err), http.StatusInternalServerError)
+		return
+	}
+	time.Sleep(pprofSeconds(r, 1))
+	trace.Stop()
+}
+
+// DebugHandler returns the HTTP handler of the debug endpoint. Requests must
+// contain the admin token as bearer token or in the "token" query parameter.
+func (c *Component) DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprofIndex)
+	mux.HandleFunc("/debug/pprof/profile", pprofCPU)
+	mux.HandleFunc("/debug/pprof/trace", pprofTrace)
+	mux.HandleFunc("/debug/config", func(w http.ResponseWriter, r *http.Request) {
+		writeDebugJSON(w, configDump())
+	})
+	mux.Handle("/debug/faults", fault.Handler())
+	mux.HandleFunc("/debug/streams", func(w http.ResponseWriter, r *http.Request) {
+		writeDebugJSON(w, c.streamCounts())
+	})
+	mux.HandleFunc("/debug/", func(w http.ResponseWriter, r *http.Request) {
+		c.debug.mu.Lock()
+		info, ok := c.debug.info[strings.TrimPrefix(r.URL.Path, "/debug/")]
+		c.debug.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeDebugJSON(w, info())
+	})
+	mux.HandleFunc("/admin/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/admin/"), "/", 2)[0]
+		c.debug.mu.Lock()
+		handler, ok := c.debug.admin[name]
+		c.debug.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if !c.validDebugToken(token) {
+			http.Error(w, "Invalid debug token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
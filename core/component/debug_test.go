@@ -0,0 +1,101 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package component
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartystreets/assertions"
+	"github.com/spf13/viper"
+)
+
+func TestDebugHandler(t *testing.T) {
+	a := assertions.New(t)
+
+	c := new(Component)
+	c.debug.token = "admin"
+	c.RegisterDebugInfo("things", func() interface{} { return []string{"a", "b"} })
+	handler := c.DebugHandler()
+
+	get := func(path string, token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	a.So(get("/debug/things", "").Code, assertions.ShouldEqual, http.StatusUnauthorized)
+	a.So(get("/debug/things", "wrong").Code, assertions.ShouldEqual, http.StatusUnauthorized)
+
+	rec := get("/debug/things", "admin")
+	a.So(rec.Code, assertions.ShouldEqual, http.StatusOK)
+	a.So(rec.Body.String(), assertions.ShouldContainSubstring, `"a"`)
+
+	a.So(get("/debug/other", "admin").Code, assertions.ShouldEqual, http.StatusNotFound)
+	a.So(get("/debug/things?token=admin", "").Code, assertions.ShouldEqual, http.StatusOK)
+
+	c.countStream("/router.Router/GatewayStatus", 1)
+	c.countStream("/router.Router/Uplink", 1)
+	c.countStream("/router.Router/Uplink", -1)
+	rec = get("/debug/streams", "admin")
+	a.So(rec.Body.String(), assertions.ShouldContainSubstring, "GatewayStatus")
+	a.So(rec.Body.String(), assertions.ShouldNotContainSubstring, "Uplink")
+
+	a.So(get("/debug/pprof/", "").Code, assertions.ShouldEqual, http.StatusUnauthorized)
+	rec = get("/debug/pprof/", "admin")
+	a.So(rec.Body.String(), assertions.ShouldContainSubstring, "goroutine")
+	a.So(get("/debug/pprof/goroutine?debug=1", "admin").Code, assertions.ShouldEqual, http.StatusOK)
+	a.So(get("/debug/pprof/unknown", "admin").Code, assertions.ShouldEqual, http.StatusNotFound)
+
+	// The profiles are not registered on the mux of the health port
+	_, pattern := http.DefaultServeMux.Handler(httptest.NewRequest("GET", "/debug/pprof/", nil))
+	a.So(pattern, assertions.ShouldBeEmpty)
+}
+
+func TestAdminHandler(t *testing.T) {
+	a := assertions.New(t)
+
+	c := new(Component)
+	c.debug.token = "admin"
+	c.RegisterAdminHandler("things", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+	handler := c.DebugHandler()
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer admin")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	a.So(do("PUT", "/admin/things/a").Body.String(), assertions.ShouldEqual, "PUT /a")
+	a.So(do("GET", "/admin/things/").Body.String(), assertions.ShouldEqual, "GET /")
+	a.So(do("GET", "/admin/other/").Code, assertions.ShouldEqual, http.StatusNotFound)
+}
+
+func TestDebugHandlerDisabled(t *testing.T) {
+	a := assertions.New(t)
+	c := new(Component)
+	req, _ := http.NewRequest("GET", "/debug/streams", nil)
+	rec := httptest.NewRecorder()
+	c.DebugHandler().ServeHTTP(rec, req)
+	a.So(rec.Code, assertions.ShouldEqual, http.StatusUnauthorized)
+}
+
+func TestConfigDump(t *testing.T) {
+	a := assertions.New(t)
+	viper.Set("debug-token", "admin")
+	viper.Set("router.server-port", 1901)
+	defer viper.Reset()
+	dump := configDump()
+	a.So(dump["debug-token"], assertions.ShouldEqual, "<hidden>")
+	a.So(dump["router.server-port"], assertions.ShouldEqual, 1901)
+}
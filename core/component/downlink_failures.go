@@ -0,0 +1,65 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ReportDownlinkFailure reports a downlink message that could not be
+// scheduled to the Handlers of the application, using their HTTP API. The
+// Handlers publish it as a downlink error event of the device.
+func (c *Component) ReportDownlinkFailure(failure types.DownlinkFailure) error {
+	if c.Discovery == nil || c.Identity == nil || failure.AppID == "" || failure.DevID == "" {
+		return nil
+	}
+	if failure.Component == "" {
+		failure.Component = c.Identity.ServiceName
+	}
+	handlers, err := c.Discovery.GetAllHandlersForAppID(failure.AppID)
+	if err != nil {
+		return errors.FromGRPCError(err)
+	}
+	token, err := c.BuildJWT()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(failure)
+	if err != nil {
+		return err
+	}
+	for _, handler := range handlers {
+		if handler.ApiAddress == "" {
+			continue
+		}
+		url := fmt.Sprintf("%s/downlink-failures", strings.TrimSuffix(handler.ApiAddress, "/"))
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Grpc-Metadata-Id", c.Identity.Id)
+		req.Header.Set("Grpc-Metadata-Service-Name", c.Identity.ServiceName)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+			body, _ := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			return errors.NewErrInternal(fmt.Sprintf("Handler %s did not accept downlink failure: %s %s", handler.Id, res.Status, strings.TrimSpace(string(body))))
+		}
+		res.Body.Close()
+	}
+	return nil
+}
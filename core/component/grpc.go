@@ -6,6 +6,7 @@ package component
 import (
 	"github.com/TheThingsNetwork/go-utils/grpc/interceptor"
 	"github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
 	"github.com/TheThingsNetwork/ttn/api/fields"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/mwitkow/go-grpc-middleware"
@@ -26,6 +27,16 @@ func (c *Component) ServerOptions() []grpc.ServerOption {
 		return iface, err
 	}
 
+	// Clients built against older protos keep working, because new fields are
+	// only added to the messages; clients that require a newer API version
+	// than this component supports are rejected
+	unaryVersion := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, err := api.VersionFromContext(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
 	streamLog := interceptor.Stream(func(srv interface{}, info *grpc.StreamServerInfo) (log.Interface, string) {
 		return c.Ctx, "Stream"
 	})
@@ -36,14 +47,25 @@ func (c *Component) ServerOptions() []grpc.ServerOption {
 		return err
 	}
 
+	streamVersion := func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, err := api.VersionFromContext(stream.Context()); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+
 	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryErr, unaryLog)),
-		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(streamErr, streamLog)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryErr, unaryLog, unaryVersion)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(streamErr, streamLog, streamVersion, c.streamDebug)),
 	}
 
 	if c.tlsConfig != nil {
 		opts = append(opts, grpc.Creds(credentials.NewTLS(c.tlsConfig)))
 	}
 
+	if c.Config.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(c.Config.MaxConcurrentStreams))
+	}
+
 	return opts
 }
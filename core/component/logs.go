@@ -0,0 +1,78 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/logging"
+	"github.com/apex/log"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+// LogStream is the server stream of the TailLogs RPC of the manager service of a component
+type LogStream interface {
+	Send(*api.LogEntry) error
+	Context() context.Context
+}
+
+// TailLogs streams the log entries of the component that match the request
+// until the stream is canceled. Entries are dropped if the client is too slow.
+func (c *Component) TailLogs(req *api.LogsRequest, stream LogStream) error {
+	if c.Identity.Id != "dev" {
+		claims, err := c.ValidateTTNAuthContext(stream.Context())
+		if err != nil {
+			return errors.Wrap(err, "No access")
+		}
+		if !claims.ComponentAccess(c.Identity.Id) {
+			return errors.NewErrPermissionDenied(fmt.Sprintf("Claims do not grant access to %s", c.Identity.Id))
+		}
+	}
+
+	filter := logging.TailFilter{
+		Level:  log.DebugLevel,
+		Fields: map[string]string{logging.ComponentField: c.Identity.ServiceName},
+	}
+	if req.Level != "" {
+		level, err := logging.ParseLevel(req.Level)
+		if err != nil {
+			return errors.NewErrInvalidArgument("Level", err.Error())
+		}
+		filter.Level = level
+	}
+	for field, value := range map[string]string{
+		"DevEUI":    req.DevEui,
+		"AppEUI":    req.AppEui,
+		"GatewayID": req.GatewayId,
+		"AppID":     req.AppId,
+		"DevID":     req.DevId,
+	} {
+		if value != "" {
+			filter.Fields[field] = value
+		}
+	}
+
+	entries, unsubscribe := logging.DefaultTail.Subscribe(filter)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case entry := <-entries:
+			fields, _ := json.Marshal(entry.Fields)
+			if err := stream.Send(&api.LogEntry{
+				Time:    entry.Time.UnixNano(),
+				Level:   entry.Level,
+				Message: entry.Message,
+				Fields:  string(fields),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
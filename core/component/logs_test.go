@@ -0,0 +1,73 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package component
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/logging"
+	"github.com/apex/log"
+	"github.com/smartystreets/assertions"
+	"golang.org/x/net/context"
+)
+
+type testLogStream struct {
+	ctx     context.Context
+	entries chan *api.LogEntry
+}
+
+func (s *testLogStream) Send(entry *api.LogEntry) error {
+	s.entries <- entry
+	return nil
+}
+
+func (s *testLogStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestTailLogs(t *testing.T) {
+	a := assertions.New(t)
+
+	c := &Component{Identity: &pb_discovery.Announcement{Id: "dev", ServiceName: "router"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &testLogStream{ctx: ctx, entries: make(chan *api.LogEntry, 10)}
+
+	err := c.TailLogs(&api.LogsRequest{Level: "everything"}, stream)
+	a.So(errors.GetErrType(err), assertions.ShouldEqual, errors.InvalidArgument)
+
+	done := make(chan error)
+	go func() {
+		done <- c.TailLogs(&api.LogsRequest{Level: "info", DevId: "dev"}, stream)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	logger := &log.Logger{Handler: logging.DefaultTail, Level: log.DebugLevel}
+	logger.WithFields(log.Fields{logging.ComponentField: "router", "DevID": "dev"}).Debug("Too verbose")
+	logger.WithFields(log.Fields{logging.ComponentField: "broker", "DevID": "dev"}).Info("Other component")
+	logger.WithFields(log.Fields{logging.ComponentField: "router", "DevID": "other"}).Info("Other device")
+	logger.WithFields(log.Fields{logging.ComponentField: "router", "DevID": "dev"}).Info("Match")
+
+	select {
+	case entry := <-stream.entries:
+		a.So(entry.Level, assertions.ShouldEqual, "info")
+		a.So(entry.Message, assertions.ShouldEqual, "Match")
+		a.So(entry.Fields, assertions.ShouldContainSubstring, `"DevID":"dev"`)
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive a log entry")
+	}
+	a.So(stream.entries, assertions.ShouldBeEmpty)
+
+	cancel()
+	select {
+	case err := <-done:
+		a.So(err, assertions.ShouldEqual, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("TailLogs did not return")
+	}
+}
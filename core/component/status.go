@@ -4,7 +4,11 @@
 package component
 
 import (
+	"fmt"
 	"sync/atomic"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/alerting"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
@@ -21,6 +25,16 @@ const (
 	StatusUnhealthy
 )
 
+func (s Status) String() string {
+	switch s {
+	case StatusHealthy:
+		return "healthy"
+	case StatusUnhealthy:
+		return "unhealthy"
+	}
+	return "unknown"
+}
+
 // GetStatus gets the health status of the component
 func (c *Component) GetStatus() Status {
 	return Status(atomic.LoadInt64(&c.status))
@@ -29,18 +43,57 @@ func (c *Component) GetStatus() Status {
 // SetStatus sets the health status of the component
 func (c *Component) SetStatus(status Status) {
 	atomic.StoreInt64(&c.status, int64(status))
-	if c.healthServer != nil {
-		switch status {
-		case StatusHealthy:
-			c.healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-		case StatusUnhealthy:
-			c.healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if c.Identity != nil {
+		c.Alerts.Update(alerting.ComponentUnhealthy, c.Identity.Id, status == StatusUnhealthy, fmt.Sprintf("%s %s is %s", c.Identity.ServiceName, c.Identity.Id, status))
+	}
+	c.setServingStatus("", status)
+}
+
+// DependencyCheckInterval is the interval in which the health of the dependencies of a component is checked
+var DependencyCheckInterval = 30 * time.Second
+
+// SetDependencyStatus sets the health status of a dependency of the component,
+// such as its database. The health server reports it as the status of the
+// service with the name of the dependency.
+func (c *Component) SetDependencyStatus(name string, status Status) {
+	c.setServingStatus(name, status)
+}
+
+// WatchDependency checks the health of a dependency of the component in every
+// DependencyCheckInterval, and reports it with SetDependencyStatus
+func (c *Component) WatchDependency(name string, check func() error) {
+	last := StatusHealthy
+	for {
+		status := StatusHealthy
+		if err := check(); err != nil {
+			status = StatusUnhealthy
+			if last == StatusHealthy {
+				c.Ctx.WithField("Dependency", name).WithError(err).Warn("Dependency is unhealthy")
+			}
 		}
+		c.SetDependencyStatus(name, status)
+		last = status
+		time.Sleep(DependencyCheckInterval)
+	}
+}
+
+func (c *Component) getHealthServer() *health.Server {
+	c.healthOnce.Do(func() {
+		c.healthServer = health.NewServer()
+	})
+	return c.healthServer
+}
+
+func (c *Component) setServingStatus(service string, status Status) {
+	switch status {
+	case StatusHealthy:
+		c.getHealthServer().SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	case StatusUnhealthy:
+		c.getHealthServer().SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
 	}
 }
 
 // RegisterHealthServer registers the component's health status to the gRPC server
 func (c *Component) RegisterHealthServer(srv *grpc.Server) {
-	c.healthServer = health.NewServer()
-	healthpb.RegisterHealthServer(srv, c.healthServer)
+	healthpb.RegisterHealthServer(srv, c.getHealthServer())
 }
@@ -0,0 +1,44 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package component
+
+import (
+	"testing"
+
+	"github.com/smartystreets/assertions"
+	"golang.org/x/net/context"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestDependencyStatus(t *testing.T) {
+	a := assertions.New(t)
+
+	c := new(Component)
+	check := func(service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+		res, err := c.getHealthServer().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return healthpb.HealthCheckResponse_UNKNOWN, err
+		}
+		return res.Status, nil
+	}
+
+	_, err := check("redis")
+	a.So(err, assertions.ShouldNotBeNil)
+
+	c.SetStatus(StatusHealthy)
+	c.SetDependencyStatus("redis", StatusUnhealthy)
+
+	status, err := check("")
+	a.So(err, assertions.ShouldBeNil)
+	a.So(status, assertions.ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+
+	status, err = check("redis")
+	a.So(err, assertions.ShouldBeNil)
+	a.So(status, assertions.ShouldEqual, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	c.SetDependencyStatus("redis", StatusHealthy)
+	status, err = check("redis")
+	a.So(err, assertions.ShouldBeNil)
+	a.So(status, assertions.ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+}
@@ -6,6 +6,7 @@ package announcement
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/TheThingsNetwork/ttn/core/storage"
@@ -143,3 +144,33 @@ func (s *cachedAnnouncementStore) Delete(serviceName, serviceID string) error {
 	s.listCache.Remove(&serviceName)
 	return nil
 }
+
+// Watch the changes of the backing store. The cached announcements are
+// invalidated before the changes are sent, so that they are not returned
+// to watchers that request the changed announcements.
+func (s *cachedAnnouncementStore) Watch() (<-chan string, func(), error) {
+	changes, stop, err := s.backingStore.Watch()
+	if err != nil {
+		return nil, nil, err
+	}
+	invalidated, done := make(chan string, WatchBuffer), make(chan struct{})
+	go func() {
+		defer close(invalidated)
+		for change := range changes {
+			s.serviceCache.Remove(change)
+			s.listCache.Remove(strings.SplitN(change, ":", 2)[0])
+			select {
+			case invalidated <- change:
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return invalidated, func() {
+		once.Do(func() {
+			close(done)
+			stop()
+		})
+	}, nil
+}
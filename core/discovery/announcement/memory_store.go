@@ -0,0 +1,245 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package announcement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// NewMemoryAnnouncementStore creates a new in-memory Announcement store. It can
+// be used in tests or when embedding the Discovery server without Redis.
+func NewMemoryAnnouncementStore() Store {
+	return &MemoryAnnouncementStore{
+		announcements: make(map[string]Announcement),
+		metadata:      make(map[string]map[string]Metadata),
+		byAppID:       make(map[string]string),
+		byAppEUI:      make(map[string]string),
+	}
+}
+
+// MemoryAnnouncementStore stores Announcements in memory
+type MemoryAnnouncementStore struct {
+	mu            sync.RWMutex
+	announcements map[string]Announcement
+	metadata      map[string]map[string]Metadata
+	byAppID       map[string]string
+	byAppEUI      map[string]string
+	watchers      watchers
+}
+
+func (s *MemoryAnnouncementStore) list(prefix string, opts *storage.ListOptions, withMetadata bool) []*Announcement {
+	keys := make([]string, 0, len(s.announcements))
+	for key := range s.announcements {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	keys = storage.SelectKeys(keys, opts)
+	announcements := make([]*Announcement, 0, len(keys))
+	for _, key := range keys {
+		announcement := s.announcements[key]
+		if withMetadata {
+			announcement.Metadata = s.getMetadata(key)
+		}
+		announcements = append(announcements, &announcement)
+	}
+	return announcements
+}
+
+func (s *MemoryAnnouncementStore) getMetadata(key string) (out []Metadata) {
+	txts := make([]string, 0, len(s.metadata[key]))
+	for txt := range s.metadata[key] {
+		txts = append(txts, txt)
+	}
+	sort.Strings(txts)
+	for _, txt := range txts {
+		out = append(out, s.metadata[key][txt])
+	}
+	return
+}
+
+// List all Announcements
+// The resulting Announcements do *not* include metadata
+func (s *MemoryAnnouncementStore) List(opts *storage.ListOptions) ([]*Announcement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list("", opts, false), nil
+}
+
+// ListService lists all Announcements for a given service (router/broker/handler)
+// The resulting Announcements *do* include metadata
+func (s *MemoryAnnouncementStore) ListService(serviceName string, opts *storage.ListOptions) ([]*Announcement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list(serviceName+":", opts, true), nil
+}
+
+func (s *MemoryAnnouncementStore) get(key string) (*Announcement, error) {
+	announcement, ok := s.announcements[key]
+	if !ok {
+		return nil, errors.NewErrNotFound(key)
+	}
+	announcement.Metadata = s.getMetadata(key)
+	return &announcement, nil
+}
+
+// Get a specific service Announcement
+// The result *does* include metadata
+func (s *MemoryAnnouncementStore) Get(serviceName, serviceID string) (*Announcement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.get(fmt.Sprintf("%s:%s", serviceName, serviceID))
+}
+
+// GetMetadata returns the metadata of the specified service
+func (s *MemoryAnnouncementStore) GetMetadata(serviceName, serviceID string) ([]Metadata, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.getMetadata(fmt.Sprintf("%s:%s", serviceName, serviceID)), nil
+}
+
+// GetForAppID returns the last Announcement that contains metadata for the given AppID
+func (s *MemoryAnnouncementStore) GetForAppID(appID string) (*Announcement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.byAppID[appID]
+	if !ok {
+		return nil, errors.NewErrNotFound(appID)
+	}
+	return s.get(key)
+}
+
+// GetForAppEUI returns the last Announcement that contains metadata for the given AppEUI
+func (s *MemoryAnnouncementStore) GetForAppEUI(appEUI types.AppEUI) (*Announcement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.byAppEUI[appEUI.String()]
+	if !ok {
+		return nil, errors.NewErrNotFound(appEUI.String())
+	}
+	return s.get(key)
+}
+
+// Set a new Announcement or update an existing one
+// The metadata of the announcement is ignored, as metadata should be managed with AddMetadata and RemoveMetadata
+func (s *MemoryAnnouncementStore) Set(new *Announcement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%s:%s", new.ServiceName, new.ID)
+	changed := hasChanged(new)
+	now := time.Now()
+	new.UpdatedAt = now
+	if existing, ok := s.announcements[key]; ok {
+		new.CreatedAt = existing.CreatedAt
+	} else {
+		new.CreatedAt = now
+		changed = true
+	}
+	stored := *new
+	stored.old = nil
+	stored.Metadata = nil
+	s.announcements[key] = stored
+	if changed {
+		s.watchers.notify(key)
+	}
+	return nil
+}
+
+// AddMetadata adds metadata to the announcement of the specified service
+func (s *MemoryAnnouncementStore) AddMetadata(serviceName, serviceID string, metadata ...Metadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%s:%s", serviceName, serviceID)
+	var changed bool
+	for _, meta := range metadata {
+		txt, err := meta.MarshalText()
+		if err != nil {
+			return err
+		}
+		var index map[string]string
+		var indexKey string
+		switch meta := meta.(type) {
+		case AppIDMetadata:
+			index, indexKey = s.byAppID, meta.AppID
+		case AppEUIMetadata:
+			index, indexKey = s.byAppEUI, meta.AppEUI.String()
+		}
+		if index != nil {
+			if existing, ok := index[indexKey]; ok && existing != key {
+				delete(s.metadata[existing], string(txt))
+			}
+			index[indexKey] = key
+		}
+		if s.metadata[key] == nil {
+			s.metadata[key] = make(map[string]Metadata)
+		}
+		if _, ok := s.metadata[key][string(txt)]; !ok {
+			changed = true
+		}
+		s.metadata[key][string(txt)] = meta
+	}
+	if changed {
+		s.watchers.notify(key)
+	}
+	return nil
+}
+
+// RemoveMetadata removes metadata from the announcement of the specified service
+func (s *MemoryAnnouncementStore) RemoveMetadata(serviceName, serviceID string, metadata ...Metadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%s:%s", serviceName, serviceID)
+	var changed bool
+	for _, meta := range metadata {
+		if txt, err := meta.MarshalText(); err == nil {
+			if _, ok := s.metadata[key][string(txt)]; ok {
+				changed = true
+			}
+			delete(s.metadata[key], string(txt))
+		}
+		switch meta := meta.(type) {
+		case AppIDMetadata:
+			delete(s.byAppID, meta.AppID)
+		case AppEUIMetadata:
+			delete(s.byAppEUI, meta.AppEUI.String())
+		}
+	}
+	if changed {
+		s.watchers.notify(key)
+	}
+	return nil
+}
+
+// Delete an Announcement and its metadata
+func (s *MemoryAnnouncementStore) Delete(serviceName, serviceID string) error {
+	metadata, _ := s.GetMetadata(serviceName, serviceID)
+	if len(metadata) > 0 {
+		s.RemoveMetadata(serviceName, serviceID, metadata...)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%s:%s", serviceName, serviceID)
+	if _, ok := s.announcements[key]; !ok {
+		return errors.NewErrNotFound(key)
+	}
+	delete(s.announcements, key)
+	delete(s.metadata, key)
+	s.watchers.notify(key)
+	return nil
+}
+
+// Watch the changes of announcements and their metadata
+func (s *MemoryAnnouncementStore) Watch() (<-chan string, func(), error) {
+	changes, stop := s.watchers.watch()
+	return changes, stop, nil
+}
@@ -0,0 +1,90 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package announcement
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestMemoryAnnouncementStore(t *testing.T) {
+	a := New(t)
+
+	s := NewMemoryAnnouncementStore()
+
+	dev, err := s.Get("router", "router1")
+	a.So(err, ShouldNotBeNil)
+	a.So(dev, ShouldBeNil)
+
+	a.So(s.Set(&Announcement{ServiceName: "router", ID: "router1"}), ShouldBeNil)
+	a.So(s.Set(&Announcement{ServiceName: "handler", ID: "handler1"}), ShouldBeNil)
+	a.So(s.Set(&Announcement{ServiceName: "handler", ID: "handler2"}), ShouldBeNil)
+
+	appEUI := types.AppEUI([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+
+	err = s.AddMetadata("handler", "handler1",
+		AppEUIMetadata{AppEUI: appEUI},
+		AppIDMetadata{AppID: "AppID"},
+	)
+	a.So(err, ShouldBeNil)
+
+	handler, err := s.GetForAppID("AppID")
+	a.So(err, ShouldBeNil)
+	a.So(handler.ID, ShouldEqual, "handler1")
+	a.So(handler.Metadata, ShouldHaveLength, 2)
+
+	// Moving metadata to another handler removes it from the first
+	err = s.AddMetadata("handler", "handler2",
+		AppEUIMetadata{AppEUI: appEUI},
+	)
+	a.So(err, ShouldBeNil)
+
+	handler, err = s.GetForAppEUI(appEUI)
+	a.So(err, ShouldBeNil)
+	a.So(handler.ID, ShouldEqual, "handler2")
+
+	metadata, err := s.GetMetadata("handler", "handler1")
+	a.So(err, ShouldBeNil)
+	a.So(metadata, ShouldHaveLength, 1)
+
+	services, err := s.ListService("handler", nil)
+	a.So(err, ShouldBeNil)
+	a.So(services, ShouldHaveLength, 2)
+
+	services, err = s.List(nil)
+	a.So(err, ShouldBeNil)
+	a.So(services, ShouldHaveLength, 3)
+
+	a.So(s.Delete("handler", "handler2"), ShouldBeNil)
+
+	_, err = s.GetForAppEUI(appEUI)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestMemoryAnnouncementStoreWatch(t *testing.T) {
+	a := New(t)
+
+	s := NewMemoryAnnouncementStore()
+
+	changes, stop, err := s.Watch()
+	a.So(err, ShouldBeNil)
+
+	announcement := &Announcement{ServiceName: "handler", ID: "handler1"}
+	a.So(s.Set(announcement), ShouldBeNil)
+	a.So(<-changes, ShouldEqual, "handler:handler1")
+
+	// Announcing again without changes is not published
+	announcement.StartUpdate()
+	a.So(s.Set(announcement), ShouldBeNil)
+	a.So(s.AddMetadata("handler", "handler1", AppIDMetadata{AppID: "AppID"}), ShouldBeNil)
+	a.So(<-changes, ShouldEqual, "handler:handler1")
+	a.So(s.AddMetadata("handler", "handler1", AppIDMetadata{AppID: "AppID"}), ShouldBeNil)
+	a.So(changes, ShouldBeEmpty)
+
+	stop()
+	_, ok := <-changes
+	a.So(ok, ShouldBeFalse)
+}
@@ -6,6 +6,7 @@ package announcement
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/TheThingsNetwork/ttn/core/discovery/announcement/migrate"
@@ -27,6 +28,9 @@ type Store interface {
 	AddMetadata(serviceName, serviceID string, metadata ...Metadata) error
 	RemoveMetadata(serviceName, serviceID string, metadata ...Metadata) error
 	Delete(serviceName, serviceID string) error
+	// Watch the changes of announcements and their metadata. The changes are
+	// sent as "<service name>:<service id>" until stop is called.
+	Watch() (changes <-chan string, stop func(), err error)
 }
 
 const defaultRedisPrefix = "discovery"
@@ -35,6 +39,7 @@ const redisAnnouncementPrefix = "announcement"
 const redisMetadataPrefix = "metadata"
 const redisAppIDPrefix = "app_id"
 const redisAppEUIPrefix = "app_eui"
+const redisChangesChannel = "changes"
 
 // NewRedisAnnouncementStore creates a new Redis-based Announcement store
 func NewRedisAnnouncementStore(client *redis.Client, prefix string) Store {
@@ -47,6 +52,8 @@ func NewRedisAnnouncementStore(client *redis.Client, prefix string) Store {
 		store.AddMigration(v, f)
 	}
 	return &RedisAnnouncementStore{
+		client:   client,
+		changes:  prefix + ":" + redisChangesChannel,
 		store:    store,
 		metadata: storage.NewRedisSetStore(client, prefix+":"+redisMetadataPrefix),
 		byAppID:  storage.NewRedisKVStore(client, prefix+":"+redisAppIDPrefix),
@@ -58,7 +65,10 @@ func NewRedisAnnouncementStore(client *redis.Client, prefix string) Store {
 // - Announcements are stored as a Hash
 // - Metadata is stored in a Set
 // - AppIDs and AppEUIs are indexed with key/value pairs
+// - Changes are published on a channel, so that all Discovery servers can watch them
 type RedisAnnouncementStore struct {
+	client   *redis.Client
+	changes  string
 	store    *storage.RedisMapStore
 	metadata *storage.RedisSetStore
 	byAppID  *storage.RedisKVStore
@@ -161,23 +171,51 @@ func (s *RedisAnnouncementStore) GetForAppEUI(appEUI types.AppEUI) (*Announcemen
 // The metadata of the announcement is ignored, as metadata should be managed with AddMetadata and RemoveMetadata
 func (s *RedisAnnouncementStore) Set(new *Announcement) error {
 	key := fmt.Sprintf("%s:%s", new.ServiceName, new.ID)
+	changed := hasChanged(new)
 	now := time.Now()
 	new.UpdatedAt = now
 	err := s.store.Update(key, *new)
 	if errors.GetErrType(err) == errors.NotFound {
 		new.CreatedAt = now
+		changed = true
 		err = s.store.Create(key, *new)
 	}
 	if err != nil {
 		return err
 	}
+	if changed {
+		s.publishChange(new.ServiceName, new.ID)
+	}
 	return nil
 }
 
+// publishChange notifies the watchers of a changed announcement
+func (s *RedisAnnouncementStore) publishChange(serviceName, serviceID string) {
+	s.client.Publish(s.changes, fmt.Sprintf("%s:%s", serviceName, serviceID))
+}
+
+// metadataSet returns the metadata of the specified service as a set
+func (s *RedisAnnouncementStore) metadataSet(key string) (map[string]bool, error) {
+	existing, err := s.metadata.Get(key)
+	if err != nil && errors.GetErrType(err) != errors.NotFound {
+		return nil, err
+	}
+	set := make(map[string]bool, len(existing))
+	for _, value := range existing {
+		set[value] = true
+	}
+	return set, nil
+}
+
 // AddMetadata adds metadata to the announcement of the specified service
 func (s *RedisAnnouncementStore) AddMetadata(serviceName, serviceID string, metadata ...Metadata) error {
 	key := fmt.Sprintf("%s:%s", serviceName, serviceID)
 
+	existing, err := s.metadataSet(key)
+	if err != nil {
+		return err
+	}
+
 	metadataStrings := make([]string, 0, len(metadata))
 	for _, meta := range metadata {
 		txt, err := meta.MarshalText()
@@ -223,15 +261,26 @@ func (s *RedisAnnouncementStore) AddMetadata(serviceName, serviceID string, meta
 			}
 		}
 	}
-	err := s.metadata.Add(key, metadataStrings...)
+	err = s.metadata.Add(key, metadataStrings...)
 	if err != nil {
 		return err
 	}
+	for _, txt := range metadataStrings {
+		if !existing[txt] {
+			s.publishChange(serviceName, serviceID)
+			break
+		}
+	}
 	return nil
 }
 
 // RemoveMetadata removes metadata from the announcement of the specified service
 func (s *RedisAnnouncementStore) RemoveMetadata(serviceName, serviceID string, metadata ...Metadata) error {
+	key := fmt.Sprintf("%s:%s", serviceName, serviceID)
+	existing, err := s.metadataSet(key)
+	if err != nil {
+		return err
+	}
 	metadataStrings := make([]string, 0, len(metadata))
 	for _, meta := range metadata {
 		if txt, err := meta.MarshalText(); err == nil {
@@ -244,10 +293,16 @@ func (s *RedisAnnouncementStore) RemoveMetadata(serviceName, serviceID string, m
 			s.byAppEUI.Delete(meta.AppEUI.String())
 		}
 	}
-	err := s.metadata.Remove(fmt.Sprintf("%s:%s", serviceName, serviceID), metadataStrings...)
+	err = s.metadata.Remove(key, metadataStrings...)
 	if err != nil {
 		return err
 	}
+	for _, txt := range metadataStrings {
+		if existing[txt] {
+			s.publishChange(serviceName, serviceID)
+			break
+		}
+	}
 	return nil
 }
 
@@ -265,5 +320,37 @@ func (s *RedisAnnouncementStore) Delete(serviceName, serviceID string) error {
 	if err != nil {
 		return err
 	}
+	s.publishChange(serviceName, serviceID)
 	return nil
 }
+
+// Watch the changes of announcements and their metadata, also if they were
+// made by other Discovery servers
+func (s *RedisAnnouncementStore) Watch() (<-chan string, func(), error) {
+	pubsub, err := s.client.Subscribe(s.changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, done := make(chan string, WatchBuffer), make(chan struct{})
+	go func() {
+		defer close(changes)
+		for {
+			msg, err := pubsub.ReceiveMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case changes <- msg.Payload:
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return changes, func() {
+		once.Do(func() {
+			close(done)
+			pubsub.Close()
+		})
+	}, nil
+}
@@ -5,6 +5,7 @@ package announcement
 
 import (
 	"testing"
+	"time"
 
 	"github.com/TheThingsNetwork/ttn/core/types"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
@@ -142,3 +143,56 @@ func TestRedisAnnouncementStore(t *testing.T) {
 	err = s.Delete("handler", "handler2")
 	a.So(err, ShouldBeNil)
 }
+
+func TestRedisAnnouncementStoreWatch(t *testing.T) {
+	a := New(t)
+
+	s := NewRedisAnnouncementStore(GetRedisClient(), "discovery-test-announcement-watch")
+
+	changes, stop, err := s.Watch()
+	a.So(err, ShouldBeNil)
+	defer stop()
+
+	next := func() string {
+		select {
+		case change := <-changes:
+			return change
+		case <-time.After(100 * time.Millisecond):
+			return ""
+		}
+	}
+
+	err = s.Set(&Announcement{ServiceName: "handler", ID: "handler1"})
+	a.So(err, ShouldBeNil)
+	defer s.Delete("handler", "handler1")
+	a.So(next(), ShouldEqual, "handler:handler1")
+
+	// Announcing again without changes is not published
+	announcement, err := s.Get("handler", "handler1")
+	a.So(err, ShouldBeNil)
+	announcement.StartUpdate()
+	err = s.Set(announcement)
+	a.So(err, ShouldBeNil)
+	a.So(next(), ShouldBeEmpty)
+
+	announcement.StartUpdate()
+	announcement.Description = "Changed"
+	err = s.Set(announcement)
+	a.So(err, ShouldBeNil)
+	a.So(next(), ShouldEqual, "handler:handler1")
+
+	err = s.AddMetadata("handler", "handler1", AppIDMetadata{AppID: "app1"})
+	a.So(err, ShouldBeNil)
+	a.So(next(), ShouldEqual, "handler:handler1")
+
+	// Adding existing metadata or removing unknown metadata is not published
+	err = s.AddMetadata("handler", "handler1", AppIDMetadata{AppID: "app1"})
+	a.So(err, ShouldBeNil)
+	err = s.RemoveMetadata("handler", "handler1", AppIDMetadata{AppID: "app2"})
+	a.So(err, ShouldBeNil)
+	a.So(next(), ShouldBeEmpty)
+
+	err = s.RemoveMetadata("handler", "handler1", AppIDMetadata{AppID: "app1"})
+	a.So(err, ShouldBeNil)
+	a.So(next(), ShouldEqual, "handler:handler1")
+}
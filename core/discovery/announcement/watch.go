@@ -0,0 +1,58 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package announcement
+
+import "sync"
+
+// WatchBuffer is the number of changes that is buffered for each watcher of
+// a store
+var WatchBuffer = 64
+
+// hasChanged returns whether the announcement changed since the last call to
+// StartUpdate, not counting the timestamps
+func hasChanged(a *Announcement) bool {
+	for _, field := range a.ChangedFields() {
+		if field != "CreatedAt" && field != "UpdatedAt" {
+			return true
+		}
+	}
+	return false
+}
+
+// watchers sends the changes of a store to its watchers
+type watchers struct {
+	mu       sync.RWMutex
+	watchers map[chan string]chan struct{}
+}
+
+func (w *watchers) watch() (<-chan string, func()) {
+	changes, done := make(chan string, WatchBuffer), make(chan struct{})
+	w.mu.Lock()
+	if w.watchers == nil {
+		w.watchers = make(map[chan string]chan struct{})
+	}
+	w.watchers[changes] = done
+	w.mu.Unlock()
+	var once sync.Once
+	return changes, func() {
+		once.Do(func() {
+			close(done) // Unblocks notify, so that the lock can be taken
+			w.mu.Lock()
+			delete(w.watchers, changes)
+			close(changes)
+			w.mu.Unlock()
+		})
+	}
+}
+
+func (w *watchers) notify(change string) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for changes, done := range w.watchers {
+		select {
+		case changes <- change:
+		case <-done:
+		}
+	}
+}
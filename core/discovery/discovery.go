@@ -129,3 +129,11 @@ func NewRedisDiscovery(client *redis.Client) Discovery {
 		masterAuthServers: make(map[string]struct{}),
 	}
 }
+
+// NewMemoryDiscovery creates a new discovery service that keeps its state in memory
+func NewMemoryDiscovery() Discovery {
+	return &discovery{
+		services:          announcement.NewMemoryAnnouncementStore(),
+		masterAuthServers: make(map[string]struct{}),
+	}
+}
@@ -5,6 +5,7 @@ package discovery
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/TheThingsNetwork/go-account-lib/rights"
 	"github.com/TheThingsNetwork/ttn/api"
@@ -163,6 +164,31 @@ func (d *discoveryServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.Anno
 	return service, nil
 }
 
+func (d *discoveryServer) Watch(req *pb.GetServiceRequest, stream pb.Discovery_WatchServer) error {
+	changes, stop, err := d.discovery.services.Watch()
+	if err != nil {
+		return err
+	}
+	defer stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case change, ok := <-changes:
+			if !ok {
+				return errors.NewErrInternal("Stopped watching announcements")
+			}
+			service := strings.SplitN(change, ":", 2)
+			if len(service) != 2 || (req.ServiceName != "" && service[0] != req.ServiceName) {
+				continue
+			}
+			if err := stream.Send(&pb.AnnouncementChange{ServiceName: service[0], Id: service[1]}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // RegisterRPC registers the local discovery with a gRPC server
 func (d *discovery) RegisterRPC(s *grpc.Server) {
 	server := &discoveryServer{d}
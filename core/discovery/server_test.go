@@ -77,3 +77,7 @@ func (d *mockDiscoveryServer) DeleteMetadata(ctx context.Context, in *pb.Metadat
 	<-time.After(5 * time.Millisecond)
 	return &empty.Empty{}, nil
 }
+func (d *mockDiscoveryServer) Watch(req *pb.GetServiceRequest, stream pb.Discovery_WatchServer) error {
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
@@ -0,0 +1,105 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/brocaar/lorawan"
+)
+
+// AckSettingsResponse is returned and accepted by the ack endpoint of the HTTP API
+type AckSettingsResponse struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id"`
+	types.AckSettings
+}
+
+// ApplyAckSettings customizes the acknowledgement of a confirmed uplink
+// message according to the AckSettings of the device. Downlink messages of
+// the application are always sent with the acknowledgement.
+func (h *handler) ApplyAckSettings(ctx ttnlog.Interface, appDown *types.DownlinkMessage, ttnDown *pb_broker.DownlinkMessage, dev *device.Device) error {
+	switch dev.Ack.Mode {
+	case types.AckModeDisabled, types.AckModePayload:
+	default:
+		return nil
+	}
+	if len(appDown.PayloadRaw) > 0 || len(appDown.PayloadFields) > 0 {
+		return nil
+	}
+
+	var phyPayload lorawan.PHYPayload
+	if err := phyPayload.UnmarshalBinary(ttnDown.Payload); err != nil {
+		return err
+	}
+	macPayload, ok := phyPayload.MACPayload.(*lorawan.MACPayload)
+	if !ok {
+		return errors.NewErrInvalidArgument("Downlink", "does not contain a MAC payload")
+	}
+	if !macPayload.FHDR.FCtrl.ACK {
+		return nil
+	}
+
+	if dev.Ack.Mode == types.AckModePayload {
+		ttnDown.Trace = ttnDown.Trace.WithEvent("set ack payload")
+		appDown.FPort = dev.Ack.FPort
+		if len(dev.Ack.PayloadRaw) > 0 {
+			appDown.PayloadRaw = dev.Ack.PayloadRaw
+		} else {
+			appDown.PayloadFields = dev.Ack.PayloadFields
+		}
+		return nil
+	}
+
+	// A downlink that only acknowledges is not sent. If the network has MAC
+	// commands for the device, in FOpts or in FRMPayload, the downlink is sent
+	// and still acknowledges.
+	if len(macPayload.FHDR.FOpts) > 0 || len(macPayload.FRMPayload) > 0 {
+		return nil
+	}
+	ttnDown.Trace = ttnDown.Trace.WithEvent("drop ack")
+	return ErrNotNeeded
+}
+
+func (h *httpHandler) getAckSettings(req *http.Request, appID, devID string) (*AckSettingsResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	return &AckSettingsResponse{AppID: dev.AppID, DevID: dev.DevID, AckSettings: dev.Ack}, nil
+}
+
+// setAckSettings sets how the confirmed uplink messages of the device are acknowledged
+func (h *httpHandler) setAckSettings(req *http.Request, appID, devID string) (*AckSettingsResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	var in AckSettingsResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := in.AckSettings.Validate(); err != nil {
+		return nil, errors.NewErrInvalidArgument("Ack Settings", err.Error())
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	dev.StartUpdate()
+	dev.Ack = in.AckSettings
+	if err := h.manager.handler.devices.Set(dev); err != nil {
+		return nil, err
+	}
+	return &AckSettingsResponse{AppID: dev.AppID, DevID: dev.DevID, AckSettings: dev.Ack}, nil
+}
@@ -0,0 +1,77 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestApplyAckSettings(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestApplyAckSettings")},
+		devices:   device.NewRedisDeviceStore(GetRedisClient(), "handler-test-apply-ack-settings"),
+	}
+	dev := &device.Device{
+		DevID: "devid",
+		AppID: "appid",
+	}
+
+	ack := func() (*types.DownlinkMessage, *pb_broker.DownlinkMessage) {
+		appDown, ttnDown := buildLorawanDownlink(nil)
+		appDown.PayloadRaw = nil
+		ttnDown.Payload = []byte{96, 4, 3, 2, 1, 0x20, 1, 0, 0, 0, 0, 0}
+		return appDown, ttnDown
+	}
+
+	// Automatic ack
+	appDown, ttnDown := ack()
+	a.So(h.ApplyAckSettings(h.Ctx, appDown, ttnDown, dev), ShouldBeNil)
+	a.So(ttnDown.Payload[5], ShouldEqual, 0x20)
+	a.So(h.ConvertToLoRaWAN(h.Ctx, appDown, ttnDown, dev), ShouldBeNil)
+
+	// Disabled ack
+	dev.Ack = types.AckSettings{Mode: types.AckModeDisabled}
+	appDown, ttnDown = ack()
+	a.So(h.ApplyAckSettings(h.Ctx, appDown, ttnDown, dev), ShouldEqual, ErrNotNeeded)
+
+	// The ack is sent with the MAC commands of the network
+	appDown, ttnDown = ack()
+	ttnDown.Payload = []byte{96, 4, 3, 2, 1, 0x21, 1, 0, 0x06, 0, 0, 0, 0}
+	a.So(h.ApplyAckSettings(h.Ctx, appDown, ttnDown, dev), ShouldBeNil)
+	a.So(ttnDown.Payload[5], ShouldEqual, 0x21)
+
+	// The downlink of the application is sent with the ack
+	appDown, ttnDown = ack()
+	appDown.FPort, appDown.PayloadRaw = 2, []byte{0xaa}
+	a.So(h.ApplyAckSettings(h.Ctx, appDown, ttnDown, dev), ShouldBeNil)
+	a.So(ttnDown.Payload[5], ShouldEqual, 0x20)
+
+	// Ack with payload
+	dev.Ack = types.AckSettings{Mode: types.AckModePayload, FPort: 10, PayloadRaw: []byte{0x01, 0x02}}
+	appDown, ttnDown = ack()
+	a.So(h.ApplyAckSettings(h.Ctx, appDown, ttnDown, dev), ShouldBeNil)
+	a.So(appDown.FPort, ShouldEqual, 10)
+	a.So(appDown.PayloadRaw, ShouldResemble, []byte{0x01, 0x02})
+	a.So(ttnDown.Payload[5], ShouldEqual, 0x20)
+
+	dev.Ack = types.AckSettings{Mode: types.AckModePayload, FPort: 10, PayloadFields: map[string]interface{}{"ack": true}}
+	appDown, ttnDown = ack()
+	a.So(h.ApplyAckSettings(h.Ctx, appDown, ttnDown, dev), ShouldBeNil)
+	a.So(appDown.PayloadFields, ShouldResemble, map[string]interface{}{"ack": true})
+
+	// Downlinks without ack are not changed
+	appDown, ttnDown = buildLorawanDownlink(nil)
+	appDown.PayloadRaw = nil
+	a.So(h.ApplyAckSettings(h.Ctx, appDown, ttnDown, dev), ShouldBeNil)
+	a.So(appDown.PayloadRaw, ShouldBeEmpty)
+	a.So(appDown.PayloadFields, ShouldBeEmpty)
+}
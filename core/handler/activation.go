@@ -4,6 +4,7 @@
 package handler
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"time"
 
@@ -15,9 +16,9 @@ import (
 	"github.com/TheThingsNetwork/ttn/core/handler/device"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
-	"github.com/TheThingsNetwork/ttn/utils/otaa"
 	"github.com/TheThingsNetwork/ttn/utils/random"
 	"github.com/brocaar/lorawan"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
 )
 
 func (h *handler) getActivationMetadata(ctx ttnlog.Interface, activation *pb_broker.DeduplicatedDeviceActivationRequest, device *device.Device) (types.Metadata, error) {
@@ -42,7 +43,8 @@ func (h *handler) HandleActivationChallenge(challenge *pb_broker.ActivationChall
 		return nil, err
 	}
 
-	if dev.AppKey.IsEmpty() {
+	crypto := h.getJoinCrypto()
+	if !crypto.HasAppKey(dev) {
 		err = errors.NewErrNotFound(fmt.Sprintf("AppKey for device %s", challenge.DevId))
 		return nil, err
 	}
@@ -54,7 +56,9 @@ func (h *handler) HandleActivationChallenge(challenge *pb_broker.ActivationChall
 	}
 
 	// Set MIC
-	if err := reqPHY.SetMIC(lorawan.AES128Key(dev.AppKey)); err != nil {
+	cryptoCtx, cancel := context.WithTimeout(context.Background(), JoinCryptoTimeout)
+	defer cancel()
+	if reqPHY.MIC, err = crypto.JoinRequestMIC(cryptoCtx, dev, challenge.Payload); err != nil {
 		err = errors.NewErrNotFound("Could not set MIC")
 		return nil, err
 	}
@@ -95,6 +99,12 @@ func (h *handler) HandleActivation(activation *pb_broker.DeduplicatedDeviceActiv
 
 	activation.Trace = activation.Trace.WithEvent(trace.ReceiveEvent)
 
+	// The Broker only forwards join requests that exceed the join rate to report them
+	if h.publishJoinLoop(activation) {
+		err = errJoinLoop
+		return nil, err
+	}
+
 	if activation.ResponseTemplate == nil {
 		err = errors.NewErrInvalidArgument("Activation", "No gateways available for downlink")
 		return nil, err
@@ -107,7 +117,8 @@ func (h *handler) HandleActivation(activation *pb_broker.DeduplicatedDeviceActiv
 		return nil, err
 	}
 
-	if dev.AppKey.IsEmpty() {
+	crypto := h.getJoinCrypto()
+	if !crypto.HasAppKey(dev) {
 		err = errors.NewErrNotFound(fmt.Sprintf("AppKey for device %s", devID))
 		return nil, err
 	}
@@ -129,9 +140,14 @@ func (h *handler) HandleActivation(activation *pb_broker.DeduplicatedDeviceActiv
 		return nil, err
 	}
 
+	// The MIC check and the join-accept share the deadline of the JoinCrypto
+	cryptoCtx, cancel := context.WithTimeout(context.Background(), JoinCryptoTimeout)
+	defer cancel()
+
 	// Validate MIC
 	activation.Trace = activation.Trace.WithEvent(trace.CheckMICEvent)
-	if ok, err = reqPHY.ValidateMIC(lorawan.AES128Key(dev.AppKey)); err != nil || !ok {
+	var mic lorawan.MIC
+	if mic, err = crypto.JoinRequestMIC(cryptoCtx, dev, activation.Payload); err != nil || subtle.ConstantTimeCompare(mic[:], reqPHY.MIC[:]) != 1 {
 		err = errors.NewErrNotFound("MIC does not match device")
 		return nil, err
 	}
@@ -149,6 +165,12 @@ func (h *handler) HandleActivation(activation *pb_broker.DeduplicatedDeviceActiv
 		return nil, err
 	}
 
+	// Devices with copied OTAA credentials keep taking over each other's
+	// session. They are not denied, as one of them is the legitimate device.
+	if dev.Conflict != nil && dev.Conflict.Reason == device.ConflictActivations {
+		ctx.Warn("Activating device with conflicting credentials")
+	}
+
 	ctx.Debug("Accepting Join Request")
 	activation.Trace = activation.Trace.WithEvent(trace.AcceptEvent)
 
@@ -168,18 +190,14 @@ func (h *handler) HandleActivation(activation *pb_broker.DeduplicatedDeviceActiv
 	}
 	resPHY.MACPayload = joinAccept
 
-	// Publish Activation
-	mqttMetadata, _ := h.getActivationMetadata(ctx, activation, dev)
-	h.mqttEvent <- &types.DeviceEvent{
-		AppID: appID,
-		DevID: devID,
-		Event: types.ActivationEvent,
-		Data: types.ActivationEventData{
-			AppEUI:   *activation.AppEui,
-			DevEUI:   *activation.DevEui,
-			DevAddr:  types.DevAddr(joinAccept.DevAddr),
-			Metadata: mqttMetadata,
-		},
+	// Set the receive window parameters of the application and device
+	rxSettings := h.getJoinAcceptSettings(dev)
+	if err = applyJoinAcceptSettings(joinAccept, activation.ActivationMetadata.GetLorawan(), rxSettings); err != nil {
+		return nil, err
+	}
+	if option := activation.ResponseTemplate.GetDownlinkOption(); option != nil && dev.DownlinkProfile.RX2Only {
+		// The device uses the other receive window parameters after the join-accept
+		option.RxSettings = &pb_broker.RXSettings{Rx2Only: true}
 	}
 
 	// Generate random AppNonce
@@ -201,35 +219,52 @@ func (h *handler) HandleActivation(activation *pb_broker.DeduplicatedDeviceActiv
 	}
 	joinAccept.AppNonce = appNonce
 
-	// Calculate session keys
-	appSKey, nwkSKey, err := otaa.CalculateSessionKeys(dev.AppKey, joinAccept.AppNonce, joinAccept.NetID, reqMAC.DevNonce)
+	// Seal the join-accept and calculate the session keys
+	resBytes, err := resPHY.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	resBytes, appSKey, nwkSKey, err := crypto.JoinAccept(cryptoCtx, dev, resBytes, reqMAC.DevNonce)
 	if err != nil {
 		return nil, err
 	}
 
+	// Publish Activation
+	mqttMetadata, _ := h.getActivationMetadata(ctx, activation, dev)
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: appID,
+		DevID: devID,
+		Event: types.ActivationEvent,
+		Data: types.ActivationEventData{
+			AppEUI:   *activation.AppEui,
+			DevEUI:   *activation.DevEui,
+			DevAddr:  types.DevAddr(joinAccept.DevAddr),
+			Metadata: mqttMetadata,
+			EndToEnd: endToEndSession(dev, appNonce, joinAccept.NetID, reqMAC.DevNonce),
+		},
+	}
+
 	// Update Device
 	dev.StartUpdate()
 	dev.DevAddr = types.DevAddr(joinAccept.DevAddr)
 	dev.AppSKey = appSKey
+	if dev.EndToEnd {
+		dev.AppSKey = types.AppSKey{} // The application derives the AppSKey from the activation event
+	}
 	dev.NwkSKey = nwkSKey
 	dev.UsedAppNonces = append(dev.UsedAppNonces, appNonce)
 	dev.UsedDevNonces = append(dev.UsedDevNonces, reqMAC.DevNonce)
+	dev.RXSettings = rxSettings
+	now := time.Now()
+	conflict := checkActivation(dev, now)
+	dev.ActivatedAt = now
+	dev.FCntUp = 0 // The frame counters of the new session start at 0
 	err = h.devices.Set(dev)
 	if err != nil {
 		return nil, err
 	}
-
-	if err = resPHY.SetMIC(lorawan.AES128Key(dev.AppKey)); err != nil {
-		return nil, err
-	}
-	if err = resPHY.EncryptJoinAcceptPayload(lorawan.AES128Key(dev.AppKey)); err != nil {
-		return nil, err
-	}
-
-	var resBytes []byte
-	resBytes, err = resPHY.MarshalBinary()
-	if err != nil {
-		return nil, err
+	if conflict {
+		h.publishConflict(dev)
 	}
 
 	metadata := activation.ActivationMetadata
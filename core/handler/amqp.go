@@ -83,7 +83,7 @@ func (h *handler) HandleAMQP(username, password, host, exchange, downlinkQueue s
 				"DevID": up.DevID,
 				"AppID": up.AppID,
 			}).Debug("Publish Uplink")
-			err := publisher.PublishUplink(*up)
+			err := publisher.PublishUplinkFormat(*up, h.payloadFormat(up.AppID))
 			if err != nil {
 				ctx.WithError(err).Warn("Could not publish Uplink")
 			}
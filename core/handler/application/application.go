@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/fatih/structs"
 )
 
@@ -30,6 +31,67 @@ type Application struct {
 	// Returns an object containing the converted values in []byte
 	Encoder string `redis:"encoder"`
 
+	// PayloadConverters are selected by port and device attribute before the
+	// payload functions above, which are used if no converter matches
+	PayloadConverters []PayloadConverter `redis:"payload_converters,omitempty"`
+
+	// DataRetention is the time after which the data of inactive devices is
+	// erased. Zero means that data is kept until the device is deleted.
+	DataRetention time.Duration `redis:"data_retention"`
+
+	// PayloadFormat is the format in which uplink messages are published over
+	// MQTT and AMQP (json, protobuf, cbor or v3). Empty means json.
+	PayloadFormat string `redis:"payload_format"`
+
+	// TopicPattern is an additional MQTT topic for uplink messages, relative
+	// to the application. See mqtt.TopicPattern for the placeholders.
+	TopicPattern string `redis:"topic_pattern"`
+
+	// TrustedGatewaysOnly marks the metadata of untrusted gateways and
+	// excludes them from application downlink and gateway locations
+	TrustedGatewaysOnly bool `redis:"trusted_gateways_only,omitempty"`
+
+	// MetadataPrivacy selects the gateway metadata that is hidden from the
+	// application. Nil uses the defaults of the Handler.
+	MetadataPrivacy *MetadataPrivacy `redis:"metadata_privacy,omitempty"`
+
+	// DeduplicationWindow is the time in which retransmissions of an uplink
+	// message with the same counter and payload are not published again.
+	// Zero disables deduplication.
+	DeduplicationWindow time.Duration `redis:"deduplication_window,omitempty"`
+
+	// JoinAccept are the receive window parameters that are sent to devices
+	// of the application when they join
+	JoinAccept types.JoinAcceptSettings `redis:"join_accept,omitempty"`
+
+	// Claimable allows devices of the application to be claimed by other
+	// applications with their claim code, for example for a manufacturer
+	Claimable bool `redis:"claimable,omitempty"`
+
+	// PayloadTests are the test vectors of the payload functions, which
+	// are run before the payload functions are updated
+	PayloadTests []PayloadTest `redis:"payload_tests,omitempty"`
+
+	// Profiles are the device profiles that devices of the application can reference
+	Profiles []DeviceProfile `redis:"profiles,omitempty"`
+
+	// Rules trigger actions when the decoded fields of uplink messages match
+	Rules []Rule `redis:"rules,omitempty"`
+
+	// TimeSeries is the database to which the numeric decoded fields of uplink messages are written
+	TimeSeries *TimeSeries `redis:"time_series,omitempty"`
+
+	// MQTTBridge is the external MQTT broker to which the uplink messages and events are published
+	MQTTBridge *MQTTBridge `redis:"mqtt_bridge,omitempty"`
+
+	// Collaborators are the roles of collaborators, which limit the rights
+	// that the account server gives them. Collaborators without a role keep
+	// the rights of their token.
+	Collaborators []Collaborator `redis:"collaborators,omitempty"`
+
+	// Migration moves devices of the application to another Handler
+	Migration *types.Migration `redis:"migration,omitempty"`
+
 	CreatedAt time.Time `redis:"created_at"`
 	UpdatedAt time.Time `redis:"updated_at"`
 }
@@ -0,0 +1,203 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"gopkg.in/redis.v5"
+)
+
+// MaxFunctionsVersions is the number of versions of the payload functions that is kept for each application
+var MaxFunctionsVersions = 50
+
+// FunctionsVersion is a version of the payload functions of an application
+type FunctionsVersion struct {
+	Version   int       `json:"version"`
+	Decoder   string    `json:"decoder,omitempty"`
+	Converter string    `json:"converter,omitempty"`
+	Validator string    `json:"validator,omitempty"`
+	Encoder   string    `json:"encoder,omitempty"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewFunctionsVersion returns a version with the payload functions of the application
+func NewFunctionsVersion(app *Application, author string) *FunctionsVersion {
+	return &FunctionsVersion{
+		Decoder:   app.Decoder,
+		Converter: app.Converter,
+		Validator: app.Validator,
+		Encoder:   app.Encoder,
+		Author:    author,
+	}
+}
+
+// Equals returns true if the versions contain the same payload functions
+func (v *FunctionsVersion) Equals(other *FunctionsVersion) bool {
+	return other != nil &&
+		v.Decoder == other.Decoder &&
+		v.Converter == other.Converter &&
+		v.Validator == other.Validator &&
+		v.Encoder == other.Encoder
+}
+
+// FunctionsHistory stores the versions of the payload functions of applications
+type FunctionsHistory interface {
+	// List the versions of the application, newest first
+	List(appID string) ([]*FunctionsVersion, error)
+	// Get a version of the application
+	Get(appID string, version int) (*FunctionsVersion, error)
+	// Add a version to the application. The version number and creation time are set by the history.
+	Add(appID string, version *FunctionsVersion) error
+	// Delete the history of the application
+	Delete(appID string) error
+}
+
+func getFunctionsVersion(versions []*FunctionsVersion, appID string, version int) (*FunctionsVersion, error) {
+	for _, v := range versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, errors.NewErrNotFound(fmt.Sprintf("Version %d of the payload functions of %s", version, appID))
+}
+
+// NewRedisFunctionsHistory creates a new Redis-based FunctionsHistory
+// if an empty prefix is passed, a default prefix will be used.
+func NewRedisFunctionsHistory(client *redis.Client, prefix string) FunctionsHistory {
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+	return &RedisFunctionsHistory{
+		queues: storage.NewRedisQueueStore(client, prefix+":functions"),
+	}
+}
+
+// RedisFunctionsHistory stores the versions of the payload functions in Redis.
+// - The versions of an application are stored as a List, newest first
+type RedisFunctionsHistory struct {
+	mu     sync.Mutex
+	queues *storage.RedisQueueStore
+}
+
+// List the versions of the application
+func (s *RedisFunctionsHistory) List(appID string) ([]*FunctionsVersion, error) {
+	res, err := s.queues.GetFront(appID, MaxFunctionsVersions)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*FunctionsVersion, 0, len(res))
+	for _, data := range res {
+		version := new(FunctionsVersion)
+		if err := json.Unmarshal([]byte(data), version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// Get a version of the application
+func (s *RedisFunctionsHistory) Get(appID string, version int) (*FunctionsVersion, error) {
+	versions, err := s.List(appID)
+	if err != nil {
+		return nil, err
+	}
+	return getFunctionsVersion(versions, appID, version)
+}
+
+// Add a version to the application
+func (s *RedisFunctionsHistory) Add(appID string, version *FunctionsVersion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latest, err := s.List(appID)
+	if err != nil {
+		return err
+	}
+	version.Version = 1
+	if len(latest) > 0 {
+		version.Version = latest[0].Version + 1
+	}
+	version.CreatedAt = time.Now().UTC()
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+	if err := s.queues.AddFront(appID, string(data)); err != nil {
+		return err
+	}
+	return s.queues.Trim(appID, MaxFunctionsVersions)
+}
+
+// Delete the history of the application
+func (s *RedisFunctionsHistory) Delete(appID string) error {
+	return s.queues.Delete(appID)
+}
+
+// NewMemoryFunctionsHistory creates a new in-memory FunctionsHistory
+func NewMemoryFunctionsHistory() FunctionsHistory {
+	return &MemoryFunctionsHistory{
+		versions: make(map[string][]*FunctionsVersion),
+	}
+}
+
+// MemoryFunctionsHistory stores the versions of the payload functions in memory
+type MemoryFunctionsHistory struct {
+	mu       sync.RWMutex
+	versions map[string][]*FunctionsVersion
+}
+
+// List the versions of the application
+func (s *MemoryFunctionsHistory) List(appID string) ([]*FunctionsVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := make([]*FunctionsVersion, 0, len(s.versions[appID]))
+	for _, version := range s.versions[appID] {
+		v := *version
+		versions = append(versions, &v)
+	}
+	return versions, nil
+}
+
+// Get a version of the application
+func (s *MemoryFunctionsHistory) Get(appID string, version int) (*FunctionsVersion, error) {
+	versions, err := s.List(appID)
+	if err != nil {
+		return nil, err
+	}
+	return getFunctionsVersion(versions, appID, version)
+}
+
+// Add a version to the application
+func (s *MemoryFunctionsHistory) Add(appID string, version *FunctionsVersion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := *version
+	v.Version = 1
+	if latest := s.versions[appID]; len(latest) > 0 {
+		v.Version = latest[0].Version + 1
+	}
+	v.CreatedAt = time.Now().UTC()
+	versions := append([]*FunctionsVersion{&v}, s.versions[appID]...)
+	if len(versions) > MaxFunctionsVersions {
+		versions = versions[:MaxFunctionsVersions]
+	}
+	s.versions[appID] = versions
+	version.Version, version.CreatedAt = v.Version, v.CreatedAt
+	return nil
+}
+
+// Delete the history of the application
+func (s *MemoryFunctionsHistory) Delete(appID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.versions, appID)
+	return nil
+}
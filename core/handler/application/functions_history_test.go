@@ -0,0 +1,65 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import (
+	"testing"
+
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func testFunctionsHistory(t *testing.T, s FunctionsHistory) {
+	a := New(t)
+
+	appID := "AppID-1"
+	defer s.Delete(appID)
+
+	versions, err := s.List(appID)
+	a.So(err, ShouldBeNil)
+	a.So(versions, ShouldBeEmpty)
+
+	_, err = s.Get(appID, 1)
+	a.So(err, ShouldNotBeNil)
+
+	first := NewFunctionsVersion(&Application{Decoder: "decoder"}, "alice")
+	a.So(s.Add(appID, first), ShouldBeNil)
+	a.So(first.Version, ShouldEqual, 1)
+	a.So(first.CreatedAt.IsZero(), ShouldBeFalse)
+
+	a.So(s.Add(appID, NewFunctionsVersion(&Application{Decoder: "new decoder"}, "bob")), ShouldBeNil)
+
+	versions, err = s.List(appID)
+	a.So(err, ShouldBeNil)
+	a.So(versions, ShouldHaveLength, 2)
+	a.So(versions[0].Version, ShouldEqual, 2)
+	a.So(versions[0].Author, ShouldEqual, "bob")
+	a.So(versions[1].Decoder, ShouldEqual, "decoder")
+
+	version, err := s.Get(appID, 1)
+	a.So(err, ShouldBeNil)
+	a.So(version.Equals(first), ShouldBeTrue)
+
+	// Old versions are removed
+	defer func(max int) { MaxFunctionsVersions = max }(MaxFunctionsVersions)
+	MaxFunctionsVersions = 2
+	a.So(s.Add(appID, NewFunctionsVersion(&Application{}, "alice")), ShouldBeNil)
+	versions, _ = s.List(appID)
+	a.So(versions, ShouldHaveLength, 2)
+	a.So(versions[0].Version, ShouldEqual, 3)
+	_, err = s.Get(appID, 1)
+	a.So(err, ShouldNotBeNil)
+
+	a.So(s.Delete(appID), ShouldBeNil)
+	versions, _ = s.List(appID)
+	a.So(versions, ShouldBeEmpty)
+}
+
+func TestRedisFunctionsHistory(t *testing.T) {
+	testFunctionsHistory(t, NewRedisFunctionsHistory(GetRedisClient(), "handler-test-functions-history"))
+}
+
+func TestMemoryFunctionsHistory(t *testing.T) {
+	testFunctionsHistory(t, NewMemoryFunctionsHistory())
+}
@@ -0,0 +1,94 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// NewMemoryApplicationStore creates a new in-memory Application store. It can
+// be used in tests or when embedding the Handler without Redis.
+func NewMemoryApplicationStore() Store {
+	return &MemoryApplicationStore{
+		applications: make(map[string]Application),
+	}
+}
+
+// MemoryApplicationStore stores Applications in memory
+type MemoryApplicationStore struct {
+	mu           sync.RWMutex
+	applications map[string]Application
+}
+
+// List all Applications
+func (s *MemoryApplicationStore) List(opts *storage.ListOptions) ([]*Application, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.applications))
+	for key := range s.applications {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	keys = storage.SelectKeys(keys, opts)
+	applications := make([]*Application, 0, len(keys))
+	for _, key := range keys {
+		application := s.applications[key]
+		applications = append(applications, &application)
+	}
+	return applications, nil
+}
+
+// Get a specific Application
+func (s *MemoryApplicationStore) Get(appID string) (*Application, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	application, ok := s.applications[appID]
+	if !ok {
+		return nil, errors.NewErrNotFound(appID)
+	}
+	return &application, nil
+}
+
+// Set a new Application or update an existing one
+func (s *MemoryApplicationStore) Set(new *Application, properties ...string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	new.UpdatedAt = now
+
+	_, exists := s.applications[new.AppID]
+	if new.old != nil {
+		if !exists {
+			return errors.NewErrNotFound(new.AppID)
+		}
+	} else {
+		if exists {
+			return errors.NewErrAlreadyExists(new.AppID)
+		}
+		new.CreatedAt = now
+	}
+
+	stored := *new
+	stored.old = nil
+	s.applications[new.AppID] = stored
+
+	return nil
+}
+
+// Delete an Application
+func (s *MemoryApplicationStore) Delete(appID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.applications[appID]; !ok {
+		return errors.NewErrNotFound(appID)
+	}
+	delete(s.applications, appID)
+	return nil
+}
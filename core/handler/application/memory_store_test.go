@@ -0,0 +1,50 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestMemoryApplicationStore(t *testing.T) {
+	a := New(t)
+
+	s := NewMemoryApplicationStore()
+
+	appID := "AppID-1"
+
+	app, err := s.Get(appID)
+	a.So(err, ShouldNotBeNil)
+	a.So(app, ShouldBeNil)
+
+	err = s.Set(&Application{AppID: appID, Encoder: "encoder"})
+	a.So(err, ShouldBeNil)
+
+	err = s.Set(&Application{AppID: appID})
+	a.So(err, ShouldNotBeNil)
+
+	app, err = s.Get(appID)
+	a.So(err, ShouldBeNil)
+	a.So(app.Encoder, ShouldEqual, "encoder")
+
+	app.StartUpdate()
+	app.Encoder = "new encoder"
+	a.So(s.Set(app), ShouldBeNil)
+
+	app, err = s.Get(appID)
+	a.So(err, ShouldBeNil)
+	a.So(app.Encoder, ShouldEqual, "new encoder")
+
+	apps, err := s.List(nil)
+	a.So(err, ShouldBeNil)
+	a.So(apps, ShouldHaveLength, 1)
+
+	a.So(s.Delete(appID), ShouldBeNil)
+
+	app, err = s.Get(appID)
+	a.So(err, ShouldNotBeNil)
+	a.So(app, ShouldBeNil)
+}
@@ -0,0 +1,61 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// MaxLocationDecimals is the maximum number of decimals to which gateway locations can be rounded
+const MaxLocationDecimals = 6
+
+// MetadataPrivacy selects the gateway metadata that is hidden from the
+// application before its uplink messages are published
+type MetadataPrivacy struct {
+	// HideGatewayIDs removes the IDs of the gateways
+	HideGatewayIDs bool `json:"hide_gateway_ids,omitempty"`
+	// HideLocations removes the locations of the gateways
+	HideLocations bool `json:"hide_locations,omitempty"`
+	// LocationDecimals rounds the locations of the gateways to this number of
+	// decimals, so that 2 is a precision of about 1 km. Zero keeps them precise.
+	LocationDecimals int `json:"location_decimals,omitempty"`
+	// HideTimestamps removes the timestamps and times of the gateways
+	HideTimestamps bool `json:"hide_timestamps,omitempty"`
+	// UntrustedOnly only hides the metadata of gateways that are not trusted,
+	// such as community gateways
+	UntrustedOnly bool `json:"untrusted_only,omitempty"`
+}
+
+// Validate the MetadataPrivacy
+func (p MetadataPrivacy) Validate() error {
+	if p.LocationDecimals < 0 || p.LocationDecimals > MaxLocationDecimals {
+		return errors.NewErrInvalidArgument("Location Decimals", fmt.Sprintf("must be between 0 and %d", MaxLocationDecimals))
+	}
+	return nil
+}
+
+// Apply hides the metadata of the gateway
+func (p MetadataPrivacy) Apply(gateway *types.GatewayMetadata) {
+	if p.UntrustedOnly && gateway.GtwTrusted {
+		return
+	}
+	if p.HideGatewayIDs {
+		gateway.GtwID = ""
+	}
+	if p.HideLocations {
+		gateway.LocationMetadata = types.LocationMetadata{}
+	} else if p.LocationDecimals > 0 {
+		factor := math.Pow(10, float64(p.LocationDecimals))
+		gateway.Latitude = float32(math.Floor(float64(gateway.Latitude)*factor+0.5) / factor)
+		gateway.Longitude = float32(math.Floor(float64(gateway.Longitude)*factor+0.5) / factor)
+	}
+	if p.HideTimestamps {
+		gateway.Timestamp = 0
+		gateway.Time = types.JSONTime{}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+// MQTTBridge configures an external MQTT broker to which the uplink messages
+// and events of the application are published
+type MQTTBridge struct {
+	// URL is the URL of the broker, such as tcp://broker.example.com:1883, or
+	// ssl://broker.example.com:8883 for TLS
+	URL      string `json:"url"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// TopicPrefix is added to the topics, so that the prefix "ttn/" results in
+	// topics such as ttn/<AppID>/devices/<DevID>/up
+	TopicPrefix string `json:"topic_prefix,omitempty"`
+	// Downlink subscribes to the downlink topics of the application on the broker
+	Downlink bool `json:"downlink,omitempty"`
+}
@@ -0,0 +1,78 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+// PayloadConverter contains the payload functions for the messages that
+// match its port and device attribute. This allows applications with
+// different types of devices to decode each product with its own functions.
+type PayloadConverter struct {
+	ID string `json:"id"`
+
+	// Port selects messages on this FPort. Zero matches all ports.
+	Port uint8 `json:"port,omitempty"`
+	// Attribute and Value select devices that have this value for the
+	// attribute, such as model=tracker. An empty attribute matches all devices.
+	Attribute string `json:"attribute,omitempty"`
+	Value     string `json:"value,omitempty"`
+
+	Decoder   string `json:"decoder,omitempty"`
+	Converter string `json:"converter,omitempty"`
+	Validator string `json:"validator,omitempty"`
+	Encoder   string `json:"encoder,omitempty"`
+}
+
+// Matches returns true if the converter applies to messages on the port of a
+// device with the attributes
+func (c PayloadConverter) Matches(port uint8, attributes map[string]string) bool {
+	if c.Port != 0 && c.Port != port {
+		return false
+	}
+	if c.Attribute != "" && attributes[c.Attribute] != c.Value {
+		return false
+	}
+	return true
+}
+
+// hasUplinkFunctions returns true if the converter has a function for uplink
+// messages
+func (c PayloadConverter) hasUplinkFunctions() bool {
+	return c.Decoder != "" || c.Converter != "" || c.Validator != ""
+}
+
+// PayloadConverterFor returns the payload functions for messages on the port of
+// a device with the attributes. The uplink functions come from the first
+// matching converter that has a decoder, converter or validator, and the
+// encoder from the first matching converter that has an encoder. Functions
+// that no matching converter has fall back to those of the application. The ID
+// is that of the first converter that is used.
+func (a Application) PayloadConverterFor(port uint8, attributes map[string]string) PayloadConverter {
+	functions := PayloadConverter{
+		Decoder:   a.Decoder,
+		Converter: a.Converter,
+		Validator: a.Validator,
+		Encoder:   a.Encoder,
+	}
+	var uplink, downlink bool
+	for _, converter := range a.PayloadConverters {
+		if !converter.Matches(port, attributes) {
+			continue
+		}
+		used := false
+		if !uplink && converter.hasUplinkFunctions() {
+			functions.Decoder, functions.Converter, functions.Validator = converter.Decoder, converter.Converter, converter.Validator
+			uplink, used = true, true
+		}
+		if !downlink && converter.Encoder != "" {
+			functions.Encoder = converter.Encoder
+			downlink, used = true, true
+		}
+		if used && functions.ID == "" {
+			functions.ID = converter.ID
+		}
+		if uplink && downlink {
+			break
+		}
+	}
+	return functions
+}
@@ -0,0 +1,42 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPayloadConverterFor(t *testing.T) {
+	a := New(t)
+
+	app := Application{
+		Decoder: "default",
+		Encoder: "default",
+		PayloadConverters: []PayloadConverter{
+			{ID: "tracker-gps", Port: 2, Attribute: "model", Value: "tracker", Decoder: "tracker-gps"},
+			{ID: "tracker", Attribute: "model", Value: "tracker", Decoder: "tracker"},
+			{ID: "config", Port: 100, Decoder: "config", Encoder: "config"},
+		},
+	}
+	tracker := map[string]string{"model": "tracker"}
+
+	a.So(app.PayloadConverterFor(2, tracker).ID, ShouldEqual, "tracker-gps")
+	a.So(app.PayloadConverterFor(1, tracker).ID, ShouldEqual, "tracker")
+	a.So(app.PayloadConverterFor(1, tracker).Encoder, ShouldEqual, "default")
+
+	config := app.PayloadConverterFor(100, tracker)
+	a.So(config.ID, ShouldEqual, "tracker")
+	a.So(config.Decoder, ShouldEqual, "tracker")
+	a.So(config.Encoder, ShouldEqual, "config")
+
+	a.So(app.PayloadConverterFor(100, nil).Encoder, ShouldEqual, "config")
+	a.So(app.PayloadConverterFor(2, map[string]string{"model": "sensor"}).ID, ShouldEqual, "")
+
+	fallback := app.PayloadConverterFor(1, nil)
+	a.So(fallback.ID, ShouldEqual, "")
+	a.So(fallback.Decoder, ShouldEqual, "default")
+	a.So(fallback.Encoder, ShouldEqual, "default")
+}
@@ -0,0 +1,18 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+// PayloadTest is a test vector for the payload functions of an application:
+// the fields that are expected from the decoder, converter and validator for
+// a payload on a port
+type PayloadTest struct {
+	Name    string                 `json:"name,omitempty"`
+	Port    uint8                  `json:"port"`
+	Payload []byte                 `json:"payload_raw"`
+	Fields  map[string]interface{} `json:"payload_fields,omitempty"`
+	// Attributes are the device attributes that select the payload converter
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Invalid tests expect the validator to reject the payload
+	Invalid bool `json:"invalid,omitempty"`
+}
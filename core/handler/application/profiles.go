@@ -0,0 +1,47 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import "github.com/TheThingsNetwork/ttn/core/types"
+
+// DeviceProfile contains the settings that are shared by the devices that
+// reference it, so that changing the profile changes all these devices
+type DeviceProfile struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+
+	// LoRaWANVersion is the LoRaWAN version of the devices (1.0, 1.0.1 or 1.0.2)
+	LoRaWANVersion string `json:"lorawan_version,omitempty"`
+	// Class is the device class of the devices (A, B or C)
+	Class string `json:"class,omitempty"`
+
+	// JoinAccept are the receive window parameters of the devices, which
+	// override those of the application and are overridden by those of a device
+	JoinAccept types.JoinAcceptSettings `json:"join_accept"`
+
+	// Codec is the payload codec of the devices in the codec repository of the
+	// Handler. The codec attribute of a device takes precedence.
+	Codec string `json:"codec,omitempty"`
+
+	// ADRLimits are the data rates and TX power that ADR of the NetworkServer
+	// can select for the devices
+	ADRLimits ADRLimits `json:"adr_limits"`
+}
+
+// ADRLimits restricts the data rates and TX power that ADR selects
+type ADRLimits struct {
+	MinDataRate string `json:"min_data_rate,omitempty"` // the slowest data rate, such as SF10BW125
+	MaxDataRate string `json:"max_data_rate,omitempty"` // the fastest data rate, such as SF7BW125
+	MaxTxPower  int    `json:"max_tx_power,omitempty"`  // in dBm, no limit if zero
+}
+
+// Profile returns the device profile with the ID
+func (a Application) Profile(id string) (DeviceProfile, bool) {
+	for _, profile := range a.Profiles {
+		if profile.ID == id {
+			return profile, true
+		}
+	}
+	return DeviceProfile{}, false
+}
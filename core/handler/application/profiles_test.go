@@ -0,0 +1,21 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestProfile(t *testing.T) {
+	a := New(t)
+
+	app := Application{Profiles: []DeviceProfile{{ID: "sensor", Class: "A"}, {ID: "actuator", Class: "C"}}}
+	profile, ok := app.Profile("actuator")
+	a.So(ok, ShouldBeTrue)
+	a.So(profile.Class, ShouldEqual, "C")
+	_, ok = app.Profile("tracker")
+	a.So(ok, ShouldBeFalse)
+}
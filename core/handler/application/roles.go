@@ -0,0 +1,58 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import "github.com/TheThingsNetwork/go-account-lib/rights"
+
+// Role of a collaborator of an application. The role limits the rights that
+// the account server gives the collaborator.
+type Role string
+
+// Collaborator roles
+const (
+	// RoleViewer can read the settings, devices and messages of the application
+	RoleViewer Role = "viewer"
+	// RoleDeveloper can also change the settings and devices, and send downlink messages
+	RoleDeveloper Role = "developer"
+	// RoleAdmin can also manage the roles of collaborators and delete the application
+	RoleAdmin Role = "admin"
+)
+
+// Valid returns true if the role is known
+func (r Role) Valid() bool {
+	switch r {
+	case RoleViewer, RoleDeveloper, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Allows returns true if the role allows using the right, for writing or only for reading
+func (r Role) Allows(right rights.Right, write bool) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleDeveloper:
+		return right != rights.AppDelete && right != rights.AppCollaborators
+	case RoleViewer:
+		return !write && right != rights.AppCollaborators
+	}
+	return false
+}
+
+// Collaborator is a user with a role in the application
+type Collaborator struct {
+	Username string `json:"username"`
+	Role     Role   `json:"role"`
+}
+
+// CollaboratorRole returns the role of the user in the application
+func (a Application) CollaboratorRole(username string) (Role, bool) {
+	for _, collaborator := range a.Collaborators {
+		if collaborator.Username == username {
+			return collaborator.Role, true
+		}
+	}
+	return "", false
+}
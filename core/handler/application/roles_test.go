@@ -0,0 +1,40 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestRoles(t *testing.T) {
+	a := New(t)
+
+	a.So(RoleViewer.Valid(), ShouldBeTrue)
+	a.So(Role("owner").Valid(), ShouldBeFalse)
+
+	a.So(RoleViewer.Allows(rights.Devices, false), ShouldBeTrue)
+	a.So(RoleViewer.Allows(rights.AppSettings, false), ShouldBeTrue)
+	a.So(RoleViewer.Allows(rights.Devices, true), ShouldBeFalse)
+	a.So(RoleViewer.Allows(rights.AppCollaborators, false), ShouldBeFalse)
+
+	a.So(RoleDeveloper.Allows(rights.Devices, true), ShouldBeTrue)
+	a.So(RoleDeveloper.Allows(rights.AppSettings, true), ShouldBeTrue)
+	a.So(RoleDeveloper.Allows(rights.AppDelete, true), ShouldBeFalse)
+	a.So(RoleDeveloper.Allows(rights.AppCollaborators, true), ShouldBeFalse)
+
+	a.So(RoleAdmin.Allows(rights.AppDelete, true), ShouldBeTrue)
+	a.So(RoleAdmin.Allows(rights.AppCollaborators, true), ShouldBeTrue)
+
+	a.So(Role("").Allows(rights.Devices, false), ShouldBeFalse)
+
+	app := Application{Collaborators: []Collaborator{{Username: "alice", Role: RoleViewer}}}
+	role, ok := app.CollaboratorRole("alice")
+	a.So(ok, ShouldBeTrue)
+	a.So(role, ShouldEqual, RoleViewer)
+	_, ok = app.CollaboratorRole("bob")
+	a.So(ok, ShouldBeFalse)
+}
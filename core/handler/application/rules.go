@@ -0,0 +1,33 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package application
+
+import "github.com/TheThingsNetwork/ttn/core/types"
+
+// Rule triggers actions when the decoded fields of an uplink message match
+// all its conditions
+type Rule struct {
+	ID         string          `json:"id"`
+	Conditions []RuleCondition `json:"conditions"`
+	// Interval is the minimum time between two triggers of the rule for the
+	// same device, for example "15m". Empty means DefaultRuleInterval.
+	Interval string `json:"interval,omitempty"`
+
+	// Downlink is enqueued for the device, at the end of the queue if the
+	// schedule is not set
+	Downlink *types.DownlinkMessage `json:"downlink,omitempty"`
+	// Webhook is a URL to which the uplink message is posted
+	Webhook string `json:"webhook,omitempty"`
+	// Alert is the message of an alert event
+	Alert string `json:"alert,omitempty"`
+}
+
+// RuleCondition compares a decoded field with a value. Nested fields are
+// separated with dots, such as gps.zone.
+type RuleCondition struct {
+	Field string `json:"field"`
+	// Operator is eq, ne, gt, gte, lt or lte
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
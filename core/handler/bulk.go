@@ -0,0 +1,399 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+// Bulk actions
+const (
+	BulkSet     = "set"
+	BulkDelete  = "delete"
+	BulkRequeue = "requeue"
+)
+
+// DeviceFilter selects devices of an application. A device is selected if it
+// matches all criteria that are set.
+type DeviceFilter struct {
+	// The IDs of the devices. An ID that ends with a * matches all IDs that
+	// start with the part before the *, so "*" selects all devices.
+	DevIDs []string `json:"dev_ids,omitempty"`
+	// Text in the description of the devices
+	Description string `json:"description,omitempty"`
+	// The activation constraints of the devices
+	ActivationConstraints string `json:"activation_constraints,omitempty"`
+	// Words in the ID, DevEUI, name, description or tags of the devices
+	Query string `json:"query,omitempty"`
+	// The tags that the devices must all have
+	Tags []string `json:"tags,omitempty"`
+}
+
+// IsEmpty returns true if the filter has no criteria
+func (f DeviceFilter) IsEmpty() bool {
+	return len(f.DevIDs) == 0 && f.Description == "" && f.ActivationConstraints == "" && f.Query == "" && len(f.Tags) == 0
+}
+
+// Matches returns true if the device is selected by the filter
+func (f DeviceFilter) Matches(dev *device.Device) bool {
+	if len(f.DevIDs) > 0 {
+		var match bool
+		for _, pattern := range f.DevIDs {
+			if pattern == dev.DevID || (strings.HasSuffix(pattern, "*") && strings.HasPrefix(dev.DevID, strings.TrimSuffix(pattern, "*"))) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	if f.Description != "" && !strings.Contains(dev.Description, f.Description) {
+		return false
+	}
+	if f.ActivationConstraints != "" && dev.Options.ActivationConstraints != f.ActivationConstraints {
+		return false
+	}
+	if f.Query != "" && !matchesDeviceQuery(dev, f.Query) {
+		return false
+	}
+	if !hasDeviceTags(dev, f.Tags) {
+		return false
+	}
+	return true
+}
+
+// DeviceUpdate is a partial update of devices. Only the fields that are set
+// are changed.
+type DeviceUpdate struct {
+	Description           *string                   `json:"description,omitempty"`
+	Latitude              *float32                  `json:"latitude,omitempty"`
+	Longitude             *float32                  `json:"longitude,omitempty"`
+	Altitude              *int32                    `json:"altitude,omitempty"`
+	ActivationConstraints *string                   `json:"activation_constraints,omitempty"`
+	DisableFCntCheck      *bool                     `json:"disable_fcnt_check,omitempty"`
+	Uses32BitFCnt         *bool                     `json:"uses_32_bit_fcnt,omitempty"`
+	DisableADR            *bool                     `json:"disable_adr,omitempty"`
+	JoinAccept            *types.JoinAcceptSettings `json:"join_accept,omitempty"`
+}
+
+// IsEmpty returns true if the update does not change anything
+func (u DeviceUpdate) IsEmpty() bool {
+	return u == DeviceUpdate{}
+}
+
+// applyTo returns a copy of the device with the fields of the update
+func (u DeviceUpdate) applyTo(dev *pb.Device) *pb.Device {
+	updated := *dev
+	lorawan := *dev.GetLorawanDevice()
+	updated.Device = &pb.Device_LorawanDevice{LorawanDevice: &lorawan}
+	if u.Description != nil {
+		updated.Description = *u.Description
+	}
+	if u.Latitude != nil {
+		updated.Latitude = *u.Latitude
+	}
+	if u.Longitude != nil {
+		updated.Longitude = *u.Longitude
+	}
+	if u.Altitude != nil {
+		updated.Altitude = *u.Altitude
+	}
+	if u.ActivationConstraints != nil {
+		lorawan.ActivationConstraints = *u.ActivationConstraints
+	}
+	if u.DisableFCntCheck != nil {
+		lorawan.DisableFCntCheck = *u.DisableFCntCheck
+	}
+	if u.Uses32BitFCnt != nil {
+		lorawan.Uses32BitFCnt = *u.Uses32BitFCnt
+	}
+	if u.DisableADR != nil {
+		lorawan.DisableAdr = *u.DisableADR
+	}
+	return &updated
+}
+
+// revert returns the update that sets the fields that u changes back to those
+// of the original device and join-accept settings
+func (u DeviceUpdate) revert(original *pb.Device, joinAccept types.JoinAcceptSettings) DeviceUpdate {
+	lorawan := original.GetLorawanDevice()
+	var reverted DeviceUpdate
+	if u.Description != nil {
+		reverted.Description = &original.Description
+	}
+	if u.Latitude != nil {
+		reverted.Latitude = &original.Latitude
+	}
+	if u.Longitude != nil {
+		reverted.Longitude = &original.Longitude
+	}
+	if u.Altitude != nil {
+		reverted.Altitude = &original.Altitude
+	}
+	if u.ActivationConstraints != nil {
+		reverted.ActivationConstraints = &lorawan.ActivationConstraints
+	}
+	if u.DisableFCntCheck != nil {
+		reverted.DisableFCntCheck = &lorawan.DisableFCntCheck
+	}
+	if u.Uses32BitFCnt != nil {
+		reverted.Uses32BitFCnt = &lorawan.Uses32BitFCnt
+	}
+	if u.DisableADR != nil {
+		reverted.DisableADR = &lorawan.DisableAdr
+	}
+	if u.JoinAccept != nil {
+		reverted.JoinAccept = &joinAccept
+	}
+	return reverted
+}
+
+// BulkRequest is accepted by the bulk endpoint of the HTTP API. The BulkDevices
+// RPC of the ApplicationManager accepts the same request as a BulkDevicesRequest.
+type BulkRequest struct {
+	Filter DeviceFilter  `json:"filter"`
+	Action string        `json:"action"`
+	Set    *DeviceUpdate `json:"set,omitempty"`
+	DryRun bool          `json:"dry_run,omitempty"`
+}
+
+// BulkResponse is returned by the bulk endpoint of the HTTP API
+type BulkResponse struct {
+	AppID   string   `json:"app_id"`
+	Action  string   `json:"action"`
+	DryRun  bool     `json:"dry_run,omitempty"`
+	Devices []string `json:"devices"`
+}
+
+func (in *BulkRequest) validate() error {
+	if in.Filter.IsEmpty() {
+		return errors.NewErrInvalidArgument("Filter", `can not be empty, use dev_ids ["*"] to select all devices`)
+	}
+	switch in.Action {
+	case BulkSet:
+		if in.Set == nil || in.Set.IsEmpty() {
+			return errors.NewErrInvalidArgument("Set", "can not be empty")
+		}
+		if in.Set.JoinAccept != nil {
+			if err := validateJoinAcceptSettings(*in.Set.JoinAccept, ""); err != nil {
+				return err
+			}
+		}
+	case BulkDelete, BulkRequeue:
+		if in.Set != nil {
+			return errors.NewErrInvalidArgument("Set", fmt.Sprintf("can not be used with %s", in.Action))
+		}
+	default:
+		return errors.NewErrInvalidArgument("Action", fmt.Sprintf("must be %s, %s or %s", BulkSet, BulkDelete, BulkRequeue))
+	}
+	return nil
+}
+
+// selectDevices returns the devices of the application that match the filter, sorted by ID
+func (h *handler) selectDevices(appID string, filter DeviceFilter) ([]*device.Device, error) {
+	devices, err := h.devices.ListForApp(appID, nil)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]*device.Device, 0, len(devices))
+	for _, dev := range devices {
+		if filter.Matches(dev) {
+			selected = append(selected, dev)
+		}
+	}
+	sort.Sort(devicesByID(selected))
+	return selected, nil
+}
+
+type devicesByID []*device.Device
+
+func (d devicesByID) Len() int           { return len(d) }
+func (d devicesByID) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d devicesByID) Less(i, j int) bool { return d[i].DevID < d[j].DevID }
+
+func (h *httpHandler) bulk(req *http.Request, appID string) (*BulkResponse, error) {
+	ctx, err := h.authorize(req, appID, rights.Devices)
+	if err != nil {
+		return nil, err
+	}
+	var in BulkRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	return h.manager.bulk(ctx, appID, &in)
+}
+
+func (h *handlerManager) BulkDevices(ctx context.Context, in *pb.BulkDevicesRequest) (*pb.BulkDevicesResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Invalid Bulk Devices Request")
+	}
+	ctx, claims, err := h.validateTTNAuthAppContext(ctx, in.AppId)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.checkAppRights(claims, in.AppId, rights.Devices, true); err != nil {
+		return nil, err
+	}
+	req := &BulkRequest{
+		Filter: DeviceFilter{
+			DevIDs:                in.DevIds,
+			Description:           in.Description,
+			ActivationConstraints: in.ActivationConstraints,
+			Query:                 in.Query,
+			Tags:                  in.Tags,
+		},
+		Action: in.Action,
+		DryRun: in.DryRun,
+	}
+	if in.Set != "" {
+		req.Set = new(DeviceUpdate)
+		if err := json.Unmarshal([]byte(in.Set), req.Set); err != nil {
+			return nil, errors.NewErrInvalidArgument("Set", err.Error())
+		}
+	}
+	res, err := h.bulk(ctx, in.AppId, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BulkDevicesResponse{DevIds: res.Devices}, nil
+}
+
+// bulk applies an action to the devices of an application that match a filter.
+// Updates are applied to all devices or to none: the updated devices are
+// validated before any device is changed, and if a device can not be updated,
+// the devices that were already updated are restored.
+func (h *handlerManager) bulk(ctx context.Context, appID string, in *BulkRequest) (*BulkResponse, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := h.handler.applications.Get(appID); err != nil {
+		return nil, errors.Wrap(err, "Application not registered to this Handler")
+	}
+
+	h.handler.provisionLock.Lock()
+	defer h.handler.provisionLock.Unlock()
+
+	devices, err := h.handler.selectDevices(appID, in.Filter)
+	if err != nil {
+		return nil, err
+	}
+	response := &BulkResponse{AppID: appID, Action: in.Action, DryRun: in.DryRun, Devices: make([]string, 0, len(devices))}
+	for _, dev := range devices {
+		response.Devices = append(response.Devices, dev.DevID)
+	}
+	if in.DryRun || len(devices) == 0 {
+		return response, nil
+	}
+
+	switch in.Action {
+	case BulkSet:
+		err = h.bulkSet(ctx, devices, *in.Set)
+	case BulkDelete:
+		err = h.bulkDelete(ctx, devices)
+	case BulkRequeue:
+		err = h.handler.bulkRequeue(devices)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// bulkSet applies the update to the devices. If a device can not be updated,
+// the fields that the update changed are set back on the devices that were
+// already updated. Restoring does not touch the frame counters, which may
+// have moved on in the meantime.
+func (h *handlerManager) bulkSet(ctx context.Context, devices []*device.Device, update DeviceUpdate) error {
+	originals := make([]*pb.Device, 0, len(devices))
+	for _, dev := range devices {
+		original, err := h.GetDevice(ctx, &pb.DeviceIdentifier{AppId: dev.AppID, DevId: dev.DevID})
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Could not get device %s", dev.DevID))
+		}
+		if err := update.applyTo(original).Validate(); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Invalid update of device %s", dev.DevID))
+		}
+		originals = append(originals, original)
+	}
+
+	for i, dev := range devices {
+		if err := h.setDeviceFields(ctx, dev, update); err != nil {
+			for j := 0; j <= i; j++ {
+				if err := h.setDeviceFields(ctx, devices[j], update.revert(originals[j], devices[j].JoinAccept)); err != nil {
+					h.handler.Ctx.WithField("AppID", devices[j].AppID).WithField("DevID", devices[j].DevID).WithError(err).Warn("Could not restore device after failed bulk update")
+				}
+			}
+			return errors.Wrap(err, fmt.Sprintf("Could not update device %s", dev.DevID))
+		}
+	}
+	return nil
+}
+
+// setDeviceFields gets the current device and sets the fields of the update,
+// so that the frame counters of the device are kept
+func (h *handlerManager) setDeviceFields(ctx context.Context, dev *device.Device, update DeviceUpdate) error {
+	current, err := h.GetDevice(ctx, &pb.DeviceIdentifier{AppId: dev.AppID, DevId: dev.DevID})
+	if err != nil {
+		return err
+	}
+	if _, err := h.SetDevice(ctx, update.applyTo(current)); err != nil {
+		return err
+	}
+	if update.JoinAccept == nil {
+		return nil
+	}
+	stored, err := h.handler.devices.Get(dev.AppID, dev.DevID)
+	if err != nil {
+		return err
+	}
+	stored.StartUpdate()
+	stored.JoinAccept = *update.JoinAccept
+	return h.handler.devices.Set(stored)
+}
+
+// bulkDelete deletes the devices. Deleted devices can not be restored, so the
+// deletion stops at the first device that can not be deleted.
+func (h *handlerManager) bulkDelete(ctx context.Context, devices []*device.Device) error {
+	for _, dev := range devices {
+		if _, err := h.DeleteDevice(ctx, &pb.DeviceIdentifier{AppId: dev.AppID, DevId: dev.DevID}); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Could not delete device %s", dev.DevID))
+		}
+	}
+	return nil
+}
+
+// bulkRequeue puts the downlink messages that were sent to the devices but
+// that were not acknowledged yet back at the front of their queues
+func (h *handler) bulkRequeue(devices []*device.Device) error {
+	for _, dev := range devices {
+		if dev.CurrentDownlink == nil {
+			continue
+		}
+		queue, err := h.devices.DownlinkQueue(dev.AppID, dev.DevID)
+		if err != nil {
+			return err
+		}
+		if err := queue.PushFirst(dev.CurrentDownlink); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Could not requeue downlink of device %s", dev.DevID))
+		}
+		dev.StartUpdate()
+		dev.CurrentDownlink = nil
+		if err := h.devices.Set(dev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestDeviceFilter(t *testing.T) {
+	a := New(t)
+	dev := &device.Device{DevID: "sensor-1", Description: "Sensor in the basement", Options: device.Options{ActivationConstraints: "local"}}
+
+	a.So(DeviceFilter{}.IsEmpty(), ShouldBeTrue)
+	a.So(DeviceFilter{DevIDs: []string{"*"}}.Matches(dev), ShouldBeTrue)
+	a.So(DeviceFilter{DevIDs: []string{"sensor-1"}}.Matches(dev), ShouldBeTrue)
+	a.So(DeviceFilter{DevIDs: []string{"sensor-*"}}.Matches(dev), ShouldBeTrue)
+	a.So(DeviceFilter{DevIDs: []string{"sensor-2", "tracker-*"}}.Matches(dev), ShouldBeFalse)
+	a.So(DeviceFilter{Description: "basement"}.Matches(dev), ShouldBeTrue)
+	a.So(DeviceFilter{Description: "attic"}.Matches(dev), ShouldBeFalse)
+	a.So(DeviceFilter{ActivationConstraints: "local"}.Matches(dev), ShouldBeTrue)
+	a.So(DeviceFilter{DevIDs: []string{"sensor-*"}, ActivationConstraints: "private"}.Matches(dev), ShouldBeFalse)
+}
+
+func TestBulkRequestValidate(t *testing.T) {
+	a := New(t)
+	description := "updated"
+
+	a.So((&BulkRequest{Action: BulkDelete}).validate(), ShouldNotBeNil)
+	a.So((&BulkRequest{Filter: DeviceFilter{DevIDs: []string{"*"}}, Action: "reset"}).validate(), ShouldNotBeNil)
+	a.So((&BulkRequest{Filter: DeviceFilter{DevIDs: []string{"*"}}, Action: BulkSet}).validate(), ShouldNotBeNil)
+	a.So((&BulkRequest{Filter: DeviceFilter{DevIDs: []string{"*"}}, Action: BulkSet, Set: &DeviceUpdate{}}).validate(), ShouldNotBeNil)
+	a.So((&BulkRequest{Filter: DeviceFilter{DevIDs: []string{"*"}}, Action: BulkDelete, Set: &DeviceUpdate{Description: &description}}).validate(), ShouldNotBeNil)
+	a.So((&BulkRequest{Filter: DeviceFilter{DevIDs: []string{"*"}}, Action: BulkSet, Set: &DeviceUpdate{JoinAccept: &types.JoinAcceptSettings{RX1Delay: 16}}}).validate(), ShouldNotBeNil)
+
+	a.So((&BulkRequest{Filter: DeviceFilter{DevIDs: []string{"*"}}, Action: BulkDelete}).validate(), ShouldBeNil)
+	a.So((&BulkRequest{Filter: DeviceFilter{DevIDs: []string{"*"}}, Action: BulkRequeue}).validate(), ShouldBeNil)
+	a.So((&BulkRequest{Filter: DeviceFilter{DevIDs: []string{"*"}}, Action: BulkSet, Set: &DeviceUpdate{
+		Description: &description,
+		JoinAccept:  &types.JoinAcceptSettings{RX2DataRate: "SF9BW125"},
+	}}).validate(), ShouldBeNil)
+}
+
+func TestDeviceUpdateApply(t *testing.T) {
+	a := New(t)
+	description := "updated"
+	disable := true
+	dev := &pb.Device{
+		Description: "original",
+		Latitude:    52,
+		Device:      &pb.Device_LorawanDevice{LorawanDevice: &pb_lorawan.Device{ActivationConstraints: "local", FCntUp: 42}},
+	}
+	update := DeviceUpdate{Description: &description, DisableFCntCheck: &disable, DisableADR: &disable}
+	updated := update.applyTo(dev)
+	a.So(updated.Description, ShouldEqual, "updated")
+	a.So(updated.Latitude, ShouldEqual, 52)
+	a.So(updated.GetLorawanDevice().DisableFCntCheck, ShouldBeTrue)
+	a.So(updated.GetLorawanDevice().DisableAdr, ShouldBeTrue)
+	a.So(updated.GetLorawanDevice().ActivationConstraints, ShouldEqual, "local")
+	a.So(dev.Description, ShouldEqual, "original")
+	a.So(dev.GetLorawanDevice().DisableAdr, ShouldBeFalse)
+
+	// Reverting only sets back the changed fields, the frame counters of the current device are kept
+	current := &pb.Device{
+		Description: "updated",
+		Latitude:    53,
+		Device:      &pb.Device_LorawanDevice{LorawanDevice: &pb_lorawan.Device{DisableFCntCheck: true, DisableAdr: true, FCntUp: 50}},
+	}
+	reverted := update.revert(dev, types.JoinAcceptSettings{}).applyTo(current)
+	a.So(reverted.Description, ShouldEqual, "original")
+	a.So(reverted.Latitude, ShouldEqual, 53)
+	a.So(reverted.GetLorawanDevice().DisableFCntCheck, ShouldBeFalse)
+	a.So(reverted.GetLorawanDevice().DisableAdr, ShouldBeFalse)
+	a.So(reverted.GetLorawanDevice().FCntUp, ShouldEqual, 50)
+
+	a.So(update.revert(dev, types.JoinAcceptSettings{}).JoinAccept, ShouldBeNil)
+	joinAccept := types.JoinAcceptSettings{RX2DataRate: "SF12BW125"}
+	update.JoinAccept = &types.JoinAcceptSettings{RX2DataRate: "SF9BW125"}
+	a.So(*update.revert(dev, joinAccept).JoinAccept, ShouldResemble, joinAccept)
+}
+
+func TestBulkRequeue(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestBulkRequeue")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev-1", CurrentDownlink: &types.DownlinkMessage{FPort: 1}})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev-2"})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "other"})
+
+	devices, err := h.selectDevices("app", DeviceFilter{DevIDs: []string{"dev-*"}})
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 2)
+	a.So(devices[0].DevID, ShouldEqual, "dev-1")
+	a.So(devices[1].DevID, ShouldEqual, "dev-2")
+
+	queue, _ := h.devices.DownlinkQueue("app", "dev-1")
+	queue.PushLast(&types.DownlinkMessage{FPort: 2})
+
+	a.So(h.bulkRequeue(devices), ShouldBeNil)
+
+	dev, _ := h.devices.Get("app", "dev-1")
+	a.So(dev.CurrentDownlink, ShouldBeNil)
+	length, _ := queue.Length()
+	a.So(length, ShouldEqual, 2)
+	next, _ := queue.Next()
+	a.So(next.FPort, ShouldEqual, 1)
+
+	queue, _ = h.devices.DownlinkQueue("app", "dev-2")
+	length, _ = queue.Length()
+	a.So(length, ShouldEqual, 0)
+}
@@ -0,0 +1,217 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ClaimRequest is accepted by the claim endpoint of the HTTP API
+type ClaimRequest struct {
+	DevEUI    types.DevEUI `json:"dev_eui"`
+	ClaimCode string       `json:"claim_code"`
+	// DevID is the ID of the device in the new application. Empty means the
+	// ID of the device in the old application.
+	DevID string `json:"dev_id"`
+}
+
+// ClaimResponse is returned by the claim and claim code endpoints of the HTTP API
+type ClaimResponse struct {
+	AppID     string       `json:"app_id"`
+	DevID     string       `json:"dev_id"`
+	AppEUI    types.AppEUI `json:"app_eui"`
+	DevEUI    types.DevEUI `json:"dev_eui"`
+	ClaimCode string       `json:"claim_code,omitempty"`
+}
+
+// claimCode returns the code that is needed to claim an OTAA device. The code
+// is derived from the DevEUI and the AppKey, so it changes when the AppKey of
+// the device is changed.
+func claimCode(devEUI types.DevEUI, appKey types.AppKey) string {
+	if appKey.IsEmpty() {
+		return ""
+	}
+	mac := hmac.New(sha256.New, appKey.Bytes())
+	mac.Write(devEUI.Bytes())
+	return fmt.Sprintf("%X", mac.Sum(nil)[:8])
+}
+
+// findClaimableDevice returns the device with the DevEUI and claim code from
+// a claimable application other than appID that is shared or that belongs to
+// the same tenant
+func (h *handler) findClaimableDevice(appID string, devEUI types.DevEUI, code string) (*device.Device, error) {
+	devices, err := h.devices.List(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, dev := range devices {
+		if dev.DevEUI != devEUI || dev.AppID == appID || !h.sameTenant(dev.AppID, appID) {
+			continue
+		}
+		app, err := h.applications.Get(dev.AppID)
+		if err != nil || !app.Claimable {
+			continue
+		}
+		expected := claimCode(dev.DevEUI, dev.AppKey)
+		if expected != "" && hmac.Equal([]byte(expected), []byte(strings.ToUpper(code))) {
+			return dev, nil
+		}
+	}
+	// We don't tell the difference between unknown devices and wrong codes
+	return nil, errors.NewErrPermissionDenied("Invalid DevEUI or claim code")
+}
+
+// claimDevice moves a device with its keys and metadata to another
+// application, first in the NetworkServer and then in the Handler. If the
+// device can not be stored in the Handler, the NetworkServer transfer is
+// reverted.
+func (h *handler) claimDevice(dev *device.Device, appID, devID string) (*device.Device, error) {
+	if err := h.transferNetworkServerDevice(dev, appID, devID); err != nil {
+		return nil, errors.Wrap(err, "Could not transfer device in NetworkServer")
+	}
+
+	claimed := *dev
+	claimed.AppID = appID
+	claimed.DevID = devID
+	claimed.CurrentDownlink = nil
+	if err := h.devices.Set(&claimed); err != nil {
+		if err := h.transferNetworkServerDevice(&claimed, dev.AppID, dev.DevID); err != nil {
+			h.Ctx.WithError(err).WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).Error("Could not revert device transfer in NetworkServer")
+		}
+		return nil, err
+	}
+	if err := h.devices.Delete(dev.AppID, dev.DevID); err != nil {
+		h.Ctx.WithError(err).WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).Warn("Could not delete claimed device")
+	}
+
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: dev.AppID,
+		DevID: dev.DevID,
+		Event: types.DeleteEvent,
+	}
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: claimed.AppID,
+		DevID: claimed.DevID,
+		Event: types.CreateEvent,
+	}
+
+	return &claimed, nil
+}
+
+// transferNetworkServerDevice moves a device to another application in the
+// NetworkServer, using the HTTP API of the NetworkServer. The request is
+// authenticated with the token of this Handler, which should handle both
+// applications.
+func (h *handler) transferNetworkServerDevice(dev *device.Device, appID, devID string) error {
+	apiAddress, err := h.networkServerAPIAddress()
+	if err != nil {
+		return err
+	}
+	token, err := h.BuildJWT()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"app_id": appID, "dev_id": devID})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/devices/%s/%s/transfer", apiAddress, dev.AppEUI, dev.DevEUI)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Grpc-Metadata-Id", h.Identity.Id)
+	req.Header.Set("Grpc-Metadata-Service-Name", h.Identity.ServiceName)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	case http.StatusForbidden:
+		return errors.NewErrPermissionDenied("NetworkServer did not transfer device")
+	default:
+		body, _ := ioutil.ReadAll(res.Body)
+		return errors.NewErrInternal(fmt.Sprintf("NetworkServer did not transfer device: %s %s", res.Status, strings.TrimSpace(string(body))))
+	}
+}
+
+func (h *httpHandler) claimCode(req *http.Request, appID, devID string) (*ClaimResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	code := claimCode(dev.DevEUI, dev.AppKey)
+	if code == "" {
+		return nil, errors.NewErrInvalidArgument("Device", "only devices with an AppKey can be claimed")
+	}
+	return &ClaimResponse{AppID: dev.AppID, DevID: dev.DevID, AppEUI: dev.AppEUI, DevEUI: dev.DevEUI, ClaimCode: code}, nil
+}
+
+func (h *httpHandler) claim(req *http.Request, appID string) (*ClaimResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	var in ClaimRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if in.DevEUI.IsEmpty() || in.ClaimCode == "" {
+		return nil, errors.NewErrInvalidArgument("Body", "DevEUI and claim code are required")
+	}
+	if in.DevID != "" && !api.ValidID(in.DevID) {
+		return nil, errors.NewErrInvalidArgument("Dev ID", "invalid format")
+	}
+	if _, err := h.manager.handler.applications.Get(appID); err != nil {
+		return nil, errors.Wrap(err, "Application not registered to this Handler")
+	}
+
+	h.manager.handler.provisionLock.Lock()
+	defer h.manager.handler.provisionLock.Unlock()
+
+	dev, err := h.manager.handler.findClaimableDevice(appID, in.DevEUI, in.ClaimCode)
+	if err != nil {
+		return nil, err
+	}
+	devID := in.DevID
+	if devID == "" {
+		devID = dev.DevID
+	}
+	if _, err := h.manager.handler.devices.Get(appID, devID); err == nil {
+		return nil, errors.NewErrAlreadyExists(fmt.Sprintf("Device %s", devID))
+	} else if errors.GetErrType(err) != errors.NotFound {
+		return nil, err
+	}
+	if err := h.manager.handler.checkDeviceQuota(appID); err != nil {
+		return nil, err
+	}
+
+	claimed, err := h.manager.handler.claimDevice(dev, appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	return &ClaimResponse{AppID: claimed.AppID, DevID: claimed.DevID, AppEUI: claimed.AppEUI, DevEUI: claimed.DevEUI}, nil
+}
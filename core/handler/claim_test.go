@@ -0,0 +1,66 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestClaimCode(t *testing.T) {
+	a := New(t)
+
+	devEUI := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	appKey := types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+
+	code := claimCode(devEUI, appKey)
+	a.So(code, ShouldHaveLength, 16)
+	a.So(claimCode(devEUI, appKey), ShouldEqual, code)
+	a.So(claimCode(types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1}, appKey), ShouldNotEqual, code)
+	a.So(claimCode(devEUI, types.AppKey{8, 7, 6, 5, 4, 3, 2, 1}), ShouldNotEqual, code)
+	a.So(claimCode(devEUI, types.AppKey{}), ShouldBeEmpty)
+}
+
+func TestFindClaimableDevice(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+
+	devEUI := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	appKey := types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	code := claimCode(devEUI, appKey)
+
+	h.applications.Set(&application.Application{AppID: "manufacturer"})
+	h.applications.Set(&application.Application{AppID: "customer"})
+	h.devices.Set(&device.Device{AppID: "manufacturer", DevID: "dev", DevEUI: devEUI, AppKey: appKey})
+
+	// The manufacturer application is not claimable
+	_, err := h.findClaimableDevice("customer", devEUI, code)
+	a.So(err, ShouldNotBeNil)
+
+	app, _ := h.applications.Get("manufacturer")
+	app.StartUpdate()
+	app.Claimable = true
+	h.applications.Set(app)
+
+	dev, err := h.findClaimableDevice("customer", devEUI, strings.ToLower(code))
+	a.So(err, ShouldBeNil)
+	a.So(dev.AppID, ShouldEqual, "manufacturer")
+
+	_, err = h.findClaimableDevice("customer", devEUI, "0000000000000000")
+	a.So(err, ShouldNotBeNil)
+	_, err = h.findClaimableDevice("customer", types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1}, code)
+	a.So(err, ShouldNotBeNil)
+
+	// Devices can not be claimed by their own application
+	_, err = h.findClaimableDevice("manufacturer", devEUI, code)
+	a.So(err, ShouldNotBeNil)
+}
@@ -0,0 +1,567 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ApplicationExport is returned by the export endpoint and accepted by the
+// import endpoint of the HTTP API
+type ApplicationExport struct {
+	AppID    string              `json:"app_id"`
+	Settings ApplicationSettings `json:"settings"`
+	Devices  []DeviceExport      `json:"devices,omitempty"`
+}
+
+// ApplicationSettings are the settings of an application in the Handler
+type ApplicationSettings struct {
+	Decoder             string                         `json:"decoder,omitempty"`
+	Converter           string                         `json:"converter,omitempty"`
+	Validator           string                         `json:"validator,omitempty"`
+	Encoder             string                         `json:"encoder,omitempty"`
+	Converters          []application.PayloadConverter `json:"converters,omitempty"`
+	DataRetention       string                         `json:"data_retention,omitempty"`
+	DeduplicationWindow string                         `json:"deduplication_window,omitempty"`
+	PayloadFormat       string                         `json:"payload_format,omitempty"`
+	TopicPattern        string                         `json:"topic_pattern,omitempty"`
+	TrustedGatewaysOnly bool                           `json:"trusted_gateways_only,omitempty"`
+	MetadataPrivacy     *application.MetadataPrivacy   `json:"metadata_privacy,omitempty"`
+	JoinAccept          types.JoinAcceptSettings       `json:"join_accept"`
+	Claimable           bool                           `json:"claimable,omitempty"`
+	PayloadTests        []application.PayloadTest      `json:"payload_tests,omitempty"`
+	Profiles            []application.DeviceProfile    `json:"profiles,omitempty"`
+	Rules               []application.Rule             `json:"rules,omitempty"`
+	TimeSeries          *application.TimeSeries        `json:"time_series,omitempty"`
+	MQTTBridge          *application.MQTTBridge        `json:"mqtt_bridge,omitempty"`
+}
+
+// DeviceExport is a device in an ApplicationExport
+type DeviceExport struct {
+	DevID                 string                   `json:"dev_id"`
+	Name                  string                   `json:"name,omitempty"`
+	Description           string                   `json:"description,omitempty"`
+	Tags                  []string                 `json:"tags,omitempty"`
+	Profile               string                   `json:"profile,omitempty"`
+	Attributes            map[string]string        `json:"attributes,omitempty"`
+	AppEUI                types.AppEUI             `json:"app_eui"`
+	DevEUI                types.DevEUI             `json:"dev_eui"`
+	AppKey                *types.AppKey            `json:"app_key,omitempty"`
+	DevAddr               *types.DevAddr           `json:"dev_addr,omitempty"`
+	NwkSKey               *types.NwkSKey           `json:"nwk_s_key,omitempty"`
+	AppSKey               *types.AppSKey           `json:"app_s_key,omitempty"`
+	FCntUp                *uint32                  `json:"f_cnt_up,omitempty"`
+	FCntDown              *uint32                  `json:"f_cnt_down,omitempty"`
+	Latitude              float32                  `json:"latitude,omitempty"`
+	Longitude             float32                  `json:"longitude,omitempty"`
+	Altitude              int32                    `json:"altitude,omitempty"`
+	ActivationConstraints string                   `json:"activation_constraints,omitempty"`
+	DisableFCntCheck      bool                     `json:"disable_fcnt_check,omitempty"`
+	Uses32BitFCnt         bool                     `json:"uses_32_bit_fcnt,omitempty"`
+	DisableADR            bool                     `json:"disable_adr,omitempty"`
+	JoinAccept            types.JoinAcceptSettings `json:"join_accept"`
+}
+
+// ImportResponse is returned by the import endpoint of the HTTP API
+type ImportResponse struct {
+	AppID   string   `json:"app_id"`
+	Devices int      `json:"devices"`
+	Created []string `json:"created,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+	Deleted []string `json:"deleted,omitempty"`
+	// Failed are the devices of which the NetworkServer could not be updated
+	// with the settings of their profile
+	Failed []string `json:"failed,omitempty"`
+}
+
+func exportApplication(app *application.Application) ApplicationSettings {
+	settings := ApplicationSettings{
+		Decoder:             app.Decoder,
+		Converter:           app.Converter,
+		Validator:           app.Validator,
+		Encoder:             app.Encoder,
+		Converters:          app.PayloadConverters,
+		PayloadFormat:       app.PayloadFormat,
+		TopicPattern:        app.TopicPattern,
+		TrustedGatewaysOnly: app.TrustedGatewaysOnly,
+		MetadataPrivacy:     app.MetadataPrivacy,
+		JoinAccept:          app.JoinAccept,
+		Claimable:           app.Claimable,
+		PayloadTests:        app.PayloadTests,
+		Profiles:            app.Profiles,
+		Rules:               app.Rules,
+	}
+	if app.DataRetention != 0 {
+		settings.DataRetention = app.DataRetention.String()
+	}
+	if app.DeduplicationWindow != 0 {
+		settings.DeduplicationWindow = app.DeduplicationWindow.String()
+	}
+	// The passwords of the integrations are not exported
+	if app.TimeSeries != nil {
+		ts := *app.TimeSeries
+		ts.URL, ts.Password = redactTimeSeriesURL(ts.URL), ""
+		settings.TimeSeries = &ts
+	}
+	if app.MQTTBridge != nil {
+		bridge := *app.MQTTBridge
+		bridge.Password = ""
+		settings.MQTTBridge = &bridge
+	}
+	return settings
+}
+
+// exportSecrets adds the passwords of the integrations to the settings, for
+// another Handler that imports the application
+func (h *handler) exportSecrets(settings *ApplicationSettings, app *application.Application) error {
+	if app.TimeSeries != nil {
+		ts := *app.TimeSeries
+		settings.TimeSeries = &ts
+	}
+	if app.MQTTBridge != nil {
+		bridge := *app.MQTTBridge
+		password, err := h.openSecret(bridge.Password)
+		if err != nil {
+			return err
+		}
+		bridge.Password = password
+		settings.MQTTBridge = &bridge
+	}
+	return nil
+}
+
+func exportDevice(dev *device.Device) DeviceExport {
+	export := DeviceExport{
+		DevID:                 dev.DevID,
+		Name:                  dev.Name,
+		Description:           dev.Description,
+		Tags:                  dev.Tags,
+		Profile:               dev.Profile,
+		Attributes:            dev.Attributes,
+		AppEUI:                dev.AppEUI,
+		DevEUI:                dev.DevEUI,
+		Latitude:              dev.Latitude,
+		Longitude:             dev.Longitude,
+		Altitude:              dev.Altitude,
+		ActivationConstraints: dev.Options.ActivationConstraints,
+		DisableFCntCheck:      dev.Options.DisableFCntCheck,
+		Uses32BitFCnt:         dev.Options.Uses32BitFCnt,
+		DisableADR:            dev.Options.DisableADR,
+		JoinAccept:            dev.JoinAccept,
+	}
+	if !dev.AppKey.IsEmpty() {
+		appKey := dev.AppKey
+		export.AppKey = &appKey
+	}
+	// The session of OTAA devices is not exported, they join again
+	if export.AppKey == nil && !dev.DevAddr.IsEmpty() {
+		devAddr, nwkSKey, appSKey := dev.DevAddr, dev.NwkSKey, dev.AppSKey
+		export.DevAddr, export.NwkSKey, export.AppSKey = &devAddr, &nwkSKey, &appSKey
+	}
+	return export
+}
+
+// apply validates the settings and applies them to the application. The
+// payload functions must pass the payload tests in the settings.
+func (settings ApplicationSettings) apply(app *application.Application) error {
+	var retention time.Duration
+	if settings.DataRetention != "" {
+		var err error
+		if retention, err = time.ParseDuration(settings.DataRetention); err != nil {
+			return errors.NewErrInvalidArgument("Data Retention", err.Error())
+		}
+		if retention < 0 {
+			return errors.NewErrInvalidArgument("Data Retention", "can not be negative")
+		}
+	}
+	var window time.Duration
+	if settings.DeduplicationWindow != "" {
+		var err error
+		if window, err = parseDeduplicationWindow(settings.DeduplicationWindow); err != nil {
+			return err
+		}
+	}
+	format, err := types.ParsePayloadFormat(settings.PayloadFormat)
+	if err != nil {
+		return errors.NewErrInvalidArgument("Payload Format", err.Error())
+	}
+	if settings.TopicPattern != "" {
+		if _, err := mqtt.ParseTopicPattern(settings.TopicPattern); err != nil {
+			return errors.NewErrInvalidArgument("Topic Pattern", err.Error())
+		}
+	}
+	if settings.MetadataPrivacy != nil {
+		if err := settings.MetadataPrivacy.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateJoinAcceptSettings(settings.JoinAccept, ""); err != nil {
+		return err
+	}
+	if err := validatePayloadTests(settings.PayloadTests); err != nil {
+		return err
+	}
+	if err := validateRules(settings.Rules); err != nil {
+		return err
+	}
+	if err := validatePayloadConverters(settings.Converters); err != nil {
+		return err
+	}
+	if err := validateProfiles(settings.Profiles); err != nil {
+		return err
+	}
+	var timeSeries *application.TimeSeries
+	if settings.TimeSeries != nil {
+		ts := *settings.TimeSeries
+		if old := app.TimeSeries; old != nil {
+			if ts.URL == redactTimeSeriesURL(old.URL) {
+				ts.URL = old.URL
+			}
+			if ts.Password == "" && ts.URL == old.URL && ts.Username == old.Username {
+				ts.Password = old.Password
+			}
+		}
+		if err := validateTimeSeries(&ts); err != nil {
+			return err
+		}
+		timeSeries = &ts
+	}
+	if settings.MQTTBridge != nil {
+		if err := validateMQTTBridge(settings.MQTTBridge); err != nil {
+			return err
+		}
+	}
+	app.Decoder = settings.Decoder
+	app.Converter = settings.Converter
+	app.Validator = settings.Validator
+	app.Encoder = settings.Encoder
+	app.PayloadConverters = settings.Converters
+	app.DataRetention = retention
+	app.DeduplicationWindow = window
+	app.PayloadFormat = string(format)
+	app.TopicPattern = settings.TopicPattern
+	app.TrustedGatewaysOnly = settings.TrustedGatewaysOnly
+	app.MetadataPrivacy = settings.MetadataPrivacy
+	app.JoinAccept = settings.JoinAccept
+	app.Claimable = settings.Claimable
+	app.PayloadTests = settings.PayloadTests
+	app.Profiles = settings.Profiles
+	app.Rules = settings.Rules
+	app.TimeSeries = timeSeries
+	return checkPayloadTests(app)
+}
+
+// applyIntegrations checks the hosts of the integrations and applies the MQTT
+// bridge of the settings to the application, after the other settings are
+// applied. If the settings have no password and the broker and username are
+// not changed, the current password is kept. It returns whether the bridge
+// changed, so that it is reconnected.
+func (h *handler) applyIntegrations(settings ApplicationSettings, app *application.Application) (bridgeChanged bool, err error) {
+	if app.TimeSeries != nil {
+		if err := h.integrationHosts.checkURL(app.TimeSeries.URL); err != nil {
+			return false, err
+		}
+	}
+	old, bridge := app.MQTTBridge, settings.MQTTBridge
+	if bridge == nil {
+		app.MQTTBridge = nil
+		return old != nil, nil
+	}
+	in := *bridge
+	if err := h.integrationHosts.checkURL(in.URL); err != nil {
+		return false, err
+	}
+	if old != nil && in.Password == "" && in.URL == old.URL && in.Username == old.Username {
+		in.Password = old.Password
+	} else if in.Password, err = h.sealSecret(in.Password); err != nil {
+		return false, err
+	}
+	app.MQTTBridge = &in
+	return old == nil || *old != in, nil
+}
+
+// checkImportDevices checks that the devices can be imported into the
+// application. The NetworkServer identifies devices by their AppEUI and
+// DevEUI, so devices that are already registered to this Handler can not be
+// imported into another application. Devices that are registered to another
+// Handler of the same NetworkServer are checked in the NetworkServer.
+func (h *handler) checkImportDevices(appID string, devices []DeviceExport) error {
+	existing, err := h.devices.List(nil)
+	if err != nil {
+		return err
+	}
+	type euis struct {
+		appEUI types.AppEUI
+		devEUI types.DevEUI
+	}
+	registered := make(map[euis]string, len(existing))
+	for _, dev := range existing {
+		registered[euis{dev.AppEUI, dev.DevEUI}] = dev.AppID
+	}
+	ids := make(map[string]bool, len(devices))
+	for _, dev := range devices {
+		if !api.ValidID(dev.DevID) {
+			return errors.NewErrInvalidArgument("Dev ID", fmt.Sprintf("%s has an invalid format", dev.DevID))
+		}
+		if ids[dev.DevID] {
+			return errors.NewErrInvalidArgument("Dev ID", fmt.Sprintf("%s is not unique", dev.DevID))
+		}
+		ids[dev.DevID] = true
+		if otherAppID, ok := registered[euis{dev.AppEUI, dev.DevEUI}]; ok && otherAppID != appID {
+			return errors.NewErrAlreadyExists(fmt.Sprintf("Device %s with AppEUI %s and DevEUI %s", dev.DevID, dev.AppEUI, dev.DevEUI))
+		}
+		if err := validateJoinAcceptSettings(dev.JoinAccept, ""); err != nil {
+			return err
+		}
+		if err := validateDeviceAttributes(dev.Attributes); err != nil {
+			return err
+		}
+		if len(dev.Name) > MaxDeviceNameLength {
+			return errors.NewErrInvalidArgument(fmt.Sprintf("Device %s name", dev.DevID), fmt.Sprintf("can not be longer than %d characters", MaxDeviceNameLength))
+		}
+		if _, err := normalizeDeviceTags(dev.Tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *httpHandler) exportApplication(req *http.Request, appID string) (*ApplicationExport, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	export := &ApplicationExport{AppID: app.AppID, Settings: exportApplication(app)}
+	if req.URL.Query().Get("devices") != "true" {
+		return export, nil
+	}
+	ctx, err := h.authorize(req, appID, rights.Devices)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := h.manager.handler.devices.ListForApp(appID, nil)
+	if err != nil {
+		return nil, err
+	}
+	redact := req.URL.Query().Get("keys") == "false"
+	counters := req.URL.Query().Get("counters") == "true"
+	migration := req.URL.Query().Get("migration") == "true"
+	export.Devices = make([]DeviceExport, 0, len(devices))
+	for _, dev := range devices {
+		deviceExport := exportDevice(dev)
+		if migration {
+			deviceExport = exportMigrationDevice(dev)
+		}
+		if redact {
+			deviceExport.redactKeys()
+		}
+		if counters && deviceExport.NwkSKey != nil {
+			// The session of ABP devices continues in another network
+			nsDev, err := h.manager.deviceManager.GetDevice(ctx, &pb_lorawan.DeviceIdentifier{AppEui: &dev.AppEUI, DevEui: &dev.DevEUI})
+			if err != nil {
+				return nil, errors.Wrap(errors.FromGRPCError(err), fmt.Sprintf("Could not get frame counters of device %s", dev.DevID))
+			}
+			fCntUp, fCntDown := nsDev.FCntUp, nsDev.FCntDown
+			deviceExport.FCntUp, deviceExport.FCntDown = &fCntUp, &fCntDown
+		}
+		export.Devices = append(export.Devices, deviceExport)
+	}
+	return export, nil
+}
+
+// redactKeys removes the keys of the device from the export
+func (export *DeviceExport) redactKeys() {
+	export.AppKey, export.NwkSKey, export.AppSKey = nil, nil, nil
+}
+
+// importApplication replaces the settings of the application by those in
+// the export and registers the devices in the export. Devices that already
+// exist in the application are updated, keeping the keys that are not in the
+// export, and their frame counters unless the export contains frame counters.
+// The NetworkServer settings of device profiles are set for the devices of
+// which the profile changed. With ?prune=true, devices of the application
+// that are not in the export are deleted. With ?migration=true,
+// the application is created if it is not registered to this Handler.
+func (h *httpHandler) importApplication(req *http.Request, appID string) (*ImportResponse, error) {
+	ctx, err := h.authorize(req, appID, rights.AppSettings)
+	if err != nil {
+		return nil, err
+	}
+	var in ApplicationExport
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	prune := req.URL.Query().Get("prune") == "true"
+	if len(in.Devices) > 0 || prune {
+		if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+			return nil, err
+		}
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil && errors.GetErrType(err) == errors.NotFound && req.URL.Query().Get("migration") == "true" {
+		// The application is migrated from another Handler, which keeps
+		// announcing it until the cutover
+		app, err = &application.Application{AppID: appID}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Application not registered to this Handler")
+	}
+
+	h.manager.handler.provisionLock.Lock()
+	defer h.manager.handler.provisionLock.Unlock()
+
+	// Validate everything before changing anything
+	old := application.Application{Profiles: app.Profiles}
+	app.StartUpdate()
+	if err := in.Settings.apply(app); err != nil {
+		return nil, err
+	}
+	bridgeChanged, err := h.manager.handler.applyIntegrations(in.Settings, app)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.checkImportDevices(appID, in.Devices); err != nil {
+		return nil, err
+	}
+	for _, export := range in.Devices {
+		if _, ok := app.Profile(export.Profile); export.Profile != "" && !ok {
+			return nil, errors.NewErrNotFound(fmt.Sprintf("Profile %s of device %s", export.Profile, export.DevID))
+		}
+	}
+	existing, err := h.manager.handler.devices.ListForApp(appID, nil)
+	if err != nil {
+		return nil, err
+	}
+	existingIDs := make(map[string]bool, len(existing))
+	oldProfiles := make(map[string]application.DeviceProfile, len(existing))
+	for _, dev := range existing {
+		existingIDs[dev.DevID] = true
+		oldProfiles[dev.DevID], _ = h.manager.handler.deviceProfile(&old, dev)
+	}
+	nsDevices := make(map[string]*pb_lorawan.Device, len(in.Devices))
+	for _, export := range in.Devices {
+		export := export
+		if !existingIDs[export.DevID] && export.AppKey == nil && export.NwkSKey == nil {
+			return nil, errors.NewErrInvalidArgument("Device", fmt.Sprintf("%s is not registered and needs an AppKey or session keys", export.DevID))
+		}
+		nsDev, err := h.manager.deviceManager.GetDevice(ctx, &pb_lorawan.DeviceIdentifier{AppEui: &export.AppEUI, DevEui: &export.DevEUI})
+		if err == nil && nsDev.AppId != appID {
+			return nil, errors.NewErrAlreadyExists(fmt.Sprintf("Device %s with AppEUI %s and DevEUI %s", export.DevID, export.AppEUI, export.DevEUI))
+		}
+		if err != nil && errors.GetErrType(errors.FromGRPCError(err)) != errors.NotFound {
+			return nil, errors.Wrap(errors.FromGRPCError(err), fmt.Sprintf("Could not check device %s", export.DevID))
+		}
+		if err == nil {
+			nsDevices[export.DevID] = nsDev
+		}
+	}
+
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.recordFunctions(app, h.functionsAuthor(ctx)); err != nil {
+		h.manager.handler.Ctx.WithField("AppID", appID).WithError(err).Warn("Could not record version of payload functions")
+	}
+	if bridgeChanged {
+		if err := h.manager.handler.startMQTTBridge(appID, app.MQTTBridge); err != nil {
+			h.manager.handler.Ctx.WithField("AppID", appID).WithError(err).Warn("Could not start MQTT bridge")
+		}
+	}
+	response := &ImportResponse{AppID: appID}
+	imported := make(map[string]bool, len(in.Devices))
+	for _, export := range in.Devices {
+		export := export
+		dev := &pb_lorawan.Device{
+			AppId:                 appID,
+			DevId:                 export.DevID,
+			AppEui:                &export.AppEUI,
+			DevEui:                &export.DevEUI,
+			AppKey:                export.AppKey,
+			DevAddr:               export.DevAddr,
+			NwkSKey:               export.NwkSKey,
+			AppSKey:               export.AppSKey,
+			ActivationConstraints: export.ActivationConstraints,
+			DisableFCntCheck:      export.DisableFCntCheck,
+			Uses32BitFCnt:         export.Uses32BitFCnt,
+			DisableAdr:            export.DisableADR,
+		}
+		if nsDev, ok := nsDevices[export.DevID]; ok {
+			dev.FCntUp, dev.FCntDown = nsDev.FCntUp, nsDev.FCntDown
+		}
+		if export.FCntUp != nil {
+			dev.FCntUp = *export.FCntUp
+		}
+		if export.FCntDown != nil {
+			dev.FCntDown = *export.FCntDown
+		}
+		_, err := h.manager.SetDevice(ctx, &pb.Device{
+			AppId:       appID,
+			DevId:       export.DevID,
+			Description: export.Description,
+			Latitude:    export.Latitude,
+			Longitude:   export.Longitude,
+			Altitude:    export.Altitude,
+			Device:      &pb.Device_LorawanDevice{LorawanDevice: dev},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("Could not import device %s", export.DevID))
+		}
+		stored, err := h.manager.handler.devices.Get(appID, export.DevID)
+		if err != nil {
+			return nil, err
+		}
+		tags, _ := normalizeDeviceTags(export.Tags) // validated before
+		if stored.JoinAccept != export.JoinAccept || stored.Profile != export.Profile || !reflect.DeepEqual(stored.Attributes, export.Attributes) ||
+			stored.Name != export.Name || !reflect.DeepEqual(stored.Tags, tags) {
+			stored.StartUpdate()
+			stored.JoinAccept = export.JoinAccept
+			stored.Profile = export.Profile
+			stored.Attributes = export.Attributes
+			stored.Name = export.Name
+			stored.Tags = tags
+			if err := h.manager.handler.devices.Set(stored); err != nil {
+				return nil, err
+			}
+		}
+		profile, _ := h.manager.handler.deviceProfile(app, stored)
+		response.Failed = append(response.Failed, h.manager.handler.syncProfile(ctx, []*device.Device{stored}, oldProfiles[export.DevID], profile)...)
+		imported[export.DevID] = true
+		if existingIDs[export.DevID] {
+			response.Updated = append(response.Updated, export.DevID)
+		} else {
+			response.Created = append(response.Created, export.DevID)
+		}
+		response.Devices++
+	}
+	if !prune {
+		return response, nil
+	}
+	for _, dev := range existing {
+		if imported[dev.DevID] {
+			continue
+		}
+		if _, err := h.manager.DeleteDevice(ctx, &pb.DeviceIdentifier{AppId: appID, DevId: dev.DevID}); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("Could not delete device %s", dev.DevID))
+		}
+		response.Deleted = append(response.Deleted, dev.DevID)
+	}
+	return response, nil
+}
@@ -0,0 +1,106 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestExportApplicationSettings(t *testing.T) {
+	a := New(t)
+
+	staging := &application.Application{
+		AppID:         "staging",
+		Decoder:       "function Decoder(bytes) { return {}; }",
+		DataRetention: 24 * time.Hour,
+		PayloadFormat: "cbor",
+		TopicPattern:  "{dev_id}/data",
+		JoinAccept:    types.JoinAcceptSettings{RX1Delay: 5},
+		TimeSeries:    &application.TimeSeries{Type: application.TimeSeriesInfluxDB, URL: "http://influxdb:8086", Database: "ttn", Username: "ttn", Password: "secret"},
+		MQTTBridge:    &application.MQTTBridge{URL: "tcp://broker:1883", Username: "ttn", Password: "secret"},
+	}
+	settings := exportApplication(staging)
+	a.So(settings.DataRetention, ShouldEqual, "24h0m0s")
+	a.So(settings.TimeSeries.Database, ShouldEqual, "ttn")
+	a.So(settings.TimeSeries.Password, ShouldBeEmpty)
+	a.So(settings.MQTTBridge.URL, ShouldEqual, "tcp://broker:1883")
+	a.So(settings.MQTTBridge.Password, ShouldBeEmpty)
+	a.So(staging.TimeSeries.Password, ShouldEqual, "secret")
+
+	// The password of an unchanged time series database is kept
+	a.So(settings.apply(staging), ShouldBeNil)
+	a.So(staging.TimeSeries.Password, ShouldEqual, "secret")
+
+	production := &application.Application{AppID: "production", Encoder: "function Encoder() {}"}
+	a.So(settings.apply(production), ShouldBeNil)
+	a.So(production.AppID, ShouldEqual, "production")
+	a.So(production.Decoder, ShouldEqual, staging.Decoder)
+	a.So(production.Encoder, ShouldBeEmpty)
+	a.So(production.DataRetention, ShouldEqual, 24*time.Hour)
+	a.So(production.PayloadFormat, ShouldEqual, "cbor")
+	a.So(production.TopicPattern, ShouldEqual, "{dev_id}/data")
+	a.So(production.JoinAccept, ShouldResemble, staging.JoinAccept)
+	a.So(production.TimeSeries.URL, ShouldEqual, staging.TimeSeries.URL)
+	a.So(production.TimeSeries.Password, ShouldBeEmpty)
+
+	a.So(ApplicationSettings{DataRetention: "-1h"}.apply(production), ShouldNotBeNil)
+	a.So(ApplicationSettings{PayloadFormat: "xml"}.apply(production), ShouldNotBeNil)
+	a.So(ApplicationSettings{JoinAccept: types.JoinAcceptSettings{RX1Delay: 16}}.apply(production), ShouldNotBeNil)
+	a.So(ApplicationSettings{TimeSeries: &application.TimeSeries{Type: "mysql"}}.apply(production), ShouldNotBeNil)
+}
+
+func TestExportDevice(t *testing.T) {
+	a := New(t)
+
+	otaa := exportDevice(&device.Device{
+		DevID:   "otaa",
+		AppKey:  types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8},
+		DevAddr: types.DevAddr{1, 2, 3, 4},
+		NwkSKey: types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8},
+	})
+	a.So(otaa.AppKey, ShouldNotBeNil)
+	a.So(otaa.DevAddr, ShouldBeNil)
+	a.So(otaa.NwkSKey, ShouldBeNil)
+
+	abp := exportDevice(&device.Device{
+		DevID:   "abp",
+		DevAddr: types.DevAddr{1, 2, 3, 4},
+		NwkSKey: types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8},
+		Options: device.Options{DisableFCntCheck: true},
+	})
+	a.So(abp.AppKey, ShouldBeNil)
+	a.So(*abp.DevAddr, ShouldEqual, types.DevAddr{1, 2, 3, 4})
+	a.So(abp.DisableFCntCheck, ShouldBeTrue)
+
+	otaa.redactKeys()
+	a.So(otaa.AppKey, ShouldBeNil)
+	abp.redactKeys()
+	a.So(abp.NwkSKey, ShouldBeNil)
+	a.So(abp.AppSKey, ShouldBeNil)
+	a.So(*abp.DevAddr, ShouldEqual, types.DevAddr{1, 2, 3, 4})
+}
+
+func TestCheckImportDevices(t *testing.T) {
+	a := New(t)
+	h := &handler{devices: device.NewMemoryDeviceStore()}
+
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	devEUI := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	h.devices.Set(&device.Device{AppID: "staging", DevID: "dev", AppEUI: appEUI, DevEUI: devEUI})
+
+	a.So(h.checkImportDevices("production", []DeviceExport{{DevID: "other", AppEUI: appEUI, DevEUI: types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1}}}), ShouldBeNil)
+
+	// The device is already registered in another application
+	a.So(h.checkImportDevices("production", []DeviceExport{{DevID: "dev", AppEUI: appEUI, DevEUI: devEUI}}), ShouldNotBeNil)
+	a.So(h.checkImportDevices("staging", []DeviceExport{{DevID: "dev", AppEUI: appEUI, DevEUI: devEUI}}), ShouldBeNil)
+
+	a.So(h.checkImportDevices("production", []DeviceExport{{DevID: "Invalid"}}), ShouldNotBeNil)
+	a.So(h.checkImportDevices("production", []DeviceExport{{DevID: "dup"}, {DevID: "dup"}}), ShouldNotBeNil)
+}
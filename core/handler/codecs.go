@@ -0,0 +1,135 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// CodecAttribute is the device attribute that selects a codec of the codec
+// repository, such as acme/tracker for the file acme/tracker.js
+const CodecAttribute = "codec"
+
+// codecResult checks the result of a LoRaWAN payload codec (TS013), logs its
+// warnings and throws its errors
+const codecResult = `
+	function __codecResult(result) {
+		if (typeof result !== "object" || result === null) {
+			throw new Error("codec does not return an object");
+		}
+		if (result.warnings) {
+			for (var i = 0; i < result.warnings.length; i++) {
+				console.log("warning: " + result.warnings[i]);
+			}
+		}
+		if (result.errors && result.errors.length > 0) {
+			throw new Error(result.errors.join(", "));
+		}
+	}
+`
+
+// codecDecodeUplink calls the decodeUplink function of a codec and returns
+// its data like the result of a Decoder function
+const codecDecodeUplink = codecResult + `
+	function __decodeUplink(bytes, fPort) {
+		var result = decodeUplink({ bytes: bytes, fPort: fPort, recvTime: new Date() });
+		__codecResult(result);
+		return result.data;
+	}
+`
+
+// codecEncodeDownlink calls the encodeDownlink function of a codec and
+// returns its bytes like the result of an Encoder function
+const codecEncodeDownlink = codecResult + `
+	function __encodeDownlink(data, fPort) {
+		var result = encodeDownlink({ data: data, fPort: fPort });
+		__codecResult(result);
+		return result.bytes;
+	}
+`
+
+type codecFile struct {
+	modTime time.Time
+	code    string
+}
+
+// codecRepository loads LoRaWAN payload codecs from the JavaScript files in a
+// directory, such as a checkout of a device repository of vendors
+type codecRepository struct {
+	dir string
+
+	mu     sync.Mutex
+	codecs map[string]*codecFile
+}
+
+func (h *handler) WithCodecRepository(dir string) Handler {
+	h.codecs = &codecRepository{
+		dir:    dir,
+		codecs: make(map[string]*codecFile),
+	}
+	return h
+}
+
+// get returns the code of the codec. Codecs are read again when their file
+// changes.
+func (r *codecRepository) get(id string) (string, error) {
+	for _, part := range strings.Split(id, "/") {
+		if !api.ValidID(part) {
+			return "", errors.NewErrInvalidArgument("Codec", fmt.Sprintf("%s has an invalid format", id))
+		}
+	}
+	filename := filepath.Join(r.dir, filepath.FromSlash(id)+".js")
+	info, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return "", errors.NewErrNotFound(fmt.Sprintf("Codec %s", id))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if codec, ok := r.codecs[id]; ok && codec.modTime.Equal(info.ModTime()) {
+		return codec.code, nil
+	}
+	code, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	r.codecs[id] = &codecFile{modTime: info.ModTime(), code: string(code)}
+	return string(code), nil
+}
+
+// payloadConverter returns the payload functions for messages of the device
+// on the port: the codec of the device or of its profile in the codec
+// repository, or the payload converter of the application. The device may be nil.
+func (h *handler) payloadConverter(app *application.Application, dev *device.Device, port uint8) (application.PayloadConverter, error) {
+	var attributes map[string]string
+	if dev != nil {
+		attributes = dev.Attributes
+	}
+	id := attributes[CodecAttribute]
+	if profile, ok := h.deviceProfile(app, dev); ok && id == "" {
+		id = profile.Codec
+	}
+	if id != "" && h.codecs != nil {
+		code, err := h.codecs.get(id)
+		if err != nil {
+			return application.PayloadConverter{}, err
+		}
+		return application.PayloadConverter{ID: id, Decoder: code, Encoder: code}, nil
+	}
+	return app.PayloadConverterFor(port, attributes), nil
+}
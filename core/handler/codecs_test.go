@@ -0,0 +1,118 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/smartystreets/assertions"
+)
+
+const testCodec = `
+function decodeUplink(input) {
+	if (input.bytes.length < 2) {
+		return { errors: ["payload too short"] };
+	}
+	var warnings = [];
+	if (input.fPort !== 1) {
+		warnings.push("unexpected port");
+	}
+	return {
+		data: { temperature: ((input.bytes[0] << 8) | input.bytes[1]) / 100 },
+		warnings: warnings
+	};
+}
+
+function encodeDownlink(input) {
+	return {
+		bytes: [input.data.led ? 1 : 0],
+		fPort: input.fPort
+	};
+}
+`
+
+func TestCodecFunctions(t *testing.T) {
+	a := New(t)
+
+	up := &UplinkFunctions{Decoder: testCodec}
+	fields, valid, err := up.Process([]byte{0x08, 0x70}, 1)
+	a.So(err, ShouldBeNil)
+	a.So(valid, ShouldBeTrue)
+	a.So(fields["temperature"], ShouldEqual, 21.6)
+
+	_, _, err = up.Process([]byte{0x08}, 1)
+	a.So(err, ShouldNotBeNil)
+	a.So(err.Error(), ShouldContainSubstring, "payload too short")
+
+	down := &DownlinkFunctions{Encoder: testCodec}
+	payload, _, err := down.Process(map[string]interface{}{"led": true}, 1)
+	a.So(err, ShouldBeNil)
+	a.So(payload, ShouldResemble, []byte{1})
+
+	// Payload functions without a codec still need a Decoder function
+	up = &UplinkFunctions{Decoder: `function decode(bytes) { return {}; }`}
+	_, _, err = up.Process([]byte{0x08, 0x70}, 1)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestCodecRepository(t *testing.T) {
+	a := New(t)
+
+	dir, err := ioutil.TempDir("", "ttn-codecs")
+	a.So(err, ShouldBeNil)
+	defer os.RemoveAll(dir)
+	a.So(os.Mkdir(filepath.Join(dir, "acme"), 0755), ShouldBeNil)
+	filename := filepath.Join(dir, "acme", "thermometer.js")
+	a.So(ioutil.WriteFile(filename, []byte(testCodec), 0644), ShouldBeNil)
+
+	h := &handler{}
+	h.WithCodecRepository(dir)
+
+	code, err := h.codecs.get("acme/thermometer")
+	a.So(err, ShouldBeNil)
+	a.So(code, ShouldEqual, testCodec)
+
+	_, err = h.codecs.get("acme/unknown")
+	a.So(errors.GetErrType(err), ShouldEqual, errors.NotFound)
+	_, err = h.codecs.get("../thermometer")
+	a.So(errors.GetErrType(err), ShouldEqual, errors.InvalidArgument)
+
+	// Changed codecs are loaded again
+	a.So(ioutil.WriteFile(filename, []byte("// changed"), 0644), ShouldBeNil)
+	later := time.Now().Add(time.Minute)
+	a.So(os.Chtimes(filename, later, later), ShouldBeNil)
+	code, err = h.codecs.get("acme/thermometer")
+	a.So(err, ShouldBeNil)
+	a.So(code, ShouldEqual, "// changed")
+
+	// The codec of the device has precedence over the converters
+	app := &application.Application{
+		Decoder: `function Decoder(bytes, port) { return {}; }`,
+		PayloadConverters: []application.PayloadConverter{
+			{ID: "acme", Attribute: "vendor", Value: "acme", Decoder: `function Decoder(bytes, port) { return {}; }`},
+		},
+	}
+	dev := &device.Device{Attributes: map[string]string{CodecAttribute: "acme/thermometer", "vendor": "acme"}}
+	converter, err := h.payloadConverter(app, dev, 1)
+	a.So(err, ShouldBeNil)
+	a.So(converter.ID, ShouldEqual, "acme/thermometer")
+	a.So(converter.Decoder, ShouldEqual, "// changed")
+	a.So(converter.Encoder, ShouldEqual, "// changed")
+
+	dev.Attributes[CodecAttribute] = "acme/unknown"
+	_, err = h.payloadConverter(app, dev, 1)
+	a.So(err, ShouldNotBeNil)
+
+	// Without a codec repository the attribute is ignored
+	converter, err = (&handler{}).payloadConverter(app, dev, 1)
+	a.So(err, ShouldBeNil)
+	a.So(converter.ID, ShouldEqual, "acme")
+}
@@ -0,0 +1,190 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+var (
+	// ConflictWindow is the window in which conflict evidence is counted
+	ConflictWindow = time.Hour
+	// ConflictThreshold is the number of frame counter regressions and repeated
+	// activations in the window after which a device is flagged
+	ConflictThreshold = 3
+	// ActivationRetryInterval is the interval in which a device can activate again
+	// without counting as conflict evidence, because the join-accept got lost
+	ActivationRetryInterval = time.Minute
+)
+
+// DeviceConflict is the conflict of a device that is returned by the HTTP API
+type DeviceConflict struct {
+	DevID string `json:"dev_id"`
+	*device.Conflict
+}
+
+// ConflictsResponse is returned by the conflicts endpoint of the HTTP API
+type ConflictsResponse struct {
+	AppID   string           `json:"app_id"`
+	Devices []DeviceConflict `json:"devices"`
+}
+
+// addConflictEvidence counts evidence that multiple physical devices use the
+// credentials of the device, and flags the device with a conflict when the
+// threshold is reached. It returns true if the device was flagged.
+func addConflictEvidence(dev *device.Device, reason string, now time.Time) bool {
+	if dev.Conflict != nil {
+		return false
+	}
+	if now.Sub(dev.ConflictEvidenceAt) > ConflictWindow {
+		dev.ConflictEvidence = 0
+		dev.ConflictEvidenceAt = now
+	}
+	dev.ConflictEvidence++
+	if dev.ConflictEvidence < ConflictThreshold {
+		return false
+	}
+	dev.Conflict = &device.Conflict{Reason: reason, DetectedAt: now}
+	return true
+}
+
+// checkFCntRegression counts a frame counter that is lower than the last one
+// as conflict evidence: devices with copied ABP credentials interleave their
+// frame counters. It returns true if the device was flagged.
+func checkFCntRegression(dev *device.Device, fCnt uint32, now time.Time) bool {
+	if fCnt >= dev.FCntUp {
+		return false
+	}
+	return addConflictEvidence(dev, device.ConflictFCntRegressions, now)
+}
+
+// handleFCntRegression counts a frame counter regression that the Broker
+// dropped as conflict evidence
+func (h *handler) handleFCntRegression(uplink *pb_broker.DeduplicatedUplinkMessage) error {
+	dev, err := h.devices.Get(uplink.AppId, uplink.DevId)
+	if err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	flagged := addConflictEvidence(dev, device.ConflictFCntRegressions, time.Now())
+	if err := h.devices.Set(dev); err != nil {
+		return err
+	}
+	if flagged {
+		h.publishConflict(dev)
+	}
+	return nil
+}
+
+// checkActivation counts an activation shortly after a previous activation as
+// conflict evidence: devices with copied OTAA credentials take over each
+// other's session. It returns true if the device was flagged.
+func checkActivation(dev *device.Device, now time.Time) bool {
+	if dev.ActivatedAt.IsZero() {
+		return false
+	}
+	since := now.Sub(dev.ActivatedAt)
+	if since < ActivationRetryInterval || since > ConflictWindow {
+		return false
+	}
+	return addConflictEvidence(dev, device.ConflictActivations, now)
+}
+
+// flagSharedSession flags the device and the other registered devices that use
+// the same DevAddr and NwkSKey, which happens when ABP credentials are copied
+func (h *handler) flagSharedSession(dev *device.Device) error {
+	if dev.DevAddr.IsEmpty() || dev.NwkSKey.IsEmpty() {
+		return nil
+	}
+	devices, err := h.devices.ListForAddress(dev.DevAddr)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, other := range devices {
+		if other == nil || (other.AppID == dev.AppID && other.DevID == dev.DevID) {
+			continue
+		}
+		if other.DevAddr != dev.DevAddr || other.NwkSKey != dev.NwkSKey {
+			continue
+		}
+		other.StartUpdate()
+		if other.Conflict == nil {
+			other.Conflict = &device.Conflict{Reason: device.ConflictSharedSession, DetectedAt: now}
+		}
+		other.Conflict.AddDevice(dev.AppID, dev.DevID)
+		if err := h.devices.Set(other); err != nil {
+			return err
+		}
+		h.publishConflict(other)
+
+		if dev.Conflict == nil {
+			dev.Conflict = &device.Conflict{Reason: device.ConflictSharedSession, DetectedAt: now}
+		}
+		dev.Conflict.AddDevice(other.AppID, other.DevID)
+	}
+	return nil
+}
+
+// publishConflict publishes a conflict event for the device and logs a warning
+func (h *handler) publishConflict(dev *device.Device) {
+	h.Ctx.WithFields(ttnlog.Fields{
+		"AppID":    dev.AppID,
+		"DevID":    dev.DevID,
+		"Reason":   dev.Conflict.Reason,
+		"Devices":  dev.Conflict.Devices,
+		"Evidence": dev.ConflictEvidence,
+	}).Warn("Detected device with conflicting credentials")
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: dev.AppID,
+		DevID: dev.DevID,
+		Event: types.DeviceConflictEvent,
+		Data: types.DeviceConflictEventData{
+			Reason:  dev.Conflict.Reason,
+			Devices: dev.Conflict.Devices,
+		},
+	}
+}
+
+func (h *httpHandler) getConflicts(req *http.Request, appID string) (*ConflictsResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	devices, err := h.manager.handler.devices.ListForApp(appID, nil)
+	if err != nil {
+		return nil, err
+	}
+	response := &ConflictsResponse{AppID: appID, Devices: []DeviceConflict{}}
+	for _, dev := range devices {
+		if dev == nil || dev.Conflict == nil {
+			continue
+		}
+		response.Devices = append(response.Devices, DeviceConflict{DevID: dev.DevID, Conflict: dev.Conflict})
+	}
+	return response, nil
+}
+
+// clearConflict removes the conflict of a device, after the operator replaced
+// the credentials of the other devices
+func (h *httpHandler) clearConflict(req *http.Request, appID, devID string) error {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	dev.Conflict = nil
+	dev.ConflictEvidence = 0
+	dev.ConflictEvidenceAt = time.Time{}
+	return h.manager.handler.devices.Set(dev)
+}
@@ -0,0 +1,134 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestCheckFCntRegression(t *testing.T) {
+	a := New(t)
+
+	now := time.Now()
+	dev := &device.Device{FCntUp: 100}
+	a.So(checkFCntRegression(dev, 101, now), ShouldBeFalse)
+	a.So(dev.ConflictEvidence, ShouldEqual, 0)
+
+	// Interleaved frame counters of two devices
+	a.So(checkFCntRegression(dev, 5, now), ShouldBeFalse)
+	a.So(checkFCntRegression(dev, 6, now.Add(time.Minute)), ShouldBeFalse)
+	a.So(checkFCntRegression(dev, 7, now.Add(2*time.Minute)), ShouldBeTrue)
+	a.So(dev.Conflict, ShouldNotBeNil)
+	a.So(dev.Conflict.Reason, ShouldEqual, device.ConflictFCntRegressions)
+
+	// A flagged device is not flagged again
+	a.So(checkFCntRegression(dev, 8, now.Add(3*time.Minute)), ShouldBeFalse)
+
+	// Evidence outside the window is forgotten
+	dev = &device.Device{FCntUp: 100}
+	a.So(checkFCntRegression(dev, 5, now), ShouldBeFalse)
+	a.So(checkFCntRegression(dev, 6, now.Add(time.Minute)), ShouldBeFalse)
+	a.So(checkFCntRegression(dev, 7, now.Add(2*time.Hour)), ShouldBeFalse)
+	a.So(dev.ConflictEvidence, ShouldEqual, 1)
+	a.So(dev.Conflict, ShouldBeNil)
+}
+
+func TestHandleFCntRegression(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleFCntRegression")},
+		devices:   device.NewMemoryDeviceStore(),
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev", FCntUp: 100})
+
+	uplink := &pb_broker.DeduplicatedUplinkMessage{
+		AppId:          "app",
+		DevId:          "dev",
+		FcntRegression: &pb_broker.FCntRegression{FCnt: 5, LastFCnt: 100},
+	}
+	for i := 1; i < ConflictThreshold; i++ {
+		a.So(h.handleFCntRegression(uplink), ShouldBeNil)
+	}
+	a.So(h.mqttEvent, ShouldHaveLength, 0)
+	a.So(h.handleFCntRegression(uplink), ShouldBeNil)
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+
+	dev, _ := h.devices.Get("app", "dev")
+	a.So(dev.Conflict, ShouldNotBeNil)
+	a.So(dev.Conflict.Reason, ShouldEqual, device.ConflictFCntRegressions)
+	a.So(dev.FCntUp, ShouldEqual, 100)
+}
+
+func TestCheckActivation(t *testing.T) {
+	a := New(t)
+
+	now := time.Now()
+	dev := &device.Device{}
+	a.So(checkActivation(dev, now), ShouldBeFalse)
+
+	// Retries after a lost join-accept are not counted
+	dev.ActivatedAt = now
+	a.So(checkActivation(dev, now.Add(10*time.Second)), ShouldBeFalse)
+	a.So(dev.ConflictEvidence, ShouldEqual, 0)
+
+	// Activations long after the previous one are not counted
+	a.So(checkActivation(dev, now.Add(2*time.Hour)), ShouldBeFalse)
+	a.So(dev.ConflictEvidence, ShouldEqual, 0)
+
+	for i := 1; i < ConflictThreshold; i++ {
+		a.So(checkActivation(dev, now.Add(5*time.Minute)), ShouldBeFalse)
+	}
+	a.So(checkActivation(dev, now.Add(5*time.Minute)), ShouldBeTrue)
+	a.So(dev.Conflict.Reason, ShouldEqual, device.ConflictActivations)
+}
+
+func TestFlagSharedSession(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestFlagSharedSession")},
+		devices:   device.NewMemoryDeviceStore(),
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+
+	devAddr := types.DevAddr{1, 2, 3, 4}
+	nwkSKey := types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	h.devices.Set(&device.Device{AppID: "app", DevID: "original", DevAddr: devAddr, NwkSKey: nwkSKey})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "other", DevAddr: devAddr})
+
+	// Devices without session are not checked
+	dev := &device.Device{AppID: "other-app", DevID: "copy"}
+	a.So(h.flagSharedSession(dev), ShouldBeNil)
+	a.So(dev.Conflict, ShouldBeNil)
+
+	dev.DevAddr, dev.NwkSKey = devAddr, nwkSKey
+	a.So(h.flagSharedSession(dev), ShouldBeNil)
+	a.So(dev.Conflict, ShouldNotBeNil)
+	a.So(dev.Conflict.Reason, ShouldEqual, device.ConflictSharedSession)
+	a.So(dev.Conflict.Devices, ShouldResemble, []string{"app/original"})
+
+	original, _ := h.devices.Get("app", "original")
+	a.So(original.Conflict, ShouldNotBeNil)
+	a.So(original.Conflict.Devices, ShouldResemble, []string{"other-app/copy"})
+
+	other, _ := h.devices.Get("app", "other")
+	a.So(other.Conflict, ShouldBeNil)
+
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+	event := <-h.mqttEvent
+	a.So(event.DevID, ShouldEqual, "original")
+	a.So(event.Event, ShouldEqual, types.DeviceConflictEvent)
+	a.So(event.Data, ShouldResemble, types.DeviceConflictEventData{
+		Reason:  device.ConflictSharedSession,
+		Devices: []string{"other-app/copy"},
+	})
+}
@@ -14,24 +14,37 @@ import (
 	"github.com/TheThingsNetwork/ttn/core/handler/functions"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/fault"
 )
 
 // ConvertFieldsUp converts the payload to fields using payload functions
-func (h *handler) ConvertFieldsUp(ctx ttnlog.Interface, _ *pb_broker.DeduplicatedUplinkMessage, appUp *types.UplinkMessage, _ *device.Device) error {
+func (h *handler) ConvertFieldsUp(ctx ttnlog.Interface, _ *pb_broker.DeduplicatedUplinkMessage, appUp *types.UplinkMessage, dev *device.Device) error {
+	// Encrypted payloads can not be decoded
+	if appUp.Encrypted {
+		return nil
+	}
+
 	// Find Application
 	app, err := h.applications.Get(appUp.AppID)
 	if err != nil {
 		return nil // Do not process if application not found
 	}
 
-	functions := &UplinkFunctions{
-		Decoder:   app.Decoder,
-		Converter: app.Converter,
-		Validator: app.Validator,
-		Logger:    functions.Ignore,
+	var fields map[string]interface{}
+	var valid bool
+	converter, err := h.payloadConverter(app, dev, appUp.FPort)
+	if err == nil {
+		err = fault.Inject(fault.HandlerDecode)
+	}
+	if err == nil {
+		functions := &UplinkFunctions{
+			Decoder:   converter.Decoder,
+			Converter: converter.Converter,
+			Validator: converter.Validator,
+			Logger:    functions.Ignore,
+		}
+		fields, valid, err = functions.Process(appUp.PayloadRaw, appUp.FPort)
 	}
-
-	fields, valid, err := functions.Process(appUp.PayloadRaw, appUp.FPort)
 	if err != nil {
 
 		// Emit the error
@@ -59,7 +72,8 @@ func (h *handler) ConvertFieldsUp(ctx ttnlog.Interface, _ *pb_broker.Deduplicate
 // UplinkFunctions decodes, converts and validates payload using JavaScript functions
 type UplinkFunctions struct {
 	// Decoder is a JavaScript function that accepts the payload as byte array and
-	// returns an object containing the decoded values
+	// returns an object containing the decoded values. It can also be a
+	// LoRaWAN payload codec with a decodeUplink function.
 	Decoder string
 	// Converter is a JavaScript function that accepts the data as decoded by
 	// Decoder and returns an object containing the converted values
@@ -87,8 +101,9 @@ func (f *UplinkFunctions) Decode(payload []byte, port uint8) (map[string]interfa
 	}
 	code := fmt.Sprintf(`
 		%s;
-		Decoder(payload.slice(0), port);
-	`, f.Decoder)
+		%s
+		typeof Decoder === "function" ? Decoder(payload.slice(0), port) : __decodeUplink(payload.slice(0), port);
+	`, f.Decoder, codecDecodeUplink)
 
 	value, err := functions.RunCode("Decoder", code, env, timeOut, f.Logger)
 	if err != nil {
@@ -190,7 +205,8 @@ func (f *UplinkFunctions) Process(payload []byte, port uint8) (map[string]interf
 // DownlinkFunctions encodes payload using JavaScript functions
 type DownlinkFunctions struct {
 	// Encoder is a JavaScript function that accepts the payload as JSON and
-	// returns an array of bytes
+	// returns an array of bytes. It can also be a LoRaWAN payload codec with an
+	// encodeDownlink function.
 	Encoder string
 
 	// Logger is the logger that will be used to store logs
@@ -210,8 +226,9 @@ func (f *DownlinkFunctions) Encode(payload map[string]interface{}, port uint8) (
 	}
 	code := fmt.Sprintf(`
 		%s;
-		Encoder(payload, port)
-	`, f.Encoder)
+		%s
+		typeof Encoder === "function" ? Encoder(payload, port) : __encodeDownlink(payload, port);
+	`, f.Encoder, codecEncodeDownlink)
 
 	value, err := functions.RunCode("Encoder", code, env, timeOut, f.Logger)
 	if err != nil {
@@ -296,7 +313,7 @@ func (f *DownlinkFunctions) Process(payload map[string]interface{}, port uint8)
 }
 
 // ConvertFieldsDown converts the fields into a payload
-func (h *handler) ConvertFieldsDown(ctx ttnlog.Interface, appDown *types.DownlinkMessage, ttnDown *pb_broker.DownlinkMessage, _ *device.Device) error {
+func (h *handler) ConvertFieldsDown(ctx ttnlog.Interface, appDown *types.DownlinkMessage, ttnDown *pb_broker.DownlinkMessage, dev *device.Device) error {
 	if appDown.PayloadFields == nil || len(appDown.PayloadFields) == 0 {
 		return nil
 	}
@@ -305,13 +322,22 @@ func (h *handler) ConvertFieldsDown(ctx ttnlog.Interface, appDown *types.Downlin
 		return errors.NewErrInvalidArgument("Downlink", "Both Fields and Payload provided")
 	}
 
+	if dev.EndToEnd {
+		return errors.NewErrInvalidArgument("Downlink", "Fields can not be encoded for devices with end-to-end encryption")
+	}
+
 	app, err := h.applications.Get(appDown.AppID)
 	if err != nil {
 		return nil
 	}
 
+	converter, err := h.payloadConverter(app, dev, appDown.FPort)
+	if err != nil {
+		return err
+	}
+
 	functions := &DownlinkFunctions{
-		Encoder: app.Encoder,
+		Encoder: converter.Encoder,
 		Logger:  functions.Ignore,
 	}
 
@@ -4,6 +4,8 @@
 package handler
 
 import (
+	"time"
+
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
 	"github.com/TheThingsNetwork/ttn/api/trace"
@@ -48,12 +50,25 @@ func (h *handler) ConvertFromLoRaWAN(ctx ttnlog.Interface, ttnUp *pb_broker.Dedu
 	if dev.FCntUp == appUp.FCnt {
 		appUp.IsRetry = true
 	}
+	// The Broker drops the regressions of devices that do not disable the
+	// frame counter check, and forwards them as FCntRegression
+	if checkFCntRegression(dev, appUp.FCnt, time.Now()) {
+		h.publishConflict(dev)
+	}
 	dev.FCntUp = appUp.FCnt
 
 	// LoRaWAN: Decrypt
 	if macPayload.FPort != nil {
 		appUp.FPort = *macPayload.FPort
-		if *macPayload.FPort != 0 && len(macPayload.FRMPayload) == 1 {
+		if *macPayload.FPort != 0 && len(macPayload.FRMPayload) == 1 && dev.EndToEnd {
+			// The application decrypts the payload
+			payload, ok := macPayload.FRMPayload[0].(*lorawan.DataPayload)
+			if !ok {
+				return errors.NewErrInvalidArgument("Uplink FRMPayload", "must be of type *lorawan.DataPayload")
+			}
+			appUp.PayloadRaw = payload.Bytes
+			appUp.Encrypted = true
+		} else if *macPayload.FPort != 0 && len(macPayload.FRMPayload) == 1 {
 			ctx = ctx.WithField("FCnt", appUp.FPort)
 			if err := phyPayload.DecryptFRMPayload(lorawan.AES128Key(dev.AppSKey)); err != nil {
 				return errors.NewErrInternal("Could not decrypt payload")
@@ -139,8 +154,12 @@ func (h *handler) ConvertToLoRaWAN(ctx ttnlog.Interface, appDown *types.Downlink
 	}
 
 	// Encrypt
-	err = phyPayload.EncryptFRMPayload(lorawan.AES128Key(dev.AppSKey))
-	if err != nil {
+	if dev.EndToEnd {
+		// The application encrypted the payload
+		if err := checkEndToEndCounter(appDown, macPayload.FHDR.FCnt); err != nil {
+			return err
+		}
+	} else if err = phyPayload.EncryptFRMPayload(lorawan.AES128Key(dev.AppSKey)); err != nil {
 		return err
 	}
 
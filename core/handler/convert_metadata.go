@@ -21,8 +21,12 @@ func (h *handler) ConvertMetadata(ctx ttnlog.Interface, ttnUp *pb_broker.Dedupli
 		appUp.Metadata.DataRate = lorawan.DataRate
 		appUp.Metadata.Bitrate = lorawan.BitRate
 		appUp.Metadata.CodingRate = lorawan.CodingRate
+		appUp.Metadata.Airtime = lorawan.Airtime
 	}
 
+	trustedOnly := h.trustedGatewaysOnly(ttnUp.AppId)
+	privacy := h.metadataPrivacy(ttnUp.AppId)
+
 	// Transform Gateway Metadata
 	appUp.Metadata.Gateways = make([]types.GatewayMetadata, 0, len(ttnUp.GatewayMetadata))
 	for i, in := range ttnUp.GatewayMetadata {
@@ -43,12 +47,18 @@ func (h *handler) ConvertMetadata(ctx ttnlog.Interface, ttnUp *pb_broker.Dedupli
 			SNR:        in.Snr,
 		}
 
-		if gps := in.GetGps(); gps != nil {
+		if trustedOnly && !in.GatewayTrusted {
+			gatewayMetadata.GtwUntrusted = true
+		} else if gps := in.GetGps(); gps != nil {
 			gatewayMetadata.Altitude = gps.Altitude
 			gatewayMetadata.Longitude = gps.Longitude
 			gatewayMetadata.Latitude = gps.Latitude
 		}
 
+		for _, p := range privacy {
+			p.Apply(&gatewayMetadata)
+		}
+
 		appUp.Metadata.Gateways = append(appUp.Metadata.Gateways, gatewayMetadata)
 	}
 
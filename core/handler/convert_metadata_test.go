@@ -12,6 +12,7 @@ import (
 	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
 	"github.com/TheThingsNetwork/ttn/core/handler/device"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
@@ -57,6 +58,14 @@ func TestConvertMetadata(t *testing.T) {
 	err = h.ConvertMetadata(h.Ctx, ttnUp, appUp, device)
 	a.So(err, ShouldBeNil)
 	a.So(appUp.Metadata.DataRate, ShouldEqual, "SF7BW125")
+	a.So(appUp.Metadata.Airtime, ShouldEqual, 0)
+
+	// The airtime is computed by the Router
+	ttnUp.ProtocolMetadata.GetLorawan().Airtime = 41216000
+
+	err = h.ConvertMetadata(h.Ctx, ttnUp, appUp, device)
+	a.So(err, ShouldBeNil)
+	a.So(appUp.Metadata.Airtime, ShouldEqual, 41216000)
 
 	ttnUp.GatewayMetadata[0].Time = 1465831736000000000
 	ttnUp.GatewayMetadata[0].Gps = &pb_gateway.GPSMetadata{
@@ -67,5 +76,21 @@ func TestConvertMetadata(t *testing.T) {
 	a.So(err, ShouldBeNil)
 	a.So(appUp.Metadata.Gateways[0].Latitude, ShouldEqual, 42)
 	a.So(time.Time(appUp.Metadata.Gateways[0].Time).UTC(), ShouldResemble, time.Date(2016, 06, 13, 15, 28, 56, 0, time.UTC))
+	a.So(appUp.Metadata.Gateways[0].GtwUntrusted, ShouldBeFalse)
 
+	// Applications that only use trusted gateways don't get the location of untrusted gateways
+	h.applications = application.NewMemoryApplicationStore()
+	h.applications.Set(&application.Application{AppID: "trusted", TrustedGatewaysOnly: true})
+	ttnUp.AppId = "trusted"
+	ttnUp.GatewayMetadata[1].GatewayTrusted = true
+	ttnUp.GatewayMetadata[1].Gps = &pb_gateway.GPSMetadata{
+		Latitude: 43,
+	}
+
+	err = h.ConvertMetadata(h.Ctx, ttnUp, appUp, device)
+	a.So(err, ShouldBeNil)
+	a.So(appUp.Metadata.Gateways[0].GtwUntrusted, ShouldBeTrue)
+	a.So(appUp.Metadata.Gateways[0].Latitude, ShouldEqual, 0)
+	a.So(appUp.Metadata.Gateways[1].GtwUntrusted, ShouldBeFalse)
+	a.So(appUp.Metadata.Gateways[1].Latitude, ShouldEqual, 43)
 }
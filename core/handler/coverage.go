@@ -0,0 +1,311 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// Sources of the location of coverage samples
+const (
+	CoverageSourceDevice  = "device"
+	CoverageSourceGateway = "gateway"
+)
+
+// CoverageConfig configures the coverage statistics of applications
+type CoverageConfig struct {
+	CellSize float64 // the size of the coverage cells in degrees
+	MaxCells int     // the maximum number of cells per application (0 for unlimited)
+}
+
+// coverageKey identifies the statistics of a gateway in a coverage cell
+type coverageKey struct {
+	lat, lon int64
+	source   string
+	gtwID    string
+}
+
+type coverageStats struct {
+	samples   int
+	rssi      [3]float32 // min, sum, max
+	snr       [3]float32 // min, sum, max
+	dataRates map[string]int
+	lastSeen  time.Time
+}
+
+func (s *coverageStats) add(other *coverageStats) {
+	if s.samples == 0 || other.rssi[0] < s.rssi[0] {
+		s.rssi[0] = other.rssi[0]
+	}
+	if s.samples == 0 || other.rssi[2] > s.rssi[2] {
+		s.rssi[2] = other.rssi[2]
+	}
+	if s.samples == 0 || other.snr[0] < s.snr[0] {
+		s.snr[0] = other.snr[0]
+	}
+	if s.samples == 0 || other.snr[2] > s.snr[2] {
+		s.snr[2] = other.snr[2]
+	}
+	s.rssi[1] += other.rssi[1]
+	s.snr[1] += other.snr[1]
+	s.samples += other.samples
+	for dataRate, n := range other.dataRates {
+		s.dataRates[dataRate] += n
+	}
+	if other.lastSeen.After(s.lastSeen) {
+		s.lastSeen = other.lastSeen
+	}
+}
+
+type coverage struct {
+	config CoverageConfig
+
+	mu    sync.Mutex
+	cells map[string]map[coverageKey]*coverageStats // by AppID
+}
+
+func (h *handler) WithCoverage(config CoverageConfig) Handler {
+	h.coverage = &coverage{
+		config: config,
+		cells:  make(map[string]map[coverageKey]*coverageStats),
+	}
+	return h
+}
+
+// payloadLocation returns the location in the decoded payload fields of an
+// uplink message, such as the position of a GPS tracker
+func payloadLocation(fields map[string]interface{}) (lat, lon float64, ok bool) {
+	get := func(names ...string) (float64, bool) {
+		for _, name := range names {
+			switch value := fields[name].(type) {
+			case float64:
+				return value, true
+			case float32:
+				return float64(value), true
+			case int:
+				return float64(value), true
+			case int64:
+				return float64(value), true
+			}
+		}
+		return 0, false
+	}
+	lat, latOK := get("latitude", "lat")
+	lon, lonOK := get("longitude", "lon", "lng")
+	if !latOK || !lonOK {
+		return 0, 0, false
+	}
+	return lat, lon, validLocation(lat, lon)
+}
+
+// validLocation returns false for locations that are out of range, and for
+// 0,0 which is what most GPS trackers send without a fix
+func validLocation(lat, lon float64) bool {
+	if lat == 0 && lon == 0 {
+		return false
+	}
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// recordCoverage adds the signal quality of an uplink message to the coverage
+// statistics of the application. The uplink is located at the location of the
+// device, from the payload or the metadata, or otherwise at the location of
+// each gateway that received it.
+func (h *handler) recordCoverage(up *types.UplinkMessage) {
+	c := h.coverage
+	if c == nil || c.config.CellSize <= 0 {
+		return
+	}
+	lat, lon, ok := payloadLocation(up.PayloadFields)
+	if !ok {
+		lat, lon = float64(up.Metadata.Latitude), float64(up.Metadata.Longitude)
+		ok = validLocation(lat, lon)
+	}
+	dataRate := up.Metadata.DataRate
+	if dataRate == "" && up.Metadata.Bitrate != 0 {
+		dataRate = fmt.Sprint(up.Metadata.Bitrate)
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cells, exists := c.cells[up.AppID]
+	if !exists {
+		cells = make(map[coverageKey]*coverageStats)
+		c.cells[up.AppID] = cells
+	}
+	for _, gateway := range up.Metadata.Gateways {
+		key := coverageKey{source: CoverageSourceDevice, gtwID: gateway.GtwID}
+		cellLat, cellLon := lat, lon
+		if !ok {
+			cellLat, cellLon = float64(gateway.Latitude), float64(gateway.Longitude)
+			if !validLocation(cellLat, cellLon) {
+				continue
+			}
+			key.source = CoverageSourceGateway
+		}
+		key.lat = int64(math.Floor(cellLat / c.config.CellSize))
+		key.lon = int64(math.Floor(cellLon / c.config.CellSize))
+		stats, exists := cells[key]
+		if !exists {
+			if c.config.MaxCells > 0 && len(cells) >= c.config.MaxCells {
+				continue
+			}
+			stats = &coverageStats{dataRates: make(map[string]int)}
+			cells[key] = stats
+		}
+		sample := &coverageStats{
+			samples:  1,
+			rssi:     [3]float32{gateway.RSSI, gateway.RSSI, gateway.RSSI},
+			snr:      [3]float32{gateway.SNR, gateway.SNR, gateway.SNR},
+			lastSeen: now,
+		}
+		if dataRate != "" {
+			sample.dataRates = map[string]int{dataRate: 1}
+		}
+		stats.add(sample)
+	}
+}
+
+// CoverageFeatureCollection is a GeoJSON FeatureCollection of coverage cells
+type CoverageFeatureCollection struct {
+	Type     string             `json:"type"`
+	Features []*CoverageFeature `json:"features"`
+}
+
+// CoverageFeature is a GeoJSON Feature of a coverage cell
+type CoverageFeature struct {
+	Type       string             `json:"type"`
+	Geometry   CoverageGeometry   `json:"geometry"`
+	Properties CoverageProperties `json:"properties"`
+}
+
+// CoverageGeometry is the GeoJSON Polygon of a coverage cell. Coordinates are
+// in longitude, latitude order.
+type CoverageGeometry struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// CoverageProperties contains the statistics of the uplink messages in a coverage cell
+type CoverageProperties struct {
+	Source    string         `json:"source"`
+	Samples   int            `json:"samples"`
+	Gateways  []string       `json:"gateways"`
+	MinRSSI   float32        `json:"min_rssi"`
+	AvgRSSI   float32        `json:"avg_rssi"`
+	MaxRSSI   float32        `json:"max_rssi"`
+	MinSNR    float32        `json:"min_snr"`
+	AvgSNR    float32        `json:"avg_snr"`
+	MaxSNR    float32        `json:"max_snr"`
+	DataRates map[string]int `json:"data_rates"`
+	LastSeen  time.Time      `json:"last_seen"`
+}
+
+type coverageFeaturesByLocation []*CoverageFeature
+
+func (f coverageFeaturesByLocation) Len() int      { return len(f) }
+func (f coverageFeaturesByLocation) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f coverageFeaturesByLocation) Less(i, j int) bool {
+	a, b := f[i].Geometry.Coordinates[0][0], f[j].Geometry.Coordinates[0][0]
+	if a[1] != b[1] {
+		return a[1] < b[1]
+	}
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	return f[i].Properties.Source < f[j].Properties.Source
+}
+
+// geoJSON returns the coverage cells of an application, optionally only for
+// one gateway and one source
+func (c *coverage) geoJSON(appID, gtwID, source string) *CoverageFeatureCollection {
+	type cellKey struct {
+		lat, lon int64
+		source   string
+	}
+	c.mu.Lock()
+	merged := make(map[cellKey]*coverageStats)
+	gateways := make(map[cellKey][]string)
+	for key, stats := range c.cells[appID] {
+		if (gtwID != "" && key.gtwID != gtwID) || (source != "" && key.source != source) {
+			continue
+		}
+		cell := cellKey{key.lat, key.lon, key.source}
+		if _, ok := merged[cell]; !ok {
+			merged[cell] = &coverageStats{dataRates: make(map[string]int)}
+		}
+		merged[cell].add(stats)
+		gateways[cell] = append(gateways[cell], key.gtwID)
+	}
+	c.mu.Unlock()
+
+	collection := &CoverageFeatureCollection{Type: "FeatureCollection", Features: make([]*CoverageFeature, 0, len(merged))}
+	for cell, stats := range merged {
+		size := c.config.CellSize
+		south, west := float64(cell.lat)*size, float64(cell.lon)*size
+		north, east := south+size, west+size
+		sort.Strings(gateways[cell])
+		collection.Features = append(collection.Features, &CoverageFeature{
+			Type: "Feature",
+			Geometry: CoverageGeometry{
+				Type:        "Polygon",
+				Coordinates: [][][2]float64{{{west, south}, {east, south}, {east, north}, {west, north}, {west, south}}},
+			},
+			Properties: CoverageProperties{
+				Source:    cell.source,
+				Samples:   stats.samples,
+				Gateways:  gateways[cell],
+				MinRSSI:   stats.rssi[0],
+				AvgRSSI:   stats.rssi[1] / float32(stats.samples),
+				MaxRSSI:   stats.rssi[2],
+				MinSNR:    stats.snr[0],
+				AvgSNR:    stats.snr[1] / float32(stats.samples),
+				MaxSNR:    stats.snr[2],
+				DataRates: stats.dataRates,
+				LastSeen:  stats.lastSeen,
+			},
+		})
+	}
+	sort.Sort(coverageFeaturesByLocation(collection.Features))
+	return collection
+}
+
+func (h *httpHandler) coverage(req *http.Request, appID string) (*CoverageFeatureCollection, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	if _, err := h.manager.handler.applications.Get(appID); err != nil {
+		return nil, err
+	}
+	c := h.manager.handler.coverage
+	if c == nil {
+		return nil, errors.NewErrNotFound("Coverage statistics on this Handler")
+	}
+	source := req.URL.Query().Get("source")
+	if source != "" && source != CoverageSourceDevice && source != CoverageSourceGateway {
+		return nil, errors.NewErrInvalidArgument("Source", fmt.Sprintf("must be %s or %s", CoverageSourceDevice, CoverageSourceGateway))
+	}
+	return c.geoJSON(appID, req.URL.Query().Get("gtw_id"), source), nil
+}
+
+// writeGeoJSON writes a GeoJSON response, or the error
+func (h *httpHandler) writeGeoJSON(res http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		h.write(res, nil, err)
+		return
+	}
+	res.Header().Set("Content-Type", "application/geo+json")
+	json.NewEncoder(res).Encode(v)
+}
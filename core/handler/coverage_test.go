@@ -0,0 +1,98 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPayloadLocation(t *testing.T) {
+	a := New(t)
+
+	lat, lon, ok := payloadLocation(map[string]interface{}{"latitude": 52.3731, "longitude": 4.8913})
+	a.So(ok, ShouldBeTrue)
+	a.So(lat, ShouldEqual, 52.3731)
+	a.So(lon, ShouldEqual, 4.8913)
+
+	_, lon, ok = payloadLocation(map[string]interface{}{"lat": 52.3731, "lng": 4.8913})
+	a.So(ok, ShouldBeTrue)
+	a.So(lon, ShouldEqual, 4.8913)
+
+	_, _, ok = payloadLocation(map[string]interface{}{"lat": 0.0, "lon": 0.0})
+	a.So(ok, ShouldBeFalse)
+	_, _, ok = payloadLocation(map[string]interface{}{"lat": 91.0, "lon": 4.8913})
+	a.So(ok, ShouldBeFalse)
+	_, _, ok = payloadLocation(map[string]interface{}{"lat": "52.3731", "lon": "4.8913"})
+	a.So(ok, ShouldBeFalse)
+	_, _, ok = payloadLocation(nil)
+	a.So(ok, ShouldBeFalse)
+}
+
+func TestRecordCoverage(t *testing.T) {
+	a := New(t)
+	h := &handler{}
+
+	uplink := func(fields map[string]interface{}, gateways ...types.GatewayMetadata) *types.UplinkMessage {
+		return &types.UplinkMessage{
+			AppID:         "app",
+			PayloadFields: fields,
+			Metadata:      types.Metadata{DataRate: "SF7BW125", Gateways: gateways},
+		}
+	}
+	gtw1 := types.GatewayMetadata{GtwID: "gtw-1", RSSI: -100, SNR: 5, LocationMetadata: types.LocationMetadata{Latitude: 52.1, Longitude: 4.1}}
+	gtw2 := types.GatewayMetadata{GtwID: "gtw-2", RSSI: -120, SNR: -10}
+
+	// Without coverage nothing is recorded
+	h.recordCoverage(uplink(map[string]interface{}{"lat": 52.3731, "lon": 4.8913}, gtw1))
+
+	h.WithCoverage(CoverageConfig{CellSize: 0.01, MaxCells: 3})
+
+	h.recordCoverage(uplink(map[string]interface{}{"lat": 52.3731, "lon": 4.8913}, gtw1, gtw2))
+	h.recordCoverage(uplink(map[string]interface{}{"lat": 52.3759, "lon": 4.8901}, gtw1))
+	h.recordCoverage(uplink(nil, gtw1, gtw2)) // gtw2 has no location
+
+	coverage := h.coverage.geoJSON("app", "", "")
+	a.So(coverage.Type, ShouldEqual, "FeatureCollection")
+	a.So(coverage.Features, ShouldHaveLength, 2)
+
+	gateway := coverage.Features[0]
+	a.So(gateway.Properties.Source, ShouldEqual, CoverageSourceGateway)
+	a.So(gateway.Properties.Samples, ShouldEqual, 1)
+	a.So(gateway.Properties.Gateways, ShouldResemble, []string{"gtw-1"})
+
+	device := coverage.Features[1]
+	a.So(device.Type, ShouldEqual, "Feature")
+	a.So(device.Geometry.Type, ShouldEqual, "Polygon")
+	a.So(device.Geometry.Coordinates[0], ShouldHaveLength, 5)
+	a.So(device.Geometry.Coordinates[0][0][0], ShouldAlmostEqual, 4.89)
+	a.So(device.Geometry.Coordinates[0][0][1], ShouldAlmostEqual, 52.37)
+	a.So(device.Geometry.Coordinates[0][2][0], ShouldAlmostEqual, 4.90)
+	a.So(device.Geometry.Coordinates[0][2][1], ShouldAlmostEqual, 52.38)
+	a.So(device.Properties.Source, ShouldEqual, CoverageSourceDevice)
+	a.So(device.Properties.Samples, ShouldEqual, 3)
+	a.So(device.Properties.Gateways, ShouldResemble, []string{"gtw-1", "gtw-2"})
+	a.So(device.Properties.MinRSSI, ShouldEqual, -120)
+	a.So(device.Properties.MaxRSSI, ShouldEqual, -100)
+	a.So(device.Properties.MinSNR, ShouldEqual, -10)
+	a.So(device.Properties.MaxSNR, ShouldEqual, 5)
+	a.So(device.Properties.DataRates, ShouldResemble, map[string]int{"SF7BW125": 3})
+
+	// Filters
+	a.So(h.coverage.geoJSON("app", "gtw-2", "").Features, ShouldHaveLength, 1)
+	a.So(h.coverage.geoJSON("app", "", CoverageSourceGateway).Features, ShouldHaveLength, 1)
+	a.So(h.coverage.geoJSON("other-app", "", "").Features, ShouldBeEmpty)
+
+	// The number of cells is limited
+	h.recordCoverage(uplink(map[string]interface{}{"lat": 51.0, "lon": 4.0}, gtw1, gtw2))
+	a.So(h.coverage.geoJSON("app", "", "").Features, ShouldHaveLength, 2)
+
+	// The response is valid GeoJSON
+	data, err := json.Marshal(coverage)
+	a.So(err, ShouldBeNil)
+	a.So(string(data), ShouldContainSubstring, `"type":"FeatureCollection"`)
+}
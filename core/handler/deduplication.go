@@ -0,0 +1,103 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// MaxDeduplicationWindow is the maximum deduplication window of an application
+const MaxDeduplicationWindow = time.Hour
+
+// deduplicationWindow returns the deduplication window of the application
+func (h *handler) deduplicationWindow(appID string) time.Duration {
+	if h.applications == nil {
+		return 0
+	}
+	app, err := h.applications.Get(appID)
+	if err != nil {
+		return 0
+	}
+	return app.DeduplicationWindow
+}
+
+func uplinkHash(up *types.UplinkMessage) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d:%d:", up.FCnt, up.FPort)
+	hash.Write(up.PayloadRaw)
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+// isDuplicateUplink returns true if the uplink message is a retransmission of
+// the last uplink message of the device, which repeaters and NbTrans cause.
+// Otherwise, it records the uplink message in the device.
+func isDuplicateUplink(dev *device.Device, up *types.UplinkMessage, window time.Duration, now time.Time) bool {
+	if window <= 0 {
+		return false
+	}
+	hash := uplinkHash(up)
+	if up.IsRetry && hash == dev.LastUplinkHash && now.Sub(dev.LastUplinkAt) < window {
+		return true
+	}
+	dev.LastUplinkHash = hash
+	dev.LastUplinkAt = now
+	return false
+}
+
+func (h *httpHandler) getDeduplication(req *http.Request, appID string) (*DeduplicationResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	return &DeduplicationResponse{AppID: app.AppID, DeduplicationWindow: app.DeduplicationWindow.String()}, nil
+}
+
+// setDeduplication sets the deduplication window of the application, zero disables deduplication
+func (h *httpHandler) setDeduplication(req *http.Request, appID string) (*DeduplicationResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in DeduplicationResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	window, err := parseDeduplicationWindow(in.DeduplicationWindow)
+	if err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.DeduplicationWindow = window
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &DeduplicationResponse{AppID: app.AppID, DeduplicationWindow: app.DeduplicationWindow.String()}, nil
+}
+
+func parseDeduplicationWindow(str string) (time.Duration, error) {
+	window, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, errors.NewErrInvalidArgument("Deduplication Window", err.Error())
+	}
+	if window < 0 || window > MaxDeduplicationWindow {
+		return 0, errors.NewErrInvalidArgument("Deduplication Window", fmt.Sprintf("must be between 0 and %s", MaxDeduplicationWindow))
+	}
+	return window, nil
+}
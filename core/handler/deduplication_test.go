@@ -0,0 +1,56 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestIsDuplicateUplink(t *testing.T) {
+	a := New(t)
+
+	dev := &device.Device{}
+	now := time.Now()
+	up := &types.UplinkMessage{FCnt: 1, FPort: 1, PayloadRaw: []byte{0x01, 0x02}}
+
+	// Deduplication disabled
+	a.So(isDuplicateUplink(dev, up, 0, now), ShouldBeFalse)
+	a.So(dev.LastUplinkHash, ShouldBeEmpty)
+
+	a.So(isDuplicateUplink(dev, up, time.Minute, now), ShouldBeFalse)
+	a.So(dev.LastUplinkHash, ShouldNotBeEmpty)
+
+	retry := *up
+	retry.IsRetry = true
+	a.So(isDuplicateUplink(dev, &retry, time.Minute, now.Add(10*time.Second)), ShouldBeTrue)
+
+	// Other payload with the same counter
+	other := retry
+	other.PayloadRaw = []byte{0x03}
+	a.So(isDuplicateUplink(dev, &other, time.Minute, now.Add(20*time.Second)), ShouldBeFalse)
+
+	// The window starts at the first message
+	a.So(isDuplicateUplink(dev, &other, time.Minute, now.Add(time.Minute)), ShouldBeTrue)
+	a.So(isDuplicateUplink(dev, &other, time.Minute, now.Add(90*time.Second)), ShouldBeFalse)
+}
+
+func TestParseDeduplicationWindow(t *testing.T) {
+	a := New(t)
+
+	window, err := parseDeduplicationWindow("30s")
+	a.So(err, ShouldBeNil)
+	a.So(window, ShouldEqual, 30*time.Second)
+
+	_, err = parseDeduplicationWindow("soon")
+	a.So(err, ShouldNotBeNil)
+	_, err = parseDeduplicationWindow("-1s")
+	a.So(err, ShouldNotBeNil)
+	_, err = parseDeduplicationWindow("2h")
+	a.So(err, ShouldNotBeNil)
+}
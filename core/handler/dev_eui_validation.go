@@ -0,0 +1,116 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/claims"
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+// DevEUIValidation configures the validation of the DevEUIs of devices that are registered
+type DevEUIValidation struct {
+	Blocks          []types.DevEUIBlock // the DevEUIs must be in one of these blocks (empty to allow all DevEUIs)
+	UniquePerTenant bool                // the DevEUIs must be unique in the applications of a tenant
+}
+
+var ouiPattern = regexp.MustCompile("^[[:xdigit:]]{6}$")
+
+// ParseDevEUIBlocks parses a list of OUIs (70B3D5) and DevEUI blocks in prefix
+// notation (70B3D57ED0000000/36). An OUI is the block of the first 24 bits.
+func ParseDevEUIBlocks(blockStrings []string) (blocks []types.DevEUIBlock, err error) {
+	for _, blockString := range blockStrings {
+		blockString = strings.TrimSpace(blockString)
+		if blockString == "" {
+			continue
+		}
+		if ouiPattern.MatchString(blockString) {
+			blockString = blockString + "0000000000/24"
+		}
+		block, err := types.ParseDevEUIBlock(blockString)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid OUI or DevEUI block %s: %s", blockString, err)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// WithDevEUIValidation sets the validation of the DevEUIs of devices that are registered
+func (h *handler) WithDevEUIValidation(config DevEUIValidation) Handler {
+	h.devEUIValidation = config
+	return h
+}
+
+// checkDevEUI returns an error if the DevEUI of a device that is registered is
+// not in the configured DevEUI blocks, or if another device in the
+// applications of the same tenant already has the DevEUI. Applications
+// without tenant are checked against each other. The caller holds devEUILock
+// until the device is stored.
+func (h *handler) checkDevEUI(appID, devID string, devEUI types.DevEUI) error {
+	if blocks := h.devEUIValidation.Blocks; len(blocks) > 0 {
+		var allowed []string
+		for _, block := range blocks {
+			if block.Contains(devEUI) {
+				allowed = nil
+				break
+			}
+			allowed = append(allowed, block.String())
+		}
+		if len(allowed) > 0 {
+			return errors.NewErrInvalidArgument("DevEUI", fmt.Sprintf("%s is not in the DevEUI blocks of this Handler (%s), application admins can override this check", devEUI, strings.Join(allowed, ", ")))
+		}
+	}
+	if !h.devEUIValidation.UniquePerTenant {
+		return nil
+	}
+	devices, err := h.devices.ListForEUI(devEUI)
+	if err != nil {
+		return err
+	}
+	tenant := h.tenants.ForApplication(appID)
+	for _, dev := range devices {
+		if dev == nil || dev.DevEUI != devEUI || (dev.AppID == appID && dev.DevID == devID) {
+			continue
+		}
+		if h.tenants.ForApplication(dev.AppID) != tenant {
+			continue
+		}
+		return errors.NewErrAlreadyExists(fmt.Sprintf("DevEUI %s (device %s in application %s, application admins can override this check)", devEUI, dev.DevID, dev.AppID))
+	}
+	return nil
+}
+
+// validateDevEUI validates the DevEUI of a device that is registered, unless
+// the request overrides the validation as an admin of the application or with
+// the debug token of the operator of the Handler
+func (h *handlerManager) validateDevEUI(ctx context.Context, claims *claims.Claims, appID, devID string, devEUI types.DevEUI) error {
+	err := h.handler.checkDevEUI(appID, devID, devEUI)
+	if err == nil {
+		return nil
+	}
+	md, _ := api.MetadataFromContext(ctx)
+	if override := md[pb.OverrideDevEUICheckMetadata]; len(override) == 0 || override[0] != "true" {
+		return err
+	}
+	if !h.handler.Component.ValidDebugContext(ctx) {
+		role, ok := h.handler.collaboratorRole(appID, claims.Subject)
+		if rightsErr := h.checkAppRights(claims, appID, rights.AppCollaborators, true); rightsErr != nil || !ok || role != application.RoleAdmin {
+			return errors.NewErrPermissionDenied(fmt.Sprintf(`Overriding the DevEUI check requires the "%s" role in Application "%s": %s`, application.RoleAdmin, appID, err))
+		}
+	}
+	h.handler.Ctx.WithField("AppID", appID).WithField("DevID", devID).WithField("DevEUI", devEUI).
+		WithField("Subject", claims.Subject).WithError(err).
+		Warn("DevEUI check overridden")
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParseDevEUIBlocks(t *testing.T) {
+	a := New(t)
+
+	blocks, err := ParseDevEUIBlocks([]string{"70B3D5", " 0004A30B00000000/32", ""})
+	a.So(err, ShouldBeNil)
+	a.So(blocks, ShouldHaveLength, 2)
+	a.So(blocks[0].String(), ShouldEqual, "70B3D50000000000/24")
+	a.So(blocks[1].String(), ShouldEqual, "0004A30B00000000/32")
+
+	blocks, err = ParseDevEUIBlocks(nil)
+	a.So(err, ShouldBeNil)
+	a.So(blocks, ShouldBeEmpty)
+
+	_, err = ParseDevEUIBlocks([]string{"70B3"})
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseDevEUIBlocks([]string{"70B3D50000000000/64"})
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestCheckDevEUI(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+
+	inBlock := types.DevEUI{0x70, 0xB3, 0xD5, 1, 2, 3, 4, 5}
+	outOfBlock := types.DevEUI{0x00, 0x04, 0xA3, 1, 2, 3, 4, 5}
+
+	// Without validation all DevEUIs are allowed
+	a.So(h.checkDevEUI("acme-sensors", "dev-1", outOfBlock), ShouldBeNil)
+
+	blocks, _ := ParseDevEUIBlocks([]string{"70B3D5"})
+	h.WithDevEUIValidation(DevEUIValidation{Blocks: blocks, UniquePerTenant: true})
+	a.So(h.checkDevEUI("acme-sensors", "dev-1", inBlock), ShouldBeNil)
+	err := h.checkDevEUI("acme-sensors", "dev-1", outOfBlock)
+	a.So(errors.GetErrType(err), ShouldEqual, errors.InvalidArgument)
+	a.So(err.Error(), ShouldContainSubstring, "70B3D50000000000/24")
+
+	tenants, _ := types.ParseTenants(map[string]string{
+		"acme":   "applications=acme-*",
+		"globex": "applications=globex-*",
+	})
+	h.WithTenants(tenants)
+	h.applications.Set(&application.Application{AppID: "acme-sensors"})
+	h.applications.Set(&application.Application{AppID: "acme-meters"})
+	h.applications.Set(&application.Application{AppID: "globex-sensors"})
+	h.applications.Set(&application.Application{AppID: "shared"})
+	h.devices.Set(&device.Device{AppID: "acme-sensors", DevID: "dev-1", DevEUI: inBlock})
+
+	// The device itself does not conflict
+	a.So(h.checkDevEUI("acme-sensors", "dev-1", inBlock), ShouldBeNil)
+
+	// Other devices of the tenant can not have the same DevEUI
+	err = h.checkDevEUI("acme-sensors", "dev-2", inBlock)
+	a.So(errors.GetErrType(err), ShouldEqual, errors.AlreadyExists)
+	err = h.checkDevEUI("acme-meters", "dev-1", inBlock)
+	a.So(errors.GetErrType(err), ShouldEqual, errors.AlreadyExists)
+	a.So(err.Error(), ShouldContainSubstring, "acme-sensors")
+
+	// Other tenants and applications without tenant are not affected
+	a.So(h.checkDevEUI("globex-sensors", "dev-1", inBlock), ShouldBeNil)
+	a.So(h.checkDevEUI("shared", "dev-1", inBlock), ShouldBeNil)
+
+	// Applications without tenant are checked against each other
+	h.devices.Set(&device.Device{AppID: "shared", DevID: "dev-1", DevEUI: inBlock})
+	a.So(errors.GetErrType(h.checkDevEUI("other-shared", "dev-1", inBlock)), ShouldEqual, errors.AlreadyExists)
+
+	h.WithDevEUIValidation(DevEUIValidation{Blocks: blocks})
+	a.So(h.checkDevEUI("acme-meters", "dev-1", inBlock), ShouldBeNil)
+}
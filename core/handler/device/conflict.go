@@ -0,0 +1,35 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import "time"
+
+// Reasons of conflicts
+const (
+	// ConflictSharedSession means that other devices are registered with the same DevAddr and NwkSKey
+	ConflictSharedSession = "shared_session"
+	// ConflictFCntRegressions means that the frame counters of multiple physical devices are interleaved
+	ConflictFCntRegressions = "fcnt_regressions"
+	// ConflictActivations means that multiple physical devices activate with the same AppKey
+	ConflictActivations = "activations"
+)
+
+// Conflict describes why the credentials of a device are assumed to be used by other devices
+type Conflict struct {
+	Reason string `json:"reason"`
+	// Devices are the other registrations (app_id/dev_id) with the same session
+	Devices    []string  `json:"devices,omitempty"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// AddDevice adds the ID of another registration with the same session
+func (c *Conflict) AddDevice(appID, devID string) {
+	id := appID + "/" + devID
+	for _, existing := range c.Devices {
+		if existing == id {
+			return
+		}
+	}
+	c.Devices = append(c.Devices, id)
+}
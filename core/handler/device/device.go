@@ -22,6 +22,7 @@ type Options struct {
 	ActivationConstraints string `json:"activation_constraints,omitempty"` // Activation Constraints (public/local/private)
 	DisableFCntCheck      bool   `json:"disable_fcnt_check,omitemtpy"`     // Disable Frame counter check (insecure)
 	Uses32BitFCnt         bool   `json:"uses_32_bit_fcnt,omitemtpy"`       // Use 32-bit Frame counters
+	DisableADR            bool   `json:"disable_adr,omitempty"`            // Do not send ADR commands
 }
 
 // Device contains the state of a device
@@ -35,6 +36,16 @@ type Device struct {
 
 	Description string `redis:"description"`
 
+	// Name and Tags help to find the device in the devices of the application
+	Name string   `redis:"name,omitempty"`
+	Tags []string `redis:"tags,omitempty"`
+
+	// Profile is the ID of the device profile of the application that the device uses
+	Profile string `redis:"profile,omitempty"`
+
+	// Attributes select the payload converters of the device, such as model=tracker
+	Attributes map[string]string `redis:"attributes,omitempty"`
+
 	Latitude  float32 `redis:"latitude"`
 	Longitude float32 `redis:"longitude"`
 	Altitude  int32   `redis:"altitude"`
@@ -50,8 +61,46 @@ type Device struct {
 	AppSKey types.AppSKey `redis:"app_s_key"`
 	FCntUp  uint32        `redis:"f_cnt_up"` // Only used to detect retries
 
+	// EndToEnd is set for devices with end-to-end encryption: the Handler does
+	// not store the AppSKey, and the application decrypts and encrypts the payload
+	EndToEnd bool `redis:"end_to_end,omitempty"`
+
+	// LastUplinkHash and LastUplinkAt identify the last uplink message that was
+	// published, for the deduplication of retransmissions
+	LastUplinkHash string    `redis:"last_uplink_hash,omitempty"`
+	LastUplinkAt   time.Time `redis:"last_uplink_at,omitempty"`
+
 	CurrentDownlink *types.DownlinkMessage `redis:"current_downlink"`
 
+	// DownlinkDeferrals counts the uplinks in the response to which the first
+	// downlink in the queue could not be sent
+	DownlinkDeferrals int `redis:"downlink_deferrals,omitempty"`
+
+	// JoinAccept overrides the join-accept settings of the application
+	JoinAccept types.JoinAcceptSettings `redis:"join_accept,omitempty"`
+	// RXSettings are the join-accept settings that were sent in the last
+	// join-accept, which the device uses until it joins again
+	RXSettings types.JoinAcceptSettings `redis:"rx_settings,omitempty"`
+
+	// DownlinkProfile configures how the downlink messages of the device are scheduled
+	DownlinkProfile types.DownlinkProfile `redis:"downlink_profile,omitempty"`
+
+	// Ack configures how confirmed uplink messages of the device are acknowledged
+	Ack types.AckSettings `redis:"ack,omitempty"`
+
+	// ActivatedAt is the time of the last activation of the device
+	ActivatedAt time.Time `redis:"activated_at,omitempty"`
+
+	// ConflictEvidence counts the frame counter regressions and repeated
+	// activations since ConflictEvidenceAt, which indicate that multiple
+	// physical devices use the credentials of the device
+	ConflictEvidence   int       `redis:"conflict_evidence,omitempty"`
+	ConflictEvidenceAt time.Time `redis:"conflict_evidence_at,omitempty"`
+
+	// Conflict is set when the Handler detected that the credentials of the
+	// device are also used by other devices
+	Conflict *Conflict `redis:"conflict"`
+
 	CreatedAt time.Time `redis:"created_at"`
 	UpdatedAt time.Time `redis:"updated_at"`
 }
@@ -99,6 +148,7 @@ func (d Device) GetLoRaWAN() *pb_lorawan.Device {
 		DisableFCntCheck:      d.Options.DisableFCntCheck,
 		Uses32BitFCnt:         d.Options.Uses32BitFCnt,
 		ActivationConstraints: d.Options.ActivationConstraints,
+		DisableAdr:            d.Options.DisableADR,
 	}
 	return dev
 }
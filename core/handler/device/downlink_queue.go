@@ -18,6 +18,7 @@ type DownlinkQueue interface {
 	Replace(msg *types.DownlinkMessage) error
 	PushFirst(msg *types.DownlinkMessage) error
 	PushLast(msg *types.DownlinkMessage) error
+	Clear() error
 }
 
 // RedisDownlinkQueue implements the downlink queue in Redis
@@ -77,3 +78,8 @@ func (s *RedisDownlinkQueue) PushLast(msg *types.DownlinkMessage) error {
 	}
 	return s.queues.AddEnd(s.key(), string(qd))
 }
+
+// Clear the downlink queue
+func (s *RedisDownlinkQueue) Clear() error {
+	return s.queues.Delete(s.key())
+}
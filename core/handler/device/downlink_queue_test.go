@@ -80,4 +80,13 @@ func TestDownlinkQueue(t *testing.T) {
 		a.So(next.PayloadRaw, ShouldResemble, []byte{0xaa, 0xbc})
 	}
 
+	{
+		s.PushLast(&types.DownlinkMessage{PayloadRaw: []byte{0x12, 0x34}})
+		s.PushLast(&types.DownlinkMessage{PayloadRaw: []byte{0x56, 0x78}})
+		err := s.Clear()
+		a.So(err, ShouldBeNil)
+		length, _ := s.Length()
+		a.So(length, ShouldEqual, 0)
+	}
+
 }
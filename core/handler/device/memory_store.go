@@ -0,0 +1,212 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// NewMemoryDeviceStore creates a new in-memory Device store. It can be used
+// in tests or when embedding the Handler without Redis.
+func NewMemoryDeviceStore() *MemoryDeviceStore {
+	return &MemoryDeviceStore{
+		devices: make(map[string]Device),
+		queues:  make(map[string][]types.DownlinkMessage),
+	}
+}
+
+// MemoryDeviceStore stores Devices in memory
+type MemoryDeviceStore struct {
+	mu      sync.RWMutex
+	devices map[string]Device
+	queues  map[string][]types.DownlinkMessage
+}
+
+func (s *MemoryDeviceStore) list(prefix string, opts *storage.ListOptions) []*Device {
+	keys := make([]string, 0, len(s.devices))
+	for key := range s.devices {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	keys = storage.SelectKeys(keys, opts)
+	devices := make([]*Device, 0, len(keys))
+	for _, key := range keys {
+		device := s.devices[key]
+		devices = append(devices, &device)
+	}
+	return devices
+}
+
+// List all Devices
+func (s *MemoryDeviceStore) List(opts *storage.ListOptions) ([]*Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list("", opts), nil
+}
+
+// ListForApp lists all devices for a specific Application
+func (s *MemoryDeviceStore) ListForApp(appID string, opts *storage.ListOptions) ([]*Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list(appID+":", opts), nil
+}
+
+// ListForAddress lists all devices for a specific DevAddr
+func (s *MemoryDeviceStore) ListForAddress(devAddr types.DevAddr) ([]*Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var devices []*Device
+	for _, device := range s.list("", nil) {
+		if device.DevAddr == devAddr {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// ListForEUI lists all devices for a specific DevEUI
+func (s *MemoryDeviceStore) ListForEUI(devEUI types.DevEUI) ([]*Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var devices []*Device
+	for _, device := range s.list("", nil) {
+		if device.DevEUI == devEUI {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// Get a specific Device
+func (s *MemoryDeviceStore) Get(appID, devID string) (*Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key := fmt.Sprintf("%s:%s", appID, devID)
+	device, ok := s.devices[key]
+	if !ok {
+		return nil, errors.NewErrNotFound(key)
+	}
+	return &device, nil
+}
+
+// DownlinkQueue for a specific Device
+func (s *MemoryDeviceStore) DownlinkQueue(appID, devID string) (DownlinkQueue, error) {
+	return &MemoryDownlinkQueue{
+		key:   fmt.Sprintf("%s:%s", appID, devID),
+		store: s,
+	}, nil
+}
+
+// Set a new Device or update an existing one
+func (s *MemoryDeviceStore) Set(new *Device, properties ...string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	new.UpdatedAt = now
+
+	key := fmt.Sprintf("%s:%s", new.AppID, new.DevID)
+	_, exists := s.devices[key]
+	if new.old != nil {
+		if !exists {
+			return errors.NewErrNotFound(key)
+		}
+	} else {
+		if exists {
+			return errors.NewErrAlreadyExists(key)
+		}
+		new.CreatedAt = now
+	}
+
+	stored := *new
+	stored.old = nil
+	s.devices[key] = stored
+
+	return nil
+}
+
+// Delete a Device
+func (s *MemoryDeviceStore) Delete(appID, devID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%s:%s", appID, devID)
+	delete(s.queues, key)
+	if _, ok := s.devices[key]; !ok {
+		return errors.NewErrNotFound(key)
+	}
+	delete(s.devices, key)
+	return nil
+}
+
+// MemoryDownlinkQueue implements the downlink queue in memory
+type MemoryDownlinkQueue struct {
+	key   string
+	store *MemoryDeviceStore
+}
+
+// Length of the downlink queue
+func (s *MemoryDownlinkQueue) Length() (int, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+	return len(s.store.queues[s.key]), nil
+}
+
+// Next item in the downlink queue
+func (s *MemoryDownlinkQueue) Next() (*types.DownlinkMessage, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	queue := s.store.queues[s.key]
+	if len(queue) == 0 {
+		return nil, nil
+	}
+	msg := queue[0]
+	if len(queue) == 1 {
+		delete(s.store.queues, s.key)
+	} else {
+		s.store.queues[s.key] = queue[1:]
+	}
+	return &msg, nil
+}
+
+// Replace the downlink queue with msg
+func (s *MemoryDownlinkQueue) Replace(msg *types.DownlinkMessage) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.store.queues[s.key] = []types.DownlinkMessage{*msg}
+	return nil
+}
+
+// PushFirst message to the downlink queue
+func (s *MemoryDownlinkQueue) PushFirst(msg *types.DownlinkMessage) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.store.queues[s.key] = append([]types.DownlinkMessage{*msg}, s.store.queues[s.key]...)
+	return nil
+}
+
+// PushLast message to the downlink queue
+func (s *MemoryDownlinkQueue) PushLast(msg *types.DownlinkMessage) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.store.queues[s.key] = append(s.store.queues[s.key], *msg)
+	return nil
+}
+
+// Clear the downlink queue
+func (s *MemoryDownlinkQueue) Clear() error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	delete(s.store.queues, s.key)
+	return nil
+}
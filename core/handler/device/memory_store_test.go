@@ -0,0 +1,91 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestMemoryDeviceStore(t *testing.T) {
+	a := New(t)
+
+	s := NewMemoryDeviceStore()
+
+	dev, err := s.Get("AppID-1", "DevID-1")
+	a.So(err, ShouldNotBeNil)
+	a.So(dev, ShouldBeNil)
+
+	err = s.Set(&Device{AppID: "AppID-1", DevID: "DevID-1"})
+	a.So(err, ShouldBeNil)
+	err = s.Set(&Device{AppID: "AppID-1", DevID: "DevID-2"})
+	a.So(err, ShouldBeNil)
+	err = s.Set(&Device{AppID: "AppID-10", DevID: "DevID-1"})
+	a.So(err, ShouldBeNil)
+
+	devs, err := s.ListForApp("AppID-1", nil)
+	a.So(err, ShouldBeNil)
+	a.So(devs, ShouldHaveLength, 2)
+
+	devs, err = s.List(nil)
+	a.So(err, ShouldBeNil)
+	a.So(devs, ShouldHaveLength, 3)
+
+	dev, err = s.Get("AppID-1", "DevID-2")
+	a.So(err, ShouldBeNil)
+	dev.StartUpdate()
+	dev.Description = "Updated"
+	a.So(s.Set(dev), ShouldBeNil)
+
+	dev, err = s.Get("AppID-1", "DevID-2")
+	a.So(err, ShouldBeNil)
+	a.So(dev.Description, ShouldEqual, "Updated")
+
+	a.So(s.Delete("AppID-1", "DevID-2"), ShouldBeNil)
+	devs, err = s.ListForApp("AppID-1", nil)
+	a.So(err, ShouldBeNil)
+	a.So(devs, ShouldHaveLength, 1)
+}
+
+func TestMemoryDownlinkQueue(t *testing.T) {
+	a := New(t)
+
+	store := NewMemoryDeviceStore()
+	s, _ := store.DownlinkQueue("test", "test")
+
+	next, err := s.Next()
+	a.So(err, ShouldBeNil)
+	a.So(next, ShouldBeNil)
+
+	a.So(s.PushLast(&types.DownlinkMessage{PayloadRaw: []byte{0x12, 0x34}}), ShouldBeNil)
+	a.So(s.PushFirst(&types.DownlinkMessage{PayloadRaw: []byte{0xab, 0xcd}}), ShouldBeNil)
+
+	length, err := s.Length()
+	a.So(err, ShouldBeNil)
+	a.So(length, ShouldEqual, 2)
+
+	next, err = s.Next()
+	a.So(err, ShouldBeNil)
+	a.So(next.PayloadRaw, ShouldResemble, []byte{0xab, 0xcd})
+
+	a.So(s.Replace(&types.DownlinkMessage{PayloadRaw: []byte{0x56, 0x78}}), ShouldBeNil)
+
+	length, _ = s.Length()
+	a.So(length, ShouldEqual, 1)
+
+	next, err = s.Next()
+	a.So(err, ShouldBeNil)
+	a.So(next.PayloadRaw, ShouldResemble, []byte{0x56, 0x78})
+
+	length, _ = s.Length()
+	a.So(length, ShouldEqual, 0)
+
+	a.So(s.PushLast(&types.DownlinkMessage{PayloadRaw: []byte{0x12, 0x34}}), ShouldBeNil)
+	a.So(s.Clear(), ShouldBeNil)
+
+	length, _ = s.Length()
+	a.So(length, ShouldEqual, 0)
+}
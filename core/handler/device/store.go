@@ -9,6 +9,7 @@ import (
 
 	"github.com/TheThingsNetwork/ttn/core/handler/device/migrate"
 	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"gopkg.in/redis.v5"
 )
@@ -17,6 +18,8 @@ import (
 type Store interface {
 	List(opts *storage.ListOptions) ([]*Device, error)
 	ListForApp(appID string, opts *storage.ListOptions) ([]*Device, error)
+	ListForAddress(devAddr types.DevAddr) ([]*Device, error)
+	ListForEUI(devEUI types.DevEUI) ([]*Device, error)
 	Get(appID, devID string) (*Device, error)
 	DownlinkQueue(appID, devID string) (DownlinkQueue, error)
 	Set(new *Device, properties ...string) (err error)
@@ -26,6 +29,8 @@ type Store interface {
 const defaultRedisPrefix = "handler"
 const redisDevicePrefix = "device"
 const redisDownlinkQueuePrefix = "downlink"
+const redisDevAddrPrefix = "dev_addr"
+const redisDevEUIPrefix = "dev_eui"
 
 // NewRedisDeviceStore creates a new Redis-based Device store
 func NewRedisDeviceStore(client *redis.Client, prefix string) *RedisDeviceStore {
@@ -39,16 +44,21 @@ func NewRedisDeviceStore(client *redis.Client, prefix string) *RedisDeviceStore
 	}
 	queues := storage.NewRedisQueueStore(client, prefix+":"+redisDownlinkQueuePrefix)
 	return &RedisDeviceStore{
-		store:  store,
-		queues: queues,
+		store:        store,
+		queues:       queues,
+		devAddrIndex: storage.NewRedisSetStore(client, prefix+":"+redisDevAddrPrefix),
+		devEUIIndex:  storage.NewRedisSetStore(client, prefix+":"+redisDevEUIPrefix),
 	}
 }
 
 // RedisDeviceStore stores Devices in Redis.
 // - Devices are stored as a Hash
+// - DevAddr and DevEUI mappings are indexed in Sets
 type RedisDeviceStore struct {
-	store  *storage.RedisMapStore
-	queues *storage.RedisQueueStore
+	store        *storage.RedisMapStore
+	queues       *storage.RedisQueueStore
+	devAddrIndex *storage.RedisSetStore
+	devEUIIndex  *storage.RedisSetStore
 }
 
 // List all Devices
@@ -81,6 +91,37 @@ func (s *RedisDeviceStore) ListForApp(appID string, opts *storage.ListOptions) (
 	return devices, nil
 }
 
+// ListForAddress lists all devices for a specific DevAddr
+func (s *RedisDeviceStore) ListForAddress(devAddr types.DevAddr) ([]*Device, error) {
+	return s.listForIndex(s.devAddrIndex, devAddr.String())
+}
+
+// ListForEUI lists all devices for a specific DevEUI
+func (s *RedisDeviceStore) ListForEUI(devEUI types.DevEUI) ([]*Device, error) {
+	return s.listForIndex(s.devEUIIndex, devEUI.String())
+}
+
+func (s *RedisDeviceStore) listForIndex(index *storage.RedisSetStore, value string) ([]*Device, error) {
+	deviceKeys, err := index.Get(value)
+	if errors.GetErrType(err) == errors.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	devicesI, err := s.store.GetAll(deviceKeys, nil)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]*Device, len(devicesI))
+	for i, deviceI := range devicesI {
+		if device, ok := deviceI.(Device); ok {
+			devices[i] = &device
+		}
+	}
+	return devices, nil
+}
+
 // Get a specific Device
 func (s *RedisDeviceStore) Get(appID, devID string) (*Device, error) {
 	deviceI, err := s.store.Get(fmt.Sprintf("%s:%s", appID, devID))
@@ -104,11 +145,24 @@ func (s *RedisDeviceStore) DownlinkQueue(appID, devID string) (DownlinkQueue, er
 
 // Set a new Device or update an existing one
 func (s *RedisDeviceStore) Set(new *Device, properties ...string) (err error) {
+	key := fmt.Sprintf("%s:%s", new.AppID, new.DevID)
+
+	// If this is an update, check if the DevAddr and DevEUI are still the same
+	old := new.old
+	if old != nil && new.DevAddr != old.DevAddr && !old.DevAddr.IsEmpty() {
+		if err := s.devAddrIndex.Remove(old.DevAddr.String(), key); err != nil {
+			return err
+		}
+	}
+	if old != nil && new.DevEUI != old.DevEUI && !old.DevEUI.IsEmpty() {
+		if err := s.devEUIIndex.Remove(old.DevEUI.String(), key); err != nil {
+			return err
+		}
+	}
 
 	now := time.Now()
 	new.UpdatedAt = now
 
-	key := fmt.Sprintf("%s:%s", new.AppID, new.DevID)
 	if new.old != nil {
 		err = s.store.Update(key, *new, properties...)
 	} else {
@@ -119,12 +173,42 @@ func (s *RedisDeviceStore) Set(new *Device, properties ...string) (err error) {
 		return
 	}
 
+	// Devices that were stored before the indexes are added on their next update
+	if !new.DevAddr.IsEmpty() {
+		if err := s.devAddrIndex.Add(new.DevAddr.String(), key); err != nil {
+			return err
+		}
+	}
+	if !new.DevEUI.IsEmpty() {
+		if err := s.devEUIIndex.Add(new.DevEUI.String(), key); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // Delete a Device
 func (s *RedisDeviceStore) Delete(appID, devID string) error {
 	key := fmt.Sprintf("%s:%s", appID, devID)
+
+	deviceI, err := s.store.GetFields(key, "dev_addr", "dev_eui")
+	if err != nil {
+		return err
+	}
+	if device, ok := deviceI.(Device); ok {
+		if !device.DevAddr.IsEmpty() {
+			if err := s.devAddrIndex.Remove(device.DevAddr.String(), key); err != nil {
+				return err
+			}
+		}
+		if !device.DevEUI.IsEmpty() {
+			if err := s.devEUIIndex.Remove(device.DevEUI.String(), key); err != nil {
+				return err
+			}
+		}
+	}
+
 	if err := s.queues.Delete(key); err != nil {
 		return err
 	}
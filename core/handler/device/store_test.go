@@ -80,8 +80,27 @@ func TestDeviceStore(t *testing.T) {
 		s.Delete("AppID-1", "DevID-2")
 	}()
 
+	// List for address
+	devices, err := s.ListForAddress(types.DevAddr([4]byte{0, 0, 0, 1}))
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 1)
+	devices, err = s.ListForAddress(types.DevAddr([4]byte{0, 0, 0, 2}))
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 0)
+	devices, err = s.ListForAddress(types.DevAddr([4]byte{0, 0, 0, 3}))
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 1)
+
+	// List for EUI
+	devices, err = s.ListForEUI(types.DevEUI([8]byte{0, 0, 0, 0, 0, 0, 0, 2}))
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 0)
+	devices, err = s.ListForEUI(types.DevEUI([8]byte{0, 0, 0, 0, 0, 0, 0, 3}))
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 1)
+
 	// List
-	devices, err := s.List(nil)
+	devices, err = s.List(nil)
 	a.So(err, ShouldBeNil)
 	a.So(devices, ShouldHaveLength, 2)
 
@@ -98,4 +117,8 @@ func TestDeviceStore(t *testing.T) {
 	a.So(err, ShouldBeNil)
 	a.So(devs, ShouldHaveLength, 1)
 
+	devices, err = s.ListForAddress(types.DevAddr([4]byte{0, 0, 0, 1}))
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 0)
+
 }
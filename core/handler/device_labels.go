@@ -0,0 +1,198 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// Limits of the labels of a device
+const (
+	MaxDeviceNameLength = 100
+	MaxDeviceTags       = 20
+)
+
+const (
+	defaultDeviceSearchLimit = 100
+	maxDeviceSearchLimit     = 1000
+)
+
+// DeviceLabelsRequest is returned and accepted by the device labels endpoint of the HTTP API
+type DeviceLabelsRequest struct {
+	AppID       string   `json:"app_id"`
+	DevID       string   `json:"dev_id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// SearchedDevice is a device in the response of the device search endpoint of the HTTP API
+type SearchedDevice struct {
+	DevID       string       `json:"dev_id"`
+	DevEUI      types.DevEUI `json:"dev_eui"`
+	Name        string       `json:"name,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+}
+
+// DeviceSearchResponse is returned by the device search endpoint of the HTTP API
+type DeviceSearchResponse struct {
+	AppID   string           `json:"app_id"`
+	Total   int              `json:"total"`
+	Devices []SearchedDevice `json:"devices"`
+}
+
+// normalizeDeviceTags validates the tags of a device and returns them sorted
+// and without duplicates, or nil if there are no tags
+func normalizeDeviceTags(tags []string) ([]string, error) {
+	unique := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if !api.ValidID(tag) {
+			return nil, errors.NewErrInvalidArgument("Tag", fmt.Sprintf("%s has an invalid format", tag))
+		}
+		unique[tag] = true
+	}
+	if len(unique) > MaxDeviceTags {
+		return nil, errors.NewErrInvalidArgument("Tags", fmt.Sprintf("can not have more than %d tags", MaxDeviceTags))
+	}
+	if len(unique) == 0 {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(unique))
+	for tag := range unique {
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// hasDeviceTags returns true if the device has all tags
+func hasDeviceTags(dev *device.Device, tags []string) bool {
+	for _, tag := range tags {
+		var found bool
+		for _, devTag := range dev.Tags {
+			if devTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesDeviceQuery returns true if every word of the query is in the ID,
+// DevEUI, name, description or tags of the device, ignoring case
+func matchesDeviceQuery(dev *device.Device, query string) bool {
+	text := strings.ToLower(strings.Join(append([]string{dev.DevID, dev.DevEUI.String(), dev.Name, dev.Description}, dev.Tags...), " "))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *httpHandler) getDeviceLabels(req *http.Request, appID, devID string) (*DeviceLabelsRequest, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	tags := dev.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &DeviceLabelsRequest{AppID: dev.AppID, DevID: dev.DevID, Name: dev.Name, Description: dev.Description, Tags: tags}, nil
+}
+
+// setDeviceLabels replaces the name, description and tags of the device
+func (h *httpHandler) setDeviceLabels(req *http.Request, appID, devID string) (*DeviceLabelsRequest, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	var in DeviceLabelsRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if len(in.Name) > MaxDeviceNameLength {
+		return nil, errors.NewErrInvalidArgument("Name", fmt.Sprintf("can not be longer than %d characters", MaxDeviceNameLength))
+	}
+	tags, err := normalizeDeviceTags(in.Tags)
+	if err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	dev.StartUpdate()
+	dev.Name = in.Name
+	dev.Description = in.Description
+	dev.Tags = tags
+	if err := h.manager.handler.devices.Set(dev); err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return &DeviceLabelsRequest{AppID: dev.AppID, DevID: dev.DevID, Name: dev.Name, Description: dev.Description, Tags: tags}, nil
+}
+
+// searchDevices returns the devices of the application that match the words
+// in ?q= and have all tags in ?tag=, sorted by ID and paginated with ?limit=
+// and ?offset=
+func (h *httpHandler) searchDevices(req *http.Request, appID string) (*DeviceSearchResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	limit, offset := defaultDeviceSearchLimit, 0
+	if limitString := query.Get("limit"); limitString != "" {
+		parsed, err := strconv.Atoi(limitString)
+		if err != nil || parsed <= 0 || parsed > maxDeviceSearchLimit {
+			return nil, errors.NewErrInvalidArgument("Limit", fmt.Sprintf("must be between 1 and %d", maxDeviceSearchLimit))
+		}
+		limit = parsed
+	}
+	if offsetString := query.Get("offset"); offsetString != "" {
+		parsed, err := strconv.Atoi(offsetString)
+		if err != nil || parsed < 0 {
+			return nil, errors.NewErrInvalidArgument("Offset", "must be a positive number")
+		}
+		offset = parsed
+	}
+	matches, err := h.manager.handler.selectDevices(appID, DeviceFilter{Query: query.Get("q"), Tags: query["tag"]})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeviceSearchResponse{AppID: appID, Total: len(matches), Devices: []SearchedDevice{}}
+	for i := offset; i < len(matches) && i < offset+limit; i++ {
+		dev := matches[i]
+		response.Devices = append(response.Devices, SearchedDevice{
+			DevID:       dev.DevID,
+			DevEUI:      dev.DevEUI,
+			Name:        dev.Name,
+			Description: dev.Description,
+			Tags:        dev.Tags,
+		})
+	}
+	return response, nil
+}
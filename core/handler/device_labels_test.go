@@ -0,0 +1,70 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestNormalizeDeviceTags(t *testing.T) {
+	a := New(t)
+
+	tags, err := normalizeDeviceTags([]string{"floor-2", "building-a", "floor-2"})
+	a.So(err, ShouldBeNil)
+	a.So(tags, ShouldResemble, []string{"building-a", "floor-2"})
+
+	tags, err = normalizeDeviceTags(nil)
+	a.So(err, ShouldBeNil)
+	a.So(tags, ShouldBeEmpty)
+
+	_, err = normalizeDeviceTags([]string{"Floor 2"})
+	a.So(err, ShouldNotBeNil)
+
+	tooMany := make([]string, MaxDeviceTags+1)
+	for i := range tooMany {
+		tooMany[i] = string([]byte{'a' + byte(i)})
+	}
+	_, err = normalizeDeviceTags(tooMany)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestSearchDevices(t *testing.T) {
+	a := New(t)
+	dev := &device.Device{
+		DevID:       "sensor-1",
+		DevEUI:      types.DevEUI{0x00, 0x01, 0xD5, 0x44, 0xB2, 0x93, 0x6F, 0xCE},
+		Name:        "Meeting Room 2",
+		Description: "Near the window",
+		Tags:        []string{"building-a", "floor-2"},
+	}
+
+	a.So(matchesDeviceQuery(dev, ""), ShouldBeTrue)
+	a.So(matchesDeviceQuery(dev, "meeting room"), ShouldBeTrue)
+	a.So(matchesDeviceQuery(dev, "WINDOW sensor"), ShouldBeTrue)
+	a.So(matchesDeviceQuery(dev, "0001d544"), ShouldBeTrue)
+	a.So(matchesDeviceQuery(dev, "building-a"), ShouldBeTrue)
+	a.So(matchesDeviceQuery(dev, "meeting kitchen"), ShouldBeFalse)
+
+	a.So(hasDeviceTags(dev, nil), ShouldBeTrue)
+	a.So(hasDeviceTags(dev, []string{"floor-2", "building-a"}), ShouldBeTrue)
+	a.So(hasDeviceTags(dev, []string{"floor-2", "building-b"}), ShouldBeFalse)
+
+	a.So(DeviceFilter{Query: "room"}.IsEmpty(), ShouldBeFalse)
+	a.So(DeviceFilter{Tags: []string{"floor-2"}}.IsEmpty(), ShouldBeFalse)
+	a.So(DeviceFilter{Query: "room", Tags: []string{"floor-2"}}.Matches(dev), ShouldBeTrue)
+	a.So(DeviceFilter{Query: "room", Tags: []string{"floor-3"}}.Matches(dev), ShouldBeFalse)
+	a.So(DeviceFilter{DevIDs: []string{"tracker-*"}, Query: "room"}.Matches(dev), ShouldBeFalse)
+}
+
+func TestExportDeviceLabels(t *testing.T) {
+	a := New(t)
+	dev := &device.Device{DevID: "sensor-1", Name: "Meeting Room 2", Tags: []string{"building-a"}}
+	export := exportDevice(dev)
+	a.So(export.Name, ShouldEqual, "Meeting Room 2")
+	a.So(export.Tags, ShouldResemble, []string{"building-a"})
+}
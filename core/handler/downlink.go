@@ -4,11 +4,14 @@
 package handler
 
 import (
+	"fmt"
 	"time"
 
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/band"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 )
@@ -30,7 +33,7 @@ func (h *handler) EnqueueDownlink(appDownlink *types.DownlinkMessage) (err error
 	}()
 
 	// Check if device exists
-	_, err = h.devices.Get(appID, devID)
+	dev, err := h.devices.Get(appID, devID)
 	if err != nil {
 		return err
 	}
@@ -49,6 +52,14 @@ func (h *handler) EnqueueDownlink(appDownlink *types.DownlinkMessage) (err error
 		}
 	}()
 
+	if err = validateDownlinkHints(appDownlink); err != nil {
+		return err
+	}
+
+	if err = validateDownlinkPriority(appDownlink); err != nil {
+		return err
+	}
+
 	// Clear redundant fields
 	appDownlink.AppID = ""
 	appDownlink.DevID = ""
@@ -73,6 +84,14 @@ func (h *handler) EnqueueDownlink(appDownlink *types.DownlinkMessage) (err error
 		return err
 	}
 
+	// A new downlink at the front of the queue was not deferred yet
+	if dev.DownlinkDeferrals != 0 && appDownlink.Schedule != types.ScheduleLast {
+		dev.DownlinkDeferrals = 0
+		if err = h.devices.Set(dev); err != nil {
+			return err
+		}
+	}
+
 	h.mqttEvent <- &types.DeviceEvent{
 		AppID: appID,
 		DevID: devID,
@@ -95,16 +114,23 @@ func (h *handler) HandleDownlink(appDownlink *types.DownlinkMessage, downlink *p
 		"DevEUI": downlink.DevEui,
 	})
 
+	var maxPayloadSize *int
+	var reason types.DownlinkFailureReason
 	defer func() {
 		if err != nil {
+			data := types.DownlinkEventData{
+				ErrorEventData: types.ErrorEventData{Error: err.Error()},
+				Message:        appDownlink,
+				MaxPayloadSize: maxPayloadSize,
+			}
+			if reason != "" {
+				data.Reason, data.Component = reason, "handler"
+			}
 			h.mqttEvent <- &types.DeviceEvent{
 				AppID: appID,
 				DevID: devID,
 				Event: types.DownlinkErrorEvent,
-				Data: types.DownlinkEventData{
-					ErrorEventData: types.ErrorEventData{Error: err.Error()},
-					Message:        appDownlink,
-				},
+				Data:  data,
 			}
 			ctx.WithError(err).Warn("Could not handle downlink")
 		}
@@ -124,6 +150,7 @@ func (h *handler) HandleDownlink(appDownlink *types.DownlinkMessage, downlink *p
 
 	// Get Processors
 	processors := []DownlinkProcessor{
+		h.ApplyAckSettings,
 		h.ConvertFieldsDown,
 		h.ConvertToLoRaWAN,
 	}
@@ -145,10 +172,30 @@ func (h *handler) HandleDownlink(appDownlink *types.DownlinkMessage, downlink *p
 	downlink.Message = nil
 	downlink.UnmarshalPayload()
 
+	if size, sizeErr := checkMaxPayloadSize(downlink); sizeErr != nil {
+		maxPayloadSize, reason = &size, types.DownlinkFailurePayloadTooLarge
+		return sizeErr
+	}
+
+	if err = checkDwellTime(downlink); err != nil {
+		reason = types.DownlinkFailurePayloadTooLarge
+		return err
+	}
+
+	err = h.meterUsage(appID, map[string]int64{
+		MeterDownlinks: 1,
+		MeterAirtime:   int64(downlinkAirtime(downlink) / time.Millisecond),
+	})
+	if err != nil {
+		return err
+	}
+
 	h.status.downlink.Mark(1)
 
 	ctx.Debug("Send Downlink")
 
+	downlink.Priority = string(appDownlink.Priority)
+
 	downlink.Trace = downlink.Trace.WithEvent(trace.ForwardEvent, "broker", h.ttnBrokerID)
 
 	h.downlink <- downlink
@@ -175,10 +222,68 @@ func (h *handler) HandleDownlink(appDownlink *types.DownlinkMessage, downlink *p
 		Data: types.DownlinkEventData{
 			Payload:   downlink.Payload,
 			Message:   appDownlink,
-			GatewayID: downlink.DownlinkOption.GatewayId,
+			GatewayID: h.gatewayID(appDownlink.AppID, downlink.DownlinkOption.GatewayId),
 			Config:    downlinkConfig,
 		},
 	}
 
 	return nil
 }
+
+// checkDwellTime returns an error if the downlink exceeds the dwell time limit
+// of the region that its frequency belongs to
+func checkDwellTime(downlink *pb_broker.DownlinkMessage) error {
+	option := downlink.DownlinkOption
+	if option == nil || option.GatewayConfig == nil {
+		return nil
+	}
+	lorawan := option.ProtocolConfig.GetLorawan()
+	if lorawan == nil || lorawan.Modulation != pb_lorawan.Modulation_LORA {
+		return nil
+	}
+	fp, err := band.Get(band.Guess(option.GatewayConfig.Frequency))
+	if err != nil {
+		return nil // We can't check this region
+	}
+	return fp.CheckDwellTime(lorawan.DataRate, lorawan.CodingRate, len(downlink.Payload))
+}
+
+// checkMaxPayloadSize returns an error and the maximum application payload
+// size if the downlink exceeds the repeater-compatible maximum payload size of
+// its data rate. The MAC commands in FOpts count towards the size.
+func checkMaxPayloadSize(downlink *pb_broker.DownlinkMessage) (int, error) {
+	option := downlink.DownlinkOption
+	if option == nil || option.GatewayConfig == nil {
+		return 0, nil
+	}
+	lorawan := option.ProtocolConfig.GetLorawan()
+	if lorawan == nil || lorawan.Modulation != pb_lorawan.Modulation_LORA {
+		return 0, nil
+	}
+	fp, err := band.Get(band.Guess(option.GatewayConfig.Frequency))
+	if err != nil {
+		return 0, nil // We can't check this region
+	}
+	size, err := fp.GetMaxPayloadSizeFor(lorawan.DataRate, true)
+	if err != nil {
+		return 0, nil // We don't know the maximum for this data rate
+	}
+	// The MACPayload is the PHYPayload without MHDR (1 byte) and MIC (4 bytes)
+	macPayloadSize := len(downlink.Payload) - 5
+	if macPayloadSize <= size.M {
+		return 0, nil
+	}
+	var payloadSize int
+	if macPayload := downlink.GetMessage().GetLorawan().GetMacPayload(); macPayload != nil {
+		payloadSize = len(macPayload.FrmPayload)
+	}
+	// The FHDR (including the FOpts) and FPort also count towards the MACPayload size
+	maxPayloadSize := size.M - (macPayloadSize - payloadSize)
+	if maxPayloadSize < 0 {
+		maxPayloadSize = 0
+	}
+	return maxPayloadSize, errors.NewErrInvalidArgument("Payload", fmt.Sprintf(
+		"%d bytes exceeds the maximum of %d bytes at %s",
+		payloadSize, maxPayloadSize, lorawan.DataRate,
+	))
+}
@@ -0,0 +1,139 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc/metadata"
+)
+
+// publishDownlinkFailure publishes a downlink error event with the reason why
+// the downlink message of the device could not be sent
+func (h *handler) publishDownlinkFailure(dev *device.Device, failure types.DownlinkFailure) {
+	h.publishDownlinkError(dev, dev.CurrentDownlink, failure, false)
+}
+
+func (h *handler) publishDownlinkError(dev *device.Device, message *types.DownlinkMessage, failure types.DownlinkFailure, deferred bool) {
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: dev.AppID,
+		DevID: dev.DevID,
+		Event: types.DownlinkErrorEvent,
+		Data: types.DownlinkEventData{
+			ErrorEventData: types.ErrorEventData{Error: failure.Error},
+			Message:        message,
+			GatewayID:      h.gatewayID(dev.AppID, failure.GatewayID),
+			Reason:         failure.Reason,
+			Component:      failure.Component,
+			Deferred:       deferred,
+		},
+	}
+}
+
+// handleDownlinkFailure publishes a downlink failure that was reported by a
+// Broker or Router. Downlinks that exceeded the airtime budget of the
+// application are deferred to the next uplink, and preempted downlinks are
+// rescheduled.
+func (h *handler) handleDownlinkFailure(failure types.DownlinkFailure) error {
+	if failure.Reason == "" {
+		return errors.NewErrInvalidArgument("Reason", "can not be empty")
+	}
+	dev, err := h.devices.Get(failure.AppID, failure.DevID)
+	if err != nil {
+		return err
+	}
+	h.Ctx.WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).WithField("Reason", failure.Reason).WithField("Component", failure.Component).Warn("Downlink failed")
+	if failure.Reason == types.DownlinkFailureAirtimeBudget && dev.CurrentDownlink != nil {
+		return h.deferFailedDownlink(dev, failure)
+	}
+	if failure.Reason == types.DownlinkFailurePreempted && dev.CurrentDownlink != nil {
+		return h.reschedulePreemptedDownlink(dev, failure)
+	}
+	h.publishDownlinkFailure(dev, failure)
+	return nil
+}
+
+// deferFailedDownlink puts the current downlink of the device back at the
+// front of the queue and publishes the failure
+func (h *handler) deferFailedDownlink(dev *device.Device, failure types.DownlinkFailure) error {
+	queue, err := h.devices.DownlinkQueue(dev.AppID, dev.DevID)
+	if err != nil {
+		return err
+	}
+	downlink := dev.CurrentDownlink
+	if err := queue.PushFirst(downlink); err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	dev.CurrentDownlink = nil
+	if err := h.devices.Set(dev); err != nil {
+		return err
+	}
+	h.publishDownlinkError(dev, downlink, failure, true)
+	return nil
+}
+
+// checkNoDownlink publishes a downlink failure if the device has a downlink
+// message, but the Broker reported that no gateway can send a downlink in
+// response to the uplink
+func (h *handler) checkNoDownlink(uplink *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) error {
+	if !uplink.NoDownlink {
+		return nil
+	}
+	if dev.CurrentDownlink == nil {
+		queue, err := h.devices.DownlinkQueue(dev.AppID, dev.DevID)
+		if err != nil {
+			return err
+		}
+		length, err := queue.Length()
+		if err != nil || length == 0 {
+			return err
+		}
+	}
+	h.publishDownlinkFailure(dev, types.DownlinkFailure{
+		Reason:    types.DownlinkFailureNoGateway,
+		Error:     "No gateway can send a downlink in response to the last uplink",
+		Component: "broker",
+	})
+	return nil
+}
+
+// componentContext returns a context with the Bearer token, ID and service
+// name of the component that sent the request
+func (h *httpHandler) componentContext(req *http.Request) context.Context {
+	var token string
+	if authorization := req.Header.Get("authorization"); len(authorization) >= 7 && strings.ToLower(authorization[0:7]) == "bearer " {
+		token = authorization[7:]
+	}
+	return metadata.NewContext(context.Background(), metadata.Pairs(
+		"token", token,
+		"id", req.Header.Get("Grpc-Metadata-Id"),
+		"service-name", req.Header.Get("Grpc-Metadata-Service-Name"),
+	))
+}
+
+// downlinkFailure accepts a downlink failure of a Broker or Router
+func (h *httpHandler) downlinkFailure(req *http.Request) error {
+	component, err := h.manager.handler.ValidateNetworkContext(h.componentContext(req))
+	if err != nil {
+		return errors.NewErrPermissionDenied(err.Error())
+	}
+	if component.ServiceName != "broker" && component.ServiceName != "router" {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("%s can not report downlink failures", component.ServiceName))
+	}
+	var failure types.DownlinkFailure
+	if err := json.NewDecoder(req.Body).Decode(&failure); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	failure.Component = component.ServiceName
+	return h.manager.handler.handleDownlinkFailure(failure)
+}
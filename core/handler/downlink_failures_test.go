@@ -0,0 +1,103 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestHandleDownlinkFailure(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleDownlinkFailure")},
+		devices:   device.NewMemoryDeviceStore(),
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev", CurrentDownlink: &types.DownlinkMessage{FPort: 1}})
+
+	err := h.handleDownlinkFailure(types.DownlinkFailure{AppID: "app", DevID: "dev"})
+	a.So(err, ShouldNotBeNil)
+
+	err = h.handleDownlinkFailure(types.DownlinkFailure{AppID: "app", DevID: "unknown", Reason: types.DownlinkFailureTooLate})
+	a.So(err, ShouldNotBeNil)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	err = h.handleDownlinkFailure(types.DownlinkFailure{
+		AppID:     "app",
+		DevID:     "dev",
+		Reason:    types.DownlinkFailureDutyCycle,
+		Error:     "duty cycle exceeded",
+		Component: "router",
+		GatewayID: "gtw",
+	})
+	a.So(err, ShouldBeNil)
+
+	event := <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.DownlinkErrorEvent)
+	data := event.Data.(types.DownlinkEventData)
+	a.So(data.Reason, ShouldEqual, types.DownlinkFailureDutyCycle)
+	a.So(data.Component, ShouldEqual, "router")
+	a.So(data.GatewayID, ShouldEqual, "gtw")
+	a.So(data.Error, ShouldEqual, "duty cycle exceeded")
+	a.So(data.Message.FPort, ShouldEqual, 1)
+	a.So(data.Deferred, ShouldBeFalse)
+
+	// Downlinks that exceed the airtime budget of the application are deferred
+	err = h.handleDownlinkFailure(types.DownlinkFailure{
+		AppID:     "app",
+		DevID:     "dev",
+		Reason:    types.DownlinkFailureAirtimeBudget,
+		Error:     "airtime budget exceeded",
+		Component: "router",
+		GatewayID: "gtw",
+	})
+	a.So(err, ShouldBeNil)
+
+	event = <-h.mqttEvent
+	data = event.Data.(types.DownlinkEventData)
+	a.So(data.Reason, ShouldEqual, types.DownlinkFailureAirtimeBudget)
+	a.So(data.Deferred, ShouldBeTrue)
+	a.So(data.Message.FPort, ShouldEqual, 1)
+
+	dev, _ := h.devices.Get("app", "dev")
+	a.So(dev.CurrentDownlink, ShouldBeNil)
+	queue, _ := h.devices.DownlinkQueue("app", "dev")
+	next, _ := queue.Next()
+	a.So(next.FPort, ShouldEqual, 1)
+}
+
+func TestCheckNoDownlink(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		devices:   device.NewMemoryDeviceStore(),
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+	dev := &device.Device{AppID: "app", DevID: "dev"}
+
+	// Without the Broker reporting it nothing happens
+	uplink := &pb_broker.DeduplicatedUplinkMessage{}
+	a.So(h.checkNoDownlink(uplink, dev), ShouldBeNil)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	// Without pending downlink nothing happens
+	uplink.NoDownlink = true
+	a.So(h.checkNoDownlink(uplink, dev), ShouldBeNil)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	// A queued downlink can not be sent
+	queue, _ := h.devices.DownlinkQueue("app", "dev")
+	queue.PushLast(&types.DownlinkMessage{FPort: 2})
+	a.So(h.checkNoDownlink(uplink, dev), ShouldBeNil)
+	event := <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.DownlinkErrorEvent)
+	a.So(event.Data.(types.DownlinkEventData).Reason, ShouldEqual, types.DownlinkFailureNoGateway)
+	a.So(event.Data.(types.DownlinkEventData).Component, ShouldEqual, "broker")
+}
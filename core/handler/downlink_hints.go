@@ -0,0 +1,139 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// validateDownlinkHints returns an error if the scheduling hints of a downlink
+// can never be satisfied
+func validateDownlinkHints(downlink *types.DownlinkMessage) error {
+	switch downlink.RXWindow {
+	case "", types.RX1Window, types.RX2Window:
+	default:
+		return errors.NewErrInvalidArgument("RX Window", fmt.Sprintf("must be %s or %s", types.RX1Window, types.RX2Window))
+	}
+	if downlink.Time != nil {
+		// Devices are scheduled in Class A, so we can only transmit in the RX windows after an uplink
+		return errors.NewErrInvalidArgument("Time", "absolute transmission times require Class B or Class C, which is not supported")
+	}
+	return nil
+}
+
+// downlinkWindow returns the RX window of the downlink option that was
+// selected for a response to the uplink, or an empty string if it can not be
+// determined
+func downlinkWindow(uplink *pb_broker.DeduplicatedUplinkMessage, option *pb_broker.DownlinkOption) types.RXWindow {
+	if option == nil || option.GatewayConfig == nil {
+		return ""
+	}
+	for _, gateway := range uplink.GatewayMetadata {
+		if gateway.GatewayId != option.GatewayId {
+			continue
+		}
+		fp, err := band.Get(band.Guess(gateway.Frequency))
+		if err != nil {
+			return ""
+		}
+		delay := option.GatewayConfig.Timestamp - gateway.Timestamp // Overflows are fine here
+		if delay >= uint32(fp.ReceiveDelay2/1000) {
+			return types.RX2Window
+		}
+		return types.RX1Window
+	}
+	return ""
+}
+
+// checkDownlinkHints returns an error if the scheduling hints of a downlink
+// can not be satisfied by the downlink option of the uplink
+func checkDownlinkHints(downlink *types.DownlinkMessage, uplink *pb_broker.DeduplicatedUplinkMessage, option *pb_broker.DownlinkOption) error {
+	if downlink.GatewayID != "" && downlink.GatewayID != option.GatewayId {
+		return errors.NewErrInvalidArgument("Gateway ID", fmt.Sprintf("gateway %s can not send the response to this uplink", downlink.GatewayID))
+	}
+	if downlink.RXWindow != "" {
+		if window := downlinkWindow(uplink, option); window != downlink.RXWindow {
+			return errors.NewErrInvalidArgument("RX Window", fmt.Sprintf("%s can not be used for the response to this uplink", downlink.RXWindow))
+		}
+	}
+	return nil
+}
+
+// selectDownlinkOption selects a downlink option of the uplink that satisfies
+// the scheduling hints of the downlink for the response, preferring the option
+// that was selected by the Broker. It returns an error if no option satisfies
+// the hints. If trustedOnly is set, only options of trusted gateways are
+// selected.
+func selectDownlinkOption(downlink *types.DownlinkMessage, uplink *pb_broker.DeduplicatedUplinkMessage, trustedOnly bool) error {
+	selected := uplink.GetResponseTemplate().GetDownlinkOption()
+	if selected == nil {
+		return nil
+	}
+	err := checkDownlinkHints(downlink, uplink, selected)
+	if err == nil {
+		return nil
+	}
+	for _, option := range uplink.DownlinkOptions {
+		if option.Identifier == selected.Identifier || (trustedOnly && !gatewayTrusted(uplink, option.GatewayId)) {
+			continue
+		}
+		if checkDownlinkHints(downlink, uplink, option) != nil {
+			continue
+		}
+		// The NetworkServer only set the frame counter in the option that was selected by the Broker
+		if lorawan := option.GetProtocolConfig().GetLorawan(); lorawan != nil {
+			lorawan.FCnt = selected.GetProtocolConfig().GetLorawan().GetFCnt()
+		}
+		uplink.ResponseTemplate.DownlinkOption = option
+		return nil
+	}
+	return err
+}
+
+// MaxDownlinkDeferrals is the number of uplinks after which a downlink that
+// could not be sent in the response to any of them is dropped
+const MaxDownlinkDeferrals = 10
+
+// deferDownlink puts the downlink back at the front of the queue when it can
+// not be sent in the response to the uplink, or drops it if it was deferred
+// too often. The caller stores the device.
+func (h *handler) deferDownlink(dev *device.Device, downlink *types.DownlinkMessage, reason error) error {
+	dev.DownlinkDeferrals++
+	if dev.DownlinkDeferrals > MaxDownlinkDeferrals {
+		dev.DownlinkDeferrals = 0
+		h.mqttEvent <- &types.DeviceEvent{
+			AppID: dev.AppID,
+			DevID: dev.DevID,
+			Event: types.DownlinkErrorEvent,
+			Data: types.DownlinkEventData{
+				ErrorEventData: types.ErrorEventData{Error: fmt.Sprintf("Downlink dropped after %d uplinks: %s", MaxDownlinkDeferrals, reason)},
+				Message:        downlink,
+			},
+		}
+		return nil
+	}
+	queue, err := h.devices.DownlinkQueue(dev.AppID, dev.DevID)
+	if err != nil {
+		return err
+	}
+	if err := queue.PushFirst(downlink); err != nil {
+		return err
+	}
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: dev.AppID,
+		DevID: dev.DevID,
+		Event: types.DownlinkErrorEvent,
+		Data: types.DownlinkEventData{
+			ErrorEventData: types.ErrorEventData{Error: fmt.Sprintf("Downlink deferred to next uplink: %s", reason)},
+			Message:        downlink,
+		},
+	}
+	return nil
+}
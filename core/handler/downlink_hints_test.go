@@ -0,0 +1,159 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateDownlinkHints(t *testing.T) {
+	a := New(t)
+	a.So(validateDownlinkHints(&types.DownlinkMessage{}), ShouldBeNil)
+	a.So(validateDownlinkHints(&types.DownlinkMessage{RXWindow: types.RX1Window}), ShouldBeNil)
+	a.So(validateDownlinkHints(&types.DownlinkMessage{RXWindow: types.RX2Window, GatewayID: "gtw"}), ShouldBeNil)
+	a.So(validateDownlinkHints(&types.DownlinkMessage{RXWindow: "rx3"}), ShouldNotBeNil)
+	a.So(validateDownlinkHints(&types.DownlinkMessage{Time: &types.JSONTime{}}), ShouldNotBeNil)
+}
+
+func buildHintsUplink(gatewayID string, delay uint32) *pb_broker.DeduplicatedUplinkMessage {
+	return &pb_broker.DeduplicatedUplinkMessage{
+		GatewayMetadata: []*pb_gateway.RxMetadata{
+			{GatewayId: "gtw-1", Timestamp: 4294000000, Frequency: 868100000},
+			{GatewayId: "gtw-2", Timestamp: 1000, Frequency: 868100000},
+		},
+		ResponseTemplate: &pb_broker.DownlinkMessage{
+			DownlinkOption: &pb_broker.DownlinkOption{
+				GatewayId:     gatewayID,
+				GatewayConfig: &pb_gateway.TxConfiguration{Timestamp: map[string]uint32{"gtw-1": 4294000000, "gtw-2": 1000}[gatewayID] + delay},
+			},
+		},
+	}
+}
+
+func TestDownlinkWindow(t *testing.T) {
+	a := New(t)
+
+	rx1 := buildHintsUplink("gtw-2", 1000000)
+	a.So(downlinkWindow(rx1, rx1.ResponseTemplate.DownlinkOption), ShouldEqual, types.RX1Window)
+
+	rx2 := buildHintsUplink("gtw-2", 2000000)
+	a.So(downlinkWindow(rx2, rx2.ResponseTemplate.DownlinkOption), ShouldEqual, types.RX2Window)
+
+	// Timestamp rollover
+	rollover := buildHintsUplink("gtw-1", 2000000)
+	a.So(downlinkWindow(rollover, rollover.ResponseTemplate.DownlinkOption), ShouldEqual, types.RX2Window)
+
+	unknown := buildHintsUplink("gtw-3", 1000000)
+	a.So(downlinkWindow(unknown, unknown.ResponseTemplate.DownlinkOption), ShouldBeEmpty)
+
+	a.So(downlinkWindow(rx1, nil), ShouldBeEmpty)
+}
+
+func TestCheckDownlinkHints(t *testing.T) {
+	a := New(t)
+
+	rx1 := buildHintsUplink("gtw-2", 1000000)
+	option := rx1.ResponseTemplate.DownlinkOption
+	a.So(checkDownlinkHints(&types.DownlinkMessage{}, rx1, option), ShouldBeNil)
+	a.So(checkDownlinkHints(&types.DownlinkMessage{GatewayID: "gtw-2", RXWindow: types.RX1Window}, rx1, option), ShouldBeNil)
+	a.So(checkDownlinkHints(&types.DownlinkMessage{GatewayID: "gtw-1"}, rx1, option), ShouldNotBeNil)
+	a.So(checkDownlinkHints(&types.DownlinkMessage{RXWindow: types.RX2Window}, rx1, option), ShouldNotBeNil)
+}
+
+func TestSelectDownlinkOption(t *testing.T) {
+	a := New(t)
+
+	// Without downlink option there is nothing to select
+	a.So(selectDownlinkOption(&types.DownlinkMessage{GatewayID: "gtw-1"}, &pb_broker.DeduplicatedUplinkMessage{}, false), ShouldBeNil)
+
+	buildUplink := func() *pb_broker.DeduplicatedUplinkMessage {
+		uplink := buildHintsUplink("gtw-2", 1000000)
+		selected := uplink.ResponseTemplate.DownlinkOption
+		selected.Identifier = "gtw-2-rx1"
+		selected.ProtocolConfig = &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{FCnt: 42}}}
+		uplink.GatewayMetadata[1].GatewayTrusted = true
+		uplink.DownlinkOptions = []*pb_broker.DownlinkOption{
+			selected,
+			{
+				Identifier:     "gtw-2-rx2",
+				GatewayId:      "gtw-2",
+				GatewayConfig:  &pb_gateway.TxConfiguration{Timestamp: 1000 + 2000000},
+				ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{}}},
+			},
+			{
+				Identifier:     "gtw-1-rx1",
+				GatewayId:      "gtw-1",
+				GatewayConfig:  &pb_gateway.TxConfiguration{Timestamp: 4294000000 + 500000},
+				ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{}}},
+			},
+		}
+		return uplink
+	}
+
+	// The option of the Broker is kept if it satisfies the hints
+	uplink := buildUplink()
+	a.So(selectDownlinkOption(&types.DownlinkMessage{RXWindow: types.RX1Window}, uplink, false), ShouldBeNil)
+	a.So(uplink.ResponseTemplate.DownlinkOption.Identifier, ShouldEqual, "gtw-2-rx1")
+
+	// Another option is selected if it satisfies the hints
+	uplink = buildUplink()
+	a.So(selectDownlinkOption(&types.DownlinkMessage{RXWindow: types.RX2Window}, uplink, false), ShouldBeNil)
+	a.So(uplink.ResponseTemplate.DownlinkOption.Identifier, ShouldEqual, "gtw-2-rx2")
+	a.So(uplink.ResponseTemplate.DownlinkOption.GetProtocolConfig().GetLorawan().FCnt, ShouldEqual, 42)
+
+	uplink = buildUplink()
+	a.So(selectDownlinkOption(&types.DownlinkMessage{GatewayID: "gtw-1"}, uplink, false), ShouldBeNil)
+	a.So(uplink.ResponseTemplate.DownlinkOption.Identifier, ShouldEqual, "gtw-1-rx1")
+
+	// Options of untrusted gateways are not selected if the application only uses trusted gateways
+	uplink = buildUplink()
+	a.So(selectDownlinkOption(&types.DownlinkMessage{GatewayID: "gtw-1"}, uplink, true), ShouldNotBeNil)
+	a.So(uplink.ResponseTemplate.DownlinkOption.Identifier, ShouldEqual, "gtw-2-rx1")
+
+	uplink = buildUplink()
+	a.So(selectDownlinkOption(&types.DownlinkMessage{GatewayID: "gtw-3"}, uplink, false), ShouldNotBeNil)
+}
+
+func TestDeferDownlink(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		devices:   device.NewMemoryDeviceStore(),
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+	dev := &device.Device{AppID: "app", DevID: "dev"}
+
+	queue, _ := h.devices.DownlinkQueue("app", "dev")
+	queue.PushLast(&types.DownlinkMessage{FPort: 2})
+
+	err := h.deferDownlink(dev, &types.DownlinkMessage{FPort: 1, GatewayID: "gtw-1"}, errors.New("gateway gtw-1 was not selected"))
+	a.So(err, ShouldBeNil)
+	a.So(dev.DownlinkDeferrals, ShouldEqual, 1)
+
+	next, _ := queue.Next()
+	a.So(next.FPort, ShouldEqual, 1)
+	a.So(next.GatewayID, ShouldEqual, "gtw-1")
+
+	event := <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.DownlinkErrorEvent)
+	a.So(event.Data.(types.DownlinkEventData).Error, ShouldContainSubstring, "gtw-1")
+
+	// The downlink is dropped after the maximum number of deferrals
+	dev.DownlinkDeferrals = MaxDownlinkDeferrals
+	err = h.deferDownlink(dev, &types.DownlinkMessage{FPort: 1, GatewayID: "gtw-1"}, errors.New("gateway gtw-1 was not selected"))
+	a.So(err, ShouldBeNil)
+	a.So(dev.DownlinkDeferrals, ShouldEqual, 0)
+	next, _ = queue.Next()
+	a.So(next.FPort, ShouldEqual, 2)
+	event = <-h.mqttEvent
+	a.So(event.Data.(types.DownlinkEventData).Error, ShouldContainSubstring, "dropped")
+}
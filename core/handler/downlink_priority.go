@@ -0,0 +1,54 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// validateDownlinkPriority returns an error if the priority of a downlink can
+// not be set by applications
+func validateDownlinkPriority(downlink *types.DownlinkMessage) error {
+	switch downlink.Priority {
+	case "", types.DownlinkPriorityLow, types.DownlinkPriorityNormal, types.DownlinkPriorityHigh:
+		return nil
+	case types.DownlinkPriorityMAC:
+		return errors.NewErrInvalidArgument("Priority", fmt.Sprintf("%s is reserved for the network", types.DownlinkPriorityMAC))
+	default:
+		return errors.NewErrInvalidArgument("Priority", fmt.Sprintf("must be %s, %s or %s", types.DownlinkPriorityLow, types.DownlinkPriorityNormal, types.DownlinkPriorityHigh))
+	}
+}
+
+// reschedulePreemptedDownlink puts the current downlink of the device back at
+// the front of the queue when a Router reports that it was preempted by a
+// downlink with a higher priority
+func (h *handler) reschedulePreemptedDownlink(dev *device.Device, failure types.DownlinkFailure) error {
+	queue, err := h.devices.DownlinkQueue(dev.AppID, dev.DevID)
+	if err != nil {
+		return err
+	}
+	downlink := dev.CurrentDownlink
+	if err := queue.PushFirst(downlink); err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	dev.CurrentDownlink = nil
+	if err := h.devices.Set(dev); err != nil {
+		return err
+	}
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: dev.AppID,
+		DevID: dev.DevID,
+		Event: types.DownlinkRescheduledEvent,
+		Data: types.DownlinkRescheduledEventData{
+			Message:     downlink,
+			PreemptedBy: failure.PreemptedBy,
+		},
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateDownlinkPriority(t *testing.T) {
+	a := New(t)
+	a.So(validateDownlinkPriority(&types.DownlinkMessage{}), ShouldBeNil)
+	a.So(validateDownlinkPriority(&types.DownlinkMessage{Priority: types.DownlinkPriorityLow}), ShouldBeNil)
+	a.So(validateDownlinkPriority(&types.DownlinkMessage{Priority: types.DownlinkPriorityNormal}), ShouldBeNil)
+	a.So(validateDownlinkPriority(&types.DownlinkMessage{Priority: types.DownlinkPriorityHigh}), ShouldBeNil)
+	a.So(validateDownlinkPriority(&types.DownlinkMessage{Priority: types.DownlinkPriorityMAC}), ShouldNotBeNil)
+	a.So(validateDownlinkPriority(&types.DownlinkMessage{Priority: "urgent"}), ShouldNotBeNil)
+}
+
+func TestReschedulePreemptedDownlink(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestReschedulePreemptedDownlink")},
+		devices:   device.NewMemoryDeviceStore(),
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev", CurrentDownlink: &types.DownlinkMessage{FPort: 1, Priority: types.DownlinkPriorityLow}})
+	queue, _ := h.devices.DownlinkQueue("app", "dev")
+	queue.PushLast(&types.DownlinkMessage{FPort: 2})
+
+	// The preempted downlink goes back to the front of the queue
+	err := h.handleDownlinkFailure(types.DownlinkFailure{
+		AppID:       "app",
+		DevID:       "dev",
+		Reason:      types.DownlinkFailurePreempted,
+		PreemptedBy: types.DownlinkPriorityMAC,
+	})
+	a.So(err, ShouldBeNil)
+
+	dev, _ := h.devices.Get("app", "dev")
+	a.So(dev.CurrentDownlink, ShouldBeNil)
+
+	next, _ := queue.Next()
+	a.So(next.FPort, ShouldEqual, 1)
+
+	event := <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.DownlinkRescheduledEvent)
+	a.So(event.Data.(types.DownlinkRescheduledEventData).PreemptedBy, ShouldEqual, types.DownlinkPriorityMAC)
+
+	// Without current downlink the preemption is published as failure
+	err = h.handleDownlinkFailure(types.DownlinkFailure{
+		AppID:       "app",
+		DevID:       "dev",
+		Reason:      types.DownlinkFailurePreempted,
+		PreemptedBy: types.DownlinkPriorityMAC,
+	})
+	a.So(err, ShouldBeNil)
+	event = <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.DownlinkErrorEvent)
+}
@@ -0,0 +1,56 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// DownlinkProfileResponse is returned and accepted by the downlink profile endpoint of the HTTP API
+type DownlinkProfileResponse struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id"`
+	types.DownlinkProfile
+}
+
+func (h *httpHandler) getDownlinkProfile(req *http.Request, appID, devID string) (*DownlinkProfileResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	return &DownlinkProfileResponse{AppID: dev.AppID, DevID: dev.DevID, DownlinkProfile: dev.DownlinkProfile}, nil
+}
+
+// setDownlinkProfile sets the downlink profile of the device, which applies
+// to the next downlink message
+func (h *httpHandler) setDownlinkProfile(req *http.Request, appID, devID string) (*DownlinkProfileResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	var in DownlinkProfileResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := in.DownlinkProfile.Validate(); err != nil {
+		return nil, errors.NewErrInvalidArgument("Downlink Profile", err.Error())
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	dev.StartUpdate()
+	dev.DownlinkProfile = in.DownlinkProfile
+	if err := h.manager.handler.devices.Set(dev); err != nil {
+		return nil, err
+	}
+	return &DownlinkProfileResponse{AppID: dev.AppID, DevID: dev.DevID, DownlinkProfile: dev.DownlinkProfile}, nil
+}
@@ -0,0 +1,30 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestDownlinkProfileRXSettings(t *testing.T) {
+	a := New(t)
+
+	// RX2 only
+	settings := downlinkRXSettings(types.JoinAcceptSettings{}, types.DownlinkProfile{RX2Only: true})
+	a.So(settings, ShouldNotBeNil)
+	a.So(settings.Rx2Only, ShouldBeTrue)
+	a.So(settings.Rx2DataRate, ShouldBeEmpty)
+
+	// The data rate of the profile
+	settings = downlinkRXSettings(types.JoinAcceptSettings{}, types.DownlinkProfile{RX2Only: true, RX2DataRate: "SF12BW125"})
+	a.So(settings.Rx2DataRate, ShouldEqual, "SF12BW125")
+
+	// The data rate of the last join-accept takes precedence
+	settings = downlinkRXSettings(types.JoinAcceptSettings{RX2DataRate: "SF10BW125"}, types.DownlinkProfile{RX2DataRate: "SF12BW125"})
+	a.So(settings.Rx2DataRate, ShouldEqual, "SF10BW125")
+	a.So(settings.Rx2Only, ShouldBeFalse)
+}
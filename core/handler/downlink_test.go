@@ -8,6 +8,9 @@ import (
 	"time"
 
 	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/core/component"
 	"github.com/TheThingsNetwork/ttn/core/handler/application"
 	"github.com/TheThingsNetwork/ttn/core/handler/device"
@@ -193,3 +196,57 @@ func TestHandleDownlink(t *testing.T) {
 	a.So(err, ShouldBeNil)
 	wg.WaitFor(100 * time.Millisecond)
 }
+
+func TestCheckMaxPayloadSize(t *testing.T) {
+	a := New(t)
+
+	buildDownlink := func(dataRate string, payloadSize int) *pb_broker.DownlinkMessage {
+		return &pb_broker.DownlinkMessage{
+			Payload: make([]byte, 1+7+1+payloadSize+4),
+			Message: &pb_protocol.Message{Protocol: &pb_protocol.Message_Lorawan{Lorawan: &pb_lorawan.Message{
+				Payload: &pb_lorawan.Message_MacPayload{MacPayload: &pb_lorawan.MACPayload{
+					FPort:      1,
+					FrmPayload: make([]byte, payloadSize),
+				}},
+			}}},
+			DownlinkOption: &pb_broker.DownlinkOption{
+				GatewayConfig: &pb_gateway.TxConfiguration{Frequency: 868100000},
+				ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{
+					Lorawan: &pb_lorawan.TxConfiguration{Modulation: pb_lorawan.Modulation_LORA, DataRate: dataRate, CodingRate: "4/5"},
+				}},
+			},
+		}
+	}
+
+	maxPayloadSize, err := checkMaxPayloadSize(buildDownlink("SF12BW125", 51))
+	a.So(err, ShouldBeNil)
+	a.So(maxPayloadSize, ShouldEqual, 0)
+
+	maxPayloadSize, err = checkMaxPayloadSize(buildDownlink("SF12BW125", 52))
+	a.So(err, ShouldNotBeNil)
+	a.So(maxPayloadSize, ShouldEqual, 51)
+
+	_, err = checkMaxPayloadSize(buildDownlink("SF7BW125", 222))
+	a.So(err, ShouldBeNil)
+
+	maxPayloadSize, err = checkMaxPayloadSize(buildDownlink("SF7BW125", 242))
+	a.So(err, ShouldNotBeNil)
+	a.So(maxPayloadSize, ShouldEqual, 222)
+
+	// The MAC commands in FOpts count towards the size
+	downlink := buildDownlink("SF12BW125", 47)
+	downlink.Payload = append(downlink.Payload, make([]byte, 5)...)
+	maxPayloadSize, err = checkMaxPayloadSize(downlink)
+	a.So(err, ShouldNotBeNil)
+	a.So(maxPayloadSize, ShouldEqual, 46)
+
+	downlink = buildDownlink("SF12BW125", 1)
+	downlink.Payload = append(downlink.Payload, make([]byte, 51)...)
+	maxPayloadSize, err = checkMaxPayloadSize(downlink)
+	a.So(err, ShouldNotBeNil)
+	a.So(maxPayloadSize, ShouldEqual, 0)
+
+	// Without downlink option, the payload size is not checked
+	_, err = checkMaxPayloadSize(&pb_broker.DownlinkMessage{Payload: make([]byte, 300)})
+	a.So(err, ShouldBeNil)
+}
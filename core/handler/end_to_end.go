@@ -0,0 +1,133 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// EndToEndResponse is returned and accepted by the end-to-end encryption endpoint of the HTTP API
+type EndToEndResponse struct {
+	AppID    string `json:"app_id"`
+	DevID    string `json:"dev_id"`
+	EndToEnd bool   `json:"end_to_end"`
+	// AppSKey can be given when end-to-end encryption is disabled, so that the
+	// Handler can decrypt the payload of the current session. It is never returned.
+	AppSKey *types.AppSKey `json:"app_s_key,omitempty"`
+}
+
+// checkEndToEndCounter checks that the payload of a downlink message for a
+// device with end-to-end encryption was encrypted by the application for the
+// frame counter of the downlink
+func checkEndToEndCounter(appDown *types.DownlinkMessage, fCnt uint32) error {
+	if len(appDown.PayloadRaw) == 0 || appDown.FCnt == fCnt {
+		return nil
+	}
+	return errors.NewErrInvalidArgument("Downlink Counter", fmt.Sprintf("payload is encrypted for %d, but the downlink counter is %d", appDown.FCnt, fCnt))
+}
+
+// invalidateEndToEndDownlinks takes the current downlink and the queued
+// downlinks of a device with end-to-end encryption if the current downlink is
+// not encrypted for the downlink counter, for example because the network sent
+// a downlink with MAC commands. The downlinks are published, so that the
+// application can encrypt them again for the counter and schedule them again.
+func (h *handler) invalidateEndToEndDownlinks(dev *device.Device, fCnt uint32) error {
+	if !dev.EndToEnd || dev.CurrentDownlink == nil || checkEndToEndCounter(dev.CurrentDownlink, fCnt) == nil {
+		return nil
+	}
+	queue, err := h.devices.DownlinkQueue(dev.AppID, dev.DevID)
+	if err != nil {
+		return err
+	}
+	invalidated := []*types.DownlinkMessage{dev.CurrentDownlink}
+	for {
+		next, err := queue.Next()
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			break
+		}
+		invalidated = append(invalidated, next)
+	}
+	dev.CurrentDownlink = nil
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: dev.AppID,
+		DevID: dev.DevID,
+		Event: types.DownlinkInvalidatedEvent,
+		Data:  types.DownlinkInvalidatedEventData{Messages: invalidated, FCnt: fCnt},
+	}
+	return nil
+}
+
+// endToEndSession returns the parameters of an activation that the application
+// needs to derive the AppSKey of a device with end-to-end encryption
+func endToEndSession(dev *device.Device, appNonce device.AppNonce, netID [3]byte, devNonce [2]byte) *types.EndToEndSession {
+	if !dev.EndToEnd {
+		return nil
+	}
+	return &types.EndToEndSession{
+		AppNonce: types.AppNonce(appNonce),
+		NetID:    types.NetID(netID),
+		DevNonce: types.DevNonce(devNonce),
+	}
+}
+
+func (h *httpHandler) getEndToEnd(req *http.Request, appID, devID string) (*EndToEndResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	return &EndToEndResponse{AppID: dev.AppID, DevID: dev.DevID, EndToEnd: dev.EndToEnd}, nil
+}
+
+// setEndToEnd enables or disables end-to-end encryption for the device. When
+// it is enabled, the Handler forgets the AppSKey of the device. It can not be
+// enabled for devices of which the Handler has the AppKey, as the Handler
+// could derive the AppSKey. When it is disabled for a device with a session,
+// the AppSKey of the session must be given.
+func (h *httpHandler) setEndToEnd(req *http.Request, appID, devID string) (*EndToEndResponse, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	var in EndToEndResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if in.EndToEnd && in.AppSKey != nil {
+		return nil, errors.NewErrInvalidArgument("AppSKey", "can not be set for devices with end-to-end encryption")
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	if in.EndToEnd && !dev.AppKey.IsEmpty() {
+		return nil, errors.NewErrInvalidArgument("AppKey", "must be removed from the Handler to enable end-to-end encryption")
+	}
+	if !in.EndToEnd && dev.EndToEnd && !dev.DevAddr.IsEmpty() && (in.AppSKey == nil || in.AppSKey.IsEmpty()) {
+		return nil, errors.NewErrInvalidArgument("AppSKey", "of the current session is needed to disable end-to-end encryption")
+	}
+	dev.StartUpdate()
+	dev.EndToEnd = in.EndToEnd
+	switch {
+	case in.EndToEnd:
+		dev.AppSKey = types.AppSKey{}
+	case in.AppSKey != nil:
+		dev.AppSKey = *in.AppSKey
+	}
+	if err := h.manager.handler.devices.Set(dev); err != nil {
+		return nil, err
+	}
+	return &EndToEndResponse{AppID: dev.AppID, DevID: dev.DevID, EndToEnd: dev.EndToEnd}, nil
+}
@@ -0,0 +1,104 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestEndToEndUplink(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestEndToEndUplink")},
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+	dev := &device.Device{DevID: "devid", AppID: "appid", EndToEnd: true}
+	ttnUp, appUp := buildLorawanUplink([]byte{0x40, 0x04, 0x03, 0x02, 0x01, 0x20, 0x01, 0x00, 0x0A, 0x46, 0x55, 0x96, 0x42, 0x92, 0xF2})
+	err := h.ConvertFromLoRaWAN(h.Ctx, ttnUp, appUp, dev)
+	a.So(err, ShouldBeNil)
+	a.So(appUp.PayloadRaw, ShouldResemble, []byte{0x46, 0x55})
+	a.So(appUp.Encrypted, ShouldBeTrue)
+	a.So(appUp.FPort, ShouldEqual, 10)
+
+	// Encrypted payloads are not decoded
+	a.So(h.ConvertFieldsUp(h.Ctx, ttnUp, appUp, dev), ShouldBeNil)
+	a.So(appUp.PayloadFields, ShouldBeNil)
+}
+
+func TestEndToEndDownlink(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestEndToEndDownlink")},
+		devices:   device.NewRedisDeviceStore(GetRedisClient(), "handler-test-end-to-end-downlink"),
+	}
+	dev := &device.Device{DevID: "devid", AppID: "appid", EndToEnd: true}
+
+	// The payload is sent as encrypted by the application
+	appDown, ttnDown := buildLorawanDownlink([]byte{0xaa, 0xbc})
+	appDown.FCnt = 1
+	err := h.ConvertToLoRaWAN(h.Ctx, appDown, ttnDown, dev)
+	a.So(err, ShouldBeNil)
+	a.So(ttnDown.Payload[:11], ShouldResemble, []byte{0x60, 0x04, 0x03, 0x02, 0x01, 0x00, 0x01, 0x00, 0x01, 0xaa, 0xbc})
+
+	// The payload must be encrypted for the downlink counter
+	appDown, ttnDown = buildLorawanDownlink([]byte{0xaa, 0xbc})
+	appDown.FCnt = 2
+	err = h.ConvertToLoRaWAN(h.Ctx, appDown, ttnDown, dev)
+	a.So(err, ShouldNotBeNil)
+
+	// Fields can not be encoded
+	appDown = &types.DownlinkMessage{AppID: "appid", DevID: "devid", PayloadFields: map[string]interface{}{"on": true}}
+	err = h.ConvertFieldsDown(h.Ctx, appDown, ttnDown, dev)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestEndToEndSession(t *testing.T) {
+	a := New(t)
+	dev := &device.Device{}
+	a.So(endToEndSession(dev, device.AppNonce{1, 2, 3}, [3]byte{0, 0, 0x13}, [2]byte{4, 5}), ShouldBeNil)
+
+	dev.EndToEnd = true
+	session := endToEndSession(dev, device.AppNonce{1, 2, 3}, [3]byte{0, 0, 0x13}, [2]byte{4, 5})
+	a.So(session, ShouldNotBeNil)
+	a.So(session.AppNonce, ShouldEqual, types.AppNonce{1, 2, 3})
+	a.So(session.NetID, ShouldEqual, types.NetID{0, 0, 0x13})
+	a.So(session.DevNonce, ShouldEqual, types.DevNonce{4, 5})
+}
+
+func TestInvalidateEndToEndDownlinks(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		devices:   device.NewMemoryDeviceStore(),
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+	dev := &device.Device{AppID: "app", DevID: "dev", EndToEnd: true}
+
+	queue, _ := h.devices.DownlinkQueue("app", "dev")
+	queue.PushLast(&types.DownlinkMessage{PayloadRaw: []byte{0xbb}, FCnt: 6})
+
+	// Downlinks that are encrypted for the downlink counter are sent
+	dev.CurrentDownlink = &types.DownlinkMessage{PayloadRaw: []byte{0xaa}, FCnt: 5}
+	a.So(h.invalidateEndToEndDownlinks(dev, 5), ShouldBeNil)
+	a.So(dev.CurrentDownlink, ShouldNotBeNil)
+	length, _ := queue.Length()
+	a.So(length, ShouldEqual, 1)
+
+	// Otherwise the current and queued downlinks are published to be encrypted again
+	a.So(h.invalidateEndToEndDownlinks(dev, 7), ShouldBeNil)
+	a.So(dev.CurrentDownlink, ShouldBeNil)
+	length, _ = queue.Length()
+	a.So(length, ShouldEqual, 0)
+	event := <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.DownlinkInvalidatedEvent)
+	data := event.Data.(types.DownlinkInvalidatedEventData)
+	a.So(data.FCnt, ShouldEqual, 7)
+	a.So(data.Messages, ShouldHaveLength, 2)
+	a.So(data.Messages[1].FCnt, ShouldEqual, 6)
+}
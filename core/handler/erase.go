@@ -0,0 +1,128 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// DataRetentionInterval is the interval in which the Handler erases the data
+// of devices that exceeded the data retention of their application
+var DataRetentionInterval = time.Hour
+
+func (h *handler) WithNetworkServer(networkServerID string) Handler {
+	h.networkServerID = networkServerID
+	return h
+}
+
+// eraseDeviceData removes the downlink queue and the current downlink of a device
+func (h *handler) eraseDeviceData(dev *device.Device) error {
+	queue, err := h.devices.DownlinkQueue(dev.AppID, dev.DevID)
+	if err != nil {
+		return err
+	}
+	if err := queue.Clear(); err != nil {
+		return err
+	}
+	if dev.CurrentDownlink == nil {
+		return nil
+	}
+	dev.StartUpdate()
+	dev.CurrentDownlink = nil
+	return h.devices.Set(dev)
+}
+
+// networkServerAPIAddress returns the address of the HTTP API of the NetworkServer
+func (h *handler) networkServerAPIAddress() (string, error) {
+	if h.networkServerID == "" {
+		return "", errors.NewErrInternal("No NetworkServer configured")
+	}
+	networkServer, err := h.Discover("networkserver", h.networkServerID)
+	if err != nil {
+		return "", err
+	}
+	if networkServer.ApiAddress == "" {
+		return "", errors.NewErrInternal(fmt.Sprintf("NetworkServer %s does not announce an HTTP API", h.networkServerID))
+	}
+	return strings.TrimSuffix(networkServer.ApiAddress, "/"), nil
+}
+
+// eraseNetworkServerData erases the data that the NetworkServer collected
+// about a device, using the HTTP API of the NetworkServer. The token must
+// have devices rights to the application of the device.
+func (h *handler) eraseNetworkServerData(token string, dev *device.Device) error {
+	apiAddress, err := h.networkServerAPIAddress()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/devices/%s/%s/data", apiAddress, dev.AppEUI, dev.DevEUI)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusNoContent, http.StatusOK, http.StatusNotFound:
+		return nil
+	case http.StatusForbidden:
+		return errors.NewErrPermissionDenied("NetworkServer did not erase device data")
+	default:
+		body, _ := ioutil.ReadAll(res.Body)
+		return errors.NewErrInternal(fmt.Sprintf("NetworkServer did not erase device data: %s %s", res.Status, strings.TrimSpace(string(body))))
+	}
+}
+
+// expireData erases the data of devices that were not active within the data
+// retention of their application
+func (h *handler) expireData(now time.Time) error {
+	apps, err := h.applications.List(nil)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		if app.DataRetention <= 0 {
+			continue
+		}
+		if err := h.expireStreamEvents(app.AppID, now.Add(-1*app.DataRetention)); err != nil {
+			h.Ctx.WithError(err).WithField("AppID", app.AppID).Warn("Could not expire event stream")
+		}
+		devices, err := h.devices.ListForApp(app.AppID, nil)
+		if err != nil {
+			return err
+		}
+		for _, dev := range devices {
+			if dev.UpdatedAt.Add(app.DataRetention).After(now) {
+				continue
+			}
+			if err := h.eraseDeviceData(dev); err != nil {
+				h.Ctx.WithError(err).WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).Warn("Could not erase expired device data")
+			}
+		}
+	}
+	return nil
+}
+
+func (h *handler) handleDataRetention() {
+	go func() {
+		for range time.Tick(DataRetentionInterval) {
+			if err := h.expireData(time.Now()); err != nil {
+				h.Ctx.WithError(err).Warn("Could not expire device data")
+			}
+		}
+	}()
+}
@@ -0,0 +1,76 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestEraseDeviceData(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestEraseDeviceData")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+
+	h.devices.Set(&device.Device{
+		AppID:           "app",
+		DevID:           "dev",
+		CurrentDownlink: &types.DownlinkMessage{PayloadRaw: []byte{0x01}},
+	})
+	queue, _ := h.devices.DownlinkQueue("app", "dev")
+	queue.PushLast(&types.DownlinkMessage{PayloadRaw: []byte{0x02}})
+
+	dev, _ := h.devices.Get("app", "dev")
+	a.So(h.eraseDeviceData(dev), ShouldBeNil)
+
+	dev, err := h.devices.Get("app", "dev")
+	a.So(err, ShouldBeNil)
+	a.So(dev.CurrentDownlink, ShouldBeNil)
+	length, _ := queue.Length()
+	a.So(length, ShouldEqual, 0)
+
+	// The NetworkServer is not configured
+	a.So(h.eraseNetworkServerData("token", dev), ShouldNotBeNil)
+}
+
+func TestExpireData(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestExpireData")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+
+	h.applications.Set(&application.Application{AppID: "retention", DataRetention: time.Hour})
+	h.applications.Set(&application.Application{AppID: "forever"})
+	for _, appID := range []string{"retention", "forever"} {
+		h.devices.Set(&device.Device{
+			AppID:           appID,
+			DevID:           "dev",
+			CurrentDownlink: &types.DownlinkMessage{PayloadRaw: []byte{0x01}},
+		})
+	}
+
+	// Within the retention
+	a.So(h.expireData(time.Now()), ShouldBeNil)
+	dev, _ := h.devices.Get("retention", "dev")
+	a.So(dev.CurrentDownlink, ShouldNotBeNil)
+
+	// After the retention
+	a.So(h.expireData(time.Now().Add(2*time.Hour)), ShouldBeNil)
+	dev, _ = h.devices.Get("retention", "dev")
+	a.So(dev.CurrentDownlink, ShouldBeNil)
+	dev, _ = h.devices.Get("forever", "dev")
+	a.So(dev.CurrentDownlink, ShouldNotBeNil)
+}
@@ -0,0 +1,270 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/golang/protobuf/ptypes/empty"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+// EventStreamLength is the (approximate) number of messages that is kept in the event stream of each application
+var EventStreamLength int64 = 10000
+
+// uplinkStreamEvent is the type of uplink messages in the event stream
+const uplinkStreamEvent = "up"
+
+// StreamEvent is a message in the event stream of an application. Messages
+// that are sent over the live data WebSocket do not have a cursor.
+type StreamEvent struct {
+	Cursor string          `json:"cursor,omitempty"`
+	DevID  string          `json:"dev_id,omitempty"`
+	Event  string          `json:"event"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+func newStreamEvent(entry storage.StreamEntry) StreamEvent {
+	event := StreamEvent{
+		Cursor: entry.ID,
+		DevID:  entry.Values["dev_id"],
+		Event:  entry.Values["event"],
+	}
+	if data := entry.Values["data"]; data != "" {
+		event.Data = json.RawMessage(data)
+	}
+	return event
+}
+
+func newLiveEvent(devID, event string, data interface{}) (*StreamEvent, error) {
+	message := &StreamEvent{
+		DevID: devID,
+		Event: event,
+	}
+	if data != nil {
+		dataBytes, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		message.Data = json.RawMessage(dataBytes)
+	}
+	return message, nil
+}
+
+func newStreamEvents(entries []storage.StreamEntry) []StreamEvent {
+	events := make([]StreamEvent, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, newStreamEvent(entry))
+	}
+	return events
+}
+
+// readStreamEvents reads messages from the event stream of the application.
+// Without a group, the messages after the cursor are returned. With a group,
+// the messages that the group did not return before are returned, or the
+// messages that are pending for the consumer.
+func (h *handler) readStreamEvents(appID, after, group, consumer string, limit int64, pending bool) ([]StreamEvent, error) {
+	if h.eventStream == nil {
+		return nil, errors.NewErrNotFound("Event stream")
+	}
+	if group == "" {
+		entries, err := h.eventStream.Range(appID, after, limit)
+		if err != nil {
+			return nil, errors.NewErrInvalidArgument("Cursor", err.Error())
+		}
+		return newStreamEvents(entries), nil
+	}
+	if consumer == "" {
+		consumer = "default"
+	}
+	if err := h.eventStream.CreateGroup(appID, group, "0"); err != nil {
+		return nil, err
+	}
+	entries, err := h.eventStream.ReadGroup(appID, group, consumer, limit, pending)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamEvents(entries), nil
+}
+
+// ackStreamEvents acknowledges messages that a consumer group read from the
+// event stream of the application
+func (h *handler) ackStreamEvents(appID, group string, cursors ...string) error {
+	if h.eventStream == nil {
+		return errors.NewErrNotFound("Event stream")
+	}
+	return h.eventStream.Ack(appID, group, cursors...)
+}
+
+func (h *handlerManager) GetEvents(ctx context.Context, in *pb.EventsRequest) (*pb.EventsResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Invalid Events Request")
+	}
+	ctx, claims, err := h.validateTTNAuthAppContext(ctx, in.AppId)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.checkAppRights(claims, in.AppId, rights.Devices, false); err != nil {
+		return nil, err
+	}
+	if _, err := h.handler.applications.Get(in.AppId); err != nil {
+		return nil, errors.Wrap(err, "Application not registered to this Handler")
+	}
+	limit := in.Limit
+	if limit == 0 {
+		limit = defaultEventsLimit
+	}
+	if limit > maxEventsLimit {
+		limit = maxEventsLimit
+	}
+	events, err := h.handler.readStreamEvents(in.AppId, in.After, in.Group, in.Consumer, limit, in.Pending)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.EventsResponse{Events: make([]*pb.StreamEvent, 0, len(events))}
+	for _, event := range events {
+		res.Events = append(res.Events, &pb.StreamEvent{
+			Cursor: event.Cursor,
+			DevId:  event.DevID,
+			Event:  event.Event,
+			Data:   string(event.Data),
+		})
+	}
+	return res, nil
+}
+
+func (h *handlerManager) AckEvents(ctx context.Context, in *pb.AckEventsRequest) (*empty.Empty, error) {
+	if err := in.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Invalid Ack Events Request")
+	}
+	ctx, claims, err := h.validateTTNAuthAppContext(ctx, in.AppId)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.checkAppRights(claims, in.AppId, rights.Devices, false); err != nil {
+		return nil, err
+	}
+	if _, err := h.handler.applications.Get(in.AppId); err != nil {
+		return nil, errors.Wrap(err, "Application not registered to this Handler")
+	}
+	if err := h.handler.ackStreamEvents(in.AppId, in.Group, in.Cursors...); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// persistUplink adds an uplink message to the event stream of the application
+func (h *handler) persistUplink(up *types.UplinkMessage) {
+	h.persistStreamEvent(up.AppID, up.DevID, uplinkStreamEvent, up)
+}
+
+// persistEvent adds an event to the event stream of the application
+func (h *handler) persistEvent(event *types.DeviceEvent) {
+	h.persistStreamEvent(event.AppID, event.DevID, string(event.Event), event.Data)
+}
+
+func (h *handler) persistStreamEvent(appID, devID, event string, data interface{}) {
+	if h.eventStream == nil {
+		return
+	}
+	ctx := h.Ctx.WithField("AppID", appID).WithField("DevID", devID).WithField("Event", event)
+	values := map[string]string{
+		"event": event,
+	}
+	if devID != "" {
+		values["dev_id"] = devID
+	}
+	if data != nil {
+		dataBytes, err := json.Marshal(data)
+		if err != nil {
+			ctx.WithError(err).Warn("Could not marshal event for event stream")
+			return
+		}
+		values["data"] = string(dataBytes)
+	}
+	cursor, err := h.eventStream.Add(appID, values)
+	if err != nil {
+		ctx.WithError(err).Warn("Could not add event to event stream")
+		return
+	}
+	if h.mqttClient != nil {
+		token := h.mqttClient.PublishEventCursor(appID, cursor)
+		go func() {
+			if token.WaitTimeout(MQTTTimeout) {
+				if token.Error() != nil {
+					ctx.WithError(token.Error()).Warn("Could not publish event cursor")
+				}
+			} else {
+				ctx.Warn("Event cursor publish timeout")
+			}
+		}()
+	}
+}
+
+// eraseStreamEvents removes the messages of a device from the event stream of its application
+func (h *handler) eraseStreamEvents(appID, devID string) error {
+	if h.eventStream == nil {
+		return nil
+	}
+	var cursor string
+	for {
+		entries, err := h.eventStream.Range(appID, cursor, 1000)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		var ids []string
+		for _, entry := range entries {
+			if entry.Values["dev_id"] == devID {
+				ids = append(ids, entry.ID)
+			}
+		}
+		if err := h.eventStream.DeleteEntries(appID, ids...); err != nil {
+			return err
+		}
+		cursor = entries[len(entries)-1].ID
+	}
+}
+
+// expireStreamEvents removes the messages that were added before the given time from the event stream of the application
+func (h *handler) expireStreamEvents(appID string, before time.Time) error {
+	if h.eventStream == nil {
+		return nil
+	}
+	for {
+		entries, err := h.eventStream.Range(appID, "", 1000)
+		if err != nil {
+			return err
+		}
+		var ids []string
+		for _, entry := range entries {
+			if !streamEventTime(entry.ID).Before(before) {
+				break
+			}
+			ids = append(ids, entry.ID)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := h.eventStream.DeleteEntries(appID, ids...); err != nil {
+			return err
+		}
+	}
+}
+
+// streamEventTime returns the time at which a message was added to the event stream
+func streamEventTime(cursor string) time.Time {
+	ms, _ := strconv.ParseInt(strings.SplitN(cursor, "-", 2)[0], 10, 64)
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
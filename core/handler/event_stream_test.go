@@ -0,0 +1,108 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestStreamEventTime(t *testing.T) {
+	a := New(t)
+	a.So(streamEventTime("1490000000123-4").Equal(time.Unix(1490000000, 123000000)), ShouldBeTrue)
+}
+
+func TestNewStreamEvent(t *testing.T) {
+	a := New(t)
+	event := newStreamEvent(storage.StreamEntry{
+		ID:     "1490000000123-4",
+		Values: map[string]string{"event": "up", "dev_id": "dev", "data": `{"port":1}`},
+	})
+	a.So(event.Cursor, ShouldEqual, "1490000000123-4")
+	a.So(event.DevID, ShouldEqual, "dev")
+	a.So(event.Event, ShouldEqual, "up")
+	a.So(string(event.Data), ShouldEqual, `{"port":1}`)
+
+	event = newStreamEvent(storage.StreamEntry{ID: "1490000000123-5", Values: map[string]string{"event": "create"}})
+	a.So(event.Data, ShouldBeNil)
+}
+
+func TestEventStream(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:   &component.Component{Ctx: GetLogger(t, "TestEventStream")},
+		eventStream: storage.NewRedisStreamStore(GetRedisClient(), "handler-test-event-stream", 100),
+	}
+	defer h.eventStream.Delete("app")
+
+	h.persistUplink(&types.UplinkMessage{AppID: "app", DevID: "dev1", FPort: 1})
+	h.persistEvent(&types.DeviceEvent{AppID: "app", DevID: "dev2", Event: types.ActivationEvent})
+	h.persistEvent(&types.DeviceEvent{AppID: "app", DevID: "dev1", Event: types.UplinkErrorEvent, Data: types.ErrorEventData{Error: "error"}})
+
+	entries, err := h.eventStream.Range("app", "", 0)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldHaveLength, 3)
+	events := newStreamEvents(entries)
+	a.So(events[0].Event, ShouldEqual, "up")
+	a.So(events[0].DevID, ShouldEqual, "dev1")
+	a.So(events[1].Event, ShouldEqual, types.ActivationEvent)
+	a.So(string(events[2].Data), ShouldEqual, `{"error":"error"}`)
+
+	a.So(h.eraseStreamEvents("app", "dev1"), ShouldBeNil)
+	entries, _ = h.eventStream.Range("app", "", 0)
+	a.So(entries, ShouldHaveLength, 1)
+	a.So(entries[0].Values["dev_id"], ShouldEqual, "dev2")
+
+	a.So(h.expireStreamEvents("app", time.Now().Add(-1*time.Hour)), ShouldBeNil)
+	entries, _ = h.eventStream.Range("app", "", 0)
+	a.So(entries, ShouldHaveLength, 1)
+
+	a.So(h.expireStreamEvents("app", time.Now().Add(time.Second)), ShouldBeNil)
+	entries, _ = h.eventStream.Range("app", "", 0)
+	a.So(entries, ShouldBeEmpty)
+}
+
+func TestReadStreamEvents(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestReadStreamEvents")},
+	}
+
+	_, err := h.readStreamEvents("app", "", "", "", 10, false)
+	a.So(err, ShouldNotBeNil)
+
+	h.eventStream = storage.NewRedisStreamStore(GetRedisClient(), "handler-test-read-stream-events", 100)
+	defer h.eventStream.Delete("app")
+
+	h.persistUplink(&types.UplinkMessage{AppID: "app", DevID: "dev", FPort: 1})
+	h.persistUplink(&types.UplinkMessage{AppID: "app", DevID: "dev", FPort: 2})
+
+	events, err := h.readStreamEvents("app", "", "", "", 10, false)
+	a.So(err, ShouldBeNil)
+	a.So(events, ShouldHaveLength, 2)
+
+	// Resume after the cursor of the first message
+	events, err = h.readStreamEvents("app", events[0].Cursor, "", "", 10, false)
+	a.So(err, ShouldBeNil)
+	a.So(events, ShouldHaveLength, 1)
+	a.So(string(events[0].Data), ShouldContainSubstring, `"port":2`)
+
+	// The group returns the messages once and keeps them pending until they are acknowledged
+	events, err = h.readStreamEvents("app", "", "integration", "", 10, false)
+	a.So(err, ShouldBeNil)
+	a.So(events, ShouldHaveLength, 2)
+	events, _ = h.readStreamEvents("app", "", "integration", "", 10, false)
+	a.So(events, ShouldBeEmpty)
+	events, _ = h.readStreamEvents("app", "", "integration", "", 10, true)
+	a.So(events, ShouldHaveLength, 2)
+	a.So(h.ackStreamEvents("app", "integration", events[0].Cursor), ShouldBeNil)
+	events, _ = h.readStreamEvents("app", "", "integration", "", 10, true)
+	a.So(events, ShouldHaveLength, 1)
+}
@@ -0,0 +1,304 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/functions"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+const (
+	defaultFunctionsTestLimit = 100
+	maxFunctionsTestLimit     = 1000
+)
+
+// FunctionsVersionsResponse is returned by the versions endpoint of the HTTP API
+type FunctionsVersionsResponse struct {
+	AppID    string                          `json:"app_id"`
+	Versions []*application.FunctionsVersion `json:"versions"`
+}
+
+// FunctionsTestRequest is accepted by the test endpoint of the HTTP API. The
+// candidate is either a stored version or the given payload functions.
+type FunctionsTestRequest struct {
+	Version   int    `json:"version,omitempty"`
+	Decoder   string `json:"decoder,omitempty"`
+	Converter string `json:"converter,omitempty"`
+	Validator string `json:"validator,omitempty"`
+	DevID     string `json:"dev_id,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+// FunctionsTestResult is the result of the candidate for an uplink message
+// that it handles differently than the payload functions that were used
+type FunctionsTestResult struct {
+	Cursor          string                 `json:"cursor"`
+	DevID           string                 `json:"dev_id"`
+	Port            uint8                  `json:"port"`
+	Counter         uint32                 `json:"counter"`
+	PayloadRaw      []byte                 `json:"payload_raw"`
+	Fields          map[string]interface{} `json:"payload_fields,omitempty"`
+	CandidateFields map[string]interface{} `json:"candidate_fields,omitempty"`
+	Changed         []string               `json:"changed,omitempty"`
+	Valid           bool                   `json:"valid"`
+	Error           string                 `json:"error,omitempty"`
+	Logs            []*pb.LogEntry         `json:"logs,omitempty"`
+}
+
+// FunctionsTestResponse is returned by the test endpoint of the HTTP API
+type FunctionsTestResponse struct {
+	AppID   string                 `json:"app_id"`
+	Tested  int                    `json:"tested"`
+	Changed int                    `json:"changed"`
+	Errors  int                    `json:"errors"`
+	Results []*FunctionsTestResult `json:"results"`
+}
+
+// FunctionsRollbackRequest is accepted by the rollback endpoint of the HTTP API
+type FunctionsRollbackRequest struct {
+	Version int `json:"version"`
+}
+
+// recordFunctions adds the payload functions of the application to its
+// history if they changed since the latest version
+func (h *handler) recordFunctions(app *application.Application, author string) error {
+	if h.functions == nil {
+		return nil
+	}
+	version := application.NewFunctionsVersion(app, author)
+	versions, err := h.functions.List(app.AppID)
+	if err != nil {
+		return err
+	}
+	if len(versions) > 0 && version.Equals(versions[0]) {
+		return nil
+	}
+	return h.functions.Add(app.AppID, version)
+}
+
+// recentUplinks returns at most count uplink messages from the event stream
+// of the application, newest first
+func (h *handler) recentUplinks(appID, devID string, count int) ([]storedUplink, error) {
+	var uplinks []storedUplink
+	var cursor string
+	for len(uplinks) < count {
+		entries, err := h.eventStream.RevRange(appID, cursor, 1000)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		for _, entry := range entries {
+			if entry.Values["event"] != uplinkStreamEvent || (devID != "" && entry.Values["dev_id"] != devID) {
+				continue
+			}
+			var up types.UplinkMessage
+			if err := json.Unmarshal([]byte(entry.Values["data"]), &up); err != nil {
+				continue
+			}
+			uplinks = append(uplinks, storedUplink{cursor: entry.ID, UplinkMessage: &up})
+			if len(uplinks) == count {
+				break
+			}
+		}
+		cursor = entries[len(entries)-1].ID
+	}
+	return uplinks, nil
+}
+
+type storedUplink struct {
+	cursor string
+	*types.UplinkMessage
+}
+
+// runFunctionsTest runs the candidate functions on the uplink message and
+// returns the result, or nil if the result equals the stored fields
+func runFunctionsTest(candidate *UplinkFunctions, up storedUplink) *FunctionsTestResult {
+	logger := functions.NewEntryLogger()
+	f := *candidate
+	f.Logger = logger
+	result := &FunctionsTestResult{
+		Cursor:     up.cursor,
+		DevID:      up.DevID,
+		Port:       up.FPort,
+		Counter:    up.FCnt,
+		PayloadRaw: up.PayloadRaw,
+		Fields:     up.PayloadFields,
+	}
+	fields, valid, err := f.Process(up.PayloadRaw, up.FPort)
+	result.Logs = logger.Logs
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Valid = valid
+	// Use the same representation as the fields that were stored in the event stream
+	if fields != nil {
+		data, err := json.Marshal(fields)
+		if err == nil {
+			err = json.Unmarshal(data, &result.CandidateFields)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+	result.Changed = diffFields(result.Fields, result.CandidateFields)
+	if valid && len(result.Changed) == 0 {
+		return nil
+	}
+	return result
+}
+
+// diffFields returns the sorted names of the top-level fields that differ
+func diffFields(fields, candidate map[string]interface{}) (changed []string) {
+	for name, value := range fields {
+		if other, ok := candidate[name]; !ok || !reflect.DeepEqual(value, other) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range candidate {
+		if _, ok := fields[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return
+}
+
+func (h *httpHandler) functionsAuthor(ctx context.Context) string {
+	claims, err := h.manager.handler.Component.ValidateTTNAuthContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return claims.Subject
+}
+
+func (h *httpHandler) getFunctionsVersions(req *http.Request, appID string) (*FunctionsVersionsResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	if _, err := h.manager.handler.applications.Get(appID); err != nil {
+		return nil, err
+	}
+	versions, err := h.manager.handler.functions.List(appID)
+	if err != nil {
+		return nil, err
+	}
+	return &FunctionsVersionsResponse{AppID: appID, Versions: versions}, nil
+}
+
+func (h *httpHandler) getFunctionsVersion(req *http.Request, appID, versionStr string) (*application.FunctionsVersion, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	version, err := strconv.Atoi(versionStr)
+	if err != nil {
+		return nil, errors.NewErrInvalidArgument("Version", "must be a number")
+	}
+	return h.manager.handler.functions.Get(appID, version)
+}
+
+// testFunctions runs a candidate version of the payload functions on the
+// recent uplink messages in the event stream and returns the messages for
+// which the result differs from the fields that were published
+func (h *httpHandler) testFunctions(req *http.Request, appID string) (*FunctionsTestResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	if _, err := h.eventStream(req, appID); err != nil {
+		return nil, err
+	}
+	var in FunctionsTestRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	candidate := &UplinkFunctions{Decoder: in.Decoder, Converter: in.Converter, Validator: in.Validator}
+	if in.Version != 0 {
+		version, err := h.manager.handler.functions.Get(appID, in.Version)
+		if err != nil {
+			return nil, err
+		}
+		candidate = &UplinkFunctions{Decoder: version.Decoder, Converter: version.Converter, Validator: version.Validator}
+	}
+	if candidate.Decoder == "" {
+		return nil, errors.NewErrInvalidArgument("Decoder", "Not specified")
+	}
+	switch {
+	case in.Limit < 0:
+		return nil, errors.NewErrInvalidArgument("Limit", "must be a positive number")
+	case in.Limit == 0:
+		in.Limit = defaultFunctionsTestLimit
+	case in.Limit > maxFunctionsTestLimit:
+		in.Limit = maxFunctionsTestLimit
+	}
+
+	uplinks, err := h.manager.handler.recentUplinks(appID, in.DevID, in.Limit)
+	if err != nil {
+		return nil, err
+	}
+	response := &FunctionsTestResponse{AppID: appID, Tested: len(uplinks), Results: []*FunctionsTestResult{}}
+	for _, up := range uplinks {
+		result := runFunctionsTest(candidate, up)
+		if result == nil {
+			continue
+		}
+		if result.Error != "" {
+			response.Errors++
+		} else {
+			response.Changed++
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+// rollbackFunctions restores a version of the payload functions, which is
+// recorded as a new version
+func (h *httpHandler) rollbackFunctions(req *http.Request, appID string) (*application.FunctionsVersion, error) {
+	ctx, err := h.authorize(req, appID, rights.AppSettings)
+	if err != nil {
+		return nil, err
+	}
+	var in FunctionsRollbackRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	version, err := h.manager.handler.functions.Get(appID, in.Version)
+	if err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.Decoder = version.Decoder
+	app.Converter = version.Converter
+	app.Validator = version.Validator
+	app.Encoder = version.Encoder
+	if err := checkPayloadTests(app); err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	rollback := application.NewFunctionsVersion(app, h.functionsAuthor(ctx))
+	if err := h.manager.handler.functions.Add(appID, rollback); err != nil {
+		return nil, err
+	}
+	return rollback, nil
+}
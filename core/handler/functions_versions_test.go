@@ -0,0 +1,84 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestRecordFunctions(t *testing.T) {
+	a := New(t)
+	h := &handler{functions: application.NewMemoryFunctionsHistory()}
+
+	app := &application.Application{AppID: "app", Decoder: "decoder"}
+	a.So(h.recordFunctions(app, "alice"), ShouldBeNil)
+	a.So(h.recordFunctions(app, "bob"), ShouldBeNil)
+
+	versions, _ := h.functions.List("app")
+	a.So(versions, ShouldHaveLength, 1)
+	a.So(versions[0].Author, ShouldEqual, "alice")
+
+	app.Decoder = "new decoder"
+	a.So(h.recordFunctions(app, "bob"), ShouldBeNil)
+	versions, _ = h.functions.List("app")
+	a.So(versions, ShouldHaveLength, 2)
+	a.So(versions[0].Version, ShouldEqual, 2)
+	a.So(versions[0].Decoder, ShouldEqual, "new decoder")
+
+	// Handlers without history don't record versions
+	a.So((&handler{}).recordFunctions(app, "alice"), ShouldBeNil)
+}
+
+func TestRunFunctionsTest(t *testing.T) {
+	a := New(t)
+
+	up := storedUplink{
+		cursor: "1490000000000-0",
+		UplinkMessage: &types.UplinkMessage{
+			DevID:         "dev",
+			FPort:         1,
+			PayloadRaw:    []byte{0x08, 0x70},
+			PayloadFields: map[string]interface{}{"temperature": 21.6},
+		},
+	}
+
+	same := &UplinkFunctions{Decoder: `function Decoder(bytes) { return { temperature: ((bytes[0] << 8) | bytes[1]) / 100 }; }`}
+	a.So(runFunctionsTest(same, up), ShouldBeNil)
+
+	changed := &UplinkFunctions{Decoder: `function Decoder(bytes) { console.log("decoding"); return { temperature: 21, humidity: 50 }; }`}
+	result := runFunctionsTest(changed, up)
+	a.So(result, ShouldNotBeNil)
+	a.So(result.Cursor, ShouldEqual, "1490000000000-0")
+	a.So(result.Changed, ShouldResemble, []string{"humidity", "temperature"})
+	a.So(result.CandidateFields["humidity"], ShouldEqual, 50.0)
+	a.So(result.Valid, ShouldBeTrue)
+	a.So(result.Logs, ShouldHaveLength, 1)
+
+	invalid := &UplinkFunctions{
+		Decoder:   same.Decoder,
+		Validator: `function Validator(fields) { return false; }`,
+	}
+	result = runFunctionsTest(invalid, up)
+	a.So(result, ShouldNotBeNil)
+	a.So(result.Changed, ShouldBeEmpty)
+	a.So(result.Valid, ShouldBeFalse)
+
+	broken := &UplinkFunctions{Decoder: `function Decoder(bytes) { throw "broken"; }`}
+	result = runFunctionsTest(broken, up)
+	a.So(result, ShouldNotBeNil)
+	a.So(result.Error, ShouldNotBeEmpty)
+}
+
+func TestDiffFields(t *testing.T) {
+	a := New(t)
+	a.So(diffFields(nil, nil), ShouldBeEmpty)
+	a.So(diffFields(
+		map[string]interface{}{"a": 1.0, "b": []interface{}{1.0}, "c": "c"},
+		map[string]interface{}{"a": 1.0, "b": []interface{}{2.0}, "d": "d"},
+	), ShouldResemble, []string{"b", "c", "d"})
+}
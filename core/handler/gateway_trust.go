@@ -0,0 +1,43 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// trustedGatewaysOnly returns true if the application excludes untrusted gateways
+func (h *handler) trustedGatewaysOnly(appID string) bool {
+	if h.applications == nil {
+		return false
+	}
+	app, err := h.applications.Get(appID)
+	if err != nil {
+		return false
+	}
+	return app.TrustedGatewaysOnly
+}
+
+// checkDownlinkGatewayTrust returns an error if the gateway that was selected
+// for the response to the uplink is not trusted
+func checkDownlinkGatewayTrust(uplink *pb_broker.DeduplicatedUplinkMessage) error {
+	option := uplink.GetResponseTemplate().GetDownlinkOption()
+	if option == nil || gatewayTrusted(uplink, option.GatewayId) {
+		return nil
+	}
+	return errors.NewErrPermissionDenied(fmt.Sprintf("gateway %s is not trusted", option.GatewayId))
+}
+
+// gatewayTrusted returns true if the gateway that received the uplink is trusted
+func gatewayTrusted(uplink *pb_broker.DeduplicatedUplinkMessage, gatewayID string) bool {
+	for _, gateway := range uplink.GatewayMetadata {
+		if gateway.GatewayId == gatewayID && gateway.GatewayTrusted {
+			return true
+		}
+	}
+	return false
+}
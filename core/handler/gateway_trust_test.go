@@ -0,0 +1,50 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestTrustedGatewaysOnly(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestTrustedGatewaysOnly")},
+	}
+	a.So(h.trustedGatewaysOnly("app"), ShouldBeFalse)
+
+	h.applications = application.NewMemoryApplicationStore()
+	a.So(h.trustedGatewaysOnly("app"), ShouldBeFalse)
+
+	h.applications.Set(&application.Application{AppID: "app", TrustedGatewaysOnly: true})
+	a.So(h.trustedGatewaysOnly("app"), ShouldBeTrue)
+}
+
+func TestCheckDownlinkGatewayTrust(t *testing.T) {
+	a := New(t)
+
+	uplink := &pb_broker.DeduplicatedUplinkMessage{
+		GatewayMetadata: []*pb_gateway.RxMetadata{
+			{GatewayId: "gtw-1", GatewayTrusted: true},
+			{GatewayId: "gtw-2"},
+		},
+	}
+	a.So(checkDownlinkGatewayTrust(uplink), ShouldBeNil)
+
+	uplink.ResponseTemplate = &pb_broker.DownlinkMessage{DownlinkOption: &pb_broker.DownlinkOption{GatewayId: "gtw-1"}}
+	a.So(checkDownlinkGatewayTrust(uplink), ShouldBeNil)
+
+	uplink.ResponseTemplate.DownlinkOption.GatewayId = "gtw-2"
+	a.So(checkDownlinkGatewayTrust(uplink), ShouldNotBeNil)
+
+	uplink.ResponseTemplate.DownlinkOption.GatewayId = "gtw-3"
+	a.So(checkDownlinkGatewayTrust(uplink), ShouldNotBeNil)
+}
@@ -5,15 +5,22 @@ package handler
 
 import (
 	"fmt"
+	"net/http"
+	"sync"
 
+	"github.com/Shopify/sarama"
 	"github.com/TheThingsNetwork/ttn/amqp"
 	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
 	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/api/ratelimit"
 	"github.com/TheThingsNetwork/ttn/core/component"
 	"github.com/TheThingsNetwork/ttn/core/handler/application"
 	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/kek"
+	"github.com/TheThingsNetwork/ttn/core/storage"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/bluele/gcache"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"gopkg.in/redis.v5"
@@ -26,11 +33,37 @@ type Handler interface {
 
 	WithMQTT(username, password string, brokers ...string) Handler
 	WithAMQP(username, password, host, exchange string) Handler
+	WithKafka(config KafkaConfig) Handler
+	WithNetworkServer(networkServerID string) Handler
+	WithDevEUIBlock(block types.DevEUIBlock) Handler
+	WithDevEUIValidation(config DevEUIValidation) Handler
+	WithTenants(tenants types.Tenants) Handler
+	WithMetering(config MeteringConfig) Handler
+	WithCoverage(config CoverageConfig) Handler
+	WithCodecRepository(dir string) Handler
+	WithMetadataPrivacy(defaults application.MetadataPrivacy) Handler
+	// WithIntegrationHosts allows the integrations of applications to connect to
+	// the hosts, domains (.example.com) and networks (10.0.0.0/8), in addition
+	// to public addresses
+	WithIntegrationHosts(hosts []string) Handler
+	// WithSecretsKey makes the Handler encrypt the credentials of the integrations
+	// of applications with the AES key before storing them
+	WithSecretsKey(key []byte) Handler
+	// WithKEKs makes the Handler wrap the session keys that it sends to the NetworkServer
+	// behind a Broker with the KEK of the label that is configured for the Broker ID
+	WithKEKs(store kek.Store, partners map[string]string) Handler
+	// WithJoinCrypto makes the Handler use the JoinCrypto instead of the AppKeys of its devices
+	WithJoinCrypto(crypto JoinCrypto) Handler
+
+	HTTPHandler(next http.Handler) http.Handler
 
 	HandleUplink(uplink *pb_broker.DeduplicatedUplinkMessage) error
 	HandleActivationChallenge(challenge *pb_broker.ActivationChallengeRequest) (*pb_broker.ActivationChallengeResponse, error)
 	HandleActivation(activation *pb_broker.DeduplicatedDeviceActivationRequest) (*pb.DeviceActivationResponse, error)
 	EnqueueDownlink(appDownlink *types.DownlinkMessage) error
+
+	Subscribe(appID string) *Subscription
+	Unsubscribe(sub *Subscription)
 }
 
 // NewRedisHandler creates a new Redis-backed Handler
@@ -38,6 +71,28 @@ func NewRedisHandler(client *redis.Client, ttnBrokerID string) Handler {
 	return &handler{
 		devices:      device.NewRedisDeviceStore(client, "handler"),
 		applications: application.NewRedisApplicationStore(client, "handler"),
+		eventStream:  storage.NewRedisStreamStore(client, "handler:events", EventStreamLength),
+		functions:    application.NewRedisFunctionsHistory(client, "handler"),
+		usage:        newUsageStore(client, "handler"),
+		ttnBrokerID:  ttnBrokerID,
+	}
+}
+
+// NewMemoryHandler creates a new Handler that keeps its state in memory
+func NewMemoryHandler(ttnBrokerID string) Handler {
+	return NewHandler(
+		device.NewMemoryDeviceStore(),
+		application.NewMemoryApplicationStore(),
+		ttnBrokerID,
+	)
+}
+
+// NewHandler creates a new Handler that uses the given stores
+func NewHandler(devices device.Store, applications application.Store, ttnBrokerID string) Handler {
+	return &handler{
+		devices:      devices,
+		applications: applications,
+		functions:    application.NewMemoryFunctionsHistory(),
 		ttnBrokerID:  ttnBrokerID,
 	}
 }
@@ -47,12 +102,57 @@ type handler struct {
 
 	devices      device.Store
 	applications application.Store
+	eventStream  *storage.RedisStreamStore
+	functions    application.FunctionsHistory
 
 	ttnBrokerID      string
 	ttnBrokerConn    *grpc.ClientConn
 	ttnBroker        pb_broker.BrokerClient
 	ttnBrokerManager pb_broker.BrokerManagerClient
 
+	networkServerID string
+
+	devEUIBlock   *types.DevEUIBlock
+	devEUICursor  *uint64 // index in the devEUIBlock of the next DevEUI that is provisioned
+	provisionLock sync.Mutex
+
+	devEUIValidation DevEUIValidation
+	devEUILock       sync.Mutex
+
+	tenants    types.Tenants
+	tenantRate map[string]*ratelimit.Registry
+
+	meter *meter
+	usage *usageStore
+
+	coverage *coverage
+
+	metadataPrivacyDefaults application.MetadataPrivacy
+
+	codecs *codecRepository
+
+	keks        kek.Store
+	kekPartners map[string]string // Broker ID to KEK label
+
+	joinCrypto JoinCrypto
+
+	ruleTriggers     gcache.Cache // app/rule/dev -> time.Time
+	ruleTriggersLock sync.Mutex
+
+	mqttClaims     gcache.Cache // username -> *claims.Claims
+	mqttClaimsLock sync.Mutex
+
+	integrationHosts         integrationHosts
+	integrationTransport     *http.Transport
+	integrationTransportOnce sync.Once
+
+	secretsKey []byte
+
+	timescaleDBs    map[string]*timescaleDB
+	timeSeriesLock  sync.Mutex
+	timeSeriesQueue chan *timeSeriesWrite
+	timeSeriesOnce  sync.Once
+
 	downlink chan *pb_broker.DownlinkMessage
 
 	mqttClient   mqtt.Client
@@ -71,6 +171,19 @@ type handler struct {
 	amqpEnabled  bool
 	amqpUp       chan *types.UplinkMessage
 
+	kafka                 *KafkaConfig
+	kafkaProducer         sarama.AsyncProducer
+	kafkaClient           sarama.Client
+	kafkaConsumer         sarama.Consumer
+	kafkaOffsets          sarama.OffsetManager
+	kafkaPartitionOffsets []sarama.PartitionOffsetManager
+	kafkaSubscription     *Subscription
+	kafkaKeys             gcache.Cache // kafkaDevice -> DevEUI string
+
+	subscriptions subscriptions
+
+	mqttBridges mqttBridges
+
 	status *status
 }
 
@@ -96,6 +209,11 @@ func (h *handler) WithAMQP(username, password, host, exchange string) Handler {
 	return h
 }
 
+func (h *handler) WithKafka(config KafkaConfig) Handler {
+	h.kafka = &config
+	return h
+}
+
 func (h *handler) Init(c *component.Component) error {
 	h.Component = c
 	h.InitStatus()
@@ -118,6 +236,8 @@ func (h *handler) Init(c *component.Component) error {
 		if err != nil {
 			return err
 		}
+	} else {
+		h.handleSubscriptions()
 	}
 
 	if h.amqpEnabled {
@@ -127,11 +247,34 @@ func (h *handler) Init(c *component.Component) error {
 		}
 	}
 
+	if h.kafka != nil {
+		err = h.HandleKafka(*h.kafka)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = h.startMQTTBridges()
+	if err != nil {
+		return err
+	}
+
+	if h.keks != nil {
+		h.RegisterAdminHandler("keks", kek.HTTPHandler(h.keks))
+	}
+
 	err = h.associateBroker()
 	if err != nil {
 		return err
 	}
 
+	h.handleDataRetention()
+	err = h.handleMetering()
+	if err != nil {
+		return err
+	}
+	h.handleMigrations()
+
 	h.Component.SetStatus(component.StatusHealthy)
 
 	return nil
@@ -144,6 +287,11 @@ func (h *handler) Shutdown() {
 	if h.amqpEnabled {
 		h.amqpClient.Disconnect()
 	}
+	h.closeTimescaleDBs()
+	if h.kafka != nil {
+		h.closeKafka()
+	}
+	h.stopMQTTBridges()
 }
 
 func (h *handler) associateBroker() error {
@@ -168,7 +316,10 @@ func (h *handler) associateBroker() error {
 
 	go func() {
 		for message := range upStream.Channel() {
-			go h.HandleUplink(message)
+			go func(message *pb_broker.DeduplicatedUplinkMessage) {
+				h.HandleUplink(message)
+				upStream.Ack(message)
+			}(message)
 		}
 	}()
 
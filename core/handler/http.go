@@ -0,0 +1,870 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/claims"
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/ratelimit"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// EraseResponse is returned by the erase endpoint of the HTTP API
+type EraseResponse struct {
+	AppID         string `json:"app_id"`
+	DevID         string `json:"dev_id"`
+	Handler       bool   `json:"handler"`
+	NetworkServer bool   `json:"networkserver"`
+}
+
+// RetentionResponse is returned and accepted by the retention endpoint of the HTTP API
+type RetentionResponse struct {
+	AppID         string `json:"app_id"`
+	DataRetention string `json:"data_retention"`
+}
+
+// PayloadFormatResponse is returned and accepted by the payload format endpoint of the HTTP API
+type PayloadFormatResponse struct {
+	AppID         string `json:"app_id"`
+	PayloadFormat string `json:"payload_format"`
+}
+
+// TopicPatternResponse is returned and accepted by the topic pattern endpoint of the HTTP API
+type TopicPatternResponse struct {
+	AppID        string `json:"app_id"`
+	TopicPattern string `json:"topic_pattern"`
+}
+
+// ClaimingResponse is returned and accepted by the claiming endpoint of the HTTP API
+type ClaimingResponse struct {
+	AppID     string `json:"app_id"`
+	Claimable bool   `json:"claimable"`
+}
+
+// GatewayTrustResponse is returned and accepted by the gateway trust endpoint of the HTTP API
+type GatewayTrustResponse struct {
+	AppID               string `json:"app_id"`
+	TrustedGatewaysOnly bool   `json:"trusted_gateways_only"`
+}
+
+// MetadataPrivacyResponse is returned and accepted by the metadata privacy endpoint of the HTTP API
+type MetadataPrivacyResponse struct {
+	AppID string `json:"app_id"`
+	application.MetadataPrivacy
+	// Default is true if the application uses the metadata privacy of the Handler
+	Default bool `json:"default"`
+	// Handler is the metadata privacy of the Handler, which is applied before
+	// the metadata privacy of the application
+	Handler *application.MetadataPrivacy `json:"handler,omitempty"`
+}
+
+// DeduplicationResponse is returned and accepted by the deduplication endpoint of the HTTP API
+type DeduplicationResponse struct {
+	AppID               string `json:"app_id"`
+	DeduplicationWindow string `json:"deduplication_window"`
+}
+
+// JoinAcceptResponse is returned and accepted by the join-accept endpoints of the HTTP API
+type JoinAcceptResponse struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id,omitempty"`
+	types.JoinAcceptSettings
+}
+
+// EventsResponse is returned by the event stream endpoints of the HTTP API
+type EventsResponse struct {
+	AppID  string        `json:"app_id"`
+	Events []StreamEvent `json:"events"`
+}
+
+// AckRequest is accepted by the acknowledgement endpoint of the event stream
+type AckRequest struct {
+	Cursors []string `json:"cursors"`
+}
+
+const (
+	defaultEventsLimit = 100
+	maxEventsLimit     = 1000
+)
+
+type httpHandler struct {
+	manager *handlerManager
+	next    http.Handler
+}
+
+// HTTPHandler returns an HTTP API for the Handler that delegates the requests
+// it does not handle to next:
+//
+//	DELETE /applications/{app_id}/devices/{dev_id}/data          erases the data that is stored about a device
+//	GET /applications/{app_id}/retention                         returns the data retention of an application
+//	PUT /applications/{app_id}/retention                         sets the data retention of an application
+//	GET /applications/{app_id}/payload-format                    returns the format of the uplink messages of an application
+//	PUT /applications/{app_id}/payload-format                    sets the format of the uplink messages of an application
+//	GET /applications/{app_id}/topic-pattern                     returns the additional MQTT topic of the uplink messages of an application
+//	PUT /applications/{app_id}/topic-pattern                     sets the additional MQTT topic of the uplink messages of an application
+//	GET /applications/{app_id}/gateway-trust                     returns whether an application only uses trusted gateways
+//	PUT /applications/{app_id}/gateway-trust                     sets whether an application only uses trusted gateways
+//	GET /applications/{app_id}/metadata-privacy                  returns the gateway metadata that is hidden from an application
+//	PUT /applications/{app_id}/metadata-privacy                  sets the gateway metadata that is hidden from an application
+//	DELETE /applications/{app_id}/metadata-privacy               resets the gateway metadata that is hidden from an application to the metadata privacy of the Handler
+//	GET /applications/{app_id}/deduplication                     returns the window in which retransmitted uplink messages are deduplicated
+//	PUT /applications/{app_id}/deduplication                     sets the window in which retransmitted uplink messages are deduplicated
+//	GET /applications/{app_id}/join-accept                       returns the join-accept settings of an application
+//	PUT /applications/{app_id}/join-accept                       sets the join-accept settings of an application
+//	POST /applications/{app_id}/devices/bulk                     updates, deletes or requeues the downlinks of the devices that match a filter
+//	GET /applications/{app_id}/devices/search                    returns the devices that match the words in ?q= and have all tags in ?tag=, paginated with ?limit= and ?offset=
+//	GET /applications/{app_id}/devices/{dev_id}/labels           returns the name, description and tags of a device
+//	PUT /applications/{app_id}/devices/{dev_id}/labels           sets the name, description and tags of a device
+//	GET /applications/{app_id}/devices/{dev_id}/join-accept      returns the join-accept settings of a device
+//	PUT /applications/{app_id}/devices/{dev_id}/join-accept      overrides the join-accept settings of a device
+//	GET /applications/{app_id}/devices/{dev_id}/downlink-profile returns the downlink profile of a device
+//	PUT /applications/{app_id}/devices/{dev_id}/downlink-profile sets whether the downlink messages of a device are only scheduled in RX2
+//	GET /applications/{app_id}/devices/{dev_id}/ack              returns how the confirmed uplink messages of a device are acknowledged
+//	PUT /applications/{app_id}/devices/{dev_id}/ack              sets how the confirmed uplink messages of a device are acknowledged
+//	GET /applications/{app_id}/devices/{dev_id}/end-to-end       returns whether a device uses end-to-end encryption
+//	PUT /applications/{app_id}/devices/{dev_id}/end-to-end       enables or disables end-to-end encryption, which makes the Handler forget the AppSKey
+//	GET /applications/{app_id}/rules                             returns the rules of an application
+//	PUT /applications/{app_id}/rules                             sets the rules that trigger actions on the decoded fields of uplink messages
+//	GET /applications/{app_id}/time-series                       returns the time-series database of an application, without the password
+//	PUT /applications/{app_id}/time-series                       sets the InfluxDB or TimescaleDB to which the numeric decoded fields of uplink messages are written
+//	DELETE /applications/{app_id}/time-series                    stops writing the decoded fields of an application to a time-series database
+//	GET /applications/{app_id}/mqtt-bridge                       returns the external MQTT broker of an application and the status of the connection, without the password
+//	PUT /applications/{app_id}/mqtt-bridge                       sets the external MQTT broker to which the uplink messages and events of an application are published
+//	DELETE /applications/{app_id}/mqtt-bridge                    stops bridging an application to an external MQTT broker
+//	GET /applications/{app_id}/converters                        returns the payload converters of an application
+//	PUT /applications/{app_id}/converters                        sets the payload converters that are selected by port and device attribute
+//	GET /applications/{app_id}/devices/{dev_id}/attributes       returns the attributes of a device
+//	PUT /applications/{app_id}/devices/{dev_id}/attributes       sets the attributes of a device that select its payload converters
+//	GET /applications/{app_id}/export                            returns the settings of an application, and its devices if ?devices=true (without keys if ?keys=false, with the frame counters of ABP sessions if ?counters=true, with the sessions of OTAA devices if ?migration=true)
+//	POST /applications/{app_id}/import                           replaces the settings of an application and registers the devices of an export, deleting other devices if ?prune=true
+//	GET /applications/{app_id}/migration                         returns the migration of the devices of an application to another Handler
+//	PUT /applications/{app_id}/migration                         synchronizes an application to another Handler and migrates a percentage or a list of its devices
+//	DELETE /applications/{app_id}/migration                      aborts the migration of an application, routing all its devices to this Handler again
+//	POST /applications/{app_id}/migration/cutover                completes the migration of an application, moving all its devices to the other Handler
+//	POST /applications/{app_id}/takeover                         registers an application that was migrated to this Handler, called by the Handler it is migrated from
+//	GET /migrations                                              returns the migrations of the applications of this Handler, called by a Broker when it starts
+//	GET /applications/{app_id}/payload-tests                     returns the test vectors of the payload functions of an application
+//	PUT /applications/{app_id}/payload-tests                     sets the test vectors of the payload functions of an application and runs them
+//	POST /applications/{app_id}/payload-tests/run                runs the test vectors of the payload functions of an application
+//	GET /applications/{app_id}/functions/versions                returns the versions of the payload functions of an application, newest first
+//	GET /applications/{app_id}/functions/versions/{version}      returns a version of the payload functions of an application
+//	POST /applications/{app_id}/functions/test                   runs a candidate version of the payload functions on recent uplink messages and returns the differences
+//	POST /applications/{app_id}/functions/rollback               restores a version of the payload functions of an application
+//	GET /applications/{app_id}/claiming                          returns whether devices can be claimed from an application
+//	PUT /applications/{app_id}/claiming                          sets whether devices can be claimed from an application
+//	POST /applications/{app_id}/claim                            moves a device from a claimable application to an application with a claim code
+//	GET /applications/{app_id}/devices/{dev_id}/claim-code       returns the claim code of a device
+//	POST /applications/{app_id}/provision                        generates and registers devices and returns their provisioning file in ?format=json, csv or qr
+//	GET /applications/{app_id}/events                            replays the event stream of an application after the cursor in ?after=
+//	GET /applications/{app_id}/events/groups/{group}             returns the events that were not yet delivered to a consumer group
+//	POST /applications/{app_id}/events/groups/{group}/ack        acknowledges the events that were processed by a consumer group
+//	GET /applications/{app_id}/live                              streams the uplink messages and events over a WebSocket, optionally filtered by ?dev_id=
+//	GET /applications/{app_id}/sse                               streams the uplink messages and events as Server-Sent Events, optionally filtered by ?dev_id=
+//	GET /applications/{app_id}/devices/{dev_id}/sse              streams the uplink messages and events of a device as Server-Sent Events
+//	GET /applications/{app_id}/profiles                          returns the device profiles of an application
+//	GET /applications/{app_id}/profiles/{profile_id}             returns a device profile and the number of devices that use it
+//	PUT /applications/{app_id}/profiles/{profile_id}             creates or replaces a device profile and updates the devices that use it
+//	DELETE /applications/{app_id}/profiles/{profile_id}          removes a device profile that is not used by devices, returns the remaining profiles
+//	GET /applications/{app_id}/devices/{dev_id}/profile          returns the profile of a device
+//	PUT /applications/{app_id}/devices/{dev_id}/profile          sets the profile of a device
+//	GET /applications/{app_id}/roles                             returns the roles of the collaborators of an application
+//	PUT /applications/{app_id}/roles/{username}                  sets the role (viewer, developer or admin) of a collaborator of an application
+//	DELETE /applications/{app_id}/roles/{username}               removes the role of a collaborator of an application
+//	GET /applications/{app_id}/usage                             returns the usage of an application in the current quota period and its quota
+//	GET /applications/{app_id}/coverage                          returns the coverage statistics of an application as GeoJSON, optionally filtered by ?gtw_id= and ?source=
+//	GET /applications/{app_id}/conflicts                         returns the devices of an application whose credentials are also used by other devices
+//	DELETE /applications/{app_id}/devices/{dev_id}/conflict      removes the conflict of a device after its credentials were replaced
+//	POST /downlink-failures                                      publishes a downlink failure that a Broker or Router reports as a downlink error event
+//	POST /mqtt/auth                                              authenticates a collaborator with a token for an MQTT broker
+//	POST /mqtt/acl                                               checks whether the role of a collaborator allows access to an MQTT topic
+//
+// Requests are authenticated with a Bearer token or an access key in the
+// headers that are set by the proxy. The WebSocket also accepts them in the
+// token and key query parameters. The MQTT endpoints are called by the MQTT
+// broker and respond with 200 OK if access is allowed.
+func (h *handler) HTTPHandler(next http.Handler) http.Handler {
+	return &httpHandler{
+		manager: &handlerManager{
+			handler:         h,
+			deviceManager:   pb_lorawan.NewDeviceManagerClient(h.ttnBrokerConn),
+			devAddrManager:  pb_lorawan.NewDevAddrManagerClient(h.ttnBrokerConn),
+			applicationRate: ratelimit.NewRegistry(5000, time.Hour),
+			clientRate:      ratelimit.NewRegistry(5000, time.Hour),
+		},
+		next: next,
+	}
+}
+
+func (h *httpHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	path := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "data" && req.Method == http.MethodDelete:
+		response, err := h.erase(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "retention" && req.Method == http.MethodGet:
+		response, err := h.getRetention(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "retention" && req.Method == http.MethodPut:
+		response, err := h.setRetention(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "payload-format" && req.Method == http.MethodGet:
+		response, err := h.getPayloadFormat(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "payload-format" && req.Method == http.MethodPut:
+		response, err := h.setPayloadFormat(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "topic-pattern" && req.Method == http.MethodGet:
+		response, err := h.getTopicPattern(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "topic-pattern" && req.Method == http.MethodPut:
+		response, err := h.setTopicPattern(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "gateway-trust" && req.Method == http.MethodGet:
+		response, err := h.getGatewayTrust(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "gateway-trust" && req.Method == http.MethodPut:
+		response, err := h.setGatewayTrust(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "metadata-privacy" && req.Method == http.MethodGet:
+		response, err := h.getMetadataPrivacy(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "metadata-privacy" && req.Method == http.MethodPut:
+		response, err := h.setMetadataPrivacy(req, path[1], false)
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "metadata-privacy" && req.Method == http.MethodDelete:
+		response, err := h.setMetadataPrivacy(req, path[1], true)
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "deduplication" && req.Method == http.MethodGet:
+		response, err := h.getDeduplication(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "deduplication" && req.Method == http.MethodPut:
+		response, err := h.setDeduplication(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "join-accept" && req.Method == http.MethodGet:
+		response, err := h.getJoinAccept(req, path[1], "")
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "join-accept" && req.Method == http.MethodPut:
+		response, err := h.setJoinAccept(req, path[1], "")
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "devices" && path[3] == "bulk" && req.Method == http.MethodPost:
+		response, err := h.bulk(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "devices" && path[3] == "search" && req.Method == http.MethodGet:
+		response, err := h.searchDevices(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "labels" && req.Method == http.MethodGet:
+		response, err := h.getDeviceLabels(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "labels" && req.Method == http.MethodPut:
+		response, err := h.setDeviceLabels(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "join-accept" && req.Method == http.MethodGet:
+		response, err := h.getJoinAccept(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "join-accept" && req.Method == http.MethodPut:
+		response, err := h.setJoinAccept(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "downlink-profile" && req.Method == http.MethodGet:
+		response, err := h.getDownlinkProfile(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "downlink-profile" && req.Method == http.MethodPut:
+		response, err := h.setDownlinkProfile(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "ack" && req.Method == http.MethodGet:
+		response, err := h.getAckSettings(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "ack" && req.Method == http.MethodPut:
+		response, err := h.setAckSettings(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "end-to-end" && req.Method == http.MethodGet:
+		response, err := h.getEndToEnd(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "end-to-end" && req.Method == http.MethodPut:
+		response, err := h.setEndToEnd(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "rules" && req.Method == http.MethodGet:
+		response, err := h.getRules(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "rules" && req.Method == http.MethodPut:
+		response, err := h.setRules(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "time-series" && req.Method == http.MethodGet:
+		response, err := h.getTimeSeries(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "time-series" && req.Method == http.MethodPut:
+		response, err := h.setTimeSeries(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "time-series" && req.Method == http.MethodDelete:
+		err := h.deleteTimeSeries(req, path[1])
+		h.write(res, struct{}{}, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "mqtt-bridge" && req.Method == http.MethodGet:
+		response, err := h.getMQTTBridge(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "mqtt-bridge" && req.Method == http.MethodPut:
+		response, err := h.setMQTTBridge(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "mqtt-bridge" && req.Method == http.MethodDelete:
+		err := h.deleteMQTTBridge(req, path[1])
+		h.write(res, struct{}{}, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "converters" && req.Method == http.MethodGet:
+		response, err := h.getPayloadConverters(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "converters" && req.Method == http.MethodPut:
+		response, err := h.setPayloadConverters(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "attributes" && req.Method == http.MethodGet:
+		response, err := h.getDeviceAttributes(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "attributes" && req.Method == http.MethodPut:
+		response, err := h.setDeviceAttributes(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "profiles" && req.Method == http.MethodGet:
+		response, err := h.getProfiles(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "profiles" && req.Method == http.MethodGet:
+		response, err := h.getProfile(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "profiles" && req.Method == http.MethodPut:
+		response, err := h.setProfile(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "profiles" && req.Method == http.MethodDelete:
+		response, err := h.deleteProfile(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "profile" && req.Method == http.MethodGet:
+		response, err := h.getDeviceProfile(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "profile" && req.Method == http.MethodPut:
+		response, err := h.setDeviceProfile(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "export" && req.Method == http.MethodGet:
+		response, err := h.exportApplication(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "import" && req.Method == http.MethodPost:
+		response, err := h.importApplication(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "migration" && req.Method == http.MethodGet:
+		response, err := h.getMigration(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "migration" && req.Method == http.MethodPut:
+		response, err := h.setMigration(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "migration" && req.Method == http.MethodDelete:
+		response, err := h.deleteMigration(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "migration" && path[3] == "cutover" && req.Method == http.MethodPost:
+		response, err := h.cutover(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "takeover" && req.Method == http.MethodPost:
+		response, err := h.takeover(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 1 && path[0] == "migrations" && req.Method == http.MethodGet:
+		response, err := h.listMigrations(req)
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "payload-tests" && req.Method == http.MethodGet:
+		response, err := h.getPayloadTests(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "payload-tests" && req.Method == http.MethodPut:
+		response, err := h.setPayloadTests(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "payload-tests" && path[3] == "run" && req.Method == http.MethodPost:
+		response, err := h.runPayloadTests(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "functions" && path[3] == "versions" && req.Method == http.MethodGet:
+		response, err := h.getFunctionsVersions(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "functions" && path[3] == "versions" && req.Method == http.MethodGet:
+		response, err := h.getFunctionsVersion(req, path[1], path[4])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "functions" && path[3] == "test" && req.Method == http.MethodPost:
+		response, err := h.testFunctions(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "functions" && path[3] == "rollback" && req.Method == http.MethodPost:
+		response, err := h.rollbackFunctions(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "claiming" && req.Method == http.MethodGet:
+		response, err := h.getClaiming(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "claiming" && req.Method == http.MethodPut:
+		response, err := h.setClaiming(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "claim" && req.Method == http.MethodPost:
+		response, err := h.claim(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "claim-code" && req.Method == http.MethodGet:
+		response, err := h.claimCode(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "provision" && req.Method == http.MethodPost:
+		response, err := h.provision(req, path[1])
+		h.writeProvision(res, req, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "events" && req.Method == http.MethodGet:
+		response, err := h.events(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "events" && path[3] == "groups" && req.Method == http.MethodGet:
+		response, err := h.readGroup(req, path[1], path[4])
+		h.write(res, response, err)
+	case len(path) == 6 && path[0] == "applications" && path[2] == "events" && path[3] == "groups" && path[5] == "ack" && req.Method == http.MethodPost:
+		if err := h.ackGroup(req, path[1], path[4]); err != nil {
+			h.write(res, nil, err)
+			return
+		}
+		res.WriteHeader(http.StatusNoContent)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "live" && req.Method == http.MethodGet:
+		h.live(res, req, path[1])
+	case len(path) == 3 && path[0] == "applications" && path[2] == "sse" && req.Method == http.MethodGet:
+		h.sse(res, req, path[1], liveDevices(req))
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "sse" && req.Method == http.MethodGet:
+		h.sse(res, req, path[1], map[string]bool{path[3]: true})
+	case len(path) == 3 && path[0] == "applications" && path[2] == "roles" && req.Method == http.MethodGet:
+		response, err := h.getRoles(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "roles" && req.Method == http.MethodPut:
+		response, err := h.setRole(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 4 && path[0] == "applications" && path[2] == "roles" && req.Method == http.MethodDelete:
+		response, err := h.deleteRole(req, path[1], path[3])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "usage" && req.Method == http.MethodGet:
+		response, err := h.usage(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "coverage" && req.Method == http.MethodGet:
+		response, err := h.coverage(req, path[1])
+		h.writeGeoJSON(res, response, err)
+	case len(path) == 3 && path[0] == "applications" && path[2] == "conflicts" && req.Method == http.MethodGet:
+		response, err := h.getConflicts(req, path[1])
+		h.write(res, response, err)
+	case len(path) == 5 && path[0] == "applications" && path[2] == "devices" && path[4] == "conflict" && req.Method == http.MethodDelete:
+		if err := h.clearConflict(req, path[1], path[3]); err != nil {
+			h.write(res, nil, err)
+			return
+		}
+		res.WriteHeader(http.StatusNoContent)
+	case len(path) == 1 && path[0] == "downlink-failures" && req.Method == http.MethodPost:
+		if err := h.downlinkFailure(req); err != nil {
+			h.write(res, nil, err)
+			return
+		}
+		res.WriteHeader(http.StatusNoContent)
+	case len(path) == 2 && path[0] == "mqtt" && path[1] == "auth" && req.Method == http.MethodPost:
+		if err := h.mqttAuth(req); err != nil {
+			h.write(res, nil, err)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	case len(path) == 2 && path[0] == "mqtt" && path[1] == "acl" && req.Method == http.MethodPost:
+		if err := h.mqttACL(req); err != nil {
+			h.write(res, nil, err)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	default:
+		h.next.ServeHTTP(res, req)
+	}
+}
+
+// authorize validates the token or access key of the request and returns a
+// context that contains the token
+func (h *httpHandler) authorize(req *http.Request, appID string, right rights.Right) (context.Context, error) {
+	ctx, _, err := h.authorizeClaims(req, appID, right)
+	return ctx, err
+}
+
+// authorizeClaims is authorize, and also returns the claims of the token
+func (h *httpHandler) authorizeClaims(req *http.Request, appID string, right rights.Right) (context.Context, *claims.Claims, error) {
+	md := metadata.MD{}
+	if token := req.Header.Get("Grpc-Metadata-Token"); token != "" {
+		md = metadata.Join(md, metadata.Pairs("token", token))
+	}
+	if key := req.Header.Get("Grpc-Metadata-Key"); key != "" {
+		md = metadata.Join(md, metadata.Pairs("key", key))
+	}
+	ctx, claims, err := h.manager.validateTTNAuthAppContext(metadata.NewContext(context.Background(), md), appID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := h.manager.checkAppRights(claims, appID, right, req.Method != http.MethodGet); err != nil {
+		return nil, nil, err
+	}
+	return ctx, claims, nil
+}
+
+func (h *httpHandler) erase(req *http.Request, appID, devID string) (*EraseResponse, error) {
+	ctx, err := h.authorize(req, appID, rights.Devices)
+	if err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.eraseDeviceData(dev); err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.eraseStreamEvents(appID, devID); err != nil {
+		return nil, err
+	}
+	response := &EraseResponse{AppID: appID, DevID: devID, Handler: true}
+
+	if h.manager.handler.networkServerID == "" {
+		return response, nil
+	}
+	md, err := api.MetadataFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := api.TokenFromMetadata(md)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.eraseNetworkServerData(token, dev); err != nil {
+		return nil, errors.Wrap(err, "Could not erase NetworkServer data")
+	}
+	response.NetworkServer = true
+
+	return response, nil
+}
+
+func (h *httpHandler) getRetention(req *http.Request, appID string) (*RetentionResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	return &RetentionResponse{AppID: app.AppID, DataRetention: app.DataRetention.String()}, nil
+}
+
+func (h *httpHandler) setRetention(req *http.Request, appID string) (*RetentionResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in RetentionResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	retention, err := time.ParseDuration(in.DataRetention)
+	if err != nil {
+		return nil, errors.NewErrInvalidArgument("Data Retention", err.Error())
+	}
+	if retention < 0 {
+		return nil, errors.NewErrInvalidArgument("Data Retention", "can not be negative")
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.DataRetention = retention
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &RetentionResponse{AppID: app.AppID, DataRetention: app.DataRetention.String()}, nil
+}
+
+func (h *httpHandler) getPayloadFormat(req *http.Request, appID string) (*PayloadFormatResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	format, _ := types.ParsePayloadFormat(app.PayloadFormat)
+	return &PayloadFormatResponse{AppID: app.AppID, PayloadFormat: string(format)}, nil
+}
+
+func (h *httpHandler) setPayloadFormat(req *http.Request, appID string) (*PayloadFormatResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in PayloadFormatResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	format, err := types.ParsePayloadFormat(in.PayloadFormat)
+	if err != nil {
+		return nil, errors.NewErrInvalidArgument("Payload Format", err.Error())
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.PayloadFormat = string(format)
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &PayloadFormatResponse{AppID: app.AppID, PayloadFormat: app.PayloadFormat}, nil
+}
+
+func (h *httpHandler) getTopicPattern(req *http.Request, appID string) (*TopicPatternResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	return &TopicPatternResponse{AppID: app.AppID, TopicPattern: app.TopicPattern}, nil
+}
+
+// setTopicPattern sets the topic pattern of the application, an empty pattern removes it
+func (h *httpHandler) setTopicPattern(req *http.Request, appID string) (*TopicPatternResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in TopicPatternResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if in.TopicPattern != "" {
+		if _, err := mqtt.ParseTopicPattern(in.TopicPattern); err != nil {
+			return nil, errors.NewErrInvalidArgument("Topic Pattern", err.Error())
+		}
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.TopicPattern = in.TopicPattern
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &TopicPatternResponse{AppID: app.AppID, TopicPattern: app.TopicPattern}, nil
+}
+
+func (h *httpHandler) getGatewayTrust(req *http.Request, appID string) (*GatewayTrustResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	return &GatewayTrustResponse{AppID: app.AppID, TrustedGatewaysOnly: app.TrustedGatewaysOnly}, nil
+}
+
+func (h *httpHandler) setGatewayTrust(req *http.Request, appID string) (*GatewayTrustResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in GatewayTrustResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.TrustedGatewaysOnly = in.TrustedGatewaysOnly
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &GatewayTrustResponse{AppID: app.AppID, TrustedGatewaysOnly: app.TrustedGatewaysOnly}, nil
+}
+
+// getJoinAccept returns the join-accept settings of the application, or of the device if devID is set
+func (h *httpHandler) getClaiming(req *http.Request, appID string) (*ClaimingResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	return &ClaimingResponse{AppID: app.AppID, Claimable: app.Claimable}, nil
+}
+
+func (h *httpHandler) setClaiming(req *http.Request, appID string) (*ClaimingResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in ClaimingResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.Claimable = in.Claimable
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &ClaimingResponse{AppID: app.AppID, Claimable: app.Claimable}, nil
+}
+
+func (h *httpHandler) getJoinAccept(req *http.Request, appID, devID string) (*JoinAcceptResponse, error) {
+	if devID == "" {
+		if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+			return nil, err
+		}
+		app, err := h.manager.handler.applications.Get(appID)
+		if err != nil {
+			return nil, err
+		}
+		return &JoinAcceptResponse{AppID: app.AppID, JoinAcceptSettings: app.JoinAccept}, nil
+	}
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	return &JoinAcceptResponse{AppID: dev.AppID, DevID: dev.DevID, JoinAcceptSettings: dev.JoinAccept}, nil
+}
+
+// setJoinAccept sets the join-accept settings of the application, or of the device if devID is set.
+// The settings are used when devices join, so they don't affect devices that already joined.
+func (h *httpHandler) setJoinAccept(req *http.Request, appID, devID string) (*JoinAcceptResponse, error) {
+	right := rights.AppSettings
+	if devID != "" {
+		right = rights.Devices
+	}
+	if _, err := h.authorize(req, appID, right); err != nil {
+		return nil, err
+	}
+	var in JoinAcceptResponse
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := validateJoinAcceptSettings(in.JoinAcceptSettings, ""); err != nil {
+		return nil, err
+	}
+	if devID == "" {
+		app, err := h.manager.handler.applications.Get(appID)
+		if err != nil {
+			return nil, err
+		}
+		app.StartUpdate()
+		app.JoinAccept = in.JoinAcceptSettings
+		if err := h.manager.handler.applications.Set(app); err != nil {
+			return nil, err
+		}
+		return &JoinAcceptResponse{AppID: app.AppID, JoinAcceptSettings: app.JoinAccept}, nil
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	dev.StartUpdate()
+	dev.JoinAccept = in.JoinAcceptSettings
+	if err := h.manager.handler.devices.Set(dev); err != nil {
+		return nil, err
+	}
+	return &JoinAcceptResponse{AppID: dev.AppID, DevID: dev.DevID, JoinAcceptSettings: dev.JoinAccept}, nil
+}
+
+func (h *httpHandler) eventStream(req *http.Request, appID string) (*storage.RedisStreamStore, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	if h.manager.handler.eventStream == nil {
+		return nil, errors.NewErrNotFound("Event stream")
+	}
+	if _, err := h.manager.handler.applications.Get(appID); err != nil {
+		return nil, err
+	}
+	return h.manager.handler.eventStream, nil
+}
+
+func eventsLimit(req *http.Request) (int64, error) {
+	limitStr := req.URL.Query().Get("limit")
+	if limitStr == "" {
+		return defaultEventsLimit, nil
+	}
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, errors.NewErrInvalidArgument("Limit", "must be a positive number")
+	}
+	if limit > maxEventsLimit {
+		limit = maxEventsLimit
+	}
+	return limit, nil
+}
+
+func (h *httpHandler) events(req *http.Request, appID string) (*EventsResponse, error) {
+	if _, err := h.eventStream(req, appID); err != nil {
+		return nil, err
+	}
+	limit, err := eventsLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	events, err := h.manager.handler.readStreamEvents(appID, req.URL.Query().Get("after"), "", "", limit, false)
+	if err != nil {
+		return nil, err
+	}
+	return &EventsResponse{AppID: appID, Events: events}, nil
+}
+
+func (h *httpHandler) readGroup(req *http.Request, appID, group string) (*EventsResponse, error) {
+	if _, err := h.eventStream(req, appID); err != nil {
+		return nil, err
+	}
+	limit, err := eventsLimit(req)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	events, err := h.manager.handler.readStreamEvents(appID, "", group, query.Get("consumer"), limit, query.Get("pending") == "true")
+	if err != nil {
+		return nil, err
+	}
+	return &EventsResponse{AppID: appID, Events: events}, nil
+}
+
+func (h *httpHandler) ackGroup(req *http.Request, appID, group string) error {
+	if _, err := h.eventStream(req, appID); err != nil {
+		return err
+	}
+	var in AckRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	return h.manager.handler.ackStreamEvents(appID, group, in.Cursors...)
+}
+
+func (h *httpHandler) write(res http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		if grpc.Code(err) != codes.Unknown {
+			err = errors.FromGRPCError(err)
+		}
+		code := http.StatusInternalServerError
+		switch errors.GetErrType(err) {
+		case errors.NotFound:
+			code = http.StatusNotFound
+		case errors.InvalidArgument:
+			code = http.StatusBadRequest
+		case errors.PermissionDenied:
+			code = http.StatusForbidden
+		case errors.AlreadyExists:
+			code = http.StatusConflict
+		}
+		http.Error(res, err.Error(), code)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(res).Encode(v)
+}
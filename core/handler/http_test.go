@@ -0,0 +1,248 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestHandlerHTTPHandler(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestHandlerHTTPHandler")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+
+	var delegated bool
+	next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		delegated = true
+	})
+	handler := h.HTTPHandler(next)
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		delegated = false
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	// Requests that are not handled are delegated
+	request("GET", "/applications/app/devices/dev")
+	a.So(delegated, ShouldBeTrue)
+	request("GET", "/applications/app/devices/dev/data")
+	a.So(delegated, ShouldBeTrue)
+	request("DELETE", "/applications/app/retention")
+	a.So(delegated, ShouldBeTrue)
+
+	// Requests without token or key are rejected
+	rec := request("DELETE", "/applications/app/devices/dev/data")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/retention")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/retention")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/payload-format")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/payload-format")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/gateway-trust")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/gateway-trust")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/metadata-privacy")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/metadata-privacy")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("DELETE", "/applications/app/metadata-privacy")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/topic-pattern")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/topic-pattern")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/join-accept")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/join-accept")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/devices/dev/join-accept")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("POST", "/applications/app/provision")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/claiming")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("POST", "/applications/app/claim")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/devices/dev/claim-code")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/deduplication")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/rules")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/export")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("POST", "/applications/app/import")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/payload-tests")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("POST", "/applications/app/payload-tests/run")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/functions/versions")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/functions/versions/1")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("POST", "/applications/app/functions/test")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("POST", "/applications/app/functions/rollback")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/roles")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/roles/user")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("DELETE", "/applications/app/roles/user")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/usage")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("POST", "/applications/app/devices/bulk")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/devices/search")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/devices/dev/labels")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("PUT", "/applications/app/devices/dev/labels")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("GET", "/applications/app/conflicts")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("DELETE", "/applications/app/devices/dev/conflict")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	// MQTT requests without body are rejected
+	rec = request("POST", "/mqtt/auth")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = request("POST", "/mqtt/acl")
+	a.So(delegated, ShouldBeFalse)
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+}
+
+func TestHandlerHTTPHandlerEvents(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestHandlerHTTPHandlerEvents")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+
+	var delegated bool
+	handler := h.HTTPHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		delegated = true
+	}))
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		delegated = false
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	request("POST", "/applications/app/events")
+	a.So(delegated, ShouldBeTrue)
+	request("GET", "/applications/app/events/groups/group/ack")
+	a.So(delegated, ShouldBeTrue)
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/applications/app/events"},
+		{"GET", "/applications/app/events/groups/group"},
+		{"POST", "/applications/app/events/groups/group/ack"},
+	} {
+		rec := request(req.method, req.path)
+		a.So(delegated, ShouldBeFalse)
+		a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// nonPublicNetworks are the networks that the integrations of applications can
+// only connect to if they are in the integration hosts of the Handler
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// integrationHosts is the allow-list of the hosts that the integrations of
+// applications (webhooks, databases and MQTT bridges) can connect to, in
+// addition to public addresses
+type integrationHosts struct {
+	names    []string // host names, or domains if they start with a dot
+	networks []*net.IPNet
+}
+
+// parseIntegrationHosts parses host names, domains (.example.com), IP
+// addresses and networks (10.0.0.0/8)
+func parseIntegrationHosts(hosts []string) (allowed integrationHosts) {
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(host); err == nil {
+			allowed.networks = append(allowed.networks, network)
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			allowed.networks = append(allowed.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		allowed.names = append(allowed.names, host)
+	}
+	return
+}
+
+func (allowed integrationHosts) allowsName(host string) bool {
+	host = strings.ToLower(host)
+	for _, name := range allowed.names {
+		if host == name || (strings.HasPrefix(name, ".") && strings.HasSuffix(host, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (allowed integrationHosts) allowsIP(ip net.IP) bool {
+	for _, network := range allowed.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup returns the addresses of the host, or an error if the integrations
+// of applications are not allowed to connect to any of them
+func (allowed integrationHosts) lookup(host string) ([]net.IP, error) {
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		var err error
+		ips, err = net.LookupIP(host)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if allowed.allowsName(host) {
+		return ips, nil
+	}
+	for _, ip := range ips {
+		if !allowed.allowsIP(ip) {
+			return nil, errors.NewErrPermissionDenied(fmt.Sprintf("Host %s is not allowed for integrations", host))
+		}
+	}
+	return ips, nil
+}
+
+// checkURL returns an error if the integrations of applications are not
+// allowed to connect to the host of the URL
+func (allowed integrationHosts) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.NewErrInvalidArgument("URL", err.Error())
+	}
+	host := u.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	_, err = allowed.lookup(host)
+	return err
+}
+
+// transport returns an HTTP transport that only connects to the addresses
+// that the integrations of applications are allowed to connect to. The
+// addresses are checked when connecting, so that a host name can not be
+// changed to resolve to a forbidden address after it was checked.
+func (allowed integrationHosts) transport() *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			ips, err := allowed.lookup(host)
+			if err != nil {
+				return nil, err
+			}
+			return dialer.DialContext(ctx, network, net.JoinHostPort(ips[0].String(), port))
+		},
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+func (h *handler) WithIntegrationHosts(hosts []string) Handler {
+	h.integrationHosts = parseIntegrationHosts(hosts)
+	return h
+}
+
+// integrationClient returns an HTTP client for the integrations of
+// applications. The clients share the connections of the Handler.
+func (h *handler) integrationClient(timeout time.Duration) *http.Client {
+	h.integrationTransportOnce.Do(func() {
+		h.integrationTransport = h.integrationHosts.transport()
+	})
+	return &http.Client{Transport: h.integrationTransport, Timeout: timeout}
+}
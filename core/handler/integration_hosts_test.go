@@ -0,0 +1,54 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestIntegrationHosts(t *testing.T) {
+	a := New(t)
+
+	var none integrationHosts
+	a.So(none.checkURL("http://8.8.8.8/webhook"), ShouldBeNil)
+	for _, url := range []string{
+		"http://127.0.0.1:8080/",
+		"http://localhost/",
+		"http://10.1.2.3/",
+		"http://169.254.169.254/latest/meta-data",
+		"postgres://ttn@192.168.1.2/ttn",
+		"http://[::1]:8086",
+	} {
+		a.So(none.checkURL(url), ShouldNotBeNil)
+	}
+
+	allowed := parseIntegrationHosts([]string{"10.0.0.0/8", "127.0.0.1", "localhost", ".internal.example"})
+	a.So(allowed.checkURL("http://10.1.2.3/"), ShouldBeNil)
+	a.So(allowed.checkURL("http://127.0.0.1:8080/"), ShouldBeNil)
+	a.So(allowed.allowsName("localhost"), ShouldBeTrue)
+	a.So(allowed.allowsName("influx.internal.example"), ShouldBeTrue)
+	a.So(allowed.allowsName("internal.example.com"), ShouldBeFalse)
+	a.So(allowed.checkURL("http://192.168.1.2/"), ShouldNotBeNil)
+}
+
+func TestIntegrationClient(t *testing.T) {
+	a := New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	h := &handler{}
+	_, err := h.integrationClient(RuleWebhookTimeout).Get(server.URL)
+	a.So(err, ShouldNotBeNil)
+
+	h = &handler{}
+	h.WithIntegrationHosts([]string{"127.0.0.1"})
+	res, err := h.integrationClient(RuleWebhookTimeout).Get(server.URL)
+	a.So(err, ShouldBeNil)
+	res.Body.Close()
+}
@@ -0,0 +1,107 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/brocaar/lorawan"
+)
+
+// validateJoinAcceptSettings validates join-accept settings. If the band is
+// known, the settings are also validated against the band.
+func validateJoinAcceptSettings(settings types.JoinAcceptSettings, bandName string) error {
+	if err := settings.Validate(); err != nil {
+		return errors.NewErrInvalidArgument("Join Accept", err.Error())
+	}
+	if bandName == "" {
+		return nil
+	}
+	fp, err := band.Get(bandName)
+	if err != nil {
+		return nil // We can't check this band
+	}
+	if settings.RX2DataRate != "" {
+		if _, err := fp.GetDataRateIndexFor(settings.RX2DataRate); err != nil {
+			return errors.NewErrInvalidArgument("RX2 Data Rate", fmt.Sprintf("%s is not available in %s", settings.RX2DataRate, bandName))
+		}
+	}
+	if settings.RX1DROffset != 0 {
+		if _, err := fp.GetRX1DataRate(0, int(settings.RX1DROffset)); err != nil {
+			return errors.NewErrInvalidArgument("RX1 Data Rate Offset", fmt.Sprintf("%d is not available in %s", settings.RX1DROffset, bandName))
+		}
+	}
+	return nil
+}
+
+// getJoinAcceptSettings returns the join-accept settings of the application,
+// overridden by those of the profile of the device and those of the device
+func (h *handler) getJoinAcceptSettings(dev *device.Device) types.JoinAcceptSettings {
+	var settings types.JoinAcceptSettings
+	if h.applications != nil {
+		if app, err := h.applications.Get(dev.AppID); err == nil {
+			settings = app.JoinAccept
+			if profile, ok := h.deviceProfile(app, dev); ok {
+				settings = settings.Merge(profile.JoinAccept)
+			}
+		}
+	}
+	return settings.Merge(dev.JoinAccept)
+}
+
+// applyJoinAcceptSettings sets the receive window parameters in the join-accept
+// and the activation metadata
+func applyJoinAcceptSettings(joinAccept *lorawan.JoinAcceptPayload, metadata *pb_lorawan.ActivationMetadata, settings types.JoinAcceptSettings) error {
+	if settings.IsEmpty() {
+		return nil
+	}
+	bandName := metadata.Region.String()
+	if err := validateJoinAcceptSettings(settings, bandName); err != nil {
+		return err
+	}
+	if settings.RX1Delay != 0 {
+		joinAccept.RXDelay = settings.RX1Delay
+		metadata.RxDelay = uint32(settings.RX1Delay)
+	}
+	if settings.RX1DROffset != 0 {
+		joinAccept.DLSettings.RX1DROffset = settings.RX1DROffset
+		metadata.Rx1DrOffset = uint32(settings.RX1DROffset)
+	}
+	if settings.RX2DataRate != "" {
+		fp, err := band.Get(bandName)
+		if err != nil {
+			return errors.NewErrInvalidArgument("RX2 Data Rate", fmt.Sprintf("the band %s is unknown", bandName))
+		}
+		drIdx, _ := fp.GetDataRateIndexFor(settings.RX2DataRate) // errors handled in validation
+		joinAccept.DLSettings.RX2DataRate = uint8(drIdx)
+		metadata.Rx2Dr = uint32(drIdx)
+	}
+	return nil
+}
+
+// downlinkRXSettings returns the receive window parameters of the last
+// join-accept and the downlink profile of the device, which the router uses
+// to build the downlink option again
+func downlinkRXSettings(settings types.JoinAcceptSettings, profile types.DownlinkProfile) *pb_broker.RXSettings {
+	rx := &pb_broker.RXSettings{
+		Rx1Delay:    uint32(settings.RX1Delay),
+		Rx1DrOffset: uint32(settings.RX1DROffset),
+		Rx2DataRate: settings.RX2DataRate,
+		Rx2Only:     profile.RX2Only,
+	}
+	if rx.Rx2DataRate == "" {
+		// The RX2 data rate of the last join-accept takes precedence
+		rx.Rx2DataRate = profile.RX2DataRate
+	}
+	if *rx == (pb_broker.RXSettings{}) {
+		return nil
+	}
+	return rx
+}
@@ -0,0 +1,74 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateJoinAcceptSettings(t *testing.T) {
+	a := New(t)
+	a.So(validateJoinAcceptSettings(types.JoinAcceptSettings{}, "EU_863_870"), ShouldBeNil)
+	a.So(validateJoinAcceptSettings(types.JoinAcceptSettings{RX1Delay: 5, RX1DROffset: 2, RX2DataRate: "SF9BW125"}, "EU_863_870"), ShouldBeNil)
+	a.So(validateJoinAcceptSettings(types.JoinAcceptSettings{RX1Delay: 16}, ""), ShouldNotBeNil)
+
+	// SF12BW125 is not available for downlink in the US band
+	a.So(validateJoinAcceptSettings(types.JoinAcceptSettings{RX2DataRate: "SF12BW125"}, ""), ShouldBeNil)
+	a.So(validateJoinAcceptSettings(types.JoinAcceptSettings{RX2DataRate: "SF12BW125"}, "US_902_928"), ShouldNotBeNil)
+	a.So(validateJoinAcceptSettings(types.JoinAcceptSettings{RX1DROffset: 7}, "EU_863_870"), ShouldNotBeNil)
+}
+
+func TestGetJoinAcceptSettings(t *testing.T) {
+	a := New(t)
+	h := &handler{}
+	dev := &device.Device{AppID: "app", JoinAccept: types.JoinAcceptSettings{RX1DROffset: 1}}
+	a.So(h.getJoinAcceptSettings(dev), ShouldResemble, types.JoinAcceptSettings{RX1DROffset: 1})
+
+	h.applications = application.NewMemoryApplicationStore()
+	h.applications.Set(&application.Application{AppID: "app", JoinAccept: types.JoinAcceptSettings{RX1Delay: 5, RX1DROffset: 2}})
+	a.So(h.getJoinAcceptSettings(dev), ShouldResemble, types.JoinAcceptSettings{RX1Delay: 5, RX1DROffset: 1})
+}
+
+func TestApplyJoinAcceptSettings(t *testing.T) {
+	a := New(t)
+
+	joinAccept := &lorawan.JoinAcceptPayload{RXDelay: 1, DLSettings: lorawan.DLSettings{RX2DataRate: 0}}
+	metadata := &pb_lorawan.ActivationMetadata{Region: pb_lorawan.Region_EU_863_870, RxDelay: 1}
+
+	a.So(applyJoinAcceptSettings(joinAccept, metadata, types.JoinAcceptSettings{}), ShouldBeNil)
+	a.So(joinAccept.RXDelay, ShouldEqual, 1)
+
+	err := applyJoinAcceptSettings(joinAccept, metadata, types.JoinAcceptSettings{RX1Delay: 5, RX1DROffset: 2, RX2DataRate: "SF9BW125"})
+	a.So(err, ShouldBeNil)
+	a.So(joinAccept.RXDelay, ShouldEqual, 5)
+	a.So(joinAccept.DLSettings.RX1DROffset, ShouldEqual, 2)
+	a.So(joinAccept.DLSettings.RX2DataRate, ShouldEqual, 3)
+	a.So(metadata.RxDelay, ShouldEqual, 5)
+	a.So(metadata.Rx1DrOffset, ShouldEqual, 2)
+	a.So(metadata.Rx2Dr, ShouldEqual, 3)
+
+	metadata.Region = pb_lorawan.Region_US_902_928
+	a.So(applyJoinAcceptSettings(joinAccept, metadata, types.JoinAcceptSettings{RX2DataRate: "SF12BW125"}), ShouldNotBeNil)
+}
+
+func TestDownlinkRXSettings(t *testing.T) {
+	a := New(t)
+
+	// Default settings are not sent to the router
+	a.So(downlinkRXSettings(types.JoinAcceptSettings{}, types.DownlinkProfile{}), ShouldBeNil)
+
+	settings := downlinkRXSettings(types.JoinAcceptSettings{RX1Delay: 5, RX1DROffset: 2, RX2DataRate: "SF12BW125"}, types.DownlinkProfile{})
+	a.So(settings, ShouldNotBeNil)
+	a.So(settings.Rx1Delay, ShouldEqual, 5)
+	a.So(settings.Rx1DrOffset, ShouldEqual, 2)
+	a.So(settings.Rx2DataRate, ShouldEqual, "SF12BW125")
+	a.So(settings.Rx2Only, ShouldBeFalse)
+}
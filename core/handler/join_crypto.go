@@ -0,0 +1,98 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/otaa"
+	"github.com/brocaar/lorawan"
+	"golang.org/x/net/context"
+)
+
+// JoinCrypto performs the cryptographic operations of the join procedure that
+// need the AppKey of a device. Implementations can delegate them to an HSM or
+// a key service, so that the AppKeys are never in the memory of the Handler.
+type JoinCrypto interface {
+	// HasAppKey returns whether the device has an AppKey
+	HasAppKey(dev *device.Device) bool
+	// StoresAppKeys returns whether the AppKeys are stored in the devices of the Handler
+	StoresAppKeys() bool
+	// SetAppKey stores the AppKey of the device
+	SetAppKey(ctx context.Context, dev *device.Device, appKey types.AppKey) error
+	// JoinRequestMIC calculates the MIC of the join request in the PHYPayload
+	JoinRequestMIC(ctx context.Context, dev *device.Device, phyPayload []byte) (lorawan.MIC, error)
+	// JoinAccept sets the MIC of the join-accept in the PHYPayload, encrypts it
+	// and derives the session keys of the activation
+	JoinAccept(ctx context.Context, dev *device.Device, phyPayload []byte, devNonce [2]byte) ([]byte, types.AppSKey, types.NwkSKey, error)
+}
+
+// WithJoinCrypto makes the Handler use the JoinCrypto instead of the AppKeys of its devices
+func (h *handler) WithJoinCrypto(crypto JoinCrypto) Handler {
+	h.joinCrypto = crypto
+	return h
+}
+
+func (h *handler) getJoinCrypto() JoinCrypto {
+	if h.joinCrypto == nil {
+		return localJoinCrypto{}
+	}
+	return h.joinCrypto
+}
+
+// localJoinCrypto uses the AppKeys that are stored in the devices
+type localJoinCrypto struct{}
+
+func (localJoinCrypto) HasAppKey(dev *device.Device) bool {
+	return !dev.AppKey.IsEmpty()
+}
+
+func (localJoinCrypto) StoresAppKeys() bool {
+	return true
+}
+
+func (localJoinCrypto) SetAppKey(_ context.Context, dev *device.Device, appKey types.AppKey) error {
+	dev.AppKey = appKey
+	return nil
+}
+
+func (localJoinCrypto) JoinRequestMIC(_ context.Context, dev *device.Device, phyPayload []byte) (mic lorawan.MIC, err error) {
+	var phy lorawan.PHYPayload
+	if err = phy.UnmarshalBinary(phyPayload); err != nil {
+		return
+	}
+	if err = phy.SetMIC(lorawan.AES128Key(dev.AppKey)); err != nil {
+		return
+	}
+	return phy.MIC, nil
+}
+
+func (localJoinCrypto) JoinAccept(_ context.Context, dev *device.Device, phyPayload []byte, devNonce [2]byte) (sealed []byte, appSKey types.AppSKey, nwkSKey types.NwkSKey, err error) {
+	var phy lorawan.PHYPayload
+	if err = phy.UnmarshalBinary(phyPayload); err != nil {
+		return
+	}
+	payload, ok := phy.MACPayload.(*lorawan.DataPayload)
+	if !ok {
+		err = errors.NewErrInvalidArgument("Join Accept", "MACPayload must be a *DataPayload")
+		return
+	}
+	joinAccept := &lorawan.JoinAcceptPayload{}
+	if err = joinAccept.UnmarshalBinary(false, payload.Bytes); err != nil {
+		return
+	}
+	if appSKey, nwkSKey, err = otaa.CalculateSessionKeys(dev.AppKey, joinAccept.AppNonce, joinAccept.NetID, devNonce); err != nil {
+		return
+	}
+	phy.MACPayload = joinAccept
+	if err = phy.SetMIC(lorawan.AES128Key(dev.AppKey)); err != nil {
+		return
+	}
+	if err = phy.EncryptJoinAcceptPayload(lorawan.AES128Key(dev.AppKey)); err != nil {
+		return
+	}
+	sealed, err = phy.MarshalBinary()
+	return
+}
@@ -0,0 +1,103 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/brocaar/lorawan"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// JoinCryptoTimeout is the time that the Handler waits for the JoinCrypto in
+// the handling of a join request
+var JoinCryptoTimeout = 2 * time.Second
+
+// RemoteJoinCrypto delegates the cryptographic operations of the join procedure
+// to a key service that implements the JoinCrypto gRPC service of the Handler
+// API, such as an adapter for an HSM. The key service identifies the AppKey by
+// the AppEUI and DevEUI of the device.
+type RemoteJoinCrypto struct {
+	client pb.JoinCryptoClient
+	token  string
+}
+
+// NewRemoteJoinCrypto returns a JoinCrypto that uses the key service on the
+// connection, authenticating with the token
+func NewRemoteJoinCrypto(conn *grpc.ClientConn, token string) *RemoteJoinCrypto {
+	return &RemoteJoinCrypto{client: pb.NewJoinCryptoClient(conn), token: token}
+}
+
+func (c *RemoteJoinCrypto) context(ctx context.Context) context.Context {
+	return metadata.NewContext(ctx, metadata.Pairs("token", c.token))
+}
+
+func newJoinCryptoRequest(dev *device.Device) *pb.JoinCryptoRequest {
+	return &pb.JoinCryptoRequest{AppId: dev.AppID, DevId: dev.DevID, AppEui: dev.AppEUI.Bytes(), DevEui: dev.DevEUI.Bytes()}
+}
+
+// HasAppKey implements JoinCrypto. The key service returns an error if it has no AppKey for the device.
+func (c *RemoteJoinCrypto) HasAppKey(dev *device.Device) bool {
+	return true
+}
+
+// StoresAppKeys implements JoinCrypto
+func (c *RemoteJoinCrypto) StoresAppKeys() bool {
+	return false
+}
+
+// SetAppKey implements JoinCrypto
+func (c *RemoteJoinCrypto) SetAppKey(ctx context.Context, dev *device.Device, appKey types.AppKey) error {
+	req := newJoinCryptoRequest(dev)
+	req.AppKey = appKey.Bytes()
+	if _, err := c.client.SetAppKey(c.context(ctx), req); err != nil {
+		return errors.Wrap(errors.FromGRPCError(err), "Key service did not set AppKey")
+	}
+	return nil
+}
+
+// JoinRequestMIC implements JoinCrypto
+func (c *RemoteJoinCrypto) JoinRequestMIC(ctx context.Context, dev *device.Device, phyPayload []byte) (mic lorawan.MIC, err error) {
+	req := newJoinCryptoRequest(dev)
+	req.Payload = phyPayload
+	res, err := c.client.JoinRequestMIC(c.context(ctx), req)
+	if err != nil {
+		return mic, errors.FromGRPCError(err)
+	}
+	if len(res.Mic) != len(mic) {
+		return mic, errors.NewErrInternal("Key service returned invalid MIC")
+	}
+	copy(mic[:], res.Mic)
+	return mic, nil
+}
+
+// JoinAccept implements JoinCrypto
+func (c *RemoteJoinCrypto) JoinAccept(ctx context.Context, dev *device.Device, phyPayload []byte, devNonce [2]byte) (sealed []byte, appSKey types.AppSKey, nwkSKey types.NwkSKey, err error) {
+	req := newJoinCryptoRequest(dev)
+	req.Payload = phyPayload
+	req.DevNonce = devNonce[:]
+	req.EndToEnd = dev.EndToEnd
+	res, err := c.client.JoinAccept(c.context(ctx), req)
+	if err != nil {
+		return nil, appSKey, nwkSKey, errors.FromGRPCError(err)
+	}
+	if len(res.Payload) != len(phyPayload) {
+		return nil, appSKey, nwkSKey, errors.NewErrInternal("Key service returned invalid join-accept")
+	}
+	if (len(res.AppSKey) != len(appSKey) && !dev.EndToEnd) || len(res.NwkSKey) != len(nwkSKey) {
+		return nil, appSKey, nwkSKey, errors.NewErrInternal("Key service returned invalid session keys")
+	}
+	copy(appSKey[:], res.AppSKey)
+	copy(nwkSKey[:], res.NwkSKey)
+	if (appSKey.IsEmpty() && !dev.EndToEnd) || nwkSKey.IsEmpty() {
+		return nil, appSKey, nwkSKey, errors.NewErrInternal("Key service returned empty session keys")
+	}
+	return res.Payload, appSKey, nwkSKey, nil
+}
@@ -0,0 +1,237 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/otaa"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/brocaar/lorawan"
+	"github.com/golang/protobuf/ptypes/empty"
+	. "github.com/smartystreets/assertions"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// testKeyService is a key service that uses the AppKeys of the devices
+type testKeyService struct {
+	devices []*device.Device
+}
+
+func (s *testKeyService) device(ctx context.Context, req *pb.JoinCryptoRequest) (*device.Device, error) {
+	md, _ := metadata.FromContext(ctx)
+	if token := md["token"]; len(token) != 1 || token[0] != "secret" {
+		return nil, errors.NewErrPermissionDenied("Invalid token")
+	}
+	for _, dev := range s.devices {
+		if bytes.Equal(dev.AppEUI.Bytes(), req.AppEui) && bytes.Equal(dev.DevEUI.Bytes(), req.DevEui) {
+			return dev, nil
+		}
+	}
+	return nil, errors.NewErrNotFound("AppKey")
+}
+
+func (s *testKeyService) JoinRequestMIC(ctx context.Context, req *pb.JoinCryptoRequest) (*pb.JoinCryptoResponse, error) {
+	dev, err := s.device(ctx, req)
+	if err != nil {
+		return nil, errors.BuildGRPCError(err)
+	}
+	mic, err := localJoinCrypto{}.JoinRequestMIC(ctx, dev, req.Payload)
+	if err != nil {
+		return nil, errors.BuildGRPCError(err)
+	}
+	return &pb.JoinCryptoResponse{Mic: mic[:]}, nil
+}
+
+func (s *testKeyService) JoinAccept(ctx context.Context, req *pb.JoinCryptoRequest) (*pb.JoinCryptoResponse, error) {
+	dev, err := s.device(ctx, req)
+	if err != nil {
+		return nil, errors.BuildGRPCError(err)
+	}
+	var devNonce [2]byte
+	copy(devNonce[:], req.DevNonce)
+	payload, appSKey, nwkSKey, err := localJoinCrypto{}.JoinAccept(ctx, dev, req.Payload, devNonce)
+	if err != nil {
+		return nil, errors.BuildGRPCError(err)
+	}
+	res := &pb.JoinCryptoResponse{Payload: payload, NwkSKey: nwkSKey.Bytes()}
+	if !req.EndToEnd {
+		res.AppSKey = appSKey.Bytes()
+	}
+	return res, nil
+}
+
+func (s *testKeyService) SetAppKey(ctx context.Context, req *pb.JoinCryptoRequest) (*empty.Empty, error) {
+	dev, err := s.device(ctx, req)
+	if err != nil {
+		return nil, errors.BuildGRPCError(err)
+	}
+	copy(dev.AppKey[:], req.AppKey)
+	return &empty.Empty{}, nil
+}
+
+// newTestKeyService starts a key service and returns a RemoteJoinCrypto that uses it
+func newTestKeyService(devices ...*device.Device) (*RemoteJoinCrypto, *grpc.Server) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := grpc.NewServer()
+	pb.RegisterJoinCryptoServer(s, &testKeyService{devices: devices})
+	go s.Serve(lis)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		panic(err)
+	}
+	return NewRemoteJoinCrypto(conn, "secret"), s
+}
+
+func TestJoinCrypto(t *testing.T) {
+	a := New(t)
+
+	appKey := types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	dev := &device.Device{
+		AppID:  "app",
+		DevID:  "dev",
+		AppEUI: types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8},
+		DevEUI: types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1},
+		AppKey: appKey,
+	}
+	stored := *dev
+
+	remote, srv := newTestKeyService(&stored)
+	defer srv.Stop()
+	ctx := context.Background()
+
+	a.So(localJoinCrypto{}.StoresAppKeys(), ShouldBeTrue)
+	a.So(localJoinCrypto{}.HasAppKey(dev), ShouldBeTrue)
+	a.So(localJoinCrypto{}.HasAppKey(&device.Device{}), ShouldBeFalse)
+	a.So(remote.StoresAppKeys(), ShouldBeFalse)
+
+	// The MIC of the join request
+	reqPHY := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{MType: lorawan.JoinRequest, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.JoinRequestPayload{
+			AppEUI:   lorawan.EUI64(dev.AppEUI),
+			DevEUI:   lorawan.EUI64(dev.DevEUI),
+			DevNonce: [2]byte{1, 2},
+		},
+	}
+	reqPHY.SetMIC(lorawan.AES128Key(appKey))
+	reqBytes, _ := reqPHY.MarshalBinary()
+
+	mic, err := localJoinCrypto{}.JoinRequestMIC(ctx, dev, reqBytes)
+	a.So(err, ShouldBeNil)
+	a.So(mic, ShouldEqual, reqPHY.MIC)
+
+	mic, err = remote.JoinRequestMIC(ctx, dev, reqBytes)
+	a.So(err, ShouldBeNil)
+	a.So(mic, ShouldEqual, reqPHY.MIC)
+
+	// The join-accept and the session keys
+	resPHY := lorawan.PHYPayload{
+		MHDR:       lorawan.MHDR{MType: lorawan.JoinAccept, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.JoinAcceptPayload{AppNonce: [3]byte{1, 2, 3}, NetID: [3]byte{0, 0, 0x13}},
+	}
+	resBytes, _ := resPHY.MarshalBinary()
+
+	sealed, appSKey, nwkSKey, err := localJoinCrypto{}.JoinAccept(ctx, dev, resBytes, [2]byte{1, 2})
+	a.So(err, ShouldBeNil)
+	remoteSealed, remoteAppSKey, remoteNwkSKey, err := remote.JoinAccept(ctx, dev, resBytes, [2]byte{1, 2})
+	a.So(err, ShouldBeNil)
+	a.So(remoteSealed, ShouldResemble, sealed)
+
+	var sealedPHY lorawan.PHYPayload
+	a.So(sealedPHY.UnmarshalBinary(sealed), ShouldBeNil)
+	a.So(sealedPHY.DecryptJoinAcceptPayload(lorawan.AES128Key(appKey)), ShouldBeNil)
+	ok, err := sealedPHY.ValidateMIC(lorawan.AES128Key(appKey))
+	a.So(err, ShouldBeNil)
+	a.So(ok, ShouldBeTrue)
+
+	expectedAppSKey, expectedNwkSKey, _ := otaa.CalculateSessionKeys(appKey, [3]byte{1, 2, 3}, [3]byte{0, 0, 0x13}, [2]byte{1, 2})
+	a.So(appSKey, ShouldEqual, expectedAppSKey)
+	a.So(nwkSKey, ShouldEqual, expectedNwkSKey)
+	a.So(remoteAppSKey, ShouldEqual, expectedAppSKey)
+	a.So(remoteNwkSKey, ShouldEqual, expectedNwkSKey)
+
+	// The AppSKey of devices with end-to-end encryption is not returned
+	dev.EndToEnd = true
+	_, remoteAppSKey, remoteNwkSKey, err = remote.JoinAccept(ctx, dev, resBytes, [2]byte{1, 2})
+	a.So(err, ShouldBeNil)
+	a.So(remoteAppSKey.IsEmpty(), ShouldBeTrue)
+	a.So(remoteNwkSKey, ShouldEqual, expectedNwkSKey)
+	dev.EndToEnd = false
+
+	// The AppKey is stored in the key service
+	newAppKey := types.AppKey{8, 7, 6, 5, 4, 3, 2, 1, 8, 7, 6, 5, 4, 3, 2, 1}
+	a.So(remote.SetAppKey(ctx, dev, newAppKey), ShouldBeNil)
+	a.So(stored.AppKey, ShouldEqual, newAppKey)
+	a.So(dev.AppKey, ShouldEqual, appKey)
+
+	// Devices that are unknown to the key service
+	_, err = remote.JoinRequestMIC(ctx, &device.Device{DevID: "other"}, reqBytes)
+	a.So(errors.GetErrType(err), ShouldEqual, errors.NotFound)
+
+	// Wrong token
+	_, err = (&RemoteJoinCrypto{client: remote.client, token: "wrong"}).JoinRequestMIC(ctx, dev, reqBytes)
+	a.So(errors.GetErrType(err), ShouldEqual, errors.PermissionDenied)
+}
+
+func TestHandleActivationWithJoinCrypto(t *testing.T) {
+	a := New(t)
+
+	appKey := types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	devEUI := types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1}
+
+	remote, srv := newTestKeyService(&device.Device{AppEUI: appEUI, DevEUI: devEUI, AppKey: appKey})
+	defer srv.Stop()
+
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestHandleActivationWithJoinCrypto")},
+		applications: application.NewRedisApplicationStore(GetRedisClient(), "handler-test-join-crypto"),
+		devices:      device.NewRedisDeviceStore(GetRedisClient(), "handler-test-join-crypto"),
+	}
+	h.InitStatus()
+	h.mqttEvent = make(chan *types.DeviceEvent, 10)
+	h.WithJoinCrypto(remote)
+
+	h.applications.Set(&application.Application{AppID: appEUI.String()})
+	defer h.applications.Delete(appEUI.String())
+	h.devices.Set(&device.Device{
+		AppID:  appEUI.String(),
+		DevID:  devEUI.String(),
+		AppEUI: appEUI,
+		DevEUI: devEUI,
+	})
+	defer h.devices.Delete(appEUI.String(), devEUI.String())
+
+	res, err := doTestHandleActivation(h, appEUI, devEUI, [2]byte{1, 2}, appKey)
+	a.So(err, ShouldBeNil)
+	a.So(res, ShouldNotBeNil)
+
+	var resPHY lorawan.PHYPayload
+	a.So(resPHY.UnmarshalBinary(res.Payload), ShouldBeNil)
+	a.So(resPHY.DecryptJoinAcceptPayload(lorawan.AES128Key(appKey)), ShouldBeNil)
+	ok, _ := resPHY.ValidateMIC(lorawan.AES128Key(appKey))
+	a.So(ok, ShouldBeTrue)
+
+	dev, _ := h.devices.Get(appEUI.String(), devEUI.String())
+	a.So(dev.AppKey.IsEmpty(), ShouldBeTrue)
+	a.So(dev.NwkSKey.IsEmpty(), ShouldBeFalse)
+
+	// The MIC of join requests with another AppKey does not match
+	_, err = doTestHandleActivation(h, appEUI, devEUI, [2]byte{1, 3}, types.AppKey{})
+	a.So(err, ShouldNotBeNil)
+}
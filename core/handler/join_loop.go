@@ -0,0 +1,39 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+var errJoinLoop = errors.NewErrPermissionDenied("Device exceeded its join rate")
+
+// publishJoinLoop publishes an activation loop event if the Broker throttled
+// the join requests of the device, and returns true if it did. The join
+// request that started the penalty is not accepted.
+func (h *handler) publishJoinLoop(activation *pb_broker.DeduplicatedDeviceActivationRequest) bool {
+	if activation.JoinLoop == nil {
+		return false
+	}
+	data := types.ActivationLoopEventData{
+		Penalty: time.Duration(activation.JoinLoop.Penalty).String(),
+	}
+	if activation.AppEui != nil {
+		data.AppEUI = *activation.AppEui
+	}
+	if activation.DevEui != nil {
+		data.DevEUI = *activation.DevEui
+	}
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: activation.AppId,
+		DevID: activation.DevId,
+		Event: types.ActivationLoopEvent,
+		Data:  data,
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPublishJoinLoop(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	devEUI := types.DevEUI{8, 7, 6, 5, 4, 3, 2, 1}
+	activation := &pb_broker.DeduplicatedDeviceActivationRequest{AppId: "app", DevId: "dev", AppEui: &appEUI, DevEui: &devEUI}
+	a.So(h.publishJoinLoop(activation), ShouldBeFalse)
+
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	activation.JoinLoop = &pb_broker.JoinLoop{Penalty: (2 * time.Minute).Nanoseconds()}
+	a.So(h.publishJoinLoop(activation), ShouldBeTrue)
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+
+	event := <-h.mqttEvent
+	a.So(event.DevID, ShouldEqual, "dev")
+	a.So(event.Event, ShouldEqual, types.ActivationLoopEvent)
+	a.So(event.Data, ShouldResemble, types.ActivationLoopEventData{
+		AppEUI:  appEUI,
+		DevEUI:  devEUI,
+		Penalty: "2m0s",
+	})
+}
@@ -0,0 +1,325 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/Shopify/sarama"
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/bluele/gcache"
+)
+
+// KafkaBufferSize is the number of uplink messages and events that are
+// buffered for Kafka. Messages are dropped if the buffer is full.
+var KafkaBufferSize = 1000
+
+// KafkaKeyCacheSize and KafkaKeyCacheExpiration configure the cache of the
+// DevEUIs that are used as the keys of the messages of devices
+var (
+	KafkaKeyCacheSize       = 10000
+	KafkaKeyCacheExpiration = time.Hour
+)
+
+// KafkaConfig configures the Kafka integration of the Handler
+type KafkaConfig struct {
+	Brokers []string
+	// UplinkTopic and EventTopic are the topics that uplink messages and
+	// events are published to, DownlinkTopic is the topic that downlink
+	// messages are consumed from. Empty topics are disabled.
+	UplinkTopic   string
+	EventTopic    string
+	DownlinkTopic string
+	// ConsumerGroup is the consumer group that the offsets of the downlink
+	// topic are committed to, so that downlinks that are produced while the
+	// Handler is not running are consumed when it starts. The default is
+	// ttn-handler-<handler ID>.
+	ConsumerGroup string
+	// TLS enables TLS, with the CA certificates in the TLSCA file or the system roots
+	TLS   bool
+	TLSCA string
+	// SASLUsername and SASLPassword enable SASL/PLAIN authentication
+	SASLUsername string
+	SASLPassword string
+}
+
+// KafkaEvent is published on the event topic
+type KafkaEvent struct {
+	AppID string `json:"app_id"`
+	StreamEvent
+}
+
+func (c KafkaConfig) saramaConfig() (*sarama.Config, error) {
+	config := sarama.NewConfig()
+	config.ClientID = "ttn-handler"
+	config.Version = sarama.V0_10_0_0
+	config.Producer.Return.Errors = true
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	if c.TLS {
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = &tls.Config{}
+		if c.TLSCA != "" {
+			pem, err := ioutil.ReadFile(c.TLSCA)
+			if err != nil {
+				return nil, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, errors.NewErrInvalidArgument("Kafka TLS CA", "no certificates found")
+			}
+			config.Net.TLS.Config.RootCAs = pool
+		}
+	}
+	if c.SASLUsername != "" {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = c.SASLUsername
+		config.Net.SASL.Password = c.SASLPassword
+	}
+	return config, config.Validate()
+}
+
+// initKafkaKeys initializes the cache of the DevEUIs of devices. Devices that
+// are not in the cache are looked up once and then cached, also if they are
+// not found.
+func (h *handler) initKafkaKeys() {
+	h.kafkaKeys = gcache.New(KafkaKeyCacheSize).Expiration(KafkaKeyCacheExpiration).LRU().
+		LoaderFunc(func(key interface{}) (interface{}, error) {
+			id := key.(kafkaDevice)
+			if dev, err := h.devices.Get(id.appID, id.devID); err == nil && !dev.DevEUI.IsEmpty() {
+				return dev.DevEUI.String(), nil
+			}
+			return "", nil
+		}).
+		Build()
+}
+
+type kafkaDevice struct {
+	appID, devID string
+}
+
+// kafkaKey returns the key of the messages of a device, so that they end up in the same partition
+func (h *handler) kafkaKey(appID, devID, devEUI string) string {
+	if devID == "" {
+		return appID
+	}
+	id := kafkaDevice{appID, devID}
+	if devEUI != "" {
+		h.kafkaKeys.Set(id, devEUI)
+		return devEUI
+	}
+	if cached, err := h.kafkaKeys.Get(id); err == nil && cached.(string) != "" {
+		return cached.(string)
+	}
+	return appID + "/" + devID
+}
+
+func (h *handler) kafkaUplinkMessage(topic string, up *types.UplinkMessage) (*sarama.ProducerMessage, error) {
+	data, err := types.MarshalUplinkMessage(h.payloadFormat(up.AppID), *up)
+	if err != nil {
+		return nil, err
+	}
+	return &sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(h.kafkaKey(up.AppID, up.DevID, up.HardwareSerial)),
+		Value: sarama.ByteEncoder(data),
+	}, nil
+}
+
+func (h *handler) kafkaEventMessage(topic string, event *types.DeviceEvent) (*sarama.ProducerMessage, error) {
+	streamEvent, err := newLiveEvent(event.DevID, string(event.Event), event.Data)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(KafkaEvent{AppID: event.AppID, StreamEvent: *streamEvent})
+	if err != nil {
+		return nil, err
+	}
+	return &sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(h.kafkaKey(event.AppID, event.DevID, "")),
+		Value: sarama.ByteEncoder(data),
+	}, nil
+}
+
+// handleKafkaDownlink enqueues a downlink message that was consumed from the
+// downlink topic. Downlink messages of applications that are not registered
+// to this Handler are skipped, as all Handlers consume the downlink topic.
+func (h *handler) handleKafkaDownlink(value []byte) error {
+	var downlink types.DownlinkMessage
+	if err := json.Unmarshal(value, &downlink); err != nil {
+		return errors.NewErrInvalidArgument("Downlink", err.Error())
+	}
+	if downlink.AppID == "" || downlink.DevID == "" {
+		return errors.NewErrInvalidArgument("Downlink", "app_id and dev_id are required")
+	}
+	if _, err := h.applications.Get(downlink.AppID); errors.GetErrType(err) == errors.NotFound {
+		return nil
+	}
+	return h.EnqueueDownlink(&downlink)
+}
+
+func (h *handler) HandleKafka(config KafkaConfig) (err error) {
+	saramaConfig, err := config.saramaConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx := h.Ctx.WithField("Protocol", "Kafka")
+
+	h.initKafkaKeys()
+
+	defer func() {
+		if err != nil {
+			h.closeKafka()
+		}
+	}()
+
+	if config.UplinkTopic != "" || config.EventTopic != "" {
+		h.kafkaProducer, err = sarama.NewAsyncProducer(config.Brokers, saramaConfig)
+		if err != nil {
+			return err
+		}
+		go func() {
+			for err := range h.kafkaProducer.Errors() {
+				ctx.WithError(err.Err).WithField("Topic", err.Msg.Topic).Warn("Could not publish message")
+			}
+		}()
+
+		sub := &Subscription{
+			Uplink: make(chan *types.UplinkMessage, KafkaBufferSize),
+			Events: make(chan *types.DeviceEvent, KafkaBufferSize),
+		}
+		h.subscriptions.add(sub)
+		h.kafkaSubscription = sub
+		go func() {
+			// The producer is closed when the subscription is removed
+			defer h.kafkaProducer.AsyncClose()
+			for {
+				var msg *sarama.ProducerMessage
+				var err error
+				select {
+				case up, ok := <-sub.Uplink:
+					if !ok {
+						return
+					}
+					if config.UplinkTopic == "" {
+						continue
+					}
+					ctx.WithFields(ttnlog.Fields{
+						"DevID": up.DevID,
+						"AppID": up.AppID,
+					}).Debug("Publish Uplink")
+					msg, err = h.kafkaUplinkMessage(config.UplinkTopic, up)
+				case event, ok := <-sub.Events:
+					if !ok {
+						return
+					}
+					if config.EventTopic == "" {
+						continue
+					}
+					msg, err = h.kafkaEventMessage(config.EventTopic, event)
+				}
+				if err != nil {
+					ctx.WithError(err).Warn("Could not marshal message")
+					continue
+				}
+				h.kafkaProducer.Input() <- msg
+			}
+		}()
+	}
+
+	if config.DownlinkTopic != "" {
+		group := config.ConsumerGroup
+		if group == "" {
+			group = fmt.Sprintf("ttn-handler-%s", h.Identity.Id)
+		}
+		h.kafkaClient, err = sarama.NewClient(config.Brokers, saramaConfig)
+		if err != nil {
+			return err
+		}
+		h.kafkaOffsets, err = sarama.NewOffsetManagerFromClient(group, h.kafkaClient)
+		if err != nil {
+			return err
+		}
+		h.kafkaConsumer, err = sarama.NewConsumerFromClient(h.kafkaClient)
+		if err != nil {
+			return err
+		}
+		partitions, err := h.kafkaConsumer.Partitions(config.DownlinkTopic)
+		if err != nil {
+			return err
+		}
+		for _, partition := range partitions {
+			if err := h.consumeKafkaDownlinks(ctx, config.DownlinkTopic, partition); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// consumeKafkaDownlinks consumes the partition of the downlink topic from the
+// offset that was last committed to the consumer group of the Handler
+func (h *handler) consumeKafkaDownlinks(ctx ttnlog.Interface, topic string, partition int32) error {
+	partitionOffsets, err := h.kafkaOffsets.ManagePartition(topic, partition)
+	if err != nil {
+		return err
+	}
+	h.kafkaPartitionOffsets = append(h.kafkaPartitionOffsets, partitionOffsets)
+	offset, _ := partitionOffsets.NextOffset()
+	partitionConsumer, err := h.kafkaConsumer.ConsumePartition(topic, partition, offset)
+	if err != nil {
+		return err
+	}
+	ctx = ctx.WithField("Partition", partition)
+	go func() {
+		for err := range partitionOffsets.Errors() {
+			ctx.WithError(err.Err).Warn("Could not commit downlink offset")
+		}
+	}()
+	go func() {
+		for err := range partitionConsumer.Errors() {
+			ctx.WithError(err.Err).Warn("Could not consume downlink")
+		}
+	}()
+	go func() {
+		for msg := range partitionConsumer.Messages() {
+			if err := h.handleKafkaDownlink(msg.Value); err != nil {
+				ctx.WithError(err).Warn("Could not enqueue downlink")
+			}
+			partitionOffsets.MarkOffset(msg.Offset+1, "")
+		}
+	}()
+	return nil
+}
+
+func (h *handler) closeKafka() {
+	if h.kafkaSubscription != nil {
+		h.subscriptions.remove(h.kafkaSubscription)
+	} else if h.kafkaProducer != nil {
+		h.kafkaProducer.AsyncClose()
+	}
+	if h.kafkaConsumer != nil {
+		h.kafkaConsumer.Close()
+	}
+	// Closing the partition offset managers commits the last offsets
+	for _, partitionOffsets := range h.kafkaPartitionOffsets {
+		partitionOffsets.Close()
+	}
+	if h.kafkaOffsets != nil {
+		h.kafkaOffsets.Close()
+	}
+	if h.kafkaClient != nil {
+		h.kafkaClient.Close()
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestKafkaConfig(t *testing.T) {
+	a := New(t)
+
+	config, err := KafkaConfig{TLS: true, SASLUsername: "ttn", SASLPassword: "secret"}.saramaConfig()
+	a.So(err, ShouldBeNil)
+	a.So(config.Net.TLS.Enable, ShouldBeTrue)
+	a.So(config.Net.SASL.Enable, ShouldBeTrue)
+	a.So(config.Net.SASL.User, ShouldEqual, "ttn")
+
+	_, err = KafkaConfig{TLS: true, TLSCA: "does-not-exist.pem"}.saramaConfig()
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestKafkaMessages(t *testing.T) {
+	a := New(t)
+
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestKafkaMessages")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+	h.applications.Set(&application.Application{AppID: "app"})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev", DevEUI: types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}})
+	h.initKafkaKeys()
+
+	a.So(h.kafkaKey("app", "dev", ""), ShouldEqual, "0102030405060708")
+	a.So(h.kafkaKey("app", "other", ""), ShouldEqual, "app/other")
+	a.So(h.kafkaKey("app", "", ""), ShouldEqual, "app")
+
+	// The DevEUI of an uplink is cached
+	a.So(h.kafkaKey("app", "other", "0807060504030201"), ShouldEqual, "0807060504030201")
+	a.So(h.kafkaKey("app", "other", ""), ShouldEqual, "0807060504030201")
+
+	up, err := h.kafkaUplinkMessage("ttn.uplink", &types.UplinkMessage{AppID: "app", DevID: "dev", HardwareSerial: "0102030405060708", FPort: 1})
+	a.So(err, ShouldBeNil)
+	a.So(up.Topic, ShouldEqual, "ttn.uplink")
+	key, _ := up.Key.Encode()
+	a.So(string(key), ShouldEqual, "0102030405060708")
+	value, _ := up.Value.Encode()
+	var uplink types.UplinkMessage
+	a.So(json.Unmarshal(value, &uplink), ShouldBeNil)
+	a.So(uplink.DevID, ShouldEqual, "dev")
+
+	event, err := h.kafkaEventMessage("ttn.events", &types.DeviceEvent{AppID: "app", DevID: "dev", Event: types.ActivationEvent})
+	a.So(err, ShouldBeNil)
+	key, _ = event.Key.Encode()
+	a.So(string(key), ShouldEqual, "0102030405060708")
+	value, _ = event.Value.Encode()
+	var kafkaEvent KafkaEvent
+	a.So(json.Unmarshal(value, &kafkaEvent), ShouldBeNil)
+	a.So(kafkaEvent.AppID, ShouldEqual, "app")
+	a.So(kafkaEvent.Event, ShouldEqual, string(types.ActivationEvent))
+}
+
+func TestHandleKafkaDownlink(t *testing.T) {
+	a := New(t)
+
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestHandleKafkaDownlink")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+		mqttEvent:    make(chan *types.DeviceEvent, 10),
+	}
+	h.applications.Set(&application.Application{AppID: "app"})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev"})
+	h.devices.Set(&device.Device{AppID: "other-app", DevID: "dev"})
+
+	// Downlinks of applications of other Handlers are skipped
+	a.So(h.handleKafkaDownlink([]byte(`{"app_id":"other-app","dev_id":"dev","port":1,"payload_raw":"AQ=="}`)), ShouldBeNil)
+	queue, _ := h.devices.DownlinkQueue("other-app", "dev")
+	next, _ := queue.Next()
+	a.So(next, ShouldBeNil)
+
+	a.So(h.handleKafkaDownlink([]byte("not json")), ShouldNotBeNil)
+	a.So(h.handleKafkaDownlink([]byte(`{"port":1,"payload_raw":"AQ=="}`)), ShouldNotBeNil)
+	a.So(h.handleKafkaDownlink([]byte(`{"app_id":"app","dev_id":"dev","port":1,"payload_raw":"AQ=="}`)), ShouldBeNil)
+
+	queue, _ = h.devices.DownlinkQueue("app", "dev")
+	next, _ = queue.Next()
+	a.So(next, ShouldNotBeNil)
+	a.So(next.PayloadRaw, ShouldResemble, []byte{0x01})
+}
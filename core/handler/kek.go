@@ -0,0 +1,70 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/kek"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc"
+)
+
+func (h *handler) WithKEKs(store kek.Store, partners map[string]string) Handler {
+	h.keks = store
+	h.kekPartners = partners
+	return h
+}
+
+// wrapNwkSKey wraps the NwkSKey with the KEK of the Broker, or returns nil if
+// the Handler does not share a KEK with the Broker
+func (h *handler) wrapNwkSKey(brokerID string, nwkSKey *types.NwkSKey) (*kek.KeyEnvelope, error) {
+	label, ok := h.kekPartners[brokerID]
+	if !ok || h.keks == nil || nwkSKey == nil {
+		return nil, nil
+	}
+	envelope, err := kek.Wrap(h.keks, label, nwkSKey.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Could not wrap NwkSKey for %s", brokerID))
+	}
+	return envelope, nil
+}
+
+// wrapSessionKeys wraps the NwkSKey of the activation with the KEK of the
+// Broker that forwards the activation to its NetworkServer. The wrapped key is
+// sent in the header of the response, instead of the NwkSKey in the response.
+func (h *handler) wrapSessionKeys(ctx context.Context, brokerID string, res *pb.DeviceActivationResponse) error {
+	lorawan := res.GetActivationMetadata().GetLorawan()
+	if lorawan == nil {
+		return nil
+	}
+	envelope, err := h.wrapNwkSKey(brokerID, lorawan.NwkSKey)
+	if err != nil || envelope == nil {
+		return err
+	}
+	lorawan.NwkSKey = &types.NwkSKey{}
+	return grpc.SendHeader(ctx, envelope.Metadata(kek.NwkSKey))
+}
+
+// setNetworkServerDevice sets the device in the NetworkServer behind the
+// Broker. The NwkSKey is wrapped with the KEK of the Broker and sent in the
+// metadata of the request, instead of the NwkSKey in the device.
+func (h *handlerManager) setNetworkServerDevice(ctx context.Context, dev *pb_lorawan.Device) error {
+	envelope, err := h.handler.wrapNwkSKey(h.handler.ttnBrokerID, dev.NwkSKey)
+	if err != nil {
+		return err
+	}
+	if envelope != nil {
+		wrapped := *dev
+		wrapped.NwkSKey = &types.NwkSKey{}
+		dev = &wrapped
+		ctx = envelope.AppendToContext(ctx, kek.NwkSKey)
+	}
+	_, err = h.deviceManager.SetDevice(ctx, dev)
+	return err
+}
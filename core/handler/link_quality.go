@@ -0,0 +1,29 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// publishLinkQuality publishes the change of the link quality level that the
+// NetworkServer added to the uplink
+func (h *handler) publishLinkQuality(uplink *pb_broker.DeduplicatedUplinkMessage) {
+	change := uplink.LinkQuality
+	if change == nil {
+		return
+	}
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: uplink.AppId,
+		DevID: uplink.DevId,
+		Event: types.LinkQualityEvent,
+		Data: types.LinkQualityEventData{
+			Quality:  change.Quality,
+			Previous: change.Previous,
+			Score:    int(change.Score),
+			Loss:     change.Loss,
+		},
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPublishLinkQuality(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+
+	uplink := &pb_broker.DeduplicatedUplinkMessage{AppId: "app", DevId: "dev"}
+	h.publishLinkQuality(uplink)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	uplink.LinkQuality = &pb_broker.LinkQualityChange{Quality: "fair", Previous: "good", Score: 76, Loss: 0.12}
+	h.publishLinkQuality(uplink)
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+
+	event := <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.LinkQualityEvent)
+	a.So(event.Data, ShouldResemble, types.LinkQualityEventData{
+		Quality:  "fair",
+		Previous: "good",
+		Score:    76,
+		Loss:     0.12,
+	})
+}
@@ -36,10 +36,16 @@ type handlerManager struct {
 	clientRate      *ratelimit.Registry
 }
 
-func checkAppRights(claims *claims.Claims, appID string, right rights.Right) error {
+// checkAppRights checks the rights in the claims, limited by the role of the
+// collaborator in the application if it has one
+func (h *handlerManager) checkAppRights(claims *claims.Claims, appID string, right rights.Right, write bool) error {
 	if !claims.AppRight(appID, right) {
 		return errors.NewErrPermissionDenied(fmt.Sprintf(`No "%s" rights to Application "%s"`, right, appID))
 	}
+	role, ok := h.handler.collaboratorRole(appID, claims.Subject)
+	if ok && !role.Allows(right, write) {
+		return errors.NewErrPermissionDenied(fmt.Sprintf(`Role "%s" does not allow "%s" rights to Application "%s"`, role, right, appID))
+	}
 	return nil
 }
 
@@ -72,6 +78,9 @@ func (h *handlerManager) validateTTNAuthAppContext(ctx context.Context, appID st
 	if h.applicationRate.Limit(appID) {
 		return ctx, claims, grpc.Errorf(codes.ResourceExhausted, "Rate limit for application reached")
 	}
+	if err := h.handler.checkTenantRate(appID); err != nil {
+		return ctx, claims, err
+	}
 	return ctx, claims, nil
 }
 
@@ -84,7 +93,7 @@ func (h *handlerManager) GetDevice(ctx context.Context, in *pb.DeviceIdentifier)
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.Devices)
+	err = h.checkAppRights(claims, in.AppId, rights.Devices, false)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +123,7 @@ func (h *handlerManager) GetDevice(ctx context.Context, in *pb.DeviceIdentifier)
 			DisableFCntCheck:      dev.Options.DisableFCntCheck,
 			Uses32BitFCnt:         dev.Options.Uses32BitFCnt,
 			ActivationConstraints: dev.Options.ActivationConstraints,
+			DisableAdr:            dev.Options.DisableADR,
 		}},
 		Latitude:  dev.Latitude,
 		Longitude: dev.Longitude,
@@ -133,7 +143,7 @@ func (h *handlerManager) GetDevice(ctx context.Context, in *pb.DeviceIdentifier)
 			"DevEUI": dev.DevEUI,
 		}).Warn("Re-registering missing device to Broker")
 		nsDev = dev.GetLoRaWAN()
-		_, err = h.deviceManager.SetDevice(ctx, nsDev)
+		err = h.setNetworkServerDevice(ctx, nsDev)
 		if err != nil {
 			return nil, errors.Wrap(errors.FromGRPCError(err), "Could not re-register missing device to Broker")
 		}
@@ -144,6 +154,7 @@ func (h *handlerManager) GetDevice(ctx context.Context, in *pb.DeviceIdentifier)
 	pbDev.GetLorawanDevice().FCntUp = nsDev.FCntUp
 	pbDev.GetLorawanDevice().FCntDown = nsDev.FCntDown
 	pbDev.GetLorawanDevice().LastSeen = nsDev.LastSeen
+	pbDev.GetLorawanDevice().GatewayGroups = nsDev.GatewayGroups
 
 	return pbDev, nil
 }
@@ -157,7 +168,7 @@ func (h *handlerManager) SetDevice(ctx context.Context, in *pb.Device) (*empty.E
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.Devices)
+	err = h.checkAppRights(claims, in.AppId, rights.Devices, true)
 	if err != nil {
 		return nil, err
 	}
@@ -176,6 +187,15 @@ func (h *handlerManager) SetDevice(ctx context.Context, in *pb.Device) (*empty.E
 		return nil, errors.NewErrInvalidArgument("Device", "No LoRaWAN Device")
 	}
 
+	if dev == nil || dev.DevEUI != *lorawan.DevEui {
+		// Registrations with a new DevEUI are checked one at a time, until the device is stored
+		h.handler.devEUILock.Lock()
+		defer h.handler.devEUILock.Unlock()
+		if err := h.validateDevEUI(ctx, claims, in.AppId, in.DevId, *lorawan.DevEui); err != nil {
+			return nil, err
+		}
+	}
+
 	var eventType types.EventType
 	if dev != nil {
 		eventType = types.UpdateEvent
@@ -192,6 +212,9 @@ func (h *handlerManager) SetDevice(ctx context.Context, in *pb.Device) (*empty.E
 		dev.StartUpdate()
 	} else {
 		eventType = types.CreateEvent
+		if err := h.handler.checkDeviceQuota(in.AppId); err != nil {
+			return nil, err
+		}
 		existingDevices, err := h.handler.devices.ListForApp(in.AppId, nil)
 		if err != nil {
 			return nil, err
@@ -215,6 +238,7 @@ func (h *handlerManager) SetDevice(ctx context.Context, in *pb.Device) (*empty.E
 		DisableFCntCheck:      lorawan.DisableFCntCheck,
 		Uses32BitFCnt:         lorawan.Uses32BitFCnt,
 		ActivationConstraints: lorawan.ActivationConstraints,
+		DisableADR:            lorawan.DisableAdr,
 	}
 	if dev.Options.ActivationConstraints == "" {
 		dev.Options.ActivationConstraints = "local"
@@ -226,11 +250,21 @@ func (h *handlerManager) SetDevice(ctx context.Context, in *pb.Device) (*empty.E
 	if lorawan.NwkSKey != nil {
 		dev.NwkSKey = *lorawan.NwkSKey
 	}
-	if lorawan.AppSKey != nil {
+	if lorawan.AppSKey != nil && !dev.EndToEnd { // The Handler does not store the AppSKey of devices with end-to-end encryption
 		dev.AppSKey = *lorawan.AppSKey
 	}
 
-	if lorawan.AppKey != nil {
+	if crypto := h.handler.getJoinCrypto(); lorawan.AppKey != nil && !crypto.StoresAppKeys() {
+		// The key service stores the AppKey, the Handler does not
+		if !lorawan.AppKey.IsEmpty() {
+			if err := crypto.SetAppKey(ctx, dev, *lorawan.AppKey); err != nil {
+				return nil, err
+			}
+		}
+	} else if lorawan.AppKey != nil {
+		if dev.EndToEnd && !lorawan.AppKey.IsEmpty() {
+			return nil, errors.NewErrInvalidArgument("Device", "the Handler can not store the AppKey of devices with end-to-end encryption")
+		}
 		if dev.AppKey != *lorawan.AppKey { // When the AppKey of an existing device is changed
 			dev.UsedAppNonces = []device.AppNonce{}
 			dev.UsedDevNonces = []device.DevNonce{}
@@ -246,16 +280,30 @@ func (h *handlerManager) SetDevice(ctx context.Context, in *pb.Device) (*empty.E
 	nsUpdated := dev.GetLoRaWAN()
 	nsUpdated.FCntUp = lorawan.FCntUp
 	nsUpdated.FCntDown = lorawan.FCntDown
+	nsUpdated.GatewayGroups = lorawan.GatewayGroups
 
-	_, err = h.deviceManager.SetDevice(ctx, nsUpdated)
+	err = h.setNetworkServerDevice(ctx, nsUpdated)
 	if err != nil {
 		return nil, errors.Wrap(errors.FromGRPCError(err), "Broker did not set device")
 	}
 
+	// Copied ABP credentials are flagged on both devices
+	var conflict bool
+	if lorawan.DevAddr != nil && lorawan.NwkSKey != nil {
+		hadConflict := dev.Conflict != nil
+		if err := h.handler.flagSharedSession(dev); err != nil {
+			return nil, err
+		}
+		conflict = !hadConflict && dev.Conflict != nil
+	}
+
 	err = h.handler.devices.Set(dev)
 	if err != nil {
 		return nil, err
 	}
+	if conflict {
+		h.handler.publishConflict(dev)
+	}
 
 	h.handler.mqttEvent <- &types.DeviceEvent{
 		AppID: dev.AppID,
@@ -275,7 +323,7 @@ func (h *handlerManager) DeleteDevice(ctx context.Context, in *pb.DeviceIdentifi
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.Devices)
+	err = h.checkAppRights(claims, in.AppId, rights.Devices, true)
 	if err != nil {
 		return nil, err
 	}
@@ -312,7 +360,7 @@ func (h *handlerManager) GetDevicesForApplication(ctx context.Context, in *pb.Ap
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.Devices)
+	err = h.checkAppRights(claims, in.AppId, rights.Devices, false)
 	if err != nil {
 		return nil, err
 	}
@@ -374,7 +422,7 @@ func (h *handlerManager) GetApplication(ctx context.Context, in *pb.ApplicationI
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.AppSettings)
+	err = h.checkAppRights(claims, in.AppId, rights.AppSettings, false)
 	if err != nil {
 		return nil, err
 	}
@@ -400,7 +448,7 @@ func (h *handlerManager) RegisterApplication(ctx context.Context, in *pb.Applica
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.AppSettings)
+	err = h.checkAppRights(claims, in.AppId, rights.AppSettings, true)
 	if err != nil {
 		return nil, err
 	}
@@ -445,7 +493,7 @@ func (h *handlerManager) SetApplication(ctx context.Context, in *pb.Application)
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.AppSettings)
+	err = h.checkAppRights(claims, in.AppId, rights.AppSettings, true)
 	if err != nil {
 		return nil, err
 	}
@@ -461,11 +509,19 @@ func (h *handlerManager) SetApplication(ctx context.Context, in *pb.Application)
 	app.Validator = in.Validator
 	app.Encoder = in.Encoder
 
+	if err := checkPayloadTests(app); err != nil {
+		return nil, err
+	}
+
 	err = h.handler.applications.Set(app)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := h.handler.recordFunctions(app, claims.Subject); err != nil {
+		h.handler.Ctx.WithField("AppID", in.AppId).WithError(err).Warn("Could not record version of payload functions")
+	}
+
 	return &empty.Empty{}, nil
 }
 
@@ -477,7 +533,7 @@ func (h *handlerManager) DeleteApplication(ctx context.Context, in *pb.Applicati
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.AppDelete)
+	err = h.checkAppRights(claims, in.AppId, rights.AppDelete, true)
 	if err != nil {
 		return nil, err
 	}
@@ -508,6 +564,13 @@ func (h *handlerManager) DeleteApplication(ctx context.Context, in *pb.Applicati
 	if err != nil {
 		return nil, err
 	}
+	h.handler.startMQTTBridge(in.AppId, nil)
+
+	if h.handler.functions != nil {
+		if err := h.handler.functions.Delete(in.AppId); err != nil {
+			h.handler.Ctx.WithField("AppID", in.AppId).WithError(err).Warn("Could not delete versions of payload functions")
+		}
+	}
 
 	token, _ := api.TokenFromContext(ctx)
 	err = h.handler.Discovery.RemoveAppID(in.AppId, token)
@@ -551,6 +614,10 @@ func (h *handlerManager) GetStatus(ctx context.Context, in *pb.StatusRequest) (*
 	return status, nil
 }
 
+func (h *handlerManager) TailLogs(in *api.LogsRequest, stream pb.HandlerManager_TailLogsServer) error {
+	return h.handler.Component.TailLogs(in, stream)
+}
+
 func (h *handler) RegisterManager(s *grpc.Server) {
 	server := &handlerManager{
 		handler:        h,
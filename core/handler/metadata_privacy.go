@@ -0,0 +1,131 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ParseMetadataPrivacy parses metadata privacy settings in the format
+// "hide-gateway-ids,hide-locations,location-decimals=2,hide-timestamps,untrusted-only"
+func ParseMetadataPrivacy(privacyString string) (privacy application.MetadataPrivacy, err error) {
+	for _, setting := range strings.Split(privacyString, ",") {
+		setting = strings.TrimSpace(setting)
+		parts := strings.SplitN(setting, "=", 2)
+		switch parts[0] {
+		case "":
+		case "hide-gateway-ids":
+			privacy.HideGatewayIDs = true
+		case "hide-locations":
+			privacy.HideLocations = true
+		case "hide-timestamps":
+			privacy.HideTimestamps = true
+		case "untrusted-only":
+			privacy.UntrustedOnly = true
+		case "location-decimals":
+			if len(parts) != 2 {
+				return privacy, fmt.Errorf("Invalid metadata privacy %s: not location-decimals=N", setting)
+			}
+			if privacy.LocationDecimals, err = strconv.Atoi(parts[1]); err != nil {
+				return privacy, fmt.Errorf("Invalid metadata privacy %s: %s", setting, err)
+			}
+		default:
+			return privacy, fmt.Errorf("Invalid metadata privacy %s", setting)
+		}
+	}
+	return privacy, privacy.Validate()
+}
+
+// WithMetadataPrivacy sets the metadata privacy of the Handler. It applies to
+// all applications, which can hide more metadata, but not less.
+func (h *handler) WithMetadataPrivacy(defaults application.MetadataPrivacy) Handler {
+	h.metadataPrivacyDefaults = defaults
+	return h
+}
+
+// metadataPrivacy returns the metadata privacy of the Handler and the metadata
+// privacy of the application, if it has any, which are applied in that order
+func (h *handler) metadataPrivacy(appID string) []application.MetadataPrivacy {
+	privacy := []application.MetadataPrivacy{h.metadataPrivacyDefaults}
+	if h.applications == nil {
+		return privacy
+	}
+	if app, err := h.applications.Get(appID); err == nil && app.MetadataPrivacy != nil {
+		privacy = append(privacy, *app.MetadataPrivacy)
+	}
+	return privacy
+}
+
+// gatewayID returns the ID of the gateway for the events of the application,
+// or an empty string if the metadata privacy hides the IDs of gateways. Events
+// do not say whether the gateway is trusted, so the IDs are also hidden if
+// they are only hidden for untrusted gateways.
+func (h *handler) gatewayID(appID string, gatewayID string) string {
+	for _, privacy := range h.metadataPrivacy(appID) {
+		if privacy.HideGatewayIDs {
+			return ""
+		}
+	}
+	return gatewayID
+}
+
+func (h *httpHandler) metadataPrivacyResponse(app *application.Application) *MetadataPrivacyResponse {
+	defaults := h.manager.handler.metadataPrivacyDefaults
+	if app.MetadataPrivacy == nil {
+		return &MetadataPrivacyResponse{AppID: app.AppID, MetadataPrivacy: defaults, Default: true}
+	}
+	response := &MetadataPrivacyResponse{AppID: app.AppID, MetadataPrivacy: *app.MetadataPrivacy}
+	if defaults != (application.MetadataPrivacy{}) {
+		response.Handler = &defaults
+	}
+	return response
+}
+
+func (h *httpHandler) getMetadataPrivacy(req *http.Request, appID string) (*MetadataPrivacyResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	return h.metadataPrivacyResponse(app), nil
+}
+
+// setMetadataPrivacy sets the metadata privacy of the application, or resets
+// it to the defaults of the Handler
+func (h *httpHandler) setMetadataPrivacy(req *http.Request, appID string, reset bool) (*MetadataPrivacyResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var privacy *application.MetadataPrivacy
+	if !reset {
+		var in MetadataPrivacyResponse
+		if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+			return nil, errors.NewErrInvalidArgument("Body", err.Error())
+		}
+		if err := in.MetadataPrivacy.Validate(); err != nil {
+			return nil, err
+		}
+		privacy = &in.MetadataPrivacy
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.MetadataPrivacy = privacy
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return h.metadataPrivacyResponse(app), nil
+}
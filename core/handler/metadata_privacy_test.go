@@ -0,0 +1,103 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParseMetadataPrivacy(t *testing.T) {
+	a := New(t)
+
+	privacy, err := ParseMetadataPrivacy("")
+	a.So(err, ShouldBeNil)
+	a.So(privacy, ShouldResemble, application.MetadataPrivacy{})
+
+	privacy, err = ParseMetadataPrivacy("hide-gateway-ids, location-decimals=2,hide-timestamps,untrusted-only")
+	a.So(err, ShouldBeNil)
+	a.So(privacy, ShouldResemble, application.MetadataPrivacy{
+		HideGatewayIDs:   true,
+		LocationDecimals: 2,
+		HideTimestamps:   true,
+		UntrustedOnly:    true,
+	})
+
+	_, err = ParseMetadataPrivacy("hide-everything")
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseMetadataPrivacy("location-decimals")
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseMetadataPrivacy("location-decimals=many")
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseMetadataPrivacy("location-decimals=7")
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestMetadataPrivacy(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestMetadataPrivacy")},
+		applications: application.NewMemoryApplicationStore(),
+	}
+	h.WithMetadataPrivacy(application.MetadataPrivacy{HideGatewayIDs: true, UntrustedOnly: true})
+	h.applications.Set(&application.Application{AppID: "default"})
+	h.applications.Set(&application.Application{AppID: "custom", MetadataPrivacy: &application.MetadataPrivacy{
+		LocationDecimals: 1,
+		HideTimestamps:   true,
+	}})
+	h.applications.Set(&application.Application{AppID: "public", MetadataPrivacy: &application.MetadataPrivacy{}})
+
+	ttnUp := &pb_broker.DeduplicatedUplinkMessage{
+		GatewayMetadata: []*pb_gateway.RxMetadata{
+			{GatewayId: "community", Timestamp: 1000, Gps: &pb_gateway.GPSMetadata{Latitude: 52.3731, Longitude: 4.8922}},
+			{GatewayId: "own", Timestamp: 1000, GatewayTrusted: true},
+		},
+	}
+	convert := func(appID string) []types.GatewayMetadata {
+		ttnUp.AppId = appID
+		appUp := &types.UplinkMessage{}
+		a.So(h.ConvertMetadata(h.Ctx, ttnUp, appUp, &device.Device{}), ShouldBeNil)
+		return appUp.Metadata.Gateways
+	}
+
+	gateways := convert("default")
+	a.So(gateways[0].GtwID, ShouldBeEmpty)
+	a.So(gateways[0].Timestamp, ShouldEqual, 1000)
+	a.So(gateways[1].GtwID, ShouldEqual, "own")
+
+	// The metadata privacy of the Handler applies in addition to that of the application
+	gateways = convert("custom")
+	a.So(gateways[0].GtwID, ShouldBeEmpty)
+	a.So(gateways[1].GtwID, ShouldEqual, "own")
+	a.So(gateways[0].Latitude, ShouldAlmostEqual, 52.4, 0.0001)
+	a.So(gateways[0].Longitude, ShouldAlmostEqual, 4.9, 0.0001)
+	a.So(gateways[0].Timestamp, ShouldEqual, 0)
+	a.So(gateways[1].Timestamp, ShouldEqual, 0)
+
+	gateways = convert("public")
+	a.So(gateways[0].GtwID, ShouldBeEmpty)
+	a.So(gateways[0].Latitude, ShouldAlmostEqual, 52.3731, 0.0001)
+
+	gateways = convert("unknown")
+	a.So(gateways[0].GtwID, ShouldBeEmpty)
+
+	// Events do not say whether the gateway is trusted
+	a.So(h.gatewayID("public", "own"), ShouldBeEmpty)
+	h.WithMetadataPrivacy(application.MetadataPrivacy{})
+	a.So(h.gatewayID("public", "own"), ShouldEqual, "own")
+	a.So(h.gatewayID("custom", "own"), ShouldEqual, "own")
+
+	privacy := application.MetadataPrivacy{HideLocations: true, LocationDecimals: 2}
+	gateway := types.GatewayMetadata{LocationMetadata: types.LocationMetadata{Latitude: 52.37, Longitude: 4.89, Altitude: 10}}
+	privacy.Apply(&gateway)
+	a.So(gateway.LocationMetadata, ShouldResemble, types.LocationMetadata{})
+}
@@ -0,0 +1,493 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
+)
+
+// Metering metrics
+const (
+	MeterUplinks   = "uplinks"
+	MeterDownlinks = "downlinks"
+	MeterAirtime   = "airtime"
+	MeterDevices   = "devices"
+)
+
+// MeteringConfig configures the metering of the usage of applications
+type MeteringConfig struct {
+	Interval  time.Duration // the interval of the usage records
+	Directory string        // the directory where usage records are exported (empty to disable)
+	Format    string        // the format of the exported records: csv or json
+	Period    time.Duration // the period of the quotas
+	Quota     Quota
+}
+
+// Limit is a quota limit: reaching the soft limit publishes a warning,
+// reaching the hard limit blocks usage until the next period. Zero disables
+// a limit.
+type Limit struct {
+	Soft int64 `json:"soft,omitempty"`
+	Hard int64 `json:"hard,omitempty"`
+}
+
+// Quota contains the limits of each application per period, the airtime in
+// milliseconds
+type Quota struct {
+	Uplinks   Limit `json:"uplinks"`
+	Downlinks Limit `json:"downlinks"`
+	Airtime   Limit `json:"airtime_ms"`
+	Devices   Limit `json:"devices"`
+}
+
+// ParseQuota parses a quota in the format "uplinks=10000/12000,airtime=1h/2h",
+// where each metric has a soft limit and an optional hard limit
+func ParseQuota(quotaString string) (quota Quota, err error) {
+	for _, setting := range strings.Split(quotaString, ",") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		parts := strings.SplitN(setting, "=", 2)
+		if len(parts) != 2 {
+			return quota, fmt.Errorf("Invalid quota %s: not metric=soft/hard", setting)
+		}
+		var limit Limit
+		for i, value := range strings.SplitN(parts[1], "/", 2) {
+			var n int64
+			if parts[0] == MeterAirtime {
+				var duration time.Duration
+				duration, err = time.ParseDuration(value)
+				n = int64(duration / time.Millisecond)
+			} else {
+				n, err = strconv.ParseInt(value, 10, 64)
+			}
+			if err != nil || n < 0 {
+				return quota, fmt.Errorf("Invalid quota %s: invalid limit %s", setting, value)
+			}
+			if i == 0 {
+				limit.Soft = n
+			} else {
+				limit.Hard = n
+			}
+		}
+		switch parts[0] {
+		case MeterUplinks:
+			quota.Uplinks = limit
+		case MeterDownlinks:
+			quota.Downlinks = limit
+		case MeterAirtime:
+			quota.Airtime = limit
+		case MeterDevices:
+			quota.Devices = limit
+		default:
+			return quota, fmt.Errorf("Invalid quota %s: unknown metric %s", setting, parts[0])
+		}
+	}
+	return quota, nil
+}
+
+// UsageRecord is the usage of an application in a metering interval or quota period
+type UsageRecord struct {
+	AppID     string    `json:"app_id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Uplinks   int64     `json:"uplinks"`
+	Downlinks int64     `json:"downlinks"`
+	AirtimeMS int64     `json:"airtime_ms"`
+	Devices   int64     `json:"devices"`
+}
+
+func (r *UsageRecord) get(metric string) int64 {
+	switch metric {
+	case MeterUplinks:
+		return r.Uplinks
+	case MeterDownlinks:
+		return r.Downlinks
+	case MeterAirtime:
+		return r.AirtimeMS
+	case MeterDevices:
+		return r.Devices
+	}
+	return 0
+}
+
+func (q Quota) get(metric string) Limit {
+	switch metric {
+	case MeterUplinks:
+		return q.Uplinks
+	case MeterDownlinks:
+		return q.Downlinks
+	case MeterAirtime:
+		return q.Airtime
+	case MeterDevices:
+		return q.Devices
+	}
+	return Limit{}
+}
+
+// UsageResponse is returned by the usage endpoint of the HTTP API
+type UsageResponse struct {
+	Usage UsageRecord `json:"usage"`
+	Quota Quota       `json:"quota"`
+}
+
+type meter struct {
+	config MeteringConfig
+	store  *usageStore // nil if the usage is only kept in memory
+
+	mu            sync.Mutex
+	intervalStart time.Time
+	interval      map[string]*UsageRecord
+	periodStart   time.Time
+	period        map[string]*UsageRecord
+	notified      map[string]bool // events that were published in this period by AppID, metric and event type
+}
+
+// reset starts a new interval, and a new period if the current period ended
+func (m *meter) reset(now time.Time) error {
+	m.intervalStart = now
+	m.interval = make(map[string]*UsageRecord)
+	if m.store != nil {
+		if err := m.store.reset(usageInterval, m.intervalStart); err != nil {
+			return err
+		}
+	}
+	return m.checkPeriod(now)
+}
+
+// checkPeriod starts a new period if the current period ended
+func (m *meter) checkPeriod(now time.Time) error {
+	if !m.periodStart.IsZero() && (m.config.Period <= 0 || now.Before(m.periodStart.Add(m.config.Period))) {
+		return nil
+	}
+	m.periodStart = now
+	if m.config.Period > 0 {
+		m.periodStart = now.Truncate(m.config.Period)
+	}
+	m.period = make(map[string]*UsageRecord)
+	m.notified = make(map[string]bool)
+	if m.store != nil {
+		return m.store.reset(usagePeriod, m.periodStart)
+	}
+	return nil
+}
+
+func (m *meter) record(records map[string]*UsageRecord, appID string, start time.Time) *UsageRecord {
+	record, ok := records[appID]
+	if !ok {
+		record = &UsageRecord{AppID: appID, Start: start}
+		records[appID] = record
+	}
+	return record
+}
+
+func (h *handler) WithMetering(config MeteringConfig) Handler {
+	h.meter = &meter{config: config}
+	h.meter.reset(time.Now())
+	// The stored usage is loaded when the Handler starts
+	h.meter.store = h.usage
+	return h
+}
+
+// meterUsage adds usage to the metrics of the application. It returns an
+// error without adding the usage if a hard limit would be exceeded.
+func (h *handler) meterUsage(appID string, usage map[string]int64) error {
+	if h.meter == nil {
+		return nil
+	}
+	var events []*types.DeviceEvent
+	defer func() {
+		for _, event := range events {
+			h.mqttEvent <- event
+		}
+	}()
+
+	m := h.meter
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkPeriod(time.Now()); err != nil {
+		h.Ctx.WithError(err).Warn("Could not store usage")
+	}
+	period := m.record(m.period, appID, m.periodStart)
+
+	for metric, n := range usage {
+		if limit := m.config.Quota.get(metric).Hard; limit > 0 && period.get(metric)+n > limit {
+			events = m.quotaEvent(events, appID, metric, types.QuotaExceededEvent, period.get(metric), limit)
+			return errors.NewErrPermissionDenied(fmt.Sprintf("Application %s reached its quota of %d %s", appID, limit, metric))
+		}
+	}
+
+	interval := m.record(m.interval, appID, m.intervalStart)
+	for _, record := range []*UsageRecord{period, interval} {
+		record.Uplinks += usage[MeterUplinks]
+		record.Downlinks += usage[MeterDownlinks]
+		record.AirtimeMS += usage[MeterAirtime]
+	}
+	if m.store != nil {
+		if err := m.store.setRecords(interval, period); err != nil {
+			h.Ctx.WithError(err).WithField("AppID", appID).Warn("Could not store usage")
+		}
+	}
+	for metric := range usage {
+		if limit := m.config.Quota.get(metric).Soft; limit > 0 && period.get(metric) >= limit {
+			events = m.quotaEvent(events, appID, metric, types.QuotaWarningEvent, period.get(metric), limit)
+		}
+	}
+	return nil
+}
+
+// checkDeviceQuota returns an error if the application can not register
+// another device
+func (h *handler) checkDeviceQuota(appID string) error {
+	if h.meter == nil {
+		return nil
+	}
+	limit := h.meter.config.Quota.Devices
+	if limit.Soft == 0 && limit.Hard == 0 {
+		return nil
+	}
+	devices, err := h.countDevices(appID)
+	if err != nil {
+		return err
+	}
+	var events []*types.DeviceEvent
+	defer func() {
+		for _, event := range events {
+			h.mqttEvent <- event
+		}
+	}()
+
+	m := h.meter
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkPeriod(time.Now()); err != nil {
+		h.Ctx.WithError(err).Warn("Could not store usage")
+	}
+	if limit.Hard > 0 && devices+1 > limit.Hard {
+		events = m.quotaEvent(events, appID, MeterDevices, types.QuotaExceededEvent, devices, limit.Hard)
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Application %s reached its quota of %d %s", appID, limit.Hard, MeterDevices))
+	}
+	if limit.Soft > 0 && devices+1 >= limit.Soft {
+		events = m.quotaEvent(events, appID, MeterDevices, types.QuotaWarningEvent, devices+1, limit.Soft)
+	}
+	return nil
+}
+
+// downlinkAirtime returns the time-on-air of a downlink message
+func downlinkAirtime(downlink *pb_broker.DownlinkMessage) time.Duration {
+	lorawan := downlink.GetDownlinkOption().GetProtocolConfig().GetLorawan()
+	if lorawan == nil {
+		return 0
+	}
+	var airtime time.Duration
+	var err error
+	if lorawan.Modulation == pb_lorawan.Modulation_FSK {
+		airtime, err = toa.ComputeFSK(uint(len(downlink.Payload)), int(lorawan.BitRate))
+	} else {
+		airtime, err = toa.ComputeLoRa(uint(len(downlink.Payload)), lorawan.DataRate, lorawan.CodingRate)
+	}
+	if err != nil {
+		return 0
+	}
+	return airtime
+}
+
+func (h *handler) countDevices(appID string) (int64, error) {
+	opts := &storage.ListOptions{Limit: 1}
+	if _, err := h.devices.ListForApp(appID, opts); err != nil {
+		return 0, err
+	}
+	total, _ := opts.GetTotalAndSelected()
+	return int64(total), nil
+}
+
+// quotaEvent appends a quota event to the events if it was not published in
+// this period yet. The meter should be locked by the caller.
+func (m *meter) quotaEvent(events []*types.DeviceEvent, appID, metric string, event types.EventType, usage, limit int64) []*types.DeviceEvent {
+	key := fmt.Sprintf("%s:%s:%s", appID, metric, event)
+	if m.notified[key] {
+		return events
+	}
+	m.notified[key] = true
+	if m.store != nil {
+		// If this fails, the event is published again after a restart
+		m.store.setNotified(key)
+	}
+	return append(events, &types.DeviceEvent{
+		AppID: appID,
+		Event: event,
+		Data: types.QuotaEventData{
+			Metric:      metric,
+			Usage:       usage,
+			Limit:       limit,
+			PeriodStart: m.periodStart.UTC().Format(time.RFC3339),
+		},
+	})
+}
+
+// collectUsage returns the usage records of the last interval, with the
+// number of devices of the applications at the end of the interval, and starts
+// a new interval
+func (h *handler) collectUsage(now time.Time) ([]*UsageRecord, error) {
+	apps, err := h.applications.List(nil)
+	if err != nil {
+		return nil, err
+	}
+	// Devices are counted before locking the meter, which would otherwise block
+	// the uplink and downlink messages of all applications
+	devices := make(map[string]int64, len(apps))
+	for _, app := range apps {
+		if devices[app.AppID], err = h.countDevices(app.AppID); err != nil {
+			return nil, err
+		}
+	}
+	m := h.meter
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records := make([]*UsageRecord, 0, len(apps))
+	for appID, count := range devices {
+		m.record(m.interval, appID, m.intervalStart).Devices = count
+	}
+	for _, record := range m.interval {
+		record.End = now
+		records = append(records, record)
+	}
+	sort.Sort(usageRecordsByAppID(records))
+	if err := m.reset(now); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+type usageRecordsByAppID []*UsageRecord
+
+func (r usageRecordsByAppID) Len() int           { return len(r) }
+func (r usageRecordsByAppID) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r usageRecordsByAppID) Less(i, j int) bool { return r[i].AppID < r[j].AppID }
+
+// writeUsage writes the usage records in the configured format
+func writeUsage(w io.Writer, format string, records []*UsageRecord) error {
+	if format == "json" {
+		return json.NewEncoder(w).Encode(records)
+	}
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"app_id", "start", "end", MeterUplinks, MeterDownlinks, "airtime_ms", MeterDevices})
+	for _, record := range records {
+		writer.Write([]string{
+			record.AppID,
+			record.Start.UTC().Format(time.RFC3339),
+			record.End.UTC().Format(time.RFC3339),
+			strconv.FormatInt(record.Uplinks, 10),
+			strconv.FormatInt(record.Downlinks, 10),
+			strconv.FormatInt(record.AirtimeMS, 10),
+			strconv.FormatInt(record.Devices, 10),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportUsage writes the usage records of the last interval to a file in the
+// metering directory
+func (h *handler) exportUsage(now time.Time) error {
+	records, err := h.collectUsage(now)
+	if err != nil {
+		return err
+	}
+	if h.meter.config.Directory == "" {
+		return nil
+	}
+	format := h.meter.config.Format
+	if format != "json" {
+		format = "csv"
+	}
+	name := filepath.Join(h.meter.config.Directory, fmt.Sprintf("usage-%s.%s", now.UTC().Format("20060102T150405Z"), format))
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := writeUsage(file, format, records); err != nil {
+		return err
+	}
+	h.Ctx.WithField("File", name).WithField("Applications", len(records)).Info("Exported usage records")
+	return nil
+}
+
+func (h *handler) handleMetering() error {
+	if h.meter == nil {
+		return nil
+	}
+	if m := h.meter; m.store != nil {
+		m.mu.Lock()
+		err := m.store.load(m)
+		if err == nil {
+			err = m.checkPeriod(time.Now())
+		}
+		m.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	if h.meter.config.Interval <= 0 {
+		return nil
+	}
+	go func() {
+		for now := range time.Tick(h.meter.config.Interval) {
+			if err := h.exportUsage(now); err != nil {
+				h.Ctx.WithError(err).Warn("Could not export usage records")
+			}
+		}
+	}()
+	return nil
+}
+
+func (h *httpHandler) usage(req *http.Request, appID string) (*UsageResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	if _, err := h.manager.handler.applications.Get(appID); err != nil {
+		return nil, err
+	}
+	m := h.manager.handler.meter
+	if m == nil {
+		return nil, errors.NewErrNotFound("Metering on this Handler")
+	}
+	devices, err := h.manager.handler.countDevices(appID)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkPeriod(time.Now()); err != nil {
+		return nil, err
+	}
+	response := &UsageResponse{Usage: UsageRecord{AppID: appID, Start: m.periodStart}, Quota: m.config.Quota}
+	if record, ok := m.period[appID]; ok {
+		response.Usage = *record
+	}
+	response.Usage.End = time.Now()
+	response.Usage.Devices = devices
+	return response, nil
+}
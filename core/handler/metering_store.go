@@ -0,0 +1,114 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gopkg.in/redis.v5"
+)
+
+const (
+	usageInterval = "interval"
+	usagePeriod   = "period"
+	usageNotified = "notified"
+)
+
+// usageStore keeps the state of the meter in a Redis hash, so that the usage
+// of applications and the quota events that were published survive a restart
+// of the Handler
+type usageStore struct {
+	client *redis.Client
+	key    string
+}
+
+func newUsageStore(client *redis.Client, prefix string) *usageStore {
+	return &usageStore{client: client, key: prefix + ":usage"}
+}
+
+// load the stored state into the meter. If nothing was stored yet, the
+// current interval and period of the meter are stored.
+func (s *usageStore) load(m *meter) error {
+	all, err := s.client.HGetAll(s.key).Result()
+	if err != nil {
+		return err
+	}
+	intervalStart, intervalErr := time.Parse(time.RFC3339Nano, all[usageInterval+"_start"])
+	periodStart, periodErr := time.Parse(time.RFC3339Nano, all[usagePeriod+"_start"])
+	if intervalErr != nil || periodErr != nil {
+		if err := s.reset(usageInterval, m.intervalStart); err != nil {
+			return err
+		}
+		return s.reset(usagePeriod, m.periodStart)
+	}
+	m.intervalStart, m.periodStart = intervalStart, periodStart
+	m.interval = make(map[string]*UsageRecord)
+	m.period = make(map[string]*UsageRecord)
+	m.notified = make(map[string]bool)
+	for field, value := range all {
+		parts := strings.SplitN(field, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		var records map[string]*UsageRecord
+		switch parts[0] {
+		case usageInterval:
+			records = m.interval
+		case usagePeriod:
+			records = m.period
+		case usageNotified:
+			m.notified[parts[1]] = true
+			continue
+		default:
+			continue
+		}
+		record := new(UsageRecord)
+		if err := json.Unmarshal([]byte(value), record); err != nil {
+			return err
+		}
+		records[parts[1]] = record
+	}
+	return nil
+}
+
+// reset removes the records of the interval, or the records and the published
+// events of the period, and stores the new start
+func (s *usageStore) reset(kind string, start time.Time) error {
+	fields, err := s.client.HKeys(s.key).Result()
+	if err != nil {
+		return err
+	}
+	var deleted []string
+	for _, field := range fields {
+		if strings.HasPrefix(field, kind+":") || (kind == usagePeriod && strings.HasPrefix(field, usageNotified+":")) {
+			deleted = append(deleted, field)
+		}
+	}
+	if len(deleted) > 0 {
+		if err := s.client.HDel(s.key, deleted...).Err(); err != nil {
+			return err
+		}
+	}
+	return s.client.HSet(s.key, kind+"_start", start.UTC().Format(time.RFC3339Nano)).Err()
+}
+
+// setRecords stores the interval and period records of the application
+func (s *usageStore) setRecords(interval, period *UsageRecord) error {
+	fields := make(map[string]string)
+	for kind, record := range map[string]*UsageRecord{usageInterval: interval, usagePeriod: period} {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		fields[kind+":"+record.AppID] = string(data)
+	}
+	return s.client.HMSet(s.key, fields).Err()
+}
+
+// setNotified stores that a quota event was published in this period
+func (s *usageStore) setNotified(key string) error {
+	return s.client.HSet(s.key, usageNotified+":"+key, "1").Err()
+}
@@ -0,0 +1,214 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParseQuota(t *testing.T) {
+	a := New(t)
+
+	quota, err := ParseQuota("uplinks=100/120, downlinks=10, airtime=1m/90s, devices=5/6")
+	a.So(err, ShouldBeNil)
+	a.So(quota.Uplinks, ShouldResemble, Limit{Soft: 100, Hard: 120})
+	a.So(quota.Downlinks, ShouldResemble, Limit{Soft: 10})
+	a.So(quota.Airtime, ShouldResemble, Limit{Soft: 60000, Hard: 90000})
+	a.So(quota.Devices, ShouldResemble, Limit{Soft: 5, Hard: 6})
+
+	quota, err = ParseQuota("")
+	a.So(err, ShouldBeNil)
+	a.So(quota, ShouldResemble, Quota{})
+
+	for _, invalid := range []string{"uplinks", "uplinks=many", "uplinks=-1", "airtime=100", "storage=1"} {
+		_, err = ParseQuota(invalid)
+		a.So(err, ShouldNotBeNil)
+	}
+}
+
+func TestMeterUsage(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestMeterUsage")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+		mqttEvent:    make(chan *types.DeviceEvent, 10),
+	}
+
+	// Without metering nothing is limited
+	a.So(h.meterUsage("app", map[string]int64{MeterUplinks: 1}), ShouldBeNil)
+	a.So(h.checkDeviceQuota("app"), ShouldBeNil)
+
+	h.WithMetering(MeteringConfig{
+		Period: time.Hour,
+		Quota: Quota{
+			Uplinks: Limit{Soft: 2, Hard: 3},
+			Airtime: Limit{Hard: 100},
+			Devices: Limit{Soft: 2, Hard: 2},
+		},
+	})
+
+	a.So(h.meterUsage("app", map[string]int64{MeterUplinks: 1, MeterAirtime: 40}), ShouldBeNil)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	// Reaching the soft limit publishes a warning once
+	a.So(h.meterUsage("app", map[string]int64{MeterUplinks: 1, MeterAirtime: 40}), ShouldBeNil)
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+	event := <-h.mqttEvent
+	a.So(event.AppID, ShouldEqual, "app")
+	a.So(event.DevID, ShouldBeEmpty)
+	a.So(event.Event, ShouldEqual, types.QuotaWarningEvent)
+	a.So(event.Data, ShouldResemble, types.QuotaEventData{
+		Metric:      MeterUplinks,
+		Usage:       2,
+		Limit:       2,
+		PeriodStart: h.meter.periodStart.UTC().Format(time.RFC3339),
+	})
+
+	// Reaching a hard limit blocks usage
+	err := h.meterUsage("app", map[string]int64{MeterUplinks: 1, MeterAirtime: 40})
+	a.So(errors.GetErrType(err), ShouldEqual, errors.PermissionDenied)
+	event = <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.QuotaExceededEvent)
+	a.So(event.Data.(types.QuotaEventData).Metric, ShouldEqual, MeterAirtime)
+	a.So(h.meterUsage("app", map[string]int64{MeterDownlinks: 1}), ShouldBeNil)
+	a.So(h.meterUsage("other", map[string]int64{MeterUplinks: 1, MeterAirtime: 40}), ShouldBeNil)
+
+	// A new period resets the quotas
+	h.meter.periodStart = h.meter.periodStart.Add(-time.Hour)
+	a.So(h.meterUsage("app", map[string]int64{MeterUplinks: 1, MeterAirtime: 40}), ShouldBeNil)
+	a.So(h.meter.period["app"].Uplinks, ShouldEqual, 1)
+
+	// Devices
+	a.So(h.checkDeviceQuota("app"), ShouldBeNil)
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev-1"})
+	a.So(h.checkDeviceQuota("app"), ShouldBeNil)
+	event = <-h.mqttEvent
+	a.So(event.Event, ShouldEqual, types.QuotaWarningEvent)
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev-2"})
+	err = h.checkDeviceQuota("app")
+	a.So(errors.GetErrType(err), ShouldEqual, errors.PermissionDenied)
+}
+
+func TestExportUsage(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestExportUsage")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+	h.WithMetering(MeteringConfig{Period: time.Hour})
+	h.applications.Set(&application.Application{AppID: "app-1"})
+	h.applications.Set(&application.Application{AppID: "app-2"})
+	h.devices.Set(&device.Device{AppID: "app-2", DevID: "dev"})
+
+	h.meterUsage("app-1", map[string]int64{MeterUplinks: 1, MeterAirtime: 50})
+	h.meterUsage("app-1", map[string]int64{MeterDownlinks: 1, MeterAirtime: 60})
+
+	end := time.Now()
+	records, err := h.collectUsage(end)
+	a.So(err, ShouldBeNil)
+	a.So(records, ShouldHaveLength, 2)
+	a.So(records[0].AppID, ShouldEqual, "app-1")
+	a.So(records[0].Uplinks, ShouldEqual, 1)
+	a.So(records[0].Downlinks, ShouldEqual, 1)
+	a.So(records[0].AirtimeMS, ShouldEqual, 110)
+	a.So(records[0].End, ShouldResemble, end)
+	a.So(records[1].AppID, ShouldEqual, "app-2")
+	a.So(records[1].Devices, ShouldEqual, 1)
+
+	// The next interval starts empty, the period continues
+	a.So(h.meter.interval, ShouldBeEmpty)
+	a.So(h.meter.period["app-1"].Uplinks, ShouldEqual, 1)
+
+	var buf bytes.Buffer
+	a.So(writeUsage(&buf, "csv", records), ShouldBeNil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	a.So(lines, ShouldHaveLength, 3)
+	a.So(lines[0], ShouldEqual, "app_id,start,end,uplinks,downlinks,airtime_ms,devices")
+	a.So(lines[1], ShouldEndWith, ",1,1,110,0")
+
+	buf.Reset()
+	a.So(writeUsage(&buf, "json", records), ShouldBeNil)
+	var decoded []UsageRecord
+	a.So(json.Unmarshal(buf.Bytes(), &decoded), ShouldBeNil)
+	a.So(decoded, ShouldHaveLength, 2)
+	a.So(decoded[0].AirtimeMS, ShouldEqual, 110)
+}
+
+func TestStoredUsage(t *testing.T) {
+	a := New(t)
+	client := GetRedisClient()
+	client.Del("handler-test-stored-usage:usage")
+	defer client.Del("handler-test-stored-usage:usage")
+
+	newHandler := func() *handler {
+		h := &handler{
+			Component:    &component.Component{Ctx: GetLogger(t, "TestStoredUsage")},
+			devices:      device.NewMemoryDeviceStore(),
+			applications: application.NewMemoryApplicationStore(),
+			mqttEvent:    make(chan *types.DeviceEvent, 10),
+			usage:        newUsageStore(client, "handler-test-stored-usage"),
+		}
+		h.WithMetering(MeteringConfig{
+			Period: time.Hour,
+			Quota:  Quota{Uplinks: Limit{Soft: 1, Hard: 2}},
+		})
+		a.So(h.handleMetering(), ShouldBeNil)
+		return h
+	}
+
+	h := newHandler()
+	a.So(h.meterUsage("app", map[string]int64{MeterUplinks: 1}), ShouldBeNil)
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+
+	// After a restart, the usage and the published events are loaded
+	h = newHandler()
+	a.So(h.meter.period["app"].Uplinks, ShouldEqual, 1)
+	a.So(h.meter.interval["app"].Uplinks, ShouldEqual, 1)
+	a.So(h.meterUsage("app", map[string]int64{MeterUplinks: 1}), ShouldBeNil)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+	err := h.meterUsage("app", map[string]int64{MeterUplinks: 1})
+	a.So(errors.GetErrType(err), ShouldEqual, errors.PermissionDenied)
+
+	// A new interval is stored
+	_, err = h.collectUsage(time.Now())
+	a.So(err, ShouldBeNil)
+	h = newHandler()
+	a.So(h.meter.interval, ShouldBeEmpty)
+	a.So(h.meter.period["app"].Uplinks, ShouldEqual, 2)
+}
+
+func TestDownlinkAirtime(t *testing.T) {
+	a := New(t)
+	a.So(downlinkAirtime(&pb_broker.DownlinkMessage{}), ShouldEqual, 0)
+
+	airtime := downlinkAirtime(&pb_broker.DownlinkMessage{
+		Payload: make([]byte, 13),
+		DownlinkOption: &pb_broker.DownlinkOption{
+			ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{
+				Modulation: pb_lorawan.Modulation_LORA,
+				DataRate:   "SF7BW125",
+				CodingRate: "4/5",
+			}}},
+		},
+	})
+	a.So(airtime, ShouldBeGreaterThan, 40*time.Millisecond)
+	a.So(airtime, ShouldBeLessThan, 60*time.Millisecond)
+}
@@ -0,0 +1,497 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// MigrationRefreshInterval is the interval in which the Handler sends the
+// migrations of its applications to the Brokers, which do not persist them
+var MigrationRefreshInterval = time.Minute
+
+// MigrationTimeout is the timeout of the requests to other Handlers and to the
+// Brokers during a migration
+var MigrationTimeout = 30 * time.Second
+
+var migrationClient = &http.Client{Timeout: MigrationTimeout}
+
+// MigrationRequest is accepted by the migration endpoint of the HTTP API
+type MigrationRequest struct {
+	// The ID of the Handler that the devices are migrated to
+	ToHandler string `json:"to_handler"`
+	// The percentage of the devices that is migrated
+	Percentage int `json:"percentage,omitempty"`
+	// The IDs of devices that are migrated in addition to the percentage
+	Devices []string `json:"devices,omitempty"`
+}
+
+// MigrationResponse is returned by the migration endpoints of the HTTP API
+type MigrationResponse struct {
+	AppID string `json:"app_id"`
+	// The Handler that the application is registered to
+	Handler string `json:"handler"`
+	// The number of devices that were synchronized to the new Handler
+	Devices   int              `json:"devices,omitempty"`
+	Migration *types.Migration `json:"migration,omitempty"`
+}
+
+// exportMigrationDevice exports a device including the session of OTAA
+// devices, so that they do not have to join again on the new Handler
+func exportMigrationDevice(dev *device.Device) DeviceExport {
+	export := exportDevice(dev)
+	if export.DevAddr == nil && !dev.DevAddr.IsEmpty() {
+		devAddr, nwkSKey, appSKey := dev.DevAddr, dev.NwkSKey, dev.AppSKey
+		export.DevAddr, export.NwkSKey, export.AppSKey = &devAddr, &nwkSKey, &appSKey
+	}
+	return export
+}
+
+// handlerAPIAddress returns the address of the HTTP API of another Handler
+func (h *handler) handlerAPIAddress(handlerID string) (string, error) {
+	announcement, err := h.Discover("handler", handlerID)
+	if err != nil {
+		return "", err
+	}
+	if announcement.ApiAddress == "" {
+		return "", errors.NewErrInternal(fmt.Sprintf("Handler %s does not announce an HTTP API", handlerID))
+	}
+	return strings.TrimSuffix(announcement.ApiAddress, "/"), nil
+}
+
+// doJSON sends a JSON request to an HTTP API and decodes the response into out
+func doJSON(method, url string, header http.Header, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := migrationClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK:
+		if out != nil {
+			return json.NewDecoder(res.Body).Decode(out)
+		}
+		return nil
+	case http.StatusNoContent:
+		return nil
+	case http.StatusForbidden:
+		return errors.NewErrPermissionDenied(fmt.Sprintf("%s %s", method, url))
+	default:
+		body, _ := ioutil.ReadAll(res.Body)
+		return errors.NewErrInternal(fmt.Sprintf("%s %s: %s %s", method, url, res.Status, strings.TrimSpace(string(body))))
+	}
+}
+
+// syncMigration imports the settings and the devices of the application that
+// are still served by this Handler into the Handler that it is migrated to.
+// The devices that are already migrated by the current migration are left
+// out, as the other Handler has their latest sessions. The token must have
+// settings and devices rights to the application.
+func (h *handler) syncMigration(token string, app *application.Application, current, migration *types.Migration) (int, error) {
+	apiAddress, err := h.handlerAPIAddress(migration.ToHandler)
+	if err != nil {
+		return 0, err
+	}
+	devices, err := h.devices.ListForApp(app.AppID, nil)
+	if err != nil {
+		return 0, err
+	}
+	export := ApplicationExport{
+		AppID:    app.AppID,
+		Settings: exportApplication(app),
+		Devices:  make([]DeviceExport, 0, len(devices)),
+	}
+	if err := h.exportSecrets(&export.Settings, app); err != nil {
+		return 0, err
+	}
+	for _, dev := range devices {
+		if current.Migrated(dev.DevID) {
+			continue
+		}
+		export.Devices = append(export.Devices, exportMigrationDevice(dev))
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	var response ImportResponse
+	url := fmt.Sprintf("%s/applications/%s/import?migration=true", apiAddress, app.AppID)
+	if err := doJSON(http.MethodPost, url, header, export, &response); err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("Handler %s did not import application", migration.ToHandler))
+	}
+	migration.SyncedAt = time.Now().UTC()
+	return response.Devices, nil
+}
+
+// pullMigration copies the sessions of the devices that were migrated by the
+// previous migration, but are no longer migrated, from the Handler that they
+// were migrated to. The devices may have joined again on the other Handler.
+// Only the sessions are copied: the NetworkServer already has them. The
+// Brokers must route the devices to this Handler before their sessions are
+// pulled, so that they do not change afterwards.
+func (h *handler) pullMigration(token string, appID string, previous, migration *types.Migration) (int, error) {
+	apiAddress, err := h.handlerAPIAddress(previous.ToHandler)
+	if err != nil {
+		return 0, err
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	var export ApplicationExport
+	url := fmt.Sprintf("%s/applications/%s/export?devices=true&migration=true", apiAddress, appID)
+	if err := doJSON(http.MethodGet, url, header, nil, &export); err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("Handler %s did not export application", previous.ToHandler))
+	}
+	var pulled int
+	for _, remote := range export.Devices {
+		if !previous.Migrated(remote.DevID) || migration.Migrated(remote.DevID) || remote.DevAddr == nil {
+			continue
+		}
+		dev, err := h.devices.Get(appID, remote.DevID)
+		if errors.GetErrType(err) == errors.NotFound {
+			continue
+		}
+		if err != nil {
+			return pulled, err
+		}
+		dev.StartUpdate()
+		dev.DevAddr = *remote.DevAddr
+		if remote.NwkSKey != nil {
+			dev.NwkSKey = *remote.NwkSKey
+		}
+		if remote.AppSKey != nil {
+			dev.AppSKey = *remote.AppSKey
+		}
+		if err := h.devices.Set(dev); err != nil {
+			return pulled, err
+		}
+		pulled++
+	}
+	return pulled, nil
+}
+
+// sendMigration sets the migration of an application in all Brokers that
+// announce an HTTP API, or deletes it if the migration is nil
+func (h *handler) sendMigration(appID string, migration *types.Migration) error {
+	brokers, err := h.Discovery.GetAll("broker")
+	if err != nil {
+		return errors.FromGRPCError(err)
+	}
+	token, err := h.BuildJWT()
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("Grpc-Metadata-Id", h.Identity.Id)
+	header.Set("Grpc-Metadata-Service-Name", h.Identity.ServiceName)
+	method, body := http.MethodPut, interface{}(migration)
+	if migration == nil {
+		method, body = http.MethodDelete, nil
+	}
+	for _, broker := range brokers {
+		if broker.ApiAddress == "" {
+			continue
+		}
+		url := fmt.Sprintf("%s/applications/%s/migration", strings.TrimSuffix(broker.ApiAddress, "/"), appID)
+		if err := doJSON(method, url, header, body, nil); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Broker %s did not accept migration", broker.Id))
+		}
+	}
+	return nil
+}
+
+// refreshMigrations sends the migrations of the applications to the Brokers
+func (h *handler) refreshMigrations() error {
+	apps, err := h.applications.List(nil)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		if app.Migration == nil {
+			continue
+		}
+		if err := h.sendMigration(app.AppID, app.Migration); err != nil {
+			h.Ctx.WithError(err).WithField("AppID", app.AppID).Warn("Could not refresh migration")
+		}
+	}
+	return nil
+}
+
+func (h *handler) handleMigrations() {
+	go func() {
+		for range time.Tick(MigrationRefreshInterval) {
+			if err := h.refreshMigrations(); err != nil {
+				h.Ctx.WithError(err).Warn("Could not refresh migrations")
+			}
+		}
+	}()
+}
+
+// authorizeMigration checks that the request has settings and devices rights
+// to the application, and returns its token
+func (h *httpHandler) authorizeMigration(req *http.Request, appID string) (string, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return "", err
+	}
+	ctx, err := h.authorize(req, appID, rights.Devices)
+	if err != nil {
+		return "", err
+	}
+	return api.TokenFromContext(ctx)
+}
+
+func (h *httpHandler) getMigration(req *http.Request, appID string) (*MigrationResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	return &MigrationResponse{AppID: appID, Handler: h.manager.handler.Identity.Id, Migration: app.Migration}, nil
+}
+
+// setMigration starts or changes the migration of the devices of an
+// application to another Handler. The application and its devices are
+// synchronized to the other Handler before the Brokers route the selected
+// devices to it.
+func (h *httpHandler) setMigration(req *http.Request, appID string) (*MigrationResponse, error) {
+	token, err := h.authorizeMigration(req, appID)
+	if err != nil {
+		return nil, err
+	}
+	var in MigrationRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	handlerID := h.manager.handler.Identity.Id
+	if in.ToHandler == "" || in.ToHandler == handlerID {
+		return nil, errors.NewErrInvalidArgument("Migration", "must have another target Handler")
+	}
+	if in.Percentage < 0 || in.Percentage > 100 {
+		return nil, errors.NewErrInvalidArgument("Migration", "percentage must be between 0 and 100")
+	}
+	for _, devID := range in.Devices {
+		if !api.ValidID(devID) {
+			return nil, errors.NewErrInvalidArgument("Dev ID", fmt.Sprintf("%s has an invalid format", devID))
+		}
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	migration := app.Migration
+	if migration == nil || migration.ToHandler != in.ToHandler {
+		migration = &types.Migration{AppID: appID, FromHandler: handlerID, StartedAt: time.Now().UTC()}
+	} else {
+		updated := *migration
+		migration = &updated
+	}
+	migration.ToHandler = in.ToHandler
+	migration.Percentage = in.Percentage
+	migration.Devices = in.Devices
+
+	handler := h.manager.handler
+	current := app.Migration
+	if current != nil && current.ToHandler != migration.ToHandler {
+		// Route the devices back to this Handler, with their latest sessions,
+		// before migrating them to another Handler
+		if err := handler.abortMigration(token, app); err != nil {
+			return nil, err
+		}
+		current = nil
+	}
+	devices, err := handler.syncMigration(token, app, current, migration)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.Migration = migration
+	if err := handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	if err := handler.sendMigration(appID, migration); err != nil {
+		return nil, err
+	}
+	if current != nil {
+		// Devices that are no longer migrated continue on this Handler
+		if _, err := handler.pullMigration(token, appID, current, migration); err != nil {
+			return nil, err
+		}
+	}
+	return &MigrationResponse{AppID: appID, Handler: handlerID, Devices: devices, Migration: migration}, nil
+}
+
+// abortMigration routes all devices of the application to this Handler again
+// and pulls the sessions of the migrated devices from the other Handler
+func (h *handler) abortMigration(token string, app *application.Application) error {
+	previous := app.Migration
+	if err := h.sendMigration(app.AppID, nil); err != nil {
+		return err
+	}
+	app.StartUpdate()
+	app.Migration = nil
+	if err := h.applications.Set(app); err != nil {
+		return err
+	}
+	if _, err := h.pullMigration(token, app.AppID, previous, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deleteMigration aborts the migration of an application. The devices are
+// routed to this Handler again, with the sessions that they have on the other
+// Handler, but stay registered to the other Handler.
+func (h *httpHandler) deleteMigration(req *http.Request, appID string) (*MigrationResponse, error) {
+	token, err := h.authorizeMigration(req, appID)
+	if err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	response := &MigrationResponse{AppID: appID, Handler: h.manager.handler.Identity.Id}
+	if app.Migration == nil {
+		return response, nil
+	}
+	if err := h.manager.handler.abortMigration(token, app); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// cutover completes the migration of an application. After a final
+// synchronization of the devices that are not migrated yet, the other Handler registers the application, so that all
+// devices are routed to it, and this Handler removes the application and its
+// devices. The devices stay registered in the NetworkServer.
+func (h *httpHandler) cutover(req *http.Request, appID string) (*MigrationResponse, error) {
+	token, err := h.authorizeMigration(req, appID)
+	if err != nil {
+		return nil, err
+	}
+	handler := h.manager.handler
+	app, err := handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if app.Migration == nil {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("Migration of application %s", appID))
+	}
+	migration := *app.Migration
+	devices, err := handler.syncMigration(token, app, app.Migration, &migration)
+	if err != nil {
+		return nil, err
+	}
+
+	apiAddress, err := handler.handlerAPIAddress(migration.ToHandler)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+	url := fmt.Sprintf("%s/applications/%s/takeover", apiAddress, appID)
+	if err := doJSON(http.MethodPost, url, header, nil, nil); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Handler %s did not take over application", migration.ToHandler))
+	}
+
+	if err := handler.sendMigration(appID, nil); err != nil {
+		handler.Ctx.WithError(err).WithField("AppID", appID).Warn("Could not delete migration from Brokers")
+	}
+	existing, err := handler.devices.ListForApp(appID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, dev := range existing {
+		if err := handler.devices.Delete(dev.AppID, dev.DevID); err != nil {
+			return nil, err
+		}
+	}
+	if err := handler.applications.Delete(appID); err != nil {
+		return nil, err
+	}
+	handler.Ctx.WithField("AppID", appID).WithField("Handler", migration.ToHandler).Info("Migrated application")
+	return &MigrationResponse{AppID: appID, Handler: migration.ToHandler, Devices: devices, Migration: &migration}, nil
+}
+
+// takeover registers an application that was migrated to this Handler with
+// the Discovery server and the Broker, so that all its devices are routed to
+// this Handler
+func (h *httpHandler) takeover(req *http.Request, appID string) (*MigrationResponse, error) {
+	ctx, err := h.authorize(req, appID, rights.AppSettings)
+	if err != nil {
+		return nil, err
+	}
+	handler := h.manager.handler
+	if _, err := handler.applications.Get(appID); err != nil {
+		return nil, errors.Wrap(err, "Application not migrated to this Handler")
+	}
+	token, _ := api.TokenFromContext(ctx)
+	if err := handler.Discovery.AddAppID(appID, token); err != nil {
+		return nil, errors.Wrap(errors.FromGRPCError(err), "Could not register Application with Discovery")
+	}
+	_, err = handler.ttnBrokerManager.RegisterApplicationHandler(ctx, &pb_broker.ApplicationHandlerRegistration{
+		AppId:     appID,
+		HandlerId: handler.Identity.Id,
+	})
+	if err != nil {
+		handler.Ctx.WithField("AppID", appID).WithError(err).Warn("Could not register Application with Broker")
+	}
+	return &MigrationResponse{AppID: appID, Handler: handler.Identity.Id}, nil
+}
+
+// listMigrations returns the migrations of the applications of this Handler to
+// a Broker, which loads them when it starts
+func (h *httpHandler) listMigrations(req *http.Request) ([]*types.Migration, error) {
+	component, err := h.manager.handler.ValidateNetworkContext(h.componentContext(req))
+	if err != nil {
+		return nil, errors.NewErrPermissionDenied(err.Error())
+	}
+	if component.ServiceName != "broker" {
+		return nil, errors.NewErrPermissionDenied(fmt.Sprintf("%s can not list migrations", component.ServiceName))
+	}
+	apps, err := h.manager.handler.applications.List(nil)
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]*types.Migration, 0)
+	for _, app := range apps {
+		if app.Migration != nil {
+			migrations = append(migrations, app.Migration)
+		}
+	}
+	return migrations, nil
+}
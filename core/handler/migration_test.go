@@ -0,0 +1,159 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestExportMigrationDevice(t *testing.T) {
+	a := New(t)
+
+	otaa := &device.Device{
+		DevID:   "otaa",
+		AppKey:  types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8},
+		DevAddr: types.DevAddr{1, 2, 3, 4},
+		NwkSKey: types.NwkSKey{1},
+		AppSKey: types.AppSKey{2},
+	}
+	export := exportMigrationDevice(otaa)
+	a.So(export.AppKey, ShouldNotBeNil)
+	a.So(*export.DevAddr, ShouldEqual, otaa.DevAddr)
+	a.So(*export.NwkSKey, ShouldEqual, otaa.NwkSKey)
+	a.So(*export.AppSKey, ShouldEqual, otaa.AppSKey)
+
+	// OTAA devices that did not join yet have no session
+	export = exportMigrationDevice(&device.Device{DevID: "new", AppKey: otaa.AppKey})
+	a.So(export.DevAddr, ShouldBeNil)
+}
+
+func TestSendMigration(t *testing.T) {
+	a := New(t)
+
+	var requests []*http.Request
+	var migrations []*types.Migration
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		var migration types.Migration
+		if json.NewDecoder(r.Body).Decode(&migration) == nil {
+			migrations = append(migrations, &migration)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	discovery := pb_discovery.NewMockClient(ctrl)
+	h := &handler{
+		Component: &component.Component{
+			Ctx:       GetLogger(t, "TestSendMigration"),
+			Discovery: discovery,
+			Identity:  &pb_discovery.Announcement{Id: "old", ServiceName: "handler"},
+		},
+		applications: application.NewMemoryApplicationStore(),
+	}
+	brokers := []*pb_discovery.Announcement{{Id: "with-api", ApiAddress: server.URL + "/"}, {Id: "without-api"}}
+
+	migration := &types.Migration{AppID: "app", FromHandler: "old", ToHandler: "new", Percentage: 10}
+	discovery.EXPECT().GetAll("broker").Return(brokers, nil)
+	a.So(h.sendMigration("app", migration), ShouldBeNil)
+	a.So(requests, ShouldHaveLength, 1)
+	a.So(requests[0].Method, ShouldEqual, "PUT")
+	a.So(requests[0].URL.Path, ShouldEqual, "/applications/app/migration")
+	a.So(requests[0].Header.Get("Grpc-Metadata-Id"), ShouldEqual, "old")
+	a.So(requests[0].Header.Get("Grpc-Metadata-Service-Name"), ShouldEqual, "handler")
+	a.So(migrations, ShouldHaveLength, 1)
+	a.So(migrations[0].ToHandler, ShouldEqual, "new")
+	a.So(migrations[0].Percentage, ShouldEqual, 10)
+
+	discovery.EXPECT().GetAll("broker").Return(brokers, nil)
+	a.So(h.sendMigration("app", nil), ShouldBeNil)
+	a.So(requests, ShouldHaveLength, 2)
+	a.So(requests[1].Method, ShouldEqual, "DELETE")
+
+	// Only applications that are being migrated are refreshed
+	h.applications.Set(&application.Application{AppID: "app", Migration: migration})
+	h.applications.Set(&application.Application{AppID: "other"})
+	discovery.EXPECT().GetAll("broker").Return(brokers, nil)
+	a.So(h.refreshMigrations(), ShouldBeNil)
+	a.So(requests, ShouldHaveLength, 3)
+	a.So(requests[2].Method, ShouldEqual, "PUT")
+}
+
+func TestSyncAndPullMigration(t *testing.T) {
+	a := New(t)
+
+	var imported ApplicationExport
+	exported := ApplicationExport{AppID: "app"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/applications/app/import":
+			json.NewDecoder(r.Body).Decode(&imported)
+			json.NewEncoder(w).Encode(ImportResponse{AppID: "app", Devices: len(imported.Devices)})
+		case "/applications/app/export":
+			a.So(r.URL.Query().Get("migration"), ShouldEqual, "true")
+			json.NewEncoder(w).Encode(exported)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	discovery := pb_discovery.NewMockClient(ctrl)
+	discovery.EXPECT().Get("handler", "new").Return(&pb_discovery.Announcement{Id: "new", ApiAddress: server.URL}, nil).AnyTimes()
+	h := &handler{
+		Component: &component.Component{
+			Ctx:       GetLogger(t, "TestSyncAndPullMigration"),
+			Discovery: discovery,
+			Identity:  &pb_discovery.Announcement{Id: "old", ServiceName: "handler"},
+		},
+		applications: application.NewMemoryApplicationStore(),
+		devices:      device.NewMemoryDeviceStore(),
+	}
+	app := &application.Application{AppID: "app"}
+	h.applications.Set(app)
+	h.devices.Set(&device.Device{AppID: "app", DevID: "migrated", DevAddr: types.DevAddr{1, 2, 3, 4}, AppSKey: types.AppSKey{1}})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "other", DevAddr: types.DevAddr{1, 2, 3, 5}})
+
+	current := &types.Migration{AppID: "app", FromHandler: "old", ToHandler: "new", Devices: []string{"migrated"}}
+	migration := &types.Migration{AppID: "app", FromHandler: "old", ToHandler: "new", Devices: []string{"migrated", "other"}}
+
+	// The new Handler has the latest sessions of the devices that are already migrated
+	devices, err := h.syncMigration("token", app, current, migration)
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldEqual, 1)
+	a.So(imported.Devices, ShouldHaveLength, 1)
+	a.So(imported.Devices[0].DevID, ShouldEqual, "other")
+	a.So(migration.SyncedAt.IsZero(), ShouldBeFalse)
+
+	// Devices that are no longer migrated continue with the session of the new Handler
+	devAddr, appSKey := types.DevAddr{1, 2, 3, 6}, types.AppSKey{2}
+	exported.Devices = []DeviceExport{
+		{DevID: "migrated", DevAddr: &devAddr, AppSKey: &appSKey},
+		{DevID: "other", DevAddr: &devAddr, AppSKey: &appSKey},
+	}
+	pulled, err := h.pullMigration("token", "app", current, nil)
+	a.So(err, ShouldBeNil)
+	a.So(pulled, ShouldEqual, 1)
+	dev, _ := h.devices.Get("app", "migrated")
+	a.So(dev.DevAddr, ShouldEqual, devAddr)
+	a.So(dev.AppSKey, ShouldEqual, appSKey)
+	dev, _ = h.devices.Get("app", "other")
+	a.So(dev.DevAddr, ShouldEqual, types.DevAddr{1, 2, 3, 5})
+}
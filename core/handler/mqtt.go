@@ -43,11 +43,14 @@ func (h *handler) HandleMQTT(username, password string, mqttBrokers ...string) e
 
 	go func() {
 		for up := range h.mqttUp {
+			h.persistUplink(up)
+			h.subscriptions.publishUplink(up)
 			ctx.WithFields(ttnlog.Fields{
 				"DevID": up.DevID,
 				"AppID": up.AppID,
 			}).Debug("Publish Uplink")
-			upToken := h.mqttClient.PublishUplink(*up)
+			format, pattern := h.uplinkPublishing(up.AppID)
+			upToken := h.mqttClient.PublishUplinkFormat(*up, format)
 			go func() {
 				if upToken.WaitTimeout(MQTTTimeout) {
 					if upToken.Error() != nil {
@@ -57,6 +60,18 @@ func (h *handler) HandleMQTT(username, password string, mqttBrokers ...string) e
 					ctx.Warn("Uplink publish timeout")
 				}
 			}()
+			if pattern != nil {
+				patternToken := h.mqttClient.PublishUplinkPattern(*up, *pattern, format)
+				go func() {
+					if patternToken.WaitTimeout(MQTTTimeout) {
+						if patternToken.Error() != nil {
+							ctx.WithError(patternToken.Error()).Warn("Could not publish Uplink on topic pattern")
+						}
+					} else {
+						ctx.Warn("Uplink publish on topic pattern timeout")
+					}
+				}()
+			}
 			if len(up.PayloadFields) > 0 {
 				fieldsToken := h.mqttClient.PublishUplinkFields(up.AppID, up.DevID, up.PayloadFields)
 				go func() {
@@ -74,6 +89,8 @@ func (h *handler) HandleMQTT(username, password string, mqttBrokers ...string) e
 
 	go func() {
 		for event := range h.mqttEvent {
+			h.persistEvent(event)
+			h.subscriptions.publishEvent(event)
 			h.Ctx.WithFields(ttnlog.Fields{
 				"DevID": event.DevID,
 				"AppID": event.AppID,
@@ -0,0 +1,336 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+var (
+	// MQTTBridgeMinBackoff is the delay before the first retry to connect to an external MQTT broker
+	MQTTBridgeMinBackoff = time.Second
+	// MQTTBridgeMaxBackoff is the maximum delay between attempts to connect to an external MQTT broker
+	MQTTBridgeMaxBackoff = 5 * time.Minute
+)
+
+// MQTTBridgeStatus is the status of the connection to the external MQTT broker of an application
+type MQTTBridgeStatus struct {
+	Connected   bool       `json:"connected"`
+	ConnectedAt *time.Time `json:"connected_at,omitempty"`
+	// Attempts is the number of failed attempts to connect since the bridge was started or last connected
+	Attempts  int        `json:"attempts,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	ErrorAt   *time.Time `json:"error_at,omitempty"`
+}
+
+// MQTTBridgeResponse is returned by the mqtt-bridge endpoint of the HTTP API. The password is not returned.
+type MQTTBridgeResponse struct {
+	application.MQTTBridge
+	Status MQTTBridgeStatus `json:"status"`
+}
+
+type mqttBridge struct {
+	appID  string
+	config application.MQTTBridge
+	hosts  integrationHosts
+	client mqtt.Client
+	stop   chan struct{}
+
+	mu     sync.Mutex
+	status MQTTBridgeStatus
+}
+
+type mqttBridges struct {
+	mu      sync.Mutex
+	bridges map[string]*mqttBridge
+}
+
+func validateMQTTBridge(bridge *application.MQTTBridge) error {
+	u, err := url.Parse(bridge.URL)
+	if err != nil || u.Host == "" {
+		return errors.NewErrInvalidArgument("MQTT bridge URL", "must be a URL such as tcp://host:1883")
+	}
+	switch u.Scheme {
+	case "tcp", "ssl", "tls", "ws", "wss":
+	default:
+		return errors.NewErrInvalidArgument("MQTT bridge URL", "scheme must be tcp, ssl, tls, ws or wss")
+	}
+	if strings.ContainsAny(bridge.TopicPrefix, "+#") {
+		return errors.NewErrInvalidArgument("MQTT bridge topic prefix", "can not contain wildcards")
+	}
+	return nil
+}
+
+func (b *mqttBridge) getStatus() MQTTBridgeStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := b.status
+	status.Connected = b.client.IsConnected()
+	return status
+}
+
+func (b *mqttBridge) setError(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.status.LastError = err.Error()
+	b.status.ErrorAt = &now
+}
+
+func (b *mqttBridge) connect() bool {
+	backoff := MQTTBridgeMinBackoff
+	for {
+		// The host is checked on every attempt, as its addresses can change
+		err := b.hosts.checkURL(b.config.URL)
+		if err == nil {
+			err = b.client.Connect()
+		}
+		if err == nil {
+			b.mu.Lock()
+			now := time.Now()
+			b.status.ConnectedAt = &now
+			b.status.Attempts = 0
+			b.mu.Unlock()
+			return true
+		}
+		b.setError(err)
+		b.mu.Lock()
+		b.status.Attempts++
+		b.mu.Unlock()
+		select {
+		case <-b.stop:
+			return false
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > MQTTBridgeMaxBackoff {
+			backoff = MQTTBridgeMaxBackoff
+		}
+	}
+}
+
+// wait records the error of the token when it completes
+func (b *mqttBridge) wait(token mqtt.Token) {
+	go func() {
+		if !token.WaitTimeout(MQTTTimeout) {
+			b.setError(fmt.Errorf("Publish timeout"))
+			return
+		}
+		if err := token.Error(); err != nil {
+			b.setError(err)
+		}
+	}()
+}
+
+// runMQTTBridge connects to the broker with exponential backoff and publishes the
+// uplink messages and events of the application until the bridge is stopped.
+// The MQTT client reconnects by itself if the connection is lost.
+func (h *handler) runMQTTBridge(b *mqttBridge) {
+	ctx := h.Ctx.WithFields(ttnlog.Fields{"AppID": b.appID, "Bridge": b.config.URL})
+	if !b.connect() {
+		return
+	}
+	defer b.client.Disconnect()
+	ctx.Info("Connected to MQTT bridge")
+
+	if b.config.Downlink {
+		b.wait(b.client.SubscribeAppDownlink(b.appID, func(_ mqtt.Client, _, _ string, req types.DownlinkMessage) {
+			select {
+			case <-b.stop:
+				return
+			default:
+			}
+			if err := h.EnqueueDownlink(&req); err != nil {
+				ctx.WithError(err).Warn("Could not enqueue downlink from MQTT bridge")
+			}
+		}))
+	}
+
+	sub := h.Subscribe(b.appID)
+	defer h.Unsubscribe(sub)
+	for {
+		select {
+		case <-b.stop:
+			return
+		case up, ok := <-sub.Uplink:
+			if !ok {
+				return
+			}
+			b.wait(b.client.PublishUplinkFormat(*up, h.payloadFormat(b.appID)))
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if event.DevID == "" {
+				b.wait(b.client.PublishAppEvent(event.AppID, event.Event, event.Data))
+			} else {
+				b.wait(b.client.PublishDeviceEvent(event.AppID, event.DevID, event.Event, event.Data))
+			}
+		}
+	}
+}
+
+func newMQTTBridgeClient(ctx ttnlog.Interface, appID string, config application.MQTTBridge) mqtt.Client {
+	var client mqtt.Client
+	if strings.HasPrefix(config.URL, "ssl://") || strings.HasPrefix(config.URL, "tls://") || strings.HasPrefix(config.URL, "wss://") {
+		client = mqtt.NewTLSClient(ctx, "ttn-bridge-"+appID, config.Username, config.Password, nil, config.URL)
+	} else {
+		client = mqtt.NewClient(ctx, "ttn-bridge-"+appID, config.Username, config.Password, config.URL)
+	}
+	client.(*mqtt.DefaultClient).SetTopicPrefix(config.TopicPrefix)
+	return client
+}
+
+// startMQTTBridge (re)starts the bridge of the application, or stops it if
+// config is nil. The password in the config is sealed with the secrets key.
+func (h *handler) startMQTTBridge(appID string, config *application.MQTTBridge) error {
+	h.mqttBridges.mu.Lock()
+	defer h.mqttBridges.mu.Unlock()
+	if b, ok := h.mqttBridges.bridges[appID]; ok {
+		close(b.stop)
+		delete(h.mqttBridges.bridges, appID)
+	}
+	if config == nil {
+		return nil
+	}
+	password, err := h.openSecret(config.Password)
+	if err != nil {
+		return err
+	}
+	if h.mqttBridges.bridges == nil {
+		h.mqttBridges.bridges = make(map[string]*mqttBridge)
+	}
+	b := &mqttBridge{
+		appID:  appID,
+		config: *config,
+		hosts:  h.integrationHosts,
+		stop:   make(chan struct{}),
+	}
+	b.config.Password = password
+	b.client = newMQTTBridgeClient(h.Ctx.WithField("AppID", appID), appID, b.config)
+	h.mqttBridges.bridges[appID] = b
+	go h.runMQTTBridge(b)
+	return nil
+}
+
+// startMQTTBridges starts the bridges of all applications that have one
+func (h *handler) startMQTTBridges() error {
+	apps, err := h.applications.List(nil)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		if app.MQTTBridge == nil {
+			continue
+		}
+		if err := h.startMQTTBridge(app.AppID, app.MQTTBridge); err != nil {
+			h.Ctx.WithField("AppID", app.AppID).WithError(err).Warn("Could not start MQTT bridge")
+		}
+	}
+	return nil
+}
+
+func (h *handler) stopMQTTBridges() {
+	h.mqttBridges.mu.Lock()
+	defer h.mqttBridges.mu.Unlock()
+	for appID, b := range h.mqttBridges.bridges {
+		close(b.stop)
+		delete(h.mqttBridges.bridges, appID)
+	}
+}
+
+func (h *handler) mqttBridgeStatus(appID string) MQTTBridgeStatus {
+	h.mqttBridges.mu.Lock()
+	b, ok := h.mqttBridges.bridges[appID]
+	h.mqttBridges.mu.Unlock()
+	if !ok {
+		return MQTTBridgeStatus{}
+	}
+	return b.getStatus()
+}
+
+func (h *httpHandler) getMQTTBridge(req *http.Request, appID string) (*MQTTBridgeResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if app.MQTTBridge == nil {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("MQTT bridge of application %s", appID))
+	}
+	response := &MQTTBridgeResponse{MQTTBridge: *app.MQTTBridge, Status: h.manager.handler.mqttBridgeStatus(appID)}
+	response.Password = ""
+	return response, nil
+}
+
+// setMQTTBridge sets the external MQTT broker of the application and
+// reconnects the bridge. If the request has no password and the broker and
+// username are not changed, the current password is kept.
+func (h *httpHandler) setMQTTBridge(req *http.Request, appID string) (*MQTTBridgeResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in application.MQTTBridge
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := validateMQTTBridge(&in); err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.integrationHosts.checkURL(in.URL); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if old := app.MQTTBridge; old != nil && in.Password == "" && in.URL == old.URL && in.Username == old.Username {
+		in.Password = old.Password
+	} else if in.Password, err = h.manager.handler.sealSecret(in.Password); err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.MQTTBridge = &in
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.startMQTTBridge(appID, &in); err != nil {
+		return nil, err
+	}
+	response := &MQTTBridgeResponse{MQTTBridge: in, Status: h.manager.handler.mqttBridgeStatus(appID)}
+	response.Password = ""
+	return response, nil
+}
+
+func (h *httpHandler) deleteMQTTBridge(req *http.Request, appID string) error {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return err
+	}
+	app.StartUpdate()
+	app.MQTTBridge = nil
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return err
+	}
+	h.manager.handler.startMQTTBridge(appID, nil)
+	return nil
+}
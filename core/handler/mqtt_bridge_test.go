@@ -0,0 +1,85 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateMQTTBridge(t *testing.T) {
+	a := New(t)
+
+	a.So(validateMQTTBridge(&application.MQTTBridge{URL: "tcp://broker.example.com:1883", TopicPrefix: "ttn/"}), ShouldBeNil)
+	a.So(validateMQTTBridge(&application.MQTTBridge{URL: "ssl://broker.example.com:8883"}), ShouldBeNil)
+	a.So(validateMQTTBridge(&application.MQTTBridge{URL: "broker.example.com:1883"}), ShouldNotBeNil)
+	a.So(validateMQTTBridge(&application.MQTTBridge{URL: "http://broker.example.com"}), ShouldNotBeNil)
+	a.So(validateMQTTBridge(&application.MQTTBridge{URL: "tcp://broker.example.com:1883", TopicPrefix: "ttn/#"}), ShouldNotBeNil)
+}
+
+func TestMQTTBridge(t *testing.T) {
+	host := os.Getenv("MQTT_ADDRESS")
+	if host == "" {
+		host = "localhost:1883"
+	}
+
+	a := New(t)
+	appID := "handler-bridge-app"
+	devID := "handler-bridge-dev"
+
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestMQTTBridge")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+		mqttEvent:    make(chan *types.DeviceEvent, 10),
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		h.WithIntegrationHosts([]string{hostname})
+	}
+	h.devices.Set(&device.Device{AppID: appID, DevID: devID})
+
+	c := mqtt.NewClient(GetLogger(t, "TestMQTTBridge"), "test", "", "", fmt.Sprintf("tcp://%s", host))
+	c.(*mqtt.DefaultClient).SetTopicPrefix("bridge/")
+	a.So(c.Connect(), ShouldBeNil)
+	defer c.Disconnect()
+
+	uplink := make(chan types.UplinkMessage, 1)
+	c.SubscribeDeviceUplink(appID, devID, func(_ mqtt.Client, _, _ string, req types.UplinkMessage) {
+		uplink <- req
+	}).Wait()
+
+	h.startMQTTBridge(appID, &application.MQTTBridge{URL: fmt.Sprintf("tcp://%s", host), TopicPrefix: "bridge/", Downlink: true})
+	defer h.startMQTTBridge(appID, nil)
+
+	for i := 0; i < 20 && !h.mqttBridgeStatus(appID).Connected; i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	a.So(h.mqttBridgeStatus(appID).Connected, ShouldBeTrue)
+	time.Sleep(50 * time.Millisecond)
+
+	h.subscriptions.publishUplink(&types.UplinkMessage{AppID: appID, DevID: devID, FPort: 1})
+	select {
+	case up := <-uplink:
+		a.So(up.FPort, ShouldEqual, 1)
+	case <-time.After(time.Second):
+		t.Fatal("Uplink was not bridged")
+	}
+
+	c.PublishDownlink(types.DownlinkMessage{AppID: appID, DevID: devID, FPort: 1, PayloadRaw: []byte{0x01}}).Wait()
+	time.Sleep(50 * time.Millisecond)
+	queue, _ := h.devices.DownlinkQueue(appID, devID)
+	next, _ := queue.Next()
+	a.So(next, ShouldNotBeNil)
+}
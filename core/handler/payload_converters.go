@@ -0,0 +1,164 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// MaxPayloadConverters is the maximum number of payload converters of an application
+const MaxPayloadConverters = 20
+
+// MaxDeviceAttributes is the maximum number of attributes of a device
+const MaxDeviceAttributes = 10
+
+// PayloadConvertersRequest is returned and accepted by the converters endpoint of the HTTP API
+type PayloadConvertersRequest struct {
+	Converters []application.PayloadConverter `json:"converters"`
+}
+
+// DeviceAttributesRequest is returned and accepted by the device attributes endpoint of the HTTP API
+type DeviceAttributesRequest struct {
+	AppID      string            `json:"app_id"`
+	DevID      string            `json:"dev_id"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+func validatePayloadConverters(converters []application.PayloadConverter) error {
+	if len(converters) > MaxPayloadConverters {
+		return errors.NewErrInvalidArgument("Converters", fmt.Sprintf("can not have more than %d converters", MaxPayloadConverters))
+	}
+	ids := make(map[string]bool, len(converters))
+	for _, converter := range converters {
+		if !api.ValidID(converter.ID) {
+			return errors.NewErrInvalidArgument("Converter ID", fmt.Sprintf("%s has an invalid format", converter.ID))
+		}
+		if ids[converter.ID] {
+			return errors.NewErrInvalidArgument("Converter ID", fmt.Sprintf("%s is not unique", converter.ID))
+		}
+		ids[converter.ID] = true
+		if converter.Port == 0 && converter.Attribute == "" {
+			return errors.NewErrInvalidArgument(fmt.Sprintf("Converter %s", converter.ID), "must select a port or an attribute")
+		}
+		if converter.Attribute == "" && converter.Value != "" {
+			return errors.NewErrInvalidArgument(fmt.Sprintf("Converter %s", converter.ID), "has a value without an attribute")
+		}
+		if converter.Decoder == "" && converter.Encoder == "" {
+			return errors.NewErrInvalidArgument(fmt.Sprintf("Converter %s", converter.ID), "does not have a decoder or encoder")
+		}
+	}
+	return nil
+}
+
+func validateDeviceAttributes(attributes map[string]string) error {
+	if len(attributes) > MaxDeviceAttributes {
+		return errors.NewErrInvalidArgument("Attributes", fmt.Sprintf("can not have more than %d attributes", MaxDeviceAttributes))
+	}
+	for key := range attributes {
+		if !api.ValidID(key) {
+			return errors.NewErrInvalidArgument("Attribute", fmt.Sprintf("%s has an invalid format", key))
+		}
+	}
+	return nil
+}
+
+func (h *httpHandler) getPayloadConverters(req *http.Request, appID string) (*PayloadConvertersRequest, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	converters := app.PayloadConverters
+	if converters == nil {
+		converters = []application.PayloadConverter{}
+	}
+	return &PayloadConvertersRequest{Converters: converters}, nil
+}
+
+// setPayloadConverters replaces the payload converters of the application.
+// The payload functions must still pass the payload tests of the application.
+func (h *httpHandler) setPayloadConverters(req *http.Request, appID string) (*PayloadConvertersRequest, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in PayloadConvertersRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := validatePayloadConverters(in.Converters); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.PayloadConverters = in.Converters
+	if err := checkPayloadTests(app); err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	if in.Converters == nil {
+		in.Converters = []application.PayloadConverter{}
+	}
+	return &in, nil
+}
+
+func (h *httpHandler) getDeviceAttributes(req *http.Request, appID, devID string) (*DeviceAttributesRequest, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	attributes := dev.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return &DeviceAttributesRequest{AppID: dev.AppID, DevID: dev.DevID, Attributes: attributes}, nil
+}
+
+// setDeviceAttributes replaces the attributes of the device
+func (h *httpHandler) setDeviceAttributes(req *http.Request, appID, devID string) (*DeviceAttributesRequest, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	var in DeviceAttributesRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := validateDeviceAttributes(in.Attributes); err != nil {
+		return nil, err
+	}
+	if codec := in.Attributes[CodecAttribute]; codec != "" && h.manager.handler.codecs != nil {
+		if _, err := h.manager.handler.codecs.get(codec); err != nil {
+			return nil, err
+		}
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	dev.StartUpdate()
+	dev.Attributes = in.Attributes
+	if err := h.manager.handler.devices.Set(dev); err != nil {
+		return nil, err
+	}
+	if in.Attributes == nil {
+		in.Attributes = map[string]string{}
+	}
+	return &DeviceAttributesRequest{AppID: dev.AppID, DevID: dev.DevID, Attributes: in.Attributes}, nil
+}
@@ -0,0 +1,107 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidatePayloadConverters(t *testing.T) {
+	a := New(t)
+
+	decoder := `function Decoder(bytes, port) { return {}; }`
+
+	a.So(validatePayloadConverters(nil), ShouldBeNil)
+	a.So(validatePayloadConverters([]application.PayloadConverter{
+		{ID: "tracker", Attribute: "model", Value: "tracker", Decoder: decoder},
+		{ID: "config", Port: 100, Encoder: `function Encoder(object, port) { return []; }`},
+	}), ShouldBeNil)
+
+	a.So(validatePayloadConverters([]application.PayloadConverter{{ID: "Invalid ID", Port: 1, Decoder: decoder}}), ShouldNotBeNil)
+	a.So(validatePayloadConverters([]application.PayloadConverter{
+		{ID: "tracker", Port: 1, Decoder: decoder},
+		{ID: "tracker", Port: 2, Decoder: decoder},
+	}), ShouldNotBeNil)
+	a.So(validatePayloadConverters([]application.PayloadConverter{{ID: "all", Decoder: decoder}}), ShouldNotBeNil)
+	a.So(validatePayloadConverters([]application.PayloadConverter{{ID: "value", Port: 1, Value: "tracker", Decoder: decoder}}), ShouldNotBeNil)
+	a.So(validatePayloadConverters([]application.PayloadConverter{{ID: "empty", Port: 1}}), ShouldNotBeNil)
+
+	a.So(validateDeviceAttributes(map[string]string{"model": "tracker"}), ShouldBeNil)
+	a.So(validateDeviceAttributes(map[string]string{"Model Name": "tracker"}), ShouldNotBeNil)
+}
+
+func TestConvertFieldsWithPayloadConverters(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		applications: application.NewMemoryApplicationStore(),
+		mqttEvent:    make(chan *types.DeviceEvent, 10),
+	}
+	app := &application.Application{
+		AppID:   "app",
+		Decoder: `function Decoder(bytes, port) { return { product: "default" }; }`,
+		Encoder: `function Encoder(object, port) { return [1]; }`,
+		PayloadConverters: []application.PayloadConverter{
+			{ID: "tracker", Attribute: "model", Value: "tracker", Decoder: `function Decoder(bytes, port) { return { product: "tracker" }; }`},
+			{ID: "config", Port: 100, Decoder: `function Decoder(bytes, port) { return { product: "config" }; }`, Encoder: `function Encoder(object, port) { return [100]; }`},
+		},
+	}
+	a.So(h.applications.Set(app), ShouldBeNil)
+
+	tracker := &device.Device{AppID: "app", DevID: "tracker", Attributes: map[string]string{"model": "tracker"}}
+	sensor := &device.Device{AppID: "app", DevID: "sensor"}
+
+	up := func(dev *device.Device, port uint8) interface{} {
+		appUp := &types.UplinkMessage{AppID: "app", FPort: port, PayloadRaw: []byte{0x01}}
+		err := h.ConvertFieldsUp(GetLogger(t, "TestConvertFieldsWithPayloadConverters"), &pb_broker.DeduplicatedUplinkMessage{}, appUp, dev)
+		a.So(err, ShouldBeNil)
+		return appUp.PayloadFields["product"]
+	}
+	a.So(up(tracker, 1), ShouldEqual, "tracker")
+	a.So(up(tracker, 100), ShouldEqual, "tracker")
+	a.So(up(sensor, 100), ShouldEqual, "config")
+	a.So(up(sensor, 1), ShouldEqual, "default")
+	a.So(up(nil, 1), ShouldEqual, "default")
+
+	down := func(dev *device.Device, port uint8) []byte {
+		appDown := &types.DownlinkMessage{AppID: "app", FPort: port, PayloadFields: map[string]interface{}{"key": true}}
+		err := h.ConvertFieldsDown(GetLogger(t, "TestConvertFieldsWithPayloadConverters"), appDown, &pb_broker.DownlinkMessage{}, dev)
+		a.So(err, ShouldBeNil)
+		return appDown.PayloadRaw
+	}
+	a.So(down(sensor, 100), ShouldResemble, []byte{100})
+	a.So(down(sensor, 1), ShouldResemble, []byte{1})
+
+	// The tracker converter does not have an encoder
+	appDown := &types.DownlinkMessage{AppID: "app", FPort: 1, PayloadFields: map[string]interface{}{"key": true}}
+	err := h.ConvertFieldsDown(GetLogger(t, "TestConvertFieldsWithPayloadConverters"), appDown, &pb_broker.DownlinkMessage{}, tracker)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestPayloadTestsWithPayloadConverters(t *testing.T) {
+	a := New(t)
+	app := &application.Application{
+		AppID:   "app",
+		Decoder: `function Decoder(bytes, port) { return { product: "default" }; }`,
+		PayloadConverters: []application.PayloadConverter{
+			{ID: "tracker", Attribute: "model", Value: "tracker", Decoder: `function Decoder(bytes, port) { return { product: "tracker" }; }`},
+		},
+		PayloadTests: []application.PayloadTest{
+			{Name: "default", Port: 1, Payload: []byte{1}, Fields: map[string]interface{}{"product": "default"}},
+			{Name: "tracker", Port: 1, Payload: []byte{1}, Attributes: map[string]string{"model": "tracker"}, Fields: map[string]interface{}{"product": "tracker"}},
+		},
+	}
+	response := runApplicationPayloadTests(app)
+	a.So(response.Passed, ShouldEqual, 2)
+	a.So(checkPayloadTests(app), ShouldBeNil)
+
+	app.PayloadConverters[0].Decoder = `function Decoder(bytes, port) { return { product: "other" }; }`
+	a.So(checkPayloadTests(app), ShouldNotBeNil)
+}
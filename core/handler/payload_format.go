@@ -0,0 +1,39 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+)
+
+// payloadFormat returns the format in which the uplink messages of the application are published
+func (h *handler) payloadFormat(appID string) types.PayloadFormat {
+	format, _ := h.uplinkPublishing(appID)
+	return format
+}
+
+// uplinkPublishing returns the format in which the uplink messages of the
+// application are published and the additional topic pattern for MQTT, if any
+func (h *handler) uplinkPublishing(appID string) (types.PayloadFormat, *mqtt.TopicPattern) {
+	app, err := h.applications.Get(appID)
+	if err != nil {
+		return types.PayloadFormatJSON, nil
+	}
+	ctx := h.Ctx.WithField("AppID", appID)
+	format, err := types.ParsePayloadFormat(app.PayloadFormat)
+	if err != nil {
+		ctx.WithError(err).Warn("Invalid payload format, using JSON")
+		format = types.PayloadFormatJSON
+	}
+	if app.TopicPattern == "" {
+		return format, nil
+	}
+	pattern, err := mqtt.ParseTopicPattern(app.TopicPattern)
+	if err != nil {
+		ctx.WithError(err).Warn("Invalid topic pattern")
+		return format, nil
+	}
+	return format, pattern
+}
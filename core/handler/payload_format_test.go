@@ -0,0 +1,47 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPayloadFormat(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestPayloadFormat")},
+		applications: application.NewMemoryApplicationStore(),
+	}
+
+	a.So(h.payloadFormat("unknown"), ShouldEqual, types.PayloadFormatJSON)
+
+	h.applications.Set(&application.Application{AppID: "default"})
+	a.So(h.payloadFormat("default"), ShouldEqual, types.PayloadFormatJSON)
+
+	h.applications.Set(&application.Application{AppID: "cbor", PayloadFormat: "cbor"})
+	a.So(h.payloadFormat("cbor"), ShouldEqual, types.PayloadFormatCBOR)
+
+	h.applications.Set(&application.Application{AppID: "invalid", PayloadFormat: "xml"})
+	a.So(h.payloadFormat("invalid"), ShouldEqual, types.PayloadFormatJSON)
+
+	format, pattern := h.uplinkPublishing("default")
+	a.So(format, ShouldEqual, types.PayloadFormatJSON)
+	a.So(pattern, ShouldBeNil)
+
+	h.applications.Set(&application.Application{AppID: "pattern", PayloadFormat: "protobuf", TopicPattern: "port/{port}"})
+	format, pattern = h.uplinkPublishing("pattern")
+	a.So(format, ShouldEqual, types.PayloadFormatProtobuf)
+	a.So(pattern, ShouldNotBeNil)
+	a.So(pattern.Topic(types.UplinkMessage{AppID: "pattern", FPort: 1}), ShouldEqual, "pattern/port/1")
+
+	h.applications.Set(&application.Application{AppID: "invalid-pattern", TopicPattern: "port/#"})
+	_, pattern = h.uplinkPublishing("invalid-pattern")
+	a.So(pattern, ShouldBeNil)
+}
@@ -0,0 +1,197 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/functions"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// MaxPayloadTests is the maximum number of payload tests of an application
+const MaxPayloadTests = 100
+
+// PayloadTestsRequest is accepted by the payload tests endpoint of the HTTP API
+type PayloadTestsRequest struct {
+	Tests []application.PayloadTest `json:"tests"`
+}
+
+// PayloadTestResult is the result of a payload test
+type PayloadTestResult struct {
+	application.PayloadTest
+	Passed          bool                   `json:"passed"`
+	CandidateFields map[string]interface{} `json:"candidate_fields,omitempty"`
+	Changed         []string               `json:"changed,omitempty"`
+	Valid           bool                   `json:"valid"`
+	Error           string                 `json:"error,omitempty"`
+	Logs            []*pb.LogEntry         `json:"logs,omitempty"`
+}
+
+// PayloadTestsResponse is returned by the payload tests endpoints of the HTTP API
+type PayloadTestsResponse struct {
+	AppID   string               `json:"app_id"`
+	Passed  int                  `json:"passed"`
+	Failed  int                  `json:"failed"`
+	Results []*PayloadTestResult `json:"results"`
+}
+
+func validatePayloadTests(tests []application.PayloadTest) error {
+	if len(tests) > MaxPayloadTests {
+		return errors.NewErrInvalidArgument("Payload Tests", fmt.Sprintf("can not have more than %d tests", MaxPayloadTests))
+	}
+	for i, test := range tests {
+		if test.Port == 0 {
+			return errors.NewErrInvalidArgument("Payload Tests", fmt.Sprintf("test %d does not have a port", i+1))
+		}
+	}
+	return nil
+}
+
+// runPayloadTest runs the payload functions on the payload of the test and
+// compares the result with the expected fields
+func runPayloadTest(f *UplinkFunctions, test application.PayloadTest) *PayloadTestResult {
+	logger := functions.NewEntryLogger()
+	withLogger := *f
+	withLogger.Logger = logger
+	result := &PayloadTestResult{PayloadTest: test}
+	fields, valid, err := withLogger.Process(test.Payload, test.Port)
+	result.Logs = logger.Logs
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Valid = valid
+	// Compare the JSON representation, as the expected fields are JSON
+	var expected map[string]interface{}
+	data, err := json.Marshal(test.Fields)
+	if err == nil {
+		err = json.Unmarshal(data, &expected)
+	}
+	if err == nil && fields != nil {
+		data, err = json.Marshal(fields)
+		if err == nil {
+			err = json.Unmarshal(data, &result.CandidateFields)
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Changed = diffFields(expected, result.CandidateFields)
+	result.Passed = valid != test.Invalid && (test.Invalid || len(result.Changed) == 0)
+	return result
+}
+
+// runPayloadTests runs the payload tests of the application with the given payload functions
+func runPayloadTests(f *UplinkFunctions, appID string, tests []application.PayloadTest) *PayloadTestsResponse {
+	response := &PayloadTestsResponse{AppID: appID, Results: make([]*PayloadTestResult, 0, len(tests))}
+	for _, test := range tests {
+		response.add(runPayloadTest(f, test))
+	}
+	return response
+}
+
+// runApplicationPayloadTests runs the payload tests of the application with
+// the payload functions that its converters select for each test
+func runApplicationPayloadTests(app *application.Application) *PayloadTestsResponse {
+	response := &PayloadTestsResponse{AppID: app.AppID, Results: make([]*PayloadTestResult, 0, len(app.PayloadTests))}
+	for _, test := range app.PayloadTests {
+		converter := app.PayloadConverterFor(test.Port, test.Attributes)
+		f := &UplinkFunctions{Decoder: converter.Decoder, Converter: converter.Converter, Validator: converter.Validator}
+		response.add(runPayloadTest(f, test))
+	}
+	return response
+}
+
+func (r *PayloadTestsResponse) add(result *PayloadTestResult) {
+	if result.Passed {
+		r.Passed++
+	} else {
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// checkPayloadTests returns an error if the payload functions of the
+// application do not pass its payload tests
+func checkPayloadTests(app *application.Application) error {
+	if len(app.PayloadTests) == 0 {
+		return nil
+	}
+	response := runApplicationPayloadTests(app)
+	if response.Failed == 0 {
+		return nil
+	}
+	var failed *PayloadTestResult
+	for _, result := range response.Results {
+		if !result.Passed {
+			failed = result
+			break
+		}
+	}
+	name := failed.Name
+	if name == "" {
+		name = fmt.Sprintf("port %d payload %X", failed.Port, failed.Payload)
+	}
+	return errors.NewErrInvalidArgument("Payload Functions", fmt.Sprintf("%d of %d payload tests fail, including %s", response.Failed, len(app.PayloadTests), name))
+}
+
+func (h *httpHandler) getPayloadTests(req *http.Request, appID string) (*PayloadTestsRequest, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	tests := app.PayloadTests
+	if tests == nil {
+		tests = []application.PayloadTest{}
+	}
+	return &PayloadTestsRequest{Tests: tests}, nil
+}
+
+// setPayloadTests replaces the payload tests of the application and returns
+// the results of the current payload functions. Tests that fail are stored,
+// so that tests can be written before the payload functions.
+func (h *httpHandler) setPayloadTests(req *http.Request, appID string) (*PayloadTestsResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in PayloadTestsRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := validatePayloadTests(in.Tests); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.PayloadTests = in.Tests
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return runApplicationPayloadTests(app), nil
+}
+
+// runPayloadTests runs the payload tests of the application with its current payload functions
+func (h *httpHandler) runPayloadTests(req *http.Request, appID string) (*PayloadTestsResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	return runApplicationPayloadTests(app), nil
+}
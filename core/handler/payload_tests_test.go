@@ -0,0 +1,60 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	. "github.com/smartystreets/assertions"
+)
+
+const testDecoder = `function Decoder(bytes, port) { return { temperature: ((bytes[0] << 8) | bytes[1]) / 100 }; }`
+
+func TestRunPayloadTests(t *testing.T) {
+	a := New(t)
+
+	f := &UplinkFunctions{
+		Decoder:   testDecoder,
+		Validator: `function Validator(fields, port) { return fields.temperature < 50; }`,
+	}
+	response := runPayloadTests(f, "app", []application.PayloadTest{
+		{Name: "room", Port: 1, Payload: []byte{0x08, 0x70}, Fields: map[string]interface{}{"temperature": 21.6}},
+		{Name: "wrong", Port: 1, Payload: []byte{0x08, 0x70}, Fields: map[string]interface{}{"temperature": 21}},
+		{Name: "too hot", Port: 1, Payload: []byte{0x27, 0x10}, Invalid: true},
+		{Name: "not too hot", Port: 1, Payload: []byte{0x08, 0x70}, Invalid: true},
+	})
+	a.So(response.AppID, ShouldEqual, "app")
+	a.So(response.Passed, ShouldEqual, 2)
+	a.So(response.Failed, ShouldEqual, 2)
+	a.So(response.Results[0].Passed, ShouldBeTrue)
+	a.So(response.Results[1].Passed, ShouldBeFalse)
+	a.So(response.Results[1].Changed, ShouldResemble, []string{"temperature"})
+	a.So(response.Results[2].Passed, ShouldBeTrue)
+	a.So(response.Results[3].Passed, ShouldBeFalse)
+
+	broken := runPayloadTest(&UplinkFunctions{Decoder: `function Decoder() { throw "broken"; }`}, application.PayloadTest{Port: 1})
+	a.So(broken.Passed, ShouldBeFalse)
+	a.So(broken.Error, ShouldNotBeEmpty)
+}
+
+func TestCheckPayloadTests(t *testing.T) {
+	a := New(t)
+
+	app := &application.Application{AppID: "app", Decoder: testDecoder}
+	a.So(checkPayloadTests(app), ShouldBeNil)
+
+	app.PayloadTests = []application.PayloadTest{
+		{Name: "room", Port: 1, Payload: []byte{0x08, 0x70}, Fields: map[string]interface{}{"temperature": 21.6}},
+	}
+	a.So(checkPayloadTests(app), ShouldBeNil)
+
+	app.Decoder = `function Decoder(bytes, port) { return { temperature: bytes[0] }; }`
+	err := checkPayloadTests(app)
+	a.So(err, ShouldNotBeNil)
+	a.So(err.Error(), ShouldContainSubstring, "room")
+
+	a.So(validatePayloadTests([]application.PayloadTest{{Port: 0}}), ShouldNotBeNil)
+	a.So(validatePayloadTests(make([]application.PayloadTest, MaxPayloadTests+1)), ShouldNotBeNil)
+}
@@ -0,0 +1,43 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// publishPolicyViolations publishes the policy violations that the
+// NetworkServer added to the uplink
+func (h *handler) publishPolicyViolations(uplink *pb_broker.DeduplicatedUplinkMessage) {
+	for _, violation := range uplink.PolicyViolations {
+		data := types.PolicyViolationEventData{
+			Policy:    violation.Policy,
+			Violation: violation.Violation,
+		}
+		if violation.Since != 0 {
+			data.Since = time.Unix(0, violation.Since).UTC().Format(time.RFC3339)
+		}
+		h.mqttEvent <- &types.DeviceEvent{
+			AppID: uplink.AppId,
+			DevID: uplink.DevId,
+			Event: types.PolicyViolationEvent,
+			Data:  data,
+		}
+	}
+}
+
+// checkPolicyViolations returns an error if the device violates a policy of the
+// network, so that its application downlink is withheld
+func checkPolicyViolations(uplink *pb_broker.DeduplicatedUplinkMessage) error {
+	if len(uplink.PolicyViolations) == 0 {
+		return nil
+	}
+	violation := uplink.PolicyViolations[0]
+	return errors.NewErrPermissionDenied(fmt.Sprintf("device violates %s policy: %s", violation.Policy, violation.Violation))
+}
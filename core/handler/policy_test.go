@@ -0,0 +1,42 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPublishPolicyViolations(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+
+	uplink := &pb_broker.DeduplicatedUplinkMessage{AppId: "app", DevId: "dev"}
+	h.publishPolicyViolations(uplink)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	uplink.PolicyViolations = []*pb_broker.PolicyViolation{{
+		Policy:    "fair-access",
+		Violation: "10 downlinks reached the maximum of 10",
+		Since:     time.Date(2017, 3, 20, 10, 12, 33, 0, time.UTC).UnixNano(),
+	}}
+	h.publishPolicyViolations(uplink)
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+
+	event := <-h.mqttEvent
+	a.So(event.AppID, ShouldEqual, "app")
+	a.So(event.DevID, ShouldEqual, "dev")
+	a.So(event.Event, ShouldEqual, types.PolicyViolationEvent)
+	a.So(event.Data, ShouldResemble, types.PolicyViolationEventData{
+		Policy:    "fair-access",
+		Violation: "10 downlinks reached the maximum of 10",
+		Since:     "2017-03-20T10:12:33Z",
+	})
+}
@@ -0,0 +1,408 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+// MaxProfiles is the maximum number of device profiles of an application
+const MaxProfiles = 20
+
+// ProfilesResponse is returned by the profiles endpoint of the HTTP API
+type ProfilesResponse struct {
+	AppID    string                      `json:"app_id"`
+	Profiles []application.DeviceProfile `json:"profiles"`
+}
+
+// ProfileResponse is returned by the profile endpoint of the HTTP API. When
+// the profile is set, Devices is the number of devices that use the profile
+// and Failed are the devices of which the NetworkServer could not be updated.
+type ProfileResponse struct {
+	AppID   string                    `json:"app_id"`
+	Profile application.DeviceProfile `json:"profile"`
+	Devices int                       `json:"devices"`
+	Failed  []string                  `json:"failed,omitempty"`
+}
+
+// DeviceProfileRequest is returned and accepted by the device profile endpoint
+// of the HTTP API. An empty profile ID removes the profile from the device.
+type DeviceProfileRequest struct {
+	AppID     string `json:"app_id"`
+	DevID     string `json:"dev_id"`
+	ProfileID string `json:"profile_id"`
+}
+
+var (
+	lorawanVersions = []string{"1.0", "1.0.1", "1.0.2"}
+	// Downlinks are only scheduled in the receive windows after an uplink, so Class B and Class C are not supported
+	deviceClasses = []string{"A"}
+)
+
+// ProfileSyncAttempts is the number of times that the Handler tries to set the
+// settings of a device profile in the NetworkServer
+var ProfileSyncAttempts = 3
+
+// ProfileSyncBackoff is the delay before the Handler tries again to set the
+// settings of a device profile in the NetworkServer. It doubles after every attempt.
+var ProfileSyncBackoff = 500 * time.Millisecond
+
+func validateProfile(profile application.DeviceProfile) error {
+	if !api.ValidID(profile.ID) {
+		return errors.NewErrInvalidArgument("Profile ID", fmt.Sprintf("%s has an invalid format", profile.ID))
+	}
+	if profile.LoRaWANVersion != "" && !stringInSlice(profile.LoRaWANVersion, lorawanVersions) {
+		return errors.NewErrInvalidArgument("LoRaWAN Version", fmt.Sprintf("must be %s", strings.Join(lorawanVersions, ", ")))
+	}
+	if profile.Class != "" && !stringInSlice(profile.Class, deviceClasses) {
+		return errors.NewErrInvalidArgument("Class", fmt.Sprintf("must be %s, as Class B and Class C are not supported", strings.Join(deviceClasses, ", ")))
+	}
+	if err := validateJoinAcceptSettings(profile.JoinAccept, ""); err != nil {
+		return err
+	}
+	for _, dataRate := range []string{profile.ADRLimits.MinDataRate, profile.ADRLimits.MaxDataRate} {
+		if dataRate == "" {
+			continue
+		}
+		if _, err := types.ParseDataRate(dataRate); err != nil && !band.IsFSK(dataRate) {
+			return errors.NewErrInvalidArgument("ADR Limits", err.Error())
+		}
+	}
+	if profile.ADRLimits.MaxTxPower < 0 {
+		return errors.NewErrInvalidArgument("ADR Limits", "max TX power can not be negative")
+	}
+	return nil
+}
+
+func validateProfiles(profiles []application.DeviceProfile) error {
+	if len(profiles) > MaxProfiles {
+		return errors.NewErrInvalidArgument("Profiles", fmt.Sprintf("can not have more than %d profiles", MaxProfiles))
+	}
+	ids := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		if err := validateProfile(profile); err != nil {
+			return err
+		}
+		if ids[profile.ID] {
+			return errors.NewErrInvalidArgument("Profile ID", fmt.Sprintf("%s is not unique", profile.ID))
+		}
+		ids[profile.ID] = true
+	}
+	return nil
+}
+
+func stringInSlice(search string, slice []string) bool {
+	for _, s := range slice {
+		if s == search {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceProfile returns the profile of the device, if it has one
+func (h *handler) deviceProfile(app *application.Application, dev *device.Device) (application.DeviceProfile, bool) {
+	if app == nil || dev == nil || dev.Profile == "" {
+		return application.DeviceProfile{}, false
+	}
+	return app.Profile(dev.Profile)
+}
+
+// setNetworkServerDevice sets a setting of a device in the NetworkServer,
+// using the HTTP API of the NetworkServer. The token must have devices rights
+// to the application of the device.
+func (h *handler) setNetworkServerDevice(token string, dev *device.Device, setting string, value interface{}) error {
+	apiAddress, err := h.networkServerAPIAddress()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/devices/%s/%s/%s", apiAddress, dev.AppEUI, dev.DevEUI, setting)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return errors.NewErrPermissionDenied(fmt.Sprintf("NetworkServer did not set %s", setting))
+	case http.StatusNotFound:
+		return errors.NewErrNotFound(fmt.Sprintf("Device %s in NetworkServer", dev.DevID))
+	default:
+		body, _ := ioutil.ReadAll(res.Body)
+		if res.StatusCode == http.StatusBadRequest {
+			return errors.NewErrInvalidArgument(setting, strings.TrimSpace(string(body)))
+		}
+		return errors.NewErrInternal(fmt.Sprintf("NetworkServer did not set %s: %s %s", setting, res.Status, strings.TrimSpace(string(body))))
+	}
+}
+
+// setNetworkServerProfile sets the settings of the profile that differ from
+// those of the old profile of the device in the NetworkServer. Requests that
+// fail are tried again, unless they are denied or invalid.
+func (h *handler) setNetworkServerProfile(token string, dev *device.Device, old, new application.DeviceProfile) error {
+	var settings []func() error
+	if old.ADRLimits != new.ADRLimits {
+		settings = append(settings, func() error {
+			return h.setNetworkServerDevice(token, dev, "adr-limits", new.ADRLimits)
+		})
+	}
+	if old.LoRaWANVersion != new.LoRaWANVersion {
+		settings = append(settings, func() error {
+			return h.setNetworkServerDevice(token, dev, "lorawan-version", map[string]string{"lorawan_version": new.LoRaWANVersion})
+		})
+	}
+	for _, set := range settings {
+		backoff := ProfileSyncBackoff
+		for attempt := 1; ; attempt++ {
+			err := set()
+			if err == nil {
+				break
+			}
+			switch errors.GetErrType(err) {
+			case errors.PermissionDenied, errors.NotFound, errors.InvalidArgument:
+				return err
+			}
+			if attempt >= ProfileSyncAttempts {
+				return err
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return nil
+}
+
+// syncProfile sets the settings of the new profile of the devices that the
+// NetworkServer applies, if they differ from those of the old profile, and
+// returns the IDs of the devices that could not be updated. Nothing is done
+// if no NetworkServer is configured.
+func (h *handler) syncProfile(ctx context.Context, devices []*device.Device, old, new application.DeviceProfile) (failed []string) {
+	if h.networkServerID == "" || len(devices) == 0 {
+		return nil
+	}
+	if old.ADRLimits == new.ADRLimits && old.LoRaWANVersion == new.LoRaWANVersion {
+		return nil
+	}
+	var token string
+	if md, err := api.MetadataFromContext(ctx); err == nil {
+		token, _ = api.TokenFromMetadata(md)
+	}
+	for _, dev := range devices {
+		if err := h.setNetworkServerProfile(token, dev, old, new); err != nil {
+			h.Ctx.WithError(err).WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).Warn("Could not set device profile in NetworkServer")
+			failed = append(failed, dev.DevID)
+		}
+	}
+	return failed
+}
+
+// profileDevices returns the devices of the application that use the profile
+func (h *handler) profileDevices(appID, profileID string) ([]*device.Device, error) {
+	devices, err := h.devices.ListForApp(appID, nil)
+	if err != nil {
+		return nil, err
+	}
+	var used []*device.Device
+	for _, dev := range devices {
+		if dev.Profile == profileID {
+			used = append(used, dev)
+		}
+	}
+	return used, nil
+}
+
+func (h *httpHandler) getProfiles(req *http.Request, appID string) (*ProfilesResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	profiles := app.Profiles
+	if profiles == nil {
+		profiles = []application.DeviceProfile{}
+	}
+	return &ProfilesResponse{AppID: app.AppID, Profiles: profiles}, nil
+}
+
+func (h *httpHandler) getProfile(req *http.Request, appID, profileID string) (*ProfileResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := app.Profile(profileID)
+	if !ok {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("Profile %s", profileID))
+	}
+	devices, err := h.manager.handler.profileDevices(appID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileResponse{AppID: app.AppID, Profile: profile, Devices: len(devices)}, nil
+}
+
+// setProfile creates or replaces a device profile of the application. If the
+// ADR limits or LoRaWAN version of the profile change, they are set in the
+// NetworkServer for all devices that use the profile.
+func (h *httpHandler) setProfile(req *http.Request, appID, profileID string) (*ProfileResponse, error) {
+	ctx, err := h.authorize(req, appID, rights.AppSettings)
+	if err != nil {
+		return nil, err
+	}
+	var profile application.DeviceProfile
+	if err := json.NewDecoder(req.Body).Decode(&profile); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	profile.ID = profileID
+	if err := validateProfile(profile); err != nil {
+		return nil, err
+	}
+	if profile.Codec != "" && h.manager.handler.codecs != nil {
+		if _, err := h.manager.handler.codecs.get(profile.Codec); err != nil {
+			return nil, err
+		}
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	old, exists := app.Profile(profileID)
+	if !exists && len(app.Profiles) >= MaxProfiles {
+		return nil, errors.NewErrInvalidArgument("Profiles", fmt.Sprintf("can not have more than %d profiles", MaxProfiles))
+	}
+	app.StartUpdate()
+	profiles := make([]application.DeviceProfile, 0, len(app.Profiles)+1)
+	for _, existing := range app.Profiles {
+		if existing.ID != profileID {
+			profiles = append(profiles, existing)
+		}
+	}
+	app.Profiles = append(profiles, profile)
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	devices, err := h.manager.handler.profileDevices(appID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	response := &ProfileResponse{AppID: app.AppID, Profile: profile, Devices: len(devices)}
+	response.Failed = h.manager.handler.syncProfile(ctx, devices, old, profile)
+	return response, nil
+}
+
+// deleteProfile removes a device profile of the application. Profiles that
+// are used by devices can not be removed.
+func (h *httpHandler) deleteProfile(req *http.Request, appID, profileID string) (*ProfilesResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := app.Profile(profileID); !ok {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("Profile %s", profileID))
+	}
+	devices, err := h.manager.handler.profileDevices(appID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) > 0 {
+		return nil, errors.NewErrInvalidArgument("Profile", fmt.Sprintf("%s is used by %d devices", profileID, len(devices)))
+	}
+	app.StartUpdate()
+	profiles := make([]application.DeviceProfile, 0, len(app.Profiles))
+	for _, existing := range app.Profiles {
+		if existing.ID != profileID {
+			profiles = append(profiles, existing)
+		}
+	}
+	app.Profiles = profiles
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &ProfilesResponse{AppID: app.AppID, Profiles: app.Profiles}, nil
+}
+
+func (h *httpHandler) getDeviceProfile(req *http.Request, appID, devID string) (*DeviceProfileRequest, error) {
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		return nil, err
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	return &DeviceProfileRequest{AppID: dev.AppID, DevID: dev.DevID, ProfileID: dev.Profile}, nil
+}
+
+// setDeviceProfile sets the profile of the device and sets the ADR limits and
+// LoRaWAN version of the profile in the NetworkServer
+func (h *httpHandler) setDeviceProfile(req *http.Request, appID, devID string) (*DeviceProfileRequest, error) {
+	ctx, err := h.authorize(req, appID, rights.Devices)
+	if err != nil {
+		return nil, err
+	}
+	var in DeviceProfileRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	var profile application.DeviceProfile
+	if in.ProfileID != "" {
+		var ok bool
+		if profile, ok = app.Profile(in.ProfileID); !ok {
+			return nil, errors.NewErrNotFound(fmt.Sprintf("Profile %s", in.ProfileID))
+		}
+	}
+	dev, err := h.manager.handler.devices.Get(appID, devID)
+	if err != nil {
+		return nil, err
+	}
+	old, _ := h.manager.handler.deviceProfile(app, dev)
+	dev.StartUpdate()
+	dev.Profile = in.ProfileID
+	if err := h.manager.handler.devices.Set(dev); err != nil {
+		return nil, err
+	}
+	if failed := h.manager.handler.syncProfile(ctx, []*device.Device{dev}, old, profile); len(failed) > 0 {
+		return nil, errors.NewErrInternal("Could not set device profile in NetworkServer")
+	}
+	return &DeviceProfileRequest{AppID: dev.AppID, DevID: dev.DevID, ProfileID: dev.Profile}, nil
+}
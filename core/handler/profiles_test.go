@@ -0,0 +1,108 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+func TestValidateProfiles(t *testing.T) {
+	a := New(t)
+
+	a.So(validateProfile(application.DeviceProfile{ID: "sensor"}), ShouldBeNil)
+	a.So(validateProfile(application.DeviceProfile{
+		ID:             "sensor",
+		LoRaWANVersion: "1.0.2",
+		Class:          "A",
+		JoinAccept:     types.JoinAcceptSettings{RX1Delay: 5},
+		ADRLimits:      application.ADRLimits{MinDataRate: "SF10BW125", MaxDataRate: "SF7BW125", MaxTxPower: 14},
+	}), ShouldBeNil)
+
+	a.So(validateProfile(application.DeviceProfile{ID: "Sensor"}), ShouldNotBeNil)
+	a.So(validateProfile(application.DeviceProfile{ID: "sensor", LoRaWANVersion: "1.1"}), ShouldNotBeNil)
+	a.So(validateProfile(application.DeviceProfile{ID: "sensor", Class: "D"}), ShouldNotBeNil)
+	a.So(validateProfile(application.DeviceProfile{ID: "sensor", Class: "C"}), ShouldNotBeNil)
+	a.So(validateProfile(application.DeviceProfile{ID: "sensor", JoinAccept: types.JoinAcceptSettings{RX1Delay: 16}}), ShouldNotBeNil)
+	a.So(validateProfile(application.DeviceProfile{ID: "sensor", ADRLimits: application.ADRLimits{MinDataRate: "SF13BW125"}}), ShouldNotBeNil)
+	a.So(validateProfile(application.DeviceProfile{ID: "sensor", ADRLimits: application.ADRLimits{MaxTxPower: -1}}), ShouldNotBeNil)
+
+	a.So(validateProfiles([]application.DeviceProfile{{ID: "sensor"}, {ID: "sensor"}}), ShouldNotBeNil)
+	a.So(validateProfiles(make([]application.DeviceProfile, MaxProfiles+1)), ShouldNotBeNil)
+}
+
+func TestDeviceProfiles(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestDeviceProfiles")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+
+	dir, err := ioutil.TempDir("", "ttn-codecs")
+	a.So(err, ShouldBeNil)
+	defer os.RemoveAll(dir)
+	a.So(ioutil.WriteFile(filepath.Join(dir, "thermometer.js"), []byte(testCodec), 0644), ShouldBeNil)
+	h.WithCodecRepository(dir)
+
+	app := &application.Application{
+		AppID:      "app",
+		JoinAccept: types.JoinAcceptSettings{RX1Delay: 2, RX2DataRate: "SF12BW125"},
+		Profiles: []application.DeviceProfile{{
+			ID:         "sensor",
+			JoinAccept: types.JoinAcceptSettings{RX1Delay: 5},
+			Codec:      "thermometer",
+		}},
+	}
+	h.applications.Set(app)
+	h.devices.Set(&device.Device{AppID: "app", DevID: "with-profile", Profile: "sensor"})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "without-profile"})
+
+	dev, _ := h.devices.Get("app", "with-profile")
+	profile, ok := h.deviceProfile(app, dev)
+	a.So(ok, ShouldBeTrue)
+	a.So(profile.ID, ShouldEqual, "sensor")
+
+	// The profile overrides the application, the device overrides the profile
+	settings := h.getJoinAcceptSettings(dev)
+	a.So(settings.RX1Delay, ShouldEqual, 5)
+	a.So(settings.RX2DataRate, ShouldEqual, "SF12BW125")
+	dev.JoinAccept = types.JoinAcceptSettings{RX1Delay: 3}
+	a.So(h.getJoinAcceptSettings(dev).RX1Delay, ShouldEqual, 3)
+
+	// The codec of the profile is used, unless the device has a codec
+	converter, err := h.payloadConverter(app, dev, 1)
+	a.So(err, ShouldBeNil)
+	a.So(converter.ID, ShouldEqual, "thermometer")
+	dev.Attributes = map[string]string{CodecAttribute: "unknown"}
+	_, err = h.payloadConverter(app, dev, 1)
+	a.So(err, ShouldNotBeNil)
+
+	other, _ := h.devices.Get("app", "without-profile")
+	_, ok = h.deviceProfile(app, other)
+	a.So(ok, ShouldBeFalse)
+	a.So(h.getJoinAcceptSettings(other).RX1Delay, ShouldEqual, 2)
+
+	devices, err := h.profileDevices("app", "sensor")
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 1)
+	a.So(devices[0].DevID, ShouldEqual, "with-profile")
+
+	// Without a NetworkServer the profile is not set
+	a.So(h.syncProfile(context.Background(), devices, application.DeviceProfile{}, application.DeviceProfile{LoRaWANVersion: "1.0.1"}), ShouldBeEmpty)
+	// The NetworkServer is not configured
+	a.So(h.setNetworkServerDevice("token", devices[0], "adr-limits", application.ADRLimits{}), ShouldNotBeNil)
+	// Unchanged settings are not set
+	a.So(h.setNetworkServerProfile("token", devices[0], application.DeviceProfile{LoRaWANVersion: "1.0"}, application.DeviceProfile{LoRaWANVersion: "1.0"}), ShouldBeNil)
+}
@@ -0,0 +1,340 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+)
+
+// MaxProvisionCount is the maximum number of devices that can be provisioned in one request
+var MaxProvisionCount = 1000
+
+// Provisioning file formats
+const (
+	ProvisionFormatJSON = "json"
+	ProvisionFormatCSV  = "csv"
+	ProvisionFormatQR   = "qr"
+)
+
+var profileIDPattern = regexp.MustCompile("^[[:xdigit:]]{8}$")
+
+// ProvisionRequest is accepted by the provisioning endpoint of the HTTP API
+type ProvisionRequest struct {
+	// Count is the number of devices to provision (default 1)
+	Count int `json:"count"`
+	// AppEUI is the AppEUI (JoinEUI) of the devices
+	AppEUI types.AppEUI `json:"app_eui"`
+	// DevIDPrefix is prepended to the lowercase DevEUI to get the DevID
+	DevIDPrefix string `json:"dev_id_prefix"`
+	// Activation is otaa (default) or abp
+	Activation  string `json:"activation"`
+	Description string `json:"description"`
+	// ProfileID is the vendor and profile ID that is put in the QR code payload (default 00000000)
+	ProfileID string `json:"profile_id"`
+}
+
+// ProvisionedDevice is a device in the provisioning file
+type ProvisionedDevice struct {
+	DevID     string         `json:"dev_id"`
+	AppEUI    types.AppEUI   `json:"app_eui"`
+	DevEUI    types.DevEUI   `json:"dev_eui"`
+	AppKey    *types.AppKey  `json:"app_key,omitempty"`
+	DevAddr   *types.DevAddr `json:"dev_addr,omitempty"`
+	NwkSKey   *types.NwkSKey `json:"nwk_s_key,omitempty"`
+	AppSKey   *types.AppSKey `json:"app_s_key,omitempty"`
+	QRCode    string         `json:"qr_code,omitempty"`
+	ClaimCode string         `json:"claim_code,omitempty"`
+}
+
+// ProvisionResponse is returned by the provisioning endpoint of the HTTP API
+type ProvisionResponse struct {
+	AppID   string              `json:"app_id"`
+	Devices []ProvisionedDevice `json:"devices"`
+}
+
+func (h *handler) WithDevEUIBlock(block types.DevEUIBlock) Handler {
+	h.devEUIBlock = &block
+	return h
+}
+
+// allocateDevEUIs returns the next count DevEUIs from the DevEUI block that
+// are not registered. Allocation continues after the DevEUIs that were
+// provisioned before, or after the highest DevEUI from the block that is
+// registered to this Handler when it provisions the first devices. Callers
+// should hold the provisionLock until the devices are registered and the
+// DevEUIs are committed.
+func (h *handler) allocateDevEUIs(count int) ([]types.DevEUI, error) {
+	if h.devEUIBlock == nil {
+		return nil, errors.NewErrInvalidArgument("DevEUI", "no DevEUI block is configured on this Handler")
+	}
+	if h.devEUICursor == nil {
+		devices, err := h.devices.List(nil)
+		if err != nil {
+			return nil, err
+		}
+		var next uint64
+		for _, dev := range devices {
+			if idx, ok := h.devEUIBlock.Index(dev.DevEUI); ok && idx >= next {
+				next = idx + 1
+			}
+		}
+		h.devEUICursor = &next
+	}
+	euis := make([]types.DevEUI, 0, count)
+	for idx := *h.devEUICursor; len(euis) < count; idx++ {
+		eui, ok := h.devEUIBlock.Get(idx)
+		if !ok {
+			return nil, errors.NewErrInvalidArgument("Count", fmt.Sprintf("the DevEUI block %s is exhausted", h.devEUIBlock))
+		}
+		registered, err := h.devices.ListForEUI(eui)
+		if err != nil {
+			return nil, err
+		}
+		if len(registered) > 0 {
+			continue
+		}
+		euis = append(euis, eui)
+	}
+	return euis, nil
+}
+
+// commitDevEUIs continues the allocation after the DevEUIs that were provisioned
+func (h *handler) commitDevEUIs(euis []types.DevEUI) {
+	if len(euis) == 0 || h.devEUICursor == nil {
+		return
+	}
+	if idx, ok := h.devEUIBlock.Index(euis[len(euis)-1]); ok && idx >= *h.devEUICursor {
+		*h.devEUICursor = idx + 1
+	}
+}
+
+// qrCodePayload returns the payload of the QR code of an OTAA device, in the
+// format of the LoRa Alliance TR005
+func qrCodePayload(appEUI types.AppEUI, devEUI types.DevEUI, profileID string) string {
+	return fmt.Sprintf("LW:D0:%X:%X:%s", appEUI.Bytes(), devEUI.Bytes(), strings.ToUpper(profileID))
+}
+
+func randomKey(key []byte) error {
+	if _, err := rand.Read(key); err != nil {
+		return errors.NewErrInternal(fmt.Sprintf("Could not generate key: %s", err))
+	}
+	return nil
+}
+
+func (in *ProvisionRequest) validate() error {
+	if in.Count == 0 {
+		in.Count = 1
+	}
+	if in.Count < 0 || in.Count > MaxProvisionCount {
+		return errors.NewErrInvalidArgument("Count", fmt.Sprintf("must be between 1 and %d", MaxProvisionCount))
+	}
+	if in.AppEUI.IsEmpty() {
+		return errors.NewErrInvalidArgument("AppEUI", "can not be empty")
+	}
+	switch in.Activation {
+	case "":
+		in.Activation = "otaa"
+	case "otaa", "abp":
+	default:
+		return errors.NewErrInvalidArgument("Activation", "must be otaa or abp")
+	}
+	if !api.ValidID(in.DevIDPrefix + "0000000000000000") {
+		return errors.NewErrInvalidArgument("Dev ID Prefix", "does not result in valid Device IDs")
+	}
+	if in.ProfileID == "" {
+		in.ProfileID = "00000000"
+	}
+	if !profileIDPattern.MatchString(in.ProfileID) {
+		return errors.NewErrInvalidArgument("Profile ID", "must be 4 bytes in hex")
+	}
+	return nil
+}
+
+// provision generates DevEUIs and keys for new devices, and registers them
+// like SetDevice does. If a device can not be registered, the devices that
+// were registered before it are deleted, so that their DevEUIs and keys are
+// not issued.
+func (h *httpHandler) provision(req *http.Request, appID string) (*ProvisionResponse, error) {
+	ctx, err := h.authorize(req, appID, rights.Devices)
+	if err != nil {
+		return nil, err
+	}
+	var in ProvisionRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+	switch req.URL.Query().Get("format") {
+	case "", ProvisionFormatJSON, ProvisionFormatCSV, ProvisionFormatQR:
+	default:
+		return nil, errors.NewErrInvalidArgument("Format", "must be json, csv or qr")
+	}
+	if _, err := h.manager.handler.applications.Get(appID); err != nil {
+		return nil, errors.Wrap(err, "Application not registered to this Handler")
+	}
+
+	h.manager.handler.provisionLock.Lock()
+	defer h.manager.handler.provisionLock.Unlock()
+
+	devEUIs, err := h.manager.handler.allocateDevEUIs(in.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProvisionResponse{AppID: appID}
+	if err := h.registerProvisioned(ctx, appID, &in, devEUIs, response); err != nil {
+		h.rollbackProvision(ctx, appID, response.Devices)
+		return nil, err
+	}
+	h.manager.handler.commitDevEUIs(devEUIs)
+	return response, nil
+}
+
+// registerProvisioned registers the devices with the DevEUIs, and adds them
+// to the response
+func (h *httpHandler) registerProvisioned(ctx context.Context, appID string, in *ProvisionRequest, devEUIs []types.DevEUI, response *ProvisionResponse) error {
+	for i, devEUI := range devEUIs {
+		appEUI := in.AppEUI
+		devEUI := devEUI
+		provisioned := ProvisionedDevice{
+			DevID:  in.DevIDPrefix + strings.ToLower(fmt.Sprintf("%X", devEUI.Bytes())),
+			AppEUI: appEUI,
+			DevEUI: devEUI,
+		}
+		dev := &pb_lorawan.Device{
+			AppId:                 appID,
+			DevId:                 provisioned.DevID,
+			AppEui:                &appEUI,
+			DevEui:                &devEUI,
+			ActivationConstraints: "local",
+		}
+
+		switch in.Activation {
+		case "otaa":
+			var appKey types.AppKey
+			if err := randomKey(appKey[:]); err != nil {
+				return err
+			}
+			dev.AppKey = &appKey
+			provisioned.AppKey = &appKey
+			provisioned.QRCode = qrCodePayload(appEUI, devEUI, in.ProfileID)
+			provisioned.ClaimCode = claimCode(devEUI, appKey)
+		case "abp":
+			res, err := h.manager.GetDevAddr(ctx, &pb_lorawan.DevAddrRequest{AppId: appID, Usage: []string{"local", "abp"}})
+			if err != nil {
+				return err
+			}
+			if res.DevAddr == nil {
+				return errors.New("Broker did not return DevAddr")
+			}
+			devAddr := *res.DevAddr
+			var nwkSKey types.NwkSKey
+			var appSKey types.AppSKey
+			if err := randomKey(nwkSKey[:]); err != nil {
+				return err
+			}
+			if err := randomKey(appSKey[:]); err != nil {
+				return err
+			}
+			dev.AppKey = new(types.AppKey)
+			dev.DevAddr, dev.NwkSKey, dev.AppSKey = &devAddr, &nwkSKey, &appSKey
+			provisioned.DevAddr, provisioned.NwkSKey, provisioned.AppSKey = &devAddr, &nwkSKey, &appSKey
+		}
+
+		if _, err := h.manager.handler.devices.Get(appID, provisioned.DevID); err == nil {
+			return errors.NewErrAlreadyExists(fmt.Sprintf("Device %s", provisioned.DevID))
+		} else if errors.GetErrType(err) != errors.NotFound {
+			return err
+		}
+
+		_, err := h.manager.SetDevice(ctx, &pb.Device{
+			AppId:       appID,
+			DevId:       provisioned.DevID,
+			Description: in.Description,
+			Device:      &pb.Device_LorawanDevice{LorawanDevice: dev},
+		})
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Could not register device %d of %d", i+1, len(devEUIs)))
+		}
+		response.Devices = append(response.Devices, provisioned)
+	}
+	return nil
+}
+
+// rollbackProvision deletes the devices that were provisioned before a device
+// could not be registered
+func (h *httpHandler) rollbackProvision(ctx context.Context, appID string, devices []ProvisionedDevice) {
+	for _, dev := range devices {
+		_, err := h.manager.DeleteDevice(ctx, &pb.DeviceIdentifier{AppId: appID, DevId: dev.DevID})
+		if err != nil {
+			h.manager.handler.Ctx.WithField("AppID", appID).WithField("DevID", dev.DevID).WithError(err).Warn("Could not delete provisioned device")
+		}
+	}
+}
+
+// writeProvision writes the provisioning file in the format of the format
+// query parameter
+func (h *httpHandler) writeProvision(res http.ResponseWriter, req *http.Request, response *ProvisionResponse, err error) {
+	if err != nil {
+		h.write(res, nil, err)
+		return
+	}
+	switch req.URL.Query().Get("format") {
+	case ProvisionFormatCSV:
+		res.Header().Set("Content-Type", "text/csv")
+		writeProvisionCSV(res, response)
+	case ProvisionFormatQR:
+		res.Header().Set("Content-Type", "text/plain")
+		for _, dev := range response.Devices {
+			if dev.QRCode != "" {
+				fmt.Fprintln(res, dev.QRCode)
+			}
+		}
+	default:
+		h.write(res, response, nil)
+	}
+}
+
+func writeProvisionCSV(res io.Writer, response *ProvisionResponse) error {
+	hex := func(b []byte) string {
+		return fmt.Sprintf("%X", b)
+	}
+	w := csv.NewWriter(res)
+	w.Write([]string{"app_id", "dev_id", "app_eui", "dev_eui", "app_key", "dev_addr", "nwk_s_key", "app_s_key", "qr_code", "claim_code"})
+	for _, dev := range response.Devices {
+		record := []string{response.AppID, dev.DevID, hex(dev.AppEUI.Bytes()), hex(dev.DevEUI.Bytes()), "", "", "", "", dev.QRCode, dev.ClaimCode}
+		if dev.AppKey != nil {
+			record[4] = hex(dev.AppKey.Bytes())
+		}
+		if dev.DevAddr != nil {
+			record[5] = hex(dev.DevAddr.Bytes())
+		}
+		if dev.NwkSKey != nil {
+			record[6] = hex(dev.NwkSKey.Bytes())
+		}
+		if dev.AppSKey != nil {
+			record[7] = hex(dev.AppSKey.Bytes())
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,89 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestAllocateDevEUIs(t *testing.T) {
+	a := New(t)
+	h := &handler{devices: device.NewMemoryDeviceStore()}
+
+	_, err := h.allocateDevEUIs(1)
+	a.So(err, ShouldNotBeNil)
+
+	block, _ := types.ParseDevEUIBlock("70B3D57ED0000000/62")
+	h.WithDevEUIBlock(block)
+
+	euis, err := h.allocateDevEUIs(2)
+	a.So(err, ShouldBeNil)
+	a.So(euis, ShouldResemble, []types.DevEUI{
+		{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x00},
+		{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x01},
+	})
+
+	// Allocation continues after the committed DevEUIs, and skips registered DevEUIs of any application
+	h.commitDevEUIs(euis[:1])
+	h.devices.Set(&device.Device{AppID: "other", DevID: "dev", DevEUI: types.DevEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x01}})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev", DevEUI: types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}})
+	euis, err = h.allocateDevEUIs(2)
+	a.So(err, ShouldBeNil)
+	a.So(euis, ShouldResemble, []types.DevEUI{
+		{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x02},
+		{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x03},
+	})
+	h.commitDevEUIs(euis)
+
+	// The block is exhausted
+	_, err = h.allocateDevEUIs(1)
+	a.So(err, ShouldNotBeNil)
+
+	// When the Handler starts, allocation continues after the highest registered DevEUI
+	h.devEUICursor = nil
+	euis, err = h.allocateDevEUIs(1)
+	a.So(err, ShouldBeNil)
+	a.So(euis[0], ShouldEqual, types.DevEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x02})
+}
+
+func TestProvisionRequestValidate(t *testing.T) {
+	a := New(t)
+
+	in := ProvisionRequest{AppEUI: types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}}
+	a.So(in.validate(), ShouldBeNil)
+	a.So(in.Count, ShouldEqual, 1)
+	a.So(in.Activation, ShouldEqual, "otaa")
+	a.So(in.ProfileID, ShouldEqual, "00000000")
+
+	a.So((&ProvisionRequest{}).validate(), ShouldNotBeNil)
+	a.So((&ProvisionRequest{AppEUI: in.AppEUI, Count: MaxProvisionCount + 1}).validate(), ShouldNotBeNil)
+	a.So((&ProvisionRequest{AppEUI: in.AppEUI, Activation: "otab"}).validate(), ShouldNotBeNil)
+	a.So((&ProvisionRequest{AppEUI: in.AppEUI, DevIDPrefix: "Sensor-"}).validate(), ShouldNotBeNil)
+	a.So((&ProvisionRequest{AppEUI: in.AppEUI, DevIDPrefix: "sensor-"}).validate(), ShouldBeNil)
+	a.So((&ProvisionRequest{AppEUI: in.AppEUI, ProfileID: "0001"}).validate(), ShouldNotBeNil)
+}
+
+func TestProvisionFormats(t *testing.T) {
+	a := New(t)
+
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	devEUI := types.DevEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x0A}
+	a.So(qrCodePayload(appEUI, devEUI, "00aabb01"), ShouldEqual, "LW:D0:0102030405060708:70B3D57ED000000A:00AABB01")
+
+	appKey := types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	var buf bytes.Buffer
+	err := writeProvisionCSV(&buf, &ProvisionResponse{AppID: "app", Devices: []ProvisionedDevice{
+		{DevID: "70b3d57ed000000a", AppEUI: appEUI, DevEUI: devEUI, AppKey: &appKey},
+	}})
+	a.So(err, ShouldBeNil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	a.So(lines, ShouldHaveLength, 2)
+	a.So(lines[1], ShouldEqual, "app,70b3d57ed000000a,0102030405060708,70B3D57ED000000A,01020304050607080102030405060708,,,,,")
+}
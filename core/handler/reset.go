@@ -0,0 +1,25 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// publishDeviceReset publishes the reset of the device that the
+// NetworkServer added to the uplink
+func (h *handler) publishDeviceReset(uplink *pb_broker.DeduplicatedUplinkMessage) {
+	if uplink.DeviceReset == nil {
+		return
+	}
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: uplink.AppId,
+		DevID: uplink.DevId,
+		Event: types.DeviceResetEvent,
+		Data: types.DeviceResetEventData{
+			LoRaWANVersion: uplink.DeviceReset.LorawanVersion,
+		},
+	}
+}
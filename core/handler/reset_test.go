@@ -0,0 +1,38 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPublishDeviceReset(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+
+	uplink := &pb_broker.DeduplicatedUplinkMessage{AppId: "app", DevId: "dev"}
+	h.publishDeviceReset(uplink)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	uplink.Trace = uplink.Trace.WithEvent(trace.HandleMACEvent, "mac", "reset")
+	h.publishDeviceReset(uplink)
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	uplink.DeviceReset = &pb_broker.DeviceReset{LorawanVersion: "1.1"}
+	h.publishDeviceReset(uplink)
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+
+	event := <-h.mqttEvent
+	a.So(event.AppID, ShouldEqual, "app")
+	a.So(event.DevID, ShouldEqual, "dev")
+	a.So(event.Event, ShouldEqual, types.DeviceResetEvent)
+	a.So(event.Data, ShouldResemble, types.DeviceResetEventData{LoRaWANVersion: "1.1"})
+}
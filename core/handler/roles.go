@@ -0,0 +1,231 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/claims"
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/bluele/gcache"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc/metadata"
+)
+
+// MQTT access levels in MQTT ACL requests
+const (
+	MQTTAccessRead      = 1
+	MQTTAccessWrite     = 2
+	MQTTAccessSubscribe = 4
+)
+
+// RolesResponse is returned by the roles endpoint of the HTTP API
+type RolesResponse struct {
+	AppID         string                     `json:"app_id"`
+	Collaborators []application.Collaborator `json:"collaborators"`
+}
+
+// RoleRequest is accepted by the role endpoint of the HTTP API
+type RoleRequest struct {
+	Role application.Role `json:"role"`
+}
+
+// MQTTAuthRequest is accepted by the MQTT endpoints of the HTTP API, which an
+// MQTT broker calls to authenticate collaborators with their token and to
+// check their access to topics
+type MQTTAuthRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	Topic    string `json:"topic,omitempty"`
+	Access   int    `json:"acc,omitempty"`
+}
+
+// MQTTClaimsCacheSize is the number of MQTT users whose claims are kept after
+// they authenticated, to check their access to topics
+var MQTTClaimsCacheSize = 10000
+
+// collaboratorRole returns the role of the user in the application. If the
+// application has collaborator roles, collaborators without a role are viewers.
+func (h *handler) collaboratorRole(appID, username string) (application.Role, bool) {
+	if h.applications == nil || username == "" {
+		return "", false
+	}
+	app, err := h.applications.Get(appID)
+	if err != nil {
+		return "", false
+	}
+	if role, ok := app.CollaboratorRole(username); ok {
+		return role, true
+	}
+	if len(app.Collaborators) > 0 {
+		return application.RoleViewer, true
+	}
+	return "", false
+}
+
+// setMQTTClaims keeps the claims of the token that the MQTT user authenticated with
+func (h *handler) setMQTTClaims(username string, claims *claims.Claims) {
+	h.mqttClaimsLock.Lock()
+	defer h.mqttClaimsLock.Unlock()
+	if h.mqttClaims == nil {
+		h.mqttClaims = gcache.New(MQTTClaimsCacheSize).LRU().Build()
+	}
+	h.mqttClaims.Set(username, claims)
+}
+
+// getMQTTClaims returns the claims of the token that the MQTT user
+// authenticated with, or an error if the user did not authenticate or the
+// token expired
+func (h *handler) getMQTTClaims(username string) (*claims.Claims, error) {
+	h.mqttClaimsLock.Lock()
+	defer h.mqttClaimsLock.Unlock()
+	if h.mqttClaims == nil {
+		return nil, errors.NewErrPermissionDenied(fmt.Sprintf("User %s is not authenticated", username))
+	}
+	cached, err := h.mqttClaims.Get(username)
+	if err != nil {
+		return nil, errors.NewErrPermissionDenied(fmt.Sprintf("User %s is not authenticated", username))
+	}
+	claims := cached.(*claims.Claims)
+	if err := claims.Valid(); err != nil {
+		h.mqttClaims.Remove(username)
+		return nil, errors.NewErrPermissionDenied(fmt.Sprintf("Token of user %s is not valid: %s", username, err))
+	}
+	return claims, nil
+}
+
+func (h *httpHandler) getRoles(req *http.Request, appID string) (*RolesResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	collaborators := app.Collaborators
+	if collaborators == nil {
+		collaborators = []application.Collaborator{}
+	}
+	return &RolesResponse{AppID: app.AppID, Collaborators: collaborators}, nil
+}
+
+// setRole sets the role of a collaborator of the application. As the other
+// collaborators become viewers when the first role is set, the collaborator
+// that sets it becomes admin.
+func (h *httpHandler) setRole(req *http.Request, appID, username string) (*RolesResponse, error) {
+	_, claims, err := h.authorizeClaims(req, appID, rights.AppCollaborators)
+	if err != nil {
+		return nil, err
+	}
+	var in RoleRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if !in.Role.Valid() {
+		return nil, errors.NewErrInvalidArgument("Role", fmt.Sprintf("must be %s, %s or %s", application.RoleViewer, application.RoleDeveloper, application.RoleAdmin))
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	collaborators := make([]application.Collaborator, 0, len(app.Collaborators)+1)
+	for _, collaborator := range app.Collaborators {
+		if collaborator.Username != username {
+			collaborators = append(collaborators, collaborator)
+		}
+	}
+	if len(app.Collaborators) == 0 && claims.Subject != username {
+		collaborators = append(collaborators, application.Collaborator{Username: claims.Subject, Role: application.RoleAdmin})
+	}
+	app.Collaborators = append(collaborators, application.Collaborator{Username: username, Role: in.Role})
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &RolesResponse{AppID: app.AppID, Collaborators: app.Collaborators}, nil
+}
+
+// deleteRole removes the role of a collaborator of the application, so that
+// the collaborator has the rights of its token again
+func (h *httpHandler) deleteRole(req *http.Request, appID, username string) (*RolesResponse, error) {
+	if _, err := h.authorize(req, appID, rights.AppCollaborators); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := app.CollaboratorRole(username); !ok {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("Role of %s", username))
+	}
+	app.StartUpdate()
+	collaborators := make([]application.Collaborator, 0, len(app.Collaborators))
+	for _, collaborator := range app.Collaborators {
+		if collaborator.Username != username {
+			collaborators = append(collaborators, collaborator)
+		}
+	}
+	app.Collaborators = collaborators
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	return &RolesResponse{AppID: app.AppID, Collaborators: app.Collaborators}, nil
+}
+
+// mqttAuth authenticates a collaborator that connects to the MQTT broker with
+// its username and a token as password
+func (h *httpHandler) mqttAuth(req *http.Request) error {
+	var in MQTTAuthRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if in.Username == "" || in.Password == "" {
+		return errors.NewErrPermissionDenied("Username and password are required")
+	}
+	ctx := metadata.NewContext(context.Background(), metadata.Pairs("token", in.Password))
+	claims, err := h.manager.handler.Component.ValidateTTNAuthContext(ctx)
+	if err != nil {
+		return errors.NewErrPermissionDenied(err.Error())
+	}
+	if claims.Subject != in.Username {
+		return errors.NewErrPermissionDenied("Token does not belong to user")
+	}
+	h.manager.handler.setMQTTClaims(in.Username, claims)
+	return nil
+}
+
+// mqttACL checks whether the rights of the token that a collaborator
+// authenticated with and its role allow access to an MQTT topic: all
+// collaborators with rights to the devices can subscribe to the topics of the
+// application, and only those that can write to devices can publish downlink
+// messages
+func (h *httpHandler) mqttACL(req *http.Request) error {
+	var in MQTTAuthRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	appID := strings.SplitN(in.Topic, "/", 2)[0]
+	if !api.ValidID(appID) {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("No access to topic %s", in.Topic))
+	}
+	claims, err := h.manager.handler.getMQTTClaims(in.Username)
+	if err != nil {
+		return err
+	}
+	write := in.Access&MQTTAccessWrite != 0
+	if write {
+		topic, err := mqtt.ParseDeviceTopic(in.Topic)
+		if err != nil || topic.Type != mqtt.DeviceDownlink || topic.DevID == "" {
+			return errors.NewErrPermissionDenied(fmt.Sprintf("Can not publish to topic %s", in.Topic))
+		}
+	}
+	return h.manager.checkAppRights(claims, appID, rights.Devices, write)
+}
@@ -0,0 +1,102 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/claims"
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	jwt "github.com/dgrijalva/jwt-go"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestCollaboratorRole(t *testing.T) {
+	a := New(t)
+	h := &handler{applications: application.NewMemoryApplicationStore()}
+	h.applications.Set(&application.Application{
+		AppID:         "app",
+		Collaborators: []application.Collaborator{{Username: "dev-user", Role: application.RoleDeveloper}},
+	})
+
+	role, ok := h.collaboratorRole("app", "dev-user")
+	a.So(ok, ShouldBeTrue)
+	a.So(role, ShouldEqual, application.RoleDeveloper)
+
+	// Collaborators without a role are viewers
+	role, ok = h.collaboratorRole("app", "other")
+	a.So(ok, ShouldBeTrue)
+	a.So(role, ShouldEqual, application.RoleViewer)
+
+	// Applications without roles use the rights of the token
+	h.applications.Set(&application.Application{AppID: "no-roles"})
+	_, ok = h.collaboratorRole("no-roles", "dev-user")
+	a.So(ok, ShouldBeFalse)
+	_, ok = h.collaboratorRole("unknown", "dev-user")
+	a.So(ok, ShouldBeFalse)
+	_, ok = (&handler{}).collaboratorRole("app", "dev-user")
+	a.So(ok, ShouldBeFalse)
+}
+
+func TestMQTTACL(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestMQTTACL")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+	h.applications.Set(&application.Application{
+		AppID: "app",
+		Collaborators: []application.Collaborator{
+			{Username: "viewer", Role: application.RoleViewer},
+			{Username: "developer", Role: application.RoleDeveloper},
+		},
+	})
+	handler := h.HTTPHandler(http.NotFoundHandler())
+
+	expires := time.Now().Add(time.Hour).Unix()
+	for _, username := range []string{"viewer", "developer", "other"} {
+		h.setMQTTClaims(username, &claims.Claims{
+			StandardClaims: jwt.StandardClaims{Subject: username, ExpiresAt: expires},
+			Apps:           map[string][]rights.Right{"app": {rights.Devices}},
+		})
+	}
+	h.setMQTTClaims("no-rights", &claims.Claims{
+		StandardClaims: jwt.StandardClaims{Subject: "no-rights", ExpiresAt: expires},
+		Apps:           map[string][]rights.Right{"app": {rights.AppSettings}},
+	})
+	h.setMQTTClaims("expired", &claims.Claims{
+		StandardClaims: jwt.StandardClaims{Subject: "expired", ExpiresAt: time.Now().Add(-time.Hour).Unix()},
+		Apps:           map[string][]rights.Right{"app": {rights.Devices}},
+	})
+
+	acl := func(body string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("POST", "/mqtt/acl", strings.NewReader(body)))
+		return rec.Code
+	}
+
+	a.So(acl(`{"username":"viewer","topic":"app/devices/+/up","acc":4}`), ShouldEqual, http.StatusOK)
+	a.So(acl(`{"username":"viewer","topic":"app/devices/dev/down","acc":2}`), ShouldEqual, http.StatusForbidden)
+	a.So(acl(`{"username":"developer","topic":"app/devices/dev/down","acc":2}`), ShouldEqual, http.StatusOK)
+	a.So(acl(`{"username":"developer","topic":"app/devices/dev/up","acc":2}`), ShouldEqual, http.StatusForbidden)
+	a.So(acl(`{"username":"developer","topic":"+/devices/+/up","acc":1}`), ShouldEqual, http.StatusForbidden)
+
+	// Collaborators without a role are viewers
+	a.So(acl(`{"username":"other","topic":"app/devices/+/up","acc":1}`), ShouldEqual, http.StatusOK)
+	a.So(acl(`{"username":"other","topic":"app/devices/dev/down","acc":2}`), ShouldEqual, http.StatusForbidden)
+
+	// The rights of the token are checked
+	a.So(acl(`{"username":"no-rights","topic":"app/devices/+/up","acc":1}`), ShouldEqual, http.StatusForbidden)
+	a.So(acl(`{"username":"expired","topic":"app/devices/+/up","acc":1}`), ShouldEqual, http.StatusForbidden)
+	a.So(acl(`{"username":"unknown","topic":"app/devices/+/up","acc":1}`), ShouldEqual, http.StatusForbidden)
+}
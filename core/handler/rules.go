@@ -0,0 +1,348 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/bluele/gcache"
+)
+
+// MaxRules is the maximum number of rules of an application
+const MaxRules = 20
+
+// DefaultRuleInterval is the minimum time between two triggers of a rule for
+// the same device if the rule does not set an interval
+var DefaultRuleInterval = time.Minute
+
+// RuleWebhookTimeout is the timeout for calling the webhook of a rule
+var RuleWebhookTimeout = 5 * time.Second
+
+// RuleTriggersCacheSize is the number of devices and rules of which the last
+// trigger is kept. If a trigger is evicted, the rule can trigger again before
+// its interval has passed.
+var RuleTriggersCacheSize = 100000
+
+// RulesRequest is returned and accepted by the rules endpoint of the HTTP API
+type RulesRequest struct {
+	Rules []application.Rule `json:"rules"`
+}
+
+// RuleWebhookMessage is posted to the webhook of a rule
+type RuleWebhookMessage struct {
+	AppID  string               `json:"app_id"`
+	DevID  string               `json:"dev_id"`
+	RuleID string               `json:"rule_id"`
+	Alert  string               `json:"alert,omitempty"`
+	Uplink *types.UplinkMessage `json:"uplink"`
+}
+
+var ruleOperators = map[string]bool{"eq": true, "ne": true, "gt": true, "gte": true, "lt": true, "lte": true}
+
+func ruleInterval(rule application.Rule) (time.Duration, error) {
+	if rule.Interval == "" {
+		return DefaultRuleInterval, nil
+	}
+	interval, err := time.ParseDuration(rule.Interval)
+	if err != nil {
+		return 0, err
+	}
+	if interval < 0 {
+		return 0, fmt.Errorf("can not be negative")
+	}
+	return interval, nil
+}
+
+func validateRules(rules []application.Rule) error {
+	if len(rules) > MaxRules {
+		return errors.NewErrInvalidArgument("Rules", fmt.Sprintf("can not have more than %d rules", MaxRules))
+	}
+	ids := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if !api.ValidID(rule.ID) {
+			return errors.NewErrInvalidArgument("Rule ID", fmt.Sprintf("%s has an invalid format", rule.ID))
+		}
+		if ids[rule.ID] {
+			return errors.NewErrInvalidArgument("Rule ID", fmt.Sprintf("%s is not unique", rule.ID))
+		}
+		ids[rule.ID] = true
+		if len(rule.Conditions) == 0 {
+			return errors.NewErrInvalidArgument(fmt.Sprintf("Rule %s", rule.ID), "does not have conditions")
+		}
+		for _, condition := range rule.Conditions {
+			if condition.Field == "" {
+				return errors.NewErrInvalidArgument(fmt.Sprintf("Rule %s", rule.ID), "condition does not have a field")
+			}
+			if !ruleOperators[condition.Operator] {
+				return errors.NewErrInvalidArgument(fmt.Sprintf("Rule %s", rule.ID), fmt.Sprintf("unknown operator %s", condition.Operator))
+			}
+			if _, isNumber := ruleNumber(condition.Value); !isNumber && condition.Operator != "eq" && condition.Operator != "ne" {
+				return errors.NewErrInvalidArgument(fmt.Sprintf("Rule %s", rule.ID), fmt.Sprintf("operator %s requires a number", condition.Operator))
+			}
+		}
+		if _, err := ruleInterval(rule); err != nil {
+			return errors.NewErrInvalidArgument(fmt.Sprintf("Rule %s interval", rule.ID), err.Error())
+		}
+		if rule.Downlink == nil && rule.Webhook == "" && rule.Alert == "" {
+			return errors.NewErrInvalidArgument(fmt.Sprintf("Rule %s", rule.ID), "does not have actions")
+		}
+		if rule.Webhook != "" {
+			webhook, err := url.Parse(rule.Webhook)
+			if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Host == "" {
+				return errors.NewErrInvalidArgument(fmt.Sprintf("Rule %s webhook", rule.ID), "must be an HTTP or HTTPS URL")
+			}
+		}
+		if rule.Downlink != nil {
+			if rule.Downlink.FPort == 0 || (rule.Downlink.PayloadRaw == nil && rule.Downlink.PayloadFields == nil) {
+				return errors.NewErrInvalidArgument(fmt.Sprintf("Rule %s downlink", rule.ID), "must have a port and a payload")
+			}
+			if err := validateDownlinkHints(rule.Downlink); err != nil {
+				return err
+			}
+			if err := validateDownlinkPriority(rule.Downlink); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ruleNumber returns the value as float64 if it is a number
+func ruleNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// ruleField returns the value of a field, with dots for nested fields
+func ruleField(fields map[string]interface{}, name string) (value interface{}, ok bool) {
+	value = fields
+	for _, part := range strings.Split(name, ".") {
+		nested, isMap := value.(map[string]interface{})
+		if !isMap {
+			return nil, false
+		}
+		if value, ok = nested[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func matchCondition(condition application.RuleCondition, fields map[string]interface{}) bool {
+	value, ok := ruleField(fields, condition.Field)
+	if !ok {
+		return false
+	}
+	number, isNumber := ruleNumber(value)
+	expected, expectedNumber := ruleNumber(condition.Value)
+	if isNumber && expectedNumber {
+		switch condition.Operator {
+		case "eq":
+			return number == expected
+		case "ne":
+			return number != expected
+		case "gt":
+			return number > expected
+		case "gte":
+			return number >= expected
+		case "lt":
+			return number < expected
+		case "lte":
+			return number <= expected
+		}
+		return false
+	}
+	switch condition.Operator {
+	case "eq":
+		return reflect.DeepEqual(value, condition.Value)
+	case "ne":
+		return !reflect.DeepEqual(value, condition.Value)
+	}
+	return false
+}
+
+func matchRule(rule application.Rule, fields map[string]interface{}) bool {
+	for _, condition := range rule.Conditions {
+		if !matchCondition(condition, fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// triggerRule returns true if the rule was not triggered for the device
+// within the interval of the rule, and records the trigger
+func (h *handler) triggerRule(appID, devID string, rule application.Rule, now time.Time) bool {
+	interval, err := ruleInterval(rule)
+	if err != nil {
+		return false
+	}
+	key := fmt.Sprintf("%s/%s/%s", appID, rule.ID, devID)
+	h.ruleTriggersLock.Lock()
+	defer h.ruleTriggersLock.Unlock()
+	if h.ruleTriggers == nil {
+		h.ruleTriggers = gcache.New(RuleTriggersCacheSize).LRU().Build()
+	}
+	if last, err := h.ruleTriggers.Get(key); err == nil && now.Sub(last.(time.Time)) < interval {
+		return false
+	}
+	h.ruleTriggers.Set(key, now)
+	return true
+}
+
+// evaluateRules evaluates the rules of the application on the decoded fields
+// of the uplink message and runs the actions of the rules that match
+func (h *handler) evaluateRules(up *types.UplinkMessage) {
+	if up.IsRetry || len(up.PayloadFields) == 0 {
+		return
+	}
+	app, err := h.applications.Get(up.AppID)
+	if err != nil || len(app.Rules) == 0 {
+		return
+	}
+	now := time.Now()
+	for _, rule := range app.Rules {
+		if !matchRule(rule, up.PayloadFields) || !h.triggerRule(up.AppID, up.DevID, rule, now) {
+			continue
+		}
+		ctx := h.Ctx.WithFields(ttnlog.Fields{"AppID": up.AppID, "DevID": up.DevID, "Rule": rule.ID})
+		ctx.Debug("Triggered rule")
+		if rule.Alert != "" {
+			h.mqttEvent <- &types.DeviceEvent{
+				AppID: up.AppID,
+				DevID: up.DevID,
+				Event: types.RuleAlertEvent,
+				Data: types.RuleAlertEventData{
+					RuleID: rule.ID,
+					Alert:  rule.Alert,
+					Fields: up.PayloadFields,
+				},
+			}
+		}
+		if rule.Downlink != nil {
+			downlink := *rule.Downlink
+			downlink.AppID, downlink.DevID = up.AppID, up.DevID
+			if downlink.Schedule == "" {
+				downlink.Schedule = types.ScheduleLast
+			}
+			if err := h.EnqueueDownlink(&downlink); err != nil {
+				ctx.WithError(err).Warn("Could not enqueue downlink of rule")
+			}
+		}
+		if rule.Webhook != "" {
+			go h.callRuleWebhook(ctx, rule, up)
+		}
+	}
+}
+
+func (h *handler) callRuleWebhook(ctx ttnlog.Interface, rule application.Rule, up *types.UplinkMessage) {
+	body, err := json.Marshal(RuleWebhookMessage{
+		AppID:  up.AppID,
+		DevID:  up.DevID,
+		RuleID: rule.ID,
+		Alert:  rule.Alert,
+		Uplink: up,
+	})
+	if err != nil {
+		ctx.WithError(err).Warn("Could not marshal webhook message of rule")
+		return
+	}
+	res, err := h.integrationClient(RuleWebhookTimeout).Post(rule.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		ctx.WithError(err).Warn("Could not call webhook of rule")
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		ctx.WithField("Status", res.Status).Warn("Webhook of rule returned an error")
+	}
+}
+
+func (h *httpHandler) getRules(req *http.Request, appID string) (*RulesRequest, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	rules := app.Rules
+	if rules == nil {
+		rules = []application.Rule{}
+	}
+	return &RulesRequest{Rules: rules}, nil
+}
+
+// setRules replaces the rules of the application
+func (h *httpHandler) setRules(req *http.Request, appID string) (*RulesRequest, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in RulesRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if err := validateRules(in.Rules); err != nil {
+		return nil, err
+	}
+	for _, rule := range in.Rules {
+		if rule.Webhook == "" {
+			continue
+		}
+		if err := h.manager.handler.integrationHosts.checkURL(rule.Webhook); err != nil {
+			return nil, err
+		}
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.Rules = in.Rules
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	if in.Rules == nil {
+		in.Rules = []application.Rule{}
+	}
+	return &in, nil
+}
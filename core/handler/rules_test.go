@@ -0,0 +1,146 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateRules(t *testing.T) {
+	a := New(t)
+
+	valid := application.Rule{
+		ID:         "too-hot",
+		Conditions: []application.RuleCondition{{Field: "temperature", Operator: "gt", Value: 30.0}},
+		Alert:      "Too hot",
+	}
+	a.So(validateRules([]application.Rule{valid}), ShouldBeNil)
+	a.So(validateRules([]application.Rule{valid, valid}), ShouldNotBeNil)
+
+	for _, modify := range []func(*application.Rule){
+		func(r *application.Rule) { r.ID = "Invalid" },
+		func(r *application.Rule) { r.Conditions = nil },
+		func(r *application.Rule) { r.Conditions = []application.RuleCondition{{Operator: "eq", Value: 1.0}} },
+		func(r *application.Rule) {
+			r.Conditions = []application.RuleCondition{{Field: "t", Operator: "like", Value: 1.0}}
+		},
+		func(r *application.Rule) {
+			r.Conditions = []application.RuleCondition{{Field: "t", Operator: "gt", Value: "hot"}}
+		},
+		func(r *application.Rule) { r.Interval = "soon" },
+		func(r *application.Rule) { r.Interval = "-1m" },
+		func(r *application.Rule) { r.Alert = "" },
+		func(r *application.Rule) { r.Webhook = "ftp://example.com" },
+		func(r *application.Rule) { r.Downlink = &types.DownlinkMessage{FPort: 1} },
+	} {
+		rule := valid
+		modify(&rule)
+		a.So(validateRules([]application.Rule{rule}), ShouldNotBeNil)
+	}
+}
+
+func TestMatchCondition(t *testing.T) {
+	a := New(t)
+
+	fields := map[string]interface{}{
+		"temperature": int64(25),
+		"state":       "open",
+		"gps":         map[string]interface{}{"zone": "A"},
+	}
+	a.So(matchCondition(application.RuleCondition{Field: "temperature", Operator: "gt", Value: 20.0}, fields), ShouldBeTrue)
+	a.So(matchCondition(application.RuleCondition{Field: "temperature", Operator: "lte", Value: 20.0}, fields), ShouldBeFalse)
+	a.So(matchCondition(application.RuleCondition{Field: "temperature", Operator: "eq", Value: 25.0}, fields), ShouldBeTrue)
+	a.So(matchCondition(application.RuleCondition{Field: "state", Operator: "eq", Value: "open"}, fields), ShouldBeTrue)
+	a.So(matchCondition(application.RuleCondition{Field: "state", Operator: "ne", Value: "open"}, fields), ShouldBeFalse)
+	a.So(matchCondition(application.RuleCondition{Field: "state", Operator: "gt", Value: 1.0}, fields), ShouldBeFalse)
+	a.So(matchCondition(application.RuleCondition{Field: "gps.zone", Operator: "eq", Value: "A"}, fields), ShouldBeTrue)
+	a.So(matchCondition(application.RuleCondition{Field: "gps.zone.name", Operator: "eq", Value: "A"}, fields), ShouldBeFalse)
+	a.So(matchCondition(application.RuleCondition{Field: "humidity", Operator: "ne", Value: 1.0}, fields), ShouldBeFalse)
+}
+
+func TestTriggerRule(t *testing.T) {
+	a := New(t)
+	h := &handler{}
+
+	rule := application.Rule{ID: "rule", Interval: "10m"}
+	now := time.Now()
+	a.So(h.triggerRule("app", "dev", rule, now), ShouldBeTrue)
+	a.So(h.triggerRule("app", "dev", rule, now.Add(time.Minute)), ShouldBeFalse)
+	a.So(h.triggerRule("app", "other", rule, now.Add(time.Minute)), ShouldBeTrue)
+	a.So(h.triggerRule("app", "dev", rule, now.Add(10*time.Minute)), ShouldBeTrue)
+
+	rule.Interval = "0s"
+	a.So(h.triggerRule("app", "dev", rule, now.Add(10*time.Minute)), ShouldBeTrue)
+}
+
+func TestEvaluateRules(t *testing.T) {
+	a := New(t)
+
+	webhook := make(chan RuleWebhookMessage, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg RuleWebhookMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		webhook <- msg
+	}))
+	defer server.Close()
+
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestEvaluateRules")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+		mqttEvent:    make(chan *types.DeviceEvent, 10),
+	}
+	h.WithIntegrationHosts([]string{"127.0.0.1"})
+	h.devices.Set(&device.Device{AppID: "app", DevID: "dev"})
+	h.applications.Set(&application.Application{
+		AppID: "app",
+		Rules: []application.Rule{
+			{
+				ID:         "too-hot",
+				Conditions: []application.RuleCondition{{Field: "temperature", Operator: "gt", Value: 30.0}},
+				Alert:      "Too hot",
+				Downlink:   &types.DownlinkMessage{FPort: 1, PayloadRaw: []byte{0x01}},
+				Webhook:    server.URL,
+			},
+		},
+	})
+
+	h.evaluateRules(&types.UplinkMessage{AppID: "app", DevID: "dev", PayloadFields: map[string]interface{}{"temperature": 20.0}})
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	h.evaluateRules(&types.UplinkMessage{AppID: "app", DevID: "dev", PayloadFields: map[string]interface{}{"temperature": 35.0}})
+	a.So(h.mqttEvent, ShouldHaveLength, 2)
+	alert := <-h.mqttEvent
+	a.So(alert.Event, ShouldEqual, types.RuleAlertEvent)
+	a.So(alert.Data.(types.RuleAlertEventData).RuleID, ShouldEqual, "too-hot")
+	a.So((<-h.mqttEvent).Event, ShouldEqual, types.DownlinkScheduledEvent)
+
+	queue, _ := h.devices.DownlinkQueue("app", "dev")
+	next, _ := queue.Next()
+	a.So(next, ShouldNotBeNil)
+	a.So(next.PayloadRaw, ShouldResemble, []byte{0x01})
+
+	select {
+	case msg := <-webhook:
+		a.So(msg.RuleID, ShouldEqual, "too-hot")
+		a.So(msg.Uplink.PayloadFields["temperature"], ShouldEqual, 35.0)
+	case <-time.After(time.Second):
+		t.Fatal("Webhook was not called")
+	}
+
+	// The rule is not triggered again within its interval
+	h.evaluateRules(&types.UplinkMessage{AppID: "app", DevID: "dev", PayloadFields: map[string]interface{}{"temperature": 36.0}})
+	a.So(h.mqttEvent, ShouldBeEmpty)
+}
@@ -0,0 +1,72 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// sealedSecretPrefix is the prefix of secrets that are encrypted with the secrets key
+const sealedSecretPrefix = "aes-gcm:"
+
+// WithSecretsKey makes the Handler encrypt the credentials of the integrations
+// of applications with the AES key before storing them
+func (h *handler) WithSecretsKey(key []byte) Handler {
+	h.secretsKey = key
+	return h
+}
+
+func (h *handler) secretsCipher() (cipher.AEAD, error) {
+	if len(h.secretsKey) == 0 {
+		return nil, errors.NewErrInternal("The Handler does not have a secrets key to store credentials")
+	}
+	block, err := aes.NewCipher(h.secretsKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealSecret encrypts the secret for storage
+func (h *handler) sealSecret(secret string) (string, error) {
+	if secret == "" {
+		return "", nil
+	}
+	aead, err := h.secretsCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return sealedSecretPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+// openSecret decrypts a secret that was sealed by sealSecret. Secrets that
+// were stored before they were encrypted are returned as they are.
+func (h *handler) openSecret(stored string) (string, error) {
+	if !strings.HasPrefix(stored, sealedSecretPrefix) {
+		return stored, nil
+	}
+	aead, err := h.secretsCipher()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, sealedSecretPrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.NewErrInvalidArgument("Secret", "is not sealed with the secrets key")
+	}
+	secret, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.NewErrInvalidArgument("Secret", "is not sealed with the secrets key")
+	}
+	return string(secret), nil
+}
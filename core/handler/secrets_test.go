@@ -0,0 +1,36 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestSecrets(t *testing.T) {
+	a := New(t)
+
+	h := &handler{}
+	_, err := h.sealSecret("secret")
+	a.So(err, ShouldNotBeNil)
+	secret, err := h.openSecret("plaintext")
+	a.So(err, ShouldBeNil)
+	a.So(secret, ShouldEqual, "plaintext")
+
+	h.WithSecretsKey([]byte("0123456789abcdef"))
+	sealed, err := h.sealSecret("secret")
+	a.So(err, ShouldBeNil)
+	a.So(strings.HasPrefix(sealed, sealedSecretPrefix), ShouldBeTrue)
+	a.So(sealed, ShouldNotContainSubstring, "secret")
+	secret, err = h.openSecret(sealed)
+	a.So(err, ShouldBeNil)
+	a.So(secret, ShouldEqual, "secret")
+
+	other := &handler{}
+	other.WithSecretsKey([]byte("fedcba9876543210"))
+	_, err = other.openSecret(sealed)
+	a.So(err, ShouldNotBeNil)
+}
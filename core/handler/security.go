@@ -0,0 +1,38 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// publishReplayAttack publishes a security event if the Broker detected that the
+// uplink message is a replay attack, and returns true if it is. The payload of
+// a replay attack is not processed.
+func (h *handler) publishReplayAttack(uplink *pb_broker.DeduplicatedUplinkMessage) bool {
+	if uplink.ReplayAttack == nil {
+		return false
+	}
+	data := types.ReplayAttackEventData{
+		Distance: strconv.FormatUint(uint64(uplink.ReplayAttack.Distance), 10),
+		Gateways: uplink.ReplayAttack.Gateways,
+	}
+	if quarantine := uplink.Quarantine; quarantine != nil {
+		data.QuarantineReason = quarantine.Reason
+		if quarantine.Since != 0 {
+			data.QuarantinedSince = time.Unix(0, quarantine.Since).UTC().Format(time.RFC3339)
+		}
+	}
+	h.mqttEvent <- &types.DeviceEvent{
+		AppID: uplink.AppId,
+		DevID: uplink.DevId,
+		Event: types.ReplayAttackEvent,
+		Data:  data,
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPublishReplayAttack(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		mqttEvent: make(chan *types.DeviceEvent, 10),
+	}
+
+	uplink := &pb_broker.DeduplicatedUplinkMessage{AppId: "app", DevId: "dev"}
+	a.So(h.publishReplayAttack(uplink), ShouldBeFalse)
+
+	a.So(h.mqttEvent, ShouldBeEmpty)
+
+	uplink.ReplayAttack = &pb_broker.ReplayAttack{Distance: 412650, Gateways: []string{"gtw-1", "gtw-2"}}
+	uplink.Quarantine = &pb_broker.Quarantine{Reason: "frame replayed", Since: time.Date(2017, 3, 20, 10, 12, 33, 0, time.UTC).UnixNano()}
+	a.So(h.publishReplayAttack(uplink), ShouldBeTrue)
+	a.So(h.mqttEvent, ShouldHaveLength, 1)
+
+	event := <-h.mqttEvent
+	a.So(event.AppID, ShouldEqual, "app")
+	a.So(event.DevID, ShouldEqual, "dev")
+	a.So(event.Event, ShouldEqual, types.ReplayAttackEvent)
+	a.So(event.Data, ShouldResemble, types.ReplayAttackEventData{
+		Distance:         "412650",
+		Gateways:         []string{"gtw-1", "gtw-2"},
+		QuarantineReason: "frame replayed",
+		QuarantinedSince: "2017-03-20T10:12:33Z",
+	})
+}
@@ -15,6 +15,10 @@ type handlerRPC struct {
 	handler Handler
 }
 
+type sessionKeyWrapper interface {
+	wrapSessionKeys(ctx context.Context, brokerID string, res *pb.DeviceActivationResponse) error
+}
+
 func (h *handlerRPC) ActivationChallenge(ctx context.Context, challenge *pb_broker.ActivationChallengeRequest) (*pb_broker.ActivationChallengeResponse, error) {
 	_, err := h.handler.ValidateNetworkContext(ctx)
 	if err != nil {
@@ -31,7 +35,7 @@ func (h *handlerRPC) ActivationChallenge(ctx context.Context, challenge *pb_brok
 }
 
 func (h *handlerRPC) Activate(ctx context.Context, activation *pb_broker.DeduplicatedDeviceActivationRequest) (*pb.DeviceActivationResponse, error) {
-	_, err := h.handler.ValidateNetworkContext(ctx)
+	broker, err := h.handler.ValidateNetworkContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +46,11 @@ func (h *handlerRPC) Activate(ctx context.Context, activation *pb_broker.Dedupli
 	if err != nil {
 		return nil, err
 	}
+	if wrapper, ok := h.handler.(sessionKeyWrapper); ok {
+		if err := wrapper.wrapSessionKeys(ctx, broker.Id, res); err != nil {
+			return nil, err
+		}
+	}
 	return res, nil
 }
 
@@ -24,7 +24,7 @@ func (h *handlerManager) SimulateUplink(ctx context.Context, in *pb.SimulatedUpl
 	if err != nil {
 		return nil, err
 	}
-	err = checkAppRights(claims, in.AppId, rights.Devices)
+	err = h.checkAppRights(claims, in.AppId, rights.Devices, true)
 	if err != nil {
 		return nil, err
 	}
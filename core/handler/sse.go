@@ -0,0 +1,136 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// SSEKeepAlive is the interval of the comments that keep Server-Sent Events
+// connections open through proxies that close idle connections
+var SSEKeepAlive = 15 * time.Second
+
+// sse streams the uplink messages and events of the application, or of the
+// devices in devIDs, as Server-Sent Events. Because the EventSource of browsers
+// can not set headers, the access key or token can also be given in the key or
+// token query parameters.
+func (h *httpHandler) sse(res http.ResponseWriter, req *http.Request, appID string, devIDs map[string]bool) {
+	queryCredentials(req)
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		h.write(res, nil, err)
+		return
+	}
+	if _, err := h.manager.handler.applications.Get(appID); err != nil {
+		h.write(res, nil, err)
+		return
+	}
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		http.Error(res, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := h.manager.handler.Subscribe(appID)
+	defer h.manager.handler.Unsubscribe(sub)
+
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no") // Do not buffer the stream in nginx
+	res.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := h.manager.handler.Ctx.WithField("AppID", appID)
+	ctx.Debug("Start live data Server-Sent Events")
+	h.manager.handler.streamSSE(res, flusher.Flush, req.Context().Done(), sub, devIDs)
+	ctx.Debug("End live data Server-Sent Events")
+}
+
+// writeSSE writes a Server-Sent Event. Events without a name are received by
+// the onmessage handler of an EventSource.
+func writeSSE(w io.Writer, id uint64, event string, data []byte) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "id: %d\n", id)
+	if event != "" {
+		fmt.Fprintf(&buf, "event: %s\n", event)
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+	buf.WriteString("\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// streamSSE writes the messages of the subscription as Server-Sent Events until
+// done is closed. Uplink messages are sent without event name, so that clients
+// receive them as plain messages, in the v3 schema if the application selected
+// that format. Other events have the name of their type. Only the messages of
+// the given devices are written, or all messages if devIDs is empty.
+func (h *handler) streamSSE(w io.Writer, flush func(), done <-chan struct{}, sub *Subscription, devIDs map[string]bool) {
+	keepAlive := time.NewTicker(SSEKeepAlive)
+	defer keepAlive.Stop()
+
+	var id uint64
+	send := func(devID, event string, data []byte, err error) bool {
+		if len(devIDs) > 0 && !devIDs[devID] {
+			return true
+		}
+		if err != nil {
+			h.Ctx.WithError(err).Warn("Could not marshal live data")
+			return true
+		}
+		id++
+		if err := writeSSE(w, id, event, data); err != nil {
+			return false
+		}
+		flush()
+		return true
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flush()
+		case up, ok := <-sub.Uplink:
+			if !ok {
+				return
+			}
+			var data []byte
+			var err error
+			if h.payloadFormat(up.AppID) == types.PayloadFormatV3 {
+				data, err = up.MarshalV3()
+			} else {
+				data, err = json.Marshal(up)
+			}
+			if !send(up.DevID, "", data, err) {
+				return
+			}
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			message, err := newLiveEvent(event.DevID, string(event.Event), event.Data)
+			var data []byte
+			if err == nil {
+				data, err = json.Marshal(message)
+			}
+			if !send(event.DevID, string(event.Event), data, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestWriteSSE(t *testing.T) {
+	a := New(t)
+	var buf bytes.Buffer
+	a.So(writeSSE(&buf, 1, "", []byte(`{"port":1}`)), ShouldBeNil)
+	a.So(writeSSE(&buf, 2, "activations", []byte("line1\nline2")), ShouldBeNil)
+	a.So(buf.String(), ShouldEqual, "id: 1\ndata: {\"port\":1}\n\nid: 2\nevent: activations\ndata: line1\ndata: line2\n\n")
+}
+
+func TestHandlerHTTPHandlerSSE(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestHandlerHTTPHandlerSSE")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+	handler := h.HTTPHandler(http.NotFoundHandler())
+
+	// Requests without token or key are rejected
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/applications/app/sse", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/applications/app/devices/dev/sse", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/applications/app/sse", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusNotFound)
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestStreamSSE(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestStreamSSE")},
+		applications: application.NewMemoryApplicationStore(),
+	}
+	h.applications.Set(&application.Application{AppID: "app", PayloadFormat: string(types.PayloadFormatV3)})
+
+	sub := h.Subscribe("app")
+	defer h.Unsubscribe(sub)
+
+	var out syncBuffer
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		h.streamSSE(&out, func() {}, done, sub, map[string]bool{"dev1": true})
+		close(stopped)
+	}()
+
+	h.subscriptions.publishUplink(&types.UplinkMessage{AppID: "app", DevID: "dev2"})
+	h.subscriptions.publishUplink(&types.UplinkMessage{AppID: "app", DevID: "dev1", FPort: 1})
+	h.subscriptions.publishEvent(&types.DeviceEvent{AppID: "app", DevID: "dev1", Event: types.ActivationEvent})
+
+	time.Sleep(50 * time.Millisecond)
+	close(done)
+	<-stopped
+
+	events := strings.Split(strings.TrimSpace(out.String()), "\n\n")
+	a.So(events, ShouldHaveLength, 2)
+	for _, event := range events {
+		switch {
+		case strings.Contains(event, "event: activations"):
+			a.So(event, ShouldContainSubstring, `"dev_id":"dev1"`)
+		default:
+			a.So(event, ShouldStartWith, "id: ")
+			a.So(event, ShouldContainSubstring, `"device_id":"dev1"`)
+			a.So(event, ShouldContainSubstring, `"f_port":1`)
+		}
+	}
+}
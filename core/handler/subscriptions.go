@@ -0,0 +1,111 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"sync"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// SubscriptionBufferSize indicates the size of the channel buffers of a Subscription
+var SubscriptionBufferSize = 10
+
+// Subscription receives the uplink messages and events of an application
+// inside the process of the Handler. Messages are dropped if the channels are
+// not read fast enough.
+type Subscription struct {
+	AppID  string
+	Uplink chan *types.UplinkMessage
+	Events chan *types.DeviceEvent
+}
+
+type subscriptions struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+func (s *subscriptions) add(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs == nil {
+		s.subs = make(map[*Subscription]struct{})
+	}
+	s.subs[sub] = struct{}{}
+}
+
+func (s *subscriptions) remove(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[sub]; !ok {
+		return
+	}
+	delete(s.subs, sub)
+	close(sub.Uplink)
+	close(sub.Events)
+}
+
+func (s *subscriptions) publishUplink(up *types.UplinkMessage) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for sub := range s.subs {
+		if sub.AppID != "" && sub.AppID != up.AppID {
+			continue
+		}
+		select {
+		case sub.Uplink <- up:
+		default:
+		}
+	}
+}
+
+func (s *subscriptions) publishEvent(event *types.DeviceEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for sub := range s.subs {
+		if sub.AppID != "" && sub.AppID != event.AppID {
+			continue
+		}
+		select {
+		case sub.Events <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe to the uplink messages and events of the given application. An
+// empty appID subscribes to all applications.
+func (h *handler) Subscribe(appID string) *Subscription {
+	sub := &Subscription{
+		AppID:  appID,
+		Uplink: make(chan *types.UplinkMessage, SubscriptionBufferSize),
+		Events: make(chan *types.DeviceEvent, SubscriptionBufferSize),
+	}
+	h.subscriptions.add(sub)
+	return sub
+}
+
+// Unsubscribe removes the subscription and closes its channels
+func (h *handler) Unsubscribe(sub *Subscription) {
+	h.subscriptions.remove(sub)
+}
+
+// handleSubscriptions forwards the uplink messages and events to the
+// subscriptions when they are not published over MQTT
+func (h *handler) handleSubscriptions() {
+	h.mqttUp = make(chan *types.UplinkMessage, MQTTBufferSize)
+	h.mqttEvent = make(chan *types.DeviceEvent, MQTTBufferSize)
+	go func() {
+		for up := range h.mqttUp {
+			h.persistUplink(up)
+			h.subscriptions.publishUplink(up)
+		}
+	}()
+	go func() {
+		for event := range h.mqttEvent {
+			h.persistEvent(event)
+			h.subscriptions.publishEvent(event)
+		}
+	}()
+}
@@ -0,0 +1,61 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestSubscriptions(t *testing.T) {
+	a := New(t)
+
+	h := &handler{}
+	h.handleSubscriptions()
+
+	all := h.Subscribe("")
+	app := h.Subscribe("app1")
+
+	h.mqttUp <- &types.UplinkMessage{AppID: "app1", DevID: "dev1"}
+	h.mqttUp <- &types.UplinkMessage{AppID: "app2", DevID: "dev2"}
+	h.mqttEvent <- &types.DeviceEvent{AppID: "app2", DevID: "dev2", Event: types.ActivationEvent}
+
+	select {
+	case up := <-app.Uplink:
+		a.So(up.DevID, ShouldEqual, "dev1")
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Did not receive uplink for app1")
+	}
+
+	for _, devID := range []string{"dev1", "dev2"} {
+		select {
+		case up := <-all.Uplink:
+			a.So(up.DevID, ShouldEqual, devID)
+		case <-time.After(50 * time.Millisecond):
+			t.Fatalf("Did not receive uplink for %s", devID)
+		}
+	}
+
+	select {
+	case event := <-all.Events:
+		a.So(event.Event, ShouldEqual, types.ActivationEvent)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Did not receive event")
+	}
+
+	select {
+	case <-app.Uplink:
+		t.Fatal("Received uplink for other application")
+	case <-app.Events:
+		t.Fatal("Received event for other application")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	h.Unsubscribe(app)
+	_, ok := <-app.Uplink
+	a.So(ok, ShouldBeFalse)
+}
@@ -0,0 +1,44 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/api/ratelimit"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func (h *handler) WithTenants(tenants types.Tenants) Handler {
+	h.tenants = tenants
+	h.tenantRate = make(map[string]*ratelimit.Registry)
+	for _, tenant := range tenants {
+		if tenant.Rate > 0 {
+			h.tenantRate[tenant.ID] = ratelimit.NewRegistry(tenant.Rate, time.Hour)
+		}
+	}
+	return h
+}
+
+// checkTenantRate returns an error if the tenant of the application reached
+// the limit of its API requests
+func (h *handler) checkTenantRate(appID string) error {
+	tenant := h.tenants.ForApplication(appID)
+	if tenant == nil {
+		return nil
+	}
+	if rate, ok := h.tenantRate[tenant.ID]; ok && rate.Limit(tenant.ID) {
+		return grpc.Errorf(codes.ResourceExhausted, "Rate limit for tenant %s reached", tenant.ID)
+	}
+	return nil
+}
+
+// sameTenant returns true if the applications belong to the same tenant, or
+// if the first application is shared
+func (h *handler) sameTenant(appID, otherAppID string) bool {
+	tenant := h.tenants.ForApplication(appID)
+	return tenant == nil || tenant == h.tenants.ForApplication(otherAppID)
+}
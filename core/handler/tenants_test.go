@@ -0,0 +1,38 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestTenants(t *testing.T) {
+	a := New(t)
+	h := &handler{}
+
+	// Without tenants there are no limits
+	a.So(h.checkTenantRate("acme-sensors"), ShouldBeNil)
+	a.So(h.sameTenant("acme-sensors", "globex-sensors"), ShouldBeTrue)
+
+	tenants, _ := types.ParseTenants(map[string]string{
+		"acme":   "applications=acme-*;rate=2",
+		"globex": "applications=globex-*",
+	})
+	h.WithTenants(tenants)
+
+	// The rate limit is shared by the applications of the tenant
+	a.So(h.checkTenantRate("acme-sensors"), ShouldBeNil)
+	a.So(h.checkTenantRate("acme-meters"), ShouldBeNil)
+	a.So(h.checkTenantRate("acme-sensors"), ShouldNotBeNil)
+	a.So(h.checkTenantRate("globex-sensors"), ShouldBeNil)
+
+	// Devices can only move from shared applications or within a tenant
+	a.So(h.sameTenant("shared", "acme-sensors"), ShouldBeTrue)
+	a.So(h.sameTenant("acme-sensors", "acme-meters"), ShouldBeTrue)
+	a.So(h.sameTenant("acme-sensors", "globex-sensors"), ShouldBeFalse)
+	a.So(h.sameTenant("acme-sensors", "shared"), ShouldBeFalse)
+}
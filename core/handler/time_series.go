@@ -0,0 +1,421 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// TimeSeriesTimeout is the timeout for writing to the time-series database of an application
+var TimeSeriesTimeout = 5 * time.Second
+
+// TimeSeriesWorkers is the number of writes to time-series databases that run
+// at the same time, and TimeSeriesQueueSize the number of writes that wait.
+// If the queue is full, the decoded fields of uplink messages are not written.
+var (
+	TimeSeriesWorkers   = 8
+	TimeSeriesQueueSize = 1000
+)
+
+// TimescaleDBMaxConnections is the maximum number of TimescaleDB databases
+// that the Handler is connected to. The connections of the database that was
+// not used the longest are closed if the maximum is reached.
+var TimescaleDBMaxConnections = 100
+
+// DefaultTimeSeriesMeasurement is the InfluxDB measurement or TimescaleDB
+// table if the application does not set one
+const DefaultTimeSeriesMeasurement = "uplink"
+
+var timeSeriesIdentifier = regexp.MustCompile("^[a-z_][a-z0-9_]{0,62}$")
+
+func validateTimeSeries(ts *application.TimeSeries) error {
+	switch ts.Type {
+	case application.TimeSeriesInfluxDB:
+		u, err := url.Parse(ts.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.NewErrInvalidArgument("Time series URL", "must be an HTTP or HTTPS URL")
+		}
+		if ts.Database == "" {
+			return errors.NewErrInvalidArgument("Time series", "InfluxDB needs a database")
+		}
+	case application.TimeSeriesTimescaleDB:
+		u, err := url.Parse(ts.URL)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+			return errors.NewErrInvalidArgument("Time series URL", "must be a postgres:// URL")
+		}
+	default:
+		return errors.NewErrInvalidArgument("Time series type", "must be influxdb or timescaledb")
+	}
+	if ts.Measurement != "" && !timeSeriesIdentifier.MatchString(ts.Measurement) {
+		return errors.NewErrInvalidArgument("Time series measurement", "must be lowercase letters, digits and underscores")
+	}
+	for _, attribute := range ts.Attributes {
+		if attribute == "dev_id" || attribute == "app_id" {
+			return errors.NewErrInvalidArgument("Time series attribute", fmt.Sprintf("%s is always written", attribute))
+		}
+	}
+	return nil
+}
+
+// timeSeriesFields returns the numeric decoded fields, with dots for nested fields
+func timeSeriesFields(fields map[string]interface{}) map[string]float64 {
+	numeric := make(map[string]float64)
+	var flatten func(prefix string, fields map[string]interface{})
+	flatten = func(prefix string, fields map[string]interface{}) {
+		for name, value := range fields {
+			if nested, ok := value.(map[string]interface{}); ok {
+				flatten(prefix+name+".", nested)
+				continue
+			}
+			if number, ok := ruleNumber(value); ok && !math.IsNaN(number) && !math.IsInf(number, 0) {
+				numeric[prefix+name] = number
+			}
+		}
+	}
+	flatten("", fields)
+	return numeric
+}
+
+// timeSeriesTags returns the device attributes that are written as tags
+func timeSeriesTags(ts *application.TimeSeries, dev *device.Device) map[string]string {
+	tags := make(map[string]string)
+	if dev == nil {
+		return tags
+	}
+	for _, attribute := range ts.Attributes {
+		if value, ok := dev.Attributes[attribute]; ok && value != "" {
+			tags[attribute] = value
+		}
+	}
+	return tags
+}
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	influxKeyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// influxLine returns the point in the InfluxDB line protocol
+func influxLine(measurement string, tags map[string]string, fields map[string]float64, t time.Time) string {
+	var line bytes.Buffer
+	line.WriteString(influxMeasurementEscaper.Replace(measurement))
+	for _, key := range sortedKeys(tags) {
+		fmt.Fprintf(&line, ",%s=%s", influxKeyEscaper.Replace(key), influxKeyEscaper.Replace(tags[key]))
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		separator := ","
+		if i == 0 {
+			separator = " "
+		}
+		fmt.Fprintf(&line, "%s%s=%s", separator, influxKeyEscaper.Replace(name), strconv.FormatFloat(fields[name], 'g', -1, 64))
+	}
+	fmt.Fprintf(&line, " %d", t.UnixNano())
+	return line.String()
+}
+
+func (h *handler) writeInfluxDB(ts *application.TimeSeries, measurement string, tags map[string]string, fields map[string]float64, t time.Time) error {
+	query := url.Values{"db": []string{ts.Database}, "precision": []string{"ns"}}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(ts.URL, "/")+"/write?"+query.Encode(), strings.NewReader(influxLine(measurement, tags, fields, t)))
+	if err != nil {
+		return err
+	}
+	if ts.Username != "" {
+		req.SetBasicAuth(ts.Username, ts.Password)
+	}
+	res, err := h.integrationClient(TimeSeriesTimeout).Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("InfluxDB returned %s", res.Status)
+	}
+	return nil
+}
+
+type timescaleDB struct {
+	*sql.DB
+	lastUsed time.Time
+}
+
+// timescaleURL returns the URL with the connect and statement timeouts
+func timescaleURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("connect_timeout", strconv.Itoa(int(TimeSeriesTimeout/time.Second)))
+	query.Set("statement_timeout", strconv.Itoa(int(TimeSeriesTimeout/time.Millisecond)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// timescaleDB returns the connection pool of the TimescaleDB at the URL. The
+// binary must register the postgres driver for database/sql.
+func (h *handler) timescaleDB(rawURL string) (*sql.DB, error) {
+	h.timeSeriesLock.Lock()
+	defer h.timeSeriesLock.Unlock()
+	if db, ok := h.timescaleDBs[rawURL]; ok {
+		db.lastUsed = time.Now()
+		return db.DB, nil
+	}
+	if err := h.integrationHosts.checkURL(rawURL); err != nil {
+		return nil, err
+	}
+	dataSource, err := timescaleURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("postgres", dataSource)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(4)
+	if h.timescaleDBs == nil {
+		h.timescaleDBs = make(map[string]*timescaleDB)
+	}
+	if len(h.timescaleDBs) >= TimescaleDBMaxConnections {
+		var oldestURL string
+		var oldest *timescaleDB
+		for url, db := range h.timescaleDBs {
+			if oldest == nil || db.lastUsed.Before(oldest.lastUsed) {
+				oldestURL, oldest = url, db
+			}
+		}
+		oldest.Close()
+		delete(h.timescaleDBs, oldestURL)
+	}
+	h.timescaleDBs[rawURL] = &timescaleDB{DB: db, lastUsed: time.Now()}
+	return db, nil
+}
+
+// closeTimescaleDBs closes the connections to TimescaleDB
+func (h *handler) closeTimescaleDBs() {
+	h.timeSeriesLock.Lock()
+	defer h.timeSeriesLock.Unlock()
+	for url, db := range h.timescaleDBs {
+		db.Close()
+		delete(h.timescaleDBs, url)
+	}
+}
+
+// timescaleInsert returns the statement and arguments that insert a row per field into the table
+func timescaleInsert(table, appID, devID string, tags map[string]string, fields map[string]float64, t time.Time) (string, []interface{}, error) {
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return "", nil, err
+	}
+	args := []interface{}{t.UTC(), appID, devID, string(tagsJSON)}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, name, fields[name])
+		values = append(values, fmt.Sprintf("($1, $2, $3, $4, $%d, $%d)", len(args)-1, len(args)))
+	}
+	statement := fmt.Sprintf("INSERT INTO %s (time, app_id, dev_id, tags, field, value) VALUES %s", table, strings.Join(values, ", "))
+	return statement, args, nil
+}
+
+func (h *handler) writeTimescaleDB(ts *application.TimeSeries, appID, devID string, tags map[string]string, fields map[string]float64, t time.Time) error {
+	db, err := h.timescaleDB(ts.URL)
+	if err != nil {
+		return err
+	}
+	measurement := ts.Measurement
+	if measurement == "" {
+		measurement = DefaultTimeSeriesMeasurement
+	}
+	statement, args, err := timescaleInsert(measurement, appID, devID, tags, fields, t)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(statement, args...)
+	return err
+}
+
+type timeSeriesWrite struct {
+	ts     application.TimeSeries
+	appID  string
+	devID  string
+	tags   map[string]string
+	fields map[string]float64
+	time   time.Time
+}
+
+func (h *handler) handleTimeSeriesWrites() {
+	h.timeSeriesQueue = make(chan *timeSeriesWrite, TimeSeriesQueueSize)
+	for i := 0; i < TimeSeriesWorkers; i++ {
+		go func() {
+			for write := range h.timeSeriesQueue {
+				var err error
+				switch write.ts.Type {
+				case application.TimeSeriesInfluxDB:
+					measurement := write.ts.Measurement
+					if measurement == "" {
+						measurement = DefaultTimeSeriesMeasurement
+					}
+					write.tags["app_id"], write.tags["dev_id"] = write.appID, write.devID
+					err = h.writeInfluxDB(&write.ts, measurement, write.tags, write.fields, write.time)
+				case application.TimeSeriesTimescaleDB:
+					err = h.writeTimescaleDB(&write.ts, write.appID, write.devID, write.tags, write.fields, write.time)
+				}
+				if err != nil {
+					h.Ctx.WithFields(ttnlog.Fields{"AppID": write.appID, "DevID": write.devID, "Type": write.ts.Type}).WithError(err).Warn("Could not write decoded fields to time series database")
+				}
+			}
+		}()
+	}
+}
+
+// writeTimeSeries queues the numeric decoded fields of the uplink message for
+// writing to the time-series database of the application, if it has one
+func (h *handler) writeTimeSeries(up *types.UplinkMessage, dev *device.Device) {
+	if up.IsRetry || len(up.PayloadFields) == 0 {
+		return
+	}
+	app, err := h.applications.Get(up.AppID)
+	if err != nil || app.TimeSeries == nil {
+		return
+	}
+	fields := timeSeriesFields(up.PayloadFields)
+	if len(fields) == 0 {
+		return
+	}
+	write := &timeSeriesWrite{
+		ts:     *app.TimeSeries,
+		appID:  up.AppID,
+		devID:  up.DevID,
+		fields: fields,
+		time:   time.Time(up.Metadata.Time),
+	}
+	write.tags = timeSeriesTags(&write.ts, dev)
+	if write.time.IsZero() {
+		write.time = time.Now()
+	}
+	h.timeSeriesOnce.Do(h.handleTimeSeriesWrites)
+	select {
+	case h.timeSeriesQueue <- write:
+	default:
+		h.Ctx.WithFields(ttnlog.Fields{"AppID": up.AppID, "DevID": up.DevID}).Warn("Time series queue is full, not writing decoded fields")
+	}
+}
+
+// redactTimeSeriesURL hides the password in the connection URL of TimescaleDB
+func redactTimeSeriesURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	if _, ok := u.User.Password(); !ok {
+		return rawURL
+	}
+	u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	return u.String()
+}
+
+func (h *httpHandler) getTimeSeries(req *http.Request, appID string) (*application.TimeSeries, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if app.TimeSeries == nil {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("Time series database of application %s", appID))
+	}
+	ts := *app.TimeSeries
+	ts.URL = redactTimeSeriesURL(ts.URL)
+	ts.Password = "" // The password is not returned
+	return &ts, nil
+}
+
+// setTimeSeries sets the time-series database of the application. If the
+// request has no password or the redacted URL, and the database is not
+// changed, the current password is kept.
+func (h *httpHandler) setTimeSeries(req *http.Request, appID string) (*application.TimeSeries, error) {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	var in application.TimeSeries
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return nil, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if old := app.TimeSeries; old != nil {
+		if in.URL == redactTimeSeriesURL(old.URL) {
+			in.URL = old.URL
+		}
+		if in.Password == "" && in.URL == old.URL && in.Username == old.Username {
+			in.Password = old.Password
+		}
+	}
+	if err := validateTimeSeries(&in); err != nil {
+		return nil, err
+	}
+	if err := h.manager.handler.integrationHosts.checkURL(in.URL); err != nil {
+		return nil, err
+	}
+	app.StartUpdate()
+	app.TimeSeries = &in
+	if err := h.manager.handler.applications.Set(app); err != nil {
+		return nil, err
+	}
+	in.URL = redactTimeSeriesURL(in.URL)
+	in.Password = ""
+	return &in, nil
+}
+
+func (h *httpHandler) deleteTimeSeries(req *http.Request, appID string) error {
+	if _, err := h.authorize(req, appID, rights.AppSettings); err != nil {
+		return err
+	}
+	app, err := h.manager.handler.applications.Get(appID)
+	if err != nil {
+		return err
+	}
+	app.StartUpdate()
+	app.TimeSeries = nil
+	return h.manager.handler.applications.Set(app)
+}
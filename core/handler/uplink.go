@@ -36,6 +36,18 @@ func (h *handler) HandleUplink(uplink *pb_broker.DeduplicatedUplinkMessage) (err
 
 	uplink.Trace = uplink.Trace.WithEvent(trace.ReceiveEvent)
 
+	// The Broker only forwards replay attacks to report them
+	if h.publishReplayAttack(uplink) {
+		ctx.Warn("Received replay attack")
+		return nil
+	}
+
+	// The Broker only forwards frame counter regressions to count them
+	if uplink.FcntRegression != nil {
+		ctx.Debug("Received frame counter regression")
+		return h.handleFCntRegression(uplink)
+	}
+
 	dev, err := h.devices.Get(appID, devID)
 	if err != nil {
 		return err
@@ -69,24 +81,68 @@ func (h *handler) HandleUplink(uplink *pb_broker.DeduplicatedUplinkMessage) (err
 		}
 	}
 
+	// Retransmissions are not published again if the application deduplicates them
+	duplicate := isDuplicateUplink(dev, appUplink, h.deduplicationWindow(appID), time.Now())
+
+	// The device is stored even if the application reached its quota, so that
+	// the state of the session is not lost
+	quotaErr := h.meterUsage(appID, map[string]int64{
+		MeterUplinks: 1,
+		MeterAirtime: appUplink.Metadata.Airtime / int64(time.Millisecond),
+	})
+
 	err = h.devices.Set(dev)
 	if err != nil {
 		return err
 	}
 	dev.StartUpdate()
 
-	// Publish Uplink
-	h.mqttUp <- appUplink
-	if h.amqpEnabled {
-		h.amqpUp <- appUplink
+	if quotaErr != nil {
+		return quotaErr
 	}
 
+	if duplicate {
+		ctx.Debug("Not publishing duplicate uplink")
+	} else {
+		// Publish Uplink
+		h.mqttUp <- appUplink
+		if h.amqpEnabled {
+			h.amqpUp <- appUplink
+		}
+		h.evaluateRules(appUplink)
+		h.writeTimeSeries(appUplink, dev)
+		h.recordCoverage(appUplink)
+	}
+
+	h.publishPolicyViolations(uplink)
+	h.publishLinkQuality(uplink)
+	h.publishDeviceReset(uplink)
+
 	if uplink.ResponseTemplate == nil {
 		ctx.Debug("No Downlink Available")
-		return nil
+		return h.checkNoDownlink(uplink, dev)
 	}
 
-	if dev.CurrentDownlink == nil {
+	// Application payloads are not sent through untrusted gateways or to devices
+	// that violate a policy of the network, but the response with the ack and
+	// MAC commands of the network is
+	var withheldErr error
+	trustedOnly := h.trustedGatewaysOnly(appID)
+	if trustedOnly {
+		withheldErr = checkDownlinkGatewayTrust(uplink)
+	}
+	if withheldErr == nil {
+		withheldErr = checkPolicyViolations(uplink)
+	}
+	if withheldErr != nil {
+		ctx.WithError(withheldErr).Debug("Not sending application downlink")
+		if dev.CurrentDownlink != nil {
+			if err = h.deferDownlink(dev, dev.CurrentDownlink, withheldErr); err != nil {
+				return err
+			}
+			dev.CurrentDownlink = nil
+		}
+	} else if dev.CurrentDownlink == nil {
 		<-time.After(ResponseDeadline)
 
 		queue, err := h.devices.DownlinkQueue(appID, devID)
@@ -102,6 +158,24 @@ func (h *handler) HandleUplink(uplink *pb_broker.DeduplicatedUplinkMessage) (err
 		dev.CurrentDownlink = next
 	}
 
+	if dev.CurrentDownlink != nil {
+		if hintsErr := selectDownlinkOption(dev.CurrentDownlink, uplink, trustedOnly); hintsErr != nil {
+			if err = h.deferDownlink(dev, dev.CurrentDownlink, hintsErr); err != nil {
+				return err
+			}
+			dev.CurrentDownlink = nil
+		} else {
+			dev.DownlinkDeferrals = 0
+		}
+	}
+
+	// The application encrypts the downlinks of devices with end-to-end encryption for a downlink counter
+	if lorawan := uplink.ResponseTemplate.GetDownlinkOption().GetProtocolConfig().GetLorawan(); lorawan != nil {
+		if err = h.invalidateEndToEndDownlinks(dev, lorawan.FCnt); err != nil {
+			return err
+		}
+	}
+
 	// Save changes (if any)
 	err = h.devices.Set(dev)
 	if err != nil {
@@ -118,6 +192,11 @@ func (h *handler) HandleUplink(uplink *pb_broker.DeduplicatedUplinkMessage) (err
 	downlink := uplink.ResponseTemplate
 	downlink.Trace = uplink.Trace.WithEvent("prepare downlink")
 
+	// The router builds the downlink option again for the receive window parameters of the device
+	if downlink.DownlinkOption != nil {
+		downlink.DownlinkOption.RxSettings = downlinkRXSettings(dev.RXSettings, dev.DownlinkProfile)
+	}
+
 	// Handle Downlink
 	err = h.HandleDownlink(&appDownlink, downlink)
 	if err != nil {
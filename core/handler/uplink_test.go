@@ -173,4 +173,58 @@ func TestHandleUplink(t *testing.T) {
 	a.So(next.PayloadRaw, ShouldResemble, []byte{0x12, 0x34})
 	a.So(dev.CurrentDownlink, ShouldNotBeNil)
 	a.So(dev.CurrentDownlink.PayloadRaw, ShouldResemble, []byte{0xaa, 0xbc})
+
+	// Test Uplink through an untrusted gateway, only the ACK is sent
+	h.applications.Set(&application.Application{
+		AppID:               appID,
+		TrustedGatewaysOnly: true,
+	})
+	downlink.DownlinkOption.GatewayId = "untrusted"
+	wg.Add(2)
+	go func() {
+		<-h.mqttUp
+		wg.Done()
+	}()
+	go func() {
+		<-h.downlink
+		wg.Done()
+	}()
+	downlink.Payload = downlinkACK
+	err = h.HandleUplink(uplink)
+	a.So(err, ShouldBeNil)
+	wg.WaitFor(50 * time.Millisecond)
+
+	dev, _ = h.devices.Get(appID, devID)
+	a.So(dev.CurrentDownlink, ShouldBeNil)
+	next, _ = queue.Next()
+	a.So(next, ShouldNotBeNil)
+	a.So(next.PayloadRaw, ShouldResemble, []byte{0xaa, 0xbc})
+
+	// Test Uplink of a device that violates a policy, only the ACK is sent
+	h.applications.Set(&application.Application{
+		AppID: appID,
+	})
+	queue.PushFirst(&types.DownlinkMessage{PayloadRaw: []byte{0xaa, 0xbc}})
+	uplink.PolicyViolations = []*pb_broker.PolicyViolation{{
+		Policy:    "fair-access",
+		Violation: "10 downlinks reached the maximum of 10",
+	}}
+	wg.Add(2)
+	go func() {
+		<-h.mqttUp
+		wg.Done()
+	}()
+	go func() {
+		<-h.downlink
+		wg.Done()
+	}()
+	downlink.Payload = downlinkACK
+	err = h.HandleUplink(uplink)
+	a.So(err, ShouldBeNil)
+	wg.WaitFor(50 * time.Millisecond)
+
+	dev, _ = h.devices.Get(appID, devID)
+	a.So(dev.CurrentDownlink, ShouldBeNil)
+	qLen, _ = queue.Length()
+	a.So(qLen, ShouldEqual, 1)
 }
@@ -0,0 +1,120 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocketWriteTimeout indicates how long we should wait for a message to be written to a WebSocket
+var WebSocketWriteTimeout = 10 * time.Second
+
+// liveDevices returns the device IDs in the dev_id query parameters of the
+// request. An empty result means that all devices are selected.
+func liveDevices(req *http.Request) map[string]bool {
+	devIDs := make(map[string]bool)
+	for _, param := range req.URL.Query()["dev_id"] {
+		for _, devID := range strings.Split(param, ",") {
+			if devID = strings.TrimSpace(devID); devID != "" {
+				devIDs[devID] = true
+			}
+		}
+	}
+	return devIDs
+}
+
+// queryCredentials uses the access key or token in the key or token query
+// parameters if the request has no key or token header, for clients that can
+// not set headers
+func queryCredentials(req *http.Request) {
+	query := req.URL.Query()
+	if key := query.Get("key"); key != "" && req.Header.Get("Grpc-Metadata-Key") == "" {
+		req.Header.Set("Grpc-Metadata-Key", key)
+	}
+	if token := query.Get("token"); token != "" && req.Header.Get("Grpc-Metadata-Token") == "" {
+		req.Header.Set("Grpc-Metadata-Token", token)
+	}
+}
+
+// live upgrades the request to a WebSocket that streams the uplink messages
+// and events of the application. Because browsers can not set headers on
+// WebSocket requests, the access key or token can also be given in the key
+// or token query parameters.
+func (h *httpHandler) live(res http.ResponseWriter, req *http.Request, appID string) {
+	queryCredentials(req)
+	if _, err := h.authorize(req, appID, rights.Devices); err != nil {
+		h.write(res, nil, err)
+		return
+	}
+	if _, err := h.manager.handler.applications.Get(appID); err != nil {
+		h.write(res, nil, err)
+		return
+	}
+
+	devIDs := liveDevices(req)
+	sub := h.manager.handler.Subscribe(appID)
+	defer h.manager.handler.Unsubscribe(sub)
+
+	ctx := h.manager.handler.Ctx.WithField("AppID", appID)
+	ctx.Debug("Start live data WebSocket")
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.manager.handler.streamLive(ws, sub, devIDs)
+	}}.ServeHTTP(res, req)
+	ctx.Debug("End live data WebSocket")
+}
+
+// streamLive writes the messages of the subscription to the WebSocket until
+// the client closes it. Only the messages of the given devices are written,
+// or all messages if devIDs is empty.
+func (h *handler) streamLive(ws *websocket.Conn, sub *Subscription, devIDs map[string]bool) {
+	defer ws.Close()
+
+	// We do not expect messages from the client, but we have to read to notice that it went away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var msg string
+		for {
+			if err := websocket.Message.Receive(ws, &msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(devID, event string, data interface{}) bool {
+		if len(devIDs) > 0 && !devIDs[devID] {
+			return true
+		}
+		message, err := newLiveEvent(devID, event, data)
+		if err != nil {
+			h.Ctx.WithError(err).Warn("Could not marshal live data")
+			return true
+		}
+		ws.SetWriteDeadline(time.Now().Add(WebSocketWriteTimeout))
+		if err := websocket.JSON.Send(ws, message); err != nil {
+			return false
+		}
+		return true
+	}
+
+	for {
+		select {
+		case <-closed:
+			return
+		case up, ok := <-sub.Uplink:
+			if !ok || !send(up.DevID, uplinkStreamEvent, up) {
+				return
+			}
+		case event, ok := <-sub.Events:
+			if !ok || !send(event.DevID, string(event.Event), event.Data) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+	"golang.org/x/net/websocket"
+)
+
+func TestLiveDevices(t *testing.T) {
+	a := New(t)
+	a.So(liveDevices(httptest.NewRequest("GET", "/applications/app/live", nil)), ShouldBeEmpty)
+	devIDs := liveDevices(httptest.NewRequest("GET", "/applications/app/live?dev_id=dev1,dev2&dev_id=dev3&dev_id=", nil))
+	a.So(devIDs, ShouldHaveLength, 3)
+	a.So(devIDs["dev1"], ShouldBeTrue)
+	a.So(devIDs["dev2"], ShouldBeTrue)
+	a.So(devIDs["dev3"], ShouldBeTrue)
+}
+
+func TestHandlerHTTPHandlerLive(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component:    &component.Component{Ctx: GetLogger(t, "TestHandlerHTTPHandlerLive")},
+		devices:      device.NewMemoryDeviceStore(),
+		applications: application.NewMemoryApplicationStore(),
+	}
+	handler := h.HTTPHandler(http.NotFoundHandler())
+
+	// Requests without token or key are rejected before the upgrade
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/applications/app/live", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/applications/app/live", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusNotFound)
+}
+
+func TestStreamLive(t *testing.T) {
+	a := New(t)
+	h := &handler{
+		Component: &component.Component{Ctx: GetLogger(t, "TestStreamLive")},
+	}
+
+	sub := h.Subscribe("app")
+	defer h.Unsubscribe(sub)
+
+	server := httptest.NewServer(websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.streamLive(ws, sub, map[string]bool{"dev1": true})
+	}})
+	defer server.Close()
+
+	ws, err := websocket.Dial(strings.Replace(server.URL, "http", "ws", 1), "", server.URL)
+	a.So(err, ShouldBeNil)
+	defer ws.Close()
+
+	h.subscriptions.publishUplink(&types.UplinkMessage{AppID: "app", DevID: "dev2"})
+	h.subscriptions.publishUplink(&types.UplinkMessage{AppID: "app", DevID: "dev1", FPort: 1})
+	h.subscriptions.publishEvent(&types.DeviceEvent{AppID: "app", DevID: "dev1", Event: types.ActivationEvent})
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+
+	// Uplinks and events are sent in the order in which they are read from the subscription
+	received := make(map[string]StreamEvent)
+	for i := 0; i < 2; i++ {
+		var message StreamEvent
+		a.So(websocket.JSON.Receive(ws, &message), ShouldBeNil)
+		a.So(message.DevID, ShouldEqual, "dev1")
+		a.So(message.Cursor, ShouldBeEmpty)
+		received[message.Event] = message
+	}
+	a.So(received, ShouldContainKey, uplinkStreamEvent)
+	a.So(string(received[uplinkStreamEvent].Data), ShouldContainSubstring, `"port":1`)
+	a.So(received, ShouldContainKey, string(types.ActivationEvent))
+}
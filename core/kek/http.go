@@ -0,0 +1,76 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package kek
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// LabelsResponse is returned by the KEK management API
+type LabelsResponse struct {
+	Labels []string `json:"labels"`
+}
+
+// SetRequest is accepted by the KEK management API
+type SetRequest struct {
+	KEK string `json:"kek"` // Hex encoded
+}
+
+// HTTPHandler returns the KEK management API. The KEKs themselves are never returned.
+//
+//	GET /             returns the labels of the KEKs
+//	PUT /{label}      sets the KEK with the label
+//	DELETE /{label}   deletes the KEK with the label
+func HTTPHandler(store Store) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		label := strings.Trim(req.URL.Path, "/")
+		var err error
+		switch {
+		case label == "" && req.Method == http.MethodGet:
+			var labels []string
+			if labels, err = store.Labels(); err == nil {
+				if labels == nil {
+					labels = []string{}
+				}
+				res.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(res).Encode(LabelsResponse{Labels: labels})
+				return
+			}
+		case label != "" && !strings.Contains(label, "/") && req.Method == http.MethodPut:
+			var in SetRequest
+			if err = json.NewDecoder(req.Body).Decode(&in); err != nil {
+				err = errors.NewErrInvalidArgument("Body", err.Error())
+				break
+			}
+			var kek []byte
+			if kek, err = hex.DecodeString(in.KEK); err != nil {
+				err = errors.NewErrInvalidArgument("KEK", err.Error())
+				break
+			}
+			err = store.Set(label, kek)
+		case label != "" && !strings.Contains(label, "/") && req.Method == http.MethodDelete:
+			err = store.Delete(label)
+		default:
+			http.NotFound(res, req)
+			return
+		}
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch errors.GetErrType(err) {
+			case errors.NotFound:
+				code = http.StatusNotFound
+			case errors.InvalidArgument:
+				code = http.StatusBadRequest
+			}
+			http.Error(res, err.Error(), code)
+			return
+		}
+		res.WriteHeader(http.StatusNoContent)
+	})
+}
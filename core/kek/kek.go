@@ -0,0 +1,157 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package kek manages the key encryption keys (KEKs) that wrap the session keys
+// that are exchanged between components, as in the LoRaWAN Backend Interfaces
+package kek
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/security"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/redis.v5"
+)
+
+// NwkSKey is the name of the envelope of the NwkSKey in gRPC metadata
+const NwkSKey = "nwk-s-key"
+
+// Store contains the KEKs by their label
+type Store interface {
+	// Get the KEK with the label
+	Get(label string) ([]byte, error)
+	// Set the KEK with the label. KEKs are AES keys of 16, 24 or 32 bytes.
+	Set(label string, kek []byte) error
+	// Delete the KEK with the label
+	Delete(label string) error
+	// Labels returns the sorted labels of the KEKs
+	Labels() ([]string, error)
+}
+
+// NewRedisStore returns a Store that keeps the KEKs in a Redis hash
+func NewRedisStore(client *redis.Client, prefix string) Store {
+	return &redisStore{client: client, key: prefix + ":keks"}
+}
+
+type redisStore struct {
+	client *redis.Client
+	key    string
+}
+
+func (s *redisStore) Get(label string) ([]byte, error) {
+	kek, err := s.client.HGet(s.key, label).Bytes()
+	if err == redis.Nil {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("KEK %s", label))
+	}
+	return kek, err
+}
+
+func (s *redisStore) Set(label string, kek []byte) error {
+	if label == "" {
+		return errors.NewErrInvalidArgument("KEK Label", "can not be empty")
+	}
+	switch len(kek) {
+	case 16, 24, 32:
+	default:
+		return errors.NewErrInvalidArgument("KEK", "must be 16, 24 or 32 bytes")
+	}
+	return s.client.HSet(s.key, label, kek).Err()
+}
+
+func (s *redisStore) Delete(label string) error {
+	return s.client.HDel(s.key, label).Err()
+}
+
+func (s *redisStore) Labels() ([]string, error) {
+	labels, err := s.client.HKeys(s.key).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// KeyEnvelope contains a key that is wrapped with the KEK with the label. If the
+// label is empty, the key is not wrapped.
+type KeyEnvelope struct {
+	KEKLabel string `json:"kek_label,omitempty"`
+	AESKey   []byte `json:"aes_key"`
+}
+
+// Wrap the key with the KEK with the label
+func Wrap(store Store, label string, key []byte) (*KeyEnvelope, error) {
+	if label == "" {
+		return &KeyEnvelope{AESKey: key}, nil
+	}
+	kek, err := store.Get(label)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := security.WrapKey(kek, key)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyEnvelope{KEKLabel: label, AESKey: wrapped}, nil
+}
+
+// Unwrap returns the key in the envelope
+func Unwrap(store Store, envelope *KeyEnvelope) ([]byte, error) {
+	if envelope.KEKLabel == "" {
+		return envelope.AESKey, nil
+	}
+	if store == nil {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("KEK %s", envelope.KEKLabel))
+	}
+	kek, err := store.Get(envelope.KEKLabel)
+	if err != nil {
+		return nil, err
+	}
+	key, err := security.UnwrapKey(kek, envelope.AESKey)
+	if err != nil {
+		return nil, errors.NewErrInvalidArgument("Key Envelope", fmt.Sprintf("could not unwrap with KEK %s", envelope.KEKLabel))
+	}
+	return key, nil
+}
+
+// Metadata returns the envelope as gRPC metadata with the name
+func (e *KeyEnvelope) Metadata(name string) metadata.MD {
+	return metadata.Pairs(
+		name+"-kek-label", e.KEKLabel,
+		name, hex.EncodeToString(e.AESKey),
+	)
+}
+
+// FromMetadata returns the envelope with the name from gRPC metadata, if any
+func FromMetadata(md metadata.MD, name string) (*KeyEnvelope, error) {
+	key, ok := md[name]
+	if !ok || len(key) == 0 {
+		return nil, nil
+	}
+	envelope := new(KeyEnvelope)
+	if label, ok := md[name+"-kek-label"]; ok && len(label) > 0 {
+		envelope.KEKLabel = label[0]
+	}
+	var err error
+	if envelope.AESKey, err = hex.DecodeString(key[0]); err != nil {
+		return nil, errors.NewErrInvalidArgument("Key Envelope", err.Error())
+	}
+	return envelope, nil
+}
+
+// AppendToContext returns a context with the envelope added to the gRPC metadata of ctx
+func (e *KeyEnvelope) AppendToContext(ctx context.Context, name string) context.Context {
+	md, ok := metadata.FromContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	for k, v := range e.Metadata(name) {
+		md[k] = v
+	}
+	return metadata.NewContext(ctx, md)
+}
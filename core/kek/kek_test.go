@@ -0,0 +1,79 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package kek
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestKeyEnvelope(t *testing.T) {
+	a := New(t)
+	store := NewRedisStore(GetRedisClient(), "test-kek-envelope")
+	defer store.Delete("ns")
+
+	key := []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+
+	_, err := Wrap(store, "ns", key)
+	a.So(err, ShouldNotBeNil)
+
+	a.So(store.Set("ns", []byte{1, 2, 3}), ShouldNotBeNil)
+	a.So(store.Set("ns", []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}), ShouldBeNil)
+
+	envelope, err := Wrap(store, "ns", key)
+	a.So(err, ShouldBeNil)
+	a.So(envelope.KEKLabel, ShouldEqual, "ns")
+	a.So(envelope.AESKey, ShouldHaveLength, 24)
+
+	unwrapped, err := Unwrap(store, envelope)
+	a.So(err, ShouldBeNil)
+	a.So(unwrapped, ShouldResemble, key)
+
+	// Without label, the key is not wrapped
+	plain, err := Wrap(store, "", key)
+	a.So(err, ShouldBeNil)
+	a.So(plain.AESKey, ShouldResemble, key)
+
+	// The envelope is sent in gRPC metadata
+	ctx := metadata.NewContext(context.Background(), metadata.Pairs("token", "abc"))
+	ctx = envelope.AppendToContext(ctx, NwkSKey)
+	md, _ := metadata.FromContext(ctx)
+	a.So(md["token"], ShouldResemble, []string{"abc"})
+	fromMetadata, err := FromMetadata(md, NwkSKey)
+	a.So(err, ShouldBeNil)
+	a.So(fromMetadata, ShouldResemble, envelope)
+
+	fromMetadata, err = FromMetadata(metadata.MD{}, NwkSKey)
+	a.So(err, ShouldBeNil)
+	a.So(fromMetadata, ShouldBeNil)
+}
+
+func TestHTTPHandler(t *testing.T) {
+	a := New(t)
+	store := NewRedisStore(GetRedisClient(), "test-kek-http")
+	defer store.Delete("ns")
+	handler := HTTPHandler(store)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	a.So(do("GET", "/", "").Body.String(), ShouldContainSubstring, `"labels":[]`)
+	a.So(do("PUT", "/ns", `{"kek":"zz"}`).Code, ShouldEqual, http.StatusBadRequest)
+	a.So(do("PUT", "/ns", `{"kek":"000102030405060708090A0B0C0D0E0F"}`).Code, ShouldEqual, http.StatusNoContent)
+	a.So(do("GET", "/", "").Body.String(), ShouldContainSubstring, `"labels":["ns"]`)
+	a.So(do("DELETE", "/ns", "").Code, ShouldEqual, http.StatusNoContent)
+	a.So(do("GET", "/", "").Body.String(), ShouldContainSubstring, `"labels":[]`)
+	a.So(do("POST", "/ns", "").Code, ShouldEqual, http.StatusNotFound)
+}
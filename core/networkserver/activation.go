@@ -18,13 +18,13 @@ import (
 	"github.com/brocaar/lorawan"
 )
 
-func (n *networkServer) getDevAddr(constraints ...string) (types.DevAddr, error) {
+func (n *networkServer) getDevAddr(tenant *types.Tenant, constraints ...string) (types.DevAddr, error) {
 	// Generate random DevAddr bytes
 	var devAddr types.DevAddr
 	copy(devAddr[:], random.Bytes(4))
 
-	// Get a random prefix that matches the constraints
-	prefixes := n.GetPrefixesFor(constraints...)
+	// Get a random prefix that matches the constraints and that can be used by the tenant
+	prefixes := n.tenantPrefixes(tenant, constraints...)
 	if len(prefixes) == 0 {
 		return types.DevAddr{}, errors.NewErrNotFound(fmt.Sprintf("DevAddr prefix with constraints %v", constraints))
 	}
@@ -48,6 +48,7 @@ func (n *networkServer) HandlePrepareActivation(activation *pb_broker.Deduplicat
 	}
 	activation.AppId = dev.AppID
 	activation.DevId = dev.DevID
+	activation.GatewayGroups = n.gatewayGroups(dev)
 
 	// Don't take any action if there is no response possible
 	if pld := activation.GetResponseTemplate(); pld == nil {
@@ -69,7 +70,7 @@ func (n *networkServer) HandlePrepareActivation(activation *pb_broker.Deduplicat
 
 	// Allocate a  device address
 	activation.Trace = activation.Trace.WithEvent("allocate devaddr")
-	devAddr, err := n.getDevAddr(activationConstraints...)
+	devAddr, err := n.getDevAddr(n.tenants.ForApplication(dev.AppID), activationConstraints...)
 	if err != nil {
 		return nil, err
 	}
@@ -134,11 +135,17 @@ func (n *networkServer) HandleActivate(activation *pb_handler.DeviceActivationRe
 	dev.FCntUp = 0
 	dev.FCntDown = 0
 	dev.ADR = device.ADRSettings{Band: dev.ADR.Band, Margin: dev.ADR.Margin}
+	dev.TxParams = device.TxParamSettings{}
+	dev.PendingMACCommands = nil
 
 	if band := meta.GetLorawan().GetRegion().String(); band != "" {
 		dev.ADR.Band = band
 	}
 
+	if err := n.resetRelaySessions(dev); err != nil {
+		n.Ctx.WithError(err).WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).Warn("Could not update relay of device")
+	}
+
 	err = n.devices.Set(dev)
 	if err != nil {
 		return nil, err
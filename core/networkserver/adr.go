@@ -4,7 +4,11 @@
 package networkserver
 
 import (
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
@@ -38,28 +42,44 @@ func lossPercentage(frames []*device.Frame) int {
 	return int(math.Floor((float64(loss) / float64(sentPackets) * 100) + .5))
 }
 
+// uplinkDataRate returns the data rate of the uplink, such as "SF7BW125". FSK
+// data rates are returned as bit rate, such as "50000".
+func uplinkDataRate(md *pb_lorawan.Metadata) string {
+	if md.GetModulation() == pb_lorawan.Modulation_FSK {
+		if md.GetBitRate() == 0 {
+			return ""
+		}
+		return strconv.Itoa(int(md.GetBitRate()))
+	}
+	return md.GetDataRate()
+}
+
 func (n *networkServer) handleUplinkADR(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) error {
 	lorawanUplinkMac := message.GetMessage().GetLorawan().GetMacPayload()
 	lorawanDownlinkMac := message.GetResponseTemplate().GetMessage().GetLorawan().GetMacPayload()
 
-	history, err := n.devices.Frames(dev.AppEUI, dev.DevEUI)
+	history, err := n.frameHistory(dev)
 	if err != nil {
 		return err
 	}
 
+	if dev.ADR.Band == "" {
+		dev.ADR.Band = message.GetProtocolMetadata().GetLorawan().GetRegion().String()
+	}
+
 	if lorawanUplinkMac.Adr {
+		dataRate := uplinkDataRate(message.GetProtocolMetadata().GetLorawan())
+		best := bestGateway(message.GetGatewayMetadata())
 		if err := history.Push(&device.Frame{
 			FCnt:         lorawanUplinkMac.FCnt,
-			SNR:          bestSNR(message.GetGatewayMetadata()),
+			SNR:          best.GetSnr(),
+			RSSI:         best.GetRssi(),
+			DataRate:     dataRate,
 			GatewayCount: uint32(len(message.GatewayMetadata)),
+			Time:         time.Now(),
 		}); err != nil {
 			n.Ctx.WithError(err).Error("Could not push frame for device")
 		}
-		if dev.ADR.Band == "" {
-			dev.ADR.Band = message.GetProtocolMetadata().GetLorawan().GetRegion().String()
-		}
-
-		dataRate := message.GetProtocolMetadata().GetLorawan().GetDataRate()
 		if dev.ADR.DataRate != dataRate {
 			dev.ADR.DataRate = dataRate
 			dev.ADR.SendReq = true // schedule a LinkADRReq
@@ -73,35 +93,36 @@ func (n *networkServer) handleUplinkADR(message *pb_broker.DeduplicatedUplinkMes
 		if err := history.Clear(); err != nil {
 			return err
 		}
-		dev.ADR.SendReq = false
-		dev.ADR.DataRate = ""
-		dev.ADR.TxPower = 0
-		dev.ADR.NbTrans = 0
+		dev.ADR = device.ADRSettings{Band: dev.ADR.Band, Margin: dev.ADR.Margin}
 	}
 
 	return nil
 }
 
 func (n *networkServer) handleDownlinkADR(message *pb_broker.DownlinkMessage, dev *device.Device) error {
-	if !dev.ADR.SendReq {
+	if !dev.ADR.SendReq || dev.Options.DisableADR {
 		return nil
 	}
 
 	if dev.ADR.Failed > 0 {
-		return nil
+		return n.handleDownlinkADRFallback(message, dev)
 	}
 
-	history, err := n.devices.Frames(dev.AppEUI, dev.DevEUI)
+	history, err := n.frameHistory(dev)
+	if err != nil {
+		return err
+	}
 
 	frames, err := history.Get()
 	if err != nil {
 		return err
 	}
-	if len(frames) < device.FramesHistorySize {
+	depth := n.frameHistorySettings(dev).GetDepth()
+	if len(frames) < depth {
 		return nil
 	}
 
-	frames = frames[:device.FramesHistorySize]
+	frames = frames[:depth]
 	// Check settings
 	if dev.ADR.DataRate == "" {
 		return nil
@@ -124,13 +145,15 @@ func (n *networkServer) handleDownlinkADR(message *pb_broker.DownlinkMessage, de
 	}
 
 	// Calculate ADR settings
-	dataRate, txPower, err := fp.ADRSettings(dev.ADR.DataRate, dev.ADR.TxPower, maxSNR(frames), float32(dev.ADR.Margin))
+	maxSNR := maxSNR(frames)
+	dataRate, txPower, err := fp.ADRSettings(dev.ADR.DataRate, dev.ADR.TxPower, maxSNR, float32(dev.ADR.Margin))
 	if err == band.ErrADRUnavailable {
 		return nil
 	}
 	if err != nil {
 		return err
 	}
+	dataRate, txPower, limited := limitADRSettings(&fp, dev.ADRLimits, dataRate, txPower)
 	drIdx, err := fp.GetDataRateIndexFor(dataRate)
 	if err != nil {
 		return err
@@ -141,8 +164,8 @@ func (n *networkServer) handleDownlinkADR(message *pb_broker.DownlinkMessage, de
 	}
 
 	var nbTrans = dev.ADR.NbTrans
+	var lossPercentage = lossPercentage(frames)
 	if dev.ADR.DataRate == dataRate && dev.ADR.TxPower == txPower && !dev.Options.DisableFCntCheck {
-		lossPercentage := lossPercentage(frames)
 		switch {
 		case lossPercentage <= 5:
 			nbTrans--
@@ -164,30 +187,168 @@ func (n *networkServer) handleDownlinkADR(message *pb_broker.DownlinkMessage, de
 	if dev.ADR.DataRate == dataRate && dev.ADR.TxPower == txPower && dev.ADR.NbTrans == nbTrans {
 		return nil
 	}
+	if dev.ADR.RejectedDataRate == dataRate && dev.ADR.RejectedTxPower == txPower {
+		return nil // Don't repeat a request that the device rejected
+	}
+	decision := &device.ADRDecision{
+		Time:             time.Now(),
+		Frames:           len(frames),
+		MaxSNR:           maxSNR,
+		Margin:           dev.ADR.Margin,
+		LossPercentage:   lossPercentage,
+		PreviousDataRate: dev.ADR.DataRate,
+		PreviousTxPower:  dev.ADR.TxPower,
+		PreviousNbTrans:  dev.ADR.NbTrans,
+		DataRate:         dataRate,
+		TxPower:          txPower,
+		NbTrans:          nbTrans,
+	}
+	if dev.ADR.DataRate != dataRate || dev.ADR.TxPower != txPower {
+		decision.Reason = fmt.Sprintf(
+			"max SNR of %.1f dB in the last %d frames with a margin of %d dB allows %s at %d dBm",
+			maxSNR, len(frames), dev.ADR.Margin, dataRate, txPower,
+		)
+		if limited {
+			decision.Reason += " within the ADR limits of the device"
+		}
+	} else {
+		decision.Reason = fmt.Sprintf(
+			"packet loss of %d%% in the last %d frames changes the number of transmissions from %d to %d",
+			lossPercentage, len(frames), dev.ADR.NbTrans, nbTrans,
+		)
+	}
+
 	dev.ADR.DataRate, dev.ADR.TxPower, dev.ADR.NbTrans = dataRate, txPower, nbTrans
 
 	// Set MAC command
+	channels := make([]bool, len(fp.UplinkChannels))
+	for i, ch := range fp.UplinkChannels {
+		for _, dr := range ch.DataRates {
+			if dr == drIdx {
+				channels[i] = true
+			}
+		}
+	}
+	appendLinkADRReqBlock(message, drIdx, powerIdx, channels, dev.ADR.NbTrans)
+
+	return n.pushADRDecision(dev, decision)
+}
+
+// appendLinkADRReqBlock appends the LinkADRReqs that enable the given uplink
+// channels. Bands with more than 16 uplink channels (such as US and AU) need a
+// LinkADRReq for every 16 channels, with the ChMaskCntl of that block of
+// channels. The device applies all of them as one request.
+func appendLinkADRReqBlock(message *pb_broker.DownlinkMessage, drIdx, powerIdx int, channels []bool, nbTrans int) {
+	const channelsPerMask = 16
+	for chMaskCntl := 0; chMaskCntl == 0 || chMaskCntl*channelsPerMask < len(channels); chMaskCntl++ {
+		var chMask lorawan.ChMask
+		if first := chMaskCntl * channelsPerMask; first < len(channels) {
+			copy(chMask[:], channels[first:])
+		}
+		appendLinkADRReq(message, drIdx, powerIdx, chMask, chMaskCntl, nbTrans)
+	}
+}
+
+func appendLinkADRReq(message *pb_broker.DownlinkMessage, drIdx, powerIdx int, chMask lorawan.ChMask, chMaskCntl int, nbTrans int) {
 	lorawanDownlinkMac := message.GetMessage().GetLorawan().GetMacPayload()
 	response := &lorawan.LinkADRReqPayload{
 		DataRate: uint8(drIdx),
 		TXPower:  uint8(powerIdx),
+		ChMask:   chMask,
 		Redundancy: lorawan.Redundancy{
-			ChMaskCntl: 0, // Different for US/AU
-			NbRep:      uint8(dev.ADR.NbTrans),
+			ChMaskCntl: uint8(chMaskCntl),
+			NbRep:      uint8(nbTrans),
 		},
 	}
-	for i, ch := range fp.UplinkChannels { // Different for US/AU
-		for _, dr := range ch.DataRates {
-			if dr == drIdx {
-				response.ChMask[i] = true
-			}
-		}
-	}
 	responsePayload, _ := response.MarshalBinary()
 	lorawanDownlinkMac.FOpts = append(lorawanDownlinkMac.FOpts, pb_lorawan.MACCommand{
 		Cid:     uint32(lorawan.LinkADRReq),
 		Payload: responsePayload,
 	})
+}
 
+func (n *networkServer) pushADRDecision(dev *device.Device, decision *device.ADRDecision) error {
+	adrHistory, err := n.devices.ADRHistory(dev.AppEUI, dev.DevEUI)
+	if err != nil {
+		return err
+	}
+	if err := adrHistory.Push(decision); err != nil {
+		n.Ctx.WithError(err).Error("Could not push ADR decision for device")
+	}
 	return nil
 }
+
+// maxADRFallbacks is the number of fallback LinkADRReqs that are sent after
+// the device rejected a LinkADRReq. After that, no more LinkADRReqs are sent
+// until ADR is reset.
+const maxADRFallbacks = 1
+
+// handleLinkADRRejection records a negative LinkADRAns for the request that was sent with the requested settings
+func (n *networkServer) handleLinkADRRejection(dev *device.Device, requested device.ADRSettings, answer lorawan.LinkADRAnsPayload) {
+	dev.ADR.Failed++
+	var rejected []string
+	if !answer.DataRateACK {
+		rejected = append(rejected, "data rate")
+	}
+	if !answer.PowerACK {
+		rejected = append(rejected, "power")
+	}
+	if !answer.ChannelMaskACK {
+		rejected = append(rejected, "channel mask")
+	}
+	dev.ADR.Rejection = strings.Join(rejected, ", ")
+	if !requested.Fallback {
+		dev.ADR.RejectedDataRate, dev.ADR.RejectedTxPower = requested.DataRate, requested.TxPower
+	}
+	dev.ADR.Fallback = false
+}
+
+// handleDownlinkADRFallback sends a safe LinkADRReq after the device rejected
+// a LinkADRReq: the current data rate, the default power and all default channels
+func (n *networkServer) handleDownlinkADRFallback(message *pb_broker.DownlinkMessage, dev *device.Device) error {
+	if dev.ADR.Fallback || dev.ADR.Failed > maxADRFallbacks {
+		return nil
+	}
+	if dev.ADR.DataRate == "" || dev.ADR.Band == "" {
+		return nil
+	}
+	fp, err := band.Get(dev.ADR.Band)
+	if err != nil {
+		return err
+	}
+	drIdx, err := fp.GetDataRateIndexFor(dev.ADR.DataRate)
+	if err != nil {
+		return err
+	}
+	powerIdx, _ := fp.GetTxPowerIndexFor(fp.DefaultTXPower)
+	if dev.ADR.NbTrans == 0 {
+		dev.ADR.NbTrans = 1
+	}
+
+	channels := make([]bool, len(fp.UplinkChannels))
+	for i := range channels {
+		channels[i] = true
+	}
+
+	decision := &device.ADRDecision{
+		Time:             time.Now(),
+		Margin:           dev.ADR.Margin,
+		PreviousDataRate: dev.ADR.DataRate,
+		PreviousTxPower:  dev.ADR.TxPower,
+		PreviousNbTrans:  dev.ADR.NbTrans,
+		DataRate:         dev.ADR.DataRate,
+		TxPower:          fp.DefaultTXPower,
+		NbTrans:          dev.ADR.NbTrans,
+		Reason: fmt.Sprintf(
+			"device rejected the %s of %s at %d dBm, falling back to the current data rate with the default channels",
+			dev.ADR.Rejection, dev.ADR.RejectedDataRate, dev.ADR.RejectedTxPower,
+		),
+	}
+
+	dev.ADR.TxPower = fp.DefaultTXPower
+	dev.ADR.Fallback = true
+
+	appendLinkADRReqBlock(message, drIdx, powerIdx, channels, dev.ADR.NbTrans)
+
+	return n.pushADRDecision(dev, decision)
+}
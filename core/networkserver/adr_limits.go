@@ -0,0 +1,92 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// validateADRLimits validates ADR limits. If the band of the device is known,
+// the limits are also validated against the band.
+func validateADRLimits(limits device.ADRLimits, bandName string) error {
+	for _, dataRate := range []string{limits.MinDataRate, limits.MaxDataRate} {
+		if dataRate == "" {
+			continue
+		}
+		if _, err := types.ParseDataRate(dataRate); err != nil && !band.IsFSK(dataRate) {
+			return errors.NewErrInvalidArgument("Data Rate", err.Error())
+		}
+	}
+	if limits.MaxTxPower < 0 {
+		return errors.NewErrInvalidArgument("Max TX Power", "can not be negative")
+	}
+	if bandName == "" {
+		return nil
+	}
+	fp, err := band.Get(bandName)
+	if err != nil {
+		return nil // We can't check this band
+	}
+	minIdx, maxIdx := 0, len(fp.DataRates)-1
+	if limits.MinDataRate != "" {
+		if minIdx, err = fp.GetDataRateIndexFor(limits.MinDataRate); err != nil {
+			return errors.NewErrInvalidArgument("Min Data Rate", fmt.Sprintf("%s is not available in %s", limits.MinDataRate, bandName))
+		}
+	}
+	if limits.MaxDataRate != "" {
+		if maxIdx, err = fp.GetDataRateIndexFor(limits.MaxDataRate); err != nil {
+			return errors.NewErrInvalidArgument("Max Data Rate", fmt.Sprintf("%s is not available in %s", limits.MaxDataRate, bandName))
+		}
+	}
+	if minIdx > maxIdx {
+		return errors.NewErrInvalidArgument("Data Rate", "the min data rate is faster than the max data rate")
+	}
+	return nil
+}
+
+// limitADRSettings returns the data rate and TX power within the ADR limits,
+// and whether they were limited
+func limitADRSettings(fp *band.FrequencyPlan, limits device.ADRLimits, dataRate string, txPower int) (string, int, bool) {
+	var limited bool
+	drIdx, err := fp.GetDataRateIndexFor(dataRate)
+	if err != nil {
+		return dataRate, txPower, false
+	}
+	if minIdx, err := fp.GetDataRateIndexFor(limits.MinDataRate); err == nil && drIdx < minIdx {
+		dataRate, limited = limits.MinDataRate, true
+	}
+	if maxIdx, err := fp.GetDataRateIndexFor(limits.MaxDataRate); err == nil && drIdx > maxIdx {
+		dataRate, limited = limits.MaxDataRate, true
+	}
+	if limits.MaxTxPower != 0 && txPower > limits.MaxTxPower {
+		// Use the highest power of the band that is within the limit
+		var found bool
+		var highest int
+		for _, power := range fp.TXPower {
+			if power <= limits.MaxTxPower && (!found || power > highest) {
+				found, highest = true, power
+			}
+		}
+		if found {
+			txPower, limited = highest, true
+		}
+	}
+	return dataRate, txPower, limited
+}
+
+// setADRLimits sets the ADR limits of the device. ADR applies them after the
+// next uplink messages.
+func (n *networkServer) setADRLimits(dev *device.Device, limits device.ADRLimits) error {
+	if err := validateADRLimits(limits, dev.ADR.Band); err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	dev.ADRLimits = limits
+	return n.devices.Set(dev)
+}
@@ -0,0 +1,94 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateADRLimits(t *testing.T) {
+	a := New(t)
+
+	a.So(validateADRLimits(device.ADRLimits{}, "EU_863_870"), ShouldBeNil)
+	a.So(validateADRLimits(device.ADRLimits{MinDataRate: "SF10BW125", MaxDataRate: "SF8BW125", MaxTxPower: 11}, "EU_863_870"), ShouldBeNil)
+	a.So(validateADRLimits(device.ADRLimits{MinDataRate: "SF10BW125"}, ""), ShouldBeNil)
+
+	a.So(validateADRLimits(device.ADRLimits{MinDataRate: "SF13BW125"}, ""), ShouldNotBeNil)
+	a.So(validateADRLimits(device.ADRLimits{MaxTxPower: -1}, ""), ShouldNotBeNil)
+	a.So(validateADRLimits(device.ADRLimits{MinDataRate: "SF8BW125", MaxDataRate: "SF10BW125"}, "EU_863_870"), ShouldNotBeNil)
+	a.So(validateADRLimits(device.ADRLimits{MaxDataRate: "SF7BW500"}, "EU_863_870"), ShouldNotBeNil)
+}
+
+func TestLimitADRSettings(t *testing.T) {
+	a := New(t)
+	fp, _ := band.Get("EU_863_870")
+
+	dataRate, txPower, limited := limitADRSettings(&fp, device.ADRLimits{}, "SF7BW125", 14)
+	a.So(dataRate, ShouldEqual, "SF7BW125")
+	a.So(txPower, ShouldEqual, 14)
+	a.So(limited, ShouldBeFalse)
+
+	limits := device.ADRLimits{MinDataRate: "SF10BW125", MaxDataRate: "SF8BW125", MaxTxPower: 12}
+
+	dataRate, txPower, limited = limitADRSettings(&fp, limits, "SF7BW125", 14)
+	a.So(dataRate, ShouldEqual, "SF8BW125")
+	a.So(txPower, ShouldEqual, 11)
+	a.So(limited, ShouldBeTrue)
+
+	dataRate, _, limited = limitADRSettings(&fp, limits, "SF12BW125", 11)
+	a.So(dataRate, ShouldEqual, "SF10BW125")
+	a.So(limited, ShouldBeTrue)
+
+	dataRate, txPower, limited = limitADRSettings(&fp, limits, "SF9BW125", 8)
+	a.So(dataRate, ShouldEqual, "SF9BW125")
+	a.So(txPower, ShouldEqual, 8)
+	a.So(limited, ShouldBeFalse)
+}
+
+func TestHandleDownlinkADRWithLimits(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+	ns.InitStatus()
+
+	appEUI := types.AppEUI([8]byte{1})
+	devEUI := types.DevEUI([8]byte{1})
+	history, _ := ns.devices.Frames(appEUI, devEUI)
+	for i := 0; i < 20; i++ {
+		history.Push(&device.Frame{SNR: 10, GatewayCount: 3, FCnt: uint32(i)})
+	}
+	dev := &device.Device{
+		AppEUI:    appEUI,
+		DevEUI:    devEUI,
+		ADR:       device.ADRSettings{Band: "EU_863_870", SendReq: true, DataRate: "SF10BW125", TxPower: 14, NbTrans: 1},
+		ADRLimits: device.ADRLimits{MaxDataRate: "SF9BW125"},
+	}
+
+	message := adrInitDownlinkMessage()
+	err := ns.handleDownlinkADR(message, dev)
+	a.So(err, ShouldBeNil)
+	fOpts := message.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 1)
+	payload := new(lorawan.LinkADRReqPayload)
+	payload.UnmarshalBinary(fOpts[0].Payload)
+	a.So(payload.DataRate, ShouldEqual, 3) // SF9BW125
+	a.So(dev.ADR.DataRate, ShouldEqual, "SF9BW125")
+
+	adrHistory, _ := ns.devices.ADRHistory(appEUI, devEUI)
+	decisions, _ := adrHistory.Get()
+	a.So(decisions, ShouldHaveLength, 1)
+	a.So(decisions[0].Reason, ShouldContainSubstring, "ADR limits")
+
+	// Setting limits that are not available in the band fails
+	a.So(ns.setADRLimits(dev, device.ADRLimits{MaxDataRate: "SF7BW500"}), ShouldNotBeNil)
+	a.So(ns.setADRLimits(dev, device.ADRLimits{MinDataRate: "SF11BW125"}), ShouldBeNil)
+	a.So(dev.ADRLimits.MinDataRate, ShouldEqual, "SF11BW125")
+}
@@ -15,7 +15,6 @@ import (
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
 	"github.com/TheThingsNetwork/ttn/core/types"
-	. "github.com/TheThingsNetwork/ttn/utils/testing"
 	"github.com/brocaar/lorawan"
 	. "github.com/smartystreets/assertions"
 )
@@ -79,17 +78,10 @@ func TestLossPercentage(t *testing.T) {
 func TestHandleUplinkADR(t *testing.T) {
 	a := New(t)
 	ns := &networkServer{
-		devices: device.NewRedisDeviceStore(GetRedisClient(), "ns-test-handle-uplink-adr"),
+		devices: device.NewMemoryDeviceStore(),
 	}
 	ns.InitStatus()
 
-	defer func() {
-		keys, _ := GetRedisClient().Keys("*ns-test-handle-uplink-adr*").Result()
-		for _, key := range keys {
-			GetRedisClient().Del(key).Result()
-		}
-	}()
-
 	appEUI := types.AppEUI([8]byte{1})
 	devEUI := types.DevEUI([8]byte{1})
 	history, _ := ns.devices.Frames(appEUI, devEUI)
@@ -103,9 +95,24 @@ func TestHandleUplinkADR(t *testing.T) {
 		a.So(err, ShouldBeNil)
 		frames, _ := history.Get()
 		a.So(frames, ShouldHaveLength, 1)
+		a.So(frames[0].SNR, ShouldEqual, 10)
+		a.So(frames[0].DataRate, ShouldEqual, "SF8BW125")
 		a.So(dev.ADR.DataRate, ShouldEqual, "SF8BW125")
 	}
 
+	// FSK uplinks should store the bit rate as data rate
+	{
+		dev := &device.Device{AppEUI: appEUI, DevEUI: devEUI}
+		message := adrInitUplinkMessage()
+		message.Message.GetLorawan().GetMacPayload().Adr = true
+		message.ProtocolMetadata.GetLorawan().Modulation = pb_lorawan.Modulation_FSK
+		message.ProtocolMetadata.GetLorawan().DataRate = ""
+		message.ProtocolMetadata.GetLorawan().BitRate = 50000
+		err := ns.handleUplinkADR(message, dev)
+		a.So(err, ShouldBeNil)
+		a.So(dev.ADR.DataRate, ShouldEqual, "50000")
+	}
+
 	// Resetting ADR to false should empty the frames
 	{
 		dev := &device.Device{AppEUI: appEUI, DevEUI: devEUI}
@@ -133,17 +140,10 @@ func TestHandleUplinkADR(t *testing.T) {
 func TestHandleDownlinkADR(t *testing.T) {
 	a := New(t)
 	ns := &networkServer{
-		devices: device.NewRedisDeviceStore(GetRedisClient(), "ns-test-handle-downlink-adr"),
+		devices: device.NewMemoryDeviceStore(),
 	}
 	ns.InitStatus()
 
-	defer func() {
-		keys, _ := GetRedisClient().Keys("*ns-test-handle-downlink-adr*").Result()
-		for _, key := range keys {
-			GetRedisClient().Del(key).Result()
-		}
-	}()
-
 	appEUI := types.AppEUI([8]byte{1})
 	devEUI := types.DevEUI([8]byte{1})
 	history, _ := ns.devices.Frames(appEUI, devEUI)
@@ -201,6 +201,10 @@ func TestHandleDownlinkADR(t *testing.T) {
 
 	dev.ADR.Band = "EU_863_870"
 
+	dev.Options.DisableADR = true
+	nothingShouldHappen()
+	dev.Options.DisableADR = false
+
 	err := ns.handleDownlinkADR(message, dev)
 	a.So(err, ShouldBeNil)
 	fOpts := message.Message.GetLorawan().GetMacPayload().FOpts
@@ -215,6 +219,15 @@ func TestHandleDownlinkADR(t *testing.T) {
 	}
 	a.So(payload.ChMask[8], ShouldBeFalse) // 9th channel (FSK) disabled
 
+	adrHistory, _ := ns.devices.ADRHistory(appEUI, devEUI)
+	decisions, _ := adrHistory.Get()
+	a.So(decisions, ShouldHaveLength, 1)
+	a.So(decisions[0].PreviousDataRate, ShouldEqual, "SF8BW125")
+	a.So(decisions[0].DataRate, ShouldEqual, "SF7BW125")
+	a.So(decisions[0].MaxSNR, ShouldEqual, 10)
+	a.So(decisions[0].Frames, ShouldEqual, device.FramesHistorySize)
+	a.So(decisions[0].Reason, ShouldContainSubstring, "max SNR")
+
 	shouldHaveNbTrans := func(nbTrans int) {
 		a := New(t)
 		message := adrInitDownlinkMessage()
@@ -253,9 +266,126 @@ func TestHandleDownlinkADR(t *testing.T) {
 		}
 	}
 
+	// FSK keeps the data rate and power, but adapts the number of transmissions
+	{
+		dev.ADR.DataRate, dev.ADR.TxPower, dev.ADR.NbTrans = "50000", 14, 1
+		resetFrames(dev.AppEUI, dev.DevEUI)
+		history.Push(&device.Frame{SNR: 0, GatewayCount: 3, FCnt: uint32(24)})
+		message := adrInitDownlinkMessage()
+		err := ns.handleDownlinkADR(message, dev)
+		a.So(err, ShouldBeNil)
+		fOpts := message.Message.GetLorawan().GetMacPayload().FOpts
+		a.So(fOpts, ShouldHaveLength, 1)
+		payload := new(lorawan.LinkADRReqPayload)
+		payload.UnmarshalBinary(fOpts[0].Payload)
+		a.So(payload.DataRate, ShouldEqual, 7) // FSK 50kbps
+		a.So(payload.TXPower, ShouldEqual, 1)  // 14
+		a.So(payload.ChMask[8], ShouldBeTrue)  // 9th channel (FSK) enabled
+		a.So(payload.Redundancy.NbRep, ShouldEqual, 2)
+		a.So(dev.ADR.DataRate, ShouldEqual, "50000")
+	}
+
 	// Invalid case
 	message = adrInitDownlinkMessage()
 	dev.ADR.DataRate = "INVALID"
 	shouldReturnError()
 
 }
+
+func TestLinkADRRejection(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+	ns.InitStatus()
+
+	appEUI := types.AppEUI([8]byte{2})
+	devEUI := types.DevEUI([8]byte{2})
+	dev := &device.Device{AppEUI: appEUI, DevEUI: devEUI}
+	dev.ADR = device.ADRSettings{Band: "EU_863_870", Margin: 15, SendReq: true, DataRate: "SF7BW125", TxPower: 14, NbTrans: 1}
+
+	// The device rejects the data rate and keeps sending at SF8BW125
+	requested := dev.ADR
+	dev.ADR.DataRate = "SF8BW125"
+	ns.handleLinkADRRejection(dev, requested, lorawan.LinkADRAnsPayload{PowerACK: true, ChannelMaskACK: true})
+	a.So(dev.ADR.Failed, ShouldEqual, 1)
+	a.So(dev.ADR.Rejection, ShouldEqual, "data rate")
+	a.So(dev.ADR.RejectedDataRate, ShouldEqual, "SF7BW125")
+	a.So(dev.ADR.RejectedTxPower, ShouldEqual, 14)
+
+	// A fallback request is sent once
+	message := adrInitDownlinkMessage()
+	err := ns.handleDownlinkADR(message, dev)
+	a.So(err, ShouldBeNil)
+	fOpts := message.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 1)
+	payload := new(lorawan.LinkADRReqPayload)
+	payload.UnmarshalBinary(fOpts[0].Payload)
+	a.So(payload.DataRate, ShouldEqual, 4) // SF8BW125
+	a.So(payload.TXPower, ShouldEqual, 1)  // 14
+	for i := 0; i < 9; i++ {               // All channels enabled
+		a.So(payload.ChMask[i], ShouldBeTrue)
+	}
+	a.So(dev.ADR.Fallback, ShouldBeTrue)
+
+	adrHistory, _ := ns.devices.ADRHistory(appEUI, devEUI)
+	decisions, _ := adrHistory.Get()
+	a.So(decisions, ShouldHaveLength, 1)
+	a.So(decisions[0].Reason, ShouldContainSubstring, "rejected the data rate of SF7BW125")
+
+	message = adrInitDownlinkMessage()
+	ns.handleDownlinkADR(message, dev)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+
+	// The device accepts the fallback, the rejected request is not repeated
+	dev.ADR.Failed, dev.ADR.Fallback, dev.ADR.SendReq = 0, false, true
+	history, _ := ns.devices.Frames(appEUI, devEUI)
+	for i := 0; i < 20; i++ {
+		history.Push(&device.Frame{SNR: 10, GatewayCount: 3, FCnt: uint32(i)})
+	}
+	message = adrInitDownlinkMessage()
+	err = ns.handleDownlinkADR(message, dev)
+	a.So(err, ShouldBeNil)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+
+	// The device also rejects the fallback, no more requests are sent
+	dev.ADR.Failed, dev.ADR.Fallback = 1, true
+	ns.handleLinkADRRejection(dev, dev.ADR, lorawan.LinkADRAnsPayload{DataRateACK: true, PowerACK: true})
+	a.So(dev.ADR.Failed, ShouldEqual, 2)
+	a.So(dev.ADR.Rejection, ShouldEqual, "channel mask")
+	a.So(dev.ADR.RejectedDataRate, ShouldEqual, "SF7BW125")
+	message = adrInitDownlinkMessage()
+	ns.handleDownlinkADR(message, dev)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+}
+
+func TestLinkADRFallbackUS(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+	ns.InitStatus()
+
+	dev := &device.Device{AppEUI: types.AppEUI([8]byte{3}), DevEUI: types.DevEUI([8]byte{3})}
+	dev.ADR = device.ADRSettings{Band: "US_902_928", SendReq: true, DataRate: "SF10BW125", TxPower: 20, NbTrans: 1, Failed: 1}
+
+	// All 72 channels are enabled with a block of LinkADRReqs
+	message := adrInitDownlinkMessage()
+	err := ns.handleDownlinkADR(message, dev)
+	a.So(err, ShouldBeNil)
+	fOpts := message.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 5)
+	for i, fOpt := range fOpts {
+		payload := new(lorawan.LinkADRReqPayload)
+		payload.UnmarshalBinary(fOpt.Payload)
+		a.So(payload.DataRate, ShouldEqual, 0) // SF10BW125
+		a.So(payload.Redundancy.ChMaskCntl, ShouldEqual, i)
+		a.So(payload.ChMask[0], ShouldBeTrue)
+		a.So(payload.ChMask[7], ShouldBeTrue)
+		if i < 4 {
+			a.So(payload.ChMask[15], ShouldBeTrue)
+		} else {
+			a.So(payload.ChMask[8], ShouldBeFalse) // There are only 72 channels
+		}
+	}
+}
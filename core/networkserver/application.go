@@ -0,0 +1,24 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+)
+
+// getApplication returns the settings of the application, or nil if they can
+// not be retrieved, in which case the settings of the NetworkServer apply
+func (n *networkServer) getApplication(appID string) *device.Application {
+	if n.applications == nil || appID == "" {
+		return nil
+	}
+	app, err := n.applications.Get(appID)
+	if err != nil {
+		if n.Component != nil {
+			n.Ctx.WithError(err).WithField("AppID", appID).Warn("Could not get application settings")
+		}
+		return nil
+	}
+	return app
+}
@@ -0,0 +1,127 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// ADRHistory for a device
+type ADRHistory interface {
+	Push(decision *ADRDecision) error
+	Get() ([]*ADRDecision, error)
+	Clear() error
+}
+
+// ADRHistorySize is the number of ADR decisions that is kept for each device
+const ADRHistorySize = 10
+
+// ADRDecision contains the inputs and outputs of an ADR calculation that
+// resulted in a LinkADRReq
+type ADRDecision struct {
+	Time time.Time `json:"time"`
+
+	// Inputs
+	Frames         int     `json:"frames"`
+	MaxSNR         float32 `json:"max_snr"`
+	Margin         int     `json:"margin"`
+	LossPercentage int     `json:"loss_percentage"`
+
+	// Settings before the decision
+	PreviousDataRate string `json:"previous_data_rate"`
+	PreviousTxPower  int    `json:"previous_tx_power"`
+	PreviousNbTrans  int    `json:"previous_nb_trans"`
+
+	// Settings that were sent in the LinkADRReq
+	DataRate string `json:"data_rate"`
+	TxPower  int    `json:"tx_power"`
+	NbTrans  int    `json:"nb_trans"`
+
+	// Reason explains the decision
+	Reason string `json:"reason"`
+}
+
+// RedisADRHistory implements the ADR history in Redis
+type RedisADRHistory struct {
+	appEUI types.AppEUI
+	devEUI types.DevEUI
+	store  *storage.RedisQueueStore
+}
+
+func (s *RedisADRHistory) key() string {
+	return fmt.Sprintf("%s:%s", s.appEUI, s.devEUI)
+}
+
+// Push an ADRDecision to the device's history
+func (s *RedisADRHistory) Push(decision *ADRDecision) error {
+	decisionBytes, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+	if err := s.store.AddFront(s.key(), string(decisionBytes)); err != nil {
+		return err
+	}
+	return s.store.Trim(s.key(), ADRHistorySize)
+}
+
+// Get the last ADR decisions from the device's history
+func (s *RedisADRHistory) Get() (out []*ADRDecision, err error) {
+	decisions, err := s.store.GetFront(s.key(), ADRHistorySize)
+	for _, decisionStr := range decisions {
+		decision := new(ADRDecision)
+		if err := json.Unmarshal([]byte(decisionStr), decision); err != nil {
+			return nil, err
+		}
+		out = append(out, decision)
+	}
+	return
+}
+
+// Clear the device's ADR history
+func (s *RedisADRHistory) Clear() error {
+	return s.store.Delete(s.key())
+}
+
+// MemoryADRHistory implements the ADR history in memory
+type MemoryADRHistory struct {
+	key   string
+	store *MemoryDeviceStore
+}
+
+// Push an ADRDecision to the device's history
+func (s *MemoryADRHistory) Push(decision *ADRDecision) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	stored := *decision
+	decisions := append([]*ADRDecision{&stored}, s.store.adrHistory[s.key]...)
+	if len(decisions) > ADRHistorySize {
+		decisions = decisions[:ADRHistorySize]
+	}
+	s.store.adrHistory[s.key] = decisions
+	return nil
+}
+
+// Get the last ADR decisions from the device's history
+func (s *MemoryADRHistory) Get() (out []*ADRDecision, err error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+	for _, decision := range s.store.adrHistory[s.key] {
+		decision := *decision
+		out = append(out, &decision)
+	}
+	return
+}
+
+// Clear the device's ADR history
+func (s *MemoryADRHistory) Clear() error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	delete(s.store.adrHistory, s.key)
+	return nil
+}
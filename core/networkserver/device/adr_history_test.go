@@ -0,0 +1,69 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestADRHistory(t *testing.T) {
+	appEUI := types.AppEUI{0, 0, 0, 0, 0, 0, 0, 1}
+	devEUI := types.DevEUI{0, 0, 0, 0, 0, 0, 0, 1}
+
+	for name, store := range map[string]Store{
+		"Redis":  NewRedisDeviceStore(GetRedisClient(), "networkserver-test-adr-history"),
+		"Memory": NewMemoryDeviceStore(),
+	} {
+		a := New(t)
+
+		s, err := store.ADRHistory(appEUI, devEUI)
+		a.So(err, ShouldBeNil)
+
+		defer s.Clear()
+
+		{
+			err := s.Push(&ADRDecision{
+				MaxSNR:   -2.5,
+				DataRate: "SF10BW125",
+				Reason:   "test",
+			})
+			a.So(err, ShouldBeNil)
+		}
+
+		{
+			decisions, err := s.Get()
+			a.So(err, ShouldBeNil)
+			a.So(decisions, ShouldHaveLength, 1)
+			a.So(decisions[0].MaxSNR, ShouldEqual, -2.5)
+			a.So(decisions[0].DataRate, ShouldEqual, "SF10BW125")
+			a.So(decisions[0].Reason, ShouldEqual, "test")
+		}
+
+		{
+			for i := 0; i < 15; i++ {
+				s.Push(&ADRDecision{Frames: i + 1})
+			}
+			decisions, err := s.Get()
+			a.So(err, ShouldBeNil)
+			a.So(decisions, ShouldHaveLength, ADRHistorySize)
+			a.So(decisions[0].Frames, ShouldEqual, 15)
+		}
+
+		{
+			err := s.Clear()
+			a.So(err, ShouldBeNil)
+			decisions, err := s.Get()
+			a.So(err, ShouldBeNil)
+			a.So(decisions, ShouldBeEmpty)
+		}
+
+		if a.Failed() {
+			t.Errorf("%s store failed", name)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"gopkg.in/redis.v5"
+)
+
+// Application contains the settings of an application. They apply to the
+// devices of the application that do not have settings of their own.
+type Application struct {
+	AppID        string               `json:"app_id"`
+	FrameHistory FrameHistorySettings `json:"frame_history,omitempty"`
+	// GatewayGroups that the devices of the application prefer for downlink, most preferred first
+	GatewayGroups []string  `json:"gateway_groups,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ApplicationStore contains the settings of applications by their AppID
+type ApplicationStore interface {
+	// Get the settings of the application. Applications without settings
+	// return empty settings.
+	Get(appID string) (*Application, error)
+	// Set the settings of the application
+	Set(app *Application) error
+	// Delete the settings of the application
+	Delete(appID string) error
+}
+
+const redisApplicationPrefix = "application"
+
+// NewRedisApplicationStore returns an ApplicationStore that keeps the
+// settings of applications in a Redis hash
+func NewRedisApplicationStore(client *redis.Client, prefix string) ApplicationStore {
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+	return &redisApplicationStore{client: client, key: prefix + ":" + redisApplicationPrefix}
+}
+
+type redisApplicationStore struct {
+	client *redis.Client
+	key    string
+}
+
+func (s *redisApplicationStore) Get(appID string) (*Application, error) {
+	data, err := s.client.HGet(s.key, appID).Bytes()
+	if err == redis.Nil {
+		return &Application{AppID: appID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	app := new(Application)
+	if err := json.Unmarshal(data, app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func (s *redisApplicationStore) Set(app *Application) error {
+	app.UpdatedAt = time.Now()
+	data, err := json.Marshal(app)
+	if err != nil {
+		return err
+	}
+	return s.client.HSet(s.key, app.AppID, data).Err()
+}
+
+func (s *redisApplicationStore) Delete(appID string) error {
+	return s.client.HDel(s.key, appID).Err()
+}
+
+// NewMemoryApplicationStore returns an ApplicationStore that keeps the
+// settings of applications in memory
+func NewMemoryApplicationStore() ApplicationStore {
+	return &memoryApplicationStore{applications: make(map[string]Application)}
+}
+
+type memoryApplicationStore struct {
+	mu           sync.RWMutex
+	applications map[string]Application
+}
+
+func (s *memoryApplicationStore) Get(appID string) (*Application, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	app, ok := s.applications[appID]
+	if !ok {
+		return &Application{AppID: appID}, nil
+	}
+	return &app, nil
+}
+
+func (s *memoryApplicationStore) Set(app *Application) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	app.UpdatedAt = time.Now()
+	s.applications[app.AppID] = *app
+	return nil
+}
+
+func (s *memoryApplicationStore) Delete(appID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.applications, appID)
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
 	"github.com/fatih/structs"
 )
 
@@ -18,23 +19,45 @@ type Options struct {
 	ActivationConstraints string `json:"activation_constraints,omitempty"` // Activation Constraints (public/local/private)
 	DisableFCntCheck      bool   `json:"disable_fcnt_check,omitemtpy"`     // Disable Frame counter check (insecure)
 	Uses32BitFCnt         bool   `json:"uses_32_bit_fcnt,omitemtpy"`       // Use 32-bit Frame counters
+	DisableADR            bool   `json:"disable_adr,omitempty"`            // Do not send ADR commands
 }
 
 // Device contains the state of a device
 type Device struct {
 	old *Device
 
-	DevEUI   types.DevEUI  `redis:"dev_eui"`
-	AppEUI   types.AppEUI  `redis:"app_eui"`
-	AppID    string        `redis:"app_id"`
-	DevID    string        `redis:"dev_id"`
-	DevAddr  types.DevAddr `redis:"dev_addr"`
-	NwkSKey  types.NwkSKey `redis:"nwk_s_key"`
-	FCntUp   uint32        `redis:"f_cnt_up"`
-	FCntDown uint32        `redis:"f_cnt_down"`
-	LastSeen time.Time     `redis:"last_seen"`
-	Options  Options       `redis:"options"`
-	ADR      ADRSettings   `redis:"adr,include"`
+	DevEUI    types.DevEUI      `redis:"dev_eui"`
+	AppEUI    types.AppEUI      `redis:"app_eui"`
+	AppID     string            `redis:"app_id"`
+	DevID     string            `redis:"dev_id"`
+	DevAddr   types.DevAddr     `redis:"dev_addr"`
+	NwkSKey   types.NwkSKey     `redis:"nwk_s_key"`
+	FCntUp    uint32            `redis:"f_cnt_up"`
+	FCntDown  uint32            `redis:"f_cnt_down"`
+	LastSeen  time.Time         `redis:"last_seen"`
+	Options   Options           `redis:"options"`
+	ADR       ADRSettings       `redis:"adr,include"`
+	StaticADR StaticADRSettings `redis:"static_adr,include"`
+	ADRLimits ADRLimits         `redis:"adr_limits,include"`
+	TxParams  TxParamSettings   `redis:"tx_params,include"`
+
+	PendingMACCommands []PendingMACCommand `redis:"pending_mac_commands"`
+
+	UplinkAirtime toa.Usage  `redis:"uplink_airtime,include"`
+	FairAccess    FairAccess `redis:"fair_access"`
+	Quarantine    Quarantine `redis:"quarantine"`
+	LinkScore     LinkScore  `redis:"link_score"`
+
+	FrameHistory FrameHistorySettings `redis:"frame_history"`
+	MACDownlinks MACDownlinkSettings  `redis:"mac_downlinks"`
+	Relay        RelaySettings        `redis:"relay"`
+	RelayServed  RelayServedSettings  `redis:"relay_served"`
+
+	// LoRaWANVersion of the device (1.0, 1.0.1 or 1.0.2), empty if unknown
+	LoRaWANVersion string `redis:"lorawan_version"`
+
+	// GatewayGroups that the device prefers for downlink, most preferred first
+	GatewayGroups []string `redis:"gateway_groups"`
 
 	CreatedAt time.Time `redis:"created_at"`
 	UpdatedAt time.Time `redis:"updated_at"`
@@ -53,6 +76,151 @@ type ADRSettings struct {
 	DataRate string `redis:"data_rate,omitempty"`
 	TxPower  int    `redis:"tx_power,omitempty"`
 	NbTrans  int    `redis:"nb_trans,omitempty"`
+
+	// Indicates whether a fallback LinkADRReq was sent after a failed attempt
+	Fallback bool `redis:"fallback,omitempty"`
+
+	// The last LinkADRReq that was rejected by the device:
+	Rejection        string `redis:"rejection,omitempty"` // the rejected parts, such as "data rate, channel mask"
+	RejectedDataRate string `redis:"rejected_data_rate,omitempty"`
+	RejectedTxPower  int    `redis:"rejected_tx_power,omitempty"`
+}
+
+// ADRLimits restricts the data rates and TX power that ADR selects for a device
+type ADRLimits struct {
+	MinDataRate string `redis:"min_data_rate,omitempty"` // the slowest data rate, such as SF10BW125
+	MaxDataRate string `redis:"max_data_rate,omitempty"` // the fastest data rate, such as SF7BW125
+	MaxTxPower  int    `redis:"max_tx_power,omitempty"`  // in dBm, no limit if zero
+}
+
+// StaticADRSettings contains the settings that the network operator pushes to
+// a device that does not use ADR
+type StaticADRSettings struct {
+	// Indicates whether the NetworkServer should send a LinkADRReq with these settings on the next downlink
+	SendReq bool `redis:"send_req,omitempty"`
+
+	DataRate string `redis:"data_rate,omitempty"`
+	TxPower  int    `redis:"tx_power,omitempty"` // the default power of the band if zero
+	Channels []int  `redis:"channels,omitempty"` // the enabled uplink channels, all channels of the band if empty
+}
+
+// TxParamSettings contains the transmit parameters that are configured with a TxParamSetupReq
+type TxParamSettings struct {
+	// Indicates whether the NetworkServer should send a TxParamSetupReq when possible
+	SendReq bool `redis:"send_req,omitempty"`
+	// Indicates whether the device answered the TxParamSetupReq
+	Configured bool `redis:"configured,omitempty"`
+	// The number of downlinks that contained the TxParamSetupReq
+	Attempts int `redis:"attempts,omitempty"`
+
+	UplinkDwellTime   bool `redis:"uplink_dwell_time,omitempty"`
+	DownlinkDwellTime bool `redis:"downlink_dwell_time,omitempty"`
+	MaxEIRP           int  `redis:"max_eirp,omitempty"`
+}
+
+// MACDownlinkSettings configure the downlinks without application payload
+// that the NetworkServer schedules to send pending MAC commands to the device
+type MACDownlinkSettings struct {
+	// Interval is the minimum time between MAC-only downlinks (the interval of the NetworkServer if zero)
+	Interval time.Duration `json:"interval,omitempty"`
+	// Disabled indicates that MAC commands always wait for a downlink of the application
+	Disabled bool `json:"disabled,omitempty"`
+	// LastSent is the time of the last MAC-only downlink
+	LastSent time.Time `json:"last_sent"`
+}
+
+// RelaySettings configure a device that acts as a relay for end devices that
+// are out of the range of gateways (LoRaWAN Relay Specification TS011)
+type RelaySettings struct {
+	// Enabled indicates that the device forwards the uplinks of end devices on the relay port
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Channel settings that are sent in a RelayConfReq
+	CADPeriodicity         int    `json:"cad_periodicity,omitempty"`          // the index of the CAD period, 0 is 1 second
+	DefaultChannelIndex    int    `json:"default_channel_index,omitempty"`    // the index of the default wake-on-radio channel
+	SecondChannelFrequency uint64 `json:"second_channel_frequency,omitempty"` // in Hz, no second channel if zero
+	SecondChannelDataRate  int    `json:"second_channel_data_rate,omitempty"` // the index of the data rate of the second channel
+	SecondChannelAckOffset int    `json:"second_channel_ack_offset,omitempty"`
+
+	// Limits on the uplinks that the relay forwards, sent in a ConfigureFwdLimitReq
+	Limits *RelayLimits `json:"limits,omitempty"`
+
+	// Indicate whether the NetworkServer should send a RelayConfReq or ConfigureFwdLimitReq when possible
+	SendConfReq   bool `json:"send_conf_req,omitempty"`
+	SendLimitsReq bool `json:"send_limits_req,omitempty"`
+
+	// Rejection contains the settings of the last RelayConfReq that the relay rejected
+	Rejection string `json:"rejection,omitempty"`
+
+	// EndDevices are the end devices that the relay notified the NetworkServer of
+	EndDevices []RelayEndDevice `json:"end_devices,omitempty"`
+
+	// UplinkList contains the end devices that are served by the relay, sent in UpdateUplinkListReqs
+	UplinkList []RelayUplinkListEntry `json:"uplink_list,omitempty"`
+}
+
+// RelayUplinkListEntry is an end device in the uplink list of a relay
+type RelayUplinkListEntry struct {
+	AppEUI types.AppEUI `json:"app_eui"`
+	DevEUI types.DevEUI `json:"dev_eui"`
+	Index  int          `json:"index"` // the index of the device in the uplink list of the relay
+
+	// Indicates whether the NetworkServer should send an UpdateUplinkListReq when possible
+	SendReq bool `json:"send_req,omitempty"`
+}
+
+// RelayServedSettings configure an end device that is served by a relay
+type RelayServedSettings struct {
+	// Enabled indicates that the end device sends its uplinks to the relay
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The relay that serves the end device
+	RelayAppEUI types.AppEUI `json:"relay_app_eui"`
+	RelayDevEUI types.DevEUI `json:"relay_dev_eui"`
+
+	// Settings that are sent in an EndDeviceConfReq. The second channel is the one of the relay.
+	Mode             int `json:"mode,omitempty"`               // 0 is always, 1 is dynamic, 2 is end device controlled
+	SmartEnableLevel int `json:"smart_enable_level,omitempty"` // the index of the number of uplinks without downlink before the relay mode is enabled
+	Backoff          int `json:"backoff,omitempty"`            // the number of uplinks without ack before the end device stops using the relay
+
+	// Limit on the uplinks of the end device that the relay forwards, sent to the relay in an UpdateUplinkListReq
+	ReloadRate int `json:"reload_rate,omitempty"` // the number of uplinks per hour (at most 62, 63 is no limit)
+	BucketSize int `json:"bucket_size,omitempty"` // the index of the multiplier of the reload rate (1, 2, 4 or 12)
+
+	// Indicates whether the NetworkServer should send an EndDeviceConfReq when possible
+	SendConfReq bool `json:"send_conf_req,omitempty"`
+
+	// Rejection contains the settings of the last EndDeviceConfReq that the end device rejected
+	Rejection string `json:"rejection,omitempty"`
+}
+
+// RelayLimits limit the uplinks that a relay forwards. The reload rates are
+// the number of uplinks per hour (at most 126, 127 is no limit), the bucket
+// size is the index of the multiplier of the reload rates (1, 2, 4 or 12).
+type RelayLimits struct {
+	JoinRequests  int `json:"join_requests"`
+	Notifications int `json:"notifications"`
+	GlobalUplinks int `json:"global_uplinks"`
+	Overall       int `json:"overall"`
+	BucketSize    int `json:"bucket_size,omitempty"`
+}
+
+// RelayEndDevice is an end device that a relay notified the NetworkServer of
+type RelayEndDevice struct {
+	DevAddr    types.DevAddr `json:"dev_addr"`
+	RSSI       int32         `json:"rssi"`
+	SNR        int32         `json:"snr"`
+	NotifiedAt time.Time     `json:"notified_at"`
+}
+
+// PendingMACCommand is a MAC command that was sent to the device, but that was not answered yet
+type PendingMACCommand struct {
+	CID      uint32    `json:"cid"`
+	Payload  []byte    `json:"payload,omitempty"`
+	FCntDown uint32    `json:"f_cnt_down"` // FCntDown of the last downlink that contained the command
+	SentAt   time.Time `json:"sent_at"`    // Time of the last downlink that contained the command
+	Attempts int       `json:"attempts"`   // Number of downlinks that contained the command
+	Expired  bool      `json:"expired,omitempty"`
 }
 
 // StartUpdate stores the state of the device
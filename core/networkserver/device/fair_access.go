@@ -0,0 +1,68 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import "time"
+
+// FairAccessWindow is the rolling window in which the usage of a device is counted
+const FairAccessWindow = 24 * time.Hour
+
+// FairAccessUsage contains the usage of the network by a device in one hour
+type FairAccessUsage struct {
+	Hour          time.Time     `json:"hour"`
+	UplinkAirtime time.Duration `json:"uplink_airtime,omitempty"`
+	Downlinks     int           `json:"downlinks,omitempty"`
+}
+
+// FairAccess contains the usage of the network by a device in the FairAccessWindow
+// and whether the device violates the fair access policy
+type FairAccess struct {
+	Usage []FairAccessUsage `json:"usage,omitempty"`
+
+	// Violation is set when the device exceeds the fair access policy
+	Violation      string    `json:"violation,omitempty"`
+	ViolationSince time.Time `json:"violation_since,omitempty"`
+}
+
+func (f *FairAccess) current(t time.Time) *FairAccessUsage {
+	hour := t.UTC().Truncate(time.Hour)
+	f.prune(t)
+	if len(f.Usage) == 0 || !f.Usage[len(f.Usage)-1].Hour.Equal(hour) {
+		f.Usage = append(f.Usage, FairAccessUsage{Hour: hour})
+	}
+	return &f.Usage[len(f.Usage)-1]
+}
+
+// prune removes the usage that is outside the window
+func (f *FairAccess) prune(t time.Time) {
+	start := t.UTC().Truncate(time.Hour).Add(-FairAccessWindow)
+	var i int
+	for i < len(f.Usage) && !f.Usage[i].Hour.After(start) {
+		i++
+	}
+	f.Usage = f.Usage[i:]
+}
+
+// AddUplink adds the airtime of an uplink message that was received at the given time
+func (f *FairAccess) AddUplink(t time.Time, airtime time.Duration) {
+	f.current(t).UplinkAirtime += airtime
+}
+
+// AddDownlink adds a downlink message that was sent at the given time
+func (f *FairAccess) AddDownlink(t time.Time) {
+	f.current(t).Downlinks++
+}
+
+// Get the total uplink airtime and the number of downlinks in the window that ends at the given time
+func (f FairAccess) Get(t time.Time) (uplinkAirtime time.Duration, downlinks int) {
+	start := t.UTC().Truncate(time.Hour).Add(-FairAccessWindow)
+	for _, usage := range f.Usage {
+		if !usage.Hour.After(start) {
+			continue
+		}
+		uplinkAirtime += usage.UplinkAirtime
+		downlinks += usage.Downlinks
+	}
+	return
+}
@@ -0,0 +1,42 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestFairAccess(t *testing.T) {
+	a := New(t)
+
+	start := time.Date(2017, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+	var fairAccess FairAccess
+	fairAccess.AddUplink(start, time.Second)
+	fairAccess.AddUplink(start.Add(10*time.Minute), time.Second)
+	fairAccess.AddDownlink(start.Add(20 * time.Minute))
+	a.So(fairAccess.Usage, ShouldHaveLength, 1)
+
+	fairAccess.AddUplink(start.Add(time.Hour), 2*time.Second)
+	a.So(fairAccess.Usage, ShouldHaveLength, 2)
+
+	uplinkAirtime, downlinks := fairAccess.Get(start.Add(time.Hour))
+	a.So(uplinkAirtime, ShouldEqual, 4*time.Second)
+	a.So(downlinks, ShouldEqual, 1)
+
+	// After 24 hours, the first hour is no longer in the window
+	uplinkAirtime, downlinks = fairAccess.Get(start.Add(24 * time.Hour))
+	a.So(uplinkAirtime, ShouldEqual, 2*time.Second)
+	a.So(downlinks, ShouldEqual, 0)
+
+	// Usage outside the window is removed
+	fairAccess.AddUplink(start.Add(24*time.Hour), time.Second)
+	a.So(fairAccess.Usage, ShouldHaveLength, 2)
+
+	uplinkAirtime, _ = fairAccess.Get(start.Add(48 * time.Hour))
+	a.So(uplinkAirtime, ShouldEqual, 0)
+}
@@ -6,6 +6,7 @@ package device
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/TheThingsNetwork/ttn/core/storage"
 	"github.com/TheThingsNetwork/ttn/core/types"
@@ -13,6 +14,8 @@ import (
 
 // FrameHistory for a device
 type FrameHistory interface {
+	// Configure sets the depth and retention of the history
+	Configure(settings FrameHistorySettings)
 	Push(frame *Frame) error
 	Get() ([]*Frame, error)
 	Clear() error
@@ -20,25 +23,56 @@ type FrameHistory interface {
 
 // RedisFrameHistory implements the frame history in Redis
 type RedisFrameHistory struct {
-	appEUI types.AppEUI
-	devEUI types.DevEUI
-	store  *storage.RedisQueueStore
+	appEUI   types.AppEUI
+	devEUI   types.DevEUI
+	store    *storage.RedisQueueStore
+	settings FrameHistorySettings
 }
 
-// FramesHistorySize for ADR
+// FramesHistorySize for ADR, unless the device has a different depth
 const FramesHistorySize = 20
 
-// Frame collected for ADR
+// FrameHistorySettings configure the frame history of a device
+type FrameHistorySettings struct {
+	// Depth is the number of frames in the history (FramesHistorySize if zero)
+	Depth int `json:"depth,omitempty"`
+	// TTL is the time after which frames are removed from the history (no expiry if zero)
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// GetDepth returns the depth of the history
+func (s FrameHistorySettings) GetDepth() int {
+	if s.Depth == 0 {
+		return FramesHistorySize
+	}
+	return s.Depth
+}
+
+// expired returns whether the frame is older than the TTL. Frames without time never expire.
+func (s FrameHistorySettings) expired(frame *Frame, now time.Time) bool {
+	return s.TTL > 0 && !frame.Time.IsZero() && now.Sub(frame.Time) > s.TTL
+}
+
+// Frame collected for ADR. The SNR and RSSI are those of the gateway that
+// received the frame best.
 type Frame struct {
-	FCnt         uint32  `json:"f_cnt"`
-	SNR          float32 `json:"snr"`
-	GatewayCount uint32  `json:"gw_cnt"`
+	FCnt         uint32    `json:"f_cnt"`
+	SNR          float32   `json:"snr"`
+	RSSI         float32   `json:"rssi,omitempty"`
+	DataRate     string    `json:"data_rate,omitempty"`
+	GatewayCount uint32    `json:"gw_cnt"`
+	Time         time.Time `json:"time,omitempty"`
 }
 
 func (s *RedisFrameHistory) key() string {
 	return fmt.Sprintf("%s:%s", s.appEUI, s.devEUI)
 }
 
+// Configure the depth and retention of the device's history
+func (s *RedisFrameHistory) Configure(settings FrameHistorySettings) {
+	s.settings = settings
+}
+
 // Push a Frame to the device's history
 func (s *RedisFrameHistory) Push(frame *Frame) error {
 	frameBytes, err := json.Marshal(frame)
@@ -48,17 +82,28 @@ func (s *RedisFrameHistory) Push(frame *Frame) error {
 	if err := s.store.AddFront(s.key(), string(frameBytes)); err != nil {
 		return err
 	}
+	if s.settings.TTL > 0 {
+		if err := s.store.Expire(s.key(), s.settings.TTL); err != nil {
+			return err
+		}
+	} else if err := s.store.Persist(s.key()); err != nil {
+		return err // The TTL may have been removed from the settings
+	}
 	return s.Trim()
 }
 
-// Get the last frames from the device's history
+// Get the last frames from the device's history, without the frames that expired
 func (s *RedisFrameHistory) Get() (out []*Frame, err error) {
-	frames, err := s.store.GetFront(s.key(), FramesHistorySize)
+	frames, err := s.store.GetFront(s.key(), s.settings.GetDepth())
+	now := time.Now()
 	for _, frameStr := range frames {
 		frame := new(Frame)
 		if err := json.Unmarshal([]byte(frameStr), frame); err != nil {
 			return nil, err
 		}
+		if s.settings.expired(frame, now) {
+			break // The older frames expired as well
+		}
 		out = append(out, frame)
 	}
 	return
@@ -66,7 +111,7 @@ func (s *RedisFrameHistory) Get() (out []*Frame, err error) {
 
 // Trim frames in the device's history
 func (s *RedisFrameHistory) Trim() error {
-	return s.store.Trim(s.key(), FramesHistorySize)
+	return s.store.Trim(s.key(), s.settings.GetDepth())
 }
 
 // Clear frames in the device's history
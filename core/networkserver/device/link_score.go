@@ -0,0 +1,114 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"math"
+	"time"
+)
+
+// LinkScoreHorizons are the numbers of frames over which the frame loss of a device is averaged
+var LinkScoreHorizons = [3]int{20, 100, 1000}
+
+// linkScoreMarginHorizon is the number of frames over which the link margin is averaged
+const linkScoreMarginHorizon = 100
+
+// Link quality levels of the LinkScore
+const (
+	LinkQualityGood = "good"
+	LinkQualityFair = "fair"
+	LinkQualityPoor = "poor"
+)
+
+// LinkScore contains the frame loss and link margin of a device, averaged
+// over longer horizons than the frame history, and a quality score that is
+// derived from them
+type LinkScore struct {
+	// Frames and Missed are the numbers of uplinks that were received and missed
+	Frames uint64 `json:"frames"`
+	Missed uint64 `json:"missed"`
+	// Loss contains the moving average of the frame loss (0-1) over each of the LinkScoreHorizons
+	Loss [3]float64 `json:"loss"`
+	// Margin is the moving average of the SNR above the demodulation floor.
+	// It is nil if the data rates of the device have no known floor.
+	Margin *float64 `json:"margin,omitempty"`
+
+	// Score is a number from 0 (no link) to 100 (perfect link)
+	Score   int    `json:"score"`
+	Quality string `json:"quality"`
+
+	LastFCnt  uint32    `json:"last_f_cnt"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// average adds x to the moving average over the horizon, of which n values are already in the average
+func average(avg float64, x float64, horizon int, n uint64) float64 {
+	weight := 1 / float64(horizon)
+	if n < uint64(horizon) {
+		weight = 1 / float64(n+1)
+	}
+	return avg + weight*(x-avg)
+}
+
+// AddFrame adds an uplink with the given FCnt to the score and returns the
+// number of uplinks that were missed before it. The margin is nil if the
+// data rate has no known demodulation floor. Uplinks with an FCnt that is
+// lower than the previous one (such as after a new activation) count as
+// received without gap.
+func (s *LinkScore) AddFrame(fCnt uint32, margin *float32, t time.Time) (missed uint32) {
+	if s.Frames > 0 && fCnt > s.LastFCnt {
+		missed = fCnt - s.LastFCnt - 1
+	}
+
+	// The averages over the longest horizon are the same after that many missed frames
+	n := s.Frames + s.Missed
+	for i := uint32(0); i < missed && i < uint32(LinkScoreHorizons[2]); i++ {
+		for j, horizon := range LinkScoreHorizons {
+			s.Loss[j] = average(s.Loss[j], 1, horizon, n)
+		}
+		n++
+	}
+	for j, horizon := range LinkScoreHorizons {
+		s.Loss[j] = average(s.Loss[j], 0, horizon, n)
+	}
+
+	if margin != nil {
+		if s.Margin == nil {
+			s.Margin = new(float64)
+			*s.Margin = float64(*margin)
+		} else {
+			*s.Margin = average(*s.Margin, float64(*margin), linkScoreMarginHorizon, s.Frames)
+		}
+	}
+
+	s.Frames++
+	s.Missed += uint64(missed)
+	s.LastFCnt = fCnt
+	s.UpdatedAt = t
+	s.Score = s.score()
+	s.Quality = linkQuality(s.Score)
+	return missed
+}
+
+// score combines the frame loss over 100 frames with the link margin. A
+// margin of 10 dB or more does not lower the score, a link without margin
+// halves it.
+func (s *LinkScore) score() int {
+	score := 100 * (1 - s.Loss[1])
+	if s.Margin != nil {
+		score *= math.Min(1, math.Max(0.5, 0.5+*s.Margin/20))
+	}
+	return int(math.Floor(score + .5))
+}
+
+func linkQuality(score int) string {
+	switch {
+	case score >= 80:
+		return LinkQualityGood
+	case score >= 50:
+		return LinkQualityFair
+	default:
+		return LinkQualityPoor
+	}
+}
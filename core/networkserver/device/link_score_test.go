@@ -0,0 +1,61 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestLinkScore(t *testing.T) {
+	a := New(t)
+	now := time.Now()
+	margin := float32(10)
+
+	var score LinkScore
+	a.So(score.AddFrame(10, &margin, now), ShouldEqual, 0)
+	a.So(score.Frames, ShouldEqual, 1)
+	a.So(score.Score, ShouldEqual, 100)
+	a.So(score.Quality, ShouldEqual, LinkQualityGood)
+	a.So(*score.Margin, ShouldEqual, 10)
+	a.So(score.UpdatedAt, ShouldResemble, now)
+
+	// 1 of 4 frames missed
+	a.So(score.AddFrame(12, &margin, now), ShouldEqual, 1)
+	a.So(score.AddFrame(13, &margin, now), ShouldEqual, 0)
+	a.So(score.Missed, ShouldEqual, 1)
+	a.So(score.Loss[0], ShouldAlmostEqual, 0.25)
+	a.So(score.Loss[2], ShouldAlmostEqual, 0.25)
+	a.So(score.Score, ShouldEqual, 75)
+	a.So(score.Quality, ShouldEqual, LinkQualityFair)
+
+	// A lower FCnt is not a gap
+	a.So(score.AddFrame(0, nil, now), ShouldEqual, 0)
+	a.So(score.Loss[0], ShouldAlmostEqual, 0.2)
+
+	// The loss over the shortest horizon recovers first
+	for fCnt := uint32(1); fCnt <= 100; fCnt++ {
+		score.AddFrame(fCnt, &margin, now)
+	}
+	a.So(score.Loss[0], ShouldBeLessThan, 0.01)
+	a.So(score.Loss[1], ShouldBeGreaterThan, score.Loss[0])
+	a.So(score.Loss[2], ShouldBeGreaterThan, score.Loss[1])
+	a.So(score.Quality, ShouldEqual, LinkQualityGood)
+
+	// A large gap makes the link poor
+	score.AddFrame(100000, &margin, now)
+	a.So(score.Missed, ShouldEqual, 1+99899)
+	a.So(score.Loss[2], ShouldBeGreaterThan, 0.5)
+	a.So(score.Quality, ShouldEqual, LinkQualityPoor)
+
+	// A link without margin halves the score
+	noMargin := float32(-5)
+	score = LinkScore{}
+	for fCnt := uint32(0); fCnt < 10; fCnt++ {
+		score.AddFrame(fCnt, &noMargin, now)
+	}
+	a.So(score.Score, ShouldEqual, 50)
+}
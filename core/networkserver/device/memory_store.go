@@ -0,0 +1,204 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// NewMemoryDeviceStore creates a new in-memory Device store. It can be used
+// in tests or when embedding the NetworkServer without Redis.
+func NewMemoryDeviceStore() Store {
+	return &MemoryDeviceStore{
+		devices:    make(map[string]Device),
+		frames:     make(map[string][]*Frame),
+		adrHistory: make(map[string][]*ADRDecision),
+	}
+}
+
+// MemoryDeviceStore stores Devices in memory
+type MemoryDeviceStore struct {
+	mu      sync.RWMutex
+	devices map[string]Device
+	frames  map[string][]*Frame
+
+	adrHistory map[string][]*ADRDecision
+}
+
+func (s *MemoryDeviceStore) sortedKeys() []string {
+	keys := make([]string, 0, len(s.devices))
+	for key := range s.devices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// List all Devices
+func (s *MemoryDeviceStore) List(opts *storage.ListOptions) ([]*Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := storage.SelectKeys(s.sortedKeys(), opts)
+	devices := make([]*Device, 0, len(keys))
+	for _, key := range keys {
+		device := s.devices[key]
+		devices = append(devices, &device)
+	}
+	return devices, nil
+}
+
+// ListForAddress lists all devices for a specific DevAddr
+func (s *MemoryDeviceStore) ListForAddress(devAddr types.DevAddr) ([]*Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var devices []*Device
+	for _, key := range s.sortedKeys() {
+		device := s.devices[key]
+		if device.DevAddr == devAddr {
+			devices = append(devices, &device)
+		}
+	}
+	return devices, nil
+}
+
+// Get a specific Device
+func (s *MemoryDeviceStore) Get(appEUI types.AppEUI, devEUI types.DevEUI) (*Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key := fmt.Sprintf("%s:%s", appEUI, devEUI)
+	device, ok := s.devices[key]
+	if !ok {
+		return nil, errors.NewErrNotFound(key)
+	}
+	return &device, nil
+}
+
+// Set a new Device or update an existing one
+func (s *MemoryDeviceStore) Set(new *Device, properties ...string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	new.UpdatedAt = now
+
+	key := fmt.Sprintf("%s:%s", new.AppEUI, new.DevEUI)
+	_, exists := s.devices[key]
+	if new.old != nil {
+		// The device may be updated with a new AppEUI or DevEUI
+		oldKey := fmt.Sprintf("%s:%s", new.old.AppEUI, new.old.DevEUI)
+		if _, ok := s.devices[oldKey]; !ok {
+			return errors.NewErrNotFound(oldKey)
+		}
+		if oldKey != key {
+			if exists {
+				return errors.NewErrAlreadyExists(key)
+			}
+			delete(s.devices, oldKey)
+			if frames, ok := s.frames[oldKey]; ok {
+				s.frames[key] = frames
+				delete(s.frames, oldKey)
+			}
+			if decisions, ok := s.adrHistory[oldKey]; ok {
+				s.adrHistory[key] = decisions
+				delete(s.adrHistory, oldKey)
+			}
+		}
+	} else {
+		if exists {
+			return errors.NewErrAlreadyExists(key)
+		}
+		new.CreatedAt = now
+	}
+
+	stored := *new
+	stored.old = nil
+	s.devices[key] = stored
+
+	return nil
+}
+
+// Delete a Device
+func (s *MemoryDeviceStore) Delete(appEUI types.AppEUI, devEUI types.DevEUI) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("%s:%s", appEUI, devEUI)
+	if _, ok := s.devices[key]; !ok {
+		return errors.NewErrNotFound(key)
+	}
+	delete(s.devices, key)
+	delete(s.frames, key)
+	delete(s.adrHistory, key)
+	return nil
+}
+
+// Frames history for a specific Device
+func (s *MemoryDeviceStore) Frames(appEUI types.AppEUI, devEUI types.DevEUI) (FrameHistory, error) {
+	return &MemoryFrameHistory{
+		key:   fmt.Sprintf("%s:%s", appEUI, devEUI),
+		store: s,
+	}, nil
+}
+
+// ADRHistory for a specific Device
+func (s *MemoryDeviceStore) ADRHistory(appEUI types.AppEUI, devEUI types.DevEUI) (ADRHistory, error) {
+	return &MemoryADRHistory{
+		key:   fmt.Sprintf("%s:%s", appEUI, devEUI),
+		store: s,
+	}, nil
+}
+
+// MemoryFrameHistory implements the frame history in memory
+type MemoryFrameHistory struct {
+	key      string
+	store    *MemoryDeviceStore
+	settings FrameHistorySettings
+}
+
+// Configure the depth and retention of the device's history
+func (s *MemoryFrameHistory) Configure(settings FrameHistorySettings) {
+	s.settings = settings
+}
+
+// Push a Frame to the device's history
+func (s *MemoryFrameHistory) Push(frame *Frame) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	stored := *frame
+	frames := append([]*Frame{&stored}, s.store.frames[s.key]...)
+	if depth := s.settings.GetDepth(); len(frames) > depth {
+		frames = frames[:depth]
+	}
+	s.store.frames[s.key] = frames
+	return nil
+}
+
+// Get the last frames from the device's history, without the frames that expired
+func (s *MemoryFrameHistory) Get() (out []*Frame, err error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+	now := time.Now()
+	for i, frame := range s.store.frames[s.key] {
+		if i == s.settings.GetDepth() || s.settings.expired(frame, now) {
+			break
+		}
+		frame := *frame
+		out = append(out, &frame)
+	}
+	return
+}
+
+// Clear frames in the device's history
+func (s *MemoryFrameHistory) Clear() error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	delete(s.store.frames, s.key)
+	return nil
+}
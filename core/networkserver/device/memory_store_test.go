@@ -0,0 +1,147 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/storage"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestMemoryDeviceStore(t *testing.T) {
+	a := New(t)
+
+	s := NewMemoryDeviceStore()
+
+	appEUI := types.AppEUI{0, 0, 0, 0, 0, 0, 0, 1}
+
+	err := s.Set(&Device{
+		DevAddr: types.DevAddr{0, 0, 0, 1},
+		DevEUI:  types.DevEUI{0, 0, 0, 0, 0, 0, 0, 1},
+		AppEUI:  appEUI,
+	})
+	a.So(err, ShouldBeNil)
+
+	err = s.Set(&Device{
+		DevAddr: types.DevAddr{0, 0, 0, 1},
+		DevEUI:  types.DevEUI{0, 0, 0, 0, 0, 0, 0, 2},
+		AppEUI:  appEUI,
+	})
+	a.So(err, ShouldBeNil)
+
+	// Creating the same device twice fails
+	err = s.Set(&Device{
+		DevEUI: types.DevEUI{0, 0, 0, 0, 0, 0, 0, 2},
+		AppEUI: appEUI,
+	})
+	a.So(err, ShouldNotBeNil)
+
+	res, err := s.ListForAddress(types.DevAddr{0, 0, 0, 1})
+	a.So(err, ShouldBeNil)
+	a.So(res, ShouldHaveLength, 2)
+
+	dev, err := s.Get(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 2})
+	a.So(err, ShouldBeNil)
+	a.So(dev.CreatedAt.IsZero(), ShouldBeFalse)
+
+	// Returned devices are copies
+	dev.FCntUp = 42
+	stored, _ := s.Get(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 2})
+	a.So(stored.FCntUp, ShouldEqual, 0)
+
+	// Update
+	dev.StartUpdate()
+	dev.DevAddr = types.DevAddr{0, 0, 0, 3}
+	a.So(s.Set(dev), ShouldBeNil)
+
+	res, err = s.ListForAddress(types.DevAddr{0, 0, 0, 1})
+	a.So(err, ShouldBeNil)
+	a.So(res, ShouldHaveLength, 1)
+	res, err = s.ListForAddress(types.DevAddr{0, 0, 0, 3})
+	a.So(err, ShouldBeNil)
+	a.So(res, ShouldHaveLength, 1)
+	a.So(res[0].FCntUp, ShouldEqual, 42)
+
+	// Update the DevEUI
+	frames, _ := s.Frames(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 2})
+	frames.Push(&Frame{FCnt: 42})
+	dev, _ = s.Get(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 2})
+	dev.StartUpdate()
+	dev.DevEUI = types.DevEUI{0, 0, 0, 0, 0, 0, 0, 4}
+	a.So(s.Set(dev), ShouldBeNil)
+	_, err = s.Get(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 2})
+	a.So(err, ShouldNotBeNil)
+	dev, err = s.Get(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 4})
+	a.So(err, ShouldBeNil)
+	a.So(dev.FCntUp, ShouldEqual, 42)
+	frames, _ = s.Frames(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 4})
+	history, _ := frames.Get()
+	a.So(history, ShouldHaveLength, 1)
+
+	// The DevEUI can not be changed to that of another device
+	dev.StartUpdate()
+	dev.DevEUI = types.DevEUI{0, 0, 0, 0, 0, 0, 0, 1}
+	a.So(s.Set(dev), ShouldNotBeNil)
+
+	// List
+	devices, err := s.List(nil)
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 2)
+
+	devices, err = s.List(&storage.ListOptions{Limit: 1})
+	a.So(err, ShouldBeNil)
+	a.So(devices, ShouldHaveLength, 1)
+
+	// Delete
+	a.So(s.Delete(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 1}), ShouldBeNil)
+	a.So(s.Delete(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 1}), ShouldNotBeNil)
+
+	dev, err = s.Get(appEUI, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 1})
+	a.So(err, ShouldNotBeNil)
+	a.So(dev, ShouldBeNil)
+}
+
+func TestMemoryFramesStore(t *testing.T) {
+	a := New(t)
+	store := NewMemoryDeviceStore()
+
+	s, err := store.Frames(types.AppEUI{0, 0, 0, 0, 0, 0, 0, 1}, types.DevEUI{0, 0, 0, 0, 0, 0, 0, 1})
+	a.So(err, ShouldBeNil)
+
+	for i := 0; i < 25; i++ {
+		a.So(s.Push(&Frame{GatewayCount: uint32(i + 1)}), ShouldBeNil)
+	}
+
+	frames, err := s.Get()
+	a.So(err, ShouldBeNil)
+	a.So(frames, ShouldHaveLength, 20)
+	a.So(frames[0].GatewayCount, ShouldEqual, 25)
+
+	a.So(s.Clear(), ShouldBeNil)
+
+	frames, err = s.Get()
+	a.So(err, ShouldBeNil)
+	a.So(frames, ShouldBeEmpty)
+
+	// Deeper history
+	s.Configure(FrameHistorySettings{Depth: 50})
+	for i := 0; i < 60; i++ {
+		a.So(s.Push(&Frame{GatewayCount: uint32(i + 1)}), ShouldBeNil)
+	}
+	frames, _ = s.Get()
+	a.So(frames, ShouldHaveLength, 50)
+
+	// Frames older than the TTL are not returned
+	s.Configure(FrameHistorySettings{Depth: 50, TTL: time.Hour})
+	a.So(s.Clear(), ShouldBeNil)
+	a.So(s.Push(&Frame{FCnt: 1, Time: time.Now().Add(-2 * time.Hour)}), ShouldBeNil)
+	a.So(s.Push(&Frame{FCnt: 2, Time: time.Now().Add(-time.Minute)}), ShouldBeNil)
+	a.So(s.Push(&Frame{FCnt: 3}), ShouldBeNil)
+	frames, _ = s.Get()
+	a.So(frames, ShouldHaveLength, 2)
+	a.So(frames[1].FCnt, ShouldEqual, 2)
+}
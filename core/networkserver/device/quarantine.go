@@ -0,0 +1,18 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package device
+
+import "time"
+
+// Quarantine is set when a device is suspected to be under attack. A
+// quarantined device does not get downlinks until the quarantine is cleared.
+type Quarantine struct {
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since,omitempty"`
+}
+
+// Active returns true if the device is quarantined
+func (q Quarantine) Active() bool {
+	return q.Reason != ""
+}
@@ -22,6 +22,7 @@ type Store interface {
 	Set(new *Device, properties ...string) (err error)
 	Delete(appEUI types.AppEUI, devEUI types.DevEUI) error
 	Frames(appEUI types.AppEUI, devEUI types.DevEUI) (FrameHistory, error)
+	ADRHistory(appEUI types.AppEUI, devEUI types.DevEUI) (ADRHistory, error)
 }
 
 const defaultRedisPrefix = "ns"
@@ -29,6 +30,7 @@ const defaultRedisPrefix = "ns"
 const redisDevicePrefix = "device"
 const redisDevAddrPrefix = "dev_addr"
 const redisFramesPrefix = "frames"
+const redisADRHistoryPrefix = "adr_history"
 
 // NewRedisDeviceStore creates a new Redis-based status store
 func NewRedisDeviceStore(client *redis.Client, prefix string) Store {
@@ -46,6 +48,7 @@ func NewRedisDeviceStore(client *redis.Client, prefix string) Store {
 		prefix:       prefix,
 		store:        store,
 		frameStore:   frameStore,
+		adrStore:     storage.NewRedisQueueStore(client, prefix+":"+redisADRHistoryPrefix),
 		devAddrIndex: storage.NewRedisSetStore(client, prefix+":"+redisDevAddrPrefix),
 	}
 }
@@ -58,6 +61,7 @@ type RedisDeviceStore struct {
 	prefix       string
 	store        *storage.RedisMapStore
 	frameStore   *storage.RedisQueueStore
+	adrStore     *storage.RedisQueueStore
 	devAddrIndex *storage.RedisSetStore
 }
 
@@ -167,6 +171,14 @@ func (s *RedisDeviceStore) Delete(appEUI types.AppEUI, devEUI types.DevEUI) erro
 		}
 	}
 
+	if err := s.frameStore.Delete(key); err != nil {
+		return err
+	}
+
+	if err := s.adrStore.Delete(key); err != nil {
+		return err
+	}
+
 	return s.store.Delete(key)
 }
 
@@ -178,3 +190,12 @@ func (s *RedisDeviceStore) Frames(appEUI types.AppEUI, devEUI types.DevEUI) (Fra
 		store:  s.frameStore,
 	}, nil
 }
+
+// ADRHistory for a specific Device
+func (s *RedisDeviceStore) ADRHistory(appEUI types.AppEUI, devEUI types.DevEUI) (ADRHistory, error) {
+	return &RedisADRHistory{
+		appEUI: appEUI,
+		devEUI: devEUI,
+		store:  s.adrStore,
+	}, nil
+}
@@ -0,0 +1,66 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"encoding/binary"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+)
+
+// deviceTimeCID is the CID of the DeviceTimeReq and DeviceTimeAns MAC commands (LoRaWAN 1.0.3)
+const deviceTimeCID = 0x0D
+
+// gpsEpoch is the start of GPS time
+var gpsEpoch = time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+// gpsLeapSeconds is the number of leap seconds between GPS time and UTC (since 2017-01-01)
+const gpsLeapSeconds = 18 * time.Second
+
+// toGPSTime returns the time since the GPS epoch
+func toGPSTime(t time.Time) time.Duration {
+	return t.Sub(gpsEpoch) + gpsLeapSeconds
+}
+
+// buildDeviceTimeAns builds the payload of a DeviceTimeAns: the seconds since
+// the GPS epoch (4 bytes, little endian) followed by the fractional second in
+// steps of 1/256 second
+func buildDeviceTimeAns(gpsTime time.Duration) []byte {
+	payload := make([]byte, 5)
+	binary.LittleEndian.PutUint32(payload[:4], uint32(gpsTime/time.Second))
+	payload[4] = uint8((gpsTime % time.Second) * 256 / time.Second)
+	return payload
+}
+
+// uplinkTime returns the time at which the uplink was received. If one of the
+// gateways that received the uplink is GPS-synchronized, its time is used and
+// accurate is true. Otherwise the time of the server is used.
+func uplinkTime(message *pb_broker.DeduplicatedUplinkMessage) (t time.Time, accurate bool) {
+	for _, md := range message.GetGatewayMetadata() {
+		if md.Time != 0 && md.GetGps().GetTime() != 0 {
+			return time.Unix(0, md.Time), true
+		}
+	}
+	if message.ServerTime != 0 {
+		return time.Unix(0, message.ServerTime), false
+	}
+	return time.Now(), false
+}
+
+// handleDeviceTimeReq answers a DeviceTimeReq with the time at which the uplink was received
+func (n *networkServer) handleDeviceTimeReq(message *pb_broker.DeduplicatedUplinkMessage) {
+	lorawanDownlinkMac := message.GetResponseTemplate().GetMessage().GetLorawan().GetMacPayload()
+	if lorawanDownlinkMac == nil {
+		return
+	}
+	t, accurate := uplinkTime(message)
+	lorawanDownlinkMac.FOpts = append(lorawanDownlinkMac.FOpts, pb_lorawan.MACCommand{
+		Cid:     deviceTimeCID,
+		Payload: buildDeviceTimeAns(toGPSTime(t)),
+	})
+	message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, "device-time", "gps", accurate)
+}
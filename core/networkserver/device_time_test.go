@@ -0,0 +1,78 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestToGPSTime(t *testing.T) {
+	a := New(t)
+	a.So(toGPSTime(gpsEpoch), ShouldEqual, 18*time.Second)
+	a.So(toGPSTime(time.Date(2017, time.March, 1, 12, 0, 0, 0, time.UTC))/time.Second, ShouldEqual, 1172404818)
+}
+
+func TestBuildDeviceTimeAns(t *testing.T) {
+	a := New(t)
+	a.So(buildDeviceTimeAns(0), ShouldResemble, []byte{0, 0, 0, 0, 0})
+	a.So(buildDeviceTimeAns(0x01020304*time.Second), ShouldResemble, []byte{0x04, 0x03, 0x02, 0x01, 0})
+
+	// Fractional seconds are encoded in steps of 1/256 second
+	a.So(buildDeviceTimeAns(10*time.Second+500*time.Millisecond), ShouldResemble, []byte{10, 0, 0, 0, 128})
+	a.So(buildDeviceTimeAns(10*time.Second+250*time.Millisecond), ShouldResemble, []byte{10, 0, 0, 0, 64})
+	a.So(buildDeviceTimeAns(10*time.Second+time.Second/256), ShouldResemble, []byte{10, 0, 0, 0, 1})
+	a.So(buildDeviceTimeAns(10*time.Second+time.Second/256-1), ShouldResemble, []byte{10, 0, 0, 0, 0})
+	a.So(buildDeviceTimeAns(11*time.Second-1), ShouldResemble, []byte{10, 0, 0, 0, 255})
+}
+
+func TestHandleDeviceTimeReq(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleDeviceTimeReq")},
+	}
+
+	gatewayTime := time.Date(2017, time.March, 1, 12, 0, 0, 500000000, time.UTC)
+	serverTime := gatewayTime.Add(200 * time.Millisecond)
+
+	build := func(md ...*pb_gateway.RxMetadata) *pb_broker.DeduplicatedUplinkMessage {
+		message := adrInitUplinkMessage()
+		message.GatewayMetadata = md
+		message.ServerTime = serverTime.UnixNano()
+		message.ResponseTemplate = adrInitDownlinkMessage()
+		return message
+	}
+
+	// Time of a GPS-synchronized gateway
+	{
+		message := build(
+			&pb_gateway.RxMetadata{Time: gatewayTime.Add(time.Second).UnixNano()},
+			&pb_gateway.RxMetadata{Time: gatewayTime.UnixNano(), Gps: &pb_gateway.GPSMetadata{Time: gatewayTime.UnixNano()}},
+		)
+		tm, accurate := uplinkTime(message)
+		a.So(accurate, ShouldBeTrue)
+		a.So(tm.Equal(gatewayTime), ShouldBeTrue)
+
+		ns.handleDeviceTimeReq(message)
+		fOpts := message.ResponseTemplate.Message.GetLorawan().GetMacPayload().FOpts
+		a.So(fOpts, ShouldHaveLength, 1)
+		a.So(fOpts[0].Cid, ShouldEqual, deviceTimeCID)
+		a.So(fOpts[0].Payload, ShouldResemble, buildDeviceTimeAns(toGPSTime(gatewayTime)))
+		a.So(fOpts[0].Payload[4], ShouldEqual, 128)
+	}
+
+	// Time of the server
+	{
+		message := build(&pb_gateway.RxMetadata{Time: gatewayTime.UnixNano()})
+		tm, accurate := uplinkTime(message)
+		a.So(accurate, ShouldBeFalse)
+		a.So(tm.Equal(serverTime), ShouldBeTrue)
+	}
+}
@@ -4,8 +4,12 @@
 package networkserver
 
 import (
+	"fmt"
+	"time"
+
 	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
 	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/brocaar/lorawan"
 )
@@ -32,6 +36,10 @@ func (n *networkServer) HandleDownlink(message *pb_broker.DownlinkMessage) (*pb_
 		return nil, errors.NewErrInvalidArgument("Downlink", "AppID and DevID do not match AppEUI and DevEUI")
 	}
 
+	if dev.Quarantine.Active() {
+		return nil, errors.NewErrPermissionDenied(fmt.Sprintf("Device is quarantined: %s", dev.Quarantine.Reason))
+	}
+
 	message.Trace = message.Trace.WithEvent(trace.UpdateStateEvent)
 
 	dev.StartUpdate()
@@ -49,14 +57,37 @@ func (n *networkServer) HandleDownlink(message *pb_broker.DownlinkMessage) (*pb_
 		return nil, errors.NewErrInvalidArgument("Downlink", "DevAddr does not match device")
 	}
 
-	err = n.handleDownlinkMAC(message, dev)
-	if err != nil {
-		return nil, err
+	// MAC commands may already be added to the response template of the uplink
+	if !message.MacCommandsAdded {
+		err = n.handleDownlinkMAC(message, dev)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	n.handleDownlinkFairAccess(dev)
+
 	lorawanDownlinkMac.FCnt = dev.FCntDown // Use full 32-bit FCnt for setting MIC
 	dev.FCntDown++                         // TODO: For confirmed downlink, FCntDown should be incremented AFTER ACK
 
+	err = n.handleDownlinkMACPlacement(message, dev)
+	if err != nil {
+		return nil, err
+	}
+
+	err = n.encryptRelayDownlink(message, dev)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lorawanDownlinkMac.FOpts) > 0 || (lorawanDownlinkMac.FPort == 0 && len(lorawanDownlinkMac.FrmPayload) > 0) {
+		// Downlinks with MAC commands take precedence over other downlinks at the gateway
+		message.Priority = string(types.DownlinkPriorityMAC)
+		if lorawanDownlinkMac.FPort == 0 || len(lorawanDownlinkMac.FrmPayload) == 0 {
+			dev.MACDownlinks.LastSent = time.Now()
+		}
+	}
+
 	phyPayload := message.Message.GetLorawan().PHYPayload()
 	phyPayload.SetMIC(lorawan.AES128Key(dev.NwkSKey))
 	bytes, err := phyPayload.MarshalBinary()
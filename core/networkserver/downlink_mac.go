@@ -12,5 +12,13 @@ func (n *networkServer) handleDownlinkMAC(message *pb_broker.DownlinkMessage, de
 	if err := n.handleDownlinkADR(message, dev); err != nil {
 		return err
 	}
+	if err := n.handleDownlinkStaticADR(message, dev); err != nil {
+		return err
+	}
+	if err := n.handleDownlinkTxParams(message, dev); err != nil {
+		return err
+	}
+	n.handleDownlinkRelay(message, dev)
+	n.handleDownlinkPendingMAC(message, dev)
 	return nil
 }
@@ -97,6 +97,9 @@ func TestHandleDownlink(t *testing.T) {
 	a.So(macPayload.FHDR.FCnt, ShouldEqual, 0)                // The first Frame counter is zero
 	a.So(phyPayload.MIC, ShouldNotEqual, [4]byte{0, 0, 0, 0}) // MIC should be set, we'll check it with actual examples in the integration test
 
+	// Downlinks without MAC commands keep the priority of the application
+	a.So(res.Priority, ShouldBeEmpty)
+
 	dev, _ := ns.devices.Get(appEUI, devEUI)
 	a.So(dev.FCntDown, ShouldEqual, 1)
 
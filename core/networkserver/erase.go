@@ -0,0 +1,42 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
+)
+
+// eraseDeviceData removes the data that the NetworkServer collected about a
+// device: the frame history, the ADR history, the link score, the pending MAC
+// commands and the usage counters. The session of the device is kept, so that
+// it can continue to use the network.
+func (n *networkServer) eraseDeviceData(dev *device.Device) error {
+	frames, err := n.devices.Frames(dev.AppEUI, dev.DevEUI)
+	if err != nil {
+		return err
+	}
+	if err := frames.Clear(); err != nil {
+		return err
+	}
+
+	history, err := n.devices.ADRHistory(dev.AppEUI, dev.DevEUI)
+	if err != nil {
+		return err
+	}
+	if err := history.Clear(); err != nil {
+		return err
+	}
+
+	dev.StartUpdate()
+	dev.LastSeen = time.Time{}
+	dev.PendingMACCommands = nil
+	dev.UplinkAirtime = toa.Usage{}
+	dev.FairAccess = device.FairAccess{}
+	dev.LinkScore = device.LinkScore{}
+
+	return n.devices.Set(dev)
+}
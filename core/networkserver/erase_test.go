@@ -0,0 +1,58 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestEraseDeviceData(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	devEUI := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	dev := &device.Device{
+		AppEUI:             appEUI,
+		DevEUI:             devEUI,
+		DevAddr:            types.DevAddr{1, 2, 3, 4},
+		FCntUp:             42,
+		LastSeen:           time.Now(),
+		PendingMACCommands: []device.PendingMACCommand{{CID: 3}},
+	}
+	dev.UplinkAirtime.Add(time.Now(), time.Second)
+	dev.FairAccess.AddUplink(time.Now(), time.Second)
+	dev.LinkScore.AddFrame(42, nil, time.Now())
+	a.So(ns.devices.Set(dev), ShouldBeNil)
+
+	frames, _ := ns.devices.Frames(appEUI, devEUI)
+	frames.Push(&device.Frame{FCnt: 42})
+	history, _ := ns.devices.ADRHistory(appEUI, devEUI)
+	history.Push(&device.ADRDecision{DataRate: "SF7BW125"})
+
+	dev, _ = ns.devices.Get(appEUI, devEUI)
+	a.So(ns.eraseDeviceData(dev), ShouldBeNil)
+
+	dev, err := ns.devices.Get(appEUI, devEUI)
+	a.So(err, ShouldBeNil)
+	a.So(dev.FCntUp, ShouldEqual, 42)
+	a.So(dev.DevAddr, ShouldEqual, types.DevAddr{1, 2, 3, 4})
+	a.So(dev.LastSeen.IsZero(), ShouldBeTrue)
+	a.So(dev.PendingMACCommands, ShouldBeEmpty)
+	a.So(dev.UplinkAirtime.Messages, ShouldEqual, 0)
+	a.So(dev.FairAccess.Usage, ShouldBeEmpty)
+	a.So(dev.LinkScore.Frames, ShouldEqual, 0)
+
+	storedFrames, _ := frames.Get()
+	a.So(storedFrames, ShouldBeEmpty)
+	decisions, _ := history.Get()
+	a.So(decisions, ShouldBeEmpty)
+}
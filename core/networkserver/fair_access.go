@@ -0,0 +1,79 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+)
+
+// FairAccessPolicy limits the usage of the network by each device in a
+// rolling window of 24 hours. Zero values mean that there is no limit.
+type FairAccessPolicy struct {
+	UplinkAirtime time.Duration // Maximum uplink airtime
+	Downlinks     int           // Maximum number of downlinks
+}
+
+// check returns the violation of the policy, or an empty string if the usage does not violate the policy
+func (p FairAccessPolicy) check(usage device.FairAccess, t time.Time) string {
+	uplinkAirtime, downlinks := usage.Get(t)
+	switch {
+	case p.UplinkAirtime > 0 && uplinkAirtime > p.UplinkAirtime:
+		return fmt.Sprintf("uplink airtime of %s exceeds the maximum of %s", uplinkAirtime, p.UplinkAirtime)
+	case p.Downlinks > 0 && downlinks >= p.Downlinks:
+		return fmt.Sprintf("%d downlinks reached the maximum of %d", downlinks, p.Downlinks)
+	}
+	return ""
+}
+
+// UseFairAccessPolicy makes the NetworkServer enforce the fair access policy.
+// Devices that violate the policy do not get application downlinks until their
+// usage is within the limits again. Acks and MAC commands are still sent.
+func (n *networkServer) UseFairAccessPolicy(policy FairAccessPolicy) {
+	n.fairAccessPolicy = &policy
+}
+
+// handleUplinkFairAccess counts the uplink and adds the violation of the policy
+// to the message, so that the Handler withholds the application downlink
+func (n *networkServer) handleUplinkFairAccess(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device, airtime time.Duration) {
+	if n.fairAccessPolicy == nil {
+		return
+	}
+
+	now := time.Now()
+	dev.FairAccess.AddUplink(now, airtime)
+
+	violation := n.fairAccessPolicy.check(dev.FairAccess, now)
+	if violation == "" {
+		if dev.FairAccess.Violation != "" {
+			n.Ctx.WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).Info("Device no longer violates fair access policy")
+		}
+		dev.FairAccess.Violation = ""
+		dev.FairAccess.ViolationSince = time.Time{}
+		return
+	}
+
+	if dev.FairAccess.Violation == "" {
+		dev.FairAccess.ViolationSince = now
+		n.Ctx.WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).WithField("Violation", violation).Warn("Device violates fair access policy")
+	}
+	dev.FairAccess.Violation = violation
+
+	message.PolicyViolations = append(message.PolicyViolations, &pb_broker.PolicyViolation{
+		Policy:    "fair-access",
+		Violation: violation,
+		Since:     dev.FairAccess.ViolationSince.UnixNano(),
+	})
+}
+
+// handleDownlinkFairAccess counts the downlink
+func (n *networkServer) handleDownlinkFairAccess(dev *device.Device) {
+	if n.fairAccessPolicy == nil {
+		return
+	}
+	dev.FairAccess.AddDownlink(time.Now())
+}
@@ -0,0 +1,79 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestFairAccessPolicyCheck(t *testing.T) {
+	a := New(t)
+	now := time.Now()
+
+	var usage device.FairAccess
+	usage.AddUplink(now, 20*time.Second)
+	usage.AddDownlink(now)
+
+	a.So(FairAccessPolicy{}.check(usage, now), ShouldBeEmpty)
+	a.So(FairAccessPolicy{UplinkAirtime: 30 * time.Second, Downlinks: 10}.check(usage, now), ShouldBeEmpty)
+	a.So(FairAccessPolicy{UplinkAirtime: 10 * time.Second}.check(usage, now), ShouldContainSubstring, "uplink airtime")
+	a.So(FairAccessPolicy{Downlinks: 1}.check(usage, now), ShouldContainSubstring, "downlinks")
+}
+
+func TestHandleFairAccess(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleFairAccess")},
+	}
+	dev := &device.Device{}
+
+	// Without policy
+	message := adrInitUplinkMessage()
+	ns.handleUplinkFairAccess(message, dev, time.Second)
+	a.So(message.PolicyViolations, ShouldBeEmpty)
+	ns.handleDownlinkFairAccess(dev)
+	a.So(dev.FairAccess.Usage, ShouldBeEmpty)
+
+	ns.UseFairAccessPolicy(FairAccessPolicy{UplinkAirtime: 3 * time.Second, Downlinks: 2})
+
+	// Within limits
+	ns.handleUplinkFairAccess(adrInitUplinkMessage(), dev, time.Second)
+	ns.handleDownlinkFairAccess(dev)
+	message = adrInitUplinkMessage()
+	ns.handleUplinkFairAccess(message, dev, time.Second)
+	a.So(message.PolicyViolations, ShouldBeEmpty)
+
+	// Downlink limit reached
+	ns.handleDownlinkFairAccess(dev)
+	message = adrInitUplinkMessage()
+	ns.handleUplinkFairAccess(message, dev, time.Second)
+	a.So(dev.FairAccess.Violation, ShouldContainSubstring, "downlinks")
+	a.So(dev.FairAccess.ViolationSince.IsZero(), ShouldBeFalse)
+	a.So(message.PolicyViolations, ShouldHaveLength, 1)
+	a.So(message.PolicyViolations[0].Policy, ShouldEqual, "fair-access")
+	a.So(message.PolicyViolations[0].Since, ShouldEqual, dev.FairAccess.ViolationSince.UnixNano())
+
+	// Airtime limit exceeded
+	since := dev.FairAccess.ViolationSince
+	ns.UseFairAccessPolicy(FairAccessPolicy{UplinkAirtime: 3 * time.Second})
+	message = adrInitUplinkMessage()
+	ns.handleUplinkFairAccess(message, dev, time.Second)
+	a.So(message.PolicyViolations, ShouldHaveLength, 1)
+	a.So(dev.FairAccess.Violation, ShouldContainSubstring, "uplink airtime")
+	a.So(dev.FairAccess.ViolationSince, ShouldResemble, since)
+
+	// Within limits again
+	ns.UseFairAccessPolicy(FairAccessPolicy{UplinkAirtime: time.Minute})
+	message = adrInitUplinkMessage()
+	ns.handleUplinkFairAccess(message, dev, time.Second)
+	a.So(message.PolicyViolations, ShouldBeEmpty)
+	a.So(dev.FairAccess.Violation, ShouldBeEmpty)
+	a.So(dev.FairAccess.ViolationSince.IsZero(), ShouldBeTrue)
+}
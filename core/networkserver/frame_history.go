@@ -0,0 +1,112 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// FrameHistoryLimits bound the frame history settings of devices. A zero
+// MaxTTL means that frames can be kept without expiry.
+type FrameHistoryLimits struct {
+	MaxDepth int           // Maximum number of frames in the history of a device
+	MaxTTL   time.Duration // Maximum time that frames are kept in the history
+}
+
+// DefaultFrameHistoryLimits are used if no other limits are configured
+var DefaultFrameHistoryLimits = FrameHistoryLimits{
+	MaxDepth: 200,
+}
+
+// UseFrameHistoryLimits sets the limits of the frame history settings of devices
+func (n *networkServer) UseFrameHistoryLimits(limits FrameHistoryLimits) {
+	n.frameHistoryLimits = &limits
+}
+
+func (n *networkServer) getFrameHistoryLimits() FrameHistoryLimits {
+	if n.frameHistoryLimits == nil {
+		return DefaultFrameHistoryLimits
+	}
+	return *n.frameHistoryLimits
+}
+
+// validateFrameHistorySettings validates frame history settings against the limits
+func validateFrameHistorySettings(settings device.FrameHistorySettings, limits FrameHistoryLimits) error {
+	if settings.Depth < 0 {
+		return errors.NewErrInvalidArgument("Depth", "can not be negative")
+	}
+	if limits.MaxDepth > 0 && settings.Depth > limits.MaxDepth {
+		return errors.NewErrInvalidArgument("Depth", fmt.Sprintf("can not be more than %d", limits.MaxDepth))
+	}
+	if settings.TTL < 0 {
+		return errors.NewErrInvalidArgument("TTL", "can not be negative")
+	}
+	if limits.MaxTTL > 0 && settings.TTL > limits.MaxTTL {
+		return errors.NewErrInvalidArgument("TTL", fmt.Sprintf("can not be more than %s", limits.MaxTTL))
+	}
+	return nil
+}
+
+// frameHistorySettings returns the frame history settings of the device,
+// or those of its application if the device has no settings of its own,
+// within the limits, which may have changed after the settings were set
+func (n *networkServer) frameHistorySettings(dev *device.Device) device.FrameHistorySettings {
+	limits := n.getFrameHistoryLimits()
+	settings := dev.FrameHistory
+	if app := n.getApplication(dev.AppID); app != nil {
+		if settings.Depth == 0 {
+			settings.Depth = app.FrameHistory.Depth
+		}
+		if settings.TTL == 0 {
+			settings.TTL = app.FrameHistory.TTL
+		}
+	}
+	if limits.MaxDepth > 0 && settings.GetDepth() > limits.MaxDepth {
+		settings.Depth = limits.MaxDepth
+	}
+	if limits.MaxTTL > 0 && (settings.TTL == 0 || settings.TTL > limits.MaxTTL) {
+		settings.TTL = limits.MaxTTL
+	}
+	return settings
+}
+
+// frameHistory returns the frame history of the device, configured with its settings
+func (n *networkServer) frameHistory(dev *device.Device) (device.FrameHistory, error) {
+	history, err := n.devices.Frames(dev.AppEUI, dev.DevEUI)
+	if err != nil {
+		return nil, err
+	}
+	history.Configure(n.frameHistorySettings(dev))
+	return history, nil
+}
+
+// setFrameHistorySettings sets the frame history settings of the device. A
+// larger depth fills up with the next uplink messages, a smaller depth
+// applies immediately.
+func (n *networkServer) setFrameHistorySettings(dev *device.Device, settings device.FrameHistorySettings) error {
+	if err := validateFrameHistorySettings(settings, n.getFrameHistoryLimits()); err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	dev.FrameHistory = settings
+	return n.devices.Set(dev)
+}
+
+// setApplicationFrameHistorySettings sets the frame history settings of the
+// application, which apply to its devices without settings of their own
+func (n *networkServer) setApplicationFrameHistorySettings(appID string, settings device.FrameHistorySettings) error {
+	if err := validateFrameHistorySettings(settings, n.getFrameHistoryLimits()); err != nil {
+		return err
+	}
+	app, err := n.applications.Get(appID)
+	if err != nil {
+		return err
+	}
+	app.FrameHistory = settings
+	return n.applications.Set(app)
+}
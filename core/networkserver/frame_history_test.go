@@ -0,0 +1,119 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateFrameHistorySettings(t *testing.T) {
+	a := New(t)
+	limits := FrameHistoryLimits{MaxDepth: 100, MaxTTL: 24 * time.Hour}
+
+	a.So(validateFrameHistorySettings(device.FrameHistorySettings{}, limits), ShouldBeNil)
+	a.So(validateFrameHistorySettings(device.FrameHistorySettings{Depth: 100, TTL: time.Hour}, limits), ShouldBeNil)
+	a.So(validateFrameHistorySettings(device.FrameHistorySettings{Depth: 1000, TTL: 1000 * time.Hour}, FrameHistoryLimits{}), ShouldBeNil)
+
+	a.So(validateFrameHistorySettings(device.FrameHistorySettings{Depth: -1}, limits), ShouldNotBeNil)
+	a.So(validateFrameHistorySettings(device.FrameHistorySettings{Depth: 101}, limits), ShouldNotBeNil)
+	a.So(validateFrameHistorySettings(device.FrameHistorySettings{TTL: -time.Hour}, limits), ShouldNotBeNil)
+	a.So(validateFrameHistorySettings(device.FrameHistorySettings{TTL: 48 * time.Hour}, limits), ShouldNotBeNil)
+}
+
+func TestFrameHistorySettings(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+
+	dev := &device.Device{AppEUI: types.AppEUI([8]byte{1}), DevEUI: types.DevEUI([8]byte{1})}
+	a.So(ns.frameHistorySettings(dev).GetDepth(), ShouldEqual, device.FramesHistorySize)
+	a.So(ns.frameHistorySettings(dev).TTL, ShouldEqual, 0)
+
+	a.So(ns.setFrameHistorySettings(dev, device.FrameHistorySettings{Depth: 1000}), ShouldNotBeNil)
+	a.So(ns.setFrameHistorySettings(dev, device.FrameHistorySettings{Depth: 100, TTL: 48 * time.Hour}), ShouldBeNil)
+	a.So(dev.FrameHistory.Depth, ShouldEqual, 100)
+
+	// Lowered limits apply to settings that were set before
+	ns.UseFrameHistoryLimits(FrameHistoryLimits{MaxDepth: 50, MaxTTL: 24 * time.Hour})
+	settings := ns.frameHistorySettings(dev)
+	a.So(settings.Depth, ShouldEqual, 50)
+	a.So(settings.TTL, ShouldEqual, 24*time.Hour)
+
+	history, err := ns.frameHistory(dev)
+	a.So(err, ShouldBeNil)
+	for i := 0; i < 60; i++ {
+		history.Push(&device.Frame{SNR: 10, GatewayCount: 3, FCnt: uint32(i)})
+	}
+	frames, _ := history.Get()
+	a.So(frames, ShouldHaveLength, 50)
+}
+
+func TestApplicationFrameHistorySettings(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices:      device.NewMemoryDeviceStore(),
+		applications: device.NewMemoryApplicationStore(),
+	}
+
+	dev := &device.Device{AppEUI: types.AppEUI([8]byte{1}), DevEUI: types.DevEUI([8]byte{1}), AppID: "test"}
+
+	ns.UseFrameHistoryLimits(FrameHistoryLimits{MaxDepth: 100})
+	a.So(ns.setApplicationFrameHistorySettings("test", device.FrameHistorySettings{Depth: 1000}), ShouldNotBeNil)
+	a.So(ns.setApplicationFrameHistorySettings("test", device.FrameHistorySettings{Depth: 50, TTL: time.Hour}), ShouldBeNil)
+
+	// Devices without settings use those of the application
+	settings := ns.frameHistorySettings(dev)
+	a.So(settings.Depth, ShouldEqual, 50)
+	a.So(settings.TTL, ShouldEqual, time.Hour)
+
+	// Settings of the device take precedence
+	a.So(ns.setFrameHistorySettings(dev, device.FrameHistorySettings{Depth: 80}), ShouldBeNil)
+	settings = ns.frameHistorySettings(dev)
+	a.So(settings.Depth, ShouldEqual, 80)
+	a.So(settings.TTL, ShouldEqual, time.Hour)
+}
+
+func TestHandleDownlinkADRWithFrameHistoryDepth(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+	ns.InitStatus()
+
+	appEUI := types.AppEUI([8]byte{1})
+	devEUI := types.DevEUI([8]byte{1})
+	dev := &device.Device{
+		AppEUI:       appEUI,
+		DevEUI:       devEUI,
+		ADR:          device.ADRSettings{Band: "EU_863_870", SendReq: true, DataRate: "SF10BW125", TxPower: 14, NbTrans: 1},
+		FrameHistory: device.FrameHistorySettings{Depth: 40},
+	}
+	history, _ := ns.frameHistory(dev)
+	for i := 0; i < 30; i++ {
+		history.Push(&device.Frame{SNR: 10, GatewayCount: 3, FCnt: uint32(i)})
+	}
+
+	// ADR waits until the history is full
+	message := adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkADR(message, dev), ShouldBeNil)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+
+	for i := 30; i < 40; i++ {
+		history.Push(&device.Frame{SNR: 10, GatewayCount: 3, FCnt: uint32(i)})
+	}
+	message = adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkADR(message, dev), ShouldBeNil)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldHaveLength, 1)
+
+	adrHistory, _ := ns.devices.ADRHistory(appEUI, devEUI)
+	decisions, _ := adrHistory.Get()
+	a.So(decisions, ShouldHaveLength, 1)
+	a.So(decisions[0].Frames, ShouldEqual, 40)
+}
@@ -0,0 +1,44 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// gatewayGroups returns the gateway groups that the device prefers for
+// downlink, or those of its application if the device has no preferences
+func (n *networkServer) gatewayGroups(dev *device.Device) []string {
+	if len(dev.GatewayGroups) > 0 {
+		return dev.GatewayGroups
+	}
+	if app := n.getApplication(dev.AppID); app != nil {
+		return app.GatewayGroups
+	}
+	return nil
+}
+
+func validateGatewayGroups(groups []string) error {
+	for _, group := range groups {
+		if group == "" {
+			return errors.NewErrInvalidArgument("Gateway Groups", "can not contain empty names")
+		}
+	}
+	return nil
+}
+
+// setApplicationGatewayGroups sets the gateway groups that the devices of the
+// application prefer for downlink, unless they have preferences of their own
+func (n *networkServer) setApplicationGatewayGroups(appID string, groups []string) error {
+	if err := validateGatewayGroups(groups); err != nil {
+		return err
+	}
+	app, err := n.applications.Get(appID)
+	if err != nil {
+		return err
+	}
+	app.GatewayGroups = groups
+	return n.applications.Set(app)
+}
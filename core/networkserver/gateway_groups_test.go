@@ -0,0 +1,34 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestGatewayGroups(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		applications: device.NewMemoryApplicationStore(),
+	}
+
+	dev := &device.Device{AppID: "test", DevID: "test"}
+	a.So(ns.gatewayGroups(dev), ShouldBeEmpty)
+
+	a.So(ns.setApplicationGatewayGroups("test", []string{"site-a", ""}), ShouldNotBeNil)
+	a.So(ns.setApplicationGatewayGroups("test", []string{"site-a", "site-b"}), ShouldBeNil)
+
+	// Devices without preferences use those of the application
+	a.So(ns.gatewayGroups(dev), ShouldResemble, []string{"site-a", "site-b"})
+
+	// Preferences of the device take precedence
+	dev.GatewayGroups = []string{"site-c"}
+	a.So(ns.gatewayGroups(dev), ShouldResemble, []string{"site-c"})
+
+	// Other applications are not affected
+	a.So(ns.gatewayGroups(&device.Device{AppID: "other"}), ShouldBeEmpty)
+}
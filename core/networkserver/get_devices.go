@@ -35,6 +35,7 @@ func (n *networkServer) HandleGetDevices(req *pb.DevicesRequest) (*pb.DevicesRes
 			FCntUp:           device.FCntUp,
 			Uses32BitFCnt:    device.Options.Uses32BitFCnt,
 			DisableFCntCheck: device.Options.DisableFCntCheck,
+			GatewayGroups:    n.gatewayGroups(device),
 		}
 		if device.Options.DisableFCntCheck {
 			res.Results = append(res.Results, dev)
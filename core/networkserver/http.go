@@ -0,0 +1,1094 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
+	pb "github.com/TheThingsNetwork/ttn/api/networkserver"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/ratelimit"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// ADRHistoryResponse is returned by the ADR history endpoint of the HTTP API
+type ADRHistoryResponse struct {
+	AppID    string `json:"app_id"`
+	DevID    string `json:"dev_id"`
+	Band     string `json:"band,omitempty"`
+	Margin   int    `json:"margin,omitempty"`
+	DataRate string `json:"data_rate,omitempty"`
+	TxPower  int    `json:"tx_power,omitempty"`
+	NbTrans  int    `json:"nb_trans,omitempty"`
+	SendReq  bool   `json:"send_req,omitempty"`
+	Failed   int    `json:"failed,omitempty"`
+	Fallback bool   `json:"fallback,omitempty"`
+
+	Rejection        string `json:"rejection,omitempty"`
+	RejectedDataRate string `json:"rejected_data_rate,omitempty"`
+	RejectedTxPower  int    `json:"rejected_tx_power,omitempty"`
+
+	Decisions []*device.ADRDecision `json:"decisions"`
+}
+
+// LinkQualityResponse is returned by the link quality endpoint of the HTTP API
+type LinkQualityResponse struct {
+	AppID    string              `json:"app_id"`
+	DevID    string              `json:"dev_id"`
+	Since    time.Time           `json:"since"`
+	Interval time.Duration       `json:"interval"`
+	Score    device.LinkScore    `json:"score"`
+	Points   []*LinkQualityPoint `json:"points"`
+}
+
+// StaticADRRequest is accepted by the static ADR endpoint of the HTTP API
+type StaticADRRequest struct {
+	DataRate string `json:"data_rate"`
+	TxPower  int    `json:"tx_power,omitempty"`
+	Channels []int  `json:"channels,omitempty"`
+}
+
+// StaticADRResponse is returned by the static ADR endpoint of the HTTP API
+type StaticADRResponse struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id"`
+	StaticADRRequest
+	SendReq bool `json:"send_req"`
+}
+
+// ADRLimitsRequest is accepted by the ADR limits endpoint of the HTTP API
+type ADRLimitsRequest struct {
+	MinDataRate string `json:"min_data_rate,omitempty"`
+	MaxDataRate string `json:"max_data_rate,omitempty"`
+	MaxTxPower  int    `json:"max_tx_power,omitempty"`
+}
+
+// ADRLimitsResponse is returned by the ADR limits endpoint of the HTTP API
+type ADRLimitsResponse struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id"`
+	ADRLimitsRequest
+}
+
+// LoRaWANVersionRequest is accepted by the LoRaWAN version endpoint of the HTTP API
+type LoRaWANVersionRequest struct {
+	LoRaWANVersion string `json:"lorawan_version"`
+}
+
+// LoRaWANVersionResponse is returned by the LoRaWAN version endpoint of the HTTP API
+type LoRaWANVersionResponse struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id"`
+	LoRaWANVersionRequest
+}
+
+// FrameHistoryRequest is accepted by the frame history endpoint of the HTTP API.
+// The TTL is a duration such as 24h; zero values use the defaults of the NetworkServer.
+type FrameHistoryRequest struct {
+	Depth int    `json:"depth,omitempty"`
+	TTL   string `json:"ttl,omitempty"`
+}
+
+// FrameHistoryResponse is returned by the frame history endpoint of the HTTP
+// API. Depth and TTL are the settings that are in effect, which are bounded
+// by the limits of the NetworkServer.
+type FrameHistoryResponse struct {
+	AppID    string `json:"app_id"`
+	DevID    string `json:"dev_id,omitempty"`
+	Depth    int    `json:"depth"`
+	TTL      string `json:"ttl,omitempty"`
+	MaxDepth int    `json:"max_depth,omitempty"`
+	MaxTTL   string `json:"max_ttl,omitempty"`
+}
+
+// GatewayGroupsRequest is accepted by the gateway groups endpoint of the HTTP
+// API. The groups are the names of gateway groups in the configuration of the
+// Broker, most preferred first.
+type GatewayGroupsRequest struct {
+	GatewayGroups []string `json:"gateway_groups"`
+}
+
+// GatewayGroupsResponse is returned by the gateway groups endpoint of the HTTP API
+type GatewayGroupsResponse struct {
+	AppID         string   `json:"app_id"`
+	GatewayGroups []string `json:"gateway_groups"`
+}
+
+// MACDownlinksRequest is accepted by the MAC downlinks endpoint of the HTTP
+// API. The Interval is a duration such as 6h; zero uses the interval of the
+// NetworkServer.
+type MACDownlinksRequest struct {
+	Interval string `json:"interval,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// MACDownlinksResponse is returned by the MAC downlinks endpoint of the HTTP
+// API. The Interval is the interval that is in effect, which is empty if the
+// NetworkServer does not schedule MAC-only downlinks for the device.
+type MACDownlinksResponse struct {
+	AppID    string     `json:"app_id"`
+	DevID    string     `json:"dev_id"`
+	Interval string     `json:"interval,omitempty"`
+	Disabled bool       `json:"disabled,omitempty"`
+	LastSent *time.Time `json:"last_sent,omitempty"`
+}
+
+// RelayRequest is accepted by the relay endpoint of the HTTP API. The
+// NetworkServer sends the settings to the relay when they change.
+type RelayRequest struct {
+	Enabled                bool                `json:"enabled"`
+	CADPeriodicity         int                 `json:"cad_periodicity,omitempty"`
+	DefaultChannelIndex    int                 `json:"default_channel_index,omitempty"`
+	SecondChannelFrequency uint64              `json:"second_channel_frequency,omitempty"`
+	SecondChannelDataRate  int                 `json:"second_channel_data_rate,omitempty"`
+	SecondChannelAckOffset int                 `json:"second_channel_ack_offset,omitempty"`
+	Limits                 *device.RelayLimits `json:"limits,omitempty"`
+}
+
+// RelayResponse is returned by the relay endpoint of the HTTP API
+type RelayResponse struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id"`
+	device.RelaySettings
+}
+
+// RelayServedRequest is accepted by the relay-served endpoint of the HTTP API.
+// The NetworkServer sends the settings to the end device and adds the end
+// device to the uplink list of the relay.
+type RelayServedRequest struct {
+	Enabled          bool         `json:"enabled"`
+	RelayAppEUI      types.AppEUI `json:"relay_app_eui"`
+	RelayDevEUI      types.DevEUI `json:"relay_dev_eui"`
+	Mode             int          `json:"mode,omitempty"`
+	SmartEnableLevel int          `json:"smart_enable_level,omitempty"`
+	Backoff          int          `json:"backoff,omitempty"`
+	ReloadRate       int          `json:"reload_rate,omitempty"`
+	BucketSize       int          `json:"bucket_size,omitempty"`
+}
+
+// RelayServedResponse is returned by the relay-served endpoint of the HTTP API
+type RelayServedResponse struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id"`
+	device.RelayServedSettings
+}
+
+// MACCommandsResponse is returned by the MAC commands endpoint of the HTTP API
+type MACCommandsResponse struct {
+	AppID   string               `json:"app_id"`
+	DevID   string               `json:"dev_id"`
+	Pending []PendingMACResponse `json:"pending"`
+}
+
+// PendingMACResponse is a pending MAC command in the MACCommandsResponse
+type PendingMACResponse struct {
+	Name string `json:"name"`
+	device.PendingMACCommand
+}
+
+// AirtimeResponse is returned by the airtime endpoint of the HTTP API
+type AirtimeResponse struct {
+	AppID      string              `json:"app_id"`
+	DevID      string              `json:"dev_id"`
+	Uplink     toa.Usage           `json:"uplink"`
+	FairAccess *FairAccessResponse `json:"fair_access,omitempty"`
+}
+
+// FairAccessResponse contains the usage of a device in the window of the fair access policy
+type FairAccessResponse struct {
+	UplinkAirtime    time.Duration `json:"uplink_airtime"`
+	MaxUplinkAirtime time.Duration `json:"max_uplink_airtime,omitempty"`
+	Downlinks        int           `json:"downlinks"`
+	MaxDownlinks     int           `json:"max_downlinks,omitempty"`
+	Violation        string        `json:"violation,omitempty"`
+	ViolationSince   *time.Time    `json:"violation_since,omitempty"`
+}
+
+// QuarantineResponse is returned by the quarantine endpoint of the HTTP API
+type QuarantineResponse struct {
+	AppID       string     `json:"app_id"`
+	DevID       string     `json:"dev_id"`
+	Quarantined bool       `json:"quarantined"`
+	Reason      string     `json:"reason,omitempty"`
+	Since       *time.Time `json:"since,omitempty"`
+}
+
+// TransferRequest is accepted by the transfer endpoint of the HTTP API
+type TransferRequest struct {
+	AppID string `json:"app_id"`
+	DevID string `json:"dev_id"`
+}
+
+type httpHandler struct {
+	manager *networkServerManager
+}
+
+// HTTPHandler returns an HTTP API for the NetworkServer:
+//
+//	GET /status                                   returns the status of the NetworkServer
+//	GET /devices/{app_eui}/{dev_eui}              returns a device
+//	PUT /devices/{app_eui}/{dev_eui}              creates or updates a device (also POST)
+//	DELETE /devices/{app_eui}/{dev_eui}           deletes a device
+//	GET /devices/{app_eui}/{dev_eui}/adr-history  returns the ADR decisions for a device
+//	GET /devices/{app_eui}/{dev_eui}/link-quality returns the link score and the SNR, RSSI, data rate and loss trend of a device,
+//	                                              aggregated from its frame history (query: interval, since; durations such as 1h and 24h)
+//	GET /devices/{app_eui}/{dev_eui}/static-adr   returns the static ADR settings of a device
+//	PUT /devices/{app_eui}/{dev_eui}/static-adr   sends static ADR settings to a device that does not use ADR (also POST)
+//	DELETE /devices/{app_eui}/{dev_eui}/static-adr cancels static ADR settings that were not sent yet
+//	GET /devices/{app_eui}/{dev_eui}/adr-limits   returns the data rates and TX power that ADR can select for a device
+//	PUT /devices/{app_eui}/{dev_eui}/adr-limits   sets the data rates and TX power that ADR can select for a device (also POST)
+//	GET /devices/{app_eui}/{dev_eui}/lorawan-version returns the LoRaWAN version of a device
+//	PUT /devices/{app_eui}/{dev_eui}/lorawan-version sets the LoRaWAN version of a device, which selects the MAC commands
+//	                                              that are sent to it (also POST)
+//	GET /devices/{app_eui}/{dev_eui}/frame-history returns the depth and retention of the frame history of a device
+//	PUT /devices/{app_eui}/{dev_eui}/frame-history sets the depth and retention of the frame history of a device (also POST)
+//	GET /devices/{app_eui}/{dev_eui}/mac-commands returns the MAC commands that the device did not answer yet
+//	GET /devices/{app_eui}/{dev_eui}/mac-downlinks returns the minimum interval between downlinks that only contain MAC commands
+//	PUT /devices/{app_eui}/{dev_eui}/mac-downlinks sets the minimum interval between downlinks that only contain MAC commands (also POST)
+//	GET /devices/{app_eui}/{dev_eui}/relay        returns the relay settings of a device and the end devices that it notified
+//	PUT /devices/{app_eui}/{dev_eui}/relay        sets the relay settings of a device (also POST)
+//	GET /devices/{app_eui}/{dev_eui}/relay-served returns the relay that serves an end device
+//	PUT /devices/{app_eui}/{dev_eui}/relay-served sets the relay that serves an end device (also POST)
+//	GET /devices/{app_eui}/{dev_eui}/airtime      returns the uplink airtime of a device
+//	DELETE /devices/{app_eui}/{dev_eui}/data      erases the data that was collected about a device
+//	POST /devices/{app_eui}/{dev_eui}/transfer    moves a device to another application
+//	GET /devices/{app_eui}/{dev_eui}/quarantine   returns whether a device is quarantined after a replay attack
+//	DELETE /devices/{app_eui}/{dev_eui}/quarantine lifts the quarantine of a device
+//	GET /applications/{app_id}/frame-history      returns the depth and retention of the frame history of the devices of an application
+//	PUT /applications/{app_id}/frame-history      sets the depth and retention of the frame history of the devices of an application
+//	                                              that do not have settings of their own (also POST)
+//	GET /applications/{app_id}/gateway-groups     returns the gateway groups that the devices of an application prefer for downlink
+//	PUT /applications/{app_id}/gateway-groups     sets the gateway groups that the devices of an application prefer for downlink,
+//	                                              unless they have preferences of their own (also POST)
+//
+// Requests are authenticated with a Bearer token that has devices rights to the application,
+// or settings rights for the settings of the application.
+// Transfers are authenticated with the token of the Handler of both applications,
+// and its ID and service name in the Grpc-Metadata-Id and Grpc-Metadata-Service-Name headers.
+// The OpenAPI specification of this API is in SwaggerJSON.
+func (n *networkServer) HTTPHandler() http.Handler {
+	return &httpHandler{manager: &networkServerManager{
+		networkServer: n,
+		clientRate:    ratelimit.NewRegistry(5000, time.Hour),
+	}}
+}
+
+// httpEndpoint serves a request to a route, with the values of the path
+// parameters of the route in params
+type httpEndpoint func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string)
+
+// httpRoute is a path of the HTTP API with the endpoints for its methods. The
+// routes must match the paths in SwaggerJSON.
+type httpRoute struct {
+	path    string
+	methods map[string]httpEndpoint
+}
+
+// noContent returns an endpoint that responds with 204 No Content if f succeeds
+func noContent(f func(h *httpHandler, req *http.Request, appEUIStr, devEUIStr string) error) httpEndpoint {
+	return func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+		h.writeNoContent(res, f(h, req, params[0], params[1]))
+	}
+}
+
+// applicationNoContent returns an endpoint of an application that responds with 204 No Content if f succeeds
+func applicationNoContent(f func(h *httpHandler, req *http.Request, appID string) error) httpEndpoint {
+	return func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+		h.writeNoContent(res, f(h, req, params[0]))
+	}
+}
+
+var httpRoutes = []httpRoute{
+	{"/status", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.manager.GetStatus(h.context(req), &pb.StatusRequest{})
+			h.writeProto(res, response, err)
+		},
+	}},
+	{"/devices/{app_eui}/{dev_eui}", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.lorawanDevice(req, params[0], params[1])
+			h.writeProto(res, response, err)
+		},
+		http.MethodPut:    noContent((*httpHandler).setLoRaWANDevice),
+		http.MethodPost:   noContent((*httpHandler).setLoRaWANDevice),
+		http.MethodDelete: noContent((*httpHandler).deleteLoRaWANDevice),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/adr-history", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.adrHistory(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+	}},
+	{"/devices/{app_eui}/{dev_eui}/link-quality", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.linkQuality(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+	}},
+	{"/devices/{app_eui}/{dev_eui}/adr-limits", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.adrLimits(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+		http.MethodPut:  noContent((*httpHandler).setADRLimits),
+		http.MethodPost: noContent((*httpHandler).setADRLimits),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/lorawan-version", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.lorawanVersion(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+		http.MethodPut:  noContent((*httpHandler).setLoRaWANVersion),
+		http.MethodPost: noContent((*httpHandler).setLoRaWANVersion),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/frame-history", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.frameHistory(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+		http.MethodPut:  noContent((*httpHandler).setFrameHistory),
+		http.MethodPost: noContent((*httpHandler).setFrameHistory),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/mac-downlinks", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.macDownlinks(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+		http.MethodPut:  noContent((*httpHandler).setMACDownlinks),
+		http.MethodPost: noContent((*httpHandler).setMACDownlinks),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/relay", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.relay(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+		http.MethodPut:  noContent((*httpHandler).setRelay),
+		http.MethodPost: noContent((*httpHandler).setRelay),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/relay-served", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.relayServed(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+		http.MethodPut:  noContent((*httpHandler).setRelayServed),
+		http.MethodPost: noContent((*httpHandler).setRelayServed),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/static-adr", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.staticADR(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+		http.MethodPut:    noContent((*httpHandler).setStaticADR),
+		http.MethodPost:   noContent((*httpHandler).setStaticADR),
+		http.MethodDelete: noContent((*httpHandler).deleteStaticADR),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/mac-commands", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.macCommands(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+	}},
+	{"/devices/{app_eui}/{dev_eui}/airtime", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.airtime(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+	}},
+	{"/devices/{app_eui}/{dev_eui}/quarantine", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.quarantine(req, params[0], params[1])
+			h.write(res, response, err)
+		},
+		http.MethodDelete: noContent((*httpHandler).clearQuarantine),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/data", map[string]httpEndpoint{
+		http.MethodDelete: noContent((*httpHandler).eraseData),
+	}},
+	{"/devices/{app_eui}/{dev_eui}/transfer", map[string]httpEndpoint{
+		http.MethodPost: noContent((*httpHandler).transfer),
+	}},
+	{"/applications/{app_id}/frame-history", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.applicationFrameHistory(req, params[0])
+			h.write(res, response, err)
+		},
+		http.MethodPut:  applicationNoContent((*httpHandler).setApplicationFrameHistory),
+		http.MethodPost: applicationNoContent((*httpHandler).setApplicationFrameHistory),
+	}},
+	{"/applications/{app_id}/gateway-groups", map[string]httpEndpoint{
+		http.MethodGet: func(h *httpHandler, res http.ResponseWriter, req *http.Request, params []string) {
+			response, err := h.applicationGatewayGroups(req, params[0])
+			h.write(res, response, err)
+		},
+		http.MethodPut:  applicationNoContent((*httpHandler).setApplicationGatewayGroups),
+		http.MethodPost: applicationNoContent((*httpHandler).setApplicationGatewayGroups),
+	}},
+}
+
+// match returns the values of the path parameters if the route matches the path
+func (r httpRoute) match(path []string) (params []string, ok bool) {
+	route := strings.Split(strings.Trim(r.path, "/"), "/")
+	if len(route) != len(path) {
+		return nil, false
+	}
+	for i, segment := range route {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, path[i])
+			continue
+		}
+		if segment != path[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (h *httpHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	path := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for _, route := range httpRoutes {
+		params, ok := route.match(path)
+		if !ok {
+			continue
+		}
+		endpoint, ok := route.methods[req.Method]
+		if !ok {
+			http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		endpoint(h, res, req, params)
+		return
+	}
+	http.NotFound(res, req)
+}
+
+// context returns a context with the Bearer token of the request
+func (h *httpHandler) context(req *http.Request) context.Context {
+	var token string
+	if authorization := req.Header.Get("authorization"); len(authorization) >= 7 && strings.ToLower(authorization[0:7]) == "bearer " {
+		token = authorization[7:]
+	}
+	return metadata.NewContext(context.Background(), metadata.Pairs("token", token))
+}
+
+// componentContext returns a context with the Bearer token, ID and service
+// name of the component that sent the request
+func (h *httpHandler) componentContext(req *http.Request) context.Context {
+	ctx := h.context(req)
+	md, _ := metadata.FromContext(ctx)
+	md = metadata.Join(md, metadata.Pairs(
+		"id", req.Header.Get("Grpc-Metadata-Id"),
+		"service-name", req.Header.Get("Grpc-Metadata-Service-Name"),
+	))
+	return metadata.NewContext(context.Background(), md)
+}
+
+func parseDeviceIdentifier(appEUIStr, devEUIStr string) (*pb_lorawan.DeviceIdentifier, error) {
+	appEUI, err := types.ParseAppEUI(appEUIStr)
+	if err != nil {
+		return nil, errors.NewErrInvalidArgument("AppEUI", err.Error())
+	}
+	devEUI, err := types.ParseDevEUI(devEUIStr)
+	if err != nil {
+		return nil, errors.NewErrInvalidArgument("DevEUI", err.Error())
+	}
+	return &pb_lorawan.DeviceIdentifier{AppEui: &appEUI, DevEui: &devEUI}, nil
+}
+
+func (h *httpHandler) getDevice(req *http.Request, appEUIStr, devEUIStr string) (*device.Device, error) {
+	identifier, err := parseDeviceIdentifier(appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	return h.manager.getDevice(h.context(req), identifier)
+}
+
+func (h *httpHandler) lorawanDevice(req *http.Request, appEUIStr, devEUIStr string) (*pb_lorawan.Device, error) {
+	identifier, err := parseDeviceIdentifier(appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	return h.manager.GetDevice(h.context(req), identifier)
+}
+
+func (h *httpHandler) setLoRaWANDevice(req *http.Request, appEUIStr, devEUIStr string) error {
+	identifier, err := parseDeviceIdentifier(appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	var in pb_lorawan.Device
+	if err := jsonpb.Unmarshal(req.Body, &in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	in.AppEui, in.DevEui = identifier.AppEui, identifier.DevEui
+	_, err = h.manager.SetDevice(h.context(req), &in)
+	return err
+}
+
+func (h *httpHandler) deleteLoRaWANDevice(req *http.Request, appEUIStr, devEUIStr string) error {
+	identifier, err := parseDeviceIdentifier(appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	_, err = h.manager.DeleteDevice(h.context(req), identifier)
+	return err
+}
+
+func (h *httpHandler) adrHistory(req *http.Request, appEUIStr, devEUIStr string) (*ADRHistoryResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	history, err := h.manager.networkServer.devices.ADRHistory(dev.AppEUI, dev.DevEUI)
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := history.Get()
+	if err != nil {
+		return nil, err
+	}
+	if decisions == nil {
+		decisions = []*device.ADRDecision{}
+	}
+	return &ADRHistoryResponse{
+		AppID:    dev.AppID,
+		DevID:    dev.DevID,
+		Band:     dev.ADR.Band,
+		Margin:   dev.ADR.Margin,
+		DataRate: dev.ADR.DataRate,
+		TxPower:  dev.ADR.TxPower,
+		NbTrans:  dev.ADR.NbTrans,
+		SendReq:  dev.ADR.SendReq,
+		Failed:   dev.ADR.Failed,
+		Fallback: dev.ADR.Fallback,
+
+		Rejection:        dev.ADR.Rejection,
+		RejectedDataRate: dev.ADR.RejectedDataRate,
+		RejectedTxPower:  dev.ADR.RejectedTxPower,
+
+		Decisions: decisions,
+	}, nil
+}
+
+// Defaults and limits of the link quality trend
+const (
+	defaultLinkQualityInterval = time.Hour
+	defaultLinkQualitySince    = 24 * time.Hour
+	minLinkQualityInterval     = time.Minute
+	maxLinkQualityPoints       = 1000
+)
+
+func (h *httpHandler) linkQuality(req *http.Request, appEUIStr, devEUIStr string) (*LinkQualityResponse, error) {
+	interval, since := defaultLinkQualityInterval, defaultLinkQualitySince
+	if str := req.URL.Query().Get("interval"); str != "" {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return nil, errors.NewErrInvalidArgument("Interval", err.Error())
+		}
+		interval = d
+	}
+	if str := req.URL.Query().Get("since"); str != "" {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return nil, errors.NewErrInvalidArgument("Since", err.Error())
+		}
+		since = d
+	}
+	if interval < minLinkQualityInterval {
+		return nil, errors.NewErrInvalidArgument("Interval", fmt.Sprintf("must be at least %s", minLinkQualityInterval))
+	}
+	if since <= 0 {
+		return nil, errors.NewErrInvalidArgument("Since", "must be positive")
+	}
+	if since/interval > maxLinkQualityPoints {
+		return nil, errors.NewErrInvalidArgument("Interval", fmt.Sprintf("results in more than %d points", maxLinkQualityPoints))
+	}
+
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	history, err := h.manager.networkServer.frameHistory(dev)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := history.Get()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now().Add(-1 * since)
+	return &LinkQualityResponse{
+		AppID:    dev.AppID,
+		DevID:    dev.DevID,
+		Since:    start,
+		Interval: interval,
+		Score:    dev.LinkScore,
+		Points:   linkQualityTrend(frames, start, interval),
+	}, nil
+}
+
+func (h *httpHandler) adrLimits(req *http.Request, appEUIStr, devEUIStr string) (*ADRLimitsResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	return &ADRLimitsResponse{
+		AppID: dev.AppID,
+		DevID: dev.DevID,
+		ADRLimitsRequest: ADRLimitsRequest{
+			MinDataRate: dev.ADRLimits.MinDataRate,
+			MaxDataRate: dev.ADRLimits.MaxDataRate,
+			MaxTxPower:  dev.ADRLimits.MaxTxPower,
+		},
+	}, nil
+}
+
+func (h *httpHandler) setADRLimits(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	var in ADRLimitsRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	return h.manager.networkServer.setADRLimits(dev, device.ADRLimits{
+		MinDataRate: in.MinDataRate,
+		MaxDataRate: in.MaxDataRate,
+		MaxTxPower:  in.MaxTxPower,
+	})
+}
+
+func (h *httpHandler) lorawanVersion(req *http.Request, appEUIStr, devEUIStr string) (*LoRaWANVersionResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	return &LoRaWANVersionResponse{
+		AppID:                 dev.AppID,
+		DevID:                 dev.DevID,
+		LoRaWANVersionRequest: LoRaWANVersionRequest{LoRaWANVersion: dev.LoRaWANVersion},
+	}, nil
+}
+
+func (h *httpHandler) setLoRaWANVersion(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	var in LoRaWANVersionRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	return h.manager.networkServer.setLoRaWANVersion(dev, in.LoRaWANVersion)
+}
+
+func (h *httpHandler) frameHistory(req *http.Request, appEUIStr, devEUIStr string) (*FrameHistoryResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	settings := h.manager.networkServer.frameHistorySettings(dev)
+	limits := h.manager.networkServer.getFrameHistoryLimits()
+	response := &FrameHistoryResponse{
+		AppID:    dev.AppID,
+		DevID:    dev.DevID,
+		Depth:    settings.GetDepth(),
+		MaxDepth: limits.MaxDepth,
+	}
+	if settings.TTL > 0 {
+		response.TTL = settings.TTL.String()
+	}
+	if limits.MaxTTL > 0 {
+		response.MaxTTL = limits.MaxTTL.String()
+	}
+	return response, nil
+}
+
+func (h *httpHandler) setFrameHistory(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	settings, err := decodeFrameHistoryRequest(req)
+	if err != nil {
+		return err
+	}
+	return h.manager.networkServer.setFrameHistorySettings(dev, settings)
+}
+
+func decodeFrameHistoryRequest(req *http.Request) (settings device.FrameHistorySettings, err error) {
+	var in FrameHistoryRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return settings, errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	settings.Depth = in.Depth
+	if in.TTL != "" {
+		if settings.TTL, err = time.ParseDuration(in.TTL); err != nil {
+			return settings, errors.NewErrInvalidArgument("TTL", err.Error())
+		}
+	}
+	return settings, nil
+}
+
+func (h *httpHandler) applicationFrameHistory(req *http.Request, appID string) (*FrameHistoryResponse, error) {
+	if err := h.manager.checkApplication(h.context(req), appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.networkServer.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	limits := h.manager.networkServer.getFrameHistoryLimits()
+	response := &FrameHistoryResponse{
+		AppID:    appID,
+		Depth:    app.FrameHistory.GetDepth(),
+		MaxDepth: limits.MaxDepth,
+	}
+	if app.FrameHistory.TTL > 0 {
+		response.TTL = app.FrameHistory.TTL.String()
+	}
+	if limits.MaxTTL > 0 {
+		response.MaxTTL = limits.MaxTTL.String()
+	}
+	return response, nil
+}
+
+func (h *httpHandler) setApplicationFrameHistory(req *http.Request, appID string) error {
+	if err := h.manager.checkApplication(h.context(req), appID, rights.AppSettings); err != nil {
+		return err
+	}
+	settings, err := decodeFrameHistoryRequest(req)
+	if err != nil {
+		return err
+	}
+	return h.manager.networkServer.setApplicationFrameHistorySettings(appID, settings)
+}
+
+func (h *httpHandler) applicationGatewayGroups(req *http.Request, appID string) (*GatewayGroupsResponse, error) {
+	if err := h.manager.checkApplication(h.context(req), appID, rights.AppSettings); err != nil {
+		return nil, err
+	}
+	app, err := h.manager.networkServer.applications.Get(appID)
+	if err != nil {
+		return nil, err
+	}
+	response := &GatewayGroupsResponse{AppID: appID, GatewayGroups: app.GatewayGroups}
+	if response.GatewayGroups == nil {
+		response.GatewayGroups = []string{}
+	}
+	return response, nil
+}
+
+func (h *httpHandler) setApplicationGatewayGroups(req *http.Request, appID string) error {
+	if err := h.manager.checkApplication(h.context(req), appID, rights.AppSettings); err != nil {
+		return err
+	}
+	var in GatewayGroupsRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	return h.manager.networkServer.setApplicationGatewayGroups(appID, in.GatewayGroups)
+}
+
+func (h *httpHandler) macDownlinks(req *http.Request, appEUIStr, devEUIStr string) (*MACDownlinksResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	response := &MACDownlinksResponse{
+		AppID:    dev.AppID,
+		DevID:    dev.DevID,
+		Disabled: dev.MACDownlinks.Disabled,
+	}
+	if interval := h.manager.networkServer.getMACDownlinkInterval(dev); interval > 0 {
+		response.Interval = interval.String()
+	}
+	if !dev.MACDownlinks.LastSent.IsZero() {
+		response.LastSent = &dev.MACDownlinks.LastSent
+	}
+	return response, nil
+}
+
+func (h *httpHandler) setMACDownlinks(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	var in MACDownlinksRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	settings := device.MACDownlinkSettings{Disabled: in.Disabled}
+	if in.Interval != "" {
+		if settings.Interval, err = time.ParseDuration(in.Interval); err != nil {
+			return errors.NewErrInvalidArgument("Interval", err.Error())
+		}
+	}
+	return h.manager.networkServer.setMACDownlinkSettings(dev, settings)
+}
+
+func (h *httpHandler) relay(req *http.Request, appEUIStr, devEUIStr string) (*RelayResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	return &RelayResponse{AppID: dev.AppID, DevID: dev.DevID, RelaySettings: dev.Relay}, nil
+}
+
+func (h *httpHandler) setRelay(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	var in RelayRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	return h.manager.networkServer.setRelaySettings(dev, device.RelaySettings{
+		Enabled:                in.Enabled,
+		CADPeriodicity:         in.CADPeriodicity,
+		DefaultChannelIndex:    in.DefaultChannelIndex,
+		SecondChannelFrequency: in.SecondChannelFrequency,
+		SecondChannelDataRate:  in.SecondChannelDataRate,
+		SecondChannelAckOffset: in.SecondChannelAckOffset,
+		Limits:                 in.Limits,
+	})
+}
+
+func (h *httpHandler) relayServed(req *http.Request, appEUIStr, devEUIStr string) (*RelayServedResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	return &RelayServedResponse{AppID: dev.AppID, DevID: dev.DevID, RelayServedSettings: dev.RelayServed}, nil
+}
+
+func (h *httpHandler) setRelayServed(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	var in RelayServedRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if in.Enabled {
+		// The relay is updated as well, so the token needs access to its application
+		if _, err := h.getDevice(req, in.RelayAppEUI.String(), in.RelayDevEUI.String()); err != nil {
+			return err
+		}
+	}
+	return h.manager.networkServer.setRelayServedSettings(dev, device.RelayServedSettings{
+		Enabled:          in.Enabled,
+		RelayAppEUI:      in.RelayAppEUI,
+		RelayDevEUI:      in.RelayDevEUI,
+		Mode:             in.Mode,
+		SmartEnableLevel: in.SmartEnableLevel,
+		Backoff:          in.Backoff,
+		ReloadRate:       in.ReloadRate,
+		BucketSize:       in.BucketSize,
+	})
+}
+
+func (h *httpHandler) staticADR(req *http.Request, appEUIStr, devEUIStr string) (*StaticADRResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	return &StaticADRResponse{
+		AppID: dev.AppID,
+		DevID: dev.DevID,
+		StaticADRRequest: StaticADRRequest{
+			DataRate: dev.StaticADR.DataRate,
+			TxPower:  dev.StaticADR.TxPower,
+			Channels: dev.StaticADR.Channels,
+		},
+		SendReq: dev.StaticADR.SendReq,
+	}, nil
+}
+
+func (h *httpHandler) setStaticADR(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	var in StaticADRRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	return h.manager.networkServer.setStaticADR(dev, device.StaticADRSettings{
+		DataRate: in.DataRate,
+		TxPower:  in.TxPower,
+		Channels: in.Channels,
+	})
+}
+
+func (h *httpHandler) deleteStaticADR(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	return h.manager.networkServer.clearStaticADR(dev)
+}
+
+func (h *httpHandler) macCommands(req *http.Request, appEUIStr, devEUIStr string) (*MACCommandsResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	response := &MACCommandsResponse{
+		AppID:   dev.AppID,
+		DevID:   dev.DevID,
+		Pending: make([]PendingMACResponse, 0, len(dev.PendingMACCommands)),
+	}
+	for _, pending := range dev.PendingMACCommands {
+		response.Pending = append(response.Pending, PendingMACResponse{
+			Name:              macCommandNames[pending.CID],
+			PendingMACCommand: pending,
+		})
+	}
+	return response, nil
+}
+
+func (h *httpHandler) airtime(req *http.Request, appEUIStr, devEUIStr string) (*AirtimeResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	response := &AirtimeResponse{
+		AppID:  dev.AppID,
+		DevID:  dev.DevID,
+		Uplink: dev.UplinkAirtime.Get(now),
+	}
+	if policy := h.manager.networkServer.fairAccessPolicy; policy != nil {
+		response.FairAccess = &FairAccessResponse{
+			MaxUplinkAirtime: policy.UplinkAirtime,
+			MaxDownlinks:     policy.Downlinks,
+			Violation:        dev.FairAccess.Violation,
+		}
+		response.FairAccess.UplinkAirtime, response.FairAccess.Downlinks = dev.FairAccess.Get(now)
+		if !dev.FairAccess.ViolationSince.IsZero() {
+			response.FairAccess.ViolationSince = &dev.FairAccess.ViolationSince
+		}
+	}
+	return response, nil
+}
+
+func (h *httpHandler) eraseData(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	return h.manager.networkServer.eraseDeviceData(dev)
+}
+
+func (h *httpHandler) quarantine(req *http.Request, appEUIStr, devEUIStr string) (*QuarantineResponse, error) {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return nil, err
+	}
+	response := &QuarantineResponse{
+		AppID:       dev.AppID,
+		DevID:       dev.DevID,
+		Quarantined: dev.Quarantine.Active(),
+		Reason:      dev.Quarantine.Reason,
+	}
+	if !dev.Quarantine.Since.IsZero() {
+		response.Since = &dev.Quarantine.Since
+	}
+	return response, nil
+}
+
+func (h *httpHandler) clearQuarantine(req *http.Request, appEUIStr, devEUIStr string) error {
+	dev, err := h.getDevice(req, appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	return h.manager.networkServer.clearQuarantine(dev)
+}
+
+func (h *httpHandler) transfer(req *http.Request, appEUIStr, devEUIStr string) error {
+	identifier, err := parseDeviceIdentifier(appEUIStr, devEUIStr)
+	if err != nil {
+		return err
+	}
+	var in TransferRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return errors.NewErrInvalidArgument("Body", err.Error())
+	}
+	if !api.ValidID(in.AppID) || !api.ValidID(in.DevID) {
+		return errors.NewErrInvalidArgument("Body", "invalid AppID or DevID")
+	}
+	handler, err := h.manager.networkServer.ValidateNetworkContext(h.componentContext(req))
+	if err != nil {
+		return err
+	}
+	dev, err := h.manager.networkServer.devices.Get(*identifier.AppEui, *identifier.DevEui)
+	if err != nil {
+		return err
+	}
+	if err := checkTransfer(handler, dev, in.AppID); err != nil {
+		return err
+	}
+	return h.manager.networkServer.transferDevice(dev, in.AppID, in.DevID)
+}
+
+func (h *httpHandler) writeError(res http.ResponseWriter, err error) {
+	if grpc.Code(err) != codes.Unknown {
+		err = errors.FromGRPCError(err)
+	}
+	code := http.StatusInternalServerError
+	switch errors.GetErrType(err) {
+	case errors.NotFound:
+		code = http.StatusNotFound
+	case errors.InvalidArgument:
+		code = http.StatusBadRequest
+	case errors.PermissionDenied:
+		code = http.StatusForbidden
+	}
+	http.Error(res, err.Error(), code)
+}
+
+func (h *httpHandler) write(res http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		h.writeError(res, err)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(res).Encode(v)
+}
+
+func (h *httpHandler) writeProto(res http.ResponseWriter, msg proto.Message, err error) {
+	if err != nil {
+		h.writeError(res, err)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	(&jsonpb.Marshaler{OrigName: true}).Marshal(res, msg)
+}
+
+func (h *httpHandler) writeNoContent(res http.ResponseWriter, err error) {
+	if err != nil {
+		h.writeError(res, err)
+		return
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
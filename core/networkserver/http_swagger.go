@@ -0,0 +1,2265 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+// SwaggerJSON is the OpenAPI specification of the HTTP API of the NetworkServer
+const SwaggerJSON = `
+{
+  "swagger": "2.0",
+  "info": {
+    "title": "NetworkServer HTTP API",
+    "version": "version not set"
+  },
+  "schemes": [
+    "http",
+    "https"
+  ],
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "paths": {
+    "/status": {
+      "get": {
+        "summary": "GetStatus returns the status of the NetworkServer",
+        "operationId": "GetStatus",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverStatus"
+            }
+          }
+        },
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}": {
+      "get": {
+        "summary": "GetDevice returns the device with the given AppEUI and DevEUI",
+        "operationId": "GetDevice",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/lorawanDevice"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "delete": {
+        "summary": "DeleteDevice deletes the device with the given AppEUI and DevEUI",
+        "operationId": "DeleteDevice",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetDevice creates or updates a device",
+        "operationId": "SetDevice",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/lorawanDevice"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetDevice creates or updates a device",
+        "operationId": "SetDevice2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/lorawanDevice"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/adr-history": {
+      "get": {
+        "summary": "GetADRHistory returns the ADR decisions for a device",
+        "operationId": "GetADRHistory",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverADRHistory"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/link-quality": {
+      "get": {
+        "summary": "GetLinkQuality returns the link score and the SNR, RSSI, data rate and loss trend of a device",
+        "description": "The trend is aggregated from the frame history of the device, which contains the uplinks with ADR. The depth and retention of the frame history are set with the frame-history endpoint.",
+        "operationId": "GetLinkQuality",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverLinkQuality"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "interval",
+            "description": "Duration of each point, such as 1h (default). At least 1m.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "since",
+            "description": "Duration before now where the trend starts, such as 24h (default)",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/adr-limits": {
+      "get": {
+        "summary": "GetADRLimits returns the ADR limits of a device",
+        "operationId": "GetADRLimits",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverADRLimits"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetADRLimits sets the data rates and TX power that ADR can select for a device",
+        "operationId": "SetADRLimits",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverADRLimitsSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetADRLimits sets the data rates and TX power that ADR can select for a device",
+        "operationId": "SetADRLimits2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverADRLimitsSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/lorawan-version": {
+      "get": {
+        "summary": "GetLoRaWANVersion returns the LoRaWAN version of a device",
+        "operationId": "GetLoRaWANVersion",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverLoRaWANVersion"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetLoRaWANVersion sets the LoRaWAN version of a device, which selects the MAC commands that are sent to it",
+        "operationId": "SetLoRaWANVersion",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverLoRaWANVersionSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetLoRaWANVersion sets the LoRaWAN version of a device, which selects the MAC commands that are sent to it",
+        "operationId": "SetLoRaWANVersion2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverLoRaWANVersionSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/frame-history": {
+      "get": {
+        "summary": "GetFrameHistory returns the depth and retention of the frame history of a device",
+        "operationId": "GetFrameHistory",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverFrameHistory"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetFrameHistory sets the depth and retention of the frame history of a device",
+        "operationId": "SetFrameHistory",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverFrameHistorySettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetFrameHistory sets the depth and retention of the frame history of a device",
+        "operationId": "SetFrameHistory2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverFrameHistorySettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/mac-downlinks": {
+      "get": {
+        "summary": "GetMACDownlinks returns the minimum interval between downlinks that only contain MAC commands",
+        "operationId": "GetMACDownlinks",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverMACDownlinks"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetMACDownlinks sets the minimum interval between downlinks that only contain MAC commands",
+        "operationId": "SetMACDownlinks",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverMACDownlinkSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetMACDownlinks sets the minimum interval between downlinks that only contain MAC commands",
+        "operationId": "SetMACDownlinks2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverMACDownlinkSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/relay": {
+      "get": {
+        "summary": "GetRelay returns the relay settings of a device and the end devices that it notified",
+        "operationId": "GetRelay",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverRelay"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetRelay sets the relay settings of a device",
+        "operationId": "SetRelay",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverRelaySettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetRelay sets the relay settings of a device",
+        "operationId": "SetRelay2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverRelaySettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/relay-served": {
+      "get": {
+        "summary": "GetRelayServed returns the relay that serves an end device",
+        "operationId": "GetRelayServed",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverRelayServed"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetRelayServed sets the relay that serves an end device",
+        "operationId": "SetRelayServed",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverRelayServedSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetRelayServed sets the relay that serves an end device",
+        "operationId": "SetRelayServed2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverRelayServedSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/static-adr": {
+      "get": {
+        "summary": "GetStaticADR returns the static ADR settings of a device",
+        "operationId": "GetStaticADR",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverStaticADR"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "delete": {
+        "summary": "DeleteStaticADR cancels static ADR settings that were not sent yet",
+        "operationId": "DeleteStaticADR",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetStaticADR sends static ADR settings to a device that does not use ADR",
+        "operationId": "SetStaticADR",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverStaticADRSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetStaticADR sends static ADR settings to a device that does not use ADR",
+        "operationId": "SetStaticADR2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverStaticADRSettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/mac-commands": {
+      "get": {
+        "summary": "GetMACCommands returns the MAC commands that the device did not answer yet",
+        "operationId": "GetMACCommands",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverMACCommands"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/airtime": {
+      "get": {
+        "summary": "GetAirtime returns the uplink airtime of a device",
+        "operationId": "GetAirtime",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverAirtime"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/quarantine": {
+      "get": {
+        "summary": "GetQuarantine returns whether a device is quarantined after a replay attack",
+        "operationId": "GetQuarantine",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverQuarantine"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "delete": {
+        "summary": "ClearQuarantine lifts the quarantine of a device",
+        "operationId": "ClearQuarantine",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/devices/{app_eui}/{dev_eui}/data": {
+      "delete": {
+        "summary": "EraseData erases the data that was collected about a device",
+        "operationId": "EraseData",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    }
+,
+    "/devices/{app_eui}/{dev_eui}/transfer": {
+      "post": {
+        "summary": "Transfer moves a device to another application of the same Handler",
+        "operationId": "Transfer",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "dev_eui",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverTransferRequest"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/applications/{app_id}/frame-history": {
+      "get": {
+        "summary": "GetApplicationFrameHistory returns the depth and retention of the frame history of the devices of an application",
+        "operationId": "GetApplicationFrameHistory",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverFrameHistory"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetApplicationFrameHistory sets the depth and retention of the frame history of the devices of an application that do not have settings of their own",
+        "operationId": "SetApplicationFrameHistory",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverFrameHistorySettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetApplicationFrameHistory sets the depth and retention of the frame history of the devices of an application that do not have settings of their own",
+        "operationId": "SetApplicationFrameHistory2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverFrameHistorySettings"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    },
+    "/applications/{app_id}/gateway-groups": {
+      "get": {
+        "summary": "GetApplicationGatewayGroups returns the gateway groups that the devices of an application prefer for downlink",
+        "operationId": "GetApplicationGatewayGroups",
+        "responses": {
+          "200": {
+            "description": "",
+            "schema": {
+              "$ref": "#/definitions/networkserverGatewayGroups"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "post": {
+        "summary": "SetApplicationGatewayGroups sets the gateway groups that the devices of an application prefer for downlink, unless they have preferences of their own",
+        "operationId": "SetApplicationGatewayGroups",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverGatewayGroups"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      },
+      "put": {
+        "summary": "SetApplicationGatewayGroups sets the gateway groups that the devices of an application prefer for downlink, unless they have preferences of their own",
+        "operationId": "SetApplicationGatewayGroups2",
+        "responses": {
+          "204": {
+            "description": ""
+          }
+        },
+        "parameters": [
+          {
+            "name": "app_id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/networkserverGatewayGroups"
+            }
+          }
+        ],
+        "tags": [
+          "NetworkServer"
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "lorawanDevice": {
+      "type": "object",
+      "properties": {
+        "app_eui": {
+          "type": "string",
+          "format": "byte",
+          "description": "The AppEUI is a unique, 8 byte identifier for the application a device belongs to."
+        },
+        "dev_eui": {
+          "type": "string",
+          "format": "byte",
+          "description": "The DevEUI is a unique, 8 byte identifier for the device."
+        },
+        "app_id": {
+          "type": "string",
+          "description": "The AppID is a unique identifier for the application a device belongs to. It can contain lowercase letters, numbers, - and _."
+        },
+        "dev_id": {
+          "type": "string",
+          "description": "The DevID is a unique identifier for the device. It can contain lowercase letters, numbers, - and _."
+        },
+        "dev_addr": {
+          "type": "string",
+          "format": "byte",
+          "description": "The DevAddr is a dynamic, 4 byte session address for the device."
+        },
+        "nwk_s_key": {
+          "type": "string",
+          "format": "byte",
+          "description": "The NwkSKey is a 16 byte session key that is known by the device and the network. It is used for routing and MAC related functionality.\nThis key is negotiated during the OTAA join procedure, or statically configured using ABP."
+        },
+        "app_s_key": {
+          "type": "string",
+          "format": "byte",
+          "description": "The AppSKey is a 16 byte session key that is known by the device and the application. It is used for payload encryption.\nThis key is negotiated during the OTAA join procedure, or statically configured using ABP."
+        },
+        "app_key": {
+          "type": "string",
+          "format": "byte",
+          "description": "The AppKey is a 16 byte static key that is known by the device and the application. It is used for negotiating session keys (OTAA)."
+        },
+        "f_cnt_up": {
+          "type": "integer",
+          "format": "int64",
+          "description": "FCntUp is the uplink frame counter for a device session."
+        },
+        "f_cnt_down": {
+          "type": "integer",
+          "format": "int64",
+          "description": "FCntDown is the downlink frame counter for a device session."
+        },
+        "disable_f_cnt_check": {
+          "type": "boolean",
+          "format": "boolean",
+          "description": "The DisableFCntCheck option disables the frame counter check. Disabling this makes the device vulnerable to replay attacks, but makes ABP slightly easier."
+        },
+        "uses32_bit_f_cnt": {
+          "type": "boolean",
+          "format": "boolean",
+          "description": "The Uses32BitFCnt option indicates that the device keeps track of full 32 bit frame counters. As only the 16 lsb are actually transmitted, the 16 msb will have to be inferred."
+        },
+        "activation_constraints": {
+          "type": "string",
+          "description": "The ActivationContstraints are used to allocate a device address for a device (comma-separated).\nThere are different prefixes for \u0060otaa\u0060, \u0060abp\u0060, \u0060world\u0060, \u0060local\u0060, \u0060private\u0060, \u0060testing\u0060."
+        },
+        "disable_adr": {
+          "type": "boolean",
+          "format": "boolean",
+          "description": "The DisableADR option prevents the NetworkServer from sending ADR commands to the device, even if the device requests ADR."
+        },
+        "last_seen": {
+          "type": "string",
+          "format": "int64",
+          "title": "When the device was last seen (Unix nanoseconds)"
+        },
+        "gateway_groups": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "The names of the gateway groups that the device prefers for downlink, most preferred first.\nDevices without preferences use those of their application."
+        }
+      }
+    },
+    "networkserverStatus": {
+      "type": "object",
+      "properties": {
+        "system": {
+          "type": "object",
+          "description": "System statistics"
+        },
+        "component": {
+          "type": "object",
+          "description": "Component statistics"
+        },
+        "uplink": {
+          "type": "object",
+          "description": "Rates of uplink messages"
+        },
+        "downlink": {
+          "type": "object",
+          "description": "Rates of downlink messages"
+        },
+        "activations": {
+          "type": "object",
+          "description": "Rates of activations"
+        },
+        "devices_per_address": {
+          "type": "object",
+          "description": "Percentiles of devices per address"
+        }
+      }
+    },
+    "networkserverADRDecision": {
+      "type": "object",
+      "properties": {
+        "time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "frames": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "max_snr": {
+          "type": "number",
+          "format": "float"
+        },
+        "margin": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "loss_percentage": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "previous_data_rate": {
+          "type": "string"
+        },
+        "previous_tx_power": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "previous_nb_trans": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "data_rate": {
+          "type": "string"
+        },
+        "tx_power": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "nb_trans": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "reason": {
+          "type": "string",
+          "description": "Reason explains the decision"
+        }
+      }
+    },
+    "networkserverADRHistory": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "band": {
+          "type": "string"
+        },
+        "margin": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "data_rate": {
+          "type": "string"
+        },
+        "tx_power": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "nb_trans": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "send_req": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "failed": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "fallback": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "rejection": {
+          "type": "string"
+        },
+        "rejected_data_rate": {
+          "type": "string"
+        },
+        "rejected_tx_power": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "decisions": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/networkserverADRDecision"
+          }
+        }
+      }
+    },
+    "networkserverTransferRequest": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        }
+      }
+    },
+    "networkserverStaticADRSettings": {
+      "type": "object",
+      "properties": {
+        "data_rate": {
+          "type": "string"
+        },
+        "tx_power": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "channels": {
+          "type": "array",
+          "items": {
+            "type": "integer",
+            "format": "int32"
+          }
+        }
+      }
+    },
+    "networkserverADRLimits": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "min_data_rate": {
+          "type": "string"
+        },
+        "max_data_rate": {
+          "type": "string"
+        },
+        "max_tx_power": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "networkserverADRLimitsSettings": {
+      "type": "object",
+      "properties": {
+        "min_data_rate": {
+          "type": "string"
+        },
+        "max_data_rate": {
+          "type": "string"
+        },
+        "max_tx_power": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "networkserverLoRaWANVersion": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "lorawan_version": {
+          "type": "string"
+        }
+      }
+    },
+    "networkserverLoRaWANVersionSettings": {
+      "type": "object",
+      "properties": {
+        "lorawan_version": {
+          "type": "string"
+        }
+      }
+    },
+    "networkserverFrameHistory": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "depth": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "ttl": {
+          "type": "string"
+        },
+        "max_depth": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "max_ttl": {
+          "type": "string"
+        }
+      }
+    },
+    "networkserverFrameHistorySettings": {
+      "type": "object",
+      "properties": {
+        "depth": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "ttl": {
+          "type": "string"
+        }
+      }
+    },
+    "networkserverGatewayGroups": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "gateway_groups": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "Names of gateway groups in the configuration of the Broker, most preferred first"
+        }
+      }
+    },
+    "networkserverMACDownlinks": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "interval": {
+          "type": "string"
+        },
+        "disabled": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "last_sent": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "networkserverMACDownlinkSettings": {
+      "type": "object",
+      "properties": {
+        "interval": {
+          "type": "string"
+        },
+        "disabled": {
+          "type": "boolean",
+          "format": "boolean"
+        }
+      }
+    },
+    "networkserverRelay": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "enabled": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "cad_periodicity": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "default_channel_index": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "second_channel_frequency": {
+          "type": "integer",
+          "format": "uint64"
+        },
+        "second_channel_data_rate": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "second_channel_ack_offset": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "limits": {
+          "$ref": "#/definitions/networkserverRelayLimits"
+        },
+        "send_conf_req": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "send_limits_req": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "rejection": {
+          "type": "string"
+        },
+        "end_devices": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/networkserverRelayEndDevice"
+          }
+        },
+        "uplink_list": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/networkserverRelayUplinkListEntry"
+          }
+        }
+      }
+    },
+    "networkserverRelayEndDevice": {
+      "type": "object",
+      "properties": {
+        "dev_addr": {
+          "type": "string"
+        },
+        "rssi": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "snr": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "notified_at": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "networkserverRelayLimits": {
+      "type": "object",
+      "properties": {
+        "join_requests": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "notifications": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "global_uplinks": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "overall": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "bucket_size": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "networkserverRelayServed": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "enabled": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "relay_app_eui": {
+          "type": "string"
+        },
+        "relay_dev_eui": {
+          "type": "string"
+        },
+        "mode": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "smart_enable_level": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "backoff": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "reload_rate": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "bucket_size": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "send_conf_req": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "rejection": {
+          "type": "string"
+        }
+      }
+    },
+    "networkserverRelayServedSettings": {
+      "type": "object",
+      "properties": {
+        "enabled": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "relay_app_eui": {
+          "type": "string"
+        },
+        "relay_dev_eui": {
+          "type": "string"
+        },
+        "mode": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "smart_enable_level": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "backoff": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "reload_rate": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "bucket_size": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "networkserverRelaySettings": {
+      "type": "object",
+      "properties": {
+        "enabled": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "cad_periodicity": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "default_channel_index": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "second_channel_frequency": {
+          "type": "integer",
+          "format": "uint64"
+        },
+        "second_channel_data_rate": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "second_channel_ack_offset": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "limits": {
+          "$ref": "#/definitions/networkserverRelayLimits"
+        }
+      }
+    },
+    "networkserverRelayUplinkListEntry": {
+      "type": "object",
+      "properties": {
+        "app_eui": {
+          "type": "string"
+        },
+        "dev_eui": {
+          "type": "string"
+        },
+        "index": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "send_req": {
+          "type": "boolean",
+          "format": "boolean"
+        }
+      }
+    },
+    "networkserverStaticADR": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "data_rate": {
+          "type": "string"
+        },
+        "tx_power": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "channels": {
+          "type": "array",
+          "items": {
+            "type": "integer",
+            "format": "int32"
+          }
+        },
+        "send_req": {
+          "type": "boolean",
+          "format": "boolean"
+        }
+      }
+    },
+    "networkserverPendingMACCommand": {
+      "type": "object",
+      "properties": {
+        "name": {
+          "type": "string"
+        },
+        "cid": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "payload": {
+          "type": "string",
+          "format": "byte"
+        },
+        "f_cnt_down": {
+          "type": "integer",
+          "format": "int64",
+          "description": "FCntDown of the last downlink that contained the command"
+        },
+        "sent_at": {
+          "type": "string",
+          "format": "date-time",
+          "description": "Time of the last downlink that contained the command"
+        },
+        "attempts": {
+          "type": "integer",
+          "format": "int32",
+          "description": "Number of downlinks that contained the command"
+        },
+        "expired": {
+          "type": "boolean",
+          "format": "boolean"
+        }
+      }
+    },
+    "networkserverMACCommands": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "pending": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/networkserverPendingMACCommand"
+          }
+        }
+      }
+    },
+    "networkserverLinkQuality": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "since": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "interval": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Duration of each point in nanoseconds"
+        },
+        "score": {
+          "$ref": "#/definitions/networkserverLinkScore"
+        },
+        "points": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/networkserverLinkQualityPoint"
+          }
+        }
+      }
+    },
+    "networkserverLinkQualityPoint": {
+      "type": "object",
+      "properties": {
+        "time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "frames": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "min_snr": {
+          "type": "number",
+          "format": "float"
+        },
+        "avg_snr": {
+          "type": "number",
+          "format": "float"
+        },
+        "max_snr": {
+          "type": "number",
+          "format": "float"
+        },
+        "min_rssi": {
+          "type": "number",
+          "format": "float"
+        },
+        "avg_rssi": {
+          "type": "number",
+          "format": "float"
+        },
+        "max_rssi": {
+          "type": "number",
+          "format": "float"
+        },
+        "avg_margin": {
+          "type": "number",
+          "format": "float",
+          "description": "Average SNR above the demodulation floor of the data rate"
+        },
+        "data_rates": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "int32"
+          },
+          "description": "Number of uplinks per data rate"
+        },
+        "missed": {
+          "type": "integer",
+          "format": "int32",
+          "description": "Number of uplinks that were missed according to the frame counters"
+        },
+        "loss_percentage": {
+          "type": "integer",
+          "format": "int32"
+        }
+      }
+    },
+    "networkserverLinkScore": {
+      "type": "object",
+      "properties": {
+        "frames": {
+          "type": "integer",
+          "format": "uint64",
+          "description": "Number of uplinks that were received"
+        },
+        "missed": {
+          "type": "integer",
+          "format": "uint64",
+          "description": "Number of uplinks that were missed"
+        },
+        "loss": {
+          "type": "array",
+          "items": {
+            "type": "number",
+            "format": "double"
+          },
+          "description": "Moving average of the frame loss (0-1) over 20, 100 and 1000 frames"
+        },
+        "margin": {
+          "type": "number",
+          "format": "double",
+          "description": "Moving average of the SNR above the demodulation floor"
+        },
+        "score": {
+          "type": "integer",
+          "format": "int32",
+          "description": "Link score from 0 (no link) to 100 (perfect link)"
+        },
+        "quality": {
+          "type": "string",
+          "description": "good, fair or poor"
+        },
+        "last_f_cnt": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "updated_at": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "networkserverUsage": {
+      "type": "object",
+      "properties": {
+        "day": {
+          "type": "string"
+        },
+        "today": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Airtime of today in nanoseconds"
+        },
+        "total": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Total airtime in nanoseconds"
+        },
+        "messages": {
+          "type": "integer",
+          "format": "int64"
+        }
+      }
+    },
+    "networkserverFairAccess": {
+      "type": "object",
+      "properties": {
+        "uplink_airtime": {
+          "type": "integer",
+          "format": "int64",
+          "description": "Uplink airtime in the last 24 hours in nanoseconds"
+        },
+        "max_uplink_airtime": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "downlinks": {
+          "type": "integer",
+          "format": "int32",
+          "description": "Downlinks in the last 24 hours"
+        },
+        "max_downlinks": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "violation": {
+          "type": "string"
+        },
+        "violation_since": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "networkserverAirtime": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "uplink": {
+          "$ref": "#/definitions/networkserverUsage"
+        },
+        "fair_access": {
+          "$ref": "#/definitions/networkserverFairAccess"
+        }
+      }
+    },
+    "networkserverQuarantine": {
+      "type": "object",
+      "properties": {
+        "app_id": {
+          "type": "string"
+        },
+        "dev_id": {
+          "type": "string"
+        },
+        "quarantined": {
+          "type": "boolean",
+          "format": "boolean"
+        },
+        "reason": {
+          "type": "string"
+        },
+        "since": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    }
+  },
+  "securityDefinitions": {
+    "bearer": {
+      "type": "apiKey",
+      "name": "Authorization",
+      "in": "header"
+    }
+  },
+  "security": [
+    {
+      "bearer": []
+    }
+  ]
+}
+`
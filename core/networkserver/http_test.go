@@ -0,0 +1,115 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHTTPHandler")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+	handler := ns.HTTPHandler()
+
+	request := func(method, path string) int {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(method, path, nil))
+		return res.Code
+	}
+
+	a.So(request("POST", "/devices/0102030405060708/0102030405060708/adr-history"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices"), ShouldEqual, http.StatusNotFound)
+	a.So(request("GET", "/devices/invalid/0102030405060708/adr-history"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("GET", "/devices/0102030405060708/invalid/adr-history"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("GET", "/devices/invalid/0102030405060708/mac-commands"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("GET", "/devices/invalid/0102030405060708/airtime"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("DELETE", "/devices/0102030405060708/0102030405060708/adr-history"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/unknown"), ShouldEqual, http.StatusNotFound)
+	a.So(request("DELETE", "/devices/invalid/0102030405060708/data"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("POST", "/status"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("PATCH", "/devices/0102030405060708/0102030405060708"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/invalid/0102030405060708"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("PUT", "/devices/invalid/0102030405060708"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("DELETE", "/devices/invalid/0102030405060708"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("PATCH", "/devices/0102030405060708/0102030405060708/static-adr"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/invalid/0102030405060708/static-adr"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("PUT", "/devices/invalid/0102030405060708/static-adr"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("DELETE", "/devices/invalid/0102030405060708/static-adr"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/transfer"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("POST", "/devices/invalid/0102030405060708/transfer"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("POST", "/devices/0102030405060708/0102030405060708/quarantine"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/invalid/0102030405060708/quarantine"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("DELETE", "/devices/invalid/0102030405060708/quarantine"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("DELETE", "/devices/0102030405060708/0102030405060708/frame-history"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/invalid/0102030405060708/frame-history"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("PUT", "/devices/invalid/0102030405060708/frame-history"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("DELETE", "/devices/0102030405060708/0102030405060708/mac-downlinks"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/invalid/0102030405060708/mac-downlinks"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("PUT", "/devices/invalid/0102030405060708/mac-downlinks"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("DELETE", "/devices/0102030405060708/0102030405060708/relay"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/invalid/0102030405060708/relay"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("PUT", "/devices/invalid/0102030405060708/relay"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("DELETE", "/devices/0102030405060708/0102030405060708/relay-served"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/invalid/0102030405060708/relay-served"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("PUT", "/devices/invalid/0102030405060708/relay-served"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("POST", "/devices/0102030405060708/0102030405060708/link-quality"), ShouldEqual, http.StatusMethodNotAllowed)
+	a.So(request("GET", "/devices/invalid/0102030405060708/link-quality"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/link-quality?interval=invalid"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/link-quality?interval=1s"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/link-quality?since=-1h"), ShouldEqual, http.StatusBadRequest)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/link-quality?interval=1m&since=720h"), ShouldEqual, http.StatusBadRequest)
+
+	// Without token
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/adr-history"), ShouldNotEqual, http.StatusOK)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/mac-commands"), ShouldNotEqual, http.StatusOK)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/airtime"), ShouldNotEqual, http.StatusOK)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/link-quality"), ShouldNotEqual, http.StatusOK)
+	a.So(request("DELETE", "/devices/0102030405060708/0102030405060708/data"), ShouldNotEqual, http.StatusNoContent)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708"), ShouldNotEqual, http.StatusOK)
+	a.So(request("DELETE", "/devices/0102030405060708/0102030405060708"), ShouldNotEqual, http.StatusNoContent)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/static-adr"), ShouldNotEqual, http.StatusOK)
+	a.So(request("PUT", "/devices/0102030405060708/0102030405060708/static-adr"), ShouldNotEqual, http.StatusNoContent)
+	a.So(request("GET", "/devices/0102030405060708/0102030405060708/quarantine"), ShouldNotEqual, http.StatusOK)
+	a.So(request("DELETE", "/devices/0102030405060708/0102030405060708/quarantine"), ShouldNotEqual, http.StatusNoContent)
+}
+
+func TestHTTPRoutesMatchSwagger(t *testing.T) {
+	a := New(t)
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	a.So(json.Unmarshal([]byte(SwaggerJSON), &spec), ShouldBeNil)
+
+	var documented []string
+	for path, methods := range spec.Paths {
+		for method := range methods {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(documented)
+
+	var routed []string
+	for _, route := range httpRoutes {
+		for method := range route.methods {
+			routed = append(routed, method+" "+route.path)
+		}
+	}
+	sort.Strings(routed)
+
+	a.So(routed, ShouldResemble, documented)
+}
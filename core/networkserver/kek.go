@@ -0,0 +1,59 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	pb_handler "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/kek"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// UseKEKs makes the NetworkServer unwrap the session keys that Handlers wrapped with the KEKs in the store
+func (n *networkServer) UseKEKs(store kek.Store) {
+	n.keks = store
+}
+
+// unwrapNwkSKey returns the NwkSKey that the Handler wrapped with a KEK and
+// sent in the metadata, or nil if the metadata does not contain a NwkSKey
+func (n *networkServer) unwrapNwkSKey(md metadata.MD) (*types.NwkSKey, error) {
+	envelope, err := kek.FromMetadata(md, kek.NwkSKey)
+	if err != nil || envelope == nil {
+		return nil, err
+	}
+	key, err := kek.Unwrap(n.keks, envelope)
+	if err != nil {
+		return nil, err
+	}
+	var nwkSKey types.NwkSKey
+	if len(key) != len(nwkSKey) {
+		return nil, errors.NewErrInvalidArgument("NwkSKey", "must be 16 bytes")
+	}
+	copy(nwkSKey[:], key)
+	return &nwkSKey, nil
+}
+
+// unwrapSessionKeys sets the NwkSKey of the activation to the NwkSKey that the
+// Handler wrapped with a KEK and sent in the metadata of the request, if any
+func (n *networkServer) unwrapSessionKeys(md metadata.MD, activation *pb_handler.DeviceActivationResponse) error {
+	lorawan := activation.GetActivationMetadata().GetLorawan()
+	if lorawan == nil {
+		return nil
+	}
+	nwkSKey, err := n.unwrapNwkSKey(md)
+	if err != nil || nwkSKey == nil {
+		return err
+	}
+	lorawan.NwkSKey = nwkSKey
+	return nil
+}
+
+// stripSessionKeys removes the NwkSKey from the activation that is returned
+// to the Broker, which does not need it
+func stripSessionKeys(activation *pb_handler.DeviceActivationResponse) {
+	if lorawan := activation.GetActivationMetadata().GetLorawan(); lorawan != nil && lorawan.NwkSKey != nil {
+		lorawan.NwkSKey = &types.NwkSKey{}
+	}
+}
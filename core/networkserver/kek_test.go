@@ -0,0 +1,65 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	pb_handler "github.com/TheThingsNetwork/ttn/api/handler"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/kek"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnwrapSessionKeys(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{}
+
+	nwkSKey := types.NwkSKey{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+	activation := func() *pb_handler.DeviceActivationResponse {
+		return &pb_handler.DeviceActivationResponse{
+			ActivationMetadata: &pb_protocol.ActivationMetadata{Protocol: &pb_protocol.ActivationMetadata_Lorawan{
+				Lorawan: &pb_lorawan.ActivationMetadata{NwkSKey: &types.NwkSKey{}},
+			}},
+		}
+	}
+
+	store := kek.NewRedisStore(GetRedisClient(), "test-ns-unwrap")
+	defer store.Delete("ns")
+	a.So(store.Set("ns", []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}), ShouldBeNil)
+	envelope, err := kek.Wrap(store, "ns", nwkSKey.Bytes())
+	a.So(err, ShouldBeNil)
+	md := envelope.Metadata(kek.NwkSKey)
+
+	// Without metadata, the activation is not changed
+	res := activation()
+	a.So(ns.unwrapSessionKeys(metadata.MD{}, res), ShouldBeNil)
+	a.So(*res.GetActivationMetadata().GetLorawan().NwkSKey, ShouldEqual, types.NwkSKey{})
+
+	// Without the KEK, the key can not be unwrapped
+	a.So(ns.unwrapSessionKeys(md, activation()), ShouldNotBeNil)
+
+	ns.UseKEKs(store)
+	res = activation()
+	a.So(ns.unwrapSessionKeys(md, res), ShouldBeNil)
+	a.So(*res.GetActivationMetadata().GetLorawan().NwkSKey, ShouldEqual, nwkSKey)
+}
+
+func TestStripSessionKeys(t *testing.T) {
+	a := New(t)
+
+	nwkSKey := types.NwkSKey{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+	res := &pb_handler.DeviceActivationResponse{
+		ActivationMetadata: &pb_protocol.ActivationMetadata{Protocol: &pb_protocol.ActivationMetadata_Lorawan{
+			Lorawan: &pb_lorawan.ActivationMetadata{NwkSKey: &nwkSKey},
+		}},
+	}
+	stripSessionKeys(res)
+	a.So(*res.GetActivationMetadata().GetLorawan().NwkSKey, ShouldEqual, types.NwkSKey{})
+	a.So(nwkSKey, ShouldNotEqual, types.NwkSKey{})
+}
@@ -0,0 +1,136 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+)
+
+// LinkQualityPoint contains the aggregated link quality of the frames in the
+// frame history of a device in one interval of the link quality trend
+type LinkQualityPoint struct {
+	Time   time.Time `json:"time"`
+	Frames int       `json:"frames"`
+
+	MinSNR float32 `json:"min_snr"`
+	AvgSNR float32 `json:"avg_snr"`
+	MaxSNR float32 `json:"max_snr"`
+
+	MinRSSI float32 `json:"min_rssi"`
+	AvgRSSI float32 `json:"avg_rssi"`
+	MaxRSSI float32 `json:"max_rssi"`
+
+	// AvgMargin is the average SNR above the demodulation floor of the data
+	// rate. It is only set if the data rates of the interval have a known floor.
+	AvgMargin *float32 `json:"avg_margin,omitempty"`
+
+	// DataRates contains the number of uplinks per data rate
+	DataRates map[string]int `json:"data_rates"`
+
+	// Missed is the number of uplinks that were missed in the interval
+	Missed         int `json:"missed"`
+	LossPercentage int `json:"loss_percentage"`
+
+	margins int
+}
+
+type byTime []*LinkQualityPoint
+
+func (a byTime) Len() int           { return len(a) }
+func (a byTime) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byTime) Less(i, j int) bool { return a[i].Time.Before(a[j].Time) }
+
+// handleUplinkLinkQuality updates the link score of the device. If the quality
+// level of the link changes, the change is added to the message.
+func (n *networkServer) handleUplinkLinkQuality(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) {
+	var margin *float32
+	dataRate := uplinkDataRate(message.GetProtocolMetadata().GetLorawan())
+	if _, ok := demodulationFloor[dataRate]; ok && len(message.GatewayMetadata) > 0 {
+		margin = new(float32)
+		*margin = linkMargin(dataRate, bestGateway(message.GatewayMetadata).Snr)
+	}
+	previousQuality := dev.LinkScore.Quality
+	dev.LinkScore.AddFrame(dev.FCntUp, margin, dev.LastSeen)
+	if previousQuality != "" && previousQuality != dev.LinkScore.Quality && dev.LinkScore.Frames >= uint64(device.LinkScoreHorizons[0]) {
+		message.LinkQuality = &pb_broker.LinkQualityChange{
+			Quality:  dev.LinkScore.Quality,
+			Previous: previousQuality,
+			Score:    uint32(dev.LinkScore.Score),
+			Loss:     dev.LinkScore.Loss[1],
+		}
+	}
+}
+
+// linkQualityTrend downsamples the frames of the frame history since the given
+// time to one point per interval. Intervals without frames are left out. The
+// uplinks that were missed are counted from the gaps between the frame counters.
+func linkQualityTrend(frames []*device.Frame, since time.Time, interval time.Duration) []*LinkQualityPoint {
+	points := make(map[int64]*LinkQualityPoint)
+	for i, frame := range frames {
+		if frame.Time.Before(since) {
+			continue
+		}
+		start := frame.Time.Truncate(interval)
+		point, ok := points[start.UnixNano()]
+		if !ok {
+			point = &LinkQualityPoint{
+				Time:      start,
+				MinSNR:    frame.SNR,
+				MaxSNR:    frame.SNR,
+				MinRSSI:   frame.RSSI,
+				MaxRSSI:   frame.RSSI,
+				DataRates: make(map[string]int),
+			}
+			points[start.UnixNano()] = point
+		}
+		point.Frames++
+		point.AvgSNR += frame.SNR
+		point.AvgRSSI += frame.RSSI
+		if frame.SNR < point.MinSNR {
+			point.MinSNR = frame.SNR
+		}
+		if frame.SNR > point.MaxSNR {
+			point.MaxSNR = frame.SNR
+		}
+		if frame.RSSI < point.MinRSSI {
+			point.MinRSSI = frame.RSSI
+		}
+		if frame.RSSI > point.MaxRSSI {
+			point.MaxRSSI = frame.RSSI
+		}
+		if frame.DataRate != "" {
+			point.DataRates[frame.DataRate]++
+		}
+		if i+1 < len(frames) { // The history is sorted newest first
+			if previous := frames[i+1]; frame.FCnt > previous.FCnt {
+				point.Missed += int(frame.FCnt - previous.FCnt - 1)
+			}
+		}
+		if _, ok := demodulationFloor[frame.DataRate]; ok {
+			if point.AvgMargin == nil {
+				point.AvgMargin = new(float32)
+			}
+			*point.AvgMargin += linkMargin(frame.DataRate, frame.SNR)
+			point.margins++
+		}
+	}
+
+	trend := make([]*LinkQualityPoint, 0, len(points))
+	for _, point := range points {
+		point.AvgSNR /= float32(point.Frames)
+		point.AvgRSSI /= float32(point.Frames)
+		if point.AvgMargin != nil {
+			*point.AvgMargin /= float32(point.margins)
+		}
+		point.LossPercentage = int(math.Floor(float64(point.Missed)/float64(point.Frames+point.Missed)*100 + .5))
+		trend = append(trend, point)
+	}
+	sort.Sort(byTime(trend))
+	return trend
+}
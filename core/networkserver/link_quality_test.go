@@ -0,0 +1,104 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestHandleUplinkLinkQuality(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleUplinkLinkQuality")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+
+	appEUI := types.AppEUI([8]byte{1})
+	devEUI := types.DevEUI([8]byte{1})
+	dev := &device.Device{AppEUI: appEUI, DevEUI: devEUI, FCntUp: 42, LastSeen: time.Now()}
+
+	message := adrInitUplinkMessage()
+	message.GatewayMetadata = []*pb_gateway.RxMetadata{
+		&pb_gateway.RxMetadata{Snr: 2, Rssi: -110},
+		&pb_gateway.RxMetadata{Snr: 8.5, Rssi: -90},
+		&pb_gateway.RxMetadata{Snr: -3, Rssi: -120},
+	}
+	ns.handleUplinkLinkQuality(message, dev)
+	a.So(dev.LinkScore.Frames, ShouldEqual, 1)
+	a.So(dev.LinkScore.Missed, ShouldEqual, 0)
+
+	// The order of the gateway metadata is not changed
+	a.So(message.GatewayMetadata[0].Snr, ShouldEqual, 2)
+
+	for i := 0; i < 30; i++ {
+		dev.FCntUp++
+		ns.handleUplinkLinkQuality(adrInitUplinkMessage(), dev)
+	}
+	a.So(dev.LinkScore.Quality, ShouldEqual, device.LinkQualityGood)
+
+	// Losing every other uplink degrades the link
+	var events int
+	for i := 0; i < 30; i++ {
+		dev.FCntUp += 2
+		message := adrInitUplinkMessage()
+		ns.handleUplinkLinkQuality(message, dev)
+		if change := message.LinkQuality; change != nil {
+			events++
+			a.So(change.Previous, ShouldEqual, device.LinkQualityGood)
+			a.So(change.Quality, ShouldEqual, device.LinkQualityFair)
+		}
+	}
+	a.So(events, ShouldEqual, 1)
+	a.So(dev.LinkScore.Missed, ShouldEqual, 30)
+}
+
+func TestLinkQualityTrend(t *testing.T) {
+	a := New(t)
+
+	start := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	frames := []*device.Frame{ // Newest first, like the history
+		{FCnt: 15, Time: start.Add(3*time.Hour + 10*time.Minute), DataRate: "50000", SNR: 0, RSSI: -70},
+		{FCnt: 14, Time: start.Add(time.Hour + 40*time.Minute), DataRate: "SF7BW125", SNR: 4, RSSI: -100},
+		{FCnt: 11, Time: start.Add(time.Hour + 20*time.Minute), DataRate: "SF9BW125", SNR: -2, RSSI: -110},
+		{FCnt: 10, Time: start.Add(10 * time.Minute), DataRate: "SF7BW125", SNR: 10, RSSI: -80},
+		{FCnt: 9, Time: start.Add(-10 * time.Minute), DataRate: "SF7BW125", SNR: 10, RSSI: -80},
+	}
+
+	trend := linkQualityTrend(frames, start, time.Hour)
+	a.So(trend, ShouldHaveLength, 3)
+
+	a.So(trend[0].Time, ShouldResemble, start)
+	a.So(trend[0].Frames, ShouldEqual, 1)
+	a.So(trend[0].AvgSNR, ShouldEqual, 10)
+	a.So(*trend[0].AvgMargin, ShouldEqual, 17.5)
+
+	a.So(trend[1].Time, ShouldResemble, start.Add(time.Hour))
+	a.So(trend[1].Frames, ShouldEqual, 2)
+	a.So(trend[1].MinSNR, ShouldEqual, -2)
+	a.So(trend[1].AvgSNR, ShouldEqual, 1)
+	a.So(trend[1].MaxSNR, ShouldEqual, 4)
+	a.So(trend[1].MinRSSI, ShouldEqual, -110)
+	a.So(trend[1].AvgRSSI, ShouldEqual, -105)
+	a.So(trend[1].MaxRSSI, ShouldEqual, -100)
+	a.So(*trend[1].AvgMargin, ShouldEqual, 11)
+	a.So(trend[1].DataRates, ShouldResemble, map[string]int{"SF7BW125": 1, "SF9BW125": 1})
+	a.So(trend[1].Missed, ShouldEqual, 2)
+	a.So(trend[1].LossPercentage, ShouldEqual, 50)
+
+	// FSK has no demodulation floor
+	a.So(trend[2].Time, ShouldResemble, start.Add(3*time.Hour))
+	a.So(trend[2].AvgMargin, ShouldBeNil)
+	a.So(trend[2].DataRates, ShouldResemble, map[string]int{"50000": 1})
+	a.So(trend[2].Missed, ShouldEqual, 0)
+
+	a.So(linkQualityTrend(nil, start, time.Hour), ShouldBeEmpty)
+}
@@ -0,0 +1,47 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// LoRaWANVersions are the LoRaWAN versions of devices that the NetworkServer supports
+var LoRaWANVersions = []string{"1.0", "1.0.1", "1.0.2"}
+
+func validateLoRaWANVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	for _, supported := range LoRaWANVersions {
+		if version == supported {
+			return nil
+		}
+	}
+	return errors.NewErrInvalidArgument("LoRaWAN Version", fmt.Sprintf("must be %s", strings.Join(LoRaWANVersions, ", ")))
+}
+
+// supportsLoRaWAN102 returns whether the device supports the MAC commands
+// that were added in LoRaWAN 1.0.2. This is assumed for devices of which the
+// LoRaWAN version is not known.
+func supportsLoRaWAN102(dev *device.Device) bool {
+	return dev.LoRaWANVersion == "" || dev.LoRaWANVersion == "1.0.2"
+}
+
+// setLoRaWANVersion sets the LoRaWAN version of the device
+func (n *networkServer) setLoRaWANVersion(dev *device.Device, version string) error {
+	if err := validateLoRaWANVersion(version); err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	dev.LoRaWANVersion = version
+	if !supportsLoRaWAN102(dev) {
+		dev.TxParams.SendReq = false
+	}
+	return n.devices.Set(dev)
+}
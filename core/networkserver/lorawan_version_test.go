@@ -0,0 +1,32 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestLoRaWANVersion(t *testing.T) {
+	a := New(t)
+
+	a.So(validateLoRaWANVersion(""), ShouldBeNil)
+	a.So(validateLoRaWANVersion("1.0.1"), ShouldBeNil)
+	a.So(validateLoRaWANVersion("1.1"), ShouldNotBeNil)
+
+	a.So(supportsLoRaWAN102(&device.Device{}), ShouldBeTrue)
+	a.So(supportsLoRaWAN102(&device.Device{LoRaWANVersion: "1.0.2"}), ShouldBeTrue)
+	a.So(supportsLoRaWAN102(&device.Device{LoRaWANVersion: "1.0.1"}), ShouldBeFalse)
+
+	// Devices before LoRaWAN 1.0.2 do not get a TxParamSetupReq
+	ns := &networkServer{}
+	dev := &device.Device{LoRaWANVersion: "1.0"}
+	message := adrInitUplinkMessage()
+	message.ProtocolMetadata.GetLorawan().Region = pb_lorawan.Region_AS_923
+	ns.handleUplinkTxParams(message, dev)
+	a.So(dev.TxParams.SendReq, ShouldBeFalse)
+}
@@ -26,6 +26,18 @@ func bestSNR(metadata []*pb_gateway.RxMetadata) float32 {
 	return sorted[len(sorted)-1].Snr
 }
 
+// bestGateway returns the metadata of the gateway that received the uplink
+// with the best SNR, without changing the order of the metadata
+func bestGateway(metadata []*pb_gateway.RxMetadata) *pb_gateway.RxMetadata {
+	var best *pb_gateway.RxMetadata
+	for _, gateway := range metadata {
+		if best == nil || gateway.Snr > best.Snr {
+			best = gateway
+		}
+	}
+	return best
+}
+
 var demodulationFloor = map[string]float32{
 	"SF7BW125":  -7.5,
 	"SF8BW125":  -10,
@@ -34,6 +46,14 @@ var demodulationFloor = map[string]float32{
 	"SF11BW125": -17.5,
 	"SF12BW125": -20,
 	"SF7BW250":  -4.5,
+	"SF5BW812":  -2.5,
+	"SF6BW812":  -5,
+	"SF7BW812":  -7.5,
+	"SF8BW812":  -10,
+	"SF9BW812":  -12.5,
+	"SF10BW812": -15,
+	"SF11BW812": -17.5,
+	"SF12BW812": -20,
 }
 
 func linkMargin(dataRate string, snr float32) float32 {
@@ -0,0 +1,70 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// UseMACDownlinkInterval makes the NetworkServer send the MAC commands that
+// are pending for a device in a downlink without application payload, at most
+// once per interval. In between, the MAC commands are batched and wait for the
+// next downlink to the device. Devices can override the interval.
+func (n *networkServer) UseMACDownlinkInterval(interval time.Duration) {
+	n.macDownlinkInterval = interval
+}
+
+// getMACDownlinkInterval returns the minimum time between MAC-only downlinks
+// to the device, or zero if the NetworkServer does not schedule them
+func (n *networkServer) getMACDownlinkInterval(dev *device.Device) time.Duration {
+	if dev.MACDownlinks.Disabled {
+		return 0
+	}
+	if dev.MACDownlinks.Interval > 0 {
+		return dev.MACDownlinks.Interval
+	}
+	return n.macDownlinkInterval
+}
+
+// handleUplinkMACDownlink adds the pending MAC commands to the response
+// template if the response is sent anyway, or if the last MAC-only downlink
+// to the device is at least the MAC downlink interval ago. The response
+// template is marked, so that the MAC commands are not added again when the
+// downlink is handled.
+func (n *networkServer) handleUplinkMACDownlink(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) error {
+	interval := n.getMACDownlinkInterval(dev)
+	if interval <= 0 {
+		return nil
+	}
+	lorawanDownlinkMac := message.GetResponseTemplate().GetMessage().GetLorawan().GetMacPayload()
+	if lorawanDownlinkMac == nil {
+		return nil
+	}
+	responseNeeded := lorawanDownlinkMac.Ack || len(lorawanDownlinkMac.FOpts) > 0
+	if !responseNeeded && time.Since(dev.MACDownlinks.LastSent) < interval {
+		return nil // batch with the next downlink
+	}
+	if err := n.handleDownlinkMAC(message.ResponseTemplate, dev); err != nil {
+		return err
+	}
+	message.ResponseTemplate.MacCommandsAdded = true
+	message.Trace = message.Trace.WithEvent(trace.MACDownlinkEvent, "mac_commands", len(lorawanDownlinkMac.FOpts))
+	return nil
+}
+
+// setMACDownlinkSettings sets the MAC downlink settings of the device
+func (n *networkServer) setMACDownlinkSettings(dev *device.Device, settings device.MACDownlinkSettings) error {
+	if settings.Interval < 0 {
+		return errors.NewErrInvalidArgument("Interval", "can not be negative")
+	}
+	dev.StartUpdate()
+	settings.LastSent = dev.MACDownlinks.LastSent
+	dev.MACDownlinks = settings
+	return n.devices.Set(dev)
+}
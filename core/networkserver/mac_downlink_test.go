@@ -0,0 +1,110 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/brocaar/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func macDownlinkUplinkMessage() *pb_broker.DeduplicatedUplinkMessage {
+	message := &pb_broker.DeduplicatedUplinkMessage{}
+	message.InitResponseTemplate().Message.InitLoRaWAN().InitDownlink()
+	return message
+}
+
+func TestGetMACDownlinkInterval(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{}
+	dev := &device.Device{}
+
+	a.So(ns.getMACDownlinkInterval(dev), ShouldEqual, 0)
+
+	ns.UseMACDownlinkInterval(time.Hour)
+	a.So(ns.getMACDownlinkInterval(dev), ShouldEqual, time.Hour)
+
+	dev.MACDownlinks.Interval = 6 * time.Hour
+	a.So(ns.getMACDownlinkInterval(dev), ShouldEqual, 6*time.Hour)
+
+	dev.MACDownlinks.Disabled = true
+	a.So(ns.getMACDownlinkInterval(dev), ShouldEqual, 0)
+}
+
+func TestHandleUplinkMACDownlink(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleUplinkMACDownlink")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+	ns.InitStatus()
+
+	dev := &device.Device{AppEUI: types.AppEUI([8]byte{1}), DevEUI: types.DevEUI([8]byte{1})}
+	dev.ADR.Band = "EU_863_870"
+	dev.StaticADR = device.StaticADRSettings{SendReq: true, DataRate: "SF9BW125"}
+
+	// Disabled by default
+	message := macDownlinkUplinkMessage()
+	a.So(ns.handleUplinkMACDownlink(message, dev), ShouldBeNil)
+	a.So(message.ResponseTemplate.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+	a.So(message.ResponseTemplate.MacCommandsAdded, ShouldBeFalse)
+	a.So(dev.StaticADR.SendReq, ShouldBeTrue)
+
+	ns.UseMACDownlinkInterval(time.Hour)
+
+	// Batched within the interval
+	dev.MACDownlinks.LastSent = time.Now().Add(-10 * time.Minute)
+	message = macDownlinkUplinkMessage()
+	a.So(ns.handleUplinkMACDownlink(message, dev), ShouldBeNil)
+	a.So(message.ResponseTemplate.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+	a.So(message.ResponseTemplate.MacCommandsAdded, ShouldBeFalse)
+	a.So(dev.StaticADR.SendReq, ShouldBeTrue)
+
+	// Added to a response that is sent anyway
+	message = macDownlinkUplinkMessage()
+	message.ResponseTemplate.Message.GetLorawan().GetMacPayload().Ack = true
+	a.So(ns.handleUplinkMACDownlink(message, dev), ShouldBeNil)
+	fOpts := message.ResponseTemplate.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 1)
+	a.So(fOpts[0].Cid, ShouldEqual, lorawan.LinkADRReq)
+	a.So(message.ResponseTemplate.MacCommandsAdded, ShouldBeTrue)
+	a.So(dev.StaticADR.SendReq, ShouldBeFalse)
+
+	// Scheduled after the interval
+	dev.StaticADR.SendReq = true
+	dev.PendingMACCommands = nil
+	dev.MACDownlinks.LastSent = time.Now().Add(-2 * time.Hour)
+	message = macDownlinkUplinkMessage()
+	a.So(ns.handleUplinkMACDownlink(message, dev), ShouldBeNil)
+	a.So(message.ResponseTemplate.Message.GetLorawan().GetMacPayload().FOpts, ShouldHaveLength, 1)
+	a.So(message.ResponseTemplate.MacCommandsAdded, ShouldBeTrue)
+
+	// Not for devices that disabled MAC-only downlinks
+	dev.StaticADR.SendReq = true
+	dev.PendingMACCommands = nil
+	dev.MACDownlinks.Disabled = true
+	message = macDownlinkUplinkMessage()
+	a.So(ns.handleUplinkMACDownlink(message, dev), ShouldBeNil)
+	a.So(message.ResponseTemplate.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+}
+
+func TestSetMACDownlinkSettings(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{devices: device.NewMemoryDeviceStore()}
+	lastSent := time.Now().Add(-time.Hour)
+	dev := &device.Device{AppEUI: types.AppEUI([8]byte{1}), DevEUI: types.DevEUI([8]byte{1})}
+	dev.MACDownlinks.LastSent = lastSent
+
+	a.So(ns.setMACDownlinkSettings(dev, device.MACDownlinkSettings{Interval: -time.Hour}), ShouldNotBeNil)
+	a.So(ns.setMACDownlinkSettings(dev, device.MACDownlinkSettings{Interval: 6 * time.Hour}), ShouldBeNil)
+	a.So(dev.MACDownlinks.Interval, ShouldEqual, 6*time.Hour)
+	a.So(dev.MACDownlinks.LastSent, ShouldResemble, lastSent)
+}
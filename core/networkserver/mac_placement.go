@@ -0,0 +1,190 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"sort"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+)
+
+// macCommandPriority determines the order of the MAC commands in a downlink.
+// Commands with a lower value are sent first, and are the last to be deferred
+// when not all MAC commands fit in the downlink.
+var macCommandPriority = map[uint32]int{
+	uint32(lorawan.LinkCheckAns):     0, // answers to requests of the device come first
+	deviceTimeCID:                    0,
+	resetCID:                         0,
+	uint32(lorawan.LinkADRReq):       1,
+	txParamSetupCID:                  2,
+	uint32(lorawan.RXParamSetupReq):  3,
+	uint32(lorawan.RXTimingSetupReq): 4,
+	uint32(lorawan.NewChannelReq):    5,
+	dlChannelCID:                     6,
+	uint32(lorawan.DutyCycleReq):     7,
+	uint32(lorawan.DevStatusReq):     8,
+	relayConfCID:                     9,
+	configureFwdLimitCID:             10,
+	endDeviceConfCID:                 11,
+	updateUplinkListCID:              12,
+}
+
+func getMACCommandPriority(cid uint32) int {
+	if priority, ok := macCommandPriority[cid]; ok {
+		return priority
+	}
+	return len(macCommandPriority)
+}
+
+type macCommandsByPriority []pb_lorawan.MACCommand
+
+func (c macCommandsByPriority) Len() int      { return len(c) }
+func (c macCommandsByPriority) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c macCommandsByPriority) Less(i, j int) bool {
+	return getMACCommandPriority(c[i].Cid) < getMACCommandPriority(c[j].Cid)
+}
+
+// maxFOptsLengthFor returns the maximum length of the FOpts of the downlink.
+// This is less than maxFOptsLength if the MACPayload would otherwise exceed the
+// repeater-compatible maximum payload size of the data rate of the downlink.
+func maxFOptsLengthFor(message *pb_broker.DownlinkMessage) int {
+	option := message.GetDownlinkOption()
+	lorawan := option.GetProtocolConfig().GetLorawan()
+	if option.GetGatewayConfig() == nil || lorawan == nil || lorawan.Modulation != pb_lorawan.Modulation_LORA {
+		return maxFOptsLength
+	}
+	fp, err := band.Get(band.Guess(option.GatewayConfig.Frequency))
+	if err != nil {
+		return maxFOptsLength // We can't check this region
+	}
+	size, err := fp.GetMaxPayloadSizeFor(lorawan.DataRate, true)
+	if err != nil {
+		return maxFOptsLength // We don't know the maximum for this data rate
+	}
+	// The FHDR without FOpts is 7 bytes, followed by the FPort and FRMPayload
+	available := size.M - 7
+	if frmPayload := message.GetMessage().GetLorawan().GetMacPayload().GetFrmPayload(); len(frmPayload) > 0 {
+		available -= 1 + len(frmPayload)
+	}
+	if available < 0 {
+		return 0
+	}
+	if available < maxFOptsLength {
+		return available
+	}
+	return maxFOptsLength
+}
+
+// handleDownlinkMACPlacement places the MAC commands of the downlink in FOpts
+// or in the FRMPayload on port 0:
+//
+// - If the MAC commands fit in FOpts, they are sent in FOpts. The FOpts count towards the maximum payload size of the data rate.
+// - If they don't fit and there is no application payload, they are sent in the FRMPayload on port 0, encrypted with the NwkSKey.
+// - If they don't fit and the application payload is confirmed, the application payload is deferred (the Handler sends it again, as it is not acknowledged) and the MAC commands are sent on port 0.
+// - If they don't fit and the application payload is unconfirmed, the MAC commands with the highest priority are sent in FOpts and the others are deferred to the next downlink.
+//
+// This has to be called after the FCnt of the downlink is set.
+func (n *networkServer) handleDownlinkMACPlacement(message *pb_broker.DownlinkMessage, dev *device.Device) error {
+	lorawanDownlink := message.GetMessage().GetLorawan()
+	lorawanDownlinkMac := lorawanDownlink.GetMacPayload()
+	if lorawanDownlinkMac == nil || len(lorawanDownlinkMac.FOpts) == 0 {
+		return nil
+	}
+
+	sort.Stable(macCommandsByPriority(lorawanDownlinkMac.FOpts))
+
+	maxLength := maxFOptsLengthFor(message)
+	if fOptsLength(lorawanDownlinkMac.FOpts) <= maxLength {
+		return nil
+	}
+
+	hasPayload := lorawanDownlinkMac.FPort > 0 && len(lorawanDownlinkMac.FrmPayload) > 0
+
+	if hasPayload && !lorawanDownlink.IsConfirmed() {
+		var fOpts []pb_lorawan.MACCommand
+		var deferred int
+		for _, cmd := range lorawanDownlinkMac.FOpts {
+			if fOptsLength(fOpts)+1+len(cmd.Payload) > maxLength {
+				deferMACCommand(dev, cmd)
+				deferred++
+				continue
+			}
+			fOpts = append(fOpts, cmd)
+		}
+		lorawanDownlinkMac.FOpts = fOpts
+		lorawanDownlinkMac.FPending = true
+		message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, "placement", "f-opts", "deferred", deferred)
+		return nil
+	}
+
+	if hasPayload {
+		lorawanDownlink.MType = pb_lorawan.MType_UNCONFIRMED_DOWN
+		lorawanDownlinkMac.FPending = true
+		message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, "placement", "frm-payload", "deferred", "application payload")
+	} else {
+		message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, "placement", "frm-payload")
+	}
+
+	var macPayload []byte
+	for _, cmd := range lorawanDownlinkMac.FOpts {
+		macPayload = append(macPayload, byte(cmd.Cid))
+		macPayload = append(macPayload, cmd.Payload...)
+	}
+
+	encrypted, err := encryptMACCommands(dev.NwkSKey, lorawanDownlinkMac.DevAddr, lorawanDownlinkMac.FCnt, macPayload)
+	if err != nil {
+		return err
+	}
+
+	lorawanDownlinkMac.FOpts = nil
+	lorawanDownlinkMac.FPort = 0
+	lorawanDownlinkMac.FrmPayload = encrypted
+
+	return nil
+}
+
+// deferMACCommand makes sure that a MAC command that is not sent is sent in the next downlink
+func deferMACCommand(dev *device.Device, cmd pb_lorawan.MACCommand) {
+	if pending := findPendingMACCommand(dev, cmd.Cid, cmd.Payload); pending != nil && pending.Attempts > 0 {
+		pending.Attempts--
+	}
+}
+
+// encryptMACCommands encrypts MAC commands that are sent in the FRMPayload of
+// a downlink on port 0. As encryption and decryption are the same operation,
+// this can also be used to decrypt them.
+func encryptMACCommands(nwkSKey types.NwkSKey, devAddr types.DevAddr, fCnt uint32, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(nwkSKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(data))
+	a := make([]byte, aes.BlockSize)
+	s := make([]byte, aes.BlockSize)
+	a[0] = 0x01
+	a[5] = 0x01 // downlink
+	for i := 0; i < 4; i++ {
+		a[6+i] = devAddr[3-i] // little endian
+	}
+	binary.LittleEndian.PutUint32(a[10:14], fCnt)
+
+	for i := 0; i < len(data); i += aes.BlockSize {
+		a[15] = byte(i/aes.BlockSize + 1)
+		block.Encrypt(s, a)
+		for j := i; j < len(data) && j < i+aes.BlockSize; j++ {
+			out[j] = data[j] ^ s[j-i]
+		}
+	}
+
+	return out, nil
+}
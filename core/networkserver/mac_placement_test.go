@@ -0,0 +1,141 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/brocaar/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestEncryptMACCommands(t *testing.T) {
+	a := New(t)
+	nwkSKey := types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	devAddr := types.DevAddr{1, 2, 3, 4}
+	data := []byte{0x03, 0x50, 0xff, 0x00, 0x01, 0x06, 0x09, 0x0f, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	encrypted, err := encryptMACCommands(nwkSKey, devAddr, 42, data)
+	a.So(err, ShouldBeNil)
+	a.So(encrypted, ShouldHaveLength, len(data))
+	a.So(encrypted, ShouldNotResemble, data)
+
+	decrypted, err := encryptMACCommands(nwkSKey, devAddr, 42, encrypted)
+	a.So(err, ShouldBeNil)
+	a.So(decrypted, ShouldResemble, data)
+
+	otherFCnt, _ := encryptMACCommands(nwkSKey, devAddr, 43, data)
+	a.So(otherFCnt, ShouldNotResemble, encrypted)
+}
+
+func TestHandleDownlinkMACPlacement(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleDownlinkMACPlacement")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+
+	devStatusReq := pb_lorawan.MACCommand{Cid: uint32(lorawan.DevStatusReq)}
+	linkCheckAns := pb_lorawan.MACCommand{Cid: uint32(lorawan.LinkCheckAns), Payload: []byte{10, 1}}
+	linkADRReq := pb_lorawan.MACCommand{Cid: uint32(lorawan.LinkADRReq), Payload: []byte{0x50, 0xff, 0x00, 0x01}}
+	rxParamSetupReq := pb_lorawan.MACCommand{Cid: uint32(lorawan.RXParamSetupReq), Payload: []byte{0x03, 0x01, 0x02, 0x03}}
+	newChannelReq := pb_lorawan.MACCommand{Cid: uint32(lorawan.NewChannelReq), Payload: []byte{0x03, 0x01, 0x02, 0x03, 0x50}}
+
+	dev := &device.Device{NwkSKey: types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}}
+
+	// Commands that fit are sorted by priority and stay in FOpts
+	{
+		message := adrInitDownlinkMessage()
+		mac := message.Message.GetLorawan().GetMacPayload()
+		mac.FOpts = []pb_lorawan.MACCommand{devStatusReq, linkADRReq, linkCheckAns}
+		err := ns.handleDownlinkMACPlacement(message, dev)
+		a.So(err, ShouldBeNil)
+		a.So(mac.FOpts, ShouldResemble, []pb_lorawan.MACCommand{linkCheckAns, linkADRReq, devStatusReq})
+		a.So(mac.FrmPayload, ShouldBeEmpty)
+	}
+
+	// Commands that don't fit are moved to port 0 if there is no application payload
+	{
+		message := adrInitDownlinkMessage()
+		mac := message.Message.GetLorawan().GetMacPayload()
+		mac.FCnt = 12
+		mac.FOpts = []pb_lorawan.MACCommand{newChannelReq, linkADRReq, rxParamSetupReq, devStatusReq}
+		err := ns.handleDownlinkMACPlacement(message, dev)
+		a.So(err, ShouldBeNil)
+		a.So(mac.FOpts, ShouldBeEmpty)
+		a.So(mac.FPort, ShouldEqual, 0)
+		decrypted, _ := encryptMACCommands(dev.NwkSKey, mac.DevAddr, 12, mac.FrmPayload)
+		a.So(decrypted, ShouldResemble, []byte{
+			0x03, 0x50, 0xff, 0x00, 0x01,
+			0x05, 0x03, 0x01, 0x02, 0x03,
+			0x07, 0x03, 0x01, 0x02, 0x03, 0x50,
+			0x06,
+		})
+	}
+
+	// A confirmed application payload is deferred
+	{
+		message := adrInitDownlinkMessage()
+		message.Message.GetLorawan().MType = pb_lorawan.MType_CONFIRMED_DOWN
+		mac := message.Message.GetLorawan().GetMacPayload()
+		mac.FPort = 1
+		mac.FrmPayload = []byte{1, 2, 3, 4}
+		mac.FOpts = []pb_lorawan.MACCommand{newChannelReq, linkADRReq, rxParamSetupReq, devStatusReq}
+		err := ns.handleDownlinkMACPlacement(message, dev)
+		a.So(err, ShouldBeNil)
+		a.So(message.Message.GetLorawan().MType, ShouldEqual, pb_lorawan.MType_UNCONFIRMED_DOWN)
+		a.So(mac.FOpts, ShouldBeEmpty)
+		a.So(mac.FPort, ShouldEqual, 0)
+		a.So(mac.FrmPayload, ShouldHaveLength, 17)
+		a.So(mac.FPending, ShouldBeTrue)
+	}
+
+	// With an unconfirmed application payload, the commands with the lowest priority are deferred
+	{
+		dev.PendingMACCommands = []device.PendingMACCommand{
+			{CID: uint32(lorawan.NewChannelReq), Payload: newChannelReq.Payload, Attempts: 1},
+		}
+		message := adrInitDownlinkMessage()
+		mac := message.Message.GetLorawan().GetMacPayload()
+		mac.FPort = 1
+		mac.FrmPayload = []byte{1, 2, 3, 4}
+		mac.FOpts = []pb_lorawan.MACCommand{newChannelReq, linkADRReq, rxParamSetupReq, devStatusReq}
+		err := ns.handleDownlinkMACPlacement(message, dev)
+		a.So(err, ShouldBeNil)
+		a.So(mac.FOpts, ShouldResemble, []pb_lorawan.MACCommand{linkADRReq, rxParamSetupReq, devStatusReq})
+		a.So(mac.FPort, ShouldEqual, 1)
+		a.So(mac.FrmPayload, ShouldResemble, []byte{1, 2, 3, 4})
+		a.So(mac.FPending, ShouldBeTrue)
+		a.So(dev.PendingMACCommands[0].Attempts, ShouldEqual, 0)
+	}
+
+	// The FOpts count towards the maximum payload size of the data rate
+	{
+		message := adrInitDownlinkMessage()
+		message.DownlinkOption = &pb_broker.DownlinkOption{
+			GatewayConfig: &pb_gateway.TxConfiguration{Frequency: 868100000},
+			ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{
+				Lorawan: &pb_lorawan.TxConfiguration{Modulation: pb_lorawan.Modulation_LORA, DataRate: "SF12BW125", CodingRate: "4/5"},
+			}},
+		}
+		mac := message.Message.GetLorawan().GetMacPayload()
+		mac.FPort = 1
+		mac.FrmPayload = make([]byte, 49)
+		mac.FOpts = []pb_lorawan.MACCommand{devStatusReq, linkCheckAns}
+		a.So(maxFOptsLengthFor(message), ShouldEqual, 2)
+		err := ns.handleDownlinkMACPlacement(message, dev)
+		a.So(err, ShouldBeNil)
+		a.So(mac.FOpts, ShouldResemble, []pb_lorawan.MACCommand{devStatusReq})
+		a.So(mac.FrmPayload, ShouldHaveLength, 49)
+		a.So(mac.FPending, ShouldBeTrue)
+	}
+}
@@ -22,6 +22,20 @@ func TestBestSNR(t *testing.T) {
 	a.So(best, ShouldEqual, 10)
 }
 
+func TestBestGateway(t *testing.T) {
+	a := New(t)
+	metadata := []*pb_gateway.RxMetadata{
+		&pb_gateway.RxMetadata{Snr: 2, Rssi: -110},
+		&pb_gateway.RxMetadata{Snr: 8.5, Rssi: -90},
+		&pb_gateway.RxMetadata{Snr: -3, Rssi: -120},
+	}
+	best := bestGateway(metadata)
+	a.So(best.Snr, ShouldEqual, 8.5)
+	a.So(best.Rssi, ShouldEqual, -90)
+	a.So(metadata[0].Snr, ShouldEqual, 2)
+	a.So(bestGateway(nil), ShouldBeNil)
+}
+
 func TestLinkMargin(t *testing.T) {
 	a := New(t)
 	a.So(linkMargin("SF7BW125", 4.3), ShouldEqual, 11.8)
@@ -9,15 +9,18 @@ import (
 
 	"github.com/TheThingsNetwork/go-account-lib/claims"
 	"github.com/TheThingsNetwork/go-account-lib/rights"
+	"github.com/TheThingsNetwork/ttn/api"
 	pb "github.com/TheThingsNetwork/ttn/api/networkserver"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	"github.com/TheThingsNetwork/ttn/api/ratelimit"
+	pb_v2_lorawan "github.com/TheThingsNetwork/ttn/api/v2/lorawan"
 	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/golang/protobuf/ptypes/empty"
 	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 type networkServerManager struct {
@@ -54,6 +57,21 @@ func (n *networkServerManager) getDevice(ctx context.Context, in *pb_lorawan.Dev
 	return dev, nil
 }
 
+// checkApplication returns an error if the client does not have the right to the application
+func (n *networkServerManager) checkApplication(ctx context.Context, appID string, right rights.Right) error {
+	if !api.ValidID(appID) {
+		return errors.NewErrInvalidArgument("Application ID", "invalid")
+	}
+	claims, err := n.networkServer.Component.ValidateTTNAuthContext(ctx)
+	if err != nil {
+		return err
+	}
+	if n.clientRate.Limit(claims.Subject) {
+		return grpc.Errorf(codes.ResourceExhausted, "Rate limit for client reached")
+	}
+	return checkAppRights(claims, appID, right)
+}
+
 func (n *networkServerManager) GetDevice(ctx context.Context, in *pb_lorawan.DeviceIdentifier) (*pb_lorawan.Device, error) {
 	dev, err := n.getDevice(ctx, in)
 	if err != nil {
@@ -76,7 +94,9 @@ func (n *networkServerManager) GetDevice(ctx context.Context, in *pb_lorawan.Dev
 		FCntDown:         dev.FCntDown,
 		DisableFCntCheck: dev.Options.DisableFCntCheck,
 		Uses32BitFCnt:    dev.Options.Uses32BitFCnt,
+		DisableAdr:       dev.Options.DisableADR,
 		LastSeen:         lastSeen.UnixNano(),
+		GatewayGroups:    dev.GatewayGroups,
 	}, nil
 }
 
@@ -84,6 +104,9 @@ func (n *networkServerManager) SetDevice(ctx context.Context, in *pb_lorawan.Dev
 	if err := in.Validate(); err != nil {
 		return nil, errors.Wrap(err, "Invalid Device")
 	}
+	if err := validateGatewayGroups(in.GatewayGroups); err != nil {
+		return nil, err
+	}
 
 	claims, err := n.networkServer.Component.ValidateTTNAuthContext(ctx)
 	if err != nil {
@@ -99,11 +122,15 @@ func (n *networkServerManager) SetDevice(ctx context.Context, in *pb_lorawan.Dev
 		return nil, err
 	}
 
+	var previousAppID string
 	if dev == nil {
 		dev = new(device.Device)
 	} else {
+		previousAppID = dev.AppID
 		dev.StartUpdate()
 	}
+	// Devices that are added to a tenant count towards its device limit
+	tenantChanged := previousAppID == "" || n.networkServer.tenants.ForApplication(previousAppID) != n.networkServer.tenants.ForApplication(in.AppId)
 
 	dev.AppID = in.AppId
 	dev.AppEUI = *in.AppEui
@@ -117,17 +144,43 @@ func (n *networkServerManager) SetDevice(ctx context.Context, in *pb_lorawan.Dev
 		DisableFCntCheck:      in.DisableFCntCheck,
 		Uses32BitFCnt:         in.Uses32BitFCnt,
 		ActivationConstraints: in.ActivationConstraints,
+		DisableADR:            in.DisableAdr,
+	}
+	dev.GatewayGroups = in.GatewayGroups
+
+	// The Handler may have wrapped the NwkSKey with a KEK
+	md, _ := metadata.FromContext(ctx)
+	nwkSKey, err := n.networkServer.unwrapNwkSKey(md)
+	if err != nil {
+		return nil, err
+	}
+	if nwkSKey != nil {
+		in.NwkSKey = nwkSKey
 	}
 
 	if in.NwkSKey != nil && in.DevAddr != nil {
+		if err := n.networkServer.checkTenantDevAddr(in.AppId, *in.DevAddr); err != nil {
+			return nil, err
+		}
 		dev.DevAddr = *in.DevAddr
 		dev.NwkSKey = *in.NwkSKey
 	}
 
+	if tenantChanged {
+		if err := n.networkServer.addTenantDevice(in.AppId); err != nil {
+			return nil, err
+		}
+	}
 	err = n.networkServer.devices.Set(dev)
 	if err != nil {
+		if tenantChanged {
+			n.networkServer.removeTenantDevice(in.AppId)
+		}
 		return nil, err
 	}
+	if tenantChanged && previousAppID != "" {
+		n.networkServer.removeTenantDevice(previousAppID)
+	}
 
 	frames, err := n.networkServer.devices.Frames(dev.AppEUI, dev.DevEUI)
 	if err != nil {
@@ -142,7 +195,7 @@ func (n *networkServerManager) SetDevice(ctx context.Context, in *pb_lorawan.Dev
 }
 
 func (n *networkServerManager) DeleteDevice(ctx context.Context, in *pb_lorawan.DeviceIdentifier) (*empty.Empty, error) {
-	_, err := n.getDevice(ctx, in)
+	dev, err := n.getDevice(ctx, in)
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +203,7 @@ func (n *networkServerManager) DeleteDevice(ctx context.Context, in *pb_lorawan.
 	if err != nil {
 		return nil, err
 	}
+	n.networkServer.removeTenantDevice(dev.AppID)
 	return &empty.Empty{}, nil
 }
 
@@ -167,7 +221,7 @@ func (n *networkServerManager) GetPrefixes(ctx context.Context, in *pb_lorawan.P
 }
 
 func (n *networkServerManager) GetDevAddr(ctx context.Context, in *pb_lorawan.DevAddrRequest) (*pb_lorawan.DevAddrResponse, error) {
-	devAddr, err := n.networkServer.getDevAddr(in.Usage...)
+	devAddr, err := n.networkServer.getDevAddr(n.networkServer.tenants.ForApplication(in.AppId), in.Usage...)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +244,10 @@ func (n *networkServerManager) GetStatus(ctx context.Context, in *pb.StatusReque
 	return status, nil
 }
 
+func (n *networkServerManager) TailLogs(in *api.LogsRequest, stream pb.NetworkServerManager_TailLogsServer) error {
+	return n.networkServer.Component.TailLogs(in, stream)
+}
+
 // RegisterManager registers this networkserver as a NetworkServerManagerServer (github.com/TheThingsNetwork/ttn/api/networkserver)
 func (n *networkServer) RegisterManager(s *grpc.Server) {
 	server := &networkServerManager{networkServer: n}
@@ -198,5 +256,6 @@ func (n *networkServer) RegisterManager(s *grpc.Server) {
 
 	pb.RegisterNetworkServerManagerServer(s, server)
 	pb_lorawan.RegisterDeviceManagerServer(s, server)
+	pb_v2_lorawan.RegisterDeviceManagerServer(s, pb_v2_lorawan.NewDeviceManagerServer(server))
 	pb_lorawan.RegisterDevAddrManagerServer(s, server)
 }
@@ -4,10 +4,14 @@
 package networkserver
 
 import (
+	"net/http"
+	"time"
+
 	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
 	pb_handler "github.com/TheThingsNetwork/ttn/api/handler"
 	pb "github.com/TheThingsNetwork/ttn/api/networkserver"
 	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/kek"
 	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
@@ -21,19 +25,43 @@ type NetworkServer interface {
 
 	UsePrefix(prefix types.DevAddrPrefix, usage []string) error
 	GetPrefixesFor(requiredUsages ...string) []types.DevAddrPrefix
+	UseFairAccessPolicy(policy FairAccessPolicy)
+	UseFrameHistoryLimits(limits FrameHistoryLimits)
+	UseMACDownlinkInterval(interval time.Duration)
+	UseTenants(tenants types.Tenants) error
+	UseKEKs(store kek.Store)
 
 	HandleGetDevices(*pb.DevicesRequest) (*pb.DevicesResponse, error)
 	HandlePrepareActivation(*pb_broker.DeduplicatedDeviceActivationRequest) (*pb_broker.DeduplicatedDeviceActivationRequest, error)
 	HandleActivate(*pb_handler.DeviceActivationResponse) (*pb_handler.DeviceActivationResponse, error)
 	HandleUplink(*pb_broker.DeduplicatedUplinkMessage) (*pb_broker.DeduplicatedUplinkMessage, error)
 	HandleDownlink(*pb_broker.DownlinkMessage) (*pb_broker.DownlinkMessage, error)
+
+	HTTPHandler() http.Handler
 }
 
 // NewRedisNetworkServer creates a new Redis-backed NetworkServer
 func NewRedisNetworkServer(client *redis.Client, netID int) NetworkServer {
+	ns := newNetworkServer(device.NewRedisDeviceStore(client, "ns"), netID)
+	ns.applications = device.NewRedisApplicationStore(client, "ns")
+	return ns
+}
+
+// NewMemoryNetworkServer creates a new NetworkServer that keeps its state in memory
+func NewMemoryNetworkServer(netID int) NetworkServer {
+	return newNetworkServer(device.NewMemoryDeviceStore(), netID)
+}
+
+// NewNetworkServer creates a new NetworkServer that uses the given device store
+func NewNetworkServer(devices device.Store, netID int) NetworkServer {
+	return newNetworkServer(devices, netID)
+}
+
+func newNetworkServer(devices device.Store, netID int) *networkServer {
 	ns := &networkServer{
-		devices:  device.NewRedisDeviceStore(client, "ns"),
-		prefixes: map[types.DevAddrPrefix][]string{},
+		devices:      devices,
+		applications: device.NewMemoryApplicationStore(),
+		prefixes:     map[types.DevAddrPrefix][]string{},
 	}
 	ns.netID = [3]byte{byte(netID >> 16), byte(netID >> 8), byte(netID)}
 	return ns
@@ -41,10 +69,20 @@ func NewRedisNetworkServer(client *redis.Client, netID int) NetworkServer {
 
 type networkServer struct {
 	*component.Component
-	devices  device.Store
-	netID    [3]byte
-	prefixes map[types.DevAddrPrefix][]string
-	status   *status
+	devices       device.Store
+	applications  device.ApplicationStore
+	netID         [3]byte
+	prefixes      map[types.DevAddrPrefix][]string
+	tenants       types.Tenants
+	tenantDevices tenantDevices
+	status        *status
+
+	fairAccessPolicy   *FairAccessPolicy
+	frameHistoryLimits *FrameHistoryLimits
+
+	macDownlinkInterval time.Duration
+
+	keks kek.Store
 }
 
 func (n *networkServer) UsePrefix(prefix types.DevAddrPrefix, usage []string) error {
@@ -83,6 +121,9 @@ func (n *networkServer) Init(c *component.Component) error {
 	if err != nil {
 		return err
 	}
+	if n.keks != nil {
+		n.RegisterAdminHandler("keks", kek.HTTPHandler(n.keks))
+	}
 	n.Component.SetStatus(component.StatusHealthy)
 	return nil
 }
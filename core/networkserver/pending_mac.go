@@ -0,0 +1,200 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"bytes"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/brocaar/lorawan"
+)
+
+// MaxMACCommandAttempts is the number of downlinks that a MAC command is sent
+// in before the NetworkServer gives up waiting for an answer
+var MaxMACCommandAttempts = 3
+
+// MACCommandBackoff is the time after which a MAC command that expired without
+// answer is forgotten, so that the same command can be sent again
+var MACCommandBackoff = time.Hour
+
+// dlChannelCID is the CID of the DlChannelReq and DlChannelAns MAC commands (LoRaWAN 1.0.2)
+const dlChannelCID = 0x0A
+
+// maxFOptsLength is the maximum length of the FOpts field
+const maxFOptsLength = 15
+
+// macCommandNames contains the names of the MAC commands that are answered by
+// the device with a MAC command with the same CID
+var macCommandNames = map[uint32]string{
+	uint32(lorawan.LinkADRReq):       "link-adr",
+	uint32(lorawan.DutyCycleReq):     "duty-cycle",
+	uint32(lorawan.RXParamSetupReq):  "rx-param-setup",
+	uint32(lorawan.DevStatusReq):     "dev-status",
+	uint32(lorawan.NewChannelReq):    "new-channel",
+	uint32(lorawan.RXTimingSetupReq): "rx-timing-setup",
+	txParamSetupCID:                  "tx-param-setup",
+	dlChannelCID:                     "dl-channel",
+	relayConfCID:                     "relay-conf",
+	endDeviceConfCID:                 "end-device-conf",
+	updateUplinkListCID:              "update-uplink-list",
+	configureFwdLimitCID:             "configure-fwd-limit",
+}
+
+// findPendingMACCommand returns the pending MAC command with the CID and payload.
+// There can be multiple pending commands with the same CID, for example a block
+// of LinkADRReq commands.
+func findPendingMACCommand(dev *device.Device, cid uint32, payload []byte) *device.PendingMACCommand {
+	for i := range dev.PendingMACCommands {
+		if dev.PendingMACCommands[i].CID == cid && bytes.Equal(dev.PendingMACCommands[i].Payload, payload) {
+			return &dev.PendingMACCommands[i]
+		}
+	}
+	return nil
+}
+
+func containsMACCommand(fOpts []pb_lorawan.MACCommand, cid uint32, payload []byte) bool {
+	for _, cmd := range fOpts {
+		if cmd.Cid == cid && bytes.Equal(cmd.Payload, payload) {
+			return true
+		}
+	}
+	return false
+}
+
+func fOptsLength(fOpts []pb_lorawan.MACCommand) (length int) {
+	for _, cmd := range fOpts {
+		length += 1 + len(cmd.Payload)
+	}
+	return
+}
+
+// handleUplinkPendingMAC removes the pending MAC commands that are answered in
+// the uplink. An answer removes all commands with its CID that were sent in the
+// same downlink, as a block of commands may be answered once or per command.
+func (n *networkServer) handleUplinkPendingMAC(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) {
+	lorawanUplinkMac := message.GetMessage().GetLorawan().GetMacPayload()
+	if lorawanUplinkMac == nil {
+		return
+	}
+	answered := make(map[uint32]bool)
+	for _, cmd := range lorawanUplinkMac.FOpts {
+		if answered[cmd.Cid] {
+			continue
+		}
+		var answeredCmd *device.PendingMACCommand
+		for i := range dev.PendingMACCommands {
+			if dev.PendingMACCommands[i].CID == cmd.Cid {
+				answeredCmd = &dev.PendingMACCommands[i]
+				break
+			}
+		}
+		if answeredCmd == nil {
+			continue
+		}
+		answered[cmd.Cid] = true
+		message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, macCommandNames[cmd.Cid], "answered-after", answeredCmd.Attempts)
+		fCntDown := answeredCmd.FCntDown
+		pendingCommands := make([]device.PendingMACCommand, 0, len(dev.PendingMACCommands))
+		for _, pending := range dev.PendingMACCommands {
+			if pending.CID == cmd.Cid && pending.FCntDown == fCntDown {
+				continue
+			}
+			pendingCommands = append(pendingCommands, pending)
+		}
+		dev.PendingMACCommands = pendingCommands
+	}
+}
+
+// handleDownlinkPendingMAC retries the unanswered MAC commands and records the
+// MAC commands that are sent in the downlink. Commands that were sent
+// MaxMACCommandAttempts times without answer expire and are not sent again
+// until MACCommandBackoff has passed. Commands that are generated again with a
+// different payload replace the pending commands with the same CID.
+func (n *networkServer) handleDownlinkPendingMAC(message *pb_broker.DownlinkMessage, dev *device.Device) {
+	lorawanDownlinkMac := message.GetMessage().GetLorawan().GetMacPayload()
+	if lorawanDownlinkMac == nil {
+		return
+	}
+	now := time.Now()
+
+	// Filter out commands that expired
+	fOpts := make([]pb_lorawan.MACCommand, 0, len(lorawanDownlinkMac.FOpts))
+	generated := make(map[uint32]bool)
+	for _, cmd := range lorawanDownlinkMac.FOpts {
+		if _, ok := macCommandNames[cmd.Cid]; ok {
+			pending := findPendingMACCommand(dev, cmd.Cid, cmd.Payload)
+			if pending != nil && (pending.Expired || pending.Attempts >= MaxMACCommandAttempts) && now.Sub(pending.SentAt) < MACCommandBackoff {
+				pending.Expired = true
+				continue
+			}
+			generated[cmd.Cid] = true
+		}
+		fOpts = append(fOpts, cmd)
+	}
+
+	// Forget commands that are replaced and expired commands after the backoff
+	pendingCommands := make([]device.PendingMACCommand, 0, len(dev.PendingMACCommands))
+	for _, pending := range dev.PendingMACCommands {
+		if generated[pending.CID] && !containsMACCommand(fOpts, pending.CID, pending.Payload) {
+			continue
+		}
+		if (pending.Expired || pending.Attempts >= MaxMACCommandAttempts) && now.Sub(pending.SentAt) >= MACCommandBackoff {
+			continue
+		}
+		pendingCommands = append(pendingCommands, pending)
+	}
+	dev.PendingMACCommands = pendingCommands
+
+	// Retry unanswered commands. Commands with the same CID are retried together,
+	// so that blocks of commands are not split.
+	retried := make(map[uint32]bool)
+	for _, pending := range dev.PendingMACCommands {
+		if pending.Expired || generated[pending.CID] || retried[pending.CID] {
+			continue
+		}
+		retried[pending.CID] = true
+		var block []pb_lorawan.MACCommand
+		for i := range dev.PendingMACCommands {
+			if other := &dev.PendingMACCommands[i]; other.CID == pending.CID && !other.Expired {
+				block = append(block, pb_lorawan.MACCommand{Cid: other.CID, Payload: other.Payload})
+			}
+		}
+		if pending.Attempts >= MaxMACCommandAttempts {
+			for i := range dev.PendingMACCommands {
+				if dev.PendingMACCommands[i].CID == pending.CID {
+					dev.PendingMACCommands[i].Expired = true
+				}
+			}
+			n.Ctx.WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).
+				WithField("MACCommand", macCommandNames[pending.CID]).
+				Warn("MAC command was not answered")
+			continue
+		}
+		if fOptsLength(fOpts)+fOptsLength(block) > maxFOptsLength {
+			continue
+		}
+		fOpts = append(fOpts, block...)
+	}
+
+	// Record the commands that are sent
+	for _, cmd := range fOpts {
+		if _, ok := macCommandNames[cmd.Cid]; !ok {
+			continue
+		}
+		pending := findPendingMACCommand(dev, cmd.Cid, cmd.Payload)
+		if pending == nil {
+			dev.PendingMACCommands = append(dev.PendingMACCommands, device.PendingMACCommand{CID: cmd.Cid, Payload: cmd.Payload})
+			pending = &dev.PendingMACCommands[len(dev.PendingMACCommands)-1]
+		}
+		pending.Attempts++
+		pending.FCntDown = dev.FCntDown
+		pending.SentAt = now
+	}
+
+	lorawanDownlinkMac.FOpts = fOpts
+}
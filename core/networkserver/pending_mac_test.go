@@ -0,0 +1,163 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+	"time"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/brocaar/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestPendingMAC(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestPendingMAC")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+
+	dev := &device.Device{FCntDown: 10}
+	linkADRReq := pb_lorawan.MACCommand{Cid: uint32(lorawan.LinkADRReq), Payload: []byte{1, 2, 3, 4}}
+
+	downlink := func(fOpts ...pb_lorawan.MACCommand) []pb_lorawan.MACCommand {
+		message := adrInitDownlinkMessage()
+		message.Message.GetLorawan().GetMacPayload().FOpts = fOpts
+		ns.handleDownlinkPendingMAC(message, dev)
+		dev.FCntDown++
+		return message.Message.GetLorawan().GetMacPayload().FOpts
+	}
+
+	// The command is recorded
+	a.So(downlink(linkADRReq), ShouldHaveLength, 1)
+	a.So(dev.PendingMACCommands, ShouldHaveLength, 1)
+	a.So(dev.PendingMACCommands[0].CID, ShouldEqual, lorawan.LinkADRReq)
+	a.So(dev.PendingMACCommands[0].Attempts, ShouldEqual, 1)
+	a.So(dev.PendingMACCommands[0].FCntDown, ShouldEqual, 10)
+
+	// Commands that are not answered are not tracked
+	a.So(downlink(pb_lorawan.MACCommand{Cid: uint32(lorawan.LinkCheckAns), Payload: []byte{1, 2}}), ShouldHaveLength, 2)
+	a.So(dev.PendingMACCommands, ShouldHaveLength, 1)
+	a.So(dev.PendingMACCommands[0].Attempts, ShouldEqual, 2)
+
+	// The command is retried
+	fOpts := downlink()
+	a.So(fOpts, ShouldHaveLength, 1)
+	a.So(fOpts[0], ShouldResemble, linkADRReq)
+	a.So(dev.PendingMACCommands[0].Attempts, ShouldEqual, 3)
+	a.So(dev.PendingMACCommands[0].FCntDown, ShouldEqual, 12)
+
+	// The command expires
+	a.So(downlink(), ShouldBeEmpty)
+	a.So(dev.PendingMACCommands[0].Expired, ShouldBeTrue)
+	a.So(downlink(linkADRReq), ShouldBeEmpty)
+
+	// A new command replaces the expired command
+	newLinkADRReq := pb_lorawan.MACCommand{Cid: uint32(lorawan.LinkADRReq), Payload: []byte{5, 6, 7, 8}}
+	a.So(downlink(newLinkADRReq), ShouldHaveLength, 1)
+	a.So(dev.PendingMACCommands, ShouldHaveLength, 1)
+	a.So(dev.PendingMACCommands[0].Expired, ShouldBeFalse)
+	a.So(dev.PendingMACCommands[0].Attempts, ShouldEqual, 1)
+
+	// The answer removes the command
+	uplink := adrInitUplinkMessage()
+	uplink.Message.GetLorawan().GetMacPayload().FOpts = []pb_lorawan.MACCommand{{Cid: uint32(lorawan.LinkADRAns), Payload: []byte{0x07}}}
+	ns.handleUplinkPendingMAC(uplink, dev)
+	a.So(dev.PendingMACCommands, ShouldBeEmpty)
+	a.So(downlink(), ShouldBeEmpty)
+}
+
+func TestPendingMACBackoff(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestPendingMACBackoff")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+
+	dev := &device.Device{FCntDown: 10}
+	devStatusReq := pb_lorawan.MACCommand{Cid: uint32(lorawan.DevStatusReq)}
+
+	downlink := func(fOpts ...pb_lorawan.MACCommand) []pb_lorawan.MACCommand {
+		message := adrInitDownlinkMessage()
+		message.Message.GetLorawan().GetMacPayload().FOpts = fOpts
+		ns.handleDownlinkPendingMAC(message, dev)
+		dev.FCntDown++
+		return message.Message.GetLorawan().GetMacPayload().FOpts
+	}
+
+	for i := 0; i < MaxMACCommandAttempts; i++ {
+		a.So(downlink(devStatusReq), ShouldHaveLength, 1)
+	}
+	a.So(downlink(devStatusReq), ShouldBeEmpty)
+	a.So(dev.PendingMACCommands[0].Expired, ShouldBeTrue)
+
+	// After the backoff, the same command can be sent again
+	dev.PendingMACCommands[0].SentAt = time.Now().Add(-MACCommandBackoff)
+	a.So(downlink(devStatusReq), ShouldHaveLength, 1)
+	a.So(dev.PendingMACCommands, ShouldHaveLength, 1)
+	a.So(dev.PendingMACCommands[0].Expired, ShouldBeFalse)
+	a.So(dev.PendingMACCommands[0].Attempts, ShouldEqual, 1)
+
+	// Expired commands that are not generated again are forgotten after the backoff
+	for i := 1; i < MaxMACCommandAttempts; i++ {
+		a.So(downlink(), ShouldHaveLength, 1)
+	}
+	a.So(downlink(), ShouldBeEmpty)
+	dev.PendingMACCommands[0].SentAt = time.Now().Add(-MACCommandBackoff)
+	a.So(downlink(), ShouldBeEmpty)
+	a.So(dev.PendingMACCommands, ShouldBeEmpty)
+}
+
+func TestPendingMACBlock(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestPendingMACBlock")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+
+	dev := &device.Device{FCntDown: 10}
+	block := []pb_lorawan.MACCommand{
+		{Cid: uint32(lorawan.LinkADRReq), Payload: []byte{0x50, 0xff, 0x00, 0x01}},
+		{Cid: uint32(lorawan.LinkADRReq), Payload: []byte{0x50, 0x00, 0x00, 0x71}},
+	}
+
+	downlink := func(fOpts ...pb_lorawan.MACCommand) []pb_lorawan.MACCommand {
+		message := adrInitDownlinkMessage()
+		message.Message.GetLorawan().GetMacPayload().FOpts = fOpts
+		ns.handleDownlinkPendingMAC(message, dev)
+		dev.FCntDown++
+		return message.Message.GetLorawan().GetMacPayload().FOpts
+	}
+
+	// All commands of the block are recorded and retried
+	a.So(downlink(block...), ShouldHaveLength, 2)
+	a.So(dev.PendingMACCommands, ShouldHaveLength, 2)
+	fOpts := downlink()
+	a.So(fOpts, ShouldResemble, block)
+	a.So(dev.PendingMACCommands[0].Attempts, ShouldEqual, 2)
+	a.So(dev.PendingMACCommands[1].Attempts, ShouldEqual, 2)
+
+	// A new block replaces the old block
+	newBlock := []pb_lorawan.MACCommand{
+		{Cid: uint32(lorawan.LinkADRReq), Payload: []byte{0x50, 0xff, 0x00, 0x01}},
+		{Cid: uint32(lorawan.LinkADRReq), Payload: []byte{0x50, 0x0f, 0x00, 0x71}},
+	}
+	a.So(downlink(newBlock...), ShouldResemble, newBlock)
+	a.So(dev.PendingMACCommands, ShouldHaveLength, 2)
+	a.So(dev.PendingMACCommands[1].Payload, ShouldResemble, newBlock[1].Payload)
+	a.So(dev.PendingMACCommands[1].Attempts, ShouldEqual, 1)
+
+	// The answers remove the block
+	uplink := adrInitUplinkMessage()
+	uplink.Message.GetLorawan().GetMacPayload().FOpts = []pb_lorawan.MACCommand{
+		{Cid: uint32(lorawan.LinkADRAns), Payload: []byte{0x07}},
+		{Cid: uint32(lorawan.LinkADRAns), Payload: []byte{0x07}},
+	}
+	ns.handleUplinkPendingMAC(uplink, dev)
+	a.So(dev.PendingMACCommands, ShouldBeEmpty)
+}
@@ -0,0 +1,53 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+	"time"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+)
+
+// quarantineDevice quarantines the device after a replay attack. The replayed
+// frame does not update the state of the device and does not get a downlink.
+func (n *networkServer) quarantineDevice(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) (*pb_broker.DeduplicatedUplinkMessage, error) {
+	if !dev.Quarantine.Active() {
+		dev.StartUpdate()
+		dev.Quarantine = device.Quarantine{
+			Reason: fmt.Sprintf("frame replayed %d meters from the original gateways", message.ReplayAttack.Distance),
+			Since:  time.Now(),
+		}
+		if err := n.devices.Set(dev); err != nil {
+			return nil, err
+		}
+		n.Ctx.WithFields(ttnlog.Fields{
+			"AppID":  dev.AppID,
+			"DevID":  dev.DevID,
+			"Reason": dev.Quarantine.Reason,
+		}).Warn("Quarantined device")
+	}
+	message.ResponseTemplate = nil
+	message.Quarantine = &pb_broker.Quarantine{
+		Reason: dev.Quarantine.Reason,
+		Since:  dev.Quarantine.Since.UnixNano(),
+	}
+	return message, nil
+}
+
+// clearQuarantine lifts the quarantine of the device
+func (n *networkServer) clearQuarantine(dev *device.Device) error {
+	if !dev.Quarantine.Active() {
+		return nil
+	}
+	dev.StartUpdate()
+	dev.Quarantine = device.Quarantine{}
+	if err := n.devices.Set(dev); err != nil {
+		return err
+	}
+	n.Ctx.WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).Info("Cleared quarantine of device")
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/brocaar/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestQuarantine(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestQuarantine")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+	ns.InitStatus()
+
+	appEUI := types.AppEUI(getEUI(1, 2, 3, 4, 5, 6, 7, 8))
+	devEUI := types.DevEUI(getEUI(1, 2, 3, 4, 5, 6, 7, 8))
+	devAddr := getDevAddr(1, 2, 3, 4)
+	ns.devices.Set(&device.Device{DevAddr: devAddr, AppEUI: appEUI, DevEUI: devEUI, AppID: "app", DevID: "dev"})
+
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{MType: lorawan.UnconfirmedDataUp, Major: lorawan.LoRaWANR1},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{DevAddr: lorawan.DevAddr(devAddr), FCnt: 1},
+		},
+	}
+	bytes, _ := phy.MarshalBinary()
+	uplink := func() *pb_broker.DeduplicatedUplinkMessage {
+		return &pb_broker.DeduplicatedUplinkMessage{
+			AppEui:           &appEUI,
+			DevEui:           &devEUI,
+			Payload:          bytes,
+			ResponseTemplate: &pb_broker.DownlinkMessage{DownlinkOption: &pb_broker.DownlinkOption{}},
+			GatewayMetadata:  []*pb_gateway.RxMetadata{{}},
+			ProtocolMetadata: &pb_protocol.RxMetadata{Protocol: &pb_protocol.RxMetadata_Lorawan{
+				Lorawan: &pb_lorawan.Metadata{DataRate: "SF7BW125", CodingRate: "4/5"},
+			}},
+		}
+	}
+
+	// A replay attack quarantines the device without updating its state
+	message := uplink()
+	message.ReplayAttack = &pb_broker.ReplayAttack{Distance: 500000}
+	res, err := ns.HandleUplink(message)
+	a.So(err, ShouldBeNil)
+	a.So(res.ResponseTemplate, ShouldBeNil)
+	a.So(res.Quarantine, ShouldNotBeNil)
+	a.So(res.Quarantine.Reason, ShouldContainSubstring, "500000 meters")
+	dev, _ := ns.devices.Get(appEUI, devEUI)
+	a.So(dev.Quarantine.Active(), ShouldBeTrue)
+	a.So(dev.Quarantine.Since.IsZero(), ShouldBeFalse)
+	a.So(dev.FCntUp, ShouldEqual, 0)
+
+	// A quarantined device does not get downlinks
+	res, err = ns.HandleUplink(uplink())
+	a.So(err, ShouldBeNil)
+	a.So(res.ResponseTemplate, ShouldBeNil)
+	_, err = ns.HandleDownlink(&pb_broker.DownlinkMessage{
+		AppEui:  &appEUI,
+		DevEui:  &devEUI,
+		AppId:   "app",
+		DevId:   "dev",
+		Payload: bytes,
+	})
+	a.So(errors.GetErrType(err), ShouldEqual, errors.PermissionDenied)
+
+	// Clearing the quarantine allows downlinks again
+	dev, _ = ns.devices.Get(appEUI, devEUI)
+	a.So(ns.clearQuarantine(dev), ShouldBeNil)
+	dev, _ = ns.devices.Get(appEUI, devEUI)
+	a.So(dev.Quarantine.Active(), ShouldBeFalse)
+	phy.MACPayload.(*lorawan.MACPayload).FHDR.FCnt = 2
+	bytes, _ = phy.MarshalBinary()
+	res, err = ns.HandleUplink(uplink())
+	a.So(err, ShouldBeNil)
+	a.So(res.ResponseTemplate, ShouldNotBeNil)
+}
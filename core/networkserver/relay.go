@@ -0,0 +1,495 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"bytes"
+	"crypto/aes"
+	"fmt"
+	"strings"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// CIDs of the relay MAC commands (LoRaWAN Relay Specification TS011)
+const (
+	relayConfCID          = 0x40 // RelayConfReq and RelayConfAns
+	endDeviceConfCID      = 0x41 // EndDeviceConfReq and EndDeviceConfAns
+	updateUplinkListCID   = 0x43 // UpdateUplinkListReq and UpdateUplinkListAns
+	configureFwdLimitCID  = 0x45 // ConfigureFwdLimitReq and ConfigureFwdLimitAns
+	notifyNewEndDeviceCID = 0x46 // NotifyNewEndDeviceReq
+)
+
+// maxRelayEndDevices is the number of end devices that the NetworkServer
+// remembers for a relay
+const maxRelayEndDevices = 16
+
+// maxRelayUplinkList is the number of end devices in the uplink list of a relay
+const maxRelayUplinkList = 16
+
+// relayConfRejections contains the names of the settings in the bits of the RelayConfAns
+var relayConfRejections = []string{
+	"second channel frequency",
+	"second channel ack offset",
+	"second channel data rate",
+	"second channel index",
+	"default channel index",
+	"cad periodicity",
+}
+
+// endDeviceConfRejections contains the names of the settings in the bits of the EndDeviceConfAns
+var endDeviceConfRejections = []string{
+	"second channel frequency",
+	"second channel ack offset",
+	"second channel data rate",
+	"second channel index",
+	"backoff",
+}
+
+func validateRelaySettings(settings device.RelaySettings) error {
+	switch {
+	case settings.CADPeriodicity < 0 || settings.CADPeriodicity > 5:
+		return errors.NewErrInvalidArgument("CAD Periodicity", "must be between 0 and 5")
+	case settings.DefaultChannelIndex < 0 || settings.DefaultChannelIndex > 1:
+		return errors.NewErrInvalidArgument("Default Channel Index", "must be 0 or 1")
+	case settings.SecondChannelFrequency/100 > 0xffffff:
+		return errors.NewErrInvalidArgument("Second Channel Frequency", "out of range")
+	case settings.SecondChannelDataRate < 0 || settings.SecondChannelDataRate > 15:
+		return errors.NewErrInvalidArgument("Second Channel Data Rate", "must be between 0 and 15")
+	case settings.SecondChannelAckOffset < 0 || settings.SecondChannelAckOffset > 7:
+		return errors.NewErrInvalidArgument("Second Channel Ack Offset", "must be between 0 and 7")
+	}
+	if limits := settings.Limits; limits != nil {
+		for _, rate := range []int{limits.JoinRequests, limits.Notifications, limits.GlobalUplinks, limits.Overall} {
+			if rate < 0 || rate > 127 {
+				return errors.NewErrInvalidArgument("Limits", "reload rates must be between 0 and 127")
+			}
+		}
+		if limits.BucketSize < 0 || limits.BucketSize > 3 {
+			return errors.NewErrInvalidArgument("Limits", "bucket size must be between 0 and 3")
+		}
+	}
+	return nil
+}
+
+// buildRelayConfReq builds the payload of a RelayConfReq: the channel settings
+// (2 bytes, little endian) with SecondChAckOffset in bits 0-2,
+// SecondChDataRate in bits 3-6, SecondChIdx in bit 7, DefaultChIdx in bits
+// 8-9, CADPeriodicity in bits 10-12 and StartStop in bit 13, followed by the
+// frequency of the second channel in steps of 100 Hz (3 bytes, little endian)
+func buildRelayConfReq(settings device.RelaySettings) []byte {
+	var channelSettings uint16
+	if settings.SecondChannelFrequency != 0 {
+		channelSettings |= uint16(settings.SecondChannelAckOffset) & 0x07
+		channelSettings |= (uint16(settings.SecondChannelDataRate) & 0x0f) << 3
+		channelSettings |= 1 << 7
+	}
+	channelSettings |= (uint16(settings.DefaultChannelIndex) & 0x03) << 8
+	channelSettings |= (uint16(settings.CADPeriodicity) & 0x07) << 10
+	if settings.Enabled {
+		channelSettings |= 1 << 13
+	}
+	frequency := uint32(settings.SecondChannelFrequency / 100)
+	return []byte{
+		byte(channelSettings), byte(channelSettings >> 8),
+		byte(frequency), byte(frequency >> 8), byte(frequency >> 16),
+	}
+}
+
+func validateRelayServedSettings(settings device.RelayServedSettings) error {
+	switch {
+	case settings.Mode < 0 || settings.Mode > 2:
+		return errors.NewErrInvalidArgument("Mode", "must be between 0 and 2")
+	case settings.SmartEnableLevel < 0 || settings.SmartEnableLevel > 3:
+		return errors.NewErrInvalidArgument("Smart Enable Level", "must be between 0 and 3")
+	case settings.Backoff < 0 || settings.Backoff > 63:
+		return errors.NewErrInvalidArgument("Backoff", "must be between 0 and 63")
+	case settings.ReloadRate < 0 || settings.ReloadRate > 63:
+		return errors.NewErrInvalidArgument("Reload Rate", "must be between 0 and 63")
+	case settings.BucketSize < 0 || settings.BucketSize > 3:
+		return errors.NewErrInvalidArgument("Bucket Size", "must be between 0 and 3")
+	}
+	return nil
+}
+
+// buildEndDeviceConfReq builds the payload of an EndDeviceConfReq: the mode
+// (1 byte) with SmartEnableLevel in bits 0-1 and the activation of the relay
+// mode in bits 4-5, the channel settings (2 bytes, little endian) with
+// SecondChIdx in bit 0, SecondChDataRate in bits 1-4, SecondChAckOffset in bits
+// 5-7 and Backoff in bits 8-13, followed by the frequency of the second
+// channel of the relay in steps of 100 Hz (3 bytes, little endian)
+func buildEndDeviceConfReq(settings device.RelayServedSettings, relay device.RelaySettings) []byte {
+	mode := byte(settings.SmartEnableLevel) & 0x03
+	if settings.Enabled {
+		mode |= (byte(settings.Mode+1) & 0x03) << 4
+	}
+	var channelSettings uint16
+	if relay.SecondChannelFrequency != 0 {
+		channelSettings |= 1
+		channelSettings |= (uint16(relay.SecondChannelDataRate) & 0x0f) << 1
+		channelSettings |= (uint16(relay.SecondChannelAckOffset) & 0x07) << 5
+	}
+	channelSettings |= (uint16(settings.Backoff) & 0x3f) << 8
+	frequency := uint32(relay.SecondChannelFrequency / 100)
+	return []byte{
+		mode,
+		byte(channelSettings), byte(channelSettings >> 8),
+		byte(frequency), byte(frequency >> 8), byte(frequency >> 16),
+	}
+}
+
+// buildUpdateUplinkListReq builds the payload of an UpdateUplinkListReq: the
+// index in the uplink list (1 byte), the uplink limit (1 byte) with the reload
+// rate in bits 0-5 and the bucket size in bits 6-7, the DevAddr (4 bytes,
+// little endian), the WOR frame counter (4 bytes, little endian) and the
+// RootWorSKey of the end device (16 bytes), which is derived from its NwkSKey
+func buildUpdateUplinkListReq(entry device.RelayUplinkListEntry, served *device.Device) ([]byte, error) {
+	block, err := aes.NewCipher(served.NwkSKey[:])
+	if err != nil {
+		return nil, err
+	}
+	rootWorSKey := make([]byte, aes.BlockSize)
+	block.Encrypt(rootWorSKey, append([]byte{0x01}, make([]byte, aes.BlockSize-1)...))
+
+	payload := []byte{
+		byte(entry.Index),
+		byte(served.RelayServed.ReloadRate)&0x3f | (byte(served.RelayServed.BucketSize)&0x03)<<6,
+		served.DevAddr[3], served.DevAddr[2], served.DevAddr[1], served.DevAddr[0],
+		0, 0, 0, 0,
+	}
+	return append(payload, rootWorSKey...), nil
+}
+
+// buildConfigureFwdLimitReq builds the payload of a ConfigureFwdLimitReq: the
+// reload rates (4 bytes, little endian) of all uplinks in bits 0-6, of
+// uplinks of all devices in bits 7-13, of notifications in bits 14-20 and of
+// join requests in bits 21-27, followed by the bucket size of each of them (2
+// bits each, in the same order)
+func buildConfigureFwdLimitReq(limits device.RelayLimits) []byte {
+	reloadRates := uint32(limits.Overall)&0x7f |
+		(uint32(limits.GlobalUplinks)&0x7f)<<7 |
+		(uint32(limits.Notifications)&0x7f)<<14 |
+		(uint32(limits.JoinRequests)&0x7f)<<21
+	bucketSize := byte(limits.BucketSize) & 0x03
+	return []byte{
+		byte(reloadRates), byte(reloadRates >> 8), byte(reloadRates >> 16), byte(reloadRates >> 24),
+		bucketSize | bucketSize<<2 | bucketSize<<4 | bucketSize<<6,
+	}
+}
+
+// handleUplinkRelay marks the uplinks in which a relay forwards the uplink of
+// an end device, so that the Broker handles the uplink of the end device
+func (n *networkServer) handleUplinkRelay(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) {
+	lorawanUplinkMac := message.GetMessage().GetLorawan().GetMacPayload()
+	if !dev.Relay.Enabled || lorawanUplinkMac.GetFPort() != pb_lorawan.RelayFPort || len(lorawanUplinkMac.GetFrmPayload()) == 0 {
+		return
+	}
+	message.RelayForward = true
+	message.Trace = message.Trace.WithEvent(trace.RelayEvent, "relay", dev.DevID)
+}
+
+func (n *networkServer) handleRelayConfAns(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device, cmd pb_lorawan.MACCommand) {
+	if len(cmd.Payload) != 1 {
+		return
+	}
+	var rejected []string
+	for i, name := range relayConfRejections {
+		if cmd.Payload[0]&(1<<uint(i)) == 0 {
+			rejected = append(rejected, name)
+		}
+	}
+	message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, "relay-conf", "rejected", len(rejected))
+	dev.Relay.SendConfReq = false
+	dev.Relay.Rejection = strings.Join(rejected, ", ")
+	if len(rejected) > 0 {
+		n.Ctx.WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).
+			WithField("Rejection", dev.Relay.Rejection).
+			Warn("Negative RelayConfAns")
+	}
+}
+
+func (n *networkServer) handleConfigureFwdLimitAns(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) {
+	message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, "configure-fwd-limit")
+	dev.Relay.SendLimitsReq = false
+}
+
+func (n *networkServer) handleEndDeviceConfAns(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device, cmd pb_lorawan.MACCommand) {
+	if len(cmd.Payload) != 1 {
+		return
+	}
+	var rejected []string
+	for i, name := range endDeviceConfRejections {
+		if cmd.Payload[0]&(1<<uint(i)) == 0 {
+			rejected = append(rejected, name)
+		}
+	}
+	message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, "end-device-conf", "rejected", len(rejected))
+	dev.RelayServed.SendConfReq = false
+	dev.RelayServed.Rejection = strings.Join(rejected, ", ")
+	if len(rejected) > 0 {
+		n.Ctx.WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).
+			WithField("Rejection", dev.RelayServed.Rejection).
+			Warn("Negative EndDeviceConfAns")
+	}
+}
+
+// handleUpdateUplinkListAns handles the (empty) answer of the relay to the
+// first UpdateUplinkListReq that was not answered yet
+func (n *networkServer) handleUpdateUplinkListAns(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) {
+	message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, "update-uplink-list")
+	for i := range dev.Relay.UplinkList {
+		if dev.Relay.UplinkList[i].SendReq {
+			dev.Relay.UplinkList[i].SendReq = false
+			return
+		}
+	}
+}
+
+// handleNotifyNewEndDeviceReq records the end device that the relay notified
+// the NetworkServer of. The payload contains the DevAddr (4 bytes, little
+// endian) and the power level (2 bytes, little endian) with SNR+20 in bits 0-4
+// and -RSSI in bits 5-11.
+func (n *networkServer) handleNotifyNewEndDeviceReq(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device, cmd pb_lorawan.MACCommand) {
+	if len(cmd.Payload) != 6 {
+		return
+	}
+	var devAddr types.DevAddr
+	for i := 0; i < 4; i++ {
+		devAddr[3-i] = cmd.Payload[i]
+	}
+	powerLevel := uint16(cmd.Payload[4]) | uint16(cmd.Payload[5])<<8
+	endDevice := device.RelayEndDevice{
+		DevAddr:    devAddr,
+		SNR:        int32(powerLevel&0x1f) - 20,
+		RSSI:       -int32((powerLevel >> 5) & 0x7f),
+		NotifiedAt: time.Now(),
+	}
+	message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, "notify-new-end-device", "dev_addr", devAddr)
+
+	endDevices := []device.RelayEndDevice{endDevice}
+	for _, existing := range dev.Relay.EndDevices {
+		if existing.DevAddr != devAddr && len(endDevices) < maxRelayEndDevices {
+			endDevices = append(endDevices, existing)
+		}
+	}
+	dev.Relay.EndDevices = endDevices
+}
+
+func (n *networkServer) handleDownlinkRelay(message *pb_broker.DownlinkMessage, dev *device.Device) {
+	lorawanDownlinkMac := message.GetMessage().GetLorawan().GetMacPayload()
+	if dev.Relay.SendConfReq {
+		lorawanDownlinkMac.FOpts = append(lorawanDownlinkMac.FOpts, pb_lorawan.MACCommand{
+			Cid:     relayConfCID,
+			Payload: buildRelayConfReq(dev.Relay),
+		})
+	}
+	if dev.Relay.SendLimitsReq && dev.Relay.Limits != nil {
+		lorawanDownlinkMac.FOpts = append(lorawanDownlinkMac.FOpts, pb_lorawan.MACCommand{
+			Cid:     configureFwdLimitCID,
+			Payload: buildConfigureFwdLimitReq(*dev.Relay.Limits),
+		})
+	}
+	for _, entry := range dev.Relay.UplinkList {
+		if !entry.SendReq {
+			continue
+		}
+		served, err := n.devices.Get(entry.AppEUI, entry.DevEUI)
+		if err != nil {
+			continue
+		}
+		payload, err := buildUpdateUplinkListReq(entry, served)
+		if err != nil {
+			continue
+		}
+		lorawanDownlinkMac.FOpts = append(lorawanDownlinkMac.FOpts, pb_lorawan.MACCommand{
+			Cid:     updateUplinkListCID,
+			Payload: payload,
+		})
+	}
+	if dev.RelayServed.SendConfReq {
+		var relay device.RelaySettings
+		if dev.RelayServed.Enabled {
+			relayDev, err := n.devices.Get(dev.RelayServed.RelayAppEUI, dev.RelayServed.RelayDevEUI)
+			if err != nil {
+				return
+			}
+			relay = relayDev.Relay
+		}
+		lorawanDownlinkMac.FOpts = append(lorawanDownlinkMac.FOpts, pb_lorawan.MACCommand{
+			Cid:     endDeviceConfCID,
+			Payload: buildEndDeviceConfReq(dev.RelayServed, relay),
+		})
+	}
+}
+
+// encryptRelayDownlink encrypts the downlink of an end device that the Broker
+// sends through the relay on the relay port. Like MAC commands on port 0, this
+// payload is encrypted with the NwkSKey. This has to be called after the FCnt
+// of the downlink is set.
+func (n *networkServer) encryptRelayDownlink(message *pb_broker.DownlinkMessage, dev *device.Device) error {
+	lorawanDownlinkMac := message.GetMessage().GetLorawan().GetMacPayload()
+	if lorawanDownlinkMac.GetFPort() != pb_lorawan.RelayFPort || len(lorawanDownlinkMac.FrmPayload) == 0 {
+		return nil
+	}
+	if !dev.Relay.Enabled {
+		return errors.NewErrInvalidArgument("Downlink", fmt.Sprintf("device is not a relay, FPort %d is reserved", pb_lorawan.RelayFPort))
+	}
+	encrypted, err := encryptMACCommands(dev.NwkSKey, lorawanDownlinkMac.DevAddr, lorawanDownlinkMac.FCnt, lorawanDownlinkMac.FrmPayload)
+	if err != nil {
+		return err
+	}
+	lorawanDownlinkMac.FrmPayload = encrypted
+	message.Trace = message.Trace.WithEvent(trace.RelayEvent, "relay", dev.DevID)
+	return nil
+}
+
+// setRelaySettings sets the relay settings of the device. The NetworkServer
+// sends the channel settings and limits to the relay when they change.
+func (n *networkServer) setRelaySettings(dev *device.Device, settings device.RelaySettings) error {
+	if err := validateRelaySettings(settings); err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	old := dev.Relay
+	settings.SendConfReq = old.SendConfReq || !bytes.Equal(buildRelayConfReq(old), buildRelayConfReq(settings))
+	settings.SendLimitsReq = settings.Limits != nil && (old.SendLimitsReq || old.Limits == nil || *old.Limits != *settings.Limits)
+	settings.Rejection = old.Rejection
+	settings.EndDevices = old.EndDevices
+	settings.UplinkList = old.UplinkList
+	dev.Relay = settings
+	if err := n.devices.Set(dev); err != nil {
+		return err
+	}
+
+	// The end devices that the relay serves use its second channel
+	if old.SecondChannelFrequency != settings.SecondChannelFrequency ||
+		old.SecondChannelDataRate != settings.SecondChannelDataRate ||
+		old.SecondChannelAckOffset != settings.SecondChannelAckOffset {
+		for _, entry := range settings.UplinkList {
+			served, err := n.devices.Get(entry.AppEUI, entry.DevEUI)
+			if err != nil {
+				continue
+			}
+			served.StartUpdate()
+			served.RelayServed.SendConfReq = true
+			if err := n.devices.Set(served); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setRelayServedSettings sets the relay that serves the end device. The
+// NetworkServer sends the settings to the end device, and adds the end device
+// to the uplink list of the relay.
+func (n *networkServer) setRelayServedSettings(dev *device.Device, settings device.RelayServedSettings) error {
+	if err := validateRelayServedSettings(settings); err != nil {
+		return err
+	}
+	var relay *device.Device
+	if settings.Enabled {
+		if settings.RelayAppEUI == dev.AppEUI && settings.RelayDevEUI == dev.DevEUI {
+			return errors.NewErrInvalidArgument("Relay", "can not serve itself")
+		}
+		var err error
+		relay, err = n.devices.Get(settings.RelayAppEUI, settings.RelayDevEUI)
+		if err != nil {
+			return err
+		}
+		if !relay.Relay.Enabled {
+			return errors.NewErrInvalidArgument("Relay", "is not enabled")
+		}
+	}
+
+	old := dev.RelayServed
+	relayChanged := old.RelayAppEUI != settings.RelayAppEUI || old.RelayDevEUI != settings.RelayDevEUI
+	if old.Enabled && (!settings.Enabled || relayChanged) {
+		if previous, err := n.devices.Get(old.RelayAppEUI, old.RelayDevEUI); err == nil {
+			previous.StartUpdate()
+			removeRelayUplinkListEntry(&previous.Relay, dev.AppEUI, dev.DevEUI)
+			if err := n.devices.Set(previous); err != nil {
+				return err
+			}
+		}
+	}
+	if relay != nil {
+		limitsChanged := !old.Enabled || relayChanged || old.ReloadRate != settings.ReloadRate || old.BucketSize != settings.BucketSize
+		relay.StartUpdate()
+		if err := addRelayUplinkListEntry(&relay.Relay, dev.AppEUI, dev.DevEUI, limitsChanged); err != nil {
+			return err
+		}
+		if err := n.devices.Set(relay); err != nil {
+			return err
+		}
+	}
+
+	dev.StartUpdate()
+	settings.SendConfReq = old.SendConfReq || relayChanged || old.Enabled != settings.Enabled ||
+		old.Mode != settings.Mode || old.SmartEnableLevel != settings.SmartEnableLevel || old.Backoff != settings.Backoff
+	settings.Rejection = old.Rejection
+	dev.RelayServed = settings
+	return n.devices.Set(dev)
+}
+
+// addRelayUplinkListEntry adds the end device to the uplink list of the relay,
+// or marks its entry to be sent again
+func addRelayUplinkListEntry(relay *device.RelaySettings, appEUI types.AppEUI, devEUI types.DevEUI, sendReq bool) error {
+	used := make(map[int]bool)
+	for i, entry := range relay.UplinkList {
+		if entry.AppEUI == appEUI && entry.DevEUI == devEUI {
+			relay.UplinkList[i].SendReq = entry.SendReq || sendReq
+			return nil
+		}
+		used[entry.Index] = true
+	}
+	for index := 0; index < maxRelayUplinkList; index++ {
+		if !used[index] {
+			relay.UplinkList = append(relay.UplinkList, device.RelayUplinkListEntry{AppEUI: appEUI, DevEUI: devEUI, Index: index, SendReq: true})
+			return nil
+		}
+	}
+	return errors.NewErrInvalidArgument("Relay", fmt.Sprintf("already serves %d end devices", maxRelayUplinkList))
+}
+
+func removeRelayUplinkListEntry(relay *device.RelaySettings, appEUI types.AppEUI, devEUI types.DevEUI) {
+	for i, entry := range relay.UplinkList {
+		if entry.AppEUI == appEUI && entry.DevEUI == devEUI {
+			relay.UplinkList = append(relay.UplinkList[:i], relay.UplinkList[i+1:]...)
+			return
+		}
+	}
+}
+
+// resetRelaySessions makes the NetworkServer send the relay settings again
+// after the device joined: the relay forgets its settings, and the relay of an
+// end device needs the new session of the end device.
+func (n *networkServer) resetRelaySessions(dev *device.Device) error {
+	if dev.Relay.Enabled {
+		dev.Relay.SendConfReq = true
+		dev.Relay.SendLimitsReq = dev.Relay.Limits != nil
+		for i := range dev.Relay.UplinkList {
+			dev.Relay.UplinkList[i].SendReq = true
+		}
+	}
+	if !dev.RelayServed.Enabled {
+		return nil
+	}
+	dev.RelayServed.SendConfReq = true
+	relay, err := n.devices.Get(dev.RelayServed.RelayAppEUI, dev.RelayServed.RelayDevEUI)
+	if err != nil {
+		return err
+	}
+	relay.StartUpdate()
+	if err := addRelayUplinkListEntry(&relay.Relay, dev.AppEUI, dev.DevEUI, true); err != nil {
+		return err
+	}
+	return n.devices.Set(relay)
+}
@@ -0,0 +1,206 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestBuildRelayMACCommands(t *testing.T) {
+	a := New(t)
+	a.So(buildRelayConfReq(device.RelaySettings{}), ShouldResemble, []byte{0x00, 0x00, 0x00, 0x00, 0x00})
+	a.So(buildRelayConfReq(device.RelaySettings{
+		Enabled:                true,
+		CADPeriodicity:         2,
+		DefaultChannelIndex:    1,
+		SecondChannelFrequency: 869525000,
+		SecondChannelDataRate:  3,
+		SecondChannelAckOffset: 1,
+	}), ShouldResemble, []byte{0x99, 0x29, 0xd2, 0xad, 0x84})
+	a.So(buildConfigureFwdLimitReq(device.RelayLimits{Overall: 127, BucketSize: 1}), ShouldResemble, []byte{0x7f, 0x00, 0x00, 0x00, 0x55})
+	a.So(buildConfigureFwdLimitReq(device.RelayLimits{JoinRequests: 1, Notifications: 1, GlobalUplinks: 1}), ShouldResemble, []byte{0x80, 0x40, 0x20, 0x00, 0x00})
+}
+
+func TestSetRelaySettings(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{devices: device.NewMemoryDeviceStore()}
+	dev := &device.Device{AppEUI: types.AppEUI([8]byte{1}), DevEUI: types.DevEUI([8]byte{1})}
+
+	a.So(ns.setRelaySettings(dev, device.RelaySettings{CADPeriodicity: 6}), ShouldNotBeNil)
+	a.So(ns.setRelaySettings(dev, device.RelaySettings{Limits: &device.RelayLimits{Overall: 128}}), ShouldNotBeNil)
+
+	a.So(ns.setRelaySettings(dev, device.RelaySettings{}), ShouldBeNil)
+	a.So(dev.Relay.SendConfReq, ShouldBeFalse)
+	a.So(dev.Relay.SendLimitsReq, ShouldBeFalse)
+
+	a.So(ns.setRelaySettings(dev, device.RelaySettings{Enabled: true, Limits: &device.RelayLimits{Overall: 60}}), ShouldBeNil)
+	a.So(dev.Relay.SendConfReq, ShouldBeTrue)
+	a.So(dev.Relay.SendLimitsReq, ShouldBeTrue)
+
+	downlink := adrInitDownlinkMessage()
+	ns.handleDownlinkRelay(downlink, dev)
+	fOpts := downlink.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 2)
+	a.So(fOpts[0].Cid, ShouldEqual, relayConfCID)
+	a.So(fOpts[1].Cid, ShouldEqual, configureFwdLimitCID)
+
+	// Answers
+	ns.Component = &component.Component{Ctx: GetLogger(t, "TestSetRelaySettings")}
+	message := adrInitUplinkMessage()
+	ns.handleRelayConfAns(message, dev, pb_lorawan.MACCommand{Cid: relayConfCID, Payload: []byte{0x2f}})
+	a.So(dev.Relay.SendConfReq, ShouldBeFalse)
+	a.So(dev.Relay.Rejection, ShouldEqual, "default channel index")
+	ns.handleConfigureFwdLimitAns(message, dev)
+	a.So(dev.Relay.SendLimitsReq, ShouldBeFalse)
+
+	// Unchanged settings are not sent again
+	a.So(ns.setRelaySettings(dev, device.RelaySettings{Enabled: true, Limits: &device.RelayLimits{Overall: 60}}), ShouldBeNil)
+	a.So(dev.Relay.SendConfReq, ShouldBeFalse)
+	a.So(dev.Relay.SendLimitsReq, ShouldBeFalse)
+	a.So(dev.Relay.Rejection, ShouldEqual, "default channel index")
+
+	downlink = adrInitDownlinkMessage()
+	ns.handleDownlinkRelay(downlink, dev)
+	a.So(downlink.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+}
+
+func TestRelayServedSettings(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{devices: device.NewMemoryDeviceStore()}
+	ns.Component = &component.Component{Ctx: GetLogger(t, "TestRelayServedSettings")}
+
+	relay := &device.Device{AppEUI: types.AppEUI([8]byte{1}), DevEUI: types.DevEUI([8]byte{1}), AppID: "app", DevID: "relay"}
+	a.So(ns.devices.Set(relay), ShouldBeNil)
+	dev := &device.Device{AppEUI: types.AppEUI([8]byte{1}), DevEUI: types.DevEUI([8]byte{2}), AppID: "app", DevID: "device",
+		DevAddr: types.DevAddr([4]byte{1, 2, 3, 4}), NwkSKey: types.NwkSKey([16]byte{1, 2, 3})}
+	a.So(ns.devices.Set(dev), ShouldBeNil)
+
+	served := device.RelayServedSettings{Enabled: true, RelayAppEUI: relay.AppEUI, RelayDevEUI: relay.DevEUI, Mode: 1, Backoff: 8, ReloadRate: 10}
+	a.So(ns.setRelayServedSettings(dev, device.RelayServedSettings{Mode: 3}), ShouldNotBeNil)
+	a.So(ns.setRelayServedSettings(dev, served), ShouldNotBeNil) // relay not enabled
+
+	a.So(ns.setRelaySettings(relay, device.RelaySettings{Enabled: true, SecondChannelFrequency: 869525000, SecondChannelDataRate: 3}), ShouldBeNil)
+	a.So(ns.setRelayServedSettings(dev, served), ShouldBeNil)
+	a.So(dev.RelayServed.SendConfReq, ShouldBeTrue)
+	relay, _ = ns.devices.Get(relay.AppEUI, relay.DevEUI)
+	a.So(relay.Relay.UplinkList, ShouldHaveLength, 1)
+	a.So(relay.Relay.UplinkList[0].SendReq, ShouldBeTrue)
+
+	// EndDeviceConfReq to the end device
+	downlink := adrInitDownlinkMessage()
+	ns.handleDownlinkRelay(downlink, dev)
+	fOpts := downlink.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 1)
+	a.So(fOpts[0].Cid, ShouldEqual, endDeviceConfCID)
+	a.So(fOpts[0].Payload, ShouldResemble, []byte{0x20, 0x07, 0x08, 0xd2, 0xad, 0x84})
+
+	// UpdateUplinkListReq to the relay
+	downlink = adrInitDownlinkMessage()
+	ns.handleDownlinkRelay(downlink, relay)
+	fOpts = downlink.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 2)
+	a.So(fOpts[1].Cid, ShouldEqual, updateUplinkListCID)
+	a.So(fOpts[1].Payload, ShouldHaveLength, 26)
+	a.So(fOpts[1].Payload[:10], ShouldResemble, []byte{0x00, 0x0a, 0x04, 0x03, 0x02, 0x01, 0x00, 0x00, 0x00, 0x00})
+
+	// Answers
+	message := adrInitUplinkMessage()
+	ns.handleEndDeviceConfAns(message, dev, pb_lorawan.MACCommand{Cid: endDeviceConfCID, Payload: []byte{0x0f}})
+	a.So(dev.RelayServed.SendConfReq, ShouldBeFalse)
+	a.So(dev.RelayServed.Rejection, ShouldEqual, "backoff")
+	ns.handleUpdateUplinkListAns(message, relay)
+	a.So(relay.Relay.UplinkList[0].SendReq, ShouldBeFalse)
+	a.So(ns.devices.Set(relay), ShouldBeNil)
+	a.So(ns.devices.Set(dev), ShouldBeNil)
+
+	// The relay gets the new session of the end device after it joined
+	a.So(ns.resetRelaySessions(dev), ShouldBeNil)
+	a.So(dev.RelayServed.SendConfReq, ShouldBeTrue)
+	relay, _ = ns.devices.Get(relay.AppEUI, relay.DevEUI)
+	a.So(relay.Relay.UplinkList[0].SendReq, ShouldBeTrue)
+
+	// The end device is removed from the uplink list of the relay
+	a.So(ns.setRelayServedSettings(dev, device.RelayServedSettings{}), ShouldBeNil)
+	relay, _ = ns.devices.Get(relay.AppEUI, relay.DevEUI)
+	a.So(relay.Relay.UplinkList, ShouldBeEmpty)
+}
+
+func TestRelayUplinkList(t *testing.T) {
+	a := New(t)
+	var relay device.RelaySettings
+	for i := 0; i < maxRelayUplinkList; i++ {
+		a.So(addRelayUplinkListEntry(&relay, types.AppEUI([8]byte{1}), types.DevEUI([8]byte{byte(i)}), false), ShouldBeNil)
+	}
+	a.So(addRelayUplinkListEntry(&relay, types.AppEUI([8]byte{1}), types.DevEUI([8]byte{0xff}), false), ShouldNotBeNil)
+
+	removeRelayUplinkListEntry(&relay, types.AppEUI([8]byte{1}), types.DevEUI([8]byte{3}))
+	a.So(relay.UplinkList, ShouldHaveLength, maxRelayUplinkList-1)
+	a.So(addRelayUplinkListEntry(&relay, types.AppEUI([8]byte{1}), types.DevEUI([8]byte{0xff}), false), ShouldBeNil)
+	a.So(relay.UplinkList[maxRelayUplinkList-1].Index, ShouldEqual, 3)
+}
+
+func TestHandleNotifyNewEndDeviceReq(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{}
+	dev := &device.Device{}
+
+	message := adrInitUplinkMessage()
+	ns.handleNotifyNewEndDeviceReq(message, dev, pb_lorawan.MACCommand{Cid: notifyNewEndDeviceCID, Payload: []byte{0x04, 0x03, 0x02, 0x01}})
+	a.So(dev.Relay.EndDevices, ShouldBeEmpty)
+
+	// SNR -5 and RSSI -90
+	payload := []byte{0x04, 0x03, 0x02, 0x01, 0x4f, 0x0b}
+	ns.handleNotifyNewEndDeviceReq(message, dev, pb_lorawan.MACCommand{Cid: notifyNewEndDeviceCID, Payload: payload})
+	a.So(dev.Relay.EndDevices, ShouldHaveLength, 1)
+	a.So(dev.Relay.EndDevices[0].DevAddr, ShouldEqual, types.DevAddr([4]byte{1, 2, 3, 4}))
+	a.So(dev.Relay.EndDevices[0].SNR, ShouldEqual, -5)
+	a.So(dev.Relay.EndDevices[0].RSSI, ShouldEqual, -90)
+
+	ns.handleNotifyNewEndDeviceReq(message, dev, pb_lorawan.MACCommand{Cid: notifyNewEndDeviceCID, Payload: payload})
+	a.So(dev.Relay.EndDevices, ShouldHaveLength, 1)
+
+	for i := 0; i < 2*maxRelayEndDevices; i++ {
+		ns.handleNotifyNewEndDeviceReq(message, dev, pb_lorawan.MACCommand{Cid: notifyNewEndDeviceCID, Payload: []byte{byte(i), 0, 0, 0, 0, 0}})
+	}
+	a.So(dev.Relay.EndDevices, ShouldHaveLength, maxRelayEndDevices)
+}
+
+func TestRelayFrames(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{}
+	dev := &device.Device{DevID: "relay", NwkSKey: types.NwkSKey([16]byte{1, 2, 3})}
+
+	message := adrInitUplinkMessage()
+	uplinkMac := message.Message.GetLorawan().GetMacPayload()
+	uplinkMac.FPort = pb_lorawan.RelayFPort
+	uplinkMac.FrmPayload = []byte{1, 2, 3, 4, 5, 6, 7}
+
+	ns.handleUplinkRelay(message, dev)
+	a.So(message.RelayForward, ShouldBeFalse)
+
+	dev.Relay.Enabled = true
+	ns.handleUplinkRelay(message, dev)
+	a.So(message.RelayForward, ShouldBeTrue)
+
+	downlink := adrInitDownlinkMessage()
+	downlinkMac := downlink.Message.GetLorawan().GetMacPayload()
+	downlinkMac.FPort = pb_lorawan.RelayFPort
+	downlinkMac.FCnt = 42
+	downlinkMac.FrmPayload = []byte{0x60, 0x04, 0x03, 0x02, 0x01}
+	a.So(ns.encryptRelayDownlink(downlink, dev), ShouldBeNil)
+	a.So(downlinkMac.FrmPayload, ShouldNotResemble, []byte{0x60, 0x04, 0x03, 0x02, 0x01})
+	decrypted, _ := encryptMACCommands(dev.NwkSKey, downlinkMac.DevAddr, 42, downlinkMac.FrmPayload)
+	a.So(decrypted, ShouldResemble, []byte{0x60, 0x04, 0x03, 0x02, 0x01})
+
+	dev.Relay.Enabled = false
+	downlinkMac.FrmPayload = []byte{0x60}
+	a.So(ns.encryptRelayDownlink(downlink, dev), ShouldNotBeNil)
+}
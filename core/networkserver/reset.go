@@ -0,0 +1,70 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+)
+
+// resetCID is the CID of the ResetInd and ResetConf MAC commands (LoRaWAN 1.1)
+const resetCID = 0x01
+
+// maxLoRaWANMinor is the highest minor version of LoRaWAN 1.x that is answered in a ResetConf
+const maxLoRaWANMinor = 1
+
+// resetMACState resets the MAC state of the device to the defaults that an
+// ABP device uses after a reboot. The frame counters and session keys are
+// not changed, as LoRaWAN 1.1 devices keep them over a reset.
+func (n *networkServer) resetMACState(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) error {
+	history, err := n.frameHistory(dev)
+	if err != nil {
+		return err
+	}
+	if err := history.Clear(); err != nil {
+		return err
+	}
+	dev.ADR = device.ADRSettings{Band: dev.ADR.Band, Margin: dev.ADR.Margin}
+	if dev.StaticADR.DataRate != "" || dev.StaticADR.TxPower != 0 || len(dev.StaticADR.Channels) != 0 {
+		dev.StaticADR.SendReq = true
+	}
+	dev.TxParams = device.TxParamSettings{}
+	n.handleUplinkTxParams(message, dev)
+	dev.PendingMACCommands = nil
+	return nil
+}
+
+// handleResetInd resets the MAC state of the device and answers a ResetInd
+// with a ResetConf. The minor version in the ResetConf is the highest
+// version that both the device and the NetworkServer support.
+func (n *networkServer) handleResetInd(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device, cmd pb_lorawan.MACCommand) error {
+	if len(cmd.Payload) != 1 {
+		return nil
+	}
+	minor := cmd.Payload[0] & 0x0F
+	if minor == 0 { // RFU in LoRaWAN 1.0
+		return nil
+	}
+	if minor > maxLoRaWANMinor {
+		minor = maxLoRaWANMinor
+	}
+
+	if err := n.resetMACState(message, dev); err != nil {
+		return err
+	}
+
+	if lorawanDownlinkMac := message.GetResponseTemplate().GetMessage().GetLorawan().GetMacPayload(); lorawanDownlinkMac != nil {
+		lorawanDownlinkMac.FOpts = append(lorawanDownlinkMac.FOpts, pb_lorawan.MACCommand{
+			Cid:     resetCID,
+			Payload: []byte{minor},
+		})
+	}
+	message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, "reset", "minor", minor)
+	message.DeviceReset = &pb_broker.DeviceReset{LorawanVersion: fmt.Sprintf("1.%d", minor)}
+	return nil
+}
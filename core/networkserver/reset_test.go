@@ -0,0 +1,63 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestHandleResetInd(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+
+	dev := &device.Device{
+		AppEUI:    types.AppEUI([8]byte{1}),
+		DevEUI:    types.DevEUI([8]byte{1}),
+		FCntUp:    42,
+		FCntDown:  12,
+		ADR:       device.ADRSettings{Band: "EU_863_870", Margin: 15, DataRate: "SF7BW125", TxPower: 2, NbTrans: 1, Failed: 1},
+		StaticADR: device.StaticADRSettings{DataRate: "SF9BW125"},
+		TxParams:  device.TxParamSettings{Configured: true, MaxEIRP: 16},
+		PendingMACCommands: []device.PendingMACCommand{
+			{CID: 0x06, Attempts: 1},
+		},
+	}
+	history, _ := ns.frameHistory(dev)
+	history.Push(&device.Frame{SNR: 10, GatewayCount: 3, FCnt: 41})
+
+	// LoRaWAN 1.0 devices don't send a ResetInd
+	message := adrInitUplinkMessage()
+	message.ResponseTemplate = adrInitDownlinkMessage()
+	a.So(ns.handleResetInd(message, dev, pb_lorawan.MACCommand{Cid: resetCID, Payload: []byte{0x00}}), ShouldBeNil)
+	a.So(dev.ADR.DataRate, ShouldEqual, "SF7BW125")
+	a.So(message.ResponseTemplate.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+
+	// A device with a newer minor version gets the version of the NetworkServer
+	a.So(ns.handleResetInd(message, dev, pb_lorawan.MACCommand{Cid: resetCID, Payload: []byte{0x02}}), ShouldBeNil)
+	fOpts := message.ResponseTemplate.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 1)
+	a.So(fOpts[0].Cid, ShouldEqual, resetCID)
+	a.So(fOpts[0].Payload, ShouldResemble, []byte{0x01})
+
+	a.So(dev.ADR, ShouldResemble, device.ADRSettings{Band: "EU_863_870", Margin: 15})
+	a.So(dev.StaticADR.SendReq, ShouldBeTrue)
+	a.So(dev.TxParams.Configured, ShouldBeFalse)
+	a.So(dev.PendingMACCommands, ShouldBeEmpty)
+	frames, _ := history.Get()
+	a.So(frames, ShouldBeEmpty)
+
+	// The session is kept
+	a.So(dev.FCntUp, ShouldEqual, 42)
+	a.So(dev.FCntDown, ShouldEqual, 12)
+
+	a.So(message.DeviceReset, ShouldNotBeNil)
+	a.So(message.DeviceReset.LorawanVersion, ShouldEqual, "1.1")
+}
@@ -19,6 +19,10 @@ type networkServerRPC struct {
 	networkServer NetworkServer
 }
 
+type sessionKeyUnwrapper interface {
+	unwrapSessionKeys(md metadata.MD, activation *handler.DeviceActivationResponse) error
+}
+
 func (s *networkServerRPC) ValidateContext(ctx context.Context) error {
 	md, ok := metadata.FromContext(ctx)
 	if !ok {
@@ -83,10 +87,17 @@ func (s *networkServerRPC) Activate(ctx context.Context, activation *handler.Dev
 	if err := activation.Validate(); err != nil {
 		return nil, errors.Wrap(err, "Invalid Activation Request")
 	}
+	if unwrapper, ok := s.networkServer.(sessionKeyUnwrapper); ok {
+		md, _ := metadata.FromContext(ctx)
+		if err := unwrapper.unwrapSessionKeys(md, activation); err != nil {
+			return nil, err
+		}
+	}
 	res, err := s.networkServer.HandleActivate(activation)
 	if err != nil {
 		return nil, err
 	}
+	stripSessionKeys(res)
 	return res, nil
 }
 
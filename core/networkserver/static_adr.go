@@ -0,0 +1,127 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/brocaar/lorawan"
+)
+
+// validateStaticADR validates static ADR settings. If the band of the device
+// is known, the settings are also validated against the band.
+func validateStaticADR(settings device.StaticADRSettings, bandName string) error {
+	if _, err := types.ParseDataRate(settings.DataRate); err != nil && !band.IsFSK(settings.DataRate) {
+		return errors.NewErrInvalidArgument("Data Rate", err.Error())
+	}
+	if settings.TxPower < 0 {
+		return errors.NewErrInvalidArgument("TX Power", "can not be negative")
+	}
+	var chMask lorawan.ChMask
+	for _, ch := range settings.Channels {
+		if ch < 0 || ch >= len(chMask) {
+			return errors.NewErrInvalidArgument("Channels", fmt.Sprintf("channel %d is not in the channel mask", ch))
+		}
+	}
+	if bandName == "" {
+		return nil
+	}
+	fp, err := band.Get(bandName)
+	if err != nil {
+		return nil // We can't check this band
+	}
+	if _, err := fp.GetDataRateIndexFor(settings.DataRate); err != nil {
+		return errors.NewErrInvalidArgument("Data Rate", fmt.Sprintf("%s is not available in %s", settings.DataRate, bandName))
+	}
+	if settings.TxPower != 0 {
+		if _, err := fp.GetTxPowerIndexFor(settings.TxPower); err != nil {
+			return errors.NewErrInvalidArgument("TX Power", fmt.Sprintf("%d dBm is not available in %s", settings.TxPower, bandName))
+		}
+	}
+	for _, ch := range settings.Channels {
+		if ch >= len(fp.UplinkChannels) {
+			return errors.NewErrInvalidArgument("Channels", fmt.Sprintf("channel %d is not available in %s", ch, bandName))
+		}
+	}
+	return nil
+}
+
+// setStaticADR schedules a one-shot LinkADRReq with the static ADR settings
+func (n *networkServer) setStaticADR(dev *device.Device, settings device.StaticADRSettings) error {
+	if err := validateStaticADR(settings, dev.ADR.Band); err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	settings.SendReq = true
+	dev.StaticADR = settings
+	return n.devices.Set(dev)
+}
+
+// clearStaticADR cancels a LinkADRReq with static ADR settings that was not sent yet
+func (n *networkServer) clearStaticADR(dev *device.Device) error {
+	dev.StartUpdate()
+	dev.StaticADR = device.StaticADRSettings{}
+	return n.devices.Set(dev)
+}
+
+// handleDownlinkStaticADR sends the static ADR settings in a LinkADRReq if the
+// device does not use ADR. The request is only sent once.
+func (n *networkServer) handleDownlinkStaticADR(message *pb_broker.DownlinkMessage, dev *device.Device) error {
+	if !dev.StaticADR.SendReq {
+		return nil
+	}
+	if dev.ADR.DataRate != "" || dev.ADR.Band == "" {
+		return nil // The device uses ADR, or we did not receive an uplink yet
+	}
+	settings := dev.StaticADR
+	dev.StaticADR.SendReq = false
+
+	if err := validateStaticADR(settings, dev.ADR.Band); err != nil {
+		n.Ctx.WithError(err).WithField("DevEUI", dev.DevEUI).Warn("Could not send static ADR settings")
+		return nil
+	}
+	fp, err := band.Get(dev.ADR.Band)
+	if err != nil {
+		return err
+	}
+	drIdx, err := fp.GetDataRateIndexFor(settings.DataRate)
+	if err != nil {
+		return err
+	}
+	if settings.TxPower == 0 {
+		settings.TxPower = fp.DefaultTXPower
+	}
+	powerIdx, err := fp.GetTxPowerIndexFor(settings.TxPower)
+	if err != nil {
+		return err
+	}
+
+	if len(settings.Channels) == 0 {
+		channels := make([]bool, len(fp.UplinkChannels))
+		for i := range channels {
+			channels[i] = true
+		}
+		appendLinkADRReqBlock(message, drIdx, powerIdx, channels, 1)
+	} else {
+		var chMask lorawan.ChMask
+		for _, ch := range settings.Channels {
+			chMask[ch] = true
+		}
+		appendLinkADRReq(message, drIdx, powerIdx, chMask, 0, 1)
+	}
+
+	return n.pushADRDecision(dev, &device.ADRDecision{
+		Time:     time.Now(),
+		DataRate: settings.DataRate,
+		TxPower:  settings.TxPower,
+		NbTrans:  1,
+		Reason:   "static settings for a device that does not use ADR",
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/brocaar/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestValidateStaticADR(t *testing.T) {
+	a := New(t)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "SF9BW125"}, ""), ShouldBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "SF9BW125", TxPower: 14, Channels: []int{0, 1, 2}}, "EU_863_870"), ShouldBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{}, ""), ShouldNotBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "SF9BW125", TxPower: -1}, ""), ShouldNotBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "SF9BW125", Channels: []int{16}}, ""), ShouldNotBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "SF9BW500"}, "EU_863_870"), ShouldNotBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "SF9BW125", TxPower: 1}, "EU_863_870"), ShouldNotBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "SF9BW125", Channels: []int{12}}, "EU_863_870"), ShouldNotBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "50000"}, "EU_863_870"), ShouldBeNil)
+	a.So(validateStaticADR(device.StaticADRSettings{DataRate: "50000"}, "US_902_928"), ShouldNotBeNil)
+}
+
+func TestHandleDownlinkStaticADR(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleDownlinkStaticADR")},
+		devices:   device.NewMemoryDeviceStore(),
+	}
+	ns.InitStatus()
+
+	dev := &device.Device{AppEUI: types.AppEUI([8]byte{1}), DevEUI: types.DevEUI([8]byte{1})}
+
+	// Nothing to send
+	message := adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkStaticADR(message, dev), ShouldBeNil)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+
+	a.So(ns.setStaticADR(dev, device.StaticADRSettings{DataRate: "invalid"}), ShouldNotBeNil)
+	a.So(ns.setStaticADR(dev, device.StaticADRSettings{DataRate: "SF9BW125", Channels: []int{0, 1, 2}}), ShouldBeNil)
+	a.So(dev.StaticADR.SendReq, ShouldBeTrue)
+
+	// Wait for the band of the device
+	message = adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkStaticADR(message, dev), ShouldBeNil)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+	a.So(dev.StaticADR.SendReq, ShouldBeTrue)
+
+	// Not for devices that use ADR
+	dev.ADR.Band = "EU_863_870"
+	dev.ADR.DataRate = "SF7BW125"
+	message = adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkStaticADR(message, dev), ShouldBeNil)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+	a.So(dev.StaticADR.SendReq, ShouldBeTrue)
+
+	dev.ADR.DataRate = ""
+	message = adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkStaticADR(message, dev), ShouldBeNil)
+	fOpts := message.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 1)
+	a.So(fOpts[0].Cid, ShouldEqual, lorawan.LinkADRReq)
+	payload := new(lorawan.LinkADRReqPayload)
+	payload.UnmarshalBinary(fOpts[0].Payload)
+	a.So(payload.DataRate, ShouldEqual, 3) // SF9BW125
+	a.So(payload.TXPower, ShouldEqual, 1)  // 14
+	a.So(payload.ChMask[0], ShouldBeTrue)
+	a.So(payload.ChMask[2], ShouldBeTrue)
+	a.So(payload.ChMask[3], ShouldBeFalse)
+	a.So(dev.StaticADR.SendReq, ShouldBeFalse)
+
+	history, _ := ns.devices.ADRHistory(dev.AppEUI, dev.DevEUI)
+	decisions, _ := history.Get()
+	a.So(decisions, ShouldHaveLength, 1)
+	a.So(decisions[0].DataRate, ShouldEqual, "SF9BW125")
+
+	// One-shot
+	message = adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkStaticADR(message, dev), ShouldBeNil)
+	a.So(message.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+
+	// Cancel
+	a.So(ns.setStaticADR(dev, device.StaticADRSettings{DataRate: "SF10BW125"}), ShouldBeNil)
+	a.So(ns.clearStaticADR(dev), ShouldBeNil)
+	a.So(dev.StaticADR.SendReq, ShouldBeFalse)
+}
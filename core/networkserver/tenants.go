@@ -0,0 +1,133 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// tenantPrefixUsage is offered by the DevAddr blocks of tenants, which are only used for the devices of the tenant
+var tenantPrefixUsage = []string{"otaa", "abp", "world", "local", "private", "testing"}
+
+func prefixesOverlap(a, b types.DevAddrPrefix) bool {
+	return a.DevAddr.HasPrefix(b) || b.DevAddr.HasPrefix(a)
+}
+
+// UseTenants reserves the DevAddr blocks of the tenants for the devices of
+// their applications. The blocks must not overlap with the other prefixes.
+func (n *networkServer) UseTenants(tenants types.Tenants) error {
+	for _, tenant := range tenants {
+		for _, prefix := range tenant.Prefixes {
+			for other := range n.prefixes {
+				if prefixesOverlap(prefix, other) {
+					return errors.NewErrInvalidArgument("Prefix", fmt.Sprintf("block %s of tenant %s overlaps with %s", prefix, tenant.ID, other))
+				}
+			}
+			if err := n.UsePrefix(prefix, tenantPrefixUsage); err != nil {
+				return err
+			}
+		}
+	}
+	n.tenants = tenants
+	return nil
+}
+
+// tenantPrefixes returns the prefixes that match the constraints and that can
+// be used for devices of the tenant: the blocks of the tenant if it has any,
+// otherwise the prefixes that are not reserved for a tenant
+func (n *networkServer) tenantPrefixes(tenant *types.Tenant, constraints ...string) (prefixes []types.DevAddrPrefix) {
+	for _, prefix := range n.GetPrefixesFor(constraints...) {
+		owner := n.tenants.ForDevAddr(prefix.DevAddr)
+		if tenant != nil && len(tenant.Prefixes) > 0 {
+			if owner == tenant {
+				prefixes = append(prefixes, prefix)
+			}
+			continue
+		}
+		if owner == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return
+}
+
+// checkTenantDevAddr returns an error if the DevAddr of a device of the
+// application is not in the blocks that can be used by its tenant
+func (n *networkServer) checkTenantDevAddr(appID string, devAddr types.DevAddr) error {
+	tenant := n.tenants.ForApplication(appID)
+	owner := n.tenants.ForDevAddr(devAddr)
+	if tenant != nil && len(tenant.Prefixes) > 0 {
+		if owner != tenant {
+			return errors.NewErrInvalidArgument("DevAddr", fmt.Sprintf("must be in a block of tenant %s", tenant.ID))
+		}
+		return nil
+	}
+	if owner != nil {
+		return errors.NewErrInvalidArgument("DevAddr", fmt.Sprintf("is reserved for tenant %s", owner.ID))
+	}
+	return nil
+}
+
+// tenantDevices counts the devices of the tenants that have a device limit.
+// The NetworkServer has the devices of all Handlers, so it enforces the limits.
+type tenantDevices struct {
+	sync.Mutex
+	counts map[string]int // nil until the devices are counted
+}
+
+// countTenantDevices counts the devices of the tenants with a device limit if
+// they were not counted yet. The lock must be held.
+func (n *networkServer) countTenantDevices() error {
+	if n.tenantDevices.counts != nil {
+		return nil
+	}
+	devices, err := n.devices.List(nil)
+	if err != nil {
+		return err
+	}
+	counts := make(map[string]int)
+	for _, dev := range devices {
+		if tenant := n.tenants.ForApplication(dev.AppID); tenant != nil && tenant.Devices > 0 {
+			counts[tenant.ID]++
+		}
+	}
+	n.tenantDevices.counts = counts
+	return nil
+}
+
+// addTenantDevice counts a device that is added to the application, or
+// returns an error if the tenant of the application reached its device limit
+func (n *networkServer) addTenantDevice(appID string) error {
+	tenant := n.tenants.ForApplication(appID)
+	if tenant == nil || tenant.Devices == 0 {
+		return nil
+	}
+	n.tenantDevices.Lock()
+	defer n.tenantDevices.Unlock()
+	if err := n.countTenantDevices(); err != nil {
+		return err
+	}
+	if n.tenantDevices.counts[tenant.ID] >= tenant.Devices {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Tenant %s reached its limit of %d devices", tenant.ID, tenant.Devices))
+	}
+	n.tenantDevices.counts[tenant.ID]++
+	return nil
+}
+
+// removeTenantDevice stops counting a device that is removed from the application
+func (n *networkServer) removeTenantDevice(appID string) {
+	tenant := n.tenants.ForApplication(appID)
+	if tenant == nil || tenant.Devices == 0 {
+		return
+	}
+	n.tenantDevices.Lock()
+	defer n.tenantDevices.Unlock()
+	if n.tenantDevices.counts[tenant.ID] > 0 {
+		n.tenantDevices.counts[tenant.ID]--
+	}
+}
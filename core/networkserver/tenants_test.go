@@ -0,0 +1,78 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestTenants(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		netID:    [3]byte{0x00, 0x00, 0x13},
+		prefixes: map[types.DevAddrPrefix][]string{},
+	}
+	shared, _ := types.ParseDevAddrPrefix("26000000/20")
+	a.So(ns.UsePrefix(shared, []string{"otaa", "abp"}), ShouldBeNil)
+
+	overlapping, _ := types.ParseTenants(map[string]string{"acme": "applications=acme-*;prefixes=26000800/24"})
+	a.So(ns.UseTenants(overlapping), ShouldNotBeNil)
+
+	tenants, _ := types.ParseTenants(map[string]string{
+		"acme":   "applications=acme-*;prefixes=26011000/24",
+		"globex": "applications=globex-*",
+	})
+	a.So(ns.UseTenants(tenants), ShouldBeNil)
+	acme, globex := tenants[0], tenants[1]
+
+	// Tenants with blocks only get addresses in their blocks, other devices only get shared addresses
+	for i := 0; i < 10; i++ {
+		devAddr, err := ns.getDevAddr(acme, "otaa")
+		a.So(err, ShouldBeNil)
+		a.So(acme.HasDevAddr(devAddr), ShouldBeTrue)
+
+		devAddr, err = ns.getDevAddr(globex, "otaa")
+		a.So(err, ShouldBeNil)
+		a.So(devAddr.HasPrefix(shared), ShouldBeTrue)
+
+		devAddr, err = ns.getDevAddr(nil, "otaa")
+		a.So(err, ShouldBeNil)
+		a.So(devAddr.HasPrefix(shared), ShouldBeTrue)
+	}
+
+	a.So(ns.checkTenantDevAddr("acme-sensors", types.DevAddr{0x26, 0x01, 0x10, 0x01}), ShouldBeNil)
+	a.So(ns.checkTenantDevAddr("acme-sensors", types.DevAddr{0x26, 0x00, 0x00, 0x01}), ShouldNotBeNil)
+	a.So(ns.checkTenantDevAddr("globex-sensors", types.DevAddr{0x26, 0x01, 0x10, 0x01}), ShouldNotBeNil)
+	a.So(ns.checkTenantDevAddr("globex-sensors", types.DevAddr{0x26, 0x00, 0x00, 0x01}), ShouldBeNil)
+	a.So(ns.checkTenantDevAddr("other", types.DevAddr{0x26, 0x01, 0x10, 0x01}), ShouldNotBeNil)
+}
+
+func TestTenantDevices(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+	tenants, _ := types.ParseTenants(map[string]string{
+		"acme":   "applications=acme-*;devices=2",
+		"globex": "applications=globex-*",
+	})
+	ns.tenants = tenants
+
+	// The devices of all Handlers are counted when the first device is added
+	ns.devices.Set(&device.Device{AppID: "acme-sensors", DevEUI: types.DevEUI{1}})
+	ns.devices.Set(&device.Device{AppID: "globex-sensors", DevEUI: types.DevEUI{2}})
+	a.So(ns.addTenantDevice("acme-meters"), ShouldBeNil)
+	err := ns.addTenantDevice("acme-sensors")
+	a.So(errors.GetErrType(err), ShouldEqual, errors.PermissionDenied)
+	a.So(ns.addTenantDevice("globex-sensors"), ShouldBeNil)
+	a.So(ns.addTenantDevice("shared"), ShouldBeNil)
+
+	ns.removeTenantDevice("acme-meters")
+	a.So(ns.addTenantDevice("acme-sensors"), ShouldBeNil)
+}
@@ -0,0 +1,46 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"fmt"
+
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// checkTransfer checks that a device can be transferred by a Handler. A
+// Handler can only transfer devices between applications that it handles.
+func checkTransfer(handler *pb_discovery.Announcement, dev *device.Device, appID string) error {
+	if handler.ServiceName != "handler" {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Devices can not be transferred by a %s", handler.ServiceName))
+	}
+	var fromHandled, toHandled bool
+	for _, handled := range handler.AppIDs() {
+		if handled == dev.AppID {
+			fromHandled = true
+		}
+		if handled == appID {
+			toHandled = true
+		}
+	}
+	if !fromHandled || !toHandled {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Handler %s does not handle both applications", handler.Id))
+	}
+	return nil
+}
+
+// transferDevice moves a device to another application. The data that was
+// collected about the device for the previous application is erased, but the
+// session of the device is kept.
+func (n *networkServer) transferDevice(dev *device.Device, appID, devID string) error {
+	if err := n.eraseDeviceData(dev); err != nil {
+		return err
+	}
+	dev.StartUpdate()
+	dev.AppID = appID
+	dev.DevID = devID
+	return n.devices.Set(dev)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestCheckTransfer(t *testing.T) {
+	a := New(t)
+
+	handler := &pb_discovery.Announcement{Id: "handler", ServiceName: "handler", Metadata: []*pb_discovery.Metadata{
+		{Metadata: &pb_discovery.Metadata_AppId{AppId: "manufacturer"}},
+		{Metadata: &pb_discovery.Metadata_AppId{AppId: "customer"}},
+	}}
+	dev := &device.Device{AppID: "manufacturer", DevID: "dev"}
+
+	a.So(checkTransfer(handler, dev, "customer"), ShouldBeNil)
+	a.So(checkTransfer(handler, dev, "other"), ShouldNotBeNil)
+	a.So(checkTransfer(handler, &device.Device{AppID: "other"}, "customer"), ShouldNotBeNil)
+	a.So(checkTransfer(&pb_discovery.Announcement{Id: "broker", ServiceName: "broker", Metadata: handler.Metadata}, dev, "customer"), ShouldNotBeNil)
+}
+
+func TestTransferDevice(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		devices: device.NewMemoryDeviceStore(),
+	}
+
+	appEUI := types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	devEUI := types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8}
+	a.So(ns.devices.Set(&device.Device{
+		AppEUI:  appEUI,
+		DevEUI:  devEUI,
+		AppID:   "manufacturer",
+		DevID:   "dev",
+		DevAddr: types.DevAddr{1, 2, 3, 4},
+	}), ShouldBeNil)
+	history, _ := ns.devices.ADRHistory(appEUI, devEUI)
+	history.Push(&device.ADRDecision{DataRate: "SF7BW125"})
+
+	dev, _ := ns.devices.Get(appEUI, devEUI)
+	a.So(ns.transferDevice(dev, "customer", "sensor"), ShouldBeNil)
+
+	dev, err := ns.devices.Get(appEUI, devEUI)
+	a.So(err, ShouldBeNil)
+	a.So(dev.AppID, ShouldEqual, "customer")
+	a.So(dev.DevID, ShouldEqual, "sensor")
+	a.So(dev.DevAddr, ShouldEqual, types.DevAddr{1, 2, 3, 4})
+	decisions, _ := history.Get()
+	a.So(decisions, ShouldBeEmpty)
+}
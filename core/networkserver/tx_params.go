@@ -0,0 +1,86 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+)
+
+// txParamSetupCID is the CID of the TxParamSetupReq and TxParamSetupAns MAC commands (LoRaWAN 1.0.2)
+const txParamSetupCID = 0x09
+
+// MaxTxParamSetupAttempts is the number of downlinks with a TxParamSetupReq
+// after which the NetworkServer stops sending it to a device that does not
+// answer, such as a device that does not implement the command
+var MaxTxParamSetupAttempts = 6
+
+// maxEIRPTable contains the values of the MaxEIRP field of the TxParamSetupReq
+var maxEIRPTable = []int{8, 10, 12, 13, 14, 16, 18, 20, 21, 24, 26, 27, 29, 30, 33, 36}
+
+// txParamsForRegion contains the transmit parameters that are configured in
+// regions that support TxParamSetupReq
+var txParamsForRegion = map[string]device.TxParamSettings{
+	pb_lorawan.Region_AS_923.String(): {UplinkDwellTime: true, DownlinkDwellTime: true, MaxEIRP: 16},
+}
+
+func maxEIRPIndex(maxEIRP int) uint8 {
+	var idx uint8
+	for i, eirp := range maxEIRPTable {
+		if eirp <= maxEIRP {
+			idx = uint8(i)
+		}
+	}
+	return idx
+}
+
+func buildTxParamSetupReq(settings device.TxParamSettings) []byte {
+	payload := maxEIRPIndex(settings.MaxEIRP)
+	if settings.UplinkDwellTime {
+		payload |= 1 << 4
+	}
+	if settings.DownlinkDwellTime {
+		payload |= 1 << 5
+	}
+	return []byte{payload}
+}
+
+func (n *networkServer) handleUplinkTxParams(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) {
+	if dev.TxParams.Configured || dev.TxParams.Attempts >= MaxTxParamSetupAttempts || !supportsLoRaWAN102(dev) {
+		return
+	}
+	settings, ok := txParamsForRegion[message.GetProtocolMetadata().GetLorawan().GetRegion().String()]
+	if !ok {
+		return
+	}
+	settings.SendReq = true
+	settings.Attempts = dev.TxParams.Attempts
+	dev.TxParams = settings
+}
+
+func (n *networkServer) handleTxParamSetupAns(message *pb_broker.DeduplicatedUplinkMessage, dev *device.Device) {
+	message.Trace = message.Trace.WithEvent(trace.HandleMACEvent, macCMD, "tx-param-setup")
+	dev.TxParams.SendReq = false
+	dev.TxParams.Configured = true
+}
+
+func (n *networkServer) handleDownlinkTxParams(message *pb_broker.DownlinkMessage, dev *device.Device) error {
+	if !dev.TxParams.SendReq {
+		return nil
+	}
+	if dev.TxParams.Attempts >= MaxTxParamSetupAttempts {
+		dev.TxParams.SendReq = false
+		n.Ctx.WithField("AppID", dev.AppID).WithField("DevID", dev.DevID).Warn("Device did not answer TxParamSetupReq")
+		return nil
+	}
+	dev.TxParams.Attempts++
+	lorawanDownlinkMac := message.GetMessage().GetLorawan().GetMacPayload()
+	lorawanDownlinkMac.FOpts = append(lorawanDownlinkMac.FOpts, pb_lorawan.MACCommand{
+		Cid:     txParamSetupCID,
+		Payload: buildTxParamSetupReq(dev.TxParams),
+	})
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package networkserver
+
+import (
+	"testing"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestBuildTxParamSetupReq(t *testing.T) {
+	a := New(t)
+	a.So(buildTxParamSetupReq(device.TxParamSettings{MaxEIRP: 8}), ShouldResemble, []byte{0x00})
+	a.So(buildTxParamSetupReq(device.TxParamSettings{MaxEIRP: 16, UplinkDwellTime: true}), ShouldResemble, []byte{0x15})
+	a.So(buildTxParamSetupReq(device.TxParamSettings{MaxEIRP: 17, UplinkDwellTime: true, DownlinkDwellTime: true}), ShouldResemble, []byte{0x35})
+	a.So(buildTxParamSetupReq(device.TxParamSettings{MaxEIRP: 36}), ShouldResemble, []byte{0x0f})
+}
+
+func TestHandleTxParams(t *testing.T) {
+	a := New(t)
+	ns := &networkServer{
+		Component: &component.Component{Ctx: GetLogger(t, "TestHandleTxParams")},
+	}
+	dev := &device.Device{}
+
+	// EU868 does not support TxParamSetupReq
+	message := adrInitUplinkMessage()
+	message.ProtocolMetadata.GetLorawan().Region = pb_lorawan.Region_EU_863_870
+	ns.handleUplinkTxParams(message, dev)
+	a.So(dev.TxParams.SendReq, ShouldBeFalse)
+
+	message = adrInitUplinkMessage()
+	message.ProtocolMetadata.GetLorawan().Region = pb_lorawan.Region_AS_923
+	ns.handleUplinkTxParams(message, dev)
+	a.So(dev.TxParams.SendReq, ShouldBeTrue)
+	a.So(dev.TxParams.DownlinkDwellTime, ShouldBeTrue)
+
+	downlink := adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkTxParams(downlink, dev), ShouldBeNil)
+	fOpts := downlink.Message.GetLorawan().GetMacPayload().FOpts
+	a.So(fOpts, ShouldHaveLength, 1)
+	a.So(fOpts[0].Cid, ShouldEqual, txParamSetupCID)
+	a.So(fOpts[0].Payload, ShouldResemble, []byte{0x35})
+
+	ns.handleTxParamSetupAns(message, dev)
+	a.So(dev.TxParams.SendReq, ShouldBeFalse)
+	a.So(dev.TxParams.Configured, ShouldBeTrue)
+
+	ns.handleUplinkTxParams(message, dev)
+	a.So(dev.TxParams.SendReq, ShouldBeFalse)
+
+	downlink = adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkTxParams(downlink, dev), ShouldBeNil)
+	a.So(downlink.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+
+	// Devices that do not answer do not get the request forever
+	dev = &device.Device{}
+	for i := 0; i < MaxTxParamSetupAttempts; i++ {
+		ns.handleUplinkTxParams(message, dev)
+		downlink = adrInitDownlinkMessage()
+		a.So(ns.handleDownlinkTxParams(downlink, dev), ShouldBeNil)
+		a.So(downlink.Message.GetLorawan().GetMacPayload().FOpts, ShouldHaveLength, 1)
+	}
+	a.So(dev.TxParams.Attempts, ShouldEqual, MaxTxParamSetupAttempts)
+	downlink = adrInitDownlinkMessage()
+	a.So(ns.handleDownlinkTxParams(downlink, dev), ShouldBeNil)
+	a.So(downlink.Message.GetLorawan().GetMacPayload().FOpts, ShouldBeEmpty)
+	a.So(dev.TxParams.SendReq, ShouldBeFalse)
+	ns.handleUplinkTxParams(message, dev)
+	a.So(dev.TxParams.SendReq, ShouldBeFalse)
+}
@@ -29,6 +29,11 @@ func (n *networkServer) HandleUplink(message *pb_broker.DeduplicatedUplinkMessag
 		return nil, err
 	}
 
+	// Replays that the Broker detected as replay attacks only quarantine the device
+	if message.ReplayAttack != nil {
+		return n.quarantineDevice(message, dev)
+	}
+
 	message.Trace = message.Trace.WithEvent(trace.UpdateStateEvent)
 
 	dev.StartUpdate()
@@ -44,6 +49,14 @@ func (n *networkServer) HandleUplink(message *pb_broker.DeduplicatedUplinkMessag
 
 	dev.FCntUp = lorawanUplinkMac.FCnt
 	dev.LastSeen = time.Now()
+	airtime := time.Duration(message.GetProtocolMetadata().GetLorawan().GetAirtime())
+	if airtime > 0 {
+		dev.UplinkAirtime.Add(dev.LastSeen, airtime)
+	}
+	n.handleUplinkLinkQuality(message, dev)
+	n.handleUplinkRelay(message, dev)
+	n.handleUplinkFairAccess(message, dev, airtime)
+	downlinkAllowed := !dev.Quarantine.Active()
 
 	// Prepare Downlink
 	message.InitResponseTemplate()
@@ -61,13 +74,20 @@ func (n *networkServer) HandleUplink(message *pb_broker.DeduplicatedUplinkMessag
 		return nil, err
 	}
 
+	if message.ResponseTemplate.DownlinkOption != nil && downlinkAllowed {
+		err = n.handleUplinkMACDownlink(message, dev)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	message.ResponseTemplate.Payload, err = lorawanDownlinkMsg.PHYPayload().MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
 
-	// Unset response if no downlink option
-	if message.ResponseTemplate.DownlinkOption == nil {
+	// Unset response if no downlink option, or if downlink is not allowed (quarantine)
+	if message.ResponseTemplate.DownlinkOption == nil || !downlinkAllowed {
 		message.ResponseTemplate = nil
 	}
 
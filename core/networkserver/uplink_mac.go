@@ -34,10 +34,17 @@ func (n *networkServer) handleUplinkMAC(message *pb_broker.DeduplicatedUplinkMes
 	}
 
 	// Adaptive DataRate
+	requestedADR := dev.ADR
 	if err := n.handleUplinkADR(message, dev); err != nil {
 		return err
 	}
 
+	// Transmit parameters
+	n.handleUplinkTxParams(message, dev)
+
+	// Answers to pending MAC commands
+	n.handleUplinkPendingMAC(message, dev)
+
 	// MAC Commands
 	for _, cmd := range lorawanUplinkMac.FOpts {
 		switch cmd.Cid {
@@ -65,12 +72,31 @@ func (n *networkServer) handleUplinkMAC(message *pb_broker.DeduplicatedUplinkMes
 			if answer.DataRateACK && answer.PowerACK && answer.ChannelMaskACK {
 				dev.ADR.Failed = 0
 				dev.ADR.SendReq = false
+				dev.ADR.Fallback = false
 			} else {
-				dev.ADR.Failed++
+				n.handleLinkADRRejection(dev, requestedADR, answer)
 				ctx.
 					WithField("Answer", fmt.Sprintf("%v/%v/%v", answer.DataRateACK, answer.PowerACK, answer.ChannelMaskACK)).
 					Warn("Negative LinkADRAns")
 			}
+		case txParamSetupCID:
+			n.handleTxParamSetupAns(message, dev)
+		case deviceTimeCID:
+			n.handleDeviceTimeReq(message)
+		case resetCID:
+			if err := n.handleResetInd(message, dev, cmd); err != nil {
+				return err
+			}
+		case relayConfCID:
+			n.handleRelayConfAns(message, dev, cmd)
+		case endDeviceConfCID:
+			n.handleEndDeviceConfAns(message, dev, cmd)
+		case updateUplinkListCID:
+			n.handleUpdateUplinkListAns(message, dev)
+		case configureFwdLimitCID:
+			n.handleConfigureFwdLimitAns(message, dev)
+		case notifyNewEndDeviceCID:
+			n.handleNotifyNewEndDeviceReq(message, dev, cmd)
 		default:
 		}
 	}
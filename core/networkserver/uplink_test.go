@@ -92,7 +92,9 @@ func TestHandleUplink(t *testing.T) {
 		},
 		ProtocolMetadata: &pb_protocol.RxMetadata{Protocol: &pb_protocol.RxMetadata_Lorawan{
 			Lorawan: &pb_lorawan.Metadata{
-				DataRate: "SF7BW125",
+				DataRate:   "SF7BW125",
+				CodingRate: "4/5",
+				Airtime:    41216000,
 			},
 		}},
 	}
@@ -117,4 +119,9 @@ func TestHandleUplink(t *testing.T) {
 	dev, _ := ns.devices.Get(appEUI, devEUI)
 	a.So(dev.FCntUp, ShouldEqual, 1)
 	a.So(time.Now().Sub(dev.LastSeen), ShouldBeLessThan, 1*time.Second)
+
+	// Airtime should have been counted
+	a.So(dev.UplinkAirtime.Messages, ShouldEqual, 1)
+	a.So(dev.UplinkAirtime.Total, ShouldEqual, 41216*time.Microsecond)
+	a.So(dev.UplinkAirtime.Today, ShouldEqual, dev.UplinkAirtime.Total)
 }
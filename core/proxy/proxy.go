@@ -85,3 +85,23 @@ func (h *paginatedHandler) ServeHTTP(res http.ResponseWriter, req *http.Request)
 func WithPagination(h http.Handler) http.Handler {
 	return &paginatedHandler{h}
 }
+
+type swaggerHandler struct {
+	handler http.Handler
+	spec    string
+}
+
+func (h *swaggerHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet && req.URL.Path == "/swagger.json" {
+		res.Header().Set("Content-Type", "application/json")
+		res.Header().Set("Access-Control-Allow-Origin", "*")
+		res.Write([]byte(h.spec))
+		return
+	}
+	h.handler.ServeHTTP(res, req)
+}
+
+// WithSwagger wraps the handler so that the OpenAPI (Swagger) specification is served on /swagger.json
+func WithSwagger(h http.Handler, spec string) http.Handler {
+	return &swaggerHandler{h, spec}
+}
@@ -124,3 +124,21 @@ func TestPaginatedProxier(t *testing.T) {
 	a.So(hdl.req, ShouldBeNil)
 	a.So(w.Code, ShouldEqual, http.StatusBadRequest)
 }
+
+func TestSwaggerHandler(t *testing.T) {
+	a := New(t)
+
+	hdl := &testHandler{}
+	p := WithSwagger(hdl, `{"swagger":"2.0"}`)
+
+	req := httptest.NewRequest("GET", "/swagger.json", bytes.NewBuffer([]byte{}))
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+	a.So(hdl.req, ShouldBeNil)
+	a.So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+	a.So(rec.Body.String(), ShouldEqual, `{"swagger":"2.0"}`)
+
+	req = httptest.NewRequest("GET", "/applications", bytes.NewBuffer([]byte{}))
+	p.ServeHTTP(httptest.NewRecorder(), req)
+	a.So(hdl.req, ShouldNotBeNil)
+}
@@ -32,6 +32,7 @@ func (r *router) HandleActivation(gatewayID string, activation *pb.DeviceActivat
 	r.status.activations.Mark(1)
 
 	activation.Trace = activation.Trace.WithEvent(trace.ReceiveEvent, "gateway", gatewayID)
+	setAirtime(activation.Payload, activation.ProtocolMetadata)
 
 	gateway := r.getGateway(gatewayID)
 	gateway.LastSeen = time.Now()
@@ -47,6 +48,16 @@ func (r *router) HandleActivation(gatewayID string, activation *pb.DeviceActivat
 		return nil, err
 	}
 
+	if activation.AppEui != nil && !r.filter.allowJoinEUI(*activation.AppEui) {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("Network for JoinEUI %s", activation.AppEui))
+	}
+
+	if activation.AppEui != nil && activation.DevEui != nil {
+		if rule, ok := r.joinFilter.allow(*activation.AppEui, *activation.DevEui); !ok {
+			return nil, errors.NewErrPermissionDenied(fmt.Sprintf("Join request of DevEUI %s with JoinEUI %s dropped by rule \"%s\"", activation.DevEui, activation.AppEui, rule))
+		}
+	}
+
 	if !gateway.Schedule.IsActive() {
 		return nil, errors.NewErrInternal(fmt.Sprintf("Gateway %s not available for downlink", gatewayID))
 	}
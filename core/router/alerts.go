@@ -0,0 +1,46 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/alerting"
+)
+
+// SetGatewayOfflineAlert makes the Router alert the operators when a gateway
+// was not seen for the duration. The alert is disabled if it is 0.
+func (r *router) SetGatewayOfflineAlert(after time.Duration) {
+	r.gatewayOfflineAlert = after
+}
+
+// checkGatewaysOffline fires the offline alerts of the gateways that were not
+// seen for the duration of the alert, and resolves those of the others
+func (r *router) checkGatewaysOffline(now time.Time) {
+	r.gatewaysLock.RLock()
+	defer r.gatewaysLock.RUnlock()
+	for id, gtw := range r.gateways {
+		if gtw.LastSeen.IsZero() {
+			continue
+		}
+		offline := now.Sub(gtw.LastSeen)
+		if offline > r.gatewayOfflineAlert {
+			r.Alerts.Update(alerting.GatewayOffline, id, true, fmt.Sprintf("Gateway %s was not seen for %s", id, offline/time.Second*time.Second))
+		} else {
+			r.Alerts.Update(alerting.GatewayOffline, id, false, fmt.Sprintf("Gateway %s is online again", id))
+		}
+	}
+}
+
+func (r *router) handleGatewayOfflineAlerts() {
+	if r.gatewayOfflineAlert == 0 {
+		return
+	}
+	go func() {
+		for range time.Tick(alerting.CheckInterval) {
+			r.checkGatewaysOffline(time.Now())
+		}
+	}()
+}
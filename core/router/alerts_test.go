@@ -0,0 +1,40 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/alerting"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestCheckGatewaysOffline(t *testing.T) {
+	a := New(t)
+
+	r := getTestRouter(t)
+	defer r.ctrl.Finish()
+	r.Identity = &pb_discovery.Announcement{Id: "router"}
+	r.Alerts = alerting.NewManager(GetLogger(t, "TestCheckGatewaysOffline"), "router")
+	r.SetGatewayOfflineAlert(10 * time.Minute)
+
+	now := time.Now()
+	r.gateways["online"] = &gateway.Gateway{ID: "online", LastSeen: now.Add(-1 * time.Minute)}
+	r.gateways["offline"] = &gateway.Gateway{ID: "offline", LastSeen: now.Add(-15 * time.Minute)}
+	r.gateways["unseen"] = &gateway.Gateway{ID: "unseen"}
+
+	r.checkGatewaysOffline(now)
+	firing := r.Alerts.Firing()
+	a.So(firing, ShouldHaveLength, 1)
+	a.So(firing[0].Subject, ShouldEqual, "offline")
+	a.So(firing[0].Message, ShouldEqual, "Gateway offline was not seen for 15m0s")
+
+	r.gateways["offline"].LastSeen = now
+	r.checkGatewaysOffline(now)
+	a.So(r.Alerts.Firing(), ShouldBeEmpty)
+}
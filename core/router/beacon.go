@@ -0,0 +1,122 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/router/frequencyplan"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
+)
+
+// BeaconLead is how long before the beacon time the beacons are scheduled
+var BeaconLead = 5 * time.Second
+
+func (r *router) EnableBeacons() {
+	r.beacons = true
+}
+
+// beaconSettings returns the beacon settings of the frequency plan of the
+// gateway, or the default settings of the region of the gateway
+func (r *router) beaconSettings(gtw *gateway.Gateway) (*frequencyplan.Beacon, bool) {
+	if r.frequencyPlans != nil {
+		if plan, err := r.frequencyPlans.ForGateway(gtw.ID); err == nil {
+			return plan.Beacon, plan.Beacon != nil
+		}
+	}
+	status, err := gtw.Status.Get()
+	if err != nil {
+		return nil, false
+	}
+	return frequencyplan.DefaultBeacon(status.Region)
+}
+
+// scheduleBeacons schedules the beacon at beaconTime on all gateways that are
+// connected for downlink and that have beacon settings
+func (r *router) scheduleBeacons(beaconTime time.Time) {
+	r.gatewaysLock.RLock()
+	gateways := make([]*gateway.Gateway, 0, len(r.gateways))
+	for _, gtw := range r.gateways {
+		gateways = append(gateways, gtw)
+	}
+	r.gatewaysLock.RUnlock()
+
+	for _, gtw := range gateways {
+		if !gtw.Schedule.IsActive() {
+			continue
+		}
+		settings, ok := r.beaconSettings(gtw)
+		if !ok {
+			continue
+		}
+		if err := r.scheduleBeacon(gtw, settings, beaconTime); err != nil {
+			r.Ctx.WithField("GatewayID", gtw.ID).WithError(err).Debug("Could not schedule beacon")
+			gtw.BeaconMissed(err)
+			continue
+		}
+		gtw.BeaconSent(beaconTime)
+	}
+}
+
+// scheduleBeacon schedules the beacon at beaconTime on the gateway
+func (r *router) scheduleBeacon(gtw *gateway.Gateway, settings *frequencyplan.Beacon, beaconTime time.Time) error {
+	timestamp, err := gtw.TimestampAt(beaconTime)
+	if err != nil {
+		return err
+	}
+	var location *gateway.Location
+	if l, ok := gtw.Location.Get(); ok {
+		location = l
+	}
+	payload, err := gateway.BuildBeacon(settings.DataRate, beaconTime, location)
+	if err != nil {
+		return err
+	}
+	timeOnAir, err := toa.ComputeLoRa(uint(len(payload)), settings.DataRate, "4/5")
+	if err != nil {
+		return err
+	}
+	var beaconTrace *trace.Trace
+	id, score := gtw.Schedule.GetOption(timestamp, uint32(timeOnAir/time.Microsecond))
+	if score > 0 {
+		return errors.NewErrInternal(fmt.Sprintf("Beacon conflicts with %d scheduled transmissions", score))
+	}
+	return gtw.HandleDownlink(id, &pb.DownlinkMessage{
+		Payload: payload,
+		ProtocolConfiguration: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{
+			Modulation: pb_lorawan.Modulation_LORA,
+			DataRate:   settings.DataRate,
+			CodingRate: "4/5",
+		}}},
+		GatewayConfiguration: &pb_gateway.TxConfiguration{
+			Timestamp: timestamp,
+			RfChain:   0,
+			// Beacons are sent without polarization inversion, which is how gateway bridges recognize
+			// them and send them with an implicit header and without CRC
+			PolarizationInversion: false,
+			Frequency:             settings.Frequency(gateway.BeaconPeriodIndex(beaconTime)),
+			Power:                 settings.Power,
+		},
+		Priority: string(types.DownlinkPriorityMAC),
+		Trace:    beaconTrace.WithEvent(trace.BeaconEvent),
+	})
+}
+
+// runBeacons schedules the beacons shortly before the start of every beacon period
+func (r *router) runBeacons() {
+	for {
+		next := gateway.NextBeacon(time.Now().Add(BeaconLead))
+		<-time.After(next.Add(-1 * BeaconLead).Sub(time.Now()))
+		r.scheduleBeacons(next)
+	}
+}
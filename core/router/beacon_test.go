@@ -0,0 +1,82 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestScheduleBeacons(t *testing.T) {
+	a := New(t)
+
+	r := getTestRouter(t)
+
+	// GPS-synchronized gateway in a region with beacons
+	gps := r.getGateway("gps")
+	gps.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+	gps.Schedule.Subscribe("test")
+	defer gps.Schedule.Stop("test")
+	now := time.Now()
+	uplink := newReferenceUplink()
+	uplink.GatewayMetadata.Timestamp = 0
+	uplink.GatewayMetadata.Time = now.Add(-100 * time.Microsecond).UnixNano()
+	uplink.GatewayMetadata.Gps = &pb_gateway.GPSMetadata{Time: now.Add(-100 * time.Microsecond).UnixNano(), Latitude: 52.37, Longitude: 4.89}
+	a.So(gps.HandleUplink(uplink), ShouldBeNil)
+	uplink = newReferenceUplink()
+	uplink.GatewayMetadata.Time = now.UnixNano()
+	uplink.GatewayMetadata.Gps = &pb_gateway.GPSMetadata{Time: now.UnixNano(), Latitude: 52.37, Longitude: 4.89}
+	a.So(gps.HandleUplink(uplink), ShouldBeNil)
+
+	// Gateway without GPS
+	noGPS := r.getGateway("no-gps")
+	noGPS.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+	noGPS.Schedule.Subscribe("test")
+	defer noGPS.Schedule.Stop("test")
+	a.So(noGPS.HandleUplink(newReferenceUplink()), ShouldBeNil)
+
+	// Gateway that is not connected for downlink
+	offline := r.getGateway("offline")
+	offline.Status.Update(&pb_gateway.Status{Region: "EU_863_870"})
+
+	r.scheduleBeacons(gateway.NextBeacon(time.Now().Add(BeaconLead)))
+
+	a.So(gps.BeaconStats().Sent, ShouldEqual, 1)
+	a.So(gps.BeaconStats().Missed, ShouldEqual, 0)
+	a.So(noGPS.BeaconStats().Sent, ShouldEqual, 0)
+	a.So(noGPS.BeaconStats().Missed, ShouldEqual, 1)
+	a.So(noGPS.BeaconStats().LastError, ShouldNotBeEmpty)
+	a.So(offline.BeaconStats().Missed, ShouldEqual, 0)
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/gateways/gps/beacons", nil)
+	r.HTTPHandler().ServeHTTP(rec, req)
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var response GatewayBeaconsResponse
+	a.So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+	a.So(response.Beacons.Capable, ShouldBeTrue)
+	a.So(response.Beacons.Sent, ShouldEqual, 1)
+}
+
+func TestBeaconSettings(t *testing.T) {
+	a := New(t)
+
+	r := getTestRouter(t)
+	gtw := r.getGateway("test")
+
+	_, ok := r.beaconSettings(gtw)
+	a.So(ok, ShouldBeFalse)
+
+	gtw.Status.Update(&pb_gateway.Status{Region: "US_902_928"})
+	settings, ok := r.beaconSettings(gtw)
+	a.So(ok, ShouldBeTrue)
+	a.So(settings.DataRate, ShouldEqual, "SF12BW500")
+}
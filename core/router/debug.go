@@ -0,0 +1,37 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"sort"
+	"time"
+)
+
+// connectedGateway is the debug information of a gateway that is connected to the Router
+type connectedGateway struct {
+	ID       string    `json:"id"`
+	LastSeen time.Time `json:"last_seen"`
+	Trusted  bool      `json:"trusted"`
+}
+
+type byGatewayID []connectedGateway
+
+func (g byGatewayID) Len() int           { return len(g) }
+func (g byGatewayID) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
+func (g byGatewayID) Less(i, j int) bool { return g[i].ID < g[j].ID }
+
+// connectedGateways returns the gateways that were seen by the Router, sorted by ID
+func (r *router) connectedGateways() interface{} {
+	r.gatewaysLock.RLock()
+	defer r.gatewaysLock.RUnlock()
+	gateways := make([]connectedGateway, 0, len(r.gateways))
+	for id, gtw := range r.gateways {
+		if gtw.LastSeen.IsZero() {
+			continue
+		}
+		gateways = append(gateways, connectedGateway{ID: id, LastSeen: gtw.LastSeen, Trusted: gtw.Trusted()})
+	}
+	sort.Sort(byGatewayID(gateways))
+	return gateways
+}
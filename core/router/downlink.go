@@ -62,6 +62,7 @@ func (r *router) HandleDownlink(downlink *pb_broker.DownlinkMessage) error {
 		Payload:               downlink.Payload,
 		ProtocolConfiguration: option.ProtocolConfig,
 		GatewayConfiguration:  option.GatewayConfig,
+		Priority:              downlink.Priority,
 		Trace:                 downlink.Trace,
 	}
 
@@ -70,24 +71,124 @@ func (r *router) HandleDownlink(downlink *pb_broker.DownlinkMessage) error {
 		identifier = strings.TrimPrefix(option.Identifier, fmt.Sprintf("%s:", r.Component.Identity.Id))
 	}
 
-	return r.getGateway(downlink.DownlinkOption.GatewayId).HandleDownlink(identifier, downlinkMessage)
+	// Record the receive windows that were skipped for the gateway of the option
+	if rx, ok := r.getRXContext(identifier); ok {
+		if skipped := rx.rxWindows.skipped(); skipped != "" {
+			downlink.Trace = downlink.Trace.WithEvent(trace.SkipRXWindowEvent,
+				"gateway", option.GatewayId,
+				"rtt", rx.rxWindows.rtt,
+				"windows", skipped,
+			)
+			downlinkMessage.Trace = downlink.Trace
+		}
+	}
+
+	identifier, err := r.applyRXSettings(option, identifier, len(downlink.Payload))
+	if err != nil {
+		if errors.GetErrType(err) == errors.NotFound {
+			r.reportDownlinkFailure(downlink, types.DownlinkFailureTooLate, err)
+		} else {
+			r.reportDownlinkFailure(downlink, types.DownlinkFailureInvalidSettings, err)
+		}
+		return err
+	}
+
+	if err := r.validateDownlink(option.GatewayId, option.GatewayConfig, option.ProtocolConfig, len(downlink.Payload)); err != nil {
+		r.reportDownlinkFailure(downlink, types.DownlinkFailureInvalidSettings, err)
+		return err
+	}
+
+	if err := r.checkDwellTime(option.GatewayId, option.GatewayConfig, option.ProtocolConfig, len(downlink.Payload)); err != nil {
+		r.reportDownlinkFailure(downlink, types.DownlinkFailurePayloadTooLarge, err)
+		return err
+	}
+
+	if err := r.checkDutyCycle(option.GatewayId, option.GatewayConfig); err != nil {
+		r.reportDownlinkFailure(downlink, types.DownlinkFailureDutyCycle, err)
+		return err
+	}
+
+	_, airtime := downlinkAirtime(option.ProtocolConfig, len(downlink.Payload))
+	budgeted := downlink.AppId != "" && hasApplicationPayload(downlink.Payload)
+	if budgeted {
+		if err := r.checkDownlinkAirtimeBudget(downlink.AppId, option.GatewayId, airtime); err != nil {
+			r.Ctx.WithFields(ttnlog.Fields{
+				"AppID":     downlink.AppId,
+				"GatewayID": option.GatewayId,
+			}).Debug("Downlink exceeds airtime budget of application")
+			r.reportDownlinkFailure(downlink, types.DownlinkFailureAirtimeBudget, err)
+			return err
+		}
+	}
+
+	gateway := r.getGateway(downlink.DownlinkOption.GatewayId)
+	gateway.Schedule.Notify(identifier, func(failure types.DownlinkFailure) {
+		r.forwardDownlinkFailure(downlink, failure)
+	})
+	if budgeted {
+		// The airtime is counted when the downlink is sent to the gateway, not
+		// when it is dropped or preempted after it was scheduled
+		appID := downlink.AppId
+		gateway.Schedule.NotifySent(identifier, func() {
+			gateway.AddDownlinkAirtime(appID, airtime)
+		})
+	}
+	err = gateway.HandleDownlink(identifier, downlinkMessage)
+	if errors.GetErrType(err) == errors.NotFound {
+		// The option on the transmission slot expired
+		r.reportDownlinkFailure(downlink, types.DownlinkFailureTooLate, err)
+	}
+	return err
+}
+
+// minDownlinkSize is the size of a LoRaWAN downlink without FOpts and FRMPayload
+const minDownlinkSize = 12
+
+// checkDwellTime checks the downlink against the dwell time limit of the region of the gateway
+func (r *router) checkDwellTime(gatewayID string, gatewayConfig *pb_gateway.TxConfiguration, protocolConfig *pb_protocol.TxConfiguration, payloadSize int) error {
+	lorawan := protocolConfig.GetLorawan()
+	if lorawan == nil || lorawan.Modulation != pb_lorawan.Modulation_LORA || gatewayConfig == nil {
+		return nil
+	}
+	status, _ := r.getGateway(gatewayID).Status.Get() // This just returns empty if non-existing
+	region := status.Region
+	if region == "" {
+		region = band.Guess(gatewayConfig.Frequency)
+	}
+	fp, err := band.Get(region)
+	if err != nil {
+		return nil // We can't check this region
+	}
+	return fp.CheckDwellTime(lorawan.DataRate, lorawan.CodingRate, payloadSize)
+}
+
+// validateDownlink checks the downlink against the frequency plan that the
+// gateway is assigned to. Gateways without a frequency plan are not validated.
+func (r *router) validateDownlink(gatewayID string, gatewayConfig *pb_gateway.TxConfiguration, protocolConfig *pb_protocol.TxConfiguration, payloadSize int) error {
+	if r.frequencyPlans == nil || gatewayConfig == nil {
+		return nil
+	}
+	plan, err := r.frequencyPlans.ForGateway(gatewayID)
+	if err != nil {
+		return nil
+	}
+	dataRate, timeOnAir := downlinkAirtime(protocolConfig, payloadSize)
+	return plan.ValidateDownlink(gatewayConfig, dataRate, timeOnAir)
 }
 
 // buildDownlinkOption builds a DownlinkOption with default values
 func (r *router) buildDownlinkOption(gatewayID string, band band.FrequencyPlan) *pb_broker.DownlinkOption {
-	dataRate, _ := types.ConvertDataRate(band.DataRates[band.RX2DataRate])
+	lorawan := &pb_lorawan.TxConfiguration{CodingRate: "4/5"}
+	lorawan.SetDataRate(band.DataRates[band.RX2DataRate])
 	return &pb_broker.DownlinkOption{
-		GatewayId: gatewayID,
-		ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{
-			Modulation: pb_lorawan.Modulation_LORA,
-			DataRate:   dataRate.String(),
-			CodingRate: "4/5",
-		}}},
+		GatewayId:      gatewayID,
+		ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: lorawan}},
 		GatewayConfig: &pb_gateway.TxConfiguration{
 			RfChain:               0,
 			PolarizationInversion: true,
 			Frequency:             uint64(band.RX2Frequency),
 			Power:                 int32(band.DefaultTXPower),
+			FrequencyDeviation:    uint32(lorawan.BitRate / 2),
 		},
 	}
 }
@@ -119,6 +220,14 @@ func (r *router) buildDownlinkOptions(uplink *pb.UplinkMessage, isActivation boo
 		return
 	}
 
+	rx1Delay, rx2Delay := band.ReceiveDelay1, band.ReceiveDelay2
+	if isActivation {
+		rx1Delay, rx2Delay = band.JoinAcceptDelay1, band.JoinAcceptDelay2
+	}
+
+	// Skip the receive windows that a downlink can not reach over the backhaul of the gateway in time
+	rxWindows := r.decideRXWindows(gateway, rx1Delay, rx2Delay, time.Now())
+
 	// Configuration for RX2
 	buildRX2 := func() (*pb_broker.DownlinkOption, error) {
 		option := r.buildDownlinkOption(gateway.ID, band)
@@ -130,12 +239,19 @@ func (r *router) buildDownlinkOptions(uplink *pb.UplinkMessage, isActivation boo
 		} else {
 			option.GatewayConfig.Timestamp = uplink.GatewayMetadata.Timestamp + uint32(band.ReceiveDelay2/1000)
 		}
-		option.ProtocolConfig.GetLorawan().CodingRate = lorawanMetadata.CodingRate
+		if lorawanMetadata.CodingRate != "" { // FSK uplinks have no coding rate
+			option.ProtocolConfig.GetLorawan().CodingRate = lorawanMetadata.CodingRate
+		}
 		return option, nil
 	}
 
-	if option, err := buildRX2(); err == nil {
-		options = append(options, option)
+	windows := make(map[*pb_broker.DownlinkOption]types.RXWindow)
+
+	if !rxWindows.skipRX2 {
+		if option, err := buildRX2(); err == nil {
+			options = append(options, option)
+			windows[option] = types.RX2Window
+		}
 	}
 
 	// Configuration for RX1
@@ -146,7 +262,9 @@ func (r *router) buildDownlinkOptions(uplink *pb.UplinkMessage, isActivation boo
 		} else {
 			option.GatewayConfig.Timestamp = uplink.GatewayMetadata.Timestamp + uint32(band.ReceiveDelay1/1000)
 		}
-		option.ProtocolConfig.GetLorawan().CodingRate = lorawanMetadata.CodingRate
+		if lorawanMetadata.CodingRate != "" {
+			option.ProtocolConfig.GetLorawan().CodingRate = lorawanMetadata.CodingRate
+		}
 
 		freq, err := band.GetRX1Frequency(int(uplink.GatewayMetadata.Frequency))
 		if err != nil {
@@ -171,27 +289,73 @@ func (r *router) buildDownlinkOptions(uplink *pb.UplinkMessage, isActivation boo
 		return option, nil
 	}
 
-	if option, err := buildRX1(); err == nil {
-		options = append(options, option)
+	if !rxWindows.skipRX1 {
+		if option, err := buildRX1(); err == nil {
+			options = append(options, option)
+			windows[option] = types.RX1Window
+		}
+	}
+
+	// Transmit on the antenna that received the uplink
+	if antenna, ok := gateway.Antenna(uplink.GatewayMetadata.Antenna); ok {
+		for _, option := range options {
+			applyAntenna(option, antenna)
+		}
 	}
 
 	computeDownlinkScores(gateway, uplink, options)
 
 	for _, option := range options {
+		identifier := option.Identifier
+
 		// Add router ID to downlink option
 		if r.Component != nil && r.Component.Identity != nil {
 			option.Identifier = fmt.Sprintf("%s:%s", r.Component.Identity.Id, option.Identifier)
 		}
 
+		// Filter all options that can not send an empty frame within the dwell time limit
+		if lorawan := option.ProtocolConfig.GetLorawan(); lorawan != nil && lorawan.Modulation == pb_lorawan.Modulation_LORA {
+			if err := band.CheckDwellTime(lorawan.DataRate, lorawan.CodingRate, minDownlinkSize); err != nil {
+				continue
+			}
+		}
+
+		// Filter all options that are not allowed by the frequency plan of the gateway
+		if err := r.validateDownlink(gateway.ID, option.GatewayConfig, option.ProtocolConfig, 0); err != nil {
+			continue
+		}
+
 		// Filter all illegal options
 		if option.Score < 1000 {
 			downlinkOptions = append(downlinkOptions, option)
+
+			// Keep the uplink of the option, so that it can be built again for the receive window parameters of the device
+			r.setRXContext(identifier, rxContext{
+				uplinkTimestamp: uplink.GatewayMetadata.Timestamp,
+				dataRate:        lorawanMetadata.DataRate,
+				antenna:         uplink.GatewayMetadata.Antenna,
+				isActivation:    isActivation,
+				region:          region,
+				window:          windows[option],
+				rxWindows:       rxWindows,
+			})
 		}
 	}
 
 	return
 }
 
+// applyAntenna sets the antenna of the option and converts the TX power of
+// the option, which is an EIRP, to the conducted power for the antenna gain
+func applyAntenna(option *pb_broker.DownlinkOption, antenna gateway.Antenna) {
+	option.GatewayConfig.Antenna = antenna.Index
+	power := option.GatewayConfig.Power - int32(math.Ceil(float64(antenna.Gain)))
+	if power < 0 {
+		power = 0
+	}
+	option.GatewayConfig.Power = power
+}
+
 // Calculating the score for each downlink option; lower is better, 0 is best
 // If a score is over 1000, it may should not be used as feasible option.
 // TODO: The weights of these parameters should be optimized. I'm sure someone
@@ -204,6 +368,11 @@ func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, o
 		region = band.Guess(uplink.GatewayMetadata.Frequency)
 	}
 
+	rssi, gain := uplink.GatewayMetadata.Rssi, float32(0)
+	if antenna, ok := gateway.Antenna(uplink.GatewayMetadata.Antenna); ok {
+		gain = antenna.Gain
+	}
+
 	gatewayRx, _ := gateway.Utilization.Get()
 	for _, option := range options {
 
@@ -247,8 +416,9 @@ func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, o
 			if uplink.GatewayMetadata.Snr < 5 {
 				signalScore += 10
 			}
-			// Prefer good RSSI
-			signalScore += math.Min(float64(uplink.GatewayMetadata.Rssi*-0.1), 10)
+			// Prefer good RSSI. All gateways transmit with the same EIRP, so the
+			// gain of the antenna that received the uplink does not count.
+			signalScore += math.Min(float64((rssi-gain)*-0.1), 10)
 		}
 
 		utilizationScore := 0.0 // Between 0 and 40 (lower is better) will be over 100 if forbidden
@@ -263,19 +433,8 @@ func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, o
 
 			// European Duty Cycle
 			if region == "EU_863_870" {
-				var duty float64
-				switch {
-				case freq >= 863000000 && freq < 868000000:
-					duty = 0.01 // g 863.0 – 868.0 MHz 1%
-				case freq >= 868000000 && freq < 868600000:
-					duty = 0.01 // g1 868.0 – 868.6 MHz 1%
-				case freq >= 868700000 && freq < 869200000:
-					duty = 0.001 // g2 868.7 – 869.2 MHz 0.1%
-				case freq >= 869400000 && freq < 869650000:
-					duty = 0.1 // g3 869.4 – 869.65 MHz 10%
-				case freq >= 869700000 && freq < 870000000:
-					duty = 0.01 // g4 869.7 – 870.0 MHz 1%
-				default:
+				duty := euDutyCycle(freq)
+				if duty == 0 {
 					utilizationScore += 100 // Transmissions on this frequency are forbidden
 				}
 				if channelTx > duty {
@@ -301,3 +460,68 @@ func computeDownlinkScores(gateway *gateway.Gateway, uplink *pb.UplinkMessage, o
 		option.Score = uint32((timeScore + signalScore + utilizationScore + scheduleScore) * 10)
 	}
 }
+
+// euDutyCycle returns the duty cycle of the European sub-band of the
+// frequency, or zero if transmissions on the frequency are forbidden
+func euDutyCycle(freq uint64) float64 {
+	switch {
+	case freq >= 863000000 && freq < 868000000:
+		return 0.01 // g 863.0 – 868.0 MHz 1%
+	case freq >= 868000000 && freq < 868600000:
+		return 0.01 // g1 868.0 – 868.6 MHz 1%
+	case freq >= 868700000 && freq < 869200000:
+		return 0.001 // g2 868.7 – 869.2 MHz 0.1%
+	case freq >= 869400000 && freq < 869650000:
+		return 0.1 // g3 869.4 – 869.65 MHz 10%
+	case freq >= 869700000 && freq < 870000000:
+		return 0.01 // g4 869.7 – 870.0 MHz 1%
+	default:
+		return 0
+	}
+}
+
+// checkDutyCycle checks that the gateway did not exceed the duty cycle of the
+// European sub-band of the downlink
+func (r *router) checkDutyCycle(gatewayID string, gatewayConfig *pb_gateway.TxConfiguration) error {
+	if gatewayConfig == nil || gatewayConfig.Frequency == 0 {
+		return nil
+	}
+	gateway := r.getGateway(gatewayID)
+	status, _ := gateway.Status.Get() // This just returns empty if non-existing
+	region := status.Region
+	if region == "" {
+		region = band.Guess(gatewayConfig.Frequency)
+	}
+	if region != "EU_863_870" || gateway.Utilization == nil {
+		return nil
+	}
+	duty := euDutyCycle(gatewayConfig.Frequency)
+	if _, channelTx := gateway.Utilization.GetChannel(gatewayConfig.Frequency); duty > 0 && channelTx > duty {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Gateway exceeded the duty cycle of %.1f%% on %d Hz", duty*100, gatewayConfig.Frequency))
+	}
+	return nil
+}
+
+// reportDownlinkFailure reports the downlink failure to the Handler of the application
+func (r *router) reportDownlinkFailure(downlink *pb_broker.DownlinkMessage, reason types.DownlinkFailureReason, err error) {
+	r.forwardDownlinkFailure(downlink, types.DownlinkFailure{
+		Reason: reason,
+		Error:  err.Error(),
+	})
+}
+
+// forwardDownlinkFailure reports a failure of the schedule of the gateway,
+// such as a preemption, to the Handler of the application
+func (r *router) forwardDownlinkFailure(downlink *pb_broker.DownlinkMessage, failure types.DownlinkFailure) {
+	if r.Component == nil {
+		return
+	}
+	failure.AppID = downlink.AppId
+	failure.DevID = downlink.DevId
+	failure.GatewayID = downlink.GetDownlinkOption().GetGatewayId()
+	go func() {
+		if err := r.ReportDownlinkFailure(failure); err != nil {
+			r.Ctx.WithError(err).WithField("AppID", failure.AppID).WithField("DevID", failure.DevID).Warn("Could not report downlink failure")
+		}
+	}()
+}
@@ -0,0 +1,103 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
+	"github.com/brocaar/lorawan"
+)
+
+// DownlinkAirtimeBudgets limit the downlink airtime that each application can
+// use on a gateway per (UTC) day, so that one application can not exhaust
+// the duty cycle of a gateway. Zero budgets mean that there is no limit.
+type DownlinkAirtimeBudgets struct {
+	Default      time.Duration            // Budget of applications without their own budget
+	Applications map[string]time.Duration // Budgets per application ID
+}
+
+// ParseDownlinkAirtimeBudget parses a budget in the format "<app-id>=<duration>"
+func ParseDownlinkAirtimeBudget(budgetString string) (appID string, budget time.Duration, err error) {
+	parts := strings.SplitN(budgetString, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", 0, fmt.Errorf("Invalid downlink airtime budget %s: expected <app-id>=<duration>", budgetString)
+	}
+	budget, err = time.ParseDuration(parts[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("Invalid downlink airtime budget %s: %s", budgetString, err)
+	}
+	if budget < 0 {
+		return "", 0, fmt.Errorf("Invalid downlink airtime budget %s: can not be negative", budgetString)
+	}
+	return parts[0], budget, nil
+}
+
+// Get returns the budget of the application
+func (b DownlinkAirtimeBudgets) Get(appID string) time.Duration {
+	if budget, ok := b.Applications[appID]; ok {
+		return budget
+	}
+	return b.Default
+}
+
+// SetDownlinkAirtimeBudgets sets the downlink airtime budgets of applications.
+// Downlinks with application payload that would exceed the budget of their
+// application on a gateway are not sent, and the failure is reported to the
+// Handler. Acknowledgements and MAC commands of the network are not limited.
+func (r *router) SetDownlinkAirtimeBudgets(budgets DownlinkAirtimeBudgets) {
+	r.downlinkBudgets = &budgets
+}
+
+// downlinkAirtime returns the time-on-air of a downlink
+func downlinkAirtime(protocolConfig *pb_protocol.TxConfiguration, payloadSize int) (dataRate string, timeOnAir time.Duration) {
+	if lorawan := protocolConfig.GetLorawan(); lorawan != nil {
+		switch lorawan.Modulation {
+		case pb_lorawan.Modulation_LORA:
+			dataRate = lorawan.DataRate
+			timeOnAir, _ = toa.ComputeLoRa(uint(payloadSize), lorawan.DataRate, lorawan.CodingRate)
+		case pb_lorawan.Modulation_FSK:
+			dataRate = strconv.Itoa(int(lorawan.BitRate))
+			timeOnAir, _ = toa.ComputeFSK(uint(payloadSize), int(lorawan.BitRate))
+		}
+	}
+	return
+}
+
+// checkDownlinkAirtimeBudget checks that the downlink does not exceed the
+// budget of the application on the gateway
+func (r *router) checkDownlinkAirtimeBudget(appID, gatewayID string, airtime time.Duration) error {
+	if r.downlinkBudgets == nil || appID == "" {
+		return nil
+	}
+	budget := r.downlinkBudgets.Get(appID)
+	if budget == 0 {
+		return nil
+	}
+	if used := r.getGateway(gatewayID).DownlinkAirtime(appID).Today; used+airtime > budget {
+		return errors.NewErrPermissionDenied(fmt.Sprintf("Application %s used %s of its downlink airtime budget of %s on gateway %s today", appID, used, budget, gatewayID))
+	}
+	return nil
+}
+
+// hasApplicationPayload returns true if the downlink contains application
+// payload. Payloads that are not LoRaWAN data downlinks are treated as
+// application payload.
+func hasApplicationPayload(payload []byte) bool {
+	var phyPayload lorawan.PHYPayload
+	if err := phyPayload.UnmarshalBinary(payload); err != nil {
+		return true
+	}
+	macPayload, ok := phyPayload.MACPayload.(*lorawan.MACPayload)
+	if !ok {
+		return true
+	}
+	return macPayload.FPort != nil && *macPayload.FPort != 0 && len(macPayload.FRMPayload) > 0
+}
@@ -0,0 +1,126 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/api/monitor"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParseDownlinkAirtimeBudget(t *testing.T) {
+	a := New(t)
+
+	appID, budget, err := ParseDownlinkAirtimeBudget("test=30s")
+	a.So(err, ShouldBeNil)
+	a.So(appID, ShouldEqual, "test")
+	a.So(budget, ShouldEqual, 30*time.Second)
+
+	for _, invalid := range []string{"", "test", "=30s", "test=30", "test=-1s"} {
+		_, _, err = ParseDownlinkAirtimeBudget(invalid)
+		a.So(err, ShouldNotBeNil)
+	}
+
+	budgets := DownlinkAirtimeBudgets{Default: time.Minute, Applications: map[string]time.Duration{"test": 0}}
+	a.So(budgets.Get("test"), ShouldEqual, 0)
+	a.So(budgets.Get("other"), ShouldEqual, time.Minute)
+}
+
+type gatewaySchedule interface {
+	gateway.Schedule
+}
+
+// sentSchedule is a schedule of which the downlinks are sent right away
+type sentSchedule struct {
+	gatewaySchedule
+}
+
+func (s sentSchedule) NotifySent(id string, sent func()) {
+	sent()
+}
+
+func TestHasApplicationPayload(t *testing.T) {
+	a := New(t)
+	a.So(hasApplicationPayload([]byte{0x60, 4, 3, 2, 1, 0, 1, 0, 1, 0xaa, 0, 0, 0, 0}), ShouldBeTrue)
+	a.So(hasApplicationPayload([]byte{0x60, 4, 3, 2, 1, 0x20, 1, 0, 0, 0, 0, 0}), ShouldBeFalse)
+	a.So(hasApplicationPayload([]byte{0x60, 4, 3, 2, 1, 0, 1, 0, 0, 0x06, 0, 0, 0, 0}), ShouldBeFalse)
+	a.So(hasApplicationPayload(make([]byte, 10)), ShouldBeTrue)
+}
+
+func TestHandleDownlinkAirtimeBudget(t *testing.T) {
+	a := New(t)
+
+	logger := GetLogger(t, "TestHandleDownlinkAirtimeBudget")
+	r := &router{
+		Component: &component.Component{
+			Ctx:      logger,
+			Monitors: monitor.NewRegistry(logger),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	// A downlink of 14 bytes at SF12BW125 takes 1155072 µs
+	r.SetDownlinkAirtimeBudgets(DownlinkAirtimeBudgets{
+		Default:      1500 * time.Millisecond,
+		Applications: map[string]time.Duration{"unlimited": 0},
+	})
+
+	gtwID := "eui-0102030405060708"
+	r.getGateway(gtwID).Schedule = sentSchedule{r.getGateway(gtwID).Schedule}
+	appPayload := []byte{0x60, 4, 3, 2, 1, 0, 1, 0, 1, 0xaa, 0, 0, 0, 0}
+	downlinkWithPayload := func(appID string, payload []byte) *pb_broker.DownlinkMessage {
+		id, _ := r.getGateway(gtwID).Schedule.GetOption(0, 10*1000)
+		return &pb_broker.DownlinkMessage{
+			AppId:   appID,
+			DevId:   "test",
+			Payload: payload,
+			DownlinkOption: &pb_broker.DownlinkOption{
+				GatewayId:  gtwID,
+				Identifier: id,
+				ProtocolConfig: &pb_protocol.TxConfiguration{Protocol: &pb_protocol.TxConfiguration_Lorawan{Lorawan: &pb_lorawan.TxConfiguration{
+					Modulation: pb_lorawan.Modulation_LORA,
+					DataRate:   "SF12BW125",
+					CodingRate: "4/5",
+				}}},
+				GatewayConfig: &pb_gateway.TxConfiguration{},
+			},
+		}
+	}
+
+	downlink := func(appID string) *pb_broker.DownlinkMessage {
+		return downlinkWithPayload(appID, appPayload)
+	}
+
+	a.So(r.HandleDownlink(downlink("test")), ShouldBeNil)
+	a.So(r.HandleDownlink(downlink("test")), ShouldNotBeNil)
+	a.So(r.getGateway(gtwID).DownlinkAirtime("test").Messages, ShouldEqual, 1)
+
+	// Acknowledgements and MAC commands are not limited
+	a.So(r.HandleDownlink(downlinkWithPayload("test", []byte{0x60, 4, 3, 2, 1, 0x20, 1, 0, 0, 0, 0, 0})), ShouldBeNil)
+	a.So(r.getGateway(gtwID).DownlinkAirtime("test").Messages, ShouldEqual, 1)
+
+	// Other applications have their own budget
+	a.So(r.HandleDownlink(downlink("other")), ShouldBeNil)
+	for i := 0; i < 3; i++ {
+		a.So(r.HandleDownlink(downlink("unlimited")), ShouldBeNil)
+	}
+
+	airtime := r.applicationDownlinkAirtime(r.getGateway(gtwID))
+	a.So(airtime, ShouldHaveLength, 3)
+	a.So(airtime[0].AppID, ShouldEqual, "other")
+	a.So(airtime[0].Budget, ShouldEqual, 1500*time.Millisecond)
+	a.So(airtime[2].AppID, ShouldEqual, "unlimited")
+	a.So(airtime[2].Airtime.Messages, ShouldEqual, 3)
+	a.So(airtime[2].Budget, ShouldEqual, 0)
+}
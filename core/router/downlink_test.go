@@ -15,6 +15,7 @@ import (
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/router/frequencyplan"
 	"github.com/TheThingsNetwork/ttn/core/router/gateway"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
 	. "github.com/smartystreets/assertions"
@@ -65,6 +66,90 @@ func TestHandleDownlink(t *testing.T) {
 	a.So(err, ShouldBeNil)
 }
 
+func TestHandleDownlinkFrequencyPlan(t *testing.T) {
+	a := New(t)
+
+	logger := GetLogger(t, "TestHandleDownlinkFrequencyPlan")
+	r := &router{
+		Component: &component.Component{
+			Ctx:      logger,
+			Monitors: monitor.NewRegistry(logger),
+		},
+		gateways:       map[string]*gateway.Gateway{},
+		frequencyPlans: frequencyplan.NewRegistry(),
+	}
+	r.InitStatus()
+
+	gtwID := "eui-0102030405060708"
+	r.frequencyPlans.Set(&frequencyplan.FrequencyPlan{
+		ID:             "test",
+		UplinkChannels: []frequencyplan.Channel{{Frequency: 868100000}},
+		MaxEIRP:        14,
+	})
+	r.frequencyPlans.Assign(gtwID, "test")
+
+	downlink := func(frequency uint64) *pb_broker.DownlinkMessage {
+		id, _ := r.getGateway(gtwID).Schedule.GetOption(0, 10*1000)
+		return &pb_broker.DownlinkMessage{
+			Payload: []byte{},
+			DownlinkOption: &pb_broker.DownlinkOption{
+				GatewayId:      gtwID,
+				Identifier:     id,
+				ProtocolConfig: &pb_protocol.TxConfiguration{},
+				GatewayConfig:  &pb_gateway.TxConfiguration{Frequency: frequency, Power: 14},
+			},
+		}
+	}
+
+	a.So(r.HandleDownlink(downlink(868100000)), ShouldBeNil)
+	a.So(r.HandleDownlink(downlink(868300000)), ShouldNotBeNil)
+}
+
+func TestHandleDownlinkDutyCycle(t *testing.T) {
+	a := New(t)
+
+	logger := GetLogger(t, "TestHandleDownlinkDutyCycle")
+	r := &router{
+		Component: &component.Component{
+			Ctx:      logger,
+			Monitors: monitor.NewRegistry(logger),
+		},
+		gateways: map[string]*gateway.Gateway{},
+	}
+	r.InitStatus()
+
+	gtwID := "eui-0102030405060708"
+	downlink := func() *pb_broker.DownlinkMessage {
+		id, _ := r.getGateway(gtwID).Schedule.GetOption(0, 10*1000)
+		return &pb_broker.DownlinkMessage{
+			Payload: []byte{},
+			DownlinkOption: &pb_broker.DownlinkOption{
+				GatewayId:      gtwID,
+				Identifier:     id,
+				ProtocolConfig: &pb_protocol.TxConfiguration{},
+				GatewayConfig:  &pb_gateway.TxConfiguration{Frequency: 868100000},
+			},
+		}
+	}
+
+	a.So(r.HandleDownlink(downlink()), ShouldBeNil)
+
+	gtw := r.getGateway(gtwID)
+	for i := 0; i < 5; i++ {
+		gtw.Utilization.AddTx(newReferenceDownlink())
+	}
+	gtw.Utilization.Tick()
+	a.So(r.HandleDownlink(downlink()), ShouldNotBeNil)
+}
+
+func TestEUDutyCycle(t *testing.T) {
+	a := New(t)
+	a.So(euDutyCycle(868100000), ShouldEqual, 0.01)
+	a.So(euDutyCycle(868800000), ShouldEqual, 0.001)
+	a.So(euDutyCycle(869525000), ShouldEqual, 0.1)
+	a.So(euDutyCycle(869300000), ShouldEqual, 0)
+}
+
 func TestSubscribeUnsubscribeDownlink(t *testing.T) {
 	a := New(t)
 
@@ -163,6 +248,37 @@ func TestUplinkBuildDownlinkOptions(t *testing.T) {
 	a.So(options[0].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF12BW125")
 }
 
+func TestUplinkBuildDownlinkOptionsAntenna(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+
+	gtw, up := newReferenceGateway(t, "EU_863_870"), newReferenceUplink()
+	a.So(gtw.Antennas.Set(gtw.ID, []gateway.Antenna{
+		{Index: 0, Gain: 2},
+		{Index: 1, Gain: 6.5},
+	}), ShouldBeNil)
+	up.GatewayMetadata.Antenna = 1
+
+	options := r.buildDownlinkOptions(up, false, gtw)
+	a.So(options, ShouldHaveLength, 2)
+
+	// Transmit on the antenna that received the uplink
+	a.So(options[1].GatewayConfig.Antenna, ShouldEqual, 1)
+	a.So(options[0].GatewayConfig.Antenna, ShouldEqual, 1)
+
+	// The antenna gain is subtracted from the power
+	a.So(options[1].GatewayConfig.Power, ShouldEqual, 7)
+	a.So(options[0].GatewayConfig.Power, ShouldEqual, 20)
+
+	// Unregistered antennas use the defaults
+	gtw, up = newReferenceGateway(t, "EU_863_870"), newReferenceUplink()
+	up.GatewayMetadata.Antenna = 1
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options[1].GatewayConfig.Antenna, ShouldEqual, 0)
+	a.So(options[1].GatewayConfig.Power, ShouldEqual, 14)
+}
+
 func TestUplinkBuildDownlinkOptionsFrequencies(t *testing.T) {
 	a := New(t)
 
@@ -268,6 +384,25 @@ func TestUplinkBuildDownlinkOptionsDataRate(t *testing.T) {
 		a.So(options[1].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, dr)
 	}
 
+	// FSK uplinks use FSK in RX1 and LoRa in RX2
+	{
+		up := newReferenceUplink()
+		up.GatewayMetadata.Frequency = 868800000
+		up.ProtocolMetadata.GetLorawan().Modulation = pb_lorawan.Modulation_FSK
+		up.ProtocolMetadata.GetLorawan().DataRate = ""
+		up.ProtocolMetadata.GetLorawan().CodingRate = ""
+		up.ProtocolMetadata.GetLorawan().BitRate = 50000
+		options := r.buildDownlinkOptions(up, false, gtw)
+		a.So(options, ShouldHaveLength, 2)
+		a.So(options[0].ProtocolConfig.GetLorawan().Modulation, ShouldEqual, pb_lorawan.Modulation_LORA)
+		a.So(options[0].ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF9BW125")
+		a.So(options[0].ProtocolConfig.GetLorawan().CodingRate, ShouldEqual, "4/5")
+		a.So(options[1].ProtocolConfig.GetLorawan().Modulation, ShouldEqual, pb_lorawan.Modulation_FSK)
+		a.So(options[1].ProtocolConfig.GetLorawan().BitRate, ShouldEqual, 50000)
+		a.So(options[1].GatewayConfig.Frequency, ShouldEqual, 868800000)
+		a.So(options[1].GatewayConfig.FrequencyDeviation, ShouldEqual, 25000)
+	}
+
 	gtw = newReferenceGateway(t, "US_902_928")
 
 	// Test 500kHz channel
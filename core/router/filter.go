@@ -0,0 +1,223 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/rcrowley/go-metrics"
+)
+
+// ForeignDevAddrPrefixLength is the length of the prefixes that filtered DevAddrs
+// are counted by. By default this is the length of the NwkID.
+var ForeignDevAddrPrefixLength = 7
+
+// ForeignJoinEUIPrefixLength is the length of the prefixes (in bits) that filtered
+// JoinEUIs are counted by. By default this is the length of an OUI.
+var ForeignJoinEUIPrefixLength = 24
+
+// JoinEUIRange is an inclusive range of JoinEUIs (AppEUIs)
+type JoinEUIRange struct {
+	Start types.AppEUI
+	End   types.AppEUI
+}
+
+// ParseJoinEUIRange parses a range of JoinEUIs in range notation
+// (70B3D57ED0000000-70B3D57ED000FFFF), prefix notation (70B3D57ED0000000/36),
+// as an OUI (70B3D5) or a single JoinEUI
+func ParseJoinEUIRange(rangeString string) (r JoinEUIRange, err error) {
+	switch {
+	case len(rangeString) == 6:
+		return ParseJoinEUIRange(rangeString + "0000000000/24")
+	case strings.Contains(rangeString, "-"):
+		parts := strings.SplitN(rangeString, "-", 2)
+		if r.Start, err = types.ParseAppEUI(parts[0]); err != nil {
+			return
+		}
+		if r.End, err = types.ParseAppEUI(parts[1]); err != nil {
+			return
+		}
+		if bytes.Compare(r.Start[:], r.End[:]) > 0 {
+			err = fmt.Errorf("Invalid JoinEUI range %s: start is after end", rangeString)
+		}
+	case strings.Contains(rangeString, "/"):
+		parts := strings.SplitN(rangeString, "/", 2)
+		var eui types.AppEUI
+		if eui, err = types.ParseAppEUI(parts[0]); err != nil {
+			return
+		}
+		var length int
+		if _, err = fmt.Sscanf(parts[1], "%d", &length); err != nil || length < 0 || length > 64 {
+			err = fmt.Errorf("Invalid JoinEUI prefix length in %s", rangeString)
+			return
+		}
+		r.Start, r.End = maskAppEUI(eui, length, 0x00), maskAppEUI(eui, length, 0xff)
+	default:
+		if r.Start, err = types.ParseAppEUI(rangeString); err != nil {
+			return
+		}
+		r.End = r.Start
+	}
+	return
+}
+
+// maskAppEUI keeps the first bits of the EUI and fills the rest with the fill byte
+func maskAppEUI(eui types.AppEUI, bits int, fill byte) (masked types.AppEUI) {
+	for i := range eui {
+		switch {
+		case bits >= 8:
+			masked[i] = eui[i]
+			bits -= 8
+		case bits > 0:
+			mask := byte(0xff) << uint(8-bits)
+			masked[i] = eui[i]&mask | fill&^mask
+			bits = 0
+		default:
+			masked[i] = fill
+		}
+	}
+	return
+}
+
+// Contains returns true if the JoinEUI is in the range
+func (r JoinEUIRange) Contains(eui types.AppEUI) bool {
+	return bytes.Compare(eui[:], r.Start[:]) >= 0 && bytes.Compare(eui[:], r.End[:]) <= 0
+}
+
+func (r JoinEUIRange) String() string {
+	if r.Start == r.End {
+		return fmt.Sprintf("%X", r.Start.Bytes())
+	}
+	return fmt.Sprintf("%X-%X", r.Start.Bytes(), r.End.Bytes())
+}
+
+// FilterStats are the statistics of the traffic of a DevAddr prefix or JoinEUI range
+type FilterStats struct {
+	Prefix   string  `json:"prefix"`
+	Type     string  `json:"type"` // "dev_addr" or "join_eui"
+	Served   bool    `json:"served"`
+	Messages int64   `json:"messages"`
+	Rate1    float64 `json:"rate_1"`
+	Rate5    float64 `json:"rate_5"`
+	Rate15   float64 `json:"rate_15"`
+}
+
+type byFilterPrefix []FilterStats
+
+func (s byFilterPrefix) Len() int      { return len(s) }
+func (s byFilterPrefix) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byFilterPrefix) Less(i, j int) bool {
+	if s[i].Type != s[j].Type {
+		return s[i].Type < s[j].Type
+	}
+	if s[i].Served != s[j].Served {
+		return s[i].Served
+	}
+	return s[i].Prefix < s[j].Prefix
+}
+
+const (
+	filterDevAddr = "dev_addr"
+	filterJoinEUI = "join_eui"
+)
+
+type filterKey struct {
+	prefix string
+	typ    string
+	served bool
+}
+
+// trafficFilter drops traffic of DevAddrs and JoinEUIs that are not served by this
+// network, so that it does not load the brokers
+type trafficFilter struct {
+	devAddrPrefixes []types.DevAddrPrefix
+	joinEUIRanges   []JoinEUIRange
+
+	sync.Mutex
+	meters map[filterKey]metrics.Meter
+}
+
+func (f *trafficFilter) mark(prefix, typ string, served bool) {
+	f.Lock()
+	defer f.Unlock()
+	key := filterKey{prefix, typ, served}
+	meter, ok := f.meters[key]
+	if !ok {
+		meter = metrics.NewMeter()
+		f.meters[key] = meter
+	}
+	meter.Mark(1)
+}
+
+// allowDevAddr returns true if the DevAddr is served, or if there is no filter for DevAddrs
+func (f *trafficFilter) allowDevAddr(devAddr types.DevAddr) bool {
+	if f == nil || len(f.devAddrPrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range f.devAddrPrefixes {
+		if devAddr.HasPrefix(prefix) {
+			f.mark(prefix.String(), filterDevAddr, true)
+			return true
+		}
+	}
+	foreign := types.DevAddrPrefix{DevAddr: devAddr.Mask(ForeignDevAddrPrefixLength), Length: ForeignDevAddrPrefixLength}
+	f.mark(foreign.String(), filterDevAddr, false)
+	return false
+}
+
+// allowJoinEUI returns true if the JoinEUI is served, or if there is no filter for JoinEUIs
+func (f *trafficFilter) allowJoinEUI(joinEUI types.AppEUI) bool {
+	if f == nil || len(f.joinEUIRanges) == 0 {
+		return true
+	}
+	for _, r := range f.joinEUIRanges {
+		if r.Contains(joinEUI) {
+			f.mark(r.String(), filterJoinEUI, true)
+			return true
+		}
+	}
+	foreign := fmt.Sprintf("%X/%d", maskAppEUI(joinEUI, ForeignJoinEUIPrefixLength, 0x00).Bytes(), ForeignJoinEUIPrefixLength)
+	f.mark(foreign, filterJoinEUI, false)
+	return false
+}
+
+func (f *trafficFilter) stats() []FilterStats {
+	if f == nil {
+		return nil
+	}
+	f.Lock()
+	defer f.Unlock()
+	stats := make([]FilterStats, 0, len(f.meters))
+	for key, meter := range f.meters {
+		snapshot := meter.Snapshot()
+		stats = append(stats, FilterStats{
+			Prefix:   key.prefix,
+			Type:     key.typ,
+			Served:   key.served,
+			Messages: snapshot.Count(),
+			Rate1:    snapshot.Rate1(),
+			Rate5:    snapshot.Rate5(),
+			Rate15:   snapshot.Rate15(),
+		})
+	}
+	sort.Sort(byFilterPrefix(stats))
+	return stats
+}
+
+func (r *router) SetTrafficFilter(devAddrPrefixes []types.DevAddrPrefix, joinEUIRanges []JoinEUIRange) {
+	if len(devAddrPrefixes) == 0 && len(joinEUIRanges) == 0 {
+		r.filter = nil
+		return
+	}
+	r.filter = &trafficFilter{
+		devAddrPrefixes: devAddrPrefixes,
+		joinEUIRanges:   joinEUIRanges,
+		meters:          make(map[filterKey]metrics.Meter),
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParseJoinEUIRange(t *testing.T) {
+	a := New(t)
+
+	r, err := ParseJoinEUIRange("70B3D57ED0000000-70B3D57ED000FFFF")
+	a.So(err, ShouldBeNil)
+	a.So(r.Start, ShouldEqual, types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x00})
+	a.So(r.End, ShouldEqual, types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0xFF, 0xFF})
+	a.So(r.String(), ShouldEqual, "70B3D57ED0000000-70B3D57ED000FFFF")
+
+	r, err = ParseJoinEUIRange("70B3D57ED0000000/36")
+	a.So(err, ShouldBeNil)
+	a.So(r.Start, ShouldEqual, types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x00})
+	a.So(r.End, ShouldEqual, types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xDF, 0xFF, 0xFF, 0xFF})
+
+	r, err = ParseJoinEUIRange("70B3D5")
+	a.So(err, ShouldBeNil)
+	a.So(r.String(), ShouldEqual, "70B3D50000000000-70B3D5FFFFFFFFFF")
+
+	r, err = ParseJoinEUIRange("70B3D57ED0000001")
+	a.So(err, ShouldBeNil)
+	a.So(r.Start, ShouldEqual, r.End)
+	a.So(r.String(), ShouldEqual, "70B3D57ED0000001")
+
+	_, err = ParseJoinEUIRange("70B3D57ED000FFFF-70B3D57ED0000000")
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseJoinEUIRange("70B3D57ED0000000/65")
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseJoinEUIRange("invalid")
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestJoinEUIRangeContains(t *testing.T) {
+	a := New(t)
+	r, _ := ParseJoinEUIRange("70B3D57ED0000000-70B3D57ED000FFFF")
+	a.So(r.Contains(types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x00}), ShouldBeTrue)
+	a.So(r.Contains(types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x12, 0x34}), ShouldBeTrue)
+	a.So(r.Contains(types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x01, 0x00, 0x00}), ShouldBeFalse)
+	a.So(r.Contains(types.AppEUI{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}), ShouldBeFalse)
+}
+
+func TestTrafficFilter(t *testing.T) {
+	a := New(t)
+
+	var f *trafficFilter
+	a.So(f.allowDevAddr(types.DevAddr{1, 2, 3, 4}), ShouldBeTrue)
+	a.So(f.allowJoinEUI(types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}), ShouldBeTrue)
+	a.So(f.stats(), ShouldBeEmpty)
+
+	r := getTestRouter(t)
+	prefix, _ := types.ParseDevAddrPrefix("26000000/7")
+	joinEUIs, _ := ParseJoinEUIRange("70B3D57ED0000000/40")
+
+	r.SetTrafficFilter([]types.DevAddrPrefix{prefix}, nil)
+	f = r.filter
+	a.So(f.allowDevAddr(types.DevAddr{0x26, 0x01, 0x02, 0x03}), ShouldBeTrue)
+	a.So(f.allowDevAddr(types.DevAddr{0x27, 0x01, 0x02, 0x03}), ShouldBeTrue)
+	a.So(f.allowDevAddr(types.DevAddr{0x01, 0x02, 0x03, 0x04}), ShouldBeFalse)
+	a.So(f.allowDevAddr(types.DevAddr{0x01, 0x02, 0x03, 0x05}), ShouldBeFalse)
+	a.So(f.allowJoinEUI(types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}), ShouldBeTrue) // No JoinEUI ranges
+
+	stats := f.stats()
+	a.So(stats, ShouldHaveLength, 2)
+	a.So(stats[0].Prefix, ShouldEqual, "26000000/7")
+	a.So(stats[0].Served, ShouldBeTrue)
+	a.So(stats[0].Messages, ShouldEqual, 2)
+	a.So(stats[1].Prefix, ShouldEqual, "00000000/7")
+	a.So(stats[1].Served, ShouldBeFalse)
+	a.So(stats[1].Messages, ShouldEqual, 2)
+
+	r.SetTrafficFilter(nil, []JoinEUIRange{joinEUIs})
+	f = r.filter
+	a.So(f.allowDevAddr(types.DevAddr{0x01, 0x02, 0x03, 0x04}), ShouldBeTrue) // No DevAddr prefixes
+	a.So(f.allowJoinEUI(types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x12, 0x34, 0x56}), ShouldBeTrue)
+	a.So(f.allowJoinEUI(types.AppEUI{0x00, 0x04, 0xA3, 0x0B, 0x00, 0x12, 0x34, 0x56}), ShouldBeFalse)
+
+	stats = f.stats()
+	a.So(stats, ShouldHaveLength, 2)
+	a.So(stats[0].Type, ShouldEqual, "join_eui")
+	a.So(stats[0].Prefix, ShouldEqual, "70B3D57ED0000000-70B3D57ED0FFFFFF")
+	a.So(stats[1].Prefix, ShouldEqual, "0004A30000000000/24")
+
+	r.SetTrafficFilter(nil, nil)
+	a.So(r.filter, ShouldBeNil)
+}
+
+func TestHandleUplinkFiltered(t *testing.T) {
+	a := New(t)
+
+	r := getTestRouter(t)
+	prefix, _ := types.ParseDevAddrPrefix("26000000/7")
+	r.SetTrafficFilter([]types.DevAddrPrefix{prefix}, nil)
+
+	// The uplink is dropped before looking up brokers
+	err := r.HandleUplink("eui-0102030405060708", newReferenceUplink())
+	a.So(err, ShouldBeNil)
+
+	stats := r.filter.stats()
+	a.So(stats, ShouldHaveLength, 1)
+	a.So(stats[0].Served, ShouldBeFalse)
+
+	// The gateway still sees the traffic
+	utilization := r.getGateway("eui-0102030405060708").Utilization
+	utilization.Tick()
+	rx, _ := utilization.Get()
+	a.So(rx, ShouldBeGreaterThan, 0)
+}
+
+func TestHTTPFilter(t *testing.T) {
+	a := New(t)
+
+	r := getTestRouter(t)
+	h := r.HTTPHandler()
+
+	get := func() (response TrafficFilterResponse) {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/filter", nil)
+		h.ServeHTTP(rec, req)
+		a.So(rec.Code, ShouldEqual, http.StatusOK)
+		a.So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+		return
+	}
+
+	response := get()
+	a.So(response.DevAddrPrefixes, ShouldBeEmpty)
+	a.So(response.Stats, ShouldBeEmpty)
+
+	prefix, _ := types.ParseDevAddrPrefix("26000000/7")
+	joinEUIs, _ := ParseJoinEUIRange("70B3D57ED0000000-70B3D57ED000FFFF")
+	r.SetTrafficFilter([]types.DevAddrPrefix{prefix}, []JoinEUIRange{joinEUIs})
+	r.filter.allowDevAddr(types.DevAddr{0x01, 0x02, 0x03, 0x04})
+
+	response = get()
+	a.So(response.DevAddrPrefixes, ShouldResemble, []string{"26000000/7"})
+	a.So(response.JoinEUIRanges, ShouldResemble, []string{"70B3D57ED0000000-70B3D57ED000FFFF"})
+	a.So(response.Stats, ShouldHaveLength, 1)
+	a.So(response.Stats[0].Prefix, ShouldEqual, "00000000/7")
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/filter", nil)
+	h.ServeHTTP(rec, req)
+	a.So(rec.Code, ShouldEqual, http.StatusMethodNotAllowed)
+}
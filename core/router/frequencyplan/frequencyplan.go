@@ -0,0 +1,197 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package frequencyplan contains the frequency plans that gateways can be assigned to
+package frequencyplan
+
+import (
+	"fmt"
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	lora "github.com/brocaar/lorawan/band"
+)
+
+// MaxDwellTime is the maximum time on air if the dwell time limit applies
+const MaxDwellTime = band.MaxDwellTime
+
+// Channel in a frequency plan. FSK data rates are given as bit rate, such as "50000".
+type Channel struct {
+	Frequency uint64   `json:"frequency" yaml:"frequency"`
+	DataRates []string `json:"data_rates,omitempty" yaml:"data-rates"`
+}
+
+// RX2 contains the settings for the second receive window
+type RX2 struct {
+	Frequency uint64 `json:"frequency" yaml:"frequency"`
+	DataRate  string `json:"data_rate" yaml:"data-rate"`
+}
+
+// Beacon contains the settings for Class B beacons
+type Beacon struct {
+	// Frequencies of the beacon. With multiple frequencies, the beacon hops
+	// to the next frequency every beacon period.
+	Frequencies []uint64 `json:"frequencies" yaml:"frequencies"`
+	DataRate    string   `json:"data_rate" yaml:"data-rate"`
+	Power       int32    `json:"power" yaml:"power"`
+}
+
+// Frequency returns the frequency of the beacon in the given beacon period
+func (b *Beacon) Frequency(period int64) uint64 {
+	return b.Frequencies[period%int64(len(b.Frequencies))]
+}
+
+// beaconChannels returns the frequencies of a beacon that hops over count channels
+func beaconChannels(first, spacing uint64, count int) []uint64 {
+	frequencies := make([]uint64, count)
+	for i := range frequencies {
+		frequencies[i] = first + uint64(i)*spacing
+	}
+	return frequencies
+}
+
+// defaultBeacons contains the default beacon settings of the regions that define them
+var defaultBeacons = map[string]Beacon{
+	"EU_863_870": {Frequencies: []uint64{869525000}, DataRate: "SF9BW125", Power: 27},
+	"US_902_928": {Frequencies: beaconChannels(923300000, 600000, 8), DataRate: "SF12BW500", Power: 20},
+	"CN_779_787": {Frequencies: []uint64{785000000}, DataRate: "SF9BW125", Power: 10},
+	"EU_433":     {Frequencies: []uint64{434665000}, DataRate: "SF9BW125", Power: 10},
+	"AU_915_928": {Frequencies: beaconChannels(923300000, 600000, 8), DataRate: "SF12BW500", Power: 20},
+	"CN_470_510": {Frequencies: beaconChannels(508300000, 200000, 8), DataRate: "SF12BW125", Power: 14},
+	"AS_923":     {Frequencies: []uint64{923400000}, DataRate: "SF9BW125", Power: 14},
+	"KR_920_923": {Frequencies: []uint64{923100000}, DataRate: "SF9BW125", Power: 14},
+}
+
+// DefaultBeacon returns the default beacon settings of a region
+func DefaultBeacon(region string) (*Beacon, bool) {
+	beacon, ok := defaultBeacons[region]
+	if !ok {
+		return nil, false
+	}
+	beacon.Frequencies = append([]uint64(nil), beacon.Frequencies...)
+	return &beacon, true
+}
+
+// FrequencyPlan contains the channels and limits that a gateway uses
+type FrequencyPlan struct {
+	ID               string    `json:"id" yaml:"id"`
+	Region           string    `json:"region" yaml:"region"`
+	UplinkChannels   []Channel `json:"uplink_channels" yaml:"uplink-channels"`
+	DownlinkChannels []Channel `json:"downlink_channels" yaml:"downlink-channels"`
+	RX2              RX2       `json:"rx2" yaml:"rx2"`
+	MaxEIRP          float32   `json:"max_eirp" yaml:"max-eirp"`
+	DwellTime        bool      `json:"dwell_time" yaml:"dwell-time"`
+	Beacon           *Beacon   `json:"beacon,omitempty" yaml:"beacon"`
+}
+
+// FromBand builds the default frequency plan for the given region
+func FromBand(region string) (*FrequencyPlan, error) {
+	fp, err := band.Get(region)
+	if err != nil {
+		return nil, err
+	}
+	channels := func(in []lora.Channel) (out []Channel) {
+		for _, channel := range in {
+			var dataRates []string
+			for _, idx := range channel.DataRates {
+				if dr, err := fp.GetDataRateStringForIndex(idx); err == nil {
+					dataRates = append(dataRates, dr)
+				}
+			}
+			out = append(out, Channel{Frequency: uint64(channel.Frequency), DataRates: dataRates})
+		}
+		return
+	}
+	plan := &FrequencyPlan{
+		ID:               region,
+		Region:           region,
+		UplinkChannels:   channels(fp.UplinkChannels),
+		DownlinkChannels: channels(fp.DownlinkChannels),
+		RX2:              RX2{Frequency: uint64(fp.RX2Frequency)},
+		DwellTime:        fp.DwellTime,
+	}
+	plan.RX2.DataRate, _ = fp.GetDataRateStringForIndex(fp.RX2DataRate)
+	plan.Beacon, _ = DefaultBeacon(region)
+	for _, power := range fp.TXPower {
+		if float32(power) > plan.MaxEIRP {
+			plan.MaxEIRP = float32(power)
+		}
+	}
+	if region == "EU_863_870" {
+		plan.MaxEIRP = 27 // The EU RX2 frequency allows up to 27dBm
+	}
+	return plan, nil
+}
+
+// Validate the frequency plan
+func (p *FrequencyPlan) Validate() error {
+	if p.ID == "" {
+		return errors.NewErrInvalidArgument("Frequency Plan", "id is empty")
+	}
+	if len(p.UplinkChannels) == 0 {
+		return errors.NewErrInvalidArgument("Frequency Plan", "no uplink channels")
+	}
+	for _, channels := range [][]Channel{p.UplinkChannels, p.DownlinkChannels} {
+		for _, channel := range channels {
+			for _, dataRate := range channel.DataRates {
+				if _, err := types.ParseDataRate(dataRate); err != nil && !band.IsFSK(dataRate) {
+					return errors.NewErrInvalidArgument("Frequency Plan", fmt.Sprintf("invalid data rate %s", dataRate))
+				}
+			}
+		}
+	}
+	if p.Beacon != nil {
+		if len(p.Beacon.Frequencies) == 0 {
+			return errors.NewErrInvalidArgument("Frequency Plan", "no beacon frequencies")
+		}
+		if _, err := types.ParseDataRate(p.Beacon.DataRate); err != nil {
+			return errors.NewErrInvalidArgument("Frequency Plan", fmt.Sprintf("invalid beacon data rate %s", p.Beacon.DataRate))
+		}
+	}
+	return nil
+}
+
+func (p *FrequencyPlan) downlinkChannels() []Channel {
+	if len(p.DownlinkChannels) > 0 {
+		return p.DownlinkChannels
+	}
+	return p.UplinkChannels
+}
+
+// ValidateDownlink checks if a downlink with the given configuration and time on air is allowed by the frequency plan
+func (p *FrequencyPlan) ValidateDownlink(config *pb_gateway.TxConfiguration, dataRate string, timeOnAir time.Duration) error {
+	allowed := func(channel Channel) bool {
+		if channel.Frequency != config.Frequency {
+			return false
+		}
+		if len(channel.DataRates) == 0 || dataRate == "" {
+			return true
+		}
+		for _, dr := range channel.DataRates {
+			if dr == dataRate {
+				return true
+			}
+		}
+		return false
+	}
+	ok := allowed(Channel{Frequency: p.RX2.Frequency})
+	for _, channel := range p.downlinkChannels() {
+		if ok {
+			break
+		}
+		ok = allowed(channel)
+	}
+	if !ok {
+		return errors.NewErrInvalidArgument("Downlink", fmt.Sprintf("frequency %d with data rate %s is not in frequency plan %s", config.Frequency, dataRate, p.ID))
+	}
+	if p.MaxEIRP > 0 && float32(config.Power) > p.MaxEIRP {
+		return errors.NewErrInvalidArgument("Downlink", fmt.Sprintf("power %d exceeds max EIRP %.1f of frequency plan %s", config.Power, p.MaxEIRP, p.ID))
+	}
+	if p.DwellTime && timeOnAir > MaxDwellTime {
+		return errors.NewErrInvalidArgument("Downlink", fmt.Sprintf("time on air %s exceeds dwell time of frequency plan %s", timeOnAir, p.ID))
+	}
+	return nil
+}
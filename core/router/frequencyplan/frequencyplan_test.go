@@ -0,0 +1,78 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package frequencyplan
+
+import (
+	"testing"
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestFromBand(t *testing.T) {
+	a := New(t)
+
+	plan, err := FromBand("EU_863_870")
+	a.So(err, ShouldBeNil)
+	a.So(plan.ID, ShouldEqual, "EU_863_870")
+	a.So(plan.UplinkChannels, ShouldNotBeEmpty)
+	a.So(plan.UplinkChannels[0].Frequency, ShouldEqual, 868100000)
+	a.So(plan.UplinkChannels[0].DataRates, ShouldContain, "SF7BW125")
+	a.So(plan.UplinkChannels[8].DataRates, ShouldResemble, []string{"50000"})
+	a.So(plan.RX2.Frequency, ShouldEqual, 869525000)
+	a.So(plan.RX2.DataRate, ShouldEqual, "SF9BW125")
+	a.So(plan.Validate(), ShouldBeNil)
+
+	_, err = FromBand("UNKNOWN")
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestBeacon(t *testing.T) {
+	a := New(t)
+
+	plan, _ := FromBand("EU_863_870")
+	a.So(plan.Beacon, ShouldNotBeNil)
+	a.So(plan.Beacon.Frequency(0), ShouldEqual, 869525000)
+	a.So(plan.Beacon.Frequency(1), ShouldEqual, 869525000)
+	a.So(plan.Beacon.DataRate, ShouldEqual, "SF9BW125")
+
+	beacon, ok := DefaultBeacon("US_902_928")
+	a.So(ok, ShouldBeTrue)
+	a.So(beacon.Frequencies, ShouldHaveLength, 8)
+	a.So(beacon.Frequency(0), ShouldEqual, 923300000)
+	a.So(beacon.Frequency(7), ShouldEqual, 927500000)
+	a.So(beacon.Frequency(8), ShouldEqual, 923300000)
+
+	_, ok = DefaultBeacon("UNKNOWN")
+	a.So(ok, ShouldBeFalse)
+
+	plan.Beacon = &Beacon{DataRate: "SF9BW125"}
+	a.So(plan.Validate(), ShouldNotBeNil)
+	plan.Beacon = &Beacon{Frequencies: []uint64{869525000}, DataRate: "SF13"}
+	a.So(plan.Validate(), ShouldNotBeNil)
+}
+
+func TestValidateDownlink(t *testing.T) {
+	a := New(t)
+
+	plan := &FrequencyPlan{
+		ID: "test",
+		UplinkChannels: []Channel{
+			{Frequency: 923200000, DataRates: []string{"SF10BW125", "SF7BW125"}},
+		},
+		RX2:       RX2{Frequency: 923200000, DataRate: "SF10BW125"},
+		MaxEIRP:   16,
+		DwellTime: true,
+	}
+	a.So(plan.Validate(), ShouldBeNil)
+
+	a.So(plan.ValidateDownlink(&pb_gateway.TxConfiguration{Frequency: 923200000, Power: 14}, "SF7BW125", 100*time.Millisecond), ShouldBeNil)
+	a.So(plan.ValidateDownlink(&pb_gateway.TxConfiguration{Frequency: 923400000, Power: 14}, "SF7BW125", 100*time.Millisecond), ShouldNotBeNil)
+	a.So(plan.ValidateDownlink(&pb_gateway.TxConfiguration{Frequency: 923200000, Power: 20}, "SF7BW125", 100*time.Millisecond), ShouldNotBeNil)
+	a.So(plan.ValidateDownlink(&pb_gateway.TxConfiguration{Frequency: 923200000, Power: 14}, "SF7BW125", 500*time.Millisecond), ShouldNotBeNil)
+
+	a.So((&FrequencyPlan{}).Validate(), ShouldNotBeNil)
+	a.So((&FrequencyPlan{ID: "test", UplinkChannels: []Channel{{Frequency: 1, DataRates: []string{"SF13"}}}}).Validate(), ShouldNotBeNil)
+}
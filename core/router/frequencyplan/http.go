@@ -0,0 +1,58 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package frequencyplan
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+type httpHandler struct {
+	registry Registry
+}
+
+// NewHTTPHandler returns a read-only HTTP API for the registry:
+//
+//	GET /frequency-plans                     lists all frequency plans
+//	GET /frequency-plans/{id}                returns a frequency plan
+//	GET /gateways/{gateway_id}/frequency-plan returns the frequency plan of a gateway
+func NewHTTPHandler(registry Registry) http.Handler {
+	return &httpHandler{registry}
+}
+
+func (h *httpHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	path := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(path) == 1 && path[0] == "frequency-plans":
+		h.write(res, h.registry.List(), nil)
+	case len(path) == 2 && path[0] == "frequency-plans":
+		plan, err := h.registry.Get(path[1])
+		h.write(res, plan, err)
+	case len(path) == 3 && path[0] == "gateways" && path[2] == "frequency-plan":
+		plan, err := h.registry.ForGateway(path[1])
+		h.write(res, plan, err)
+	default:
+		http.NotFound(res, req)
+	}
+}
+
+func (h *httpHandler) write(res http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.GetErrType(err) == errors.NotFound {
+			code = http.StatusNotFound
+		}
+		http.Error(res, err.Error(), code)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(res).Encode(v)
+}
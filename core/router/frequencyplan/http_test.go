@@ -0,0 +1,51 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package frequencyplan
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	a := New(t)
+
+	r := NewRegistry()
+	r.Set(&FrequencyPlan{ID: "test", UplinkChannels: []Channel{{Frequency: 868100000}}})
+	r.Assign("gtw", "test")
+
+	h := NewHTTPHandler(r)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/frequency-plans")
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var plans []*FrequencyPlan
+	a.So(json.Unmarshal(rec.Body.Bytes(), &plans), ShouldBeNil)
+	a.So(plans, ShouldHaveLength, 1)
+
+	rec = get("/frequency-plans/test")
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+
+	rec = get("/frequency-plans/other")
+	a.So(rec.Code, ShouldEqual, http.StatusNotFound)
+
+	rec = get("/gateways/gtw/frequency-plan")
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var plan FrequencyPlan
+	a.So(json.Unmarshal(rec.Body.Bytes(), &plan), ShouldBeNil)
+	a.So(plan.ID, ShouldEqual, "test")
+
+	rec = get("/gateways/other/frequency-plan")
+	a.So(rec.Code, ShouldEqual, http.StatusNotFound)
+}
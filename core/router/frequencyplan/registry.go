@@ -0,0 +1,160 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package frequencyplan
+
+import (
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Registry contains frequency plans and the gateways that are assigned to them
+type Registry interface {
+	List() []*FrequencyPlan
+	Get(id string) (*FrequencyPlan, error)
+	Set(plan *FrequencyPlan) error
+	Delete(id string) error
+
+	// Assign a gateway to a frequency plan. An empty planID removes the assignment.
+	Assign(gatewayID string, planID string) error
+	// ForGateway returns the frequency plan that the gateway is assigned to
+	ForGateway(gatewayID string) (*FrequencyPlan, error)
+}
+
+// NewRegistry returns a new in-memory Registry
+func NewRegistry() Registry {
+	return &registry{
+		plans:    make(map[string]*FrequencyPlan),
+		gateways: make(map[string]string),
+	}
+}
+
+type registry struct {
+	mu       sync.RWMutex
+	plans    map[string]*FrequencyPlan
+	gateways map[string]string
+}
+
+func (r *registry) List() []*FrequencyPlan {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.plans))
+	for id := range r.plans {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	plans := make([]*FrequencyPlan, 0, len(ids))
+	for _, id := range ids {
+		plans = append(plans, r.plans[id])
+	}
+	return plans
+}
+
+func (r *registry) Get(id string) (*FrequencyPlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if plan, ok := r.plans[id]; ok {
+		return plan, nil
+	}
+	return nil, errors.NewErrNotFound(id)
+}
+
+func (r *registry) Set(plan *FrequencyPlan) error {
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.plans[plan.ID] = plan
+	return nil
+}
+
+func (r *registry) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.plans[id]; !ok {
+		return errors.NewErrNotFound(id)
+	}
+	delete(r.plans, id)
+	for gatewayID, planID := range r.gateways {
+		if planID == id {
+			delete(r.gateways, gatewayID)
+		}
+	}
+	return nil
+}
+
+func (r *registry) Assign(gatewayID string, planID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if planID == "" {
+		delete(r.gateways, gatewayID)
+		return nil
+	}
+	if _, ok := r.plans[planID]; !ok {
+		return errors.NewErrNotFound(planID)
+	}
+	r.gateways[gatewayID] = planID
+	return nil
+}
+
+func (r *registry) ForGateway(gatewayID string) (*FrequencyPlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	planID, ok := r.gateways[gatewayID]
+	if !ok {
+		return nil, errors.NewErrNotFound(gatewayID)
+	}
+	return r.plans[planID], nil
+}
+
+// Config is the structure of a frequency plan config file
+type Config struct {
+	// Regions for which the default frequency plan should be added
+	Regions []string `yaml:"regions"`
+	// FrequencyPlans that should be added
+	FrequencyPlans []*FrequencyPlan `yaml:"frequency-plans"`
+	// Gateways maps gateway IDs to frequency plan IDs
+	Gateways map[string]string `yaml:"gateways"`
+}
+
+// Load the config into the registry
+func Load(registry Registry, config Config) error {
+	for _, region := range config.Regions {
+		plan, err := FromBand(region)
+		if err != nil {
+			return errors.Wrapf(err, "could not build frequency plan for %s", region)
+		}
+		if err := registry.Set(plan); err != nil {
+			return err
+		}
+	}
+	for _, plan := range config.FrequencyPlans {
+		if err := registry.Set(plan); err != nil {
+			return errors.Wrapf(err, "invalid frequency plan %s", plan.ID)
+		}
+	}
+	for gatewayID, planID := range config.Gateways {
+		if err := registry.Assign(gatewayID, planID); err != nil {
+			return errors.Wrapf(err, "could not assign %s", gatewayID)
+		}
+	}
+	return nil
+}
+
+// LoadFile loads a YAML (or JSON) config file into the registry
+func LoadFile(registry Registry, filename string) error {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var config Config
+	if err := yaml.Unmarshal(contents, &config); err != nil {
+		return err
+	}
+	return Load(registry, config)
+}
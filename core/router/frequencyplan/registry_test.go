@@ -0,0 +1,73 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package frequencyplan
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestRegistry(t *testing.T) {
+	a := New(t)
+
+	r := NewRegistry()
+
+	_, err := r.Get("test")
+	a.So(err, ShouldNotBeNil)
+
+	a.So(r.Set(&FrequencyPlan{ID: "test"}), ShouldNotBeNil)
+	a.So(r.Set(&FrequencyPlan{ID: "test", UplinkChannels: []Channel{{Frequency: 868100000}}}), ShouldBeNil)
+	a.So(r.List(), ShouldHaveLength, 1)
+
+	a.So(r.Assign("gtw", "other"), ShouldNotBeNil)
+	a.So(r.Assign("gtw", "test"), ShouldBeNil)
+
+	plan, err := r.ForGateway("gtw")
+	a.So(err, ShouldBeNil)
+	a.So(plan.ID, ShouldEqual, "test")
+
+	a.So(r.Delete("test"), ShouldBeNil)
+	_, err = r.ForGateway("gtw")
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestLoadFile(t *testing.T) {
+	a := New(t)
+
+	file, err := ioutil.TempFile("", "ttn-frequency-plans")
+	a.So(err, ShouldBeNil)
+	defer os.Remove(file.Name())
+	file.WriteString(`
+regions: [EU_863_870]
+frequency-plans:
+- id: private
+  region: EU_863_870
+  uplink-channels:
+  - frequency: 869100000
+    data-rates: [SF7BW125]
+  rx2:
+    frequency: 869525000
+    data-rate: SF9BW125
+  max-eirp: 14
+gateways:
+  gtw-1: private
+  gtw-2: EU_863_870
+`)
+	file.Close()
+
+	r := NewRegistry()
+	a.So(LoadFile(r, file.Name()), ShouldBeNil)
+	a.So(r.List(), ShouldHaveLength, 2)
+
+	plan, err := r.ForGateway("gtw-1")
+	a.So(err, ShouldBeNil)
+	a.So(plan.MaxEIRP, ShouldEqual, 14)
+
+	plan, err = r.ForGateway("gtw-2")
+	a.So(err, ShouldBeNil)
+	a.So(plan.ID, ShouldEqual, "EU_863_870")
+}
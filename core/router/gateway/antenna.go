@@ -0,0 +1,176 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// Antenna of a gateway, as registered in the gateway registry
+type Antenna struct {
+	// Index of the antenna in the antenna field of the gateway metadata
+	Index uint32 `json:"index"`
+	// Gain of the antenna in dBi
+	Gain float32 `json:"gain"`
+	// Location of the antenna, if it differs from the location of the gateway
+	Latitude  float32 `json:"latitude,omitempty"`
+	Longitude float32 `json:"longitude,omitempty"`
+	Altitude  int32   `json:"altitude,omitempty"`
+}
+
+// HasLocation returns true if the antenna has a location
+func (a Antenna) HasLocation() bool {
+	return a.Latitude != 0 || a.Longitude != 0
+}
+
+// GPS returns the location of the antenna as gateway GPS metadata
+func (a Antenna) GPS() *pb.GPSMetadata {
+	if !a.HasLocation() {
+		return nil
+	}
+	return &pb.GPSMetadata{
+		Latitude:  a.Latitude,
+		Longitude: a.Longitude,
+		Altitude:  a.Altitude,
+	}
+}
+
+// Validate the antenna
+func (a Antenna) Validate() error {
+	if a.Gain < -10 || a.Gain > 30 {
+		return errors.NewErrInvalidArgument("Gain", fmt.Sprintf("%.1f dBi is out of range", a.Gain))
+	}
+	if a.Latitude < -90 || a.Latitude > 90 {
+		return errors.NewErrInvalidArgument("Latitude", "must be between -90 and 90")
+	}
+	if a.Longitude < -180 || a.Longitude > 180 {
+		return errors.NewErrInvalidArgument("Longitude", "must be between -180 and 180")
+	}
+	return nil
+}
+
+type byIndex []Antenna
+
+func (a byIndex) Len() int           { return len(a) }
+func (a byIndex) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byIndex) Less(i, j int) bool { return a[i].Index < a[j].Index }
+
+// AntennaRegistry contains the antennas of registered gateways that have more
+// than one antenna, or of which the antenna gain or location is known
+type AntennaRegistry interface {
+	// Set the antennas of a gateway. An empty list removes the antennas.
+	Set(gatewayID string, antennas []Antenna) error
+	// Get the antennas of a gateway, sorted by index
+	Get(gatewayID string) []Antenna
+}
+
+// NewAntennaRegistry returns a new in-memory AntennaRegistry
+func NewAntennaRegistry() AntennaRegistry {
+	return &antennaRegistry{
+		gateways: make(map[string][]Antenna),
+	}
+}
+
+type antennaRegistry struct {
+	sync.RWMutex
+	gateways map[string][]Antenna
+}
+
+func (r *antennaRegistry) Set(gatewayID string, antennas []Antenna) error {
+	seen := make(map[uint32]bool)
+	for _, antenna := range antennas {
+		if seen[antenna.Index] {
+			return errors.NewErrInvalidArgument("Antennas", fmt.Sprintf("duplicate index %d", antenna.Index))
+		}
+		seen[antenna.Index] = true
+		if err := antenna.Validate(); err != nil {
+			return err
+		}
+	}
+	r.Lock()
+	defer r.Unlock()
+	if len(antennas) == 0 {
+		delete(r.gateways, gatewayID)
+		return nil
+	}
+	sorted := append([]Antenna(nil), antennas...)
+	sort.Sort(byIndex(sorted))
+	r.gateways[gatewayID] = sorted
+	return nil
+}
+
+func (r *antennaRegistry) Get(gatewayID string) []Antenna {
+	r.RLock()
+	defer r.RUnlock()
+	return r.gateways[gatewayID]
+}
+
+// AntennaStats contains the statistics of an antenna
+type AntennaStats struct {
+	Antenna
+	Uplinks  uint64    `json:"uplinks"`
+	Rssi     float32   `json:"rssi"` // Average RSSI
+	Snr      float32   `json:"snr"`  // Average SNR
+	LastSeen time.Time `json:"last_seen,omitempty"`
+}
+
+type antennaStats struct {
+	sync.RWMutex
+	items map[uint32]*AntennaStats
+}
+
+func (s *antennaStats) add(index uint32, rssi, snr float32) {
+	s.Lock()
+	defer s.Unlock()
+	if s.items == nil {
+		s.items = make(map[uint32]*AntennaStats)
+	}
+	stats, ok := s.items[index]
+	if !ok {
+		stats = &AntennaStats{}
+		s.items[index] = stats
+	}
+	stats.Uplinks++
+	stats.Rssi += (rssi - stats.Rssi) / float32(stats.Uplinks)
+	stats.Snr += (snr - stats.Snr) / float32(stats.Uplinks)
+	stats.LastSeen = time.Now()
+}
+
+func (s *antennaStats) list(antennas []Antenna) []AntennaStats {
+	s.RLock()
+	defer s.RUnlock()
+	list := make([]AntennaStats, 0, len(antennas)+len(s.items))
+	registered := make(map[uint32]bool)
+	for _, antenna := range antennas {
+		registered[antenna.Index] = true
+		var stats AntennaStats
+		if item, ok := s.items[antenna.Index]; ok {
+			stats = *item
+		}
+		stats.Antenna = antenna
+		list = append(list, stats)
+	}
+	for index, item := range s.items {
+		if registered[index] {
+			continue
+		}
+		stats := *item
+		stats.Antenna = Antenna{Index: index}
+		list = append(list, stats)
+	}
+	sort.Sort(statsByIndex(list))
+	return list
+}
+
+type statsByIndex []AntennaStats
+
+func (a statsByIndex) Len() int           { return len(a) }
+func (a statsByIndex) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a statsByIndex) Less(i, j int) bool { return a[i].Index < a[j].Index }
@@ -0,0 +1,84 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"testing"
+
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestAntennaValidate(t *testing.T) {
+	a := New(t)
+	a.So(Antenna{Gain: 3}.Validate(), ShouldBeNil)
+	a.So(Antenna{Gain: 40}.Validate(), ShouldNotBeNil)
+	a.So(Antenna{Latitude: 91}.Validate(), ShouldNotBeNil)
+	a.So(Antenna{Longitude: -181}.Validate(), ShouldNotBeNil)
+}
+
+func TestAntennaRegistry(t *testing.T) {
+	a := New(t)
+	registry := NewAntennaRegistry()
+	a.So(registry.Get("eui-0102030405060708"), ShouldBeEmpty)
+
+	err := registry.Set("eui-0102030405060708", []Antenna{{Index: 0}, {Index: 0}})
+	a.So(err, ShouldNotBeNil)
+
+	err = registry.Set("eui-0102030405060708", []Antenna{{Index: 1, Gain: 6}, {Index: 0, Gain: 2}})
+	a.So(err, ShouldBeNil)
+	antennas := registry.Get("eui-0102030405060708")
+	a.So(antennas, ShouldHaveLength, 2)
+	a.So(antennas[0].Index, ShouldEqual, 0)
+	a.So(antennas[1].Gain, ShouldEqual, 6)
+
+	a.So(registry.Set("eui-0102030405060708", nil), ShouldBeNil)
+	a.So(registry.Get("eui-0102030405060708"), ShouldBeEmpty)
+}
+
+func TestGatewayAntennas(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayAntennas"), "eui-0102030405060708")
+	a.So(gtw.Antennas.Set(gtw.ID, []Antenna{
+		{Index: 0, Gain: 2},
+		{Index: 1, Gain: 6, Latitude: 52.37, Longitude: 4.89},
+	}), ShouldBeNil)
+
+	_, ok := gtw.Antenna(2)
+	a.So(ok, ShouldBeFalse)
+
+	// The location of the antenna is injected
+	up := buildUplink(868100000)
+	up.GatewayMetadata.Antenna = 1
+	up.GatewayMetadata.Rssi = -100
+	up.GatewayMetadata.Snr = 5
+	a.So(gtw.HandleUplink(up), ShouldBeNil)
+	a.So(up.GatewayMetadata.Gps, ShouldNotBeNil)
+	a.So(up.GatewayMetadata.Gps.Latitude, ShouldEqual, float32(52.37))
+
+	up = buildUplink(868100000)
+	up.GatewayMetadata.Antenna = 1
+	up.GatewayMetadata.Rssi = -80
+	up.GatewayMetadata.Snr = 7
+	a.So(gtw.HandleUplink(up), ShouldBeNil)
+
+	// Antenna 0 has no location
+	up = buildUplink(868100000)
+	a.So(gtw.HandleUplink(up), ShouldBeNil)
+	a.So(up.GatewayMetadata.Gps, ShouldBeNil)
+
+	// Unregistered antennas are also in the stats
+	up = buildUplink(868100000)
+	up.GatewayMetadata.Antenna = 3
+	a.So(gtw.HandleUplink(up), ShouldBeNil)
+
+	stats := gtw.AntennaStats()
+	a.So(stats, ShouldHaveLength, 3)
+	a.So(stats[0].Uplinks, ShouldEqual, 1)
+	a.So(stats[1].Gain, ShouldEqual, 6)
+	a.So(stats[1].Uplinks, ShouldEqual, 2)
+	a.So(stats[1].Rssi, ShouldEqual, -90)
+	a.So(stats[1].Snr, ShouldEqual, 6)
+	a.So(stats[2].Index, ShouldEqual, 3)
+}
@@ -0,0 +1,172 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// BeaconPeriod is the time between two Class B beacons
+const BeaconPeriod = 128 * time.Second
+
+// MaxTimeReferenceAge is the maximum age of the GPS time reference that is used
+// to convert the time of a beacon to the timestamp of a gateway
+var MaxTimeReferenceAge = 5 * time.Minute
+
+// gpsEpoch is the start of GPS time
+var gpsEpoch = time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+// gpsLeapSeconds is the number of leap seconds between GPS time and UTC (since 2017-01-01)
+const gpsLeapSeconds = 18 * time.Second
+
+// toGPSTime returns the time since the GPS epoch
+func toGPSTime(t time.Time) time.Duration {
+	return t.Sub(gpsEpoch) + gpsLeapSeconds
+}
+
+// NextBeacon returns the time of the first beacon after t. Beacons are sent
+// at the start of every beacon period in GPS time.
+func NextBeacon(t time.Time) time.Time {
+	next := (toGPSTime(t)/BeaconPeriod + 1) * BeaconPeriod
+	return gpsEpoch.Add(next - gpsLeapSeconds)
+}
+
+// BeaconPeriodIndex returns the number of beacon periods since the GPS epoch,
+// which is used to select the frequency of the beacon
+func BeaconPeriodIndex(t time.Time) int64 {
+	return int64(toGPSTime(t) / BeaconPeriod)
+}
+
+// beaconLayout contains the sizes of the RFU fields of a beacon, which depend on the data rate
+type beaconLayout struct {
+	rfu1, rfu2 int
+}
+
+var beaconLayouts = map[string]beaconLayout{
+	"SF9BW125":  {rfu1: 2, rfu2: 0},
+	"SF12BW125": {rfu1: 3, rfu2: 1},
+	"SF12BW500": {rfu1: 5, rfu2: 3},
+}
+
+// beaconCRC returns the CRC-16 (CCITT polynomial, initial value 0) of a beacon field
+func beaconCRC(data []byte) (crc uint16) {
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return
+}
+
+// putCoordinate writes a coordinate as a 24 bit two's complement value, where max is mapped to 2^23
+func putCoordinate(b []byte, coordinate float64, max float64) {
+	value := int32(math.Floor(coordinate / max * (1 << 23)))
+	if value > 1<<23-1 {
+		value = 1<<23 - 1
+	}
+	b[0], b[1], b[2] = byte(value), byte(value>>8), byte(value>>16)
+}
+
+// BuildBeacon builds the frame of the beacon at time t for the given data rate.
+// The gateway specific part contains the location of the gateway, if known.
+func BuildBeacon(dataRate string, t time.Time, location *Location) ([]byte, error) {
+	layout, ok := beaconLayouts[dataRate]
+	if !ok {
+		return nil, errors.NewErrInvalidArgument("Beacon", fmt.Sprintf("no beacon format for data rate %s", dataRate))
+	}
+	frame := make([]byte, layout.rfu1+4+2+7+layout.rfu2+2)
+	binary.LittleEndian.PutUint32(frame[layout.rfu1:], uint32(toGPSTime(t)/time.Second))
+	binary.LittleEndian.PutUint16(frame[layout.rfu1+4:], beaconCRC(frame[:layout.rfu1+4]))
+	gwSpecific := frame[layout.rfu1+6 : len(frame)-2]
+	// InfoDesc 0: the GPS coordinates of the gateway antenna
+	if location != nil {
+		putCoordinate(gwSpecific[1:4], float64(location.Latitude), 90)
+		putCoordinate(gwSpecific[4:7], float64(location.Longitude), 180)
+	}
+	binary.LittleEndian.PutUint16(frame[len(frame)-2:], beaconCRC(gwSpecific))
+	return frame, nil
+}
+
+// BeaconStats are the statistics of the beacons of a gateway
+type BeaconStats struct {
+	// Capable is true if the gateway has a recent GPS time reference and a concentrator clock that is reliable for Class B
+	Capable   bool       `json:"capable"`
+	Sent      uint64     `json:"sent"`
+	Missed    uint64     `json:"missed"`
+	LastSent  *time.Time `json:"last_sent,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// timeReference relates the timestamp of a gateway to the GPS time
+type timeReference struct {
+	timestamp  uint32
+	time       time.Time
+	receivedAt time.Time
+}
+
+type beaconState struct {
+	mu        sync.RWMutex
+	reference *timeReference
+	stats     BeaconStats
+}
+
+// setTimeReference sets the GPS time reference of the gateway
+func (g *Gateway) setTimeReference(timestamp uint32, t time.Time) {
+	g.beacons.mu.Lock()
+	defer g.beacons.mu.Unlock()
+	g.beacons.reference = &timeReference{timestamp: timestamp, time: t, receivedAt: time.Now()}
+}
+
+// TimestampAt returns the gateway timestamp (in microseconds) at time t. This
+// requires a recent uplink message with a GPS time and a concentrator clock
+// that is reliable for Class B.
+func (g *Gateway) TimestampAt(t time.Time) (uint32, error) {
+	if !g.timeSync.get().ClassB {
+		return 0, errors.NewErrInternal(fmt.Sprintf("Timestamps of gateway %s are not reliable for Class B", g.ID))
+	}
+	g.beacons.mu.RLock()
+	defer g.beacons.mu.RUnlock()
+	ref := g.beacons.reference
+	if ref == nil || time.Since(ref.receivedAt) > MaxTimeReferenceAge {
+		return 0, errors.NewErrNotFound(fmt.Sprintf("Recent GPS time of gateway %s", g.ID))
+	}
+	return ref.timestamp + uint32(int64(t.Sub(ref.time)/time.Microsecond)), nil
+}
+
+// BeaconSent records that a beacon was scheduled
+func (g *Gateway) BeaconSent(t time.Time) {
+	g.beacons.mu.Lock()
+	defer g.beacons.mu.Unlock()
+	g.beacons.stats.Sent++
+	g.beacons.stats.LastSent = &t
+	g.beacons.stats.LastError = ""
+}
+
+// BeaconMissed records that a beacon could not be scheduled
+func (g *Gateway) BeaconMissed(err error) {
+	g.beacons.mu.Lock()
+	defer g.beacons.mu.Unlock()
+	g.beacons.stats.Missed++
+	g.beacons.stats.LastError = err.Error()
+}
+
+// BeaconStats returns the statistics of the beacons of the gateway
+func (g *Gateway) BeaconStats() BeaconStats {
+	g.beacons.mu.RLock()
+	defer g.beacons.mu.RUnlock()
+	stats := g.beacons.stats
+	stats.Capable = g.beacons.reference != nil && time.Since(g.beacons.reference.receivedAt) <= MaxTimeReferenceAge && g.timeSync.get().ClassB
+	return stats
+}
@@ -0,0 +1,110 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestNextBeacon(t *testing.T) {
+	a := New(t)
+
+	a.So(NextBeacon(gpsEpoch), ShouldResemble, gpsEpoch.Add(110*time.Second))
+
+	now := time.Now()
+	next := NextBeacon(now)
+	a.So(next.After(now), ShouldBeTrue)
+	a.So(next.Sub(now), ShouldBeLessThanOrEqualTo, BeaconPeriod)
+	a.So(toGPSTime(next)%BeaconPeriod, ShouldEqual, 0)
+	a.So(NextBeacon(next), ShouldResemble, next.Add(BeaconPeriod))
+	a.So(BeaconPeriodIndex(NextBeacon(next)), ShouldEqual, BeaconPeriodIndex(next)+1)
+}
+
+func TestBeaconCRC(t *testing.T) {
+	a := New(t)
+
+	// Example beacon of the LoRaWAN Class B specification
+	a.So(beaconCRC([]byte{0x00, 0x00, 0x00, 0x00, 0x02, 0xCC}), ShouldEqual, 0x7EA2)
+	a.So(beaconCRC([]byte{0x00, 0x01, 0x20, 0x00, 0x00, 0x81, 0x03}), ShouldEqual, 0x55DE)
+}
+
+func TestBuildBeacon(t *testing.T) {
+	a := New(t)
+
+	beaconTime := NextBeacon(time.Now())
+
+	frame, err := BuildBeacon("SF9BW125", beaconTime, &Location{Latitude: 90, Longitude: -180})
+	a.So(err, ShouldBeNil)
+	a.So(frame, ShouldHaveLength, 17)
+	a.So(frame[:2], ShouldResemble, []byte{0, 0})
+	a.So(binary.LittleEndian.Uint32(frame[2:6]), ShouldEqual, uint32(toGPSTime(beaconTime)/time.Second))
+	a.So(binary.LittleEndian.Uint16(frame[6:8]), ShouldEqual, beaconCRC(frame[:6]))
+	a.So(frame[8:15], ShouldResemble, []byte{0x00, 0xFF, 0xFF, 0x7F, 0x00, 0x00, 0x80})
+	a.So(binary.LittleEndian.Uint16(frame[15:17]), ShouldEqual, beaconCRC(frame[8:15]))
+
+	frame, err = BuildBeacon("SF12BW500", beaconTime, nil)
+	a.So(err, ShouldBeNil)
+	a.So(frame, ShouldHaveLength, 23)
+	a.So(frame[11:18], ShouldResemble, make([]byte, 7))
+
+	frame, err = BuildBeacon("SF12BW125", beaconTime, nil)
+	a.So(err, ShouldBeNil)
+	a.So(frame, ShouldHaveLength, 19)
+
+	_, err = BuildBeacon("SF7BW125", beaconTime, nil)
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestGatewayBeacons(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayBeacons"), "eui-0102030405060708")
+
+	_, err := gtw.TimestampAt(time.Now())
+	a.So(err, ShouldNotBeNil)
+	a.So(gtw.BeaconStats().Capable, ShouldBeFalse)
+
+	// Uplink without GPS time
+	a.So(gtw.HandleUplink(buildUplink(868100000)), ShouldBeNil)
+	a.So(gtw.BeaconStats().Capable, ShouldBeFalse)
+
+	now := time.Now()
+	uplink := buildUplink(868100000)
+	uplink.GatewayMetadata.Timestamp = 1<<32 - 10 - 1000000
+	uplink.GatewayMetadata.Time = now.Add(-1 * time.Second).UnixNano()
+	uplink.GatewayMetadata.Gps = &pb.GPSMetadata{Time: now.Add(-1 * time.Second).UnixNano(), Latitude: 52.37, Longitude: 4.89}
+	a.So(gtw.HandleUplink(uplink), ShouldBeNil)
+	a.So(gtw.BeaconStats().Capable, ShouldBeFalse) // The drift is not known yet
+
+	uplink = buildUplink(868100000)
+	uplink.GatewayMetadata.Timestamp = 1<<32 - 10
+	uplink.GatewayMetadata.Time = now.UnixNano()
+	uplink.GatewayMetadata.Gps = &pb.GPSMetadata{Time: now.UnixNano(), Latitude: 52.37, Longitude: 4.89}
+	a.So(gtw.HandleUplink(uplink), ShouldBeNil)
+	a.So(gtw.BeaconStats().Capable, ShouldBeTrue)
+
+	timestamp, err := gtw.TimestampAt(now.Add(time.Second))
+	a.So(err, ShouldBeNil)
+	a.So(timestamp, ShouldEqual, 1000000-10)
+	timestamp, err = gtw.TimestampAt(now.Add(20 * time.Microsecond))
+	a.So(err, ShouldBeNil)
+	a.So(timestamp, ShouldEqual, 10)
+
+	gtw.BeaconMissed(errors.New("conflict"))
+	stats := gtw.BeaconStats()
+	a.So(stats.Missed, ShouldEqual, 1)
+	a.So(stats.LastError, ShouldEqual, "conflict")
+
+	gtw.BeaconSent(now)
+	stats = gtw.BeaconStats()
+	a.So(stats.Sent, ShouldEqual, 1)
+	a.So(*stats.LastSent, ShouldResemble, now)
+	a.So(stats.LastError, ShouldBeEmpty)
+}
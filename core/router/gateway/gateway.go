@@ -4,6 +4,7 @@
 package gateway
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -13,6 +14,8 @@ import (
 	pb_monitor "github.com/TheThingsNetwork/ttn/api/monitor"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/alerting"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
 )
 
 // NewGateway creates a new in-memory Gateway structure
@@ -23,6 +26,8 @@ func NewGateway(ctx ttnlog.Interface, id string) *Gateway {
 		Status:      NewStatusStore(),
 		Utilization: NewUtilization(),
 		Schedule:    NewSchedule(ctx),
+		Antennas:    NewAntennaRegistry(),
+		Location:    NewLocationTracker(),
 		Monitors:    pb_monitor.NewRegistry(ctx),
 		Ctx:         ctx,
 	}
@@ -36,13 +41,30 @@ type Gateway struct {
 	Status      StatusStore
 	Utilization Utilization
 	Schedule    Schedule
+	Antennas    AntennaRegistry
+	Location    LocationTracker
 	LastSeen    time.Time
 
-	mu            sync.RWMutex // Protect token and authenticated
+	mu            sync.RWMutex // Protect token, authenticated, trust and registration
 	token         string
 	authenticated bool
+	trust         TrustLevel
+	registration  Registration
 
 	Monitors pb_monitor.Registry
+	Alerts   *alerting.Manager
+
+	airtimeLock     sync.RWMutex
+	uplinkAirtime   toa.Usage
+	downlinkAirtime map[string]*toa.Usage // per application
+
+	antennaStats antennaStats
+
+	beacons beaconState
+
+	timeSync timeSync
+
+	rtt rtt
 
 	Ctx ttnlog.Interface
 }
@@ -58,19 +80,145 @@ func (g *Gateway) SetAuth(token string, authenticated bool) {
 	g.Monitors.SetGatewayToken(g.ID, g.token)
 }
 
+// SetTrust sets the trust level of the gateway
+func (g *Gateway) SetTrust(trust TrustLevel) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.trust = trust
+}
+
+// Registration is the region and location of a gateway that were configured
+// when it was registered
+type Registration struct {
+	Region   string
+	Location *Location
+}
+
+// SetRegistration sets the region and location of the gateway that are used
+// if the gateway does not send them in its status messages
+func (g *Gateway) SetRegistration(registration Registration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.registration = registration
+}
+
+// Trusted returns true if the metadata of the gateway is trusted
+func (g *Gateway) Trusted() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.trusted()
+}
+
+func (g *Gateway) trusted() bool {
+	switch g.trust {
+	case TrustTrusted:
+		return true
+	case TrustUntrusted:
+		return false
+	}
+	return g.authenticated
+}
+
 func (g *Gateway) updateLastSeen() {
 	g.LastSeen = time.Now()
 }
 
+func (g *Gateway) addUplinkAirtime(uplink *pb_router.UplinkMessage) {
+	airtime := uplink.GetProtocolMetadata().GetLorawan().GetAirtime()
+	if airtime <= 0 {
+		return
+	}
+	g.airtimeLock.Lock()
+	defer g.airtimeLock.Unlock()
+	g.uplinkAirtime.Add(time.Now(), time.Duration(airtime))
+}
+
+// UplinkAirtime returns the airtime of the uplink messages that were received by the gateway
+func (g *Gateway) UplinkAirtime() toa.Usage {
+	g.airtimeLock.RLock()
+	defer g.airtimeLock.RUnlock()
+	return g.uplinkAirtime.Get(time.Now())
+}
+
+// AddDownlinkAirtime adds the airtime of a downlink message of the application
+func (g *Gateway) AddDownlinkAirtime(appID string, airtime time.Duration) {
+	g.airtimeLock.Lock()
+	defer g.airtimeLock.Unlock()
+	if g.downlinkAirtime == nil {
+		g.downlinkAirtime = make(map[string]*toa.Usage)
+	}
+	usage, ok := g.downlinkAirtime[appID]
+	if !ok {
+		usage = new(toa.Usage)
+		g.downlinkAirtime[appID] = usage
+	}
+	usage.Add(time.Now(), airtime)
+}
+
+// DownlinkAirtime returns the airtime of the downlink messages that were sent
+// by the gateway for the application
+func (g *Gateway) DownlinkAirtime(appID string) toa.Usage {
+	g.airtimeLock.RLock()
+	defer g.airtimeLock.RUnlock()
+	if usage, ok := g.downlinkAirtime[appID]; ok {
+		return usage.Get(time.Now())
+	}
+	return toa.Usage{}
+}
+
+// ApplicationDownlinkAirtime returns the airtime of the downlink messages that
+// were sent by the gateway, per application
+func (g *Gateway) ApplicationDownlinkAirtime() map[string]toa.Usage {
+	g.airtimeLock.RLock()
+	defer g.airtimeLock.RUnlock()
+	now := time.Now()
+	airtime := make(map[string]toa.Usage, len(g.downlinkAirtime))
+	for appID, usage := range g.downlinkAirtime {
+		airtime[appID] = usage.Get(now)
+	}
+	return airtime
+}
+
+// Antenna returns the registered antenna with the given index
+func (g *Gateway) Antenna(index uint32) (Antenna, bool) {
+	for _, antenna := range g.Antennas.Get(g.ID) {
+		if antenna.Index == index {
+			return antenna, true
+		}
+	}
+	return Antenna{}, false
+}
+
+// AntennaStats returns the statistics of the registered antennas and of the
+// antennas that the gateway received uplink messages on
+func (g *Gateway) AntennaStats() []AntennaStats {
+	return g.antennaStats.list(g.Antennas.Get(g.ID))
+}
+
 func (g *Gateway) HandleStatus(status *pb.Status) (err error) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	status.GatewayTrusted = g.authenticated
+	status.GatewayTrusted = g.trusted()
+	if status.Region == "" {
+		status.Region = g.registration.Region
+	}
+	if status.Gps == nil && g.registration.Location != nil {
+		status.Gps = g.registration.Location.GPS()
+	}
 	if err = g.Status.Update(status); err != nil {
 		return err
 	}
 	g.updateLastSeen()
 
+	g.measureRTT(status.Time, time.Now())
+
+	g.Location.Update(status.Gps, time.Now())
+	if moved, ok := g.Location.Moved(); ok {
+		g.Alerts.Update(alerting.GatewayMoved, g.ID, true, fmt.Sprintf("Gateway %s moved %.0fm from %f,%f to %f,%f", g.ID, moved.Distance, moved.From.Latitude, moved.From.Longitude, moved.To.Latitude, moved.To.Longitude))
+	} else {
+		g.Alerts.Update(alerting.GatewayMoved, g.ID, false, fmt.Sprintf("Gateway %s is back at its location", g.ID))
+	}
+
 	clone := *status // Avoid race conditions
 	for _, monitor := range g.Monitors.GatewayClients(g.ID) {
 		go monitor.SendStatus(&clone)
@@ -84,13 +232,36 @@ func (g *Gateway) HandleUplink(uplink *pb_router.UplinkMessage) (err error) {
 		return err
 	}
 	g.Schedule.Sync(uplink.GatewayMetadata.Timestamp)
+	var gpsTime time.Time
+	if uplink.GatewayMetadata.Time != 0 && uplink.GatewayMetadata.GetGps().GetTime() != 0 {
+		// The gateway is GPS-synchronized
+		gpsTime = time.Unix(0, uplink.GatewayMetadata.Time)
+		g.setTimeReference(uplink.GatewayMetadata.Timestamp, gpsTime)
+	}
+	g.timeSync.add(uplink.GatewayMetadata.Timestamp, time.Now(), gpsTime)
+	g.measureRTT(uplink.GatewayMetadata.Time, time.Now())
+	if !gpsTime.IsZero() && g.timeSync.unreliableGPSTime() {
+		// Don't let the GPS time of the gateway be used for geolocation
+		uplink.GatewayMetadata.Time = 0
+	}
 	g.updateLastSeen()
+	g.addUplinkAirtime(uplink)
+	g.antennaStats.add(uplink.GatewayMetadata.Antenna, uplink.GatewayMetadata.Rssi, uplink.GatewayMetadata.Snr)
+
+	// Inject the location of the antenna that received the uplink
+	if antenna, ok := g.Antenna(uplink.GatewayMetadata.Antenna); ok && uplink.GatewayMetadata.Gps == nil {
+		uplink.GatewayMetadata.Gps = antenna.GPS()
+	}
 
 	status, err := g.Status.Get()
 	if err == nil {
-		// Inject Gateway location
+		// Inject Gateway location, falling back to the last status if it is not known yet
 		if uplink.GatewayMetadata.Gps == nil {
-			uplink.GatewayMetadata.Gps = status.GetGps()
+			if location, ok := g.Location.Get(); ok {
+				uplink.GatewayMetadata.Gps = location.GPS()
+			} else {
+				uplink.GatewayMetadata.Gps = status.GetGps()
+			}
 		}
 		// Inject Gateway region
 		if region, ok := pb_lorawan.Region_value[status.Region]; ok {
@@ -100,10 +271,10 @@ func (g *Gateway) HandleUplink(uplink *pb_router.UplinkMessage) (err error) {
 		}
 	}
 
-	// Inject authenticated as GatewayTrusted
+	// Inject trust as GatewayTrusted
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	uplink.GatewayMetadata.GatewayTrusted = g.authenticated
+	uplink.GatewayMetadata.GatewayTrusted = g.trusted()
 	uplink.GatewayMetadata.GatewayId = g.ID
 
 	clone := *uplink
@@ -5,7 +5,9 @@ package gateway
 
 import (
 	"testing"
+	"time"
 
+	pb "github.com/TheThingsNetwork/ttn/api/gateway"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
 	. "github.com/smartystreets/assertions"
 )
@@ -15,3 +17,80 @@ func TestNewGateway(t *testing.T) {
 	gtw := NewGateway(GetLogger(t, "TestNewGateway"), "eui-0102030405060708")
 	a.So(gtw, ShouldNotBeNil)
 }
+
+func TestGatewayUplinkAirtime(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayUplinkAirtime"), "eui-0102030405060708")
+	a.So(gtw.UplinkAirtime().Messages, ShouldEqual, 0)
+
+	a.So(gtw.HandleUplink(buildUplink(868100000)), ShouldBeNil)
+	a.So(gtw.HandleUplink(buildUplink(868300000)), ShouldBeNil)
+
+	airtime := gtw.UplinkAirtime()
+	a.So(airtime.Messages, ShouldEqual, 2)
+	a.So(airtime.Total, ShouldAlmostEqual, 2*41216*time.Microsecond, 2*time.Microsecond)
+	a.So(airtime.Today, ShouldEqual, airtime.Total)
+}
+
+func TestGatewayDownlinkAirtime(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayDownlinkAirtime"), "eui-0102030405060708")
+	a.So(gtw.DownlinkAirtime("app").Messages, ShouldEqual, 0)
+	a.So(gtw.ApplicationDownlinkAirtime(), ShouldBeEmpty)
+
+	gtw.AddDownlinkAirtime("app", time.Second)
+	gtw.AddDownlinkAirtime("app", time.Second)
+	gtw.AddDownlinkAirtime("other-app", time.Second)
+
+	a.So(gtw.DownlinkAirtime("app").Today, ShouldEqual, 2*time.Second)
+	a.So(gtw.DownlinkAirtime("app").Messages, ShouldEqual, 2)
+	airtime := gtw.ApplicationDownlinkAirtime()
+	a.So(airtime, ShouldHaveLength, 2)
+	a.So(airtime["other-app"].Today, ShouldEqual, time.Second)
+}
+
+func TestGatewayTrust(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayTrust"), "eui-0102030405060708")
+
+	// By default, authenticated gateways are trusted
+	a.So(gtw.Trusted(), ShouldBeFalse)
+	gtw.SetAuth("token", true)
+	a.So(gtw.Trusted(), ShouldBeTrue)
+	up := buildUplink(868100000)
+	a.So(gtw.HandleUplink(up), ShouldBeNil)
+	a.So(up.GatewayMetadata.GatewayTrusted, ShouldBeTrue)
+
+	gtw.SetTrust(TrustUntrusted)
+	a.So(gtw.Trusted(), ShouldBeFalse)
+	up = buildUplink(868100000)
+	a.So(gtw.HandleUplink(up), ShouldBeNil)
+	a.So(up.GatewayMetadata.GatewayTrusted, ShouldBeFalse)
+
+	gtw.SetAuth("", false)
+	gtw.SetTrust(TrustTrusted)
+	a.So(gtw.Trusted(), ShouldBeTrue)
+}
+
+func TestGatewayRegistration(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayRegistration"), "eui-0102030405060708")
+	gtw.SetRegistration(Registration{
+		Region:   "EU_863_870",
+		Location: &Location{Latitude: 52.37403, Longitude: 4.88968},
+	})
+
+	// The registration is used if the gateway does not send a region and location
+	a.So(gtw.HandleStatus(&pb.Status{}), ShouldBeNil)
+	status, err := gtw.Status.Get()
+	a.So(err, ShouldBeNil)
+	a.So(status.Region, ShouldEqual, "EU_863_870")
+	a.So(status.Gps.Latitude, ShouldEqual, float32(52.37403))
+
+	// The region and location that the gateway sends take precedence
+	a.So(gtw.HandleStatus(&pb.Status{Region: "US_902_928", Gps: &pb.GPSMetadata{Latitude: 10, Longitude: 20}}), ShouldBeNil)
+	status, err = gtw.Status.Get()
+	a.So(err, ShouldBeNil)
+	a.So(status.Region, ShouldEqual, "US_902_928")
+	a.So(status.Gps.Latitude, ShouldEqual, 10)
+}
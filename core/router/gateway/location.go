@@ -0,0 +1,227 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/gateway"
+)
+
+// LocationAccuracy is the maximum distance in meters between consecutive GPS
+// fixes in status messages for them to be considered accurate
+var LocationAccuracy = 50.0
+
+// LocationFixes is the number of consecutive accurate GPS fixes that is needed
+// before the location of a gateway is updated
+var LocationFixes = 3
+
+// LocationHistorySize is the number of locations that is kept for each gateway
+var LocationHistorySize = 100
+
+// MovementRadius is the distance in meters that a gateway can move before a
+// location alert is emitted (0 to disable)
+var MovementRadius = 1000.0
+
+const earthRadius = 6371000.0 // in meters
+
+// Location of a gateway
+type Location struct {
+	Latitude  float32   `json:"latitude"`
+	Longitude float32   `json:"longitude"`
+	Altitude  int32     `json:"altitude,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// LocationFromGPS returns the location from GPS metadata, or nil if it is not a valid fix
+func LocationFromGPS(gps *pb.GPSMetadata, t time.Time) *Location {
+	if gps == nil || (gps.Latitude == 0 && gps.Longitude == 0) {
+		return nil
+	}
+	if gps.Latitude < -90 || gps.Latitude > 90 || gps.Longitude < -180 || gps.Longitude > 180 {
+		return nil
+	}
+	return &Location{
+		Latitude:  gps.Latitude,
+		Longitude: gps.Longitude,
+		Altitude:  gps.Altitude,
+		Time:      t,
+	}
+}
+
+// GPS returns the location as gateway GPS metadata
+func (l Location) GPS() *pb.GPSMetadata {
+	return &pb.GPSMetadata{
+		Latitude:  l.Latitude,
+		Longitude: l.Longitude,
+		Altitude:  l.Altitude,
+	}
+}
+
+// Distance in meters to another location
+func (l Location) Distance(other Location) float64 {
+	rad := func(deg float32) float64 { return float64(deg) * math.Pi / 180 }
+	dLat := rad(other.Latitude - l.Latitude)
+	dLon := rad(other.Longitude - l.Longitude)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(rad(l.Latitude))*math.Cos(rad(other.Latitude))*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// LocationAlert is emitted when a gateway appears to have moved beyond the MovementRadius
+type LocationAlert struct {
+	From     Location  `json:"from"`
+	To       Location  `json:"to"`
+	Distance float64   `json:"distance"`
+	Time     time.Time `json:"time"`
+}
+
+// LocationTracker keeps track of the location of a gateway
+type LocationTracker interface {
+	// Update the location with the GPS fix of a status message. If the gateway
+	// moved beyond the MovementRadius, an alert is returned.
+	Update(gps *pb.GPSMetadata, t time.Time) *LocationAlert
+	// Get the current location
+	Get() (*Location, bool)
+	// History of the location, oldest first
+	History() []Location
+	// Alerts that were emitted, oldest first
+	Alerts() []LocationAlert
+	// Moved returns the movement of the gateway since it left the location
+	// of its first alert. It is reset when the gateway is back within the
+	// MovementRadius of that location.
+	Moved() (*LocationAlert, bool)
+}
+
+// NewLocationTracker creates a new in-memory LocationTracker
+func NewLocationTracker() LocationTracker {
+	return &locationTracker{}
+}
+
+type locationTracker struct {
+	sync.RWMutex
+	fixes     []Location
+	current   *Location
+	reference *Location
+	history   []Location
+	alerts    []LocationAlert
+	moved     *LocationAlert
+}
+
+func (l *locationTracker) Update(gps *pb.GPSMetadata, t time.Time) *LocationAlert {
+	fix := LocationFromGPS(gps, t)
+	if fix == nil {
+		return nil
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	// Filter inaccurate fixes: the location is only updated after a number
+	// of consecutive fixes that are close to each other
+	if len(l.fixes) > 0 && l.fixes[len(l.fixes)-1].Distance(*fix) > LocationAccuracy {
+		l.fixes = l.fixes[:0]
+	}
+	l.fixes = append(l.fixes, *fix)
+	if len(l.fixes) > LocationFixes {
+		l.fixes = l.fixes[len(l.fixes)-LocationFixes:]
+	}
+	if len(l.fixes) < LocationFixes {
+		return nil
+	}
+
+	location := averageLocation(l.fixes)
+	l.current = &location
+
+	if len(l.history) == 0 || l.history[len(l.history)-1].Distance(location) > LocationAccuracy {
+		l.history = append(l.history, location)
+		if len(l.history) > LocationHistorySize {
+			l.history = l.history[len(l.history)-LocationHistorySize:]
+		}
+	}
+
+	if l.reference == nil {
+		l.reference = &location
+		return nil
+	}
+
+	returned := false
+	if l.moved != nil {
+		if distance := l.moved.From.Distance(location); distance <= MovementRadius {
+			l.moved, returned = nil, true
+		} else {
+			l.moved.To, l.moved.Distance = location, distance
+		}
+	}
+
+	distance := l.reference.Distance(location)
+	if MovementRadius == 0 || distance <= MovementRadius {
+		return nil
+	}
+	alert := LocationAlert{
+		From:     *l.reference,
+		To:       location,
+		Distance: distance,
+		Time:     t,
+	}
+	l.alerts = append(l.alerts, alert)
+	if len(l.alerts) > LocationHistorySize {
+		l.alerts = l.alerts[len(l.alerts)-LocationHistorySize:]
+	}
+	l.reference = &location // Only alert once for every move
+	if l.moved == nil && !returned {
+		moved := alert
+		l.moved = &moved
+	}
+	return &alert
+}
+
+func averageLocation(fixes []Location) Location {
+	var lat, lon, alt float64
+	for _, fix := range fixes {
+		lat += float64(fix.Latitude)
+		lon += float64(fix.Longitude)
+		alt += float64(fix.Altitude)
+	}
+	n := float64(len(fixes))
+	return Location{
+		Latitude:  float32(lat / n),
+		Longitude: float32(lon / n),
+		Altitude:  int32(alt / n),
+		Time:      fixes[len(fixes)-1].Time,
+	}
+}
+
+func (l *locationTracker) Get() (*Location, bool) {
+	l.RLock()
+	defer l.RUnlock()
+	if l.current == nil {
+		return nil, false
+	}
+	location := *l.current
+	return &location, true
+}
+
+func (l *locationTracker) History() []Location {
+	l.RLock()
+	defer l.RUnlock()
+	return append([]Location(nil), l.history...)
+}
+
+func (l *locationTracker) Alerts() []LocationAlert {
+	l.RLock()
+	defer l.RUnlock()
+	return append([]LocationAlert(nil), l.alerts...)
+}
+
+func (l *locationTracker) Moved() (*LocationAlert, bool) {
+	l.RLock()
+	defer l.RUnlock()
+	if l.moved == nil {
+		return nil, false
+	}
+	moved := *l.moved
+	return &moved, true
+}
@@ -0,0 +1,131 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/alerting"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestLocationFromGPS(t *testing.T) {
+	a := New(t)
+	a.So(LocationFromGPS(nil, time.Now()), ShouldBeNil)
+	a.So(LocationFromGPS(&pb.GPSMetadata{}, time.Now()), ShouldBeNil)
+	a.So(LocationFromGPS(&pb.GPSMetadata{Latitude: 100, Longitude: 4.89}, time.Now()), ShouldBeNil)
+	a.So(LocationFromGPS(&pb.GPSMetadata{Latitude: 52.37, Longitude: 4.89}, time.Now()), ShouldNotBeNil)
+}
+
+func TestLocationDistance(t *testing.T) {
+	a := New(t)
+	amsterdam := Location{Latitude: 52.3702, Longitude: 4.8952}
+	rotterdam := Location{Latitude: 51.9244, Longitude: 4.4777}
+	a.So(amsterdam.Distance(amsterdam), ShouldEqual, 0)
+	a.So(amsterdam.Distance(rotterdam), ShouldAlmostEqual, 57000, 1000)
+}
+
+func TestLocationTracker(t *testing.T) {
+	a := New(t)
+	tracker := NewLocationTracker()
+
+	now := time.Now()
+	home := &pb.GPSMetadata{Latitude: 52.3702, Longitude: 4.8952, Altitude: 10}
+	nearby := &pb.GPSMetadata{Latitude: 52.3703, Longitude: 4.8953, Altitude: 12}
+	away := &pb.GPSMetadata{Latitude: 51.9244, Longitude: 4.4777, Altitude: 5}
+
+	// Invalid fixes are ignored
+	a.So(tracker.Update(nil, now), ShouldBeNil)
+	a.So(tracker.Update(&pb.GPSMetadata{}, now), ShouldBeNil)
+
+	// The location is only known after enough accurate fixes
+	for i := 0; i < LocationFixes-1; i++ {
+		a.So(tracker.Update(home, now), ShouldBeNil)
+	}
+	_, ok := tracker.Get()
+	a.So(ok, ShouldBeFalse)
+
+	// A single inaccurate fix resets the filter
+	a.So(tracker.Update(away, now), ShouldBeNil)
+	a.So(tracker.Update(home, now), ShouldBeNil)
+	_, ok = tracker.Get()
+	a.So(ok, ShouldBeFalse)
+
+	for i := 0; i < LocationFixes-1; i++ {
+		a.So(tracker.Update(home, now), ShouldBeNil)
+	}
+	location, ok := tracker.Get()
+	a.So(ok, ShouldBeTrue)
+	a.So(location.Latitude, ShouldEqual, home.Latitude)
+	a.So(tracker.History(), ShouldHaveLength, 1)
+
+	// Small movements are averaged and don't add to the history
+	a.So(tracker.Update(nearby, now), ShouldBeNil)
+	location, _ = tracker.Get()
+	a.So(location.Distance(Location{Latitude: home.Latitude, Longitude: home.Longitude}), ShouldBeLessThan, LocationAccuracy)
+	a.So(tracker.History(), ShouldHaveLength, 1)
+
+	// Moving beyond the radius emits an alert once
+	var alerts []*LocationAlert
+	for i := 0; i < LocationFixes+2; i++ {
+		if alert := tracker.Update(away, now.Add(time.Hour)); alert != nil {
+			alerts = append(alerts, alert)
+		}
+	}
+	a.So(alerts, ShouldHaveLength, 1)
+	a.So(alerts[0].Distance, ShouldBeGreaterThan, MovementRadius)
+	a.So(alerts[0].From.Latitude, ShouldAlmostEqual, home.Latitude, 0.001)
+	a.So(alerts[0].To.Latitude, ShouldAlmostEqual, away.Latitude, 0.001)
+	a.So(tracker.Alerts(), ShouldHaveLength, 1)
+	a.So(tracker.History(), ShouldHaveLength, 2)
+	moved, ok := tracker.Moved()
+	a.So(ok, ShouldBeTrue)
+	a.So(moved.From.Latitude, ShouldAlmostEqual, home.Latitude, 0.001)
+
+	// Moving back resets the movement
+	for i := 0; i < LocationFixes; i++ {
+		tracker.Update(home, now.Add(2*time.Hour))
+	}
+	_, ok = tracker.Moved()
+	a.So(ok, ShouldBeFalse)
+}
+
+func TestGatewayLocation(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayLocation"), "eui-0102030405060708")
+	gtw.Alerts = alerting.NewManager(GetLogger(t, "TestGatewayLocation"), "router")
+
+	gps := &pb.GPSMetadata{Latitude: 52.3702, Longitude: 4.8952}
+	for i := 0; i < LocationFixes; i++ {
+		a.So(gtw.HandleStatus(&pb.Status{Gps: gps}), ShouldBeNil)
+	}
+	location, ok := gtw.Location.Get()
+	a.So(ok, ShouldBeTrue)
+	a.So(location.Latitude, ShouldEqual, gps.Latitude)
+
+	// The tracked location is injected in uplink messages
+	up := buildUplink(868100000)
+	a.So(gtw.HandleUplink(up), ShouldBeNil)
+	a.So(up.GatewayMetadata.Gps, ShouldNotBeNil)
+	a.So(up.GatewayMetadata.Gps.Latitude, ShouldEqual, gps.Latitude)
+
+	// Moving beyond the radius fires an alert
+	away := &pb.GPSMetadata{Latitude: 51.9244, Longitude: 4.4777}
+	for i := 0; i < LocationFixes; i++ {
+		a.So(gtw.HandleStatus(&pb.Status{Gps: away}), ShouldBeNil)
+	}
+	firing := gtw.Alerts.Firing()
+	a.So(firing, ShouldHaveLength, 1)
+	a.So(firing[0].Kind, ShouldEqual, alerting.GatewayMoved)
+	a.So(firing[0].Subject, ShouldEqual, gtw.ID)
+
+	// Moving back resolves the alert
+	for i := 0; i < LocationFixes; i++ {
+		a.So(gtw.HandleStatus(&pb.Status{Gps: gps}), ShouldBeNil)
+	}
+	a.So(gtw.Alerts.Firing(), ShouldBeEmpty)
+}
@@ -0,0 +1,97 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"sync"
+	"time"
+)
+
+// MaxRTTAge is the time after which the round-trip time of the backhaul of a
+// gateway is no longer used if it was not measured again
+var MaxRTTAge = 5 * time.Minute
+
+// MaxBackhaulDelay is the longest delay of a message of a gateway that is used
+// to measure the round-trip time of its backhaul. Messages that took longer
+// were buffered by the gateway or its bridge.
+var MaxBackhaulDelay = 10 * time.Second
+
+// RTTStats describe the round-trip time of the backhaul of a gateway, as
+// measured by the Router from the delay of the messages of the gateway
+type RTTStats struct {
+	Samples int `json:"samples"`
+	// Last is the last measured round-trip time
+	Last time.Duration `json:"last"`
+	// Smoothed is the exponentially weighted moving average of the round-trip time
+	Smoothed time.Duration `json:"smoothed"`
+	// Deviation is the exponentially weighted moving average of the deviation from Smoothed
+	Deviation time.Duration `json:"deviation"`
+	LastSeen  time.Time     `json:"last_seen,omitempty"`
+}
+
+// Estimate returns a conservative estimate of the round-trip time, which
+// covers most of the variation of the backhaul (like the TCP retransmission timeout)
+func (s RTTStats) Estimate() time.Duration {
+	return s.Smoothed + 4*s.Deviation
+}
+
+type rtt struct {
+	sync.RWMutex
+	stats RTTStats
+}
+
+// add a round-trip time measurement, using the same smoothing as TCP (RFC 6298)
+func (r *rtt) add(sample time.Duration, now time.Time) {
+	r.Lock()
+	defer r.Unlock()
+	if r.stats.Samples == 0 || now.Sub(r.stats.LastSeen) > MaxRTTAge {
+		r.stats = RTTStats{Smoothed: sample, Deviation: sample / 2}
+	} else {
+		diff := r.stats.Smoothed - sample
+		if diff < 0 {
+			diff = -diff
+		}
+		r.stats.Deviation = (3*r.stats.Deviation + diff) / 4
+		r.stats.Smoothed = (7*r.stats.Smoothed + sample) / 8
+	}
+	r.stats.Samples++
+	r.stats.Last = sample
+	r.stats.LastSeen = now
+}
+
+func (r *rtt) get() RTTStats {
+	r.RLock()
+	defer r.RUnlock()
+	return r.stats
+}
+
+// measureRTT measures the round-trip time of the backhaul from the time that a
+// message of the gateway was received or sent according to the clock of the
+// gateway and the time that the Router received it. Downlink messages are
+// expected to take as long as uplink messages.
+func (g *Gateway) measureRTT(gatewayTime int64, now time.Time) {
+	if gatewayTime == 0 {
+		return
+	}
+	delay := now.Sub(time.Unix(0, gatewayTime))
+	if delay < 0 || delay > MaxBackhaulDelay {
+		return // The clock of the gateway is ahead, or the message was buffered
+	}
+	g.rtt.add(2*delay, now)
+}
+
+// RTTStats returns the round-trip time statistics of the backhaul of the gateway
+func (g *Gateway) RTTStats() RTTStats {
+	return g.rtt.get()
+}
+
+// RTT returns the estimated round-trip time of the backhaul of the gateway. It
+// returns false if the round-trip time was not measured recently.
+func (g *Gateway) RTT(now time.Time) (time.Duration, bool) {
+	stats := g.rtt.get()
+	if stats.Samples == 0 || now.Sub(stats.LastSeen) > MaxRTTAge {
+		return 0, false
+	}
+	return stats.Estimate(), true
+}
@@ -0,0 +1,79 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestRTT(t *testing.T) {
+	a := New(t)
+	now := time.Now()
+
+	r := &rtt{}
+	r.add(100*time.Millisecond, now)
+	stats := r.get()
+	a.So(stats.Samples, ShouldEqual, 1)
+	a.So(stats.Smoothed, ShouldEqual, 100*time.Millisecond)
+	a.So(stats.Deviation, ShouldEqual, 50*time.Millisecond)
+
+	// A stable backhaul converges to its round-trip time
+	for i := 0; i < 50; i++ {
+		r.add(100*time.Millisecond, now)
+	}
+	a.So(r.get().Estimate(), ShouldAlmostEqual, 100*time.Millisecond, time.Millisecond)
+
+	// Spikes increase the estimate more than the average
+	r.add(900*time.Millisecond, now)
+	stats = r.get()
+	a.So(stats.Last, ShouldEqual, 900*time.Millisecond)
+	a.So(stats.Smoothed, ShouldEqual, 200*time.Millisecond)
+	a.So(stats.Estimate(), ShouldBeGreaterThan, 900*time.Millisecond)
+
+	// Old measurements are discarded
+	r.add(300*time.Millisecond, now.Add(2*MaxRTTAge))
+	stats = r.get()
+	a.So(stats.Samples, ShouldEqual, 1)
+	a.So(stats.Smoothed, ShouldEqual, 300*time.Millisecond)
+}
+
+func TestGatewayRTT(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayRTT"), "test")
+
+	_, ok := gtw.RTT(time.Now())
+	a.So(ok, ShouldBeFalse)
+
+	// Messages without the time of the gateway can not be measured
+	a.So(gtw.HandleStatus(&pb.Status{}), ShouldBeNil)
+	_, ok = gtw.RTT(time.Now())
+	a.So(ok, ShouldBeFalse)
+
+	// Clocks that are ahead and buffered messages are not measured
+	gtw.measureRTT(time.Now().Add(time.Second).UnixNano(), time.Now())
+	gtw.measureRTT(time.Now().Add(-2*MaxBackhaulDelay).UnixNano(), time.Now())
+	_, ok = gtw.RTT(time.Now())
+	a.So(ok, ShouldBeFalse)
+
+	a.So(gtw.HandleStatus(&pb.Status{Time: time.Now().Add(-100 * time.Millisecond).UnixNano()}), ShouldBeNil)
+	rtt, ok := gtw.RTT(time.Now())
+	a.So(ok, ShouldBeTrue)
+	a.So(rtt, ShouldAlmostEqual, 600*time.Millisecond, 50*time.Millisecond)
+	a.So(gtw.RTTStats().Last, ShouldAlmostEqual, 200*time.Millisecond, 25*time.Millisecond)
+
+	_, ok = gtw.RTT(time.Now().Add(2 * MaxRTTAge))
+	a.So(ok, ShouldBeFalse)
+
+	// Uplink messages are measured from the time that the gateway received them
+	gtw = NewGateway(GetLogger(t, "TestGatewayRTT"), "test")
+	up := buildUplink(868100000)
+	up.GatewayMetadata.Time = time.Now().Add(-50 * time.Millisecond).UnixNano()
+	a.So(gtw.HandleUplink(up), ShouldBeNil)
+	a.So(gtw.RTTStats().Last, ShouldAlmostEqual, 100*time.Millisecond, 25*time.Millisecond)
+}
@@ -13,6 +13,7 @@ import (
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	router_pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/TheThingsNetwork/ttn/utils/random"
 	"github.com/TheThingsNetwork/ttn/utils/toa"
@@ -27,6 +28,10 @@ type Schedule interface {
 	GetOption(timestamp uint32, length uint32) (id string, score uint)
 	// Schedule a transmission on a slot
 	Schedule(id string, downlink *router_pb.DownlinkMessage) error
+	// Notify calls dropped if the transmission on a slot is dropped or preempted after it was scheduled
+	Notify(id string, dropped func(failure types.DownlinkFailure))
+	// NotifySent calls sent when the transmission on a slot is sent to the gateway
+	NotifySent(id string, sent func())
 	// Subscribe to downlink messages
 	Subscribe(subscriptionID string) <-chan *router_pb.DownlinkMessage
 	// Whether the gateway has active downlink
@@ -64,12 +69,62 @@ func NewSchedule(ctx ttnlog.Interface) Schedule {
 }
 
 type scheduledItem struct {
-	id         string
-	deadlineAt time.Time
-	timestamp  uint32
-	length     uint32
-	score      uint
-	payload    *router_pb.DownlinkMessage
+	id          string
+	deadlineAt  time.Time
+	timestamp   uint32
+	length      uint32
+	score       uint
+	payload     *router_pb.DownlinkMessage
+	priority    types.DownlinkPriority
+	preempted   bool
+	preemptedBy types.DownlinkPriority
+	dropped     func(failure types.DownlinkFailure)
+	sent        func()
+}
+
+// drop notifies that the item will not be sent
+func (i *scheduledItem) drop(reason types.DownlinkFailureReason, err error) {
+	if i.dropped != nil {
+		go i.dropped(types.DownlinkFailure{
+			Reason:      reason,
+			Error:       err.Error(),
+			PreemptedBy: i.preemptedBy,
+		})
+	}
+}
+
+// send notifies that the item was sent to the gateway
+func (i *scheduledItem) send() {
+	if i.sent != nil {
+		go i.sent()
+	}
+}
+
+// downlinkPriority returns the priority that the Handler or NetworkServer
+// set on the downlink
+func downlinkPriority(downlink *router_pb.DownlinkMessage) types.DownlinkPriority {
+	if downlink.Priority == "" {
+		return types.DownlinkPriorityNormal
+	}
+	return types.DownlinkPriority(downlink.Priority)
+}
+
+// overlaps returns true if the item overlaps with the slot at timestamp for
+// the duration of length (both in microseconds)
+func (i *scheduledItem) overlaps(timestamp uint32, length uint32) bool {
+	scheduledFrom := uint64(i.timestamp) % uintmax
+	scheduledTo := scheduledFrom + uint64(i.length)
+	from := uint64(timestamp)
+	to := from + uint64(length)
+
+	if scheduledTo > uintmax || to > uintmax {
+		if scheduledTo-uintmax <= from || scheduledFrom >= to-uintmax {
+			return false
+		}
+	} else if scheduledTo <= from || scheduledFrom >= to {
+		return false
+	}
+	return true
 }
 
 type schedule struct {
@@ -104,16 +159,7 @@ func (s *schedule) getConflicts(timestamp uint32, length uint32) (conflicts uint
 	s.RLock()
 	defer s.RUnlock()
 	for _, item := range s.items {
-		scheduledFrom := uint64(item.timestamp) % uintmax
-		scheduledTo := scheduledFrom + uint64(item.length)
-		from := uint64(timestamp)
-		to := from + uint64(length)
-
-		if scheduledTo > uintmax || to > uintmax {
-			if scheduledTo-uintmax <= from || scheduledFrom >= to-uintmax {
-				continue
-			}
-		} else if scheduledTo <= from || scheduledFrom >= to {
+		if !item.overlaps(timestamp, length) {
 			continue
 		}
 
@@ -126,6 +172,42 @@ func (s *schedule) getConflicts(timestamp uint32, length uint32) (conflicts uint
 	return
 }
 
+// preempt resolves the conflicts between the item and the downlinks that are
+// already scheduled in the same slot. Downlinks with a lower priority are
+// preempted by the item, and the item is preempted if a downlink with a higher
+// priority is scheduled. Downlinks with the same priority are all sent.
+// Preempted downlinks are dropped, which notifies the Router right away.
+// The schedule should be locked when calling this func.
+func (s *schedule) preempt(item *scheduledItem) {
+	var lower []*scheduledItem
+	for _, other := range s.items {
+		if other == item || other.payload == nil || other.preempted || !other.overlaps(item.timestamp, item.length) {
+			continue
+		}
+		if time.Now().After(other.deadlineAt) {
+			continue // Already sent to the gateway
+		}
+		switch {
+		case other.priority.Level() > item.priority.Level():
+			item.preempted = true
+			s.reportPreemption(item, other.priority)
+			return
+		case other.priority.Level() < item.priority.Level():
+			lower = append(lower, other)
+		}
+	}
+	for _, other := range lower {
+		other.preempted = true
+		s.reportPreemption(other, item.priority)
+	}
+}
+
+func (s *schedule) reportPreemption(item *scheduledItem, by types.DownlinkPriority) {
+	s.ctx.WithField("Identifier", item.id).WithField("Priority", item.priority).WithField("PreemptedBy", by).Info("Preempted downlink")
+	item.preemptedBy = by
+	item.drop(types.DownlinkFailurePreempted, fmt.Errorf("Downlink was preempted by a downlink with %s priority", by))
+}
+
 // realtime gets the synchronized time for a timestamp (in microseconds). Time
 // should first be syncronized using func Sync()
 func (s *schedule) realtime(timestamp uint32) (t time.Time) {
@@ -168,6 +250,7 @@ func (s *schedule) Schedule(id string, downlink *router_pb.DownlinkMessage) erro
 	defer s.Unlock()
 	if item, ok := s.items[id]; ok {
 		item.payload = downlink
+		item.priority = downlinkPriority(downlink)
 
 		if lorawan := downlink.GetProtocolConfiguration().GetLorawan(); lorawan != nil {
 			var time time.Duration
@@ -189,6 +272,11 @@ func (s *schedule) Schedule(id string, downlink *router_pb.DownlinkMessage) erro
 			item.length = uint32(time / 1000)
 		}
 
+		s.preempt(item)
+		if item.preempted {
+			return errors.NewErrPermissionDenied("Downlink was preempted by a downlink with a higher priority")
+		}
+
 		if time.Now().Before(item.deadlineAt) {
 			// Schedule transmission before the Deadline
 			go func() {
@@ -198,25 +286,40 @@ func (s *schedule) Schedule(id string, downlink *router_pb.DownlinkMessage) erro
 				<-time.After(waitTime)
 				s.RLock()
 				defer s.RUnlock()
+				if item.preempted {
+					ctx.Debug("Discard Preempted Downlink")
+					return
+				}
 				if s.downlink != nil {
 					s.downlink <- item.payload
+					item.send()
+				} else {
+					ctx.Warn("Unable to send Downlink")
+					item.drop(types.DownlinkFailureNoGateway, errors.New("Gateway is not connected"))
 				}
 			}()
 		} else {
 			go func() {
 				s.RLock()
 				defer s.RUnlock()
+				if item.preempted {
+					ctx.Debug("Discard Preempted Downlink")
+					return
+				}
 				if s.downlink != nil {
 					overdue := time.Now().Sub(item.deadlineAt)
 					if overdue < Deadline {
 						// Immediately send it
 						ctx.WithField("Overdue", overdue).Warn("Send Late Downlink")
 						s.downlink <- item.payload
+						item.send()
 					} else {
 						ctx.WithField("Overdue", overdue).Warn("Discard Late Downlink")
+						item.drop(types.DownlinkFailureTooLate, fmt.Errorf("Downlink arrived %s after the deadline", overdue))
 					}
 				} else {
 					ctx.Warn("Unable to send Downlink")
+					item.drop(types.DownlinkFailureNoGateway, errors.New("Gateway is not connected"))
 				}
 			}()
 		}
@@ -226,6 +329,24 @@ func (s *schedule) Schedule(id string, downlink *router_pb.DownlinkMessage) erro
 	return errors.NewErrNotFound(id)
 }
 
+// see interface
+func (s *schedule) Notify(id string, dropped func(failure types.DownlinkFailure)) {
+	s.Lock()
+	defer s.Unlock()
+	if item, ok := s.items[id]; ok {
+		item.dropped = dropped
+	}
+}
+
+// see interface
+func (s *schedule) NotifySent(id string, sent func()) {
+	s.Lock()
+	defer s.Unlock()
+	if item, ok := s.items[id]; ok {
+		item.sent = sent
+	}
+}
+
 func (s *schedule) Stop(subscriptionID string) {
 	s.downlinkSubscriptionsLock.Lock()
 	defer s.downlinkSubscriptionsLock.Unlock()
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	router_pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/types"
 	. "github.com/TheThingsNetwork/ttn/utils/testing"
 	. "github.com/smartystreets/assertions"
 )
@@ -118,6 +119,68 @@ func TestScheduleSchedule(t *testing.T) {
 	a.So(conflicts, ShouldEqual, 100)
 }
 
+func buildPriorityDownlink(priority types.DownlinkPriority) *router_pb.DownlinkMessage {
+	return &router_pb.DownlinkMessage{Priority: string(priority)}
+}
+
+func TestSchedulePreempt(t *testing.T) {
+	a := New(t)
+	s := NewSchedule(GetLogger(t, "TestSchedulePreempt")).(*schedule)
+	s.Sync(0)
+
+	preempted := make(chan types.DownlinkFailure, 1)
+	notify := func(id string) {
+		s.Notify(id, func(failure types.DownlinkFailure) {
+			preempted <- failure
+		})
+	}
+	expectPreemption := func(by types.DownlinkPriority) {
+		select {
+		case failure := <-preempted:
+			a.So(failure.Reason, ShouldEqual, types.DownlinkFailurePreempted)
+			a.So(failure.PreemptedBy, ShouldEqual, by)
+		case <-time.After(time.Second):
+			t.Fatal("Preemption was not reported")
+		}
+	}
+
+	lowID, _ := s.GetOption(5000000, 100)
+	notify(lowID)
+	a.So(s.Schedule(lowID, buildPriorityDownlink(types.DownlinkPriorityLow)), ShouldBeNil)
+
+	// Conflicting downlink with higher priority preempts the scheduled downlink
+	normalID, _ := s.GetOption(5000000, 100)
+	a.So(s.Schedule(normalID, &router_pb.DownlinkMessage{}), ShouldBeNil)
+	a.So(s.items[lowID].preempted, ShouldBeTrue)
+	expectPreemption(types.DownlinkPriorityNormal)
+
+	// Conflicting downlink with lower priority is preempted itself
+	otherLowID, _ := s.GetOption(5000000, 100)
+	notify(otherLowID)
+	a.So(s.Schedule(otherLowID, buildPriorityDownlink(types.DownlinkPriorityLow)), ShouldNotBeNil)
+	a.So(s.items[normalID].preempted, ShouldBeFalse)
+	expectPreemption(types.DownlinkPriorityNormal)
+
+	// Downlinks with the same priority are both sent
+	otherNormalID, _ := s.GetOption(5000000, 100)
+	a.So(s.Schedule(otherNormalID, buildPriorityDownlink(types.DownlinkPriorityNormal)), ShouldBeNil)
+	a.So(s.items[normalID].preempted, ShouldBeFalse)
+	a.So(s.items[otherNormalID].preempted, ShouldBeFalse)
+
+	// MAC downlinks preempt everything
+	notify(normalID)
+	macID, _ := s.GetOption(5000000, 100)
+	a.So(s.Schedule(macID, buildPriorityDownlink(types.DownlinkPriorityMAC)), ShouldBeNil)
+	a.So(s.items[normalID].preempted, ShouldBeTrue)
+	a.So(s.items[otherNormalID].preempted, ShouldBeTrue)
+	expectPreemption(types.DownlinkPriorityMAC)
+
+	// Downlinks in other slots are not affected
+	laterID, _ := s.GetOption(6000000, 100)
+	a.So(s.Schedule(laterID, buildPriorityDownlink(types.DownlinkPriorityLow)), ShouldBeNil)
+	a.So(s.items[laterID].preempted, ShouldBeFalse)
+}
+
 func TestScheduleSubscribe(t *testing.T) {
 	a := New(t)
 	s := NewSchedule(GetLogger(t, "TestScheduleSubscribe")).(*schedule)
@@ -158,3 +221,62 @@ func TestScheduleSubscribe(t *testing.T) {
 	<-time.After(500 * time.Millisecond)
 
 }
+
+func TestScheduleNotify(t *testing.T) {
+	a := New(t)
+	s := NewSchedule(GetLogger(t, "TestScheduleNotify")).(*schedule)
+	s.Sync(0)
+
+	dropped := make(chan types.DownlinkFailureReason, 1)
+	id, _ := s.GetOption(0, 100)
+	s.Notify(id, func(failure types.DownlinkFailure) {
+		dropped <- failure.Reason
+	})
+	a.So(s.Schedule(id, &router_pb.DownlinkMessage{}), ShouldBeNil)
+
+	// Nobody is subscribed to the downlink of the gateway
+	select {
+	case reason := <-dropped:
+		a.So(reason, ShouldEqual, types.DownlinkFailureNoGateway)
+	case <-time.After(time.Second):
+		t.Fatal("Downlink was not dropped")
+	}
+}
+
+func TestScheduleNotifySent(t *testing.T) {
+	a := New(t)
+	s := NewSchedule(GetLogger(t, "TestScheduleNotifySent")).(*schedule)
+	s.Sync(0)
+	Deadline = 1 * time.Millisecond // Very short deadline
+
+	downlinks := s.Subscribe("")
+	defer s.Stop("")
+
+	sent := make(chan string, 2)
+	notify := func(id string) {
+		s.NotifySent(id, func() {
+			sent <- id
+		})
+	}
+
+	// Preempted downlinks are not sent
+	preemptedID, _ := s.GetOption(20000, 50)
+	notify(preemptedID)
+	a.So(s.Schedule(preemptedID, buildPriorityDownlink(types.DownlinkPriorityLow)), ShouldBeNil)
+	id, _ := s.GetOption(20000, 50)
+	notify(id)
+	a.So(s.Schedule(id, &router_pb.DownlinkMessage{}), ShouldBeNil)
+
+	select {
+	case <-downlinks:
+	case <-time.After(time.Second):
+		t.Fatal("Downlink was not sent")
+	}
+	select {
+	case sentID := <-sent:
+		a.So(sentID, ShouldEqual, id)
+	case <-time.After(time.Second):
+		t.Fatal("Sent downlink was not notified")
+	}
+	a.So(sent, ShouldBeEmpty)
+}
@@ -0,0 +1,169 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// MaxTimeSyncSamples is the number of uplink messages that is used to
+// determine the synchronization of the concentrator clock of a gateway
+var MaxTimeSyncSamples = 50
+
+// MinTimeSyncSamples is the number of uplink messages that is needed to
+// determine the drift and jitter of the concentrator clock of a gateway
+var MinTimeSyncSamples = 3
+
+// MaxClockDrift is the maximum drift (in parts per million) of the concentrator
+// clock of a gateway for its timestamps to be reliable for Class B
+var MaxClockDrift = 20.0
+
+// MaxGeolocationJitter is the maximum jitter between the concentrator clock and
+// the GPS time of a gateway for its timestamps to be reliable for geolocation
+var MaxGeolocationJitter = 1 * time.Microsecond
+
+// maxTimestampDeviation is the maximum difference between the time that
+// passed according to the concentrator clock and according to the reference
+// clock. If it is exceeded, the concentrator was restarted.
+var maxTimestampDeviation = 1 * time.Second
+
+// TimeSyncStats describe the synchronization of the concentrator clock of a
+// gateway. The reference clock is the GPS time of the gateway if the last
+// uplink messages contained it, or the time of the server otherwise.
+type TimeSyncStats struct {
+	Samples int  `json:"samples"`
+	GPS     bool `json:"gps"`
+	// Offset is the server time minus the GPS time of the gateway at the last uplink
+	Offset time.Duration `json:"offset,omitempty"`
+	// Drift of the concentrator clock relative to the reference clock in parts per million
+	Drift float64 `json:"drift"`
+	// Jitter is the standard deviation of the concentrator clock from the reference clock after correcting for drift
+	Jitter   time.Duration `json:"jitter"`
+	LastSync time.Time     `json:"last_sync,omitempty"`
+	// Geolocation is true if the timestamps of the gateway are reliable for geolocation
+	Geolocation bool `json:"geolocation"`
+	// ClassB is true if the timestamps of the gateway are reliable for Class B beacons and ping slots
+	ClassB bool `json:"class_b"`
+}
+
+type timeSyncSample struct {
+	timestamp int64 // Concentrator clock without rollover in microseconds
+	server    time.Time
+	gps       time.Time // Zero if the uplink did not contain GPS time
+}
+
+// reference returns the time of the sample according to the reference clock
+func (s timeSyncSample) reference(gps bool) time.Time {
+	if gps {
+		return s.gps
+	}
+	return s.server
+}
+
+type timeSync struct {
+	sync.RWMutex
+	samples []timeSyncSample
+	last    uint32
+}
+
+// add a sample of the concentrator timestamp of an uplink message, the time
+// that the server received it and the GPS time of the gateway, if any
+func (s *timeSync) add(timestamp uint32, server time.Time, gps time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	sample := timeSyncSample{timestamp: int64(timestamp), server: server, gps: gps}
+	if len(s.samples) > 0 {
+		previous := s.samples[len(s.samples)-1]
+		elapsed := time.Duration(timestamp-s.last) * time.Microsecond
+		if behind := time.Duration(int32(s.last-timestamp)) * time.Microsecond; behind > 0 && behind < maxTimestampDeviation {
+			return // Uplink messages of the same gateway were handled out of order
+		}
+		referenceElapsed := server.Sub(previous.server)
+		if !gps.IsZero() && !previous.gps.IsZero() {
+			referenceElapsed = gps.Sub(previous.gps)
+		}
+		if deviation := elapsed - referenceElapsed; deviation > maxTimestampDeviation || deviation < -maxTimestampDeviation {
+			s.samples = s.samples[:0] // The concentrator was restarted
+		} else {
+			sample.timestamp = previous.timestamp + int64(elapsed/time.Microsecond)
+		}
+	}
+	s.last = timestamp
+	s.samples = append(s.samples, sample)
+	if len(s.samples) > MaxTimeSyncSamples {
+		s.samples = s.samples[len(s.samples)-MaxTimeSyncSamples:]
+	}
+}
+
+// get the synchronization statistics
+func (s *timeSync) get() (stats TimeSyncStats) {
+	s.RLock()
+	defer s.RUnlock()
+	if len(s.samples) == 0 {
+		return
+	}
+	last := s.samples[len(s.samples)-1]
+	stats.LastSync = last.server
+	stats.GPS = !last.gps.IsZero()
+	samples := s.samples
+	if stats.GPS {
+		// Only use the last samples with GPS time
+		first := len(samples) - 1
+		for first > 0 && !samples[first-1].gps.IsZero() {
+			first--
+		}
+		samples = samples[first:]
+		stats.Offset = last.server.Sub(last.gps)
+	}
+	stats.Samples = len(samples)
+
+	// Linear regression of the offset between the reference clock and the concentrator clock
+	var sumX, sumY float64
+	x := make([]float64, len(samples))
+	y := make([]float64, len(samples))
+	for i, sample := range samples {
+		x[i] = float64(sample.timestamp - samples[0].timestamp)
+		y[i] = float64(sample.reference(stats.GPS).Sub(samples[0].reference(stats.GPS))/time.Nanosecond)/1000 - x[i]
+		sumX += x[i]
+		sumY += y[i]
+	}
+	n := float64(len(samples))
+	meanX, meanY := sumX/n, sumY/n
+	var varX, covXY float64
+	for i := range samples {
+		varX += (x[i] - meanX) * (x[i] - meanX)
+		covXY += (x[i] - meanX) * (y[i] - meanY)
+	}
+	if varX == 0 {
+		return
+	}
+	slope := covXY / varX
+	stats.Drift = slope * 1e6
+	var residuals float64
+	for i := range samples {
+		residual := y[i] - meanY - slope*(x[i]-meanX)
+		residuals += residual * residual
+	}
+	stats.Jitter = time.Duration(math.Sqrt(residuals/n) * float64(time.Microsecond))
+
+	enough := stats.Samples >= MinTimeSyncSamples
+	stats.Geolocation = stats.GPS && enough && stats.Jitter <= MaxGeolocationJitter
+	stats.ClassB = stats.GPS && stats.Samples >= 2 && math.Abs(stats.Drift) <= MaxClockDrift && time.Since(stats.LastSync) <= MaxTimeReferenceAge
+	return
+}
+
+// unreliableGPSTime returns true if enough uplink messages with GPS time were
+// received to know that the timestamps of the gateway are not reliable for
+// geolocation
+func (s *timeSync) unreliableGPSTime() bool {
+	stats := s.get()
+	return stats.GPS && stats.Samples >= MinTimeSyncSamples && !stats.Geolocation
+}
+
+// TimeSync returns the synchronization statistics of the concentrator clock of the gateway
+func (g *Gateway) TimeSync() TimeSyncStats {
+	return g.timeSync.get()
+}
@@ -0,0 +1,113 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/TheThingsNetwork/ttn/api/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestTimeSync(t *testing.T) {
+	a := New(t)
+	now := time.Now()
+
+	s := &timeSync{}
+	a.So(s.get().Samples, ShouldEqual, 0)
+
+	// Without GPS the server time is the reference
+	for i := 0; i < 3; i++ {
+		s.add(uint32(i*1000000), now.Add(time.Duration(i)*time.Second), time.Time{})
+	}
+	stats := s.get()
+	a.So(stats.Samples, ShouldEqual, 3)
+	a.So(stats.GPS, ShouldBeFalse)
+	a.So(stats.Drift, ShouldAlmostEqual, 0, 0.001)
+	a.So(stats.Geolocation, ShouldBeFalse)
+	a.So(stats.ClassB, ShouldBeFalse)
+
+	// Concentrator clock that runs 10ppm fast compared to GPS time
+	s = &timeSync{}
+	for i := 0; i < 5; i++ {
+		gps := now.Add(time.Duration(i) * time.Second)
+		s.add(uint32(i*1000010), gps.Add(20*time.Millisecond), gps)
+	}
+	stats = s.get()
+	a.So(stats.Samples, ShouldEqual, 5)
+	a.So(stats.GPS, ShouldBeTrue)
+	a.So(stats.Offset, ShouldEqual, 20*time.Millisecond)
+	a.So(stats.Drift, ShouldAlmostEqual, -10, 0.01)
+	a.So(stats.Jitter, ShouldBeLessThan, time.Microsecond)
+	a.So(stats.Geolocation, ShouldBeTrue)
+	a.So(stats.ClassB, ShouldBeTrue)
+	a.So(s.unreliableGPSTime(), ShouldBeFalse)
+
+	// Concentrator clock that drifts too much for Class B
+	s = &timeSync{}
+	for i := 0; i < 5; i++ {
+		gps := now.Add(time.Duration(i) * time.Second)
+		s.add(uint32(i*1000100), gps, gps)
+	}
+	a.So(s.get().Drift, ShouldAlmostEqual, -100, 0.1)
+	a.So(s.get().ClassB, ShouldBeFalse)
+
+	// GPS time with jitter is not reliable for geolocation
+	s = &timeSync{}
+	for i, jitter := range []time.Duration{0, 10, -10, 10, -10} {
+		gps := now.Add(time.Duration(i)*time.Second + jitter*time.Microsecond)
+		s.add(uint32(i*1000000), gps, gps)
+	}
+	a.So(s.get().Jitter, ShouldBeGreaterThan, MaxGeolocationJitter)
+	a.So(s.get().Geolocation, ShouldBeFalse)
+	a.So(s.unreliableGPSTime(), ShouldBeTrue)
+}
+
+func TestTimeSyncTimestamps(t *testing.T) {
+	a := New(t)
+	now := time.Now()
+
+	// Rollover of the concentrator clock
+	s := &timeSync{}
+	s.add(1<<32-500000, now, now)
+	s.add(500000, now.Add(time.Second), now.Add(time.Second))
+	a.So(s.get().Samples, ShouldEqual, 2)
+	a.So(s.samples[1].timestamp, ShouldEqual, 1<<32+500000)
+
+	// Uplink messages that are handled out of order are ignored
+	s.add(400000, now.Add(900*time.Millisecond), now.Add(900*time.Millisecond))
+	a.So(s.get().Samples, ShouldEqual, 2)
+
+	// Restart of the concentrator
+	s.add(3000000000, now.Add(2*time.Second), now.Add(2*time.Second))
+	a.So(s.get().Samples, ShouldEqual, 1)
+
+	// Uplink messages without GPS time
+	s.add(3001000000, now.Add(3*time.Second), time.Time{})
+	a.So(s.get().GPS, ShouldBeFalse)
+	a.So(s.get().Samples, ShouldEqual, 2)
+}
+
+func TestGatewayTimeSync(t *testing.T) {
+	a := New(t)
+	gtw := NewGateway(GetLogger(t, "TestGatewayTimeSync"), "eui-0102030405060708")
+
+	now := time.Now()
+	uplink := buildUplink(868100000)
+	for i, jitter := range []time.Duration{0, 10, -10, 10} {
+		gps := now.Add(time.Duration(i)*time.Second + jitter*time.Microsecond)
+		uplink = buildUplink(868100000)
+		uplink.GatewayMetadata.Timestamp = uint32(i * 1000000)
+		uplink.GatewayMetadata.Time = gps.UnixNano()
+		uplink.GatewayMetadata.Gps = &pb.GPSMetadata{Time: gps.UnixNano()}
+		a.So(gtw.HandleUplink(uplink), ShouldBeNil)
+	}
+	a.So(gtw.TimeSync().Samples, ShouldEqual, 4)
+	a.So(gtw.TimeSync().Geolocation, ShouldBeFalse)
+
+	// The GPS time is removed so that it is not used for geolocation
+	a.So(uplink.GatewayMetadata.Time, ShouldEqual, 0)
+}
@@ -0,0 +1,17 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package gateway
+
+// TrustLevel of a gateway determines whether its metadata is marked as trusted
+type TrustLevel string
+
+// Trust levels
+const (
+	// TrustAuthenticated trusts a gateway if it authenticates with a valid token
+	TrustAuthenticated TrustLevel = ""
+	// TrustTrusted always trusts a gateway
+	TrustTrusted TrustLevel = "trusted"
+	// TrustUntrusted never trusts a gateway, even if it authenticates
+	TrustUntrusted TrustLevel = "untrusted"
+)
@@ -18,6 +18,7 @@ func buildUplink(freq uint64) *pb.UplinkMessage {
 		Protocol: &pb_protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{
 			DataRate:   "SF7BW125",
 			CodingRate: "4/5",
+			Airtime:    41216000,
 		}},
 	}, GatewayMetadata: &gateway.RxMetadata{
 		Frequency: freq,
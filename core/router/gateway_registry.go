@@ -0,0 +1,77 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/router/frequencyplan"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/router/registry"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// SetGatewayRegistry sets the local gateway registry
+func (r *router) SetGatewayRegistry(store registry.Store) {
+	r.gatewayRegistry = store
+}
+
+// validateGatewayAccessKey returns the gateway in the local gateway registry,
+// or an error if the access key is not valid for the gateway
+func (r *router) validateGatewayAccessKey(gatewayID, key string) (*registry.Gateway, error) {
+	if r.gatewayRegistry == nil {
+		return nil, errors.NewErrPermissionDenied("Gateway access keys are not accepted by this Router")
+	}
+	if err := registry.ValidateKey(r.gatewayRegistry, gatewayID, key); err != nil {
+		return nil, errors.NewErrPermissionDenied(fmt.Sprintf("Gateway access key invalid: %s", err))
+	}
+	return r.gatewayRegistry.Get(gatewayID)
+}
+
+// applyRegisteredGateway applies the frequency plan, location and antennas of
+// the gateway in the local gateway registry. The frequency plan is either the
+// ID of a frequency plan of the Router or a region, such as EU_863_870.
+func (r *router) applyRegisteredGateway(gtw *gateway.Gateway, registered *registry.Gateway) {
+	antennas := make([]gateway.Antenna, 0, len(registered.Antennas))
+	for _, in := range registered.Antennas {
+		antenna := gateway.Antenna{Index: in.Index, Gain: in.Gain}
+		if in.Location != nil {
+			antenna.Latitude = float32(in.Location.Latitude)
+			antenna.Longitude = float32(in.Location.Longitude)
+			antenna.Altitude = in.Location.Altitude
+		}
+		antennas = append(antennas, antenna)
+	}
+	if err := gtw.Antennas.Set(gtw.ID, antennas); err != nil {
+		gtw.Ctx.WithError(err).Warn("Could not set antennas of registered gateway")
+	}
+
+	var registration gateway.Registration
+	if registered.Location != nil {
+		registration.Location = &gateway.Location{
+			Latitude:  float32(registered.Location.Latitude),
+			Longitude: float32(registered.Location.Longitude),
+			Altitude:  registered.Location.Altitude,
+		}
+	}
+	if registered.FrequencyPlan != "" {
+		if _, err := band.Get(registered.FrequencyPlan); err == nil {
+			registration.Region = registered.FrequencyPlan
+		}
+		if r.frequencyPlans != nil {
+			if _, err := r.frequencyPlans.Get(registered.FrequencyPlan); err != nil {
+				if plan, err := frequencyplan.FromBand(registered.FrequencyPlan); err == nil {
+					r.frequencyPlans.Set(plan)
+				}
+			}
+			if err := r.frequencyPlans.Assign(gtw.ID, registered.FrequencyPlan); err != nil {
+				gtw.Ctx.WithError(err).WithField("FrequencyPlan", registered.FrequencyPlan).Warn("Could not assign frequency plan of registered gateway")
+			} else if plan, err := r.frequencyPlans.ForGateway(gtw.ID); err == nil && plan.Region != "" {
+				registration.Region = plan.Region
+			}
+		}
+	}
+	gtw.SetRegistration(registration)
+}
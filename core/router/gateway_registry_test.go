@@ -0,0 +1,66 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/core/router/frequencyplan"
+	"github.com/TheThingsNetwork/ttn/core/router/registry"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGatewayAccessKey(t *testing.T) {
+	a := New(t)
+	r := getTestRouter(t)
+	rpc := &routerRPC{router: r.router}
+
+	store := registry.NewRedisStore(GetRedisClient(), "test-router-gateway-registry")
+	defer store.Delete("test-gateway")
+	key, err := registry.Register(store, registry.Gateway{
+		ID:            "test-gateway",
+		FrequencyPlan: "EU_863_870",
+		Location:      &registry.Location{Latitude: 52.37403, Longitude: 4.88968},
+		Antennas: []registry.Antenna{
+			{Index: 0, Gain: 3},
+			{Index: 1, Gain: 6, Location: &registry.Location{Latitude: 52.3741, Longitude: 4.8897}},
+		},
+	})
+	a.So(err, ShouldBeNil)
+
+	// Without registry
+	_, err = rpc.gatewayFromMetadata(metadata.Pairs("id", "test-gateway", "token", key))
+	a.So(err, ShouldNotBeNil)
+
+	r.SetGatewayRegistry(store)
+	r.frequencyPlans = frequencyplan.NewRegistry()
+
+	gtw, err := rpc.gatewayFromMetadata(metadata.Pairs("id", "test-gateway", "token", key))
+	a.So(err, ShouldBeNil)
+	a.So(gtw.Trusted(), ShouldBeTrue)
+
+	// The frequency plan and location of the registry are applied to the gateway
+	plan, err := r.frequencyPlans.ForGateway("test-gateway")
+	a.So(err, ShouldBeNil)
+	a.So(plan.Region, ShouldEqual, "EU_863_870")
+	a.So(gtw.HandleStatus(&pb_gateway.Status{}), ShouldBeNil)
+	status, _ := gtw.Status.Get()
+	a.So(status.Region, ShouldEqual, "EU_863_870")
+	a.So(status.Gps, ShouldNotBeNil)
+
+	// The antennas of the registry are applied to the gateway
+	antenna, ok := gtw.Antenna(1)
+	a.So(ok, ShouldBeTrue)
+	a.So(antenna.Gain, ShouldEqual, 6)
+	a.So(antenna.HasLocation(), ShouldBeTrue)
+
+	_, err = rpc.gatewayFromMetadata(metadata.Pairs("id", "other-gateway", "token", key))
+	a.So(err, ShouldNotBeNil)
+
+	_, err = rpc.gatewayFromMetadata(metadata.Pairs("id", "test-gateway", "token", registry.AccessKeyPrefix+"invalid"))
+	a.So(err, ShouldNotBeNil)
+}
@@ -0,0 +1,196 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/router/frequencyplan"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
+)
+
+// GatewayAirtimeResponse is returned by the gateway airtime endpoint of the HTTP API
+type GatewayAirtimeResponse struct {
+	GatewayID string                       `json:"gateway_id"`
+	Uplink    toa.Usage                    `json:"uplink"`
+	Downlink  []ApplicationDownlinkAirtime `json:"downlink"`
+}
+
+// ApplicationDownlinkAirtime is the downlink airtime of an application on a
+// gateway, with its daily budget if it has one
+type ApplicationDownlinkAirtime struct {
+	AppID   string        `json:"app_id"`
+	Airtime toa.Usage     `json:"airtime"`
+	Budget  time.Duration `json:"budget,omitempty"`
+}
+
+// GatewayAntennasResponse is returned by the gateway antennas endpoint of the HTTP API
+type GatewayAntennasResponse struct {
+	GatewayID string                 `json:"gateway_id"`
+	Antennas  []gateway.AntennaStats `json:"antennas"`
+}
+
+// GatewayBeaconsResponse is returned by the gateway beacons endpoint of the HTTP API
+type GatewayBeaconsResponse struct {
+	GatewayID string              `json:"gateway_id"`
+	Beacons   gateway.BeaconStats `json:"beacons"`
+}
+
+// GatewayLocationResponse is returned by the gateway location endpoint of the HTTP API
+type GatewayLocationResponse struct {
+	GatewayID string                  `json:"gateway_id"`
+	Location  *gateway.Location       `json:"location,omitempty"`
+	History   []gateway.Location      `json:"history"`
+	Alerts    []gateway.LocationAlert `json:"alerts"`
+}
+
+// GatewayTimeSyncResponse is returned by the gateway time sync endpoint of the HTTP API
+type GatewayTimeSyncResponse struct {
+	GatewayID string                `json:"gateway_id"`
+	TimeSync  gateway.TimeSyncStats `json:"time_sync"`
+}
+
+// TrafficFilterResponse is returned by the traffic filter endpoint of the HTTP API
+type TrafficFilterResponse struct {
+	DevAddrPrefixes  []string               `json:"dev_addr_prefixes"`
+	JoinEUIRanges    []string               `json:"join_eui_ranges"`
+	Stats            []FilterStats          `json:"stats"`
+	JoinRequestRules []JoinRequestRuleStats `json:"join_request_rules"`
+}
+
+type httpHandler struct {
+	router         *router
+	frequencyPlans http.Handler
+}
+
+// HTTPHandler returns a read-only HTTP API for the Router:
+//
+//	GET /gateways/{gateway_id}/airtime   returns the uplink airtime of a gateway and its downlink airtime per application
+//	GET /gateways/{gateway_id}/antennas  returns the antennas of a gateway with their statistics
+//	GET /gateways/{gateway_id}/beacons   returns the statistics of the Class B beacons of a gateway
+//	GET /gateways/{gateway_id}/location  returns the location of a gateway with its history and alerts
+//	GET /gateways/{gateway_id}/time-sync returns the offset, drift and jitter of the concentrator clock of a gateway
+//	GET /filter                          returns the served prefixes and the traffic per prefix and join request rule
+//
+// In addition, it serves the HTTP API of the frequency plans.
+func (r *router) HTTPHandler() http.Handler {
+	return &httpHandler{
+		router:         r,
+		frequencyPlans: frequencyplan.NewHTTPHandler(r.FrequencyPlans()),
+	}
+}
+
+func (h *httpHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	path := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(path) == 1 && path[0] == "filter" {
+		h.serveFilter(res, req)
+		return
+	}
+	if len(path) != 3 || path[0] != "gateways" || (path[2] != "airtime" && path[2] != "antennas" && path[2] != "beacons" && path[2] != "location" && path[2] != "time-sync") {
+		h.frequencyPlans.ServeHTTP(res, req)
+		return
+	}
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.router.gatewaysLock.RLock()
+	gtw, ok := h.router.gateways[path[1]]
+	h.router.gatewaysLock.RUnlock()
+	res.Header().Set("Content-Type", "application/json")
+	switch {
+	case ok && path[2] == "airtime":
+		json.NewEncoder(res).Encode(GatewayAirtimeResponse{
+			GatewayID: gtw.ID,
+			Uplink:    gtw.UplinkAirtime(),
+			Downlink:  h.router.applicationDownlinkAirtime(gtw),
+		})
+	case ok && path[2] == "antennas":
+		json.NewEncoder(res).Encode(GatewayAntennasResponse{
+			GatewayID: gtw.ID,
+			Antennas:  gtw.AntennaStats(),
+		})
+	case ok && path[2] == "beacons":
+		json.NewEncoder(res).Encode(GatewayBeaconsResponse{
+			GatewayID: gtw.ID,
+			Beacons:   gtw.BeaconStats(),
+		})
+	case ok && path[2] == "location":
+		location, _ := gtw.Location.Get()
+		json.NewEncoder(res).Encode(GatewayLocationResponse{
+			GatewayID: gtw.ID,
+			Location:  location,
+			History:   gtw.Location.History(),
+			Alerts:    gtw.Location.Alerts(),
+		})
+	case ok && path[2] == "time-sync":
+		json.NewEncoder(res).Encode(GatewayTimeSyncResponse{
+			GatewayID: gtw.ID,
+			TimeSync:  gtw.TimeSync(),
+		})
+	case path[2] == "antennas" && h.router.antennas != nil && len(h.router.antennas.Get(path[1])) > 0:
+		// Registered antennas are returned, even if the gateway is not connected
+		response := GatewayAntennasResponse{GatewayID: path[1]}
+		for _, antenna := range h.router.antennas.Get(path[1]) {
+			response.Antennas = append(response.Antennas, gateway.AntennaStats{Antenna: antenna})
+		}
+		json.NewEncoder(res).Encode(response)
+	default:
+		res.Header().Del("Content-Type")
+		http.Error(res, fmt.Sprintf("Gateway %s not found", path[1]), http.StatusNotFound)
+	}
+}
+
+// applicationDownlinkAirtime returns the downlink airtime of the applications
+// on the gateway, sorted by application ID
+func (r *router) applicationDownlinkAirtime(gtw *gateway.Gateway) []ApplicationDownlinkAirtime {
+	airtime := gtw.ApplicationDownlinkAirtime()
+	appIDs := make([]string, 0, len(airtime))
+	for appID := range airtime {
+		appIDs = append(appIDs, appID)
+	}
+	sort.Strings(appIDs)
+	response := make([]ApplicationDownlinkAirtime, 0, len(airtime))
+	for _, appID := range appIDs {
+		app := ApplicationDownlinkAirtime{AppID: appID, Airtime: airtime[appID]}
+		if r.downlinkBudgets != nil {
+			app.Budget = r.downlinkBudgets.Get(appID)
+		}
+		response = append(response, app)
+	}
+	return response
+}
+
+func (h *httpHandler) serveFilter(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	response := TrafficFilterResponse{
+		DevAddrPrefixes:  []string{},
+		JoinEUIRanges:    []string{},
+		Stats:            []FilterStats{},
+		JoinRequestRules: []JoinRequestRuleStats{},
+	}
+	if filter := h.router.filter; filter != nil {
+		for _, prefix := range filter.devAddrPrefixes {
+			response.DevAddrPrefixes = append(response.DevAddrPrefixes, prefix.String())
+		}
+		for _, r := range filter.joinEUIRanges {
+			response.JoinEUIRanges = append(response.JoinEUIRanges, r.String())
+		}
+		response.Stats = filter.stats()
+	}
+	if joinFilter := h.router.joinFilter; joinFilter != nil {
+		response.JoinRequestRules = joinFilter.stats()
+	}
+	res.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(res).Encode(response)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/router/frequencyplan"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	a := New(t)
+
+	r := getTestRouter(t)
+	r.frequencyPlans = frequencyplan.NewRegistry()
+	h := r.HTTPHandler()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Frequency plans are still served
+	a.So(get("/frequency-plans").Code, ShouldEqual, http.StatusOK)
+
+	// Unknown gateway
+	a.So(get("/gateways/eui-0102030405060708/airtime").Code, ShouldEqual, http.StatusNotFound)
+
+	gtw := r.getGateway("eui-0102030405060708")
+	err := gtw.HandleUplink(&pb.UplinkMessage{
+		Payload: make([]byte, 10),
+		ProtocolMetadata: &pb_protocol.RxMetadata{Protocol: &pb_protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{
+			DataRate:   "SF7BW125",
+			CodingRate: "4/5",
+		}}},
+		GatewayMetadata: &pb_gateway.RxMetadata{Frequency: 868100000},
+	})
+	a.So(err, ShouldBeNil)
+
+	rec := get("/gateways/eui-0102030405060708/airtime")
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var response GatewayAirtimeResponse
+	a.So(json.Unmarshal(rec.Body.Bytes(), &response), ShouldBeNil)
+	a.So(response.GatewayID, ShouldEqual, "eui-0102030405060708")
+	a.So(response.Uplink.Messages, ShouldEqual, 1)
+	a.So(response.Uplink.Total, ShouldBeGreaterThan, 0)
+
+	// Time synchronization
+	rec = get("/gateways/eui-0102030405060708/time-sync")
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var timeSync GatewayTimeSyncResponse
+	a.So(json.Unmarshal(rec.Body.Bytes(), &timeSync), ShouldBeNil)
+	a.So(timeSync.TimeSync.Samples, ShouldEqual, 1)
+	a.So(timeSync.TimeSync.GPS, ShouldBeFalse)
+
+	// Antennas
+	a.So(get("/gateways/eui-0102030405060708/antennas").Code, ShouldEqual, http.StatusOK)
+	a.So(get("/gateways/eui-0807060504030201/antennas").Code, ShouldEqual, http.StatusNotFound)
+
+	r.antennas = gateway.NewAntennaRegistry()
+	a.So(r.antennas.Set("eui-0807060504030201", []gateway.Antenna{{Index: 0, Gain: 3}, {Index: 1, Gain: 6}}), ShouldBeNil)
+	rec = get("/gateways/eui-0807060504030201/antennas")
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var antennas GatewayAntennasResponse
+	a.So(json.Unmarshal(rec.Body.Bytes(), &antennas), ShouldBeNil)
+	a.So(antennas.GatewayID, ShouldEqual, "eui-0807060504030201")
+	a.So(antennas.Antennas, ShouldHaveLength, 2)
+	a.So(antennas.Antennas[1].Gain, ShouldEqual, 6)
+	a.So(antennas.Antennas[1].Uplinks, ShouldEqual, 0)
+}
@@ -0,0 +1,162 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/rcrowley/go-metrics"
+)
+
+// JoinRequestRule allows or denies join requests by their JoinEUI or DevEUI
+type JoinRequestRule struct {
+	Deny   bool
+	DevEUI bool // The rule matches DevEUIs instead of JoinEUIs
+	EUIs   JoinEUIRange
+}
+
+// ParseJoinRequestRule parses a rule in the format "<allow|deny> <join-eui|dev-eui> <EUIs>",
+// where the EUIs are an EUI, an OUI, a prefix or a range (see ParseJoinEUIRange)
+func ParseJoinRequestRule(ruleString string) (rule JoinRequestRule, err error) {
+	parts := strings.Fields(ruleString)
+	if len(parts) != 3 {
+		return rule, fmt.Errorf("Invalid join request rule %s: expected <allow|deny> <join-eui|dev-eui> <EUIs>", ruleString)
+	}
+	switch parts[0] {
+	case "allow":
+	case "deny":
+		rule.Deny = true
+	default:
+		return rule, fmt.Errorf("Invalid join request rule %s: action must be allow or deny", ruleString)
+	}
+	switch parts[1] {
+	case "join-eui":
+	case "dev-eui":
+		rule.DevEUI = true
+	default:
+		return rule, fmt.Errorf("Invalid join request rule %s: field must be join-eui or dev-eui", ruleString)
+	}
+	rule.EUIs, err = ParseJoinEUIRange(parts[2])
+	return
+}
+
+// Matches returns true if the JoinEUI or DevEUI of a join request is matched by the rule
+func (r JoinRequestRule) Matches(joinEUI types.AppEUI, devEUI types.DevEUI) bool {
+	if r.DevEUI {
+		return r.EUIs.Contains(types.AppEUI(devEUI))
+	}
+	return r.EUIs.Contains(joinEUI)
+}
+
+func (r JoinRequestRule) String() string {
+	action, field := "allow", "join-eui"
+	if r.Deny {
+		action = "deny"
+	}
+	if r.DevEUI {
+		field = "dev-eui"
+	}
+	return fmt.Sprintf("%s %s %s", action, field, r.EUIs)
+}
+
+// JoinRequestRuleStats are the statistics of the join requests that matched a rule
+type JoinRequestRuleStats struct {
+	Rule     string  `json:"rule"`
+	Messages int64   `json:"messages"`
+	Rate1    float64 `json:"rate_1"`
+	Rate5    float64 `json:"rate_5"`
+	Rate15   float64 `json:"rate_15"`
+}
+
+// notAllowedRule is the rule in the statistics of join requests that did not match the allow rules
+const notAllowedRule = "not allowed"
+
+// joinRequestFilter drops join requests of unknown vendors or devices before they are
+// forwarded to the brokers. Join requests that match a deny rule are dropped. If there
+// are allow rules for JoinEUIs or DevEUIs, a join request must also match one of them.
+type joinRequestFilter struct {
+	rules      []JoinRequestRule
+	meters     []metrics.Meter // The meters of the rules
+	notAllowed metrics.Meter
+}
+
+// allow returns true if the join request is allowed, or the rule that dropped it
+func (f *joinRequestFilter) allow(joinEUI types.AppEUI, devEUI types.DevEUI) (string, bool) {
+	if f == nil {
+		return "", true
+	}
+	var hasJoinEUIAllow, hasDevEUIAllow, joinEUIAllowed, devEUIAllowed bool
+	allowed := make([]int, 0, 2)
+	for i, rule := range f.rules {
+		if !rule.Deny {
+			if rule.DevEUI {
+				hasDevEUIAllow = true
+			} else {
+				hasJoinEUIAllow = true
+			}
+		}
+		if !rule.Matches(joinEUI, devEUI) {
+			continue
+		}
+		switch {
+		case rule.Deny:
+			f.meters[i].Mark(1)
+			return rule.String(), false
+		case rule.DevEUI && !devEUIAllowed:
+			devEUIAllowed = true
+			allowed = append(allowed, i)
+		case !rule.DevEUI && !joinEUIAllowed:
+			joinEUIAllowed = true
+			allowed = append(allowed, i)
+		}
+	}
+	if (hasJoinEUIAllow && !joinEUIAllowed) || (hasDevEUIAllow && !devEUIAllowed) {
+		f.notAllowed.Mark(1)
+		return notAllowedRule, false
+	}
+	for _, i := range allowed {
+		f.meters[i].Mark(1)
+	}
+	return "", true
+}
+
+func (f *joinRequestFilter) stats() []JoinRequestRuleStats {
+	if f == nil {
+		return nil
+	}
+	stats := make([]JoinRequestRuleStats, 0, len(f.rules)+1)
+	add := func(rule string, meter metrics.Meter) {
+		snapshot := meter.Snapshot()
+		stats = append(stats, JoinRequestRuleStats{
+			Rule:     rule,
+			Messages: snapshot.Count(),
+			Rate1:    snapshot.Rate1(),
+			Rate5:    snapshot.Rate5(),
+			Rate15:   snapshot.Rate15(),
+		})
+	}
+	for i, rule := range f.rules {
+		add(rule.String(), f.meters[i])
+	}
+	add(notAllowedRule, f.notAllowed)
+	return stats
+}
+
+func (r *router) SetJoinRequestRules(rules []JoinRequestRule) {
+	if len(rules) == 0 {
+		r.joinFilter = nil
+		return
+	}
+	filter := &joinRequestFilter{
+		rules:      rules,
+		meters:     make([]metrics.Meter, len(rules)),
+		notAllowed: metrics.NewMeter(),
+	}
+	for i := range rules {
+		filter.meters[i] = metrics.NewMeter()
+	}
+	r.joinFilter = filter
+}
@@ -0,0 +1,115 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+
+	pb "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParseJoinRequestRule(t *testing.T) {
+	a := New(t)
+
+	rule, err := ParseJoinRequestRule("deny dev-eui 0004A3")
+	a.So(err, ShouldBeNil)
+	a.So(rule.Deny, ShouldBeTrue)
+	a.So(rule.DevEUI, ShouldBeTrue)
+	a.So(rule.String(), ShouldEqual, "deny dev-eui 0004A30000000000-0004A3FFFFFFFFFF")
+
+	rule, err = ParseJoinRequestRule("allow join-eui 70B3D57ED0000000/36")
+	a.So(err, ShouldBeNil)
+	a.So(rule.Deny, ShouldBeFalse)
+	a.So(rule.DevEUI, ShouldBeFalse)
+
+	for _, invalid := range []string{"", "deny dev-eui", "block dev-eui 0004A3", "deny app-eui 0004A3", "deny dev-eui invalid"} {
+		_, err = ParseJoinRequestRule(invalid)
+		a.So(err, ShouldNotBeNil)
+	}
+}
+
+func TestJoinRequestFilter(t *testing.T) {
+	a := New(t)
+
+	var f *joinRequestFilter
+	_, ok := f.allow(types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}, types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8})
+	a.So(ok, ShouldBeTrue)
+	a.So(f.stats(), ShouldBeEmpty)
+
+	parse := func(rules ...string) (parsed []JoinRequestRule) {
+		for _, rule := range rules {
+			r, err := ParseJoinRequestRule(rule)
+			a.So(err, ShouldBeNil)
+			parsed = append(parsed, r)
+		}
+		return
+	}
+
+	r := getTestRouter(t)
+	r.SetJoinRequestRules(parse(
+		"deny dev-eui 0004A3",
+		"allow join-eui 70B3D57ED0000000/36",
+		"allow join-eui 0000000000000001",
+	))
+	f = r.joinFilter
+
+	ttnJoinEUI := types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x01}
+
+	rule, ok := f.allow(ttnJoinEUI, types.DevEUI{0x00, 0x04, 0xA3, 0x0B, 0x00, 0x00, 0x00, 0x01})
+	a.So(ok, ShouldBeFalse)
+	a.So(rule, ShouldStartWith, "deny dev-eui")
+
+	_, ok = f.allow(ttnJoinEUI, types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8})
+	a.So(ok, ShouldBeTrue)
+	_, ok = f.allow(types.AppEUI{0, 0, 0, 0, 0, 0, 0, 1}, types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8})
+	a.So(ok, ShouldBeTrue)
+
+	rule, ok = f.allow(types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}, types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8})
+	a.So(ok, ShouldBeFalse)
+	a.So(rule, ShouldEqual, "not allowed")
+
+	stats := f.stats()
+	a.So(stats, ShouldHaveLength, 4)
+	a.So(stats[0].Messages, ShouldEqual, 1)
+	a.So(stats[1].Messages, ShouldEqual, 1)
+	a.So(stats[2].Messages, ShouldEqual, 1)
+	a.So(stats[3].Rule, ShouldEqual, "not allowed")
+	a.So(stats[3].Messages, ShouldEqual, 1)
+
+	// Without allow rules, only denied join requests are dropped
+	r.SetJoinRequestRules(parse("deny join-eui 0000000000000001"))
+	f = r.joinFilter
+	_, ok = f.allow(types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8}, types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8})
+	a.So(ok, ShouldBeTrue)
+	_, ok = f.allow(types.AppEUI{0, 0, 0, 0, 0, 0, 0, 1}, types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8})
+	a.So(ok, ShouldBeFalse)
+
+	r.SetJoinRequestRules(nil)
+	a.So(r.joinFilter, ShouldBeNil)
+}
+
+func TestHandleActivationJoinRequestRules(t *testing.T) {
+	a := New(t)
+
+	r := getTestRouter(t)
+	rule, _ := ParseJoinRequestRule("deny dev-eui 0004A3")
+	r.SetJoinRequestRules([]JoinRequestRule{rule})
+
+	appEUI := types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x01}
+	devEUI := types.DevEUI{0x00, 0x04, 0xA3, 0x0B, 0x00, 0x00, 0x00, 0x01}
+	uplink := newReferenceUplink()
+	_, err := r.HandleActivation("eui-0102030405060708", &pb.DeviceActivationRequest{
+		Payload:          []byte{},
+		ProtocolMetadata: uplink.ProtocolMetadata,
+		GatewayMetadata:  uplink.GatewayMetadata,
+		AppEui:           &appEUI,
+		DevEui:           &devEUI,
+	})
+	a.So(err, ShouldNotBeNil)
+	a.So(errors.GetErrType(err), ShouldEqual, errors.PermissionDenied)
+	a.So(r.joinFilter.stats()[0].Messages, ShouldEqual, 1)
+}
@@ -6,6 +6,7 @@ package router
 import (
 	"fmt"
 
+	"github.com/TheThingsNetwork/ttn/api"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
@@ -57,6 +58,10 @@ func (r *routerManager) GetStatus(ctx context.Context, in *pb.StatusRequest) (*p
 	return status, nil
 }
 
+func (r *routerManager) TailLogs(in *api.LogsRequest, stream pb.RouterManager_TailLogsServer) error {
+	return r.router.Component.TailLogs(in, stream)
+}
+
 // RegisterManager registers this router as a RouterManagerServer (github.com/TheThingsNetwork/ttn/api/router)
 func (r *router) RegisterManager(s *grpc.Server) {
 	server := &routerManager{r}
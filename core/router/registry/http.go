@@ -0,0 +1,154 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// GatewayRequest is accepted by the gateway registry API
+type GatewayRequest struct {
+	FrequencyPlan string    `json:"frequency_plan,omitempty"`
+	Location      *Location `json:"location,omitempty"`
+	Antennas      []Antenna `json:"antennas,omitempty"`
+	Description   string    `json:"description,omitempty"`
+}
+
+// GatewayResponse is returned by the gateway registry API. The access key is
+// only returned when a gateway is registered and when its key is rotated.
+type GatewayResponse struct {
+	ID string `json:"id"`
+	GatewayRequest
+	AccessKey          string     `json:"access_key,omitempty"`
+	KeyRotatedAt       time.Time  `json:"key_rotated_at"`
+	PreviousKeyExpires *time.Time `json:"previous_key_expires,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// GatewaysResponse is returned by the gateway registry API
+type GatewaysResponse struct {
+	Gateways []*GatewayResponse `json:"gateways"`
+}
+
+func gatewayResponse(gtw *Gateway) *GatewayResponse {
+	response := &GatewayResponse{
+		ID: gtw.ID,
+		GatewayRequest: GatewayRequest{
+			FrequencyPlan: gtw.FrequencyPlan,
+			Location:      gtw.Location,
+			Antennas:      gtw.Antennas,
+			Description:   gtw.Description,
+		},
+		KeyRotatedAt: gtw.KeyRotatedAt,
+		CreatedAt:    gtw.CreatedAt,
+		UpdatedAt:    gtw.UpdatedAt,
+	}
+	if gtw.PreviousKeyHash != "" {
+		response.PreviousKeyExpires = &gtw.PreviousKeyExpires
+	}
+	return response
+}
+
+// HTTPHandler returns the gateway registry API. The access keys are only
+// returned when they are created.
+//
+//	GET /                             returns the gateways in the registry
+//	GET /{gateway_id}                 returns a gateway
+//	PUT /{gateway_id}                 registers a gateway, or updates its settings
+//	DELETE /{gateway_id}              deletes a gateway
+//	POST /{gateway_id}/rotate-key     replaces the access key of a gateway
+//	                                  (query: grace, the duration that the previous key remains valid)
+func HTTPHandler(store Store) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		path := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+		var response interface{}
+		var err error
+		switch {
+		case len(path) == 1 && path[0] == "" && req.Method == http.MethodGet:
+			var gateways []*Gateway
+			if gateways, err = store.List(); err == nil {
+				list := &GatewaysResponse{Gateways: make([]*GatewayResponse, 0, len(gateways))}
+				for _, gtw := range gateways {
+					list.Gateways = append(list.Gateways, gatewayResponse(gtw))
+				}
+				response = list
+			}
+		case len(path) == 1 && path[0] != "" && req.Method == http.MethodGet:
+			var gtw *Gateway
+			if gtw, err = store.Get(path[0]); err == nil {
+				response = gatewayResponse(gtw)
+			}
+		case len(path) == 1 && path[0] != "" && req.Method == http.MethodPut:
+			var in GatewayRequest
+			if err = json.NewDecoder(req.Body).Decode(&in); err != nil {
+				err = errors.NewErrInvalidArgument("Body", err.Error())
+				break
+			}
+			var key string
+			if key, err = Register(store, Gateway{
+				ID:            path[0],
+				FrequencyPlan: in.FrequencyPlan,
+				Location:      in.Location,
+				Antennas:      in.Antennas,
+				Description:   in.Description,
+			}); err != nil {
+				break
+			}
+			var gtw *Gateway
+			if gtw, err = store.Get(path[0]); err == nil {
+				withKey := gatewayResponse(gtw)
+				withKey.AccessKey = key
+				response = withKey
+			}
+		case len(path) == 1 && path[0] != "" && req.Method == http.MethodDelete:
+			if _, err = store.Get(path[0]); err == nil {
+				err = store.Delete(path[0])
+			}
+		case len(path) == 2 && path[1] == "rotate-key" && req.Method == http.MethodPost:
+			var grace time.Duration
+			if graceStr := req.URL.Query().Get("grace"); graceStr != "" {
+				if grace, err = time.ParseDuration(graceStr); err != nil {
+					err = errors.NewErrInvalidArgument("Grace", err.Error())
+					break
+				}
+			}
+			var key string
+			if key, err = RotateKey(store, path[0], grace); err != nil {
+				break
+			}
+			var gtw *Gateway
+			if gtw, err = store.Get(path[0]); err == nil {
+				withKey := gatewayResponse(gtw)
+				withKey.AccessKey = key
+				response = withKey
+			}
+		default:
+			http.NotFound(res, req)
+			return
+		}
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch errors.GetErrType(err) {
+			case errors.NotFound:
+				code = http.StatusNotFound
+			case errors.InvalidArgument:
+				code = http.StatusBadRequest
+			}
+			http.Error(res, err.Error(), code)
+			return
+		}
+		if response == nil {
+			res.WriteHeader(http.StatusNoContent)
+			return
+		}
+		res.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(res).Encode(response)
+	})
+}
@@ -0,0 +1,251 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package registry is a local registry of gateways. Routers that are not
+// connected to the account server authenticate gateways with the access keys
+// in the registry.
+package registry
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"gopkg.in/redis.v5"
+)
+
+// AccessKeyPrefix is the prefix of the access keys of the registry, which
+// distinguishes them from the gateway tokens of the account server
+const AccessKeyPrefix = "ttn-gw-key."
+
+// Location of a gateway
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  int32   `json:"altitude,omitempty"`
+}
+
+// Antenna of a gateway with more than one antenna, or of which the antenna
+// gain or location is known
+type Antenna struct {
+	// Index of the antenna in the antenna field of the gateway metadata
+	Index uint32 `json:"index"`
+	// Gain of the antenna in dBi
+	Gain float32 `json:"gain"`
+	// Location of the antenna, if it differs from the location of the gateway
+	Location *Location `json:"location,omitempty"`
+}
+
+// Gateway is a gateway in the registry. Only the hashes of its access keys are stored.
+type Gateway struct {
+	ID            string    `json:"id"`
+	FrequencyPlan string    `json:"frequency_plan,omitempty"`
+	Location      *Location `json:"location,omitempty"`
+	Antennas      []Antenna `json:"antennas,omitempty"`
+	Description   string    `json:"description,omitempty"`
+
+	KeyHash      string    `json:"key_hash"`
+	KeyRotatedAt time.Time `json:"key_rotated_at"`
+
+	// The previous access key remains valid until the gateway uses the new one, or until it expires
+	PreviousKeyHash    string    `json:"previous_key_hash,omitempty"`
+	PreviousKeyExpires time.Time `json:"previous_key_expires,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store contains the gateways of the registry by their ID
+type Store interface {
+	// Get the gateway with the ID
+	Get(gatewayID string) (*Gateway, error)
+	// List returns the gateways, sorted by ID
+	List() ([]*Gateway, error)
+	// Set the gateway
+	Set(gtw *Gateway) error
+	// Delete the gateway with the ID
+	Delete(gatewayID string) error
+}
+
+// NewRedisStore returns a Store that keeps the gateways in a Redis hash
+func NewRedisStore(client *redis.Client, prefix string) Store {
+	return &redisStore{client: client, key: prefix + ":gateways"}
+}
+
+type redisStore struct {
+	client *redis.Client
+	key    string
+}
+
+func (s *redisStore) Get(gatewayID string) (*Gateway, error) {
+	data, err := s.client.HGet(s.key, gatewayID).Bytes()
+	if err == redis.Nil {
+		return nil, errors.NewErrNotFound(fmt.Sprintf("Gateway %s", gatewayID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	gtw := new(Gateway)
+	if err := json.Unmarshal(data, gtw); err != nil {
+		return nil, err
+	}
+	return gtw, nil
+}
+
+type byID []*Gateway
+
+func (a byID) Len() int           { return len(a) }
+func (a byID) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byID) Less(i, j int) bool { return a[i].ID < a[j].ID }
+
+func (s *redisStore) List() ([]*Gateway, error) {
+	all, err := s.client.HGetAll(s.key).Result()
+	if err != nil {
+		return nil, err
+	}
+	gateways := make([]*Gateway, 0, len(all))
+	for _, data := range all {
+		gtw := new(Gateway)
+		if err := json.Unmarshal([]byte(data), gtw); err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, gtw)
+	}
+	sort.Sort(byID(gateways))
+	return gateways, nil
+}
+
+func (s *redisStore) Set(gtw *Gateway) error {
+	if !api.ValidID(gtw.ID) {
+		return errors.NewErrInvalidArgument("Gateway ID", "invalid")
+	}
+	data, err := json.Marshal(gtw)
+	if err != nil {
+		return err
+	}
+	return s.client.HSet(s.key, gtw.ID, data).Err()
+}
+
+func (s *redisStore) Delete(gatewayID string) error {
+	return s.client.HDel(s.key, gatewayID).Err()
+}
+
+// IsAccessKey returns whether the token is an access key of the registry
+func IsAccessKey(token string) bool {
+	return strings.HasPrefix(token, AccessKeyPrefix)
+}
+
+func newAccessKey() (key string, hash string, err error) {
+	random := make([]byte, 24)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+	key = AccessKeyPrefix + hex.EncodeToString(random)
+	return key, hashAccessKey(key), nil
+}
+
+func hashAccessKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+func matchesHash(key, hash string) bool {
+	return hash != "" && subtle.ConstantTimeCompare([]byte(hashAccessKey(key)), []byte(hash)) == 1
+}
+
+// Register adds the gateway to the registry, or updates the settings of a
+// gateway that already exists. The access key of a new gateway is returned;
+// it can not be retrieved later.
+func Register(store Store, gtw Gateway) (key string, err error) {
+	if err := validateAntennas(gtw.Antennas); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	existing, err := store.Get(gtw.ID)
+	switch {
+	case err == nil:
+		existing.FrequencyPlan = gtw.FrequencyPlan
+		existing.Location = gtw.Location
+		existing.Antennas = gtw.Antennas
+		existing.Description = gtw.Description
+		existing.UpdatedAt = now
+		return "", store.Set(existing)
+	case errors.GetErrType(err) != errors.NotFound:
+		return "", err
+	}
+	key, gtw.KeyHash, err = newAccessKey()
+	if err != nil {
+		return "", err
+	}
+	gtw.KeyRotatedAt, gtw.CreatedAt, gtw.UpdatedAt = now, now, now
+	gtw.PreviousKeyHash, gtw.PreviousKeyExpires = "", time.Time{}
+	if err := store.Set(&gtw); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func validateAntennas(antennas []Antenna) error {
+	seen := make(map[uint32]bool, len(antennas))
+	for _, antenna := range antennas {
+		if seen[antenna.Index] {
+			return errors.NewErrInvalidArgument("Antennas", fmt.Sprintf("duplicate index %d", antenna.Index))
+		}
+		seen[antenna.Index] = true
+		if antenna.Gain < -10 || antenna.Gain > 30 {
+			return errors.NewErrInvalidArgument("Antennas", fmt.Sprintf("gain of %.1f dBi is out of range", antenna.Gain))
+		}
+	}
+	return nil
+}
+
+// RotateKey replaces the access key of the gateway. The previous access key
+// remains valid for the grace period, so that the gateway can be reconfigured.
+func RotateKey(store Store, gatewayID string, grace time.Duration) (key string, err error) {
+	gtw, err := store.Get(gatewayID)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	gtw.PreviousKeyHash, gtw.PreviousKeyExpires = "", time.Time{}
+	if grace > 0 {
+		gtw.PreviousKeyHash, gtw.PreviousKeyExpires = gtw.KeyHash, now.Add(grace)
+	}
+	key, gtw.KeyHash, err = newAccessKey()
+	if err != nil {
+		return "", err
+	}
+	gtw.KeyRotatedAt, gtw.UpdatedAt = now, now
+	if err := store.Set(gtw); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// ValidateKey returns an error if the key is not a valid access key of the
+// gateway. Once the gateway uses its new access key, the previous one is revoked.
+func ValidateKey(store Store, gatewayID, key string) error {
+	gtw, err := store.Get(gatewayID)
+	if err != nil {
+		return err
+	}
+	if matchesHash(key, gtw.KeyHash) {
+		if gtw.PreviousKeyHash != "" {
+			gtw.PreviousKeyHash, gtw.PreviousKeyExpires = "", time.Time{}
+			return store.Set(gtw)
+		}
+		return nil
+	}
+	if matchesHash(key, gtw.PreviousKeyHash) && time.Now().Before(gtw.PreviousKeyExpires) {
+		return nil
+	}
+	return errors.NewErrPermissionDenied(fmt.Sprintf("Access key of gateway %s is not valid", gatewayID))
+}
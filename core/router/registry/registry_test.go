@@ -0,0 +1,126 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestRegistry(t *testing.T) {
+	a := New(t)
+	store := NewRedisStore(GetRedisClient(), "test-gateway-registry")
+	defer store.Delete("test-gateway")
+
+	_, err := RotateKey(store, "test-gateway", 0)
+	a.So(err, ShouldNotBeNil)
+	a.So(ValidateKey(store, "test-gateway", "ttn-gw-key.invalid"), ShouldNotBeNil)
+
+	_, err = Register(store, Gateway{ID: "Invalid ID"})
+	a.So(err, ShouldNotBeNil)
+
+	_, err = Register(store, Gateway{ID: "test-gateway", Antennas: []Antenna{{Index: 0}, {Index: 0, Gain: 3}}})
+	a.So(err, ShouldNotBeNil)
+
+	key, err := Register(store, Gateway{ID: "test-gateway", FrequencyPlan: "EU_863_870"})
+	a.So(err, ShouldBeNil)
+	a.So(IsAccessKey(key), ShouldBeTrue)
+	a.So(ValidateKey(store, "test-gateway", key), ShouldBeNil)
+	a.So(ValidateKey(store, "test-gateway", key+"0"), ShouldNotBeNil)
+	a.So(ValidateKey(store, "other-gateway", key), ShouldNotBeNil)
+
+	// Updates do not change the key
+	again, err := Register(store, Gateway{ID: "test-gateway", FrequencyPlan: "US_902_928", Description: "Rooftop"})
+	a.So(err, ShouldBeNil)
+	a.So(again, ShouldBeEmpty)
+	gtw, err := store.Get("test-gateway")
+	a.So(err, ShouldBeNil)
+	a.So(gtw.FrequencyPlan, ShouldEqual, "US_902_928")
+	a.So(gtw.Description, ShouldEqual, "Rooftop")
+	a.So(ValidateKey(store, "test-gateway", key), ShouldBeNil)
+
+	// Without grace period, the previous key is revoked immediately
+	rotated, err := RotateKey(store, "test-gateway", 0)
+	a.So(err, ShouldBeNil)
+	a.So(rotated, ShouldNotEqual, key)
+	a.So(ValidateKey(store, "test-gateway", key), ShouldNotBeNil)
+	a.So(ValidateKey(store, "test-gateway", rotated), ShouldBeNil)
+
+	// With grace period, the previous key is valid until the new key is used
+	previous := rotated
+	rotated, err = RotateKey(store, "test-gateway", time.Hour)
+	a.So(err, ShouldBeNil)
+	a.So(ValidateKey(store, "test-gateway", previous), ShouldBeNil)
+	a.So(ValidateKey(store, "test-gateway", rotated), ShouldBeNil)
+	a.So(ValidateKey(store, "test-gateway", previous), ShouldNotBeNil)
+
+	gateways, err := store.List()
+	a.So(err, ShouldBeNil)
+	a.So(gateways, ShouldHaveLength, 1)
+	a.So(gateways[0].ID, ShouldEqual, "test-gateway")
+}
+
+func TestIsAccessKey(t *testing.T) {
+	a := New(t)
+	a.So(IsAccessKey("ttn-gw-key.0102"), ShouldBeTrue)
+	a.So(IsAccessKey("eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9"), ShouldBeFalse)
+	a.So(IsAccessKey(""), ShouldBeFalse)
+}
+
+func TestHTTPHandler(t *testing.T) {
+	a := New(t)
+	store := NewRedisStore(GetRedisClient(), "test-gateway-registry-http")
+	defer store.Delete("test-gateway")
+	handler := HTTPHandler(store)
+
+	request := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	a.So(request("GET", "/test-gateway", nil).Code, ShouldEqual, http.StatusNotFound)
+	a.So(request("PUT", "/test-gateway", []byte("invalid")).Code, ShouldEqual, http.StatusBadRequest)
+	a.So(request("PATCH", "/test-gateway", nil).Code, ShouldEqual, http.StatusNotFound)
+
+	rec := request("PUT", "/test-gateway", []byte(`{"frequency_plan":"EU_863_870","location":{"latitude":52.37,"longitude":4.88}}`))
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var registered GatewayResponse
+	a.So(json.NewDecoder(rec.Body).Decode(&registered), ShouldBeNil)
+	a.So(registered.AccessKey, ShouldNotBeEmpty)
+	a.So(registered.Location, ShouldNotBeNil)
+	a.So(ValidateKey(store, "test-gateway", registered.AccessKey), ShouldBeNil)
+
+	rec = request("GET", "/test-gateway", nil)
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var info GatewayResponse
+	a.So(json.NewDecoder(rec.Body).Decode(&info), ShouldBeNil)
+	a.So(info.AccessKey, ShouldBeEmpty)
+	a.So(info.FrequencyPlan, ShouldEqual, "EU_863_870")
+
+	rec = request("GET", "/", nil)
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var list GatewaysResponse
+	a.So(json.NewDecoder(rec.Body).Decode(&list), ShouldBeNil)
+	a.So(list.Gateways, ShouldHaveLength, 1)
+
+	a.So(request("POST", "/test-gateway/rotate-key?grace=invalid", nil).Code, ShouldEqual, http.StatusBadRequest)
+	rec = request("POST", "/test-gateway/rotate-key?grace=24h", nil)
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	var rotated GatewayResponse
+	a.So(json.NewDecoder(rec.Body).Decode(&rotated), ShouldBeNil)
+	a.So(rotated.AccessKey, ShouldNotEqual, registered.AccessKey)
+	a.So(rotated.PreviousKeyExpires, ShouldNotBeNil)
+
+	a.So(request("DELETE", "/test-gateway", nil).Code, ShouldEqual, http.StatusNoContent)
+	a.So(request("DELETE", "/test-gateway", nil).Code, ShouldEqual, http.StatusNotFound)
+}
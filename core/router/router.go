@@ -4,6 +4,7 @@
 package router
 
 import (
+	"net/http"
 	"sync"
 	"time"
 
@@ -14,7 +15,12 @@ import (
 	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/router/frequencyplan"
 	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/router/registry"
+	"github.com/TheThingsNetwork/ttn/core/router/spool"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/bluele/gcache"
 	"golang.org/x/net/context"
 )
 
@@ -35,6 +41,38 @@ type Router interface {
 	UnsubscribeDownlink(gatewayID string, subscriptionID string) error
 	// Handle a device activation
 	HandleActivation(gatewayID string, activation *pb.DeviceActivationRequest) (*pb.DeviceActivationResponse, error)
+	// FrequencyPlans returns the frequency plans that gateways can be assigned to
+	FrequencyPlans() frequencyplan.Registry
+	// SetGatewayTrust sets the trust level of a gateway
+	SetGatewayTrust(gatewayID string, trust gateway.TrustLevel)
+	// SetTrafficFilter sets the DevAddr prefixes and JoinEUI ranges that are served by this
+	// Router. Traffic of other devices is dropped. Without prefixes or ranges, nothing is dropped.
+	SetTrafficFilter(devAddrPrefixes []types.DevAddrPrefix, joinEUIRanges []JoinEUIRange)
+	// SetJoinRequestRules sets the rules that allow or deny join requests by their JoinEUI or
+	// DevEUI. Join requests that are not allowed are dropped. Without rules, nothing is dropped.
+	SetJoinRequestRules(rules []JoinRequestRule)
+	// EnableBeacons makes the Router schedule Class B beacons on the gateways that have beacon
+	// settings in their frequency plan or region. It should be called before Init.
+	EnableBeacons()
+	// SetGatewayOfflineAlert makes the Router alert the operators when a gateway was not
+	// seen for the duration. The alert is disabled if it is 0.
+	SetGatewayOfflineAlert(after time.Duration)
+	// SetSpool makes the Router store uplink messages on disk while a Broker is unreachable,
+	// and forward them when the connection is restored
+	SetSpool(s *spool.Spool)
+	// SetRXWindowMargin sets the time that is needed to process an uplink message and schedule
+	// the downlink, in addition to the round-trip time of the backhaul of the gateway. Receive
+	// windows that a downlink can not reach in time are skipped.
+	SetRXWindowMargin(margin time.Duration)
+	// SetDownlinkAirtimeBudgets sets the downlink airtime that each application can use on a
+	// gateway per day. Downlinks that exceed the budget are not sent.
+	SetDownlinkAirtimeBudgets(budgets DownlinkAirtimeBudgets)
+	// SetGatewayRegistry makes the Router authenticate gateways with the access keys of the
+	// local gateway registry, in addition to the gateway tokens of the account server. The
+	// registry is managed with the admin API of the debug server.
+	SetGatewayRegistry(store registry.Store)
+	// HTTPHandler returns the HTTP API of the Router
+	HTTPHandler() http.Handler
 
 	getGateway(gatewayID string) *gateway.Gateway
 }
@@ -50,8 +88,10 @@ type broker struct {
 // NewRouter creates a new Router
 func NewRouter() Router {
 	return &router{
-		gateways: make(map[string]*gateway.Gateway),
-		brokers:  make(map[string]*broker),
+		gateways:       make(map[string]*gateway.Gateway),
+		brokers:        make(map[string]*broker),
+		frequencyPlans: frequencyplan.NewRegistry(),
+		antennas:       gateway.NewAntennaRegistry(),
 	}
 }
 
@@ -62,6 +102,37 @@ type router struct {
 	brokers      map[string]*broker
 	brokersLock  sync.RWMutex
 	status       *status
+
+	frequencyPlans frequencyplan.Registry
+	antennas       gateway.AntennaRegistry
+	gatewayTrust   map[string]gateway.TrustLevel // Protected by gatewaysLock
+	filter         *trafficFilter
+	joinFilter     *joinRequestFilter
+	beacons        bool
+
+	gatewayOfflineAlert time.Duration
+	spool               *spool.Spool
+	rxWindowMargin      time.Duration
+	downlinkBudgets     *DownlinkAirtimeBudgets
+	gatewayRegistry     registry.Store
+	rxContexts          gcache.Cache
+	rxContextsLock      sync.Mutex
+}
+
+func (r *router) FrequencyPlans() frequencyplan.Registry {
+	return r.frequencyPlans
+}
+
+func (r *router) SetGatewayTrust(gatewayID string, trust gateway.TrustLevel) {
+	r.gatewaysLock.Lock()
+	defer r.gatewaysLock.Unlock()
+	if r.gatewayTrust == nil {
+		r.gatewayTrust = make(map[string]gateway.TrustLevel)
+	}
+	r.gatewayTrust[gatewayID] = trust
+	if gtw, ok := r.gateways[gatewayID]; ok {
+		gtw.SetTrust(trust)
+	}
 }
 
 func (r *router) tickGateways() {
@@ -90,6 +161,14 @@ func (r *router) Init(c *component.Component) error {
 			r.tickGateways()
 		}
 	}()
+	if r.beacons {
+		go r.runBeacons()
+	}
+	r.handleGatewayOfflineAlerts()
+	r.Component.RegisterDebugInfo("gateways", r.connectedGateways)
+	if r.gatewayRegistry != nil {
+		r.Component.RegisterAdminHandler("gateways", registry.HTTPHandler(r.gatewayRegistry))
+	}
 	r.Component.SetStatus(component.StatusHealthy)
 	return nil
 }
@@ -120,6 +199,11 @@ func (r *router) getGateway(id string) *gateway.Gateway {
 	if !ok {
 		gtw = gateway.NewGateway(r.Ctx, id)
 		gtw.Monitors = r.Component.Monitors
+		gtw.Alerts = r.Component.Alerts
+		if r.antennas != nil {
+			gtw.Antennas = r.antennas
+		}
+		gtw.SetTrust(r.gatewayTrust[id])
 
 		r.gateways[id] = gtw
 	}
@@ -163,13 +247,18 @@ func (r *router) getBroker(brokerAnnouncement *pb_discovery.Announcement) (*brok
 			uplink:      make(chan *pb_broker.UplinkMessage),
 		}
 
+		brokerID := brokerAnnouncement.Id
 		go func() {
+			drain := time.NewTicker(spoolDrainInterval)
+			defer drain.Stop()
 			for {
 				select {
 				case message := <-brk.uplink:
-					association.Send(message)
+					r.sendToBroker(brokerID, association, message)
 				case message := <-downlink:
 					go r.HandleDownlink(message)
+				case <-drain.C:
+					r.drainSpool(brokerID, association)
 				}
 			}
 		}()
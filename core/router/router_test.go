@@ -3,8 +3,25 @@
 
 package router
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/smartystreets/assertions"
+)
 
 func TestRouterIntegration(t *testing.T) {
 
 }
+
+func TestSetGatewayTrust(t *testing.T) {
+	a := New(t)
+	r := getTestRouter(t)
+
+	existing := r.getGateway("eui-0102030405060708")
+	r.SetGatewayTrust("eui-0102030405060708", gateway.TrustTrusted)
+	a.So(existing.Trusted(), ShouldBeTrue)
+
+	r.SetGatewayTrust("eui-0807060504030201", gateway.TrustTrusted)
+	a.So(r.getGateway("eui-0807060504030201").Trusted(), ShouldBeTrue)
+}
@@ -0,0 +1,131 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/band"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/bluele/gcache"
+)
+
+// RXContextCacheSize is the number of downlink options that the Router keeps
+// the uplink context of, to apply the receive window parameters of devices
+var RXContextCacheSize = 10000
+
+// RXContextExpiration is how long the Router keeps the uplink context of a
+// downlink option. This covers the longest RX2 delay of 16 seconds.
+var RXContextExpiration = 20 * time.Second
+
+// rxContext is the uplink message that a downlink option was built for
+type rxContext struct {
+	uplinkTimestamp uint32
+	dataRate        string
+	antenna         uint32
+	isActivation    bool
+	region          string
+	window          types.RXWindow
+	rxWindows       rxWindowDecision
+}
+
+func (r *router) setRXContext(identifier string, rx rxContext) {
+	r.rxContextsLock.Lock()
+	defer r.rxContextsLock.Unlock()
+	if r.rxContexts == nil {
+		r.rxContexts = gcache.New(RXContextCacheSize).Expiration(RXContextExpiration).LRU().Build()
+	}
+	r.rxContexts.Set(identifier, rx)
+}
+
+func (r *router) getRXContext(identifier string) (rx rxContext, ok bool) {
+	r.rxContextsLock.Lock()
+	defer r.rxContextsLock.Unlock()
+	if r.rxContexts == nil {
+		return rx, false
+	}
+	value, err := r.rxContexts.Get(identifier)
+	if err != nil {
+		return rx, false
+	}
+	return value.(rxContext), true
+}
+
+// applyRXSettings builds the downlink option again for the receive window
+// parameters that the Handler set on it, and reserves a transmission slot on
+// the schedule of the gateway. It returns the identifier of that slot.
+func (r *router) applyRXSettings(option *pb_broker.DownlinkOption, identifier string, payloadSize int) (string, error) {
+	settings := option.GetRxSettings()
+	if settings == nil || *settings == (pb_broker.RXSettings{}) || option.GatewayConfig == nil {
+		return identifier, nil
+	}
+	lorawan := option.GetProtocolConfig().GetLorawan()
+	if lorawan == nil || lorawan.Modulation != pb_lorawan.Modulation_LORA {
+		return identifier, nil
+	}
+	rx, ok := r.getRXContext(identifier)
+	if !ok {
+		return "", errors.NewErrNotFound(fmt.Sprintf("Downlink option %s", identifier))
+	}
+	fp, err := band.Get(rx.region)
+	if err != nil {
+		return "", err
+	}
+
+	rx1Delay, rx2Delay := fp.ReceiveDelay1, fp.ReceiveDelay2
+	if rx.isActivation {
+		// The device uses the defaults of the band until it receives the join-accept
+		settings = &pb_broker.RXSettings{Rx2Only: settings.Rx2Only}
+		rx1Delay, rx2Delay = fp.JoinAcceptDelay1, fp.JoinAcceptDelay2
+		if rx.region == "EU_863_870" {
+			fp.RX2DataRate = 0
+		}
+	} else if settings.Rx1Delay != 0 {
+		// RX2 is always one second after RX1
+		rx1Delay = time.Duration(settings.Rx1Delay) * time.Second
+		rx2Delay = rx1Delay + time.Second
+	}
+
+	var drIdx int
+	if rx.window == types.RX2Window || settings.Rx2Only {
+		option.GatewayConfig.Timestamp = rx.uplinkTimestamp + uint32(rx2Delay/time.Microsecond) // Overflows are fine here
+		if rx.window != types.RX2Window {
+			option.GatewayConfig.Frequency = uint64(fp.RX2Frequency)
+			option.GatewayConfig.Power = int32(fp.DefaultTXPower)
+			if rx.region == "EU_863_870" {
+				option.GatewayConfig.Power = 27 // The EU RX2 frequency allows up to 27dBm
+			}
+			if antenna, ok := r.getGateway(option.GatewayId).Antenna(rx.antenna); ok {
+				applyAntenna(option, antenna)
+			}
+		}
+		drIdx = fp.RX2DataRate
+		if settings.Rx2DataRate != "" {
+			if drIdx, err = fp.GetDataRateIndexFor(settings.Rx2DataRate); err != nil {
+				return "", err
+			}
+		}
+	} else {
+		option.GatewayConfig.Timestamp = rx.uplinkTimestamp + uint32(rx1Delay/time.Microsecond) // Overflows are fine here
+		upDR, err := fp.GetDataRateIndexFor(rx.dataRate)
+		if err != nil {
+			return "", err
+		}
+		if drIdx, err = fp.GetRX1DataRate(upDR, int(settings.Rx1DrOffset)); err != nil {
+			return "", err
+		}
+	}
+	if err := lorawan.SetDataRate(fp.DataRates[drIdx]); err != nil {
+		return "", err
+	}
+	option.GatewayConfig.FrequencyDeviation = uint32(lorawan.BitRate / 2)
+
+	_, airtime := downlinkAirtime(option.ProtocolConfig, payloadSize)
+	id, _ := r.getGateway(option.GatewayId).Schedule.GetOption(option.GatewayConfig.Timestamp, uint32(airtime/time.Microsecond))
+	return id, nil
+}
@@ -0,0 +1,77 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestApplyRXSettings(t *testing.T) {
+	a := New(t)
+
+	r := &router{gateways: map[string]*gateway.Gateway{}}
+
+	build := func(isActivation bool) (rx1, rx2 *pb_broker.DownlinkOption) {
+		gtw, up := newReferenceGateway(t, "EU_863_870"), newReferenceUplink()
+		r.gateways[gtw.ID] = gtw
+		options := r.buildDownlinkOptions(up, isActivation, gtw)
+		a.So(options, ShouldHaveLength, 2)
+		return options[1], options[0]
+	}
+
+	// Options without settings are not changed
+	rx1, _ := build(false)
+	id, err := r.applyRXSettings(rx1, rx1.Identifier, 10)
+	a.So(err, ShouldBeNil)
+	a.So(id, ShouldEqual, rx1.Identifier)
+	a.So(rx1.GatewayConfig.Timestamp, ShouldEqual, 1000100)
+
+	settings := &pb_broker.RXSettings{Rx1Delay: 5, Rx1DrOffset: 2, Rx2DataRate: "SF12BW125"}
+
+	// RX1
+	rx1, rx2 := build(false)
+	rx1.RxSettings = settings
+	id, err = r.applyRXSettings(rx1, rx1.Identifier, 10)
+	a.So(err, ShouldBeNil)
+	a.So(id, ShouldNotEqual, rx1.Identifier)
+	a.So(rx1.GatewayConfig.Timestamp, ShouldEqual, 5000100)
+	a.So(rx1.ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF9BW125")
+
+	// RX2
+	rx2.RxSettings = settings
+	_, err = r.applyRXSettings(rx2, rx2.Identifier, 10)
+	a.So(err, ShouldBeNil)
+	a.So(rx2.GatewayConfig.Timestamp, ShouldEqual, 6000100)
+	a.So(rx2.ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF12BW125")
+
+	// RX1 is moved to RX2
+	rx1, _ = build(false)
+	rx1.RxSettings = &pb_broker.RXSettings{Rx2Only: true}
+	_, err = r.applyRXSettings(rx1, rx1.Identifier, 10)
+	a.So(err, ShouldBeNil)
+	a.So(rx1.GatewayConfig.Timestamp, ShouldEqual, 2000100)
+	a.So(rx1.GatewayConfig.Frequency, ShouldEqual, 869525000)
+	a.So(rx1.GatewayConfig.Power, ShouldEqual, 27)
+	a.So(rx1.ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF9BW125")
+
+	// Join-accepts use the join-accept delays and the defaults of the band
+	rx1, _ = build(true)
+	rx1.RxSettings = &pb_broker.RXSettings{Rx2Only: true, Rx2DataRate: "SF9BW125"}
+	_, err = r.applyRXSettings(rx1, rx1.Identifier, 10)
+	a.So(err, ShouldBeNil)
+	a.So(rx1.GatewayConfig.Timestamp, ShouldEqual, 6000100)
+	a.So(rx1.ProtocolConfig.GetLorawan().DataRate, ShouldEqual, "SF12BW125")
+
+	// The option can not be built again without the uplink
+	rx1, _ = build(false)
+	rx1.RxSettings = settings
+	_, err = r.applyRXSettings(rx1, "unknown", 10)
+	a.So(err, ShouldNotBeNil)
+	a.So(errors.GetErrType(err), ShouldEqual, errors.NotFound)
+}
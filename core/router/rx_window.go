@@ -0,0 +1,53 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+)
+
+// SetRXWindowMargin sets the time that the network needs to process an uplink
+// message and schedule the downlink, in addition to the round-trip time of the
+// backhaul of the gateway. Receive windows that open before the round-trip time
+// plus the margin have passed are skipped for that gateway.
+func (r *router) SetRXWindowMargin(margin time.Duration) {
+	r.rxWindowMargin = margin
+}
+
+// rxWindowDecision is the decision on the receive windows in which a downlink
+// can reach a gateway in time over its backhaul
+type rxWindowDecision struct {
+	rtt     time.Duration
+	skipRX1 bool
+	skipRX2 bool
+}
+
+// skipped returns the names of the receive windows that are skipped
+func (d rxWindowDecision) skipped() string {
+	var windows []string
+	if d.skipRX1 {
+		windows = append(windows, "rx1")
+	}
+	if d.skipRX2 {
+		windows = append(windows, "rx2")
+	}
+	return strings.Join(windows, ",")
+}
+
+// decideRXWindows decides which receive windows are skipped for the gateway,
+// given the delays of RX1 and RX2 after the uplink. Without a recent round-trip
+// time of the gateway, no receive windows are skipped.
+func (r *router) decideRXWindows(gtw *gateway.Gateway, rx1Delay, rx2Delay time.Duration, now time.Time) (decision rxWindowDecision) {
+	rtt, ok := gtw.RTT(now)
+	if !ok {
+		return
+	}
+	decision.rtt = rtt
+	decision.skipRX1 = rtt+r.rxWindowMargin >= rx1Delay
+	decision.skipRX2 = rtt+r.rxWindowMargin >= rx2Delay
+	return
+}
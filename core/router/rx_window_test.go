@@ -0,0 +1,70 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/api/monitor"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestBuildDownlinkOptionsRTT(t *testing.T) {
+	a := New(t)
+
+	r := &router{}
+	r.SetRXWindowMargin(500 * time.Millisecond)
+
+	// A fast backhaul can make RX1
+	gtw, up := newReferenceGateway(t, "EU_863_870"), newReferenceUplink()
+	gtw.HandleStatus(&pb_gateway.Status{Region: "EU_863_870", Time: time.Now().Add(-25 * time.Millisecond).UnixNano()})
+	options := r.buildDownlinkOptions(up, false, gtw)
+	a.So(options, ShouldHaveLength, 2)
+
+	// A slow backhaul can only make RX2
+	gtw, up = newReferenceGateway(t, "EU_863_870"), newReferenceUplink()
+	gtw.HandleStatus(&pb_gateway.Status{Region: "EU_863_870", Time: time.Now().Add(-100 * time.Millisecond).UnixNano()})
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options, ShouldHaveLength, 1)
+	a.So(options[0].GatewayConfig.Timestamp, ShouldEqual, 2000100)
+	a.So(options[0].GatewayConfig.Frequency, ShouldEqual, 869525000)
+
+	// The skipped window is recorded on the trace of the downlink
+	logger := GetLogger(t, "TestBuildDownlinkOptionsRTT")
+	r.Component = &component.Component{Ctx: logger, Monitors: monitor.NewRegistry(logger)}
+	r.gateways = map[string]*gateway.Gateway{gtw.ID: gtw}
+	r.InitStatus()
+	downlink := &pb_broker.DownlinkMessage{Payload: make([]byte, 12), DownlinkOption: options[0]}
+	r.HandleDownlink(downlink)
+	var skipped *trace.Trace
+	for _, event := range downlink.Trace.Flatten() {
+		if event.Event == trace.SkipRXWindowEvent {
+			skipped = event
+		}
+	}
+	a.So(skipped, ShouldNotBeNil)
+	a.So(skipped.Metadata["windows"], ShouldEqual, "rx1")
+	a.So(skipped.Metadata["gateway"], ShouldEqual, gtw.ID)
+	for _, event := range up.Trace.Flatten() {
+		a.So(event.Event, ShouldNotEqual, trace.SkipRXWindowEvent)
+	}
+
+	// The join accept delays leave more time
+	up = newReferenceUplink()
+	options = r.buildDownlinkOptions(up, true, gtw)
+	a.So(options, ShouldHaveLength, 2)
+
+	// With a very slow backhaul, another gateway should send the downlink
+	gtw, up = newReferenceGateway(t, "EU_863_870"), newReferenceUplink()
+	gtw.HandleStatus(&pb_gateway.Status{Region: "EU_863_870", Time: time.Now().Add(-300 * time.Millisecond).UnixNano()})
+	options = r.buildDownlinkOptions(up, false, gtw)
+	a.So(options, ShouldBeEmpty)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/TheThingsNetwork/ttn/api/ratelimit"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/core/router/gateway"
+	"github.com/TheThingsNetwork/ttn/core/router/registry"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
 	"github.com/TheThingsNetwork/ttn/utils/random"
 	"github.com/spf13/viper"
@@ -39,8 +40,15 @@ func (r *routerRPC) gatewayFromMetadata(md metadata.MD) (gtw *gateway.Gateway, e
 	authErr := errors.NewErrPermissionDenied("Gateway not authenticated")
 	authenticated := false
 	token, _ := api.TokenFromMetadata(md)
+	var registered *registry.Gateway
 
-	if token != "" {
+	if registry.IsAccessKey(token) {
+		// The access key is not passed on, as the token of the gateway is sent to its monitors
+		if registered, authErr = r.router.validateGatewayAccessKey(gatewayID, token); authErr == nil {
+			authenticated = true
+		}
+		token = ""
+	} else if token != "" {
 		if r.router.TokenKeyProvider == nil {
 			return nil, errors.NewErrInternal("No token provider configured")
 		}
@@ -63,6 +71,9 @@ func (r *routerRPC) gatewayFromMetadata(md metadata.MD) (gtw *gateway.Gateway, e
 
 	gtw = r.router.getGateway(gatewayID)
 	gtw.SetAuth(token, authenticated)
+	if registered != nil {
+		r.router.applyRegisteredGateway(gtw, registered)
+	}
 
 	return gtw, nil
 }
@@ -0,0 +1,72 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/api/trace"
+	"github.com/TheThingsNetwork/ttn/core/router/spool"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// spoolDrainInterval is the interval at which the spool of a connected Broker is drained
+var spoolDrainInterval = time.Second
+
+// SetSpool makes the Router store uplink messages on disk while a Broker is
+// unreachable, and forward them when the connection is restored
+func (r *router) SetSpool(s *spool.Spool) {
+	r.spool = s
+}
+
+// sendToBroker sends the uplink message on the association with the Broker,
+// or spools it if the Broker is unreachable. Spooled messages are sent first,
+// so that the Broker receives the uplink messages of a device in order.
+func (r *router) sendToBroker(brokerID string, association pb_broker.RouterStream, message *pb_broker.UplinkMessage) {
+	if r.spool == nil {
+		association.Send(message)
+		return
+	}
+	if association.Connected() {
+		r.drainSpool(brokerID, association)
+		if r.spool.Size(brokerID) == 0 {
+			association.Send(message)
+			return
+		}
+	}
+	// The receive windows of the device will have passed when the message is sent
+	message.Delayed = true
+	message.DownlinkOptions = nil
+	if err := r.spool.Add(brokerID, message); err != nil {
+		r.Ctx.WithField("BrokerID", brokerID).WithError(err).Warn("Could not spool uplink message")
+		return
+	}
+	r.Ctx.WithField("BrokerID", brokerID).Debug("Spooled uplink message")
+}
+
+// drainSpool forwards the spooled uplink messages to the Broker if it is reachable.
+// Messages that can not be sent stay in the spool for the next attempt.
+func (r *router) drainSpool(brokerID string, association pb_broker.RouterStream) {
+	if r.spool == nil || !association.Connected() || r.spool.Size(brokerID) == 0 {
+		return
+	}
+	now := time.Now()
+	sent, err := r.spool.Drain(brokerID, func(entry spool.Entry) error {
+		if !association.Connected() {
+			return errors.NewErrInternal("Broker disconnected")
+		}
+		// The gateway timestamps are kept, the delay is recorded in the trace
+		entry.Uplink.Trace = entry.Uplink.Trace.WithEvent(trace.DelayEvent, "spooled", now.Sub(entry.Time).String())
+		return association.Send(entry.Uplink)
+	})
+	ctx := r.Ctx.WithField("BrokerID", brokerID).WithField("Count", sent)
+	if err != nil {
+		ctx.WithError(err).Warn("Could not drain spooled uplink messages")
+		return
+	}
+	if sent > 0 {
+		ctx.Info("Forwarded spooled uplink messages")
+	}
+}
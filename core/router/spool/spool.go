@@ -0,0 +1,171 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package spool keeps uplink messages on disk while a Broker is unreachable
+package spool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+)
+
+// ErrFull is returned when the spool of a Broker reached its maximum size
+var ErrFull = errors.New("Spool is full")
+
+// Entry is an uplink message in the spool
+type Entry struct {
+	Time   time.Time
+	Uplink *pb_broker.UplinkMessage
+}
+
+// Spool keeps uplink messages per Broker in files in a directory, bounded by
+// the size of each file and the age of the messages
+type Spool struct {
+	dir     string
+	maxSize int64
+	maxAge  time.Duration
+	mu      sync.Mutex
+}
+
+// New returns a Spool in the directory, which is created if it does not exist
+func New(dir string, maxSize int64, maxAge time.Duration) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Spool{dir: dir, maxSize: maxSize, maxAge: maxAge}, nil
+}
+
+func (s *Spool) path(brokerID string) string {
+	return filepath.Join(s.dir, filepath.Base(brokerID)+".spool")
+}
+
+// headerSize is the size of the time and length that precede each message
+const headerSize = 12
+
+// Add appends the uplink message to the spool of the Broker
+func (s *Spool) Add(brokerID string, uplink *pb_broker.UplinkMessage) error {
+	data, err := uplink.Marshal()
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxMessageSize {
+		return errors.NewErrInvalidArgument("Uplink", "too large to spool")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path(brokerID), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if s.maxSize > 0 && info.Size()+headerSize+int64(len(data)) > s.maxSize {
+		return ErrFull
+	}
+
+	record := make([]byte, headerSize+len(data))
+	binary.BigEndian.PutUint64(record, uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint32(record[8:], uint32(len(data)))
+	copy(record[headerSize:], data)
+	_, err = f.Write(record)
+	return err
+}
+
+// Size returns the size in bytes of the spool of the Broker
+func (s *Spool) Size(brokerID string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := os.Stat(s.path(brokerID))
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// MaxMessageSize is the maximum size of a spooled message. Records with a larger
+// length are considered corrupt.
+const MaxMessageSize = 64 * 1024
+
+// Drain sends the messages in the spool of the Broker, except the ones that
+// exceed the maximum age. If sending a message fails, the remaining messages
+// are kept in the spool. It returns the number of messages that were sent.
+func (s *Spool) Drain(brokerID string, send func(Entry) error) (sent int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path(brokerID))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	now := time.Now()
+	r := bufio.NewReader(f)
+	header := make([]byte, headerSize)
+	var remaining []byte
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			// A partially written record at the end is discarded
+			break
+		}
+		t := time.Unix(0, int64(binary.BigEndian.Uint64(header)))
+		length := binary.BigEndian.Uint32(header[8:])
+		if length > MaxMessageSize {
+			// The length is corrupt, so the records that follow can not be read
+			break
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(r, data); err != nil {
+			break
+		}
+		if remaining != nil {
+			remaining = append(remaining, header...)
+			remaining = append(remaining, data...)
+			continue
+		}
+		if s.maxAge > 0 && now.Sub(t) > s.maxAge {
+			continue
+		}
+		uplink := new(pb_broker.UplinkMessage)
+		if err := uplink.Unmarshal(data); err != nil {
+			continue
+		}
+		if err := send(Entry{Time: t, Uplink: uplink}); err != nil {
+			remaining = append(append([]byte{}, header...), data...)
+			continue
+		}
+		sent++
+	}
+
+	if remaining == nil {
+		return sent, os.Remove(s.path(brokerID))
+	}
+	return sent, s.rewrite(brokerID, remaining)
+}
+
+// rewrite atomically replaces the spool of the Broker with the records
+func (s *Spool) rewrite(brokerID string, records []byte) error {
+	tmp := s.path(brokerID) + ".tmp"
+	if err := ioutil.WriteFile(tmp, records, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(brokerID))
+}
@@ -0,0 +1,120 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package spool
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/smartystreets/assertions"
+)
+
+func take(s *Spool, brokerID string) (entries []Entry, err error) {
+	_, err = s.Drain(brokerID, func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	return
+}
+
+func TestSpool(t *testing.T) {
+	a := assertions.New(t)
+
+	dir, err := ioutil.TempDir("", "spool")
+	a.So(err, assertions.ShouldBeNil)
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir, 100, time.Minute)
+	a.So(err, assertions.ShouldBeNil)
+
+	entries, err := take(s, "broker")
+	a.So(err, assertions.ShouldBeNil)
+	a.So(entries, assertions.ShouldBeEmpty)
+
+	a.So(s.Add("broker", &pb_broker.UplinkMessage{Payload: []byte{1, 2, 3}}), assertions.ShouldBeNil)
+	a.So(s.Add("broker", &pb_broker.UplinkMessage{Payload: []byte{4, 5, 6}}), assertions.ShouldBeNil)
+	a.So(s.Add("other", &pb_broker.UplinkMessage{Payload: []byte{7}}), assertions.ShouldBeNil)
+	a.So(s.Size("broker"), assertions.ShouldEqual, 2*(headerSize+5))
+
+	// The spool is bounded by its size
+	a.So(s.Add("broker", &pb_broker.UplinkMessage{Payload: make([]byte, 100)}), assertions.ShouldEqual, ErrFull)
+
+	entries, err = take(s, "broker")
+	a.So(err, assertions.ShouldBeNil)
+	a.So(entries, assertions.ShouldHaveLength, 2)
+	a.So(entries[0].Uplink.Payload, assertions.ShouldResemble, []byte{1, 2, 3})
+	a.So(entries[1].Uplink.Payload, assertions.ShouldResemble, []byte{4, 5, 6})
+	a.So(s.Size("broker"), assertions.ShouldEqual, 0)
+
+	// Messages that exceed the maximum age are discarded
+	s.maxAge = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	entries, err = take(s, "other")
+	a.So(err, assertions.ShouldBeNil)
+	a.So(entries, assertions.ShouldBeEmpty)
+}
+
+func TestSpoolDrainFailure(t *testing.T) {
+	a := assertions.New(t)
+
+	dir, err := ioutil.TempDir("", "spool")
+	a.So(err, assertions.ShouldBeNil)
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir, 0, time.Minute)
+	a.So(err, assertions.ShouldBeNil)
+	for i := byte(1); i <= 3; i++ {
+		a.So(s.Add("broker", &pb_broker.UplinkMessage{Payload: []byte{i}}), assertions.ShouldBeNil)
+	}
+
+	// The messages that were not sent stay in the spool
+	sent, err := s.Drain("broker", func(entry Entry) error {
+		if entry.Uplink.Payload[0] == 2 {
+			return errors.New("disconnected")
+		}
+		return nil
+	})
+	a.So(err, assertions.ShouldBeNil)
+	a.So(sent, assertions.ShouldEqual, 1)
+
+	entries, err := take(s, "broker")
+	a.So(err, assertions.ShouldBeNil)
+	a.So(entries, assertions.ShouldHaveLength, 2)
+	a.So(entries[0].Uplink.Payload, assertions.ShouldResemble, []byte{2})
+	a.So(entries[1].Uplink.Payload, assertions.ShouldResemble, []byte{3})
+}
+
+func TestSpoolCorruptLength(t *testing.T) {
+	a := assertions.New(t)
+
+	dir, err := ioutil.TempDir("", "spool")
+	a.So(err, assertions.ShouldBeNil)
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir, 0, time.Minute)
+	a.So(err, assertions.ShouldBeNil)
+	a.So(s.Add("broker", &pb_broker.UplinkMessage{Payload: []byte{1}}), assertions.ShouldBeNil)
+
+	// A record with a length above the maximum is not read
+	header := make([]byte, headerSize)
+	binary.BigEndian.PutUint64(header, uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint32(header[8:], 0xffffffff)
+	f, err := os.OpenFile(s.path("broker"), os.O_WRONLY|os.O_APPEND, 0644)
+	a.So(err, assertions.ShouldBeNil)
+	f.Write(header)
+	f.Close()
+
+	entries, err := take(s, "broker")
+	a.So(err, assertions.ShouldBeNil)
+	a.So(entries, assertions.ShouldHaveLength, 1)
+	a.So(s.Size("broker"), assertions.ShouldEqual, 0)
+
+	// Messages above the maximum size are not spooled
+	a.So(s.Add("broker", &pb_broker.UplinkMessage{Payload: make([]byte, MaxMessageSize)}), assertions.ShouldNotBeNil)
+}
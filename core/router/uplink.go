@@ -9,14 +9,28 @@ import (
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	pb_broker "github.com/TheThingsNetwork/ttn/api/broker"
 	"github.com/TheThingsNetwork/ttn/api/fields"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
 	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
 	pb "github.com/TheThingsNetwork/ttn/api/router"
 	"github.com/TheThingsNetwork/ttn/api/trace"
 	"github.com/TheThingsNetwork/ttn/core/types"
 	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/TheThingsNetwork/ttn/utils/toa"
 	"github.com/brocaar/lorawan"
 )
 
+// setAirtime sets the time-on-air of a message that a gateway received in its
+// metadata, so that the Broker, NetworkServer and Handler do not compute it
+// again. Airtimes that the gateway reported are overwritten.
+func setAirtime(payload []byte, md *pb_protocol.RxMetadata) {
+	lorawan := md.GetLorawan()
+	if lorawan == nil {
+		return
+	}
+	airtime, _ := toa.ComputeLoRaWAN(uint(len(payload)), lorawan)
+	lorawan.Airtime = int64(airtime)
+}
+
 func (r *router) HandleUplink(gatewayID string, uplink *pb.UplinkMessage) (err error) {
 	ctx := r.Ctx.WithField("GatewayID", gatewayID).WithFields(fields.Get(uplink))
 	start := time.Now()
@@ -29,6 +43,7 @@ func (r *router) HandleUplink(gatewayID string, uplink *pb.UplinkMessage) (err e
 	r.status.uplink.Mark(1)
 
 	uplink.Trace = uplink.Trace.WithEvent(trace.ReceiveEvent, "gateway", gatewayID)
+	setAirtime(uplink.Payload, uplink.ProtocolMetadata)
 
 	// LoRaWAN: Unmarshal
 	var phyPayload lorawan.PHYPayload
@@ -93,6 +108,12 @@ func (r *router) HandleUplink(gatewayID string, uplink *pb.UplinkMessage) (err e
 		return err
 	}
 
+	if !r.filter.allowDevAddr(devAddr) {
+		ctx.Debug("Drop uplink of foreign DevAddr")
+		uplink.Trace = uplink.Trace.WithEvent(trace.DropEvent, "reason", "foreign DevAddr")
+		return nil
+	}
+
 	var downlinkOptions []*pb_broker.DownlinkOption
 	if gateway.Schedule.IsActive() {
 		downlinkOptions = r.buildDownlinkOptions(uplink, false, gateway)
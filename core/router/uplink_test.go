@@ -78,5 +78,9 @@ func TestHandleUplink(t *testing.T) {
 	rx, _ := utilization.Get()
 	a.So(rx, ShouldBeGreaterThan, 0)
 
+	// The airtime is computed once, by the Router
+	a.So(uplink.ProtocolMetadata.GetLorawan().Airtime, ShouldBeGreaterThan, 0)
+	a.So(int64(r.getGateway(gtwID).UplinkAirtime().Total), ShouldEqual, uplink.ProtocolMetadata.GetLorawan().Airtime)
+
 	// TODO: Integration test that checks broker forward
 }
@@ -6,6 +6,7 @@ package storage
 import (
 	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/redis.v5"
 )
@@ -198,6 +199,22 @@ func (s *RedisQueueStore) Trim(key string, length int) error {
 	return err
 }
 
+// Expire the entire queue after the given duration, prepending the prefix to the key if necessary
+func (s *RedisQueueStore) Expire(key string, expiration time.Duration) error {
+	if !strings.HasPrefix(key, s.prefix) {
+		key = s.prefix + key
+	}
+	return s.client.Expire(key, expiration).Err()
+}
+
+// Persist removes the expiry of the entire queue, prepending the prefix to the key if necessary
+func (s *RedisQueueStore) Persist(key string) error {
+	if !strings.HasPrefix(key, s.prefix) {
+		key = s.prefix + key
+	}
+	return s.client.Persist(key).Err()
+}
+
 // Delete the entire queue
 func (s *RedisQueueStore) Delete(key string) error {
 	if !strings.HasPrefix(key, s.prefix) {
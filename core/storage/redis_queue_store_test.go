@@ -5,6 +5,7 @@ package storage
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/assertions"
 )
@@ -86,6 +87,20 @@ func TestRedisQueueStore(t *testing.T) {
 	a.So(err, ShouldBeNil)
 	a.So(res, ShouldResemble, []string{"value1", "value3"})
 
+	err = s.Expire("test", time.Minute)
+	a.So(err, ShouldBeNil)
+
+	ttl, err := c.TTL("test-redis-queue-store:test").Result()
+	a.So(err, ShouldBeNil)
+	a.So(ttl, ShouldBeGreaterThan, 0)
+
+	err = s.Persist("test")
+	a.So(err, ShouldBeNil)
+
+	ttl, err = c.TTL("test-redis-queue-store:test").Result()
+	a.So(err, ShouldBeNil)
+	a.So(ttl, ShouldBeLessThan, 0)
+
 	err = s.Delete("test")
 	a.So(err, ShouldBeNil)
 
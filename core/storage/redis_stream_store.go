@@ -0,0 +1,273 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/redis.v5"
+)
+
+// StreamEntry is an entry in a Redis Stream
+type StreamEntry struct {
+	ID     string
+	Values map[string]string
+}
+
+// RedisStreamStore stores append-only streams in Redis Streams (requires Redis 5.0 or newer)
+type RedisStreamStore struct {
+	prefix string
+	client *redis.Client
+	maxLen int64
+}
+
+// NewRedisStreamStore creates a new RedisStreamStore. When maxLen is larger
+// than 0, each stream is trimmed to approximately that length.
+func NewRedisStreamStore(client *redis.Client, prefix string, maxLen int64) *RedisStreamStore {
+	if !strings.HasSuffix(prefix, ":") {
+		prefix += ":"
+	}
+	return &RedisStreamStore{
+		client: client,
+		prefix: prefix,
+		maxLen: maxLen,
+	}
+}
+
+func (s *RedisStreamStore) key(key string) string {
+	if !strings.HasPrefix(key, s.prefix) {
+		key = s.prefix + key
+	}
+	return key
+}
+
+// Add an entry to the stream and return its ID
+func (s *RedisStreamStore) Add(key string, values map[string]string) (string, error) {
+	args := []interface{}{"XADD", s.key(key)}
+	if s.maxLen > 0 {
+		args = append(args, "MAXLEN", "~", s.maxLen)
+	}
+	args = append(args, "*")
+	for k, v := range values {
+		args = append(args, k, v)
+	}
+	cmd := redis.NewStringCmd(args...)
+	s.client.Process(cmd)
+	return cmd.Result()
+}
+
+// Range returns at most count entries of the stream that come after the given
+// ID. An empty ID returns the entries from the start of the stream.
+func (s *RedisStreamStore) Range(key string, after string, count int64) ([]StreamEntry, error) {
+	start := "-"
+	if after != "" {
+		next, err := nextStreamID(after)
+		if err != nil {
+			return nil, err
+		}
+		start = next
+	}
+	args := []interface{}{"XRANGE", s.key(key), start, "+"}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	cmd := redis.NewSliceCmd(args...)
+	s.client.Process(cmd)
+	res, err := cmd.Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseStreamEntries(res)
+}
+
+// RevRange returns at most count entries of the stream that come before the
+// given ID, newest first. An empty ID returns the entries from the end of the stream.
+func (s *RedisStreamStore) RevRange(key string, before string, count int64) ([]StreamEntry, error) {
+	end := "+"
+	if before != "" {
+		prev, err := prevStreamID(before)
+		if err != nil {
+			return nil, err
+		}
+		if prev == "" {
+			return nil, nil
+		}
+		end = prev
+	}
+	args := []interface{}{"XREVRANGE", s.key(key), end, "-"}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	cmd := redis.NewSliceCmd(args...)
+	s.client.Process(cmd)
+	res, err := cmd.Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseStreamEntries(res)
+}
+
+// Last returns the ID of the last entry in the stream, or an empty string if the stream is empty
+func (s *RedisStreamStore) Last(key string) (string, error) {
+	cmd := redis.NewSliceCmd("XREVRANGE", s.key(key), "+", "-", "COUNT", 1)
+	s.client.Process(cmd)
+	res, err := cmd.Result()
+	if err != nil {
+		return "", err
+	}
+	entries, err := parseStreamEntries(res)
+	if err != nil || len(entries) == 0 {
+		return "", err
+	}
+	return entries[0].ID, nil
+}
+
+// CreateGroup creates a consumer group for the stream that starts reading
+// after the given ID ("0" for the start of the stream, "$" for new entries).
+// It is not an error if the group already exists.
+func (s *RedisStreamStore) CreateGroup(key string, group string, start string) error {
+	cmd := redis.NewStatusCmd("XGROUP", "CREATE", s.key(key), group, start, "MKSTREAM")
+	s.client.Process(cmd)
+	if err := cmd.Err(); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// ReadGroup returns at most count entries that were not yet delivered to the
+// consumer group. When pending is true, it returns the entries that were
+// delivered to the consumer but were not acknowledged instead.
+func (s *RedisStreamStore) ReadGroup(key string, group string, consumer string, count int64, pending bool) ([]StreamEntry, error) {
+	args := []interface{}{"XREADGROUP", "GROUP", group, consumer}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	id := ">"
+	if pending {
+		id = "0"
+	}
+	args = append(args, "STREAMS", s.key(key), id)
+	cmd := redis.NewSliceCmd(args...)
+	s.client.Process(cmd)
+	res, err := cmd.Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The result contains the entries per stream
+	for _, streamI := range res {
+		stream, ok := streamI.([]interface{})
+		if !ok || len(stream) != 2 {
+			return nil, fmt.Errorf("Invalid stream in Redis response: %v", streamI)
+		}
+		entries, ok := stream[1].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Invalid stream entries in Redis response: %v", stream[1])
+		}
+		return parseStreamEntries(entries)
+	}
+	return nil, nil
+}
+
+// Ack acknowledges the entries with the given IDs for the consumer group
+func (s *RedisStreamStore) Ack(key string, group string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := []interface{}{"XACK", s.key(key), group}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	cmd := redis.NewIntCmd(args...)
+	s.client.Process(cmd)
+	return cmd.Err()
+}
+
+// DeleteEntries deletes the entries with the given IDs from the stream
+func (s *RedisStreamStore) DeleteEntries(key string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := []interface{}{"XDEL", s.key(key)}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	cmd := redis.NewIntCmd(args...)
+	s.client.Process(cmd)
+	return cmd.Err()
+}
+
+// Delete the entire stream, including its consumer groups
+func (s *RedisStreamStore) Delete(key string) error {
+	return s.client.Del(s.key(key)).Err()
+}
+
+func parseStreamEntries(res []interface{}) ([]StreamEntry, error) {
+	entries := make([]StreamEntry, 0, len(res))
+	for _, entryI := range res {
+		entry, ok := entryI.([]interface{})
+		if !ok || len(entry) != 2 {
+			return nil, fmt.Errorf("Invalid stream entry in Redis response: %v", entryI)
+		}
+		id, ok := entry[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("Invalid stream entry ID in Redis response: %v", entry[0])
+		}
+		fields, _ := entry[1].([]interface{})
+		values := make(map[string]string, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			k, _ := fields[i].(string)
+			v, _ := fields[i+1].(string)
+			values[k] = v
+		}
+		entries = append(entries, StreamEntry{ID: id, Values: values})
+	}
+	return entries, nil
+}
+
+// nextStreamID returns the smallest stream ID that is larger than the given ID
+func nextStreamID(id string) (string, error) {
+	parts := strings.SplitN(id, "-", 2)
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("Invalid stream ID %s", id)
+	}
+	if len(parts) == 1 {
+		return fmt.Sprintf("%d-%d", ms+1, 0), nil
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("Invalid stream ID %s", id)
+	}
+	return fmt.Sprintf("%d-%d", ms, seq+1), nil
+}
+
+// prevStreamID returns the largest stream ID that is smaller than the given
+// ID, or an empty string if there is no such ID
+func prevStreamID(id string) (string, error) {
+	parts := strings.SplitN(id, "-", 2)
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("Invalid stream ID %s", id)
+	}
+	var seq uint64
+	if len(parts) == 2 {
+		if seq, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+			return "", fmt.Errorf("Invalid stream ID %s", id)
+		}
+	}
+	switch {
+	case seq > 0:
+		return fmt.Sprintf("%d-%d", ms, seq-1), nil
+	case ms > 0:
+		return fmt.Sprintf("%d-%d", ms-1, uint64(math.MaxUint64)), nil
+	default:
+		return "", nil
+	}
+}
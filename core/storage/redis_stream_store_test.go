@@ -0,0 +1,135 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestNextStreamID(t *testing.T) {
+	a := New(t)
+
+	next, err := nextStreamID("1490000000000-0")
+	a.So(err, ShouldBeNil)
+	a.So(next, ShouldEqual, "1490000000000-1")
+
+	next, err = nextStreamID("1490000000000")
+	a.So(err, ShouldBeNil)
+	a.So(next, ShouldEqual, "1490000000001-0")
+
+	_, err = nextStreamID("cursor")
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestPrevStreamID(t *testing.T) {
+	a := New(t)
+
+	prev, err := prevStreamID("1490000000000-1")
+	a.So(err, ShouldBeNil)
+	a.So(prev, ShouldEqual, "1490000000000-0")
+
+	prev, err = prevStreamID("1490000000000-0")
+	a.So(err, ShouldBeNil)
+	a.So(prev, ShouldEqual, "1489999999999-18446744073709551615")
+
+	prev, err = prevStreamID("0-0")
+	a.So(err, ShouldBeNil)
+	a.So(prev, ShouldBeEmpty)
+
+	_, err = prevStreamID("cursor")
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestRedisStreamStore(t *testing.T) {
+	a := New(t)
+	c := getRedisClient()
+	s := NewRedisStreamStore(c, "test-redis-stream-store", 100)
+	a.So(s, ShouldNotBeNil)
+
+	defer func() {
+		c.Del("test-redis-stream-store:test").Result()
+	}()
+
+	entries, err := s.Range("test", "", 0)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldBeEmpty)
+
+	last, err := s.Last("test")
+	a.So(err, ShouldBeNil)
+	a.So(last, ShouldBeEmpty)
+
+	id1, err := s.Add("test", map[string]string{"value": "1"})
+	a.So(err, ShouldBeNil)
+	id2, err := s.Add("test", map[string]string{"value": "2"})
+	a.So(err, ShouldBeNil)
+	id3, err := s.Add("test", map[string]string{"value": "3"})
+	a.So(err, ShouldBeNil)
+
+	last, err = s.Last("test")
+	a.So(err, ShouldBeNil)
+	a.So(last, ShouldEqual, id3)
+
+	entries, err = s.Range("test", "", 0)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldResemble, []StreamEntry{
+		{ID: id1, Values: map[string]string{"value": "1"}},
+		{ID: id2, Values: map[string]string{"value": "2"}},
+		{ID: id3, Values: map[string]string{"value": "3"}},
+	})
+
+	// Replay after a cursor
+	entries, err = s.Range("test", id1, 1)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldHaveLength, 1)
+	a.So(entries[0].ID, ShouldEqual, id2)
+
+	// Read backwards
+	entries, err = s.RevRange("test", "", 2)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldHaveLength, 2)
+	a.So(entries[0].ID, ShouldEqual, id3)
+	a.So(entries[1].ID, ShouldEqual, id2)
+
+	entries, err = s.RevRange("test", id2, 0)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldHaveLength, 1)
+	a.So(entries[0].ID, ShouldEqual, id1)
+
+	// Consumer groups
+	a.So(s.CreateGroup("test", "group", "0"), ShouldBeNil)
+	a.So(s.CreateGroup("test", "group", "0"), ShouldBeNil)
+
+	entries, err = s.ReadGroup("test", "group", "consumer", 2, false)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldHaveLength, 2)
+	a.So(entries[0].ID, ShouldEqual, id1)
+
+	entries, err = s.ReadGroup("test", "group", "consumer", 0, false)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldHaveLength, 1)
+	a.So(entries[0].ID, ShouldEqual, id3)
+
+	a.So(s.Ack("test", "group", id1, id2), ShouldBeNil)
+
+	entries, err = s.ReadGroup("test", "group", "consumer", 0, true)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldHaveLength, 1)
+	a.So(entries[0].ID, ShouldEqual, id3)
+
+	entries, err = s.ReadGroup("test", "group", "consumer", 0, false)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldBeEmpty)
+
+	a.So(s.DeleteEntries("test", id2), ShouldBeNil)
+	entries, err = s.Range("test", "", 0)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldHaveLength, 2)
+
+	a.So(s.Delete("test"), ShouldBeNil)
+	entries, err = s.Range("test", "", 0)
+	a.So(err, ShouldBeNil)
+	a.So(entries, ShouldBeEmpty)
+}
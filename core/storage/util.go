@@ -17,6 +17,12 @@ func (o ListOptions) GetTotalAndSelected() (total, selected int) {
 	return o.total, o.selected
 }
 
+// SelectKeys selects the keys that fall within the limit and offset of the ListOptions. This
+// can be used by store implementations that do not use Redis.
+func SelectKeys(keys []string, options *ListOptions) []string {
+	return selectKeys(keys, options)
+}
+
 func selectKeys(keys []string, options *ListOptions) []string {
 	var start int
 	var end = len(keys)
@@ -0,0 +1,45 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import "fmt"
+
+// Modes for the acknowledgement of confirmed uplink messages
+const (
+	// AckModeAuto acknowledges confirmed uplink messages with an empty downlink if no downlink message is queued
+	AckModeAuto = "auto"
+	// AckModeDisabled only acknowledges confirmed uplink messages with downlink messages of the application
+	AckModeDisabled = "disabled"
+	// AckModePayload acknowledges confirmed uplink messages with the payload of the AckSettings if no downlink message is queued
+	AckModePayload = "payload"
+)
+
+// AckSettings configures how confirmed uplink messages of a device are acknowledged
+type AckSettings struct {
+	// Mode is AckModeAuto (default), AckModeDisabled or AckModePayload
+	Mode string `json:"mode,omitempty"`
+
+	// The payload of the acknowledgement in AckModePayload. PayloadFields
+	// are encoded with the encoder of the application.
+	FPort         uint8                  `json:"port,omitempty"`
+	PayloadRaw    []byte                 `json:"payload_raw,omitempty"`
+	PayloadFields map[string]interface{} `json:"payload_fields,omitempty"`
+}
+
+// Validate the settings
+func (s AckSettings) Validate() error {
+	switch s.Mode {
+	case "", AckModeAuto, AckModeDisabled:
+	case AckModePayload:
+		if len(s.PayloadRaw) == 0 && len(s.PayloadFields) == 0 {
+			return fmt.Errorf("Mode %s needs a payload_raw or payload_fields", s.Mode)
+		}
+		if s.FPort == 0 || s.FPort > 223 {
+			return fmt.Errorf("Invalid port %d", s.FPort)
+		}
+	default:
+		return fmt.Errorf("Invalid mode %s", s.Mode)
+	}
+	return nil
+}
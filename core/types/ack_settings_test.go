@@ -0,0 +1,24 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestAckSettingsValidate(t *testing.T) {
+	a := New(t)
+	a.So(AckSettings{}.Validate(), ShouldBeNil)
+	a.So(AckSettings{Mode: AckModeAuto}.Validate(), ShouldBeNil)
+	a.So(AckSettings{Mode: AckModeDisabled}.Validate(), ShouldBeNil)
+	a.So(AckSettings{Mode: AckModePayload, FPort: 1, PayloadRaw: []byte{0x01}}.Validate(), ShouldBeNil)
+	a.So(AckSettings{Mode: AckModePayload, FPort: 2, PayloadFields: map[string]interface{}{"ack": true}}.Validate(), ShouldBeNil)
+
+	a.So(AckSettings{Mode: "sometimes"}.Validate(), ShouldNotBeNil)
+	a.So(AckSettings{Mode: AckModePayload, FPort: 1}.Validate(), ShouldNotBeNil)
+	a.So(AckSettings{Mode: AckModePayload, PayloadRaw: []byte{0x01}}.Validate(), ShouldNotBeNil)
+	a.So(AckSettings{Mode: AckModePayload, FPort: 224, PayloadRaw: []byte{0x01}}.Validate(), ShouldNotBeNil)
+}
@@ -18,9 +18,10 @@ type DataRate struct {
 	Bandwidth       uint `json:"bandwidth,omitempty"`
 }
 
-// ParseDataRate parses a 32-bit hex-encoded string to a Devdatr
+// ParseDataRate parses a 32-bit hex-encoded string to a Devdatr. LoRa in the
+// 2.4 GHz band uses 812.5 kHz bandwidth, which is written as BW812.
 func ParseDataRate(input string) (datr *DataRate, err error) {
-	re := regexp.MustCompile("SF(7|8|9|10|11|12)BW(125|250|500)")
+	re := regexp.MustCompile("SF(5|6|7|8|9|10|11|12)BW(125|250|500|812)")
 	matches := re.FindStringSubmatch(input)
 	if len(matches) != 3 {
 		return nil, errors.New("ttn/core: Invalid DataRate")
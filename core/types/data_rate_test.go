@@ -57,6 +57,14 @@ func TestDataRate(t *testing.T) {
 	_, err = ParseDataRate("")
 	a.So(err, ShouldNotBeNil)
 
+	// 2.4 GHz
+	pOut, err = ParseDataRate("SF5BW812")
+	a.So(err, ShouldBeNil)
+	a.So(*pOut, ShouldResemble, DataRate{5, 812})
+	a.So(pOut.String(), ShouldEqual, "SF5BW812")
+	_, err = ParseDataRate("SF4BW812")
+	a.So(err, ShouldNotBeNil)
+
 	// Convert
 	dr := band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: 7, Bandwidth: 125}
 	cOut, err := ConvertDataRate(dr)
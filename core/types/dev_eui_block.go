@@ -0,0 +1,74 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// DevEUIBlock is a block of DevEUIs that is assigned to a network or a manufacturer
+type DevEUIBlock struct {
+	DevEUI DevEUI
+	Length int
+}
+
+// ParseDevEUIBlock parses a DevEUI in prefix notation (70B3D57ED0000000/36) to a block
+func ParseDevEUIBlock(blockString string) (block DevEUIBlock, err error) {
+	pattern := regexp.MustCompile("^([[:xdigit:]]{16})/([[:digit:]]+)$")
+	matches := pattern.FindStringSubmatch(blockString)
+	if len(matches) != 3 {
+		err = errors.New("Invalid DevEUI block")
+		return
+	}
+	eui, _ := ParseDevEUI(matches[1])          // errors handled in regexp
+	block.Length, _ = strconv.Atoi(matches[2]) // errors handled in regexp
+	if block.Length < 1 || block.Length > 63 {
+		err = errors.New("Invalid DevEUI block: the prefix length must be between 1 and 63")
+		return
+	}
+	block.DevEUI = block.mask(eui)
+	return
+}
+
+func (block DevEUIBlock) mask(eui DevEUI) DevEUI {
+	var masked DevEUI
+	binary.BigEndian.PutUint64(masked[:], binary.BigEndian.Uint64(eui[:])&^(^uint64(0)>>uint(block.Length)))
+	return masked
+}
+
+// String implements the fmt.Stringer interface
+func (block DevEUIBlock) String() string {
+	return fmt.Sprintf("%X/%d", block.DevEUI.Bytes(), block.Length)
+}
+
+// Size returns the number of DevEUIs in the block
+func (block DevEUIBlock) Size() uint64 {
+	return uint64(1) << uint(64-block.Length)
+}
+
+// Contains returns true if the DevEUI is in the block
+func (block DevEUIBlock) Contains(eui DevEUI) bool {
+	return block.mask(eui) == block.DevEUI
+}
+
+// Index returns the position of the DevEUI in the block
+func (block DevEUIBlock) Index(eui DevEUI) (uint64, bool) {
+	if !block.Contains(eui) {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(eui[:]) - binary.BigEndian.Uint64(block.DevEUI[:]), true
+}
+
+// Get returns the DevEUI at the given position in the block
+func (block DevEUIBlock) Get(index uint64) (eui DevEUI, ok bool) {
+	if index >= block.Size() {
+		return eui, false
+	}
+	binary.BigEndian.PutUint64(eui[:], binary.BigEndian.Uint64(block.DevEUI[:])+index)
+	return eui, true
+}
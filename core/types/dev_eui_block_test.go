@@ -0,0 +1,39 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestDevEUIBlock(t *testing.T) {
+	a := New(t)
+
+	block, err := ParseDevEUIBlock("70B3D57ED00000FF/56")
+	a.So(err, ShouldBeNil)
+	a.So(block.DevEUI, ShouldEqual, DevEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x00})
+	a.So(block.String(), ShouldEqual, "70B3D57ED0000000/56")
+	a.So(block.Size(), ShouldEqual, 256)
+
+	eui, ok := block.Get(0x12)
+	a.So(ok, ShouldBeTrue)
+	a.So(eui, ShouldEqual, DevEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x00, 0x12})
+	a.So(block.Contains(eui), ShouldBeTrue)
+	idx, ok := block.Index(eui)
+	a.So(ok, ShouldBeTrue)
+	a.So(idx, ShouldEqual, 0x12)
+
+	_, ok = block.Get(256)
+	a.So(ok, ShouldBeFalse)
+	a.So(block.Contains(DevEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x01, 0x00}), ShouldBeFalse)
+	_, ok = block.Index(DevEUI{0x70, 0xB3, 0xD5, 0x7E, 0xD0, 0x00, 0x01, 0x00})
+	a.So(ok, ShouldBeFalse)
+
+	for _, invalid := range []string{"", "70B3D57ED0000000", "70B3D57ED0000000/0", "70B3D57ED0000000/64", "70B3D57ED000/24"} {
+		_, err = ParseDevEUIBlock(invalid)
+		a.So(err, ShouldNotBeNil)
+	}
+}
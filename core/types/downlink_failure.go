@@ -0,0 +1,39 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+// DownlinkFailureReason is the reason why a downlink message could not be sent
+type DownlinkFailureReason string
+
+// Downlink failure reasons
+const (
+	// DownlinkFailureNoGateway means that no gateway is connected that can send the message
+	DownlinkFailureNoGateway DownlinkFailureReason = "no_gateway"
+	// DownlinkFailureDutyCycle means that sending the message would exceed the duty cycle of the gateway
+	DownlinkFailureDutyCycle DownlinkFailureReason = "duty_cycle"
+	// DownlinkFailureTooLate means that the message arrived after the receive window of the device
+	DownlinkFailureTooLate DownlinkFailureReason = "too_late"
+	// DownlinkFailurePayloadTooLarge means that the payload exceeds the maximum size or dwell time
+	DownlinkFailurePayloadTooLarge DownlinkFailureReason = "payload_too_large"
+	// DownlinkFailureInvalidSettings means that the frequency plan of the gateway does not allow the transmission
+	DownlinkFailureInvalidSettings DownlinkFailureReason = "invalid_settings"
+	// DownlinkFailureAirtimeBudget means that the application exceeded its downlink airtime budget on the gateway
+	DownlinkFailureAirtimeBudget DownlinkFailureReason = "airtime_budget"
+	// DownlinkFailureNetworkServer means that the NetworkServer did not accept the message
+	DownlinkFailureNetworkServer DownlinkFailureReason = "network_server"
+	// DownlinkFailurePreempted means that the message was preempted by a downlink with a higher priority on the gateway
+	DownlinkFailurePreempted DownlinkFailureReason = "preempted"
+)
+
+// DownlinkFailure is reported to the Handler by the Broker and Router when a
+// downlink message could not be scheduled
+type DownlinkFailure struct {
+	AppID       string                `json:"app_id"`
+	DevID       string                `json:"dev_id"`
+	Reason      DownlinkFailureReason `json:"reason"`
+	Error       string                `json:"error,omitempty"`
+	Component   string                `json:"component,omitempty"`
+	GatewayID   string                `json:"gateway_id,omitempty"`
+	PreemptedBy DownlinkPriority      `json:"preempted_by,omitempty"`
+}
@@ -13,6 +13,41 @@ const (
 	ScheduleLast    ScheduleType = "last"
 )
 
+// RXWindow can be "rx1" or "rx2"
+type RXWindow string
+
+// RXWindows
+const (
+	RX1Window RXWindow = "rx1"
+	RX2Window RXWindow = "rx2"
+)
+
+// DownlinkPriority can be "low", "normal" (default), "high" or "mac"
+type DownlinkPriority string
+
+// DownlinkPriorities
+const (
+	DownlinkPriorityLow    DownlinkPriority = "low"
+	DownlinkPriorityNormal DownlinkPriority = "normal"
+	DownlinkPriorityHigh   DownlinkPriority = "high"
+	DownlinkPriorityMAC    DownlinkPriority = "mac" // reserved for downlinks with MAC commands
+)
+
+// Level of the priority. When the transmissions of two downlinks conflict at
+// the gateway, the downlink with the highest level is sent.
+func (p DownlinkPriority) Level() int {
+	switch p {
+	case DownlinkPriorityLow:
+		return 0
+	case DownlinkPriorityHigh:
+		return 2
+	case DownlinkPriorityMAC:
+		return 3
+	default:
+		return 1
+	}
+}
+
 // DownlinkMessage represents an application-layer downlink message
 type DownlinkMessage struct {
 	AppID         string                 `json:"app_id,omitempty"`
@@ -22,4 +57,13 @@ type DownlinkMessage struct {
 	Schedule      ScheduleType           `json:"schedule,omitempty"` // allowed values: "replace" (default), "first", "last"
 	PayloadRaw    []byte                 `json:"payload_raw,omitempty"`
 	PayloadFields map[string]interface{} `json:"payload_fields,omitempty"`
+	Priority      DownlinkPriority       `json:"priority,omitempty"` // allowed values: "low", "normal" (default), "high"
+
+	// Scheduling hints; the downlink stays in the queue until they can be satisfied
+	RXWindow  RXWindow  `json:"rx_window,omitempty"`  // allowed values: "rx1", "rx2"
+	GatewayID string    `json:"gateway_id,omitempty"` // the gateway that should transmit the downlink
+	Time      *JSONTime `json:"time,omitempty"`       // absolute transmission time (Class B/C)
+
+	// FCnt is the downlink counter with which PayloadRaw is encrypted, for devices with end-to-end encryption
+	FCnt uint32 `json:"counter,omitempty"`
 }
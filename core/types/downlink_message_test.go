@@ -0,0 +1,18 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestDownlinkPriorityLevel(t *testing.T) {
+	a := New(t)
+	a.So(DownlinkPriorityLow.Level(), ShouldBeLessThan, DownlinkPriorityNormal.Level())
+	a.So(DownlinkPriorityNormal.Level(), ShouldBeLessThan, DownlinkPriorityHigh.Level())
+	a.So(DownlinkPriorityHigh.Level(), ShouldBeLessThan, DownlinkPriorityMAC.Level())
+	a.So(DownlinkPriority("").Level(), ShouldEqual, DownlinkPriorityNormal.Level())
+}
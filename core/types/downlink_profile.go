@@ -0,0 +1,25 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import "fmt"
+
+// DownlinkProfile configures how the downlink messages of a device are scheduled
+type DownlinkProfile struct {
+	// RX2Only schedules all downlink messages in RX2, for devices that never receive RX1
+	RX2Only bool `json:"rx2_only,omitempty"`
+	// RX2DataRate is the data rate of RX2 (for example SF9BW125). The RX2 data rate
+	// of the last join-accept takes precedence, the default is that of the band.
+	RX2DataRate string `json:"rx2_data_rate,omitempty"`
+}
+
+// Validate the profile, independent of the band
+func (p DownlinkProfile) Validate() error {
+	if p.RX2DataRate != "" {
+		if _, err := ParseDataRate(p.RX2DataRate); err != nil {
+			return fmt.Errorf("Invalid RX2 data rate %s", p.RX2DataRate)
+		}
+	}
+	return nil
+}
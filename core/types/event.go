@@ -10,17 +10,35 @@ type EventType string
 const (
 	UplinkErrorEvent EventType = "up/errors"
 
-	DownlinkScheduledEvent EventType = "down/scheduled"
-	DownlinkSentEvent      EventType = "down/sent"
-	DownlinkErrorEvent     EventType = "down/errors"
-	DownlinkAckEvent       EventType = "down/acks"
+	DownlinkScheduledEvent   EventType = "down/scheduled"
+	DownlinkSentEvent        EventType = "down/sent"
+	DownlinkErrorEvent       EventType = "down/errors"
+	DownlinkAckEvent         EventType = "down/acks"
+	DownlinkRescheduledEvent EventType = "down/rescheduled"
+	DownlinkInvalidatedEvent EventType = "down/invalidated"
 
 	ActivationEvent      EventType = "activations"
 	ActivationErrorEvent EventType = "activations/errors"
+	ActivationLoopEvent  EventType = "activations/loops"
 
 	CreateEvent EventType = "create"
 	UpdateEvent EventType = "update"
 	DeleteEvent EventType = "delete"
+
+	PolicyViolationEvent EventType = "policy/violations"
+
+	RuleAlertEvent EventType = "rules/alerts"
+
+	LinkQualityEvent EventType = "status/link-quality"
+	DeviceResetEvent EventType = "status/reset"
+
+	ReplayAttackEvent   EventType = "security/replays"
+	DeviceConflictEvent EventType = "security/conflicts"
+
+	QuotaWarningEvent  EventType = "quota/warnings"
+	QuotaExceededEvent EventType = "quota/exceeded"
+
+	EventCursorEvent EventType = "cursor"
 )
 
 // DeviceEvent represents an application-layer event message for a device event
@@ -43,6 +61,23 @@ type ActivationEventData struct {
 	DevEUI   DevEUI   `json:"dev_eui"`
 	DevAddr  DevAddr  `json:"dev_addr"`
 	Metadata Metadata `json:"metadata"`
+	// EndToEnd is added for devices with end-to-end encryption
+	EndToEnd *EndToEndSession `json:"end_to_end,omitempty"`
+}
+
+// EndToEndSession contains the parameters of an activation that the
+// application needs to derive the AppSKey of the session
+type EndToEndSession struct {
+	AppNonce AppNonce `json:"app_nonce"`
+	NetID    NetID    `json:"net_id"`
+	DevNonce DevNonce `json:"dev_nonce"`
+}
+
+// ActivationLoopEventData is added to activation loop events
+type ActivationLoopEventData struct {
+	AppEUI  AppEUI `json:"app_eui"`
+	DevEUI  DevEUI `json:"dev_eui"`
+	Penalty string `json:"penalty"`
 }
 
 // DownlinkEventConfigInfo contains configuration information for a downlink message, all fields are optional
@@ -62,4 +97,89 @@ type DownlinkEventData struct {
 	Message   *DownlinkMessage        `json:"message,omitempty"`
 	GatewayID string                  `json:"gateway_id,omitempty"`
 	Config    DownlinkEventConfigInfo `json:"config,omitempty"`
+
+	// MaxPayloadSize is the maximum application payload size if the payload
+	// was too large. It is set even if no application payload fits.
+	MaxPayloadSize *int `json:"max_payload_size,omitempty"`
+
+	// Reason and Component explain why and where a downlink message could
+	// not be sent, for example no_gateway in the router
+	Reason    DownlinkFailureReason `json:"reason,omitempty"`
+	Component string                `json:"component,omitempty"`
+
+	// Deferred is true if the message was put back in the queue, to be sent
+	// after a next uplink message
+	Deferred bool `json:"deferred,omitempty"`
+}
+
+// DownlinkRescheduledEventData is added to downlink rescheduled events
+type DownlinkRescheduledEventData struct {
+	Message     *DownlinkMessage `json:"message,omitempty"`
+	PreemptedBy DownlinkPriority `json:"preempted_by"`
+}
+
+// DownlinkInvalidatedEventData is added to downlink invalidated events, which
+// are published when the downlink messages of a device with end-to-end
+// encryption are not encrypted for the downlink counter
+type DownlinkInvalidatedEventData struct {
+	Messages []*DownlinkMessage `json:"messages"`
+	// FCnt is the downlink counter of the next downlink message
+	FCnt uint32 `json:"counter"`
+}
+
+// PolicyViolationEventData is added to policy violation events
+type PolicyViolationEventData struct {
+	Policy    string `json:"policy"`
+	Violation string `json:"violation"`
+	Since     string `json:"since,omitempty"`
+}
+
+// LinkQualityEventData is added to link quality events, which are published
+// when the quality level of the link of a device changes
+type LinkQualityEventData struct {
+	Quality  string  `json:"quality"`
+	Previous string  `json:"previous"`
+	Score    int     `json:"score"`
+	Loss     float64 `json:"loss"`
+}
+
+// DeviceResetEventData is added to device reset events, which are published
+// when an ABP device indicates that it rebooted
+type DeviceResetEventData struct {
+	LoRaWANVersion string `json:"lorawan_version"`
+}
+
+// RuleAlertEventData is added to rule alert events
+type RuleAlertEventData struct {
+	RuleID string                 `json:"rule_id"`
+	Alert  string                 `json:"alert"`
+	Fields map[string]interface{} `json:"payload_fields,omitempty"`
+}
+
+// ReplayAttackEventData is added to replay attack events
+type ReplayAttackEventData struct {
+	Distance         string   `json:"distance"`
+	Gateways         []string `json:"gateways,omitempty"`
+	QuarantineReason string   `json:"quarantine_reason,omitempty"`
+	QuarantinedSince string   `json:"quarantined_since,omitempty"`
+}
+
+// DeviceConflictEventData is added to device conflict events
+type DeviceConflictEventData struct {
+	Reason  string   `json:"reason"`
+	Devices []string `json:"devices,omitempty"`
+}
+
+// EventCursorEventData is the retained event with the cursor of the last
+// message in the event stream of an application
+type EventCursorEventData struct {
+	Cursor string `json:"cursor"`
+}
+
+// QuotaEventData is added to quota events
+type QuotaEventData struct {
+	Metric      string `json:"metric"`
+	Usage       int64  `json:"usage"`
+	Limit       int64  `json:"limit"`
+	PeriodStart string `json:"period_start"`
 }
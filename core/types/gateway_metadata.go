@@ -5,13 +5,14 @@ package types
 
 // GatewayMetadata contains metadata for each gateway that received a message
 type GatewayMetadata struct {
-	GtwID      string   `json:"gtw_id,omitempty"`
-	GtwTrusted bool     `json:"gtw_trusted,omitempty"`
-	Timestamp  uint32   `json:"timestamp,omitempty"`
-	Time       JSONTime `json:"time,omitempty"`
-	Channel    uint32   `json:"channel"`
-	RSSI       float32  `json:"rssi,omitempty"`
-	SNR        float32  `json:"snr,omitempty"`
-	RFChain    uint32   `json:"rf_chain,omitempty"`
+	GtwID        string   `json:"gtw_id,omitempty"`
+	GtwTrusted   bool     `json:"gtw_trusted,omitempty"`
+	GtwUntrusted bool     `json:"gtw_untrusted,omitempty"`
+	Timestamp    uint32   `json:"timestamp,omitempty"`
+	Time         JSONTime `json:"time,omitempty"`
+	Channel      uint32   `json:"channel"`
+	RSSI         float32  `json:"rssi,omitempty"`
+	SNR          float32  `json:"snr,omitempty"`
+	RFChain      uint32   `json:"rf_chain,omitempty"`
 	LocationMetadata
 }
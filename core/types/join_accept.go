@@ -0,0 +1,52 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import "fmt"
+
+// JoinAcceptSettings are the receive window parameters that are sent to a
+// device in the join-accept. Zero values mean the default of the band.
+type JoinAcceptSettings struct {
+	// RX1Delay is the delay in seconds between the end of an uplink and RX1 (1-15)
+	RX1Delay uint8 `json:"rx1_delay,omitempty"`
+	// RX1DROffset is the offset between the uplink data rate and the RX1 data rate (0-7)
+	RX1DROffset uint8 `json:"rx1_dr_offset,omitempty"`
+	// RX2DataRate is the data rate of RX2 (for example SF9BW125)
+	RX2DataRate string `json:"rx2_data_rate,omitempty"`
+}
+
+// IsEmpty returns true if the settings are the defaults of the band
+func (s JoinAcceptSettings) IsEmpty() bool {
+	return s == JoinAcceptSettings{}
+}
+
+// Validate the settings, independent of the band
+func (s JoinAcceptSettings) Validate() error {
+	if s.RX1Delay > 15 {
+		return fmt.Errorf("Invalid RX1 delay %d: must be at most 15 seconds", s.RX1Delay)
+	}
+	if s.RX1DROffset > 7 {
+		return fmt.Errorf("Invalid RX1 data rate offset %d: must be at most 7", s.RX1DROffset)
+	}
+	if s.RX2DataRate != "" {
+		if _, err := ParseDataRate(s.RX2DataRate); err != nil {
+			return fmt.Errorf("Invalid RX2 data rate %s", s.RX2DataRate)
+		}
+	}
+	return nil
+}
+
+// Merge returns the settings with the non-zero values of other taking precedence
+func (s JoinAcceptSettings) Merge(other JoinAcceptSettings) JoinAcceptSettings {
+	if other.RX1Delay != 0 {
+		s.RX1Delay = other.RX1Delay
+	}
+	if other.RX1DROffset != 0 {
+		s.RX1DROffset = other.RX1DROffset
+	}
+	if other.RX2DataRate != "" {
+		s.RX2DataRate = other.RX2DataRate
+	}
+	return s
+}
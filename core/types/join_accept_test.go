@@ -0,0 +1,26 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestJoinAcceptSettings(t *testing.T) {
+	a := New(t)
+
+	a.So(JoinAcceptSettings{}.IsEmpty(), ShouldBeTrue)
+	a.So(JoinAcceptSettings{}.Validate(), ShouldBeNil)
+	a.So(JoinAcceptSettings{RX1Delay: 5, RX1DROffset: 2, RX2DataRate: "SF9BW125"}.Validate(), ShouldBeNil)
+	a.So(JoinAcceptSettings{RX1Delay: 16}.Validate(), ShouldNotBeNil)
+	a.So(JoinAcceptSettings{RX1DROffset: 8}.Validate(), ShouldNotBeNil)
+	a.So(JoinAcceptSettings{RX2DataRate: "SF13BW125"}.Validate(), ShouldNotBeNil)
+
+	app := JoinAcceptSettings{RX1Delay: 5, RX2DataRate: "SF9BW125"}
+	merged := app.Merge(JoinAcceptSettings{RX1DROffset: 1, RX2DataRate: "SF12BW125"})
+	a.So(merged, ShouldResemble, JoinAcceptSettings{RX1Delay: 5, RX1DROffset: 1, RX2DataRate: "SF12BW125"})
+	a.So(app.Merge(JoinAcceptSettings{}), ShouldResemble, app)
+}
@@ -11,6 +11,7 @@ type Metadata struct {
 	DataRate   string            `json:"data_rate,omitempty"`
 	Bitrate    uint32            `json:"bit_rate,omitempty"`
 	CodingRate string            `json:"coding_rate,omitempty"`
+	Airtime    int64             `json:"airtime,omitempty"` // time-on-air in nanoseconds
 	Gateways   []GatewayMetadata `json:"gateways,omitempty"`
 	LocationMetadata
 }
@@ -0,0 +1,42 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// Migration moves the devices of an application from one Handler to another.
+// A percentage of the devices, or an explicit list of devices, is served by
+// the new Handler while the other devices stay on the old Handler.
+type Migration struct {
+	AppID       string    `json:"app_id"`
+	FromHandler string    `json:"from_handler,omitempty"`
+	ToHandler   string    `json:"to_handler"`
+	Percentage  int       `json:"percentage,omitempty"`
+	Devices     []string  `json:"devices,omitempty"`
+	StartedAt   time.Time `json:"started_at,omitempty"`
+	SyncedAt    time.Time `json:"synced_at,omitempty"`
+}
+
+// Migrated returns true if the device is served by the new Handler. Devices
+// that are not in the explicit list are selected by a hash of their ID, so
+// that increasing the percentage only adds devices.
+func (m *Migration) Migrated(devID string) bool {
+	if m == nil || devID == "" {
+		return false
+	}
+	for _, id := range m.Devices {
+		if id == devID {
+			return true
+		}
+	}
+	if m.Percentage <= 0 {
+		return false
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(m.AppID + ":" + devID))
+	return int(hash.Sum32()%100) < m.Percentage
+}
@@ -0,0 +1,45 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestMigrationMigrated(t *testing.T) {
+	a := New(t)
+
+	var m *Migration
+	a.So(m.Migrated("dev"), ShouldBeFalse)
+
+	m = &Migration{AppID: "app", ToHandler: "new", Devices: []string{"dev-1"}}
+	a.So(m.Migrated("dev-1"), ShouldBeTrue)
+	a.So(m.Migrated("dev-2"), ShouldBeFalse)
+	a.So(m.Migrated(""), ShouldBeFalse)
+
+	count := func(percentage int) (migrated int) {
+		m := &Migration{AppID: "app", ToHandler: "new", Percentage: percentage}
+		for i := 0; i < 1000; i++ {
+			if m.Migrated(fmt.Sprintf("dev-%d", i)) {
+				migrated++
+			}
+		}
+		return
+	}
+	a.So(count(0), ShouldEqual, 0)
+	a.So(count(10), ShouldBeBetween, 50, 150)
+	a.So(count(100), ShouldEqual, 1000)
+
+	// Increasing the percentage only adds devices
+	m10 := &Migration{AppID: "app", Percentage: 10}
+	m50 := &Migration{AppID: "app", Percentage: 50}
+	for i := 0; i < 1000; i++ {
+		if devID := fmt.Sprintf("dev-%d", i); m10.Migrated(devID) {
+			a.So(m50.Migrated(devID), ShouldBeTrue)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+)
+
+// PayloadFormat is the serialization format of messages that are published to applications
+type PayloadFormat string
+
+// PayloadFormat values
+const (
+	PayloadFormatJSON     PayloadFormat = "json"
+	PayloadFormatProtobuf PayloadFormat = "protobuf"
+	PayloadFormatCBOR     PayloadFormat = "cbor"
+	// PayloadFormatV3 is JSON in the schema of The Things Stack (v3)
+	PayloadFormatV3 PayloadFormat = "v3"
+)
+
+// ParsePayloadFormat parses a PayloadFormat. An empty string is the JSON format.
+func ParsePayloadFormat(format string) (PayloadFormat, error) {
+	switch PayloadFormat(format) {
+	case "", PayloadFormatJSON:
+		return PayloadFormatJSON, nil
+	case PayloadFormatProtobuf, PayloadFormatCBOR, PayloadFormatV3:
+		return PayloadFormat(format), nil
+	}
+	return "", fmt.Errorf("Invalid payload format %s", format)
+}
+
+// ContentType returns the MIME type of the PayloadFormat
+func (f PayloadFormat) ContentType() string {
+	switch f {
+	case PayloadFormatProtobuf:
+		return "application/x-protobuf"
+	case PayloadFormatCBOR:
+		return "application/cbor"
+	}
+	return "application/json"
+}
+
+// DetectPayloadFormat returns the format of a marshaled UplinkMessage. JSON
+// messages start with {, CBOR messages with a map header and Protobuf
+// messages with a field tag. JSON messages in the schema of The Things Stack
+// contain the end_device_ids.
+func DetectPayloadFormat(data []byte) PayloadFormat {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '{' && bytes.Contains(trimmed, []byte(`"end_device_ids"`)):
+		return PayloadFormatV3
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		return PayloadFormatJSON
+	case len(data) > 0 && data[0]>>5 == 5:
+		return PayloadFormatCBOR
+	case len(data) == 0:
+		return PayloadFormatJSON
+	}
+	return PayloadFormatProtobuf
+}
+
+// cborHandle encodes maps with their keys in canonical order and decodes them
+// to map[string]interface{} like encoding/json
+var cborHandle = func() *codec.CborHandle {
+	h := new(codec.CborHandle)
+	h.Canonical = true
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return h
+}()
+
+// marshalCBOR encodes the UplinkMessage with the same field names as the JSON
+// format. CBOR has byte strings, so we don't need base64 for the payload.
+func marshalCBOR(msg UplinkMessage) ([]byte, error) {
+	jsonBytes, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonBytes))
+	dec.UseNumber()
+	var value map[string]interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	value = cborValue(value).(map[string]interface{})
+	value["payload_raw"] = msg.PayloadRaw
+	var data []byte
+	if err := codec.NewEncoderBytes(&data, cborHandle).Encode(value); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// cborValue converts the JSON numbers in v to integers where possible, so
+// that they get the short CBOR encoding
+func cborValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = cborValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = cborValue(item)
+		}
+	}
+	return v
+}
+
+// unmarshalCBOR decodes the CBOR encoding of an UplinkMessage through its
+// JSON representation
+func unmarshalCBOR(data []byte, msg *UplinkMessage) error {
+	var value map[string]interface{}
+	if err := codec.NewDecoderBytes(data, cborHandle).Decode(&value); err != nil {
+		return err
+	}
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonBytes, msg)
+}
+
+// MarshalUplinkMessage marshals an UplinkMessage in the given format
+func MarshalUplinkMessage(format PayloadFormat, msg UplinkMessage) ([]byte, error) {
+	switch format {
+	case "", PayloadFormatJSON:
+		return json.Marshal(msg)
+	case PayloadFormatProtobuf:
+		return msg.MarshalProtobuf()
+	case PayloadFormatV3:
+		return msg.MarshalV3()
+	case PayloadFormatCBOR:
+		return marshalCBOR(msg)
+	}
+	return nil, fmt.Errorf("Invalid payload format %s", format)
+}
+
+// UnmarshalUplinkMessage unmarshals an UplinkMessage in any of the supported formats
+func UnmarshalUplinkMessage(data []byte, msg *UplinkMessage) error {
+	switch DetectPayloadFormat(data) {
+	case PayloadFormatProtobuf:
+		return msg.UnmarshalProtobuf(data)
+	case PayloadFormatCBOR:
+		return unmarshalCBOR(data, msg)
+	case PayloadFormatV3:
+		return msg.UnmarshalV3(data)
+	}
+	return json.Unmarshal(data, msg)
+}
@@ -0,0 +1,164 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParsePayloadFormat(t *testing.T) {
+	a := New(t)
+	for in, out := range map[string]PayloadFormat{
+		"":         PayloadFormatJSON,
+		"json":     PayloadFormatJSON,
+		"protobuf": PayloadFormatProtobuf,
+		"cbor":     PayloadFormatCBOR,
+	} {
+		format, err := ParsePayloadFormat(in)
+		a.So(err, ShouldBeNil)
+		a.So(format, ShouldEqual, out)
+	}
+	_, err := ParsePayloadFormat("xml")
+	a.So(err, ShouldNotBeNil)
+
+	a.So(PayloadFormatJSON.ContentType(), ShouldEqual, "application/json")
+	a.So(PayloadFormatProtobuf.ContentType(), ShouldEqual, "application/x-protobuf")
+	a.So(PayloadFormatCBOR.ContentType(), ShouldEqual, "application/cbor")
+}
+
+func TestMarshalUplinkMessage(t *testing.T) {
+	a := New(t)
+
+	msg := UplinkMessage{
+		AppID:          "app",
+		DevID:          "dev",
+		HardwareSerial: "0102030405060708",
+		FPort:          1,
+		FCnt:           42,
+		IsRetry:        true,
+		PayloadRaw:     []byte{0x01, 0x02, 0x03},
+		PayloadFields: map[string]interface{}{
+			"temperature": 21.5,
+			"count":       3,
+			"name":        "sensor",
+			"on":          false,
+			"empty":       nil,
+			"nested":      map[string]interface{}{"list": []interface{}{1.0, "two", true}},
+		},
+		Metadata: Metadata{
+			Time:       BuildTime(1465831736000000000),
+			Frequency:  868.1,
+			Modulation: "LORA",
+			DataRate:   "SF7BW125",
+			CodingRate: "4/5",
+			Airtime:    46336000,
+			Gateways: []GatewayMetadata{
+				{GtwID: "gtw", GtwTrusted: true, Timestamp: 12345, Time: BuildTime(1465831736000000000), Channel: 2, RSSI: -35, SNR: 7.5, RFChain: 1, LocationMetadata: LocationMetadata{Latitude: 52.3, Longitude: 4.9, Altitude: -5}},
+				{GtwID: "untrusted", GtwUntrusted: true, Timestamp: 23456, Channel: 3, RSSI: -100, SNR: -2.5},
+			},
+			LocationMetadata: LocationMetadata{Latitude: 52.3, Longitude: 4.9, Altitude: 12},
+		},
+	}
+
+	expected, _ := json.Marshal(msg)
+
+	for _, format := range []PayloadFormat{PayloadFormatJSON, PayloadFormatProtobuf, PayloadFormatCBOR} {
+		data, err := MarshalUplinkMessage(format, msg)
+		a.So(err, ShouldBeNil)
+		a.So(DetectPayloadFormat(data), ShouldEqual, format)
+		if format != PayloadFormatJSON {
+			a.So(len(data), ShouldBeLessThan, len(expected))
+		}
+
+		var out UplinkMessage
+		a.So(UnmarshalUplinkMessage(data, &out), ShouldBeNil)
+		actual, _ := json.Marshal(out)
+		a.So(string(actual), ShouldEqual, string(expected))
+		a.So(time.Time(out.Metadata.Time).Equal(time.Time(msg.Metadata.Time)), ShouldBeTrue)
+	}
+
+	_, err := MarshalUplinkMessage("xml", msg)
+	a.So(err, ShouldNotBeNil)
+
+	var out UplinkMessage
+	a.So(UnmarshalUplinkMessage([]byte{0x0a, 0x10}, &out), ShouldNotBeNil)
+	a.So(UnmarshalUplinkMessage([]byte{0xa1, 0x61}, &out), ShouldNotBeNil)
+}
+
+func TestUnmarshalProtobufUnknownFields(t *testing.T) {
+	a := New(t)
+	// dev_id = "dev", unknown field 15 (varint), unknown field 14 (fixed64)
+	data := []byte{0x12, 0x03, 'd', 'e', 'v', 0x78, 0x96, 0x01, 0x71, 1, 2, 3, 4, 5, 6, 7, 8}
+	var out UplinkMessage
+	a.So(out.UnmarshalProtobuf(data), ShouldBeNil)
+	a.So(out.DevID, ShouldEqual, "dev")
+}
+
+func TestMarshalUplinkMessageV3(t *testing.T) {
+	a := New(t)
+
+	format, err := ParsePayloadFormat("v3")
+	a.So(err, ShouldBeNil)
+	a.So(format, ShouldEqual, PayloadFormatV3)
+	a.So(format.ContentType(), ShouldEqual, "application/json")
+
+	msg := UplinkMessage{
+		AppID:          "app",
+		DevID:          "dev",
+		HardwareSerial: "0102030405060708",
+		FPort:          1,
+		FCnt:           42,
+		PayloadRaw:     []byte{0x01, 0x02, 0x03},
+		PayloadFields:  map[string]interface{}{"led": true},
+		Metadata: Metadata{
+			Time:       BuildTime(1465831736000000000),
+			Frequency:  868.1,
+			Modulation: "LORA",
+			DataRate:   "SF7BW125",
+			CodingRate: "4/5",
+			Airtime:    46336000,
+			Gateways: []GatewayMetadata{
+				{GtwID: "gtw", Timestamp: 12345, Time: BuildTime(1465831736000000000), Channel: 2, RSSI: -35, SNR: 7.5, LocationMetadata: LocationMetadata{Latitude: 52.3, Longitude: 4.9, Altitude: -5}},
+			},
+			LocationMetadata: LocationMetadata{Latitude: 52.3, Longitude: 4.9, Altitude: 12},
+		},
+	}
+
+	data, err := MarshalUplinkMessage(PayloadFormatV3, msg)
+	a.So(err, ShouldBeNil)
+	a.So(DetectPayloadFormat(data), ShouldEqual, PayloadFormatV3)
+
+	var v3 map[string]interface{}
+	a.So(json.Unmarshal(data, &v3), ShouldBeNil)
+	a.So(v3["end_device_ids"], ShouldResemble, map[string]interface{}{
+		"device_id":       "dev",
+		"application_ids": map[string]interface{}{"application_id": "app"},
+		"dev_eui":         "0102030405060708",
+	})
+	up := v3["uplink_message"].(map[string]interface{})
+	a.So(up["f_port"], ShouldEqual, 1)
+	a.So(up["f_cnt"], ShouldEqual, 42)
+	a.So(up["frm_payload"], ShouldEqual, "AQID")
+	a.So(up["decoded_payload"], ShouldResemble, map[string]interface{}{"led": true})
+	a.So(up["consumed_airtime"], ShouldEqual, "0.046336s")
+	a.So(up["settings"], ShouldResemble, map[string]interface{}{
+		"data_rate":   map[string]interface{}{"lora": map[string]interface{}{"bandwidth": 125000.0, "spreading_factor": 7.0}},
+		"coding_rate": "4/5",
+		"frequency":   "868100000",
+	})
+	rx := up["rx_metadata"].([]interface{})[0].(map[string]interface{})
+	a.So(rx["gateway_ids"], ShouldResemble, map[string]interface{}{"gateway_id": "gtw"})
+	a.So(rx["channel_index"], ShouldEqual, 2)
+	a.So(rx["rssi"], ShouldEqual, -35)
+
+	var out UplinkMessage
+	a.So(UnmarshalUplinkMessage(data, &out), ShouldBeNil)
+	expected, _ := json.Marshal(msg)
+	actual, _ := json.Marshal(out)
+	a.So(string(actual), ShouldEqual, string(expected))
+}
@@ -0,0 +1,169 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tenant is an isolated customer of a deployment that serves multiple customers
+type Tenant struct {
+	ID string
+
+	// The applications and gateways of the tenant. An ID that ends with a *
+	// matches all IDs that start with the part before the *.
+	Applications []string
+	Gateways     []string
+
+	// The DevAddr blocks that are reserved for the devices of the tenant
+	Prefixes []DevAddrPrefix
+
+	// The maximum number of devices of the tenant (0 for unlimited)
+	Devices int
+	// The maximum number of API requests of the tenant per hour (0 for unlimited)
+	Rate int
+}
+
+// ParseTenant parses the configuration of a tenant, for example
+// "applications=acme-*;gateways=acme-gw-*;prefixes=26011000/24;devices=1000;rate=10000"
+func ParseTenant(id, config string) (*Tenant, error) {
+	if id == "" {
+		return nil, fmt.Errorf("Invalid tenant: missing ID")
+	}
+	tenant := &Tenant{ID: id}
+	for _, setting := range strings.Split(config, ";") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		parts := strings.SplitN(setting, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid tenant %s: setting %s is not key=value", id, setting)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch key {
+		case "applications":
+			tenant.Applications = splitList(value)
+		case "gateways":
+			tenant.Gateways = splitList(value)
+		case "prefixes":
+			for _, prefixString := range splitList(value) {
+				prefix, err := ParseDevAddrPrefix(prefixString)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid tenant %s: %s", id, err)
+				}
+				tenant.Prefixes = append(tenant.Prefixes, prefix)
+			}
+		case "devices", "rate":
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				return nil, fmt.Errorf("Invalid tenant %s: %s must be a non-negative number", id, key)
+			}
+			if key == "devices" {
+				tenant.Devices = limit
+			} else {
+				tenant.Rate = limit
+			}
+		default:
+			return nil, fmt.Errorf("Invalid tenant %s: unknown setting %s", id, key)
+		}
+	}
+	return tenant, nil
+}
+
+func splitList(value string) (list []string) {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+func matchID(patterns []string, id string) bool {
+	for _, pattern := range patterns {
+		if pattern == id || (strings.HasSuffix(pattern, "*") && strings.HasPrefix(id, strings.TrimSuffix(pattern, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasApplication returns true if the application belongs to the tenant
+func (t *Tenant) HasApplication(appID string) bool {
+	return t != nil && matchID(t.Applications, appID)
+}
+
+// HasGateway returns true if the gateway belongs to the tenant
+func (t *Tenant) HasGateway(gatewayID string) bool {
+	return t != nil && matchID(t.Gateways, gatewayID)
+}
+
+// HasDevAddr returns true if the DevAddr is in one of the blocks of the tenant
+func (t *Tenant) HasDevAddr(devAddr DevAddr) bool {
+	if t == nil {
+		return false
+	}
+	for _, prefix := range t.Prefixes {
+		if devAddr.HasPrefix(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tenants is the list of tenants of a deployment. Applications, gateways and
+// DevAddrs that do not belong to a tenant are shared by the deployment.
+type Tenants []*Tenant
+
+// ParseTenants parses the tenant configurations by tenant ID
+func ParseTenants(configs map[string]string) (Tenants, error) {
+	ids := make([]string, 0, len(configs))
+	for id := range configs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	tenants := make(Tenants, 0, len(ids))
+	for _, id := range ids {
+		tenant, err := ParseTenant(id, configs[id])
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, nil
+}
+
+// ForApplication returns the tenant of the application, or nil if the application is shared
+func (t Tenants) ForApplication(appID string) *Tenant {
+	for _, tenant := range t {
+		if tenant.HasApplication(appID) {
+			return tenant
+		}
+	}
+	return nil
+}
+
+// ForGateway returns the tenant of the gateway, or nil if the gateway is shared
+func (t Tenants) ForGateway(gatewayID string) *Tenant {
+	for _, tenant := range t {
+		if tenant.HasGateway(gatewayID) {
+			return tenant
+		}
+	}
+	return nil
+}
+
+// ForDevAddr returns the tenant that has the DevAddr in its blocks, or nil if the DevAddr is shared
+func (t Tenants) ForDevAddr(devAddr DevAddr) *Tenant {
+	for _, tenant := range t {
+		if tenant.HasDevAddr(devAddr) {
+			return tenant
+		}
+	}
+	return nil
+}
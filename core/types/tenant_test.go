@@ -0,0 +1,49 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestTenants(t *testing.T) {
+	a := New(t)
+
+	tenants, err := ParseTenants(map[string]string{
+		"acme":   "applications=acme-*,legacy; gateways=acme-gw-*; prefixes=26011000/24; devices=2; rate=100",
+		"globex": "applications=globex",
+	})
+	a.So(err, ShouldBeNil)
+	a.So(tenants, ShouldHaveLength, 2)
+
+	acme := tenants[0]
+	a.So(acme.ID, ShouldEqual, "acme")
+	a.So(acme.Applications, ShouldResemble, []string{"acme-*", "legacy"})
+	a.So(acme.Gateways, ShouldResemble, []string{"acme-gw-*"})
+	a.So(acme.Prefixes, ShouldHaveLength, 1)
+	a.So(acme.Devices, ShouldEqual, 2)
+	a.So(acme.Rate, ShouldEqual, 100)
+
+	a.So(tenants.ForApplication("acme-sensors"), ShouldEqual, acme)
+	a.So(tenants.ForApplication("legacy"), ShouldEqual, acme)
+	a.So(tenants.ForApplication("legacy-2"), ShouldBeNil)
+	a.So(tenants.ForApplication("globex"), ShouldEqual, tenants[1])
+	a.So(tenants.ForGateway("acme-gw-1"), ShouldEqual, acme)
+	a.So(tenants.ForGateway("globex-gw-1"), ShouldBeNil)
+	a.So(tenants.ForDevAddr(DevAddr{0x26, 0x01, 0x10, 0x01}), ShouldEqual, acme)
+	a.So(tenants.ForDevAddr(DevAddr{0x26, 0x01, 0x20, 0x01}), ShouldBeNil)
+
+	var shared *Tenant
+	a.So(shared.HasApplication("acme-sensors"), ShouldBeFalse)
+	a.So(shared.HasDevAddr(DevAddr{0x26, 0x01, 0x10, 0x01}), ShouldBeFalse)
+
+	for _, invalid := range []string{"applications", "prefixes=26011000", "devices=-1", "rate=many", "owner=acme"} {
+		_, err = ParseTenant("acme", invalid)
+		a.So(err, ShouldNotBeNil)
+	}
+	_, err = ParseTenant("", "")
+	a.So(err, ShouldNotBeNil)
+}
@@ -12,6 +12,7 @@ type UplinkMessage struct {
 	FCnt           uint32                 `json:"counter"`
 	IsRetry        bool                   `json:"is_retry,omitempty"`
 	PayloadRaw     []byte                 `json:"payload_raw"`
+	Encrypted      bool                   `json:"encrypted,omitempty"` // PayloadRaw is encrypted with the AppSKey (end-to-end encryption)
 	PayloadFields  map[string]interface{} `json:"payload_fields,omitempty"`
 	Metadata       Metadata               `json:"metadata,omitempty"`
 }
@@ -0,0 +1,279 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	pb_types "github.com/gogo/protobuf/types"
+)
+
+// The Protobuf encoding of the UplinkMessage uses the following schema, the
+// payload fields are a google.protobuf.Struct:
+//
+//	message UplinkMessage {
+//	  string                 app_id          = 1;
+//	  string                 dev_id          = 2;
+//	  string                 hardware_serial = 3;
+//	  uint32                 port            = 4;
+//	  uint32                 counter         = 5;
+//	  bool                   is_retry        = 6;
+//	  bytes                  payload_raw     = 7;
+//	  google.protobuf.Struct payload_fields  = 8;
+//	  Metadata               metadata        = 9;
+//	}
+//
+//	message Metadata {
+//	  int64                    time        = 1; // Unix nanoseconds
+//	  float                    frequency   = 2;
+//	  string                   modulation  = 3;
+//	  string                   data_rate   = 4;
+//	  uint32                   bit_rate    = 5;
+//	  string                   coding_rate = 6;
+//	  int64                    airtime     = 7;
+//	  repeated GatewayMetadata gateways    = 8;
+//	  float                    latitude    = 9;
+//	  float                    longitude   = 10;
+//	  int32                    altitude    = 11;
+//	}
+//
+//	message GatewayMetadata {
+//	  string gtw_id        = 1;
+//	  bool   gtw_trusted   = 2;
+//	  uint32 timestamp     = 3;
+//	  int64  time          = 4; // Unix nanoseconds
+//	  uint32 channel       = 5;
+//	  float  rssi          = 6;
+//	  float  snr           = 7;
+//	  uint32 rf_chain      = 8;
+//	  float  latitude      = 9;
+//	  float  longitude     = 10;
+//	  int32  altitude      = 11;
+//	  bool   gtw_untrusted = 12;
+//	}
+
+type protoUplinkMessage struct {
+	AppID          string           `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	DevID          string           `protobuf:"bytes,2,opt,name=dev_id,json=devId,proto3" json:"dev_id,omitempty"`
+	HardwareSerial string           `protobuf:"bytes,3,opt,name=hardware_serial,json=hardwareSerial,proto3" json:"hardware_serial,omitempty"`
+	Port           uint32           `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	Counter        uint32           `protobuf:"varint,5,opt,name=counter,proto3" json:"counter,omitempty"`
+	IsRetry        bool             `protobuf:"varint,6,opt,name=is_retry,json=isRetry,proto3" json:"is_retry,omitempty"`
+	PayloadRaw     []byte           `protobuf:"bytes,7,opt,name=payload_raw,json=payloadRaw,proto3" json:"payload_raw,omitempty"`
+	PayloadFields  *pb_types.Struct `protobuf:"bytes,8,opt,name=payload_fields,json=payloadFields" json:"payload_fields,omitempty"`
+	Metadata       *protoMetadata   `protobuf:"bytes,9,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (m *protoUplinkMessage) Reset()         { *m = protoUplinkMessage{} }
+func (m *protoUplinkMessage) String() string { return proto.CompactTextString(m) }
+func (*protoUplinkMessage) ProtoMessage()    {}
+
+type protoMetadata struct {
+	Time       int64                   `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Frequency  float32                 `protobuf:"fixed32,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Modulation string                  `protobuf:"bytes,3,opt,name=modulation,proto3" json:"modulation,omitempty"`
+	DataRate   string                  `protobuf:"bytes,4,opt,name=data_rate,json=dataRate,proto3" json:"data_rate,omitempty"`
+	BitRate    uint32                  `protobuf:"varint,5,opt,name=bit_rate,json=bitRate,proto3" json:"bit_rate,omitempty"`
+	CodingRate string                  `protobuf:"bytes,6,opt,name=coding_rate,json=codingRate,proto3" json:"coding_rate,omitempty"`
+	Airtime    int64                   `protobuf:"varint,7,opt,name=airtime,proto3" json:"airtime,omitempty"`
+	Gateways   []*protoGatewayMetadata `protobuf:"bytes,8,rep,name=gateways" json:"gateways,omitempty"`
+	Latitude   float32                 `protobuf:"fixed32,9,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude  float32                 `protobuf:"fixed32,10,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Altitude   int32                   `protobuf:"varint,11,opt,name=altitude,proto3" json:"altitude,omitempty"`
+}
+
+func (m *protoMetadata) Reset()         { *m = protoMetadata{} }
+func (m *protoMetadata) String() string { return proto.CompactTextString(m) }
+func (*protoMetadata) ProtoMessage()    {}
+
+type protoGatewayMetadata struct {
+	GtwID        string  `protobuf:"bytes,1,opt,name=gtw_id,json=gtwId,proto3" json:"gtw_id,omitempty"`
+	GtwTrusted   bool    `protobuf:"varint,2,opt,name=gtw_trusted,json=gtwTrusted,proto3" json:"gtw_trusted,omitempty"`
+	Timestamp    uint32  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Time         int64   `protobuf:"varint,4,opt,name=time,proto3" json:"time,omitempty"`
+	Channel      uint32  `protobuf:"varint,5,opt,name=channel,proto3" json:"channel,omitempty"`
+	RSSI         float32 `protobuf:"fixed32,6,opt,name=rssi,proto3" json:"rssi,omitempty"`
+	SNR          float32 `protobuf:"fixed32,7,opt,name=snr,proto3" json:"snr,omitempty"`
+	RFChain      uint32  `protobuf:"varint,8,opt,name=rf_chain,json=rfChain,proto3" json:"rf_chain,omitempty"`
+	Latitude     float32 `protobuf:"fixed32,9,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude    float32 `protobuf:"fixed32,10,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Altitude     int32   `protobuf:"varint,11,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	GtwUntrusted bool    `protobuf:"varint,12,opt,name=gtw_untrusted,json=gtwUntrusted,proto3" json:"gtw_untrusted,omitempty"`
+}
+
+func (m *protoGatewayMetadata) Reset()         { *m = protoGatewayMetadata{} }
+func (m *protoGatewayMetadata) String() string { return proto.CompactTextString(m) }
+func (*protoGatewayMetadata) ProtoMessage()    {}
+
+func unixNano(t JSONTime) int64 {
+	if time.Time(t).IsZero() {
+		return 0
+	}
+	return time.Time(t).UnixNano()
+}
+
+// MarshalProtobuf returns the Protobuf encoding of the UplinkMessage
+func (m UplinkMessage) MarshalProtobuf() ([]byte, error) {
+	msg := &protoUplinkMessage{
+		AppID:          m.AppID,
+		DevID:          m.DevID,
+		HardwareSerial: m.HardwareSerial,
+		Port:           uint32(m.FPort),
+		Counter:        m.FCnt,
+		IsRetry:        m.IsRetry,
+		PayloadRaw:     m.PayloadRaw,
+		Metadata: &protoMetadata{
+			Time:       unixNano(m.Metadata.Time),
+			Frequency:  m.Metadata.Frequency,
+			Modulation: m.Metadata.Modulation,
+			DataRate:   m.Metadata.DataRate,
+			BitRate:    m.Metadata.Bitrate,
+			CodingRate: m.Metadata.CodingRate,
+			Airtime:    m.Metadata.Airtime,
+			Latitude:   m.Metadata.Latitude,
+			Longitude:  m.Metadata.Longitude,
+			Altitude:   m.Metadata.Altitude,
+		},
+	}
+	for _, gtw := range m.Metadata.Gateways {
+		msg.Metadata.Gateways = append(msg.Metadata.Gateways, &protoGatewayMetadata{
+			GtwID:        gtw.GtwID,
+			GtwTrusted:   gtw.GtwTrusted,
+			Timestamp:    gtw.Timestamp,
+			Time:         unixNano(gtw.Time),
+			Channel:      gtw.Channel,
+			RSSI:         gtw.RSSI,
+			SNR:          gtw.SNR,
+			RFChain:      gtw.RFChain,
+			Latitude:     gtw.Latitude,
+			Longitude:    gtw.Longitude,
+			Altitude:     gtw.Altitude,
+			GtwUntrusted: gtw.GtwUntrusted,
+		})
+	}
+	if len(m.PayloadFields) > 0 {
+		// Normalize the values to the types of the JSON encoding
+		jsonBytes, err := json.Marshal(m.PayloadFields)
+		if err != nil {
+			return nil, err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(jsonBytes, &fields); err != nil {
+			return nil, err
+		}
+		msg.PayloadFields = toStruct(fields)
+	}
+	return proto.Marshal(msg)
+}
+
+// UnmarshalProtobuf decodes the Protobuf encoding of an UplinkMessage
+func (m *UplinkMessage) UnmarshalProtobuf(data []byte) error {
+	var msg protoUplinkMessage
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	m.AppID = msg.AppID
+	m.DevID = msg.DevID
+	m.HardwareSerial = msg.HardwareSerial
+	m.FPort = uint8(msg.Port)
+	m.FCnt = msg.Counter
+	m.IsRetry = msg.IsRetry
+	m.PayloadRaw = msg.PayloadRaw
+	if msg.PayloadFields != nil {
+		m.PayloadFields = fromStruct(msg.PayloadFields)
+	}
+	if md := msg.Metadata; md != nil {
+		m.Metadata.Time = BuildTime(md.Time)
+		m.Metadata.Frequency = md.Frequency
+		m.Metadata.Modulation = md.Modulation
+		m.Metadata.DataRate = md.DataRate
+		m.Metadata.Bitrate = md.BitRate
+		m.Metadata.CodingRate = md.CodingRate
+		m.Metadata.Airtime = md.Airtime
+		m.Metadata.Latitude = md.Latitude
+		m.Metadata.Longitude = md.Longitude
+		m.Metadata.Altitude = md.Altitude
+		for _, gtw := range md.Gateways {
+			m.Metadata.Gateways = append(m.Metadata.Gateways, GatewayMetadata{
+				GtwID:        gtw.GtwID,
+				GtwTrusted:   gtw.GtwTrusted,
+				GtwUntrusted: gtw.GtwUntrusted,
+				Timestamp:    gtw.Timestamp,
+				Time:         BuildTime(gtw.Time),
+				Channel:      gtw.Channel,
+				RSSI:         gtw.RSSI,
+				SNR:          gtw.SNR,
+				RFChain:      gtw.RFChain,
+				LocationMetadata: LocationMetadata{
+					Latitude:  gtw.Latitude,
+					Longitude: gtw.Longitude,
+					Altitude:  gtw.Altitude,
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// toStruct converts JSON-like fields to a google.protobuf.Struct
+func toStruct(fields map[string]interface{}) *pb_types.Struct {
+	s := &pb_types.Struct{Fields: make(map[string]*pb_types.Value, len(fields))}
+	for k, v := range fields {
+		s.Fields[k] = toValue(v)
+	}
+	return s
+}
+
+// toValue converts a JSON-like value to a google.protobuf.Value
+func toValue(v interface{}) *pb_types.Value {
+	switch v := v.(type) {
+	case float64:
+		return &pb_types.Value{Kind: &pb_types.Value_NumberValue{NumberValue: v}}
+	case string:
+		return &pb_types.Value{Kind: &pb_types.Value_StringValue{StringValue: v}}
+	case bool:
+		return &pb_types.Value{Kind: &pb_types.Value_BoolValue{BoolValue: v}}
+	case map[string]interface{}:
+		return &pb_types.Value{Kind: &pb_types.Value_StructValue{StructValue: toStruct(v)}}
+	case []interface{}:
+		list := &pb_types.ListValue{Values: make([]*pb_types.Value, 0, len(v))}
+		for _, item := range v {
+			list.Values = append(list.Values, toValue(item))
+		}
+		return &pb_types.Value{Kind: &pb_types.Value_ListValue{ListValue: list}}
+	}
+	return &pb_types.Value{Kind: &pb_types.Value_NullValue{NullValue: pb_types.NullValue_NULL_VALUE}}
+}
+
+// fromStruct converts a google.protobuf.Struct to JSON-like fields
+func fromStruct(s *pb_types.Struct) map[string]interface{} {
+	fields := make(map[string]interface{}, len(s.GetFields()))
+	for k, v := range s.GetFields() {
+		fields[k] = fromValue(v)
+	}
+	return fields
+}
+
+// fromValue converts a google.protobuf.Value to a JSON-like value
+func fromValue(v *pb_types.Value) interface{} {
+	switch kind := v.GetKind().(type) {
+	case *pb_types.Value_NumberValue:
+		return kind.NumberValue
+	case *pb_types.Value_StringValue:
+		return kind.StringValue
+	case *pb_types.Value_BoolValue:
+		return kind.BoolValue
+	case *pb_types.Value_StructValue:
+		return fromStruct(kind.StructValue)
+	case *pb_types.Value_ListValue:
+		values := make([]interface{}, 0, len(kind.ListValue.GetValues()))
+		for _, item := range kind.ListValue.GetValues() {
+			values = append(values, fromValue(item))
+		}
+		return values
+	}
+	return nil
+}
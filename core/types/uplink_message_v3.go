@@ -0,0 +1,237 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UplinkMessageV3 is an UplinkMessage in the JSON schema of The Things Stack
+// (v3), so that integrations that are written for The Things Stack can consume it
+type UplinkMessageV3 struct {
+	EndDeviceIDs  EndDeviceIdentifiersV3 `json:"end_device_ids"`
+	ReceivedAt    *JSONTime              `json:"received_at,omitempty"`
+	UplinkMessage ApplicationUplinkV3    `json:"uplink_message"`
+}
+
+// EndDeviceIdentifiersV3 identifies a device in The Things Stack
+type EndDeviceIdentifiersV3 struct {
+	DeviceID       string                   `json:"device_id"`
+	ApplicationIDs ApplicationIdentifiersV3 `json:"application_ids"`
+	DevEUI         string                   `json:"dev_eui,omitempty"`
+}
+
+// ApplicationIdentifiersV3 identifies an application in The Things Stack
+type ApplicationIdentifiersV3 struct {
+	ApplicationID string `json:"application_id"`
+}
+
+// GatewayIdentifiersV3 identifies a gateway in The Things Stack
+type GatewayIdentifiersV3 struct {
+	GatewayID string `json:"gateway_id"`
+}
+
+// ApplicationUplinkV3 is the uplink_message in an UplinkMessageV3
+type ApplicationUplinkV3 struct {
+	FPort           uint8                  `json:"f_port"`
+	FCnt            uint32                 `json:"f_cnt"`
+	FRMPayload      []byte                 `json:"frm_payload"`
+	DecodedPayload  map[string]interface{} `json:"decoded_payload,omitempty"`
+	RxMetadata      []RxMetadataV3         `json:"rx_metadata,omitempty"`
+	Settings        TxSettingsV3           `json:"settings"`
+	ReceivedAt      *JSONTime              `json:"received_at,omitempty"`
+	ConsumedAirtime string                 `json:"consumed_airtime,omitempty"`
+	Locations       map[string]LocationV3  `json:"locations,omitempty"`
+}
+
+// RxMetadataV3 contains the metadata of a gateway that received an uplink
+type RxMetadataV3 struct {
+	GatewayIDs   GatewayIdentifiersV3 `json:"gateway_ids"`
+	Time         *JSONTime            `json:"time,omitempty"`
+	Timestamp    uint32               `json:"timestamp,omitempty"`
+	RSSI         float32              `json:"rssi,omitempty"`
+	ChannelRSSI  float32              `json:"channel_rssi,omitempty"`
+	SNR          float32              `json:"snr,omitempty"`
+	Location     *LocationV3          `json:"location,omitempty"`
+	ChannelIndex uint32               `json:"channel_index,omitempty"`
+}
+
+// TxSettingsV3 contains the transmission settings of an uplink
+type TxSettingsV3 struct {
+	DataRate   DataRateV3 `json:"data_rate"`
+	CodingRate string     `json:"coding_rate,omitempty"`
+	Frequency  string     `json:"frequency,omitempty"` // Hz
+}
+
+// DataRateV3 is the data rate of an uplink
+type DataRateV3 struct {
+	LoRa *LoRaDataRateV3 `json:"lora,omitempty"`
+	FSK  *FSKDataRateV3  `json:"fsk,omitempty"`
+}
+
+// LoRaDataRateV3 is a LoRa data rate
+type LoRaDataRateV3 struct {
+	Bandwidth       uint32 `json:"bandwidth"` // Hz
+	SpreadingFactor uint32 `json:"spreading_factor"`
+}
+
+// FSKDataRateV3 is an FSK data rate
+type FSKDataRateV3 struct {
+	BitRate uint32 `json:"bit_rate"`
+}
+
+// LocationV3 is a location in The Things Stack
+type LocationV3 struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  int32   `json:"altitude,omitempty"`
+	Source    string  `json:"source,omitempty"`
+}
+
+func jsonTimeV3(t JSONTime) *JSONTime {
+	if time.Time(t).IsZero() || time.Time(t).Unix() == 0 {
+		return nil
+	}
+	return &t
+}
+
+func locationV3(location LocationMetadata, source string) *LocationV3 {
+	if location.Latitude == 0 && location.Longitude == 0 {
+		return nil
+	}
+	return &LocationV3{
+		Latitude:  float64(location.Latitude),
+		Longitude: float64(location.Longitude),
+		Altitude:  location.Altitude,
+		Source:    source,
+	}
+}
+
+func (location *LocationV3) metadata() (metadata LocationMetadata) {
+	if location == nil {
+		return
+	}
+	return LocationMetadata{
+		Latitude:  float32(location.Latitude),
+		Longitude: float32(location.Longitude),
+		Altitude:  location.Altitude,
+	}
+}
+
+// V3 converts the UplinkMessage to the JSON schema of The Things Stack
+func (msg UplinkMessage) V3() UplinkMessageV3 {
+	var v3 UplinkMessageV3
+	v3.EndDeviceIDs.DeviceID = msg.DevID
+	v3.EndDeviceIDs.ApplicationIDs.ApplicationID = msg.AppID
+	v3.EndDeviceIDs.DevEUI = msg.HardwareSerial
+	v3.ReceivedAt = jsonTimeV3(msg.Metadata.Time)
+
+	up := &v3.UplinkMessage
+	up.FPort = msg.FPort
+	up.FCnt = msg.FCnt
+	up.FRMPayload = msg.PayloadRaw
+	up.DecodedPayload = msg.PayloadFields
+	up.ReceivedAt = v3.ReceivedAt
+	if msg.Metadata.Airtime != 0 {
+		up.ConsumedAirtime = fmt.Sprintf("%gs", time.Duration(msg.Metadata.Airtime).Seconds())
+	}
+	if location := locationV3(msg.Metadata.LocationMetadata, "SOURCE_REGISTRY"); location != nil {
+		up.Locations = map[string]LocationV3{"user": *location}
+	}
+
+	up.Settings.CodingRate = msg.Metadata.CodingRate
+	if msg.Metadata.Frequency != 0 {
+		// Format the float32 in MHz before converting it to Hz, so that 868.1 becomes 868100000
+		mhz, _ := strconv.ParseFloat(strconv.FormatFloat(float64(msg.Metadata.Frequency), 'f', -1, 32), 64)
+		up.Settings.Frequency = strconv.FormatUint(uint64(mhz*1e6+0.5), 10)
+	}
+	if datr, err := ParseDataRate(msg.Metadata.DataRate); err == nil {
+		up.Settings.DataRate.LoRa = &LoRaDataRateV3{Bandwidth: uint32(datr.Bandwidth) * 1000, SpreadingFactor: uint32(datr.SpreadingFactor)}
+	} else if msg.Metadata.Modulation == "FSK" {
+		up.Settings.DataRate.FSK = &FSKDataRateV3{BitRate: msg.Metadata.Bitrate}
+	}
+
+	for _, gtw := range msg.Metadata.Gateways {
+		var rx RxMetadataV3
+		rx.GatewayIDs.GatewayID = gtw.GtwID
+		rx.Time = jsonTimeV3(gtw.Time)
+		rx.Timestamp = gtw.Timestamp
+		rx.RSSI = gtw.RSSI
+		rx.ChannelRSSI = gtw.RSSI
+		rx.SNR = gtw.SNR
+		rx.Location = locationV3(gtw.LocationMetadata, "SOURCE_REGISTRY")
+		rx.ChannelIndex = gtw.Channel
+		up.RxMetadata = append(up.RxMetadata, rx)
+	}
+	return v3
+}
+
+// ToUplinkMessage converts the message in the JSON schema of The Things Stack to an UplinkMessage
+func (v3 UplinkMessageV3) ToUplinkMessage() UplinkMessage {
+	up := v3.UplinkMessage
+	msg := UplinkMessage{
+		AppID:          v3.EndDeviceIDs.ApplicationIDs.ApplicationID,
+		DevID:          v3.EndDeviceIDs.DeviceID,
+		HardwareSerial: v3.EndDeviceIDs.DevEUI,
+		FPort:          up.FPort,
+		FCnt:           up.FCnt,
+		PayloadRaw:     up.FRMPayload,
+		PayloadFields:  up.DecodedPayload,
+	}
+	if v3.ReceivedAt != nil {
+		msg.Metadata.Time = *v3.ReceivedAt
+	}
+	if airtime, err := strconv.ParseFloat(strings.TrimSuffix(up.ConsumedAirtime, "s"), 64); err == nil {
+		msg.Metadata.Airtime = int64(airtime*float64(time.Second) + 0.5)
+	}
+	if location, ok := up.Locations["user"]; ok {
+		msg.Metadata.LocationMetadata = location.metadata()
+	}
+	msg.Metadata.CodingRate = up.Settings.CodingRate
+	if hz, err := strconv.ParseUint(up.Settings.Frequency, 10, 64); err == nil {
+		msg.Metadata.Frequency = float32(float64(hz) / 1e6)
+	}
+	if lora := up.Settings.DataRate.LoRa; lora != nil {
+		msg.Metadata.Modulation = "LORA"
+		msg.Metadata.DataRate = DataRate{SpreadingFactor: uint(lora.SpreadingFactor), Bandwidth: uint(lora.Bandwidth / 1000)}.String()
+	}
+	if fsk := up.Settings.DataRate.FSK; fsk != nil {
+		msg.Metadata.Modulation = "FSK"
+		msg.Metadata.Bitrate = fsk.BitRate
+	}
+	for _, rx := range up.RxMetadata {
+		gtw := GatewayMetadata{
+			GtwID:            rx.GatewayIDs.GatewayID,
+			Timestamp:        rx.Timestamp,
+			Channel:          rx.ChannelIndex,
+			RSSI:             rx.RSSI,
+			SNR:              rx.SNR,
+			LocationMetadata: rx.Location.metadata(),
+		}
+		if rx.Time != nil {
+			gtw.Time = *rx.Time
+		}
+		msg.Metadata.Gateways = append(msg.Metadata.Gateways, gtw)
+	}
+	return msg
+}
+
+// MarshalV3 marshals the UplinkMessage in the JSON schema of The Things Stack
+func (msg UplinkMessage) MarshalV3() ([]byte, error) {
+	return json.Marshal(msg.V3())
+}
+
+// UnmarshalV3 unmarshals an UplinkMessage in the JSON schema of The Things Stack
+func (msg *UplinkMessage) UnmarshalV3(data []byte) error {
+	var v3 UplinkMessageV3
+	if err := json.Unmarshal(data, &v3); err != nil {
+		return err
+	}
+	*msg = v3.ToUplinkMessage()
+	return nil
+}
@@ -5,6 +5,7 @@ package mqtt
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,6 +33,8 @@ type Client interface {
 
 	// Uplink pub/sub
 	PublishUplink(payload types.UplinkMessage) Token
+	PublishUplinkFormat(payload types.UplinkMessage, format types.PayloadFormat) Token
+	PublishUplinkPattern(payload types.UplinkMessage, pattern TopicPattern, format types.PayloadFormat) Token
 	PublishUplinkFields(appID string, devID string, fields map[string]interface{}) Token
 	SubscribeDeviceUplink(appID string, devID string, handler UplinkHandler) Token
 	SubscribeAppUplink(appID string, handler UplinkHandler) Token
@@ -52,6 +55,7 @@ type Client interface {
 	// Event pub/sub
 	PublishAppEvent(appID string, eventType types.EventType, payload interface{}) Token
 	PublishDeviceEvent(appID string, devID string, eventType types.EventType, payload interface{}) Token
+	PublishEventCursor(appID string, cursor string) Token
 	SubscribeAppEvents(appID string, eventType types.EventType, handler AppEventHandler) Token
 	SubscribeDeviceEvents(appID string, devID string, eventType types.EventType, handler DeviceEventHandler) Token
 	UnsubscribeAppEvents(appID string, eventType types.EventType) Token
@@ -148,6 +152,7 @@ type DefaultClient struct {
 	mqtt          MQTT.Client
 	ctx           log.Interface
 	subscriptions map[string]MQTT.MessageHandler
+	topicPrefix   string
 }
 
 // NewClient creates a new DefaultClient
@@ -236,18 +241,45 @@ func (c *DefaultClient) Connect() error {
 	return nil
 }
 
+// SetTopicPrefix sets a prefix, such as "ttn/", that is added to the topics
+// of all messages that are published and subscribed to. It must be set
+// before subscribing.
+func (c *DefaultClient) SetTopicPrefix(prefix string) {
+	c.topicPrefix = prefix
+}
+
+// prefixedMessage is a message of which the topic prefix is removed
+type prefixedMessage struct {
+	MQTT.Message
+	topic string
+}
+
+func (m prefixedMessage) Topic() string {
+	return m.topic
+}
+
 func (c *DefaultClient) publish(topic string, msg []byte) Token {
-	return c.mqtt.Publish(topic, PublishQoS, false, msg)
+	return c.mqtt.Publish(c.topicPrefix+topic, PublishQoS, false, msg)
+}
+
+func (c *DefaultClient) publishRetained(topic string, msg []byte) Token {
+	return c.mqtt.Publish(c.topicPrefix+topic, PublishQoS, true, msg)
 }
 
 func (c *DefaultClient) subscribe(topic string, handler MQTT.MessageHandler) Token {
 	c.subscriptions[topic] = handler
-	return c.mqtt.Subscribe(topic, SubscribeQoS, handler)
+	prefix := c.topicPrefix
+	return c.mqtt.Subscribe(prefix+topic, SubscribeQoS, func(client MQTT.Client, msg MQTT.Message) {
+		if prefix != "" {
+			msg = prefixedMessage{msg, strings.TrimPrefix(msg.Topic(), prefix)}
+		}
+		handler(client, msg)
+	})
 }
 
 func (c *DefaultClient) unsubscribe(topic string) Token {
 	delete(c.subscriptions, topic)
-	return c.mqtt.Unsubscribe(topic)
+	return c.mqtt.Unsubscribe(c.topicPrefix + topic)
 }
 
 // Disconnect from the MQTT broker
@@ -169,6 +169,32 @@ func TestRandomTopicPublish(t *testing.T) {
 	ctx.Info("This test should have printed one message.")
 }
 
+func TestTopicPrefix(t *testing.T) {
+	a := New(t)
+	ctx := getLogger(t, "TestTopicPrefix")
+
+	c := NewClient(ctx, "test", "", "", fmt.Sprintf("tcp://%s", host))
+	c.(*DefaultClient).SetTopicPrefix("bridge/")
+	c.Connect()
+	defer c.Disconnect()
+
+	received := make(chan string, 1)
+	subToken := c.SubscribeDeviceDownlink("prefix-app", "prefix-dev", func(client Client, appID string, devID string, req types.DownlinkMessage) {
+		received <- devID
+	})
+	waitForOK(subToken, a)
+
+	pubToken := c.(*DefaultClient).mqtt.Publish("bridge/prefix-app/devices/prefix-dev/down", PublishQoS, false, []byte(`{"port":1}`))
+	waitForOK(pubToken, a)
+
+	select {
+	case devID := <-received:
+		a.So(devID, ShouldEqual, "prefix-dev")
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive the downlink on the prefixed topic")
+	}
+}
+
 func ExampleNewClient() {
 	ctx := apex.Wrap(log.WithField("Example", "NewClient"))
 	exampleClient := NewClient(ctx, "ttnctl", "my-app-id", "my-access-key", "eu.thethings.network:1883")
@@ -39,6 +39,18 @@ func (c *DefaultClient) PublishDeviceEvent(appID string, devID string, eventType
 	return c.publish(topic.String(), msg)
 }
 
+// PublishEventCursor publishes the cursor of the last message in the event
+// stream of the application as a retained message, so that clients that
+// connect get the cursor they can replay from
+func (c *DefaultClient) PublishEventCursor(appID string, cursor string) Token {
+	topic := ApplicationTopic{appID, AppEvents, string(types.EventCursorEvent)}
+	msg, err := json.Marshal(types.EventCursorEventData{Cursor: cursor})
+	if err != nil {
+		return &simpleToken{fmt.Errorf("Unable to marshal the message payload")}
+	}
+	return c.publishRetained(topic.String(), msg)
+}
+
 // SubscribeAppEvents subscribes to events of the given type for the given application. In order to subscribe to
 // application events from all applications the user has access to, pass an empty string as appID.
 func (c *DefaultClient) SubscribeAppEvents(appID string, eventType types.EventType, handler AppEventHandler) Token {
@@ -55,3 +55,27 @@ func TestPublishSubscribeDeviceEvents(t *testing.T) {
 	waitForOK(unsubToken, a)
 	wg.WaitFor(100 * time.Millisecond)
 }
+
+func TestPublishEventCursor(t *testing.T) {
+	a := New(t)
+	c := NewClient(getLogger(t, "Test"), "test", "", "", fmt.Sprintf("tcp://%s", host))
+	c.Connect()
+	defer c.Disconnect()
+
+	// The cursor is retained, so it is received after subscribing
+	pubToken := c.PublishEventCursor("app-id", "1490000000000-0")
+	waitForOK(pubToken, a)
+
+	var wg WaitGroup
+	wg.Add(1)
+	subToken := c.SubscribeAppEvents("app-id", types.EventCursorEvent, func(_ Client, appID string, eventType types.EventType, payload []byte) {
+		a.So(appID, ShouldEqual, "app-id")
+		a.So(eventType, ShouldEqual, types.EventCursorEvent)
+		a.So(string(payload), ShouldEqual, `{"cursor":"1490000000000-0"}`)
+		wg.Done()
+	})
+	waitForOK(subToken, a)
+	a.So(wg.WaitFor(100*time.Millisecond), ShouldBeNil)
+	unsubToken := c.UnsubscribeAppEvents("app-id", types.EventCursorEvent)
+	waitForOK(unsubToken, a)
+}
@@ -0,0 +1,149 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// fieldPlaceholderPrefix is the prefix of placeholders for decoded payload fields
+const fieldPlaceholderPrefix = "field:"
+
+// emptyTopicValue replaces placeholders that have no value
+const emptyTopicValue = "_"
+
+// TopicPattern is a template for the topic of uplink messages. It is relative
+// to the application, so the pattern "port/{port}/{dev_id}" results in the
+// topic "<AppID>/port/1/<DevID>". The pattern can contain the placeholders
+// {dev_id}, {hardware_serial}, {port}, {counter} and {field:<name>}, where
+// <name> is the name of a decoded payload field. Nested fields are separated
+// with dots, such as {field:gps.zone}.
+type TopicPattern struct {
+	pattern  string
+	segments []topicSegment
+}
+
+type topicSegment struct {
+	literal     string
+	placeholder string
+}
+
+var topicPlaceholders = map[string]bool{
+	"dev_id":          true,
+	"hardware_serial": true,
+	"port":            true,
+	"counter":         true,
+}
+
+// ParseTopicPattern parses and validates a TopicPattern
+func ParseTopicPattern(pattern string) (*TopicPattern, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("Topic pattern can not be empty")
+	}
+	if strings.ContainsAny(pattern, simpleWildcard+wildcard) {
+		return nil, fmt.Errorf("Topic pattern can not contain wildcards")
+	}
+	levels := strings.Split(pattern, "/")
+	for _, level := range levels {
+		if level == "" {
+			return nil, fmt.Errorf("Topic pattern can not contain empty levels")
+		}
+	}
+	switch levels[0] {
+	case "devices", string(AppEvents):
+		return nil, fmt.Errorf("Topic pattern can not start with %s", levels[0])
+	}
+
+	p := &TopicPattern{pattern: pattern}
+	rest := pattern
+	for rest != "" {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			if strings.Contains(rest, "}") {
+				return nil, fmt.Errorf("Topic pattern contains an unmatched }")
+			}
+			p.segments = append(p.segments, topicSegment{literal: rest})
+			break
+		}
+		if strings.Contains(rest[:open], "}") {
+			return nil, fmt.Errorf("Topic pattern contains an unmatched }")
+		}
+		if open > 0 {
+			p.segments = append(p.segments, topicSegment{literal: rest[:open]})
+		}
+		end := strings.Index(rest[open:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("Topic pattern contains an unmatched {")
+		}
+		placeholder := rest[open+1 : open+end]
+		if strings.ContainsAny(placeholder, "{/") {
+			return nil, fmt.Errorf("Topic pattern contains an invalid placeholder {%s", placeholder)
+		}
+		if !topicPlaceholders[placeholder] && (!strings.HasPrefix(placeholder, fieldPlaceholderPrefix) || placeholder == fieldPlaceholderPrefix) {
+			return nil, fmt.Errorf("Topic pattern contains an unknown placeholder {%s}", placeholder)
+		}
+		p.segments = append(p.segments, topicSegment{placeholder: placeholder})
+		rest = rest[open+end+1:]
+	}
+	return p, nil
+}
+
+// String implements the Stringer interface
+func (p TopicPattern) String() string {
+	return p.pattern
+}
+
+// Topic returns the topic for the uplink message
+func (p TopicPattern) Topic(up types.UplinkMessage) string {
+	topic := up.AppID + "/"
+	for _, segment := range p.segments {
+		if segment.placeholder == "" {
+			topic += segment.literal
+			continue
+		}
+		topic += topicValue(p.value(segment.placeholder, up))
+	}
+	return topic
+}
+
+func (p TopicPattern) value(placeholder string, up types.UplinkMessage) interface{} {
+	switch placeholder {
+	case "dev_id":
+		return up.DevID
+	case "hardware_serial":
+		return up.HardwareSerial
+	case "port":
+		return up.FPort
+	case "counter":
+		return up.FCnt
+	}
+	var value interface{} = up.PayloadFields
+	for _, name := range strings.Split(strings.TrimPrefix(placeholder, fieldPlaceholderPrefix), ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = fields[name]
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return nil
+	}
+	return value
+}
+
+// topicValue formats a value for use in a topic level
+func topicValue(value interface{}) string {
+	if value == nil {
+		return emptyTopicValue
+	}
+	str := strings.NewReplacer("/", "_", simpleWildcard, "_", wildcard, "_").Replace(fmt.Sprint(value))
+	if str == "" {
+		return emptyTopicValue
+	}
+	return str
+}
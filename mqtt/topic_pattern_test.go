@@ -0,0 +1,83 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestParseTopicPattern(t *testing.T) {
+	a := New(t)
+
+	for _, pattern := range []string{
+		"port/{port}",
+		"port/{port}/{dev_id}",
+		"{hardware_serial}/{counter}",
+		"zones/{field:gps.zone}/{dev_id}",
+		"up-{port}",
+	} {
+		p, err := ParseTopicPattern(pattern)
+		a.So(err, ShouldBeNil)
+		a.So(p.String(), ShouldEqual, pattern)
+	}
+
+	for _, pattern := range []string{
+		"",
+		"port/+",
+		"port/#",
+		"/port",
+		"port//{port}",
+		"port/",
+		"devices/{dev_id}",
+		"events/{port}",
+		"port/{port",
+		"port/port}",
+		"port/{unknown}",
+		"port/{field:}",
+		"port/{{port}}",
+	} {
+		_, err := ParseTopicPattern(pattern)
+		a.So(err, ShouldNotBeNil)
+	}
+}
+
+func TestTopicPatternTopic(t *testing.T) {
+	a := New(t)
+
+	up := types.UplinkMessage{
+		AppID:          "app",
+		DevID:          "dev",
+		HardwareSerial: "0102030405060708",
+		FPort:          2,
+		FCnt:           42,
+		PayloadFields: map[string]interface{}{
+			"type":  "temp/humidity",
+			"alarm": true,
+			"level": 3.0,
+			"gps":   map[string]interface{}{"zone": "north"},
+			"empty": "",
+		},
+	}
+
+	for pattern, topic := range map[string]string{
+		"port/{port}/{dev_id}":            "app/port/2/dev",
+		"{hardware_serial}/{counter}":     "app/0102030405060708/42",
+		"up-{port}":                       "app/up-2",
+		"zones/{field:gps.zone}/{dev_id}": "app/zones/north/dev",
+		"types/{field:type}":              "app/types/temp_humidity",
+		"alarms/{field:alarm}":            "app/alarms/true",
+		"levels/{field:level}":            "app/levels/3",
+		"missing/{field:unknown}":         "app/missing/_",
+		"nested/{field:gps}":              "app/nested/_",
+		"deep/{field:gps.zone.deeper}":    "app/deep/_",
+		"empty/{field:empty}":             "app/empty/_",
+	} {
+		p, err := ParseTopicPattern(pattern)
+		a.So(err, ShouldBeNil)
+		a.So(p.Topic(up), ShouldEqual, topic)
+	}
+}
@@ -16,14 +16,29 @@ type UplinkHandler func(client Client, appID string, devID string, req types.Upl
 
 // PublishUplink publishes an uplink message to the MQTT broker
 func (c *DefaultClient) PublishUplink(dataUp types.UplinkMessage) Token {
+	return c.PublishUplinkFormat(dataUp, types.PayloadFormatJSON)
+}
+
+// PublishUplinkFormat publishes an uplink message to the MQTT broker in the given format
+func (c *DefaultClient) PublishUplinkFormat(dataUp types.UplinkMessage, format types.PayloadFormat) Token {
 	topic := DeviceTopic{dataUp.AppID, dataUp.DevID, DeviceUplink, ""}
-	msg, err := json.Marshal(dataUp)
+	msg, err := types.MarshalUplinkMessage(format, dataUp)
 	if err != nil {
 		return &simpleToken{fmt.Errorf("Unable to marshal the message payload")}
 	}
 	return c.publish(topic.String(), msg)
 }
 
+// PublishUplinkPattern publishes an uplink message to the MQTT broker in the
+// given format on the topic of the given pattern
+func (c *DefaultClient) PublishUplinkPattern(dataUp types.UplinkMessage, pattern TopicPattern, format types.PayloadFormat) Token {
+	msg, err := types.MarshalUplinkMessage(format, dataUp)
+	if err != nil {
+		return &simpleToken{fmt.Errorf("Unable to marshal the message payload")}
+	}
+	return c.publish(pattern.Topic(dataUp), msg)
+}
+
 // PublishUplinkFields publishes uplink fields to MQTT
 func (c *DefaultClient) PublishUplinkFields(appID string, devID string, fields map[string]interface{}) Token {
 	flattenedFields := make(map[string]interface{})
@@ -75,7 +90,7 @@ func (c *DefaultClient) SubscribeDeviceUplink(appID string, devID string, handle
 
 		// Unmarshal the payload
 		dataUp := &types.UplinkMessage{}
-		err = json.Unmarshal(msg.Payload(), dataUp)
+		err = types.UnmarshalUplinkMessage(msg.Payload(), dataUp)
 		dataUp.AppID = topic.AppID
 		dataUp.DevID = topic.DevID
 
@@ -183,6 +183,41 @@ func TestPubSubUplink(t *testing.T) {
 	waitForOK(unsubToken, a)
 }
 
+func TestPubSubUplinkFormat(t *testing.T) {
+	a := New(t)
+	c := NewClient(getLogger(t, "Test"), "test", "", "", fmt.Sprintf("tcp://%s", host))
+	c.Connect()
+	defer c.Disconnect()
+
+	waitChan := make(chan types.UplinkMessage, 1)
+
+	subToken := c.SubscribeDeviceUplink("app1", "dev2", func(client Client, appID string, devID string, req types.UplinkMessage) {
+		waitChan <- req
+	})
+	waitForOK(subToken, a)
+
+	for _, format := range []types.PayloadFormat{types.PayloadFormatProtobuf, types.PayloadFormatCBOR} {
+		pubToken := c.PublishUplinkFormat(types.UplinkMessage{
+			PayloadRaw:    []byte{0x01, 0x02, 0x03, 0x04},
+			PayloadFields: map[string]interface{}{"temperature": 21.5},
+			AppID:         "app1",
+			DevID:         "dev2",
+		}, format)
+		waitForOK(pubToken, a)
+
+		select {
+		case up := <-waitChan:
+			a.So(up.PayloadRaw, ShouldResemble, []byte{0x01, 0x02, 0x03, 0x04})
+			a.So(up.PayloadFields["temperature"], ShouldEqual, 21.5)
+		case <-time.After(1 * time.Second):
+			panic("Did not receive Uplink")
+		}
+	}
+
+	unsubToken := c.UnsubscribeDeviceUplink("app1", "dev2")
+	waitForOK(unsubToken, a)
+}
+
 func TestPubSubAppUplink(t *testing.T) {
 	a := New(t)
 	c := NewClient(getLogger(t, "Test"), "test", "", "", fmt.Sprintf("tcp://%s", host))
@@ -0,0 +1,92 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var applicationsApplyCmd = &cobra.Command{
+	Use:   "apply [File]",
+	Short: "Apply a YAML configuration to an application",
+	Long: `ttnctl applications apply changes an application to match a YAML file that was created with ttnctl applications export.
+The settings, payload functions and rules of the application are replaced, and the devices in the file are registered or updated.
+Devices without keys in the file keep their current keys. With --prune, devices that are not in the file are deleted.`,
+	Example: `$ ttnctl applications apply test.yml --prune
+  INFO Using Application                        AppID=test
+Are you sure you want to delete the devices of application test that are not in test.yml?
+> yes
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Applied configuration                    AppID=test Created=1 Deleted=1 Updated=11
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		appID := util.GetAppID(ctx)
+
+		yml, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not read file")
+		}
+		export, err := util.YAMLToJSON(yml)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not parse YAML")
+		}
+		var config struct {
+			AppID string `json:"app_id"`
+		}
+		if err := json.Unmarshal(export, &config); err != nil {
+			ctx.WithError(err).Fatal("Invalid application configuration")
+		}
+		if config.AppID != "" && config.AppID != appID {
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				ctx.Fatalf("The file is a configuration of application %s, use --force to apply it to application %s", config.AppID, appID)
+			}
+		}
+
+		prune, _ := cmd.Flags().GetBool("prune")
+		if prune && !confirm(fmt.Sprintf("Are you sure you want to delete the devices of application %s that are not in %s?", appID, args[0])) {
+			ctx.Info("Not doing anything")
+			return
+		}
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/import", strings.TrimSuffix(apiAddress, "/"), appID)
+		if prune {
+			url += "?prune=true"
+		}
+		res := util.HandlerAPIRequest(ctx, "POST", url, appID, bytes.NewReader(export))
+		defer res.Body.Close()
+		var imported struct {
+			Created []string `json:"created"`
+			Updated []string `json:"updated"`
+			Deleted []string `json:"deleted"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&imported); err != nil {
+			ctx.WithError(err).Fatal("Could not decode import response")
+		}
+
+		ctx.WithFields(ttnlog.Fields{
+			"AppID":   appID,
+			"Created": len(imported.Created),
+			"Updated": len(imported.Updated),
+			"Deleted": len(imported.Deleted),
+		}).Info("Applied configuration")
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsApplyCmd)
+	applicationsApplyCmd.Flags().Bool("prune", false, "Delete the devices that are not in the file")
+	applicationsApplyCmd.Flags().Bool("force", false, "Apply the configuration of another application")
+}
@@ -0,0 +1,185 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var applicationsChirpStackCmd = &cobra.Command{
+	Use:   "chirpstack",
+	Short: "Import applications from ChirpStack and export them to ChirpStack",
+	Long: `ttnctl applications chirpstack migrates applications between ChirpStack and this network.
+The devices, their keys and sessions, and the JavaScript payload codecs are converted.`,
+}
+
+var applicationsChirpStackImportCmd = &cobra.Command{
+	Use:   "import [File]",
+	Short: "Import an application from ChirpStack",
+	Long: `ttnctl applications chirpstack import registers the devices of a ChirpStack application in this application,
+with their keys and sessions, and converts the JavaScript payload codecs. The ChirpStack application is read from a
+JSON file in the format of ttnctl applications chirpstack export, or from the REST API of ChirpStack. ChirpStack does
+not store the AppEUI of devices, so the devices are registered with the AppEUI of this application. The frame counters
+of the sessions are kept, so that the devices can continue without joining again.`,
+	Example: `$ ttnctl applications chirpstack import --chirpstack-url https://chirpstack.example.com --chirpstack-token $TOKEN --chirpstack-application-id 3
+  INFO Using Application                        AppEUI=70B3D57EF0000001 AppID=test
+  INFO Reading ChirpStack application...        ApplicationID=3
+  WARN Variables of device sensor-3 are not supported
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Imported ChirpStack application          AppID=test Created=12 Updated=0
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 1)
+
+		appID := util.GetAppID(ctx)
+		appEUI := util.GetAppEUI(ctx)
+		ctx.WithFields(ttnlog.Fields{
+			"AppID":  appID,
+			"AppEUI": appEUI,
+		}).Info("Using Application")
+
+		var in *util.ChirpStackExport
+		if len(args) == 1 {
+			data, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not read file")
+			}
+			in = new(util.ChirpStackExport)
+			if err := json.Unmarshal(data, in); err != nil {
+				ctx.WithError(err).Fatal("Invalid ChirpStack application")
+			}
+		} else {
+			url, _ := cmd.Flags().GetString("chirpstack-url")
+			token, _ := cmd.Flags().GetString("chirpstack-token")
+			applicationID, _ := cmd.Flags().GetString("chirpstack-application-id")
+			if url == "" || applicationID == "" {
+				ctx.Fatal("Give a file or the --chirpstack-url and --chirpstack-application-id of the ChirpStack application")
+			}
+			ctx.WithField("ApplicationID", applicationID).Info("Reading ChirpStack application...")
+			var err error
+			in, err = util.NewChirpStackClient(url, token).GetApplication(applicationID)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not read ChirpStack application")
+			}
+		}
+
+		export, warnings, err := util.FromChirpStack(in, appID, appEUI)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not convert ChirpStack application")
+		}
+		for _, warning := range warnings {
+			ctx.Warn(warning)
+		}
+		if codecs, _ := cmd.Flags().GetBool("codecs"); !codecs {
+			export.Settings.Decoder, export.Settings.Encoder = "", ""
+		}
+
+		// Without payload functions in the export, the import would remove the current payload functions
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		res := util.HandlerAPIRequest(ctx, "GET", fmt.Sprintf("%s/applications/%s/export", strings.TrimSuffix(apiAddress, "/"), appID), appID, nil)
+		var current handler.ApplicationExport
+		err = json.NewDecoder(res.Body).Decode(&current)
+		res.Body.Close()
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not decode application export")
+		}
+		settings := current.Settings
+		if export.Settings.Decoder != "" || export.Settings.Encoder != "" {
+			settings.Decoder, settings.Encoder = export.Settings.Decoder, export.Settings.Encoder
+			settings.Converter, settings.Validator = "", ""
+			settings.PayloadTests = nil
+		}
+		export.Settings = settings
+
+		request, err := json.Marshal(export)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not encode application")
+		}
+		res = util.HandlerAPIRequest(ctx, "POST", fmt.Sprintf("%s/applications/%s/import", strings.TrimSuffix(apiAddress, "/"), appID), appID, bytes.NewReader(request))
+		defer res.Body.Close()
+		var imported handler.ImportResponse
+		if err := json.NewDecoder(res.Body).Decode(&imported); err != nil {
+			ctx.WithError(err).Fatal("Could not decode import response")
+		}
+
+		ctx.WithFields(ttnlog.Fields{
+			"AppID":   appID,
+			"Created": len(imported.Created),
+			"Updated": len(imported.Updated),
+		}).Info("Imported ChirpStack application")
+	},
+}
+
+var applicationsChirpStackExportCmd = &cobra.Command{
+	Use:   "export [File]",
+	Short: "Export an application for ChirpStack",
+	Long: `ttnctl applications chirpstack export writes the devices of an application, with their keys and sessions, and its
+payload functions as a ChirpStack application in JSON to a file, or to stdout if no file is given. The frame counters
+of the sessions of ABP devices are included. Stop the devices of the application in this network before they continue
+in ChirpStack, so that the frame counters are not reused.`,
+	Example: `$ ttnctl applications chirpstack export test.json
+  INFO Using Application                        AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  WARN Rules are not supported
+  INFO Exported application for ChirpStack      AppID=test Devices=12 File=test.json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 1)
+
+		appID := util.GetAppID(ctx)
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/export?devices=true&counters=true", strings.TrimSuffix(apiAddress, "/"), appID)
+		res := util.HandlerAPIRequest(ctx, "GET", url, appID, nil)
+		var export handler.ApplicationExport
+		err := json.NewDecoder(res.Body).Decode(&export)
+		res.Body.Close()
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not decode application export")
+		}
+
+		out, warnings := util.ToChirpStack(&export)
+		for _, warning := range warnings {
+			ctx.Warn(warning)
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not encode ChirpStack application")
+		}
+
+		if len(args) == 0 {
+			os.Stdout.Write(data)
+			return
+		}
+		if err := ioutil.WriteFile(args[0], data, 0600); err != nil {
+			ctx.WithError(err).Fatal("Could not write file")
+		}
+		ctx.WithFields(ttnlog.Fields{
+			"AppID":   appID,
+			"Devices": len(out.Devices),
+			"File":    args[0],
+		}).Info("Exported application for ChirpStack")
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsChirpStackCmd)
+	applicationsChirpStackCmd.AddCommand(applicationsChirpStackImportCmd)
+	applicationsChirpStackImportCmd.Flags().String("chirpstack-url", "", "The URL of the REST API of ChirpStack")
+	applicationsChirpStackImportCmd.Flags().String("chirpstack-token", "", "The API token for ChirpStack")
+	applicationsChirpStackImportCmd.Flags().String("chirpstack-application-id", "", "The ID of the application in ChirpStack")
+	applicationsChirpStackImportCmd.Flags().Bool("codecs", true, "Replace the payload functions by the converted payload codec")
+	applicationsChirpStackCmd.AddCommand(applicationsChirpStackExportCmd)
+}
@@ -0,0 +1,99 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var applicationsCloneCmd = &cobra.Command{
+	Use:   "clone [Target AppID]",
+	Short: "Clone this application into another application",
+	Long: `ttnctl applications clone copies the payload functions and settings of this application to another application, optionally with its devices.
+The other application must already be registered to its Handler, which can be another Handler than the Handler of this application.
+This replaces the settings of the other application. Devices can only be registered once per NetworkServer, so they
+can only be cloned to a Handler in another region.`,
+	Example: `$ ttnctl applications clone production --devices --target-handler-id ttn-handler-us-west
+  INFO Using Application                        AppID=staging
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Exported application                     AppID=staging Devices=12
+  INFO Discovering Handler...                   Handler=ttn-handler-us-west
+  INFO Cloned application                       AppID=production Devices=12
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		targetAppID := args[0]
+		if !api.ValidID(targetAppID) {
+			ctx.Fatal("Invalid Application ID")
+		}
+
+		appID := util.GetAppID(ctx)
+		if appID == targetAppID {
+			ctx.Fatal("Can not clone an application into itself")
+		}
+
+		devices, _ := cmd.Flags().GetBool("devices")
+		targetHandlerID, _ := cmd.Flags().GetString("target-handler-id")
+		if targetHandlerID == "" {
+			targetHandlerID = viper.GetString("handler-id")
+		}
+
+		// Export
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/export", strings.TrimSuffix(apiAddress, "/"), appID)
+		if devices {
+			url += "?devices=true"
+		}
+		res := util.HandlerAPIRequest(ctx, "GET", url, appID, nil)
+		export, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not read application export")
+		}
+		var exported struct {
+			Devices []json.RawMessage `json:"devices"`
+		}
+		if err := json.Unmarshal(export, &exported); err != nil {
+			ctx.WithError(err).Fatal("Could not decode application export")
+		}
+		ctx.WithFields(ttnlog.Fields{
+			"AppID":   appID,
+			"Devices": len(exported.Devices),
+		}).Info("Exported application")
+
+		// Import
+		apiAddress = util.GetHandlerAPIAddress(ctx, targetHandlerID)
+		url = fmt.Sprintf("%s/applications/%s/import", strings.TrimSuffix(apiAddress, "/"), targetAppID)
+		res = util.HandlerAPIRequest(ctx, "POST", url, targetAppID, bytes.NewReader(export))
+		defer res.Body.Close()
+		var imported struct {
+			Devices int `json:"devices"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&imported); err != nil {
+			ctx.WithError(err).Fatal("Could not decode import response")
+		}
+
+		ctx.WithFields(ttnlog.Fields{
+			"AppID":   targetAppID,
+			"Devices": imported.Devices,
+		}).Info("Cloned application")
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsCloneCmd)
+	applicationsCloneCmd.Flags().Bool("devices", false, "Also clone the devices (only to a Handler in another region)")
+	applicationsCloneCmd.Flags().String("target-handler-id", "", "The ID of the Handler of the target application (default the Handler of this application)")
+}
@@ -0,0 +1,71 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var applicationsExportCmd = &cobra.Command{
+	Use:   "export [File]",
+	Short: "Export the configuration of an application as YAML",
+	Long: `ttnctl applications export writes the settings, payload functions, rules, integrations and optionally the devices of an application
+as declarative YAML to a file, or to stdout if no file is given. The file can be changed and applied with ttnctl applications apply.`,
+	Example: `$ ttnctl applications export test.yml --devices --keys=false
+  INFO Using Application                        AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Exported application                     AppID=test File=test.yml
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 1)
+
+		appID := util.GetAppID(ctx)
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/export", strings.TrimSuffix(apiAddress, "/"), appID)
+		if devices, _ := cmd.Flags().GetBool("devices"); devices {
+			url += "?devices=true"
+			if keys, _ := cmd.Flags().GetBool("keys"); !keys {
+				url += "&keys=false"
+			}
+		}
+		res := util.HandlerAPIRequest(ctx, "GET", url, appID, nil)
+		export, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not read application export")
+		}
+
+		yml, err := util.JSONToYAML(export)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not convert application export to YAML")
+		}
+
+		if len(args) == 0 {
+			os.Stdout.Write(yml)
+			return
+		}
+		if err := ioutil.WriteFile(args[0], yml, 0600); err != nil {
+			ctx.WithError(err).Fatal("Could not write file")
+		}
+		ctx.WithFields(ttnlog.Fields{
+			"AppID": appID,
+			"File":  args[0],
+		}).Info("Exported application")
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsExportCmd)
+	applicationsExportCmd.Flags().Bool("devices", false, "Also export the devices")
+	applicationsExportCmd.Flags().Bool("keys", true, "Export the keys of the devices")
+}
@@ -0,0 +1,62 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+)
+
+var applicationsFrameHistoryCmd = &cobra.Command{
+	Use:   "frame-history",
+	Short: "Show or set the frame history settings of an application",
+	Long: `ttnctl applications frame-history shows or sets the depth and retention of the frame history of the
+devices of an application. Devices with frame history settings of their own (see ttnctl devices frame-history)
+use those instead. The depth and TTL are bounded by the limits of the NetworkServer.`,
+	Example: `$ ttnctl applications frame-history --depth 100 --ttl 24h
+  INFO Using Application                        AppEUI=70B3D57EF0000024 AppID=test
+  INFO Discovering NetworkServer...             NetworkServer=ttn-networkserver-eu
+  INFO Set frame history settings               AppID=test
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 0)
+
+		depth, _ := cmd.Flags().GetInt("depth")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+
+		appID := util.GetAppID(ctx)
+		path := fmt.Sprintf("/applications/%s/frame-history", appID)
+
+		if cmd.Flags().Changed("depth") || cmd.Flags().Changed("ttl") {
+			settings := frameHistory{Depth: depth}
+			if ttl > 0 {
+				settings.TTL = ttl.String()
+			}
+			data, _ := json.Marshal(settings)
+			res, err := requestNetworkServer(appID, path, "PUT", bytes.NewReader(data))
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not set frame history settings")
+			}
+			res.Body.Close()
+			ctx.WithField("AppID", appID).Info("Set frame history settings")
+			return
+		}
+
+		res, err := requestNetworkServer(appID, path, "GET", nil)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get frame history settings")
+		}
+		printFrameHistory(res)
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsFrameHistoryCmd)
+	applicationsFrameHistoryCmd.Flags().Int("depth", 0, "Number of frames in the frame history (default of the NetworkServer if 0)")
+	applicationsFrameHistoryCmd.Flags().Duration("ttl", 0, "Time after which frames are removed from the frame history (no expiry if 0)")
+}
@@ -0,0 +1,76 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+)
+
+type gatewayGroups struct {
+	GatewayGroups []string `json:"gateway_groups"`
+}
+
+var applicationsGatewayGroupsCmd = &cobra.Command{
+	Use:   "gateway-groups [Group...]",
+	Short: "Show or set the gateway groups that the devices of an application prefer for downlink",
+	Long: `ttnctl applications gateway-groups shows or sets the gateway groups that the devices of an application
+prefer for downlink, most preferred first. The groups are configured by the operator of the Broker.
+Devices with preferences of their own (see ttnctl devices set --gateway-groups) use those instead.
+With --clear, the preferences of the application are removed.`,
+	Example: `$ ttnctl applications gateway-groups site-a site-b
+  INFO Using Application                        AppEUI=70B3D57EF0000024 AppID=test
+  INFO Discovering NetworkServer...             NetworkServer=ttn-networkserver-eu
+  INFO Set gateway groups                       AppID=test
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		clearGroups, _ := cmd.Flags().GetBool("clear")
+		if clearGroups {
+			assertArgsLength(cmd, args, 0, 0)
+		}
+
+		appID := util.GetAppID(ctx)
+		path := fmt.Sprintf("/applications/%s/gateway-groups", appID)
+
+		if len(args) > 0 || clearGroups {
+			data, _ := json.Marshal(gatewayGroups{GatewayGroups: args})
+			res, err := requestNetworkServer(appID, path, "PUT", bytes.NewReader(data))
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not set gateway groups")
+			}
+			res.Body.Close()
+			ctx.WithField("AppID", appID).Info("Set gateway groups")
+			return
+		}
+
+		res, err := requestNetworkServer(appID, path, "GET", nil)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get gateway groups")
+		}
+		defer res.Body.Close()
+
+		var groups gatewayGroups
+		if err := json.NewDecoder(res.Body).Decode(&groups); err != nil {
+			ctx.WithError(err).Fatal("Could not decode gateway groups")
+		}
+
+		fmt.Println()
+		if len(groups.GatewayGroups) == 0 {
+			fmt.Println("  Gateway groups: none")
+		} else {
+			fmt.Printf("  Gateway groups: %s\n", strings.Join(groups.GatewayGroups, ", "))
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsGatewayGroupsCmd)
+	applicationsGatewayGroupsCmd.Flags().Bool("clear", false, "Remove the gateway groups of the application")
+}
@@ -0,0 +1,102 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var applicationsMigrateCmd = &cobra.Command{
+	Use:   "migrate [Target Handler ID]",
+	Short: "Migrate devices of an application to another Handler",
+	Long: `ttnctl applications migrate migrates devices of an application to another Handler.
+The application and its devices are synchronized to the target Handler, after which the
+Brokers route a percentage of the devices, and the devices in --devices, to the target Handler.
+Running the command again with a higher percentage migrates more devices. When all devices work
+on the target Handler, --cutover moves the application to it. --abort routes all devices to the
+current Handler again. Without arguments, the current migration is shown.`,
+	Example: `$ ttnctl applications migrate ttn-handler-eu-2 --percentage 10
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Migrating devices                        AppID=test Devices=42 Percentage=10 ToHandler=ttn-handler-eu-2
+$ ttnctl applications migrate --cutover
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Migrated application                     AppID=test Devices=42 Handler=ttn-handler-eu-2
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 1)
+
+		appID := util.GetAppID(ctx)
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/migration", strings.TrimSuffix(apiAddress, "/"), appID)
+
+		cutover, _ := cmd.Flags().GetBool("cutover")
+		abort, _ := cmd.Flags().GetBool("abort")
+
+		method := "GET"
+		var body io.Reader
+		switch {
+		case cutover && abort:
+			ctx.Fatal("Can not use --cutover and --abort together")
+		case cutover:
+			method = "POST"
+			url += "/cutover"
+		case abort:
+			method = "DELETE"
+		case len(args) == 1:
+			percentage, _ := cmd.Flags().GetInt("percentage")
+			devices, _ := cmd.Flags().GetStringSlice("devices")
+			request, _ := json.Marshal(handler.MigrationRequest{ToHandler: args[0], Percentage: percentage, Devices: devices})
+			method = "PUT"
+			body = bytes.NewReader(request)
+		}
+
+		res := util.HandlerAPIRequest(ctx, method, url, appID, body)
+		defer res.Body.Close()
+		var response handler.MigrationResponse
+		if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+			ctx.WithError(err).Fatal("Could not decode migration")
+		}
+
+		switch {
+		case cutover:
+			ctx.WithField("AppID", appID).WithField("Handler", response.Handler).WithField("Devices", response.Devices).Info("Migrated application")
+			return
+		case abort:
+			ctx.WithField("AppID", appID).Info("Aborted migration")
+			return
+		case response.Migration == nil:
+			ctx.WithField("AppID", appID).Info("Application is not being migrated")
+			return
+		}
+
+		migration := response.Migration
+		migrationCtx := ctx.WithField("AppID", appID).WithField("ToHandler", migration.ToHandler).WithField("Percentage", migration.Percentage)
+		if len(migration.Devices) > 0 {
+			migrationCtx = migrationCtx.WithField("DevIDs", strings.Join(migration.Devices, ","))
+		}
+		if method == "PUT" {
+			migrationCtx.WithField("Devices", response.Devices).Info("Migrating devices")
+		} else {
+			migrationCtx.WithField("StartedAt", migration.StartedAt).WithField("SyncedAt", migration.SyncedAt).Info("Migration")
+		}
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsMigrateCmd)
+	applicationsMigrateCmd.Flags().Int("percentage", 0, "Percentage of the devices to migrate")
+	applicationsMigrateCmd.Flags().StringSlice("devices", []string{}, "IDs of devices to migrate in addition to the percentage")
+	applicationsMigrateCmd.Flags().Bool("cutover", false, "Complete the migration, moving the application to the target Handler")
+	applicationsMigrateCmd.Flags().Bool("abort", false, "Abort the migration, routing all devices to the current Handler again")
+}
@@ -0,0 +1,108 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var applicationsPayloadFunctionsTestCmd = &cobra.Command{
+	Use:   "test [tests.json]",
+	Short: "Run the payload tests of an application",
+	Long: `ttnctl applications pf test runs the test vectors of the payload functions of an application.
+If a file is supplied, the tests in the file replace the tests of the application first.
+The Handler rejects payload functions that do not pass the tests of the application.`,
+	Example: `$ cat tests.json
+{
+  "tests": [
+    {"name": "room", "port": 1, "payload_raw": "CHA=", "payload_fields": {"temperature": 21.6}},
+    {"name": "too hot", "port": 1, "payload_raw": "JxA=", "invalid": true}
+  ]
+}
+$ ttnctl applications pf test tests.json
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Passed                                   Name=room
+  WARN Failed                                   Name=too hot Valid=true
+  FATAL Payload tests failed                    AppID=test Failed=1 Passed=1
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 1)
+
+		appID := util.GetAppID(ctx)
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/payload-tests", strings.TrimSuffix(apiAddress, "/"), appID)
+
+		method := "POST"
+		var body io.Reader
+		if len(args) == 1 {
+			tests, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not read tests file")
+			}
+			var in handler.PayloadTestsRequest
+			if err := json.Unmarshal(tests, &in); err != nil {
+				ctx.WithError(err).Fatal("Could not decode tests file")
+			}
+			method = "PUT"
+			body = bytes.NewReader(tests)
+		} else {
+			url += "/run"
+		}
+
+		res := util.HandlerAPIRequest(ctx, method, url, appID, body)
+		defer res.Body.Close()
+		var response handler.PayloadTestsResponse
+		if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+			ctx.WithError(err).Fatal("Could not decode test results")
+		}
+
+		for i, result := range response.Results {
+			name := result.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			if result.Passed {
+				ctx.WithField("Name", name).Info("Passed")
+				continue
+			}
+			fields := ttnlog.Fields{"Name": name, "Valid": result.Valid}
+			if len(result.Changed) > 0 {
+				fields["Changed"] = strings.Join(result.Changed, ",")
+			}
+			if result.Error != "" {
+				fields["Error"] = result.Error
+			}
+			ctx.WithFields(fields).Warn("Failed")
+			for _, log := range result.Logs {
+				ctx.WithField("Function", log.Function).Info(strings.Join(log.Fields, " "))
+			}
+		}
+
+		ctx = ctx.WithFields(ttnlog.Fields{
+			"AppID":  appID,
+			"Passed": response.Passed,
+			"Failed": response.Failed,
+		})
+		if response.Failed > 0 {
+			ctx.Fatal("Payload tests failed")
+		}
+		ctx.Info("Payload tests passed")
+	},
+}
+
+func init() {
+	applicationsPayloadFunctionsCmd.AddCommand(applicationsPayloadFunctionsTestCmd)
+}
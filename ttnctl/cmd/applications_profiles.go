@@ -0,0 +1,164 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var applicationsProfilesCmd = &cobra.Command{
+	Use:   "profiles [Profile ID]",
+	Short: "Show or set the device profiles of an application",
+	Long: `ttnctl applications profiles shows the device profiles of an application.
+If a profile ID is supplied, the flags that are set change that profile, which is created if it does not exist.
+A device profile contains the LoRaWAN version, class, receive window parameters, payload codec and ADR limits of
+the devices that use it. Changing a profile changes all these devices. Use ttnctl devices profile to set the
+profile of a device.`,
+	Example: `$ ttnctl applications profiles sensor --class A --codec acme/thermometer --max-data-rate SF8BW125
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Updated profile                          AppID=test Devices=12 ProfileID=sensor
+
+  ID                Version  Class  Codec                     ADR Limits                    Description
+  sensor                     A      acme/thermometer          max SF8BW125
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 1)
+
+		appID := util.GetAppID(ctx)
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/profiles", strings.TrimSuffix(apiAddress, "/"), appID)
+
+		res := util.HandlerAPIRequest(ctx, "GET", url, appID, nil)
+		var response handler.ProfilesResponse
+		if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+			ctx.WithError(err).Fatal("Could not decode profiles")
+		}
+		res.Body.Close()
+
+		if len(args) == 1 {
+			profileID := args[0]
+			if !api.ValidID(profileID) {
+				ctx.Fatal("Invalid Profile ID")
+			}
+			profile := application.DeviceProfile{ID: profileID}
+			for _, existing := range response.Profiles {
+				if existing.ID == profileID {
+					profile = existing
+				}
+			}
+
+			if remove, _ := cmd.Flags().GetBool("delete"); remove {
+				res := util.HandlerAPIRequest(ctx, "DELETE", url+"/"+profileID, appID, nil)
+				response = handler.ProfilesResponse{}
+				if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+					ctx.WithError(err).Fatal("Could not decode profiles")
+				}
+				res.Body.Close()
+				ctx.WithField("AppID", appID).WithField("ProfileID", profileID).Info("Deleted profile")
+			} else {
+				flags := cmd.Flags()
+				if flags.Changed("description") {
+					profile.Description, _ = flags.GetString("description")
+				}
+				if flags.Changed("lorawan-version") {
+					profile.LoRaWANVersion, _ = flags.GetString("lorawan-version")
+				}
+				if flags.Changed("class") {
+					profile.Class, _ = flags.GetString("class")
+				}
+				if flags.Changed("codec") {
+					profile.Codec, _ = flags.GetString("codec")
+				}
+				if flags.Changed("rx1-delay") {
+					delay, _ := flags.GetUint8("rx1-delay")
+					profile.JoinAccept.RX1Delay = delay
+				}
+				if flags.Changed("rx1-dr-offset") {
+					offset, _ := flags.GetUint8("rx1-dr-offset")
+					profile.JoinAccept.RX1DROffset = offset
+				}
+				if flags.Changed("rx2-data-rate") {
+					profile.JoinAccept.RX2DataRate, _ = flags.GetString("rx2-data-rate")
+				}
+				if flags.Changed("min-data-rate") {
+					profile.ADRLimits.MinDataRate, _ = flags.GetString("min-data-rate")
+				}
+				if flags.Changed("max-data-rate") {
+					profile.ADRLimits.MaxDataRate, _ = flags.GetString("max-data-rate")
+				}
+				if flags.Changed("max-tx-power") {
+					profile.ADRLimits.MaxTxPower, _ = flags.GetInt("max-tx-power")
+				}
+
+				request, _ := json.Marshal(profile)
+				res := util.HandlerAPIRequest(ctx, "PUT", url+"/"+profileID, appID, bytes.NewReader(request))
+				var updated handler.ProfileResponse
+				if err := json.NewDecoder(res.Body).Decode(&updated); err != nil {
+					ctx.WithError(err).Fatal("Could not decode profile")
+				}
+				res.Body.Close()
+				ctx.WithField("AppID", appID).WithField("ProfileID", profileID).WithField("Devices", updated.Devices).Info("Updated profile")
+				if len(updated.Failed) > 0 {
+					ctx.WithField("Devices", strings.Join(updated.Failed, ", ")).Warn("Could not set ADR limits of devices in NetworkServer")
+				}
+
+				profiles := make([]application.DeviceProfile, 0, len(response.Profiles)+1)
+				for _, existing := range response.Profiles {
+					if existing.ID != profileID {
+						profiles = append(profiles, existing)
+					}
+				}
+				response.Profiles = append(profiles, updated.Profile)
+			}
+		}
+
+		fmt.Println()
+		if len(response.Profiles) == 0 {
+			fmt.Println("  This application has no device profiles")
+			fmt.Println()
+			return
+		}
+		fmt.Printf("  %-16s  %-7s  %-5s  %-24s  %-28s  %s\n", "ID", "Version", "Class", "Codec", "ADR Limits", "Description")
+		for _, profile := range response.Profiles {
+			var limits []string
+			if profile.ADRLimits.MinDataRate != "" {
+				limits = append(limits, "min "+profile.ADRLimits.MinDataRate)
+			}
+			if profile.ADRLimits.MaxDataRate != "" {
+				limits = append(limits, "max "+profile.ADRLimits.MaxDataRate)
+			}
+			if profile.ADRLimits.MaxTxPower != 0 {
+				limits = append(limits, fmt.Sprintf("%d dBm", profile.ADRLimits.MaxTxPower))
+			}
+			fmt.Printf("  %-16s  %-7s  %-5s  %-24s  %-28s  %s\n", profile.ID, profile.LoRaWANVersion, profile.Class, profile.Codec, strings.Join(limits, ", "), profile.Description)
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsProfilesCmd)
+	applicationsProfilesCmd.Flags().Bool("delete", false, "Delete the profile, which must not be used by devices")
+	applicationsProfilesCmd.Flags().String("description", "", "Description of the profile")
+	applicationsProfilesCmd.Flags().String("lorawan-version", "", "LoRaWAN version of the devices (1.0, 1.0.1 or 1.0.2)")
+	applicationsProfilesCmd.Flags().String("class", "", "Class of the devices (only A is supported)")
+	applicationsProfilesCmd.Flags().String("codec", "", "Payload codec of the devices in the codec repository of the Handler")
+	applicationsProfilesCmd.Flags().Uint8("rx1-delay", 0, "RX1 delay in seconds that is sent in the join-accept (0 for the default)")
+	applicationsProfilesCmd.Flags().Uint8("rx1-dr-offset", 0, "RX1 data rate offset that is sent in the join-accept")
+	applicationsProfilesCmd.Flags().String("rx2-data-rate", "", "RX2 data rate that is sent in the join-accept (empty for the default)")
+	applicationsProfilesCmd.Flags().String("min-data-rate", "", "Slowest data rate that ADR selects (for example SF10BW125)")
+	applicationsProfilesCmd.Flags().String("max-data-rate", "", "Fastest data rate that ADR selects (for example SF7BW125)")
+	applicationsProfilesCmd.Flags().Int("max-tx-power", 0, "Highest TX power in dBm that ADR selects (0 for no limit)")
+}
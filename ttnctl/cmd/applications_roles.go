@@ -0,0 +1,97 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var applicationsRolesCmd = &cobra.Command{
+	Use:   "roles [Username [Role]]",
+	Short: "Show or set the roles of the collaborators of an application",
+	Long: `ttnctl applications roles shows the roles of the collaborators of an application.
+If a username and a role (viewer, developer or admin) are supplied, the role of that collaborator is set.
+A role limits the rights of a collaborator on the Handler, also on MQTT:
+viewers can only read, developers can not delete the application or manage roles.`,
+	Example: `$ ttnctl applications roles alice developer
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Updated roles                            AppID=test
+
+  Username  Role
+  alice     developer
+  bob       viewer
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 2)
+
+		appID := util.GetAppID(ctx)
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/roles", strings.TrimSuffix(apiAddress, "/"), appID)
+
+		method := "GET"
+		var body io.Reader
+		if len(args) > 0 {
+			url += "/" + args[0]
+			if remove, _ := cmd.Flags().GetBool("delete"); remove {
+				method = "DELETE"
+			} else {
+				if len(args) != 2 {
+					ctx.Fatal("Role is required, use --delete to remove the role of a collaborator")
+				}
+				role := application.Role(args[1])
+				if !role.Valid() {
+					ctx.Fatalf("Invalid role %s, must be %s, %s or %s", role, application.RoleViewer, application.RoleDeveloper, application.RoleAdmin)
+				}
+				request, _ := json.Marshal(handler.RoleRequest{Role: role})
+				method = "PUT"
+				body = bytes.NewReader(request)
+			}
+		}
+
+		res := util.HandlerAPIRequest(ctx, method, url, appID, body)
+		defer res.Body.Close()
+		var response handler.RolesResponse
+		if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+			ctx.WithError(err).Fatal("Could not decode roles")
+		}
+
+		if method != "GET" {
+			ctx.WithField("AppID", appID).Info("Updated roles")
+		}
+
+		fmt.Println()
+		if len(response.Collaborators) == 0 {
+			fmt.Println("  The collaborators of this application have no roles")
+			fmt.Println()
+			return
+		}
+		width := len("Username")
+		for _, collaborator := range response.Collaborators {
+			if len(collaborator.Username) > width {
+				width = len(collaborator.Username)
+			}
+		}
+		fmt.Printf("  %-*s  %s\n", width, "Username", "Role")
+		for _, collaborator := range response.Collaborators {
+			fmt.Printf("  %-*s  %s\n", width, collaborator.Username, collaborator.Role)
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	applicationsCmd.AddCommand(applicationsRolesCmd)
+	applicationsRolesCmd.Flags().Bool("delete", false, "Remove the role of the collaborator, so that it is a viewer, or has the rights of its token if no collaborator has a role")
+}
@@ -0,0 +1,173 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/spf13/cobra"
+)
+
+var hexPayload = regexp.MustCompile("^([0-9a-fA-F]{2})+$")
+
+func parsePHYPayload(in string) ([]byte, error) {
+	in = strings.TrimSpace(in)
+	if hexPayload.MatchString(in) {
+		return hex.DecodeString(in)
+	}
+	return base64.StdEncoding.DecodeString(in)
+}
+
+func formatMACCommands(commands []pb_lorawan.DecodedMACCommand) string {
+	formatted := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		name := cmd.Name
+		if name == "" {
+			name = "Unknown"
+		}
+		formatted = append(formatted, fmt.Sprintf("%s (0x%02X) %X", name, cmd.CID, cmd.Payload))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+var decodeCmd = &cobra.Command{
+	Use:   "decode [PHYPayload]",
+	Short: "Decode a LoRaWAN message",
+	Long: `ttnctl decode can be used to decode a LoRaWAN message, for example from the logs of a gateway.
+The PHYPayload can be given as base64 or hex. If the keys are given, the MIC is verified and the payload is decrypted.`,
+	Example: `$ ttnctl decode QPF9vkkAAgABlUN4disR/w0= --nwk-s-key 44024241ED4CE9A68C6A8BC055233FD3 --app-s-key EC925802AE430CA77FD3DD73CB2CC588
+
+  MType:       UNCONFIRMED_UP
+  Major:       LORAWAN_R1
+  DevAddr:     49BE7DF1
+  FCtrl:       ADR=false ADRAckReq=false ACK=false FPending=false
+  FCnt:        2
+  FPort:       1
+  FRMPayload:  95437876
+  Decrypted:   74657374
+  MIC:         2B11FF0D (valid)
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		payload, err := parsePHYPayload(args[0])
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not parse PHYPayload")
+		}
+
+		var keys pb_lorawan.DecodeKeys
+		if in, _ := cmd.Flags().GetString("nwk-s-key"); in != "" {
+			if keys.NwkSKey, err = types.ParseNwkSKey(in); err != nil {
+				ctx.WithError(err).Fatal("Could not parse NwkSKey")
+			}
+		}
+		if in, _ := cmd.Flags().GetString("app-s-key"); in != "" {
+			if keys.AppSKey, err = types.ParseAppSKey(in); err != nil {
+				ctx.WithError(err).Fatal("Could not parse AppSKey")
+			}
+		}
+		if in, _ := cmd.Flags().GetString("app-key"); in != "" {
+			if keys.AppKey, err = types.ParseAppKey(in); err != nil {
+				ctx.WithError(err).Fatal("Could not parse AppKey")
+			}
+		}
+		if in, _ := cmd.Flags().GetString("dev-nonce"); in != "" {
+			devNonce, err := types.ParseHEX(in, 2)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not parse DevNonce")
+			}
+			keys.DevNonce = &types.DevNonce{devNonce[0], devNonce[1]}
+		}
+
+		decoded, err := pb_lorawan.DecodePHYPayload(payload, keys)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not decode PHYPayload")
+		}
+
+		if jsonFlag, _ := cmd.Flags().GetBool("json"); jsonFlag {
+			out, err := json.MarshalIndent(decoded, "", "  ")
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not marshal decoded PHYPayload")
+			}
+			fmt.Println(string(out))
+			return
+		}
+
+		fmt.Println()
+		fmt.Printf("  MType:       %s\n", decoded.MType)
+		fmt.Printf("  Major:       %s\n", decoded.Major)
+
+		if req := decoded.JoinRequest; req != nil {
+			fmt.Printf("  AppEUI:      %s\n", req.AppEui)
+			fmt.Printf("  DevEUI:      %s\n", req.DevEui)
+			fmt.Printf("  DevNonce:    %s\n", req.DevNonce)
+		}
+
+		if accept := decoded.JoinAccept; accept != nil {
+			if accept.Encrypted {
+				fmt.Println("  Join-accept is encrypted, use --app-key to decrypt it")
+			} else {
+				fmt.Printf("  AppNonce:    %s\n", accept.AppNonce)
+				fmt.Printf("  NetID:       %s\n", accept.NetID)
+				fmt.Printf("  DevAddr:     %s\n", accept.DevAddr)
+				fmt.Printf("  DLSettings:  RX1DROffset=%d RX2DataRate=%d\n", accept.RX1DROffset, accept.RX2DataRate)
+				fmt.Printf("  RXDelay:     %d\n", accept.RXDelay)
+				if len(accept.CFList) > 0 {
+					fmt.Printf("  CFList:      %v\n", accept.CFList)
+				}
+				if accept.AppSKey != nil {
+					fmt.Printf("  AppSKey:     %s\n", accept.AppSKey)
+					fmt.Printf("  NwkSKey:     %s\n", accept.NwkSKey)
+				}
+			}
+		}
+
+		if data := decoded.Data; data != nil {
+			fmt.Printf("  DevAddr:     %s\n", data.DevAddr)
+			fmt.Printf("  FCtrl:       ADR=%t ADRAckReq=%t ACK=%t FPending=%t\n", data.ADR, data.ADRAckReq, data.ACK, data.FPending)
+			fmt.Printf("  FCnt:        %d\n", data.FCnt)
+			if len(data.FOpts) > 0 {
+				fmt.Printf("  FOpts:       %s\n", formatMACCommands(data.FOpts))
+			}
+			if data.FPort != nil {
+				fmt.Printf("  FPort:       %d\n", *data.FPort)
+				fmt.Printf("  FRMPayload:  %X\n", data.FRMPayload)
+			}
+			if data.Decrypted != nil {
+				fmt.Printf("  Decrypted:   %X\n", data.Decrypted)
+			}
+			if len(data.MACCommands) > 0 {
+				fmt.Printf("  MACCommands: %s\n", formatMACCommands(data.MACCommands))
+			}
+		}
+
+		mic := "not verified"
+		if decoded.MICValid != nil {
+			mic = "invalid"
+			if *decoded.MICValid {
+				mic = "valid"
+			}
+		}
+		if decoded.JoinAccept == nil || !decoded.JoinAccept.Encrypted {
+			fmt.Printf("  MIC:         %s (%s)\n", decoded.MIC, mic)
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(decodeCmd)
+	decodeCmd.Flags().String("nwk-s-key", "", "NwkSKey to verify the MIC of data messages and decrypt MAC commands on FPort 0")
+	decodeCmd.Flags().String("app-s-key", "", "AppSKey to decrypt the payload of data messages")
+	decodeCmd.Flags().String("app-key", "", "AppKey to verify the MIC of join messages and decrypt join-accepts")
+	decodeCmd.Flags().String("dev-nonce", "", "DevNonce of the join-request, to derive the session keys from a join-accept")
+	decodeCmd.Flags().Bool("json", false, "Print the decoded message as JSON")
+}
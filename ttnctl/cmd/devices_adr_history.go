@@ -0,0 +1,151 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/scope"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+type adrHistory struct {
+	Band     string `json:"band"`
+	Margin   int    `json:"margin"`
+	DataRate string `json:"data_rate"`
+	TxPower  int    `json:"tx_power"`
+	NbTrans  int    `json:"nb_trans"`
+	SendReq  bool   `json:"send_req"`
+	Failed   int    `json:"failed"`
+
+	Rejection        string `json:"rejection"`
+	RejectedDataRate string `json:"rejected_data_rate"`
+	RejectedTxPower  int    `json:"rejected_tx_power"`
+
+	Decisions []*device.ADRDecision `json:"decisions"`
+}
+
+var devicesADRHistoryCmd = &cobra.Command{
+	Use:   "adr-history [Device ID]",
+	Short: "Show the ADR decisions for a device",
+	Long: `ttnctl devices adr-history shows the last ADR decisions of the NetworkServer for a device.
+For each LinkADRReq that was sent, it shows the inputs that were used and why the settings were changed.`,
+	Example: `$ ttnctl devices adr-history test
+  INFO Using Application                        AppEUI=70B3D57EF0000024 AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Connecting with Handler...               Handler=eu.thethings.network:1904
+  INFO Discovering NetworkServer...             NetworkServer=ttn-networkserver-eu
+
+      Band: EU_863_870
+    Margin: 15 dB
+  Settings: SF7BW125 at 14 dBm, 1 transmission(s)
+
+TIME                     	FRAMES	MAX SNR	LOSS	FROM                	TO                  	REASON
+2017-03-20T10:12:33+01:00	20    	12.5   	0%  	SF12BW125 14dBm 1x	SF7BW125 14dBm 1x	max SNR of 12.5 dB in the last 20 frames with a margin of 15 dB allows SF7BW125 at 14 dBm
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		devID := args[0]
+		if !api.ValidID(devID) {
+			ctx.Fatalf("Invalid Device ID") // TODO: Add link to wiki explaining device IDs
+		}
+
+		appID := util.GetAppID(ctx)
+
+		conn, manager := util.GetHandlerManager(ctx, appID)
+		defer conn.Close()
+
+		dev, err := manager.GetDevice(appID, devID)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get existing device.")
+		}
+		lorawan := dev.GetLorawanDevice()
+		if lorawan == nil || lorawan.AppEui == nil || lorawan.DevEui == nil {
+			ctx.Fatal("Device is not a LoRaWAN device")
+		}
+
+		apiAddress := util.GetNetworkServerAPIAddress(ctx)
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/devices/%s/%s/adr-history", strings.TrimSuffix(apiAddress, "/"), lorawan.AppEui, lorawan.DevEui), nil)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not build request")
+		}
+		req.Header.Set("Authorization", "Bearer "+util.TokenForScope(ctx, scope.App(appID)))
+		req.Header.Set("User-Agent", util.GetUserAgent())
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get ADR history")
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(res.Body)
+			ctx.WithField("Status", res.Status).Fatalf("Could not get ADR history: %s", strings.TrimSpace(string(body)))
+		}
+
+		var history adrHistory
+		if err := json.NewDecoder(res.Body).Decode(&history); err != nil {
+			ctx.WithError(err).Fatal("Could not decode ADR history")
+		}
+
+		fmt.Println()
+		if history.Band == "" {
+			fmt.Println("  ADR is not enabled for this device, or the NetworkServer did not receive ADR uplinks yet")
+			fmt.Println()
+			return
+		}
+		fmt.Printf("      Band: %s\n", history.Band)
+		fmt.Printf("    Margin: %d dB\n", history.Margin)
+		fmt.Printf("  Settings: %s at %d dBm, %d transmission(s)\n", history.DataRate, history.TxPower, history.NbTrans)
+		if history.Failed > 0 {
+			fmt.Printf("    Failed: %d LinkADRReq(s) were rejected by the device\n", history.Failed)
+		}
+		if history.Rejection != "" {
+			fmt.Printf("  Rejected: %s of %s at %d dBm\n", history.Rejection, history.RejectedDataRate, history.RejectedTxPower)
+		}
+		fmt.Println()
+
+		if len(history.Decisions) == 0 {
+			switch {
+			case !history.SendReq:
+				fmt.Println("  No LinkADRReq was sent yet, the device did not request one")
+			default:
+				fmt.Printf("  No LinkADRReq was sent yet, the NetworkServer needs %d uplink frames to decide\n", device.FramesHistorySize)
+			}
+			fmt.Println()
+			return
+		}
+
+		table := uitable.New()
+		table.MaxColWidth = 100
+		table.AddRow("TIME", "FRAMES", "MAX SNR", "LOSS", "FROM", "TO", "REASON")
+		for _, decision := range history.Decisions {
+			table.AddRow(
+				decision.Time.Format(time.RFC3339),
+				decision.Frames,
+				fmt.Sprintf("%.1f", decision.MaxSNR),
+				fmt.Sprintf("%d%%", decision.LossPercentage),
+				fmt.Sprintf("%s %ddBm %dx", decision.PreviousDataRate, decision.PreviousTxPower, decision.PreviousNbTrans),
+				fmt.Sprintf("%s %ddBm %dx", decision.DataRate, decision.TxPower, decision.NbTrans),
+				decision.Reason,
+			)
+		}
+		fmt.Println(table)
+		fmt.Println()
+	},
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesADRHistoryCmd)
+}
@@ -0,0 +1,187 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb "github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+)
+
+var devicesBulkCmd = &cobra.Command{
+	Use:   "bulk [set|delete|requeue] [Device ID ...]",
+	Short: "Update, delete or requeue the downlinks of multiple devices",
+	Long: `ttnctl devices bulk applies an action to the devices that match the Device IDs and the filter flags.
+A Device ID that ends with a * matches all devices that start with the part before the *.
+
+set:     updates the fields of the set flags; if a device can not be updated, no device is changed
+delete:  deletes the devices
+requeue: puts the downlinks that were sent but not acknowledged back at the front of the queue`,
+	Example: `$ ttnctl devices bulk set "sensor-*" --rx2-data-rate SF9BW125 --disable-adr
+  INFO Using Application                        AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Connecting with Handler...               Handler=eu.thethings.network:1904
+  INFO Updated devices                          AppID=test Devices=2
+
+  sensor-1
+  sensor-2
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			assertArgsLength(cmd, args, 1, 1)
+		}
+
+		appID := util.GetAppID(ctx)
+
+		in := &pb.BulkDevicesRequest{AppId: appID, Action: args[0], DevIds: args[1:]}
+		in.Description, _ = cmd.Flags().GetString("filter-description")
+		in.ActivationConstraints, _ = cmd.Flags().GetString("filter-activation-constraints")
+		in.Query, _ = cmd.Flags().GetString("filter-query")
+		in.Tags, _ = cmd.Flags().GetStringSlice("filter-tags")
+		in.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		filter := handler.DeviceFilter{DevIDs: in.DevIds, Description: in.Description, ActivationConstraints: in.ActivationConstraints, Query: in.Query, Tags: in.Tags}
+		if filter.IsEmpty() {
+			ctx.Fatal(`Select devices with Device IDs or filter flags, use "*" to select all devices`)
+		}
+
+		if in.Action == handler.BulkSet {
+			set, err := json.Marshal(getDeviceUpdate(cmd))
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not encode update")
+			}
+			in.Set = string(set)
+		}
+
+		if in.Action == handler.BulkDelete && !in.DryRun {
+			if !confirm(fmt.Sprintf("Are you sure you want to delete the selected devices from application %s?", appID)) {
+				ctx.Info("Not doing anything")
+				return
+			}
+		}
+
+		conn, manager := util.GetHandlerManager(ctx, appID)
+		defer conn.Close()
+
+		devIDs, err := manager.BulkDevices(in)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not apply bulk action")
+		}
+
+		message := map[string]string{
+			handler.BulkSet:     "Updated devices",
+			handler.BulkDelete:  "Deleted devices",
+			handler.BulkRequeue: "Requeued downlinks of devices",
+		}[in.Action]
+		if in.DryRun {
+			message = "Selected devices"
+		}
+		ctx.WithFields(ttnlog.Fields{
+			"AppID":   appID,
+			"Devices": len(devIDs),
+		}).Info(message)
+
+		if len(devIDs) == 0 {
+			return
+		}
+		fmt.Println()
+		for _, devID := range devIDs {
+			fmt.Printf("  %s\n", devID)
+		}
+		fmt.Println()
+	},
+}
+
+// getDeviceUpdate returns the update of the set flags of the bulk command
+func getDeviceUpdate(cmd *cobra.Command) *handler.DeviceUpdate {
+	update := new(handler.DeviceUpdate)
+	flags := cmd.Flags()
+	if flags.Changed("description") {
+		description, _ := flags.GetString("description")
+		update.Description = &description
+	}
+	if flags.Changed("latitude") {
+		latitude, _ := flags.GetFloat32("latitude")
+		update.Latitude = &latitude
+	}
+	if flags.Changed("longitude") {
+		longitude, _ := flags.GetFloat32("longitude")
+		update.Longitude = &longitude
+	}
+	if flags.Changed("altitude") {
+		altitude, _ := flags.GetInt32("altitude")
+		update.Altitude = &altitude
+	}
+	if flags.Changed("activation-constraints") {
+		constraints, _ := flags.GetString("activation-constraints")
+		update.ActivationConstraints = &constraints
+	}
+	if disable, _ := flags.GetBool("disable-fcnt-check"); disable {
+		update.DisableFCntCheck = &disable
+	}
+	if enable, _ := flags.GetBool("enable-fcnt-check"); enable {
+		disable := false
+		update.DisableFCntCheck = &disable
+	}
+	if uses32Bit, _ := flags.GetBool("32-bit-fcnt"); uses32Bit {
+		update.Uses32BitFCnt = &uses32Bit
+	}
+	if uses16Bit, _ := flags.GetBool("16-bit-fcnt"); uses16Bit {
+		uses32Bit := false
+		update.Uses32BitFCnt = &uses32Bit
+	}
+	if disable, _ := flags.GetBool("disable-adr"); disable {
+		update.DisableADR = &disable
+	}
+	if enable, _ := flags.GetBool("enable-adr"); enable {
+		disable := false
+		update.DisableADR = &disable
+	}
+	if flags.Changed("rx1-delay") || flags.Changed("rx1-dr-offset") || flags.Changed("rx2-data-rate") {
+		var settings types.JoinAcceptSettings
+		rx1Delay, _ := flags.GetUint8("rx1-delay")
+		settings.RX1Delay = rx1Delay
+		rx1DROffset, _ := flags.GetUint8("rx1-dr-offset")
+		settings.RX1DROffset = rx1DROffset
+		settings.RX2DataRate, _ = flags.GetString("rx2-data-rate")
+		if err := settings.Validate(); err != nil {
+			ctx.WithError(err).Fatal("Invalid join-accept settings")
+		}
+		update.JoinAccept = &settings
+	}
+	if update.IsEmpty() {
+		ctx.Fatal("Nothing to set, use the set flags to select the fields to update")
+	}
+	return update
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesBulkCmd)
+
+	devicesBulkCmd.Flags().String("filter-description", "", "Only select devices with this text in their description")
+	devicesBulkCmd.Flags().String("filter-activation-constraints", "", "Only select devices with these activation constraints")
+	devicesBulkCmd.Flags().String("filter-query", "", "Only select devices with these words in their ID, DevEUI, name, description or tags")
+	devicesBulkCmd.Flags().StringSlice("filter-tags", []string{}, "Only select devices with all these tags")
+	devicesBulkCmd.Flags().Bool("dry-run", false, "Only show the selected devices")
+
+	devicesBulkCmd.Flags().String("description", "", "Set description")
+	devicesBulkCmd.Flags().Float32("latitude", 0, "Set latitude")
+	devicesBulkCmd.Flags().Float32("longitude", 0, "Set longitude")
+	devicesBulkCmd.Flags().Int32("altitude", 0, "Set altitude")
+	devicesBulkCmd.Flags().String("activation-constraints", "", "Set activation constraints")
+	devicesBulkCmd.Flags().Bool("disable-fcnt-check", false, "Disable FCnt check")
+	devicesBulkCmd.Flags().Bool("enable-fcnt-check", false, "Enable FCnt check")
+	devicesBulkCmd.Flags().Bool("32-bit-fcnt", false, "Use 32 bit FCnt")
+	devicesBulkCmd.Flags().Bool("16-bit-fcnt", false, "Use 16 bit FCnt")
+	devicesBulkCmd.Flags().Bool("disable-adr", false, "Do not send ADR commands")
+	devicesBulkCmd.Flags().Bool("enable-adr", false, "Send ADR commands")
+	devicesBulkCmd.Flags().Uint8("rx1-delay", 0, "Set the RX1 delay in seconds of the next join-accepts")
+	devicesBulkCmd.Flags().Uint8("rx1-dr-offset", 0, "Set the RX1 data rate offset of the next join-accepts")
+	devicesBulkCmd.Flags().String("rx2-data-rate", "", "Set the RX2 data rate of the next join-accepts (for example SF9BW125)")
+}
@@ -0,0 +1,180 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/scope"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/api/handler"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+)
+
+type frameHistory struct {
+	Depth    int    `json:"depth,omitempty"`
+	TTL      string `json:"ttl,omitempty"`
+	MaxDepth int    `json:"max_depth,omitempty"`
+	MaxTTL   string `json:"max_ttl,omitempty"`
+}
+
+var devicesFrameHistoryCmd = &cobra.Command{
+	Use:   "frame-history [Device ID]",
+	Short: "Show or set the frame history settings of a device",
+	Long: `ttnctl devices frame-history shows or sets the depth and retention of the frame history of a device.
+The NetworkServer uses the frame history for ADR. Devices that send many uplinks can use a deeper
+history for meaningful loss statistics, and frames older than the TTL are no longer used.
+The depth and TTL are bounded by the limits of the NetworkServer, and devices without settings of
+their own use those of the application (see ttnctl applications frame-history).
+With --all, the settings are set for all devices of the application.`,
+	Example: `$ ttnctl devices frame-history test --depth 100 --ttl 24h
+  INFO Using Application                        AppEUI=70B3D57EF0000024 AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Connecting with Handler...               Handler=eu.thethings.network:1904
+  INFO Discovering NetworkServer...             NetworkServer=ttn-networkserver-eu
+  INFO Set frame history settings               AppID=test DevID=test
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		if all {
+			assertArgsLength(cmd, args, 0, 0)
+		} else {
+			assertArgsLength(cmd, args, 1, 1)
+		}
+
+		depth, _ := cmd.Flags().GetInt("depth")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		set := cmd.Flags().Changed("depth") || cmd.Flags().Changed("ttl")
+		if all && !set {
+			ctx.Fatal("The depth or TTL is required to set the frame history of all devices")
+		}
+
+		appID := util.GetAppID(ctx)
+
+		conn, manager := util.GetHandlerManager(ctx, appID)
+		defer conn.Close()
+
+		var devices []*handler.Device
+		if all {
+			list, err := manager.GetDevicesForApplication(appID, 0, 0)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not get devices.")
+			}
+			devices = list
+		} else {
+			devID := args[0]
+			if !api.ValidID(devID) {
+				ctx.Fatalf("Invalid Device ID") // TODO: Add link to wiki explaining device IDs
+			}
+			dev, err := manager.GetDevice(appID, devID)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not get existing device.")
+			}
+			devices = []*handler.Device{dev}
+		}
+
+		request := func(dev *handler.Device, method string, body io.Reader) (*http.Response, error) {
+			lorawan := dev.GetLorawanDevice()
+			if lorawan == nil || lorawan.AppEui == nil || lorawan.DevEui == nil {
+				return nil, fmt.Errorf("%s is not a LoRaWAN device", dev.DevId)
+			}
+			return requestNetworkServer(appID, fmt.Sprintf("/devices/%s/%s/frame-history", lorawan.AppEui, lorawan.DevEui), method, body)
+		}
+
+		if set {
+			settings := frameHistory{Depth: depth}
+			if ttl > 0 {
+				settings.TTL = ttl.String()
+			}
+			data, _ := json.Marshal(settings)
+			var failed int
+			for _, dev := range devices {
+				res, err := request(dev, "PUT", bytes.NewReader(data))
+				if err != nil {
+					ctx.WithError(err).WithField("DevID", dev.DevId).Warn("Could not set frame history settings")
+					failed++
+					continue
+				}
+				res.Body.Close()
+				ctx.WithField("AppID", appID).WithField("DevID", dev.DevId).Info("Set frame history settings")
+			}
+			if failed > 0 {
+				ctx.Fatalf("Could not set frame history settings of %d devices", failed)
+			}
+			return
+		}
+
+		res, err := request(devices[0], "GET", nil)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get frame history settings")
+		}
+		printFrameHistory(res)
+	},
+}
+
+// requestNetworkServer sends a request to the HTTP API of the NetworkServer,
+// authenticated with a token for the application
+func requestNetworkServer(appID, path, method string, body io.Reader) (*http.Response, error) {
+	apiAddress := strings.TrimSuffix(util.GetNetworkServerAPIAddress(ctx), "/")
+	req, err := http.NewRequest(method, apiAddress+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+util.TokenForScope(ctx, scope.App(appID)))
+	req.Header.Set("User-Agent", util.GetUserAgent())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		return nil, fmt.Errorf("%s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+	return res, nil
+}
+
+// printFrameHistory prints the frame history settings in the response
+func printFrameHistory(res *http.Response) {
+	defer res.Body.Close()
+
+	var settings frameHistory
+	if err := json.NewDecoder(res.Body).Decode(&settings); err != nil {
+		ctx.WithError(err).Fatal("Could not decode frame history settings")
+	}
+
+	fmt.Println()
+	fmt.Printf("  Depth: %d frames", settings.Depth)
+	if settings.MaxDepth != 0 {
+		fmt.Printf(" (max %d)", settings.MaxDepth)
+	}
+	fmt.Println()
+	if settings.TTL != "" {
+		fmt.Printf("    TTL: %s", settings.TTL)
+	} else {
+		fmt.Print("    TTL: none")
+	}
+	if settings.MaxTTL != "" {
+		fmt.Printf(" (max %s)", settings.MaxTTL)
+	}
+	fmt.Println()
+	fmt.Println()
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesFrameHistoryCmd)
+	devicesFrameHistoryCmd.Flags().Int("depth", 0, "Number of frames in the frame history (default of the NetworkServer if 0)")
+	devicesFrameHistoryCmd.Flags().Duration("ttl", 0, "Time after which frames are removed from the frame history (no expiry if 0)")
+	devicesFrameHistoryCmd.Flags().Bool("all", false, "Set the frame history settings of all devices of the application")
+}
@@ -105,6 +105,9 @@ var devicesInfoCmd = &cobra.Command{
 				options = append(options, "16BitFCnt")
 			}
 			fmt.Printf("    Options: %s\n", strings.Join(options, ", "))
+			if len(lorawan.GatewayGroups) > 0 {
+				fmt.Printf("     Groups: %s\n", strings.Join(lorawan.GatewayGroups, ", "))
+			}
 		}
 
 	},
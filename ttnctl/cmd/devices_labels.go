@@ -0,0 +1,111 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var devicesLabelsCmd = &cobra.Command{
+	Use:   "labels [Device ID]",
+	Short: "Show or set the name, description and tags of a device",
+	Long: `ttnctl devices labels shows the name, description and tags of a device.
+Use the flags to change them. Devices can be found by their labels with ttnctl devices search.`,
+	Example: `$ ttnctl devices labels test --name "Meeting room 2" --add-tags building-a,floor-2
+  INFO Using Application                        AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Set labels                               AppID=test Description= DevID=test Name=Meeting room 2 Tags=building-a,floor-2
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		devID := args[0]
+		if !api.ValidID(devID) {
+			ctx.Fatalf("Invalid Device ID") // TODO: Add link to wiki explaining device IDs
+		}
+
+		appID := util.GetAppID(ctx)
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/devices/%s/labels", strings.TrimSuffix(apiAddress, "/"), appID, devID)
+
+		res := util.HandlerAPIRequest(ctx, "GET", url, appID, nil)
+		var labels handler.DeviceLabelsRequest
+		err := json.NewDecoder(res.Body).Decode(&labels)
+		res.Body.Close()
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not decode labels")
+		}
+
+		flags := cmd.Flags()
+		changed := flags.Changed("name") || flags.Changed("description") || flags.Changed("tags") || flags.Changed("add-tags") || flags.Changed("remove-tags")
+		if changed {
+			if flags.Changed("name") {
+				labels.Name, _ = flags.GetString("name")
+			}
+			if flags.Changed("description") {
+				labels.Description, _ = flags.GetString("description")
+			}
+			if flags.Changed("tags") {
+				labels.Tags, _ = flags.GetStringSlice("tags")
+			}
+			add, _ := flags.GetStringSlice("add-tags")
+			labels.Tags = append(labels.Tags, add...)
+			remove, _ := flags.GetStringSlice("remove-tags")
+			removed := make(map[string]bool, len(remove))
+			for _, tag := range remove {
+				removed[tag] = true
+			}
+			tags := make([]string, 0, len(labels.Tags))
+			for _, tag := range labels.Tags {
+				if !removed[tag] {
+					tags = append(tags, tag)
+				}
+			}
+			labels.Tags = tags
+
+			request, err := json.Marshal(labels)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not encode labels")
+			}
+			res = util.HandlerAPIRequest(ctx, "PUT", url, appID, bytes.NewReader(request))
+			err = json.NewDecoder(res.Body).Decode(&labels)
+			res.Body.Close()
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not decode labels")
+			}
+		}
+
+		message := "Device labels"
+		if changed {
+			message = "Set labels"
+		}
+		ctx.WithFields(ttnlog.Fields{
+			"AppID":       appID,
+			"DevID":       devID,
+			"Name":        labels.Name,
+			"Description": labels.Description,
+			"Tags":        strings.Join(labels.Tags, ","),
+		}).Info(message)
+	},
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesLabelsCmd)
+	devicesLabelsCmd.Flags().String("name", "", "Set the name")
+	devicesLabelsCmd.Flags().String("description", "", "Set the description")
+	devicesLabelsCmd.Flags().StringSlice("tags", []string{}, "Replace the tags")
+	devicesLabelsCmd.Flags().StringSlice("add-tags", []string{}, "Add tags")
+	devicesLabelsCmd.Flags().StringSlice("remove-tags", []string{}, "Remove tags")
+}
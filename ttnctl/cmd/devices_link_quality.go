@@ -0,0 +1,169 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/scope"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+type linkQuality struct {
+	Since    time.Time     `json:"since"`
+	Interval time.Duration `json:"interval"`
+	Score    struct {
+		Frames  uint64     `json:"frames"`
+		Missed  uint64     `json:"missed"`
+		Loss    [3]float64 `json:"loss"`
+		Score   int        `json:"score"`
+		Quality string     `json:"quality"`
+	} `json:"score"`
+	Points []struct {
+		Time      time.Time      `json:"time"`
+		Frames    int            `json:"frames"`
+		MinSNR    float32        `json:"min_snr"`
+		AvgSNR    float32        `json:"avg_snr"`
+		MaxSNR    float32        `json:"max_snr"`
+		MinRSSI   float32        `json:"min_rssi"`
+		AvgRSSI   float32        `json:"avg_rssi"`
+		MaxRSSI   float32        `json:"max_rssi"`
+		AvgMargin *float32       `json:"avg_margin"`
+		DataRates map[string]int `json:"data_rates"`
+		Missed    int            `json:"missed"`
+		Loss      int            `json:"loss_percentage"`
+	} `json:"points"`
+}
+
+var devicesLinkQualityCmd = &cobra.Command{
+	Use:   "link-quality [Device ID]",
+	Short: "Show the link quality trend of a device",
+	Long: `ttnctl devices link-quality shows how the SNR, RSSI and data rate of the uplinks of a device evolved.
+The NetworkServer aggregates the frame history of the device, which contains the signal quality at the gateway that
+received each uplink with ADR best, into one row per interval. Use ttnctl devices frame-history to keep more frames.
+The margin is the average SNR above the demodulation floor of the data rate, the loss is the percentage of uplinks that
+was missed according to the frame counters. The score (0-100) combines the frame loss over the last 100 uplinks with the margin.`,
+	Example: `$ ttnctl devices link-quality test --interval 6h --since 24h
+  INFO Using Application                        AppEUI=70B3D57EF0000024 AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Connecting with Handler...               Handler=eu.thethings.network:1904
+  INFO Discovering NetworkServer...             NetworkServer=ttn-networkserver-eu
+
+  Link score: 84 (good), 2% of the last 100 uplinks missed, 3% of the last 1000 uplinks missed
+
+TIME                     	FRAMES	LOSS	SNR (MIN/AVG/MAX)	RSSI (MIN/AVG/MAX)	MARGIN	DATA RATES
+2017-06-01T00:00:00+02:00	36    	0%  	4.0/7.2/9.5      	-98/-91/-85       	14.7  	SF7BW125 (36)
+2017-06-01T06:00:00+02:00	36    	5%  	-4.5/1.3/5.0     	-112/-104/-96     	12.1  	SF7BW125 (12), SF9BW125 (24)
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		devID := args[0]
+		if !api.ValidID(devID) {
+			ctx.Fatalf("Invalid Device ID") // TODO: Add link to wiki explaining device IDs
+		}
+
+		appID := util.GetAppID(ctx)
+
+		conn, manager := util.GetHandlerManager(ctx, appID)
+		defer conn.Close()
+
+		dev, err := manager.GetDevice(appID, devID)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get existing device.")
+		}
+		lorawan := dev.GetLorawanDevice()
+		if lorawan == nil || lorawan.AppEui == nil || lorawan.DevEui == nil {
+			ctx.Fatal("Device is not a LoRaWAN device")
+		}
+
+		query := url.Values{}
+		if interval, _ := cmd.Flags().GetDuration("interval"); interval != 0 {
+			query.Set("interval", interval.String())
+		}
+		if since, _ := cmd.Flags().GetDuration("since"); since != 0 {
+			query.Set("since", since.String())
+		}
+
+		apiAddress := util.GetNetworkServerAPIAddress(ctx)
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/devices/%s/%s/link-quality?%s", strings.TrimSuffix(apiAddress, "/"), lorawan.AppEui, lorawan.DevEui, query.Encode()), nil)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not build request")
+		}
+		req.Header.Set("Authorization", "Bearer "+util.TokenForScope(ctx, scope.App(appID)))
+		req.Header.Set("User-Agent", util.GetUserAgent())
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get link quality")
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(res.Body)
+			ctx.WithField("Status", res.Status).Fatalf("Could not get link quality: %s", strings.TrimSpace(string(body)))
+		}
+
+		var quality linkQuality
+		if err := json.NewDecoder(res.Body).Decode(&quality); err != nil {
+			ctx.WithError(err).Fatal("Could not decode link quality")
+		}
+
+		fmt.Println()
+		if quality.Score.Frames > 0 {
+			fmt.Printf("  Link score: %d (%s), %.0f%% of the last 100 uplinks missed, %.0f%% of the last 1000 uplinks missed\n", quality.Score.Score, quality.Score.Quality, quality.Score.Loss[1]*100, quality.Score.Loss[2]*100)
+			fmt.Println()
+		}
+		if len(quality.Points) == 0 {
+			fmt.Printf("  The frame history of this device has no uplinks since %s\n", quality.Since.Format(time.RFC3339))
+			fmt.Println()
+			return
+		}
+
+		table := uitable.New()
+		table.MaxColWidth = 100
+		table.AddRow("TIME", "FRAMES", "LOSS", "SNR (MIN/AVG/MAX)", "RSSI (MIN/AVG/MAX)", "MARGIN", "DATA RATES")
+		for _, point := range quality.Points {
+			margin := "-"
+			if point.AvgMargin != nil {
+				margin = fmt.Sprintf("%.1f", *point.AvgMargin)
+			}
+			dataRates := make([]string, 0, len(point.DataRates))
+			for dataRate := range point.DataRates {
+				dataRates = append(dataRates, dataRate)
+			}
+			sort.Strings(dataRates)
+			for i, dataRate := range dataRates {
+				dataRates[i] = fmt.Sprintf("%s (%d)", dataRate, point.DataRates[dataRate])
+			}
+			table.AddRow(
+				point.Time.Local().Format(time.RFC3339),
+				point.Frames,
+				fmt.Sprintf("%d%%", point.Loss),
+				fmt.Sprintf("%.1f/%.1f/%.1f", point.MinSNR, point.AvgSNR, point.MaxSNR),
+				fmt.Sprintf("%.0f/%.0f/%.0f", point.MinRSSI, point.AvgRSSI, point.MaxRSSI),
+				margin,
+				strings.Join(dataRates, ", "),
+			)
+		}
+		fmt.Println(table)
+		fmt.Println()
+	},
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesLinkQualityCmd)
+	devicesLinkQualityCmd.Flags().Duration("interval", time.Hour, "Duration of each row")
+	devicesLinkQualityCmd.Flags().Duration("since", 24*time.Hour, "Show the trend since this duration ago")
+}
@@ -77,7 +77,7 @@ var devicesPersonalizeCmd = &cobra.Command{
 		}
 		constraints = append(constraints, "abp")
 
-		devAddr, err := manager.GetDevAddr(constraints...)
+		devAddr, err := manager.GetDevAddrForApp(appID, constraints...)
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not request device address")
 		}
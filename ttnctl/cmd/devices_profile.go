@@ -0,0 +1,79 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var devicesProfileCmd = &cobra.Command{
+	Use:   "profile [Device ID] [Profile ID]",
+	Short: "Show or set the device profile of a device",
+	Long: `ttnctl devices profile shows the device profile of a device.
+If a profile ID is supplied, the device uses that profile of the application. The ADR limits of
+the profile are also set in the NetworkServer. Use --remove to remove the profile from the device.`,
+	Example: `$ ttnctl devices profile test sensor
+  INFO Using Application                        AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Set profile                              AppID=test DevID=test ProfileID=sensor
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 2)
+
+		devID := args[0]
+		if !api.ValidID(devID) {
+			ctx.Fatalf("Invalid Device ID") // TODO: Add link to wiki explaining device IDs
+		}
+
+		appID := util.GetAppID(ctx)
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		url := fmt.Sprintf("%s/applications/%s/devices/%s/profile", strings.TrimSuffix(apiAddress, "/"), appID, devID)
+
+		method := "GET"
+		var body io.Reader
+		remove, _ := cmd.Flags().GetBool("remove")
+		if len(args) == 2 || remove {
+			request := handler.DeviceProfileRequest{AppID: appID, DevID: devID}
+			if len(args) == 2 {
+				request.ProfileID = args[1]
+			}
+			encoded, _ := json.Marshal(request)
+			method = "PUT"
+			body = bytes.NewReader(encoded)
+		}
+
+		res := util.HandlerAPIRequest(ctx, method, url, appID, body)
+		defer res.Body.Close()
+		var response handler.DeviceProfileRequest
+		if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+			ctx.WithError(err).Fatal("Could not decode profile")
+		}
+
+		if method == "PUT" {
+			ctx.WithField("AppID", appID).WithField("DevID", devID).WithField("ProfileID", response.ProfileID).Info("Set profile")
+			return
+		}
+		if response.ProfileID == "" {
+			ctx.WithField("AppID", appID).WithField("DevID", devID).Info("Device has no profile")
+			return
+		}
+		ctx.WithField("AppID", appID).WithField("DevID", devID).WithField("ProfileID", response.ProfileID).Info("Device profile")
+	},
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesProfileCmd)
+	devicesProfileCmd.Flags().Bool("remove", false, "Remove the profile from the device")
+}
@@ -0,0 +1,113 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/scope"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+)
+
+type quarantine struct {
+	Quarantined bool       `json:"quarantined"`
+	Reason      string     `json:"reason,omitempty"`
+	Since       *time.Time `json:"since,omitempty"`
+}
+
+var devicesQuarantineCmd = &cobra.Command{
+	Use:   "quarantine [Device ID]",
+	Short: "Show or clear the quarantine of a device",
+	Long: `ttnctl devices quarantine shows whether a device is quarantined.
+The NetworkServer quarantines a device when the Broker receives a replay of one of its frames
+far away from the gateways that received the original frame. A quarantined device does not get
+downlinks until the quarantine is cleared.`,
+	Example: `$ ttnctl devices quarantine test --clear
+  INFO Using Application                        AppEUI=70B3D57EF0000024 AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Connecting with Handler...               Handler=eu.thethings.network:1904
+  INFO Discovering NetworkServer...             NetworkServer=ttn-networkserver-eu
+  INFO Cleared quarantine                       AppID=test DevID=test
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		devID := args[0]
+		if !api.ValidID(devID) {
+			ctx.Fatalf("Invalid Device ID") // TODO: Add link to wiki explaining device IDs
+		}
+
+		appID := util.GetAppID(ctx)
+
+		conn, manager := util.GetHandlerManager(ctx, appID)
+		defer conn.Close()
+
+		dev, err := manager.GetDevice(appID, devID)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get existing device.")
+		}
+		lorawan := dev.GetLorawanDevice()
+		if lorawan == nil || lorawan.AppEui == nil || lorawan.DevEui == nil {
+			ctx.Fatal("Device is not a LoRaWAN device")
+		}
+
+		method := "GET"
+		if clear, _ := cmd.Flags().GetBool("clear"); clear {
+			method = "DELETE"
+		}
+
+		apiAddress := util.GetNetworkServerAPIAddress(ctx)
+
+		req, err := http.NewRequest(method, fmt.Sprintf("%s/devices/%s/%s/quarantine", strings.TrimSuffix(apiAddress, "/"), lorawan.AppEui, lorawan.DevEui), nil)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not build request")
+		}
+		req.Header.Set("Authorization", "Bearer "+util.TokenForScope(ctx, scope.App(appID)))
+		req.Header.Set("User-Agent", util.GetUserAgent())
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not reach NetworkServer")
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+			body, _ := ioutil.ReadAll(res.Body)
+			ctx.WithField("Status", res.Status).Fatalf("Could not handle quarantine: %s", strings.TrimSpace(string(body)))
+		}
+
+		if method == "DELETE" {
+			ctx.WithField("AppID", appID).WithField("DevID", devID).Info("Cleared quarantine")
+			return
+		}
+
+		var status quarantine
+		if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+			ctx.WithError(err).Fatal("Could not decode quarantine")
+		}
+
+		fmt.Println()
+		if !status.Quarantined {
+			fmt.Println("  The device is not quarantined")
+			fmt.Println()
+			return
+		}
+		fmt.Printf("  Quarantined: %s\n", status.Reason)
+		if status.Since != nil {
+			fmt.Printf("        Since: %s\n", status.Since.UTC().Format("2006-01-02 15:04:05"))
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesQuarantineCmd)
+	devicesQuarantineCmd.Flags().Bool("clear", false, "Clear the quarantine, so that the device gets downlinks again")
+}
@@ -86,7 +86,11 @@ var devicesRegisterCmd = &cobra.Command{
 		conn, manager := util.GetHandlerManager(ctx, appID)
 		defer conn.Close()
 
-		err = manager.SetDevice(device)
+		if override, _ := cmd.Flags().GetBool("override-dev-eui-check"); override {
+			err = manager.SetDeviceOverridingDevEUICheck(device)
+		} else {
+			err = manager.SetDevice(device)
+		}
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not register Device")
 		}
@@ -103,4 +107,5 @@ var devicesRegisterCmd = &cobra.Command{
 
 func init() {
 	devicesCmd.AddCommand(devicesRegisterCmd)
+	devicesRegisterCmd.Flags().Bool("override-dev-eui-check", false, "Register the device even if its DevEUI is not allowed by the Handler (requires admin rights)")
 }
@@ -0,0 +1,83 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var devicesSearchCmd = &cobra.Command{
+	Use:   "search [words ...]",
+	Short: "Search the devices of an application by their labels",
+	Long: `ttnctl devices search lists the devices that have all words in their ID, DevEUI,
+name, description or tags (ignoring case) and that have all tags of the --tags flag.`,
+	Example: `$ ttnctl devices search meeting --tags building-a
+  INFO Using Application                        AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+
+DevID	DevEUI          	Name          	Tags
+test 	0001D544B2936FCE	Meeting room 2	building-a,floor-2
+
+  INFO Found 1 devices                          AppID=test Total=1
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		appID := util.GetAppID(ctx)
+
+		query := url.Values{}
+		if len(args) > 0 {
+			query.Set("q", strings.Join(args, " "))
+		}
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		for _, tag := range tags {
+			query.Add("tag", tag)
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		query.Set("limit", strconv.Itoa(limit))
+		offset, _ := cmd.Flags().GetInt("offset")
+		query.Set("offset", strconv.Itoa(offset))
+
+		apiAddress := util.GetHandlerAPIAddress(ctx, viper.GetString("handler-id"))
+		res := util.HandlerAPIRequest(ctx, "GET", fmt.Sprintf("%s/applications/%s/devices/search?%s", strings.TrimSuffix(apiAddress, "/"), appID, query.Encode()), appID, nil)
+		defer res.Body.Close()
+		var response handler.DeviceSearchResponse
+		if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+			ctx.WithError(err).Fatal("Could not decode devices")
+		}
+
+		if len(response.Devices) > 0 {
+			table := uitable.New()
+			table.MaxColWidth = 70
+			table.AddRow("DevID", "DevEUI", "Name", "Tags", "Description")
+			for _, dev := range response.Devices {
+				table.AddRow(dev.DevID, dev.DevEUI, crop(dev.Name, 30), strings.Join(dev.Tags, ","), crop(dev.Description, 20))
+			}
+			fmt.Println()
+			fmt.Println(table)
+			fmt.Println()
+		}
+
+		ctx.WithFields(ttnlog.Fields{
+			"AppID": appID,
+			"Total": response.Total,
+		}).Infof("Found %d devices", len(response.Devices))
+	},
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesSearchCmd)
+	devicesSearchCmd.Flags().StringSlice("tags", []string{}, "Only list devices with all these tags")
+	devicesSearchCmd.Flags().Int("limit", 100, "The maximum number of devices")
+	devicesSearchCmd.Flags().Int("offset", 0, "The number of devices to skip")
+}
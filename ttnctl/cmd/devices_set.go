@@ -152,7 +152,15 @@ var devicesSetCmd = &cobra.Command{
 			dev.Description = in
 		}
 
-		err = manager.SetDevice(dev)
+		if cmd.Flags().Changed("gateway-groups") {
+			dev.GetLorawanDevice().GatewayGroups, _ = cmd.Flags().GetStringSlice("gateway-groups")
+		}
+
+		if override, _ := cmd.Flags().GetBool("override-dev-eui-check"); override {
+			err = manager.SetDeviceOverridingDevEUICheck(dev)
+		} else {
+			err = manager.SetDevice(dev)
+		}
 		if err != nil {
 			ctx.WithError(err).Fatal("Could not update Device")
 		}
@@ -168,6 +176,7 @@ func init() {
 	devicesCmd.AddCommand(devicesSetCmd)
 
 	devicesSetCmd.Flags().Bool("override", false, "Override protection against breaking changes")
+	devicesSetCmd.Flags().Bool("override-dev-eui-check", false, "Set the DevEUI even if it is not allowed by the Handler (requires admin rights)")
 
 	devicesSetCmd.Flags().String("app-eui", "", "Set AppEUI")
 	devicesSetCmd.Flags().String("dev-eui", "", "Set DevEUI")
@@ -189,4 +198,6 @@ func init() {
 	devicesSetCmd.Flags().Int32("altitude", 0, "Set altitude")
 
 	devicesSetCmd.Flags().String("description", "", "Set Description")
+
+	devicesSetCmd.Flags().StringSlice("gateway-groups", []string{}, "Set the gateway groups that the device prefers for downlink, most preferred first")
 }
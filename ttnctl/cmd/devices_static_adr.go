@@ -0,0 +1,156 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TheThingsNetwork/go-account-lib/scope"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+)
+
+type staticADR struct {
+	DataRate string `json:"data_rate"`
+	TxPower  int    `json:"tx_power,omitempty"`
+	Channels []int  `json:"channels,omitempty"`
+	SendReq  bool   `json:"send_req,omitempty"`
+}
+
+var devicesStaticADRCmd = &cobra.Command{
+	Use:   "static-adr [Device ID]",
+	Short: "Send static ADR settings to a device",
+	Long: `ttnctl devices static-adr sends a data rate, TX power and channel mask to a device that does not use ADR.
+The NetworkServer sends the settings once, in a LinkADRReq on the next downlink to the device.
+Without flags, it shows the settings that were configured for the device.`,
+	Example: `$ ttnctl devices static-adr test --data-rate SF9BW125 --tx-power 14 --channels 0,1,2
+  INFO Using Application                        AppEUI=70B3D57EF0000024 AppID=test
+  INFO Discovering Handler...                   Handler=ttn-handler-eu
+  INFO Connecting with Handler...               Handler=eu.thethings.network:1904
+  INFO Discovering NetworkServer...             NetworkServer=ttn-networkserver-eu
+  INFO Scheduled static ADR settings            AppID=test DevID=test
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		devID := args[0]
+		if !api.ValidID(devID) {
+			ctx.Fatalf("Invalid Device ID") // TODO: Add link to wiki explaining device IDs
+		}
+
+		appID := util.GetAppID(ctx)
+
+		conn, manager := util.GetHandlerManager(ctx, appID)
+		defer conn.Close()
+
+		dev, err := manager.GetDevice(appID, devID)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not get existing device.")
+		}
+		lorawan := dev.GetLorawanDevice()
+		if lorawan == nil || lorawan.AppEui == nil || lorawan.DevEui == nil {
+			ctx.Fatal("Device is not a LoRaWAN device")
+		}
+
+		dataRate, _ := cmd.Flags().GetString("data-rate")
+		txPower, _ := cmd.Flags().GetInt("tx-power")
+		channelStrings, _ := cmd.Flags().GetStringSlice("channels")
+		cancel, _ := cmd.Flags().GetBool("clear")
+
+		method, body := "GET", io.Reader(nil)
+		switch {
+		case cancel:
+			method = "DELETE"
+		case dataRate != "":
+			settings := staticADR{DataRate: dataRate, TxPower: txPower}
+			for _, str := range channelStrings {
+				channel, err := strconv.Atoi(strings.TrimSpace(str))
+				if err != nil {
+					ctx.WithError(err).Fatalf("Invalid channel %s", str)
+				}
+				settings.Channels = append(settings.Channels, channel)
+			}
+			data, _ := json.Marshal(settings)
+			method, body = "PUT", bytes.NewReader(data)
+		case txPower != 0 || len(channelStrings) != 0:
+			ctx.Fatal("The data rate is required")
+		}
+
+		apiAddress := util.GetNetworkServerAPIAddress(ctx)
+
+		req, err := http.NewRequest(method, fmt.Sprintf("%s/devices/%s/%s/static-adr", strings.TrimSuffix(apiAddress, "/"), lorawan.AppEui, lorawan.DevEui), body)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not build request")
+		}
+		req.Header.Set("Authorization", "Bearer "+util.TokenForScope(ctx, scope.App(appID)))
+		req.Header.Set("User-Agent", util.GetUserAgent())
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			ctx.WithError(err).Fatal("Could not reach NetworkServer")
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+			body, _ := ioutil.ReadAll(res.Body)
+			ctx.WithField("Status", res.Status).Fatalf("Could not handle static ADR settings: %s", strings.TrimSpace(string(body)))
+		}
+
+		switch method {
+		case "DELETE":
+			ctx.WithField("AppID", appID).WithField("DevID", devID).Info("Cancelled static ADR settings")
+			return
+		case "PUT":
+			ctx.WithField("AppID", appID).WithField("DevID", devID).Info("Scheduled static ADR settings")
+			return
+		}
+
+		var settings staticADR
+		if err := json.NewDecoder(res.Body).Decode(&settings); err != nil {
+			ctx.WithError(err).Fatal("Could not decode static ADR settings")
+		}
+
+		fmt.Println()
+		if settings.DataRate == "" {
+			fmt.Println("  No static ADR settings were configured for this device")
+			fmt.Println()
+			return
+		}
+		fmt.Printf("  Data Rate: %s\n", settings.DataRate)
+		if settings.TxPower != 0 {
+			fmt.Printf("   TX Power: %d dBm\n", settings.TxPower)
+		} else {
+			fmt.Println("   TX Power: default")
+		}
+		if len(settings.Channels) != 0 {
+			fmt.Printf("   Channels: %s\n", strings.Trim(fmt.Sprint(settings.Channels), "[]"))
+		} else {
+			fmt.Println("   Channels: all")
+		}
+		if settings.SendReq {
+			fmt.Println("     Status: waiting for the next downlink")
+		} else {
+			fmt.Println("     Status: sent")
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	devicesCmd.AddCommand(devicesStaticADRCmd)
+	devicesStaticADRCmd.Flags().String("data-rate", "", "Data rate for the device, such as SF9BW125")
+	devicesStaticADRCmd.Flags().Int("tx-power", 0, "TX power for the device in dBm (default power of the band if not set)")
+	devicesStaticADRCmd.Flags().StringSlice("channels", []string{}, "Uplink channels that the device can use (all channels of the band if not set)")
+	devicesStaticADRCmd.Flags().Bool("clear", false, "Cancel static ADR settings that were not sent yet")
+}
@@ -0,0 +1,285 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/account"
+	"github.com/TheThingsNetwork/go-account-lib/claims"
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/api/health"
+	"github.com/TheThingsNetwork/ttn/mqtt"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context" // See https://github.com/grpc/grpc-go/issues/711"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// doctorFinding is the result of a check of ttnctl doctor. Failed checks
+// have a hint that tells the user how to fix them.
+type doctorFinding struct {
+	check   string
+	ok      bool
+	skipped bool
+	message string
+	hint    string
+}
+
+type doctor struct {
+	findings []doctorFinding
+	ctx      context.Context
+	loggedIn bool
+}
+
+func (d *doctor) ok(check, message string) {
+	d.findings = append(d.findings, doctorFinding{check: check, ok: true, message: message})
+}
+
+func (d *doctor) skip(check, message string) {
+	d.findings = append(d.findings, doctorFinding{check: check, ok: true, skipped: true, message: message})
+}
+
+func (d *doctor) fail(check, message, hint string) {
+	d.findings = append(d.findings, doctorFinding{check: check, message: message, hint: hint})
+}
+
+func (d *doctor) failures() (failures []doctorFinding) {
+	for _, finding := range d.findings {
+		if !finding.ok {
+			failures = append(failures, finding)
+		}
+	}
+	return
+}
+
+func (d *doctor) checkConfig() {
+	if _, err := os.Stat(cfgFile); err == nil {
+		d.ok("config", fmt.Sprintf("Using config file %s", cfgFile))
+	} else {
+		d.ok("config", "No config file found, using the default settings")
+	}
+	if appID := util.GetSelectedAppID(); appID != "" {
+		d.ok("application", fmt.Sprintf("Selected application %s", appID))
+	} else {
+		d.skip("application", "No application selected")
+	}
+}
+
+func (d *doctor) checkAccount() {
+	token, err := util.GetStoredToken()
+	if err != nil {
+		d.fail("account", err.Error(), "Login with ttnctl user login [access code]")
+		return
+	}
+	token, err = util.GetTokenSource(ctx).Token()
+	if err != nil {
+		d.fail("account", fmt.Sprintf("Could not refresh the access token: %s", err), "Login again with ttnctl user login [access code]")
+		return
+	}
+	profile, err := account.New(viper.GetString("auth-server"), token.AccessToken).WithHeader("User-Agent", util.GetUserAgent()).Profile()
+	if err != nil {
+		d.fail("account", fmt.Sprintf("Account server %s rejected the access token: %s", viper.GetString("auth-server"), err), "Check the auth-server setting, or login again with ttnctl user login [access code]")
+		return
+	}
+	message := fmt.Sprintf("Logged in as %s", profile.Username)
+	if claims, err := claims.FromTokenWithoutValidation(token.AccessToken); err == nil && claims.ExpiresAt != 0 {
+		message += fmt.Sprintf(", access token valid until %s", time.Unix(claims.ExpiresAt, 0).Format(time.Stamp))
+	}
+	d.ok("account", message)
+	d.ctx = util.GetContext(ctx)
+	d.loggedIn = true
+}
+
+func (d *doctor) checkHealth(check string, conn *grpc.ClientConn) {
+	if healthy, err := health.Check(conn); err != nil {
+		d.fail(check, fmt.Sprintf("Health check failed: %s", errors.FromGRPCError(err)), "The component may be down or restarting, try again later or contact its operator")
+		return
+	} else if !healthy {
+		d.fail(check, "Component reports that it is unhealthy", "Contact the operator of the component")
+		return
+	}
+	d.ok(check, "Component is healthy")
+
+	healthy, err := health.CheckService(conn, "redis")
+	switch {
+	case grpc.Code(err) == codes.NotFound || grpc.Code(err) == codes.Unimplemented:
+		// The component does not use Redis
+	case err != nil:
+		d.fail(check+" redis", fmt.Sprintf("Redis health check failed: %s", errors.FromGRPCError(err)), "Contact the operator of the component")
+	case !healthy:
+		d.fail(check+" redis", "Component can not reach its Redis database", "Check the Redis server of the component and its redis-address setting")
+	default:
+		d.ok(check+" redis", "Redis database is healthy")
+	}
+}
+
+func (d *doctor) checkDiscovery() (client discovery.DiscoveryClient, conn *grpc.ClientConn) {
+	address := viper.GetString("discovery-address")
+	caCert := path.Join(util.GetDataDir(), "/ca.cert")
+	if cert, err := ioutil.ReadFile(caCert); err == nil && !api.RootCAs.AppendCertsFromPEM(cert) {
+		d.fail("discovery", fmt.Sprintf("Could not add root certificates from %s", caCert), "Replace the file with the PEM-encoded CA certificate of the network")
+	}
+	conn, err := api.Dial(address)
+	if err != nil {
+		d.fail("discovery", fmt.Sprintf("Could not connect to Discovery server %s: %s", address, err), "Check the discovery-address setting and your internet connection")
+		return nil, nil
+	}
+	d.ok("discovery", fmt.Sprintf("Connected to Discovery server %s", address))
+	d.checkHealth("discovery", conn)
+	return discovery.NewDiscoveryClient(conn), conn
+}
+
+func (d *doctor) checkAnnouncement(client discovery.DiscoveryClient, serviceName, id string) {
+	check := fmt.Sprintf("%s %s", serviceName, id)
+	announcement, err := client.Get(d.ctx, &discovery.GetRequest{ServiceName: serviceName, Id: id})
+	if err != nil {
+		d.fail(check, fmt.Sprintf("Not found in the Discovery server: %s", errors.FromGRPCError(err)), fmt.Sprintf("Check the %s-id setting", serviceName))
+		return
+	}
+	d.ok(check, fmt.Sprintf("Announced at %s", announcement.NetAddress))
+	d.checkComponent(check, announcement)
+}
+
+func (d *doctor) checkBrokers(client discovery.DiscoveryClient) {
+	res, err := client.GetAll(d.ctx, &discovery.GetServiceRequest{ServiceName: "broker"})
+	if err != nil {
+		d.fail("brokers", fmt.Sprintf("Could not get brokers from the Discovery server: %s", errors.FromGRPCError(err)), "Try again later")
+		return
+	}
+	if len(res.Services) == 0 {
+		d.fail("brokers", "No brokers are announced", "Start a broker, or check the discovery-address setting")
+		return
+	}
+	d.ok("brokers", fmt.Sprintf("Found %d %s", len(res.Services), plural(len(res.Services), "broker")))
+	for _, announcement := range res.Services {
+		d.checkComponent(fmt.Sprintf("broker %s", announcement.Id), announcement)
+	}
+}
+
+func (d *doctor) checkComponent(check string, announcement *discovery.Announcement) {
+	if announcement.NetAddress == "" {
+		return
+	}
+	conn, err := announcement.Dial()
+	if err != nil {
+		d.fail(check, fmt.Sprintf("Could not connect to %s: %s", announcement.NetAddress, err), "The component may be down, or a firewall may block the connection")
+		return
+	}
+	defer conn.Close()
+	d.checkHealth(check, conn)
+}
+
+func (d *doctor) checkMQTT() {
+	if viper.GetString("mqtt-username") == "" && !strings.HasPrefix(viper.GetString("mqtt-address"), "localhost") {
+		// The credentials are the access keys of the selected application
+		if util.GetSelectedAppID() == "" || !d.loggedIn {
+			d.skip("mqtt", "Login and select an application, or set mqtt-username to check MQTT")
+			return
+		}
+	}
+	retries := mqtt.ConnectRetries
+	mqtt.ConnectRetries = 1
+	defer func() { mqtt.ConnectRetries = retries }()
+	client, err := util.ConnectMQTT(ctx)
+	if err != nil {
+		d.fail("mqtt", err.Error(), "Check the mqtt-address, mqtt-username and mqtt-password settings, and the access keys of the application")
+		return
+	}
+	client.Disconnect()
+	d.ok("mqtt", fmt.Sprintf("Connected to %s", viper.GetString("mqtt-address")))
+}
+
+func (d *doctor) print() {
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+	table.AddRow("", "Check", "Result")
+	for _, finding := range d.findings {
+		status := "OK"
+		switch {
+		case finding.skipped:
+			status = "SKIP"
+		case !finding.ok:
+			status = "FAIL"
+		}
+		table.AddRow(status, finding.check, finding.message)
+	}
+	fmt.Println()
+	fmt.Println(table)
+	fmt.Println()
+
+	failures := d.failures()
+	if len(failures) == 0 {
+		ctx.Info("No problems found")
+		return
+	}
+	ctx.Warnf("Found %d %s:", len(failures), plural(len(failures), "problem"))
+	fmt.Println()
+	for _, failure := range failures {
+		fmt.Printf("  - %s: %s\n", failure.check, failure.hint)
+	}
+	fmt.Println()
+	os.Exit(1)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the connectivity and configuration of ttnctl",
+	Long: `ttnctl doctor checks the connectivity and configuration of ttnctl end to
+end: the Discovery server, the access token, the announcements and health of
+the configured Router, Handler, NetworkServer and the Brokers, the Redis
+databases of these components and the MQTT broker.`,
+	Example: `$ ttnctl doctor
+  INFO Running checks...
+
+      	Check                         	Result
+  OK  	config                        	No config file found, using the default settings
+  OK  	application                   	Selected application test
+  OK  	account                       	Logged in as yourname, access token valid until Sep 20 09:04:12
+  OK  	discovery                     	Connected to Discovery server discover.thethingsnetwork.org:1900
+  OK  	discovery                     	Component is healthy
+  OK  	router ttn-router-eu          	Announced at eu.thethings.network:1901
+  OK  	router ttn-router-eu          	Component is healthy
+  OK  	handler ttn-handler-eu        	Announced at eu.thethings.network:1904
+  FAIL	handler ttn-handler-eu        	Health check failed: context deadline exceeded
+  ...
+
+  WARN Found 1 problem:
+
+  - handler ttn-handler-eu: The component may be down or restarting, try again later or contact its operator
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 0, 0)
+
+		ctx.Info("Running checks...")
+
+		d := &doctor{ctx: context.Background()}
+		d.checkConfig()
+		d.checkAccount()
+		if client, conn := d.checkDiscovery(); client != nil {
+			defer conn.Close()
+			d.checkAnnouncement(client, "router", viper.GetString("router-id"))
+			d.checkBrokers(client)
+			d.checkAnnouncement(client, "networkserver", viper.GetString("networkserver-id"))
+			d.checkAnnouncement(client, "handler", viper.GetString("handler-id"))
+		}
+		d.checkMQTT()
+		d.print()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(doctorCmd)
+}
@@ -56,11 +56,29 @@ $ ttnctl downlink test --json '{"led":"on"}'
 			ctx.WithError(err).Fatal("Failed to read confirmed flag")
 		}
 
+		rxWindow, err := cmd.Flags().GetString("rx-window")
+		if err != nil {
+			ctx.WithError(err).Fatal("Failed to read rx-window flag")
+		}
+
+		gatewayID, err := cmd.Flags().GetString("gateway-id")
+		if err != nil {
+			ctx.WithError(err).Fatal("Failed to read gateway-id flag")
+		}
+
+		priority, err := cmd.Flags().GetString("priority")
+		if err != nil {
+			ctx.WithError(err).Fatal("Failed to read priority flag")
+		}
+
 		message := types.DownlinkMessage{
 			AppID:     appID,
 			DevID:     devID,
 			FPort:     uint8(fPort),
 			Confirmed: confirmed,
+			RXWindow:  types.RXWindow(rxWindow),
+			GatewayID: gatewayID,
+			Priority:  types.DownlinkPriority(priority),
 		}
 
 		if args[1] == "" {
@@ -104,4 +122,7 @@ func init() {
 	downlinkCmd.Flags().Int("fport", 1, "FPort for downlink")
 	downlinkCmd.Flags().Bool("confirmed", false, "Confirmed downlink")
 	downlinkCmd.Flags().Bool("json", false, "Provide the payload as JSON")
+	downlinkCmd.Flags().String("rx-window", "", "Only send the downlink in this RX window (rx1 or rx2)")
+	downlinkCmd.Flags().String("gateway-id", "", "Only send the downlink through this gateway")
+	downlinkCmd.Flags().String("priority", "", "Priority of the downlink (low, normal or high)")
 }
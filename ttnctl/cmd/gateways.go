@@ -6,6 +6,7 @@ package cmd
 import (
 	"github.com/TheThingsNetwork/ttn/ttnctl/util"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var gatewaysCmd = &cobra.Command{
@@ -15,10 +16,17 @@ var gatewaysCmd = &cobra.Command{
 	Long:    `ttnctl gateways can be used to manage gateways.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		RootCmd.PersistentPreRun(cmd, args)
-		util.GetAccount(ctx)
+		if !util.UsesGatewayRegistry() {
+			util.GetAccount(ctx)
+		}
 	},
 }
 
 func init() {
 	RootCmd.AddCommand(gatewaysCmd)
+
+	gatewaysCmd.PersistentFlags().String("registry-address", "", "The address of the debug server of a Router, to manage its local gateway registry instead of the account server")
+	viper.BindPFlag("gateway-registry-address", gatewaysCmd.PersistentFlags().Lookup("registry-address"))
+	gatewaysCmd.PersistentFlags().String("registry-token", "", "The admin token of the debug server of the Router")
+	viper.BindPFlag("gateway-registry-token", gatewaysCmd.PersistentFlags().Lookup("registry-token"))
 }
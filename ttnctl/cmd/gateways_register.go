@@ -4,8 +4,13 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
 	"github.com/TheThingsNetwork/go-account-lib/account"
 	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/router/registry"
 	"github.com/TheThingsNetwork/ttn/ttnctl/util"
 	"github.com/spf13/cobra"
 )
@@ -13,9 +18,18 @@ import (
 var gatewaysRegisterCmd = &cobra.Command{
 	Use:   "register [GatewayID] [FrequencyPlan] [Location]",
 	Short: "Register a gateway",
-	Long:  `ttnctl gateways register can be used to register a gateway`,
+	Long: `ttnctl gateways register can be used to register a gateway.
+With --registry-address, the gateway is registered in the local gateway registry of a Router,
+and the access key that the gateway uses as its token is printed.`,
 	Example: `$ ttnctl gateways register test US 52.37403,4.88968
   INFO Registered gateway                          Gateway ID=test
+
+$ ttnctl gateways register test EU_863_870 --registry-address http://localhost:6061 --registry-token admin
+  INFO Registered gateway                          Gateway ID=test
+
+ttn-gw-key.4f2b1ce0d1a3e5c7b9f0a2c4e6d8b0a1c3e5f7a9b1d3e5f7
+
+  INFO Configure the gateway with this access key as its token, it can not be retrieved later
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		assertArgsLength(cmd, args, 2, 3)
@@ -36,6 +50,25 @@ var gatewaysRegisterCmd = &cobra.Command{
 			}
 		}
 
+		if util.UsesGatewayRegistry() {
+			in := registry.GatewayRequest{FrequencyPlan: frequencyPlan}
+			if location != nil {
+				in.Location = &registry.Location{Latitude: location.Latitude, Longitude: location.Longitude}
+			}
+			body, err := json.Marshal(in)
+			if err != nil {
+				ctx.WithError(err).Fatal("Could not encode gateway")
+			}
+			gateway := util.GatewayRegistryRequest(ctx, "PUT", gatewayID, bytes.NewReader(body))
+			if gateway.AccessKey == "" {
+				ctx.WithField("Gateway ID", gateway.ID).Info("Updated gateway")
+				return
+			}
+			ctx.WithField("Gateway ID", gateway.ID).Info("Registered gateway")
+			printAccessKey(gateway)
+			return
+		}
+
 		settings := account.GatewaySettings{
 			Location: location,
 		}
@@ -52,6 +85,14 @@ var gatewaysRegisterCmd = &cobra.Command{
 	},
 }
 
+// printAccessKey prints the access key of a gateway in the local gateway registry
+func printAccessKey(gateway *registry.GatewayResponse) {
+	fmt.Println()
+	fmt.Println(gateway.AccessKey)
+	fmt.Println()
+	ctx.Info("Configure the gateway with this access key as its token, it can not be retrieved later")
+}
+
 func init() {
 	gatewaysCmd.AddCommand(gatewaysRegisterCmd)
 }
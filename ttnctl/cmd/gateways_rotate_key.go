@@ -0,0 +1,58 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/ttnctl/util"
+	"github.com/spf13/cobra"
+)
+
+var gatewaysRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key [GatewayID]",
+	Short: "Rotate the access key of a gateway",
+	Long: `ttnctl gateways rotate-key replaces the access key of a gateway in the local gateway registry of a Router.
+With --grace, the previous access key remains valid for that duration, or until the gateway
+connects with the new access key.`,
+	Example: `$ ttnctl gateways rotate-key test --grace 24h --registry-address http://localhost:6061 --registry-token admin
+  INFO Rotated access key                       Gateway ID=test
+
+ttn-gw-key.9d7c5e3a1b0f2d4c6e8a0b2d4f6a8c0e2b4d6f8a0c2e4b6d
+
+  INFO Configure the gateway with this access key as its token, it can not be retrieved later
+  INFO The previous access key remains valid     Until=2017-06-02 10:42:11 +0200 CEST
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertArgsLength(cmd, args, 1, 1)
+
+		gatewayID := args[0]
+		if !api.ValidID(gatewayID) {
+			ctx.Fatal("Invalid Gateway ID")
+		}
+		if !util.UsesGatewayRegistry() {
+			ctx.Fatal("Access keys can only be rotated in a local gateway registry, use --registry-address")
+		}
+
+		query := url.Values{}
+		if grace, _ := cmd.Flags().GetDuration("grace"); grace > 0 {
+			query.Set("grace", grace.String())
+		}
+
+		gateway := util.GatewayRegistryRequest(ctx, "POST", fmt.Sprintf("%s/rotate-key?%s", gatewayID, query.Encode()), nil)
+
+		ctx.WithField("Gateway ID", gateway.ID).Info("Rotated access key")
+		printAccessKey(gateway)
+		if gateway.PreviousKeyExpires != nil {
+			ctx.WithField("Until", *gateway.PreviousKeyExpires).Info("The previous access key remains valid")
+		}
+	},
+}
+
+func init() {
+	gatewaysCmd.AddCommand(gatewaysRotateKeyCmd)
+	gatewaysRotateKeyCmd.Flags().Duration("grace", 0, "The duration that the previous access key remains valid")
+}
@@ -76,6 +76,9 @@ func init() {
 	RootCmd.PersistentFlags().String("handler-id", "ttn-handler-eu", "The ID of the TTN Handler as announced in the Discovery server")
 	viper.BindPFlag("handler-id", RootCmd.PersistentFlags().Lookup("handler-id"))
 
+	RootCmd.PersistentFlags().String("networkserver-id", "ttn-networkserver-eu", "The ID of the TTN NetworkServer as announced in the Discovery server")
+	viper.BindPFlag("networkserver-id", RootCmd.PersistentFlags().Lookup("networkserver-id"))
+
 	RootCmd.PersistentFlags().String("mqtt-address", "eu.thethings.network:1883", "The address of the MQTT broker")
 	viper.BindPFlag("mqtt-address", RootCmd.PersistentFlags().Lookup("mqtt-address"))
 
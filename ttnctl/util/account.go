@@ -5,6 +5,7 @@ package util
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -74,20 +75,29 @@ func getAccountServerTokenSource(token *oauth2.Token) oauth2.TokenSource {
 	return getOAuth().TokenSource(token)
 }
 
-func getStoredToken(ctx ttnlog.Interface) *oauth2.Token {
+// GetStoredToken returns the token that is stored by ttnctl user login
+func GetStoredToken() (*oauth2.Token, error) {
 	tokenCache := GetTokenCache()
 	data, err := tokenCache.Get(tokenName())
 	if err != nil {
-		ctx.WithError(err).Fatal("Could not read stored token")
+		return nil, fmt.Errorf("Could not read stored token: %s", err)
 	}
 	if data == nil {
-		ctx.Fatal("No account information found. Please login with ttnctl user login [access code]")
+		return nil, errors.New("No account information found. Please login with ttnctl user login [access code]")
 	}
 
 	token := &oauth2.Token{}
 	err = json.Unmarshal(data, token)
 	if err != nil {
-		ctx.Fatal("Account information invalid. Please login with ttnctl user login [access code]")
+		return nil, errors.New("Account information invalid. Please login with ttnctl user login [access code]")
+	}
+	return token, nil
+}
+
+func getStoredToken(ctx ttnlog.Interface) *oauth2.Token {
+	token, err := GetStoredToken()
+	if err != nil {
+		ctx.Fatal(err.Error())
 	}
 	return token
 }
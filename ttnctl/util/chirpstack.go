@@ -0,0 +1,350 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/api"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/core/types"
+)
+
+// ChirpStackExport contains a ChirpStack application and its devices, in the
+// JSON format of the ChirpStack REST API
+type ChirpStackExport struct {
+	Application ChirpStackApplication `json:"application"`
+	Devices     []ChirpStackDevice    `json:"devices"`
+}
+
+// ChirpStackApplication is an application in ChirpStack
+type ChirpStackApplication struct {
+	ID                   string `json:"id,omitempty"`
+	Name                 string `json:"name"`
+	Description          string `json:"description,omitempty"`
+	PayloadCodec         string `json:"payloadCodec,omitempty"`
+	PayloadDecoderScript string `json:"payloadDecoderScript,omitempty"`
+	PayloadEncoderScript string `json:"payloadEncoderScript,omitempty"`
+}
+
+// ChirpStackDevice is a device in ChirpStack with its keys and activation
+type ChirpStackDevice struct {
+	Device           ChirpStackDeviceInfo        `json:"device"`
+	DeviceKeys       *ChirpStackDeviceKeys       `json:"deviceKeys,omitempty"`
+	DeviceActivation *ChirpStackDeviceActivation `json:"deviceActivation,omitempty"`
+}
+
+// ChirpStackDeviceInfo contains the settings of a device in ChirpStack
+type ChirpStackDeviceInfo struct {
+	DevEUI            string            `json:"devEUI"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description,omitempty"`
+	SkipFCntCheck     bool              `json:"skipFCntCheck,omitempty"`
+	ReferenceAltitude float64           `json:"referenceAltitude,omitempty"`
+	Variables         map[string]string `json:"variables,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+}
+
+// ChirpStackDeviceKeys contains the root keys of an OTAA device in ChirpStack.
+// For LoRaWAN 1.0 devices, the AppKey is in NwkKey.
+type ChirpStackDeviceKeys struct {
+	NwkKey string `json:"nwkKey"`
+	AppKey string `json:"appKey,omitempty"`
+}
+
+// ChirpStackDeviceActivation contains the session of a device in ChirpStack.
+// For LoRaWAN 1.0 devices, the network session keys are the same.
+type ChirpStackDeviceActivation struct {
+	DevAddr     string `json:"devAddr"`
+	AppSKey     string `json:"appSKey"`
+	NwkSEncKey  string `json:"nwkSEncKey"`
+	SNwkSIntKey string `json:"sNwkSIntKey"`
+	FNwkSIntKey string `json:"fNwkSIntKey"`
+	FCntUp      uint32 `json:"fCntUp"`
+	NFCntDown   uint32 `json:"nFCntDown"`
+	AFCntDown   uint32 `json:"aFCntDown"`
+}
+
+// chirpStackCodecMarker separates the payload function from the function that
+// calls it with the signature of the other network server
+const chirpStackCodecMarker = "\n// Converted by ttnctl, do not change below this line\n"
+
+const (
+	chirpStackDecoder = chirpStackCodecMarker + `function Decoder(bytes, port) {
+  return Decode(port, bytes, {});
+}
+`
+	chirpStackEncoder = chirpStackCodecMarker + `function Encoder(object, port) {
+  return Encode(port, object, {});
+}
+`
+	ttnDecoder = chirpStackCodecMarker + `function Decode(fPort, bytes, variables) {
+  return Decoder(bytes, fPort);
+}
+`
+	ttnEncoder = chirpStackCodecMarker + `function Encode(fPort, obj, variables) {
+  return Encoder(obj, fPort);
+}
+`
+)
+
+// convertCodec returns the payload function with a function that calls it
+// with the signature of the other network server. The function that was added
+// by an earlier conversion is removed.
+func convertCodec(script, call string) string {
+	if script == "" {
+		return ""
+	}
+	if i := strings.Index(script, chirpStackCodecMarker); i >= 0 {
+		return script[:i]
+	}
+	return strings.TrimRight(script, "\n") + "\n" + call
+}
+
+var invalidIDChars = regexp.MustCompile("[^0-9a-z]+")
+
+// chirpStackDevID converts the name of a device in ChirpStack to a Device ID,
+// or returns the DevEUI if the name can not be converted
+func chirpStackDevID(name string, devEUI types.DevEUI) string {
+	id := strings.Trim(invalidIDChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if api.ValidID(id) {
+		return id
+	}
+	return strings.ToLower(devEUI.String())
+}
+
+// FromChirpStack converts a ChirpStack application to an application export
+// that can be imported into a Handler. ChirpStack does not store the AppEUI
+// of devices, so it has to be given. It returns warnings about the settings
+// and devices that could not be converted.
+func FromChirpStack(in *ChirpStackExport, appID string, appEUI types.AppEUI) (*handler.ApplicationExport, []string, error) {
+	var warnings []string
+	export := &handler.ApplicationExport{AppID: appID}
+	switch in.Application.PayloadCodec {
+	case "", "NONE":
+	case "CUSTOM_JS":
+		export.Settings.Decoder = convertCodec(in.Application.PayloadDecoderScript, chirpStackDecoder)
+		export.Settings.Encoder = convertCodec(in.Application.PayloadEncoderScript, chirpStackEncoder)
+	default:
+		warnings = append(warnings, fmt.Sprintf("Payload codec %s is not supported", in.Application.PayloadCodec))
+	}
+
+	ids := make(map[string]bool, len(in.Devices))
+	for _, dev := range in.Devices {
+		devEUI, err := types.ParseDevEUI(dev.Device.DevEUI)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid DevEUI of device %s: %s", dev.Device.Name, err)
+		}
+		devExport := handler.DeviceExport{
+			DevID:            chirpStackDevID(dev.Device.Name, devEUI),
+			Description:      dev.Device.Description,
+			AppEUI:           appEUI,
+			DevEUI:           devEUI,
+			Altitude:         int32(dev.Device.ReferenceAltitude),
+			DisableFCntCheck: dev.Device.SkipFCntCheck,
+		}
+		if ids[devExport.DevID] {
+			devExport.DevID = strings.ToLower(devEUI.String())
+		}
+		for key, value := range dev.Device.Tags {
+			if !api.ValidID(key) {
+				warnings = append(warnings, fmt.Sprintf("Tag %s of device %s is not a valid attribute", key, devExport.DevID))
+				continue
+			}
+			if devExport.Attributes == nil {
+				devExport.Attributes = make(map[string]string)
+			}
+			devExport.Attributes[key] = value
+		}
+		if len(dev.Device.Variables) > 0 {
+			warnings = append(warnings, fmt.Sprintf("Variables of device %s are not supported", devExport.DevID))
+		}
+
+		if keys := dev.DeviceKeys; keys != nil && keys.NwkKey != "" {
+			if keys.AppKey != "" && keys.AppKey != keys.NwkKey && !isZeroKey(keys.AppKey) {
+				warnings = append(warnings, fmt.Sprintf("Device %s uses LoRaWAN 1.1 and was skipped", devExport.DevID))
+				continue
+			}
+			appKey, err := types.ParseAppKey(keys.NwkKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid keys of device %s: %s", devExport.DevID, err)
+			}
+			devExport.AppKey = &appKey
+		}
+		if activation := dev.DeviceActivation; activation != nil && activation.DevAddr != "" {
+			if activation.NwkSEncKey != activation.FNwkSIntKey || activation.SNwkSIntKey != activation.FNwkSIntKey {
+				warnings = append(warnings, fmt.Sprintf("Device %s uses LoRaWAN 1.1 and was skipped", devExport.DevID))
+				continue
+			}
+			devAddr, err := types.ParseDevAddr(activation.DevAddr)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid activation of device %s: %s", devExport.DevID, err)
+			}
+			nwkSKey, err := types.ParseNwkSKey(activation.FNwkSIntKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid activation of device %s: %s", devExport.DevID, err)
+			}
+			appSKey, err := types.ParseAppSKey(activation.AppSKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid activation of device %s: %s", devExport.DevID, err)
+			}
+			fCntUp, fCntDown := activation.FCntUp, activation.NFCntDown
+			devExport.DevAddr, devExport.NwkSKey, devExport.AppSKey = &devAddr, &nwkSKey, &appSKey
+			devExport.FCntUp, devExport.FCntDown = &fCntUp, &fCntDown
+		}
+		if devExport.AppKey == nil && devExport.NwkSKey == nil {
+			warnings = append(warnings, fmt.Sprintf("Device %s has no keys and was skipped", devExport.DevID))
+			continue
+		}
+		ids[devExport.DevID] = true
+		export.Devices = append(export.Devices, devExport)
+	}
+	return export, warnings, nil
+}
+
+func isZeroKey(key string) bool {
+	return strings.Trim(key, "0") == ""
+}
+
+// ToChirpStack converts an application export of a Handler to a ChirpStack
+// application. It returns warnings about the settings and devices that could
+// not be converted.
+func ToChirpStack(in *handler.ApplicationExport) (*ChirpStackExport, []string) {
+	var warnings []string
+	export := &ChirpStackExport{Application: ChirpStackApplication{Name: in.AppID}}
+	if in.Settings.Decoder != "" || in.Settings.Encoder != "" {
+		export.Application.PayloadCodec = "CUSTOM_JS"
+		export.Application.PayloadDecoderScript = convertCodec(in.Settings.Decoder, ttnDecoder)
+		export.Application.PayloadEncoderScript = convertCodec(in.Settings.Encoder, ttnEncoder)
+	}
+	if in.Settings.Converter != "" || in.Settings.Validator != "" || len(in.Settings.Converters) > 0 {
+		warnings = append(warnings, "Converter and validator functions are not supported")
+	}
+	if len(in.Settings.Rules) > 0 {
+		warnings = append(warnings, "Rules are not supported")
+	}
+
+	export.Devices = make([]ChirpStackDevice, 0, len(in.Devices))
+	for _, dev := range in.Devices {
+		csDev := ChirpStackDevice{
+			Device: ChirpStackDeviceInfo{
+				DevEUI:            strings.ToLower(dev.DevEUI.String()),
+				Name:              dev.DevID,
+				Description:       dev.Description,
+				SkipFCntCheck:     dev.DisableFCntCheck,
+				ReferenceAltitude: float64(dev.Altitude),
+				Tags:              dev.Attributes,
+			},
+		}
+		if dev.AppKey != nil {
+			csDev.DeviceKeys = &ChirpStackDeviceKeys{NwkKey: strings.ToLower(dev.AppKey.String())}
+		}
+		if dev.DevAddr != nil && dev.NwkSKey != nil && dev.AppSKey != nil {
+			nwkSKey := strings.ToLower(dev.NwkSKey.String())
+			csDev.DeviceActivation = &ChirpStackDeviceActivation{
+				DevAddr:     strings.ToLower(dev.DevAddr.String()),
+				AppSKey:     strings.ToLower(dev.AppSKey.String()),
+				NwkSEncKey:  nwkSKey,
+				SNwkSIntKey: nwkSKey,
+				FNwkSIntKey: nwkSKey,
+			}
+			if dev.FCntUp != nil {
+				csDev.DeviceActivation.FCntUp = *dev.FCntUp
+			}
+			if dev.FCntDown != nil {
+				csDev.DeviceActivation.NFCntDown = *dev.FCntDown
+			}
+		}
+		if csDev.DeviceKeys == nil && csDev.DeviceActivation == nil {
+			warnings = append(warnings, fmt.Sprintf("Device %s has no keys in the export", dev.DevID))
+		}
+		export.Devices = append(export.Devices, csDev)
+	}
+	return export, warnings
+}
+
+// ChirpStackClient reads applications from the REST API of ChirpStack
+type ChirpStackClient struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+// NewChirpStackClient returns a client for the REST API of ChirpStack at the
+// URL, that authenticates with the API token
+func NewChirpStackClient(url, token string) *ChirpStackClient {
+	return &ChirpStackClient{
+		URL:    strings.TrimSuffix(url, "/"),
+		Token:  token,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// get decodes the response to the request into v. It returns false if the
+// resource does not exist.
+func (c *ChirpStackClient) get(path string, v interface{}) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, c.URL+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Grpc-Metadata-Authorization", "Bearer "+c.Token)
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return false, nil
+	case res.StatusCode < 200 || res.StatusCode >= 300:
+		return false, fmt.Errorf("ChirpStack returned %s for %s", res.Status, path)
+	}
+	return true, json.NewDecoder(res.Body).Decode(v)
+}
+
+// GetApplication reads the application with the ID from ChirpStack, with its
+// devices and their keys and activations
+func (c *ChirpStackClient) GetApplication(applicationID string) (*ChirpStackExport, error) {
+	var app struct {
+		Application ChirpStackApplication `json:"application"`
+	}
+	if found, err := c.get("/api/applications/"+applicationID, &app); err != nil || !found {
+		if err == nil {
+			err = fmt.Errorf("Application %s not found", applicationID)
+		}
+		return nil, err
+	}
+	export := &ChirpStackExport{Application: app.Application}
+
+	const limit = 100
+	for offset := 0; ; offset += limit {
+		var list struct {
+			Result []ChirpStackDeviceInfo `json:"result"`
+		}
+		if _, err := c.get(fmt.Sprintf("/api/devices?applicationID=%s&limit=%d&offset=%d", applicationID, limit, offset), &list); err != nil {
+			return nil, err
+		}
+		for _, listed := range list.Result {
+			var dev ChirpStackDevice
+			if _, err := c.get("/api/devices/"+listed.DevEUI, &dev); err != nil {
+				return nil, err
+			}
+			if _, err := c.get("/api/devices/"+listed.DevEUI+"/keys", &dev); err != nil {
+				return nil, err
+			}
+			if _, err := c.get("/api/devices/"+listed.DevEUI+"/activation", &dev); err != nil {
+				return nil, err
+			}
+			export.Devices = append(export.Devices, dev)
+		}
+		if len(list.Result) < limit {
+			return export, nil
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/smartystreets/assertions"
+)
+
+const testChirpStackApplication = `{
+  "application": {
+    "id": "3",
+    "name": "sensors",
+    "payloadCodec": "CUSTOM_JS",
+    "payloadDecoderScript": "function Decode(fPort, bytes, variables) {\n  return {temperature: bytes[0]};\n}\n"
+  },
+  "devices": [
+    {
+      "device": {"devEUI": "0102030405060708", "name": "Sensor 1", "tags": {"floor": "2", "Room Number": "12"}},
+      "deviceKeys": {"nwkKey": "01020304050607080102030405060708", "appKey": "00000000000000000000000000000000"}
+    },
+    {
+      "device": {"devEUI": "0102030405060709", "name": "sensor_2", "skipFCntCheck": true, "variables": {"offset": "2"}},
+      "deviceActivation": {
+        "devAddr": "26011234",
+        "appSKey": "01020304050607080102030405060708",
+        "nwkSEncKey": "08070605040302010807060504030201",
+        "sNwkSIntKey": "08070605040302010807060504030201",
+        "fNwkSIntKey": "08070605040302010807060504030201",
+        "fCntUp": 42,
+        "nFCntDown": 7
+      }
+    },
+    {
+      "device": {"devEUI": "010203040506070a", "name": "v1.1"},
+      "deviceKeys": {"nwkKey": "01020304050607080102030405060708", "appKey": "08070605040302010807060504030201"}
+    },
+    {
+      "device": {"devEUI": "010203040506070b", "name": "no-keys"}
+    }
+  ]
+}`
+
+func TestFromChirpStack(t *testing.T) {
+	a := New(t)
+
+	var in ChirpStackExport
+	a.So(json.Unmarshal([]byte(testChirpStackApplication), &in), ShouldBeNil)
+
+	appEUI := types.AppEUI{0x70, 0xB3, 0xD5, 0x7E, 0xF0, 0, 0, 1}
+	export, warnings, err := FromChirpStack(&in, "test", appEUI)
+	a.So(err, ShouldBeNil)
+	a.So(warnings, ShouldHaveLength, 4)
+	a.So(export.AppID, ShouldEqual, "test")
+	a.So(export.Settings.Decoder, ShouldStartWith, in.Application.PayloadDecoderScript)
+	a.So(export.Settings.Decoder, ShouldContainSubstring, "function Decoder(bytes, port)")
+	a.So(export.Settings.Encoder, ShouldBeEmpty)
+
+	a.So(export.Devices, ShouldHaveLength, 2)
+	otaa, abp := export.Devices[0], export.Devices[1]
+
+	a.So(otaa.DevID, ShouldEqual, "sensor-1")
+	a.So(otaa.AppEUI, ShouldEqual, appEUI)
+	a.So(otaa.DevEUI, ShouldEqual, types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8})
+	a.So(*otaa.AppKey, ShouldEqual, types.AppKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8})
+	a.So(otaa.Attributes, ShouldResemble, map[string]string{"floor": "2"})
+	a.So(otaa.NwkSKey, ShouldBeNil)
+
+	a.So(abp.DevID, ShouldEqual, "sensor-2")
+	a.So(abp.DisableFCntCheck, ShouldBeTrue)
+	a.So(abp.AppKey, ShouldBeNil)
+	a.So(*abp.DevAddr, ShouldEqual, types.DevAddr{0x26, 0x01, 0x12, 0x34})
+	a.So(*abp.NwkSKey, ShouldEqual, types.NwkSKey{8, 7, 6, 5, 4, 3, 2, 1, 8, 7, 6, 5, 4, 3, 2, 1})
+	a.So(*abp.FCntUp, ShouldEqual, 42)
+	a.So(*abp.FCntDown, ShouldEqual, 7)
+
+	// Back to ChirpStack
+	out, warnings := ToChirpStack(export)
+	a.So(warnings, ShouldBeEmpty)
+	a.So(out.Application.Name, ShouldEqual, "test")
+	a.So(out.Application.PayloadCodec, ShouldEqual, "CUSTOM_JS")
+	a.So(out.Application.PayloadDecoderScript, ShouldEqual, in.Application.PayloadDecoderScript)
+	a.So(out.Devices, ShouldHaveLength, 2)
+	a.So(out.Devices[0].Device.DevEUI, ShouldEqual, "0102030405060708")
+	a.So(out.Devices[0].DeviceKeys.NwkKey, ShouldEqual, "01020304050607080102030405060708")
+	a.So(out.Devices[0].DeviceActivation, ShouldBeNil)
+	a.So(out.Devices[1].DeviceKeys, ShouldBeNil)
+	a.So(*out.Devices[1].DeviceActivation, ShouldResemble, *in.Devices[1].DeviceActivation)
+
+	// Payload functions of this network
+	out, _ = ToChirpStack(&handler.ApplicationExport{AppID: "test", Settings: handler.ApplicationSettings{
+		Encoder: "function Encoder(object, port) {\n  return [object.led];\n}",
+	}})
+	a.So(out.Application.PayloadDecoderScript, ShouldBeEmpty)
+	a.So(out.Application.PayloadEncoderScript, ShouldContainSubstring, "function Encode(fPort, obj, variables)")
+}
+
+func TestChirpStackClient(t *testing.T) {
+	a := New(t)
+
+	var in ChirpStackExport
+	json.Unmarshal([]byte(testChirpStackApplication), &in)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Grpc-Metadata-Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		enc := json.NewEncoder(w)
+		switch r.URL.Path {
+		case "/api/applications/3":
+			enc.Encode(map[string]interface{}{"application": in.Application})
+		case "/api/devices":
+			var result []ChirpStackDeviceInfo
+			if r.URL.Query().Get("offset") == "0" {
+				for _, dev := range in.Devices {
+					result = append(result, dev.Device)
+				}
+			}
+			enc.Encode(map[string]interface{}{"totalCount": "4", "result": result})
+		default:
+			for _, dev := range in.Devices {
+				switch r.URL.Path {
+				case "/api/devices/" + dev.Device.DevEUI:
+					enc.Encode(map[string]interface{}{"device": dev.Device})
+					return
+				case "/api/devices/" + dev.Device.DevEUI + "/keys":
+					if dev.DeviceKeys != nil {
+						enc.Encode(map[string]interface{}{"deviceKeys": dev.DeviceKeys})
+						return
+					}
+				case "/api/devices/" + dev.Device.DevEUI + "/activation":
+					if dev.DeviceActivation != nil {
+						enc.Encode(map[string]interface{}{"deviceActivation": dev.DeviceActivation})
+						return
+					}
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	out, err := NewChirpStackClient(srv.URL, "token").GetApplication("3")
+	a.So(err, ShouldBeNil)
+	a.So(out, ShouldResemble, &in)
+
+	_, err = NewChirpStackClient(srv.URL, "token").GetApplication("4")
+	a.So(err, ShouldNotBeNil)
+	_, err = NewChirpStackClient(srv.URL, "wrong").GetApplication("3")
+	a.So(err, ShouldNotBeNil)
+}
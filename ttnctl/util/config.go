@@ -168,6 +168,16 @@ func SetAppEUI(ctx ttnlog.Interface, appEUI types.AppEUI) {
 	}
 }
 
+// GetSelectedAppID returns the AppID that is set in the command options or
+// config, or an empty string if no application is selected
+func GetSelectedAppID() string {
+	if appID := viper.GetString("app-id"); appID != "" {
+		return appID
+	}
+	appID, _ := readData(appFilename)[idKey].(string)
+	return appID
+}
+
 // GetAppID returns the AppID that must be set in the command options or config
 func GetAppID(ctx ttnlog.Interface) string {
 	appID := viper.GetString("app-id")
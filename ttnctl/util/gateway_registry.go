@@ -0,0 +1,52 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/core/router/registry"
+	"github.com/spf13/viper"
+)
+
+// UsesGatewayRegistry returns whether gateways are managed in the local
+// gateway registry of a Router, instead of in the account server
+func UsesGatewayRegistry() bool {
+	return viper.GetString("gateway-registry-address") != ""
+}
+
+// GatewayRegistryRequest does a request to the gateway registry API of the
+// Router with its admin token, and returns the gateway in the response
+func GatewayRegistryRequest(ctx ttnlog.Interface, method, path string, body io.Reader) *registry.GatewayResponse {
+	url := fmt.Sprintf("%s/admin/gateways/%s", strings.TrimSuffix(viper.GetString("gateway-registry-address"), "/"), strings.TrimPrefix(path, "/"))
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		ctx.WithError(err).Fatal("Could not build request")
+	}
+	req.Header.Set("Authorization", "Bearer "+viper.GetString("gateway-registry-token"))
+	req.Header.Set("User-Agent", GetUserAgent())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		ctx.WithError(err).Fatal("Could not reach gateway registry")
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		ctx.WithField("Status", res.Status).Fatalf("Gateway registry returned an error: %s", strings.TrimSpace(string(body)))
+	}
+	var gtw registry.GatewayResponse
+	if err := json.NewDecoder(res.Body).Decode(&gtw); err != nil {
+		ctx.WithError(err).Fatal("Could not decode response of gateway registry")
+	}
+	return &gtw
+}
@@ -4,6 +4,11 @@
 package util
 
 import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
 	"github.com/TheThingsNetwork/go-account-lib/scope"
 	ttnlog "github.com/TheThingsNetwork/go-utils/log"
 	"github.com/TheThingsNetwork/ttn/api/discovery"
@@ -39,3 +44,44 @@ func GetHandlerManager(ctx ttnlog.Interface, appID string) (*grpc.ClientConn, *h
 	}
 	return hdlConn, managerClient
 }
+
+// GetHandlerAPIAddress discovers the address of the HTTP API of a Handler
+func GetHandlerAPIAddress(ctx ttnlog.Interface, handlerID string) string {
+	ctx.WithField("Handler", handlerID).Info("Discovering Handler...")
+	dscConn, client := GetDiscovery(ctx)
+	defer dscConn.Close()
+	handlerAnnouncement, err := client.Get(GetContext(ctx), &discovery.GetRequest{
+		ServiceName: "handler",
+		Id:          handlerID,
+	})
+	if err != nil {
+		ctx.WithError(errors.FromGRPCError(err)).Fatal("Could not find Handler")
+	}
+	if handlerAnnouncement.ApiAddress == "" {
+		ctx.Fatal("Handler does not announce an HTTP API")
+	}
+	return handlerAnnouncement.ApiAddress
+}
+
+// HandlerAPIRequest does a request to the HTTP API of a Handler with a token for the application
+func HandlerAPIRequest(ctx ttnlog.Interface, method, url, appID string, body io.Reader) *http.Response {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		ctx.WithError(err).Fatal("Could not build request")
+	}
+	req.Header.Set("Authorization", "Bearer "+TokenForScope(ctx, scope.App(appID)))
+	req.Header.Set("User-Agent", GetUserAgent())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		ctx.WithError(err).Fatal("Could not reach Handler")
+	}
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		ctx.WithField("Status", res.Status).WithField("AppID", appID).Fatalf("Handler returned an error: %s", strings.TrimSpace(string(body)))
+	}
+	return res
+}
@@ -16,15 +16,24 @@ import (
 
 // GetMQTT connects a new MQTT clients with the specified credentials
 func GetMQTT(ctx ttnlog.Interface) mqtt.Client {
+	client, err := ConnectMQTT(ctx)
+	if err != nil {
+		ctx.WithError(err).Fatal("Could not connect")
+	}
+	return client
+}
+
+// ConnectMQTT connects a new MQTT client with the specified credentials, and
+// returns an error if that fails
+func ConnectMQTT(ctx ttnlog.Interface) (mqtt.Client, error) {
 	username, password, err := getMQTTCredentials(ctx)
 	if err != nil {
-		ctx.WithError(err).Fatal("Failed to get MQTT credentials")
+		return nil, fmt.Errorf("Failed to get MQTT credentials: %s", err)
 	}
 
 	mqttProto := "tcp"
 	if strings.HasSuffix(viper.GetString("mqtt-address"), ":8883") {
-		mqttProto = "ssl"
-		ctx.Fatal("TLS connections are not yet supported by ttnctl")
+		return nil, errors.New("TLS connections are not yet supported by ttnctl")
 	}
 	broker := fmt.Sprintf("%s://%s", mqttProto, viper.GetString("mqtt-address"))
 	client := mqtt.NewClient(ctx, "ttnctl", username, password, broker)
@@ -35,10 +44,10 @@ func GetMQTT(ctx ttnlog.Interface) mqtt.Client {
 	}).Info("Connecting to MQTT...")
 
 	if err := client.Connect(); err != nil {
-		ctx.WithError(err).Fatal("Could not connect")
+		return nil, err
 	}
 
-	return client
+	return client, nil
 }
 
 func getMQTTCredentials(ctx ttnlog.Interface) (username string, password string, err error) {
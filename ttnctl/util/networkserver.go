@@ -0,0 +1,29 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package util
+
+import (
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	"github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/utils/errors"
+	"github.com/spf13/viper"
+)
+
+// GetNetworkServerAPIAddress discovers the address of the HTTP API of the NetworkServer
+func GetNetworkServerAPIAddress(ctx ttnlog.Interface) string {
+	ctx.WithField("NetworkServer", viper.GetString("networkserver-id")).Info("Discovering NetworkServer...")
+	dscConn, client := GetDiscovery(ctx)
+	defer dscConn.Close()
+	networkServerAnnouncement, err := client.Get(GetContext(ctx), &discovery.GetRequest{
+		ServiceName: "networkserver",
+		Id:          viper.GetString("networkserver-id"),
+	})
+	if err != nil {
+		ctx.WithError(errors.FromGRPCError(err)).Fatal("Could not find NetworkServer")
+	}
+	if networkServerAnnouncement.ApiAddress == "" {
+		ctx.Fatal("NetworkServer does not announce an HTTP API")
+	}
+	return networkServerAnnouncement.ApiAddress
+}
@@ -0,0 +1,109 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// JSONToYAML converts JSON to YAML, keeping the order of the fields
+func JSONToYAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeOrdered(decoder)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(value)
+}
+
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		var object yaml.MapSlice
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err = decoder.Token() // }
+		return object, err
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token() // ]
+		return array, err
+	}
+	if number, ok := token.(json.Number); ok {
+		if i, err := number.Int64(); err == nil {
+			return i, nil
+		}
+		return number.Float64()
+	}
+	return token, nil
+}
+
+// YAMLToJSON converts YAML to JSON
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	value, err := convertYAML(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// convertYAML converts the maps of YAML, which can have any key, to maps that can be marshaled to JSON
+func convertYAML(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("Key %v is not a string", key)
+			}
+			converted, err := convertYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			object[keyString] = converted
+		}
+		return object, nil
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, item := range value {
+			converted, err := convertYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = converted
+		}
+		return array, nil
+	}
+	return value, nil
+}
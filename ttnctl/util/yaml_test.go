@@ -0,0 +1,36 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestYAML(t *testing.T) {
+	a := New(t)
+
+	in := `{"app_id":"test","settings":{"decoder":"function Decoder(bytes) {\n  return {};\n}","join_accept":{"rx1_delay":2}},"devices":[{"dev_id":"dev","latitude":52.5}]}`
+
+	yml, err := JSONToYAML([]byte(in))
+	a.So(err, ShouldBeNil)
+	a.So(strings.Index(string(yml), "app_id"), ShouldBeLessThan, strings.Index(string(yml), "settings"))
+	a.So(strings.Index(string(yml), "settings"), ShouldBeLessThan, strings.Index(string(yml), "devices"))
+	a.So(string(yml), ShouldContainSubstring, "rx1_delay: 2")
+
+	out, err := YAMLToJSON(yml)
+	a.So(err, ShouldBeNil)
+	var expected, actual interface{}
+	json.Unmarshal([]byte(in), &expected)
+	a.So(json.Unmarshal(out, &actual), ShouldBeNil)
+	a.So(actual, ShouldResemble, expected)
+
+	_, err = YAMLToJSON([]byte("1: one"))
+	a.So(err, ShouldNotBeNil)
+	_, err = JSONToYAML([]byte(`{"app_id":`))
+	a.So(err, ShouldNotBeNil)
+}
@@ -0,0 +1,99 @@
+// +build chaos
+
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package fault
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/redis.v5"
+)
+
+var (
+	mu     sync.RWMutex
+	faults = make(map[Point]Fault)
+)
+
+// Enabled indicates whether faults can be injected in this binary
+const Enabled = true
+
+// Set sets the fault that is injected at the point. A zero Fault removes it.
+func Set(point Point, fault Fault) {
+	mu.Lock()
+	defer mu.Unlock()
+	if fault == (Fault{}) {
+		delete(faults, point)
+		return
+	}
+	faults[point] = fault
+}
+
+// Get returns the faults that are injected
+func Get() map[Point]Fault {
+	mu.RLock()
+	defer mu.RUnlock()
+	res := make(map[Point]Fault, len(faults))
+	for point, fault := range faults {
+		res[point] = fault
+	}
+	return res
+}
+
+// Inject injects the fault of the point; it delays the call and returns
+// ErrInjected if the call should fail
+func Inject(point Point) error {
+	mu.RLock()
+	fault, ok := faults[point]
+	mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	if fault.DelayMS > 0 {
+		time.Sleep(time.Duration(fault.DelayMS) * time.Millisecond)
+	}
+	if fault.FailPercent > 0 && rand.Float64()*100 < fault.FailPercent {
+		return ErrInjected
+	}
+	return nil
+}
+
+// WrapRedis injects the Redis fault in the calls of the client
+func WrapRedis(client *redis.Client) {
+	client.WrapProcess(func(process func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			if err := Inject(Redis); err != nil {
+				return err
+			}
+			return process(cmd)
+		}
+	})
+}
+
+// Handler returns the admin API that gets (GET) and sets (PUT) the injected faults
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+		case "PUT":
+			var in map[Point]Fault
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			for point, fault := range in {
+				Set(point, fault)
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Get())
+	})
+}
@@ -0,0 +1,51 @@
+// +build chaos
+
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestInject(t *testing.T) {
+	a := New(t)
+	defer Set(Redis, Fault{})
+
+	a.So(Inject(Redis), ShouldBeNil)
+
+	Set(Redis, Fault{FailPercent: 100})
+	a.So(Inject(Redis), ShouldEqual, ErrInjected)
+	a.So(Inject(HandlerDecode), ShouldBeNil)
+
+	Set(Redis, Fault{DelayMS: 20})
+	start := time.Now()
+	a.So(Inject(Redis), ShouldBeNil)
+	a.So(time.Since(start), ShouldBeGreaterThanOrEqualTo, 20*time.Millisecond)
+
+	Set(Redis, Fault{})
+	a.So(Get(), ShouldBeEmpty)
+}
+
+func TestHandler(t *testing.T) {
+	a := New(t)
+	defer Set(BrokerToNetworkServer, Fault{})
+
+	req, _ := http.NewRequest("PUT", "/debug/faults", strings.NewReader(`{"broker-networkserver":{"fail_percent":10}}`))
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, req)
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	a.So(Get()[BrokerToNetworkServer].FailPercent, ShouldEqual, 10)
+
+	req, _ = http.NewRequest("DELETE", "/debug/faults", nil)
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, req)
+	a.So(rec.Code, ShouldEqual, http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,32 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package fault injects faults (dropped messages, delays, failing calls) into
+// components, so that their resilience can be verified in staging. Faults are
+// only injected in binaries that are built with the "chaos" build tag.
+package fault
+
+import "github.com/TheThingsNetwork/ttn/utils/errors"
+
+// Point is a location in the code where faults can be injected
+type Point string
+
+const (
+	// BrokerToNetworkServer is where the Broker sends messages to the NetworkServer
+	BrokerToNetworkServer Point = "broker-networkserver"
+	// HandlerDecode is where the Handler decodes uplink payloads
+	HandlerDecode Point = "handler-decode"
+	// Redis is where components call Redis
+	Redis Point = "redis"
+)
+
+// Fault is the fault that is injected at a Point
+type Fault struct {
+	// FailPercent is the percentage of calls that fail
+	FailPercent float64 `json:"fail_percent"`
+	// DelayMS is the delay (in ms) that is added to each call
+	DelayMS int `json:"delay_ms"`
+}
+
+// ErrInjected is returned by calls that fail because of an injected fault
+var ErrInjected = errors.New("Injected fault")
@@ -0,0 +1,28 @@
+// +build !chaos
+
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package fault
+
+import (
+	"net/http"
+
+	"gopkg.in/redis.v5"
+)
+
+// Enabled indicates whether faults can be injected in this binary
+const Enabled = false
+
+// Inject does nothing, because this binary is not built with the chaos tag
+func Inject(point Point) error { return nil }
+
+// WrapRedis does nothing, because this binary is not built with the chaos tag
+func WrapRedis(client *redis.Client) {}
+
+// Handler returns an admin API that reports that fault injection is not available
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Fault injection is not available, build with -tags chaos", http.StatusNotImplemented)
+	})
+}
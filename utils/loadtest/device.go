@@ -0,0 +1,119 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package loadtest
+
+import (
+	"bufio"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/pointer"
+	"github.com/brocaar/lorawan"
+)
+
+// Device is an ABP device that sends uplink messages
+type Device struct {
+	DevAddr types.DevAddr
+	NwkSKey types.NwkSKey
+	AppSKey types.AppSKey
+	FCnt    uint32
+}
+
+// RandomDevices returns n devices with random addresses and keys. The
+// NetworkServer does not know these devices, so their uplink is only
+// handled by the Router and Broker.
+func RandomDevices(n int) []*Device {
+	devices := make([]*Device, n)
+	for i := range devices {
+		dev := new(Device)
+		rand.Read(dev.DevAddr[:])
+		rand.Read(dev.NwkSKey[:])
+		rand.Read(dev.AppSKey[:])
+		devices[i] = dev
+	}
+	return devices
+}
+
+// ReadDevices reads devices from lines with a DevAddr, NwkSKey, AppSKey and
+// optionally the last FCnt, separated by spaces or commas. Empty lines and
+// lines starting with # are skipped.
+func ReadDevices(r io.Reader) ([]*Device, error) {
+	var devices []*Device
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(parts) != 3 && len(parts) != 4 {
+			return nil, fmt.Errorf("line %d: expected DevAddr, NwkSKey, AppSKey and optionally FCnt", line)
+		}
+		dev := new(Device)
+		var err error
+		if dev.DevAddr, err = types.ParseDevAddr(parts[0]); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		if dev.NwkSKey, err = types.ParseNwkSKey(parts[1]); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		if dev.AppSKey, err = types.ParseAppSKey(parts[2]); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		if len(parts) == 4 {
+			fCnt, err := strconv.ParseUint(parts[3], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err)
+			}
+			dev.FCnt = uint32(fCnt)
+		}
+		devices = append(devices, dev)
+	}
+	return devices, scanner.Err()
+}
+
+// WriteDevices writes the devices in the format of ReadDevices, including
+// their last FCnt, so that the next load test continues with the next FCnt
+func WriteDevices(w io.Writer, devices []*Device) error {
+	if _, err := fmt.Fprintln(w, "# DevAddr NwkSKey AppSKey FCnt"); err != nil {
+		return err
+	}
+	for _, dev := range devices {
+		if _, err := fmt.Fprintf(w, "%s %s %s %d\n", dev.DevAddr, dev.NwkSKey, dev.AppSKey, dev.FCnt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uplink builds the next uplink frame of the device with the payload
+func (d *Device) uplink(fPort uint8, payload []byte) ([]byte, error) {
+	d.FCnt++
+	macPayload := &lorawan.MACPayload{
+		FHDR: lorawan.FHDR{
+			DevAddr: lorawan.DevAddr(d.DevAddr),
+			FCnt:    d.FCnt,
+		},
+		FPort:      pointer.Uint8(fPort),
+		FRMPayload: []lorawan.Payload{&lorawan.DataPayload{Bytes: payload}},
+	}
+	phyPayload := &lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: macPayload,
+	}
+	if err := phyPayload.EncryptFRMPayload(lorawan.AES128Key(d.AppSKey)); err != nil {
+		return nil, err
+	}
+	if err := phyPayload.SetMIC(lorawan.AES128Key(d.NwkSKey)); err != nil {
+		return nil, err
+	}
+	return phyPayload.MarshalBinary()
+}
@@ -0,0 +1,186 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package loadtest generates uplink traffic from simulated gateways and
+// devices against a Router, and reports the end-to-end latency and loss
+package loadtest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+)
+
+// tickInterval is the interval at which uplink messages are sent
+var tickInterval = 10 * time.Millisecond
+
+// Config is the configuration of a load test
+type Config struct {
+	// Gateways is the number of simulated gateways
+	Gateways int
+	// GatewayPrefix is the prefix of the IDs of the simulated gateways
+	GatewayPrefix string
+	// GatewayToken is the access token of the simulated gateways
+	GatewayToken string
+	// Rate is the number of uplink messages per second
+	Rate int
+	// Duration is the duration of the load test
+	Duration time.Duration
+	// Wait is the time to wait for uplink messages after the last one was sent
+	Wait time.Duration
+	// Frequency is the frequency (in Hz) of the uplink messages
+	Frequency uint64
+	// DataRate is the data rate of the uplink messages
+	DataRate string
+	// FPort is the FPort of the uplink messages
+	FPort uint8
+}
+
+// LoadTest sends uplink messages of devices through simulated gateways and
+// keeps track of the messages that are received
+type LoadTest struct {
+	ctx     ttnlog.Interface
+	config  Config
+	devices []*Device
+
+	mu      sync.Mutex
+	seq     uint64
+	pending map[uint64]time.Time
+	report  *Report
+}
+
+// New returns a new LoadTest for the devices
+func New(ctx ttnlog.Interface, config Config, devices []*Device) *LoadTest {
+	return &LoadTest{
+		ctx:     ctx,
+		config:  config,
+		devices: devices,
+		pending: make(map[uint64]time.Time),
+		report:  new(Report),
+	}
+}
+
+// payloadSize is the size of the payload of uplink messages, which contains the sequence number
+const payloadSize = 8
+
+// nextPayload registers an uplink message that is sent now and returns its sequence number and payload
+func (l *LoadTest) nextPayload(now time.Time) (uint64, []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	l.pending[l.seq] = now
+	l.report.Sent++
+	payload := make([]byte, payloadSize)
+	binary.BigEndian.PutUint64(payload, l.seq)
+	return l.seq, payload
+}
+
+func (l *LoadTest) sendFailed(seq uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.pending, seq)
+	l.report.Sent--
+	l.report.SendErrors++
+}
+
+// HandleUplink handles the decrypted payload of an uplink message that was
+// received by the application; it should be called when the message arrives
+func (l *LoadTest) HandleUplink(payload []byte) {
+	now := time.Now()
+	if len(payload) != payloadSize {
+		return
+	}
+	seq := binary.BigEndian.Uint64(payload)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sent, ok := l.pending[seq]
+	if !ok {
+		l.report.Unexpected++
+		return
+	}
+	delete(l.pending, seq)
+	l.report.Received++
+	l.report.latencies = append(l.report.latencies, now.Sub(sent))
+}
+
+func (l *LoadTest) uplink(dev *Device, gatewayID string, now time.Time) (uint64, *pb_router.UplinkMessage, error) {
+	seq, payload := l.nextPayload(now)
+	frame, err := dev.uplink(l.config.FPort, payload)
+	if err != nil {
+		l.sendFailed(seq)
+		return seq, nil, err
+	}
+	return seq, &pb_router.UplinkMessage{
+		Payload: frame,
+		GatewayMetadata: &pb_gateway.RxMetadata{
+			GatewayId: gatewayID,
+			Timestamp: uint32(now.UnixNano() / 1000),
+			Frequency: l.config.Frequency,
+			Rssi:      -25.0,
+			Snr:       5.0,
+		},
+		ProtocolMetadata: &protocol.RxMetadata{Protocol: &protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{
+			CodingRate: "4/5",
+			DataRate:   l.config.DataRate,
+			Modulation: pb_lorawan.Modulation_LORA,
+		}}},
+	}, nil
+}
+
+// Run runs the load test against the Router and returns the report
+func (l *LoadTest) Run(client pb_router.RouterClient) *Report {
+	streams := make([]pb_router.UplinkStream, l.config.Gateways)
+	gatewayIDs := make([]string, l.config.Gateways)
+	for i := range streams {
+		gatewayIDs[i] = fmt.Sprintf("%s%d", l.config.GatewayPrefix, i+1)
+		gtwClient := pb_router.NewRouterClientForGateway(client, gatewayIDs[i], l.config.GatewayToken)
+		defer gtwClient.Close()
+		streams[i] = pb_router.NewMonitoredUplinkStream(gtwClient)
+		defer streams[i].Close()
+	}
+
+	l.ctx.WithFields(ttnlog.Fields{
+		"Gateways": l.config.Gateways,
+		"Devices":  len(l.devices),
+		"Rate":     l.config.Rate,
+		"Duration": l.config.Duration,
+	}).Info("Starting load test")
+
+	start := time.Now()
+	ticker := time.NewTicker(tickInterval)
+	var sent int
+	for now := range ticker.C {
+		elapsed := now.Sub(start)
+		if elapsed > l.config.Duration {
+			break
+		}
+		for target := int(elapsed.Seconds() * float64(l.config.Rate)); sent < target; sent++ {
+			dev := l.devices[sent%len(l.devices)]
+			gtw := sent % len(streams)
+			seq, uplink, err := l.uplink(dev, gatewayIDs[gtw], now)
+			if err != nil {
+				l.ctx.WithError(err).Warn("Could not build uplink")
+				continue
+			}
+			if err := streams[gtw].Send(uplink); err != nil {
+				l.sendFailed(seq)
+			}
+		}
+	}
+	ticker.Stop()
+
+	l.ctx.WithField("Sent", sent).Info("Waiting for uplink messages")
+	time.Sleep(l.config.Wait)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.report.Duration = time.Since(start)
+	return l.report
+}
@@ -0,0 +1,80 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package loadtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/brocaar/lorawan"
+	"github.com/smartystreets/assertions"
+)
+
+func TestReadDevices(t *testing.T) {
+	a := assertions.New(t)
+
+	devices, err := ReadDevices(strings.NewReader(`
+# DevAddr NwkSKey AppSKey
+26000001 00112233445566778899AABBCCDDEEFF FFEEDDCCBBAA99887766554433221100
+26000002,00112233445566778899AABBCCDDEEFF,FFEEDDCCBBAA99887766554433221100
+`))
+	a.So(err, assertions.ShouldBeNil)
+	a.So(devices, assertions.ShouldHaveLength, 2)
+	a.So(devices[1].DevAddr, assertions.ShouldEqual, types.DevAddr{0x26, 0, 0, 0x02})
+
+	_, err = ReadDevices(strings.NewReader("26000001 00112233445566778899AABBCCDDEEFF"))
+	a.So(err, assertions.ShouldNotBeNil)
+
+	// The FCnt is written, so that the next run continues with the next FCnt
+	devices[1].FCnt = 42
+	var buf bytes.Buffer
+	a.So(WriteDevices(&buf, devices), assertions.ShouldBeNil)
+	read, err := ReadDevices(&buf)
+	a.So(err, assertions.ShouldBeNil)
+	a.So(read, assertions.ShouldResemble, devices)
+	a.So(read[1].FCnt, assertions.ShouldEqual, 42)
+}
+
+func TestDeviceUplink(t *testing.T) {
+	a := assertions.New(t)
+	dev := RandomDevices(1)[0]
+
+	frame, err := dev.uplink(1, []byte{1, 2, 3})
+	a.So(err, assertions.ShouldBeNil)
+	a.So(dev.FCnt, assertions.ShouldEqual, 1)
+
+	var phy lorawan.PHYPayload
+	a.So(phy.UnmarshalBinary(frame), assertions.ShouldBeNil)
+	ok, err := phy.ValidateMIC(lorawan.AES128Key(dev.NwkSKey))
+	a.So(err, assertions.ShouldBeNil)
+	a.So(ok, assertions.ShouldBeTrue)
+	a.So(phy.DecryptFRMPayload(lorawan.AES128Key(dev.AppSKey)), assertions.ShouldBeNil)
+	macPayload := phy.MACPayload.(*lorawan.MACPayload)
+	a.So(macPayload.FRMPayload[0].(*lorawan.DataPayload).Bytes, assertions.ShouldResemble, []byte{1, 2, 3})
+}
+
+func TestHandleUplink(t *testing.T) {
+	a := assertions.New(t)
+	l := New(GetLogger(t, "TestHandleUplink"), Config{}, RandomDevices(1))
+
+	_, first := l.nextPayload(time.Now().Add(-100 * time.Millisecond))
+	l.nextPayload(time.Now())
+	seq, _ := l.nextPayload(time.Now())
+	l.sendFailed(seq)
+
+	l.HandleUplink(first)
+	l.HandleUplink(first)
+	l.HandleUplink([]byte{1, 2, 3})
+
+	a.So(l.report.Sent, assertions.ShouldEqual, 2)
+	a.So(l.report.SendErrors, assertions.ShouldEqual, 1)
+	a.So(l.report.Received, assertions.ShouldEqual, 1)
+	a.So(l.report.Unexpected, assertions.ShouldEqual, 1)
+	a.So(l.report.Loss(), assertions.ShouldEqual, 0.5)
+	a.So(l.report.Latency(50), assertions.ShouldBeGreaterThanOrEqualTo, 100*time.Millisecond)
+}
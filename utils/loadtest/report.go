@@ -0,0 +1,66 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package loadtest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Report contains the statistics of a load test
+type Report struct {
+	Duration   time.Duration
+	Sent       int
+	SendErrors int
+	Received   int
+	Unexpected int
+
+	latencies []time.Duration
+}
+
+// Loss returns the fraction of the sent uplink messages that was not received
+func (r *Report) Loss() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Sent-r.Received) / float64(r.Sent)
+}
+
+// Latency returns the p-th percentile (0-100) of the end-to-end latency
+func (r *Report) Latency(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(r.latencies))
+	copy(sorted, r.latencies)
+	sort.Sort(byDuration(sorted))
+	i := int(p / 100 * float64(len(sorted)-1))
+	return sorted[i]
+}
+
+type byDuration []time.Duration
+
+func (d byDuration) Len() int           { return len(d) }
+func (d byDuration) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d byDuration) Less(i, j int) bool { return d[i] < d[j] }
+
+func (r *Report) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Duration:     %s\n", r.Duration/time.Millisecond*time.Millisecond)
+	fmt.Fprintf(&buf, "Sent:         %d (%.1f/s)\n", r.Sent, float64(r.Sent)/r.Duration.Seconds())
+	fmt.Fprintf(&buf, "Send errors:  %d\n", r.SendErrors)
+	fmt.Fprintf(&buf, "Received:     %d\n", r.Received)
+	fmt.Fprintf(&buf, "Unexpected:   %d\n", r.Unexpected)
+	fmt.Fprintf(&buf, "Loss:         %.2f%%\n", r.Loss()*100)
+	if len(r.latencies) > 0 {
+		fmt.Fprintf(&buf, "Latency min:  %s\n", r.Latency(0))
+		fmt.Fprintf(&buf, "Latency p50:  %s\n", r.Latency(50))
+		fmt.Fprintf(&buf, "Latency p95:  %s\n", r.Latency(95))
+		fmt.Fprintf(&buf, "Latency p99:  %s\n", r.Latency(99))
+		fmt.Fprintf(&buf, "Latency max:  %s\n", r.Latency(100))
+	}
+	return buf.String()
+}
@@ -0,0 +1,67 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+)
+
+// LogfmtHandler writes log entries as logfmt lines
+type LogfmtHandler struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewLogfmtHandler returns a handler that writes logfmt lines to w
+func NewLogfmtHandler(w io.Writer) *LogfmtHandler {
+	return &LogfmtHandler{writer: w}
+}
+
+// HandleLog implements log.Handler
+func (h *LogfmtHandler) HandleLog(e *log.Entry) error {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	line := []string{
+		"time=" + e.Timestamp.UTC().Format(time.RFC3339Nano),
+		"level=" + levelNames[e.Level],
+		"msg=" + logfmtValue(e.Message),
+	}
+	for _, name := range names {
+		line = append(line, logfmtKey(name)+"="+logfmtValue(fmt.Sprint(e.Fields[name])))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(h.writer, strings.Join(line, " "))
+	return err
+}
+
+// logfmtKey removes the characters that are not allowed in logfmt keys
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes values that contain spaces, quotes or equal signs
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\t\r\n") {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
@@ -0,0 +1,32 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apex/log"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestLogfmtHandler(t *testing.T) {
+	a := New(t)
+
+	var buf bytes.Buffer
+	h := NewLogfmtHandler(&buf)
+	err := h.HandleLog(&log.Entry{
+		Level:     log.WarnLevel,
+		Message:   "Could not forward uplink",
+		Timestamp: time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC),
+		Fields: log.Fields{
+			ComponentField: "router",
+			"GatewayID":    "gtw",
+			"error":        `timeout="5s"`,
+		},
+	})
+	a.So(err, ShouldBeNil)
+	a.So(buf.String(), ShouldEqual, `time=2017-05-01T12:00:00Z level=warn msg="Could not forward uplink" Component=router GatewayID=gtw error="timeout=\"5s\""`+"\n")
+}
@@ -0,0 +1,220 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package logging implements a log handler with per-component log levels and
+// sampling of chatty debug logs, which can be configured at runtime.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apex/log"
+)
+
+// ComponentField is the field that identifies the component of a log entry
+const ComponentField = "Component"
+
+// SamplingInterval is the interval in which sampled log entries are counted
+var SamplingInterval = time.Second
+
+var levelNames = [...]string{
+	log.DebugLevel: "debug",
+	log.InfoLevel:  "info",
+	log.WarnLevel:  "warn",
+	log.ErrorLevel: "error",
+	log.FatalLevel: "fatal",
+}
+
+// ParseLevel parses a log level
+func ParseLevel(name string) (log.Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return log.Level(level), nil
+		}
+	}
+	if strings.EqualFold(name, "warning") {
+		return log.WarnLevel, nil
+	}
+	return log.InfoLevel, fmt.Errorf("Invalid log level %s", name)
+}
+
+// Config is the configuration of a Handler
+type Config struct {
+	// Level is the minimum level of log entries
+	Level string `json:"level"`
+	// Levels overrides the level of components
+	Levels map[string]string `json:"levels,omitempty"`
+	// Sampling logs the first Sampling debug entries with the same message
+	// in each interval, and every Sampling-th entry after that. Sampling
+	// is disabled if it is 0.
+	Sampling int `json:"sampling,omitempty"`
+}
+
+// ParseLevels parses component log levels in the format component=level
+func ParseLevels(values []string) (map[string]string, error) {
+	levels := make(map[string]string, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%s is not in the format component=level", value)
+		}
+		if _, err := ParseLevel(parts[1]); err != nil {
+			return nil, err
+		}
+		levels[parts[0]] = parts[1]
+	}
+	return levels, nil
+}
+
+type config struct {
+	level    log.Level
+	levels   map[string]log.Level
+	sampling int
+}
+
+func (c Config) parse() (*config, error) {
+	parsed := &config{level: log.InfoLevel, levels: make(map[string]log.Level, len(c.Levels)), sampling: c.Sampling}
+	var err error
+	if c.Level != "" {
+		if parsed.level, err = ParseLevel(c.Level); err != nil {
+			return nil, err
+		}
+	}
+	for component, level := range c.Levels {
+		if parsed.levels[component], err = ParseLevel(level); err != nil {
+			return nil, err
+		}
+	}
+	if c.Sampling < 0 {
+		return nil, fmt.Errorf("Sampling can not be negative")
+	}
+	return parsed, nil
+}
+
+// Handler filters log entries by the level of their component and samples
+// debug entries before they are passed to the next handler
+type Handler struct {
+	next log.Handler
+
+	// parsed is the *config that is checked for every entry without locking
+	parsed atomic.Value
+
+	mu      sync.Mutex
+	config  Config
+	start   time.Time
+	counts  map[string]int
+	dropped int
+}
+
+// DefaultHandler is the Handler of the process, which is served on the admin
+// API of the debug server
+var DefaultHandler *Handler
+
+// NewHandler returns a Handler that passes the log entries to next
+func NewHandler(next log.Handler, config Config) (*Handler, error) {
+	h := &Handler{next: next}
+	if err := h.SetConfig(config); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Config returns the configuration of the Handler
+func (h *Handler) Config() Config {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.config
+}
+
+// SetConfig changes the configuration of the Handler
+func (h *Handler) SetConfig(config Config) error {
+	parsed, err := config.parse()
+	if err != nil {
+		return err
+	}
+	if config.Level == "" {
+		config.Level = levelNames[parsed.level]
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.config = config
+	h.counts = make(map[string]int)
+	h.parsed.Store(parsed)
+	return nil
+}
+
+// HandleLog implements log.Handler
+func (h *Handler) HandleLog(e *log.Entry) error {
+	if !h.allow(e) {
+		return nil
+	}
+	return h.next.HandleLog(e)
+}
+
+// allow returns true if the entry passes the levels and sampling. Only
+// sampled debug entries take the lock.
+func (h *Handler) allow(e *log.Entry) bool {
+	parsed := h.parsed.Load().(*config)
+	level := parsed.level
+	if component, ok := e.Fields[ComponentField].(string); ok {
+		if componentLevel, ok := parsed.levels[component]; ok {
+			level = componentLevel
+		}
+	}
+	if e.Level < level {
+		return false
+	}
+	if parsed.sampling == 0 || e.Level != log.DebugLevel {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	if now.Sub(h.start) >= SamplingInterval {
+		h.start = now
+		h.counts = make(map[string]int)
+	}
+	h.counts[e.Message]++
+	count := h.counts[e.Message]
+	if count <= parsed.sampling || (count-parsed.sampling)%parsed.sampling == 0 {
+		return true
+	}
+	h.dropped++
+	return false
+}
+
+// Dropped returns the number of debug entries that were dropped by sampling
+func (h *Handler) Dropped() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.dropped
+}
+
+// ServeHTTP returns the configuration of the Handler on GET requests and
+// changes it on PUT requests
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var config Config
+		if err := json.NewDecoder(req.Body).Decode(&config); err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.SetConfig(config); err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(res).Encode(h.Config())
+}
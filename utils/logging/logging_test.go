@@ -0,0 +1,110 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apex/log"
+	. "github.com/smartystreets/assertions"
+)
+
+type memoryHandler struct {
+	Entries []*log.Entry
+}
+
+func (h *memoryHandler) HandleLog(e *log.Entry) error {
+	h.Entries = append(h.Entries, e)
+	return nil
+}
+
+func TestParseLevels(t *testing.T) {
+	a := New(t)
+
+	levels, err := ParseLevels([]string{"router=debug", "handler=warn"})
+	a.So(err, ShouldBeNil)
+	a.So(levels, ShouldResemble, map[string]string{"router": "debug", "handler": "warn"})
+
+	_, err = ParseLevels([]string{"router"})
+	a.So(err, ShouldNotBeNil)
+	_, err = ParseLevels([]string{"router=verbose"})
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestHandlerLevels(t *testing.T) {
+	a := New(t)
+
+	next := &memoryHandler{}
+	h, err := NewHandler(next, Config{Levels: map[string]string{"router": "debug", "handler": "error"}})
+	a.So(err, ShouldBeNil)
+	a.So(h.Config().Level, ShouldEqual, "info")
+
+	logger := &log.Logger{Handler: h}
+	logger.Debug("dropped")
+	logger.WithField(ComponentField, "router").Debug("router debug")
+	logger.WithField(ComponentField, "handler").Warn("dropped")
+	logger.WithField(ComponentField, "handler").Error("handler error")
+	logger.WithField(ComponentField, "broker").Info("broker info")
+
+	a.So(next.Entries, ShouldHaveLength, 3)
+	a.So(next.Entries[0].Message, ShouldEqual, "router debug")
+	a.So(next.Entries[1].Message, ShouldEqual, "handler error")
+	a.So(next.Entries[2].Message, ShouldEqual, "broker info")
+
+	_, err = NewHandler(next, Config{Level: "verbose"})
+	a.So(err, ShouldNotBeNil)
+	_, err = NewHandler(next, Config{Sampling: -1})
+	a.So(err, ShouldNotBeNil)
+}
+
+func TestHandlerSampling(t *testing.T) {
+	a := New(t)
+
+	interval := SamplingInterval
+	SamplingInterval = time.Hour
+	defer func() { SamplingInterval = interval }()
+
+	next := &memoryHandler{}
+	h, err := NewHandler(next, Config{Level: "debug", Sampling: 3})
+	a.So(err, ShouldBeNil)
+
+	logger := &log.Logger{Handler: h}
+	for i := 0; i < 10; i++ {
+		logger.Debug("uplink")
+	}
+	logger.Debug("other")
+	logger.Info("info")
+
+	// The first 3 uplinks, the 6th and the 9th
+	a.So(next.Entries, ShouldHaveLength, 7)
+	a.So(h.Dropped(), ShouldEqual, 5)
+}
+
+func TestHandlerHTTP(t *testing.T) {
+	a := New(t)
+
+	h, err := NewHandler(&memoryHandler{}, Config{Level: "info"})
+	a.So(err, ShouldBeNil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("PUT", "/log", strings.NewReader(`{"level":"warn","levels":{"router":"debug"},"sampling":10}`)))
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	a.So(h.Config().Level, ShouldEqual, "warn")
+	a.So(h.Config().Levels["router"], ShouldEqual, "debug")
+	a.So(h.Config().Sampling, ShouldEqual, 10)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("PUT", "/log", strings.NewReader(`{"level":"verbose"}`)))
+	a.So(rec.Code, ShouldEqual, http.StatusBadRequest)
+	a.So(h.Config().Level, ShouldEqual, "warn")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/log", nil))
+	a.So(rec.Code, ShouldEqual, http.StatusOK)
+	a.So(rec.Body.String(), ShouldContainSubstring, `"sampling":10`)
+}
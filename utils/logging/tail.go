@@ -0,0 +1,110 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+)
+
+// TailBuffer is the number of log entries that is buffered for each
+// subscriber of a Tail. Entries are dropped for subscribers that are too slow.
+var TailBuffer = 256
+
+// DefaultTail receives the log entries of the process, before they are
+// filtered by level
+var DefaultTail = NewTail()
+
+// TailFilter selects the log entries of a Tail subscription
+type TailFilter struct {
+	// Level is the minimum level of the entries
+	Level log.Level
+	// Fields contains the values that the fields of the entries must have
+	Fields map[string]string
+}
+
+func (f TailFilter) match(e *log.Entry) bool {
+	if e.Level < f.Level {
+		return false
+	}
+	for field, value := range f.Fields {
+		actual, ok := e.Fields[field]
+		if !ok || !strings.EqualFold(fmt.Sprint(actual), value) {
+			return false
+		}
+	}
+	return true
+}
+
+// TailEntry is a log entry that is streamed by a Tail
+type TailEntry struct {
+	Time    time.Time  `json:"time"`
+	Level   string     `json:"level"`
+	Message string     `json:"message"`
+	Fields  log.Fields `json:"fields,omitempty"`
+}
+
+type tailSubscriber struct {
+	filter  TailFilter
+	entries chan *TailEntry
+}
+
+// Tail is a log handler that streams log entries to its subscribers
+type Tail struct {
+	mu          sync.RWMutex
+	subscribers map[*tailSubscriber]struct{}
+}
+
+// NewTail returns a new Tail
+func NewTail() *Tail {
+	return &Tail{subscribers: make(map[*tailSubscriber]struct{})}
+}
+
+// Subscribe to the log entries that match the filter. The channel is closed
+// when unsubscribe is called.
+func (t *Tail) Subscribe(filter TailFilter) (entries <-chan *TailEntry, unsubscribe func()) {
+	sub := &tailSubscriber{filter: filter, entries: make(chan *TailEntry, TailBuffer)}
+	t.mu.Lock()
+	t.subscribers[sub] = struct{}{}
+	t.mu.Unlock()
+	var once sync.Once
+	return sub.entries, func() {
+		once.Do(func() {
+			t.mu.Lock()
+			delete(t.subscribers, sub)
+			t.mu.Unlock()
+			close(sub.entries)
+		})
+	}
+}
+
+// HandleLog implements log.Handler
+func (t *Tail) HandleLog(e *log.Entry) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var entry *TailEntry
+	for sub := range t.subscribers {
+		if !sub.filter.match(e) {
+			continue
+		}
+		if entry == nil {
+			entry = &TailEntry{Time: e.Timestamp, Level: levelNames[e.Level], Message: e.Message, Fields: make(log.Fields, len(e.Fields))}
+			for k, v := range e.Fields {
+				if err, ok := v.(error); ok {
+					v = err.Error()
+				}
+				entry.Fields[k] = v
+			}
+		}
+		select {
+		case sub.entries <- entry:
+		default:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apex/log"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestTailSubscribe(t *testing.T) {
+	a := New(t)
+
+	tail := NewTail()
+	logger := &log.Logger{Handler: tail}
+
+	// Without subscribers
+	logger.Info("dropped")
+
+	entries, unsubscribe := tail.Subscribe(TailFilter{Level: log.InfoLevel, Fields: map[string]string{"DevEUI": "0102030405060708"}})
+	logger.WithField("DevEUI", "0807060504030201").Info("other device")
+	logger.WithField("DevEUI", "0102030405060708").Debug("debug")
+	logger.WithField("DevEUI", "0102030405060708").WithError(errors.New("failed")).Warn("device warning")
+
+	entry := <-entries
+	a.So(entry.Message, ShouldEqual, "device warning")
+	a.So(entry.Level, ShouldEqual, "warn")
+	a.So(entry.Fields["error"], ShouldEqual, "failed")
+
+	unsubscribe()
+	unsubscribe()
+	_, ok := <-entries
+	a.So(ok, ShouldBeFalse)
+
+	logger.WithField("DevEUI", "0102030405060708").Warn("after unsubscribe")
+}
@@ -42,6 +42,7 @@ var exampleValues = map[string]interface{}{
 	".lorawan.Device.dev_addr":                "01020304",
 	".lorawan.Device.dev_eui":                 "0102030405060708",
 	".lorawan.Device.dev_id":                  "some-dev-id",
+	".lorawan.Device.gateway_groups":          "some-site",
 	".lorawan.Device.nwk_s_key":               "01020304050607080102030405060708",
 	".lorawan.Device.uses32_bit_f_cnt":        true,
 }
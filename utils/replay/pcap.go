@@ -0,0 +1,153 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package replay
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Magic numbers of pcap files with microsecond and nanosecond timestamps
+const (
+	pcapMagic      = 0xa1b2c3d4
+	pcapMagicNanos = 0xa1b23c4d
+)
+
+// Link types of pcap files that are supported
+const (
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLinuxSLL = 113
+)
+
+// DefaultPort is the UDP port of the Semtech packet forwarder protocol
+const DefaultPort = 1700
+
+// IsPCAP returns true if the header is the header of a pcap file
+func IsPCAP(header []byte) bool {
+	if len(header) < 4 {
+		return false
+	}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		if magic := order.Uint32(header); magic == pcapMagic || magic == pcapMagicNanos {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadPCAP reads the PUSH_DATA packets that were sent to the given UDP port
+// from a pcap file. Packets to other ports and other packets of the Semtech
+// UDP protocol are skipped. The packets get the time of the capture.
+func ReadPCAP(r io.Reader, port uint16) ([]*Packet, error) {
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if magic := binary.BigEndian.Uint32(header); magic == pcapMagic || magic == pcapMagicNanos {
+		order = binary.BigEndian
+	}
+	magic := order.Uint32(header)
+	if magic != pcapMagic && magic != pcapMagicNanos {
+		return nil, fmt.Errorf("not a pcap file")
+	}
+	linkType := order.Uint32(header[20:])
+
+	var result []*Packet
+	record := make([]byte, 16)
+	for i := 1; ; i++ {
+		if _, err := io.ReadFull(r, record); err != nil {
+			if err == io.EOF {
+				return result, nil
+			}
+			return nil, err
+		}
+		sec, frac := int64(order.Uint32(record)), int64(order.Uint32(record[4:]))
+		if magic == pcapMagic {
+			frac *= 1000
+		}
+		data := make([]byte, order.Uint32(record[8:]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		payload, ok := udpPayload(linkType, data, port)
+		if !ok {
+			continue
+		}
+		packets, err := parsePushData(time.Unix(sec, frac).UTC(), payload)
+		if err != nil {
+			return nil, fmt.Errorf("packet %d: %s", i, err)
+		}
+		result = append(result, packets...)
+	}
+}
+
+// udpPayload returns the payload of a captured UDP packet to the given port
+func udpPayload(linkType uint32, data []byte, port uint16) ([]byte, bool) {
+	var etherType uint16
+	switch linkType {
+	case linkTypeNull:
+		if len(data) < 4 {
+			return nil, false
+		}
+		data = data[4:]
+	case linkTypeEthernet:
+		if len(data) < 14 {
+			return nil, false
+		}
+		etherType, data = binary.BigEndian.Uint16(data[12:]), data[14:]
+		if etherType == 0x8100 && len(data) >= 4 { // VLAN tag
+			etherType, data = binary.BigEndian.Uint16(data[2:]), data[4:]
+		}
+	case linkTypeRaw:
+	case linkTypeLinuxSLL:
+		if len(data) < 16 {
+			return nil, false
+		}
+		etherType, data = binary.BigEndian.Uint16(data[14:]), data[16:]
+	default:
+		return nil, false
+	}
+	if etherType != 0 && etherType != 0x0800 && etherType != 0x86DD {
+		return nil, false
+	}
+	if len(data) < 1 {
+		return nil, false
+	}
+
+	switch data[0] >> 4 {
+	case 4:
+		if len(data) < 20 {
+			return nil, false
+		}
+		headerLength := int(data[0]&0x0f) * 4
+		if data[9] != 17 || len(data) < headerLength {
+			return nil, false
+		}
+		data = data[headerLength:]
+	case 6:
+		if len(data) < 40 || data[6] != 17 {
+			return nil, false
+		}
+		data = data[40:]
+	default:
+		return nil, false
+	}
+
+	if len(data) < 8 {
+		return nil, false
+	}
+	if port != 0 && binary.BigEndian.Uint16(data[2:]) != port {
+		return nil, false
+	}
+	length := int(binary.BigEndian.Uint16(data[4:]))
+	if length < 8 || length > len(data) {
+		length = len(data)
+	}
+	return data[8:length], true
+}
@@ -0,0 +1,120 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package replay reads uplink traffic that was captured from packet
+// forwarders and replays it against a Router. In tests, the packets can be
+// replayed directly into a Router with Replay(ctx, packets, 0, router.HandleUplink).
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+)
+
+// Packet is an uplink message that was received by a gateway
+type Packet struct {
+	// Time is the time at which the packet was captured. Packets without
+	// time are replayed immediately after the previous packet.
+	Time      time.Time
+	GatewayID string
+	Uplink    *pb_router.UplinkMessage
+}
+
+// Read reads packets from a pcap file or from JSON lines, depending on the
+// header of the file. The port is the UDP port of PUSH_DATA packets in pcap files.
+func Read(r io.Reader, port uint16) ([]*Packet, error) {
+	buffered := bufio.NewReader(r)
+	header, _ := buffered.Peek(4)
+	if IsPCAP(header) {
+		return ReadPCAP(buffered, port)
+	}
+	return ReadJSON(buffered)
+}
+
+// SendFunc sends the uplink message of a gateway
+type SendFunc func(gatewayID string, uplink *pb_router.UplinkMessage) error
+
+// Report contains the statistics of a replay
+type Report struct {
+	Duration   time.Duration
+	Sent       int
+	SendErrors int
+}
+
+func (r *Report) String() string {
+	return fmt.Sprintf("Sent %d packets in %s (%d errors)\n", r.Sent, r.Duration, r.SendErrors)
+}
+
+// Replay sends the packets in order. The delays between the packets are
+// divided by the speed; with speed 0 the packets are sent without delay.
+func Replay(ctx ttnlog.Interface, packets []*Packet, speed float64, send SendFunc) *Report {
+	report := new(Report)
+	start := time.Now()
+	var first time.Time
+	for i, packet := range packets {
+		if speed > 0 && !packet.Time.IsZero() {
+			if first.IsZero() {
+				first = packet.Time
+			}
+			offset := time.Duration(float64(packet.Time.Sub(first)) / speed)
+			if wait := start.Add(offset).Sub(time.Now()); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+		if err := send(packet.GatewayID, packet.Uplink); err != nil {
+			ctx.WithError(err).WithFields(ttnlog.Fields{
+				"Packet":    i + 1,
+				"GatewayID": packet.GatewayID,
+			}).Warn("Could not send packet")
+			report.SendErrors++
+			continue
+		}
+		report.Sent++
+	}
+	report.Duration = time.Since(start)
+	return report
+}
+
+// RouterStreams sends uplink messages to a Router, with a stream for each gateway
+type RouterStreams struct {
+	client  pb_router.RouterClient
+	token   string
+	clients []pb_router.RouterClientForGateway
+	streams map[string]pb_router.UplinkStream
+}
+
+// NewRouterStreams returns a new RouterStreams. The gateways authenticate with the token.
+func NewRouterStreams(client pb_router.RouterClient, gatewayToken string) *RouterStreams {
+	return &RouterStreams{
+		client:  client,
+		token:   gatewayToken,
+		streams: make(map[string]pb_router.UplinkStream),
+	}
+}
+
+// Send sends the uplink message of the gateway. It is a SendFunc.
+func (s *RouterStreams) Send(gatewayID string, uplink *pb_router.UplinkMessage) error {
+	stream, ok := s.streams[gatewayID]
+	if !ok {
+		gtwClient := pb_router.NewRouterClientForGateway(s.client, gatewayID, s.token)
+		s.clients = append(s.clients, gtwClient)
+		stream = pb_router.NewMonitoredUplinkStream(gtwClient)
+		s.streams[gatewayID] = stream
+	}
+	return stream.Send(uplink)
+}
+
+// Close closes the streams
+func (s *RouterStreams) Close() {
+	for _, stream := range s.streams {
+		stream.Close()
+	}
+	for _, client := range s.clients {
+		client.Close()
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	"github.com/smartystreets/assertions"
+)
+
+const pushDataJSON = `{"rxpk":[{"time":"2017-06-01T12:00:00.000001Z","tmst":3512348611,"chan":2,"rfch":0,"freq":868.5,"stat":1,"modu":"LORA","datr":"SF7BW125","codr":"4/5","rssi":-35,"lsnr":5.1,"size":4,"data":"AQIDBA=="},{"tmst":3512348612,"freq":868.1,"stat":-1,"modu":"LORA","datr":"SF7BW125","codr":"4/5","data":"AQIDBA=="}]}`
+
+func udpFrame(port uint16, payload []byte) []byte {
+	frame := make([]byte, 14+20+8)
+	binary.BigEndian.PutUint16(frame[12:], 0x0800)
+	ip := frame[14:]
+	ip[0] = 0x45
+	ip[9] = 17
+	udp := frame[34:]
+	binary.BigEndian.PutUint16(udp[0:], 40000)
+	binary.BigEndian.PutUint16(udp[2:], port)
+	binary.BigEndian.PutUint16(udp[4:], uint16(8+len(payload)))
+	return append(frame, payload...)
+}
+
+func pcapFile(frames map[time.Time][]byte, order []time.Time) []byte {
+	var buf bytes.Buffer
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header, pcapMagic)
+	binary.LittleEndian.PutUint32(header[16:], 65535)
+	binary.LittleEndian.PutUint32(header[20:], linkTypeEthernet)
+	buf.Write(header)
+	for _, t := range order {
+		frame := frames[t]
+		record := make([]byte, 16)
+		binary.LittleEndian.PutUint32(record, uint32(t.Unix()))
+		binary.LittleEndian.PutUint32(record[4:], uint32(t.Nanosecond()/1000))
+		binary.LittleEndian.PutUint32(record[8:], uint32(len(frame)))
+		binary.LittleEndian.PutUint32(record[12:], uint32(len(frame)))
+		buf.Write(record)
+		buf.Write(frame)
+	}
+	return buf.Bytes()
+}
+
+func TestReadPCAP(t *testing.T) {
+	a := assertions.New(t)
+
+	eui := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	pushData := append(append([]byte{0x02, 0x12, 0x34, 0x00}, eui...), []byte(pushDataJSON)...)
+	pullData := append([]byte{0x02, 0x12, 0x35, 0x02}, eui...)
+
+	t1 := time.Date(2017, 6, 1, 12, 0, 0, 500000000, time.UTC)
+	t2, t3 := t1.Add(time.Second), t1.Add(2*time.Second)
+	file := pcapFile(map[time.Time][]byte{
+		t1: udpFrame(DefaultPort, pushData),
+		t2: udpFrame(DefaultPort, pullData),
+		t3: udpFrame(53, pushData),
+	}, []time.Time{t1, t2, t3})
+
+	a.So(IsPCAP(file), assertions.ShouldBeTrue)
+	packets, err := Read(bytes.NewReader(file), DefaultPort)
+	a.So(err, assertions.ShouldBeNil)
+	a.So(packets, assertions.ShouldHaveLength, 1)
+	a.So(packets[0].Time, assertions.ShouldResemble, t1)
+	a.So(packets[0].GatewayID, assertions.ShouldEqual, "eui-0102030405060708")
+
+	uplink := packets[0].Uplink
+	a.So(uplink.Payload, assertions.ShouldResemble, []byte{1, 2, 3, 4})
+	a.So(uplink.GatewayMetadata.Timestamp, assertions.ShouldEqual, 3512348611)
+	a.So(uplink.GatewayMetadata.Frequency, assertions.ShouldEqual, 868500000)
+	a.So(uplink.GatewayMetadata.Channel, assertions.ShouldEqual, 2)
+	a.So(uplink.GatewayMetadata.Rssi, assertions.ShouldEqual, -35)
+	a.So(uplink.GatewayMetadata.Time, assertions.ShouldEqual, time.Date(2017, 6, 1, 12, 0, 0, 1000, time.UTC).UnixNano())
+	a.So(uplink.GetProtocolMetadata().GetLorawan().DataRate, assertions.ShouldEqual, "SF7BW125")
+	a.So(uplink.GetProtocolMetadata().GetLorawan().CodingRate, assertions.ShouldEqual, "4/5")
+
+	packets, err = ReadPCAP(bytes.NewReader(file), 0)
+	a.So(err, assertions.ShouldBeNil)
+	a.So(packets, assertions.ShouldHaveLength, 2)
+
+	_, err = ReadPCAP(strings.NewReader("not a pcap file, but long enough"), DefaultPort)
+	a.So(err, assertions.ShouldNotBeNil)
+}
+
+func TestReadJSON(t *testing.T) {
+	a := assertions.New(t)
+
+	packets, err := Read(strings.NewReader(`
+# Captured from the field
+{"time":"2017-06-01T12:00:01Z","gateway_eui":"0102030405060708","rxpk":[{"tmst":1,"freq":868.1,"stat":1,"modu":"LORA","datr":"SF12BW125","codr":"4/5","data":"AQ=="}]}
+{"gateway_id":"my-gateway","rxpk":[{"time":"2017-06-01T12:00:02Z","freq":868.3,"stat":1,"modu":"FSK","datr":50000,"data":"Ag=="}]}
+`), DefaultPort)
+	a.So(err, assertions.ShouldBeNil)
+	a.So(packets, assertions.ShouldHaveLength, 2)
+	a.So(packets[0].GatewayID, assertions.ShouldEqual, "eui-0102030405060708")
+	a.So(packets[0].Time, assertions.ShouldResemble, time.Date(2017, 6, 1, 12, 0, 1, 0, time.UTC))
+	a.So(packets[1].GatewayID, assertions.ShouldEqual, "my-gateway")
+	a.So(packets[1].Time, assertions.ShouldResemble, time.Date(2017, 6, 1, 12, 0, 2, 0, time.UTC))
+	a.So(packets[1].Uplink.GetProtocolMetadata().GetLorawan().BitRate, assertions.ShouldEqual, 50000)
+
+	_, err = ReadJSON(strings.NewReader(`{"rxpk":[]}`))
+	a.So(err, assertions.ShouldNotBeNil)
+
+	_, err = ReadJSON(strings.NewReader(`{"gateway_id":"gtw","rxpk":[{"modu":"LORA","datr":"SF7BW125","data":"!"}]}`))
+	a.So(err, assertions.ShouldNotBeNil)
+}
+
+func TestReplay(t *testing.T) {
+	a := assertions.New(t)
+	ctx := GetLogger(t, "TestReplay")
+
+	start := time.Now()
+	packets := []*Packet{
+		{Time: start, GatewayID: "gtw-1", Uplink: &pb_router.UplinkMessage{Payload: []byte{1}}},
+		{Time: start.Add(200 * time.Millisecond), GatewayID: "gtw-2", Uplink: &pb_router.UplinkMessage{Payload: []byte{2}}},
+		{GatewayID: "gtw-1", Uplink: &pb_router.UplinkMessage{Payload: []byte{3}}},
+	}
+
+	var sent []string
+	send := func(gatewayID string, uplink *pb_router.UplinkMessage) error {
+		sent = append(sent, gatewayID)
+		if uplink.Payload[0] == 3 {
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	report := Replay(ctx, packets, 0, send)
+	a.So(sent, assertions.ShouldResemble, []string{"gtw-1", "gtw-2", "gtw-1"})
+	a.So(report.Sent, assertions.ShouldEqual, 2)
+	a.So(report.SendErrors, assertions.ShouldEqual, 1)
+	a.So(report.Duration, assertions.ShouldBeLessThan, 50*time.Millisecond)
+
+	report = Replay(ctx, packets, 4, send)
+	a.So(report.Duration, assertions.ShouldBeBetween, 40*time.Millisecond, 150*time.Millisecond)
+}
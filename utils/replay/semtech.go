@@ -0,0 +1,164 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package replay
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	"github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+)
+
+// pushData is the identifier of PUSH_DATA packets of the Semtech UDP protocol
+const pushData = 0x00
+
+// rxpk is a received packet in the Semtech UDP protocol
+type rxpk struct {
+	Time string          `json:"time"`
+	Tmst uint32          `json:"tmst"`
+	Freq float64         `json:"freq"`
+	Chan uint32          `json:"chan"`
+	RFCh uint32          `json:"rfch"`
+	Stat int             `json:"stat"`
+	Modu string          `json:"modu"`
+	DatR json.RawMessage `json:"datr"`
+	CodR string          `json:"codr"`
+	RSSI float32         `json:"rssi"`
+	LSNR float32         `json:"lsnr"`
+	Data string          `json:"data"`
+}
+
+// gatewayID returns the ID that the packet forwarder bridge uses for a gateway EUI
+func gatewayID(eui []byte) string {
+	return "eui-" + hex.EncodeToString(eui)
+}
+
+// uplink converts the rxpk to an uplink message. Packets with a failed CRC are skipped (nil, nil).
+func (p rxpk) uplink(gatewayID string) (*pb_router.UplinkMessage, error) {
+	if p.Stat == -1 {
+		return nil, nil
+	}
+	payload, err := base64.StdEncoding.DecodeString(p.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data: %s", err)
+	}
+	gatewayMetadata := &pb_gateway.RxMetadata{
+		GatewayId: gatewayID,
+		Timestamp: p.Tmst,
+		RfChain:   p.RFCh,
+		Channel:   p.Chan,
+		Frequency: uint64(math.Floor(p.Freq*1000000 + 0.5)),
+		Rssi:      p.RSSI,
+		Snr:       p.LSNR,
+	}
+	if t, err := time.Parse(time.RFC3339Nano, p.Time); err == nil {
+		gatewayMetadata.Time = t.UnixNano()
+	}
+	lorawanMetadata := &pb_lorawan.Metadata{CodingRate: p.CodR}
+	switch p.Modu {
+	case "LORA":
+		lorawanMetadata.Modulation = pb_lorawan.Modulation_LORA
+		if err := json.Unmarshal(p.DatR, &lorawanMetadata.DataRate); err != nil {
+			return nil, fmt.Errorf("invalid LoRa data rate: %s", p.DatR)
+		}
+	case "FSK":
+		lorawanMetadata.Modulation = pb_lorawan.Modulation_FSK
+		if err := json.Unmarshal(p.DatR, &lorawanMetadata.BitRate); err != nil {
+			return nil, fmt.Errorf("invalid FSK bit rate: %s", p.DatR)
+		}
+	default:
+		return nil, fmt.Errorf("unknown modulation %q", p.Modu)
+	}
+	return &pb_router.UplinkMessage{
+		Payload:          payload,
+		GatewayMetadata:  gatewayMetadata,
+		ProtocolMetadata: &protocol.RxMetadata{Protocol: &protocol.RxMetadata_Lorawan{Lorawan: lorawanMetadata}},
+	}, nil
+}
+
+// rxpkPackets converts the rxpks that were received at the given time to packets
+func rxpkPackets(t time.Time, gatewayID string, rxpks []rxpk) ([]*Packet, error) {
+	var packets []*Packet
+	for _, p := range rxpks {
+		uplink, err := p.uplink(gatewayID)
+		if err != nil {
+			return nil, err
+		}
+		if uplink == nil {
+			continue
+		}
+		packetTime := t
+		if packetTime.IsZero() && uplink.GatewayMetadata.Time != 0 {
+			packetTime = time.Unix(0, uplink.GatewayMetadata.Time).UTC()
+		}
+		packets = append(packets, &Packet{Time: packetTime, GatewayID: gatewayID, Uplink: uplink})
+	}
+	return packets, nil
+}
+
+// parsePushData parses a Semtech UDP packet that was captured at the given
+// time. Packets other than PUSH_DATA are skipped.
+func parsePushData(t time.Time, data []byte) ([]*Packet, error) {
+	if len(data) < 12 || data[3] != pushData {
+		return nil, nil
+	}
+	var push struct {
+		RXPK []rxpk `json:"rxpk"`
+	}
+	if err := json.Unmarshal(data[12:], &push); err != nil {
+		return nil, fmt.Errorf("invalid PUSH_DATA: %s", err)
+	}
+	return rxpkPackets(t, gatewayID(data[4:12]), push.RXPK)
+}
+
+// jsonLine is a line of a JSON log. The gateway is identified by its EUI or ID.
+type jsonLine struct {
+	Time       time.Time `json:"time"`
+	GatewayEUI string    `json:"gateway_eui"`
+	GatewayID  string    `json:"gateway_id"`
+	RXPK       []rxpk    `json:"rxpk"`
+}
+
+// ReadJSON reads packets from JSON lines with the time, the gateway_eui or
+// gateway_id and the rxpk array of a PUSH_DATA packet. If a line has no time,
+// the time of the rxpk is used. Empty lines and lines starting with # are skipped.
+func ReadJSON(r io.Reader) ([]*Packet, error) {
+	var result []*Packet
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var in jsonLine
+		if err := json.Unmarshal([]byte(text), &in); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		id := in.GatewayID
+		if id == "" {
+			eui, err := hex.DecodeString(in.GatewayEUI)
+			if err != nil || len(eui) != 8 {
+				return nil, fmt.Errorf("line %d: expected gateway_id or gateway_eui", line)
+			}
+			id = gatewayID(eui)
+		}
+		packets, err := rxpkPackets(in.Time, id, in.RXPK)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		result = append(result, packets...)
+	}
+	return result, scanner.Err()
+}
@@ -0,0 +1,78 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package security
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+var keyWrapIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// ErrKeyUnwrap is returned when a wrapped key can not be unwrapped with the KEK
+var ErrKeyUnwrap = errors.New("security: could not unwrap key")
+
+// WrapKey wraps the key with the key encryption key using the AES Key Wrap
+// algorithm of RFC 3394. The key must be a multiple of 8 bytes.
+func WrapKey(kek, key []byte) ([]byte, error) {
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, errors.New("security: key to wrap must be a multiple of 8 bytes and at least 16 bytes")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	n := len(key) / 8
+	wrapped := make([]byte, 8+len(key))
+	copy(wrapped[8:], key)
+	a := make([]byte, 8)
+	copy(a, keyWrapIV)
+	b := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(b[:8], a)
+			copy(b[8:], wrapped[i*8:(i+1)*8])
+			block.Encrypt(b, b)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(b[:8])^t)
+			copy(wrapped[i*8:(i+1)*8], b[8:])
+		}
+	}
+	copy(wrapped[:8], a)
+	return wrapped, nil
+}
+
+// UnwrapKey unwraps a key that was wrapped with WrapKey. It returns
+// ErrKeyUnwrap if the integrity check fails, for example because the KEK is wrong.
+func UnwrapKey(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, errors.New("security: wrapped key must be a multiple of 8 bytes and at least 24 bytes")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	n := len(wrapped)/8 - 1
+	key := make([]byte, len(wrapped)-8)
+	copy(key, wrapped[8:])
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+	b := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b[:8], binary.BigEndian.Uint64(a)^t)
+			copy(b[8:], key[(i-1)*8:i*8])
+			block.Decrypt(b, b)
+			copy(a, b[:8])
+			copy(key[(i-1)*8:i*8], b[8:])
+		}
+	}
+	if subtle.ConstantTimeCompare(a, keyWrapIV) != 1 {
+		return nil, ErrKeyUnwrap
+	}
+	return key, nil
+}
@@ -0,0 +1,48 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package security
+
+import (
+	"encoding/hex"
+	"testing"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestKeyWrap(t *testing.T) {
+	a := New(t)
+
+	mustDecode := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			panic(err)
+		}
+		return b
+	}
+
+	// Test vectors of RFC 3394
+	for _, vector := range []struct {
+		kek, key, wrapped string
+	}{
+		{"000102030405060708090A0B0C0D0E0F", "00112233445566778899AABBCCDDEEFF", "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5"},
+		{"000102030405060708090A0B0C0D0E0F1011121314151617", "00112233445566778899AABBCCDDEEFF", "96778B25AE6CA435F92B5B97C050AED2468AB8A17AD84E5D"},
+		{"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F", "00112233445566778899AABBCCDDEEFF0001020304050607", "A8F9BC1612C68B3FF6E6F4FBE30E71E4769C8B80A32CB8958CD5D17D6B254DA1"},
+	} {
+		wrapped, err := WrapKey(mustDecode(vector.kek), mustDecode(vector.key))
+		a.So(err, ShouldBeNil)
+		a.So(wrapped, ShouldResemble, mustDecode(vector.wrapped))
+
+		key, err := UnwrapKey(mustDecode(vector.kek), wrapped)
+		a.So(err, ShouldBeNil)
+		a.So(key, ShouldResemble, mustDecode(vector.key))
+	}
+
+	// A wrong KEK fails the integrity check
+	wrapped, _ := WrapKey(mustDecode("000102030405060708090A0B0C0D0E0F"), mustDecode("00112233445566778899AABBCCDDEEFF"))
+	_, err := UnwrapKey(mustDecode("0F0E0D0C0B0A09080706050403020100"), wrapped)
+	a.So(err, ShouldEqual, ErrKeyUnwrap)
+
+	_, err = WrapKey(mustDecode("000102030405060708090A0B0C0D0E0F"), []byte{1, 2, 3})
+	a.So(err, ShouldNotBeNil)
+}
@@ -0,0 +1,123 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package harness
+
+import (
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	pb_gateway "github.com/TheThingsNetwork/ttn/api/gateway"
+	pb_protocol "github.com/TheThingsNetwork/ttn/api/protocol"
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	pb_router "github.com/TheThingsNetwork/ttn/api/router"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	handler_device "github.com/TheThingsNetwork/ttn/core/handler/device"
+	ns_device "github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/brocaar/lorawan"
+)
+
+// ABPDevice contains the session of a device that is registered with ABP
+type ABPDevice struct {
+	AppID   string
+	DevID   string
+	AppEUI  types.AppEUI
+	DevEUI  types.DevEUI
+	DevAddr types.DevAddr
+	NwkSKey types.NwkSKey
+	AppSKey types.AppSKey
+
+	// DisableFCntCheck disables the frame counter check in the Network Server
+	DisableFCntCheck bool
+}
+
+// AddApplication registers the application in the Handler and announces the
+// Handler as responsible for the application
+func (n *Network) AddApplication(appID string) error {
+	if err := n.Applications.Set(&application.Application{AppID: appID}); err != nil {
+		return err
+	}
+	return n.Discovery.AddMetadata("handler", n.ID, &pb_discovery.Metadata{Metadata: &pb_discovery.Metadata_AppId{
+		AppId: appID,
+	}})
+}
+
+// AddABPDevice registers the device in the Network Server and Handler
+func (n *Network) AddABPDevice(dev ABPDevice) error {
+	err := n.NetworkServerDevices.Set(&ns_device.Device{
+		AppID:   dev.AppID,
+		DevID:   dev.DevID,
+		AppEUI:  dev.AppEUI,
+		DevEUI:  dev.DevEUI,
+		DevAddr: dev.DevAddr,
+		NwkSKey: dev.NwkSKey,
+		Options: ns_device.Options{
+			ActivationConstraints: "abp",
+			DisableFCntCheck:      dev.DisableFCntCheck,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return n.HandlerDevices.Set(&handler_device.Device{
+		AppID:   dev.AppID,
+		DevID:   dev.DevID,
+		AppEUI:  dev.AppEUI,
+		DevEUI:  dev.DevEUI,
+		DevAddr: dev.DevAddr,
+		NwkSKey: dev.NwkSKey,
+		AppSKey: dev.AppSKey,
+	})
+}
+
+// BuildUplink builds an unconfirmed uplink message for the device, as it
+// would be received by a gateway
+func BuildUplink(dev ABPDevice, fCnt uint32, fPort uint8, payload []byte) (*pb_router.UplinkMessage, error) {
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{
+				DevAddr: lorawan.DevAddr(dev.DevAddr),
+				FCnt:    fCnt,
+			},
+			FPort: &fPort,
+			FRMPayload: []lorawan.Payload{
+				&lorawan.DataPayload{Bytes: payload},
+			},
+		},
+	}
+	if err := phy.EncryptFRMPayload(lorawan.AES128Key(dev.AppSKey)); err != nil {
+		return nil, err
+	}
+	if err := phy.SetMIC(lorawan.AES128Key(dev.NwkSKey)); err != nil {
+		return nil, err
+	}
+	bytes, err := phy.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &pb_router.UplinkMessage{
+		Payload: bytes,
+		ProtocolMetadata: &pb_protocol.RxMetadata{Protocol: &pb_protocol.RxMetadata_Lorawan{Lorawan: &pb_lorawan.Metadata{
+			CodingRate: "4/5",
+			DataRate:   "SF7BW125",
+			Modulation: pb_lorawan.Modulation_LORA,
+		}}},
+		GatewayMetadata: &pb_gateway.RxMetadata{
+			Frequency: 868100000,
+			Rssi:      -25.0,
+			Snr:       5.0,
+		},
+	}, nil
+}
+
+// SendUplink injects the uplink message at the Router, as if it was received
+// by the given gateway
+func (n *Network) SendUplink(gatewayID string, uplink *pb_router.UplinkMessage) error {
+	if uplink.GatewayMetadata != nil {
+		uplink.GatewayMetadata.GatewayId = gatewayID
+	}
+	return n.Router.HandleUplink(gatewayID, uplink)
+}
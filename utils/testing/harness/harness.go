@@ -0,0 +1,280 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+// Package harness assembles a complete network of real components that talk to
+// each other over loopback gRPC. It can be used to write end-to-end tests
+// without external dependencies such as Redis or an MQTT broker.
+package harness
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"time"
+
+	"github.com/TheThingsNetwork/go-account-lib/claims"
+	ttnlog "github.com/TheThingsNetwork/go-utils/log"
+	pb_discovery "github.com/TheThingsNetwork/ttn/api/discovery"
+	"github.com/TheThingsNetwork/ttn/core/broker"
+	"github.com/TheThingsNetwork/ttn/core/component"
+	"github.com/TheThingsNetwork/ttn/core/discovery"
+	"github.com/TheThingsNetwork/ttn/core/handler"
+	"github.com/TheThingsNetwork/ttn/core/handler/application"
+	handler_device "github.com/TheThingsNetwork/ttn/core/handler/device"
+	"github.com/TheThingsNetwork/ttn/core/networkserver"
+	ns_device "github.com/TheThingsNetwork/ttn/core/networkserver/device"
+	"github.com/TheThingsNetwork/ttn/core/router"
+	"github.com/TheThingsNetwork/ttn/core/types"
+	"github.com/TheThingsNetwork/ttn/utils/security"
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+)
+
+// issuer is the auth server ID that is used for the tokens of the components
+const issuer = "harness"
+
+// DefaultPrefix is the DevAddr prefix that is used by the Network Server if
+// no prefix is configured
+var DefaultPrefix = types.DevAddrPrefix{DevAddr: types.DevAddr{0x26, 0x00, 0x00, 0x00}, Length: 7}
+
+// Config for a Network
+type Config struct {
+	// ID of the components. Defaults to "dev"
+	ID string
+	// NetID of the Network Server. Defaults to 0x13 (The Things Network)
+	NetID int
+	// Prefixes that are used by the Network Server. Defaults to DefaultPrefix
+	Prefixes []types.DevAddrPrefix
+	// DeduplicationDelay of the Broker. Defaults to 50ms
+	DeduplicationDelay time.Duration
+}
+
+// Network is a complete network of components
+type Network struct {
+	Ctx ttnlog.Interface
+	ID  string
+
+	Discovery     discovery.Discovery
+	Router        router.Router
+	Broker        broker.Broker
+	NetworkServer networkserver.NetworkServer
+	Handler       handler.Handler
+
+	// The stores that are used by the Network Server and Handler
+	NetworkServerDevices ns_device.Store
+	HandlerDevices       handler_device.Store
+	Applications         application.Store
+
+	keyDir     string
+	privKey    *ecdsa.PrivateKey
+	servers    []*grpc.Server
+	components []component.Interface
+}
+
+// NewNetwork starts the discovery, network server, broker, router and handler
+// on random loopback ports. Because the components are configured through
+// viper, only one Network should be running at the same time.
+func NewNetwork(ctx ttnlog.Interface, config Config) (*Network, error) {
+	if config.ID == "" {
+		config.ID = "dev"
+	}
+	if config.NetID == 0 {
+		config.NetID = 0x13
+	}
+	if len(config.Prefixes) == 0 {
+		config.Prefixes = []types.DevAddrPrefix{DefaultPrefix}
+	}
+	if config.DeduplicationDelay == 0 {
+		config.DeduplicationDelay = 50 * time.Millisecond
+	}
+
+	n := &Network{
+		Ctx:                  ctx,
+		ID:                   config.ID,
+		NetworkServerDevices: ns_device.NewMemoryDeviceStore(),
+		HandlerDevices:       handler_device.NewMemoryDeviceStore(),
+		Applications:         application.NewMemoryApplicationStore(),
+	}
+	if err := n.init(config); err != nil {
+		n.Close()
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *Network) init(config Config) (err error) {
+	n.keyDir, err = ioutil.TempDir("", "ttn-harness")
+	if err != nil {
+		return err
+	}
+	if err = security.GenerateKeypair(n.keyDir); err != nil {
+		return err
+	}
+	if n.privKey, err = security.LoadKeypair(n.keyDir); err != nil {
+		return err
+	}
+
+	viper.Set("id", config.ID)
+	viper.Set("key-dir", n.keyDir)
+	viper.Set("auth-servers", map[string]string{issuer: fmt.Sprintf("file://%s/server.pub", n.keyDir)})
+	viper.Set("tls", false)
+	viper.Set("health-port", 0)
+
+	// Discovery
+	dsc := discovery.NewMemoryDiscovery()
+	dsc.WithMasterAuthServers(issuer)
+	n.Discovery = dsc
+	dscAddr, err := n.start("discovery", dsc)
+	if err != nil {
+		return err
+	}
+	viper.Set("discovery-address", dscAddr)
+
+	// Network Server
+	ns := networkserver.NewNetworkServer(n.NetworkServerDevices, config.NetID)
+	for _, prefix := range config.Prefixes {
+		if err = ns.UsePrefix(prefix, []string{"otaa", "abp", "world", "local", "group"}); err != nil {
+			return err
+		}
+	}
+	n.NetworkServer = ns
+	nsAddr, err := n.start("networkserver", ns)
+	if err != nil {
+		return err
+	}
+
+	// Broker
+	privPEM, err := security.PrivatePEM(n.privKey)
+	if err != nil {
+		return err
+	}
+	nsToken, err := security.BuildJWT(config.ID, 0, privPEM)
+	if err != nil {
+		return err
+	}
+	brk := broker.NewBroker(config.DeduplicationDelay)
+	brk.SetNetworkServer(nsAddr, "", nsToken)
+	n.Broker = brk
+	if _, err = n.start("broker", brk); err != nil {
+		return err
+	}
+	for _, prefix := range config.Prefixes {
+		err = dsc.AddMetadata("broker", config.ID, &pb_discovery.Metadata{Metadata: &pb_discovery.Metadata_DevAddrPrefix{
+			DevAddrPrefix: prefix.Bytes(),
+		}})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Router
+	n.Router = router.NewRouter()
+	if _, err = n.start("router", n.Router); err != nil {
+		return err
+	}
+
+	// Handler
+	n.Handler = handler.NewHandler(n.HandlerDevices, n.Applications, config.ID)
+	if _, err = n.start("handler", n.Handler); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (n *Network) start(serviceName string, impl component.Interface) (string, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	addr := lis.Addr().String()
+	c, err := component.New(n.Ctx.WithField("Component", serviceName), serviceName, addr)
+	if err != nil {
+		lis.Close()
+		return "", err
+	}
+	c.AccessToken, err = n.buildToken(serviceName)
+	if err != nil {
+		lis.Close()
+		return "", err
+	}
+	if err := impl.Init(c); err != nil {
+		lis.Close()
+		return "", err
+	}
+	srv := grpc.NewServer(c.ServerOptions()...)
+	c.RegisterHealthServer(srv)
+	impl.RegisterRPC(srv)
+	if manager, ok := impl.(component.ManagementInterface); ok {
+		manager.RegisterManager(srv)
+	}
+	go srv.Serve(lis)
+	n.servers = append(n.servers, srv)
+	n.components = append(n.components, impl)
+	return addr, nil
+}
+
+func (n *Network) buildToken(serviceName string) (string, error) {
+	var claims claims.ComponentClaims
+	claims.Subject = n.ID
+	claims.Type = serviceName
+	claims.Issuer = issuer
+	claims.IssuedAt = time.Now().Unix()
+	claims.NotBefore = time.Now().Unix()
+	return jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(n.privKey)
+}
+
+// Close stops all components and removes the temporary keys
+func (n *Network) Close() {
+	for i := len(n.servers) - 1; i >= 0; i-- {
+		n.servers[i].Stop()
+		n.components[i].Shutdown()
+	}
+	n.servers, n.components = nil, nil
+	if n.keyDir != "" {
+		os.RemoveAll(n.keyDir)
+	}
+}
+
+// ErrTimeout is returned when an expected message was not received in time
+var ErrTimeout = errors.New("harness: timeout expired")
+
+// Subscribe to the output of the Handler for the given application
+func (n *Network) Subscribe(appID string) *handler.Subscription {
+	return n.Handler.Subscribe(appID)
+}
+
+// WaitForUplink waits for the next uplink message on the subscription
+func WaitForUplink(sub *handler.Subscription, timeout time.Duration) (*types.UplinkMessage, error) {
+	select {
+	case up, ok := <-sub.Uplink:
+		if !ok {
+			return nil, errors.New("harness: subscription closed")
+		}
+		return up, nil
+	case <-time.After(timeout):
+		return nil, ErrTimeout
+	}
+}
+
+// WaitForEvent waits for the next event of the given type on the subscription
+func WaitForEvent(sub *handler.Subscription, event types.EventType, timeout time.Duration) (*types.DeviceEvent, error) {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case evt, ok := <-sub.Events:
+			if !ok {
+				return nil, errors.New("harness: subscription closed")
+			}
+			if evt.Event == event {
+				return evt, nil
+			}
+		case <-deadline:
+			return nil, ErrTimeout
+		}
+	}
+}
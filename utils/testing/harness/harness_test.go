@@ -0,0 +1,45 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package harness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheThingsNetwork/ttn/core/types"
+	. "github.com/TheThingsNetwork/ttn/utils/testing"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestNetworkUplink(t *testing.T) {
+	a := New(t)
+
+	n, err := NewNetwork(GetLogger(t, "TestNetworkUplink"), Config{})
+	a.So(err, ShouldBeNil)
+	defer n.Close()
+
+	dev := ABPDevice{
+		AppID:   "test-app",
+		DevID:   "test-dev",
+		AppEUI:  types.AppEUI{1, 2, 3, 4, 5, 6, 7, 8},
+		DevEUI:  types.DevEUI{1, 2, 3, 4, 5, 6, 7, 8},
+		DevAddr: types.DevAddr{0x26, 0x01, 0x02, 0x03},
+		NwkSKey: types.NwkSKey{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8},
+		AppSKey: types.AppSKey{8, 7, 6, 5, 4, 3, 2, 1, 8, 7, 6, 5, 4, 3, 2, 1},
+	}
+	a.So(n.AddApplication(dev.AppID), ShouldBeNil)
+	a.So(n.AddABPDevice(dev), ShouldBeNil)
+
+	sub := n.Subscribe(dev.AppID)
+
+	uplink, err := BuildUplink(dev, 1, 1, []byte{0xaa, 0xbc})
+	a.So(err, ShouldBeNil)
+	a.So(n.SendUplink("eui-0102030405060708", uplink), ShouldBeNil)
+
+	up, err := WaitForUplink(sub, 2*time.Second)
+	a.So(err, ShouldBeNil)
+	a.So(up.DevID, ShouldEqual, dev.DevID)
+	a.So(up.FCnt, ShouldEqual, 1)
+	a.So(up.PayloadRaw, ShouldResemble, []byte{0xaa, 0xbc})
+}
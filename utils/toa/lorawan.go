@@ -0,0 +1,26 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package toa
+
+import (
+	"errors"
+	"time"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+)
+
+// ComputeLoRaWAN computes the time-on-air of a LoRaWAN message given its PHY
+// payload size in bytes and its protocol metadata
+func ComputeLoRaWAN(payloadSize uint, md *pb_lorawan.Metadata) (time.Duration, error) {
+	if md == nil {
+		return 0, errors.New("No LoRaWAN metadata")
+	}
+	switch md.Modulation {
+	case pb_lorawan.Modulation_LORA:
+		return ComputeLoRa(payloadSize, md.DataRate, md.CodingRate)
+	case pb_lorawan.Modulation_FSK:
+		return ComputeFSK(payloadSize, int(md.BitRate))
+	}
+	return 0, errors.New("Invalid Modulation")
+}
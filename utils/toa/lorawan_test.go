@@ -0,0 +1,27 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package toa
+
+import (
+	"testing"
+	"time"
+
+	pb_lorawan "github.com/TheThingsNetwork/ttn/api/protocol/lorawan"
+	. "github.com/smartystreets/assertions"
+)
+
+func TestComputeLoRaWAN(t *testing.T) {
+	a := New(t)
+
+	_, err := ComputeLoRaWAN(10, nil)
+	a.So(err, ShouldNotBeNil)
+
+	toa, err := ComputeLoRaWAN(10, &pb_lorawan.Metadata{Modulation: pb_lorawan.Modulation_LORA, DataRate: "SF7BW125", CodingRate: "4/5"})
+	a.So(err, ShouldBeNil)
+	a.So(toa, ShouldAlmostEqual, 41216*time.Microsecond, time.Microsecond)
+
+	toa, err = ComputeLoRaWAN(10, &pb_lorawan.Metadata{Modulation: pb_lorawan.Modulation_FSK, BitRate: 50000})
+	a.So(err, ShouldBeNil)
+	a.So(toa, ShouldEqual, 3360*time.Microsecond)
+}
@@ -20,13 +20,13 @@ func ComputeLoRa(payloadSize uint, datr string, codr string) (time.Duration, err
 	// Determine CR
 	var cr float64
 	switch codr {
-	case "4/5":
+	case "4/5", "4/5LI":
 		cr = 1
-	case "4/6":
+	case "4/6", "4/6LI":
 		cr = 2
 	case "4/7":
 		cr = 3
-	case "4/8":
+	case "4/8", "4/8LI":
 		cr = 4
 	default:
 		return 0, errors.New("Invalid Codr")
@@ -36,6 +36,9 @@ func ComputeLoRa(payloadSize uint, datr string, codr string) (time.Duration, err
 	if err != nil {
 		return 0, err
 	}
+	if dr.Bandwidth == 812 {
+		return computeLoRa2400(payloadSize, dr.SpreadingFactor, cr), nil
+	}
 	// Determine DE
 	var de float64
 	if dr.Bandwidth == 125 && (dr.SpreadingFactor == 11 || dr.SpreadingFactor == 12) {
@@ -54,6 +57,30 @@ func ComputeLoRa(payloadSize uint, datr string, codr string) (time.Duration, err
 	return time.Duration(timeOnAir), nil
 }
 
+// computeLoRa2400 computes the time-on-air of LoRa in the 2.4 GHz band, which
+// uses 812.5 kHz bandwidth. The long interleaving coding rates (LI) are
+// computed as the regular coding rates with the same redundancy.
+//
+// See the SX1280 datasheet, section 7.4.4
+func computeLoRa2400(payloadSize uint, spreadingFactor uint, cr float64) time.Duration {
+	pl := float64(payloadSize)
+	sf := float64(spreadingFactor)
+
+	tSym := math.Pow(2, sf) / 812.5
+
+	header, bits, symbolBits := 4.25, 8.0*pl+16.0-4.0*sf+8.0+20.0, 4.0*sf
+	switch {
+	case spreadingFactor < 7:
+		header, bits = 6.25, 8.0*pl+16.0-4.0*sf+20.0
+	case spreadingFactor > 10:
+		symbolBits = 4.0 * (sf - 2.0)
+	}
+	payloadNb := 8.0 + math.Ceil(math.Max(0.0, bits)/symbolBits)*(cr+4.0)
+	timeOnAir := (payloadNb + 8.0 + header) * tSym * 1000000 // in nanoseconds
+
+	return time.Duration(timeOnAir)
+}
+
 // ComputeFSK computes the time-on-air given a PHY payload size in bytes and a
 // bitrate, Note that this function operates on the PHY payload size and does
 // not add the LoRaWAN header.
@@ -78,6 +78,19 @@ func TestComputeLoRa(t *testing.T) {
 		a.So(toa, ShouldAlmostEqual, time.Duration(us)*time.Microsecond)
 	}
 
+	// Test the 2.4 GHz band
+	ism2400Tests := map[string]time.Duration{
+		"SF5BW812":  2451692 * time.Nanosecond,
+		"SF6BW812":  4273230 * time.Nanosecond,
+		"SF7BW812":  8231384 * time.Nanosecond,
+		"SF10BW812": 55768615 * time.Nanosecond,
+		"SF12BW812": 182744615 * time.Nanosecond,
+	}
+	for dr, expected := range ism2400Tests {
+		toa, err = ComputeLoRa(10, dr, "4/8LI")
+		a.So(err, ShouldBeNil)
+		a.So(toa, ShouldAlmostEqual, expected, time.Microsecond)
+	}
 }
 
 func TestComputeFSK(t *testing.T) {
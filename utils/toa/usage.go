@@ -0,0 +1,39 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package toa
+
+import "time"
+
+// dayFormat is the format of Usage.Day
+const dayFormat = "2006-01-02"
+
+// Usage counts the time-on-air of messages, in total and per (UTC) day
+type Usage struct {
+	Day      string        `json:"day,omitempty" redis:"day"`
+	Today    time.Duration `json:"today" redis:"today"`
+	Total    time.Duration `json:"total" redis:"total"`
+	Messages uint64        `json:"messages" redis:"messages"`
+}
+
+// Add the time-on-air of a message that was sent or received at the given time
+func (u *Usage) Add(t time.Time, timeOnAir time.Duration) {
+	day := t.UTC().Format(dayFormat)
+	if u.Day != day {
+		u.Day = day
+		u.Today = 0
+	}
+	u.Today += timeOnAir
+	u.Total += timeOnAir
+	u.Messages++
+}
+
+// Get the usage at the given time. If the usage was not updated on that day,
+// the time-on-air of today is zero.
+func (u Usage) Get(t time.Time) Usage {
+	if day := t.UTC().Format(dayFormat); u.Day != day {
+		u.Day = day
+		u.Today = 0
+	}
+	return u
+}
@@ -0,0 +1,37 @@
+// Copyright © 2017 The Things Network
+// Use of this source code is governed by the MIT license that can be found in the LICENSE file.
+
+package toa
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/assertions"
+)
+
+func TestUsage(t *testing.T) {
+	a := New(t)
+
+	day1 := time.Date(2017, time.March, 1, 23, 0, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Hour)
+
+	var usage Usage
+	usage.Add(day1, 50*time.Millisecond)
+	usage.Add(day1, 100*time.Millisecond)
+	a.So(usage.Day, ShouldEqual, "2017-03-01")
+	a.So(usage.Today, ShouldEqual, 150*time.Millisecond)
+	a.So(usage.Total, ShouldEqual, 150*time.Millisecond)
+	a.So(usage.Messages, ShouldEqual, 2)
+
+	// The usage of today is reset on the next day
+	a.So(usage.Get(day2).Today, ShouldEqual, 0)
+	a.So(usage.Get(day2).Total, ShouldEqual, 150*time.Millisecond)
+	a.So(usage.Today, ShouldEqual, 150*time.Millisecond)
+
+	usage.Add(day2, 10*time.Millisecond)
+	a.So(usage.Day, ShouldEqual, "2017-03-02")
+	a.So(usage.Today, ShouldEqual, 10*time.Millisecond)
+	a.So(usage.Total, ShouldEqual, 160*time.Millisecond)
+	a.So(usage.Messages, ShouldEqual, 3)
+}